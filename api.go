@@ -19,6 +19,8 @@
 package polaris
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
@@ -32,6 +34,9 @@ type GetInstancesRequest api.GetInstancesRequest
 // GetAllInstancesRequest is the request struct for GetAllInstances.
 type GetAllInstancesRequest api.GetAllInstancesRequest
 
+// IterateInstancesRequest is the request struct for IterateInstances.
+type IterateInstancesRequest api.IterateInstancesRequest
+
 // GetServiceRuleRequest is the request struct for GetServiceRule.
 type GetServiceRuleRequest api.GetServiceRuleRequest
 
@@ -53,6 +58,30 @@ type WatchAllInstancesRequest api.WatchAllInstancesRequest
 // WatchAllServicesRequest is the request to watch services
 type WatchAllServicesRequest api.WatchAllServicesRequest
 
+// WatchInstanceMetadataRequest is the request to watch instance metadata changes
+type WatchInstanceMetadataRequest api.WatchInstanceMetadataRequest
+
+// InjectFaultRequest is the request struct for InjectFault.
+type InjectFaultRequest api.InjectFaultRequest
+
+// ExtractTrafficLabelsRequest is the request struct for ExtractTrafficLabels.
+type ExtractTrafficLabelsRequest api.ExtractTrafficLabelsRequest
+
+// RegisterFallbackRequest is the request struct for RegisterFallback.
+type RegisterFallbackRequest api.RegisterFallbackRequest
+
+// InvokeWithRetryRequest is the request struct for InvokeWithRetry.
+type InvokeWithRetryRequest api.InvokeWithRetryRequest
+
+// GetInstancesForHedgingRequest is the request struct for GetInstancesForHedging.
+type GetInstancesForHedgingRequest api.GetInstancesForHedgingRequest
+
+// GetInstancesByHashKeyRequest is the request struct for GetInstancesByHashKey.
+type GetInstancesByHashKeyRequest api.GetInstancesByHashKeyRequest
+
+// PrefetchRequest is the request struct for Prefetch.
+type PrefetchRequest api.PrefetchRequest
+
 // ConsumerAPI 主调端API方法.
 type ConsumerAPI interface {
 	api.SDKOwner
@@ -64,6 +93,18 @@ type ConsumerAPI interface {
 	GetAllInstances(req *GetAllInstancesRequest) (*model.InstancesResponse, error)
 	// GetRouteRule 同步获取服务路由规则
 	GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
+	// GetOneInstanceWithContext 同GetOneInstance，调用过程中ctx取消或超时会中止等待及后续重试
+	GetOneInstanceWithContext(ctx context.Context, req *GetOneInstanceRequest) (*model.OneInstanceResponse, error)
+	// GetInstancesWithContext 同GetInstances，调用过程中ctx取消或超时会中止等待及后续重试
+	GetInstancesWithContext(ctx context.Context, req *GetInstancesRequest) (*model.InstancesResponse, error)
+	// GetAllInstancesWithContext 同GetAllInstances，调用过程中ctx取消或超时会中止等待及后续重试
+	GetAllInstancesWithContext(ctx context.Context, req *GetAllInstancesRequest) (*model.InstancesResponse, error)
+	// IterateInstances 分批遍历服务的全部实例，支持按实例元数据在服务端过滤
+	IterateInstances(req *IterateInstancesRequest) (*model.InstancesIterator, error)
+	// GetRouteRuleWithContext 同GetRouteRule，调用过程中ctx取消或超时会中止等待及后续重试
+	GetRouteRuleWithContext(ctx context.Context, req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
+	// GetCircuitBreakerRule 同步获取服务熔断规则
+	GetCircuitBreakerRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
 	// UpdateServiceCallResult 上报服务调用结果
 	UpdateServiceCallResult(req *ServiceCallResult) error
 	// WatchService 订阅服务消息
@@ -76,6 +117,22 @@ type ConsumerAPI interface {
 	WatchAllInstances(req *WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error)
 	// WatchAllServices 监听服务列表变更事件
 	WatchAllServices(req *WatchAllServicesRequest) (*model.WatchAllServicesResponse, error)
+	// WatchInstanceMetadata 监听服务实例的元数据变更
+	WatchInstanceMetadata(req *WatchInstanceMetadataRequest) (*model.WatchAllInstancesResponse, error)
+	// InjectFault 根据故障注入规则计算本次调用需要注入的故障
+	InjectFault(req *InjectFaultRequest) (*model.InjectFaultResponse, error)
+	// ExtractTrafficLabels 从框架相关的请求对象中提取流量标签
+	ExtractTrafficLabels(req *ExtractTrafficLabelsRequest) (*model.ExtractTrafficLabelsResponse, error)
+	// RegisterFallback 注册实例全部熔断时的降级函数
+	RegisterFallback(req *RegisterFallbackRequest) error
+	// InvokeWithRetry 选择实例并执行带重试的调用
+	InvokeWithRetry(req *InvokeWithRetryRequest) (*model.InvokeWithRetryResponse, error)
+	// GetInstancesForHedging 获取对冲请求的主实例、备份实例及对冲延迟
+	GetInstancesForHedging(req *GetInstancesForHedgingRequest) (*model.HedgingInstancesResponse, error)
+	// GetInstancesByHashKey 沿一致性hash环获取hash key对应的多个不同实例
+	GetInstancesByHashKey(req *GetInstancesByHashKeyRequest) (*model.HashKeyInstancesResponse, error)
+	// Prefetch 后台预热服务实例及规则，通过应答的Ready通道等待完成
+	Prefetch(req *PrefetchRequest) (*model.PrefetchResponse, error)
 	// Destroy 销毁API，销毁后无法再进行调用
 	Destroy()
 }
@@ -89,10 +146,24 @@ type InstanceDeRegisterRequest api.InstanceDeRegisterRequest
 // InstanceHeartbeatRequest 实例心跳请求.
 type InstanceHeartbeatRequest api.InstanceHeartbeatRequest
 
+// InstanceMetadataUpdateRequest 实例元数据更新请求.
+type InstanceMetadataUpdateRequest api.InstanceMetadataUpdateRequest
+
+// BatchRegisterRequest 批量注册请求.
+type BatchRegisterRequest api.BatchRegisterRequest
+
+// BatchHeartbeatRequest 批量心跳上报请求.
+type BatchHeartbeatRequest api.BatchHeartbeatRequest
+
+// InstanceGracefulDeregisterRequest 实例优雅下线请求.
+type InstanceGracefulDeregisterRequest api.InstanceGracefulDeregisterRequest
+
 // ProviderAPI CL5服务端API的主接口.
 type ProviderAPI interface {
 	api.SDKOwner
-	// RegisterInstance
+	// RegisterInstance 注册实例，并由SDK自动维护TTL心跳
+	// 心跳失败时按指数退避重试，服务端不存在该实例时自动重新注册，
+	// 调用Deregister或销毁SDK时心跳任务自动停止
 	// minimum supported version of polaris-server is v1.10.0
 	RegisterInstance(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
 	// Register
@@ -102,10 +173,34 @@ type ProviderAPI interface {
 	// Deregister
 	// 同步反注册服务
 	Deregister(instance *InstanceDeRegisterRequest) error
+	// GracefulDeregister
+	// 优雅下线，先将实例置为隔离，等待排空时长结束或在途请求归零后再反注册
+	GracefulDeregister(instance *InstanceGracefulDeregisterRequest) error
 	// Deprecated: Use RegisterInstance instead.
 	// Heartbeat
 	// 心跳上报
 	Heartbeat(instance *InstanceHeartbeatRequest) error
+	// UpdateInstanceMetadata
+	// 更新已注册实例的元数据及权重，无需反注册，最小推送间隔内的多次更新会被合并
+	UpdateInstanceMetadata(req *InstanceMetadataUpdateRequest) error
+	// AddLoadSampler
+	// 添加自适应权重使用的负载采样器，需开启 provider.adaptiveWeight.enable
+	AddLoadSampler(sampler model.LoadSampler)
+	// RegisterInstances
+	// 并发注册多个实例，返回与请求一一对应的结果，单个实例失败不影响其他实例
+	RegisterInstances(req *BatchRegisterRequest) (*model.BatchRegisterResponse, error)
+	// HeartbeatInstances
+	// 并发上报多个实例的心跳，返回与请求一一对应的结果
+	HeartbeatInstances(req *BatchHeartbeatRequest) (*model.BatchHeartbeatResponse, error)
+	// RegisterInstanceWithContext 同RegisterInstance，调用过程中ctx取消或超时会中止等待及后续重试
+	RegisterInstanceWithContext(ctx context.Context,
+		instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// RegisterWithContext 同Register，调用过程中ctx取消或超时会中止等待及后续重试
+	RegisterWithContext(ctx context.Context, instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// DeregisterWithContext 同Deregister，调用过程中ctx取消或超时会中止等待及后续重试
+	DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error
+	// HeartbeatWithContext 同Heartbeat，调用过程中ctx取消或超时会中止等待及后续重试
+	HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error
 	// Destroy
 	// 销毁API，销毁后无法再进行调用
 	Destroy()
@@ -122,10 +217,32 @@ type LimitAPI interface {
 	api.SDKOwner
 	// GetQuota the interface obtains only one quota at a time
 	GetQuota(request QuotaRequest) (QuotaFuture, error)
+	// GetQuotaWithContext 同GetQuota，调用过程中ctx取消或超时会中止等待及后续重试
+	GetQuotaWithContext(ctx context.Context, request QuotaRequest) (QuotaFuture, error)
 	// Destroy the api is destroyed and cannot be called again
 	Destroy()
 }
 
+// AcquireGetOneInstanceRequest 从对象池获取单个实例查询请求，使用完毕后需通过 ReleaseGetOneInstanceRequest 归还
+func AcquireGetOneInstanceRequest() *GetOneInstanceRequest {
+	return (*GetOneInstanceRequest)(api.AcquireGetOneInstanceRequest())
+}
+
+// ReleaseGetOneInstanceRequest 重置请求并归还到对象池，归还后不可再访问该请求
+func ReleaseGetOneInstanceRequest(req *GetOneInstanceRequest) {
+	api.ReleaseGetOneInstanceRequest((*api.GetOneInstanceRequest)(req))
+}
+
+// ValidateConfigFile 严格校验配置文件并一次性返回全部问题，只做校验而不创建SDK上下文，可用于在CI中检查配置文件
+func ValidateConfigFile(path string) error {
+	return api.ValidateConfigFile(path)
+}
+
+// ValidateConfigStream 严格校验YAML配置内容并一次性返回全部问题
+func ValidateConfigStream(buf []byte) error {
+	return api.ValidateConfigStream(buf)
+}
+
 // NewQuotaRequest example create a quota query request.
 func NewQuotaRequest() QuotaRequest {
 	return &model.QuotaRequestImpl{}
@@ -134,6 +251,15 @@ func NewQuotaRequest() QuotaRequest {
 type GetConfigFileRequest api.GetConfigFileRequest
 type GetConfigGroupRequest api.GetConfigGroupRequest
 
+// ConfigFileModifyRequest 创建或者更新配置文件的请求
+type ConfigFileModifyRequest api.ConfigFileModifyRequest
+
+// ConfigFilePublishRequest 发布配置文件的请求
+type ConfigFilePublishRequest api.ConfigFilePublishRequest
+
+// ConfigFileRollbackRequest 回滚配置文件的请求
+type ConfigFileRollbackRequest api.ConfigFileRollbackRequest
+
 // ConfigFile config
 type ConfigFile model.ConfigFile
 
@@ -151,6 +277,14 @@ type ConfigAPI interface {
 	UpdateConfigFile(namespace, fileGroup, fileName, content string) error
 	// PublishConfigFile publish configuration file
 	PublishConfigFile(namespace, fileGroup, fileName string) error
+	// CreateConfigFileWithReq create configuration file with comment and version check
+	CreateConfigFileWithReq(*ConfigFileModifyRequest) error
+	// UpdateConfigFileWithReq update configuration file with comment and version check
+	UpdateConfigFileWithReq(*ConfigFileModifyRequest) error
+	// PublishConfigFileWithReq publish configuration file with release name, description and version check
+	PublishConfigFileWithReq(*ConfigFilePublishRequest) error
+	// RollbackConfigFile rollback configuration file by republishing the content of target release
+	RollbackConfigFile(*ConfigFileRollbackRequest) error
 }
 
 // ConfigGroupAPI .
@@ -161,14 +295,28 @@ type ConfigGroupAPI interface {
 
 	// FetchConfigGroup 获取配置分组
 	FetchConfigGroup(*GetConfigGroupRequest) (model.ConfigFileGroup, error)
+	// ListConfigFiles 列出配置分组下所有已发布的配置文件
+	ListConfigFiles(namespace, group string) ([]*model.SimpleConfigFile, error)
+	// FetchConfigFiles 一次获取配置分组下所有配置文件的内容
+	FetchConfigFiles(namespace, group string) ([]model.ConfigFile, error)
+	// WatchConfigGroup 监听配置分组，分组内任意文件内容变更、新增以及删除时回调cb
+	WatchConfigGroup(namespace, group string, cb model.OnConfigFileChange) error
+	// WatchConfigFilesByPattern 按文件名通配符（如 "*.yaml"、"db/*"）监听配置分组，回调参数为实际变更的文件
+	WatchConfigFilesByPattern(namespace, group, pattern string, cb model.OnConfigFileChange) error
 }
 
 type CircuitBreakerAPI interface {
 	api.SDKOwner
 	// Check
 	Check(model.Resource) (*model.CheckResult, error)
+	// CheckResource 检查任意资源的熔断状态，与Check等价
+	CheckResource(model.Resource) (*model.CheckResult, error)
 	// Report
 	Report(*model.ResourceStat) error
+	// DoWithCircuitBreaker 在熔断保护下执行fn并自动上报结果，资源被熔断时执行fallback
+	DoWithCircuitBreaker(resource model.Resource, fn func() error, fallback model.FallbackFunction) error
+	// AddStatusListener 添加熔断状态监听器
+	AddStatusListener(model.CircuitBreakerStatusListener) error
 	// MakeFunctionDecorator
 	MakeFunctionDecorator(model.CustomerFunction, *api.RequestContext) model.DecoratorFunction
 	// MakeInvokeHandler
@@ -210,3 +358,15 @@ func (r *ProcessRoutersRequest) convert() {
 type ProcessLoadBalanceRequest struct {
 	model.ProcessLoadBalanceRequest
 }
+
+// Invocation 被拦截的一次 SDK 调用，包含接口标识及请求对象
+type Invocation = model.Invocation
+
+// Interceptor SDK 调用拦截器，作用于 GetOneInstance、GetQuota、Register、Heartbeat 及配置获取等调用
+type Interceptor = model.Interceptor
+
+// AddInterceptor 为 SDK 上下文添加调用拦截器，多个拦截器按添加顺序由外向内执行，
+// 基于同一上下文创建的各类 API 对象共享这些拦截器
+func AddInterceptor(sdkCtx api.SDKContext, interceptor Interceptor) {
+	sdkCtx.GetEngine().AddInterceptor(interceptor)
+}
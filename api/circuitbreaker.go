@@ -27,8 +27,14 @@ type CircuitBreakerAPI interface {
 	SDKOwner
 	// Check
 	Check(model.Resource) (*model.CheckResult, error)
+	// CheckResource 检查任意资源（如DB、缓存、三方HTTP接口）的熔断状态，与Check等价
+	CheckResource(model.Resource) (*model.CheckResult, error)
 	// Report
 	Report(*model.ResourceStat) error
+	// DoWithCircuitBreaker 在熔断保护下执行fn并自动上报结果，资源被熔断时执行fallback
+	DoWithCircuitBreaker(resource model.Resource, fn func() error, fallback model.FallbackFunction) error
+	// AddStatusListener 添加熔断状态监听器，可观测熔断、半开放量及恢复的完整状态变化
+	AddStatusListener(model.CircuitBreakerStatusListener) error
 	// MakeFunctionDecorator
 	MakeFunctionDecorator(model.CustomerFunction, *RequestContext) model.DecoratorFunction
 	// MakeInvokeHandler
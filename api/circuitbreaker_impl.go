@@ -35,10 +35,23 @@ func (c *circuitBreakerAPI) Check(resource model.Resource) (*model.CheckResult,
 	return c.context.GetEngine().Check(resource)
 }
 
+func (c *circuitBreakerAPI) CheckResource(resource model.Resource) (*model.CheckResult, error) {
+	return c.context.GetEngine().Check(resource)
+}
+
+func (c *circuitBreakerAPI) DoWithCircuitBreaker(resource model.Resource, fn func() error,
+	fallback model.FallbackFunction) error {
+	return c.context.GetEngine().DoWithCircuitBreaker(resource, fn, fallback)
+}
+
 func (c *circuitBreakerAPI) Report(reportStat *model.ResourceStat) error {
 	return c.context.GetEngine().Report(reportStat)
 }
 
+func (c *circuitBreakerAPI) AddStatusListener(listener model.CircuitBreakerStatusListener) error {
+	return c.context.GetEngine().AddCircuitBreakerStatusListener(listener)
+}
+
 func (c *circuitBreakerAPI) MakeFunctionDecorator(f model.CustomerFunction, reqCtx *RequestContext) model.DecoratorFunction {
 	return c.context.GetEngine().MakeFunctionDecorator(f, &reqCtx.RequestContext)
 }
@@ -19,6 +19,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -63,6 +64,12 @@ type SDKContext interface {
 	// @brief 销毁SDK上下文
 	Destroy()
 
+	// DestroyWithContext
+	// @brief 优雅销毁SDK上下文：拒绝新的API调用并等待在途调用结束，对自动心跳的实例发送最后一次心跳或反注册，
+	// 写出待上报的统计数据及待落盘的缓存，最后销毁引擎及插件；ctx结束后跳过剩余的等待，
+	// 各环节的错误以环节名（drain/provider/engine/flush/plugins）为前缀汇总返回
+	DestroyWithContext(ctx context.Context) error
+
 	// IsDestroyed
 	// @brief SDK上下文是否已经销毁
 	IsDestroyed() bool
@@ -107,20 +114,53 @@ func checkAvailable(owner SDKOwner) error {
 	return nil
 }
 
+// enterCall 判断API是否可用，可用时登记一次在途调用，需在调用结束后通过 exitCall 注销
+func enterCall(owner SDKOwner) error {
+	if reflect2.IsNil(owner) {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "API can not be nil")
+	}
+	ctx, ok := owner.SDKContext().(*sdkContext)
+	if !ok {
+		return checkAvailable(owner)
+	}
+	// 先登记再检查，保证优雅销毁开始后不会遗漏在途调用
+	atomic.AddInt64(&ctx.inFlight, 1)
+	if err := checkAvailable(owner); err != nil {
+		atomic.AddInt64(&ctx.inFlight, -1)
+		return err
+	}
+	return nil
+}
+
+// exitCall 注销一次在途调用
+func exitCall(owner SDKOwner) {
+	if ctx, ok := owner.SDKContext().(*sdkContext); ok {
+		atomic.AddInt64(&ctx.inFlight, -1)
+	}
+}
+
+// drainCheckInterval 优雅销毁时检查在途调用数的间隔
+const drainCheckInterval = 10 * time.Millisecond
+
 // sdkContext SDK上下文实现
 type sdkContext struct {
+	// 在途的API调用数，需保持在首位以保证64位原子操作的对齐
+	inFlight     int64
 	config       config.Configuration
 	plugins      plugin.Manager
 	engine       model.Engine
 	valueContext model.ValueContext
 	// 标识是否已经销毁，0未销毁，1已销毁
 	destroyed uint32
+	// 具名上下文的名称，通过 InitNamedContextByConfig 创建时设置
+	name string
 }
 
 // Destroy 销毁SDK上下文
 func (s *sdkContext) Destroy() {
 	var err error
 	atomic.StoreUint32(&s.destroyed, 1)
+	unregisterNamedContext(s)
 	err = s.engine.Destroy()
 	if err != nil {
 		log.GetBaseLogger().Errorf("fail to destroy engine, error %+v", err)
@@ -131,6 +171,48 @@ func (s *sdkContext) Destroy() {
 	}
 }
 
+// DestroyWithContext 优雅销毁SDK上下文，重复调用时直接返回
+func (s *sdkContext) DestroyWithContext(ctx context.Context) error {
+	if !atomic.CompareAndSwapUint32(&s.destroyed, 0, 1) {
+		return nil
+	}
+	unregisterNamedContext(s)
+	var errs error
+	if err := s.waitInFlight(ctx); err != nil {
+		errs = multierror.Append(errs, multierror.Prefix(err, "drain:"))
+	}
+	if err := s.engine.DestroyWithContext(ctx); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := s.plugins.FlushPlugins(ctx); err != nil {
+		errs = multierror.Append(errs, multierror.Prefix(err, "flush:"))
+	}
+	if err := s.plugins.DestroyPlugins(); err != nil {
+		errs = multierror.Append(errs, multierror.Prefix(err, "plugins:"))
+	}
+	if errs != nil {
+		log.GetBaseLogger().Errorf("fail to destroy sdk context gracefully, error %+v", errs)
+	}
+	return errs
+}
+
+// waitInFlight 等待在途的API调用结束
+func (s *sdkContext) waitInFlight(ctx context.Context) error {
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+	for {
+		count := atomic.LoadInt64(&s.inFlight)
+		if count <= 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d in-flight calls not finished before deadline: %v", count, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
 // IsDestroyed SDK上下文是否已经销毁
 func (s *sdkContext) IsDestroyed() bool {
 	return atomic.LoadUint32(&s.destroyed) > 0
@@ -165,7 +247,15 @@ func InitContextByFile(path string) (SDKContext, error) {
 	if err != nil {
 		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "fail to read context file %s", path)
 	}
-	return InitContextByStream(buff)
+	cfg, err := config.LoadConfiguration(buff)
+	if err != nil {
+		return nil, err
+	}
+	// 启用配置热更新且未指定监听文件时，监听初始化所用的配置文件
+	if reloadCfg := cfg.GetGlobal().GetConfigReload(); reloadCfg.IsEnable() && len(reloadCfg.GetFile()) == 0 {
+		reloadCfg.SetFile(path)
+	}
+	return InitContextByConfig(cfg)
 }
 
 // InitContextByStream 通过YAML流新建服务消费者配置
@@ -260,6 +350,7 @@ func InitContextByConfig(cfg config.Configuration) (SDKContext, error) {
 	if err := cfg.Verify(); err != nil {
 		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to verify input config")
 	}
+	model.SetRegexOptions(cfg.GetGlobal().GetRegex().ToRegexOptions())
 	initSelfIP(cfg)
 	token := &model.SDKToken{
 		IP:       cfg.GetGlobal().GetAPI().GetBindIP(),
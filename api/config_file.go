@@ -27,6 +27,21 @@ type GetConfigGroupRequest struct {
 	*model.GetConfigGroupRequest
 }
 
+// ConfigFileModifyRequest 创建或者更新配置文件的请求
+type ConfigFileModifyRequest struct {
+	*model.ConfigFileModifyRequest
+}
+
+// ConfigFilePublishRequest 发布配置文件的请求
+type ConfigFilePublishRequest struct {
+	*model.ConfigFilePublishRequest
+}
+
+// ConfigFileRollbackRequest 回滚配置文件的请求
+type ConfigFileRollbackRequest struct {
+	*model.ConfigFileRollbackRequest
+}
+
 // ConfigFileAPI 配置文件的 API
 type ConfigFileAPI interface {
 	SDKOwner
@@ -41,6 +56,14 @@ type ConfigFileAPI interface {
 	UpdateConfigFile(namespace, fileGroup, fileName, content string) error
 	// PublishConfigFile 发布配置文件
 	PublishConfigFile(namespace, fileGroup, fileName string) error
+	// CreateConfigFileWithReq 创建配置文件，支持配置描述以及版本校验
+	CreateConfigFileWithReq(*ConfigFileModifyRequest) error
+	// UpdateConfigFileWithReq 更新配置文件，支持配置描述以及版本校验
+	UpdateConfigFileWithReq(*ConfigFileModifyRequest) error
+	// PublishConfigFileWithReq 发布配置文件，支持发布名称、发布描述以及版本校验
+	PublishConfigFileWithReq(*ConfigFilePublishRequest) error
+	// RollbackConfigFile 回滚配置文件，将目标版本的配置内容重新发布
+	RollbackConfigFile(*ConfigFileRollbackRequest) error
 }
 
 type ConfigGroupAPI interface {
@@ -49,6 +72,14 @@ type ConfigGroupAPI interface {
 	GetConfigGroup(namespace, group string) (model.ConfigFileGroup, error)
 	// FetchConfigGroup 获取配置文件
 	FetchConfigGroup(*GetConfigGroupRequest) (model.ConfigFileGroup, error)
+	// ListConfigFiles 列出配置分组下所有已发布的配置文件
+	ListConfigFiles(namespace, group string) ([]*model.SimpleConfigFile, error)
+	// FetchConfigFiles 一次获取配置分组下所有配置文件的内容
+	FetchConfigFiles(namespace, group string) ([]model.ConfigFile, error)
+	// WatchConfigGroup 监听配置分组，分组内任意文件内容变更、新增以及删除时回调cb
+	WatchConfigGroup(namespace, group string, cb model.OnConfigFileChange) error
+	// WatchConfigFilesByPattern 按文件名通配符（如 "*.yaml"、"db/*"）监听配置分组，回调参数为实际变更的文件
+	WatchConfigFilesByPattern(namespace, group, pattern string, cb model.OnConfigFileChange) error
 }
 
 var (
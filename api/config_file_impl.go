@@ -82,6 +82,26 @@ func (c *configFileAPI) PublishConfigFile(namespace, fileGroup, fileName string)
 	return c.context.GetEngine().SyncPublishConfigFile(namespace, fileGroup, fileName)
 }
 
+// CreateConfigFileWithReq 创建配置文件
+func (c *configFileAPI) CreateConfigFileWithReq(req *ConfigFileModifyRequest) error {
+	return c.context.GetEngine().SyncCreateConfigFileWithReq(req.ConfigFileModifyRequest)
+}
+
+// UpdateConfigFileWithReq 更新配置文件
+func (c *configFileAPI) UpdateConfigFileWithReq(req *ConfigFileModifyRequest) error {
+	return c.context.GetEngine().SyncUpdateConfigFileWithReq(req.ConfigFileModifyRequest)
+}
+
+// PublishConfigFileWithReq 发布配置文件
+func (c *configFileAPI) PublishConfigFileWithReq(req *ConfigFilePublishRequest) error {
+	return c.context.GetEngine().SyncPublishConfigFileWithReq(req.ConfigFilePublishRequest)
+}
+
+// RollbackConfigFile 回滚配置文件
+func (c *configFileAPI) RollbackConfigFile(req *ConfigFileRollbackRequest) error {
+	return c.context.GetEngine().SyncRollbackConfigFile(req.ConfigFileRollbackRequest)
+}
+
 // SDKContext 获取SDK上下文
 func (c *configFileAPI) SDKContext() SDKContext {
 	return c.context
@@ -127,6 +147,38 @@ func (c *configGroupAPI) FetchConfigGroup(req *GetConfigGroupRequest) (model.Con
 	return c.context.GetEngine().SyncGetConfigGroupWithReq(req.GetConfigGroupRequest)
 }
 
+// ListConfigFiles 列出配置分组下所有已发布的配置文件
+func (c *configGroupAPI) ListConfigFiles(namespace, group string) ([]*model.SimpleConfigFile, error) {
+	configGroup, err := c.context.GetEngine().SyncGetConfigGroup(namespace, group)
+	if err != nil {
+		return nil, err
+	}
+	files, _, _ := configGroup.GetFiles()
+	return files, nil
+}
+
+// FetchConfigFiles 获取配置分组下所有配置文件的内容
+func (c *configGroupAPI) FetchConfigFiles(namespace, group string) ([]model.ConfigFile, error) {
+	return c.context.GetEngine().SyncGetConfigGroupFiles(namespace, group)
+}
+
+// WatchConfigGroup 监听配置分组
+func (c *configGroupAPI) WatchConfigGroup(namespace, group string, cb model.OnConfigFileChange) error {
+	if cb == nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "config group watcher can not be nil")
+	}
+	return c.context.GetEngine().WatchConfigGroup(namespace, group, cb)
+}
+
+// WatchConfigFilesByPattern 按文件名通配符监听配置分组
+func (c *configGroupAPI) WatchConfigFilesByPattern(namespace, group, pattern string,
+	cb model.OnConfigFileChange) error {
+	if cb == nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "config file watcher can not be nil")
+	}
+	return c.context.GetEngine().WatchConfigFilesByPattern(namespace, group, pattern, cb)
+}
+
 // SDKContext 获取SDK上下文
 func (c *configGroupAPI) SDKContext() SDKContext {
 	return c.context
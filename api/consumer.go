@@ -18,6 +18,9 @@
 package api
 
 import (
+	"context"
+	"sync"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -45,6 +48,29 @@ type GetOneInstanceRequest struct {
 	model.GetOneInstanceRequest
 }
 
+// getOneInstanceRequestPool 单个实例查询请求对象池
+var getOneInstanceRequestPool = &sync.Pool{}
+
+// AcquireGetOneInstanceRequest 从对象池获取单个实例查询请求，适用于高频调用的场景，
+// 使用完毕后需通过 ReleaseGetOneInstanceRequest 归还。返回的请求各字段均为零值
+func AcquireGetOneInstanceRequest() *GetOneInstanceRequest {
+	value := getOneInstanceRequestPool.Get()
+	if nil == value {
+		return &GetOneInstanceRequest{}
+	}
+	return value.(*GetOneInstanceRequest)
+}
+
+// ReleaseGetOneInstanceRequest 重置请求并归还到对象池。归还后不可再访问该请求，同一请求不可重复归还。
+// GetOneInstance 返回的应答为独立对象，归还请求后仍可继续使用
+func ReleaseGetOneInstanceRequest(req *GetOneInstanceRequest) {
+	if nil == req {
+		return
+	}
+	req.GetOneInstanceRequest.Reset()
+	getOneInstanceRequestPool.Put(req)
+}
+
 func (r *GetOneInstanceRequest) convert() {
 	if len(r.Arguments) == 0 {
 		return
@@ -108,6 +134,46 @@ type GetServicesRequest struct {
 	model.GetServicesRequest
 }
 
+// InjectFaultRequest 故障注入请求
+type InjectFaultRequest struct {
+	model.InjectFaultRequest
+}
+
+// ExtractTrafficLabelsRequest 流量标签提取请求
+type ExtractTrafficLabelsRequest struct {
+	model.ExtractTrafficLabelsRequest
+}
+
+// RegisterFallbackRequest 熔断降级函数注册请求
+type RegisterFallbackRequest struct {
+	model.RegisterFallbackRequest
+}
+
+// InvokeWithRetryRequest 带重试的服务调用请求
+type InvokeWithRetryRequest struct {
+	model.InvokeWithRetryRequest
+}
+
+// GetInstancesForHedgingRequest 对冲请求的实例获取请求
+type GetInstancesForHedgingRequest struct {
+	model.GetInstancesForHedgingRequest
+}
+
+// GetInstancesByHashKeyRequest 按hash key获取多个副本实例的请求
+type GetInstancesByHashKeyRequest struct {
+	model.GetInstancesByHashKeyRequest
+}
+
+// IterateInstancesRequest 分批遍历服务实例的请求
+type IterateInstancesRequest struct {
+	model.IterateInstancesRequest
+}
+
+// PrefetchRequest 缓存预热请求
+type PrefetchRequest struct {
+	model.PrefetchRequest
+}
+
 // WatchServiceRequest WatchService req
 type WatchServiceRequest struct {
 	model.WatchServiceRequest
@@ -128,6 +194,11 @@ type WatchAllServicesRequest struct {
 	model.WatchAllServicesRequest
 }
 
+// WatchInstanceMetadataRequest .
+type WatchInstanceMetadataRequest struct {
+	model.WatchInstanceMetadataRequest
+}
+
 // ConsumerAPI 主调端API方法
 type ConsumerAPI interface {
 	SDKOwner
@@ -139,6 +210,19 @@ type ConsumerAPI interface {
 	GetAllInstances(req *GetAllInstancesRequest) (*model.InstancesResponse, error)
 	// GetRouteRule 同步获取服务路由规则
 	GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
+	// GetOneInstanceWithContext 同GetOneInstance，调用过程中ctx取消或超时会中止等待及后续重试
+	GetOneInstanceWithContext(ctx context.Context, req *GetOneInstanceRequest) (*model.OneInstanceResponse, error)
+	// GetInstancesWithContext 同GetInstances，调用过程中ctx取消或超时会中止等待及后续重试
+	GetInstancesWithContext(ctx context.Context, req *GetInstancesRequest) (*model.InstancesResponse, error)
+	// GetAllInstancesWithContext 同GetAllInstances，调用过程中ctx取消或超时会中止等待及后续重试
+	GetAllInstancesWithContext(ctx context.Context, req *GetAllInstancesRequest) (*model.InstancesResponse, error)
+	// IterateInstances 分批遍历服务的全部实例（包括隔离及不健康的实例），适用于实例数量巨大的服务，
+	// 支持按实例元数据过滤，开启ServerSideFilter时由服务端过滤以减少传输的数据量
+	IterateInstances(req *IterateInstancesRequest) (*model.InstancesIterator, error)
+	// GetRouteRuleWithContext 同GetRouteRule，调用过程中ctx取消或超时会中止等待及后续重试
+	GetRouteRuleWithContext(ctx context.Context, req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
+	// GetCircuitBreakerRule 同步获取服务熔断规则
+	GetCircuitBreakerRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
 	// UpdateServiceCallResult 上报服务调用结果
 	UpdateServiceCallResult(req *ServiceCallResult) error
 	// Destroy 销毁API，销毁后无法再进行调用
@@ -154,6 +238,28 @@ type ConsumerAPI interface {
 	WatchAllInstances(req *WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error)
 	// WatchAllServices 监听服务列表变更事件
 	WatchAllServices(req *WatchAllServicesRequest) (*model.WatchAllServicesResponse, error)
+	// WatchInstanceMetadata 监听服务实例的元数据变更，仅在已存在实例的元数据（可限定键）变化时回调，
+	// 实例上下线不会触发，适用于权重、特性开关等基于元数据的动态配置
+	WatchInstanceMetadata(req *WatchInstanceMetadataRequest) (*model.WatchAllInstancesResponse, error)
+	// InjectFault 根据故障注入规则计算本次调用需要注入的延迟或中断，供RPC框架在调用下游前使用
+	InjectFault(req *InjectFaultRequest) (*model.InjectFaultResponse, error)
+	// ExtractTrafficLabels 从gin/grpc等框架的请求对象中提取流量标签，提取结果可直接作为路由及限流的请求参数，
+	// 保证规则中引用的$header.x-user-id等标签在不同框架中取值一致
+	ExtractTrafficLabels(req *ExtractTrafficLabelsRequest) (*model.ExtractTrafficLabelsResponse, error)
+	// RegisterFallback 注册服务或方法级别的降级函数，GetOneInstance发现实例全部被熔断时自动调用并返回降级应答
+	RegisterFallback(req *RegisterFallbackRequest) error
+	// InvokeWithRetry 选择实例并执行调用，失败时在重试预算内退避重试，重试会排除已调用过的实例，
+	// 所有实例都调用过后返回最后一次调用的错误
+	InvokeWithRetry(req *InvokeWithRetryRequest) (*model.InvokeWithRetryResponse, error)
+	// GetInstancesForHedging 获取对冲请求的主实例及备份实例，备份调用按HedgeDelays延迟发起，
+	// 上报调用结果时需带上HedgeID，同一对冲请求只有最先上报的结果计入调用统计、时延采样及熔断统计
+	GetInstancesForHedging(req *GetInstancesForHedgingRequest) (*model.HedgingInstancesResponse, error)
+	// GetInstancesByHashKey 从hash key所在节点开始沿一致性hash环返回Replicas个不同实例，用于多副本写入等场景，
+	// 每个副本都会跳过隔离、不健康及熔断的实例，默认使用ringHash负载均衡
+	GetInstancesByHashKey(req *GetInstancesByHashKeyRequest) (*model.HashKeyInstancesResponse, error)
+	// Prefetch 在后台并发预热服务实例及路由、限流规则并持久化，立即返回，
+	// 可通过应答的Ready通道等待预热完成，避免首次调用承担服务发现的时延
+	Prefetch(req *PrefetchRequest) (*model.PrefetchResponse, error)
 }
 
 var (
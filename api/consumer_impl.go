@@ -18,6 +18,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/go-multierror"
@@ -36,9 +37,10 @@ type consumerAPI struct {
 
 // GetOneInstance sync get one instance after load balance
 func (c *consumerAPI) GetOneInstance(req *GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return nil, err
 	}
+	defer exitCall(c)
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
@@ -48,9 +50,10 @@ func (c *consumerAPI) GetOneInstance(req *GetOneInstanceRequest) (*model.OneInst
 
 // GetInstances syncs get one instance after route
 func (c *consumerAPI) GetInstances(req *GetInstancesRequest) (*model.InstancesResponse, error) {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return nil, err
 	}
+	defer exitCall(c)
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
@@ -60,20 +63,76 @@ func (c *consumerAPI) GetInstances(req *GetInstancesRequest) (*model.InstancesRe
 
 // GetAllInstances 获取完整的服务列表
 func (c *consumerAPI) GetAllInstances(req *GetAllInstancesRequest) (*model.InstancesResponse, error) {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return nil, err
 	}
+	defer exitCall(c)
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 	return c.context.GetEngine().SyncGetAllInstances(&req.GetAllInstancesRequest)
 }
 
+// GetOneInstanceWithContext 同GetOneInstance，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *consumerAPI) GetOneInstanceWithContext(ctx context.Context,
+	req *GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	reqCopy := *req
+	if err := applyContextDeadline(ctx, c.context, &reqCopy); err != nil {
+		return nil, err
+	}
+	return c.GetOneInstance(&reqCopy)
+}
+
+// GetInstancesWithContext 同GetInstances，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *consumerAPI) GetInstancesWithContext(ctx context.Context,
+	req *GetInstancesRequest) (*model.InstancesResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	reqCopy := *req
+	if err := applyContextDeadline(ctx, c.context, &reqCopy); err != nil {
+		return nil, err
+	}
+	return c.GetInstances(&reqCopy)
+}
+
+// GetAllInstancesWithContext 同GetAllInstances，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *consumerAPI) GetAllInstancesWithContext(ctx context.Context,
+	req *GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	reqCopy := *req
+	if err := applyContextDeadline(ctx, c.context, &reqCopy); err != nil {
+		return nil, err
+	}
+	return c.GetAllInstances(&reqCopy)
+}
+
+// IterateInstances 分批遍历服务的全部实例
+func (c *consumerAPI) IterateInstances(req *IterateInstancesRequest) (*model.InstancesIterator, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncIterateInstances(&req.IterateInstancesRequest)
+}
+
 // UpdateServiceCallResult update the service call error code and delay
 func (c *consumerAPI) UpdateServiceCallResult(req *ServiceCallResult) error {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return err
 	}
+	defer exitCall(c)
 	if err := req.Validate(); err != nil {
 		return err
 	}
@@ -82,20 +141,48 @@ func (c *consumerAPI) UpdateServiceCallResult(req *ServiceCallResult) error {
 
 // GetRouteRule 同步获取服务路由规则
 func (c *consumerAPI) GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return nil, err
 	}
+	defer exitCall(c)
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 	return c.context.GetEngine().SyncGetServiceRule(model.EventRouting, &req.GetServiceRuleRequest)
 }
 
+// GetRouteRuleWithContext 同GetRouteRule，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *consumerAPI) GetRouteRuleWithContext(ctx context.Context,
+	req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	reqCopy := *req
+	if err := applyContextDeadline(ctx, c.context, &reqCopy); err != nil {
+		return nil, err
+	}
+	return c.GetRouteRule(&reqCopy)
+}
+
+// GetCircuitBreakerRule 同步获取服务熔断规则
+func (c *consumerAPI) GetCircuitBreakerRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncGetServiceRule(model.EventCircuitBreaker, &req.GetServiceRuleRequest)
+}
+
 // GetServices 同步获取批量服务
 func (c *consumerAPI) GetServices(req *GetServicesRequest) (*model.ServicesResponse, error) {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return nil, err
 	}
+	defer exitCall(c)
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
@@ -104,9 +191,10 @@ func (c *consumerAPI) GetServices(req *GetServicesRequest) (*model.ServicesRespo
 
 // InitCalleeService 初始化服务运行中需要的被调服务
 func (c *consumerAPI) InitCalleeService(req *InitCalleeServiceRequest) error {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return err
 	}
+	defer exitCall(c)
 	if err := req.Validate(); err != nil {
 		return err
 	}
@@ -115,25 +203,127 @@ func (c *consumerAPI) InitCalleeService(req *InitCalleeServiceRequest) error {
 
 // WatchAllInstances 监听服务实例变更事件
 func (c *consumerAPI) WatchAllInstances(req *WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error) {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return nil, err
 	}
+	defer exitCall(c)
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 	return c.context.GetEngine().WatchAllInstances(&req.WatchAllInstancesRequest)
 }
 
+// WatchInstanceMetadata 监听服务实例的元数据变更
+func (c *consumerAPI) WatchInstanceMetadata(
+	req *WatchInstanceMetadataRequest) (*model.WatchAllInstancesResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().WatchInstanceMetadata(&req.WatchInstanceMetadataRequest)
+}
+
 func (c *consumerAPI) WatchAllServices(req *WatchAllServicesRequest) (*model.WatchAllServicesResponse, error) {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return nil, err
 	}
+	defer exitCall(c)
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 	return c.context.GetEngine().WatchAllServices(&req.WatchAllServicesRequest)
 }
 
+// InjectFault 根据故障注入规则计算本次调用需要注入的故障
+func (c *consumerAPI) InjectFault(req *InjectFaultRequest) (*model.InjectFaultResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncInjectFault(&req.InjectFaultRequest)
+}
+
+// ExtractTrafficLabels 从框架相关的请求对象中提取流量标签
+func (c *consumerAPI) ExtractTrafficLabels(
+	req *ExtractTrafficLabelsRequest) (*model.ExtractTrafficLabelsResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncExtractTrafficLabels(&req.ExtractTrafficLabelsRequest)
+}
+
+// RegisterFallback 注册实例全部熔断时的降级函数
+func (c *consumerAPI) RegisterFallback(req *RegisterFallbackRequest) error {
+	if err := enterCall(c); err != nil {
+		return err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	return c.context.GetEngine().RegisterInstanceFallback(&req.RegisterFallbackRequest)
+}
+
+// InvokeWithRetry 选择实例并执行带重试的调用
+func (c *consumerAPI) InvokeWithRetry(req *InvokeWithRetryRequest) (*model.InvokeWithRetryResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncInvokeWithRetry(&req.InvokeWithRetryRequest)
+}
+
+// GetInstancesForHedging 获取对冲请求的主实例及备份实例
+func (c *consumerAPI) GetInstancesForHedging(
+	req *GetInstancesForHedgingRequest) (*model.HedgingInstancesResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncGetInstancesForHedging(&req.GetInstancesForHedgingRequest)
+}
+
+// GetInstancesByHashKey 沿一致性hash环获取hash key对应的多个不同实例
+func (c *consumerAPI) GetInstancesByHashKey(
+	req *GetInstancesByHashKeyRequest) (*model.HashKeyInstancesResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncGetInstancesByHashKey(&req.GetInstancesByHashKeyRequest)
+}
+
+// Prefetch 后台预热服务实例及规则
+func (c *consumerAPI) Prefetch(req *PrefetchRequest) (*model.PrefetchResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().Prefetch(&req.PrefetchRequest), nil
+}
+
 // SDKContext 获取SDK上下文
 func (c *consumerAPI) SDKContext() SDKContext {
 	return c.context
@@ -148,9 +338,10 @@ func (c *consumerAPI) Destroy() {
 
 // WatchService 订阅服务消息
 func (c *consumerAPI) WatchService(req *WatchServiceRequest) (*model.WatchServiceResponse, error) {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return nil, err
 	}
+	defer exitCall(c)
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
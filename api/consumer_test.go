@@ -19,6 +19,7 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -75,3 +76,61 @@ func TestGetInstancesRequest_convert(t *testing.T) {
 		})
 	}
 }
+
+func TestReleaseGetOneInstanceRequest(t *testing.T) {
+	req := AcquireGetOneInstanceRequest()
+	req.Namespace = "Test"
+	req.Service = "svc"
+	req.Metadata = map[string]string{"env": "test"}
+	req.Arguments = []model.Argument{model.BuildHeaderArgument("uid", "123")}
+	req.SetTimeout(time.Second)
+	req.SetRetryCount(3)
+	ReleaseGetOneInstanceRequest(req)
+	assert.Equal(t, model.GetOneInstanceRequest{}, req.GetOneInstanceRequest)
+	assert.Nil(t, req.Timeout)
+	assert.Nil(t, req.RetryCount)
+	// 归还 nil 不应 panic
+	ReleaseGetOneInstanceRequest(nil)
+}
+
+var benchGetOneInstanceRequest *GetOneInstanceRequest
+
+func BenchmarkGetOneInstanceRequest(b *testing.B) {
+	fill := func(req *GetOneInstanceRequest) {
+		req.Namespace = "Test"
+		req.Service = "svc"
+		req.SetTimeout(time.Second)
+		req.SetRetryCount(1)
+	}
+	b.Run("新建请求", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			req := &GetOneInstanceRequest{}
+			fill(req)
+			benchGetOneInstanceRequest = req
+		}
+	})
+	b.Run("对象池", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			req := AcquireGetOneInstanceRequest()
+			fill(req)
+			ReleaseGetOneInstanceRequest(req)
+		}
+	})
+}
+
+func TestGetOneInstanceRequestCopy(t *testing.T) {
+	req := &GetOneInstanceRequest{}
+	req.SetTimeout(time.Second)
+	req.SetRetryCount(1)
+	copied := *req
+	// 超时及重试次数按值保存，修改或归还原请求不影响副本
+	req.SetTimeout(2 * time.Second)
+	req.SetRetryCount(2)
+	assert.Equal(t, time.Second, *copied.Timeout)
+	assert.Equal(t, 1, *copied.RetryCount)
+	req.Reset()
+	assert.Equal(t, time.Second, *copied.Timeout)
+	assert.Equal(t, 1, *copied.RetryCount)
+}
@@ -0,0 +1,76 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// timeoutRequest 支持设置超时时间及重试次数的请求对象
+type timeoutRequest interface {
+	// GetTimeoutPtr 获取超时值指针
+	GetTimeoutPtr() *time.Duration
+	// SetTimeout 设置超时时间
+	SetTimeout(duration time.Duration)
+	// GetRetryCountPtr 获取重试次数指针
+	GetRetryCountPtr() *int
+	// SetRetryCount 设置重试次数
+	SetRetryCount(retryCount int)
+	// SetContext 设置调用方的ctx
+	SetContext(ctx context.Context)
+}
+
+// applyContextDeadline 将ctx绑定到请求上，并将ctx的截止时间收敛到请求的超时时间上
+// ctx随请求传递到主流程的等待、重试逻辑及服务端连接器，调用过程中取消会中止等待及后续重试；
+// 同时收敛单次超时及重试次数，保证(1+重试次数)*超时时间不超过ctx的剩余时间
+// req需为调用方请求的副本，避免修改调用方的请求对象
+func applyContextDeadline(ctx context.Context, sdkCtx SDKContext, req timeoutRequest) error {
+	if err := ctx.Err(); err != nil {
+		return model.NewContextError(err)
+	}
+	req.SetContext(ctx)
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	remain := time.Until(deadline)
+	if remain <= 0 {
+		return model.NewContextError(context.DeadlineExceeded)
+	}
+	apiCfg := sdkCtx.GetConfig().GetGlobal().GetAPI()
+	timeout := apiCfg.GetTimeout()
+	if ptr := req.GetTimeoutPtr(); ptr != nil {
+		timeout = *ptr
+	}
+	retryCount := apiCfg.GetMaxRetryTimes()
+	if ptr := req.GetRetryCountPtr(); ptr != nil {
+		retryCount = *ptr
+	}
+	if timeout <= 0 || timeout >= remain {
+		req.SetTimeout(remain)
+		req.SetRetryCount(0)
+		return nil
+	}
+	if maxRetry := int(remain/timeout) - 1; retryCount > maxRetry {
+		req.SetRetryCount(maxRetry)
+	}
+	return nil
+}
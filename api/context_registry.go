@@ -0,0 +1,95 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+var (
+	namedContextMutex sync.RWMutex
+	namedContexts     = map[string]*sdkContext{}
+)
+
+// InitNamedContextByConfig 通过配置对象新建具名的SDK上下文，用于一个进程服务多个租户的场景，
+// 不同上下文的配置相互隔离，周期任务共用进程级调度器，开启 shareConnection 时复用到同一server地址的连接，
+// 上下文销毁后自动从注册表移除
+func InitNamedContextByConfig(name string, cfg config.Configuration) (SDKContext, error) {
+	if len(name) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "context name can not be empty")
+	}
+	if _, ok := GetContext(name); ok {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "context %s already exists", name)
+	}
+	ctx, err := InitContextByConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sdkCtx := ctx.(*sdkContext)
+	namedContextMutex.Lock()
+	if exists, ok := namedContexts[name]; ok && !exists.IsDestroyed() {
+		namedContextMutex.Unlock()
+		sdkCtx.Destroy()
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "context %s already exists", name)
+	}
+	sdkCtx.name = name
+	namedContexts[name] = sdkCtx
+	namedContextMutex.Unlock()
+	return sdkCtx, nil
+}
+
+// GetContext 根据名称获取已创建的SDK上下文
+func GetContext(name string) (SDKContext, bool) {
+	namedContextMutex.RLock()
+	defer namedContextMutex.RUnlock()
+	ctx, ok := namedContexts[name]
+	if !ok || ctx.IsDestroyed() {
+		return nil, false
+	}
+	return ctx, true
+}
+
+// GetContextNames 获取所有已创建的SDK上下文名称
+func GetContextNames() []string {
+	namedContextMutex.RLock()
+	defer namedContextMutex.RUnlock()
+	names := make([]string, 0, len(namedContexts))
+	for name, ctx := range namedContexts {
+		if !ctx.IsDestroyed() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unregisterNamedContext 上下文销毁时从注册表移除
+func unregisterNamedContext(ctx *sdkContext) {
+	if len(ctx.name) == 0 {
+		return
+	}
+	namedContextMutex.Lock()
+	defer namedContextMutex.Unlock()
+	if exists, ok := namedContexts[ctx.name]; ok && exists == ctx {
+		delete(namedContexts, ctx.name)
+	}
+}
@@ -18,6 +18,7 @@
 package api
 
 import (
+	"context"
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -50,6 +51,10 @@ type QuotaRequest interface {
 
 	// SetRetryCount 设置最大重试次数
 	SetRetryCount(retryCount int)
+
+	// SetThrottling 开启匀速排队模式，配额不足时不直接拒绝，而是返回需要等待的时间（QuotaResponse.WaitMs），
+	// 调用QuotaFuture.Get会阻塞到等待结束，排队时间超过maxQueueTime时才拒绝
+	SetThrottling(maxQueueTime time.Duration)
 }
 
 // NewQuotaRequest 创建配额查询请求
@@ -65,7 +70,9 @@ type QuotaFuture interface {
 	Get() *model.QuotaResponse
 	// GetImmediately 立刻获取分配结果，不等待
 	GetImmediately() *model.QuotaResponse
-	// Release 释放资源，仅用于并发数限流的场景
+	// Release 释放配额，分配成功后必须在请求结束时调用，多次调用只会释放一次
+	// 并发数限流及自适应限流（adaptive）依赖该调用统计在途请求数及请求耗时，
+	// 未调用时在途请求数只增不减，会导致持续限流
 	Release()
 }
 
@@ -81,6 +88,8 @@ type LimitAPI interface {
 	SDKOwner
 	// GetQuota 获取限流配额，一次接口只获取一个配额
 	GetQuota(request QuotaRequest) (QuotaFuture, error)
+	// GetQuotaWithContext 同GetQuota，调用过程中ctx取消或超时会中止等待及后续重试
+	GetQuotaWithContext(ctx context.Context, request QuotaRequest) (QuotaFuture, error)
 	// Destroy 销毁API，销毁后无法再进行调用
 	Destroy()
 }
@@ -18,6 +18,8 @@
 package api
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
@@ -34,9 +36,10 @@ func (c *limitAPI) SDKContext() SDKContext {
 
 // GetQuota 获取限流配额
 func (c *limitAPI) GetQuota(request QuotaRequest) (QuotaFuture, error) {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return nil, err
 	}
+	defer exitCall(c)
 	mRequest := request.(*model.QuotaRequestImpl)
 	if err := mRequest.Validate(); err != nil {
 		return nil, err
@@ -44,6 +47,19 @@ func (c *limitAPI) GetQuota(request QuotaRequest) (QuotaFuture, error) {
 	return c.context.GetEngine().AsyncGetQuota(mRequest)
 }
 
+// GetQuotaWithContext 同GetQuota，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *limitAPI) GetQuotaWithContext(ctx context.Context, request QuotaRequest) (QuotaFuture, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	reqCopy := *request.(*model.QuotaRequestImpl)
+	if err := applyContextDeadline(ctx, c.context, &reqCopy); err != nil {
+		return nil, err
+	}
+	return c.GetQuota(&reqCopy)
+}
+
 // Destroy 销毁API
 func (c *limitAPI) Destroy() {
 	if nil != c.context {
@@ -24,12 +24,16 @@ import (
 	"github.com/hashicorp/go-multierror"
 
 	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/register"
 )
 
 // Logger 别名类
 type Logger log.Logger
 
+// StructuredLogger 支持结构化字段的日志对象
+type StructuredLogger log.StructuredLogger
+
 // 日志级别
 const (
 	// TraceLog 跟踪级别
@@ -244,3 +248,30 @@ func SetLoggersDir(logDir string) error {
 	}
 	return errs
 }
+
+// SetModuleLogLevel 运行时调整指定日志模块的打印级别，module 取值为 base、stat、statReport、detect、network、cache
+func SetModuleLogLevel(module string, level int) error {
+	return log.SetModuleLogLevel(module, level)
+}
+
+// GetModuleLogLevels 获取各日志模块当前生效的打印级别
+func GetModuleLogLevels() map[string]string {
+	return log.GetModuleLogLevels()
+}
+
+// WatchModuleLogLevels 以配置中心的配置文件内容作为各日志模块的打印级别，并在配置变更时动态调整，
+// 文件内容每行格式为 module=level，module 为 * 时对所有模块生效
+func WatchModuleLogLevels(file model.ConfigFile) error {
+	if file.HasContent() {
+		if err := log.ApplyModuleLogLevels(file.GetContent()); err != nil {
+			return err
+		}
+	}
+	file.AddChangeListener(func(event model.ConfigFileChangeEvent) {
+		if err := log.ApplyModuleLogLevels(event.NewValue); err != nil {
+			log.GetBaseLogger().Errorf("fail to apply log levels from config file %s: %v",
+				event.ConfigFileMetadata.GetFileName(), err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,192 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Option 以函数式选项的方式设置SDK配置，未设置的配置项沿用默认配置
+type Option func(opts *options) error
+
+// options 构建SDK上下文使用的选项
+type options struct {
+	cfg    config.Configuration
+	logger Logger
+}
+
+// WithServerAddress 设置服务发现server地址，格式为<host>:<port>
+func WithServerAddress(addresses ...string) Option {
+	return func(opts *options) error {
+		if len(addresses) == 0 {
+			return errors.New("server address is empty")
+		}
+		opts.cfg.GetGlobal().GetServerConnector().SetAddresses(addresses)
+		return nil
+	}
+}
+
+// WithConfigAddress 设置配置中心server地址，格式为<host>:<port>
+func WithConfigAddress(addresses ...string) Option {
+	return func(opts *options) error {
+		if len(addresses) == 0 {
+			return errors.New("config address is empty")
+		}
+		opts.cfg.GetConfigFile().GetConfigConnectorConfig().SetAddresses(addresses)
+		return nil
+	}
+}
+
+// WithToken 设置访问server的鉴权token，服务发现与配置中心共用
+func WithToken(token string) Option {
+	return func(opts *options) error {
+		opts.cfg.GetGlobal().GetServerConnector().SetToken(token)
+		opts.cfg.GetConfigFile().GetConfigConnectorConfig().SetToken(token)
+		return nil
+	}
+}
+
+// WithConnectTimeout 设置与server的建连超时时间
+func WithConnectTimeout(timeout time.Duration) Option {
+	return func(opts *options) error {
+		if timeout <= 0 {
+			return errors.New("connect timeout must be greater than 0")
+		}
+		opts.cfg.GetGlobal().GetServerConnector().SetConnectTimeout(timeout)
+		return nil
+	}
+}
+
+// WithLoadBalancer 设置默认的负载均衡插件，如 weightedRandom、ringHash、p2c
+func WithLoadBalancer(lbType string) Option {
+	return func(opts *options) error {
+		if len(lbType) == 0 {
+			return errors.New("load balancer type is empty")
+		}
+		opts.cfg.GetConsumer().GetLoadbalancer().SetType(lbType)
+		return nil
+	}
+}
+
+// WithLocalCacheDir 设置服务数据本地缓存的持久化目录
+func WithLocalCacheDir(dir string) Option {
+	return func(opts *options) error {
+		if len(dir) == 0 {
+			return errors.New("local cache dir is empty")
+		}
+		opts.cfg.GetConsumer().GetLocalCache().SetPersistDir(dir)
+		return nil
+	}
+}
+
+// WithLogger 设置基础日志对象，日志对象为进程级，对所有SDK上下文生效
+func WithLogger(logger Logger) Option {
+	return func(opts *options) error {
+		if nil == logger {
+			return errors.New("logger is nil")
+		}
+		opts.logger = logger
+		return nil
+	}
+}
+
+// WithConfiguration 通过回调直接修改配置对象，用于设置没有对应选项的配置项
+func WithConfiguration(modify func(cfg config.Configuration)) Option {
+	return func(opts *options) error {
+		if nil == modify {
+			return errors.New("configuration modifier is nil")
+		}
+		modify(opts.cfg)
+		return nil
+	}
+}
+
+// WithFlagOverlay 使用 -polaris.<path>=<value> 形式的命令行参数覆盖配置项，如 os.Args[1:]，
+// 与其他选项按传入顺序生效
+func WithFlagOverlay(args []string) Option {
+	return func(opts *options) error {
+		cfg, ok := opts.cfg.(*config.ConfigurationImpl)
+		if !ok {
+			return errors.New("flag overlay requires *config.ConfigurationImpl")
+		}
+		return config.ApplyFlagOverlay(cfg, args)
+	}
+}
+
+// NewConfigurationWithOptions 在默认配置的基础上应用选项，生成配置对象
+func NewConfigurationWithOptions(opts ...Option) (config.Configuration, error) {
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return o.cfg, nil
+}
+
+// NewSDKContextWithOptions 在默认配置的基础上应用选项，创建SDK上下文
+func NewSDKContextWithOptions(opts ...Option) (SDKContext, error) {
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if o.logger != nil {
+		SetBaseLogger(o.logger)
+	}
+	return InitContextByConfig(o.cfg)
+}
+
+// NewConsumerAPIWithOptions 在默认配置的基础上应用选项，创建ConsumerAPI
+func NewConsumerAPIWithOptions(opts ...Option) (ConsumerAPI, error) {
+	context, err := NewSDKContextWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newConsumerAPIByContext(context), nil
+}
+
+// NewProviderAPIWithOptions 在默认配置的基础上应用选项，创建ProviderAPI
+func NewProviderAPIWithOptions(opts ...Option) (ProviderAPI, error) {
+	context, err := NewSDKContextWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newProviderAPIByContext(context), nil
+}
+
+// applyOptions 依次应用选项，汇总所有选项的错误
+func applyOptions(opts []Option) (*options, error) {
+	o := &options{cfg: config.NewDefaultConfigurationWithDomain()}
+	var errs error
+	for _, opt := range opts {
+		if nil == opt {
+			continue
+		}
+		if err := opt(o); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	if errs != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, errs, "fail to apply options")
+	}
+	return o, nil
+}
@@ -18,6 +18,8 @@
 package api
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -36,10 +38,32 @@ type InstanceRegisterRequest struct {
 	model.InstanceRegisterRequest
 }
 
+// BatchRegisterRequest 批量注册请求
+type BatchRegisterRequest struct {
+	model.BatchRegisterRequest
+}
+
+// BatchHeartbeatRequest 批量心跳上报请求
+type BatchHeartbeatRequest struct {
+	model.BatchHeartbeatRequest
+}
+
+// InstanceMetadataUpdateRequest 实例元数据更新请求
+type InstanceMetadataUpdateRequest struct {
+	model.InstanceMetadataUpdateRequest
+}
+
+// InstanceGracefulDeregisterRequest 优雅下线请求
+type InstanceGracefulDeregisterRequest struct {
+	model.InstanceGracefulDeregisterRequest
+}
+
 // ProviderAPI CL5服务端API的主接口
 type ProviderAPI interface {
 	SDKOwner
-	// RegisterInstance
+	// RegisterInstance 注册实例，并由SDK自动维护TTL心跳
+	// 心跳失败时按指数退避重试，服务端不存在该实例时自动重新注册，
+	// 调用Deregister或销毁SDK时心跳任务自动停止
 	// minimum supported version of polaris-server is v1.10.0
 	RegisterInstance(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
 	// Register
@@ -49,9 +73,31 @@ type ProviderAPI interface {
 	Register(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
 	// Deregister synchronize the anti registration service
 	Deregister(instance *InstanceDeRegisterRequest) error
+	// GracefulDeregister 优雅下线，先将实例置为隔离使新流量不再进入，
+	// 等待排空时长结束或在途请求归零后，再进行反注册
+	GracefulDeregister(instance *InstanceGracefulDeregisterRequest) error
 	// Heartbeat the heartbeat report
 	// Deprecated: Use RegisterInstance instead.
 	Heartbeat(instance *InstanceHeartbeatRequest) error
+	// UpdateInstanceMetadata 更新已注册实例的元数据及权重，无需反注册
+	// 更新异步推送到服务端，最小推送间隔内的多次更新会被合并
+	UpdateInstanceMetadata(req *InstanceMetadataUpdateRequest) error
+	// AddLoadSampler 添加自适应权重使用的负载采样器，实例负载取所有采样器的最大值
+	// 需开启 provider.adaptiveWeight.enable
+	AddLoadSampler(sampler model.LoadSampler)
+	// RegisterInstances 并发注册多个实例，返回与请求一一对应的结果，单个实例失败不影响其他实例
+	RegisterInstances(req *BatchRegisterRequest) (*model.BatchRegisterResponse, error)
+	// HeartbeatInstances 并发上报多个实例的心跳，返回与请求一一对应的结果
+	HeartbeatInstances(req *BatchHeartbeatRequest) (*model.BatchHeartbeatResponse, error)
+	// RegisterInstanceWithContext 同RegisterInstance，调用过程中ctx取消或超时会中止等待及后续重试
+	RegisterInstanceWithContext(ctx context.Context,
+		instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// RegisterWithContext 同Register，调用过程中ctx取消或超时会中止等待及后续重试
+	RegisterWithContext(ctx context.Context, instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// DeregisterWithContext 同Deregister，调用过程中ctx取消或超时会中止等待及后续重试
+	DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error
+	// HeartbeatWithContext 同Heartbeat，调用过程中ctx取消或超时会中止等待及后续重试
+	HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error
 	// Destroy the api is destroyed and cannot be called again
 	Destroy()
 }
@@ -18,6 +18,8 @@
 package api
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/register"
@@ -32,9 +34,10 @@ type providerAPI struct {
 // the Instance ID field in Instance is filled
 // minimum supported version of polaris-server is v1.10.0
 func (c *providerAPI) RegisterInstance(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return nil, err
 	}
+	defer exitCall(c)
 	if err := instance.Validate(); err != nil {
 		return nil, err
 	}
@@ -45,9 +48,10 @@ func (c *providerAPI) RegisterInstance(instance *InstanceRegisterRequest) (*mode
 // Register 同步注册服务，服务注册成功后会填充instance中的InstanceId字段
 // 用户可保持该instance对象用于反注册和心跳上报
 func (c *providerAPI) Register(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return nil, err
 	}
+	defer exitCall(c)
 	if err := instance.Validate(); err != nil {
 		return nil, err
 	}
@@ -56,26 +60,135 @@ func (c *providerAPI) Register(instance *InstanceRegisterRequest) (*model.Instan
 
 // Deregister 同步反注册服务
 func (c *providerAPI) Deregister(instance *InstanceDeRegisterRequest) error {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return err
 	}
+	defer exitCall(c)
 	if err := instance.Validate(); err != nil {
 		return err
 	}
 	return c.context.GetEngine().SyncDeregister(&instance.InstanceDeRegisterRequest)
 }
 
+// GracefulDeregister 优雅下线
+func (c *providerAPI) GracefulDeregister(instance *InstanceGracefulDeregisterRequest) error {
+	if err := enterCall(c); err != nil {
+		return err
+	}
+	defer exitCall(c)
+	if err := instance.Validate(); err != nil {
+		return err
+	}
+	return c.context.GetEngine().SyncGracefulDeregister(&instance.InstanceGracefulDeregisterRequest)
+}
+
 // Heartbeat 心跳上报
 func (c *providerAPI) Heartbeat(instance *InstanceHeartbeatRequest) error {
-	if err := checkAvailable(c); err != nil {
+	if err := enterCall(c); err != nil {
 		return err
 	}
+	defer exitCall(c)
 	if err := instance.Validate(); err != nil {
 		return err
 	}
 	return c.context.GetEngine().SyncHeartbeat(&instance.InstanceHeartbeatRequest)
 }
 
+// UpdateInstanceMetadata 更新已注册实例的元数据及权重
+func (c *providerAPI) UpdateInstanceMetadata(req *InstanceMetadataUpdateRequest) error {
+	if err := enterCall(c); err != nil {
+		return err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	return c.context.GetEngine().SyncUpdateInstanceMetadata(&req.InstanceMetadataUpdateRequest)
+}
+
+// AddLoadSampler 添加自适应权重使用的负载采样器
+func (c *providerAPI) AddLoadSampler(sampler model.LoadSampler) {
+	c.context.GetEngine().AddLoadSampler(sampler)
+}
+
+// RegisterInstances 并发注册多个实例
+func (c *providerAPI) RegisterInstances(req *BatchRegisterRequest) (*model.BatchRegisterResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncBatchRegister(&req.BatchRegisterRequest), nil
+}
+
+// HeartbeatInstances 并发上报多个实例的心跳
+func (c *providerAPI) HeartbeatInstances(req *BatchHeartbeatRequest) (*model.BatchHeartbeatResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncBatchHeartbeat(&req.BatchHeartbeatRequest), nil
+}
+
+// RegisterInstanceWithContext 同RegisterInstance，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *providerAPI) RegisterInstanceWithContext(ctx context.Context,
+	instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	reqCopy := *instance
+	if err := applyContextDeadline(ctx, c.context, &reqCopy); err != nil {
+		return nil, err
+	}
+	return c.RegisterInstance(&reqCopy)
+}
+
+// RegisterWithContext 同Register，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *providerAPI) RegisterWithContext(ctx context.Context,
+	instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	if err := enterCall(c); err != nil {
+		return nil, err
+	}
+	defer exitCall(c)
+	reqCopy := *instance
+	if err := applyContextDeadline(ctx, c.context, &reqCopy); err != nil {
+		return nil, err
+	}
+	return c.Register(&reqCopy)
+}
+
+// DeregisterWithContext 同Deregister，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *providerAPI) DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error {
+	if err := enterCall(c); err != nil {
+		return err
+	}
+	defer exitCall(c)
+	reqCopy := *instance
+	if err := applyContextDeadline(ctx, c.context, &reqCopy); err != nil {
+		return err
+	}
+	return c.Deregister(&reqCopy)
+}
+
+// HeartbeatWithContext 同Heartbeat，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *providerAPI) HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error {
+	if err := enterCall(c); err != nil {
+		return err
+	}
+	defer exitCall(c)
+	reqCopy := *instance
+	if err := applyContextDeadline(ctx, c.context, &reqCopy); err != nil {
+		return err
+	}
+	return c.Heartbeat(&reqCopy)
+}
+
 // SDKContext 获取SDK上下文
 func (c *providerAPI) SDKContext() SDKContext {
 	return c.context
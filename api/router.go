@@ -0,0 +1,56 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package api
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// ProcessRoutersRequest 执行路由链的请求对象
+// DstInstances可以是ConsumerAPI.GetAllInstances的返回值，也可以是业务框架自行维护的实例列表(model.DefaultServiceInstances)
+type ProcessRoutersRequest struct {
+	model.ProcessRoutersRequest
+}
+
+// ProcessLoadBalanceRequest 执行负载均衡的请求对象
+type ProcessLoadBalanceRequest struct {
+	model.ProcessLoadBalanceRequest
+}
+
+// RouterAPI 路由及负载均衡API，供自行维护实例列表的业务框架复用北极星的路由能力
+type RouterAPI interface {
+	SDKOwner
+	// ProcessRouters 对传入的实例列表执行路由链过滤
+	ProcessRouters(*ProcessRoutersRequest) (*model.InstancesResponse, error)
+	// ProcessLoadBalance 对传入的实例列表执行负载均衡，选出一个实例
+	ProcessLoadBalance(*ProcessLoadBalanceRequest) (*model.OneInstanceResponse, error)
+	// Destroy 销毁API，销毁后无法再进行调用
+	Destroy()
+}
+
+var (
+	// NewRouterAPI 通过以默认域名为埋点server的默认配置创建RouterAPI
+	NewRouterAPI = newRouterAPI
+	// NewRouterAPIByConfig 通过配置对象创建RouterAPI
+	NewRouterAPIByConfig = newRouterAPIByConfig
+	// NewRouterAPIByContext 通过sdkContext创建RouterAPI
+	NewRouterAPIByContext = newRouterAPIByContext
+	// NewRouterAPIByFile 通过配置文件创建SDK RouterAPI对象
+	NewRouterAPIByFile = newRouterAPIByFile
+	// NewRouterAPIByAddress 通过address创建RouterAPI
+	NewRouterAPIByAddress = newRouterAPIByAddress
+)
@@ -0,0 +1,110 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package api
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// routerAPI 路由API对象
+type routerAPI struct {
+	context SDKContext
+}
+
+// SDKContext 获取SDK上下文
+func (r *routerAPI) SDKContext() SDKContext {
+	return r.context
+}
+
+// ProcessRouters 执行路由链过滤
+func (r *routerAPI) ProcessRouters(request *ProcessRoutersRequest) (*model.InstancesResponse, error) {
+	if err := enterCall(r); err != nil {
+		return nil, err
+	}
+	defer exitCall(r)
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	argumentsToLabels(&request.ProcessRoutersRequest)
+	return r.context.GetEngine().ProcessRouters(&request.ProcessRoutersRequest)
+}
+
+// ProcessLoadBalance 执行负载均衡
+func (r *routerAPI) ProcessLoadBalance(request *ProcessLoadBalanceRequest) (*model.OneInstanceResponse, error) {
+	if err := enterCall(r); err != nil {
+		return nil, err
+	}
+	defer exitCall(r)
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	return r.context.GetEngine().ProcessLoadBalance(&request.ProcessLoadBalanceRequest)
+}
+
+// Destroy 销毁API
+func (r *routerAPI) Destroy() {
+	if nil != r.context {
+		r.context.Destroy()
+	}
+}
+
+// argumentsToLabels 将主调的流量标签合并到主调服务的元数据中，供路由规则匹配
+func argumentsToLabels(request *model.ProcessRoutersRequest) {
+	if len(request.Arguments) == 0 {
+		return
+	}
+	if len(request.SourceService.Metadata) == 0 {
+		request.SourceService.Metadata = map[string]string{}
+	}
+	for i := range request.Arguments {
+		request.Arguments[i].ToLabels(request.SourceService.Metadata)
+	}
+}
+
+// newRouterAPI 通过以默认域名为埋点server的默认配置创建RouterAPI
+func newRouterAPI() (RouterAPI, error) {
+	return newRouterAPIByConfig(config.NewDefaultConfigurationWithDomain())
+}
+
+// newRouterAPIByConfig 通过配置对象创建SDK RouterAPI对象
+func newRouterAPIByConfig(cfg config.Configuration) (RouterAPI, error) {
+	context, err := InitContextByConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &routerAPI{context}, nil
+}
+
+// newRouterAPIByContext 通过上下文创建SDK RouterAPI对象
+func newRouterAPIByContext(context SDKContext) RouterAPI {
+	return &routerAPI{context}
+}
+
+// newRouterAPIByFile 通过配置文件创建SDK RouterAPI对象
+func newRouterAPIByFile(path string) (RouterAPI, error) {
+	context, err := InitContextByFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &routerAPI{context: context}, nil
+}
+
+func newRouterAPIByAddress(address ...string) (RouterAPI, error) {
+	conf := config.NewDefaultConfiguration(address)
+	return newRouterAPIByConfig(conf)
+}
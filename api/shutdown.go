@@ -0,0 +1,69 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// RegisterShutdownHook 监听SIGTERM及SIGINT信号，收到信号后对instance执行优雅下线
+// 下线完成后调用onShutdown，onShutdown为空时以退出码0结束进程；返回的函数用于取消监听
+func RegisterShutdownHook(provider ProviderAPI, instance *InstanceGracefulDeregisterRequest,
+	onShutdown func(err error)) func() {
+	return RegisterShutdownHookFunc(func() error {
+		return provider.GracefulDeregister(instance)
+	}, onShutdown)
+}
+
+// RegisterShutdownHookFunc 监听SIGTERM及SIGINT信号，收到信号后执行deregister
+// 下线完成后调用onShutdown，onShutdown为空时以退出码0结束进程；返回的函数用于取消监听
+func RegisterShutdownHookFunc(deregister func() error, onShutdown func(err error)) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-done:
+			return
+		case sig := <-signals:
+			signal.Stop(signals)
+			log.GetBaseLogger().Infof("[Provider][ShutdownHook] receive signal %v, start graceful deregister", sig)
+			err := deregister()
+			if err != nil {
+				log.GetBaseLogger().Errorf("[Provider][ShutdownHook] fail to deregister, err %v", err)
+			}
+			if onShutdown != nil {
+				onShutdown(err)
+				return
+			}
+			os.Exit(0)
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
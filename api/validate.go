@@ -0,0 +1,92 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// ValidateConfigFile 严格校验配置文件，只做校验而不创建SDK上下文，可用于在CI中检查配置文件
+// 全部问题会被一次性返回，错误原因的类型为 *config.ValidationErrors
+func ValidateConfigFile(path string) error {
+	if !model.IsFile(path) {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "invalid context file %s", path)
+	}
+	buff, err := ioutil.ReadFile(path)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "fail to read context file %s", path)
+	}
+	return ValidateConfigStream(buff)
+}
+
+// ValidateConfigStream 严格校验YAML配置内容，除配置本身的校验外，还会检查引用的插件是否已注册
+func ValidateConfigStream(buf []byte) error {
+	return config.ValidateConfiguration(buf, verifyPluginNames)
+}
+
+// verifyPluginNames 检查配置中引用的插件是否已注册
+func verifyPluginNames(cfg *config.ConfigurationImpl, errs *config.ValidationErrors) {
+	check := func(path string, typ common.Type, names ...string) {
+		for i, name := range names {
+			if plugin.IsPluginRegistered(typ, name) {
+				continue
+			}
+			itemPath := path
+			if len(names) > 1 {
+				itemPath = fmt.Sprintf("%s[%d]", path, i)
+			}
+			errs.Add(itemPath, "%s: unknown %s plugin %q", itemPath, typ, name)
+		}
+	}
+	global := cfg.GetGlobal()
+	check("global.serverConnector.protocol", common.TypeServerConnector, global.GetServerConnector().GetProtocol())
+	if global.GetStatReporter().IsEnable() {
+		check("global.statReporter.chain", common.TypeStatReporter, global.GetStatReporter().GetChain()...)
+	}
+	if global.GetEventReporter().IsEnable() {
+		check("global.eventReporter.chain", common.TypeEventReporter, global.GetEventReporter().GetChain()...)
+	}
+	if global.GetIdentityProvider().IsEnable() {
+		check("global.identityProvider.type", common.TypeIdentityProvider, global.GetIdentityProvider().GetType())
+	}
+	consumer := cfg.GetConsumer()
+	check("consumer.localCache.type", common.TypeLocalRegistry, consumer.GetLocalCache().GetType())
+	check("consumer.serviceRouter.chain", common.TypeServiceRouter, consumer.GetServiceRouter().GetChain()...)
+	check("consumer.serviceRouter.afterChain", common.TypeServiceRouter,
+		consumer.GetServiceRouter().GetAfterChain()...)
+	check("consumer.loadbalancer.type", common.TypeLoadBalancer, consumer.GetLoadbalancer().GetType())
+	if consumer.GetCircuitBreaker().IsEnable() {
+		check("consumer.circuitBreaker.chain", common.TypeCircuitBreaker, consumer.GetCircuitBreaker().GetChain()...)
+	}
+	check("consumer.healthCheck.chain", common.TypeHealthCheck, consumer.GetHealthCheck().GetChain()...)
+	configFile := cfg.GetConfigFile()
+	if configFile.IsEnable() {
+		check("config.configConnector.protocol", common.TypeConfigConnector,
+			configFile.GetConfigConnectorConfig().GetProtocol())
+		if configFile.GetConfigFilterConfig().IsEnable() {
+			check("config.configFilter.chain", common.TypeConfigFilter,
+				configFile.GetConfigFilterConfig().GetChain()...)
+		}
+	}
+}
@@ -73,11 +73,27 @@ func (c *circuitBreakerAPI) Check(res model.Resource) (*model.CheckResult, error
 	return c.rawAPI.Check(res)
 }
 
+// CheckResource 检查任意资源的熔断状态
+func (c *circuitBreakerAPI) CheckResource(res model.Resource) (*model.CheckResult, error) {
+	return c.rawAPI.CheckResource(res)
+}
+
+// DoWithCircuitBreaker 在熔断保护下执行fn并自动上报结果
+func (c *circuitBreakerAPI) DoWithCircuitBreaker(res model.Resource, fn func() error,
+	fallback model.FallbackFunction) error {
+	return c.rawAPI.DoWithCircuitBreaker(res, fn, fallback)
+}
+
 // Report
 func (c *circuitBreakerAPI) Report(stat *model.ResourceStat) error {
 	return c.rawAPI.Report(stat)
 }
 
+// AddStatusListener 添加熔断状态监听器
+func (c *circuitBreakerAPI) AddStatusListener(listener model.CircuitBreakerStatusListener) error {
+	return c.rawAPI.AddStatusListener(listener)
+}
+
 // MakeFunctionDecorator
 func (c *circuitBreakerAPI) MakeFunctionDecorator(f model.CustomerFunction, reqCtx *api.RequestContext) model.DecoratorFunction {
 	return c.rawAPI.MakeFunctionDecorator(f, reqCtx)
@@ -93,6 +93,26 @@ func (c *configAPI) PublishConfigFile(namespace, fileGroup, fileName string) err
 	return c.rawAPI.PublishConfigFile(namespace, fileGroup, fileName)
 }
 
+// CreateConfigFileWithReq 创建配置文件
+func (c *configAPI) CreateConfigFileWithReq(req *ConfigFileModifyRequest) error {
+	return c.rawAPI.CreateConfigFileWithReq((*api.ConfigFileModifyRequest)(req))
+}
+
+// UpdateConfigFileWithReq 更新配置文件
+func (c *configAPI) UpdateConfigFileWithReq(req *ConfigFileModifyRequest) error {
+	return c.rawAPI.UpdateConfigFileWithReq((*api.ConfigFileModifyRequest)(req))
+}
+
+// PublishConfigFileWithReq 发布配置文件
+func (c *configAPI) PublishConfigFileWithReq(req *ConfigFilePublishRequest) error {
+	return c.rawAPI.PublishConfigFileWithReq((*api.ConfigFilePublishRequest)(req))
+}
+
+// RollbackConfigFile 回滚配置文件
+func (c *configAPI) RollbackConfigFile(req *ConfigFileRollbackRequest) error {
+	return c.rawAPI.RollbackConfigFile((*api.ConfigFileRollbackRequest)(req))
+}
+
 // SDKContext 获取SDK上下文
 func (c *configAPI) SDKContext() api.SDKContext {
 	return c.rawAPI.SDKContext()
@@ -152,6 +172,27 @@ func (c *configGroupAPI) FetchConfigGroup(req *GetConfigGroupRequest) (model.Con
 	return c.rawAPI.FetchConfigGroup((*api.GetConfigGroupRequest)(req))
 }
 
+// ListConfigFiles 列出配置分组下所有已发布的配置文件
+func (c *configGroupAPI) ListConfigFiles(namespace, group string) ([]*model.SimpleConfigFile, error) {
+	return c.rawAPI.ListConfigFiles(namespace, group)
+}
+
+// FetchConfigFiles 获取配置分组下所有配置文件的内容
+func (c *configGroupAPI) FetchConfigFiles(namespace, group string) ([]model.ConfigFile, error) {
+	return c.rawAPI.FetchConfigFiles(namespace, group)
+}
+
+// WatchConfigGroup 监听配置分组
+func (c *configGroupAPI) WatchConfigGroup(namespace, group string, cb model.OnConfigFileChange) error {
+	return c.rawAPI.WatchConfigGroup(namespace, group, cb)
+}
+
+// WatchConfigFilesByPattern 按文件名通配符监听配置分组
+func (c *configGroupAPI) WatchConfigFilesByPattern(namespace, group, pattern string,
+	cb model.OnConfigFileChange) error {
+	return c.rawAPI.WatchConfigFilesByPattern(namespace, group, pattern, cb)
+}
+
 // SDKContext 获取SDK上下文
 func (c *configGroupAPI) SDKContext() api.SDKContext {
 	return c.rawAPI.SDKContext()
@@ -18,6 +18,8 @@
 package polaris
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -53,6 +55,40 @@ func (c *consumerAPI) GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRu
 	return c.rawAPI.GetRouteRule((*api.GetServiceRuleRequest)(req))
 }
 
+// GetOneInstanceWithContext 同GetOneInstance，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *consumerAPI) GetOneInstanceWithContext(ctx context.Context,
+	req *GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	return c.rawAPI.GetOneInstanceWithContext(ctx, (*api.GetOneInstanceRequest)(req))
+}
+
+// GetInstancesWithContext 同GetInstances，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *consumerAPI) GetInstancesWithContext(ctx context.Context,
+	req *GetInstancesRequest) (*model.InstancesResponse, error) {
+	return c.rawAPI.GetInstancesWithContext(ctx, (*api.GetInstancesRequest)(req))
+}
+
+// GetAllInstancesWithContext 同GetAllInstances，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *consumerAPI) GetAllInstancesWithContext(ctx context.Context,
+	req *GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	return c.rawAPI.GetAllInstancesWithContext(ctx, (*api.GetAllInstancesRequest)(req))
+}
+
+// IterateInstances 分批遍历服务的全部实例，支持按实例元数据在服务端过滤
+func (c *consumerAPI) IterateInstances(req *IterateInstancesRequest) (*model.InstancesIterator, error) {
+	return c.rawAPI.IterateInstances((*api.IterateInstancesRequest)(req))
+}
+
+// GetRouteRuleWithContext 同GetRouteRule，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *consumerAPI) GetRouteRuleWithContext(ctx context.Context,
+	req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
+	return c.rawAPI.GetRouteRuleWithContext(ctx, (*api.GetServiceRuleRequest)(req))
+}
+
+// GetCircuitBreakerRule 同步获取服务熔断规则
+func (c *consumerAPI) GetCircuitBreakerRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
+	return c.rawAPI.GetCircuitBreakerRule((*api.GetServiceRuleRequest)(req))
+}
+
 // UpdateServiceCallResult 上报服务调用结果
 func (c *consumerAPI) UpdateServiceCallResult(req *ServiceCallResult) error {
 	return c.rawAPI.UpdateServiceCallResult((*api.ServiceCallResult)(req))
@@ -78,11 +114,55 @@ func (c *consumerAPI) WatchAllInstances(req *WatchAllInstancesRequest) (*model.W
 	return c.rawAPI.WatchAllInstances((*api.WatchAllInstancesRequest)(req))
 }
 
+// WatchInstanceMetadata 监听服务实例的元数据变更
+func (c *consumerAPI) WatchInstanceMetadata(
+	req *WatchInstanceMetadataRequest) (*model.WatchAllInstancesResponse, error) {
+	return c.rawAPI.WatchInstanceMetadata((*api.WatchInstanceMetadataRequest)(req))
+}
+
 // WatchAllServices 监听服务列表变更事件
 func (c *consumerAPI) WatchAllServices(req *WatchAllServicesRequest) (*model.WatchAllServicesResponse, error) {
 	return c.rawAPI.WatchAllServices((*api.WatchAllServicesRequest)(req))
 }
 
+// InjectFault 根据故障注入规则计算本次调用需要注入的故障
+func (c *consumerAPI) InjectFault(req *InjectFaultRequest) (*model.InjectFaultResponse, error) {
+	return c.rawAPI.InjectFault((*api.InjectFaultRequest)(req))
+}
+
+// ExtractTrafficLabels 从框架相关的请求对象中提取流量标签
+func (c *consumerAPI) ExtractTrafficLabels(
+	req *ExtractTrafficLabelsRequest) (*model.ExtractTrafficLabelsResponse, error) {
+	return c.rawAPI.ExtractTrafficLabels((*api.ExtractTrafficLabelsRequest)(req))
+}
+
+// RegisterFallback 注册实例全部熔断时的降级函数
+func (c *consumerAPI) RegisterFallback(req *RegisterFallbackRequest) error {
+	return c.rawAPI.RegisterFallback((*api.RegisterFallbackRequest)(req))
+}
+
+// InvokeWithRetry 选择实例并执行带重试的调用
+func (c *consumerAPI) InvokeWithRetry(req *InvokeWithRetryRequest) (*model.InvokeWithRetryResponse, error) {
+	return c.rawAPI.InvokeWithRetry((*api.InvokeWithRetryRequest)(req))
+}
+
+// GetInstancesForHedging 获取对冲请求的主实例、备份实例及对冲延迟
+func (c *consumerAPI) GetInstancesForHedging(
+	req *GetInstancesForHedgingRequest) (*model.HedgingInstancesResponse, error) {
+	return c.rawAPI.GetInstancesForHedging((*api.GetInstancesForHedgingRequest)(req))
+}
+
+// GetInstancesByHashKey 沿一致性hash环获取hash key对应的多个不同实例
+func (c *consumerAPI) GetInstancesByHashKey(
+	req *GetInstancesByHashKeyRequest) (*model.HashKeyInstancesResponse, error) {
+	return c.rawAPI.GetInstancesByHashKey((*api.GetInstancesByHashKeyRequest)(req))
+}
+
+// Prefetch 后台预热服务实例及规则
+func (c *consumerAPI) Prefetch(req *PrefetchRequest) (*model.PrefetchResponse, error) {
+	return c.rawAPI.Prefetch((*api.PrefetchRequest)(req))
+}
+
 // Destroy 销毁API，销毁后无法再进行调用
 func (c *consumerAPI) Destroy() {
 	c.rawAPI.Destroy()
@@ -130,6 +210,15 @@ func NewConsumerAPIByConfig(cfg config.Configuration) (ConsumerAPI, error) {
 	return &consumerAPI{rawAPI: c}, nil
 }
 
+// NewConsumerAPIWithOptions 通过函数式选项创建调用者API，如 api.WithServerAddress、api.WithLoadBalancer
+func NewConsumerAPIWithOptions(opts ...api.Option) (ConsumerAPI, error) {
+	c, err := api.NewConsumerAPIWithOptions(opts...)
+	if nil != err {
+		return nil, err
+	}
+	return &consumerAPI{rawAPI: c}, nil
+}
+
 // NewSDKContext 创建SDK上下文
 func NewSDKContext() (api.SDKContext, error) {
 	return api.InitContextByConfig(config.NewDefaultConfigurationWithDomain())
@@ -144,3 +233,13 @@ func NewSDKContextByAddress(address ...string) (api.SDKContext, error) {
 func NewSDKContextByConfig(cfg config.Configuration) (api.SDKContext, error) {
 	return api.InitContextByConfig(cfg)
 }
+
+// NewNamedSDKContextByConfig 根据配置创建具名的SDK上下文，可通过 GetSDKContext 按名称获取
+func NewNamedSDKContextByConfig(name string, cfg config.Configuration) (api.SDKContext, error) {
+	return api.InitNamedContextByConfig(name, cfg)
+}
+
+// GetSDKContext 根据名称获取已创建的SDK上下文
+func GetSDKContext(name string) (api.SDKContext, bool) {
+	return api.GetContext(name)
+}
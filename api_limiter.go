@@ -18,6 +18,8 @@
 package polaris
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/config"
 )
@@ -36,6 +38,11 @@ func (c *limitAPI) GetQuota(request QuotaRequest) (QuotaFuture, error) {
 	return c.rawAPI.GetQuota(request)
 }
 
+// GetQuotaWithContext 同GetQuota，调用过程中ctx取消或超时会中止等待及后续重试
+func (c *limitAPI) GetQuotaWithContext(ctx context.Context, request QuotaRequest) (QuotaFuture, error) {
+	return c.rawAPI.GetQuotaWithContext(ctx, request)
+}
+
 // Destroy 销毁API，销毁后无法再进行调用
 func (c *limitAPI) Destroy() {
 	c.rawAPI.Destroy()
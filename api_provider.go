@@ -18,6 +18,8 @@
 package polaris
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -52,11 +54,58 @@ func (p *providerAPI) Deregister(instance *InstanceDeRegisterRequest) error {
 	return p.rawAPI.Deregister((*api.InstanceDeRegisterRequest)(instance))
 }
 
+// GracefulDeregister 优雅下线，隔离实例并等待排空后反注册
+func (p *providerAPI) GracefulDeregister(instance *InstanceGracefulDeregisterRequest) error {
+	return p.rawAPI.GracefulDeregister((*api.InstanceGracefulDeregisterRequest)(instance))
+}
+
 // Heartbeat the heartbeat report
 func (p *providerAPI) Heartbeat(instance *InstanceHeartbeatRequest) error {
 	return p.rawAPI.Heartbeat((*api.InstanceHeartbeatRequest)(instance))
 }
 
+// UpdateInstanceMetadata 更新已注册实例的元数据及权重，无需反注册
+func (p *providerAPI) UpdateInstanceMetadata(req *InstanceMetadataUpdateRequest) error {
+	return p.rawAPI.UpdateInstanceMetadata((*api.InstanceMetadataUpdateRequest)(req))
+}
+
+// AddLoadSampler 添加自适应权重使用的负载采样器
+func (p *providerAPI) AddLoadSampler(sampler model.LoadSampler) {
+	p.rawAPI.AddLoadSampler(sampler)
+}
+
+// RegisterInstances 并发注册多个实例，返回与请求一一对应的结果
+func (p *providerAPI) RegisterInstances(req *BatchRegisterRequest) (*model.BatchRegisterResponse, error) {
+	return p.rawAPI.RegisterInstances((*api.BatchRegisterRequest)(req))
+}
+
+// HeartbeatInstances 并发上报多个实例的心跳，返回与请求一一对应的结果
+func (p *providerAPI) HeartbeatInstances(req *BatchHeartbeatRequest) (*model.BatchHeartbeatResponse, error) {
+	return p.rawAPI.HeartbeatInstances((*api.BatchHeartbeatRequest)(req))
+}
+
+// RegisterInstanceWithContext 同RegisterInstance，调用过程中ctx取消或超时会中止等待及后续重试
+func (p *providerAPI) RegisterInstanceWithContext(ctx context.Context,
+	instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return p.rawAPI.RegisterInstanceWithContext(ctx, (*api.InstanceRegisterRequest)(instance))
+}
+
+// RegisterWithContext 同Register，调用过程中ctx取消或超时会中止等待及后续重试
+func (p *providerAPI) RegisterWithContext(ctx context.Context,
+	instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return p.rawAPI.RegisterWithContext(ctx, (*api.InstanceRegisterRequest)(instance))
+}
+
+// DeregisterWithContext 同Deregister，调用过程中ctx取消或超时会中止等待及后续重试
+func (p *providerAPI) DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error {
+	return p.rawAPI.DeregisterWithContext(ctx, (*api.InstanceDeRegisterRequest)(instance))
+}
+
+// HeartbeatWithContext 同Heartbeat，调用过程中ctx取消或超时会中止等待及后续重试
+func (p *providerAPI) HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error {
+	return p.rawAPI.HeartbeatWithContext(ctx, (*api.InstanceHeartbeatRequest)(instance))
+}
+
 // Destroy the api is destroyed and cannot be called again
 func (p *providerAPI) Destroy() {
 	p.rawAPI.Destroy()
@@ -103,3 +152,21 @@ func NewProviderAPIByAddress(address ...string) (ProviderAPI, error) {
 	}
 	return &providerAPI{rawAPI: p}, nil
 }
+
+// NewProviderAPIWithOptions 通过函数式选项创建ProviderAPI，如 api.WithServerAddress
+func NewProviderAPIWithOptions(opts ...api.Option) (ProviderAPI, error) {
+	p, err := api.NewProviderAPIWithOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &providerAPI{rawAPI: p}, nil
+}
+
+// RegisterShutdownHook 监听SIGTERM及SIGINT信号，收到信号后对instance执行优雅下线
+// 下线完成后调用onShutdown，onShutdown为空时以退出码0结束进程；返回的函数用于取消监听
+func RegisterShutdownHook(provider ProviderAPI, instance *InstanceGracefulDeregisterRequest,
+	onShutdown func(err error)) func() {
+	return api.RegisterShutdownHookFunc(func() error {
+		return provider.GracefulDeregister(instance)
+	}, onShutdown)
+}
@@ -0,0 +1,276 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package agent
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+const (
+	// unix socket地址前缀
+	unixScheme = "unix://"
+	// 首次拉取等待服务端应答的超时时间
+	defaultFirstFetchTimeout = 3 * time.Second
+)
+
+// Agent 本机共享缓存代理
+// 代理独占到服务端的discover连接，通过unix socket为本机多个SDK实例提供服务发现，
+// SDK实例通过grpc插件的agentAddress配置接入，代理自身的SDK上下文不能再配置agentAddress
+type Agent struct {
+	connector         serverconnector.ServerConnector
+	refreshInterval   time.Duration
+	expireTime        time.Duration
+	firstFetchTimeout time.Duration
+	address           string
+	sockPath          string
+	server            *grpc.Server
+	listener          net.Listener
+	mutex             sync.Mutex
+	entries           map[model.ServiceEventKey]*entry
+	stopCh            chan struct{}
+	stopOnce          sync.Once
+}
+
+// NewAgent 基于SDK上下文创建代理，address为unix socket地址，如unix:///var/run/polaris/agent.sock
+func NewAgent(sdkCtx api.SDKContext, address string) (*Agent, error) {
+	if !strings.HasPrefix(address, unixScheme) {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"agent address %s must start with %s", address, unixScheme)
+	}
+	cfg := sdkCtx.GetConfig()
+	protocol := cfg.GetGlobal().GetServerConnector().GetProtocol()
+	connectorPlugin, err := sdkCtx.GetPlugins().GetPlugin(common.TypeServerConnector, protocol)
+	if err != nil {
+		return nil, err
+	}
+	return newAgent(connectorPlugin.(serverconnector.ServerConnector), address,
+		cfg.GetConsumer().GetLocalCache().GetServiceRefreshInterval(),
+		cfg.GetConsumer().GetLocalCache().GetServiceExpireTime()), nil
+}
+
+// newAgent 基于服务端连接器创建代理
+func newAgent(connector serverconnector.ServerConnector, address string,
+	refreshInterval time.Duration, expireTime time.Duration) *Agent {
+	return &Agent{
+		connector:         connector,
+		refreshInterval:   refreshInterval,
+		expireTime:        expireTime,
+		firstFetchTimeout: defaultFirstFetchTimeout,
+		address:           address,
+		sockPath:          strings.TrimPrefix(address, unixScheme),
+		entries:           make(map[model.ServiceEventKey]*entry),
+		stopCh:            make(chan struct{}),
+	}
+}
+
+// Start 监听unix socket并开始提供服务，非阻塞
+func (a *Agent) Start() error {
+	sockPath := a.sockPath
+	// 清理上次进程退出残留的socket文件
+	if err := os.Remove(sockPath); err != nil && !os.IsNotExist(err) {
+		return model.NewSDKError(model.ErrCodeInternalError, err, "fail to remove stale socket %s", sockPath)
+	}
+	listener, err := net.Listen("unix", sockPath)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeInternalError, err, "fail to listen on %s", sockPath)
+	}
+	a.listener = listener
+	a.server = grpc.NewServer()
+	apiservice.RegisterPolarisGRPCServer(a.server, a)
+	go func() {
+		if err := a.server.Serve(listener); err != nil {
+			log.GetBaseLogger().Errorf("agent %s stop serving: %v", a.address, err)
+		}
+	}()
+	go a.eliminateExpired()
+	log.GetBaseLogger().Infof("agent is serving on %s", a.address)
+	return nil
+}
+
+// Stop 停止服务，删除socket文件，并注销所有服务监听
+func (a *Agent) Stop() {
+	a.stopOnce.Do(func() {
+		close(a.stopCh)
+		if a.server != nil {
+			a.server.Stop()
+		}
+		if a.listener != nil {
+			// Serve尚未开始时grpc不会关闭监听，这里确保关闭并删除socket文件，避免残留文件
+			_ = a.listener.Close()
+			if err := os.Remove(a.sockPath); err != nil && !os.IsNotExist(err) {
+				log.GetBaseLogger().Warnf("agent: fail to remove socket %s: %v", a.sockPath, err)
+			}
+		}
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+		for key := range a.entries {
+			svcKey := key
+			_ = a.connector.DeRegisterServiceHandler(&svcKey)
+		}
+		a.entries = make(map[model.ServiceEventKey]*entry)
+	})
+}
+
+// Discover 处理SDK实例的服务发现stream，应答来自代理的本地缓存
+func (a *Agent) Discover(server apiservice.PolarisGRPC_DiscoverServer) error {
+	for {
+		req, err := server.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err = server.Send(a.discover(req)); err != nil {
+			return err
+		}
+	}
+}
+
+// discover 从缓存中获取应答，首次访问时向服务端注册监听并等待首次拉取完成
+func (a *Agent) discover(req *apiservice.DiscoverRequest) *apiservice.DiscoverResponse {
+	key := model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{
+			Namespace: req.GetService().GetNamespace().GetValue(),
+			Service:   req.GetService().GetName().GetValue(),
+		},
+		Type: pb.GetEventTypeByRequest(req.GetType()),
+	}
+	if key.Type == model.EventUnknown {
+		return errorResponse(req, key.Type, apimodel.Code_InvalidParameter, "unsupported discover type")
+	}
+	e, err := a.getOrRegister(key)
+	if err != nil {
+		return errorResponse(req, key.Type, apimodel.Code_ExecuteException, err.Error())
+	}
+	resp := e.wait(a.firstFetchTimeout)
+	if resp == nil {
+		return errorResponse(req, key.Type, apimodel.Code_ExecuteException, "agent fetch from server timeout")
+	}
+	clientRevision := req.GetService().GetRevision().GetValue()
+	if len(clientRevision) > 0 && clientRevision == resp.GetService().GetRevision().GetValue() {
+		return &apiservice.DiscoverResponse{
+			Code:    &wrappers.UInt32Value{Value: uint32(apimodel.Code_DataNoChange)},
+			Type:    resp.GetType(),
+			Service: resp.GetService(),
+		}
+	}
+	return resp
+}
+
+// getOrRegister 获取服务缓存，不存在时向服务端注册监听
+func (a *Agent) getOrRegister(key model.ServiceEventKey) (*entry, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if e, ok := a.entries[key]; ok {
+		e.touch()
+		return e, nil
+	}
+	e := newEntry()
+	svcKey := key
+	if err := a.connector.RegisterServiceHandler(&serverconnector.ServiceEventHandler{
+		ServiceEventKey: &svcKey,
+		TargetCluster:   config.DiscoverCluster,
+		RefreshInterval: a.refreshInterval,
+		Handler:         e,
+	}); err != nil {
+		return nil, err
+	}
+	a.entries[key] = e
+	return e, nil
+}
+
+// eliminateExpired 淘汰长时间没有SDK实例访问的服务，并注销服务端监听
+func (a *Agent) eliminateExpired() {
+	checkTime := a.expireTime / 2
+	if checkTime > config.DefaultMaxServiceExpireCheckTime {
+		checkTime = config.DefaultMaxServiceExpireCheckTime
+	}
+	ticker := time.NewTicker(checkTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stopCh:
+			return
+		case <-ticker.C:
+			a.mutex.Lock()
+			for key, e := range a.entries {
+				if time.Since(time.Unix(0, atomic.LoadInt64(&e.lastVisitTime))) < a.expireTime {
+					continue
+				}
+				svcKey := key
+				log.GetBaseLogger().Infof("agent: %s expired, deregister from server", svcKey)
+				_ = a.connector.DeRegisterServiceHandler(&svcKey)
+				delete(a.entries, key)
+			}
+			a.mutex.Unlock()
+		}
+	}
+}
+
+// RegisterInstance 代理只提供服务发现
+func (a *Agent) RegisterInstance(context.Context, *apiservice.Instance) (*apiservice.Response, error) {
+	return nil, status.Error(codes.Unimplemented, "agent only serves discover")
+}
+
+// DeregisterInstance 代理只提供服务发现
+func (a *Agent) DeregisterInstance(context.Context, *apiservice.Instance) (*apiservice.Response, error) {
+	return nil, status.Error(codes.Unimplemented, "agent only serves discover")
+}
+
+// Heartbeat 代理只提供服务发现
+func (a *Agent) Heartbeat(context.Context, *apiservice.Instance) (*apiservice.Response, error) {
+	return nil, status.Error(codes.Unimplemented, "agent only serves discover")
+}
+
+// ReportClient 代理只提供服务发现
+func (a *Agent) ReportClient(context.Context, *apiservice.Client) (*apiservice.Response, error) {
+	return nil, status.Error(codes.Unimplemented, "agent only serves discover")
+}
+
+// errorResponse 构造错误应答
+func errorResponse(req *apiservice.DiscoverRequest, eventType model.EventType,
+	code apimodel.Code, info string) *apiservice.DiscoverResponse {
+	return &apiservice.DiscoverResponse{
+		Code:    &wrappers.UInt32Value{Value: uint32(code)},
+		Info:    &wrappers.StringValue{Value: info},
+		Type:    pb.GetProtoResponseType(eventType),
+		Service: req.GetService(),
+	}
+}
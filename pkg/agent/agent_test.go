@@ -0,0 +1,263 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package agent
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+// fakeConnector 注册监听时推送预置的应答，没有预置应答的服务不推送
+type fakeConnector struct {
+	serverconnector.ServerConnector
+	mutex        sync.Mutex
+	responses    map[string]*apiservice.DiscoverResponse
+	registered   []model.ServiceEventKey
+	deregistered []model.ServiceEventKey
+}
+
+func (c *fakeConnector) RegisterServiceHandler(handler *serverconnector.ServiceEventHandler) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.registered = append(c.registered, *handler.ServiceEventKey)
+	if resp, ok := c.responses[handler.ServiceEventKey.Service]; ok {
+		go handler.Handler.OnServiceUpdate(&serverconnector.ServiceEvent{
+			ServiceEventKey: *handler.ServiceEventKey,
+			Value:           resp,
+		})
+	}
+	return nil
+}
+
+func (c *fakeConnector) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.deregistered = append(c.deregistered, *key)
+	return nil
+}
+
+func (c *fakeConnector) keys() ([]model.ServiceEventKey, []model.ServiceEventKey) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]model.ServiceEventKey(nil), c.registered...), append([]model.ServiceEventKey(nil), c.deregistered...)
+}
+
+func newInstancesResponse(service string, revision string) *apiservice.DiscoverResponse {
+	return &apiservice.DiscoverResponse{
+		Code: wrapperspb.UInt32(uint32(apimodel.Code_ExecuteSuccess)),
+		Type: apiservice.DiscoverResponse_INSTANCE,
+		Service: &apiservice.Service{
+			Namespace: wrapperspb.String("Test"),
+			Name:      wrapperspb.String(service),
+			Revision:  wrapperspb.String(revision),
+		},
+		Instances: []*apiservice.Instance{
+			{
+				Id:   wrapperspb.String("inst-1"),
+				Host: wrapperspb.String("127.0.0.1"),
+				Port: wrapperspb.UInt32(8080),
+			},
+		},
+	}
+}
+
+func newDiscoverRequest(typ apiservice.DiscoverRequest_DiscoverRequestType,
+	service string, revision string) *apiservice.DiscoverRequest {
+	return &apiservice.DiscoverRequest{
+		Type: typ,
+		Service: &apiservice.Service{
+			Namespace: wrapperspb.String("Test"),
+			Name:      wrapperspb.String(service),
+			Revision:  wrapperspb.String(revision),
+		},
+	}
+}
+
+// startTestAgent 在临时目录的socket上启动代理，socket路径上预先放置残留文件
+func startTestAgent(t *testing.T, connector *fakeConnector) (*Agent, string) {
+	dir, err := ioutil.TempDir("", "polaris-agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	sockPath := filepath.Join(dir, "agent.sock")
+	assert.Nil(t, ioutil.WriteFile(sockPath, []byte("stale"), 0644))
+
+	agent := newAgent(connector, unixScheme+sockPath, time.Second, time.Minute)
+	agent.firstFetchTimeout = 200 * time.Millisecond
+	assert.Nil(t, agent.Start())
+	t.Cleanup(agent.Stop)
+	return agent, sockPath
+}
+
+func dialTestAgent(t *testing.T, agent *Agent) *grpc.ClientConn {
+	conn, err := grpc.Dial(agent.address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+// TestAgentDiscover 测试通过unix socket从代理获取服务发现应答
+func TestAgentDiscover(t *testing.T) {
+	connector := &fakeConnector{responses: map[string]*apiservice.DiscoverResponse{
+		"svc": newInstancesResponse("svc", "rev-1"),
+	}}
+	agent, _ := startTestAgent(t, connector)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := apiservice.NewPolarisGRPCClient(dialTestAgent(t, agent)).Discover(ctx)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name          string
+		req           *apiservice.DiscoverRequest
+		wantCode      apimodel.Code
+		wantRevision  string
+		wantInstances int
+	}{
+		{
+			name:          "首次访问时注册监听并等待首次拉取",
+			req:           newDiscoverRequest(apiservice.DiscoverRequest_INSTANCE, "svc", ""),
+			wantCode:      apimodel.Code_ExecuteSuccess,
+			wantRevision:  "rev-1",
+			wantInstances: 1,
+		},
+		{
+			name:          "客户端版本号不一致时返回缓存",
+			req:           newDiscoverRequest(apiservice.DiscoverRequest_INSTANCE, "svc", "rev-0"),
+			wantCode:      apimodel.Code_ExecuteSuccess,
+			wantRevision:  "rev-1",
+			wantInstances: 1,
+		},
+		{
+			name:         "客户端版本号一致时返回未变更",
+			req:          newDiscoverRequest(apiservice.DiscoverRequest_INSTANCE, "svc", "rev-1"),
+			wantCode:     apimodel.Code_DataNoChange,
+			wantRevision: "rev-1",
+		},
+		{
+			name:     "不支持的发现类型",
+			req:      newDiscoverRequest(apiservice.DiscoverRequest_UNKNOWN, "svc", ""),
+			wantCode: apimodel.Code_InvalidParameter,
+		},
+		{
+			name:     "服务端未应答时超时返回错误",
+			req:      newDiscoverRequest(apiservice.DiscoverRequest_INSTANCE, "silent", ""),
+			wantCode: apimodel.Code_ExecuteException,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, stream.Send(tt.req))
+			resp, err := stream.Recv()
+			assert.Nil(t, err)
+			assert.Equal(t, uint32(tt.wantCode), resp.GetCode().GetValue())
+			assert.Equal(t, tt.wantRevision, resp.GetService().GetRevision().GetValue())
+			assert.Equal(t, tt.wantInstances, len(resp.GetInstances()))
+		})
+	}
+
+	// 同一服务只注册一次监听
+	registered, _ := connector.keys()
+	assert.Equal(t, []model.ServiceEventKey{
+		{ServiceKey: model.ServiceKey{Namespace: "Test", Service: "svc"}, Type: model.EventInstances},
+		{ServiceKey: model.ServiceKey{Namespace: "Test", Service: "silent"}, Type: model.EventInstances},
+	}, registered)
+}
+
+// TestAgentUnimplemented 测试代理只提供服务发现
+func TestAgentUnimplemented(t *testing.T) {
+	agent, _ := startTestAgent(t, &fakeConnector{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := apiservice.NewPolarisGRPCClient(dialTestAgent(t, agent)).RegisterInstance(ctx, &apiservice.Instance{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+// TestAgentStop 测试停止后删除socket文件并注销所有服务监听
+func TestAgentStop(t *testing.T) {
+	connector := &fakeConnector{responses: map[string]*apiservice.DiscoverResponse{
+		"svc": newInstancesResponse("svc", "rev-1"),
+	}}
+	agent, sockPath := startTestAgent(t, connector)
+	_, err := os.Stat(sockPath)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := apiservice.NewPolarisGRPCClient(dialTestAgent(t, agent)).Discover(ctx)
+	assert.Nil(t, err)
+	assert.Nil(t, stream.Send(newDiscoverRequest(apiservice.DiscoverRequest_INSTANCE, "svc", "")))
+	_, err = stream.Recv()
+	assert.Nil(t, err)
+
+	agent.Stop()
+	_, err = os.Stat(sockPath)
+	assert.True(t, os.IsNotExist(err))
+	_, deregistered := connector.keys()
+	assert.Equal(t, []model.ServiceEventKey{
+		{ServiceKey: model.ServiceKey{Namespace: "Test", Service: "svc"}, Type: model.EventInstances},
+	}, deregistered)
+	_, err = stream.Recv()
+	assert.NotNil(t, err)
+	// 重复停止不会再次注销
+	agent.Stop()
+	_, deregistered = connector.keys()
+	assert.Equal(t, 1, len(deregistered))
+}
+
+// TestAgentStopBeforeServe 测试监听后立即停止也会删除socket文件
+func TestAgentStopBeforeServe(t *testing.T) {
+	agent, sockPath := startTestAgent(t, &fakeConnector{})
+	agent.Stop()
+	_, err := os.Stat(sockPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestNewAgentInvalidAddress(t *testing.T) {
+	_, err := NewAgent(nil, "/var/run/polaris/agent.sock")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,97 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package agent
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+// entry 代理中单个资源的缓存，作为服务端推送的事件回调
+type entry struct {
+	// 最近一次服务端成功应答
+	resp atomic.Value
+	// 最近被SDK实例访问的时间
+	lastVisitTime int64
+	// 首次拉取完成的通知
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+func newEntry() *entry {
+	e := &entry{ready: make(chan struct{})}
+	e.touch()
+	return e
+}
+
+// touch 更新访问时间
+func (e *entry) touch() {
+	atomic.StoreInt64(&e.lastVisitTime, time.Now().UnixNano())
+}
+
+// load 获取缓存的应答
+func (e *entry) load() *apiservice.DiscoverResponse {
+	value := e.resp.Load()
+	if value == nil {
+		return nil
+	}
+	return value.(*apiservice.DiscoverResponse)
+}
+
+// wait 等待首次拉取完成，超时返回nil
+func (e *entry) wait(timeout time.Duration) *apiservice.DiscoverResponse {
+	select {
+	case <-e.ready:
+	case <-time.After(timeout):
+	}
+	return e.load()
+}
+
+// OnServiceUpdate 服务端应答回调，未变更的应答不覆盖缓存
+func (e *entry) OnServiceUpdate(event *serverconnector.ServiceEvent) {
+	if event.Error != nil {
+		log.GetBaseLogger().Warnf("agent: fail to update %s, %v", event.ServiceEventKey, event.Error)
+		return
+	}
+	resp, ok := event.Value.(*apiservice.DiscoverResponse)
+	if !ok {
+		return
+	}
+	if resp.GetCode().GetValue() != uint32(apimodel.Code_DataNoChange) {
+		e.resp.Store(resp)
+	}
+	e.readyOnce.Do(func() {
+		close(e.ready)
+	})
+}
+
+// GetRevision 获取缓存版本号
+func (e *entry) GetRevision() string {
+	return e.load().GetService().GetRevision().GetValue()
+}
+
+// GetBusiness 获取业务
+func (e *entry) GetBusiness() string {
+	return ""
+}
@@ -0,0 +1,35 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package hash
+
+const (
+	// FNV32aOffset FNV-1a 32位哈希的初始值
+	FNV32aOffset uint32 = 2166136261
+	// fnv32aPrime FNV-1a 32位哈希的乘数
+	fnv32aPrime uint32 = 16777619
+)
+
+// FNV32aString 在 hash 的基础上继续累加字符串 s 的 FNV-1a 哈希，不产生内存分配，
+// 用于热点路径上的分片选择，首次调用时 hash 传入 FNV32aOffset
+func FNV32aString(hash uint32, s string) uint32 {
+	for i := 0; i < len(s); i++ {
+		hash ^= uint32(s[i])
+		hash *= fnv32aPrime
+	}
+	return hash
+}
@@ -0,0 +1,267 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type operator int
+
+const (
+	opEqual operator = iota
+	opNotEqual
+	opGreater
+	opGreaterEqual
+	opLess
+	opLessEqual
+)
+
+// 运算符按长度从长到短排列，保证前缀匹配时优先匹配到长运算符
+var operators = []struct {
+	text string
+	op   operator
+}{
+	{">=", opGreaterEqual},
+	{"<=", opLessEqual},
+	{"!=", opNotEqual},
+	{"==", opEqual},
+	{">", opGreater},
+	{"<", opLess},
+	{"=", opEqual},
+}
+
+// condition 单个比较条件
+type condition struct {
+	op      operator
+	version Version
+}
+
+func (c condition) check(v Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case opEqual:
+		return cmp == 0
+	case opNotEqual:
+		return cmp != 0
+	case opGreater:
+		return cmp > 0
+	case opGreaterEqual:
+		return cmp >= 0
+	case opLess:
+		return cmp < 0
+	case opLessEqual:
+		return cmp <= 0
+	}
+	return false
+}
+
+// Constraint 版本约束，由"||"分隔的多组条件构成，组内以空格或逗号分隔的条件需要同时满足
+// 支持的写法：">=1.2.0 <2.0.0"、"~1.2"、"^1.2.3"、"1.2.x"、"*"、"1.0.0 || >=2.0.0"
+type Constraint struct {
+	text   string
+	groups [][]condition
+}
+
+// ParseConstraint 解析版本约束
+func ParseConstraint(text string) (*Constraint, error) {
+	c := &Constraint{text: text}
+	for _, orPart := range strings.Split(text, "||") {
+		tokens := tokenize(orPart)
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q: empty condition", text)
+		}
+		var group []condition
+		for _, token := range tokens {
+			conds, err := parseCondition(token)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %v", text, err)
+			}
+			group = append(group, conds...)
+		}
+		c.groups = append(c.groups, group)
+	}
+	return c, nil
+}
+
+// Check 判断版本是否满足约束
+func (c *Constraint) Check(v Version) bool {
+	for _, group := range c.groups {
+		matched := true
+		for _, cond := range group {
+			if !cond.check(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckString 判断字符串形式的版本是否满足约束，无法解析的版本视为不满足
+func (c *Constraint) CheckString(value string) bool {
+	v, err := Parse(value)
+	if err != nil {
+		return false
+	}
+	return c.Check(v)
+}
+
+// String 转换为字符串
+func (c *Constraint) String() string {
+	return c.text
+}
+
+// tokenize 按空格及逗号切分条件，运算符与版本号之间允许有空格
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\t'
+	})
+	var tokens []string
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if isOperatorOnly(field) && i+1 < len(fields) {
+			field += fields[i+1]
+			i++
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+func isOperatorOnly(field string) bool {
+	if field == "~" || field == "^" {
+		return true
+	}
+	for _, o := range operators {
+		if field == o.text {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCondition 解析单个条件，~、^及通配符会被展开为一组比较条件
+func parseCondition(token string) ([]condition, error) {
+	switch {
+	case token == "*" || token == "x" || token == "X":
+		return nil, nil
+	case strings.HasPrefix(token, "~"):
+		return parseTilde(token[1:])
+	case strings.HasPrefix(token, "^"):
+		return parseCaret(token[1:])
+	}
+	op := opEqual
+	for _, o := range operators {
+		if strings.HasPrefix(token, o.text) {
+			op = o.op
+			token = token[len(o.text):]
+			break
+		}
+	}
+	if op == opEqual && isWildcard(token) {
+		return parseWildcard(token)
+	}
+	v, err := Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	return []condition{{op: op, version: v}}, nil
+}
+
+// parseTilde ~1.2.3 表示 >=1.2.3 <1.3.0，~1 表示 >=1.0.0 <2.0.0
+func parseTilde(token string) ([]condition, error) {
+	v, err := Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	upper := Version{Major: v.Major, Minor: v.Minor + 1}
+	if countParts(token) == 1 {
+		upper = Version{Major: v.Major + 1}
+	}
+	return []condition{{op: opGreaterEqual, version: v}, {op: opLess, version: upper}}, nil
+}
+
+// parseCaret ^1.2.3 表示 >=1.2.3 <2.0.0，^0.2.3 表示 >=0.2.3 <0.3.0，^0.0.3 表示 >=0.0.3 <0.0.4
+func parseCaret(token string) ([]condition, error) {
+	v, err := Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	var upper Version
+	switch {
+	case v.Major > 0 || countParts(token) == 1:
+		upper = Version{Major: v.Major + 1}
+	case v.Minor > 0 || countParts(token) == 2:
+		upper = Version{Minor: v.Minor + 1}
+	default:
+		upper = Version{Patch: v.Patch + 1}
+	}
+	return []condition{{op: opGreaterEqual, version: v}, {op: opLess, version: upper}}, nil
+}
+
+func isWildcard(token string) bool {
+	for _, part := range strings.Split(strings.TrimPrefix(token, "v"), ".") {
+		if part == "x" || part == "X" || part == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWildcard 1.2.x 表示 >=1.2.0 <1.3.0，1.x 表示 >=1.0.0 <2.0.0
+func parseWildcard(token string) ([]condition, error) {
+	parts := strings.Split(strings.TrimPrefix(token, "v"), ".")
+	var nums []uint64
+	for _, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		num, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %v", token, err)
+		}
+		nums = append(nums, num)
+	}
+	switch len(nums) {
+	case 0:
+		return nil, nil
+	case 1:
+		return []condition{
+			{op: opGreaterEqual, version: Version{Major: nums[0]}},
+			{op: opLess, version: Version{Major: nums[0] + 1}},
+		}, nil
+	default:
+		return []condition{
+			{op: opGreaterEqual, version: Version{Major: nums[0], Minor: nums[1]}},
+			{op: opLess, version: Version{Major: nums[0], Minor: nums[1] + 1}},
+		}, nil
+	}
+}
+
+func countParts(token string) int {
+	raw := strings.TrimPrefix(token, "v")
+	if idx := strings.IndexAny(raw, "-+"); idx >= 0 {
+		raw = raw[:idx]
+	}
+	return len(strings.Split(raw, "."))
+}
@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version 语义化版本号
+type Version struct {
+	Major uint64
+	Minor uint64
+	Patch uint64
+	// Prerelease 预发布标识，如 1.2.0-beta.1 中的 beta.1
+	Prerelease string
+}
+
+// Parse 解析版本号，支持v前缀，缺省的次版本号及修订号按0处理，构建元数据(+xxx)会被忽略
+func Parse(value string) (Version, error) {
+	var v Version
+	raw := strings.TrimSpace(value)
+	raw = strings.TrimPrefix(strings.TrimPrefix(raw, "v"), "V")
+	if idx := strings.Index(raw, "+"); idx >= 0 {
+		raw = raw[:idx]
+	}
+	if idx := strings.Index(raw, "-"); idx >= 0 {
+		v.Prerelease = raw[idx+1:]
+		raw = raw[:idx]
+		if len(v.Prerelease) == 0 {
+			return v, fmt.Errorf("invalid version %q: empty prerelease", value)
+		}
+	}
+	parts := strings.Split(raw, ".")
+	if len(raw) == 0 || len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", value)
+	}
+	nums := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		num, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return v, fmt.Errorf("invalid version %q: %v", value, err)
+		}
+		*nums[i] = num
+	}
+	return v, nil
+}
+
+// Compare 比较两个版本，v小于、等于、大于other时分别返回-1、0、1
+func (v Version) Compare(other Version) int {
+	if c := compareUint(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// String 转换为字符串
+func (v Version) String() string {
+	if len(v.Prerelease) > 0 {
+		return fmt.Sprintf("%d.%d.%d-%s", v.Major, v.Minor, v.Patch, v.Prerelease)
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease 无预发布标识的版本大于有预发布标识的版本，标识按点分段比较，数字段按数值比较
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if len(a) == 0 {
+		return 1
+	}
+	if len(b) == 0 {
+		return -1
+	}
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.ParseUint(aParts[i], 10, 64)
+		bNum, bErr := strconv.ParseUint(bParts[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if c := compareUint(aNum, bNum); c != 0 {
+				return c
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareUint(uint64(len(aParts)), uint64(len(bParts)))
+}
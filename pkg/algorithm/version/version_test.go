@@ -0,0 +1,65 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package version
+
+import (
+	"testing"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint string
+		version    string
+		want       bool
+	}{
+		{name: "区间内", constraint: ">=1.2.0 <2.0.0", version: "1.5.3", want: true},
+		{name: "区间上界", constraint: ">=1.2.0 <2.0.0", version: "2.0.0", want: false},
+		{name: "运算符与版本号之间有空格", constraint: ">= 1.2.0, < 2.0.0", version: "1.2.0", want: true},
+		{name: "tilde范围内", constraint: "~1.2", version: "1.2.9", want: true},
+		{name: "tilde范围外", constraint: "~1.2", version: "1.3.0", want: false},
+		{name: "caret主版本为0", constraint: "^0.2.3", version: "0.2.9", want: true},
+		{name: "caret主版本为0范围外", constraint: "^0.2.3", version: "0.3.0", want: false},
+		{name: "通配符", constraint: "1.2.x", version: "1.2.7", want: true},
+		{name: "通配符范围外", constraint: "1.x", version: "2.0.0", want: false},
+		{name: "匹配全部", constraint: "*", version: "9.9.9", want: true},
+		{name: "或条件", constraint: "1.0.0 || >=2.0.0", version: "2.1.0", want: true},
+		{name: "或条件均不满足", constraint: "1.0.0 || >=2.0.0", version: "1.5.0", want: false},
+		{name: "预发布版本小于正式版本", constraint: ">=1.0.0", version: "1.0.0-beta", want: false},
+		{name: "v前缀", constraint: "v1.2", version: "1.2.0", want: true},
+		{name: "无法解析的版本", constraint: ">=1.0.0", version: "latest", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q) error: %v", tt.constraint, err)
+			}
+			if got := c.CheckString(tt.version); got != tt.want {
+				t.Errorf("%q.CheckString(%q) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraintInvalid(t *testing.T) {
+	for _, text := range []string{">=abc", "", "1.0.0 ||", "1.2.3.4"} {
+		if _, err := ParseConstraint(text); err == nil {
+			t.Errorf("ParseConstraint(%q) expect error", text)
+		}
+	}
+}
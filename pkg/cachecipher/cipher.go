@@ -0,0 +1,164 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package cachecipher
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// encryptedMagic 加密缓存文件的头部标识，不带该标识的文件按明文处理，便于从明文缓存平滑迁移
+var encryptedMagic = []byte("PLEC")
+
+// CacheCipher 本地缓存文件加解密接口
+type CacheCipher interface {
+	// Name 加解密器名称，对应配置中的cipher
+	Name() string
+	// Encrypt 加密
+	Encrypt(plaintext []byte) ([]byte, error)
+	// Decrypt 解密
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// KeyProvider 数据密钥提供者，返回16、24或32字节的AES密钥，可对接环境变量、KMS等
+type KeyProvider func() ([]byte, error)
+
+var (
+	cipherMutex sync.RWMutex
+	cipherSet   = make(map[string]CacheCipher)
+)
+
+// RegisterCacheCipher 注册缓存加解密器，需在创建SDK上下文前调用
+func RegisterCacheCipher(c CacheCipher) {
+	cipherMutex.Lock()
+	defer cipherMutex.Unlock()
+	if _, exist := cipherSet[c.Name()]; exist {
+		panic(fmt.Sprintf("existed cache cipher: name=%v", c.Name()))
+	}
+	cipherSet[c.Name()] = c
+}
+
+// GetCacheCipher 获取缓存加解密器，name为空时返回nil，表示不加密
+func GetCacheCipher(name string) (CacheCipher, error) {
+	if len(name) == 0 {
+		return nil, nil
+	}
+	cipherMutex.RLock()
+	defer cipherMutex.RUnlock()
+	c, exist := cipherSet[name]
+	if !exist {
+		return nil, fmt.Errorf("cache cipher %s not registered", name)
+	}
+	return c, nil
+}
+
+// Seal 加密并添加头部标识，c为nil时原样返回
+func Seal(c CacheCipher, plaintext []byte) ([]byte, error) {
+	if c == nil {
+		return plaintext, nil
+	}
+	ciphertext, err := c.Encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(make([]byte, 0, len(encryptedMagic)+len(ciphertext)), encryptedMagic...),
+		ciphertext...), nil
+}
+
+// Open 解密带头部标识的数据，不带标识的数据按明文原样返回
+func Open(c CacheCipher, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	if c == nil {
+		return nil, errors.New("cache file is encrypted but no cipher configured")
+	}
+	return c.Decrypt(data[len(encryptedMagic):])
+}
+
+// IsEncrypted 数据是否为加密缓存
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+// aesGCMCipher 基于AES-GCM的加解密器，密文格式为nonce+密文
+type aesGCMCipher struct {
+	name     string
+	provider KeyProvider
+	once     sync.Once
+	aead     cipher.AEAD
+	err      error
+}
+
+// NewAESGCMCipher 创建AES-GCM加解密器，密钥在首次使用时通过provider获取
+func NewAESGCMCipher(name string, provider KeyProvider) CacheCipher {
+	return &aesGCMCipher{name: name, provider: provider}
+}
+
+// Name 加解密器名称
+func (a *aesGCMCipher) Name() string {
+	return a.name
+}
+
+func (a *aesGCMCipher) getAEAD() (cipher.AEAD, error) {
+	a.once.Do(func() {
+		key, err := a.provider()
+		if err != nil {
+			a.err = fmt.Errorf("fail to get key for cache cipher %s: %v", a.name, err)
+			return
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			a.err = fmt.Errorf("invalid key for cache cipher %s: %v", a.name, err)
+			return
+		}
+		a.aead, a.err = cipher.NewGCM(block)
+	})
+	return a.aead, a.err
+}
+
+// Encrypt 加密
+func (a *aesGCMCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	aead, err := a.getAEAD()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt 解密
+func (a *aesGCMCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	aead, err := a.getAEAD()
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("cache ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, nil)
+}
@@ -0,0 +1,217 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package cachecipher
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testKey  = bytes.Repeat([]byte{1}, 32)
+	otherKey = bytes.Repeat([]byte{2}, 32)
+)
+
+func staticKey(key []byte) KeyProvider {
+	return func() ([]byte, error) {
+		return key, nil
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	plaintext := []byte(`{"service":"svc","instances":[]}`)
+	sealed, err := Seal(NewAESGCMCipher("test", staticKey(testKey)), plaintext)
+	assert.Nil(t, err)
+	assert.True(t, IsEncrypted(sealed))
+	assert.False(t, bytes.Contains(sealed, plaintext))
+
+	tests := []struct {
+		name    string
+		cipher  CacheCipher
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name:   "使用相同密钥解密",
+			cipher: NewAESGCMCipher("test", staticKey(testKey)),
+			data:   sealed,
+			want:   plaintext,
+		},
+		{
+			name:   "明文缓存原样返回",
+			cipher: NewAESGCMCipher("test", staticKey(testKey)),
+			data:   plaintext,
+			want:   plaintext,
+		},
+		{
+			name: "未配置加解密器时明文原样返回",
+			data: plaintext,
+			want: plaintext,
+		},
+		{
+			name:    "未配置加解密器时无法读取加密缓存",
+			data:    sealed,
+			wantErr: true,
+		},
+		{
+			name:    "密钥错误",
+			cipher:  NewAESGCMCipher("test", staticKey(otherKey)),
+			data:    sealed,
+			wantErr: true,
+		},
+		{
+			name:    "密文被篡改",
+			cipher:  NewAESGCMCipher("test", staticKey(testKey)),
+			data:    append(append([]byte{}, sealed[:len(sealed)-1]...), sealed[len(sealed)-1]^0xff),
+			wantErr: true,
+		},
+		{
+			name:    "密文长度不足",
+			cipher:  NewAESGCMCipher("test", staticKey(testKey)),
+			data:    append(append([]byte{}, encryptedMagic...), 1, 2, 3),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Open(tt.cipher, tt.data)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				assert.Nil(t, got)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestSealNonce 测试每次加密使用随机nonce，相同明文的密文不同
+func TestSealNonce(t *testing.T) {
+	c := NewAESGCMCipher("test", staticKey(testKey))
+	plaintext := []byte("hello")
+	first, err := Seal(c, plaintext)
+	assert.Nil(t, err)
+	second, err := Seal(c, plaintext)
+	assert.Nil(t, err)
+	assert.NotEqual(t, first, second)
+
+	// 未配置加解密器时原样返回
+	sealed, err := Seal(nil, plaintext)
+	assert.Nil(t, err)
+	assert.Equal(t, plaintext, sealed)
+}
+
+func TestAESGCMCipherKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider KeyProvider
+		wantErr  bool
+	}{
+		{name: "AES-128密钥", provider: staticKey(testKey[:16])},
+		{name: "AES-192密钥", provider: staticKey(testKey[:24])},
+		{name: "AES-256密钥", provider: staticKey(testKey)},
+		{name: "密钥长度非法", provider: staticKey(testKey[:15]), wantErr: true},
+		{
+			name: "获取密钥失败",
+			provider: func() ([]byte, error) {
+				return nil, errors.New("kms unavailable")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			c := NewAESGCMCipher("test", func() ([]byte, error) {
+				calls++
+				return tt.provider()
+			})
+			for i := 0; i < 2; i++ {
+				ciphertext, err := c.Encrypt([]byte("hello"))
+				if tt.wantErr {
+					assert.NotNil(t, err)
+					_, err = c.Decrypt([]byte("0123456789abcdef"))
+					assert.NotNil(t, err)
+					continue
+				}
+				assert.Nil(t, err)
+				plaintext, err := c.Decrypt(ciphertext)
+				assert.Nil(t, err)
+				assert.Equal(t, []byte("hello"), plaintext)
+			}
+			// 密钥只在首次使用时获取一次
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
+
+func TestEnvKeyProvider(t *testing.T) {
+	const env = "POLARIS_CACHE_KEY_TEST"
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "环境变量为空", wantErr: true},
+		{name: "非base64编码", value: "not-base64!", wantErr: true},
+		{name: "base64编码的密钥", value: base64.StdEncoding.EncodeToString(testKey)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, os.Setenv(env, tt.value))
+			defer os.Unsetenv(env)
+
+			c := NewAESGCMCipher("test", envKeyProvider(env))
+			sealed, err := Seal(c, []byte("hello"))
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			// 与直接使用相同密钥的加解密器互通
+			plaintext, err := Open(NewAESGCMCipher("test", staticKey(testKey)), sealed)
+			assert.Nil(t, err)
+			assert.Equal(t, []byte("hello"), plaintext)
+		})
+	}
+}
+
+func TestGetCacheCipher(t *testing.T) {
+	c, err := GetCacheCipher("")
+	assert.Nil(t, err)
+	assert.Nil(t, c)
+
+	for _, name := range []string{EnvCipherName, MetadataEnvCipherName} {
+		c, err = GetCacheCipher(name)
+		assert.Nil(t, err)
+		assert.Equal(t, name, c.Name())
+	}
+
+	_, err = GetCacheCipher("unknown")
+	assert.NotNil(t, err)
+
+	assert.Panics(t, func() {
+		RegisterCacheCipher(NewAESGCMCipher(EnvCipherName, staticKey(testKey)))
+	})
+}
@@ -0,0 +1,50 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package cachecipher
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+const (
+	// EnvCipherName 从环境变量读取密钥的加解密器名称
+	EnvCipherName = "env"
+	// EnvCacheKey 保存base64编码AES密钥的环境变量
+	EnvCacheKey = "POLARIS_CACHE_KEY"
+	// MetadataEnvCipherName 从环境变量读取实例元数据共享密钥的加解密器名称
+	MetadataEnvCipherName = "metadataEnv"
+	// EnvMetadataKey 保存base64编码实例元数据共享密钥的环境变量，主调方与被调方需配置相同的密钥
+	EnvMetadataKey = "POLARIS_METADATA_KEY"
+)
+
+func init() {
+	RegisterCacheCipher(NewAESGCMCipher(EnvCipherName, envKeyProvider(EnvCacheKey)))
+	RegisterCacheCipher(NewAESGCMCipher(MetadataEnvCipherName, envKeyProvider(EnvMetadataKey)))
+}
+
+// envKeyProvider 从环境变量中获取密钥
+func envKeyProvider(env string) KeyProvider {
+	return func() ([]byte, error) {
+		value := os.Getenv(env)
+		if len(value) == 0 {
+			return nil, fmt.Errorf("env %s is empty", env)
+		}
+		return base64.StdEncoding.DecodeString(value)
+	}
+}
@@ -0,0 +1,94 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cachecipher
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// encryptedValuePrefix 加密后的元数据值前缀，不带该前缀的值按明文处理
+const encryptedValuePrefix = "enc:v1:"
+
+// IsEncryptedValue 元数据值是否已加密
+func IsEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, encryptedValuePrefix)
+}
+
+// EncryptMetadata 加密元数据中的敏感key，返回加密后的副本，不修改原元数据；已加密的值不重复加密
+func EncryptMetadata(c CacheCipher, metadata map[string]string, keys []string) (map[string]string, error) {
+	if c == nil || len(metadata) == 0 || len(keys) == 0 {
+		return metadata, nil
+	}
+	var encrypted map[string]string
+	for _, key := range keys {
+		value, ok := metadata[key]
+		if !ok || IsEncryptedValue(value) {
+			continue
+		}
+		ciphertext, err := c.Encrypt([]byte(value))
+		if err != nil {
+			return nil, err
+		}
+		if encrypted == nil {
+			encrypted = copyMetadata(metadata)
+		}
+		encrypted[key] = encryptedValuePrefix + base64.RawURLEncoding.EncodeToString(ciphertext)
+	}
+	if encrypted == nil {
+		return metadata, nil
+	}
+	return encrypted, nil
+}
+
+// DecryptMetadata 解密元数据中的加密值，存在加密值时返回解密后的副本及true，否则原样返回
+func DecryptMetadata(c CacheCipher, metadata map[string]string) (map[string]string, bool, error) {
+	if c == nil {
+		return metadata, false, nil
+	}
+	var decrypted map[string]string
+	for key, value := range metadata {
+		if !IsEncryptedValue(value) {
+			continue
+		}
+		ciphertext, err := base64.RawURLEncoding.DecodeString(value[len(encryptedValuePrefix):])
+		if err != nil {
+			return nil, false, err
+		}
+		plaintext, err := c.Decrypt(ciphertext)
+		if err != nil {
+			return nil, false, err
+		}
+		if decrypted == nil {
+			decrypted = copyMetadata(metadata)
+		}
+		decrypted[key] = string(plaintext)
+	}
+	if decrypted == nil {
+		return metadata, false, nil
+	}
+	return decrypted, true, nil
+}
+
+func copyMetadata(metadata map[string]string) map[string]string {
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
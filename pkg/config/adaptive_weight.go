@@ -0,0 +1,156 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// AdaptiveWeightConfigImpl 自适应权重配置，根据实例负载定期调整自动心跳实例的权重或负载分数
+type AdaptiveWeightConfigImpl struct {
+	// Enable 是否启用自适应权重
+	Enable *bool `yaml:"enable" json:"enable"`
+	// Mode 调整方式，weight调整实例权重，metadata将负载分数写入metadata
+	Mode string `yaml:"mode" json:"mode"`
+	// Interval 负载采样及上报的周期
+	Interval time.Duration `yaml:"interval" json:"interval"`
+	// LoadThreshold 负载超过该值后开始降低权重，取值[0, 1)
+	LoadThreshold float64 `yaml:"loadThreshold" json:"loadThreshold"`
+	// MinWeight 降低权重的下限
+	MinWeight int `yaml:"minWeight" json:"minWeight"`
+	// MetadataKey metadata方式下负载分数的键
+	MetadataKey string `yaml:"metadataKey" json:"metadataKey"`
+}
+
+// IsEnable 是否启用自适应权重
+func (a *AdaptiveWeightConfigImpl) IsEnable() bool {
+	return *a.Enable
+}
+
+// SetEnable 设置是否启用自适应权重
+func (a *AdaptiveWeightConfigImpl) SetEnable(enable bool) {
+	a.Enable = &enable
+}
+
+// GetMode 获取调整方式
+func (a *AdaptiveWeightConfigImpl) GetMode() string {
+	return a.Mode
+}
+
+// SetMode 设置调整方式
+func (a *AdaptiveWeightConfigImpl) SetMode(mode string) {
+	a.Mode = mode
+}
+
+// GetInterval 获取采样周期
+func (a *AdaptiveWeightConfigImpl) GetInterval() time.Duration {
+	return a.Interval
+}
+
+// SetInterval 设置采样周期
+func (a *AdaptiveWeightConfigImpl) SetInterval(interval time.Duration) {
+	a.Interval = interval
+}
+
+// GetLoadThreshold 获取开始降低权重的负载阈值
+func (a *AdaptiveWeightConfigImpl) GetLoadThreshold() float64 {
+	return a.LoadThreshold
+}
+
+// SetLoadThreshold 设置开始降低权重的负载阈值
+func (a *AdaptiveWeightConfigImpl) SetLoadThreshold(threshold float64) {
+	a.LoadThreshold = threshold
+}
+
+// GetMinWeight 获取权重下限
+func (a *AdaptiveWeightConfigImpl) GetMinWeight() int {
+	return a.MinWeight
+}
+
+// SetMinWeight 设置权重下限
+func (a *AdaptiveWeightConfigImpl) SetMinWeight(weight int) {
+	a.MinWeight = weight
+}
+
+// GetMetadataKey 获取负载分数的metadata键
+func (a *AdaptiveWeightConfigImpl) GetMetadataKey() string {
+	return a.MetadataKey
+}
+
+// SetMetadataKey 设置负载分数的metadata键
+func (a *AdaptiveWeightConfigImpl) SetMetadataKey(key string) {
+	a.MetadataKey = key
+}
+
+// Verify 检验自适应权重配置
+func (a *AdaptiveWeightConfigImpl) Verify() error {
+	if nil == a {
+		return errors.New("AdaptiveWeightConfig is nil")
+	}
+	var errs error
+	if a.Mode != model.AdaptiveWeightModeWeight && a.Mode != model.AdaptiveWeightModeMetadata {
+		errs = multierror.Append(errs, fmt.Errorf("provider.adaptiveWeight.mode must be %s or %s",
+			model.AdaptiveWeightModeWeight, model.AdaptiveWeightModeMetadata))
+	}
+	if a.Interval <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("provider.adaptiveWeight.interval must be greater than 0"))
+	}
+	if a.LoadThreshold < 0 || a.LoadThreshold >= 1 {
+		errs = multierror.Append(errs, fmt.Errorf("provider.adaptiveWeight.loadThreshold must be in [0, 1)"))
+	}
+	if a.MinWeight < model.MinWeight || a.MinWeight > model.MaxWeight {
+		errs = multierror.Append(errs, fmt.Errorf("provider.adaptiveWeight.minWeight must be in [%d, %d]",
+			model.MinWeight, model.MaxWeight))
+	}
+	if a.Mode == model.AdaptiveWeightModeMetadata && len(a.MetadataKey) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("provider.adaptiveWeight.metadataKey can not be empty"))
+	}
+	return errs
+}
+
+// SetDefault 设置自适应权重配置的默认值
+func (a *AdaptiveWeightConfigImpl) SetDefault() {
+	if nil == a.Enable {
+		a.SetEnable(DefaultAdaptiveWeightEnabled)
+	}
+	if len(a.Mode) == 0 {
+		a.Mode = model.AdaptiveWeightModeWeight
+	}
+	if a.Interval == 0 {
+		a.Interval = DefaultAdaptiveWeightInterval
+	}
+	if a.LoadThreshold == 0 {
+		a.LoadThreshold = DefaultAdaptiveWeightLoadThreshold
+	}
+	if a.MinWeight == 0 {
+		a.MinWeight = DefaultAdaptiveWeightMinWeight
+	}
+	if len(a.MetadataKey) == 0 {
+		a.MetadataKey = DefaultAdaptiveWeightMetadataKey
+	}
+}
+
+// Init 初始化
+func (a *AdaptiveWeightConfigImpl) Init() {
+}
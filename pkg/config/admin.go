@@ -0,0 +1,91 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// AdminConfigImpl 本地管理端口配置
+type AdminConfigImpl struct {
+	// Enable 是否启用管理端口
+	Enable *bool `yaml:"enable" json:"enable"`
+	// Host 管理端口监听的地址，默认只监听本机回环地址
+	Host string `yaml:"host" json:"host"`
+	// Port 管理端口监听的端口
+	Port int `yaml:"port" json:"port"`
+}
+
+// IsEnable 是否启用管理端口
+func (a *AdminConfigImpl) IsEnable() bool {
+	return *a.Enable
+}
+
+// SetEnable 设置是否启用管理端口
+func (a *AdminConfigImpl) SetEnable(enable bool) {
+	a.Enable = &enable
+}
+
+// GetHost 获取管理端口监听的地址
+func (a *AdminConfigImpl) GetHost() string {
+	return a.Host
+}
+
+// SetHost 设置管理端口监听的地址
+func (a *AdminConfigImpl) SetHost(host string) {
+	a.Host = host
+}
+
+// GetPort 获取管理端口监听的端口
+func (a *AdminConfigImpl) GetPort() int {
+	return a.Port
+}
+
+// SetPort 设置管理端口监听的端口
+func (a *AdminConfigImpl) SetPort(port int) {
+	a.Port = port
+}
+
+// Verify 检验管理端口配置
+func (a *AdminConfigImpl) Verify() error {
+	if nil == a {
+		return errors.New("AdminConfig is nil")
+	}
+	var errs error
+	if a.Port < 0 || a.Port > 65535 {
+		errs = multierror.Append(errs, fmt.Errorf("global.admin.port must be in range [0, 65535]"))
+	}
+	return errs
+}
+
+// SetDefault 设置管理端口配置的默认值
+func (a *AdminConfigImpl) SetDefault() {
+	if nil == a.Enable {
+		enable := DefaultAdminEnabled
+		a.Enable = &enable
+	}
+	if len(a.Host) == 0 {
+		a.Host = DefaultAdminHost
+	}
+	if a.Port == 0 {
+		a.Port = DefaultAdminPort
+	}
+}
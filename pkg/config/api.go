@@ -42,10 +42,164 @@ type GlobalConfig interface {
 	GetServerConnector() ServerConnectorConfig
 	// GetStatReporter global.statReporter前缀开头的所有配置项
 	GetStatReporter() StatReporterConfig
+	// GetEventReporter global.eventReporter前缀开头的所有配置项
+	GetEventReporter() EventReporterConfig
 	// GetLocation global.location前缀开头的所有配置项
 	GetLocation() LocationConfig
 	// GetClient global.client前缀开头的所有配置项
 	GetClient() ClientConfig
+	// GetRegex global.regex前缀开头的所有配置项
+	GetRegex() RegexConfig
+	// GetAdmin global.admin前缀开头的所有配置项
+	GetAdmin() AdminConfig
+	// GetDiagnostics global.diagnostics前缀开头的所有配置项
+	GetDiagnostics() DiagnosticsConfig
+	// GetIdentityProvider global.identityProvider前缀开头的所有配置项
+	GetIdentityProvider() IdentityProviderConfig
+	// GetMetadataEncryption global.metadataEncryption前缀开头的所有配置项
+	GetMetadataEncryption() MetadataEncryptionConfig
+	// GetListenerDispatch global.listenerDispatch前缀开头的所有配置项
+	GetListenerDispatch() ListenerDispatchConfig
+	// GetConfigReload global.configReload前缀开头的所有配置项
+	GetConfigReload() ConfigReloadConfig
+}
+
+// IdentityProviderConfig 工作负载身份配置.
+type IdentityProviderConfig interface {
+	BaseConfig
+	PluginConfig
+	// IsEnable 是否启用工作负载身份
+	IsEnable() bool
+	// SetEnable 设置是否启用工作负载身份
+	SetEnable(bool)
+	// GetType 身份提供插件名
+	GetType() string
+	// SetType 设置身份提供插件名
+	SetType(string)
+	// GetLabelKey 在路由及限流请求中携带调用方身份所用的标签名
+	GetLabelKey() string
+	// SetLabelKey 设置调用方身份标签名
+	SetLabelKey(string)
+}
+
+// MetadataEncryptionConfig 实例元数据加密配置.
+type MetadataEncryptionConfig interface {
+	BaseConfig
+	// IsEnable 是否启用元数据加密
+	IsEnable() bool
+	// SetEnable 设置是否启用元数据加密
+	SetEnable(bool)
+	// GetCipher 加解密器名称
+	GetCipher() string
+	// SetCipher 设置加解密器名称
+	SetCipher(string)
+	// GetSensitiveKeys 注册时需要加密的元数据key
+	GetSensitiveKeys() []string
+	// SetSensitiveKeys 设置需要加密的元数据key
+	SetSensitiveKeys([]string)
+}
+
+// ConfigReloadConfig SDK自身配置热更新配置.
+type ConfigReloadConfig interface {
+	BaseConfig
+	// IsEnable 是否启用配置热更新
+	IsEnable() bool
+	// SetEnable 设置是否启用配置热更新
+	SetEnable(bool)
+	// GetFile 监听的本地配置文件
+	GetFile() string
+	// SetFile 设置监听的本地配置文件
+	SetFile(string)
+	// GetInterval 检查本地配置文件变更的周期
+	GetInterval() time.Duration
+	// SetInterval 设置检查本地配置文件变更的周期
+	SetInterval(time.Duration)
+	// GetNamespace 配置中心中引导配置文件的命名空间
+	GetNamespace() string
+	// GetFileGroup 配置中心中引导配置文件的分组
+	GetFileGroup() string
+	// GetFileName 配置中心中引导配置文件的文件名
+	GetFileName() string
+	// SetConfigCenterFile 设置配置中心中的引导配置文件
+	SetConfigCenterFile(namespace, fileGroup, fileName string)
+	// GetLogLevels 各日志模块的打印级别
+	GetLogLevels() map[string]string
+	// SetLogLevels 设置各日志模块的打印级别
+	SetLogLevels(map[string]string)
+}
+
+// ListenerDispatchConfig 监听器回调分发配置.
+type ListenerDispatchConfig interface {
+	BaseConfig
+	// GetWorkers 分发协程数
+	GetWorkers() int
+	// SetWorkers 设置分发协程数
+	SetWorkers(int)
+	// GetQueueSize 每个分发协程的队列长度
+	GetQueueSize() int
+	// SetQueueSize 设置每个分发协程的队列长度
+	SetQueueSize(int)
+	// GetOverflowPolicy 队列满时的处理策略
+	GetOverflowPolicy() string
+	// SetOverflowPolicy 设置队列满时的处理策略
+	SetOverflowPolicy(string)
+}
+
+// DiagnosticsConfig 调用诊断配置，对慢调用及失败调用进行采样.
+type DiagnosticsConfig interface {
+	BaseConfig
+	// IsEnable 是否启用调用诊断
+	IsEnable() bool
+	// SetEnable 设置是否启用调用诊断
+	SetEnable(bool)
+	// GetSlowThreshold 调用耗时超过该值时视为慢调用
+	GetSlowThreshold() time.Duration
+	// SetSlowThreshold 设置慢调用阈值
+	SetSlowThreshold(time.Duration)
+	// GetMaxSamplesPerMinute 每分钟最多采样的调用数
+	GetMaxSamplesPerMinute() int
+	// SetMaxSamplesPerMinute 设置每分钟最多采样的调用数
+	SetMaxSamplesPerMinute(int)
+	// GetBufferSize 保留的最近采样记录数
+	GetBufferSize() int
+	// SetBufferSize 设置保留的最近采样记录数
+	SetBufferSize(int)
+}
+
+// AdminConfig 本地管理端口配置，用于输出SDK内部状态.
+type AdminConfig interface {
+	BaseConfig
+	// IsEnable 是否启用管理端口
+	IsEnable() bool
+	// SetEnable 设置是否启用管理端口
+	SetEnable(bool)
+	// GetHost 管理端口监听的地址
+	GetHost() string
+	// SetHost 设置管理端口监听的地址
+	SetHost(string)
+	// GetPort 管理端口监听的端口
+	GetPort() int
+	// SetPort 设置管理端口监听的端口
+	SetPort(int)
+}
+
+// RegexConfig 规则中正则表达式的编译配置.
+type RegexConfig interface {
+	BaseConfig
+	// GetEngine 正则引擎，取值为regexp2或std
+	GetEngine() string
+	// SetEngine 设置正则引擎
+	SetEngine(engine string)
+	// GetMaxLength 表达式最大长度，超出的表达式视为非法
+	GetMaxLength() int
+	// SetMaxLength 设置表达式最大长度
+	SetMaxLength(length int)
+	// GetMatchTimeout regexp2引擎单次匹配的超时时间
+	GetMatchTimeout() time.Duration
+	// SetMatchTimeout 设置单次匹配的超时时间
+	SetMatchTimeout(timeout time.Duration)
+	// ToRegexOptions 转换为正则表达式的编译选项
+	ToRegexOptions() model.RegexOptions
 }
 
 // ConsumerConfig consumer config object.
@@ -61,10 +215,91 @@ type ConsumerConfig interface {
 	GetCircuitBreaker() CircuitBreakerConfig
 	// GetHealthCheck get health check config
 	GetHealthCheck() HealthCheckConfig
+	// GetFaultInjection get fault injection config
+	GetFaultInjection() FaultInjectionConfig
+	// GetRetry get retry config
+	GetRetry() RetryConfig
+	// GetHedging get hedging config
+	GetHedging() HedgingConfig
 	// GetServiceSpecific 服务独立配置
 	GetServiceSpecific(namespace string, service string) ServiceSpecificConfig
 }
 
+// FaultInjectionConfig 故障注入配置.
+type FaultInjectionConfig interface {
+	BaseConfig
+	// IsEnable 是否启用故障注入
+	IsEnable() bool
+	// SetEnable 设置是否启用故障注入
+	SetEnable(enable bool)
+	// GetNamespace 规则所在配置文件的命名空间
+	GetNamespace() string
+	// SetNamespace 设置规则所在配置文件的命名空间
+	SetNamespace(namespace string)
+	// GetFileGroup 规则所在配置文件的分组
+	GetFileGroup() string
+	// SetFileGroup 设置规则所在配置文件的分组
+	SetFileGroup(group string)
+	// GetFileName 规则所在配置文件名
+	GetFileName() string
+	// SetFileName 设置规则所在配置文件名
+	SetFileName(name string)
+}
+
+// RetryConfig 调用重试配置.
+type RetryConfig interface {
+	BaseConfig
+	// GetMaxAttempts 最大调用次数，包含首次调用
+	GetMaxAttempts() int
+	// SetMaxAttempts 设置最大调用次数
+	SetMaxAttempts(maxAttempts int)
+	// GetPerTryTimeout 单次调用超时时间
+	GetPerTryTimeout() time.Duration
+	// SetPerTryTimeout 设置单次调用超时时间
+	SetPerTryTimeout(timeout time.Duration)
+	// GetRetryableCodes 可重试的返回码，为空表示所有失败均可重试
+	GetRetryableCodes() []int32
+	// SetRetryableCodes 设置可重试的返回码
+	SetRetryableCodes(codes []int32)
+	// GetBaseBackoff 指数退避的初始间隔
+	GetBaseBackoff() time.Duration
+	// SetBaseBackoff 设置指数退避的初始间隔
+	SetBaseBackoff(backoff time.Duration)
+	// GetMaxBackoff 指数退避的最大间隔
+	GetMaxBackoff() time.Duration
+	// SetMaxBackoff 设置指数退避的最大间隔
+	SetMaxBackoff(backoff time.Duration)
+	// GetBudgetPercent 重试预算，重试请求占总请求的最大百分比
+	GetBudgetPercent() float64
+	// SetBudgetPercent 设置重试预算百分比
+	SetBudgetPercent(percent float64)
+	// GetMinRetriesPerSecond 不受预算限制的每秒最小重试数
+	GetMinRetriesPerSecond() int
+	// SetMinRetriesPerSecond 设置每秒最小重试数
+	SetMinRetriesPerSecond(count int)
+}
+
+// HedgingConfig 对冲请求配置.
+type HedgingConfig interface {
+	BaseConfig
+	// GetPercentile 计算对冲延迟所用的时延分位值
+	GetPercentile() float64
+	// SetPercentile 设置时延分位值
+	SetPercentile(percentile float64)
+	// GetDefaultDelay 时延样本不足时使用的对冲延迟
+	GetDefaultDelay() time.Duration
+	// SetDefaultDelay 设置默认对冲延迟
+	SetDefaultDelay(delay time.Duration)
+	// GetMinSamples 按分位值计算对冲延迟所需的最小样本数
+	GetMinSamples() int
+	// SetMinSamples 设置最小样本数
+	SetMinSamples(count int)
+	// GetSampleSize 每个服务保留的最近时延样本数
+	GetSampleSize() int
+	// SetSampleSize 设置时延样本数
+	SetSampleSize(size int)
+}
+
 // ProviderConfig 被调端配置对象.
 type ProviderConfig interface {
 	BaseConfig
@@ -72,6 +307,49 @@ type ProviderConfig interface {
 	GetRateLimit() RateLimitConfig
 	// GetMinRegisterInterval get minimum interval between two register operation
 	GetMinRegisterInterval() time.Duration
+	// GetDrainPeriod 优雅下线时隔离实例后等待排空的最大时长
+	GetDrainPeriod() time.Duration
+	// SetDrainPeriod 设置优雅下线的排空时长
+	SetDrainPeriod(period time.Duration)
+	// IsDeregisterOnDestroy 优雅销毁SDK上下文时是否反注册自动心跳的实例
+	IsDeregisterOnDestroy() bool
+	// SetDeregisterOnDestroy 设置优雅销毁SDK上下文时是否反注册自动心跳的实例
+	SetDeregisterOnDestroy(bool)
+	// GetMetadataUpdateInterval 实例元数据更新的最小推送间隔
+	GetMetadataUpdateInterval() time.Duration
+	// SetMetadataUpdateInterval 设置实例元数据更新的最小推送间隔
+	SetMetadataUpdateInterval(interval time.Duration)
+	// GetAdaptiveWeight 自适应权重配置
+	GetAdaptiveWeight() AdaptiveWeightConfig
+}
+
+// AdaptiveWeightConfig 自适应权重配置.
+type AdaptiveWeightConfig interface {
+	BaseConfig
+	// IsEnable 是否启用自适应权重
+	IsEnable() bool
+	// SetEnable 设置是否启用自适应权重
+	SetEnable(enable bool)
+	// GetMode 调整方式，weight或metadata
+	GetMode() string
+	// SetMode 设置调整方式
+	SetMode(mode string)
+	// GetInterval 负载采样及上报的周期
+	GetInterval() time.Duration
+	// SetInterval 设置采样周期
+	SetInterval(interval time.Duration)
+	// GetLoadThreshold 负载超过该值后开始降低权重
+	GetLoadThreshold() float64
+	// SetLoadThreshold 设置负载阈值
+	SetLoadThreshold(threshold float64)
+	// GetMinWeight 降低权重的下限
+	GetMinWeight() int
+	// SetMinWeight 设置权重下限
+	SetMinWeight(weight int)
+	// GetMetadataKey metadata方式下负载分数的键
+	GetMetadataKey() string
+	// SetMetadataKey 设置负载分数的键
+	SetMetadataKey(key string)
 }
 
 // ConfigFileConfig 配置中心的配置.
@@ -117,6 +395,18 @@ type RateLimitConfig interface {
 	SetLimiterNamespace(value string)
 	// GetLimiterNamespace 获取限流命名空间
 	GetLimiterNamespace() string
+	// IsShadow 是否对所有规则开启影子模式
+	IsShadow() bool
+	// SetShadow 设置是否对所有规则开启影子模式
+	SetShadow(bool)
+	// GetShadowRules 获取开启影子模式的规则名列表
+	GetShadowRules() []string
+	// SetShadowRules 设置开启影子模式的规则名列表
+	SetShadowRules([]string)
+	// GetParentRules 获取子规则名到父规则名的映射
+	GetParentRules() map[string]string
+	// SetParentRules 设置子规则名到父规则名的映射
+	SetParentRules(map[string]string)
 }
 
 // SystemConfig 系统配置信息.
@@ -144,6 +434,33 @@ type SystemConfig interface {
 	SetVariable(key, value string)
 	// UnsetVariable 取消一个路由环境变量
 	UnsetVariable(key string)
+	// AddVariableListener 添加路由环境变量变更的监听
+	AddVariableListener(listener func(key string))
+	// GetVariableResolvers global.systemConfig.variableResolvers
+	// 路由环境变量的解析链
+	GetVariableResolvers() []string
+	// SetVariableResolvers 设置路由环境变量的解析链
+	SetVariableResolvers(resolvers []string)
+	// GetVariableFile global.systemConfig.variableFile
+	// 配置中心解析器读取的变量文件
+	GetVariableFile() VariableFileConfig
+}
+
+// VariableFileConfig 存放路由环境变量的配置中心文件.
+type VariableFileConfig interface {
+	BaseConfig
+	// GetNamespace 配置文件所在命名空间
+	GetNamespace() string
+	// SetNamespace 设置配置文件所在命名空间
+	SetNamespace(namespace string)
+	// GetFileGroup 配置文件分组
+	GetFileGroup() string
+	// SetFileGroup 设置配置文件分组
+	SetFileGroup(fileGroup string)
+	// GetFileName 配置文件名
+	GetFileName() string
+	// SetFileName 设置配置文件名
+	SetFileName(fileName string)
 }
 
 // ServerClusterConfig 单个系统服务集群.
@@ -212,6 +529,20 @@ type StatReporterConfig interface {
 	SetChain([]string)
 }
 
+// EventReporterConfig 治理事件上报配置.
+type EventReporterConfig interface {
+	BaseConfig
+	PluginConfig
+	// IsEnable 是否启用事件上报
+	IsEnable() bool
+	// SetEnable 设置是否启用事件上报
+	SetEnable(bool)
+	// GetChain 事件上报器插件链
+	GetChain() []string
+	// SetChain 设置事件上报器插件链
+	SetChain([]string)
+}
+
 // LocationConfig SDK获取自身当前地理位置配置.
 type LocationConfig interface {
 	BaseConfig
@@ -227,6 +558,8 @@ type ClientConfig interface {
 	GetId() string
 	// GetLabels 获取客户端标签
 	GetLabels() map[string]string
+	// GetEnv 获取客户端所属环境
+	GetEnv() string
 }
 
 // ServerConnectorConfig 与名字服务服务端的连接配置.
@@ -276,6 +609,86 @@ type ServerConnectorConfig interface {
 	GetToken() string
 	// SetToken .
 	SetToken(string)
+	// GetClusters global.serverConnector.clusters
+	// 按优先级排序的备用server集群
+	GetClusters() []*PriorityClusterConfig
+	// SetClusters 设置备用server集群
+	SetClusters([]*PriorityClusterConfig)
+	// GetFailoverThreshold global.serverConnector.failoverThreshold
+	// 连续失败多少次后切换到下一个集群
+	GetFailoverThreshold() int32
+	// SetFailoverThreshold 设置切换集群的连续失败次数
+	SetFailoverThreshold(int32)
+	// GetFailbackWindow global.serverConnector.failbackWindow
+	// 高优先级集群持续健康多久后切回
+	GetFailbackWindow() time.Duration
+	// SetFailbackWindow 设置切回高优先级集群的稳定窗口
+	SetFailbackWindow(time.Duration)
+	// GetClusterProbeInterval global.serverConnector.clusterProbeInterval
+	// 集群健康探测的间隔
+	GetClusterProbeInterval() time.Duration
+	// SetClusterProbeInterval 设置集群健康探测的间隔
+	SetClusterProbeInterval(time.Duration)
+	// GetTokenFile global.serverConnector.tokenFile
+	// 鉴权token文件，文件内容变更后自动生效
+	GetTokenFile() string
+	// SetTokenFile 设置鉴权token文件
+	SetTokenFile(string)
+	// GetTokenEnv global.serverConnector.tokenEnv
+	// 存放鉴权token的环境变量名
+	GetTokenEnv() string
+	// SetTokenEnv 设置存放鉴权token的环境变量名
+	SetTokenEnv(string)
+	// GetTokenRefreshInterval global.serverConnector.tokenRefreshInterval
+	// 重新读取鉴权token文件的间隔
+	GetTokenRefreshInterval() time.Duration
+	// SetTokenRefreshInterval 设置重新读取鉴权token文件的间隔
+	SetTokenRefreshInterval(time.Duration)
+	// GetTLS global.serverConnector.tls
+	// 与server通信的TLS配置
+	GetTLS() TLSConfig
+	// IsShareConnection global.serverConnector.shareConnection
+	// 是否与进程内其他SDK上下文共享连接
+	IsShareConnection() bool
+	// SetShareConnection 设置是否与进程内其他SDK上下文共享连接
+	SetShareConnection(bool)
+}
+
+// TLSConfig 与server通信的TLS配置.
+type TLSConfig interface {
+	BaseConfig
+	// IsEnable 是否启用TLS
+	IsEnable() bool
+	// SetEnable 设置是否启用TLS
+	SetEnable(bool)
+	// GetCAFile 校验服务端证书的CA证书文件，为空时使用系统根证书
+	GetCAFile() string
+	// SetCAFile 设置CA证书文件
+	SetCAFile(string)
+	// GetCertFile 双向认证时使用的客户端证书文件
+	GetCertFile() string
+	// SetCertFile 设置客户端证书文件
+	SetCertFile(string)
+	// GetKeyFile 双向认证时使用的客户端私钥文件
+	GetKeyFile() string
+	// SetKeyFile 设置客户端私钥文件
+	SetKeyFile(string)
+	// GetServerName 校验服务端证书及SNI使用的服务端名称
+	GetServerName() string
+	// SetServerName 设置服务端名称
+	SetServerName(string)
+	// IsInsecureSkipVerify 是否跳过服务端证书校验
+	IsInsecureSkipVerify() bool
+	// SetInsecureSkipVerify 设置是否跳过服务端证书校验
+	SetInsecureSkipVerify(bool)
+}
+
+// PriorityClusterConfig 按优先级故障转移的一个独立server集群.
+type PriorityClusterConfig struct {
+	// 集群名称，仅用于日志
+	Name string `yaml:"name" json:"name"`
+	// 集群server地址，格式为<host>:<port>
+	Addresses []string `yaml:"addresses" json:"addresses"`
 }
 
 // LocalCacheConfig 本地缓存相关配置项.
@@ -334,6 +747,21 @@ type LocalCacheConfig interface {
 	SetPushEmptyProtection(pushEmptyProtection bool)
 	// GetPushEmptyProtection 获取推空保护开关
 	GetPushEmptyProtection() bool
+	// GetPersistFormat consumer.localCache.persistFormat
+	// 缓存文件格式，json或binary
+	GetPersistFormat() string
+	// SetPersistFormat 设置缓存文件格式
+	SetPersistFormat(string)
+	// GetMaxServiceCount consumer.localCache.maxServiceCount
+	// 本地缓存的最大服务数，0表示不限制
+	GetMaxServiceCount() int
+	// SetMaxServiceCount 设置本地缓存的最大服务数
+	SetMaxServiceCount(int)
+	// GetCipher consumer.localCache.cipher
+	// 缓存文件加解密器名称，为空时不加密
+	GetCipher() string
+	// SetCipher 设置缓存文件加解密器名称
+	SetCipher(string)
 }
 
 // NearbyConfig 就近路由配置.
@@ -368,6 +796,15 @@ type NearbyConfig interface {
 	GetUnhealthyPercentToDegrade() int
 	// SetUnhealthyPercentToDegrade 设置触发降级匹配的不健康实例比例,consumer.serviceRouter.plugin.nearbyBasedRouter.unhealthyPercentToDegrade
 	SetUnhealthyPercentToDegrade(u int)
+	// GetSpillHealthyPercent 获取触发流量溢出的健康实例比例,
+	// consumer.serviceRouter.plugin.nearbyBasedRouter.spillHealthyPercent
+	GetSpillHealthyPercent() int
+	// SetSpillHealthyPercent 设置触发流量溢出的健康实例比例,consumer.serviceRouter.plugin.nearbyBasedRouter.spillHealthyPercent
+	SetSpillHealthyPercent(p int)
+	// GetSpillPercent 获取溢出到上一级别的流量比例,consumer.serviceRouter.plugin.nearbyBasedRouter.spillPercent
+	GetSpillPercent() int
+	// SetSpillPercent 设置溢出到上一级别的流量比例,consumer.serviceRouter.plugin.nearbyBasedRouter.spillPercent
+	SetSpillPercent(p int)
 }
 
 // ServiceRouterConfig 服务路由相关配置项.
@@ -402,6 +839,21 @@ type LoadbalancerConfig interface {
 	GetType() string
 	// SetType 设置负载均衡类型
 	SetType(string)
+	// GetWarmup 新实例预热配置
+	GetWarmup() WarmupConfig
+}
+
+// WarmupConfig 新实例预热配置.
+type WarmupConfig interface {
+	BaseConfig
+	// IsEnable 是否启用预热
+	IsEnable() bool
+	// SetEnable 设置是否启用预热
+	SetEnable(enable bool)
+	// GetWindow 预热窗口，窗口内实例的有效权重随注册时长线性增长
+	GetWindow() time.Duration
+	// SetWindow 设置预热窗口
+	SetWindow(window time.Duration)
 }
 
 // CircuitBreakerConfig 熔断相关的配置项.
@@ -452,6 +904,22 @@ type CircuitBreakerConfig interface {
 	// SetRecoverNumBuckets 设置半开后请求数统计滑桶数量
 	// Deprecated: 不在使用
 	SetRecoverNumBuckets(value int)
+	// IsMethodLevel 是否按服务+方法维度统计携带方法名的调用结果
+	IsMethodLevel() bool
+	// SetMethodLevel 设置是否按服务+方法维度统计调用结果
+	SetMethodLevel(methodLevel bool)
+	// IsMethodIsolation 是否开启接口级熔断隔离，开启后携带方法名的调用结果不计入实例级熔断
+	IsMethodIsolation() bool
+	// SetMethodIsolation 设置是否开启接口级熔断隔离
+	SetMethodIsolation(isolation bool)
+	// GetHalfOpenRamp 获取半开后的逐级放量比例
+	GetHalfOpenRamp() []int
+	// SetHalfOpenRamp 设置半开后的逐级放量比例，如[1, 5, 25, 100]
+	SetHalfOpenRamp(ramp []int)
+	// GetHalfOpenRampWindow 获取半开放量每一级的观察窗口
+	GetHalfOpenRampWindow() time.Duration
+	// SetHalfOpenRampWindow 设置半开放量每一级的观察窗口
+	SetHalfOpenRampWindow(window time.Duration)
 	// GetErrorCountConfig 连续错误数熔断配置
 	// Deprecated: 不在使用
 	GetErrorCountConfig() ErrorCountConfig
@@ -551,6 +1019,11 @@ type ConfigLocalCacheConfig interface {
 	SetFallbackToLocalCache(enable bool)
 	// IsFallbackToLocalCache .
 	IsFallbackToLocalCache() bool
+	// GetCipher config.localCache.cipher
+	// 缓存文件加解密器名称，为空时不加密
+	GetCipher() string
+	// SetCipher 设置缓存文件加解密器名称
+	SetCipher(string)
 }
 
 // ConfigConnectorConfig 配置中心连接相关的配置.
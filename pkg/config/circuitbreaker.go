@@ -46,6 +46,14 @@ type CircuitBreakerConfigImpl struct {
 	RecoverWindow *time.Duration `yaml:"recoverWindow" json:"recoverWindow"`
 	// RecoverNumBuckets 半开后的统计的滑窗数
 	RecoverNumBuckets int `yaml:"recoverNumBuckets" json:"recoverNumBuckets"`
+	// MethodLevel 是否按服务+方法维度统计携带方法名的调用结果，开启后单个接口异常可只熔断该接口
+	MethodLevel bool `yaml:"methodLevel" json:"methodLevel"`
+	// MethodIsolation 接口级熔断隔离，需开启MethodLevel，开启后携带方法名的调用结果只计入服务+方法维度，不影响实例级熔断
+	MethodIsolation bool `yaml:"methodIsolation" json:"methodIsolation"`
+	// HalfOpenRamp 半开后的逐级放量比例，如[1, 5, 25, 100]，为空则使用固定探测请求数
+	HalfOpenRamp []int `yaml:"halfOpenRamp" json:"halfOpenRamp"`
+	// HalfOpenRampWindow 半开放量每一级的观察窗口，窗口内无失败则进入下一级
+	HalfOpenRampWindow *time.Duration `yaml:"halfOpenRampWindow" json:"halfOpenRampWindow"`
 	// Plugin 插件配置反序列化后的对象
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
 }
@@ -130,6 +138,46 @@ func (c *CircuitBreakerConfigImpl) SetRecoverNumBuckets(value int) {
 	c.RecoverNumBuckets = value
 }
 
+// IsMethodLevel 是否按服务+方法维度统计调用结果
+func (c *CircuitBreakerConfigImpl) IsMethodLevel() bool {
+	return c.MethodLevel
+}
+
+// SetMethodLevel 设置是否按服务+方法维度统计调用结果
+func (c *CircuitBreakerConfigImpl) SetMethodLevel(methodLevel bool) {
+	c.MethodLevel = methodLevel
+}
+
+// IsMethodIsolation 是否开启接口级熔断隔离
+func (c *CircuitBreakerConfigImpl) IsMethodIsolation() bool {
+	return c.MethodIsolation
+}
+
+// SetMethodIsolation 设置是否开启接口级熔断隔离
+func (c *CircuitBreakerConfigImpl) SetMethodIsolation(isolation bool) {
+	c.MethodIsolation = isolation
+}
+
+// GetHalfOpenRamp 获取半开后的逐级放量比例
+func (c *CircuitBreakerConfigImpl) GetHalfOpenRamp() []int {
+	return c.HalfOpenRamp
+}
+
+// SetHalfOpenRamp 设置半开后的逐级放量比例
+func (c *CircuitBreakerConfigImpl) SetHalfOpenRamp(ramp []int) {
+	c.HalfOpenRamp = ramp
+}
+
+// GetHalfOpenRampWindow 获取半开放量每一级的观察窗口
+func (c *CircuitBreakerConfigImpl) GetHalfOpenRampWindow() time.Duration {
+	return *c.HalfOpenRampWindow
+}
+
+// SetHalfOpenRampWindow 设置半开放量每一级的观察窗口
+func (c *CircuitBreakerConfigImpl) SetHalfOpenRampWindow(window time.Duration) {
+	c.HalfOpenRampWindow = &window
+}
+
 // GetErrorCountConfig 获取连续错误数熔断配置
 func (c *CircuitBreakerConfigImpl) GetErrorCountConfig() ErrorCountConfig {
 	return c.Plugin[DefaultCircuitBreakerErrCount].(ErrorCountConfig)
@@ -173,12 +221,40 @@ func (c *CircuitBreakerConfigImpl) Verify() error {
 			fmt.Errorf(
 				"consumer.circuitbreaker.recoverNumBuckets must be greater than %d", MinRecoverNumBuckets))
 	}
+	if len(c.HalfOpenRamp) > 0 {
+		if err := verifyHalfOpenRamp(c.HalfOpenRamp); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	if nil != c.HalfOpenRampWindow && *c.HalfOpenRampWindow <= 0 {
+		errs = multierror.Append(errs,
+			fmt.Errorf("consumer.circuitbreaker.halfOpenRampWindow must be greater than 0"))
+	}
+	if c.MethodIsolation && !c.MethodLevel {
+		errs = multierror.Append(errs,
+			fmt.Errorf("consumer.circuitbreaker.methodIsolation requires consumer.circuitbreaker.methodLevel"))
+	}
 	if err := c.Plugin.Verify(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
 	return errs
 }
 
+// verifyHalfOpenRamp 放量比例必须在(0, 100]内严格递增，且最后一级为100
+func verifyHalfOpenRamp(ramp []int) error {
+	prev := 0
+	for _, percent := range ramp {
+		if percent <= prev || percent > 100 {
+			return fmt.Errorf("consumer.circuitbreaker.halfOpenRamp must be increasing in (0, 100], got %v", ramp)
+		}
+		prev = percent
+	}
+	if prev != 100 {
+		return fmt.Errorf("consumer.circuitbreaker.halfOpenRamp must end with 100, got %v", ramp)
+	}
+	return nil
+}
+
 // SetDefault 设置CircuitBreakerConfigImpl配置的默认值
 func (c *CircuitBreakerConfigImpl) SetDefault() {
 	if nil == c.CheckPeriod {
@@ -206,6 +282,9 @@ func (c *CircuitBreakerConfigImpl) SetDefault() {
 	if c.RecoverNumBuckets == 0 {
 		c.RecoverNumBuckets = DefaultRecoverNumBuckets
 	}
+	if nil == c.HalfOpenRampWindow {
+		c.HalfOpenRampWindow = model.ToDurationPtr(DefaultHalfOpenRampWindow)
+	}
 	c.Plugin.SetDefault(common.TypeCircuitBreaker)
 }
 
@@ -53,6 +53,30 @@ type ConfigConnectorConfigImpl struct {
 
 	Token string `yaml:"token" json:"token"`
 
+	// 备用集群列表，按优先级排序，addresses 所在集群优先级最高
+	Clusters []*PriorityClusterConfig `yaml:"clusters" json:"clusters"`
+
+	FailoverThreshold *int32 `yaml:"failoverThreshold" json:"failoverThreshold"`
+
+	FailbackWindow *time.Duration `yaml:"failbackWindow" json:"failbackWindow"`
+
+	ClusterProbeInterval *time.Duration `yaml:"clusterProbeInterval" json:"clusterProbeInterval"`
+
+	// 鉴权token文件，文件内容变更后自动生效，优先级高于token
+	TokenFile string `yaml:"tokenFile" json:"tokenFile"`
+
+	// 存放鉴权token的环境变量名，优先级高于token
+	TokenEnv string `yaml:"tokenEnv" json:"tokenEnv"`
+
+	// 重新读取鉴权token文件的间隔
+	TokenRefreshInterval *time.Duration `yaml:"tokenRefreshInterval" json:"tokenRefreshInterval"`
+
+	// 与server通信的TLS配置
+	TLS *TLSConfigImpl `yaml:"tls" json:"tls"`
+
+	// 是否与进程内其他开启共享的SDK上下文复用到同一server地址的连接
+	ShareConnection *bool `yaml:"shareConnection" json:"shareConnection"`
+
 	ConnectorType string `yaml:"connectorType" json:"connectorType"`
 }
 
@@ -170,15 +194,123 @@ func (c *ConfigConnectorConfigImpl) SetToken(token string) {
 	c.Token = token
 }
 
+// GetClusters config.configConnector.clusters
+// 按优先级排序的备用server集群.
+func (c *ConfigConnectorConfigImpl) GetClusters() []*PriorityClusterConfig {
+	return c.Clusters
+}
+
+// SetClusters 设置备用server集群.
+func (c *ConfigConnectorConfigImpl) SetClusters(clusters []*PriorityClusterConfig) {
+	c.Clusters = clusters
+}
+
+// GetFailoverThreshold config.configConnector.failoverThreshold
+// 连续失败多少次后切换到下一个集群.
+func (c *ConfigConnectorConfigImpl) GetFailoverThreshold() int32 {
+	return *c.FailoverThreshold
+}
+
+// SetFailoverThreshold 设置切换集群的连续失败次数.
+func (c *ConfigConnectorConfigImpl) SetFailoverThreshold(threshold int32) {
+	c.FailoverThreshold = &threshold
+}
+
+// GetFailbackWindow config.configConnector.failbackWindow
+// 高优先级集群持续健康多久后切回.
+func (c *ConfigConnectorConfigImpl) GetFailbackWindow() time.Duration {
+	return *c.FailbackWindow
+}
+
+// SetFailbackWindow 设置切回高优先级集群的稳定窗口.
+func (c *ConfigConnectorConfigImpl) SetFailbackWindow(window time.Duration) {
+	c.FailbackWindow = &window
+}
+
+// GetClusterProbeInterval config.configConnector.clusterProbeInterval
+// 集群健康探测的间隔.
+func (c *ConfigConnectorConfigImpl) GetClusterProbeInterval() time.Duration {
+	return *c.ClusterProbeInterval
+}
+
+// SetClusterProbeInterval 设置集群健康探测的间隔.
+func (c *ConfigConnectorConfigImpl) SetClusterProbeInterval(interval time.Duration) {
+	c.ClusterProbeInterval = &interval
+}
+
+// GetTokenFile config.configConnector.tokenFile
+// 鉴权token文件.
+func (c *ConfigConnectorConfigImpl) GetTokenFile() string {
+	return c.TokenFile
+}
+
+// SetTokenFile 设置鉴权token文件.
+func (c *ConfigConnectorConfigImpl) SetTokenFile(file string) {
+	c.TokenFile = file
+}
+
+// GetTokenEnv config.configConnector.tokenEnv
+// 存放鉴权token的环境变量名.
+func (c *ConfigConnectorConfigImpl) GetTokenEnv() string {
+	return c.TokenEnv
+}
+
+// SetTokenEnv 设置存放鉴权token的环境变量名.
+func (c *ConfigConnectorConfigImpl) SetTokenEnv(env string) {
+	c.TokenEnv = env
+}
+
+// GetTokenRefreshInterval config.configConnector.tokenRefreshInterval
+// 重新读取鉴权token文件的间隔.
+func (c *ConfigConnectorConfigImpl) GetTokenRefreshInterval() time.Duration {
+	return *c.TokenRefreshInterval
+}
+
+// SetTokenRefreshInterval 设置重新读取鉴权token文件的间隔.
+func (c *ConfigConnectorConfigImpl) SetTokenRefreshInterval(interval time.Duration) {
+	c.TokenRefreshInterval = &interval
+}
+
+// GetTLS config.configConnector.tls
+// 与server通信的TLS配置.
+func (c *ConfigConnectorConfigImpl) GetTLS() TLSConfig {
+	return c.TLS
+}
+
+// IsShareConnection config.configConnector.shareConnection
+// 是否与进程内其他SDK上下文共享连接.
+func (c *ConfigConnectorConfigImpl) IsShareConnection() bool {
+	return *c.ShareConnection
+}
+
+// SetShareConnection 设置是否与进程内其他SDK上下文共享连接.
+func (c *ConfigConnectorConfigImpl) SetShareConnection(share bool) {
+	c.ShareConnection = &share
+}
+
 // Verify 检验ConfigConnector配置.
 func (c *ConfigConnectorConfigImpl) Verify() error {
 	if nil == c {
 		return errors.New("ConfigConnectorConfig is nil")
 	}
 	var errs error
-	if len(c.Addresses) == 0 {
+	if len(c.Addresses) == 0 && len(c.Clusters) == 0 {
 		errs = multierror.Append(errs, fmt.Errorf("config.configConnector.addresses is empty"))
 	}
+	for i, cluster := range c.Clusters {
+		if cluster == nil || len(cluster.Addresses) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("config.configConnector.clusters[%d].addresses is empty", i))
+		}
+	}
+	if c.FailoverThreshold != nil && *c.FailoverThreshold <= 0 {
+		errs = multierror.Append(errs,
+			fmt.Errorf("config.configConnector.failoverThreshold %v is invalid", *c.FailoverThreshold))
+	}
+	if c.ClusterProbeInterval != nil && *c.ClusterProbeInterval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("config.configConnector.clusterProbeInterval %v is less than minimal timing interval %v",
+				*c.ClusterProbeInterval, DefaultMinTimingInterval))
+	}
 	if nil != c.RequestQueueSize && *c.RequestQueueSize < 0 {
 		errs = multierror.Append(errs,
 			fmt.Errorf("config.configConnector.requestQueueSize %v is invalid", c.RequestQueueSize))
@@ -198,6 +330,16 @@ func (c *ConfigConnectorConfigImpl) Verify() error {
 	if len(c.ConnectorType) == 0 {
 		errs = multierror.Append(errs, fmt.Errorf("config.configConnector.connectorType is empty"))
 	}
+	if c.TokenRefreshInterval != nil && *c.TokenRefreshInterval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("config.configConnector.tokenRefreshInterval %v is less than minimal timing interval %v",
+				*c.TokenRefreshInterval, DefaultMinTimingInterval))
+	}
+	if nil != c.TLS {
+		if err := c.TLS.Verify(); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("config.configConnector.%v", err))
+		}
+	}
 	return errs
 }
 
@@ -224,12 +366,31 @@ func (c *ConfigConnectorConfigImpl) SetDefault() {
 	if len(c.Protocol) == 0 {
 		c.Protocol = DefaultConfigConnector
 	}
-	if len(c.Addresses) == 0 {
+	if c.FailoverThreshold == nil {
+		c.FailoverThreshold = proto.Int32(int32(DefaultClusterFailoverThreshold))
+	}
+	if c.FailbackWindow == nil {
+		c.FailbackWindow = model.ToDurationPtr(DefaultClusterFailbackWindow)
+	}
+	if c.ClusterProbeInterval == nil {
+		c.ClusterProbeInterval = model.ToDurationPtr(DefaultClusterProbeInterval)
+	}
+	if len(c.Addresses) == 0 && len(c.Clusters) == 0 {
 		c.SetAddresses([]string{DefaultConfigConnectorAddresses})
 	}
 	if len(c.ConnectorType) == 0 {
 		c.ConnectorType = DefaultConnectorType
 	}
+	if nil == c.TokenRefreshInterval {
+		c.TokenRefreshInterval = model.ToDurationPtr(DefaultTokenRefreshInterval)
+	}
+	if nil == c.TLS {
+		c.TLS = &TLSConfigImpl{}
+	}
+	c.TLS.SetDefault()
+	if nil == c.ShareConnection {
+		c.SetShareConnection(DefaultShareConnection)
+	}
 	c.Plugin.SetDefault(common.TypeConfigConnector)
 }
 
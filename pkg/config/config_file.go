@@ -154,6 +154,9 @@ type ConfigLocalCacheConfigImpl struct {
 	PersistRetryInterval *time.Duration `yaml:"persistRetryInterval" json:"persistRetryInterval"`
 	// config.localCache.fallbackToLocalCache
 	FallbackToLocalCache *bool `yaml:"fallbackToLocalCache" json:"fallbackToLocalCache"`
+	// config.localCache.cipher
+	// 缓存文件加解密器名称，为空时不加密
+	Cipher string `yaml:"cipher" json:"cipher"`
 }
 
 // IsPersistEnable consumer.localCache.persistEnable
@@ -224,6 +227,17 @@ func (l *ConfigLocalCacheConfigImpl) IsFallbackToLocalCache() bool {
 	return *l.FallbackToLocalCache
 }
 
+// GetCipher config.localCache.cipher
+// 缓存文件加解密器名称.
+func (l *ConfigLocalCacheConfigImpl) GetCipher() string {
+	return l.Cipher
+}
+
+// SetCipher 设置缓存文件加解密器名称.
+func (l *ConfigLocalCacheConfigImpl) SetCipher(name string) {
+	l.Cipher = name
+}
+
 // Verify 检验LocalCacheConfig配置.
 func (l *ConfigLocalCacheConfigImpl) Verify() error {
 	if nil == l {
@@ -0,0 +1,130 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConfigReloadConfigImpl SDK自身配置热更新配置，监听本地配置文件或配置中心中的引导配置文件，
+// 运行时生效可安全变更的配置项：日志级别、路由链、负载均衡类型、调用超时及熔断阈值
+type ConfigReloadConfigImpl struct {
+	// Enable 是否启用配置热更新
+	Enable *bool `yaml:"enable" json:"enable"`
+	// File 监听的本地配置文件，通过配置文件初始化时默认为该文件
+	File string `yaml:"file" json:"file"`
+	// Interval 检查本地配置文件变更的周期
+	Interval *time.Duration `yaml:"interval" json:"interval"`
+	// Namespace 配置中心中引导配置文件的命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// FileGroup 配置中心中引导配置文件的分组
+	FileGroup string `yaml:"fileGroup" json:"fileGroup"`
+	// FileName 配置中心中引导配置文件的文件名，为空时不监听配置中心
+	FileName string `yaml:"fileName" json:"fileName"`
+	// LogLevels 各日志模块的打印级别，module 为 * 时对所有模块生效
+	LogLevels map[string]string `yaml:"logLevels" json:"logLevels"`
+}
+
+// IsEnable 是否启用配置热更新
+func (c *ConfigReloadConfigImpl) IsEnable() bool {
+	return *c.Enable
+}
+
+// SetEnable 设置是否启用配置热更新
+func (c *ConfigReloadConfigImpl) SetEnable(enable bool) {
+	c.Enable = &enable
+}
+
+// GetFile 获取监听的本地配置文件
+func (c *ConfigReloadConfigImpl) GetFile() string {
+	return c.File
+}
+
+// SetFile 设置监听的本地配置文件
+func (c *ConfigReloadConfigImpl) SetFile(file string) {
+	c.File = file
+}
+
+// GetInterval 获取检查本地配置文件变更的周期
+func (c *ConfigReloadConfigImpl) GetInterval() time.Duration {
+	return *c.Interval
+}
+
+// SetInterval 设置检查本地配置文件变更的周期
+func (c *ConfigReloadConfigImpl) SetInterval(interval time.Duration) {
+	c.Interval = &interval
+}
+
+// GetNamespace 获取引导配置文件的命名空间
+func (c *ConfigReloadConfigImpl) GetNamespace() string {
+	return c.Namespace
+}
+
+// GetFileGroup 获取引导配置文件的分组
+func (c *ConfigReloadConfigImpl) GetFileGroup() string {
+	return c.FileGroup
+}
+
+// GetFileName 获取引导配置文件的文件名
+func (c *ConfigReloadConfigImpl) GetFileName() string {
+	return c.FileName
+}
+
+// SetConfigCenterFile 设置配置中心中的引导配置文件
+func (c *ConfigReloadConfigImpl) SetConfigCenterFile(namespace, fileGroup, fileName string) {
+	c.Namespace = namespace
+	c.FileGroup = fileGroup
+	c.FileName = fileName
+}
+
+// GetLogLevels 获取各日志模块的打印级别
+func (c *ConfigReloadConfigImpl) GetLogLevels() map[string]string {
+	return c.LogLevels
+}
+
+// SetLogLevels 设置各日志模块的打印级别
+func (c *ConfigReloadConfigImpl) SetLogLevels(levels map[string]string) {
+	c.LogLevels = levels
+}
+
+// Verify 检验配置热更新配置
+func (c *ConfigReloadConfigImpl) Verify() error {
+	if nil == c {
+		return errors.New("ConfigReloadConfig is nil")
+	}
+	if *c.Interval < DefaultMinTimingInterval {
+		return fmt.Errorf("global.configReload.interval %v is less than minimal timing interval %v",
+			*c.Interval, DefaultMinTimingInterval)
+	}
+	if len(c.FileName) > 0 && (len(c.Namespace) == 0 || len(c.FileGroup) == 0) {
+		return fmt.Errorf("global.configReload.namespace and fileGroup must be set when fileName is set")
+	}
+	return nil
+}
+
+// SetDefault 设置配置热更新配置的默认值
+func (c *ConfigReloadConfigImpl) SetDefault() {
+	if nil == c.Enable {
+		c.SetEnable(DefaultConfigReloadEnabled)
+	}
+	if nil == c.Interval {
+		c.SetInterval(DefaultConfigReloadInterval)
+	}
+}
@@ -55,8 +55,18 @@ const (
 	DefaultRequestQueueSize int = 1000
 	// DefaultServerSwitchInterval 默认server的切换时间时间.
 	DefaultServerSwitchInterval = 10 * time.Minute
+	// DefaultClusterFailoverThreshold 默认连续失败多少次后切换到下一个server集群.
+	DefaultClusterFailoverThreshold = 3
+	// DefaultClusterFailbackWindow 默认高优先级集群恢复后的稳定窗口.
+	DefaultClusterFailbackWindow = 1 * time.Minute
+	// DefaultClusterProbeInterval 默认server集群健康探测间隔.
+	DefaultClusterProbeInterval = 5 * time.Second
 	// DefaultCachePersistEnable 默认缓存持久化存储开启.
 	DefaultCachePersistEnable bool = true
+	// PersistFormatJSON 缓存文件使用json格式.
+	PersistFormatJSON = "json"
+	// PersistFormatBinary 缓存文件使用长度前缀的protobuf二进制格式.
+	PersistFormatBinary = "binary"
 	// DefaultCachePersistDir 默认缓存持久化存储目录.
 	DefaultCachePersistDir string = "./polaris/backup"
 	// DefaultPersistMaxWriteRetry 持久化缓存写文件的默认重试次数.
@@ -109,6 +119,84 @@ const (
 	DefaultRequestCountAfterHalfOpen = 10
 	// DefaultSuccessCountAfterHalfOpen 半开状态后恢复的成功请求数.
 	DefaultSuccessCountAfterHalfOpen = 8
+	// DefaultFaultInjectionEnabled 故障注入默认关闭.
+	DefaultFaultInjectionEnabled = false
+	// DefaultFaultInjectionNamespace 故障注入规则配置文件默认命名空间.
+	DefaultFaultInjectionNamespace = "Polaris"
+	// DefaultFaultInjectionFileGroup 故障注入规则配置文件默认分组.
+	DefaultFaultInjectionFileGroup = "fault-injection"
+	// DefaultFaultInjectionFileName 故障注入规则配置文件默认文件名.
+	DefaultFaultInjectionFileName = "rules.json"
+	// DefaultHalfOpenRampWindow 半开逐级放量时每一级的默认观察窗口.
+	DefaultHalfOpenRampWindow = 10 * time.Second
+	// DefaultRetryMaxAttempts 默认最大调用次数，包含首次调用.
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryPerTryTimeout 默认单次调用超时时间.
+	DefaultRetryPerTryTimeout = 1 * time.Second
+	// DefaultRetryBaseBackoff 默认重试退避初始间隔.
+	DefaultRetryBaseBackoff = 25 * time.Millisecond
+	// DefaultRetryMaxBackoff 默认重试退避最大间隔.
+	DefaultRetryMaxBackoff = 250 * time.Millisecond
+	// DefaultRetryBudgetPercent 默认重试预算，重试请求不超过总请求的20%.
+	DefaultRetryBudgetPercent = 20.0
+	// DefaultRetryMinRetriesPerSecond 默认每秒最小重试数.
+	DefaultRetryMinRetriesPerSecond = 10
+	// DefaultRegexMaxLength 默认的正则表达式最大长度.
+	DefaultRegexMaxLength = 1024
+	// DefaultVariableFileNamespace 默认的路由变量配置文件命名空间.
+	DefaultVariableFileNamespace = "Polaris"
+	// DefaultVariableFileGroup 默认的路由变量配置文件分组.
+	DefaultVariableFileGroup = "variables"
+	// DefaultRegexMatchTimeout 默认的regexp2单次匹配超时时间.
+	DefaultRegexMatchTimeout = 50 * time.Millisecond
+	// DefaultAdminEnabled 默认不启用本地管理端口.
+	DefaultAdminEnabled = false
+	// DefaultAdminHost 默认的管理端口监听地址.
+	DefaultAdminHost = "127.0.0.1"
+	// DefaultAdminPort 默认的管理端口.
+	DefaultAdminPort = 28090
+	// DefaultTLSEnabled 默认不启用TLS.
+	DefaultTLSEnabled = false
+	// DefaultTokenRefreshInterval 默认重新读取鉴权token文件的间隔.
+	DefaultTokenRefreshInterval = 30 * time.Second
+	// DefaultIdentityProviderEnabled 默认不启用工作负载身份.
+	DefaultIdentityProviderEnabled = false
+	// DefaultIdentityProvider 默认的身份提供插件.
+	DefaultIdentityProvider = "spiffe"
+	// DefaultIdentityLabelKey 默认的调用方身份标签名.
+	DefaultIdentityLabelKey = "spiffe_id"
+	// DefaultMetadataEncryptionEnabled 默认不启用元数据加密.
+	DefaultMetadataEncryptionEnabled = false
+	// DefaultMetadataCipher 默认的元数据加解密器，从环境变量读取共享密钥.
+	DefaultMetadataCipher = "metadataEnv"
+	// DefaultShareConnection 默认不与进程内其他SDK上下文共享连接.
+	DefaultShareConnection = false
+	// DefaultConfigReloadEnabled 默认不启用配置热更新.
+	DefaultConfigReloadEnabled = false
+	// DefaultConfigReloadInterval 默认检查本地配置文件变更的周期.
+	DefaultConfigReloadInterval = 5 * time.Second
+	// DefaultListenerDispatchWorkers 默认的监听器回调分发协程数.
+	DefaultListenerDispatchWorkers = 4
+	// DefaultListenerDispatchQueueSize 默认的每个分发协程的队列长度.
+	DefaultListenerDispatchQueueSize = 1024
+	// DefaultListenerOverflowPolicy 默认队列满时使用新协程执行回调，不阻塞规则更新也不丢弃事件.
+	DefaultListenerOverflowPolicy = "spawn"
+	// DefaultDiagnosticsEnabled 默认不启用调用诊断.
+	DefaultDiagnosticsEnabled = false
+	// DefaultDiagnosticsSlowThreshold 默认的慢调用阈值.
+	DefaultDiagnosticsSlowThreshold = time.Second
+	// DefaultDiagnosticsMaxSamplesPerMinute 默认每分钟最多采样的调用数.
+	DefaultDiagnosticsMaxSamplesPerMinute = 10
+	// DefaultDiagnosticsBufferSize 默认保留的采样记录数.
+	DefaultDiagnosticsBufferSize = 100
+	// DefaultHedgingPercentile 默认按P95时延计算对冲延迟.
+	DefaultHedgingPercentile = 95.0
+	// DefaultHedgingDelay 时延样本不足时的默认对冲延迟.
+	DefaultHedgingDelay = 50 * time.Millisecond
+	// DefaultHedgingSampleSize 每个服务默认保留的时延样本数.
+	DefaultHedgingSampleSize = 256
+	// DefaultHedgingMinSamples 按分位值计算对冲延迟的默认最小样本数.
+	DefaultHedgingMinSamples = 20
 	// DefaultRateLimitWindowCount 限流上报时间窗数量，上报间隔=时间间隔/时间窗数量.
 	DefaultRateLimitWindowCount = 10
 	// MinRateLimitReportInterval 最小限流上报周期.
@@ -141,6 +229,26 @@ const (
 	DefaultConfigConnectorAddresses = "127.0.0.1:8093"
 	// DefaultMinRegisterInterval
 	DefaultMinRegisterInterval = 30 * time.Second
+	// DefaultDrainPeriod 默认的优雅下线排空时长
+	DefaultDrainPeriod = 10 * time.Second
+	// DefaultDeregisterOnDestroy 默认优雅销毁时不反注册实例
+	DefaultDeregisterOnDestroy = false
+	// DefaultMetadataUpdateInterval 默认的实例元数据更新推送间隔
+	DefaultMetadataUpdateInterval = time.Second
+	// DefaultWarmupEnabled 默认不启用新实例预热
+	DefaultWarmupEnabled = false
+	// DefaultWarmupWindow 默认的新实例预热窗口
+	DefaultWarmupWindow = time.Minute
+	// DefaultAdaptiveWeightEnabled 默认不启用自适应权重
+	DefaultAdaptiveWeightEnabled = false
+	// DefaultAdaptiveWeightInterval 默认的自适应权重采样周期
+	DefaultAdaptiveWeightInterval = 10 * time.Second
+	// DefaultAdaptiveWeightLoadThreshold 默认负载超过60%后开始降低权重
+	DefaultAdaptiveWeightLoadThreshold = 0.6
+	// DefaultAdaptiveWeightMinWeight 默认的自适应权重下限
+	DefaultAdaptiveWeightMinWeight = 10
+	// DefaultAdaptiveWeightMetadataKey 默认的负载分数metadata键
+	DefaultAdaptiveWeightMetadataKey = "polaris.load"
 	// DefaultConfigFilterEnabled 默认配置过滤是否开启
 	DefaultConfigFilterEnabled bool = true
 )
@@ -177,8 +285,18 @@ const (
 	DefaultServiceRouterDstMeta string = "dstMetaRouter"
 	// DefaultServiceRouterCanary 金丝雀路由.
 	DefaultServiceRouterCanary string = "canaryRouter"
+	// DefaultServiceRouterCanaryPercent 金丝雀按比例分流路由.
+	DefaultServiceRouterCanaryPercent string = "canaryPercentRouter"
+	// DefaultServiceRouterVersion 基于版本约束的路由.
+	DefaultServiceRouterVersion string = "versionRouter"
+	// DefaultServiceRouterZoneAware 按可用区容量加权的路由.
+	DefaultServiceRouterZoneAware string = "zoneAwareRouter"
+	// DefaultServiceRouterBlacklist 基于隔离黑名单的路由.
+	DefaultServiceRouterBlacklist string = "blacklistRouter"
 	// DefaultServiceRouterZeroProtect 零实例保护
 	DefaultServiceRouterZeroProtect string = "zeroProtectRouter"
+	// DefaultServiceRouterLane 泳道路由.
+	DefaultServiceRouterLane string = "laneRouter"
 
 	// DefaultLoadBalancerWR 默认负载均衡器,权重随机.
 	DefaultLoadBalancerWR string = "weightedRandom"
@@ -190,6 +308,12 @@ const (
 	DefaultLoadBalancerL5CST string = "l5cst"
 	// DefaultLoadBalancerHash 负载均衡器,普通hash.
 	DefaultLoadBalancerHash string = "hash"
+	// DefaultLoadBalancerPeakEWMA 负载均衡器,基于时延的peak ewma.
+	DefaultLoadBalancerPeakEWMA string = "peakEwma"
+	// DefaultLoadBalancerP2C 负载均衡器,基于在途请求数的p2c.
+	DefaultLoadBalancerP2C string = "p2c"
+	// DefaultLoadBalancerStickySession 负载均衡器,会话保持.
+	DefaultLoadBalancerStickySession string = "stickySession"
 	// DefaultCircuitBreaker 默认错误率熔断器.
 	DefaultCircuitBreaker string = "composite"
 	// DefaultCircuitBreakerErrRate 默认错误率熔断器.
@@ -198,6 +322,8 @@ const (
 	DefaultCircuitBreakerErrCount string = "errorCount"
 	// DefaultCircuitBreakerErrCheck 默认错误探测熔断器.
 	DefaultCircuitBreakerErrCheck string = "errorCheck"
+	// DefaultCircuitBreakerOutlier 离群检测熔断器，基于连续5xx及时延分位值剔除实例.
+	DefaultCircuitBreakerOutlier string = "outlier"
 	// DefaultTCPHealthCheck 默认TCP探测器.
 	DefaultTCPHealthCheck string = "tcp"
 	// DefaultUDPHealthCheck 默认UDP探测器.
@@ -206,11 +332,17 @@ const (
 	// DefaultRejectRateLimiter 默认的reject限流器.
 	DefaultRejectRateLimiter = "reject"
 	// DefaultWarmUpRateLimiter 默认warmup限流器.
-	DefaultWarmUpRateLimiter = "warmUp"
+	DefaultWarmUpRateLimiter = "warmup"
 	// DefaultUniformRateLimiter 默认的匀速限流器.
 	DefaultUniformRateLimiter = "unirate"
 	// DefaultWarmUpWaitLimiter 默认限流插件，预热匀速.
 	DefaultWarmUpWaitLimiter = "warmup-wait"
+	// DefaultAdaptiveRateLimiter 自适应限流器.
+	DefaultAdaptiveRateLimiter = "adaptive"
+	// DefaultSlidingLogRateLimiter 滑动日志限流器.
+	DefaultSlidingLogRateLimiter = "slidinglog"
+	// DefaultConcurrencyRateLimiter 并发数限流器.
+	DefaultConcurrencyRateLimiter = "concurrency"
 	// SubscribeLocalChannel 默认订阅事件处理插件.
 	SubscribeLocalChannel = "subscribeLocalChannel"
 
@@ -252,6 +384,10 @@ const (
 	DefaultStatReportEnabled = true
 	// DefaultMetricsChain .
 	DefaultMetricsChain = "prometheus"
+	// DefaultEventReportEnabled .
+	DefaultEventReportEnabled = false
+	// DefaultEventReporterChain .
+	DefaultEventReporterChain = "stdout"
 )
 
 const (
@@ -435,9 +571,33 @@ func (g *GlobalConfigImpl) Verify() error {
 	if err = g.StatReporter.Verify(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
+	if err = g.EventReporter.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	if err = g.Location.Verify(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
+	if err = g.Regex.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = g.Admin.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = g.Diagnostics.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = g.Identity.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = g.MetadataEncryption.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = g.ListenerDispatch.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = g.ConfigReload.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	return errs
 }
 
@@ -447,7 +607,15 @@ func (g *GlobalConfigImpl) SetDefault() {
 	g.ServerConnector.SetDefault()
 	g.System.SetDefault()
 	g.StatReporter.SetDefault()
+	g.EventReporter.SetDefault()
 	g.Location.SetDefault()
+	g.Regex.SetDefault()
+	g.Admin.SetDefault()
+	g.Diagnostics.SetDefault()
+	g.Identity.SetDefault()
+	g.MetadataEncryption.SetDefault()
+	g.ListenerDispatch.SetDefault()
+	g.ConfigReload.SetDefault()
 }
 
 // Init 全局配置初始化.
@@ -459,10 +627,20 @@ func (g *GlobalConfigImpl) Init() {
 	g.ServerConnector.Init()
 	g.StatReporter = &StatReporterConfigImpl{}
 	g.StatReporter.Init()
+	g.EventReporter = &EventReporterConfigImpl{}
+	g.EventReporter.Init()
 	g.Location = &LocationConfigImpl{}
 	g.Location.Init()
 	g.Client = &ClientConfigImpl{}
 	g.Client.Init()
+	g.Regex = &RegexConfigImpl{}
+	g.Admin = &AdminConfigImpl{}
+	g.Diagnostics = &DiagnosticsConfigImpl{}
+	g.Identity = &IdentityProviderConfigImpl{}
+	g.Identity.Init()
+	g.MetadataEncryption = &MetadataEncryptionConfigImpl{}
+	g.ListenerDispatch = &ListenerDispatchConfigImpl{}
+	g.ConfigReload = &ConfigReloadConfigImpl{}
 }
 
 // Init 初始化ConsumerConfigImpl.
@@ -477,6 +655,9 @@ func (c *ConsumerConfigImpl) Init() {
 	c.Loadbalancer.Init()
 	c.HealthCheck = &HealthCheckConfigImpl{}
 	c.HealthCheck.Init()
+	c.FaultInjection = &FaultInjectionConfigImpl{}
+	c.Retry = &RetryConfigImpl{}
+	c.Hedging = &HedgingConfigImpl{}
 }
 
 // Verify 检验consumerConfig配置.
@@ -501,6 +682,15 @@ func (c *ConsumerConfigImpl) Verify() error {
 	if err = c.HealthCheck.Verify(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
+	if err = c.FaultInjection.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = c.Retry.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = c.Hedging.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	return errs
 }
 
@@ -511,6 +701,9 @@ func (c *ConsumerConfigImpl) SetDefault() {
 	c.ServiceRouter.SetDefault()
 	c.CircuitBreaker.SetDefault()
 	c.HealthCheck.SetDefault()
+	c.FaultInjection.SetDefault()
+	c.Retry.SetDefault()
+	c.Hedging.SetDefault()
 }
 
 // Init 初始化整体配置对象.
@@ -544,6 +737,9 @@ func (c *ConfigurationImpl) Verify() error {
 	if err = c.Config.Verify(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
+	if err = c.verifyConflicts(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	return errs
 }
 
@@ -555,8 +751,16 @@ func (c *ConfigurationImpl) SetDefault() {
 	c.Config.SetDefault()
 }
 
+// DefaultVariableResolvers 默认的路由变量解析链.
+var DefaultVariableResolvers = []string{
+	model.VariableResolverSystem,
+	model.VariableResolverEnv,
+}
+
 // Init systemConfig init.
 func (s *SystemConfigImpl) Init() {
+	s.VariableFile = &VariableFileConfigImpl{}
+	s.VariableFile.Init()
 	s.DiscoverCluster = &ServerClusterConfigImpl{}
 	s.HealthCheckCluster = &ServerClusterConfigImpl{}
 	s.MonitorCluster = &ServerClusterConfigImpl{
@@ -570,6 +774,10 @@ func (s *SystemConfigImpl) SetDefault() {
 	s.DiscoverCluster.SetDefault()
 	s.HealthCheckCluster.SetDefault()
 	s.MonitorCluster.SetDefault()
+	if len(s.VariableResolvers) == 0 {
+		s.VariableResolvers = append([]string{}, DefaultVariableResolvers...)
+	}
+	s.VariableFile.SetDefault()
 }
 
 // Verify 校验systemConfig配置.
@@ -596,6 +804,18 @@ func (s *SystemConfigImpl) Verify() error {
 		errs = multierror.Append(errs,
 			fmt.Errorf("fail to verify serverClusters.monitorCluster, error is %v", err))
 	}
+	for _, name := range s.VariableResolvers {
+		switch name {
+		case model.VariableResolverSystem, model.VariableResolverEnv, model.VariableResolverFlag:
+		case model.VariableResolverConfigCenter:
+			if err = s.VariableFile.Verify(); err != nil {
+				errs = multierror.Append(errs,
+					fmt.Errorf("fail to verify system.variableFile, error is %v", err))
+			}
+		default:
+			errs = multierror.Append(errs, fmt.Errorf("global.system.variableResolvers: unknown resolver %s", name))
+		}
+	}
 	return errs
 }
 
@@ -622,6 +842,8 @@ func (s *ServerClusterConfigImpl) Verify() error {
 type ClientConfigImpl struct {
 	ID     string            `yaml:"id" json:"id"`
 	Labels map[string]string `yaml:"labels" json:"labels"`
+	// Env 客户端所属环境，作为客户端标签上报，用于配置灰度发布等场景
+	Env string `yaml:"env" json:"env"`
 }
 
 // Init 初始化
@@ -636,6 +858,16 @@ func (c *ClientConfigImpl) GetId() string {
 	return c.ID
 }
 
+// GetEnv 获取客户端所属环境
+func (c *ClientConfigImpl) GetEnv() string {
+	return c.Env
+}
+
+// SetEnv 设置客户端所属环境
+func (c *ClientConfigImpl) SetEnv(env string) {
+	c.Env = env
+}
+
 func (c *ClientConfigImpl) SetLabels(m map[string]string) {
 	c.Labels = m
 }
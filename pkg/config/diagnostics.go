@@ -0,0 +1,115 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// DiagnosticsConfigImpl 调用诊断配置，对慢调用及失败调用进行采样并记录路由决策过程
+type DiagnosticsConfigImpl struct {
+	// Enable 是否启用调用诊断
+	Enable *bool `yaml:"enable" json:"enable"`
+	// SlowThreshold 调用耗时超过该值时视为慢调用
+	SlowThreshold *time.Duration `yaml:"slowThreshold" json:"slowThreshold"`
+	// MaxSamplesPerMinute 每分钟最多采样的调用数
+	MaxSamplesPerMinute int `yaml:"maxSamplesPerMinute" json:"maxSamplesPerMinute"`
+	// BufferSize 保留的最近采样记录数
+	BufferSize int `yaml:"bufferSize" json:"bufferSize"`
+}
+
+// IsEnable 是否启用调用诊断
+func (d *DiagnosticsConfigImpl) IsEnable() bool {
+	return *d.Enable
+}
+
+// SetEnable 设置是否启用调用诊断
+func (d *DiagnosticsConfigImpl) SetEnable(enable bool) {
+	d.Enable = &enable
+}
+
+// GetSlowThreshold 获取慢调用阈值
+func (d *DiagnosticsConfigImpl) GetSlowThreshold() time.Duration {
+	return *d.SlowThreshold
+}
+
+// SetSlowThreshold 设置慢调用阈值
+func (d *DiagnosticsConfigImpl) SetSlowThreshold(threshold time.Duration) {
+	d.SlowThreshold = &threshold
+}
+
+// GetMaxSamplesPerMinute 获取每分钟最大采样数
+func (d *DiagnosticsConfigImpl) GetMaxSamplesPerMinute() int {
+	return d.MaxSamplesPerMinute
+}
+
+// SetMaxSamplesPerMinute 设置每分钟最大采样数
+func (d *DiagnosticsConfigImpl) SetMaxSamplesPerMinute(count int) {
+	d.MaxSamplesPerMinute = count
+}
+
+// GetBufferSize 获取保留的采样记录数
+func (d *DiagnosticsConfigImpl) GetBufferSize() int {
+	return d.BufferSize
+}
+
+// SetBufferSize 设置保留的采样记录数
+func (d *DiagnosticsConfigImpl) SetBufferSize(size int) {
+	d.BufferSize = size
+}
+
+// Verify 检验调用诊断配置
+func (d *DiagnosticsConfigImpl) Verify() error {
+	if nil == d {
+		return errors.New("DiagnosticsConfig is nil")
+	}
+	var errs error
+	if d.SlowThreshold != nil && *d.SlowThreshold <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("global.diagnostics.slowThreshold must be greater than 0"))
+	}
+	if d.MaxSamplesPerMinute < 1 {
+		errs = multierror.Append(errs, fmt.Errorf("global.diagnostics.maxSamplesPerMinute must be greater than 0"))
+	}
+	if d.BufferSize < 1 {
+		errs = multierror.Append(errs, fmt.Errorf("global.diagnostics.bufferSize must be greater than 0"))
+	}
+	return errs
+}
+
+// SetDefault 设置调用诊断配置的默认值
+func (d *DiagnosticsConfigImpl) SetDefault() {
+	if nil == d.Enable {
+		enable := DefaultDiagnosticsEnabled
+		d.Enable = &enable
+	}
+	if nil == d.SlowThreshold {
+		d.SlowThreshold = model.ToDurationPtr(DefaultDiagnosticsSlowThreshold)
+	}
+	if d.MaxSamplesPerMinute == 0 {
+		d.MaxSamplesPerMinute = DefaultDiagnosticsMaxSamplesPerMinute
+	}
+	if d.BufferSize == 0 {
+		d.BufferSize = DefaultDiagnosticsBufferSize
+	}
+}
@@ -0,0 +1,89 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// EventReporterConfigImpl global.eventReporter.
+type EventReporterConfigImpl struct {
+	// 是否启用治理事件上报
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 事件上报插件链
+	Chain []string `yaml:"chain" json:"chain"`
+	// 插件相关配置
+	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
+}
+
+// IsEnable 是否启用事件上报.
+func (e *EventReporterConfigImpl) IsEnable() bool {
+	return *e.Enable
+}
+
+// SetEnable 设置是否启用事件上报.
+func (e *EventReporterConfigImpl) SetEnable(enable bool) {
+	e.Enable = &enable
+}
+
+// GetChain 插件链条.
+func (e *EventReporterConfigImpl) GetChain() []string {
+	return e.Chain
+}
+
+// SetChain 设置插件链条.
+func (e *EventReporterConfigImpl) SetChain(chain []string) {
+	e.Chain = chain
+}
+
+// GetPluginConfig 获取一个插件的配置.
+func (e *EventReporterConfigImpl) GetPluginConfig(name string) BaseConfig {
+	value, ok := e.Plugin[name]
+	if !ok {
+		return nil
+	}
+	return value.(BaseConfig)
+}
+
+// Verify 检测eventReporter配置.
+func (e *EventReporterConfigImpl) Verify() error {
+	return e.Plugin.Verify()
+}
+
+// SetDefault 设置eventReporter默认值.
+func (e *EventReporterConfigImpl) SetDefault() {
+	if nil == e.Enable {
+		enable := DefaultEventReportEnabled
+		e.Enable = &enable
+	}
+	if len(e.Chain) == 0 {
+		e.Chain = []string{DefaultEventReporterChain}
+	}
+	e.Plugin.SetDefault(common.TypeEventReporter)
+}
+
+// Init 配置初始化.
+func (e *EventReporterConfigImpl) Init() {
+	e.Plugin = PluginConfigs{}
+	e.Plugin.Init(common.TypeEventReporter)
+}
+
+// SetPluginConfig 输出插件具体配置.
+func (e *EventReporterConfigImpl) SetPluginConfig(plugName string, value BaseConfig) error {
+	return e.Plugin.SetPluginConfig(common.TypeEventReporter, plugName, value)
+}
@@ -0,0 +1,115 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// FaultInjectionConfigImpl 故障注入配置，规则以配置文件的形式通过北极星配置中心下发
+type FaultInjectionConfigImpl struct {
+	// Enable 是否启用故障注入
+	Enable *bool `yaml:"enable" json:"enable"`
+	// Namespace 规则所在配置文件的命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// FileGroup 规则所在配置文件的分组
+	FileGroup string `yaml:"fileGroup" json:"fileGroup"`
+	// FileName 规则所在配置文件名
+	FileName string `yaml:"fileName" json:"fileName"`
+}
+
+// IsEnable 是否启用故障注入
+func (f *FaultInjectionConfigImpl) IsEnable() bool {
+	return *f.Enable
+}
+
+// SetEnable 设置是否启用故障注入
+func (f *FaultInjectionConfigImpl) SetEnable(enable bool) {
+	f.Enable = &enable
+}
+
+// GetNamespace 获取规则所在配置文件的命名空间
+func (f *FaultInjectionConfigImpl) GetNamespace() string {
+	return f.Namespace
+}
+
+// SetNamespace 设置规则所在配置文件的命名空间
+func (f *FaultInjectionConfigImpl) SetNamespace(namespace string) {
+	f.Namespace = namespace
+}
+
+// GetFileGroup 获取规则所在配置文件的分组
+func (f *FaultInjectionConfigImpl) GetFileGroup() string {
+	return f.FileGroup
+}
+
+// SetFileGroup 设置规则所在配置文件的分组
+func (f *FaultInjectionConfigImpl) SetFileGroup(group string) {
+	f.FileGroup = group
+}
+
+// GetFileName 获取规则所在配置文件名
+func (f *FaultInjectionConfigImpl) GetFileName() string {
+	return f.FileName
+}
+
+// SetFileName 设置规则所在配置文件名
+func (f *FaultInjectionConfigImpl) SetFileName(name string) {
+	f.FileName = name
+}
+
+// Verify 检验故障注入配置
+func (f *FaultInjectionConfigImpl) Verify() error {
+	if nil == f {
+		return errors.New("FaultInjectionConfig is nil")
+	}
+	if !f.IsEnable() {
+		return nil
+	}
+	var errs error
+	if f.Namespace == "" {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.faultInjection.namespace can not be empty"))
+	}
+	if f.FileGroup == "" {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.faultInjection.fileGroup can not be empty"))
+	}
+	if f.FileName == "" {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.faultInjection.fileName can not be empty"))
+	}
+	return errs
+}
+
+// SetDefault 设置故障注入配置的默认值
+func (f *FaultInjectionConfigImpl) SetDefault() {
+	if nil == f.Enable {
+		enable := DefaultFaultInjectionEnabled
+		f.Enable = &enable
+	}
+	if f.Namespace == "" {
+		f.Namespace = DefaultFaultInjectionNamespace
+	}
+	if f.FileGroup == "" {
+		f.FileGroup = DefaultFaultInjectionFileGroup
+	}
+	if f.FileName == "" {
+		f.FileName = DefaultFaultInjectionFileName
+	}
+}
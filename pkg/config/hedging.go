@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// HedgingConfigImpl 对冲请求配置，对冲延迟根据服务调用时延的分位值计算
+type HedgingConfigImpl struct {
+	// Percentile 计算对冲延迟所用的时延分位值
+	Percentile float64 `yaml:"percentile" json:"percentile"`
+	// DefaultDelay 时延样本不足时使用的对冲延迟
+	DefaultDelay *time.Duration `yaml:"defaultDelay" json:"defaultDelay"`
+	// MinSamples 按分位值计算对冲延迟所需的最小样本数
+	MinSamples int `yaml:"minSamples" json:"minSamples"`
+	// SampleSize 每个服务保留的最近时延样本数
+	SampleSize int `yaml:"sampleSize" json:"sampleSize"`
+}
+
+// GetPercentile 获取时延分位值
+func (h *HedgingConfigImpl) GetPercentile() float64 {
+	return h.Percentile
+}
+
+// SetPercentile 设置时延分位值
+func (h *HedgingConfigImpl) SetPercentile(percentile float64) {
+	h.Percentile = percentile
+}
+
+// GetDefaultDelay 获取默认对冲延迟
+func (h *HedgingConfigImpl) GetDefaultDelay() time.Duration {
+	return *h.DefaultDelay
+}
+
+// SetDefaultDelay 设置默认对冲延迟
+func (h *HedgingConfigImpl) SetDefaultDelay(delay time.Duration) {
+	h.DefaultDelay = &delay
+}
+
+// GetMinSamples 获取最小样本数
+func (h *HedgingConfigImpl) GetMinSamples() int {
+	return h.MinSamples
+}
+
+// SetMinSamples 设置最小样本数
+func (h *HedgingConfigImpl) SetMinSamples(count int) {
+	h.MinSamples = count
+}
+
+// GetSampleSize 获取时延样本数
+func (h *HedgingConfigImpl) GetSampleSize() int {
+	return h.SampleSize
+}
+
+// SetSampleSize 设置时延样本数
+func (h *HedgingConfigImpl) SetSampleSize(size int) {
+	h.SampleSize = size
+}
+
+// Verify 检验对冲配置
+func (h *HedgingConfigImpl) Verify() error {
+	if nil == h {
+		return errors.New("HedgingConfig is nil")
+	}
+	var errs error
+	if h.Percentile <= 0 || h.Percentile > 100 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.hedging.percentile must be in (0, 100]"))
+	}
+	if h.DefaultDelay != nil && *h.DefaultDelay <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.hedging.defaultDelay must be greater than 0"))
+	}
+	if h.SampleSize < 1 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.hedging.sampleSize must be greater than 0"))
+	}
+	if h.MinSamples < 1 || h.MinSamples > h.SampleSize {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.hedging.minSamples must be in [1, sampleSize]"))
+	}
+	return errs
+}
+
+// SetDefault 设置对冲配置的默认值
+func (h *HedgingConfigImpl) SetDefault() {
+	if h.Percentile == 0 {
+		h.Percentile = DefaultHedgingPercentile
+	}
+	if nil == h.DefaultDelay {
+		h.DefaultDelay = model.ToDurationPtr(DefaultHedgingDelay)
+	}
+	if h.SampleSize == 0 {
+		h.SampleSize = DefaultHedgingSampleSize
+	}
+	if h.MinSamples == 0 {
+		h.MinSamples = DefaultHedgingMinSamples
+	}
+}
@@ -0,0 +1,119 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// IdentityProviderConfigImpl global.identityProvider.
+type IdentityProviderConfigImpl struct {
+	// 是否启用工作负载身份
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 身份提供插件名
+	Type string `yaml:"type" json:"type"`
+	// 在路由及限流请求中携带调用方身份所用的标签名
+	LabelKey string `yaml:"labelKey" json:"labelKey"`
+	// 插件相关配置
+	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
+}
+
+// IsEnable 是否启用工作负载身份.
+func (i *IdentityProviderConfigImpl) IsEnable() bool {
+	return *i.Enable
+}
+
+// SetEnable 设置是否启用工作负载身份.
+func (i *IdentityProviderConfigImpl) SetEnable(enable bool) {
+	i.Enable = &enable
+}
+
+// GetType 身份提供插件名.
+func (i *IdentityProviderConfigImpl) GetType() string {
+	return i.Type
+}
+
+// SetType 设置身份提供插件名.
+func (i *IdentityProviderConfigImpl) SetType(typ string) {
+	i.Type = typ
+}
+
+// GetLabelKey 调用方身份标签名.
+func (i *IdentityProviderConfigImpl) GetLabelKey() string {
+	return i.LabelKey
+}
+
+// SetLabelKey 设置调用方身份标签名.
+func (i *IdentityProviderConfigImpl) SetLabelKey(key string) {
+	i.LabelKey = key
+}
+
+// GetPluginConfig 获取一个插件的配置.
+func (i *IdentityProviderConfigImpl) GetPluginConfig(name string) BaseConfig {
+	value, ok := i.Plugin[name]
+	if !ok {
+		return nil
+	}
+	return value.(BaseConfig)
+}
+
+// SetPluginConfig 输出插件具体配置.
+func (i *IdentityProviderConfigImpl) SetPluginConfig(plugName string, value BaseConfig) error {
+	return i.Plugin.SetPluginConfig(common.TypeIdentityProvider, plugName, value)
+}
+
+// Verify 检测identityProvider配置.
+func (i *IdentityProviderConfigImpl) Verify() error {
+	if nil == i {
+		return errors.New("IdentityProviderConfig is nil")
+	}
+	var errs error
+	if i.IsEnable() && len(i.Type) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("global.identityProvider.type is empty"))
+	}
+	if err := i.Plugin.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return errs
+}
+
+// SetDefault 设置identityProvider默认值.
+func (i *IdentityProviderConfigImpl) SetDefault() {
+	if nil == i.Enable {
+		enable := DefaultIdentityProviderEnabled
+		i.Enable = &enable
+	}
+	if len(i.Type) == 0 {
+		i.Type = DefaultIdentityProvider
+	}
+	if len(i.LabelKey) == 0 {
+		i.LabelKey = DefaultIdentityLabelKey
+	}
+	i.Plugin.SetDefault(common.TypeIdentityProvider)
+}
+
+// Init 配置初始化.
+func (i *IdentityProviderConfigImpl) Init() {
+	i.Plugin = PluginConfigs{}
+	i.Plugin.Init(common.TypeIdentityProvider)
+}
@@ -18,14 +18,10 @@
 package config
 
 import (
-	"bytes"
 	"io/ioutil"
 	"log"
-	"os"
 	"time"
 
-	"gopkg.in/yaml.v2"
-
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -59,12 +55,20 @@ func (c *ConfigurationImpl) GetConfigFile() ConfigFileConfig {
 
 // GlobalConfigImpl 全局配置.
 type GlobalConfigImpl struct {
-	System          *SystemConfigImpl          `yaml:"system" json:"system"`
-	API             *APIConfigImpl             `yaml:"api" json:"api"`
-	ServerConnector *ServerConnectorConfigImpl `yaml:"serverConnector" json:"serverConnector"`
-	StatReporter    *StatReporterConfigImpl    `yaml:"statReporter" json:"statReporter"`
-	Location        *LocationConfigImpl        `yaml:"location" json:"location"`
-	Client          *ClientConfigImpl          `yaml:"client" json:"client"`
+	System             *SystemConfigImpl             `yaml:"system" json:"system"`
+	API                *APIConfigImpl                `yaml:"api" json:"api"`
+	ServerConnector    *ServerConnectorConfigImpl    `yaml:"serverConnector" json:"serverConnector"`
+	StatReporter       *StatReporterConfigImpl       `yaml:"statReporter" json:"statReporter"`
+	EventReporter      *EventReporterConfigImpl      `yaml:"eventReporter" json:"eventReporter"`
+	Location           *LocationConfigImpl           `yaml:"location" json:"location"`
+	Client             *ClientConfigImpl             `yaml:"client" json:"client"`
+	Regex              *RegexConfigImpl              `yaml:"regex" json:"regex"`
+	Admin              *AdminConfigImpl              `yaml:"admin" json:"admin"`
+	Diagnostics        *DiagnosticsConfigImpl        `yaml:"diagnostics" json:"diagnostics"`
+	Identity           *IdentityProviderConfigImpl   `yaml:"identityProvider" json:"identityProvider"`
+	MetadataEncryption *MetadataEncryptionConfigImpl `yaml:"metadataEncryption" json:"metadataEncryption"`
+	ListenerDispatch   *ListenerDispatchConfigImpl   `yaml:"listenerDispatch" json:"listenerDispatch"`
+	ConfigReload       *ConfigReloadConfigImpl       `yaml:"configReload" json:"configReload"`
 }
 
 // GetSystem 获取系统配置.
@@ -87,6 +91,11 @@ func (g *GlobalConfigImpl) GetStatReporter() StatReporterConfig {
 	return g.StatReporter
 }
 
+// GetEventReporter global.eventReporter前缀开头的所有配置项.
+func (g *GlobalConfigImpl) GetEventReporter() EventReporterConfig {
+	return g.EventReporter
+}
+
 // GetLocation cl5.global.location前缀开头的所有配置项.
 func (g *GlobalConfigImpl) GetLocation() LocationConfig {
 	return g.Location
@@ -97,6 +106,41 @@ func (g *GlobalConfigImpl) GetClient() ClientConfig {
 	return g.Client
 }
 
+// GetRegex global.regex前缀开头的所有配置项.
+func (g *GlobalConfigImpl) GetRegex() RegexConfig {
+	return g.Regex
+}
+
+// GetAdmin global.admin前缀开头的所有配置项.
+func (g *GlobalConfigImpl) GetAdmin() AdminConfig {
+	return g.Admin
+}
+
+// GetDiagnostics global.diagnostics前缀开头的所有配置项.
+func (g *GlobalConfigImpl) GetDiagnostics() DiagnosticsConfig {
+	return g.Diagnostics
+}
+
+// GetIdentityProvider global.identityProvider前缀开头的所有配置项.
+func (g *GlobalConfigImpl) GetIdentityProvider() IdentityProviderConfig {
+	return g.Identity
+}
+
+// GetMetadataEncryption global.metadataEncryption前缀开头的所有配置项.
+func (g *GlobalConfigImpl) GetMetadataEncryption() MetadataEncryptionConfig {
+	return g.MetadataEncryption
+}
+
+// GetListenerDispatch global.listenerDispatch前缀开头的所有配置项.
+func (g *GlobalConfigImpl) GetListenerDispatch() ListenerDispatchConfig {
+	return g.ListenerDispatch
+}
+
+// GetConfigReload global.configReload前缀开头的所有配置项.
+func (g *GlobalConfigImpl) GetConfigReload() ConfigReloadConfig {
+	return g.ConfigReload
+}
+
 // ConsumerConfigImpl 消费者配置.
 type ConsumerConfigImpl struct {
 	LocalCache       *LocalCacheConfigImpl     `yaml:"localCache" json:"localCache"`
@@ -104,6 +148,9 @@ type ConsumerConfigImpl struct {
 	Loadbalancer     *LoadBalancerConfigImpl   `yaml:"loadbalancer" json:"loadbalancer"`
 	CircuitBreaker   *CircuitBreakerConfigImpl `yaml:"circuitBreaker" json:"circuitBreaker"`
 	HealthCheck      *HealthCheckConfigImpl    `yaml:"healthCheck" json:"healthCheck"`
+	FaultInjection   *FaultInjectionConfigImpl `yaml:"faultInjection" json:"faultInjection"`
+	Retry            *RetryConfigImpl          `yaml:"retry" json:"retry"`
+	Hedging          *HedgingConfigImpl        `yaml:"hedging" json:"hedging"`
 	ServicesSpecific []*ServiceSpecific        `yaml:"servicesSpecific" json:"servicesSpecific"`
 }
 
@@ -112,6 +159,21 @@ func (c *ConsumerConfigImpl) GetLocalCache() LocalCacheConfig {
 	return c.LocalCache
 }
 
+// GetFaultInjection consumer.faultInjection前缀开头的所有配置.
+func (c *ConsumerConfigImpl) GetFaultInjection() FaultInjectionConfig {
+	return c.FaultInjection
+}
+
+// GetRetry consumer.retry前缀开头的所有配置.
+func (c *ConsumerConfigImpl) GetRetry() RetryConfig {
+	return c.Retry
+}
+
+// GetHedging consumer.hedging前缀开头的所有配置.
+func (c *ConsumerConfigImpl) GetHedging() HedgingConfig {
+	return c.Hedging
+}
+
 // GetServiceRouter consumer.serviceRouter前缀开头的所有配置.
 func (c *ConsumerConfigImpl) GetServiceRouter() ServiceRouterConfig {
 	return c.ServiceRouter
@@ -154,6 +216,12 @@ type SystemConfigImpl struct {
 	MonitorCluster *ServerClusterConfigImpl `yaml:"monitorCluster" json:"monitorCluster"`
 	// 传入的路由规则variables
 	Variables map[string]string `yaml:"variables" json:"variables"`
+	// 路由规则variable的解析链，按顺序取第一个存在的值
+	VariableResolvers []string `yaml:"variableResolvers" json:"variableResolvers"`
+	// 配置中心解析器读取的变量文件
+	VariableFile *VariableFileConfigImpl `yaml:"variableFile" json:"variableFile"`
+	// variables变更的监听者
+	variableListeners []func(key string)
 }
 
 // GetMode SDK运行模式，agent还是noagent.
@@ -196,6 +264,7 @@ func (s *SystemConfigImpl) SetVariable(key, value string) {
 		s.Variables = make(map[string]string)
 	}
 	s.Variables[key] = value
+	s.onVariableChange(key)
 }
 
 // UnsetVariable 取消一个路由variable.
@@ -203,6 +272,33 @@ func (s *SystemConfigImpl) UnsetVariable(key string) {
 	if s.Variables != nil {
 		delete(s.Variables, key)
 	}
+	s.onVariableChange(key)
+}
+
+// AddVariableListener 添加variables变更的监听.
+func (s *SystemConfigImpl) AddVariableListener(listener func(key string)) {
+	s.variableListeners = append(s.variableListeners, listener)
+}
+
+func (s *SystemConfigImpl) onVariableChange(key string) {
+	for _, listener := range s.variableListeners {
+		listener(key)
+	}
+}
+
+// GetVariableResolvers 路由规则variable的解析链.
+func (s *SystemConfigImpl) GetVariableResolvers() []string {
+	return s.VariableResolvers
+}
+
+// SetVariableResolvers 设置路由规则variable的解析链.
+func (s *SystemConfigImpl) SetVariableResolvers(resolvers []string) {
+	s.VariableResolvers = resolvers
+}
+
+// GetVariableFile 配置中心解析器读取的变量文件.
+func (s *SystemConfigImpl) GetVariableFile() VariableFileConfig {
+	return s.VariableFile
 }
 
 // ServerClusterConfigImpl 单个服务集群配置.
@@ -334,6 +430,9 @@ func NewDefaultConfiguration(addresses []string) *ConfigurationImpl {
 	cfg := &ConfigurationImpl{}
 	cfg.Init()
 	cfg.SetDefault()
+	if err := ApplyEnvOverlay(cfg); err != nil {
+		log.Printf("fail to apply env overlay to default config, err is %v", err)
+	}
 	if len(addresses) > 0 {
 		cfg.GetGlobal().GetServerConnector().(*ServerConnectorConfigImpl).Addresses = addresses
 	}
@@ -382,23 +481,7 @@ func LoadConfigurationByDefaultFile() (*ConfigurationImpl, error) {
 	return LoadConfigurationByFile(DefaultConfigFile)
 }
 
-// LoadConfiguration 加载配置项.
+// LoadConfiguration 加载配置项，配置中的全部问题会被汇总后一并返回.
 func LoadConfiguration(buf []byte) (*ConfigurationImpl, error) {
-	var err error
-	cfg := &ConfigurationImpl{}
-	cfg.Init()
-	// to support environment variables
-	content := os.ExpandEnv(string(buf))
-	decoder := yaml.NewDecoder(bytes.NewBufferString(content))
-	if err = decoder.Decode(cfg); err != nil {
-		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
-			"fail to decode config string")
-	}
-	cfg.SetDefault()
-	if err = cfg.Verify(); err != nil {
-		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
-			"fail to verify config string")
-	}
-
-	return cfg, nil
+	return loadConfiguration(buf, false)
 }
@@ -0,0 +1,98 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/polarismesh/polaris-go/pkg/dispatcher"
+)
+
+// ListenerDispatchConfigImpl 监听器回调分发配置，配置变更及实例变更的回调通过有界协程池执行
+type ListenerDispatchConfigImpl struct {
+	// Workers 分发协程数
+	Workers int `yaml:"workers" json:"workers"`
+	// QueueSize 每个分发协程的队列长度
+	QueueSize int `yaml:"queueSize" json:"queueSize"`
+	// OverflowPolicy 队列满时的处理策略，可选 block、dropOldest、spawn
+	OverflowPolicy string `yaml:"overflowPolicy" json:"overflowPolicy"`
+}
+
+// GetWorkers 获取分发协程数
+func (l *ListenerDispatchConfigImpl) GetWorkers() int {
+	return l.Workers
+}
+
+// SetWorkers 设置分发协程数
+func (l *ListenerDispatchConfigImpl) SetWorkers(workers int) {
+	l.Workers = workers
+}
+
+// GetQueueSize 获取每个分发协程的队列长度
+func (l *ListenerDispatchConfigImpl) GetQueueSize() int {
+	return l.QueueSize
+}
+
+// SetQueueSize 设置每个分发协程的队列长度
+func (l *ListenerDispatchConfigImpl) SetQueueSize(size int) {
+	l.QueueSize = size
+}
+
+// GetOverflowPolicy 获取队列满时的处理策略
+func (l *ListenerDispatchConfigImpl) GetOverflowPolicy() string {
+	return l.OverflowPolicy
+}
+
+// SetOverflowPolicy 设置队列满时的处理策略
+func (l *ListenerDispatchConfigImpl) SetOverflowPolicy(policy string) {
+	l.OverflowPolicy = policy
+}
+
+// Verify 检验监听器回调分发配置
+func (l *ListenerDispatchConfigImpl) Verify() error {
+	if nil == l {
+		return errors.New("ListenerDispatchConfig is nil")
+	}
+	if l.Workers <= 0 {
+		return fmt.Errorf("global.listenerDispatch.workers must be greater than 0")
+	}
+	if l.QueueSize <= 0 {
+		return fmt.Errorf("global.listenerDispatch.queueSize must be greater than 0")
+	}
+	switch l.OverflowPolicy {
+	case dispatcher.OverflowBlock, dispatcher.OverflowDropOldest, dispatcher.OverflowSpawn:
+	default:
+		return fmt.Errorf("global.listenerDispatch.overflowPolicy %s is invalid, must be one of %s, %s, %s",
+			l.OverflowPolicy, dispatcher.OverflowBlock, dispatcher.OverflowDropOldest, dispatcher.OverflowSpawn)
+	}
+	return nil
+}
+
+// SetDefault 设置监听器回调分发配置的默认值
+func (l *ListenerDispatchConfigImpl) SetDefault() {
+	if l.Workers == 0 {
+		l.Workers = DefaultListenerDispatchWorkers
+	}
+	if l.QueueSize == 0 {
+		l.QueueSize = DefaultListenerDispatchQueueSize
+	}
+	if len(l.OverflowPolicy) == 0 {
+		l.OverflowPolicy = DefaultListenerOverflowPolicy
+	}
+}
@@ -18,6 +18,9 @@
 package config
 
 import (
+	"errors"
+	"time"
+
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 )
 
@@ -27,6 +30,8 @@ type LoadBalancerConfigImpl struct {
 	Type string `yaml:"type" json:"type"`
 	// 插件相关配置
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
+	// 新实例预热配置
+	Warmup *WarmupConfigImpl `yaml:"warmup" json:"warmup"`
 }
 
 // GetType 负载均衡类型.
@@ -39,6 +44,11 @@ func (l *LoadBalancerConfigImpl) SetType(typ string) {
 	l.Type = typ
 }
 
+// GetWarmup consumer.loadbalancer.warmup.
+func (l *LoadBalancerConfigImpl) GetWarmup() WarmupConfig {
+	return l.Warmup
+}
+
 // GetPluginConfig consumer.loadbalancer.plugin.
 func (l *LoadBalancerConfigImpl) GetPluginConfig(pluginName string) BaseConfig {
 	cfgValue, ok := l.Plugin[pluginName]
@@ -55,6 +65,9 @@ func (l *LoadBalancerConfigImpl) SetPluginConfig(pluginName string, value BaseCo
 
 // Verify 检验LocalCacheConfig配置.
 func (l *LoadBalancerConfigImpl) Verify() error {
+	if err := l.Warmup.Verify(); err != nil {
+		return err
+	}
 	return l.Plugin.Verify()
 }
 
@@ -63,6 +76,10 @@ func (l *LoadBalancerConfigImpl) SetDefault() {
 	if len(l.Type) == 0 {
 		l.Type = DefaultLoadBalancerWR
 	}
+	if nil == l.Warmup {
+		l.Warmup = &WarmupConfigImpl{}
+	}
+	l.Warmup.SetDefault()
 	l.Plugin.SetDefault(common.TypeLoadBalancer)
 }
 
@@ -70,4 +87,54 @@ func (l *LoadBalancerConfigImpl) SetDefault() {
 func (l *LoadBalancerConfigImpl) Init() {
 	l.Plugin = PluginConfigs{}
 	l.Plugin.Init(common.TypeLoadBalancer)
+	l.Warmup = &WarmupConfigImpl{}
+}
+
+// WarmupConfigImpl 新实例预热配置，预热窗口内实例的有效权重随注册时长线性增长.
+type WarmupConfigImpl struct {
+	// 是否启用预热
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 预热窗口
+	Window time.Duration `yaml:"window" json:"window"`
+}
+
+// IsEnable 是否启用预热.
+func (w *WarmupConfigImpl) IsEnable() bool {
+	return *w.Enable
+}
+
+// SetEnable 设置是否启用预热.
+func (w *WarmupConfigImpl) SetEnable(enable bool) {
+	w.Enable = &enable
+}
+
+// GetWindow 预热窗口.
+func (w *WarmupConfigImpl) GetWindow() time.Duration {
+	return w.Window
+}
+
+// SetWindow 设置预热窗口.
+func (w *WarmupConfigImpl) SetWindow(window time.Duration) {
+	w.Window = window
+}
+
+// Verify 检验预热配置.
+func (w *WarmupConfigImpl) Verify() error {
+	if nil == w {
+		return errors.New("WarmupConfig is nil")
+	}
+	if w.Window <= 0 {
+		return errors.New("consumer.loadbalancer.warmup.window must be greater than 0")
+	}
+	return nil
+}
+
+// SetDefault 设置预热配置的默认值.
+func (w *WarmupConfigImpl) SetDefault() {
+	if nil == w.Enable {
+		w.SetEnable(DefaultWarmupEnabled)
+	}
+	if w.Window == 0 {
+		w.Window = DefaultWarmupWindow
+	}
 }
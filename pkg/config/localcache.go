@@ -56,6 +56,15 @@ type LocalCacheConfigImpl struct {
 	StartUseFileCache *bool `yaml:"startUseFileCache" json:"startUseFileCache"`
 	// PushEmptyProtection 推空保护开关
 	PushEmptyProtection *bool `yaml:"pushEmptyProtection" json:"pushEmptyProtection"`
+	// consumer.localCache.persistFormat
+	// 缓存文件格式，json或binary
+	PersistFormat string `yaml:"persistFormat" json:"persistFormat"`
+	// consumer.localCache.maxServiceCount
+	// 本地缓存的最大服务数，超出后按最近访问时间淘汰，0表示不限制
+	MaxServiceCount int `yaml:"maxServiceCount" json:"maxServiceCount"`
+	// consumer.localCache.cipher
+	// 缓存文件加解密器名称，为空时不加密
+	Cipher string `yaml:"cipher" json:"cipher"`
 	// 插件相关配置
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
 }
@@ -182,6 +191,39 @@ func (l *LocalCacheConfigImpl) GetPushEmptyProtection() bool {
 	return *l.PushEmptyProtection
 }
 
+// GetPersistFormat consumer.localCache.persistFormat
+// 缓存文件格式.
+func (l *LocalCacheConfigImpl) GetPersistFormat() string {
+	return l.PersistFormat
+}
+
+// SetPersistFormat 设置缓存文件格式.
+func (l *LocalCacheConfigImpl) SetPersistFormat(format string) {
+	l.PersistFormat = format
+}
+
+// GetMaxServiceCount consumer.localCache.maxServiceCount
+// 本地缓存的最大服务数.
+func (l *LocalCacheConfigImpl) GetMaxServiceCount() int {
+	return l.MaxServiceCount
+}
+
+// SetMaxServiceCount 设置本地缓存的最大服务数.
+func (l *LocalCacheConfigImpl) SetMaxServiceCount(count int) {
+	l.MaxServiceCount = count
+}
+
+// GetCipher consumer.localCache.cipher
+// 缓存文件加解密器名称.
+func (l *LocalCacheConfigImpl) GetCipher() string {
+	return l.Cipher
+}
+
+// SetCipher 设置缓存文件加解密器名称.
+func (l *LocalCacheConfigImpl) SetCipher(name string) {
+	l.Cipher = name
+}
+
 // GetPluginConfig consumer.localCache.plugin.
 func (l *LocalCacheConfigImpl) GetPluginConfig(pluginName string) BaseConfig {
 	cfgValue, ok := l.Plugin[pluginName]
@@ -206,6 +248,14 @@ func (l *LocalCacheConfigImpl) Verify() error {
 		errs = multierror.Append(errs, fmt.Errorf("consumer.localCache.serviceExpireTime %v"+
 			" is less than the minimal allowed duration %v", l.ServiceExpireTime, DefaultMinServiceExpireTime))
 	}
+	if l.PersistFormat != PersistFormatJSON && l.PersistFormat != PersistFormatBinary {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.localCache.persistFormat %s is invalid,"+
+			" must be %s or %s", l.PersistFormat, PersistFormatJSON, PersistFormatBinary))
+	}
+	if l.MaxServiceCount < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.localCache.maxServiceCount %d"+
+			" must not be negative", l.MaxServiceCount))
+	}
 	plugErr := l.Plugin.Verify()
 	if nil != plugErr {
 		errs = multierror.Append(errs, plugErr)
@@ -248,6 +298,9 @@ func (l *LocalCacheConfigImpl) SetDefault() {
 	if nil == l.PushEmptyProtection {
 		l.PushEmptyProtection = &DefaultPushEmptyProtection
 	}
+	if len(l.PersistFormat) == 0 {
+		l.PersistFormat = PersistFormatJSON
+	}
 	l.Plugin.SetDefault(common.TypeLocalRegistry)
 }
 
@@ -0,0 +1,85 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MetadataEncryptionConfigImpl 实例元数据加密配置，注册时加密敏感元数据，服务发现时透明解密
+type MetadataEncryptionConfigImpl struct {
+	// Enable 是否启用元数据加密
+	Enable *bool `yaml:"enable" json:"enable"`
+	// Cipher 加解密器名称，可通过 cachecipher.RegisterCacheCipher 注册对接KMS的加解密器
+	Cipher string `yaml:"cipher" json:"cipher"`
+	// SensitiveKeys 注册时需要加密的元数据key
+	SensitiveKeys []string `yaml:"sensitiveKeys" json:"sensitiveKeys"`
+}
+
+// IsEnable 是否启用元数据加密
+func (m *MetadataEncryptionConfigImpl) IsEnable() bool {
+	return *m.Enable
+}
+
+// SetEnable 设置是否启用元数据加密
+func (m *MetadataEncryptionConfigImpl) SetEnable(enable bool) {
+	m.Enable = &enable
+}
+
+// GetCipher 获取加解密器名称
+func (m *MetadataEncryptionConfigImpl) GetCipher() string {
+	return m.Cipher
+}
+
+// SetCipher 设置加解密器名称
+func (m *MetadataEncryptionConfigImpl) SetCipher(cipher string) {
+	m.Cipher = cipher
+}
+
+// GetSensitiveKeys 获取需要加密的元数据key
+func (m *MetadataEncryptionConfigImpl) GetSensitiveKeys() []string {
+	return m.SensitiveKeys
+}
+
+// SetSensitiveKeys 设置需要加密的元数据key
+func (m *MetadataEncryptionConfigImpl) SetSensitiveKeys(keys []string) {
+	m.SensitiveKeys = keys
+}
+
+// Verify 检验元数据加密配置
+func (m *MetadataEncryptionConfigImpl) Verify() error {
+	if nil == m {
+		return errors.New("MetadataEncryptionConfig is nil")
+	}
+	if m.IsEnable() && len(m.Cipher) == 0 {
+		return fmt.Errorf("global.metadataEncryption.cipher is empty")
+	}
+	return nil
+}
+
+// SetDefault 设置元数据加密配置的默认值
+func (m *MetadataEncryptionConfigImpl) SetDefault() {
+	if nil == m.Enable {
+		enable := DefaultMetadataEncryptionEnabled
+		m.Enable = &enable
+	}
+	if len(m.Cipher) == 0 {
+		m.Cipher = DefaultMetadataCipher
+	}
+}
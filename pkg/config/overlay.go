@@ -0,0 +1,226 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/go-multierror"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// EnvOverlayPrefix 覆盖配置项的环境变量前缀
+	EnvOverlayPrefix = "POLARIS_"
+	// FlagOverlayPrefix 覆盖配置项的命令行参数前缀
+	FlagOverlayPrefix = "polaris."
+)
+
+// OverlayField 可被环境变量或命令行参数覆盖的配置项
+type OverlayField struct {
+	// Path 配置项在配置文件中的路径，如 global.serverConnector.addresses
+	Path string
+	// Env 对应的环境变量名，如 POLARIS_GLOBAL_SERVER_CONNECTOR_ADDRESSES
+	Env string
+	// value 配置项的值
+	value reflect.Value
+}
+
+// set 按配置项的类型解析文本并赋值：字符串直接赋值，字符串列表支持逗号分隔，其余类型按YAML解析
+func (f *OverlayField) set(text string) error {
+	target := f.value
+	if target.Kind() == reflect.Ptr && target.Type().Elem().Kind() == reflect.String {
+		value := reflect.New(target.Type().Elem())
+		value.Elem().SetString(text)
+		target.Set(value)
+		return nil
+	}
+	switch {
+	case target.Kind() == reflect.String:
+		target.SetString(text)
+		return nil
+	case target.Kind() == reflect.Slice && target.Type().Elem().Kind() == reflect.String &&
+		!strings.HasPrefix(strings.TrimSpace(text), "["):
+		values := reflect.MakeSlice(target.Type(), 0, 0)
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); len(item) > 0 {
+				values = reflect.Append(values, reflect.ValueOf(item).Convert(target.Type().Elem()))
+			}
+		}
+		target.Set(values)
+		return nil
+	}
+	value := reflect.New(target.Type())
+	if err := yaml.Unmarshal([]byte(text), value.Interface()); err != nil {
+		return fmt.Errorf("invalid value %q of %s: %v", text, f.Path, err)
+	}
+	target.Set(value.Elem())
+	return nil
+}
+
+// GetOverlayFields 根据配置结构体的yaml标签生成全部可覆盖的配置项，顺序固定
+func GetOverlayFields(cfg *ConfigurationImpl) []*OverlayField {
+	var fields []*OverlayField
+	collectOverlayFields(reflect.ValueOf(cfg).Elem(), nil, &fields)
+	return fields
+}
+
+// ApplyEnvOverlay 使用 POLARIS_ 开头的环境变量覆盖配置项，优先级为：默认值 < 配置文件 < 环境变量 < 代码设置
+func ApplyEnvOverlay(cfg *ConfigurationImpl) error {
+	var errs error
+	for _, field := range GetOverlayFields(cfg) {
+		text, ok := os.LookupEnv(field.Env)
+		if !ok {
+			continue
+		}
+		if err := field.set(text); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("env %s: %v", field.Env, err))
+		}
+	}
+	return errs
+}
+
+// ApplyFlagOverlay 使用 -polaris.<path>=<value> 或 -polaris.<path> <value> 形式的命令行参数覆盖配置项，
+// 优先级高于环境变量，其余参数将被忽略，如 -polaris.global.serverConnector.addresses=127.0.0.1:8091；
+// 后一种形式中以 - 开头的下一个参数视为另一个参数而非值，值以 - 开头时需使用 = 的形式
+func ApplyFlagOverlay(cfg *ConfigurationImpl, args []string) error {
+	fields := make(map[string]*OverlayField)
+	for _, field := range GetOverlayFields(cfg) {
+		fields[field.Path] = field
+	}
+	var errs error
+	for i := 0; i < len(args); i++ {
+		raw := args[i]
+		arg := strings.TrimLeft(raw, "-")
+		if len(arg) == len(raw) || !strings.HasPrefix(arg, FlagOverlayPrefix) {
+			continue
+		}
+		path, text := strings.TrimPrefix(arg, FlagOverlayPrefix), ""
+		hasValue := false
+		if idx := strings.Index(path, "="); idx >= 0 {
+			path, text, hasValue = path[:idx], path[idx+1:], true
+		} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			i++
+			text, hasValue = args[i], true
+		}
+		field, ok := fields[path]
+		if !ok {
+			errs = multierror.Append(errs, fmt.Errorf("flag %s: unknown config path %s", raw, path))
+			continue
+		}
+		if !hasValue {
+			errs = multierror.Append(errs, fmt.Errorf("flag %s: missing value", raw))
+			continue
+		}
+		if err := field.set(text); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("flag %s: %v", FlagOverlayPrefix+path, err))
+		}
+	}
+	return errs
+}
+
+func collectOverlayFields(value reflect.Value, path []string, fields *[]*OverlayField) {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		structField := valueType.Field(i)
+		if len(structField.PkgPath) > 0 {
+			continue
+		}
+		tag := strings.Split(structField.Tag.Get("yaml"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		fieldValue := value.Field(i)
+		if len(tag) > 1 && tag[1] == "inline" {
+			if fieldValue.Kind() == reflect.Struct {
+				collectOverlayFields(fieldValue, path, fields)
+			}
+			continue
+		}
+		if len(name) == 0 {
+			name = strings.ToLower(structField.Name)
+		}
+		collectOverlayValue(fieldValue, appendPath(path, name), fields)
+	}
+}
+
+func collectOverlayValue(value reflect.Value, path []string, fields *[]*OverlayField) {
+	switch {
+	case value.Kind() == reflect.Struct:
+		collectOverlayFields(value, path, fields)
+	case value.Kind() == reflect.Ptr && value.Type().Elem().Kind() == reflect.Struct:
+		if value.IsNil() {
+			return
+		}
+		collectOverlayFields(value.Elem(), path, fields)
+	case value.Type() == reflect.TypeOf(PluginConfigs{}):
+		// 插件配置按插件名展开，只处理已转换为配置对象的插件
+		names := make([]string, 0, value.Len())
+		for _, key := range value.MapKeys() {
+			names = append(names, key.String())
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			plugValue := value.MapIndex(reflect.ValueOf(name)).Elem()
+			if plugValue.Kind() == reflect.Ptr && !plugValue.IsNil() && plugValue.Elem().Kind() == reflect.Struct {
+				collectOverlayFields(plugValue.Elem(), appendPath(path, name), fields)
+			}
+		}
+	case value.Kind() == reflect.Interface || value.Kind() == reflect.Func || value.Kind() == reflect.Chan:
+		return
+	default:
+		*fields = append(*fields, &OverlayField{
+			Path:  strings.Join(path, "."),
+			Env:   toEnvName(path),
+			value: value,
+		})
+	}
+}
+
+func appendPath(path []string, name string) []string {
+	newPath := make([]string, 0, len(path)+1)
+	newPath = append(newPath, path...)
+	return append(newPath, name)
+}
+
+// toEnvName 将配置路径转换为环境变量名，驼峰命名按单词拆分，如 serverConnector 转换为 SERVER_CONNECTOR
+func toEnvName(path []string) string {
+	words := make([]string, 0, len(path))
+	for _, name := range path {
+		var builder strings.Builder
+		runes := []rune(name)
+		for i, r := range runes {
+			if i > 0 && unicode.IsUpper(r) && (!unicode.IsUpper(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				builder.WriteRune('_')
+			}
+			if r == '-' || r == '.' {
+				r = '_'
+			}
+			builder.WriteRune(unicode.ToUpper(r))
+		}
+		words = append(words, builder.String())
+	}
+	return EnvOverlayPrefix + strings.Join(words, "_")
+}
@@ -0,0 +1,216 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToEnvName(t *testing.T) {
+	tests := []struct {
+		name string
+		path []string
+		want string
+	}{
+		{
+			name: "驼峰命名按单词拆分",
+			path: []string{"global", "serverConnector", "addresses"},
+			want: "POLARIS_GLOBAL_SERVER_CONNECTOR_ADDRESSES",
+		},
+		{
+			name: "结尾的连续大写视为一个单词",
+			path: []string{"global", "api", "bindIP"},
+			want: "POLARIS_GLOBAL_API_BIND_IP",
+		},
+		{
+			name: "开头的连续大写视为一个单词",
+			path: []string{"global", "IPAddress"},
+			want: "POLARIS_GLOBAL_IP_ADDRESS",
+		},
+		{
+			name: "插件名中的-和.转换为下划线",
+			path: []string{"consumer", "serviceRouter", "plugin", "nearby-based.v2", "matchLevel"},
+			want: "POLARIS_CONSUMER_SERVICE_ROUTER_PLUGIN_NEARBY_BASED_V2_MATCH_LEVEL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, toEnvName(tt.path))
+		})
+	}
+}
+
+// overlayTarget 覆盖各类配置项类型的结构体
+type overlayTarget struct {
+	Str      string
+	StrPtr   *string
+	List     []string
+	Duration *time.Duration
+	Flag     *bool
+	Num      int
+}
+
+func TestOverlayFieldSet(t *testing.T) {
+	strValue, duration, flag := "abc", 3*time.Second, true
+	tests := []struct {
+		name    string
+		field   string
+		text    string
+		wantErr bool
+		want    interface{}
+	}{
+		{
+			name:  "字符串直接赋值",
+			field: "Str",
+			text:  "a,b",
+			want:  "a,b",
+		},
+		{
+			name:  "字符串指针直接赋值",
+			field: "StrPtr",
+			text:  "abc",
+			want:  &strValue,
+		},
+		{
+			name:  "字符串列表按逗号分隔并忽略空项",
+			field: "List",
+			text:  "a, b,,c",
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "字符串列表支持YAML格式",
+			field: "List",
+			text:  "[a, b]",
+			want:  []string{"a", "b"},
+		},
+		{
+			name:  "时长按YAML解析",
+			field: "Duration",
+			text:  "3s",
+			want:  &duration,
+		},
+		{
+			name:  "布尔值按YAML解析",
+			field: "Flag",
+			text:  "true",
+			want:  &flag,
+		},
+		{
+			name:  "整数按YAML解析",
+			field: "Num",
+			text:  "5",
+			want:  5,
+		},
+		{
+			name:    "非法值返回错误且不修改原值",
+			field:   "Num",
+			text:    "abc",
+			wantErr: true,
+			want:    0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &overlayTarget{}
+			value := reflect.ValueOf(target).Elem().FieldByName(tt.field)
+			field := &OverlayField{Path: "test." + tt.field, value: value}
+			err := field.set(tt.text)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tt.want, value.Interface())
+		})
+	}
+}
+
+func TestApplyFlagOverlay(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantErr       bool
+		wantTimeout   time.Duration
+		wantAddresses []string
+	}{
+		{
+			name:          "使用=形式赋值",
+			args:          []string{"-polaris.global.api.timeout=3s"},
+			wantTimeout:   3 * time.Second,
+			wantAddresses: []string{"127.0.0.1:8091"},
+		},
+		{
+			name:          "使用下一个参数作为值",
+			args:          []string{"--polaris.global.api.timeout", "3s"},
+			wantTimeout:   3 * time.Second,
+			wantAddresses: []string{"127.0.0.1:8091"},
+		},
+		{
+			name:          "下一个参数以-开头时不作为值",
+			args:          []string{"-polaris.global.api.timeout", "-v"},
+			wantErr:       true,
+			wantTimeout:   DefaultAPIInvokeTimeout,
+			wantAddresses: []string{"127.0.0.1:8091"},
+		},
+		{
+			name: "下一个覆盖参数仍然生效",
+			args: []string{"-polaris.global.api.timeout",
+				"-polaris.global.serverConnector.addresses=127.0.0.2:8091,127.0.0.3:8091"},
+			wantErr:       true,
+			wantTimeout:   DefaultAPIInvokeTimeout,
+			wantAddresses: []string{"127.0.0.2:8091", "127.0.0.3:8091"},
+		},
+		{
+			name:          "最后一个参数缺少值",
+			args:          []string{"-polaris.global.api.timeout"},
+			wantErr:       true,
+			wantTimeout:   DefaultAPIInvokeTimeout,
+			wantAddresses: []string{"127.0.0.1:8091"},
+		},
+		{
+			name:          "其余参数被忽略",
+			args:          []string{"-v", "polaris.global.api.timeout=3s", "-other=1"},
+			wantTimeout:   DefaultAPIInvokeTimeout,
+			wantAddresses: []string{"127.0.0.1:8091"},
+		},
+		{
+			name:          "未知配置路径",
+			args:          []string{"-polaris.global.api.unknown=1", "-polaris.global.api.timeout=3s"},
+			wantErr:       true,
+			wantTimeout:   3 * time.Second,
+			wantAddresses: []string{"127.0.0.1:8091"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+			err := ApplyFlagOverlay(cfg, tt.args)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tt.wantTimeout, cfg.GetGlobal().GetAPI().GetTimeout())
+			assert.Equal(t, tt.wantAddresses, cfg.GetGlobal().GetServerConnector().GetAddresses())
+		})
+	}
+}
@@ -33,6 +33,14 @@ type ProviderConfigImpl struct {
 	RateLimit *RateLimitConfigImpl `yaml:"rateLimit" json:"rateLimit"`
 	// minimum interval between tow register operation
 	MinRgisterInterval time.Duration `yaml:"minRegisterInterval" json:"minRegisterInterval"`
+	// 优雅下线时隔离实例后等待排空的最大时长
+	DrainPeriod *time.Duration `yaml:"drainPeriod" json:"drainPeriod"`
+	// 优雅销毁SDK上下文时是否反注册自动心跳的实例，为false时仅发送最后一次心跳
+	DeregisterOnDestroy *bool `yaml:"deregisterOnDestroy" json:"deregisterOnDestroy"`
+	// 实例元数据更新的最小推送间隔，间隔内的多次更新会被合并
+	MetadataUpdateInterval time.Duration `yaml:"metadataUpdateInterval" json:"metadataUpdateInterval"`
+	// 自适应权重配置
+	AdaptiveWeight *AdaptiveWeightConfigImpl `yaml:"adaptiveWeight" json:"adaptiveWeight"`
 }
 
 // GetRateLimit 是否启用限流能力.
@@ -45,6 +53,41 @@ func (p *ProviderConfigImpl) GetMinRegisterInterval() time.Duration {
 	return p.MinRgisterInterval
 }
 
+// GetDrainPeriod 优雅下线时隔离实例后等待排空的最大时长.
+func (p *ProviderConfigImpl) GetDrainPeriod() time.Duration {
+	return *p.DrainPeriod
+}
+
+// SetDrainPeriod 设置优雅下线的排空时长.
+func (p *ProviderConfigImpl) SetDrainPeriod(period time.Duration) {
+	p.DrainPeriod = &period
+}
+
+// IsDeregisterOnDestroy 优雅销毁SDK上下文时是否反注册自动心跳的实例.
+func (p *ProviderConfigImpl) IsDeregisterOnDestroy() bool {
+	return *p.DeregisterOnDestroy
+}
+
+// SetDeregisterOnDestroy 设置优雅销毁SDK上下文时是否反注册自动心跳的实例.
+func (p *ProviderConfigImpl) SetDeregisterOnDestroy(deregister bool) {
+	p.DeregisterOnDestroy = &deregister
+}
+
+// GetAdaptiveWeight 自适应权重配置.
+func (p *ProviderConfigImpl) GetAdaptiveWeight() AdaptiveWeightConfig {
+	return p.AdaptiveWeight
+}
+
+// GetMetadataUpdateInterval 实例元数据更新的最小推送间隔.
+func (p *ProviderConfigImpl) GetMetadataUpdateInterval() time.Duration {
+	return p.MetadataUpdateInterval
+}
+
+// SetMetadataUpdateInterval 设置实例元数据更新的最小推送间隔.
+func (p *ProviderConfigImpl) SetMetadataUpdateInterval(interval time.Duration) {
+	p.MetadataUpdateInterval = interval
+}
+
 // Verify 校验配置参数.
 func (p *ProviderConfigImpl) Verify() error {
 	if nil == p {
@@ -55,9 +98,18 @@ func (p *ProviderConfigImpl) Verify() error {
 	if err = p.RateLimit.Verify(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
+	if err = p.AdaptiveWeight.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	if p.MinRgisterInterval <= 0 {
 		errs = multierror.Append(errs, errors.New("minRegisterInterval should be greater than zero"))
 	}
+	if p.DrainPeriod != nil && *p.DrainPeriod < 0 {
+		errs = multierror.Append(errs, errors.New("drainPeriod should not be negative"))
+	}
+	if p.MetadataUpdateInterval <= 0 {
+		errs = multierror.Append(errs, errors.New("metadataUpdateInterval should be greater than zero"))
+	}
 	return errs
 }
 
@@ -67,13 +119,28 @@ func (p *ProviderConfigImpl) SetDefault() {
 		p.RateLimit = &RateLimitConfigImpl{}
 	}
 	p.RateLimit.SetDefault()
+	if nil == p.AdaptiveWeight {
+		p.AdaptiveWeight = &AdaptiveWeightConfigImpl{}
+	}
+	p.AdaptiveWeight.SetDefault()
 	if p.MinRgisterInterval == 0 {
 		p.MinRgisterInterval = DefaultMinRegisterInterval
 	}
+	if p.DrainPeriod == nil {
+		p.SetDrainPeriod(DefaultDrainPeriod)
+	}
+	if p.DeregisterOnDestroy == nil {
+		p.SetDeregisterOnDestroy(DefaultDeregisterOnDestroy)
+	}
+	if p.MetadataUpdateInterval == 0 {
+		p.MetadataUpdateInterval = DefaultMetadataUpdateInterval
+	}
 }
 
 // Init 配置初始化.
 func (p *ProviderConfigImpl) Init() {
 	p.RateLimit = &RateLimitConfigImpl{}
 	p.RateLimit.Init()
+	p.AdaptiveWeight = &AdaptiveWeightConfigImpl{}
+	p.AdaptiveWeight.Init()
 }
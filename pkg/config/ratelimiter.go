@@ -39,6 +39,12 @@ type RateLimitConfigImpl struct {
 	LimiterNamespace string `yaml:"limiterNamespace" json:"limiterNamespace"`
 	// LimiterService 限流服务的服务名
 	LimiterService string `yaml:"limiterService" json:"limiterService"`
+	// Shadow 是否对所有规则开启影子模式，影子模式下只上报本应被限流的请求，不实际限流
+	Shadow bool `yaml:"shadow" json:"shadow"`
+	// ShadowRules 开启影子模式的规则名列表
+	ShadowRules []string `yaml:"shadowRules" json:"shadowRules"`
+	// ParentRules 子规则名到父规则名的映射，子规则匹配时同时从父规则的共享配额中分配
+	ParentRules map[string]string `yaml:"parentRules" json:"parentRules"`
 }
 
 // IsEnable 是否启用限流能力.
@@ -62,6 +68,11 @@ func (r *RateLimitConfigImpl) Verify() error {
 	if nil == r.Enable {
 		return fmt.Errorf("provider.rateLimit.enable must not be nil")
 	}
+	for child, parent := range r.ParentRules {
+		if child == parent {
+			return fmt.Errorf("provider.rateLimit.parentRules: rule %s can not be parent of itself", child)
+		}
+	}
 	return r.Plugin.Verify()
 }
 
@@ -140,3 +151,33 @@ func (r *RateLimitConfigImpl) SetLimiterNamespace(value string) {
 func (r *RateLimitConfigImpl) GetLimiterNamespace() string {
 	return r.LimiterNamespace
 }
+
+// IsShadow 是否对所有规则开启影子模式.
+func (r *RateLimitConfigImpl) IsShadow() bool {
+	return r.Shadow
+}
+
+// SetShadow 设置是否对所有规则开启影子模式.
+func (r *RateLimitConfigImpl) SetShadow(value bool) {
+	r.Shadow = value
+}
+
+// GetShadowRules 获取开启影子模式的规则名列表.
+func (r *RateLimitConfigImpl) GetShadowRules() []string {
+	return r.ShadowRules
+}
+
+// SetShadowRules 设置开启影子模式的规则名列表.
+func (r *RateLimitConfigImpl) SetShadowRules(ruleNames []string) {
+	r.ShadowRules = ruleNames
+}
+
+// GetParentRules 获取子规则名到父规则名的映射.
+func (r *RateLimitConfigImpl) GetParentRules() map[string]string {
+	return r.ParentRules
+}
+
+// SetParentRules 设置子规则名到父规则名的映射.
+func (r *RateLimitConfigImpl) SetParentRules(parentRules map[string]string) {
+	r.ParentRules = parentRules
+}
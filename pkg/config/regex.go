@@ -0,0 +1,109 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// RegexConfigImpl 规则中正则表达式的编译配置
+type RegexConfigImpl struct {
+	// Engine 正则引擎，取值为regexp2或std
+	Engine string `yaml:"engine" json:"engine"`
+	// MaxLength 表达式最大长度，超出的表达式视为非法
+	MaxLength int `yaml:"maxLength" json:"maxLength"`
+	// MatchTimeout regexp2引擎单次匹配的超时时间
+	MatchTimeout *time.Duration `yaml:"matchTimeout" json:"matchTimeout"`
+}
+
+// GetEngine 获取正则引擎
+func (r *RegexConfigImpl) GetEngine() string {
+	return r.Engine
+}
+
+// SetEngine 设置正则引擎
+func (r *RegexConfigImpl) SetEngine(engine string) {
+	r.Engine = engine
+}
+
+// GetMaxLength 获取表达式最大长度
+func (r *RegexConfigImpl) GetMaxLength() int {
+	return r.MaxLength
+}
+
+// SetMaxLength 设置表达式最大长度
+func (r *RegexConfigImpl) SetMaxLength(length int) {
+	r.MaxLength = length
+}
+
+// GetMatchTimeout 获取单次匹配的超时时间
+func (r *RegexConfigImpl) GetMatchTimeout() time.Duration {
+	return *r.MatchTimeout
+}
+
+// SetMatchTimeout 设置单次匹配的超时时间
+func (r *RegexConfigImpl) SetMatchTimeout(timeout time.Duration) {
+	r.MatchTimeout = &timeout
+}
+
+// ToRegexOptions 转换为正则表达式的编译选项
+func (r *RegexConfigImpl) ToRegexOptions() model.RegexOptions {
+	return model.RegexOptions{
+		Engine:       r.Engine,
+		MaxLength:    r.MaxLength,
+		MatchTimeout: r.GetMatchTimeout(),
+	}
+}
+
+// Verify 检验正则配置
+func (r *RegexConfigImpl) Verify() error {
+	if nil == r {
+		return errors.New("RegexConfig is nil")
+	}
+	var errs error
+	if r.Engine != model.RegexEngineRegexp2 && r.Engine != model.RegexEngineStd {
+		errs = multierror.Append(errs, fmt.Errorf("global.regex.engine must be %s or %s",
+			model.RegexEngineRegexp2, model.RegexEngineStd))
+	}
+	if r.MaxLength < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("global.regex.maxLength can not be negative"))
+	}
+	if r.MatchTimeout != nil && *r.MatchTimeout < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("global.regex.matchTimeout can not be negative"))
+	}
+	return errs
+}
+
+// SetDefault 设置正则配置的默认值
+func (r *RegexConfigImpl) SetDefault() {
+	if len(r.Engine) == 0 {
+		r.Engine = model.RegexEngineRegexp2
+	}
+	if r.MaxLength == 0 {
+		r.MaxLength = DefaultRegexMaxLength
+	}
+	if nil == r.MatchTimeout {
+		r.MatchTimeout = model.ToDurationPtr(DefaultRegexMatchTimeout)
+	}
+}
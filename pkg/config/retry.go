@@ -0,0 +1,161 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// RetryConfigImpl 调用重试配置，重试次数受重试预算限制，避免故障时重试流量放大
+type RetryConfigImpl struct {
+	// MaxAttempts 最大调用次数，包含首次调用
+	MaxAttempts int `yaml:"maxAttempts" json:"maxAttempts"`
+	// PerTryTimeout 单次调用超时时间
+	PerTryTimeout *time.Duration `yaml:"perTryTimeout" json:"perTryTimeout"`
+	// RetryableCodes 可重试的返回码，为空表示所有失败均可重试
+	RetryableCodes []int32 `yaml:"retryableCodes" json:"retryableCodes"`
+	// BaseBackoff 指数退避的初始间隔
+	BaseBackoff *time.Duration `yaml:"baseBackoff" json:"baseBackoff"`
+	// MaxBackoff 指数退避的最大间隔
+	MaxBackoff *time.Duration `yaml:"maxBackoff" json:"maxBackoff"`
+	// BudgetPercent 重试预算，重试请求数占统计窗口内请求数的最大百分比
+	BudgetPercent float64 `yaml:"budgetPercent" json:"budgetPercent"`
+	// MinRetriesPerSecond 不受预算限制的每秒最小重试数，保证低流量时也能重试
+	MinRetriesPerSecond int `yaml:"minRetriesPerSecond" json:"minRetriesPerSecond"`
+}
+
+// GetMaxAttempts 获取最大调用次数
+func (r *RetryConfigImpl) GetMaxAttempts() int {
+	return r.MaxAttempts
+}
+
+// SetMaxAttempts 设置最大调用次数
+func (r *RetryConfigImpl) SetMaxAttempts(maxAttempts int) {
+	r.MaxAttempts = maxAttempts
+}
+
+// GetPerTryTimeout 获取单次调用超时时间
+func (r *RetryConfigImpl) GetPerTryTimeout() time.Duration {
+	return *r.PerTryTimeout
+}
+
+// SetPerTryTimeout 设置单次调用超时时间
+func (r *RetryConfigImpl) SetPerTryTimeout(timeout time.Duration) {
+	r.PerTryTimeout = &timeout
+}
+
+// GetRetryableCodes 获取可重试的返回码
+func (r *RetryConfigImpl) GetRetryableCodes() []int32 {
+	return r.RetryableCodes
+}
+
+// SetRetryableCodes 设置可重试的返回码
+func (r *RetryConfigImpl) SetRetryableCodes(codes []int32) {
+	r.RetryableCodes = codes
+}
+
+// GetBaseBackoff 获取指数退避的初始间隔
+func (r *RetryConfigImpl) GetBaseBackoff() time.Duration {
+	return *r.BaseBackoff
+}
+
+// SetBaseBackoff 设置指数退避的初始间隔
+func (r *RetryConfigImpl) SetBaseBackoff(backoff time.Duration) {
+	r.BaseBackoff = &backoff
+}
+
+// GetMaxBackoff 获取指数退避的最大间隔
+func (r *RetryConfigImpl) GetMaxBackoff() time.Duration {
+	return *r.MaxBackoff
+}
+
+// SetMaxBackoff 设置指数退避的最大间隔
+func (r *RetryConfigImpl) SetMaxBackoff(backoff time.Duration) {
+	r.MaxBackoff = &backoff
+}
+
+// GetBudgetPercent 获取重试预算百分比
+func (r *RetryConfigImpl) GetBudgetPercent() float64 {
+	return r.BudgetPercent
+}
+
+// SetBudgetPercent 设置重试预算百分比
+func (r *RetryConfigImpl) SetBudgetPercent(percent float64) {
+	r.BudgetPercent = percent
+}
+
+// GetMinRetriesPerSecond 获取每秒最小重试数
+func (r *RetryConfigImpl) GetMinRetriesPerSecond() int {
+	return r.MinRetriesPerSecond
+}
+
+// SetMinRetriesPerSecond 设置每秒最小重试数
+func (r *RetryConfigImpl) SetMinRetriesPerSecond(count int) {
+	r.MinRetriesPerSecond = count
+}
+
+// Verify 检验重试配置
+func (r *RetryConfigImpl) Verify() error {
+	if nil == r {
+		return errors.New("RetryConfig is nil")
+	}
+	var errs error
+	if r.MaxAttempts < 1 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.retry.maxAttempts must be greater than 0"))
+	}
+	if r.PerTryTimeout != nil && *r.PerTryTimeout <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.retry.perTryTimeout must be greater than 0"))
+	}
+	if r.BaseBackoff != nil && r.MaxBackoff != nil && *r.BaseBackoff > *r.MaxBackoff {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.retry.baseBackoff can not be greater than maxBackoff"))
+	}
+	if r.BudgetPercent < 0 || r.BudgetPercent > 100 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.retry.budgetPercent must be in [0, 100]"))
+	}
+	if r.MinRetriesPerSecond < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.retry.minRetriesPerSecond can not be negative"))
+	}
+	return errs
+}
+
+// SetDefault 设置重试配置的默认值
+func (r *RetryConfigImpl) SetDefault() {
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if nil == r.PerTryTimeout {
+		r.PerTryTimeout = model.ToDurationPtr(DefaultRetryPerTryTimeout)
+	}
+	if nil == r.BaseBackoff {
+		r.BaseBackoff = model.ToDurationPtr(DefaultRetryBaseBackoff)
+	}
+	if nil == r.MaxBackoff {
+		r.MaxBackoff = model.ToDurationPtr(DefaultRetryMaxBackoff)
+	}
+	if r.BudgetPercent == 0 {
+		r.BudgetPercent = DefaultRetryBudgetPercent
+	}
+	if r.MinRetriesPerSecond == 0 {
+		r.MinRetriesPerSecond = DefaultRetryMinRetriesPerSecond
+	}
+}
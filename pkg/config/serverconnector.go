@@ -51,6 +51,30 @@ type ServerConnectorConfigImpl struct {
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
 
 	Token string `yaml:"token" json:"token"`
+
+	// 备用集群列表，按优先级排序，addresses 所在集群优先级最高
+	Clusters []*PriorityClusterConfig `yaml:"clusters" json:"clusters"`
+
+	FailoverThreshold *int32 `yaml:"failoverThreshold" json:"failoverThreshold"`
+
+	FailbackWindow *time.Duration `yaml:"failbackWindow" json:"failbackWindow"`
+
+	ClusterProbeInterval *time.Duration `yaml:"clusterProbeInterval" json:"clusterProbeInterval"`
+
+	// 鉴权token文件，文件内容变更后自动生效，优先级高于token
+	TokenFile string `yaml:"tokenFile" json:"tokenFile"`
+
+	// 存放鉴权token的环境变量名，优先级高于token
+	TokenEnv string `yaml:"tokenEnv" json:"tokenEnv"`
+
+	// 重新读取鉴权token文件的间隔
+	TokenRefreshInterval *time.Duration `yaml:"tokenRefreshInterval" json:"tokenRefreshInterval"`
+
+	// 与server通信的TLS配置
+	TLS *TLSConfigImpl `yaml:"tls" json:"tls"`
+
+	// 是否与进程内其他开启共享的SDK上下文复用到同一server地址的连接
+	ShareConnection *bool `yaml:"shareConnection" json:"shareConnection"`
 }
 
 // GetAddresses global.serverConnector.addresses
@@ -165,6 +189,99 @@ func (s *ServerConnectorConfigImpl) SetToken(t string) {
 	s.Token = t
 }
 
+// GetClusters global.serverConnector.clusters
+// 按优先级排序的备用server集群.
+func (s *ServerConnectorConfigImpl) GetClusters() []*PriorityClusterConfig {
+	return s.Clusters
+}
+
+// SetClusters 设置备用server集群.
+func (s *ServerConnectorConfigImpl) SetClusters(clusters []*PriorityClusterConfig) {
+	s.Clusters = clusters
+}
+
+// GetFailoverThreshold global.serverConnector.failoverThreshold
+// 连续失败多少次后切换到下一个集群.
+func (s *ServerConnectorConfigImpl) GetFailoverThreshold() int32 {
+	return *s.FailoverThreshold
+}
+
+// SetFailoverThreshold 设置切换集群的连续失败次数.
+func (s *ServerConnectorConfigImpl) SetFailoverThreshold(threshold int32) {
+	s.FailoverThreshold = &threshold
+}
+
+// GetFailbackWindow global.serverConnector.failbackWindow
+// 高优先级集群持续健康多久后切回.
+func (s *ServerConnectorConfigImpl) GetFailbackWindow() time.Duration {
+	return *s.FailbackWindow
+}
+
+// SetFailbackWindow 设置切回高优先级集群的稳定窗口.
+func (s *ServerConnectorConfigImpl) SetFailbackWindow(window time.Duration) {
+	s.FailbackWindow = &window
+}
+
+// GetClusterProbeInterval global.serverConnector.clusterProbeInterval
+// 集群健康探测的间隔.
+func (s *ServerConnectorConfigImpl) GetClusterProbeInterval() time.Duration {
+	return *s.ClusterProbeInterval
+}
+
+// SetClusterProbeInterval 设置集群健康探测的间隔.
+func (s *ServerConnectorConfigImpl) SetClusterProbeInterval(interval time.Duration) {
+	s.ClusterProbeInterval = &interval
+}
+
+// GetTokenFile global.serverConnector.tokenFile
+// 鉴权token文件.
+func (s *ServerConnectorConfigImpl) GetTokenFile() string {
+	return s.TokenFile
+}
+
+// SetTokenFile 设置鉴权token文件.
+func (s *ServerConnectorConfigImpl) SetTokenFile(file string) {
+	s.TokenFile = file
+}
+
+// GetTokenEnv global.serverConnector.tokenEnv
+// 存放鉴权token的环境变量名.
+func (s *ServerConnectorConfigImpl) GetTokenEnv() string {
+	return s.TokenEnv
+}
+
+// SetTokenEnv 设置存放鉴权token的环境变量名.
+func (s *ServerConnectorConfigImpl) SetTokenEnv(env string) {
+	s.TokenEnv = env
+}
+
+// GetTokenRefreshInterval global.serverConnector.tokenRefreshInterval
+// 重新读取鉴权token文件的间隔.
+func (s *ServerConnectorConfigImpl) GetTokenRefreshInterval() time.Duration {
+	return *s.TokenRefreshInterval
+}
+
+// SetTokenRefreshInterval 设置重新读取鉴权token文件的间隔.
+func (s *ServerConnectorConfigImpl) SetTokenRefreshInterval(interval time.Duration) {
+	s.TokenRefreshInterval = &interval
+}
+
+// GetTLS global.serverConnector.tls
+// 与server通信的TLS配置.
+func (s *ServerConnectorConfigImpl) GetTLS() TLSConfig {
+	return s.TLS
+}
+
+// IsShareConnection global.serverConnector.shareConnection
+// 是否与进程内其他SDK上下文共享连接.
+func (s *ServerConnectorConfigImpl) IsShareConnection() bool {
+	return *s.ShareConnection
+}
+
+// SetShareConnection 设置是否与进程内其他SDK上下文共享连接.
+func (s *ServerConnectorConfigImpl) SetShareConnection(share bool) {
+	s.ShareConnection = &share
+}
 
 // Verify 检验ServerConnector配置.
 func (s *ServerConnectorConfigImpl) Verify() error {
@@ -172,9 +289,23 @@ func (s *ServerConnectorConfigImpl) Verify() error {
 		return errors.New("ServerConnectorConfig is nil")
 	}
 	var errs error
-	if len(s.Addresses) == 0 {
+	if len(s.Addresses) == 0 && len(s.Clusters) == 0 {
 		errs = multierror.Append(errs, fmt.Errorf("global.serverConnector.addresses is empty"))
 	}
+	for i, cluster := range s.Clusters {
+		if cluster == nil || len(cluster.Addresses) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("global.serverConnector.clusters[%d].addresses is empty", i))
+		}
+	}
+	if s.FailoverThreshold != nil && *s.FailoverThreshold <= 0 {
+		errs = multierror.Append(errs,
+			fmt.Errorf("global.serverConnector.failoverThreshold %v is invalid", *s.FailoverThreshold))
+	}
+	if s.ClusterProbeInterval != nil && *s.ClusterProbeInterval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("global.serverConnector.clusterProbeInterval %v is less than minimal timing interval %v",
+				*s.ClusterProbeInterval, DefaultMinTimingInterval))
+	}
 	if nil != s.RequestQueueSize && *s.RequestQueueSize < 0 {
 		errs = multierror.Append(errs,
 			fmt.Errorf("global.serverConnector.requestQueueSize %v is invalid", s.RequestQueueSize))
@@ -191,6 +322,16 @@ func (s *ServerConnectorConfigImpl) Verify() error {
 				" is less than or equal to global.serverConnector.connectionIdleTimeout %v",
 				*s.ServerSwitchInterval, *s.ConnectionIdleTimeout))
 	}
+	if s.TokenRefreshInterval != nil && *s.TokenRefreshInterval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("global.serverConnector.tokenRefreshInterval %v is less than minimal timing interval %v",
+				*s.TokenRefreshInterval, DefaultMinTimingInterval))
+	}
+	if nil != s.TLS {
+		if err := s.TLS.Verify(); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("global.serverConnector.%v", err))
+		}
+	}
 	return errs
 }
 
@@ -217,6 +358,25 @@ func (s *ServerConnectorConfigImpl) SetDefault() {
 	if len(s.Protocol) == 0 {
 		s.Protocol = DefaultServerConnector
 	}
+	if nil == s.FailoverThreshold {
+		s.FailoverThreshold = proto.Int32(int32(DefaultClusterFailoverThreshold))
+	}
+	if nil == s.FailbackWindow {
+		s.FailbackWindow = model.ToDurationPtr(DefaultClusterFailbackWindow)
+	}
+	if nil == s.ClusterProbeInterval {
+		s.ClusterProbeInterval = model.ToDurationPtr(DefaultClusterProbeInterval)
+	}
+	if nil == s.TokenRefreshInterval {
+		s.TokenRefreshInterval = model.ToDurationPtr(DefaultTokenRefreshInterval)
+	}
+	if nil == s.TLS {
+		s.TLS = &TLSConfigImpl{}
+	}
+	s.TLS.SetDefault()
+	if nil == s.ShareConnection {
+		s.SetShareConnection(DefaultShareConnection)
+	}
 	s.Plugin.SetDefault(common.TypeServerConnector)
 }
 
@@ -0,0 +1,120 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+)
+
+// TLSConfigImpl 与服务端通信的TLS配置.
+type TLSConfigImpl struct {
+	// Enable 是否启用TLS
+	Enable *bool `yaml:"enable" json:"enable"`
+	// CAFile 校验服务端证书的CA证书文件，为空时使用系统根证书
+	CAFile string `yaml:"caFile" json:"caFile"`
+	// CertFile 双向认证时使用的客户端证书文件
+	CertFile string `yaml:"certFile" json:"certFile"`
+	// KeyFile 双向认证时使用的客户端私钥文件
+	KeyFile string `yaml:"keyFile" json:"keyFile"`
+	// ServerName 校验服务端证书及SNI使用的服务端名称，为空时使用连接地址
+	ServerName string `yaml:"serverName" json:"serverName"`
+	// InsecureSkipVerify 是否跳过服务端证书校验，仅用于测试
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify" json:"insecureSkipVerify"`
+}
+
+// IsEnable 是否启用TLS.
+func (t *TLSConfigImpl) IsEnable() bool {
+	return *t.Enable
+}
+
+// SetEnable 设置是否启用TLS.
+func (t *TLSConfigImpl) SetEnable(enable bool) {
+	t.Enable = &enable
+}
+
+// GetCAFile 获取CA证书文件.
+func (t *TLSConfigImpl) GetCAFile() string {
+	return t.CAFile
+}
+
+// SetCAFile 设置CA证书文件.
+func (t *TLSConfigImpl) SetCAFile(file string) {
+	t.CAFile = file
+}
+
+// GetCertFile 获取客户端证书文件.
+func (t *TLSConfigImpl) GetCertFile() string {
+	return t.CertFile
+}
+
+// SetCertFile 设置客户端证书文件.
+func (t *TLSConfigImpl) SetCertFile(file string) {
+	t.CertFile = file
+}
+
+// GetKeyFile 获取客户端私钥文件.
+func (t *TLSConfigImpl) GetKeyFile() string {
+	return t.KeyFile
+}
+
+// SetKeyFile 设置客户端私钥文件.
+func (t *TLSConfigImpl) SetKeyFile(file string) {
+	t.KeyFile = file
+}
+
+// GetServerName 获取服务端名称.
+func (t *TLSConfigImpl) GetServerName() string {
+	return t.ServerName
+}
+
+// SetServerName 设置服务端名称.
+func (t *TLSConfigImpl) SetServerName(name string) {
+	t.ServerName = name
+}
+
+// IsInsecureSkipVerify 是否跳过服务端证书校验.
+func (t *TLSConfigImpl) IsInsecureSkipVerify() bool {
+	return t.InsecureSkipVerify
+}
+
+// SetInsecureSkipVerify 设置是否跳过服务端证书校验.
+func (t *TLSConfigImpl) SetInsecureSkipVerify(skip bool) {
+	t.InsecureSkipVerify = skip
+}
+
+// Verify 检验TLS配置.
+func (t *TLSConfigImpl) Verify() error {
+	if nil == t {
+		return errors.New("TLSConfig is nil")
+	}
+	if nil != t.Enable && !*t.Enable {
+		return nil
+	}
+	if (len(t.CertFile) == 0) != (len(t.KeyFile) == 0) {
+		return errors.New("tls.certFile and tls.keyFile must be set together")
+	}
+	return nil
+}
+
+// SetDefault 设置TLS配置的默认值.
+func (t *TLSConfigImpl) SetDefault() {
+	if nil == t.Enable {
+		enable := DefaultTLSEnabled
+		t.Enable = &enable
+	}
+}
@@ -0,0 +1,239 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+var (
+	// yamlErrorRegex yaml解析错误的格式，如 line 12: field foo not found in type config.APIConfigImpl
+	yamlErrorRegex = regexp.MustCompile(`^line (\d+): (.*)$`)
+	// yamlKeyRegex yaml中配置项所在行的格式
+	yamlKeyRegex = regexp.MustCompile(`^([A-Za-z0-9_\-.]+)\s*:`)
+	// configPathRegex 校验错误信息开头的配置项路径，如 global.serverConnector.clusters[0].addresses
+	configPathRegex = regexp.MustCompile(`^((?:global|consumer|provider|config)(?:\.[A-Za-z0-9_\-]+(?:\[\d+\])?)+)`)
+	// pathIndexRegex 配置项路径中的数组下标
+	pathIndexRegex = regexp.MustCompile(`\[\d+\]`)
+)
+
+// ValidationIssue 配置校验发现的单个问题
+type ValidationIssue struct {
+	// Path 问题所在配置项的路径，如 global.api.timeout，无法定位时为空
+	Path string
+	// Line 问题在配置文件中的行号，无法定位时为0
+	Line int
+	// Message 问题描述
+	Message string
+}
+
+// String 输出带位置信息的问题描述
+func (i ValidationIssue) String() string {
+	msg := i.Message
+	if len(i.Path) > 0 && !strings.Contains(msg, i.Path) {
+		msg = i.Path + ": " + msg
+	}
+	if i.Line > 0 {
+		return fmt.Sprintf("line %d: %s", i.Line, msg)
+	}
+	return msg
+}
+
+// ValidationErrors 汇总配置校验发现的全部问题
+type ValidationErrors struct {
+	// Issues 校验发现的问题，按发现顺序排列
+	Issues []ValidationIssue
+	// lines 配置项路径到配置文件行号的映射
+	lines map[string]int
+	// paths 配置文件行号到配置项路径的映射
+	paths map[int]string
+}
+
+// newValidationErrors 创建校验结果，content为配置文件内容，用于定位问题所在的行
+func newValidationErrors(content string) *ValidationErrors {
+	v := &ValidationErrors{lines: map[string]int{}, paths: map[int]string{}}
+	v.indexLines(content)
+	return v
+}
+
+// Error 输出全部问题
+func (v *ValidationErrors) Error() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%d problem(s) found in configuration:", len(v.Issues)))
+	for _, issue := range v.Issues {
+		builder.WriteString("\n\t* ")
+		builder.WriteString(issue.String())
+	}
+	return builder.String()
+}
+
+// Add 添加配置项的问题，行号根据配置项路径定位
+func (v *ValidationErrors) Add(path string, format string, args ...interface{}) {
+	v.Issues = append(v.Issues, ValidationIssue{
+		Path:    path,
+		Line:    v.lines[pathIndexRegex.ReplaceAllString(path, "")],
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// AddError 添加校验错误，多个错误会被展开，并从错误信息开头解析配置项路径
+func (v *ValidationErrors) AddError(err error) {
+	if err == nil {
+		return
+	}
+	if merr, ok := err.(*multierror.Error); ok {
+		for _, e := range merr.WrappedErrors() {
+			v.AddError(e)
+		}
+		return
+	}
+	if verr, ok := err.(*ValidationErrors); ok {
+		v.Issues = append(v.Issues, verr.Issues...)
+		return
+	}
+	msg := err.Error()
+	path := configPathRegex.FindString(msg)
+	v.Issues = append(v.Issues, ValidationIssue{
+		Path:    path,
+		Line:    v.lines[pathIndexRegex.ReplaceAllString(path, "")],
+		Message: msg,
+	})
+}
+
+// HasIssues 是否发现了问题
+func (v *ValidationErrors) HasIssues() bool {
+	return len(v.Issues) > 0
+}
+
+// addYAMLErrors 添加yaml解析时的类型错误及未知配置项
+func (v *ValidationErrors) addYAMLErrors(errs []string) {
+	for _, e := range errs {
+		issue := ValidationIssue{Message: e}
+		if matches := yamlErrorRegex.FindStringSubmatch(e); len(matches) == 3 {
+			issue.Line, _ = strconv.Atoi(matches[1])
+			issue.Message = matches[2]
+			issue.Path = v.paths[issue.Line]
+		}
+		v.Issues = append(v.Issues, issue)
+	}
+}
+
+// indexLines 按缩进解析配置文件，建立配置项路径与行号的映射，列表元素内的配置项不做定位
+func (v *ValidationErrors) indexLines(content string) {
+	type level struct {
+		indent int
+		key    string
+	}
+	var stack []level
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+		matches := yamlKeyRegex.FindStringSubmatch(trimmed)
+		if len(matches) != 2 {
+			continue
+		}
+		indent := len(line) - len(trimmed)
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, level{indent: indent, key: matches[1]})
+		keys := make([]string, 0, len(stack))
+		for _, l := range stack {
+			keys = append(keys, l.key)
+		}
+		path := strings.Join(keys, ".")
+		if _, ok := v.lines[path]; !ok {
+			v.lines[path] = i + 1
+		}
+		v.paths[i+1] = path
+	}
+}
+
+// ValidationCheck 额外的配置校验逻辑，如校验插件名是否已注册
+type ValidationCheck func(cfg *ConfigurationImpl, errs *ValidationErrors)
+
+// ValidateConfiguration 严格校验配置内容，只做校验而不创建SDK上下文，可用于在CI中检查配置文件
+// 未知的配置项、类型错误、非法取值及相互冲突的配置均会被一次性返回，错误类型为 *ValidationErrors
+func ValidateConfiguration(buf []byte, checks ...ValidationCheck) error {
+	_, err := loadConfiguration(buf, true, checks...)
+	return err
+}
+
+// loadConfiguration 解析并校验配置，strict为true时未知的配置项视为错误
+func loadConfiguration(buf []byte, strict bool, checks ...ValidationCheck) (*ConfigurationImpl, error) {
+	cfg := &ConfigurationImpl{}
+	cfg.Init()
+	// to support environment variables
+	content := os.ExpandEnv(string(buf))
+	errs := newValidationErrors(content)
+	decoder := yaml.NewDecoder(bytes.NewBufferString(content))
+	decoder.SetStrict(strict)
+	if err := decoder.Decode(cfg); err != nil {
+		typeErr, ok := err.(*yaml.TypeError)
+		if !ok {
+			return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
+				"fail to decode config string")
+		}
+		// 类型错误不影响其余配置项的解析，继续校验以便一次性返回全部问题
+		errs.addYAMLErrors(typeErr.Errors)
+	}
+	cfg.SetDefault()
+	// 环境变量的优先级高于配置文件
+	errs.AddError(ApplyEnvOverlay(cfg))
+	errs.AddError(cfg.Verify())
+	for _, check := range checks {
+		check(cfg, errs)
+	}
+	if errs.HasIssues() {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, errs,
+			"fail to verify config string")
+	}
+	return cfg, nil
+}
+
+// verifyConflicts 校验相互冲突的配置
+func (c *ConfigurationImpl) verifyConflicts() error {
+	if c.Global == nil || c.Global.ConfigReload == nil || c.Global.System == nil || c.Config == nil {
+		return nil
+	}
+	var errs error
+	configEnabled := c.Config.IsEnable()
+	if len(c.Global.ConfigReload.GetFileName()) > 0 && !configEnabled {
+		errs = multierror.Append(errs, fmt.Errorf(
+			"global.configReload.fileName requires config.enable to be true"))
+	}
+	for _, resolver := range c.Global.System.GetVariableResolvers() {
+		if resolver == model.VariableResolverConfigCenter && !configEnabled {
+			errs = multierror.Append(errs, fmt.Errorf(
+				"global.system.variableResolvers %s requires config.enable to be true", resolver))
+		}
+	}
+	return errs
+}
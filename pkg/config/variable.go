@@ -0,0 +1,97 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// VariableFileConfigImpl 存放路由规则变量的配置中心文件，文件内容为变量名到变量值的YAML/JSON映射
+type VariableFileConfigImpl struct {
+	// 配置文件所在命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// 配置文件分组
+	FileGroup string `yaml:"fileGroup" json:"fileGroup"`
+	// 配置文件名
+	FileName string `yaml:"fileName" json:"fileName"`
+}
+
+// GetNamespace 获取配置文件所在命名空间
+func (v *VariableFileConfigImpl) GetNamespace() string {
+	return v.Namespace
+}
+
+// SetNamespace 设置配置文件所在命名空间
+func (v *VariableFileConfigImpl) SetNamespace(namespace string) {
+	v.Namespace = namespace
+}
+
+// GetFileGroup 获取配置文件分组
+func (v *VariableFileConfigImpl) GetFileGroup() string {
+	return v.FileGroup
+}
+
+// SetFileGroup 设置配置文件分组
+func (v *VariableFileConfigImpl) SetFileGroup(fileGroup string) {
+	v.FileGroup = fileGroup
+}
+
+// GetFileName 获取配置文件名
+func (v *VariableFileConfigImpl) GetFileName() string {
+	return v.FileName
+}
+
+// SetFileName 设置配置文件名
+func (v *VariableFileConfigImpl) SetFileName(fileName string) {
+	v.FileName = fileName
+}
+
+// Init 初始化
+func (v *VariableFileConfigImpl) Init() {
+}
+
+// SetDefault 设置默认值
+func (v *VariableFileConfigImpl) SetDefault() {
+	if len(v.Namespace) == 0 {
+		v.Namespace = DefaultVariableFileNamespace
+	}
+	if len(v.FileGroup) == 0 {
+		v.FileGroup = DefaultVariableFileGroup
+	}
+}
+
+// Verify 校验配置，仅在启用了配置中心变量解析器时调用
+func (v *VariableFileConfigImpl) Verify() error {
+	if v == nil {
+		return errors.New("VariableFileConfig is nil")
+	}
+	var errs error
+	if len(v.Namespace) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("global.system.variableFile.namespace is empty"))
+	}
+	if len(v.FileGroup) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("global.system.variableFile.fileGroup is empty"))
+	}
+	if len(v.FileName) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("global.system.variableFile.fileName is empty"))
+	}
+	return errs
+}
@@ -0,0 +1,165 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package dispatcher 监听器回调的分发协程池
+package dispatcher
+
+import (
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+const (
+	// OverflowBlock 队列满时阻塞分发方，直到队列有空位
+	OverflowBlock = "block"
+	// OverflowDropOldest 队列满时丢弃队列中最早的回调
+	OverflowDropOldest = "dropOldest"
+	// OverflowSpawn 队列满时使用新协程执行回调，不阻塞也不丢弃，但不再保证同一监听器的回调顺序
+	OverflowSpawn = "spawn"
+)
+
+// listenerSeq 监听器标识的自增序列
+var listenerSeq uint64
+
+// NextKey 为新注册的监听器分配分发标识，同一标识的回调在同一协程中按顺序执行
+func NextKey() uint64 {
+	return atomic.AddUint64(&listenerSeq, 1)
+}
+
+// task 待执行的回调
+type task struct {
+	name string
+	fn   func()
+}
+
+// Pool 有界的回调分发协程池，每个协程持有独立的队列，按监听器标识选择协程，
+// 保证同一监听器的回调按顺序执行，且单个监听器的异常或阻塞不影响规则更新流程
+type Pool struct {
+	name     string
+	policy   string
+	queues   []chan task
+	done     chan struct{}
+	stopOnce sync.Once
+	// dropped 因队列满被丢弃的回调数
+	dropped uint64
+}
+
+// NewPool 创建分发协程池
+func NewPool(name string, workers int, queueSize int, policy string) *Pool {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+	p := &Pool{
+		name:   name,
+		policy: policy,
+		queues: make([]chan task, workers),
+		done:   make(chan struct{}),
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan task, queueSize)
+		go p.work(p.queues[i])
+	}
+	return p
+}
+
+// Dispatch 分发回调，key 为监听器标识，name 用于日志，pool 为 nil 时直接在当前协程执行
+func (p *Pool) Dispatch(key uint64, name string, fn func()) {
+	t := task{name: name, fn: fn}
+	if nil == p {
+		safeRun("", t)
+		return
+	}
+	queue := p.queues[key%uint64(len(p.queues))]
+	switch p.policy {
+	case OverflowDropOldest:
+		p.dispatchDropOldest(queue, t)
+	case OverflowSpawn:
+		select {
+		case queue <- t:
+		case <-p.done:
+		default:
+			go safeRun(p.name, t)
+		}
+	default:
+		select {
+		case queue <- t:
+		case <-p.done:
+		}
+	}
+}
+
+// dispatchDropOldest 队列满时丢弃最早的回调后重试
+func (p *Pool) dispatchDropOldest(queue chan task, t task) {
+	for {
+		select {
+		case queue <- t:
+			return
+		case <-p.done:
+			return
+		default:
+		}
+		select {
+		case old := <-queue:
+			atomic.AddUint64(&p.dropped, 1)
+			log.GetBaseLogger().Warnf("[Dispatcher] %s queue is full, drop oldest callback of %s", p.name, old.name)
+		default:
+		}
+	}
+}
+
+// GetDropped 获取因队列满被丢弃的回调数
+func (p *Pool) GetDropped() uint64 {
+	return atomic.LoadUint64(&p.dropped)
+}
+
+// work 按顺序执行队列中的回调
+func (p *Pool) work(queue chan task) {
+	for {
+		select {
+		case t := <-queue:
+			safeRun(p.name, t)
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// Destroy 停止分发协程，未执行的回调将被丢弃
+func (p *Pool) Destroy() {
+	if nil == p {
+		return
+	}
+	p.stopOnce.Do(func() {
+		close(p.done)
+	})
+}
+
+// safeRun 执行回调，回调 panic 时记录日志，不影响其他监听器
+func safeRun(pool string, t task) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.GetBaseLogger().Errorf("[Dispatcher] %s listener %s panic: %v\n%s", pool, t.name, err, debug.Stack())
+		}
+	}()
+	t.fn()
+}
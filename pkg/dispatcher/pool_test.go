@@ -0,0 +1,185 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dispatcher
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+func TestPoolOverflow(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		// blocked 队列满时分发方是否被阻塞
+		blocked bool
+		// beforeRelease 执行协程被占用期间就已执行的回调数
+		beforeRelease int
+		expectOrder   []string
+		expectDropped uint64
+	}{
+		{
+			name:          "阻塞",
+			policy:        OverflowBlock,
+			blocked:       true,
+			expectOrder:   []string{"first", "second"},
+			expectDropped: 0,
+		},
+		{
+			name:          "未知策略按阻塞处理",
+			policy:        "unknown",
+			blocked:       true,
+			expectOrder:   []string{"first", "second"},
+			expectDropped: 0,
+		},
+		{
+			name:          "丢弃最早的回调",
+			policy:        OverflowDropOldest,
+			expectOrder:   []string{"second"},
+			expectDropped: 1,
+		},
+		{
+			name:          "新建协程执行",
+			policy:        OverflowSpawn,
+			beforeRelease: 1,
+			expectOrder:   []string{"second", "first"},
+			expectDropped: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPool("test", 1, 1, tt.policy)
+			defer p.Destroy()
+
+			var mutex sync.Mutex
+			var order []string
+			ran := make(chan struct{}, len(tt.expectOrder))
+			record := func(name string) func() {
+				return func() {
+					mutex.Lock()
+					order = append(order, name)
+					mutex.Unlock()
+					ran <- struct{}{}
+				}
+			}
+			// 占用唯一的执行协程，再填满长度为1的队列
+			started := make(chan struct{})
+			release := make(chan struct{})
+			p.Dispatch(1, "blocker", func() {
+				close(started)
+				<-release
+			})
+			<-started
+			p.Dispatch(1, "first", record("first"))
+
+			dispatched := make(chan struct{})
+			go func() {
+				p.Dispatch(1, "second", record("second"))
+				close(dispatched)
+			}()
+			blocked := false
+			select {
+			case <-dispatched:
+			case <-time.After(50 * time.Millisecond):
+				blocked = true
+			}
+			assert.Equal(t, tt.blocked, blocked)
+			for i := 0; i < tt.beforeRelease; i++ {
+				<-ran
+			}
+			close(release)
+			<-dispatched
+			for i := tt.beforeRelease; i < len(tt.expectOrder); i++ {
+				<-ran
+			}
+			mutex.Lock()
+			assert.Equal(t, tt.expectOrder, order)
+			mutex.Unlock()
+			assert.Equal(t, tt.expectDropped, p.GetDropped())
+		})
+	}
+}
+
+func TestPoolKeepsOrderPerKey(t *testing.T) {
+	p := NewPool("test", 4, 16, OverflowBlock)
+	defer p.Destroy()
+	const count = 100
+	var mutex sync.Mutex
+	var order []int
+	done := make(chan struct{})
+	key := NextKey()
+	for i := 0; i < count; i++ {
+		i := i
+		p.Dispatch(key, "listener", func() {
+			mutex.Lock()
+			order = append(order, i)
+			mutex.Unlock()
+			if i == count-1 {
+				close(done)
+			}
+		})
+	}
+	<-done
+	for i := 0; i < count; i++ {
+		assert.Equal(t, i, order[i])
+	}
+}
+
+func TestPoolRecoverPanic(t *testing.T) {
+	tests := []struct {
+		name string
+		pool *Pool
+	}{
+		{
+			name: "未创建协程池时在当前协程执行",
+		},
+		{
+			name: "协程池",
+			pool: NewPool("test", 1, 1, OverflowBlock),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer tt.pool.Destroy()
+			done := make(chan struct{})
+			tt.pool.Dispatch(1, "panic", func() {
+				panic("listener panic")
+			})
+			// 回调 panic 后同一监听器的后续回调仍然执行
+			tt.pool.Dispatch(1, "next", func() {
+				close(done)
+			})
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("callback after panic not run")
+			}
+		})
+	}
+}
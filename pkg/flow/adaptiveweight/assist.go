@@ -0,0 +1,233 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package adaptiveweight
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/metric"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// 实例未设置权重时的默认权重
+	defaultInstanceWeight = 100
+)
+
+// AdaptiveWeightAssistant 自适应权重的辅助类，定期采样实例负载，调整自动心跳实例的权重或负载分数
+type AdaptiveWeightAssistant struct {
+	cfg config.AdaptiveWeightConfig
+	// 获取自动心跳的实例
+	listRegisters func() []*model.InstanceRegisterRequest
+	// 更新实例元数据
+	update func(req *model.InstanceMetadataUpdateRequest) error
+	// 负载采样器
+	mutex    sync.RWMutex
+	samplers []model.LoadSampler
+	// 实例的原始权重，仅在采样协程中访问
+	baseWeights map[string]int
+	// 实例上次上报的值，仅在采样协程中访问
+	lastValues map[string]string
+	done       chan struct{}
+	stopOnce   sync.Once
+}
+
+// Init 初始化，默认使用CPU及内存使用率作为负载
+func (a *AdaptiveWeightAssistant) Init(cfg config.Configuration, listRegisters func() []*model.InstanceRegisterRequest,
+	update func(req *model.InstanceMetadataUpdateRequest) error) {
+	a.cfg = cfg.GetProvider().GetAdaptiveWeight()
+	a.listRegisters = listRegisters
+	a.update = update
+	a.samplers = []model.LoadSampler{&cpuSampler{reader: metric.NewCPUReader()}, &memorySampler{}}
+	a.baseWeights = make(map[string]int)
+	a.lastValues = make(map[string]string)
+	a.done = make(chan struct{})
+}
+
+// AddSampler 添加负载采样器，实例负载取所有采样器的最大值
+func (a *AdaptiveWeightAssistant) AddSampler(sampler model.LoadSampler) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.samplers = append(a.samplers, sampler)
+}
+
+// Start 启用时启动采样协程
+func (a *AdaptiveWeightAssistant) Start() {
+	if !a.cfg.IsEnable() {
+		return
+	}
+	go a.run()
+}
+
+// Destroy 停止采样
+func (a *AdaptiveWeightAssistant) Destroy() {
+	a.stopOnce.Do(func() {
+		close(a.done)
+	})
+}
+
+func (a *AdaptiveWeightAssistant) run() {
+	ticker := time.NewTicker(a.cfg.GetInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.done:
+			return
+		case <-ticker.C:
+			load, ok := a.sampleLoad()
+			if !ok {
+				continue
+			}
+			a.adjust(load)
+		}
+	}
+}
+
+// sampleLoad 获取所有采样器中的最大负载
+func (a *AdaptiveWeightAssistant) sampleLoad() (float64, bool) {
+	a.mutex.RLock()
+	samplers := a.samplers
+	a.mutex.RUnlock()
+	var maxLoad float64
+	sampled := false
+	for _, sampler := range samplers {
+		load, ok := sampler.Sample()
+		if !ok {
+			continue
+		}
+		sampled = true
+		if load > maxLoad {
+			maxLoad = load
+		}
+	}
+	return maxLoad, sampled
+}
+
+// adjust 根据负载更新所有自动心跳实例
+func (a *AdaptiveWeightAssistant) adjust(load float64) {
+	alive := make(map[string]struct{})
+	for _, instance := range a.listRegisters() {
+		key := fmt.Sprintf("%s##%s##%s##%d", instance.Namespace, instance.Service, instance.Host, instance.Port)
+		alive[key] = struct{}{}
+		req := &model.InstanceMetadataUpdateRequest{
+			Namespace: instance.Namespace,
+			Service:   instance.Service,
+			Host:      instance.Host,
+			Port:      instance.Port,
+		}
+		var value string
+		if a.cfg.GetMode() == model.AdaptiveWeightModeMetadata {
+			value = strconv.FormatFloat(load, 'f', 2, 64)
+			req.Metadata = map[string]string{a.cfg.GetMetadataKey(): value}
+		} else {
+			weight := a.computeWeight(a.getBaseWeight(key, instance), load)
+			value = strconv.Itoa(weight)
+			req.SetWeight(weight)
+		}
+		if a.lastValues[key] == value {
+			continue
+		}
+		if err := a.update(req); err != nil {
+			log.GetBaseLogger().Warnf("[Provider][AdaptiveWeight] fail to update instance %s, err %v", key, err)
+			continue
+		}
+		a.lastValues[key] = value
+		log.GetBaseLogger().Debugf("[Provider][AdaptiveWeight] instance %s load %.2f, update %s to %s",
+			key, load, a.cfg.GetMode(), value)
+	}
+	for key := range a.baseWeights {
+		if _, ok := alive[key]; !ok {
+			delete(a.baseWeights, key)
+		}
+	}
+	for key := range a.lastValues {
+		if _, ok := alive[key]; !ok {
+			delete(a.lastValues, key)
+		}
+	}
+}
+
+// getBaseWeight 获取实例首次出现时的权重作为原始权重
+func (a *AdaptiveWeightAssistant) getBaseWeight(key string, instance *model.InstanceRegisterRequest) int {
+	if weight, ok := a.baseWeights[key]; ok {
+		return weight
+	}
+	weight := defaultInstanceWeight
+	if instance.Weight != nil {
+		weight = *instance.Weight
+	}
+	a.baseWeights[key] = weight
+	return weight
+}
+
+// computeWeight 负载超过阈值后按比例降低权重，满载时降至权重下限
+func (a *AdaptiveWeightAssistant) computeWeight(base int, load float64) int {
+	threshold := a.cfg.GetLoadThreshold()
+	if load <= threshold {
+		return base
+	}
+	weight := int(float64(base) * (1 - (load-threshold)/(1-threshold)))
+	if minWeight := a.cfg.GetMinWeight(); weight < minWeight {
+		weight = minWeight
+	}
+	if weight > base {
+		weight = base
+	}
+	return weight
+}
+
+// cpuSampler 基于CPU使用率的负载采样器
+type cpuSampler struct {
+	reader *metric.CPUReader
+}
+
+// Name 采样器名称
+func (s *cpuSampler) Name() string {
+	return "cpu"
+}
+
+// Sample 采样距上次采样的CPU使用率
+func (s *cpuSampler) Sample() (float64, bool) {
+	usage, ok := s.reader.Read()
+	if !ok {
+		return 0, false
+	}
+	return float64(usage) / 1000, true
+}
+
+// memorySampler 基于内存使用率的负载采样器
+type memorySampler struct{}
+
+// Name 采样器名称
+func (s *memorySampler) Name() string {
+	return "memory"
+}
+
+// Sample 采样当前内存使用率
+func (s *memorySampler) Sample() (float64, bool) {
+	usage, ok := metric.ReadMemoryUsage()
+	if !ok {
+		return 0, false
+	}
+	return float64(usage) / 1000, true
+}
@@ -0,0 +1,381 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/modern-go/reflect2"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+// adminServer 本地管理端口，以JSON格式输出SDK内部状态，用于排查请求路由的原因
+type adminServer struct {
+	engine *Engine
+	server *http.Server
+	ln     net.Listener
+}
+
+// sensitiveConfigKeys 输出配置时需要脱敏的字段关键字
+var sensitiveConfigKeys = []string{"token", "password", "secret", "privatekey"}
+
+// startAdminServer 启动本地管理端口
+func (e *Engine) startAdminServer() error {
+	adminCfg := e.configuration.GetGlobal().GetAdmin()
+	if !adminCfg.IsEnable() {
+		return nil
+	}
+	address := fmt.Sprintf("%s:%d", adminCfg.GetHost(), adminCfg.GetPort())
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to listen admin address %s", address)
+	}
+	admin := &adminServer{engine: e, ln: ln}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", admin.handleIndex)
+	mux.HandleFunc("/services", admin.handleServices)
+	mux.HandleFunc("/instances", admin.handleInstances)
+	mux.HandleFunc("/routing", admin.handleRouting)
+	mux.HandleFunc("/ratelimit", admin.handleRateLimit)
+	mux.HandleFunc("/config", admin.handleConfig)
+	mux.HandleFunc("/loglevel", admin.handleLogLevel)
+	mux.HandleFunc("/health", admin.handleHealth)
+	mux.HandleFunc("/diagnostics", admin.handleDiagnostics)
+	admin.server = &http.Server{Handler: mux}
+	e.admin = admin
+	go func() {
+		log.GetBaseLogger().Infof("[Admin] admin server started at %s", ln.Addr().String())
+		if err := admin.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.GetBaseLogger().Errorf("[Admin] admin server stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// stop 关闭管理端口
+func (a *adminServer) stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = a.server.Shutdown(ctx)
+}
+
+// handleIndex 列出所有支持的路径
+func (a *adminServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	writeAdminJSON(w, map[string]string{
+		"/services":    "cached services and rules with revisions",
+		"/instances":   "instances with circuit breaker status, ?namespace=&service=",
+		"/routing":     "active routing rule with revision, ?namespace=&service=",
+		"/ratelimit":   "rate limit windows and quota usage",
+		"/config":      "effective sdk configuration",
+		"/loglevel":    "log level of each logger module, POST ?module=&level= to change at runtime",
+		"/health":      "sdk self metrics: cache size, pending discover requests, reconnects and task queues",
+		"/diagnostics": "sampled slow or failed calls with routing trail, DELETE to clear",
+	})
+}
+
+// adminCacheKey 本地缓存中的资源
+type adminCacheKey struct {
+	Namespace string `json:"namespace"`
+	Service   string `json:"service"`
+	Type      string `json:"type"`
+	Revision  string `json:"revision"`
+}
+
+// handleServices 输出本地缓存的服务及规则
+func (a *adminServer) handleServices(w http.ResponseWriter, r *http.Request) {
+	inspector, ok := localregistry.GetCacheInspector(a.engine.registry)
+	if !ok {
+		http.Error(w, "local registry does not support cache inspection", http.StatusNotImplemented)
+		return
+	}
+	keys := inspector.ListCachedKeys()
+	result := make([]adminCacheKey, 0, len(keys))
+	for _, key := range keys {
+		svcKey := key.ServiceKey
+		cacheKey := adminCacheKey{Namespace: key.Namespace, Service: key.Service, Type: key.Type.String()}
+		if key.Type == model.EventInstances {
+			if instances := a.engine.registry.GetInstances(&svcKey, false, true); !reflect2.IsNil(instances) {
+				cacheKey.Revision = instances.GetRevision()
+			}
+		} else if rule := a.engine.registry.GetServiceRule(&key, false); !reflect2.IsNil(rule) {
+			cacheKey.Revision = rule.GetRevision()
+		}
+		result = append(result, cacheKey)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		if result[i].Service != result[j].Service {
+			return result[i].Service < result[j].Service
+		}
+		return result[i].Type < result[j].Type
+	})
+	writeAdminJSON(w, result)
+}
+
+// adminInstance 实例及其熔断状态
+type adminInstance struct {
+	ID             string            `json:"id"`
+	Host           string            `json:"host"`
+	Port           uint32            `json:"port"`
+	Weight         int               `json:"weight"`
+	Healthy        bool              `json:"healthy"`
+	Isolated       bool              `json:"isolated"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	CircuitBreaker string            `json:"circuitBreaker,omitempty"`
+	BreakerStatus  string            `json:"breakerStatus"`
+	StatusSince    *time.Time        `json:"statusSince,omitempty"`
+}
+
+// handleInstances 输出服务实例及熔断状态
+func (a *adminServer) handleInstances(w http.ResponseWriter, r *http.Request) {
+	svcKey, ok := parseAdminServiceKey(w, r)
+	if !ok {
+		return
+	}
+	instances := a.engine.registry.GetInstances(svcKey, false, true)
+	if reflect2.IsNil(instances) || !instances.IsInitialized() {
+		http.Error(w, fmt.Sprintf("service %s not found in local cache", svcKey), http.StatusNotFound)
+		return
+	}
+	result := make([]adminInstance, 0, len(instances.GetInstances()))
+	for _, instance := range instances.GetInstances() {
+		item := adminInstance{
+			ID:            instance.GetId(),
+			Host:          instance.GetHost(),
+			Port:          instance.GetPort(),
+			Weight:        instance.GetWeight(),
+			Healthy:       instance.IsHealthy(),
+			Isolated:      instance.IsIsolated(),
+			Metadata:      instance.GetMetadata(),
+			BreakerStatus: model.Close.String(),
+		}
+		if cbStatus := instance.GetCircuitBreakerStatus(); !reflect2.IsNil(cbStatus) {
+			startTime := cbStatus.GetStartTime()
+			item.CircuitBreaker = cbStatus.GetCircuitBreaker()
+			item.BreakerStatus = cbStatus.GetStatus().String()
+			item.StatusSince = &startTime
+		}
+		result = append(result, item)
+	}
+	writeAdminJSON(w, map[string]interface{}{
+		"namespace": svcKey.Namespace,
+		"service":   svcKey.Service,
+		"revision":  instances.GetRevision(),
+		"instances": result,
+	})
+}
+
+// handleRouting 输出服务当前生效的路由规则
+func (a *adminServer) handleRouting(w http.ResponseWriter, r *http.Request) {
+	svcKey, ok := parseAdminServiceKey(w, r)
+	if !ok {
+		return
+	}
+	rule := a.engine.registry.GetServiceRouteRule(svcKey, false)
+	if reflect2.IsNil(rule) || !rule.IsInitialized() {
+		http.Error(w, fmt.Sprintf("routing of %s not found in local cache", svcKey), http.StatusNotFound)
+		return
+	}
+	var ruleValue json.RawMessage
+	if msg, ok := rule.GetValue().(proto.Message); ok && !reflect2.IsNil(msg) {
+		text, err := (&jsonpb.Marshaler{}).MarshalToString(msg)
+		if err == nil {
+			ruleValue = json.RawMessage(text)
+		}
+	}
+	result := map[string]interface{}{
+		"namespace": svcKey.Namespace,
+		"service":   svcKey.Service,
+		"revision":  rule.GetRevision(),
+		"rule":      ruleValue,
+	}
+	if err := rule.GetValidateError(); err != nil {
+		result["validateError"] = err.Error()
+	}
+	writeAdminJSON(w, result)
+}
+
+// adminRateLimitWindow 限流窗口的配额使用情况
+type adminRateLimitWindow struct {
+	Namespace      string                   `json:"namespace"`
+	Service        string                   `json:"service"`
+	RuleID         string                   `json:"ruleId"`
+	Revision       string                   `json:"revision"`
+	Labels         string                   `json:"labels,omitempty"`
+	Mode           string                   `json:"mode"`
+	Amounts        []map[string]interface{} `json:"amounts"`
+	Passed         uint64                   `json:"passed"`
+	Limited        uint64                   `json:"limited"`
+	LastAccessTime time.Time                `json:"lastAccessTime"`
+}
+
+// handleRateLimit 输出限流窗口及配额使用情况
+func (a *adminServer) handleRateLimit(w http.ResponseWriter, r *http.Request) {
+	result := make([]adminRateLimitWindow, 0)
+	for _, windowSet := range a.engine.flowQuotaAssistant.GetAllWindowSets() {
+		for _, window := range windowSet.GetRateLimitWindows() {
+			item := adminRateLimitWindow{
+				Namespace:      window.SvcKey.Namespace,
+				Service:        window.SvcKey.Service,
+				RuleID:         window.Rule.GetId().GetValue(),
+				Revision:       window.Rule.GetRevision().GetValue(),
+				Labels:         window.Labels,
+				Mode:           "local",
+				LastAccessTime: time.Unix(0, window.GetLastAccessTimeMilli()*int64(time.Millisecond)),
+			}
+			if window.GetConfigMode() == model.ConfigQuotaGlobalMode {
+				item.Mode = "global"
+			}
+			for _, amount := range window.GetAmountInfos() {
+				item.Amounts = append(item.Amounts, map[string]interface{}{
+					"validDuration": fmt.Sprintf("%ds", amount.ValidDuration),
+					"maxAmount":     amount.MaxAmount,
+				})
+			}
+			item.Passed, item.Limited = window.GetUsage()
+			result = append(result, item)
+		}
+	}
+	writeAdminJSON(w, result)
+}
+
+// handleConfig 输出当前生效的配置，敏感字段会被脱敏
+func (a *adminServer) handleConfig(w http.ResponseWriter, r *http.Request) {
+	data, err := json.Marshal(a.engine.configuration)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var values map[string]interface{}
+	if err = json.Unmarshal(data, &values); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	redactConfigValues(values)
+	writeAdminJSON(w, values)
+}
+
+// handleHealth 输出 SDK 自身的运行指标
+func (a *adminServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, a.engine.collectHealthGauge())
+}
+
+// handleDiagnostics 输出采样的慢调用及失败调用，DELETE 请求时清空记录
+func (a *adminServer) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
+	assistant := a.engine.diagnosticsAssistant
+	switch r.Method {
+	case http.MethodGet:
+		writeAdminJSON(w, map[string]interface{}{
+			"enable":  assistant.IsEnable(),
+			"samples": assistant.Samples(),
+		})
+	case http.MethodDelete:
+		assistant.Clear()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleLogLevel 查看各日志模块的打印级别，POST 请求时调整指定模块的级别，module 为 * 时调整所有模块
+func (a *adminServer) handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodPut:
+		module := r.URL.Query().Get("module")
+		level := r.URL.Query().Get("level")
+		if module == "" || level == "" {
+			http.Error(w, "module and level are required", http.StatusBadRequest)
+			return
+		}
+		if err := log.ApplyModuleLogLevels(module + "=" + level); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Logw(log.GetBaseLogger(), log.InfoLog, "[Admin] log level changed", "module", module, "level", level)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeAdminJSON(w, log.GetModuleLogLevels())
+}
+
+// redactConfigValues 对配置中的敏感字段进行脱敏
+func redactConfigValues(values map[string]interface{}) {
+	for key, value := range values {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			redactConfigValues(v)
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					redactConfigValues(m)
+				}
+			}
+		case string:
+			lowerKey := strings.ToLower(key)
+			for _, sensitive := range sensitiveConfigKeys {
+				if len(v) > 0 && strings.Contains(lowerKey, sensitive) {
+					values[key] = "******"
+					break
+				}
+			}
+		}
+	}
+}
+
+// parseAdminServiceKey 从请求参数中解析服务标识
+func parseAdminServiceKey(w http.ResponseWriter, r *http.Request) (*model.ServiceKey, bool) {
+	svcKey := &model.ServiceKey{
+		Namespace: r.URL.Query().Get("namespace"),
+		Service:   r.URL.Query().Get("service"),
+	}
+	if len(svcKey.Namespace) == 0 || len(svcKey.Service) == 0 {
+		http.Error(w, "namespace and service are required", http.StatusBadRequest)
+		return nil, false
+	}
+	return svcKey, true
+}
+
+// writeAdminJSON 以JSON格式输出应答
+func writeAdminJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		log.GetBaseLogger().Errorf("[Admin] fail to write response: %v", err)
+	}
+}
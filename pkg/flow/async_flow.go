@@ -26,6 +26,15 @@ import (
 
 // AsyncGetQuota 异步获取配额信息
 func (e *Engine) AsyncGetQuota(request *model.QuotaRequestImpl) (*model.QuotaFutureImpl, error) {
+	resp, err := e.interceptors.invoke(model.ApiGetQuota, request, func(inv *model.Invocation) (interface{}, error) {
+		return e.asyncGetQuota(inv.Request.(*model.QuotaRequestImpl))
+	})
+	future, _ := resp.(*model.QuotaFutureImpl)
+	return future, err
+}
+
+// asyncGetQuota 异步获取配额信息
+func (e *Engine) asyncGetQuota(request *model.QuotaRequestImpl) (*model.QuotaFutureImpl, error) {
 	commonRequest := data.PoolGetCommonRateLimitRequest()
 	commonRequest.InitByGetQuotaRequest(request, e.configuration)
 	startTime := model.CurrentMillisecond()
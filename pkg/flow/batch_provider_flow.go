@@ -0,0 +1,91 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// 默认批量注册及心跳的并发请求数
+	defaultBatchProviderConcurrency = 16
+)
+
+// SyncBatchRegister 并发注册多个实例，请求复用连接器的同一连接，单个实例失败不影响其他实例
+func (e *Engine) SyncBatchRegister(req *model.BatchRegisterRequest) *model.BatchRegisterResponse {
+	resp := &model.BatchRegisterResponse{Results: make([]*model.InstanceRegisterResult, len(req.Instances))}
+	runBatch(len(req.Instances), req.Concurrency, func(idx int) {
+		instance := req.Instances[idx]
+		result := &model.InstanceRegisterResult{Instance: instance}
+		resp.Results[idx] = result
+		if result.Err = instance.Validate(); result.Err != nil {
+			return
+		}
+		result.Response, result.Err = e.SyncRegister(instance)
+	})
+	if failed := resp.Failed(); len(failed) > 0 {
+		log.GetBaseLogger().Warnf("[Provider][BatchRegister] %d of %d instances failed to register",
+			len(failed), len(req.Instances))
+	}
+	return resp
+}
+
+// SyncBatchHeartbeat 并发上报多个实例的心跳，单个实例失败不影响其他实例
+func (e *Engine) SyncBatchHeartbeat(req *model.BatchHeartbeatRequest) *model.BatchHeartbeatResponse {
+	resp := &model.BatchHeartbeatResponse{Results: make([]*model.InstanceHeartbeatResult, len(req.Instances))}
+	runBatch(len(req.Instances), req.Concurrency, func(idx int) {
+		instance := req.Instances[idx]
+		result := &model.InstanceHeartbeatResult{Instance: instance}
+		resp.Results[idx] = result
+		if result.Err = instance.Validate(); result.Err != nil {
+			return
+		}
+		result.Err = e.SyncHeartbeat(instance)
+	})
+	if failed := resp.Failed(); len(failed) > 0 {
+		log.GetBaseLogger().Warnf("[Provider][BatchHeartbeat] %d of %d instances failed to heartbeat",
+			len(failed), len(req.Instances))
+	}
+	return resp
+}
+
+// runBatch 以指定并发数执行count个任务，全部完成后返回
+func runBatch(count int, concurrency int, task func(idx int)) {
+	if concurrency == 0 {
+		concurrency = defaultBatchProviderConcurrency
+	}
+	indexes := make(chan int, count)
+	for i := 0; i < count; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < concurrency && i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				task(idx)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/polarismesh/polaris-go/pkg/dispatcher"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
@@ -37,6 +38,16 @@ func (e *Engine) Report(reportStat *model.ResourceStat) error {
 	return e.circuitBreakerFlow.Report(reportStat)
 }
 
+// AddCircuitBreakerStatusListener 添加熔断状态监听器
+func (e *Engine) AddCircuitBreakerStatusListener(listener model.CircuitBreakerStatusListener) error {
+	return e.circuitBreakerFlow.AddStatusListener(listener)
+}
+
+// DoWithCircuitBreaker 在熔断保护下执行业务逻辑
+func (e *Engine) DoWithCircuitBreaker(resource model.Resource, fn func() error, fallback model.FallbackFunction) error {
+	return e.circuitBreakerFlow.DoWithCircuitBreaker(resource, fn, fallback)
+}
+
 // MakeFunctionDecorator
 func (e *Engine) MakeFunctionDecorator(f model.CustomerFunction, reqCtx *model.RequestContext) model.DecoratorFunction {
 	return e.circuitBreakerFlow.MakeFunctionDecorator(f, reqCtx)
@@ -48,25 +59,46 @@ func (e *Engine) MakeInvokeHandler(reqCtx *model.RequestContext) model.InvokeHan
 }
 
 type CircuitBreakerFlow struct {
-	engine          *Engine
-	resourceBreaker circuitbreaker.CircuitBreaker
+	engine           *Engine
+	resourceBreakers []circuitbreaker.CircuitBreaker
 }
 
-func newCircuitBreakerFlow(e *Engine, breaker circuitbreaker.CircuitBreaker) *CircuitBreakerFlow {
+func newCircuitBreakerFlow(e *Engine, breakers []circuitbreaker.CircuitBreaker) *CircuitBreakerFlow {
 	return &CircuitBreakerFlow{
-		engine:          e,
-		resourceBreaker: breaker,
+		engine:           e,
+		resourceBreakers: breakers,
 	}
 }
 
 func (e *CircuitBreakerFlow) Check(resource model.Resource) (*model.CheckResult, error) {
-	if e.resourceBreaker == nil {
+	if len(e.resourceBreakers) == 0 {
 		return nil, model.NewSDKError(model.ErrCodeInternalError, nil, "circuitbreaker not found")
 	}
 
-	status := e.resourceBreaker.CheckResource(resource)
-	if status != nil {
-		return circuitBreakerStatusToResult(status), nil
+	// 按照熔断链顺序检查，任一熔断器处于打开状态则直接返回
+	var passStatus model.CircuitBreakerStatus
+	for _, breaker := range e.resourceBreakers {
+		status := breaker.CheckResource(resource)
+		if status == nil {
+			continue
+		}
+		if status.GetStatus() == model.Open {
+			return circuitBreakerStatusToResult(status), nil
+		}
+		// 半开逐级放量，超出当前放量比例的请求直接拒绝
+		if halfOpen, ok := status.(*model.HalfOpenStatus); ok && !halfOpen.Allow() {
+			return &model.CheckResult{
+				Pass:         false,
+				RuleName:     status.GetCircuitBreaker(),
+				FallbackInfo: status.GetFallbackInfo(),
+			}, nil
+		}
+		if passStatus == nil {
+			passStatus = status
+		}
+	}
+	if passStatus != nil {
+		return circuitBreakerStatusToResult(passStatus), nil
 	}
 
 	return &model.CheckResult{
@@ -93,10 +125,94 @@ func circuitBreakerStatusToResult(breakerStatus model.CircuitBreakerStatus) *mod
 }
 
 func (e *CircuitBreakerFlow) Report(reportStat *model.ResourceStat) error {
-	if e.resourceBreaker == nil {
+	if len(e.resourceBreakers) == 0 {
 		return model.NewSDKError(model.ErrCodeInternalError, nil, "circuitbreaker not found")
 	}
-	return e.resourceBreaker.Report(reportStat)
+	var lastErr error
+	for _, breaker := range e.resourceBreakers {
+		if err := breaker.Report(reportStat); err != nil {
+			log.GetBaseLogger().Errorf("[CircuitBreaker] report to %s fail, resource %s, err %v",
+				breaker.Name(), reportStat.Resource.String(), err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// AddStatusListener 将监听器注册到熔断链上所有支持状态监听的熔断器，
+// 回调经监听器分发协程池执行，用户回调阻塞或panic不影响熔断状态变更
+func (e *CircuitBreakerFlow) AddStatusListener(listener model.CircuitBreakerStatusListener) error {
+	if e == nil || len(e.resourceBreakers) == 0 {
+		return model.NewSDKError(model.ErrCodeInternalError, nil, "circuitbreaker not found")
+	}
+	var pool *dispatcher.Pool
+	if e.engine != nil {
+		pool = e.engine.listenerDispatcher
+	}
+	dispatched := &dispatchedStatusListener{
+		key:        dispatcher.NextKey(),
+		dispatcher: pool,
+		listener:   listener,
+	}
+	for _, breaker := range e.resourceBreakers {
+		if observable, ok := circuitbreaker.GetStatusObservable(breaker); ok {
+			observable.AddStatusListener(dispatched)
+		}
+	}
+	return nil
+}
+
+// dispatchedStatusListener 通过分发协程池回调的熔断状态监听器，同一监听器的回调按状态变更顺序执行
+type dispatchedStatusListener struct {
+	key        uint64
+	dispatcher *dispatcher.Pool
+	listener   model.CircuitBreakerStatusListener
+}
+
+// OnStatusChange 资源熔断状态变更时回调
+func (d *dispatchedStatusListener) OnStatusChange(resource model.Resource, previous model.CircuitBreakerStatus,
+	current model.CircuitBreakerStatus) {
+	d.dispatcher.Dispatch(d.key, resource.String(), func() {
+		d.listener.OnStatusChange(resource, previous, current)
+	})
+}
+
+// DoWithCircuitBreaker 检查资源熔断状态，放通时执行fn并上报耗时及结果，
+// 被熔断时执行fallback，未设置fallback则返回熔断错误；熔断检查本身失败时不阻断业务调用
+func (e *CircuitBreakerFlow) DoWithCircuitBreaker(resource model.Resource, fn func() error,
+	fallback model.FallbackFunction) error {
+	if resource == nil || fn == nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "resource and function are required")
+	}
+	result, err := e.Check(resource)
+	if err != nil {
+		log.GetBaseLogger().Warnf("[CircuitBreaker] check resource %s fail, skip circuitbreaker, err %v",
+			resource.String(), err)
+		return fn()
+	}
+	if !result.Pass {
+		if fallback != nil {
+			return fallback(result)
+		}
+		return model.NewSDKError(model.ErrCodeCircuitBreakerError, model.ErrorCallAborted,
+			"resource %s is circuit broken by rule %s", resource.String(), result.RuleName)
+	}
+	start := time.Now()
+	fnErr := fn()
+	stat := &model.ResourceStat{
+		Resource:  resource,
+		RetCode:   "0",
+		Delay:     time.Since(start),
+		RetStatus: model.RetSuccess,
+	}
+	if fnErr != nil {
+		stat.RetCode = "-1"
+		stat.RetStatus = model.RetFail
+	}
+	if err := e.Report(stat); err != nil {
+		log.GetBaseLogger().Errorf("[CircuitBreaker] report resource %s fail, err %v", resource.String(), err)
+	}
+	return fnErr
 }
 
 func (e *CircuitBreakerFlow) MakeFunctionDecorator(f model.CustomerFunction, reqCtx *model.RequestContext) model.DecoratorFunction {
@@ -151,7 +267,7 @@ func (df *DefaultFunctionalDecorator) Decorator(ctx context.Context, args interf
 				Duration: delay,
 				Result:   ret,
 			}
-			invoke.OnError(rspCtx)
+			invoke.OnSuccess(rspCtx)
 		}
 	}()
 
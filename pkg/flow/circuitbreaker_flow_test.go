@@ -0,0 +1,176 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package flow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/dispatcher"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
+)
+
+// recordInvokeHandler 记录装饰器的熔断检查及结果回调
+type recordInvokeHandler struct {
+	pass       bool
+	aborted    *model.CallAborted
+	acquireErr error
+	successes  []*model.ResponseContext
+	errors     []*model.ResponseContext
+}
+
+func (h *recordInvokeHandler) AcquirePermission() (bool, *model.CallAborted, error) {
+	return h.pass, h.aborted, h.acquireErr
+}
+
+func (h *recordInvokeHandler) OnSuccess(respCtx *model.ResponseContext) {
+	h.successes = append(h.successes, respCtx)
+}
+
+func (h *recordInvokeHandler) OnError(respCtx *model.ResponseContext) {
+	h.errors = append(h.errors, respCtx)
+}
+
+// TestFunctionalDecorator 测试装饰器按业务函数的结果回调OnSuccess或OnError，成功的调用不能被记为失败
+func TestFunctionalDecorator(t *testing.T) {
+	errCall := errors.New("call fail")
+	errCheck := errors.New("check fail")
+	aborted := model.NewCallAborted(model.ErrorCallAborted, "rule", nil)
+	tests := []struct {
+		name          string
+		handler       *recordInvokeHandler
+		ret           interface{}
+		callErr       error
+		wantRet       interface{}
+		wantAborted   *model.CallAborted
+		wantErr       error
+		wantCalled    bool
+		wantSuccesses int
+		wantErrors    int
+	}{
+		{
+			name:          "调用成功回调OnSuccess",
+			handler:       &recordInvokeHandler{pass: true},
+			ret:           "ok",
+			wantRet:       "ok",
+			wantCalled:    true,
+			wantSuccesses: 1,
+		},
+		{
+			name:       "调用失败回调OnError",
+			handler:    &recordInvokeHandler{pass: true},
+			callErr:    errCall,
+			wantErr:    errCall,
+			wantCalled: true,
+			wantErrors: 1,
+		},
+		{
+			name:        "被熔断时不调用业务函数也不上报",
+			handler:     &recordInvokeHandler{aborted: aborted},
+			wantAborted: aborted,
+			wantErr:     model.ErrorCallAborted,
+		},
+		{
+			name:    "熔断检查失败时直接返回错误",
+			handler: &recordInvokeHandler{pass: true, acquireErr: errCheck},
+			wantErr: errCheck,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			decorator := &DefaultFunctionalDecorator{
+				invoke: tt.handler,
+				customerFunc: func(ctx context.Context, args interface{}) (interface{}, error) {
+					called = true
+					assert.Equal(t, "args", args)
+					return tt.ret, tt.callErr
+				},
+			}
+			ret, gotAborted, err := decorator.Decorator(context.Background(), "args")
+			assert.Equal(t, tt.wantRet, ret)
+			assert.Same(t, tt.wantAborted, gotAborted)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantCalled, called)
+			assert.Len(t, tt.handler.successes, tt.wantSuccesses)
+			assert.Len(t, tt.handler.errors, tt.wantErrors)
+			for _, respCtx := range tt.handler.successes {
+				assert.Equal(t, tt.ret, respCtx.Result)
+				assert.Nil(t, respCtx.Err)
+			}
+			for _, respCtx := range tt.handler.errors {
+				assert.Equal(t, tt.callErr, respCtx.Err)
+			}
+		})
+	}
+}
+
+// observableCircuitBreaker 记录注册到熔断器的状态监听器
+type observableCircuitBreaker struct {
+	circuitbreaker.CircuitBreaker
+	listeners []model.CircuitBreakerStatusListener
+}
+
+func (o *observableCircuitBreaker) AddStatusListener(listener model.CircuitBreakerStatusListener) {
+	o.listeners = append(o.listeners, listener)
+}
+
+// blockingStatusListener 回调时阻塞直到release关闭，记录收到的状态
+type blockingStatusListener struct {
+	release  chan struct{}
+	statuses chan model.Status
+}
+
+func (b *blockingStatusListener) OnStatusChange(resource model.Resource, previous model.CircuitBreakerStatus,
+	current model.CircuitBreakerStatus) {
+	<-b.release
+	b.statuses <- current.GetStatus()
+}
+
+// TestAddStatusListenerDispatch 测试熔断状态监听器经分发协程池回调，阻塞的用户回调不阻塞熔断器，且按顺序回调
+func TestAddStatusListenerDispatch(t *testing.T) {
+	pool := dispatcher.NewPool("test", 1, 8, dispatcher.OverflowBlock)
+	defer pool.Destroy()
+	breaker := &observableCircuitBreaker{}
+	e := &Engine{listenerDispatcher: pool}
+	e.circuitBreakerFlow = newCircuitBreakerFlow(e, []circuitbreaker.CircuitBreaker{breaker})
+	listener := &blockingStatusListener{release: make(chan struct{}), statuses: make(chan model.Status, 2)}
+	assert.Nil(t, e.AddCircuitBreakerStatusListener(listener))
+	assert.Len(t, breaker.listeners, 1)
+
+	resource, err := model.NewServiceResource(&model.ServiceKey{Namespace: "Test", Service: "svc"}, nil)
+	assert.Nil(t, err)
+	notified := make(chan struct{})
+	go func() {
+		breaker.listeners[0].OnStatusChange(resource, nil, model.NewCircuitBreakerStatus("rule", model.Open, time.Now()))
+		breaker.listeners[0].OnStatusChange(resource, nil, model.NewCircuitBreakerStatus("rule", model.Close, time.Now()))
+		close(notified)
+	}()
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		t.Fatal("status change should not wait for user listener")
+	}
+	close(listener.release)
+	assert.Equal(t, model.Open, <-listener.statuses)
+	assert.Equal(t, model.Close, <-listener.statuses)
+}
@@ -0,0 +1,214 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/dispatcher"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/flow/reload"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// defaultRouting 默认的服务路由责任链及负载均衡器，被调服务未单独配置时使用
+type defaultRouting struct {
+	routerChain  *servicerouter.RouterChain
+	loadbalancer loadbalancer.LoadBalancer
+}
+
+// configChangedListener 带分发标识的SDK配置热更新监听器
+type configChangedListener struct {
+	key      uint64
+	listener model.SDKConfigChangedListener
+}
+
+// AddSDKConfigChangedListener 添加SDK自身配置热更新监听器
+func (e *Engine) AddSDKConfigChangedListener(listener model.SDKConfigChangedListener) {
+	if listener == nil {
+		return
+	}
+	e.configReloadMutex.Lock()
+	defer e.configReloadMutex.Unlock()
+	e.configChangedListeners = append(e.configChangedListeners,
+		configChangedListener{key: dispatcher.NextKey(), listener: listener})
+}
+
+// applyReloadedConfig 将新配置中可安全变更的配置项应用到运行时，新的路由链或负载均衡器无法构建时整体不生效
+func (e *Engine) applyReloadedConfig(source string, newCfg config.Configuration) error {
+	e.configReloadMutex.Lock()
+	defer e.configReloadMutex.Unlock()
+	oldAPI, newAPI := e.configuration.GetGlobal().GetAPI(), newCfg.GetGlobal().GetAPI()
+	oldRouter, newRouter := e.configuration.GetConsumer().GetServiceRouter(), newCfg.GetConsumer().GetServiceRouter()
+	oldLB, newLB := e.configuration.GetConsumer().GetLoadbalancer(), newCfg.GetConsumer().GetLoadbalancer()
+	oldCB, newCB := e.configuration.GetConsumer().GetCircuitBreaker(), newCfg.GetConsumer().GetCircuitBreaker()
+	oldReload, newReload := e.configuration.GetGlobal().GetConfigReload(), newCfg.GetGlobal().GetConfigReload()
+
+	var changes []model.SDKConfigChange
+	diff := func(key string, oldValue, newValue interface{}) bool {
+		oldStr, newStr := formatConfigValue(oldValue), formatConfigValue(newValue)
+		if oldStr == newStr {
+			return false
+		}
+		changes = append(changes, model.SDKConfigChange{Key: key, OldValue: oldStr, NewValue: newStr})
+		return true
+	}
+	timeoutChanged := diff("global.api.timeout", oldAPI.GetTimeout(), newAPI.GetTimeout())
+	retryTimesChanged := diff("global.api.maxRetryTimes", oldAPI.GetMaxRetryTimes(), newAPI.GetMaxRetryTimes())
+	retryIntervalChanged := diff("global.api.retryInterval", oldAPI.GetRetryInterval(), newAPI.GetRetryInterval())
+	routerChanged := diff("consumer.serviceRouter.chain", oldRouter.GetChain(), newRouter.GetChain())
+	lbChanged := diff("consumer.loadbalancer.type", oldLB.GetType(), newLB.GetType())
+	sleepWindowChanged := diff("consumer.circuitBreaker.sleepWindow", oldCB.GetSleepWindow(), newCB.GetSleepWindow())
+	requestCountChanged := diff("consumer.circuitBreaker.requestCountAfterHalfOpen",
+		oldCB.GetRequestCountAfterHalfOpen(), newCB.GetRequestCountAfterHalfOpen())
+	successCountChanged := diff("consumer.circuitBreaker.successCountAfterHalfOpen",
+		oldCB.GetSuccessCountAfterHalfOpen(), newCB.GetSuccessCountAfterHalfOpen())
+	rampChanged := diff("consumer.circuitBreaker.halfOpenRamp", oldCB.GetHalfOpenRamp(), newCB.GetHalfOpenRamp())
+	rampWindowChanged := diff("consumer.circuitBreaker.halfOpenRampWindow",
+		oldCB.GetHalfOpenRampWindow(), newCB.GetHalfOpenRampWindow())
+	logLevelsChanged := diff("global.configReload.logLevels", oldReload.GetLogLevels(), newReload.GetLogLevels())
+	if len(changes) == 0 {
+		log.GetBaseLogger().Debugf("[ConfigReload] no reloadable config changed from %s", source)
+		return nil
+	}
+
+	// 先校验，全部通过后再生效
+	current := e.defaultRouting.Load().(*defaultRouting)
+	routing := &defaultRouting{routerChain: current.routerChain, loadbalancer: current.loadbalancer}
+	var err error
+	if routerChanged {
+		if routing.routerChain, err = data.GetServiceRouterChain(newCfg, e.plugins); err != nil {
+			return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "invalid consumer.serviceRouter.chain")
+		}
+	}
+	if lbChanged {
+		if routing.loadbalancer, err = data.GetLoadBalancer(newCfg, e.plugins); err != nil {
+			return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "invalid consumer.loadbalancer.type")
+		}
+	}
+	if logLevelsChanged {
+		for module, level := range newReload.GetLogLevels() {
+			if _, err = log.ParseLogLevel(level); err != nil {
+				return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
+					"invalid global.configReload.logLevels of module %s", module)
+			}
+		}
+	}
+
+	if logLevelsChanged {
+		if err = reload.ApplyLogLevels(newReload.GetLogLevels()); err != nil {
+			log.GetBaseLogger().Errorf("[ConfigReload] fail to apply log levels, err %v", err)
+		}
+		oldReload.SetLogLevels(newReload.GetLogLevels())
+	}
+
+	if timeoutChanged {
+		oldAPI.SetTimeout(newAPI.GetTimeout())
+	}
+	if retryTimesChanged {
+		oldAPI.SetMaxRetryTimes(newAPI.GetMaxRetryTimes())
+	}
+	if retryIntervalChanged {
+		oldAPI.SetRetryInterval(newAPI.GetRetryInterval())
+	}
+	if routerChanged {
+		oldRouter.SetChain(newRouter.GetChain())
+	}
+	if lbChanged {
+		oldLB.SetType(newLB.GetType())
+	}
+	if routerChanged || lbChanged {
+		e.defaultRouting.Store(routing)
+	}
+	if sleepWindowChanged {
+		oldCB.SetSleepWindow(newCB.GetSleepWindow())
+	}
+	if requestCountChanged {
+		oldCB.SetRequestCountAfterHalfOpen(newCB.GetRequestCountAfterHalfOpen())
+	}
+	if successCountChanged {
+		oldCB.SetSuccessCountAfterHalfOpen(newCB.GetSuccessCountAfterHalfOpen())
+	}
+	if rampChanged {
+		oldCB.SetHalfOpenRamp(newCB.GetHalfOpenRamp())
+	}
+	if rampWindowChanged {
+		oldCB.SetHalfOpenRampWindow(newCB.GetHalfOpenRampWindow())
+	}
+
+	event := &model.SDKConfigChangedEvent{Source: source, Changes: changes}
+	e.notifyConfigChanged(event)
+	return nil
+}
+
+// notifyConfigChanged 通知插件、治理事件上报插件及用户监听器SDK配置已变更
+func (e *Engine) notifyConfigChanged(event *model.SDKConfigChangedEvent) {
+	keys := make([]string, 0, len(event.Changes))
+	for _, change := range event.Changes {
+		keys = append(keys, change.Key)
+		log.GetBaseLogger().Infof("[ConfigReload] %s changed from %s to %s, source %s",
+			change.Key, change.OldValue, change.NewValue, event.Source)
+	}
+	pluginEvent := &common.PluginEvent{EventType: common.OnConfigReloaded, EventObject: event}
+	for _, handler := range e.plugins.GetEventSubscribers(common.OnConfigReloaded) {
+		if err := handler.Callback(pluginEvent); err != nil {
+			log.GetBaseLogger().Errorf("[ConfigReload] fail to handle config reloaded event, err %v", err)
+		}
+	}
+	govEvent := model.NewGovernanceEvent(model.EventConfigChanged, nil)
+	govEvent.CurrentStatus = strings.Join(keys, ",")
+	govEvent.Reason = event.Source
+	_ = e.SyncReportEvent(govEvent)
+	for _, l := range e.configChangedListeners {
+		listener := l.listener
+		e.listenerDispatcher.Dispatch(l.key, "configReload", func() {
+			listener(event)
+		})
+	}
+}
+
+// formatConfigValue 将配置项的值格式化为便于比较和展示的字符串
+func formatConfigValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Duration:
+		return v.String()
+	case []string:
+		return strings.Join(v, ",")
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			pairs = append(pairs, k+"="+v[k])
+		}
+		return strings.Join(pairs, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
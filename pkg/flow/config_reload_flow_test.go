@@ -0,0 +1,177 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+)
+
+// reloadLoadBalancer 按名称区分的负载均衡器
+type reloadLoadBalancer struct {
+	loadbalancer.LoadBalancer
+	name string
+}
+
+// reloadSupplier 只提供负载均衡器及配置热更新事件订阅者的插件仓库
+type reloadSupplier struct {
+	plugin.Supplier
+	lbs      map[string]loadbalancer.LoadBalancer
+	handlers []common.PluginEventHandler
+}
+
+func (s *reloadSupplier) GetPlugin(typ common.Type, name string) (plugin.Plugin, error) {
+	if lb, ok := s.lbs[name]; ok && typ == common.TypeLoadBalancer {
+		return lb, nil
+	}
+	return nil, fmt.Errorf("plugin %s not found", name)
+}
+
+func (s *reloadSupplier) GetEventSubscribers(event common.PluginEventType) []common.PluginEventHandler {
+	if event != common.OnConfigReloaded {
+		return nil
+	}
+	return s.handlers
+}
+
+func TestApplyReloadedConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		// modify 修改新配置
+		modify      func(cfg config.Configuration)
+		wantErr     bool
+		wantKeys    []string
+		wantTimeout time.Duration
+		wantLB      string
+	}{
+		{
+			name: "可安全变更的配置项生效",
+			modify: func(cfg config.Configuration) {
+				cfg.GetGlobal().GetAPI().SetTimeout(3 * time.Second)
+				cfg.GetConsumer().GetLoadbalancer().SetType(config.DefaultLoadBalancerRingHash)
+				cfg.GetConsumer().GetCircuitBreaker().SetSleepWindow(time.Minute)
+			},
+			wantKeys: []string{"global.api.timeout", "consumer.loadbalancer.type",
+				"consumer.circuitBreaker.sleepWindow"},
+			wantTimeout: 3 * time.Second,
+			wantLB:      config.DefaultLoadBalancerRingHash,
+		},
+		{
+			name: "只变更不可热更新的配置项时不生效",
+			modify: func(cfg config.Configuration) {
+				cfg.GetGlobal().GetServerConnector().SetAddresses([]string{"127.0.0.2:8091"})
+				cfg.GetConsumer().GetLocalCache().SetPersistDir("/tmp/reload")
+			},
+			wantTimeout: config.DefaultAPIInvokeTimeout,
+			wantLB:      config.DefaultLoadBalancerWR,
+		},
+		{
+			name: "同时变更时只生效可安全变更的配置项",
+			modify: func(cfg config.Configuration) {
+				cfg.GetGlobal().GetServerConnector().SetAddresses([]string{"127.0.0.2:8091"})
+				cfg.GetGlobal().GetAPI().SetTimeout(3 * time.Second)
+			},
+			wantKeys:    []string{"global.api.timeout"},
+			wantTimeout: 3 * time.Second,
+			wantLB:      config.DefaultLoadBalancerWR,
+		},
+		{
+			name: "负载均衡器不存在时整体不生效",
+			modify: func(cfg config.Configuration) {
+				cfg.GetGlobal().GetAPI().SetTimeout(3 * time.Second)
+				cfg.GetConsumer().GetLoadbalancer().SetType("notExists")
+			},
+			wantErr:     true,
+			wantTimeout: config.DefaultAPIInvokeTimeout,
+			wantLB:      config.DefaultLoadBalancerWR,
+		},
+		{
+			name: "日志级别非法时整体不生效",
+			modify: func(cfg config.Configuration) {
+				cfg.GetGlobal().GetAPI().SetTimeout(3 * time.Second)
+				cfg.GetGlobal().GetConfigReload().SetLogLevels(map[string]string{"base": "verbose"})
+			},
+			wantErr:     true,
+			wantTimeout: config.DefaultAPIInvokeTimeout,
+			wantLB:      config.DefaultLoadBalancerWR,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pluginEvents []*model.SDKConfigChangedEvent
+			supplier := &reloadSupplier{
+				lbs: map[string]loadbalancer.LoadBalancer{
+					config.DefaultLoadBalancerWR:       &reloadLoadBalancer{name: config.DefaultLoadBalancerWR},
+					config.DefaultLoadBalancerRingHash: &reloadLoadBalancer{name: config.DefaultLoadBalancerRingHash},
+				},
+				handlers: []common.PluginEventHandler{{Callback: func(event *common.PluginEvent) error {
+					pluginEvents = append(pluginEvents, event.EventObject.(*model.SDKConfigChangedEvent))
+					return nil
+				}}},
+			}
+			cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+			e := &Engine{configuration: cfg, plugins: supplier}
+			e.defaultRouting.Store(&defaultRouting{loadbalancer: supplier.lbs[config.DefaultLoadBalancerWR]})
+			var listenerEvents []*model.SDKConfigChangedEvent
+			e.AddSDKConfigChangedListener(func(event *model.SDKConfigChangedEvent) {
+				listenerEvents = append(listenerEvents, event)
+			})
+
+			newCfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+			tt.modify(newCfg)
+			err := e.applyReloadedConfig("test", newCfg)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+
+			// 不可热更新的配置项始终保持原值
+			assert.Equal(t, []string{"127.0.0.1:8091"}, cfg.GetGlobal().GetServerConnector().GetAddresses())
+			assert.NotEqual(t, "/tmp/reload", cfg.GetConsumer().GetLocalCache().GetPersistDir())
+			assert.Equal(t, tt.wantTimeout, cfg.GetGlobal().GetAPI().GetTimeout())
+			assert.Equal(t, tt.wantLB, cfg.GetConsumer().GetLoadbalancer().GetType())
+			routing := e.defaultRouting.Load().(*defaultRouting)
+			assert.Equal(t, tt.wantLB, routing.loadbalancer.(*reloadLoadBalancer).name)
+
+			if len(tt.wantKeys) == 0 {
+				assert.Empty(t, pluginEvents)
+				assert.Empty(t, listenerEvents)
+				return
+			}
+			assert.Len(t, pluginEvents, 1)
+			assert.Len(t, listenerEvents, 1)
+			assert.Equal(t, "test", pluginEvents[0].Source)
+			var keys []string
+			for _, change := range pluginEvents[0].Changes {
+				keys = append(keys, change.Key)
+			}
+			assert.ElementsMatch(t, tt.wantKeys, keys)
+			assert.Equal(t, pluginEvents[0], listenerEvents[0])
+		})
+	}
+}
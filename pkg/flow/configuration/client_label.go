@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/pkg/version"
+)
+
+// clientLabelTags 构建随配置请求上报的客户端标签，服务端根据客户端标签匹配灰度发布的配置版本
+// 用户配置的 global.client.labels 优先级最高
+func clientLabelTags(conf config.Configuration) []*configconnector.ConfigFileTag {
+	labels := map[string]string{
+		configconnector.ClientLabelLanguage: "golang",
+		configconnector.ClientLabelVersion:  version.Version,
+	}
+	if ip := conf.GetGlobal().GetAPI().GetBindIP(); ip != "" {
+		labels[configconnector.ClientLabelIP] = ip
+	}
+	client := conf.GetGlobal().GetClient()
+	if id := client.GetId(); id != "" {
+		labels[configconnector.ClientLabelID] = id
+	}
+	if env := client.GetEnv(); env != "" {
+		labels[configconnector.ClientLabelEnv] = env
+	}
+	for k, v := range client.GetLabels() {
+		labels[k] = v
+	}
+	tags := make([]*configconnector.ConfigFileTag, 0, len(labels))
+	for k, v := range labels {
+		tags = append(tags, &configconnector.ConfigFileTag{
+			Key:   k,
+			Value: v,
+		})
+	}
+	return tags
+}
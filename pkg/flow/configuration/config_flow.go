@@ -27,6 +27,7 @@ import (
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 
 	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/dispatcher"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
@@ -48,18 +49,20 @@ type ConfigFileFlow struct {
 	conf      config.Configuration
 
 	persistHandler *CachePersistHandler
+	dispatcher     *dispatcher.Pool
 
 	startLongPollingTaskOnce sync.Once
 }
 
 // NewConfigFileFlow 创建配置中心服务
 func NewConfigFileFlow(connector configconnector.ConfigConnector, chain configfilter.Chain,
-	conf config.Configuration) (*ConfigFileFlow, error) {
+	conf config.Configuration, pool *dispatcher.Pool) (*ConfigFileFlow, error) {
 	persistHandler, err := NewCachePersistHandler(
 		conf.GetConfigFile().GetLocalCache().GetPersistDir(),
 		conf.GetConfigFile().GetLocalCache().GetPersistMaxWriteRetry(),
 		conf.GetConfigFile().GetLocalCache().GetPersistMaxReadRetry(),
 		conf.GetConfigFile().GetLocalCache().GetPersistRetryInterval(),
+		conf.GetConfigFile().GetLocalCache().GetCipher(),
 	)
 	if err != nil {
 		return nil, err
@@ -74,6 +77,7 @@ func NewConfigFileFlow(connector configconnector.ConfigConnector, chain configfi
 		configFilePool:  map[string]*ConfigFileRepo{},
 		notifiedVersion: map[string]uint64{},
 		persistHandler:  persistHandler,
+		dispatcher:      pool,
 	}
 
 	return configFileService, nil
@@ -117,7 +121,7 @@ func (c *ConfigFileFlow) GetConfigFile(req *model.GetConfigFileRequest) (model.C
 	if err != nil {
 		return nil, err
 	}
-	configFile = newDefaultConfigFile(configFileMetadata, fileRepo)
+	configFile = newDefaultConfigFile(configFileMetadata, fileRepo, c.dispatcher)
 
 	if req.Subscribe {
 		c.addConfigFileToLongPollingPool(fileRepo)
@@ -129,91 +133,161 @@ func (c *ConfigFileFlow) GetConfigFile(req *model.GetConfigFileRequest) (model.C
 
 // CreateConfigFile 创建配置文件
 func (c *ConfigFileFlow) CreateConfigFile(namespace, fileGroup, fileName, content string) error {
-	// 校验参数
-	configFile := &configconnector.ConfigFile{
+	return c.CreateConfigFileWithReq(&model.ConfigFileModifyRequest{
 		Namespace: namespace,
 		FileGroup: fileGroup,
 		FileName:  fileName,
-	}
-	configFile.SetContent(content)
+		Content:   content,
+	})
+}
 
-	if err := model.CheckConfigFileMetadata(configFile); err != nil {
-		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "")
+// CreateConfigFileWithReq 创建配置文件
+func (c *ConfigFileFlow) CreateConfigFileWithReq(req *model.ConfigFileModifyRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	configFile := &configconnector.ConfigFile{
+		Namespace: req.Namespace,
+		FileGroup: req.FileGroup,
+		FileName:  req.FileName,
+		Comment:   req.Comment,
 	}
+	configFile.SetContent(req.Content)
 
 	c.fclock.Lock()
 	defer c.fclock.Unlock()
 
-	resp, err := c.connector.CreateConfigFile(configFile)
-	if err != nil {
+	if err := c.checkExpectedVersion(configFile, req.ExpectedVersion); err != nil {
 		return err
 	}
-
-	responseCode := resp.GetCode()
-
-	if responseCode != uint32(apimodel.Code_ExecuteSuccess) {
-		log.GetBaseLogger().Infof("[Config] failed to create config file. namespace = %s, fileGroup = %s, fileName = %s, response code = %d",
-			namespace, fileGroup, fileName, responseCode)
-		errMsg := fmt.Sprintf("failed to create config file. namespace = %s, fileGroup = %s, fileName = %s, response code = %d",
-			namespace, fileGroup, fileName, responseCode)
-		return model.NewSDKError(model.ErrCodeInternalError, nil, errMsg)
-	}
-
-	return nil
+	return c.doModify("create", configFile, c.connector.CreateConfigFile)
 }
 
 // UpdateConfigFile 更新配置文件
 func (c *ConfigFileFlow) UpdateConfigFile(namespace, fileGroup, fileName, content string) error {
-	// 校验参数
-	configFile := &configconnector.ConfigFile{
+	return c.UpdateConfigFileWithReq(&model.ConfigFileModifyRequest{
 		Namespace: namespace,
 		FileGroup: fileGroup,
 		FileName:  fileName,
-	}
-	configFile.SetContent(content)
+		Content:   content,
+	})
+}
 
-	if err := model.CheckConfigFileMetadata(configFile); err != nil {
-		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "")
+// UpdateConfigFileWithReq 更新配置文件
+func (c *ConfigFileFlow) UpdateConfigFileWithReq(req *model.ConfigFileModifyRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	configFile := &configconnector.ConfigFile{
+		Namespace: req.Namespace,
+		FileGroup: req.FileGroup,
+		FileName:  req.FileName,
+		Comment:   req.Comment,
 	}
+	configFile.SetContent(req.Content)
 
 	c.fclock.Lock()
 	defer c.fclock.Unlock()
 
-	resp, err := c.connector.UpdateConfigFile(configFile)
-	if err != nil {
+	if err := c.checkExpectedVersion(configFile, req.ExpectedVersion); err != nil {
 		return err
 	}
-
-	responseCode := resp.GetCode()
-
-	if responseCode != uint32(apimodel.Code_ExecuteSuccess) {
-		log.GetBaseLogger().Infof("[Config] failed to update config file. namespace = %s, fileGroup = %s, fileName = %s, response code = %d",
-			namespace, fileGroup, fileName, responseCode)
-		errMsg := fmt.Sprintf("failed to update config file. namespace = %s, fileGroup = %s, fileName = %s, response code = %d",
-			namespace, fileGroup, fileName, responseCode)
-		return model.NewSDKError(model.ErrCodeInternalError, nil, errMsg)
-	}
-
-	return nil
+	return c.doModify("update", configFile, c.connector.UpdateConfigFile)
 }
 
 // PublishConfigFile 发布配置文件
 func (c *ConfigFileFlow) PublishConfigFile(namespace, fileGroup, fileName string) error {
-	// 检验参数
-	configFile := &configconnector.ConfigFile{
+	return c.PublishConfigFileWithReq(&model.ConfigFilePublishRequest{
 		Namespace: namespace,
 		FileGroup: fileGroup,
 		FileName:  fileName,
+	})
+}
+
+// PublishConfigFileWithReq 发布配置文件
+func (c *ConfigFileFlow) PublishConfigFileWithReq(req *model.ConfigFilePublishRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	configFile := &configconnector.ConfigFile{
+		Namespace:          req.Namespace,
+		FileGroup:          req.FileGroup,
+		FileName:           req.FileName,
+		ReleaseName:        req.ReleaseName,
+		ReleaseDescription: req.ReleaseDescription,
 	}
 
-	if err := model.CheckConfigFileMetadata(configFile); err != nil {
-		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "")
+	c.fclock.Lock()
+	defer c.fclock.Unlock()
+
+	if err := c.checkExpectedVersion(configFile, req.ExpectedVersion); err != nil {
+		return err
+	}
+	return c.doModify("publish", configFile, c.connector.PublishConfigFile)
+}
+
+// RollbackConfigFile 回滚配置文件，将目标版本的配置内容更新后重新发布
+func (c *ConfigFileFlow) RollbackConfigFile(req *model.ConfigFileRollbackRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	configFile := &configconnector.ConfigFile{
+		Namespace:          req.Namespace,
+		FileGroup:          req.FileGroup,
+		FileName:           req.FileName,
+		ReleaseName:        req.ReleaseName,
+		ReleaseDescription: req.ReleaseDescription,
 	}
+	configFile.SetContent(req.Content)
 
 	c.fclock.Lock()
 	defer c.fclock.Unlock()
 
-	resp, err := c.connector.PublishConfigFile(configFile)
+	if err := c.checkExpectedVersion(configFile, req.ExpectedVersion); err != nil {
+		return err
+	}
+	if err := c.doModify("update", configFile, c.connector.UpdateConfigFile); err != nil {
+		return err
+	}
+	return c.doModify("publish", configFile, c.connector.PublishConfigFile)
+}
+
+// checkExpectedVersion 校验服务端当前发布版本与期望版本一致
+// 服务端不提供版本比较更新的能力，校验与变更之间仍可能被其他客户端修改
+func (c *ConfigFileFlow) checkExpectedVersion(configFile *configconnector.ConfigFile, expected *uint64) error {
+	if expected == nil {
+		return nil
+	}
+	resp, err := c.connector.GetConfigFile(&configconnector.ConfigFile{
+		Namespace: configFile.Namespace,
+		FileGroup: configFile.FileGroup,
+		FileName:  configFile.FileName,
+	})
+	if err != nil {
+		return err
+	}
+	var current uint64
+	switch resp.GetCode() {
+	case uint32(apimodel.Code_ExecuteSuccess):
+		current = resp.GetConfigFile().GetVersion()
+	case uint32(apimodel.Code_NotFoundResource):
+		current = 0
+	default:
+		return model.NewSDKError(model.ErrCodeInternalError, nil,
+			"failed to query config file version. namespace = %s, fileGroup = %s, fileName = %s, response code = %d",
+			configFile.Namespace, configFile.FileGroup, configFile.FileName, resp.GetCode())
+	}
+	if current != *expected {
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+			"config file version mismatch. namespace = %s, fileGroup = %s, fileName = %s, expected = %d, current = %d",
+			configFile.Namespace, configFile.FileGroup, configFile.FileName, *expected, current)
+	}
+	return nil
+}
+
+func (c *ConfigFileFlow) doModify(op string, configFile *configconnector.ConfigFile,
+	handle func(*configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error)) error {
+	resp, err := handle(configFile)
 	if err != nil {
 		return err
 	}
@@ -221,10 +295,10 @@ func (c *ConfigFileFlow) PublishConfigFile(namespace, fileGroup, fileName string
 	responseCode := resp.GetCode()
 
 	if responseCode != uint32(apimodel.Code_ExecuteSuccess) {
-		log.GetBaseLogger().Infof("[Config] failed to publish config file. namespace = %s, fileGroup = %s, fileName = %s, response code = %d",
-			namespace, fileGroup, fileName, responseCode)
-		errMsg := fmt.Sprintf("failed to publish config file. namespace = %s, fileGroup = %s, fileName = %s, response code = %d",
-			namespace, fileGroup, fileName, responseCode)
+		log.GetBaseLogger().Infof("[Config] failed to %s config file. namespace = %s, fileGroup = %s, fileName = %s, response code = %d",
+			op, configFile.Namespace, configFile.FileGroup, configFile.FileName, responseCode)
+		errMsg := fmt.Sprintf("failed to %s config file. namespace = %s, fileGroup = %s, fileName = %s, response code = %d",
+			op, configFile.Namespace, configFile.FileGroup, configFile.FileName, responseCode)
 		return model.NewSDKError(model.ErrCodeInternalError, nil, errMsg)
 	}
 
@@ -377,6 +451,7 @@ func (c *ConfigFileFlow) assembleWatchConfigFiles() []*configconnector.ConfigFil
 	c.fclock.RLock()
 	defer c.fclock.RUnlock()
 	watchConfigFiles := make([]*configconnector.ConfigFile, 0, len(c.configFilePool))
+	tags := clientLabelTags(c.conf)
 
 	for cacheKey := range c.configFilePool {
 		configFileMetadata := extractConfigFileMetadata(cacheKey)
@@ -386,6 +461,7 @@ func (c *ConfigFileFlow) assembleWatchConfigFiles() []*configconnector.ConfigFil
 			FileGroup: configFileMetadata.GetFileGroup(),
 			FileName:  configFileMetadata.GetFileName(),
 			Version:   c.getConfigFileNotifiedVersion(cacheKey, false),
+			Tags:      tags,
 		})
 	}
 
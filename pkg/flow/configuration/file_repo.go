@@ -151,13 +151,7 @@ func (r *ConfigFileRepo) pull() error {
 		FileName:  r.configFileMetadata.GetFileName(),
 		Version:   r.notifiedVersion,
 		Mode:      r.configFileMetadata.GetFileMode(),
-		Tags:      make([]*configconnector.ConfigFileTag, 0, len(r.conf.GetGlobal().GetClient().GetLabels())),
-	}
-	for k, v := range r.conf.GetGlobal().GetClient().GetLabels() {
-		pullConfigFileReq.Tags = append(pullConfigFileReq.Tags, &configconnector.ConfigFileTag{
-			Key:   k,
-			Value: v,
-		})
+		Tags:      clientLabelTags(r.conf),
 	}
 
 	log.GetBaseLogger().Infof("[Config] start pull config file. config file = %+v, version = %d",
@@ -227,7 +221,10 @@ func (r *ConfigFileRepo) pull() error {
 		retryTimes++
 		r.retryPolicy.delay()
 	}
-	r.fallbackIfNecessary(retryTimes, pullConfigFileReq)
+	// 服务端不可达时使用本地持久化的配置，启动阶段也能正常获取配置文件，后续由长轮询恢复同步
+	if r.fallbackIfNecessary(retryTimes, pullConfigFileReq) {
+		return nil
+	}
 	return err
 }
 
@@ -235,15 +232,15 @@ const (
 	PullConfigMaxRetryTimes = 3
 )
 
-func (r *ConfigFileRepo) fallbackIfNecessary(retryTimes int, req *configconnector.ConfigFile) {
+func (r *ConfigFileRepo) fallbackIfNecessary(retryTimes int, req *configconnector.ConfigFile) bool {
 	if !(retryTimes >= PullConfigMaxRetryTimes && r.fallbackToLocalCache) {
-		return
+		return false
 	}
 	cacheVal := &configconnector.ConfigFile{}
 	fileName := fmt.Sprintf(PatternService, url.QueryEscape(req.Namespace), url.QueryEscape(req.FileGroup),
 		url.QueryEscape(req.FileName)) + CacheSuffix
 	if err := r.persistHandler.LoadMessageFromFile(fileName, cacheVal); err != nil {
-		return
+		return false
 	}
 
 	response, err := r.chain.Execute(req, func(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
@@ -263,11 +260,12 @@ func (r *ConfigFileRepo) fallbackIfNecessary(retryTimes int, req *configconnecto
 	})
 	if err != nil {
 		log.GetBaseLogger().Errorf("[Config] fallback to local cache fail. %+v", err)
-		return
+		return false
 	}
 	log.GetBaseLogger().Errorf("[Config] fallback to local cache success.")
 	localFile := response.ConfigFile
 	r.fireChangeEvent(localFile)
+	return true
 }
 
 func (r *ConfigFileRepo) saveCacheConfigFile(file *configconnector.ConfigFile) {
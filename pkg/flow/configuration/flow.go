@@ -19,6 +19,7 @@ package configuration
 
 import (
 	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/dispatcher"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
 )
@@ -30,12 +31,12 @@ type ConfigFlow struct {
 
 // NewConfigFlow 创建配置中心服务
 func NewConfigFlow(connector configconnector.ConfigConnector, chain configfilter.Chain,
-	configuration config.Configuration) (*ConfigFlow, error) {
-	fileFlow, err := NewConfigFileFlow(connector, chain, configuration)
+	configuration config.Configuration, pool *dispatcher.Pool) (*ConfigFlow, error) {
+	fileFlow, err := NewConfigFileFlow(connector, chain, configuration, pool)
 	if err != nil {
 		return nil, err
 	}
-	groupFlow, err := newConfigGroupFlow(connector, configuration)
+	groupFlow, err := newConfigGroupFlow(connector, configuration, pool)
 	if err != nil {
 		return nil, err
 	}
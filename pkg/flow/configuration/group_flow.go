@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/dispatcher"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
@@ -37,15 +38,18 @@ type ConfigGroupFlow struct {
 
 	connector     configconnector.ConfigConnector
 	configuration config.Configuration
+	dispatcher    *dispatcher.Pool
 }
 
-func newConfigGroupFlow(connector configconnector.ConfigConnector, configuration config.Configuration) (*ConfigGroupFlow, error) {
+func newConfigGroupFlow(connector configconnector.ConfigConnector, configuration config.Configuration,
+	pool *dispatcher.Pool) (*ConfigGroupFlow, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	groupFlow := &ConfigGroupFlow{
 		cancel:        cancel,
 		connector:     connector,
 		configuration: configuration,
+		dispatcher:    pool,
 		repos:         map[string]*ConfigGroupRepo{},
 		groupCache:    map[string]model.ConfigFileGroup{},
 	}
@@ -79,7 +83,7 @@ func (flow *ConfigGroupFlow) GetConfigGroup(namespace, fileGroup string) (model.
 	}
 	flow.repos[cacheKey] = groupRepo
 
-	configGroup = newDefaultConfigGroup(namespace, fileGroup, groupRepo)
+	configGroup = newDefaultConfigGroup(namespace, fileGroup, groupRepo, flow.dispatcher)
 	flow.groupCache[cacheKey] = configGroup
 	return configGroup, nil
 }
@@ -109,7 +113,7 @@ func (flow *ConfigGroupFlow) GetConfigGroupWithReq(req *model.GetConfigGroupRequ
 	}
 	flow.repos[cacheKey] = groupRepo
 
-	configGroup = newDefaultConfigGroup(req.Namespace, req.FileGroup, groupRepo)
+	configGroup = newDefaultConfigGroup(req.Namespace, req.FileGroup, groupRepo, flow.dispatcher)
 	flow.groupCache[cacheKey] = configGroup
 	return configGroup, nil
 }
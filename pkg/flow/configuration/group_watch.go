@@ -0,0 +1,214 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// GetConfigGroupFiles 获取配置分组下所有已发布的配置文件内容
+func (c *ConfigFlow) GetConfigGroupFiles(namespace, fileGroup string) ([]model.ConfigFile, error) {
+	group, err := c.GetConfigGroup(namespace, fileGroup)
+	if err != nil {
+		return nil, err
+	}
+	files, _, _ := group.GetFiles()
+	configFiles := make([]model.ConfigFile, 0, len(files))
+	for _, file := range files {
+		configFile, err := c.getGroupMemberFile(file)
+		if err != nil {
+			return nil, err
+		}
+		configFiles = append(configFiles, configFile)
+	}
+	return configFiles, nil
+}
+
+// WatchConfigGroup 监听配置分组，分组内任意文件内容变更、新增以及删除时回调cb
+func (c *ConfigFlow) WatchConfigGroup(namespace, fileGroup string, cb model.OnConfigFileChange) error {
+	return c.watchConfigGroup(namespace, fileGroup, nil, cb)
+}
+
+// WatchConfigFilesByPattern 按文件名通配符监听配置分组，匹配的文件内容变更、新增以及删除时回调cb
+// 通配符语法同 path.Match，例如 "*.yaml"、"db/*"
+// 客户端协议无法列出命名空间下的分组，因此分组名不支持通配符
+func (c *ConfigFlow) WatchConfigFilesByPattern(namespace, fileGroup, pattern string,
+	cb model.OnConfigFileChange) error {
+	if strings.ContainsAny(fileGroup, "*?[") {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"wildcard in fileGroup %s is not supported, only fileName pattern is supported", fileGroup)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "invalid fileName pattern %s", pattern)
+	}
+	return c.watchConfigGroup(namespace, fileGroup, func(fileName string) bool {
+		matched, _ := path.Match(pattern, fileName)
+		return matched
+	}, cb)
+}
+
+func (c *ConfigFlow) watchConfigGroup(namespace, fileGroup string, match func(fileName string) bool,
+	cb model.OnConfigFileChange) error {
+	group, err := c.GetConfigGroup(namespace, fileGroup)
+	if err != nil {
+		return err
+	}
+	watcher := &configGroupWatcher{
+		flow:       c,
+		cb:         cb,
+		match:      match,
+		subscribed: map[string]bool{},
+		members:    map[string]model.ConfigFile{},
+	}
+	files, _, _ := group.GetFiles()
+	for _, file := range files {
+		if !watcher.matches(file.FileName) {
+			continue
+		}
+		if err := watcher.watchFile(file, false); err != nil {
+			return err
+		}
+	}
+	group.AddChangeListener(watcher.onGroupChange)
+	return nil
+}
+
+func (c *ConfigFlow) getGroupMemberFile(file *model.SimpleConfigFile) (model.ConfigFile, error) {
+	return c.GetConfigFile(&model.GetConfigFileRequest{
+		Namespace: file.Namespace,
+		FileGroup: file.FileGroup,
+		FileName:  file.FileName,
+		Subscribe: true,
+	})
+}
+
+// configGroupWatcher 配置分组监听器，聚合分组成员变化以及成员文件的内容变更
+type configGroupWatcher struct {
+	flow *ConfigFlow
+	cb   model.OnConfigFileChange
+	// 文件名过滤，为空时监听分组内全部文件
+	match func(fileName string) bool
+
+	lock sync.Mutex
+	// 已注册过内容监听的文件，文件监听无法移除，重新加入分组时不再重复注册
+	subscribed map[string]bool
+	// 当前属于分组的文件
+	members map[string]model.ConfigFile
+}
+
+func (w *configGroupWatcher) matches(fileName string) bool {
+	return w.match == nil || w.match(fileName)
+}
+
+// watchFile 将文件加入分组成员，notify为true时回调新增事件
+func (w *configGroupWatcher) watchFile(file *model.SimpleConfigFile, notify bool) error {
+	configFile, err := w.flow.getGroupMemberFile(file)
+	if err != nil {
+		return err
+	}
+	w.lock.Lock()
+	if _, ok := w.members[file.FileName]; ok {
+		w.lock.Unlock()
+		return nil
+	}
+	w.members[file.FileName] = configFile
+	needSubscribe := !w.subscribed[file.FileName]
+	w.subscribed[file.FileName] = true
+	w.lock.Unlock()
+
+	if needSubscribe {
+		fileName := file.FileName
+		configFile.AddChangeListener(func(event model.ConfigFileChangeEvent) {
+			w.onFileChange(fileName, event)
+		})
+	}
+	if notify {
+		w.cb(model.ConfigFileChangeEvent{
+			ConfigFileMetadata: configFile,
+			NewValue:           configFile.GetContent(),
+			ChangeType:         model.Added,
+			Persistent:         configFile.GetPersistent(),
+		})
+	}
+	return nil
+}
+
+// onFileChange 成员文件内容变更，非分组成员的文件事件忽略
+func (w *configGroupWatcher) onFileChange(fileName string, event model.ConfigFileChangeEvent) {
+	if event.ChangeType == model.NotChanged {
+		return
+	}
+	w.lock.Lock()
+	_, ok := w.members[fileName]
+	if ok && event.ChangeType == model.Deleted {
+		delete(w.members, fileName)
+	}
+	w.lock.Unlock()
+	if ok {
+		w.cb(event)
+	}
+}
+
+// onGroupChange 分组文件列表变化，计算新增和删除的文件
+func (w *configGroupWatcher) onGroupChange(event *model.ConfigGroupChangeEvent) {
+	after := make(map[string]*model.SimpleConfigFile, len(event.After))
+	for _, file := range event.After {
+		if w.matches(file.FileName) {
+			after[file.FileName] = file
+		}
+	}
+
+	w.lock.Lock()
+	removed := make([]model.ConfigFile, 0)
+	for name, configFile := range w.members {
+		if _, ok := after[name]; !ok {
+			removed = append(removed, configFile)
+			delete(w.members, name)
+		}
+	}
+	added := make([]*model.SimpleConfigFile, 0)
+	for name, file := range after {
+		if _, ok := w.members[name]; !ok {
+			added = append(added, file)
+		}
+	}
+	w.lock.Unlock()
+
+	for _, configFile := range removed {
+		w.cb(model.ConfigFileChangeEvent{
+			ConfigFileMetadata: configFile,
+			OldValue:           configFile.GetContent(),
+			ChangeType:         model.Deleted,
+			Persistent:         configFile.GetPersistent(),
+		})
+	}
+	for _, file := range added {
+		// 分组变更回调在同步协程中执行，获取新文件放到独立协程，避免阻塞分组同步
+		go func(file *model.SimpleConfigFile) {
+			if err := w.watchFile(file, true); err != nil {
+				log.GetBaseLogger().Errorf("[Config][Group] watch added file fail. file = %s/%s/%s, err = %v",
+					file.Namespace, file.FileGroup, file.FileName, err)
+			}
+		}(file)
+	}
+}
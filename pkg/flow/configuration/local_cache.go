@@ -28,6 +28,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/go-multierror"
 
+	"github.com/polarismesh/polaris-go/pkg/cachecipher"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
@@ -47,6 +48,8 @@ type CachePersistHandler struct {
 	maxWriteRetry int
 	maxReadRetry  int
 	retryInterval time.Duration
+	// 缓存文件加解密器，为nil时不加密
+	cipher cachecipher.CacheCipher
 }
 
 // CacheFileInfo 文件信息
@@ -57,8 +60,13 @@ type CacheFileInfo struct {
 
 // NewCachePersistHandler create persistence handler
 func NewCachePersistHandler(persistDir string, maxWriteRetry int,
-	maxReadRetry int, retryInterval time.Duration) (*CachePersistHandler, error) {
+	maxReadRetry int, retryInterval time.Duration, cipherName string) (*CachePersistHandler, error) {
+	cacheCipher, err := cachecipher.GetCacheCipher(cipherName)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to init cachePersistHandler")
+	}
 	handler := &CachePersistHandler{}
+	handler.cipher = cacheCipher
 	handler.persistDir = persistDir
 	handler.maxReadRetry = maxReadRetry
 	handler.maxWriteRetry = maxWriteRetry
@@ -96,6 +104,11 @@ func (cph *CachePersistHandler) loadMessageFromAbsoluteFile(cacheFile string, me
 			// 文件打开失败的话，重试没有意义，直接失败
 			break
 		}
+		if cacheJson, err = cachecipher.Open(cph.cipher, cacheJson); err != nil {
+			lastErr = model.NewSDKError(model.ErrCodeDiskError, err, "fail to decrypt file cache")
+			// 密钥错误或未配置加解密器，重试没有意义，直接失败
+			break
+		}
 		if err := json.Unmarshal(cacheJson, message); err != nil {
 			lastErr = multierror.Prefix(err, "Fail to unmarshal file cache: ")
 			time.Sleep(cph.retryInterval)
@@ -140,6 +153,10 @@ func (cph *CachePersistHandler) SaveMessageToFile(fileName string, svcResp inter
 		log.GetBaseLogger().Warnf("Fail to marshal the service response for %s", fileToAdd)
 		return
 	}
+	if msg, err = cachecipher.Seal(cph.cipher, msg); err != nil {
+		log.GetBaseLogger().Warnf("Fail to encrypt the config file for %s, %v", fileToAdd, err)
+		return
+	}
 	for retryTimes := 0; retryTimes <= cph.maxWriteRetry; retryTimes++ {
 		err = cph.doWriteFile(fileToAdd, []byte(msg))
 		if err != nil {
@@ -18,8 +18,12 @@
 package configuration
 
 import (
+	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/polarismesh/polaris-go/pkg/dispatcher"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
@@ -31,21 +35,55 @@ type defaultConfigFile struct {
 	fileRepo   *ConfigFileRepo
 	content    string
 	persistent model.Persistent
+	// 当前对应用可见的配置对象 *configconnector.ConfigFile，校验失败时不更新
+	visibleFile atomic.Value
 
 	lock                sync.RWMutex
-	changeListeners     []func(event model.ConfigFileChangeEvent)
-	changeListenerChans []chan model.ConfigFileChangeEvent
+	changeListeners     []fileChangeListener
+	changeListenerChans []fileChangeChan
+	validators          []model.OnConfigFileValidate
+	rollbackListeners   []fileRollbackListener
+	// dispatcher 监听器回调分发协程池，cacheKey 用于回调异常时的日志
+	dispatcher *dispatcher.Pool
+	cacheKey   string
+
+	// 解析后的配置项缓存，内容变化时重新解析
+	valuesLock    sync.Mutex
+	valuesContent string
+	values        map[string]interface{}
 }
 
-func newDefaultConfigFile(metadata model.ConfigFileMetadata, repo *ConfigFileRepo) *defaultConfigFile {
+// fileChangeListener 配置文件变更监听器，key 为分发标识，保证同一监听器的回调按顺序执行
+type fileChangeListener struct {
+	key uint64
+	cb  model.OnConfigFileChange
+}
+
+// fileChangeChan 通过 channel 接收配置文件变更的监听器
+type fileChangeChan struct {
+	key uint64
+	ch  chan model.ConfigFileChangeEvent
+}
+
+// fileRollbackListener 配置文件变更校验失败的监听器
+type fileRollbackListener struct {
+	key uint64
+	cb  model.OnConfigFileRollback
+}
+
+func newDefaultConfigFile(metadata model.ConfigFileMetadata, repo *ConfigFileRepo,
+	pool *dispatcher.Pool) *defaultConfigFile {
 	configFile := &defaultConfigFile{
 		fileRepo:   repo,
 		content:    repo.GetContent(),
 		persistent: repo.GetPersistent(),
+		dispatcher: pool,
+		cacheKey:   genCacheKeyByMetadata(metadata),
 	}
 	configFile.Namespace = metadata.GetNamespace()
 	configFile.FileGroup = metadata.GetFileGroup()
 	configFile.FileName = metadata.GetFileName()
+	configFile.storeVisibleFile()
 
 	repo.AddChangeListener(configFile.repoChangeListener)
 	return configFile
@@ -53,7 +91,7 @@ func newDefaultConfigFile(metadata model.ConfigFileMetadata, repo *ConfigFileRep
 
 // GetLabels 获取标签
 func (c *defaultConfigFile) GetLabels() map[string]string {
-	remote := c.fileRepo.loadRemoteFile()
+	remote := c.loadVisibleFile()
 	if remote == nil {
 		return map[string]string{}
 	}
@@ -73,6 +111,24 @@ func (c *defaultConfigFile) GetPersistent() model.Persistent {
 	return c.persistent
 }
 
+// GetVersion 获取当前配置内容的发布版本号
+func (c *defaultConfigFile) GetVersion() uint64 {
+	remote := c.loadVisibleFile()
+	if remote == nil {
+		return 0
+	}
+	return remote.GetVersion()
+}
+
+// GetReleaseName 获取当前配置内容命中的发布名称
+func (c *defaultConfigFile) GetReleaseName() string {
+	remote := c.loadVisibleFile()
+	if remote == nil {
+		return ""
+	}
+	return remote.GetReleaseName()
+}
+
 // HasContent 是否有配置内容
 func (c *defaultConfigFile) HasContent() bool {
 	return c.content != "" && c.content != NotExistedFileContent
@@ -105,18 +161,186 @@ func (c *defaultConfigFile) repoChangeListener(configFileMetadata model.ConfigFi
 		ChangeType:         changeType,
 		Persistent:         persistent,
 	}
+	if changeType != model.NotChanged {
+		if err := c.validate(event); err != nil {
+			log.GetBaseLogger().Errorf("[Config] validate content fail, keep old content. file = %+v, err = %v",
+				configFileMetadata, err)
+			c.fireRollbackEvent(event, err)
+			return err
+		}
+	}
 	c.content = newContent
+	c.storeVisibleFile()
 
 	c.fireChangeEvent(event)
 	return nil
 }
 
+func (c *defaultConfigFile) storeVisibleFile() {
+	remote := c.fileRepo.loadRemoteFile()
+	if remote == nil {
+		// 配置已删除
+		remote = &configconnector.ConfigFile{}
+	}
+	c.visibleFile.Store(remote)
+}
+
+func (c *defaultConfigFile) loadVisibleFile() *configconnector.ConfigFile {
+	if val := c.visibleFile.Load(); val != nil {
+		return val.(*configconnector.ConfigFile)
+	}
+	return nil
+}
+
+// validate 执行变更校验，任意校验函数失败则拒绝变更
+func (c *defaultConfigFile) validate(event model.ConfigFileChangeEvent) error {
+	c.lock.RLock()
+	validators := c.validators
+	c.lock.RUnlock()
+	for _, validator := range validators {
+		if err := validator(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *defaultConfigFile) fireRollbackEvent(event model.ConfigFileChangeEvent, err error) {
+	c.lock.RLock()
+	listeners := c.rollbackListeners
+	c.lock.RUnlock()
+	for i := range listeners {
+		listener := listeners[i]
+		c.dispatcher.Dispatch(listener.key, c.cacheKey, func() {
+			listener.cb(event, err)
+		})
+	}
+}
+
+// AddValidator 增加配置文件变更校验函数
+func (c *defaultConfigFile) AddValidator(validator model.OnConfigFileValidate) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.validators = append(c.validators, validator)
+}
+
+// AddRollbackListener 增加配置文件变更校验失败的监听器
+func (c *defaultConfigFile) AddRollbackListener(cb model.OnConfigFileRollback) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rollbackListeners = append(c.rollbackListeners, fileRollbackListener{key: dispatcher.NextKey(), cb: cb})
+}
+
+// getValue 获取解析后的配置项
+func (c *defaultConfigFile) getValue(key string) (interface{}, bool) {
+	content := c.GetContent()
+	c.valuesLock.Lock()
+	defer c.valuesLock.Unlock()
+	if c.values == nil || c.valuesContent != content {
+		values, err := parseValues(parseFileFormat(c.FileName, content), content)
+		if err != nil {
+			log.GetBaseLogger().Errorf("[Config] parse content fail. file = %s/%s/%s, err = %v",
+				c.Namespace, c.FileGroup, c.FileName, err)
+			values = map[string]interface{}{}
+		}
+		c.values = values
+		c.valuesContent = content
+	}
+	value, ok := c.values[key]
+	return value, ok && value != nil
+}
+
+// GetString 获取配置项的字符串值
+func (c *defaultConfigFile) GetString(key string, defaultValue string) string {
+	value, ok := c.getValue(key)
+	if !ok {
+		return defaultValue
+	}
+	return toString(value)
+}
+
+// GetInt 获取配置项的整数值
+func (c *defaultConfigFile) GetInt(key string, defaultValue int) int {
+	value, ok := c.getValue(key)
+	if !ok {
+		return defaultValue
+	}
+	if i, ok := toInt(value); ok {
+		return i
+	}
+	return defaultValue
+}
+
+// GetBool 获取配置项的布尔值
+func (c *defaultConfigFile) GetBool(key string, defaultValue bool) bool {
+	value, ok := c.getValue(key)
+	if !ok {
+		return defaultValue
+	}
+	if b, ok := toBool(value); ok {
+		return b
+	}
+	return defaultValue
+}
+
+// GetDuration 获取配置项的时长
+func (c *defaultConfigFile) GetDuration(key string, defaultValue time.Duration) time.Duration {
+	value, ok := c.getValue(key)
+	if !ok {
+		return defaultValue
+	}
+	if d, ok := toDuration(value); ok {
+		return d
+	}
+	return defaultValue
+}
+
+// GetStringSlice 获取配置项的字符串列表
+func (c *defaultConfigFile) GetStringSlice(key string, defaultValue []string) []string {
+	value, ok := c.getValue(key)
+	if !ok {
+		return defaultValue
+	}
+	return toStringSlice(value)
+}
+
+// Bind 将配置内容绑定到结构体，配置变更时重新绑定
+func (c *defaultConfigFile) Bind(target interface{}, cb model.OnConfigFileBind) error {
+	if err := checkBindTarget(target); err != nil {
+		return err
+	}
+	content := c.GetContent()
+	if err := bindContent(parseFileFormat(c.FileName, content), content, target); err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
+			"fail to bind config file %s/%s/%s", c.Namespace, c.FileGroup, c.FileName)
+	}
+	targetType := reflect.TypeOf(target).Elem()
+	c.AddChangeListener(func(event model.ConfigFileChangeEvent) {
+		if event.ChangeType == model.NotChanged {
+			return
+		}
+		// 先绑定到新对象，成功后再整体替换，避免绑定失败时target被部分修改
+		fresh := reflect.New(targetType)
+		err := bindContent(parseFileFormat(c.FileName, event.NewValue), event.NewValue, fresh.Interface())
+		if err != nil {
+			log.GetBaseLogger().Errorf("[Config] rebind content fail. file = %s/%s/%s, err = %v",
+				c.Namespace, c.FileGroup, c.FileName, err)
+		} else {
+			reflect.ValueOf(target).Elem().Set(fresh.Elem())
+		}
+		if cb != nil {
+			cb(event, err)
+		}
+	})
+	return nil
+}
+
 // AddChangeListenerWithChannel 增加配置文件变更监听器
 func (c *defaultConfigFile) AddChangeListenerWithChannel() <-chan model.ConfigFileChangeEvent {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	changeChan := make(chan model.ConfigFileChangeEvent, 64)
-	c.changeListenerChans = append(c.changeListenerChans, changeChan)
+	c.changeListenerChans = append(c.changeListenerChans, fileChangeChan{key: dispatcher.NextKey(), ch: changeChan})
 	return changeChan
 }
 
@@ -125,16 +349,27 @@ func (c *defaultConfigFile) AddChangeListener(cb model.OnConfigFileChange) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	c.changeListeners = append(c.changeListeners, cb)
+	c.changeListeners = append(c.changeListeners, fileChangeListener{key: dispatcher.NextKey(), cb: cb})
 }
 
 func (c *defaultConfigFile) fireChangeEvent(event model.ConfigFileChangeEvent) {
-	for _, listenerChan := range c.changeListenerChans {
-		listenerChan <- event
+	c.lock.RLock()
+	listenerChans := c.changeListenerChans
+	changeListeners := c.changeListeners
+	c.lock.RUnlock()
+
+	for i := range listenerChans {
+		listenerChan := listenerChans[i]
+		c.dispatcher.Dispatch(listenerChan.key, c.cacheKey, func() {
+			listenerChan.ch <- event
+		})
 	}
 
-	for _, changeListener := range c.changeListeners {
-		changeListener(event)
+	for i := range changeListeners {
+		changeListener := changeListeners[i]
+		c.dispatcher.Dispatch(changeListener.key, c.cacheKey, func() {
+			changeListener.cb(event)
+		})
 	}
 }
 
@@ -143,15 +378,23 @@ type defaultConfigGroup struct {
 	group           string
 	repo            *ConfigGroupRepo
 	lock            sync.RWMutex
-	changeListeners []model.OnConfigGroupChange
+	changeListeners []groupChangeListener
+	dispatcher      *dispatcher.Pool
+}
+
+// groupChangeListener 配置分组变更监听器
+type groupChangeListener struct {
+	key uint64
+	cb  model.OnConfigGroupChange
 }
 
-func newDefaultConfigGroup(ns, group string, repo *ConfigGroupRepo) *defaultConfigGroup {
+func newDefaultConfigGroup(ns, group string, repo *ConfigGroupRepo, pool *dispatcher.Pool) *defaultConfigGroup {
 	configGroup := &defaultConfigGroup{
 		namespace:       ns,
 		group:           group,
 		repo:            repo,
-		changeListeners: []model.OnConfigGroupChange{},
+		changeListeners: []groupChangeListener{},
+		dispatcher:      pool,
 	}
 	repo.AddChangeListener(configGroup.repoChangeListener)
 	return configGroup
@@ -162,7 +405,7 @@ func (c *defaultConfigGroup) AddChangeListener(cb model.OnConfigGroupChange) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	c.changeListeners = append(c.changeListeners, cb)
+	c.changeListeners = append(c.changeListeners, groupChangeListener{key: dispatcher.NextKey(), cb: cb})
 }
 
 func (c *defaultConfigGroup) GetFiles() ([]*model.SimpleConfigFile, string, bool) {
@@ -191,6 +434,9 @@ func (c *defaultConfigGroup) repoChangeListener(val *configconnector.ConfigGroup
 	defer c.lock.RUnlock()
 
 	for i := range c.changeListeners {
-		c.changeListeners[i](event)
+		listener := c.changeListeners[i]
+		c.dispatcher.Dispatch(listener.key, c.namespace+"@"+c.group, func() {
+			listener.cb(event)
+		})
 	}
 }
@@ -0,0 +1,235 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	fileFormatYaml       = "yaml"
+	fileFormatJSON       = "json"
+	fileFormatProperties = "properties"
+)
+
+// parseFileFormat 根据文件名后缀判断配置格式，无法判断时根据内容判断
+func parseFileFormat(fileName string, content string) string {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".yaml", ".yml":
+		return fileFormatYaml
+	case ".json":
+		return fileFormatJSON
+	case ".properties":
+		return fileFormatProperties
+	}
+	if strings.HasPrefix(strings.TrimSpace(content), "{") {
+		return fileFormatJSON
+	}
+	return fileFormatYaml
+}
+
+// parseValues 解析配置内容，多层结构的key以"."拼接
+func parseValues(format string, content string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if strings.TrimSpace(content) == "" {
+		return values, nil
+	}
+	switch format {
+	case fileFormatProperties:
+		return parseProperties(content), nil
+	case fileFormatJSON:
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(content), &data); err != nil {
+			return nil, err
+		}
+		flattenValues("", data, values)
+	default:
+		var data map[interface{}]interface{}
+		if err := yaml.Unmarshal([]byte(content), &data); err != nil {
+			return nil, err
+		}
+		flattenValues("", data, values)
+	}
+	return values, nil
+}
+
+// parseProperties 解析properties格式的配置内容
+func parseProperties(content string) map[string]interface{} {
+	values := map[string]interface{}{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		idx := strings.IndexAny(line, "=:")
+		if idx < 0 {
+			values[line] = ""
+			continue
+		}
+		values[strings.TrimSpace(line[:idx])] = strings.TrimSpace(line[idx+1:])
+	}
+	return values
+}
+
+func flattenValues(prefix string, data interface{}, values map[string]interface{}) {
+	switch v := data.(type) {
+	case map[interface{}]interface{}:
+		for key, value := range v {
+			flattenValues(joinKey(prefix, fmt.Sprint(key)), value, values)
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			flattenValues(joinKey(prefix, key), value, values)
+		}
+	default:
+		if prefix != "" {
+			values[prefix] = v
+		}
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// unflattenValues 将properties的扁平key还原为多层结构，用于结构体绑定
+func unflattenValues(values map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range values {
+		parts := strings.Split(key, ".")
+		current := result
+		for i, part := range parts {
+			if i == len(parts)-1 {
+				current[part] = value
+				break
+			}
+			next, ok := current[part].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				current[part] = next
+			}
+			current = next
+		}
+	}
+	return result
+}
+
+// bindContent 将配置内容反序列化到target中
+func bindContent(format string, content string, target interface{}) error {
+	if strings.TrimSpace(content) == "" {
+		return nil
+	}
+	switch format {
+	case fileFormatJSON:
+		return json.Unmarshal([]byte(content), target)
+	case fileFormatProperties:
+		data, err := yaml.Marshal(unflattenValues(parseProperties(content)))
+		if err != nil {
+			return err
+		}
+		return yaml.Unmarshal(data, target)
+	default:
+		return yaml.Unmarshal([]byte(content), target)
+	}
+}
+
+// checkBindTarget 检查绑定对象必须为非空指针
+func checkBindTarget(target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"bind target must be a non-nil pointer, got %T", target)
+	}
+	return nil
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(toString(value)))
+	return i, err == nil
+}
+
+func toBool(value interface{}) (bool, bool) {
+	if v, ok := value.(bool); ok {
+		return v, true
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(toString(value)))
+	return b, err == nil
+}
+
+// toDuration 字符串按Go时长格式解析，纯数字按毫秒解析
+func toDuration(value interface{}) (time.Duration, bool) {
+	if i, ok := toInt(value); ok {
+		return time.Duration(i) * time.Millisecond, true
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(toString(value)))
+	return d, err == nil
+}
+
+// toStringSlice 列表按元素转换，字符串按","切分
+func toStringSlice(value interface{}) []string {
+	if list, ok := value.([]interface{}); ok {
+		result := make([]string, 0, len(list))
+		for _, item := range list {
+			result = append(result, toString(item))
+		}
+		return result
+	}
+	str := strings.TrimSpace(toString(value))
+	if str == "" {
+		return []string{}
+	}
+	items := strings.Split(str, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
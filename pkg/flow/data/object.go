@@ -197,6 +197,7 @@ func (c *CommonInstancesRequest) clearValues(cfg config.Configuration) {
 	c.DstInstances = nil
 	c.Criteria.HashValue = 0
 	c.Criteria.HashKey = nil
+	c.Criteria.SessionKey = ""
 	c.Criteria.Cluster = nil
 	c.Trigger.Clear()
 	c.Criteria.ReplicateInfo.Count = 0
@@ -221,6 +222,8 @@ func (c *CommonInstancesRequest) InitByGetOneRequest(request *model.GetOneInstan
 	c.RouteInfo.EnableFailOverDefaultMeta = request.EnableFailOverDefaultMeta
 	c.RouteInfo.FailOverDefaultMeta = request.FailOverDefaultMeta
 	c.RouteInfo.Canary = request.Canary
+	c.RouteInfo.CanaryHashKey = request.CanaryHashKey
+	c.RouteInfo.TargetVersion = request.TargetVersion
 	c.response = request.GetResponse()
 	c.DoLoadBalance = true
 	srcService := request.SourceService
@@ -237,6 +240,7 @@ func (c *CommonInstancesRequest) InitByGetOneRequest(request *model.GetOneInstan
 	}
 	c.Criteria.HashKey = request.HashKey
 	c.Criteria.HashValue = request.HashValue
+	c.Criteria.SessionKey = request.SessionKey
 	c.Criteria.ReplicateInfo.Count = request.ReplicateCount
 	c.CallResult.APIName = model.ApiGetOneInstance
 	c.CallResult.RetStatus = model.RetSuccess
@@ -304,7 +308,11 @@ func (c *CommonInstancesRequest) InitByGetMultiRequest(request *model.GetInstanc
 	c.DstService.Service = request.Service
 	c.DstService.Namespace = request.Namespace
 	c.RouteInfo.DestService = request
+	c.RouteInfo.EnableFailOverDefaultMeta = request.EnableFailOverDefaultMeta
+	c.RouteInfo.FailOverDefaultMeta = request.FailOverDefaultMeta
 	c.RouteInfo.Canary = request.Canary
+	c.RouteInfo.CanaryHashKey = request.CanaryHashKey
+	c.RouteInfo.TargetVersion = request.TargetVersion
 	c.response = request.GetResponse()
 	c.SkipRouteFilter = request.SkipRouteFilter
 	srcService := request.SourceService
@@ -558,11 +566,17 @@ type CommonRateLimitRequest struct {
 	Trigger       model.NotifyTrigger
 	ControlParam  model.ControlParam
 	CallResult    model.APICallResult
+	// 是否为匀速排队模式
+	Throttling bool
+	// 匀速排队模式下的最大排队时间
+	MaxQueueMs int64
 }
 
 // clearValues 清理请求体
 func (cl *CommonRateLimitRequest) clearValues() {
 	cl.QuotaRequest = nil
+	cl.Throttling = false
+	cl.MaxQueueMs = 0
 	cl.Trigger.Clear()
 	cl.Method = ""
 	cl.Token = 0
@@ -613,6 +627,10 @@ func (cl *CommonRateLimitRequest) InitByGetQuotaRequest(request *model.QuotaRequ
 	cl.Token = request.GetToken()
 	cl.Method = request.GetMethod()
 	cl.Arguments = parseArguments(request.Arguments())
+	if maxQueueTime := request.GetMaxQueueTimePtr(); maxQueueTime != nil {
+		cl.Throttling = true
+		cl.MaxQueueMs = maxQueueTime.Milliseconds()
+	}
 	cl.Trigger.EnableDstRateLimit = true
 	cl.CallResult.APIName = model.ApiGetQuota
 	cl.CallResult.RetStatus = model.RetSuccess
@@ -18,6 +18,7 @@
 package data
 
 import (
+	"context"
 	"time"
 
 	"github.com/modern-go/reflect2"
@@ -38,6 +39,12 @@ type ControlParamProvider interface {
 	SetRetryCount(int)
 }
 
+// contextProvider 携带调用方ctx的请求
+type contextProvider interface {
+	// GetContext 获取调用方的ctx
+	GetContext() context.Context
+}
+
 // BuildControlParam 为服务注册的请求设置默认值
 func BuildControlParam(
 	provider ControlParamProvider, cfg config.Configuration, param *model.ControlParam) {
@@ -52,6 +59,11 @@ func BuildControlParam(
 		param.MaxRetry = *provider.GetRetryCountPtr()
 	}
 	param.RetryInterval = cfg.GetGlobal().GetAPI().GetRetryInterval()
+	param.CacheOnly = false
+	param.Context = nil
+	if ctxProvider, ok := provider.(contextProvider); ok && !reflect2.IsNil(provider) {
+		param.Context = ctxProvider.GetContext()
+	}
 	if !reflect2.IsNil(provider) {
 		provider.SetTimeout(param.Timeout)
 		provider.SetRetryCount(param.MaxRetry)
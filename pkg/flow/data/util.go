@@ -18,6 +18,7 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
+	"github.com/polarismesh/polaris-go/pkg/plugin/events"
 	"github.com/polarismesh/polaris-go/pkg/plugin/healthcheck"
 	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
 	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
@@ -157,6 +159,23 @@ func GetStatReporterChain(cfg config.Configuration, supplier plugin.Supplier) ([
 	return reporterChain, nil
 }
 
+// GetEventReporterChain 获取治理事件上报插件
+func GetEventReporterChain(cfg config.Configuration, supplier plugin.Supplier) ([]events.EventReporter, error) {
+	if !cfg.GetGlobal().GetEventReporter().IsEnable() {
+		return make([]events.EventReporter, 0), nil
+	}
+	reporterNames := cfg.GetGlobal().GetEventReporter().GetChain()
+	reporterChain := make([]events.EventReporter, 0, len(reporterNames))
+	for _, reporter := range reporterNames {
+		targetPlugin, err := supplier.GetPlugin(common.TypeEventReporter, reporter)
+		if err != nil {
+			return nil, err
+		}
+		reporterChain = append(reporterChain, targetPlugin.(events.EventReporter))
+	}
+	return reporterChain, nil
+}
+
 // GetLoadBalancer 获取负载均衡插件
 func GetLoadBalancer(cfg config.Configuration, supplier plugin.Supplier) (loadbalancer.LoadBalancer, error) {
 	lbType := cfg.GetConsumer().GetLoadbalancer().GetType()
@@ -179,20 +198,28 @@ func GetLoadBalancerByLbType(lbType string, supplier plugin.Supplier) (loadbalan
 // SingleInvoke 同步调用的通用方法定义
 type SingleInvoke func(request interface{}) (interface{}, error)
 
-// RetrySyncCall 通用的带重试的同步调用逻辑
+// RetrySyncCall 通用的带重试的同步调用逻辑，param.Context 取消或超时后不再发起重试
 func RetrySyncCall(name string, svcKey *model.ServiceKey,
 	request interface{}, call SingleInvoke, param *model.ControlParam) (interface{}, model.SDKError) {
+	ctx := param.GetContext()
 	retryTimes := -1
 	var resp interface{}
 	var err error
 	retryInterval := param.RetryInterval
 	for retryTimes < param.MaxRetry {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return resp, model.NewContextError(ctxErr)
+		}
 		startTime := clock.GetClock().Now()
 		resp, err = call(request)
 		consumeTime := clock.GetClock().Now().Sub(startTime)
 		if err == nil {
 			return resp, nil
 		}
+		// 调用过程中ctx结束导致的失败，直接返回ctx的错误
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return resp, model.NewContextError(ctxErr)
+		}
 		sdkErr, ok := err.(model.SDKError)
 		if !ok || !sdkErr.ErrorCode().Retryable() {
 			return resp, sdkErr
@@ -201,7 +228,9 @@ func RetrySyncCall(name string, svcKey *model.ServiceKey,
 		if retryTimes >= param.MaxRetry {
 			break
 		}
-		time.Sleep(retryInterval)
+		if !SleepWithContext(ctx, retryInterval) {
+			return resp, model.NewContextError(ctx.Err())
+		}
 		log.GetBaseLogger().Warnf("retry %s for timeout, consume time %v,"+
 			" Namespace: %s, Service: %s, retry times: %d",
 			name, consumeTime, svcKey.Namespace, svcKey.Service, retryTimes)
@@ -209,3 +238,18 @@ func RetrySyncCall(name string, svcKey *model.ServiceKey,
 	return resp, model.NewSDKError(model.ErrCodeAPITimeoutError, err,
 		fmt.Sprintf("fail to do %s after retry %v times", name, retryTimes))
 }
+
+// SleepWithContext 等待指定时长，ctx提前结束时返回false
+func SleepWithContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
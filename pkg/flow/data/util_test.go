@@ -0,0 +1,137 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package data
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestRetrySyncCallContextCanceled 测试调用过程中取消ctx后中止等待及重试
+func TestRetrySyncCallContextCanceled(t *testing.T) {
+	svcKey := &model.ServiceKey{Namespace: "Test", Service: "svc"}
+	tests := []struct {
+		name string
+		// call 远程调用，返回前会收到started通知
+		call func(ctx context.Context, started chan<- struct{}) (interface{}, error)
+		// retryInterval 重试间隔
+		retryInterval time.Duration
+		// wantCode 期望的错误码
+		wantCode model.ErrCode
+		// cancelled 是否取消ctx，否则等待ctx超时
+		cancelled bool
+	}{
+		{
+			name: "阻塞调用中取消",
+			call: func(ctx context.Context, started chan<- struct{}) (interface{}, error) {
+				started <- struct{}{}
+				<-ctx.Done()
+				return nil, model.NewSDKError(model.ErrCodeNetworkError, ctx.Err(), "call aborted")
+			},
+			retryInterval: time.Millisecond,
+			wantCode:      model.ErrCodeInvalidStateError,
+			cancelled:     true,
+		},
+		{
+			name: "重试间隔中取消",
+			call: func(ctx context.Context, started chan<- struct{}) (interface{}, error) {
+				started <- struct{}{}
+				return nil, model.NewSDKError(model.ErrCodeNetworkError, errors.New("unavailable"), "call failed")
+			},
+			retryInterval: time.Hour,
+			wantCode:      model.ErrCodeInvalidStateError,
+			cancelled:     true,
+		},
+		{
+			name: "重试间隔中超时",
+			call: func(ctx context.Context, started chan<- struct{}) (interface{}, error) {
+				started <- struct{}{}
+				return nil, model.NewSDKError(model.ErrCodeNetworkError, errors.New("unavailable"), "call failed")
+			},
+			retryInterval: time.Hour,
+			wantCode:      model.ErrCodeAPITimeoutError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctx context.Context
+			var cancel context.CancelFunc
+			if tt.cancelled {
+				ctx, cancel = context.WithCancel(context.Background())
+			} else {
+				ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+			}
+			defer cancel()
+			param := &model.ControlParam{
+				Timeout:       time.Second,
+				MaxRetry:      5,
+				RetryInterval: tt.retryInterval,
+				Context:       ctx,
+			}
+			started := make(chan struct{}, 10)
+			var attempts int32
+			call := func(request interface{}) (interface{}, error) {
+				atomic.AddInt32(&attempts, 1)
+				return tt.call(ctx, started)
+			}
+			type result struct {
+				err model.SDKError
+			}
+			done := make(chan result, 1)
+			go func() {
+				_, err := RetrySyncCall("register", svcKey, nil, call, param)
+				done <- result{err: err}
+			}()
+			<-started
+			if tt.cancelled {
+				cancel()
+			}
+			select {
+			case res := <-done:
+				assert.NotNil(t, res.err)
+				assert.Equal(t, tt.wantCode, res.err.ErrorCode())
+			case <-time.After(5 * time.Second):
+				t.Fatal("RetrySyncCall not aborted by ctx")
+			}
+			assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+// TestRetrySyncCallCanceledBeforeCall 测试ctx已结束时不发起调用
+func TestRetrySyncCallCanceledBeforeCall(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	param := &model.ControlParam{Timeout: time.Second, MaxRetry: 3, Context: ctx}
+	var attempts int32
+	_, err := RetrySyncCall("heartbeat", &model.ServiceKey{Namespace: "Test", Service: "svc"}, nil,
+		func(request interface{}) (interface{}, error) {
+			atomic.AddInt32(&attempts, 1)
+			return nil, nil
+		}, param)
+	assert.NotNil(t, err)
+	assert.Equal(t, model.ErrCodeInvalidStateError, err.ErrorCode())
+	assert.Equal(t, int32(0), atomic.LoadInt32(&attempts))
+}
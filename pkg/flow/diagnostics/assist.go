@@ -0,0 +1,184 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package diagnostics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// Sample 一次慢调用或失败调用的诊断记录
+type Sample struct {
+	// Time 调用发生的时间
+	Time time.Time `json:"time"`
+	// API 调用的接口
+	API string `json:"api"`
+	// Delay 调用耗时
+	Delay string `json:"delay"`
+	// Slow 是否为慢调用
+	Slow bool `json:"slow"`
+	// Error 调用失败的错误信息
+	Error string `json:"error,omitempty"`
+	// Request 调用的请求参数
+	Request *RequestPayload `json:"request"`
+	// Route 路由决策过程
+	Route *servicerouter.RouteTrace `json:"route,omitempty"`
+	// LbPolicy 使用的负载均衡策略
+	LbPolicy string `json:"lbPolicy,omitempty"`
+	// Instance 负载均衡选中的实例
+	Instance string `json:"instance,omitempty"`
+}
+
+// RequestPayload 诊断记录中保存的请求参数
+type RequestPayload struct {
+	Namespace     string             `json:"namespace"`
+	Service       string             `json:"service"`
+	Metadata      map[string]string  `json:"metadata,omitempty"`
+	SourceService *model.ServiceInfo `json:"sourceService,omitempty"`
+	HashKey       string             `json:"hashKey,omitempty"`
+	Canary        string             `json:"canary,omitempty"`
+	TargetVersion string             `json:"targetVersion,omitempty"`
+	Routers       []string           `json:"routers,omitempty"`
+}
+
+// NewRequestPayload 根据获取单个实例的请求构建诊断记录的请求参数
+func NewRequestPayload(req *model.GetOneInstanceRequest) *RequestPayload {
+	payload := &RequestPayload{
+		Namespace:     req.Namespace,
+		Service:       req.Service,
+		HashKey:       string(req.HashKey),
+		Canary:        req.Canary,
+		TargetVersion: req.TargetVersion,
+		Routers:       req.Routers,
+	}
+	if len(req.Metadata) > 0 {
+		payload.Metadata = make(map[string]string, len(req.Metadata))
+		for k, v := range req.Metadata {
+			payload.Metadata[k] = v
+		}
+	}
+	if nil != req.SourceService {
+		srcService := *req.SourceService
+		payload.SourceService = &srcService
+	}
+	return payload
+}
+
+// DiagnosticsAssistant 调用诊断辅助类，按分钟限额采样慢调用及失败调用，并保存在环形缓冲区中
+type DiagnosticsAssistant struct {
+	enable        bool
+	slowThreshold time.Duration
+	maxPerMinute  int
+
+	mutex      sync.Mutex
+	minute     int64
+	sampledNum int
+	buffer     []*Sample
+	next       int
+	bufferFull bool
+}
+
+// Init 初始化
+func (d *DiagnosticsAssistant) Init(cfg config.Configuration) {
+	diagCfg := cfg.GetGlobal().GetDiagnostics()
+	d.enable = diagCfg.IsEnable()
+	d.slowThreshold = diagCfg.GetSlowThreshold()
+	d.maxPerMinute = diagCfg.GetMaxSamplesPerMinute()
+	d.buffer = make([]*Sample, diagCfg.GetBufferSize())
+}
+
+// IsEnable 是否启用调用诊断
+func (d *DiagnosticsAssistant) IsEnable() bool {
+	return d.enable
+}
+
+// StartTrace 开始记录一次调用的路由过程，未启用或者本分钟采样数已满时返回空
+func (d *DiagnosticsAssistant) StartTrace() *servicerouter.RouteTrace {
+	if !d.enable {
+		return nil
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.rollMinute(time.Now())
+	if d.sampledNum >= d.maxPerMinute {
+		return nil
+	}
+	return &servicerouter.RouteTrace{}
+}
+
+// ShouldSample 判断调用是否需要采样
+func (d *DiagnosticsAssistant) ShouldSample(delay time.Duration, err error) bool {
+	return err != nil || delay >= d.slowThreshold
+}
+
+// Record 保存一条诊断记录，本分钟采样数已满时丢弃
+func (d *DiagnosticsAssistant) Record(delay time.Duration, sample *Sample) {
+	sample.Delay = delay.String()
+	sample.Slow = delay >= d.slowThreshold
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.rollMinute(sample.Time)
+	if d.sampledNum >= d.maxPerMinute {
+		return
+	}
+	d.sampledNum++
+	d.buffer[d.next] = sample
+	d.next = (d.next + 1) % len(d.buffer)
+	if d.next == 0 {
+		d.bufferFull = true
+	}
+}
+
+// Samples 获取所有诊断记录，按时间倒序排列
+func (d *DiagnosticsAssistant) Samples() []*Sample {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	count := d.next
+	if d.bufferFull {
+		count = len(d.buffer)
+	}
+	samples := make([]*Sample, 0, count)
+	for i := 1; i <= count; i++ {
+		samples = append(samples, d.buffer[(d.next-i+len(d.buffer))%len(d.buffer)])
+	}
+	return samples
+}
+
+// Clear 清空诊断记录
+func (d *DiagnosticsAssistant) Clear() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for i := range d.buffer {
+		d.buffer[i] = nil
+	}
+	d.next = 0
+	d.bufferFull = false
+}
+
+// rollMinute 进入新的一分钟时重置采样计数
+func (d *DiagnosticsAssistant) rollMinute(now time.Time) {
+	minute := now.Unix() / 60
+	if minute != d.minute {
+		d.minute = minute
+		d.sampledNum = 0
+	}
+}
@@ -0,0 +1,55 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/flow/diagnostics"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// recordDiagnostics 慢调用或失败调用时记录请求参数、路由过程及负载均衡结果
+func (e *Engine) recordDiagnostics(delay time.Duration, req *model.GetOneInstanceRequest,
+	commonRequest *data.CommonInstancesRequest, resp *model.OneInstanceResponse, err error) {
+	if !e.diagnosticsAssistant.ShouldSample(delay, err) {
+		return
+	}
+	sample := &diagnostics.Sample{
+		Time:    e.globalCtx.Now(),
+		API:     model.ApiGetOneInstance.String(),
+		Request: diagnostics.NewRequestPayload(req),
+		Route:   commonRequest.RouteInfo.Trace,
+	}
+	if err != nil {
+		sample.Error = err.Error()
+	}
+	if nil != commonRequest.DstInstances {
+		if balancer, lbErr := e.getLoadBalancer(commonRequest.DstInstances, commonRequest.LbPolicy); lbErr == nil {
+			sample.LbPolicy = balancer.Name()
+		}
+	}
+	if nil != resp {
+		if inst := resp.GetInstance(); nil != inst {
+			sample.Instance = fmt.Sprintf("%s:%d", inst.GetHost(), inst.GetPort())
+		}
+	}
+	e.diagnosticsAssistant.Record(delay, sample)
+}
@@ -0,0 +1,88 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"github.com/modern-go/reflect2"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// SyncReportEvent 上报治理事件到事件上报插件中
+func (e *Engine) SyncReportEvent(event *model.GovernanceEvent) error {
+	if event == nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "governance event is nil")
+	}
+	if len(e.eventReporterChain) == 0 {
+		return nil
+	}
+	if len(event.ClientID) == 0 && e.globalCtx != nil {
+		event.ClientID = e.globalCtx.GetClientId()
+	}
+	for _, reporter := range e.eventReporterChain {
+		if err := reporter.ReportEvent(event); err != nil {
+			log.Logw(log.GetBaseLogger(), log.ErrorLog, "fail to report event",
+				"event", event.EventType, "reporter", reporter.Name(), "err", err)
+		}
+	}
+	return nil
+}
+
+// reportRuleEvent 规则版本号变化时上报路由或限流规则的变更事件
+func (e *Engine) reportRuleEvent(event *common.PluginEvent) {
+	if len(e.eventReporterChain) == 0 || event.EventType != common.OnServiceUpdated {
+		return
+	}
+	svcEventObject, ok := event.EventObject.(*common.ServiceEventObject)
+	if !ok {
+		return
+	}
+	var eventType model.GovernanceEventType
+	switch svcEventObject.SvcEventKey.Type {
+	case model.EventRouting:
+		eventType = model.EventRouteRuleUpdated
+	case model.EventRateLimiting:
+		eventType = model.EventRateLimitRuleChanged
+	default:
+		return
+	}
+	oldRevision := ruleRevision(svcEventObject.OldValue)
+	newRevision := ruleRevision(svcEventObject.NewValue)
+	if oldRevision == newRevision {
+		return
+	}
+	govEvent := model.NewGovernanceEvent(eventType, &svcEventObject.SvcEventKey.ServiceKey)
+	govEvent.PreviousStatus = oldRevision
+	govEvent.CurrentStatus = newRevision
+	govEvent.Reason = "rule revision changed"
+	_ = e.SyncReportEvent(govEvent)
+}
+
+// ruleRevision 从缓存值中提取规则的版本号
+func ruleRevision(value interface{}) string {
+	if reflect2.IsNil(value) {
+		return ""
+	}
+	rule, ok := value.(model.ServiceRule)
+	if !ok {
+		return ""
+	}
+	return rule.GetRevision()
+}
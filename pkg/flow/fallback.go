@@ -0,0 +1,129 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package flow
+
+import (
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// fallbackKey 降级函数索引，method为空表示服务级别
+type fallbackKey struct {
+	model.ServiceKey
+	method string
+}
+
+// instanceFallbacks 按服务及方法维度注册的熔断降级函数
+type instanceFallbacks struct {
+	rwMutex   sync.RWMutex
+	fallbacks map[fallbackKey]model.InstanceFallbackFunction
+}
+
+func newInstanceFallbacks() *instanceFallbacks {
+	return &instanceFallbacks{
+		fallbacks: make(map[fallbackKey]model.InstanceFallbackFunction),
+	}
+}
+
+// register 注册降级函数，fallback为nil时删除
+func (f *instanceFallbacks) register(req *model.RegisterFallbackRequest) {
+	key := fallbackKey{
+		ServiceKey: model.ServiceKey{Namespace: req.Namespace, Service: req.Service},
+		method:     req.Method,
+	}
+	f.rwMutex.Lock()
+	defer f.rwMutex.Unlock()
+	if req.Fallback == nil {
+		delete(f.fallbacks, key)
+		return
+	}
+	f.fallbacks[key] = req.Fallback
+}
+
+// lookup 优先匹配方法级别的降级函数，其次为服务级别
+func (f *instanceFallbacks) lookup(svcKey model.ServiceKey, method string) model.InstanceFallbackFunction {
+	f.rwMutex.RLock()
+	defer f.rwMutex.RUnlock()
+	if len(f.fallbacks) == 0 {
+		return nil
+	}
+	if method != "" {
+		if fallback, ok := f.fallbacks[fallbackKey{ServiceKey: svcKey, method: method}]; ok {
+			return fallback
+		}
+	}
+	return f.fallbacks[fallbackKey{ServiceKey: svcKey}]
+}
+
+// RegisterInstanceFallback 注册服务实例全部熔断时的降级函数
+func (e *Engine) RegisterInstanceFallback(req *model.RegisterFallbackRequest) error {
+	e.instanceFallbacks.register(req)
+	return nil
+}
+
+// tryInstanceFallback 路由后的实例全部被熔断且注册了降级函数时，执行降级并返回合成应答
+func (e *Engine) tryInstanceFallback(req *model.GetOneInstanceRequest,
+	commonRequest *data.CommonInstancesRequest) (*model.OneInstanceResponse, bool, error) {
+	fallback := e.instanceFallbacks.lookup(commonRequest.DstService, getRequestMethod(req.Arguments))
+	if fallback == nil || commonRequest.Criteria.Cluster == nil {
+		return nil, false, nil
+	}
+	instances, _ := commonRequest.Criteria.Cluster.GetAllInstances()
+	if !allInstancesCircuitBroken(instances) {
+		return nil, false, nil
+	}
+	log.GetBaseLogger().Warnf("[CircuitBreaker] all instances of %s are circuit broken, invoke fallback",
+		commonRequest.DstService)
+	resp, err := fallback(req)
+	if err != nil {
+		return nil, true, err
+	}
+	if resp == nil {
+		resp = &model.OneInstanceResponse{}
+	}
+	resp.Fallback = true
+	return resp, true, nil
+}
+
+// allInstancesCircuitBroken 非隔离的实例是否全部处于熔断打开状态
+func allInstancesCircuitBroken(instances []model.Instance) bool {
+	var candidates int
+	for _, instance := range instances {
+		if instance.IsIsolated() || instance.GetWeight() == 0 {
+			continue
+		}
+		candidates++
+		cbStatus := instance.GetCircuitBreakerStatus()
+		if cbStatus == nil || cbStatus.GetStatus() != model.Open {
+			return false
+		}
+	}
+	return candidates > 0
+}
+
+// getRequestMethod 从路由参数中获取被调方法
+func getRequestMethod(arguments []model.Argument) string {
+	for _, argument := range arguments {
+		if argument.ArgumentType() == model.ArgumentTypeMethod {
+			return argument.Value()
+		}
+	}
+	return ""
+}
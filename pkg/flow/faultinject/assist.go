@@ -0,0 +1,158 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package faultinject
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/match"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// FaultInjectionAssistant 故障注入流程的辅助类，规则以配置文件的形式从北极星配置中心获取并监听变更
+type FaultInjectionAssistant struct {
+	// 是否启用故障注入
+	enable bool
+	// 流程执行引擎
+	engine model.Engine
+	// 规则所在的配置文件
+	fileReq *model.GetConfigFileRequest
+	// 规则是否已加载
+	loaded uint32
+	// 加载锁
+	mutex sync.Mutex
+	// 当前生效的规则，类型为[]*model.FaultInjectionRule
+	rules atomic.Value
+}
+
+// Init 初始化
+func (f *FaultInjectionAssistant) Init(engine model.Engine, cfg config.Configuration) error {
+	f.engine = engine
+	faultCfg := cfg.GetConsumer().GetFaultInjection()
+	f.enable = faultCfg.IsEnable()
+	f.fileReq = &model.GetConfigFileRequest{
+		Namespace: faultCfg.GetNamespace(),
+		FileGroup: faultCfg.GetFileGroup(),
+		FileName:  faultCfg.GetFileName(),
+		Subscribe: true,
+	}
+	f.rules.Store([]*model.FaultInjectionRule{})
+	return nil
+}
+
+// InjectFault 根据故障注入规则计算本次调用需要注入的故障，规则获取失败时不注入
+func (f *FaultInjectionAssistant) InjectFault(req *model.InjectFaultRequest) (*model.InjectFaultResponse, error) {
+	resp := &model.InjectFaultResponse{}
+	if !f.enable {
+		return resp, nil
+	}
+	if err := f.loadRules(); err != nil {
+		return resp, err
+	}
+	rules := f.rules.Load().([]*model.FaultInjectionRule)
+	for _, rule := range rules {
+		if !matchRule(rule, req) {
+			continue
+		}
+		resp.RuleName = rule.Name
+		if rule.Delay != nil && hitPercentage(rule.Delay.Percentage) {
+			resp.Delay = time.Duration(rule.Delay.DelayMs) * time.Millisecond
+		}
+		if rule.Abort != nil && hitPercentage(rule.Abort.Percentage) {
+			resp.Abort = true
+			resp.AbortCode = rule.Abort.Code
+			resp.AbortMessage = rule.Abort.Message
+		}
+		return resp, nil
+	}
+	return resp, nil
+}
+
+// loadRules 首次调用时获取规则配置文件，并监听后续的变更
+func (f *FaultInjectionAssistant) loadRules() error {
+	if atomic.LoadUint32(&f.loaded) == 1 {
+		return nil
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if atomic.LoadUint32(&f.loaded) == 1 {
+		return nil
+	}
+	configFile, err := f.engine.SyncGetConfigFile(f.fileReq)
+	if err != nil {
+		log.GetBaseLogger().Errorf("[FaultInjection] fail to get rule file %s/%s/%s, err %v",
+			f.fileReq.Namespace, f.fileReq.FileGroup, f.fileReq.FileName, err)
+		return err
+	}
+	f.updateRules(configFile.GetContent())
+	configFile.AddChangeListener(func(event model.ConfigFileChangeEvent) {
+		f.updateRules(event.NewValue)
+	})
+	atomic.StoreUint32(&f.loaded, 1)
+	return nil
+}
+
+// updateRules 解析规则内容，解析失败时保留原有规则
+func (f *FaultInjectionAssistant) updateRules(content string) {
+	parsed, err := model.ParseFaultInjectionRules(content)
+	if err != nil {
+		log.GetBaseLogger().Errorf("[FaultInjection] fail to parse rules, keep the previous rules, err %v", err)
+		return
+	}
+	rules := make([]*model.FaultInjectionRule, 0, len(parsed.Rules))
+	for _, rule := range parsed.Rules {
+		if rule.Enable {
+			rules = append(rules, rule)
+		}
+	}
+	f.rules.Store(rules)
+	log.GetBaseLogger().Infof("[FaultInjection] rules updated, enabled rule count %d", len(rules))
+}
+
+func matchRule(rule *model.FaultInjectionRule, req *model.InjectFaultRequest) bool {
+	if !matchAll(rule.Namespace, req.Namespace) || !matchAll(rule.Service, req.Service) ||
+		!matchAll(rule.Method, req.Method) {
+		return false
+	}
+	for key, expect := range rule.Labels {
+		actual, ok := req.Labels[key]
+		if !ok {
+			return false
+		}
+		if expect != match.MatchAll && expect != actual {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAll(expect, actual string) bool {
+	return expect == "" || expect == match.MatchAll || expect == actual
+}
+
+func hitPercentage(percentage float64) bool {
+	if percentage <= 0 {
+		return false
+	}
+	return rand.Float64()*100 < percentage
+}
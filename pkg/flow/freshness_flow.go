@@ -0,0 +1,93 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package flow
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+// applyFreshness 按调用的新鲜度要求处理本地缓存
+// 缓存满足要求时只读本地缓存，否则先等待一次服务端应答，再走常规的获取流程
+func (e *Engine) applyFreshness(freshness *model.Freshness, req *data.CommonInstancesRequest) error {
+	if nil == freshness {
+		return nil
+	}
+	switch freshness.Mode {
+	case model.FreshnessCacheOnly:
+		req.ControlParam.CacheOnly = true
+	case model.FreshnessAcceptStale:
+		aware, ok := localregistry.GetFreshnessAware(e.registry)
+		if !ok {
+			return nil
+		}
+		age, exists := aware.GetInstancesAge(&req.DstService)
+		if !exists {
+			// 首次获取，走常规流程
+			return nil
+		}
+		if age <= freshness.MaxStaleness {
+			// 直接使用缓存，服务实例由连接器在后台持续刷新
+			req.ControlParam.CacheOnly = true
+			return nil
+		}
+		return e.waitFreshInstances(aware, req)
+	case model.FreshnessMustBeFresh:
+		aware, ok := localregistry.GetFreshnessAware(e.registry)
+		if !ok {
+			return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+				"localRegistry %s not support freshness control", e.registry.Name())
+		}
+		return e.waitFreshInstances(aware, req)
+	}
+	return nil
+}
+
+// waitFreshInstances 发起一次服务实例刷新，并等待服务端应答
+func (e *Engine) waitFreshInstances(aware localregistry.FreshnessAware, req *data.CommonInstancesRequest) error {
+	notifier, err := aware.RefreshInstances(&req.DstService)
+	if err != nil {
+		return err
+	}
+	timeout := req.ControlParam.Timeout * time.Duration(req.ControlParam.MaxRetry+1)
+	select {
+	case <-notifier.GetContext().Done():
+		if sdkErr := notifier.GetError(); sdkErr != nil {
+			return sdkErr
+		}
+		return nil
+	case <-time.After(timeout):
+		return model.NewSDKError(model.ErrCodeAPITimeoutError, nil,
+			"wait fresh instances of %s timeout after %v", req.DstService, timeout)
+	}
+}
+
+// getResourcesFromCache 只从本地缓存获取资源，不等待远程加载
+func (e *Engine) getResourcesFromCache(req model.CacheValueQuery) error {
+	success, err := tryGetServiceValuesFromCache(e.registry, req)
+	if success {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return model.NewSDKError(model.ErrCodeServiceNotFound, nil,
+		"resource of %s not found in local cache", *req.GetDstService())
+}
@@ -0,0 +1,112 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// SyncGetInstancesByHashKey 沿一致性hash环获取hash key对应的多个不同实例，每个副本都会按健康及熔断状态过滤
+func (e *Engine) SyncGetInstancesByHashKey(
+	req *model.GetInstancesByHashKeyRequest) (*model.HashKeyInstancesResponse, error) {
+	oneReq := &req.GetOneInstanceRequest
+	replicas := req.Replicas
+	if replicas == 0 {
+		replicas = 1
+	}
+	instancesResp, err := e.SyncGetInstances(toGetInstancesRequest(oneReq))
+	if err != nil {
+		return nil, err
+	}
+	allInstances := instancesResp.GetInstances()
+	if len(allInstances) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"no instance available, namespace %s, service %s", oneReq.Namespace, oneReq.Service)
+	}
+	lbPolicy := oneReq.LbPolicy
+	if len(lbPolicy) == 0 {
+		lbPolicy = config.DefaultLoadBalancerRingHash
+	}
+	svcInfo := model.ServiceInfo{
+		Namespace: instancesResp.GetNamespace(),
+		Service:   instancesResp.GetService(),
+		Metadata:  instancesResp.GetMetadata(),
+	}
+	// 备份节点数取全部实例数，得到从hash key所在节点开始沿环排列的全部不同实例，再逐个过滤
+	lbResp, err := e.ProcessLoadBalance(&model.ProcessLoadBalanceRequest{
+		DstInstances:   model.NewDefaultServiceInstances(svcInfo, allInstances),
+		LbPolicy:       lbPolicy,
+		HashKey:        oneReq.HashKey,
+		ReplicateCount: len(allInstances) - 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	selected := selectHashReplicas(lbResp.GetInstances(), replicas, oneReq.IncludeCircuitBreakInstances)
+	if len(selected) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"no instance available for hash key, namespace %s, service %s", oneReq.Namespace, oneReq.Service)
+	}
+	return &model.HashKeyInstancesResponse{Instances: selected}, nil
+}
+
+// selectHashReplicas 按环上顺序从候选实例中选取至多 replicas 个可用副本，
+// 半开实例只允许少量探测请求，排在正常实例之后，仅在正常实例不足时补齐
+func selectHashReplicas(candidates []model.Instance, replicas int, includeCircuitBreak bool) []model.Instance {
+	selected := make([]model.Instance, 0, replicas)
+	var halfOpens []model.Instance
+	for _, instance := range candidates {
+		if len(selected) == replicas {
+			break
+		}
+		if !isReplicaAvailable(instance, includeCircuitBreak) {
+			continue
+		}
+		if isHalfOpen(instance) && !includeCircuitBreak {
+			halfOpens = append(halfOpens, instance)
+			continue
+		}
+		selected = append(selected, instance)
+	}
+	for _, instance := range halfOpens {
+		if len(selected) == replicas {
+			break
+		}
+		selected = append(selected, instance)
+	}
+	return selected
+}
+
+// isReplicaAvailable 实例是否可以作为副本，隔离、不健康及熔断打开的实例会被跳过
+func isReplicaAvailable(instance model.Instance, includeCircuitBreak bool) bool {
+	if instance.IsIsolated() || !instance.IsHealthy() || instance.GetWeight() == 0 {
+		return false
+	}
+	if includeCircuitBreak {
+		return true
+	}
+	cbStatus := instance.GetCircuitBreakerStatus()
+	return cbStatus == nil || cbStatus.GetStatus() != model.Open
+}
+
+// isHalfOpen 实例是否处于熔断半开状态
+func isHalfOpen(instance model.Instance) bool {
+	cbStatus := instance.GetCircuitBreakerStatus()
+	return cbStatus != nil && cbStatus.GetStatus() == model.HalfOpen
+}
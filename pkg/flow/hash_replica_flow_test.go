@@ -0,0 +1,148 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// replicaOption 测试实例的状态
+type replicaOption struct {
+	id        string
+	unhealthy bool
+	isolated  bool
+	// zeroWeight 权重为0，否则使用默认权重100
+	zeroWeight bool
+	// cbStatus 熔断状态，零值表示没有熔断记录
+	cbStatus model.Status
+}
+
+func buildReplica(opt replicaOption) model.Instance {
+	weight := uint32(100)
+	if opt.zeroWeight {
+		weight = 0
+	}
+	localValue := local.NewInstanceLocalValue()
+	if opt.cbStatus != 0 {
+		localValue.(*local.DefaultInstanceLocalValue).SetCircuitBreakerStatus(
+			model.NewCircuitBreakerStatus("test", opt.cbStatus, time.Now()))
+	}
+	return pb.NewInstanceInProto(&apiservice.Instance{
+		Id:      wrapperspb.String(opt.id),
+		Host:    wrapperspb.String("127.0.0.1"),
+		Port:    wrapperspb.UInt32(8080),
+		Healthy: wrapperspb.Bool(!opt.unhealthy),
+		Isolate: wrapperspb.Bool(opt.isolated),
+		Weight:  wrapperspb.UInt32(weight),
+	}, &model.ServiceKey{Namespace: "default", Service: "svc"}, localValue)
+}
+
+func TestSelectHashReplicas(t *testing.T) {
+	tests := []struct {
+		name                string
+		candidates          []replicaOption
+		replicas            int
+		includeCircuitBreak bool
+		expect              []string
+	}{
+		{
+			name:       "按环上顺序取前N个实例",
+			candidates: []replicaOption{{id: "a"}, {id: "b"}, {id: "c"}},
+			replicas:   2,
+			expect:     []string{"a", "b"},
+		},
+		{
+			name:       "实例数不足时返回全部可用实例",
+			candidates: []replicaOption{{id: "a"}, {id: "b"}},
+			replicas:   3,
+			expect:     []string{"a", "b"},
+		},
+		{
+			name: "跳过不健康、隔离、权重为0及熔断打开的实例",
+			candidates: []replicaOption{
+				{id: "a", unhealthy: true},
+				{id: "b", isolated: true},
+				{id: "c", zeroWeight: true},
+				{id: "d", cbStatus: model.Open},
+				{id: "e", cbStatus: model.Close},
+				{id: "f"},
+			},
+			replicas: 2,
+			expect:   []string{"e", "f"},
+		},
+		{
+			name: "半开实例排在正常实例之后",
+			candidates: []replicaOption{
+				{id: "a", cbStatus: model.HalfOpen},
+				{id: "b"},
+				{id: "c"},
+			},
+			replicas: 2,
+			expect:   []string{"b", "c"},
+		},
+		{
+			name: "正常实例不足时由半开实例补齐",
+			candidates: []replicaOption{
+				{id: "a", cbStatus: model.HalfOpen},
+				{id: "b"},
+				{id: "c", cbStatus: model.Open},
+			},
+			replicas: 2,
+			expect:   []string{"b", "a"},
+		},
+		{
+			name: "包含熔断实例时保持环上顺序",
+			candidates: []replicaOption{
+				{id: "a", cbStatus: model.HalfOpen},
+				{id: "b", cbStatus: model.Open},
+				{id: "c"},
+			},
+			replicas:            2,
+			includeCircuitBreak: true,
+			expect:              []string{"a", "b"},
+		},
+		{
+			name:       "无可用实例",
+			candidates: []replicaOption{{id: "a", unhealthy: true}, {id: "b", cbStatus: model.Open}},
+			replicas:   1,
+			expect:     []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := make([]model.Instance, 0, len(tt.candidates))
+			for _, opt := range tt.candidates {
+				candidates = append(candidates, buildReplica(opt))
+			}
+			ids := []string{}
+			for _, instance := range selectHashReplicas(candidates, tt.replicas, tt.includeCircuitBreak) {
+				ids = append(ids, instance.GetId())
+			}
+			assert.Equal(t, tt.expect, ids)
+		})
+	}
+}
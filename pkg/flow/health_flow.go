@@ -0,0 +1,92 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+const (
+	// healthReportInterval SDK自监控指标的上报周期
+	healthReportInterval = 30 * time.Second
+	// discoverQueueName 服务发现任务队列在指标中的名称
+	discoverQueueName = "discover"
+)
+
+// healthReportCallBack SDK自监控指标上报任务回调
+type healthReportCallBack struct {
+	engine *Engine
+}
+
+// Process 执行任务
+func (h *healthReportCallBack) Process(
+	taskKey interface{}, taskValue interface{}, lastProcessTime time.Time) model.TaskResult {
+	if !lastProcessTime.IsZero() && time.Since(lastProcessTime) < healthReportInterval {
+		return model.SKIP
+	}
+	_ = h.engine.SyncReportStat(model.SDKHealthStat, h.engine.collectHealthGauge())
+	return model.CONTINUE
+}
+
+// OnTaskEvent 任务事件回调
+func (h *healthReportCallBack) OnTaskEvent(event model.TaskEvent) {
+
+}
+
+// addHealthReportTask 添加定期上报SDK自监控指标的任务，未启用统计上报插件时不添加
+func (e *Engine) addHealthReportTask() model.TaskValues {
+	if len(e.reporterChain) == 0 {
+		return nil
+	}
+	_, taskValues := e.ScheduleTask(&model.PeriodicTask{
+		Name:         taskHealthReport,
+		CallBack:     &healthReportCallBack{engine: e},
+		TakePriority: false,
+		LongRun:      true,
+		Period:       healthReportInterval / 2,
+	})
+	return taskValues
+}
+
+// collectHealthGauge 采集本地缓存、连接器及任务队列的运行状态
+func (e *Engine) collectHealthGauge() *model.SDKHealthGauge {
+	gauge := &model.SDKHealthGauge{
+		TaskQueueDepths: make(map[string]int),
+		Goroutines:      runtime.NumGoroutine(),
+	}
+	if statistics, ok := localregistry.GetCacheStatistics(e.registry); ok {
+		gauge.CachedServices, gauge.CachedInstances, gauge.PersistFailures = statistics.GetCacheStatistics()
+	}
+	if statistics, ok := serverconnector.GetHealthStatistics(e.connector); ok {
+		gauge.PendingDiscoverRequests = statistics.GetPendingDiscoverRequests()
+		gauge.ReconnectCount = statistics.GetReconnectCount()
+		gauge.TaskQueueDepths[discoverQueueName] = statistics.GetDiscoverQueueDepth()
+	}
+	e.taskMutex.Lock()
+	routines := e.taskRoutines
+	e.taskMutex.Unlock()
+	for _, routine := range routines {
+		gauge.TaskQueueDepths[routine.GetName()] += routine.GetQueueDepth()
+	}
+	return gauge
+}
@@ -0,0 +1,161 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package hedging
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// hedgeGroupTTL 对冲请求标识的保留时间，超时后不再参与去重
+const hedgeGroupTTL = time.Minute
+
+// HedgingAssistant 对冲请求协助辅助类，负责统计服务调用时延及对冲调用结果去重
+type HedgingAssistant struct {
+	cfg       config.HedgingConfig
+	samplers  sync.Map
+	groups    sync.Map
+	lastSweep int64
+}
+
+// Init 初始化
+func (h *HedgingAssistant) Init(cfg config.Configuration) {
+	h.cfg = cfg.GetConsumer().GetHedging()
+	h.lastSweep = time.Now().UnixNano()
+}
+
+// NewHedgeID 生成对冲请求标识
+func (h *HedgingAssistant) NewHedgeID() string {
+	now := time.Now()
+	h.sweep(now)
+	hedgeID := uuid.New().String()
+	h.groups.Store(hedgeID, &hedgeGroup{createTime: now})
+	return hedgeID
+}
+
+// AcquireReport 同一对冲请求只有最先上报的结果返回true，其余调用多为被取消的调用，不计入调用统计及熔断统计
+func (h *HedgingAssistant) AcquireReport(hedgeID string) bool {
+	value, ok := h.groups.Load(hedgeID)
+	if !ok {
+		// 未知或已过期的标识，按普通调用处理
+		return true
+	}
+	return atomic.CompareAndSwapInt32(&value.(*hedgeGroup).reported, 0, 1)
+}
+
+// RecordLatency 记录服务成功调用的时延
+func (h *HedgingAssistant) RecordLatency(svcKey model.ServiceKey, delay time.Duration) {
+	value, ok := h.samplers.Load(svcKey)
+	if !ok {
+		value, _ = h.samplers.LoadOrStore(svcKey, newLatencySampler(h.cfg.GetSampleSize()))
+	}
+	value.(*latencySampler).add(delay)
+}
+
+// HedgeDelays 计算count个备份调用的发起延迟，第i个备份在主调用发出后 i*分位时延 发起
+func (h *HedgingAssistant) HedgeDelays(svcKey model.ServiceKey, count int) []time.Duration {
+	base := h.cfg.GetDefaultDelay()
+	if value, ok := h.samplers.Load(svcKey); ok {
+		if delay, ok := value.(*latencySampler).percentile(h.cfg.GetPercentile(), h.cfg.GetMinSamples()); ok {
+			base = delay
+		}
+	}
+	delays := make([]time.Duration, 0, count)
+	for i := 1; i <= count; i++ {
+		delays = append(delays, base*time.Duration(i))
+	}
+	return delays
+}
+
+// sweep 定期清理过期的对冲请求标识
+func (h *HedgingAssistant) sweep(now time.Time) {
+	last := atomic.LoadInt64(&h.lastSweep)
+	if now.UnixNano()-last < int64(hedgeGroupTTL) {
+		return
+	}
+	if !atomic.CompareAndSwapInt64(&h.lastSweep, last, now.UnixNano()) {
+		return
+	}
+	h.groups.Range(func(key, value interface{}) bool {
+		if now.Sub(value.(*hedgeGroup).createTime) > hedgeGroupTTL {
+			h.groups.Delete(key)
+		}
+		return true
+	})
+}
+
+// hedgeGroup 一次对冲请求的上报状态
+type hedgeGroup struct {
+	createTime time.Time
+	reported   int32
+}
+
+// latencySampler 保留最近若干次调用时延的环形缓冲
+type latencySampler struct {
+	mutex   sync.Mutex
+	samples []time.Duration
+	next    int
+	full    bool
+}
+
+func newLatencySampler(size int) *latencySampler {
+	return &latencySampler{samples: make([]time.Duration, size)}
+}
+
+func (l *latencySampler) add(delay time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.samples[l.next] = delay
+	l.next++
+	if l.next == len(l.samples) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// percentile 计算时延分位值，样本数不足minSamples时返回false
+func (l *latencySampler) percentile(percent float64, minSamples int) (time.Duration, bool) {
+	l.mutex.Lock()
+	count := l.next
+	if l.full {
+		count = len(l.samples)
+	}
+	if count == 0 || count < minSamples {
+		l.mutex.Unlock()
+		return 0, false
+	}
+	sorted := make([]time.Duration, count)
+	copy(sorted, l.samples[:count])
+	l.mutex.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	index := int(math.Ceil(percent/100*float64(count))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index], true
+}
@@ -0,0 +1,134 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package hedging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+var testSvcKey = model.ServiceKey{Namespace: "Test", Service: "svc"}
+
+// newTestAssistant 创建时延样本数为sampleSize、最小样本数为minSamples、按P50计算对冲延迟的协助类
+func newTestAssistant(sampleSize int, minSamples int) *HedgingAssistant {
+	cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	hedgingCfg := cfg.GetConsumer().GetHedging()
+	hedgingCfg.SetPercentile(50)
+	hedgingCfg.SetDefaultDelay(10 * time.Millisecond)
+	hedgingCfg.SetSampleSize(sampleSize)
+	hedgingCfg.SetMinSamples(minSamples)
+	h := &HedgingAssistant{}
+	h.Init(cfg)
+	return h
+}
+
+// TestAcquireReport 测试同一对冲请求只有最先上报的结果参与统计
+func TestAcquireReport(t *testing.T) {
+	h := newTestAssistant(10, 1)
+	first := h.NewHedgeID()
+	second := h.NewHedgeID()
+	assert.NotEqual(t, first, second)
+
+	assert.True(t, h.AcquireReport(first))
+	assert.False(t, h.AcquireReport(first))
+	assert.False(t, h.AcquireReport(first))
+	// 不同对冲请求互不影响
+	assert.True(t, h.AcquireReport(second))
+	// 未知的标识按普通调用处理，每次都参与统计
+	assert.True(t, h.AcquireReport("unknown"))
+	assert.True(t, h.AcquireReport("unknown"))
+}
+
+// TestSweepExpiredHedgeID 测试过期的对冲请求标识被清理，清理后不再参与去重
+func TestSweepExpiredHedgeID(t *testing.T) {
+	h := newTestAssistant(10, 1)
+	expired := h.NewHedgeID()
+	alive := h.NewHedgeID()
+	assert.True(t, h.AcquireReport(expired))
+	assert.True(t, h.AcquireReport(alive))
+
+	past := time.Now().Add(-2 * hedgeGroupTTL)
+	value, _ := h.groups.Load(expired)
+	value.(*hedgeGroup).createTime = past
+	// 未到清理周期时不清理
+	h.NewHedgeID()
+	assert.False(t, h.AcquireReport(expired))
+
+	h.lastSweep = past.UnixNano()
+	h.NewHedgeID()
+	_, ok := h.groups.Load(expired)
+	assert.False(t, ok)
+	assert.True(t, h.AcquireReport(expired))
+	assert.False(t, h.AcquireReport(alive))
+}
+
+// TestHedgeDelays 测试对冲延迟按时延分位值计算，样本不足时使用默认延迟
+func TestHedgeDelays(t *testing.T) {
+	tests := []struct {
+		name       string
+		sampleSize int
+		minSamples int
+		samples    []time.Duration
+		want       []time.Duration
+	}{
+		{
+			name:       "没有样本时使用默认延迟",
+			sampleSize: 4,
+			minSamples: 1,
+			want:       []time.Duration{10 * time.Millisecond, 20 * time.Millisecond},
+		},
+		{
+			name:       "样本数不足时使用默认延迟",
+			sampleSize: 4,
+			minSamples: 3,
+			samples:    []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+			want:       []time.Duration{10 * time.Millisecond, 20 * time.Millisecond},
+		},
+		{
+			name:       "按分位值计算对冲延迟",
+			sampleSize: 4,
+			minSamples: 3,
+			samples:    []time.Duration{300 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond},
+			want:       []time.Duration{200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:       "只保留最近的样本",
+			sampleSize: 2,
+			minSamples: 2,
+			samples: []time.Duration{
+				900 * time.Millisecond, 800 * time.Millisecond, 100 * time.Millisecond, 300 * time.Millisecond,
+			},
+			want: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestAssistant(tt.sampleSize, tt.minSamples)
+			for _, sample := range tt.samples {
+				h.RecordLatency(testSvcKey, sample)
+			}
+			// 其他服务的样本不影响本服务的对冲延迟
+			h.RecordLatency(model.ServiceKey{Namespace: "Test", Service: "other"}, time.Hour)
+			assert.Equal(t, tt.want, h.HedgeDelays(testSvcKey, len(tt.want)))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package flow
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// SyncGetInstancesForHedging 获取对冲请求的主实例及备份实例，并根据服务调用时延分位值计算对冲延迟
+func (e *Engine) SyncGetInstancesForHedging(
+	req *model.GetInstancesForHedgingRequest) (*model.HedgingInstancesResponse, error) {
+	oneReq := &req.GetOneInstanceRequest
+	instancesResp, err := e.SyncGetInstances(toGetInstancesRequest(oneReq))
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]struct{}, req.BackupCount+1)
+	primary, err := e.selectUntriedInstance(oneReq, instancesResp, selected)
+	if err != nil {
+		return nil, err
+	}
+	selected[primary.GetId()] = struct{}{}
+
+	total := len(instancesResp.GetInstances())
+	backups := make([]model.Instance, 0, req.BackupCount)
+	for len(backups) < req.BackupCount && len(selected) < total {
+		backup, err := e.selectUntriedInstance(oneReq, instancesResp, selected)
+		if err != nil {
+			break
+		}
+		selected[backup.GetId()] = struct{}{}
+		backups = append(backups, backup)
+	}
+	svcKey := model.ServiceKey{Namespace: oneReq.Namespace, Service: oneReq.Service}
+	return &model.HedgingInstancesResponse{
+		HedgeID:     e.hedgingAssistant.NewHedgeID(),
+		Primary:     primary,
+		Backups:     backups,
+		HedgeDelays: e.hedgingAssistant.HedgeDelays(svcKey, len(backups)),
+	}, nil
+}
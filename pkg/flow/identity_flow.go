@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/identity"
+)
+
+// newIdentityInterceptor 在路由及限流请求中携带调用方的工作负载身份，用户已显式设置同名标签时不覆盖
+func newIdentityInterceptor(provider identity.IdentityProvider, labelKey string) model.Interceptor {
+	return func(inv *model.Invocation, next model.Invoker) (interface{}, error) {
+		id := provider.GetIdentity()
+		if id == "" {
+			return next(inv)
+		}
+		switch request := inv.Request.(type) {
+		case *model.GetOneInstanceRequest:
+			if !hasArgument(request.Arguments, labelKey) {
+				request.AddArguments(model.BuildCustomArgument(labelKey, id))
+			}
+		case *model.QuotaRequestImpl:
+			if !hasArgument(request.Arguments(), labelKey) {
+				request.AddArgument(model.BuildCustomArgument(labelKey, id))
+			}
+		}
+		return next(inv)
+	}
+}
+
+// hasArgument 是否已存在同名的自定义参数
+func hasArgument(arguments []model.Argument, key string) bool {
+	for _, argument := range arguments {
+		if argument.ArgumentType() == model.ArgumentTypeCustom && argument.Key() == key {
+			return true
+		}
+	}
+	return false
+}
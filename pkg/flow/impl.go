@@ -21,16 +21,26 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/modern-go/reflect2"
 
+	"github.com/polarismesh/polaris-go/pkg/cachecipher"
 	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/dispatcher"
+	"github.com/polarismesh/polaris-go/pkg/flow/adaptiveweight"
 	"github.com/polarismesh/polaris-go/pkg/flow/configuration"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/flow/diagnostics"
+	"github.com/polarismesh/polaris-go/pkg/flow/faultinject"
+	"github.com/polarismesh/polaris-go/pkg/flow/hedging"
 	"github.com/polarismesh/polaris-go/pkg/flow/quota"
 	"github.com/polarismesh/polaris-go/pkg/flow/registerstate"
+	"github.com/polarismesh/polaris-go/pkg/flow/reload"
+	"github.com/polarismesh/polaris-go/pkg/flow/retry"
 	"github.com/polarismesh/polaris-go/pkg/flow/schedule"
+	"github.com/polarismesh/polaris-go/pkg/flow/variable"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/model/pb"
@@ -38,12 +48,15 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
+	"github.com/polarismesh/polaris-go/pkg/plugin/events"
+	"github.com/polarismesh/polaris-go/pkg/plugin/identity"
 	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
 	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
 	"github.com/polarismesh/polaris-go/pkg/plugin/location"
 	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
 	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
 	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/pkg/scheduler"
 )
 
 // Engine 编排调度引擎，API相关逻辑在这里执行
@@ -58,14 +71,48 @@ type Engine struct {
 	configuration config.Configuration
 	// 只做过滤的服务路由插件实例
 	finalRouterPlugin servicerouter.ServiceRouter
-	// 服务路由责任链
-	routerChain *servicerouter.RouterChain
+	// 默认的服务路由责任链及负载均衡器，类型为*defaultRouting，配置热更新时整体替换
+	defaultRouting atomic.Value
 	// 上报插件链
 	reporterChain []statreporter.StatReporter
-	// 负载均衡器
-	loadbalancer loadbalancer.LoadBalancer
+	// 上报插件链中关注SDK自身API调用统计的插件
+	sdkStatReporters []statreporter.SDKStatReporter
+	// 各服务本地缓存命中的采样计数，ServiceKey -> *uint32
+	cacheHitCounters sync.Map
+	// 治理事件上报插件链
+	eventReporterChain []events.EventReporter
+	// 感知调用结果的负载均衡器
+	callResultAwareLBs []loadbalancer.CallResultAware
 	// 限流处理协助辅助类
 	flowQuotaAssistant *quota.FlowQuotaAssistant
+	// 故障注入协助辅助类
+	faultInjectionAssistant *faultinject.FaultInjectionAssistant
+	// 实例全部熔断时的降级函数
+	instanceFallbacks *instanceFallbacks
+	// SDK 调用拦截器
+	interceptors *interceptorChain
+	// 请求指定路由链的预解析结果
+	routerChains routerChainCache
+	// 合并并发的首次资源加载
+	resolveGroup *resolveGroup
+	// 调用重试协助辅助类
+	retryAssistant *retry.RetryAssistant
+	// 对冲请求协助辅助类
+	hedgingAssistant *hedging.HedgingAssistant
+	// 调用诊断辅助类
+	diagnosticsAssistant *diagnostics.DiagnosticsAssistant
+	// 路由变量解析辅助类
+	variableAssistant *variable.VariableAssistant
+	// SDK自身配置热更新辅助类
+	configReloader *reload.ConfigReloader
+	// SDK自身配置热更新监听器
+	configChangedListeners []configChangedListener
+	// 配置热更新监听器锁，同时保证热更新串行执行
+	configReloadMutex sync.Mutex
+	// 实例元数据加解密器，未启用元数据加密时为空
+	metadataCipher cachecipher.CacheCipher
+	// 注册时需要加密的元数据key
+	sensitiveMetadataKeys []string
 	// 全局上下文，在reportclient
 	globalCtx model.ValueContext
 	// 系统服务列表
@@ -74,6 +121,12 @@ type Engine struct {
 	plugins plugin.Supplier
 	// 任务调度协程
 	taskRoutines []schedule.TaskRoutine
+	// 守护任务调度协程列表
+	taskMutex sync.Mutex
+	// 周期任务在进程级调度器中的任务组
+	scheduleGroup *scheduler.Group
+	// 配置变更及实例变更监听器的回调分发协程池
+	listenerDispatcher *dispatcher.Pool
 	// 熔断引擎
 	circuitBreakerFlow *CircuitBreakerFlow
 	// 修改消息订阅插件链
@@ -82,10 +135,14 @@ type Engine struct {
 	configFlow *configuration.ConfigFlow
 	// 注册状态管理器
 	registerStates *registerstate.RegisterStateManager
+	// 自适应权重辅助类
+	adaptiveWeightAssistant *adaptiveweight.AdaptiveWeightAssistant
 	// watchEngine .
 	watchEngine *WatchEngine
 	// 配置过滤链
 	configFilterChain configfilter.Chain
+	// 本地管理端口
+	admin *adminServer
 }
 
 // InitFlowEngine 初始化flowEngine实例
@@ -112,6 +169,17 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 		if err != nil {
 			return err
 		}
+		for _, reporter := range flowEngine.reporterChain {
+			if sdkReporter, ok := statreporter.GetSDKStatReporter(reporter); ok {
+				flowEngine.sdkStatReporters = append(flowEngine.sdkStatReporters, sdkReporter)
+			}
+		}
+	}
+	if cfg.GetGlobal().GetEventReporter().IsEnable() {
+		flowEngine.eventReporterChain, err = data.GetEventReporterChain(cfg, plugins)
+		if err != nil {
+			return err
+		}
 	}
 
 	// 加载配置中心连接器
@@ -141,6 +209,46 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 	if err = flowEngine.flowQuotaAssistant.Init(flowEngine, flowEngine.configuration, flowEngine.plugins); err != nil {
 		return err
 	}
+	// 初始化故障注入
+	flowEngine.faultInjectionAssistant = &faultinject.FaultInjectionAssistant{}
+	if err = flowEngine.faultInjectionAssistant.Init(flowEngine, flowEngine.configuration); err != nil {
+		return err
+	}
+	flowEngine.instanceFallbacks = newInstanceFallbacks()
+	flowEngine.interceptors = newInterceptorChain()
+	flowEngine.resolveGroup = newResolveGroup()
+	// 启用工作负载身份时，在路由及限流请求中携带调用方身份
+	identityProvider, err := identity.GetIdentityProvider(cfg, flowEngine.plugins)
+	if err != nil {
+		return err
+	}
+	if identityProvider != nil {
+		flowEngine.interceptors.add(newIdentityInterceptor(identityProvider,
+			cfg.GetGlobal().GetIdentityProvider().GetLabelKey()))
+	}
+	// 初始化调用重试
+	flowEngine.retryAssistant = &retry.RetryAssistant{}
+	flowEngine.retryAssistant.Init(flowEngine.configuration)
+	// 初始化对冲请求
+	flowEngine.hedgingAssistant = &hedging.HedgingAssistant{}
+	flowEngine.hedgingAssistant.Init(flowEngine.configuration)
+	// 初始化调用诊断
+	flowEngine.diagnosticsAssistant = &diagnostics.DiagnosticsAssistant{}
+	flowEngine.diagnosticsAssistant.Init(flowEngine.configuration)
+	// 初始化元数据加密
+	if err = flowEngine.initMetadataCipher(cfg); err != nil {
+		return err
+	}
+	// 初始化路由变量解析链
+	flowEngine.variableAssistant = &variable.VariableAssistant{}
+	flowEngine.variableAssistant.Init(flowEngine, flowEngine.configuration)
+	globalCtx.SetValue(model.ContextKeyVariableResolver, flowEngine.variableAssistant.GetChain())
+	// 初始化SDK自身配置热更新
+	flowEngine.configReloader = &reload.ConfigReloader{}
+	flowEngine.configReloader.Init(flowEngine, flowEngine.configuration, flowEngine.applyReloadedConfig)
+	dispatchCfg := cfg.GetGlobal().GetListenerDispatch()
+	flowEngine.listenerDispatcher = dispatcher.NewPool("listener", dispatchCfg.GetWorkers(),
+		dispatchCfg.GetQueueSize(), dispatchCfg.GetOverflowPolicy())
 	// 加载熔断器插件
 	if enable := cfg.GetConsumer().GetCircuitBreaker().IsEnable(); enable {
 		breakers, err := data.GetCircuitBreakers(cfg, flowEngine.plugins)
@@ -150,12 +258,14 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 		if len(breakers) == 0 {
 			return fmt.Errorf("consumer.circuitBreaker.chain not set")
 		}
-		flowEngine.circuitBreakerFlow = newCircuitBreakerFlow(flowEngine, breakers[0])
+		flowEngine.circuitBreakerFlow = newCircuitBreakerFlow(flowEngine, breakers)
 	}
-	flowEngine.watchEngine = NewWatchEngine(flowEngine.registry)
+	flowEngine.watchEngine = NewWatchEngine(flowEngine.registry, flowEngine.listenerDispatcher)
 	flowEngine.subscribe = &subscribeChannel{
 		registerServices: []model.ServiceKey{},
 		eventChannelMap:  make(map[model.ServiceKey]chan model.SubScribeEvent),
+		watchedRules:     make(map[model.ServiceEventKey]struct{}),
+		registry:         flowEngine.registry,
 	}
 	callbackHandler := common.PluginEventHandler{
 		Callback: flowEngine.ServiceEventCallback,
@@ -166,7 +276,8 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 
 	// 初始化配置中心服务
 	if cfg.GetConfigFile().IsEnable() {
-		configFlow, err := configuration.NewConfigFlow(flowEngine.configConnector, flowEngine.configFilterChain, flowEngine.configuration)
+		configFlow, err := configuration.NewConfigFlow(flowEngine.configConnector, flowEngine.configFilterChain,
+			flowEngine.configuration, flowEngine.listenerDispatcher)
 		if err != nil {
 			return err
 		}
@@ -174,14 +285,19 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 	}
 
 	// 初始注册状态管理器
-	flowEngine.registerStates = registerstate.NewRegisterStateManager(flowEngine.configuration.GetProvider().GetMinRegisterInterval())
+	flowEngine.registerStates = registerstate.NewRegisterStateManager(
+		flowEngine.configuration.GetProvider().GetMinRegisterInterval(),
+		flowEngine.configuration.GetProvider().GetMetadataUpdateInterval())
+	// 初始化自适应权重
+	flowEngine.adaptiveWeightAssistant = &adaptiveweight.AdaptiveWeightAssistant{}
+	flowEngine.adaptiveWeightAssistant.Init(flowEngine.configuration, flowEngine.registerStates.ListRegisters,
+		flowEngine.SyncUpdateInstanceMetadata)
 	return nil
 }
 
 // LoadFlowRouteChain 加载服务路由链插件
 func (e *Engine) LoadFlowRouteChain() error {
-	var err error
-	e.routerChain, err = data.GetServiceRouterChain(e.configuration, e.plugins)
+	routerChain, err := data.GetServiceRouterChain(e.configuration, e.plugins)
 	if err != nil {
 		return err
 	}
@@ -203,10 +319,20 @@ func (e *Engine) LoadFlowRouteChain() error {
 	}
 	e.finalRouterPlugin = finalRouterPlugin.(servicerouter.ServiceRouter)
 	// 加载负载均衡插件
-	e.loadbalancer, err = data.GetLoadBalancer(e.configuration, e.plugins)
+	lb, err := data.GetLoadBalancer(e.configuration, e.plugins)
+	if err != nil {
+		return err
+	}
+	e.defaultRouting.Store(&defaultRouting{routerChain: routerChain, loadbalancer: lb})
+	lbPlugins, err := e.plugins.GetPlugins(common.TypeLoadBalancer)
 	if err != nil {
 		return err
 	}
+	for _, lbPlugin := range lbPlugins {
+		if aware, ok := loadbalancer.GetCallResultAware(lbPlugin); ok {
+			e.callResultAwareLBs = append(e.callResultAwareLBs, aware)
+		}
+	}
 	return nil
 }
 
@@ -229,7 +355,13 @@ func (e *Engine) WatchService(req *model.WatchServiceRequest) (*model.WatchServi
 	if err != nil {
 		return nil, err
 	}
-	ch, err := e.subscribe.WatchService(req.Key)
+	for _, ruleType := range req.RuleTypes {
+		ruleReq := &model.GetServiceRuleRequest{Namespace: req.Key.Namespace, Service: req.Key.Service}
+		if _, err = e.SyncGetServiceRule(ruleType, ruleReq); err != nil {
+			return nil, err
+		}
+	}
+	ch, err := e.subscribe.WatchService(req.Key, req.RuleTypes)
 	if err != nil {
 		log.GetBaseLogger().Errorf("watch service %s %s error:%s", req.Key.Namespace, req.Key.Service,
 			err.Error())
@@ -257,6 +389,7 @@ func (e *Engine) ServiceEventCallback(event *common.PluginEvent) error {
 			log.GetBaseLogger().Errorf("subscribePlugin.DoSubScribe error:%s", err.Error())
 		}
 	}
+	e.reportRuleEvent(event)
 	return e.watchEngine.ServiceEventCallback(event)
 }
 
@@ -276,6 +409,14 @@ func (e *Engine) Start() error {
 	}
 	// 添加上报sdk配置任务
 	configReportTaskValues := e.addSDKConfigReportTask()
+	// 添加SDK自监控指标上报任务
+	healthReportTaskValues := e.addHealthReportTask()
+	// 加载配置中心中的路由变量
+	e.variableAssistant.Start()
+	// 监听SDK自身配置的变更
+	e.configReloader.Start()
+	// 启动自适应权重采样
+	e.adaptiveWeightAssistant.Start()
 	// 启动协程
 	discoverSvc := e.serverServices.GetClusterService(config.DiscoverCluster)
 	if nil != discoverSvc {
@@ -289,7 +430,13 @@ func (e *Engine) Start() error {
 	schedule.StartTask(
 		taskConfigReport, configReportTaskValues, map[interface{}]model.TaskValue{
 			taskConfigReport: &data.AllEqualsComparable{}})
-	return nil
+	if healthReportTaskValues != nil {
+		schedule.StartTask(
+			taskHealthReport, healthReportTaskValues, map[interface{}]model.TaskValue{
+				taskHealthReport: &data.AllEqualsComparable{}})
+	}
+	// 启动本地管理端口
+	return e.startAdminServer()
 }
 
 // getRouterChain 根据服务获取路由链
@@ -301,7 +448,7 @@ func (e *Engine) getRouterChain(svcInstances model.ServiceInstances) *servicerou
 			return routerChain
 		}
 	}
-	return e.routerChain
+	return e.defaultRouting.Load().(*defaultRouting).routerChain
 }
 
 // getLoadBalancer 根据服务获取负载均衡器
@@ -316,25 +463,41 @@ func (e *Engine) getLoadBalancer(svcInstances model.ServiceInstances, chooseAlgo
 		}
 	}
 	if chooseAlgorithm == "" {
-		return e.loadbalancer, nil
+		return e.defaultRouting.Load().(*defaultRouting).loadbalancer, nil
 	}
 	return data.GetLoadBalancerByLbType(chooseAlgorithm, e.plugins)
 }
 
 // Destroy 销毁流程引擎
 func (e *Engine) Destroy() error {
+	if e.admin != nil {
+		e.admin.stop()
+	}
 	if len(e.taskRoutines) > 0 {
 		for _, routine := range e.taskRoutines {
 			routine.Destroy()
 		}
 	}
+	if e.scheduleGroup != nil {
+		e.scheduleGroup.Drain(scheduler.DefaultDrainTimeout)
+	}
 	if e.flowQuotaAssistant != nil {
 		e.flowQuotaAssistant.Destroy()
 	}
 	if e.configFlow != nil {
 		e.configFlow.Destroy()
 	}
+	if e.variableAssistant != nil {
+		e.variableAssistant.Destroy()
+	}
+	if e.configReloader != nil {
+		e.configReloader.Destroy()
+	}
+	if e.adaptiveWeightAssistant != nil {
+		e.adaptiveWeightAssistant.Destroy()
+	}
 	e.registerStates.Destroy()
+	e.listenerDispatcher.Destroy()
 	return nil
 }
 
@@ -354,9 +517,13 @@ func (e *Engine) SyncReportStat(typ model.MetricType, stat model.InstanceGauge)
 }
 
 // reportAPIStat 上报api数据
+// SDK 本身的调用数据不能和用户的监控数据混合在一起，只上报给实现了 SDKStatReporter 的插件
 func (e *Engine) reportAPIStat(result *model.APICallResult) error {
-	// TODO: SDK 本身和北极星 server 的服务调用监控数据不能和用户的监控数据混合在一起，这里可以打印在本地日志中
-	// return e.SyncReportStat(model.SDKAPIStat, result)
+	for _, reporter := range e.sdkStatReporters {
+		if err := reporter.ReportSDKAPIStat(result); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -365,6 +532,39 @@ func (e *Engine) reportSvcStat(result *model.ServiceCallResult) error {
 	return e.SyncReportStat(model.ServiceStat, result)
 }
 
+// cacheHitSampleInterval 本地缓存命中的采样间隔，每命中该次数上报一次
+const cacheHitSampleInterval = 64
+
+// reportCacheStat 上报本地缓存命中数据
+// 每次查询都会调用，未命中总是上报，命中按服务采样上报并通过Count折算回实际次数
+func (e *Engine) reportCacheStat(svcKey *model.ServiceKey, hit bool) {
+	if len(e.reporterChain) == 0 {
+		return
+	}
+	var key model.ServiceKey
+	if svcKey != nil {
+		key = *svcKey
+	}
+	count := uint32(1)
+	if hit {
+		value, ok := e.cacheHitCounters.Load(key)
+		if !ok {
+			value, _ = e.cacheHitCounters.LoadOrStore(key, new(uint32))
+		}
+		if atomic.AddUint32(value.(*uint32), 1)%cacheHitSampleInterval != 0 {
+			return
+		}
+		count = cacheHitSampleInterval
+	}
+	gauge := &model.CacheGauge{
+		Namespace: key.Namespace,
+		Service:   key.Service,
+		Hit:       hit,
+		Count:     count,
+	}
+	_ = e.SyncReportStat(model.CacheStat, gauge)
+}
+
 // loadLocation 上报服务数据
 func (e *Engine) loadLocation() {
 	providers := e.configuration.GetGlobal().GetLocation().GetProviders()
@@ -402,7 +602,10 @@ func pushToBufferChannel(event model.SubScribeEvent, ch chan model.SubScribeEven
 type subscribeChannel struct {
 	registerServices []model.ServiceKey
 	eventChannelMap  map[model.ServiceKey]chan model.SubScribeEvent
-	lock             sync.RWMutex
+	// 被监听的服务规则
+	watchedRules map[model.ServiceEventKey]struct{}
+	registry     localregistry.LocalRegistry
+	lock         sync.RWMutex
 }
 
 var (
@@ -418,26 +621,40 @@ func (s *subscribeChannel) DoSubScribe(event *common.PluginEvent) error {
 		return nil
 	}
 	serviceEvent := event.EventObject.(*common.ServiceEventObject)
-	if serviceEvent.SvcEventKey.Type != model.EventInstances {
+	eventType := serviceEvent.SvcEventKey.Type
+	if eventType == model.EventServices {
 		return nil
 	}
 	s.lock.RLock()
 	channel, ok := s.eventChannelMap[serviceEvent.SvcEventKey.ServiceKey]
+	_, ruleWatched := s.watchedRules[serviceEvent.SvcEventKey]
 	s.lock.RUnlock()
-	if !ok {
+	if !ok || (eventType != model.EventInstances && !ruleWatched) {
 		log.GetBaseLogger().Debugf("%s %s not watch", serviceEvent.SvcEventKey.ServiceKey.Namespace,
 			serviceEvent.SvcEventKey.ServiceKey.Service)
 		return nil
 	}
 
-	insEvent := &model.InstanceEvent{}
-	insEvent.AddEvent = data.CheckAddInstances(serviceEvent)
-	insEvent.UpdateEvent = data.CheckUpdateInstances(serviceEvent)
-	insEvent.DeleteEvent = data.CheckDeleteInstances(serviceEvent)
+	var subEvent model.SubScribeEvent
+	if eventType == model.EventInstances {
+		insEvent := &model.InstanceEvent{}
+		insEvent.AddEvent = data.CheckAddInstances(serviceEvent)
+		insEvent.UpdateEvent = data.CheckUpdateInstances(serviceEvent)
+		insEvent.DeleteEvent = data.CheckDeleteInstances(serviceEvent)
+		subEvent = insEvent
+	} else {
+		ruleEvent := &model.RuleEvent{
+			Type:    eventType,
+			Service: serviceEvent.SvcEventKey.ServiceKey,
+		}
+		ruleEvent.Before, _ = serviceEvent.OldValue.(model.ServiceRule)
+		ruleEvent.After, _ = serviceEvent.NewValue.(model.ServiceRule)
+		subEvent = ruleEvent
+	}
 
 	var err error
 	for i := 0; i < 2; i++ {
-		if err = pushToBufferChannel(insEvent, channel); err == nil {
+		if err = pushToBufferChannel(subEvent, channel); err == nil {
 			break
 		} else {
 			time.Sleep(time.Millisecond * 10)
@@ -451,9 +668,19 @@ func (s *subscribeChannel) DoSubScribe(event *common.PluginEvent) error {
 }
 
 // WatchService is called when a new service is added
-func (s *subscribeChannel) WatchService(key model.ServiceKey) (<-chan model.SubScribeEvent, error) {
+func (s *subscribeChannel) WatchService(key model.ServiceKey,
+	ruleTypes []model.EventType) (<-chan model.SubScribeEvent, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
+	for _, ruleType := range ruleTypes {
+		ruleKey := model.ServiceEventKey{ServiceKey: key, Type: ruleType}
+		if _, ok := s.watchedRules[ruleKey]; ok {
+			continue
+		}
+		s.watchedRules[ruleKey] = struct{}{}
+		// 被监听的规则不会因为长时间未访问而被淘汰
+		s.registry.WatchService(ruleKey)
+	}
 	value, ok := s.eventChannelMap[key]
 	if !ok {
 		ch := make(chan model.SubScribeEvent, 64)
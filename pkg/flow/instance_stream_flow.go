@@ -0,0 +1,73 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+// SyncIterateInstances 获取服务实例的分批迭代器，开启服务端过滤时直接向服务端查询，否则基于本地缓存遍历
+func (e *Engine) SyncIterateInstances(req *model.IterateInstancesRequest) (*model.InstancesIterator, error) {
+	svcKey := model.ServiceKey{Namespace: req.Namespace, Service: req.Service}
+	if req.ServerSideFilter {
+		if querier, ok := serverconnector.GetInstancesQuerier(e.connector); ok {
+			return e.queryInstancesIterator(querier, &svcKey, req)
+		}
+		log.GetBaseLogger().Warnf("server connector %s not support instance query, "+
+			"filter instances of %s locally", e.connector.Name(), svcKey)
+	}
+	resp, err := e.SyncGetAllInstances(&model.GetAllInstancesRequest{
+		FlowID:     req.FlowID,
+		Service:    req.Service,
+		Namespace:  req.Namespace,
+		Timeout:    req.Timeout,
+		RetryCount: req.RetryCount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 直接引用缓存中的实例列表，迭代时按需分段
+	return model.NewSliceInstancesIterator(resp.GetRevision(), resp.GetInstances(), req.Metadata, req.ChunkSize), nil
+}
+
+// queryInstancesIterator 向服务端发起一次带过滤条件的实例查询
+func (e *Engine) queryInstancesIterator(querier serverconnector.InstancesQuerier, svcKey *model.ServiceKey,
+	req *model.IterateInstancesRequest) (*model.InstancesIterator, error) {
+	timeout := e.configuration.GetGlobal().GetAPI().GetTimeout()
+	if req.Timeout != nil {
+		timeout = *req.Timeout
+	}
+	svcEvent, err := querier.QueryInstances(svcKey, req.Metadata, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if svcEvent.Error != nil {
+		return nil, svcEvent.Error
+	}
+	resp, ok := svcEvent.Value.(*apiservice.DiscoverResponse)
+	if !ok {
+		return nil, model.NewSDKError(model.ErrCodeInvalidResponse, nil,
+			"invalid instances response for %s", *svcKey)
+	}
+	return pb.NewQueriedInstancesIterator(resp, svcKey, req.Metadata, req.ChunkSize), nil
+}
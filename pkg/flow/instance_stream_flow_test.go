@@ -0,0 +1,173 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+// queryConnector 支持直接查询实例的连接器，记录查询参数并返回预设的应答
+type queryConnector struct {
+	serverconnector.ServerConnector
+	value       proto.Message
+	svcErr      model.SDKError
+	err         error
+	gotKey      *model.ServiceKey
+	gotMetadata map[string]string
+	gotTimeout  time.Duration
+}
+
+func (q *queryConnector) QueryInstances(svcKey *model.ServiceKey, metadata map[string]string,
+	timeout time.Duration) (*serverconnector.ServiceEvent, error) {
+	q.gotKey, q.gotMetadata, q.gotTimeout = svcKey, metadata, timeout
+	if q.err != nil {
+		return nil, q.err
+	}
+	return &serverconnector.ServiceEvent{
+		ServiceEventKey: model.ServiceEventKey{ServiceKey: *svcKey, Type: model.EventInstances},
+		Value:           q.value,
+		Error:           q.svcErr,
+	}, nil
+}
+
+// queryTestResponse 创建查询应答，实例的env元数据依次为envs中的值
+func queryTestResponse(envs ...string) *apiservice.DiscoverResponse {
+	resp := &apiservice.DiscoverResponse{
+		Type: apiservice.DiscoverResponse_INSTANCE,
+		Service: &apiservice.Service{
+			Name:      wrapperspb.String("svc"),
+			Namespace: wrapperspb.String("Test"),
+			Revision:  wrapperspb.String("rev1"),
+		},
+	}
+	for i, env := range envs {
+		resp.Instances = append(resp.Instances, &apiservice.Instance{
+			Id:       wrapperspb.String(string(rune('a' + i))),
+			Host:     wrapperspb.String("127.0.0.1"),
+			Port:     wrapperspb.UInt32(uint32(8080 + i)),
+			Metadata: map[string]string{"env": env},
+		})
+	}
+	return resp
+}
+
+// TestSyncIterateInstancesServerSideFilter 测试服务端过滤的实例遍历，服务端未按条件过滤时仍在本地过滤
+func TestSyncIterateInstancesServerSideFilter(t *testing.T) {
+	requestTimeout := 3 * time.Second
+	tests := []struct {
+		name        string
+		connector   *queryConnector
+		timeout     *time.Duration
+		wantErr     bool
+		wantTimeout time.Duration
+		wantIds     [][]string
+		wantTotal   int
+	}{
+		{
+			name:        "服务端已按元数据过滤",
+			connector:   &queryConnector{value: queryTestResponse("prod", "prod", "prod")},
+			wantTimeout: config.DefaultAPIInvokeTimeout,
+			wantIds:     [][]string{{"a", "b"}, {"c"}},
+			wantTotal:   3,
+		},
+		{
+			name:        "服务端不支持过滤时在本地过滤",
+			connector:   &queryConnector{value: queryTestResponse("prod", "test", "prod", "test", "prod")},
+			wantTimeout: config.DefaultAPIInvokeTimeout,
+			wantIds:     [][]string{{"a", "c"}, {"e"}},
+			wantTotal:   5,
+		},
+		{
+			name:        "使用请求中的超时时间",
+			connector:   &queryConnector{value: queryTestResponse("prod")},
+			timeout:     &requestTimeout,
+			wantTimeout: requestTimeout,
+			wantIds:     [][]string{{"a"}},
+			wantTotal:   1,
+		},
+		{
+			name:        "查询失败",
+			connector:   &queryConnector{err: errors.New("network error")},
+			wantErr:     true,
+			wantTimeout: config.DefaultAPIInvokeTimeout,
+		},
+		{
+			name: "服务端返回错误",
+			connector: &queryConnector{
+				svcErr: model.NewSDKError(model.ErrCodeServerUserError, nil, "service not found"),
+			},
+			wantErr:     true,
+			wantTimeout: config.DefaultAPIInvokeTimeout,
+		},
+		{
+			name:        "应答类型非法",
+			connector:   &queryConnector{value: &apiservice.DiscoverRequest{}},
+			wantErr:     true,
+			wantTimeout: config.DefaultAPIInvokeTimeout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+			e := &Engine{configuration: cfg, connector: &serverconnector.Proxy{ServerConnector: tt.connector}}
+			metadata := map[string]string{"env": "prod"}
+			it, err := e.SyncIterateInstances(&model.IterateInstancesRequest{
+				Namespace:        "Test",
+				Service:          "svc",
+				Metadata:         metadata,
+				ChunkSize:        2,
+				ServerSideFilter: true,
+				Timeout:          tt.timeout,
+			})
+			assert.Equal(t, &model.ServiceKey{Namespace: "Test", Service: "svc"}, tt.connector.gotKey)
+			assert.Equal(t, metadata, tt.connector.gotMetadata)
+			assert.Equal(t, tt.wantTimeout, tt.connector.gotTimeout)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				assert.Nil(t, it)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, "rev1", it.GetRevision())
+			assert.Equal(t, tt.wantTotal, it.Total())
+			var ids [][]string
+			_ = it.ForEachChunk(func(chunk []model.Instance) error {
+				chunkIds := make([]string, 0, len(chunk))
+				for _, instance := range chunk {
+					assert.Equal(t, "prod", instance.GetMetadata()["env"])
+					assert.Equal(t, "svc", instance.GetService())
+					chunkIds = append(chunkIds, instance.GetId())
+				}
+				ids = append(ids, chunkIds)
+				return nil
+			})
+			assert.Equal(t, tt.wantIds, ids)
+		})
+	}
+}
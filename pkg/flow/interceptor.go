@@ -0,0 +1,87 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// interceptorChain 按添加顺序组织的 SDK 调用拦截器
+// 拦截器列表存放在 atomic.Value 中，读路径无锁
+type interceptorChain struct {
+	mutex        sync.Mutex
+	interceptors atomic.Value
+}
+
+func newInterceptorChain() *interceptorChain {
+	chain := &interceptorChain{}
+	chain.interceptors.Store([]model.Interceptor(nil))
+	return chain
+}
+
+// load 获取当前的拦截器列表
+func (c *interceptorChain) load() []model.Interceptor {
+	return c.interceptors.Load().([]model.Interceptor)
+}
+
+// empty 是否未添加任何拦截器，为true时调用方可直接执行实际调用，避免构造调用链
+func (c *interceptorChain) empty() bool {
+	return len(c.load()) == 0
+}
+
+// add 添加拦截器，采用写时复制，不影响正在执行的调用
+func (c *interceptorChain) add(interceptor model.Interceptor) {
+	if interceptor == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	current := c.load()
+	interceptors := make([]model.Interceptor, 0, len(current)+1)
+	interceptors = append(interceptors, current...)
+	c.interceptors.Store(append(interceptors, interceptor))
+}
+
+// invoke 依次经过各拦截器后执行实际调用，未添加拦截器时直接执行
+func (c *interceptorChain) invoke(api model.ApiOperation, request interface{},
+	invoker model.Invoker) (interface{}, error) {
+	interceptors := c.load()
+	inv := &model.Invocation{API: api, Request: request}
+	if len(interceptors) == 0 {
+		return invoker(inv)
+	}
+	return chainInvoker(interceptors, 0, invoker)(inv)
+}
+
+// chainInvoker 构造从第 index 个拦截器开始的调用链
+func chainInvoker(interceptors []model.Interceptor, index int, invoker model.Invoker) model.Invoker {
+	if index == len(interceptors) {
+		return invoker
+	}
+	return func(inv *model.Invocation) (interface{}, error) {
+		return interceptors[index](inv, chainInvoker(interceptors, index+1, invoker))
+	}
+}
+
+// AddInterceptor 添加 SDK 调用拦截器
+func (e *Engine) AddInterceptor(interceptor model.Interceptor) {
+	e.interceptors.add(interceptor)
+}
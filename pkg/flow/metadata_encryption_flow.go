@@ -0,0 +1,55 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/cachecipher"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// initMetadataCipher 启用元数据加密时获取加解密器
+func (e *Engine) initMetadataCipher(cfg config.Configuration) error {
+	encryptionCfg := cfg.GetGlobal().GetMetadataEncryption()
+	if !encryptionCfg.IsEnable() {
+		return nil
+	}
+	metadataCipher, err := cachecipher.GetCacheCipher(encryptionCfg.GetCipher())
+	if err != nil {
+		return err
+	}
+	e.metadataCipher = metadataCipher
+	e.sensitiveMetadataKeys = encryptionCfg.GetSensitiveKeys()
+	return nil
+}
+
+// encryptRegisterMetadata 加密注册请求中的敏感元数据，返回发送给服务端的请求副本，原请求保持明文以便重复注册
+func (e *Engine) encryptRegisterMetadata(
+	instance *model.InstanceRegisterRequest) (*model.InstanceRegisterRequest, error) {
+	if e.metadataCipher == nil || len(e.sensitiveMetadataKeys) == 0 {
+		return instance, nil
+	}
+	metadata, err := cachecipher.EncryptMetadata(e.metadataCipher, instance.Metadata, e.sensitiveMetadataKeys)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeInternalError, err,
+			"fail to encrypt metadata of instance %s:%d", instance.Host, instance.Port)
+	}
+	encrypted := *instance
+	encrypted.Metadata = metadata
+	return &encrypted, nil
+}
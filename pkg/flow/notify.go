@@ -20,6 +20,8 @@ package flow
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -66,12 +68,14 @@ func (s *SingleNotifyContext) Err() model.SDKError {
 	return s.notifier.GetError()
 }
 
-// Wait notify 异步任务执行回调函数
-func (s *SingleNotifyContext) Wait(timeout time.Duration) bool {
+// Wait notify 异步任务执行回调函数，返回是否超时，ctx结束时提前返回
+func (s *SingleNotifyContext) Wait(ctx context.Context, timeout time.Duration) bool {
 	afterTimer := time.After(timeout)
 	select {
 	case <-afterTimer:
 		return true
+	case <-ctx.Done():
+		return true
 	case <-s.notifier.GetContext().Done():
 		log.GetBaseLogger().Debugf("context %s has been notified", *s.name)
 		return false
@@ -116,25 +120,36 @@ func (c *CombineNotifyContext) Errs() map[ContextKey]model.SDKError {
 	return errs
 }
 
+// resolveKey 以各子回调的服务及资源类型作为合并等待的标识
+func (c *CombineNotifyContext) resolveKey() string {
+	keys := make([]string, 0, len(c.notifiers))
+	for _, notifier := range c.notifiers {
+		keys = append(keys, notifier.name.ServiceKey.Namespace+"/"+notifier.name.ServiceKey.Service+
+			"#"+notifier.name.Operation)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
 // logNotifier 打印通知日志
 func (c *CombineNotifyContext) logNotifier(operation string, notifier *SingleNotifyContext, restWait int32) {
 	log.GetBaseLogger().Debugf("notifier %s of %s has been notified, rest %v", *notifier.name, c.svcKey, restWait)
 }
 
-// Wait notify 异步任务执行回调函数
+// Wait notify 异步任务执行回调函数，ctx结束时提前返回
 // 返回值，是否超时
-func (c *CombineNotifyContext) Wait(timeout time.Duration) (exceedTime bool) {
+func (c *CombineNotifyContext) Wait(ctx context.Context, timeout time.Duration) (exceedTime bool) {
 	var restWait = atomic.LoadInt32(&c.waitCount)
 	if restWait == 0 {
 		return false
 	}
 	log.GetBaseLogger().Debugf("notifiers of %s start to wait, rest %d", *c.svcKey, restWait)
 	doneKeyChan := make(chan string)
-	ctx, cancel := context.WithCancel(context.Background())
+	collectCtx, cancel := context.WithCancel(context.Background())
 	go func() {
 		for {
 			select {
-			case <-ctx.Done():
+			case <-collectCtx.Done():
 				return
 			case key := <-doneKeyChan:
 				c.doneContextKeys.Add(key)
@@ -153,6 +168,8 @@ func (c *CombineNotifyContext) Wait(timeout time.Duration) (exceedTime bool) {
 			select {
 			case <-afterTimer:
 				return
+			case <-ctx.Done():
+				return
 			case <-notifier.notifier.GetContext().Done():
 				doneKeyChan <- notifier.name.Operation
 				nextWait := atomic.AddInt32(&c.waitCount, -1)
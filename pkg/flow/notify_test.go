@@ -0,0 +1,120 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+// newTestCombineContext 创建等待单个资源的复合回调上下文
+func newTestCombineContext(operation string) (*CombineNotifyContext, *common.Notifier) {
+	svcKey := &model.ServiceKey{Namespace: "Test", Service: "svc"}
+	notifier := common.NewNotifier()
+	single := NewSingleNotifyContext(&ContextKey{ServiceKey: svcKey, Operation: operation}, notifier)
+	return NewCombineNotifyContext(svcKey, []*SingleNotifyContext{single}), notifier
+}
+
+// waitResult 在后台执行等待，返回结果通道
+func waitResult(wait func() bool) <-chan bool {
+	done := make(chan bool, 1)
+	go func() {
+		done <- wait()
+	}()
+	return done
+}
+
+// TestNotifyContextWaitCanceled 测试等待过程中取消ctx后立即返回
+func TestNotifyContextWaitCanceled(t *testing.T) {
+	tests := []struct {
+		name string
+		wait func(ctx context.Context) bool
+	}{
+		{
+			name: "单个回调上下文",
+			wait: func(ctx context.Context) bool {
+				key := &ContextKey{ServiceKey: &model.ServiceKey{Namespace: "Test", Service: "svc"}, Operation: "instances"}
+				return NewSingleNotifyContext(key, common.NewNotifier()).Wait(ctx, time.Hour)
+			},
+		},
+		{
+			name: "复合回调上下文",
+			wait: func(ctx context.Context) bool {
+				combineCtx, _ := newTestCombineContext("instances")
+				return combineCtx.Wait(ctx, time.Hour)
+			},
+		},
+		{
+			name: "合并等待的跟随者",
+			wait: func(ctx context.Context) bool {
+				group := newResolveGroup()
+				leaderCtx, _ := newTestCombineContext("instances")
+				followerCtx, _ := newTestCombineContext("instances")
+				leaderStarted := make(chan struct{})
+				go func() {
+					close(leaderStarted)
+					group.wait(context.Background(), leaderCtx, 200*time.Millisecond)
+				}()
+				<-leaderStarted
+				exceedTimeout, _ := group.wait(ctx, followerCtx, time.Hour)
+				return exceedTimeout
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			done := waitResult(func() bool { return tt.wait(ctx) })
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+			select {
+			case exceedTimeout := <-done:
+				assert.True(t, exceedTimeout)
+			case <-time.After(5 * time.Second):
+				t.Fatal("wait not aborted by ctx")
+			}
+		})
+	}
+}
+
+// TestCombineNotifyContextWaitNotified 测试未取消ctx时正常等待到通知
+func TestCombineNotifyContextWaitNotified(t *testing.T) {
+	combineCtx, notifier := newTestCombineContext("instances")
+	done := waitResult(func() bool { return combineCtx.Wait(context.Background(), time.Hour) })
+	notifier.Notify(nil)
+	select {
+	case exceedTimeout := <-done:
+		assert.False(t, exceedTimeout)
+		assert.True(t, combineCtx.IsDone())
+	case <-time.After(5 * time.Second):
+		t.Fatal("wait not finished after notify")
+	}
+}
@@ -0,0 +1,89 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// 默认并发预热的服务数
+	defaultPrefetchConcurrency = 10
+)
+
+// Prefetch 后台并发拉取服务实例及路由、限流规则，结果经本地缓存持久化，完成后关闭Ready通道
+func (e *Engine) Prefetch(req *model.PrefetchRequest) *model.PrefetchResponse {
+	resp := model.NewPrefetchResponse()
+	concurrency := req.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultPrefetchConcurrency
+	}
+	services := make(chan model.ServiceKey, len(req.Services))
+	for _, svc := range req.Services {
+		services <- svc
+	}
+	close(services)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < concurrency && i < len(req.Services); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for svc := range services {
+				e.prefetchService(req, svc, resp)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		log.GetBaseLogger().Infof("prefetch %d services finished, %d failed",
+			len(req.Services), len(resp.Errors()))
+		resp.Finish()
+	}()
+	return resp
+}
+
+// prefetchService 预热单个服务
+func (e *Engine) prefetchService(req *model.PrefetchRequest, svc model.ServiceKey, resp *model.PrefetchResponse) {
+	instancesReq := &model.GetAllInstancesRequest{
+		Namespace: svc.Namespace,
+		Service:   svc.Service,
+		Timeout:   req.Timeout,
+	}
+	if _, err := e.SyncGetAllInstances(instancesReq); err != nil {
+		log.GetBaseLogger().Warnf("prefetch instances of %s failed: %v", svc, err)
+		resp.AddError(svc, err)
+		return
+	}
+	if req.SkipRules {
+		return
+	}
+	for _, eventType := range []model.EventType{model.EventRouting, model.EventRateLimiting} {
+		ruleReq := &model.GetServiceRuleRequest{
+			Namespace: svc.Namespace,
+			Service:   svc.Service,
+			Timeout:   req.Timeout,
+		}
+		if _, err := e.SyncGetServiceRule(eventType, ruleReq); err != nil {
+			log.GetBaseLogger().Warnf("prefetch %v rule of %s failed: %v", eventType, svc, err)
+			resp.AddError(svc, err)
+		}
+	}
+}
@@ -19,9 +19,11 @@ package quota
 
 import (
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/modern-go/reflect2"
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
@@ -34,6 +36,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/model/pb"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
 )
 
 const (
@@ -70,6 +73,14 @@ type FlowQuotaAssistant struct {
 
 	remoteNamespace string
 	remoteService   string
+	// 无需限流规则即可生效的限流插件
+	ruleFreeLimiters []ratelimiter.RuleFreeRateLimiter
+	// 是否对所有规则开启影子模式
+	shadow bool
+	// 开启影子模式的规则名
+	shadowRules map[string]struct{}
+	// 子规则名到父规则名的映射
+	parentRules map[string]string
 }
 
 // AsyncRateLimitConnector 异步限流连接器
@@ -117,6 +128,12 @@ func (f *FlowQuotaAssistant) Init(engine model.Engine, cfg config.Configuration,
 	if !f.enable {
 		return nil
 	}
+	f.shadow = cfg.GetProvider().GetRateLimit().IsShadow()
+	f.shadowRules = make(map[string]struct{})
+	for _, ruleName := range cfg.GetProvider().GetRateLimit().GetShadowRules() {
+		f.shadowRules[ruleName] = struct{}{}
+	}
+	f.parentRules = cfg.GetProvider().GetRateLimit().GetParentRules()
 	callback, err := NewRemoteQuotaCallback(cfg, supplier, engine, f.asyncRateLimitConnector)
 	if err != nil {
 		return err
@@ -129,6 +146,15 @@ func (f *FlowQuotaAssistant) Init(engine model.Engine, cfg config.Configuration,
 		DelayStart: true,
 	})
 	f.taskValues = taskValues
+	limiters, err := supplier.GetPlugins(common.TypeRateLimiter)
+	if err != nil {
+		return err
+	}
+	for _, limiter := range limiters {
+		if ruleFree, ok := ratelimiter.GetRuleFreeRateLimiter(limiter); ok {
+			f.ruleFreeLimiters = append(f.ruleFreeLimiters, ruleFree)
+		}
+	}
 	supplier.RegisterEventSubscriber(common.OnServiceUpdated,
 		common.PluginEventHandler{Callback: f.OnServiceUpdated})
 	supplier.RegisterEventSubscriber(common.OnServiceDeleted,
@@ -198,9 +224,9 @@ func (f *FlowQuotaAssistant) GetAllWindowSets() map[model.ServiceKey]*RateLimitW
 
 // GetRateLimitWindow 获取配额分配窗口
 func (f *FlowQuotaAssistant) GetRateLimitWindow(svcKey model.ServiceKey, rule *apitraffic.Rule,
-	label string) (*RateLimitWindowSet, *RateLimitWindow) {
+	label string, regexSpread bool) (*RateLimitWindowSet, *RateLimitWindow) {
 	windowSet := f.GetRateLimitWindowSet(svcKey, true)
-	return windowSet, windowSet.GetRateLimitWindow(rule, label)
+	return windowSet, windowSet.GetRateLimitWindow(rule, label, regexSpread)
 }
 
 // OnServiceUpdated 服务更新回调，找到具体的限流窗口集合，然后触发更新
@@ -248,28 +274,96 @@ func (f *FlowQuotaAssistant) GetQuota(commonRequest *data.CommonRateLimitRequest
 		return nil, err
 	}
 	if len(windows) == 0 {
-		// 没有限流规则，直接放通
-		resp := &model.QuotaResponse{
-			Code: model.QuotaResultOk,
-			Info: RuleNotExists,
-		}
-		return model.QuotaFutureWithResponse(resp), nil
+		return f.getRuleFreeQuota(commonRequest), nil
 	}
+	return f.allocateQuota(commonRequest, windows), nil
+}
+
+// allocateQuota 在匹配的限流窗口中依次分配配额，非影子规则限流时释放已分配的配额并返回限流结果
+func (f *FlowQuotaAssistant) allocateQuota(commonRequest *data.CommonRateLimitRequest,
+	windows []*RateLimitWindow) *model.QuotaFutureImpl {
 	var maxWaitMs int64 = 0
+	var shadowRuleName string
+	allocateTime := time.Now()
+	// 请求对象会被复用，释放回调中只能使用拷贝的值
+	throttling := commonRequest.Throttling
+	allocatedWindows := make([]*RateLimitWindow, 0, len(windows))
 	for _, window := range windows {
 		window.Init()
 		quotaResult := window.AllocateQuota(commonRequest)
 		if quotaResult.Code == model.QuotaResultLimited {
-			return model.QuotaFutureWithResponse(quotaResult), nil
+			if f.isShadow(window.Rule) {
+				// 影子模式下只记录本应限流的规则，不实际限流
+				if len(shadowRuleName) == 0 {
+					shadowRuleName = window.Rule.GetName().GetValue()
+				}
+				log.GetBaseLogger().Debugf("[RateLimit]shadow limited by rule %s, window %s",
+					window.Rule.GetName().GetValue(), window.uniqueKey)
+				continue
+			}
+			// 释放已经分配的配额
+			for _, allocated := range allocatedWindows {
+				allocated.Release(throttling, allocateTime)
+			}
+			return model.QuotaFutureWithResponse(quotaResult)
 		}
 		if quotaResult.WaitMs > maxWaitMs {
 			maxWaitMs = quotaResult.WaitMs
 		}
+		allocatedWindows = append(allocatedWindows, window)
+	}
+	future := model.QuotaFutureWithResponse(&model.QuotaResponse{
+		Code:           model.QuotaResultOk,
+		WaitMs:         maxWaitMs,
+		ShadowRuleName: shadowRuleName,
+	})
+	for _, window := range allocatedWindows {
+		allocated := window
+		future.AddReleaseFunc(func() {
+			allocated.Release(throttling, allocateTime)
+		})
+	}
+	return future
+}
+
+// isShadow 规则是否开启了影子模式
+func (f *FlowQuotaAssistant) isShadow(rule *apitraffic.Rule) bool {
+	if f.shadow {
+		return true
+	}
+	_, ok := f.shadowRules[rule.GetName().GetValue()]
+	return ok
+}
+
+// getRuleFreeQuota 没有匹配的限流规则时，使用无需规则的限流插件分配配额
+func (f *FlowQuotaAssistant) getRuleFreeQuota(commonRequest *data.CommonRateLimitRequest) *model.QuotaFutureImpl {
+	allocateTime := time.Now()
+	buckets := make([]ratelimiter.QuotaBucket, 0, len(f.ruleFreeLimiters))
+	for _, limiter := range f.ruleFreeLimiters {
+		bucket := limiter.GetRuleFreeBucket(commonRequest.DstService, commonRequest.Method)
+		if bucket == nil {
+			continue
+		}
+		quotaResult := bucket.GetQuota(model.CurrentMillisecond(), commonRequest.Token)
+		if quotaResult.Code == model.QuotaResultLimited {
+			for _, allocated := range buckets {
+				releaseBucket(allocated, allocateTime)
+			}
+			return model.QuotaFutureWithResponse(quotaResult)
+		}
+		buckets = append(buckets, bucket)
 	}
-	return model.QuotaFutureWithResponse(&model.QuotaResponse{
-		Code:   model.QuotaResultOk,
-		WaitMs: maxWaitMs,
-	}), nil
+	future := model.QuotaFutureWithResponse(&model.QuotaResponse{
+		Code: model.QuotaResultOk,
+		Info: RuleNotExists,
+	})
+	for _, bucket := range buckets {
+		allocated := bucket
+		future.AddReleaseFunc(func() {
+			releaseBucket(allocated, allocateTime)
+		})
+	}
+	return future
 }
 
 // lookupRateLimitWindow 计算限流窗口
@@ -291,11 +385,12 @@ func (f *FlowQuotaAssistant) lookupRateLimitWindow(
 	if len(rules) == 0 {
 		return nil, nil
 	}
+	rules = f.appendParentRules(commonRequest.RateLimitRule, rules)
 	windows := make([]*RateLimitWindow, 0, len(rules))
 	for _, rule := range rules {
 		// 2.获取已有的QuotaWindow
 		labelStr, regexSpread := FormatLabelToStr(commonRequest, rule)
-		windowSet, window := f.GetRateLimitWindow(commonRequest.DstService, rule, labelStr)
+		windowSet, window := f.GetRateLimitWindow(commonRequest.DstService, rule, labelStr, regexSpread)
 		if nil != window {
 			// 已经存在限流窗口，则直接分配
 			windows = append(windows, window)
@@ -317,24 +412,20 @@ func matchStringValue(matchString *apimodel.MatchString, value string, ruleCache
 
 	switch matchType {
 	case apimodel.MatchString_EXACT:
+		if pb.IsPrefixMatchValue(matchString) {
+			return strings.HasPrefix(value, strings.TrimSuffix(matchValue, pb.MatchAll))
+		}
 		return value == matchValue
+	case apimodel.MatchString_RANGE:
+		return matchRangeValue(matchString, value, ruleCache)
 	case apimodel.MatchString_REGEX:
-		regexObj, err := ruleCache.GetRegexMatcher(matchValue)
+		matcher, err := ruleCache.GetMatcher(matchValue)
 		if nil != err {
 			log.GetBaseLogger().Errorf("regex compile error. ruleMetaValueStr: %s, value: %s, errors: %s",
 				matchValue, value, err)
 			return false
 		}
-		m, err := regexObj.FindStringMatch(value)
-		if err != nil {
-			log.GetBaseLogger().Errorf("regex match error. ruleMetaValueStr: %s, value: %s, errors: %s",
-				matchValue, value, err)
-			return false
-		}
-		if m == nil || m.String() == "" {
-			return false
-		}
-		return true
+		return matcher.MatchString(value)
 	case apimodel.MatchString_NOT_EQUALS:
 		return value != matchValue
 	case apimodel.MatchString_IN:
@@ -357,6 +448,78 @@ func matchStringValue(matchString *apimodel.MatchString, value string, ruleCache
 	return false
 }
 
+// matchRangeValue 数值范围匹配，优先使用规则校验时缓存的范围
+func matchRangeValue(matchString *apimodel.MatchString, value string, ruleCache model.RuleCache) bool {
+	numberRange, ok := ruleCache.GetMessageCache(matchString).(*pb.NumberRange)
+	if !ok {
+		var err error
+		if numberRange, err = pb.ParseNumberRange(matchString.GetValue().GetValue()); err != nil {
+			log.GetBaseLogger().Errorf("range parse error. ruleMetaValueStr: %s, errors: %s",
+				matchString.GetValue().GetValue(), err)
+			return false
+		}
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	return numberRange.Contains(number)
+}
+
+// appendParentRules 将匹配规则声明的父规则加入结果，多个子规则共享父规则的配额
+// 父规则排在所有子规则之后，子规则限流时不会占用父规则的共享配额
+func (f *FlowQuotaAssistant) appendParentRules(svcRule model.ServiceRule, rules []*apitraffic.Rule) []*apitraffic.Rule {
+	if len(f.parentRules) == 0 {
+		return rules
+	}
+	rulesByName := make(map[string]*apitraffic.Rule)
+	for _, rule := range svcRule.GetValue().(*apitraffic.RateLimit).GetRules() {
+		if nil != rule.GetDisable() && rule.GetDisable().GetValue() {
+			continue
+		}
+		rulesByName[rule.GetName().GetValue()] = rule
+	}
+	children := make([]*apitraffic.Rule, 0, len(rules))
+	parents := make([]*apitraffic.Rule, 0)
+	added := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		added[rule.GetName().GetValue()] = true
+	}
+	isParent := make(map[string]bool)
+	// 逐级查找父规则，已经加入的规则不再重复加入，避免循环引用
+	for i := 0; i < len(rules)+len(parents); i++ {
+		var rule *apitraffic.Rule
+		if i < len(rules) {
+			rule = rules[i]
+		} else {
+			rule = parents[i-len(rules)]
+		}
+		parentName, ok := f.parentRules[rule.GetName().GetValue()]
+		if !ok {
+			continue
+		}
+		isParent[parentName] = true
+		if added[parentName] {
+			continue
+		}
+		parentRule, ok := rulesByName[parentName]
+		if !ok || len(parentRule.GetAmounts()) == 0 {
+			continue
+		}
+		added[parentName] = true
+		parents = append(parents, parentRule)
+	}
+	for _, rule := range rules {
+		if isParent[rule.GetName().GetValue()] {
+			// 自身匹配的父规则同样需要排在子规则之后
+			parents = append([]*apitraffic.Rule{rule}, parents...)
+			continue
+		}
+		children = append(children, rule)
+	}
+	return append(children, parents...)
+}
+
 // lookupRule 寻址规则
 func lookupRules(svcRule model.ServiceRule, method string, arguments map[apitraffic.MatchArgument_Type]map[string]string) []*apitraffic.Rule {
 	if reflect2.IsNil(svcRule) || reflect2.IsNil(svcRule.GetValue()) {
@@ -423,11 +586,11 @@ func FormatLabelToStr(request *data.CommonRateLimitRequest, rule *apitraffic.Rul
 	methodValue := ""
 	var regexSpread bool
 	if nil != methodMatcher && !pb.IsMatchAllValue(methodMatcher) {
-		if regexCombine && methodMatcher.GetType() != apimodel.MatchString_EXACT {
+		if regexCombine && !pb.IsExactMatchValue(methodMatcher) {
 			methodValue = methodMatcher.GetValue().GetValue()
 		} else {
 			methodValue = request.Method
-			if methodMatcher.GetType() != apimodel.MatchString_EXACT {
+			if !pb.IsExactMatchValue(methodMatcher) {
 				regexSpread = true
 			}
 		}
@@ -437,12 +600,12 @@ func FormatLabelToStr(request *data.CommonRateLimitRequest, rule *apitraffic.Rul
 	for _, argumentMatcher := range argumentsList {
 		var labelValue string
 		valueMatcher := argumentMatcher.GetValue()
-		if regexCombine && valueMatcher.GetType() != apimodel.MatchString_EXACT {
+		if regexCombine && !pb.IsExactMatchValue(valueMatcher) {
 			labelValue = valueMatcher.GetValue().GetValue()
 		} else {
 			stringStringMap := request.Arguments[argumentMatcher.GetType()]
 			labelValue, _ = getLabelValue(argumentMatcher, stringStringMap)
-			if valueMatcher.GetType() != apimodel.MatchString_EXACT {
+			if !pb.IsExactMatchValue(valueMatcher) {
 				regexSpread = true
 			}
 		}
@@ -0,0 +1,348 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// testRuleSpec 测试规则的阈值及是否为影子规则
+type testRuleSpec struct {
+	name      string
+	maxAmount uint32
+	shadow    bool
+}
+
+// TestAllocateQuotaShadow 测试影子规则只记录本应限流的请求，不实际限流
+func TestAllocateQuotaShadow(t *testing.T) {
+	tests := []struct {
+		name       string
+		shadowAll  bool
+		rules      []testRuleSpec
+		wantCode   model.QuotaResultCode
+		wantShadow string
+		// 分配结束后各规则配额池的占用数及释放次数
+		wantUsed     map[string]uint32
+		wantReleased map[string]uint32
+		// 各规则窗口累计被限流的请求数
+		wantLimited map[string]uint64
+	}{
+		{
+			name:         "影子规则限流时放通并记录规则名",
+			rules:        []testRuleSpec{{name: "shadow", maxAmount: 0, shadow: true}},
+			wantCode:     model.QuotaResultOk,
+			wantShadow:   "shadow",
+			wantUsed:     map[string]uint32{"shadow": 0},
+			wantReleased: map[string]uint32{"shadow": 0},
+			wantLimited:  map[string]uint64{"shadow": 1},
+		},
+		{
+			name:         "全局影子模式下所有规则都不限流",
+			shadowAll:    true,
+			rules:        []testRuleSpec{{name: "a", maxAmount: 0}, {name: "b", maxAmount: 0}},
+			wantCode:     model.QuotaResultOk,
+			wantShadow:   "a",
+			wantUsed:     map[string]uint32{"a": 0, "b": 0},
+			wantReleased: map[string]uint32{"a": 0, "b": 0},
+			wantLimited:  map[string]uint64{"a": 1, "b": 1},
+		},
+		{
+			name:         "影子规则未限流时不记录规则名",
+			rules:        []testRuleSpec{{name: "shadow", maxAmount: 1, shadow: true}},
+			wantCode:     model.QuotaResultOk,
+			wantUsed:     map[string]uint32{"shadow": 1},
+			wantReleased: map[string]uint32{"shadow": 0},
+			wantLimited:  map[string]uint64{"shadow": 0},
+		},
+		{
+			name:         "影子规则限流后其他规则正常分配",
+			rules:        []testRuleSpec{{name: "shadow", maxAmount: 0, shadow: true}, {name: "a", maxAmount: 1}},
+			wantCode:     model.QuotaResultOk,
+			wantShadow:   "shadow",
+			wantUsed:     map[string]uint32{"shadow": 0, "a": 1},
+			wantReleased: map[string]uint32{"shadow": 0, "a": 0},
+			wantLimited:  map[string]uint64{"shadow": 1, "a": 0},
+		},
+		{
+			name:         "影子规则不影响其他规则限流",
+			rules:        []testRuleSpec{{name: "shadow", maxAmount: 0, shadow: true}, {name: "a", maxAmount: 0}},
+			wantCode:     model.QuotaResultLimited,
+			wantUsed:     map[string]uint32{"shadow": 0, "a": 0},
+			wantReleased: map[string]uint32{"shadow": 0, "a": 0},
+			wantLimited:  map[string]uint64{"shadow": 1, "a": 1},
+		},
+		{
+			name:         "非影子规则限流时释放已分配的配额",
+			rules:        []testRuleSpec{{name: "a", maxAmount: 1}, {name: "b", maxAmount: 0}},
+			wantCode:     model.QuotaResultLimited,
+			wantUsed:     map[string]uint32{"a": 0, "b": 0},
+			wantReleased: map[string]uint32{"a": 1, "b": 0},
+			wantLimited:  map[string]uint64{"a": 0, "b": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, limiter := newTestAssistant()
+			f.shadow = tt.shadowAll
+			windows := make(map[string]*RateLimitWindow, len(tt.rules))
+			matched := make([]*RateLimitWindow, 0, len(tt.rules))
+			for _, spec := range tt.rules {
+				if spec.shadow {
+					f.shadowRules[spec.name] = struct{}{}
+				}
+				window := newTestWindow(f, newTestRule(spec.name, spec.maxAmount), "", false)
+				windows[spec.name] = window
+				matched = append(matched, window)
+			}
+			future := f.allocateQuota(&data.CommonRateLimitRequest{Token: 1}, matched)
+			resp := future.Get()
+			assert.Equal(t, tt.wantCode, resp.Code)
+			assert.Equal(t, tt.wantShadow, resp.ShadowRuleName)
+			for name, window := range windows {
+				bucket := limiter.bucket(name, window)
+				assert.Equal(t, tt.wantUsed[name], bucket.used, "used of %s", name)
+				assert.Equal(t, tt.wantReleased[name], bucket.released, "released of %s", name)
+				_, limited := window.GetUsage()
+				assert.Equal(t, tt.wantLimited[name], limited, "limited of %s", name)
+			}
+		})
+	}
+}
+
+// TestAllocateQuotaShadowRelease 测试影子规则限流后放通的请求，释放时只归还实际分配的配额
+func TestAllocateQuotaShadowRelease(t *testing.T) {
+	f, limiter := newTestAssistant()
+	f.shadowRules["shadow"] = struct{}{}
+	shadowWindow := newTestWindow(f, newTestRule("shadow", 0), "", false)
+	window := newTestWindow(f, newTestRule("a", 1), "", false)
+	future := f.allocateQuota(&data.CommonRateLimitRequest{Token: 1}, []*RateLimitWindow{shadowWindow, window})
+	assert.Equal(t, model.QuotaResultOk, future.Get().Code)
+	future.Release()
+	future.Release()
+	assert.Equal(t, uint32(0), limiter.bucket("shadow", shadowWindow).released)
+	assert.Equal(t, uint32(1), limiter.bucket("a", window).released)
+	assert.Equal(t, uint32(0), limiter.bucket("a", window).used)
+}
+
+// TestAppendParentRules 测试按配置逐级加入父规则，父规则排在子规则之后
+func TestAppendParentRules(t *testing.T) {
+	disabled := newTestRule("disabled", 10)
+	disabled.Disable = wrapperspb.Bool(true)
+	noAmount := newTestRule("noAmount", 10)
+	noAmount.Amounts = nil
+	svcRule := newTestServiceRule(newTestRule("child", 1), newTestRule("sibling", 1), newTestRule("parent", 10),
+		newTestRule("grandParent", 100), disabled, noAmount, newTestRule("a", 1), newTestRule("b", 1))
+	tests := []struct {
+		name        string
+		parentRules map[string]string
+		matched     []string
+		want        []string
+	}{
+		{
+			name:    "未配置父规则时保持原样",
+			matched: []string{"child"},
+			want:    []string{"child"},
+		},
+		{
+			name:        "子规则匹配时加入父规则",
+			parentRules: map[string]string{"child": "parent"},
+			matched:     []string{"child"},
+			want:        []string{"child", "parent"},
+		},
+		{
+			name:        "逐级加入祖先规则",
+			parentRules: map[string]string{"child": "parent", "parent": "grandParent"},
+			matched:     []string{"child"},
+			want:        []string{"child", "parent", "grandParent"},
+		},
+		{
+			name:        "多个子规则共享同一个父规则，父规则只加入一次",
+			parentRules: map[string]string{"child": "parent", "sibling": "parent"},
+			matched:     []string{"child", "sibling"},
+			want:        []string{"child", "sibling", "parent"},
+		},
+		{
+			name:        "自身匹配的父规则排在子规则之后",
+			parentRules: map[string]string{"child": "parent"},
+			matched:     []string{"parent", "child"},
+			want:        []string{"child", "parent"},
+		},
+		{
+			name:        "被禁用的父规则不加入",
+			parentRules: map[string]string{"child": "disabled"},
+			matched:     []string{"child"},
+			want:        []string{"child"},
+		},
+		{
+			name:        "没有阈值的父规则不加入",
+			parentRules: map[string]string{"child": "noAmount"},
+			matched:     []string{"child"},
+			want:        []string{"child"},
+		},
+		{
+			name:        "父规则不存在时忽略",
+			parentRules: map[string]string{"child": "missing"},
+			matched:     []string{"child"},
+			want:        []string{"child"},
+		},
+		{
+			name:        "循环引用时每个规则只加入一次",
+			parentRules: map[string]string{"a": "b", "b": "a"},
+			matched:     []string{"a"},
+			want:        []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, _ := newTestAssistant()
+			f.parentRules = tt.parentRules
+			matched := make([]*apitraffic.Rule, 0, len(tt.matched))
+			for _, name := range tt.matched {
+				for _, rule := range svcRule.rateLimit.GetRules() {
+					if rule.GetName().GetValue() == name {
+						matched = append(matched, rule)
+					}
+				}
+			}
+			got := make([]string, 0, len(tt.want))
+			for _, rule := range f.appendParentRules(svcRule, matched) {
+				got = append(got, rule.GetName().GetValue())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func matchString(matchType apimodel.MatchString_MatchStringType, value string) *apimodel.MatchString {
+	return &apimodel.MatchString{Type: matchType, Value: wrapperspb.String(value)}
+}
+
+// TestMatchStringValue 测试限流规则的标签匹配，包括前缀及数值范围匹配
+func TestMatchStringValue(t *testing.T) {
+	// 规则校验时缓存的范围优先于字面值，用于验证使用了缓存的解析结果
+	cachedRange := matchString(apimodel.MatchString_RANGE, "10~20")
+	ruleCache := model.NewRuleCache()
+	ruleCache.SetMessageCache(cachedRange, &pb.NumberRange{Low: 0, High: 5})
+	tests := []struct {
+		name    string
+		matcher *apimodel.MatchString
+		value   string
+		want    bool
+	}{
+		{name: "精确匹配", matcher: matchString(apimodel.MatchString_EXACT, "gold"), value: "gold", want: true},
+		{name: "精确匹配不匹配前缀", matcher: matchString(apimodel.MatchString_EXACT, "gold"), value: "golden"},
+		{name: "通配符匹配所有值", matcher: matchString(apimodel.MatchString_EXACT, "*"), value: "any", want: true},
+		{name: "前缀匹配", matcher: matchString(apimodel.MatchString_EXACT, "gold*"), value: "golden", want: true},
+		{name: "前缀匹配包含前缀本身", matcher: matchString(apimodel.MatchString_EXACT, "gold*"), value: "gold", want: true},
+		{name: "前缀不匹配", matcher: matchString(apimodel.MatchString_EXACT, "gold*"), value: "silver"},
+		{name: "值短于前缀", matcher: matchString(apimodel.MatchString_EXACT, "gold*"), value: "go"},
+		{name: "范围下界", matcher: matchString(apimodel.MatchString_RANGE, "10~20"), value: "10", want: true},
+		{name: "范围上界", matcher: matchString(apimodel.MatchString_RANGE, "10~20"), value: "20", want: true},
+		{name: "超出范围上界", matcher: matchString(apimodel.MatchString_RANGE, "10~20"), value: "21"},
+		{name: "低于范围下界", matcher: matchString(apimodel.MatchString_RANGE, "10~20"), value: "9"},
+		{name: "负数范围", matcher: matchString(apimodel.MatchString_RANGE, " -5 ~ 5 "), value: "-5", want: true},
+		{name: "非数值不匹配范围", matcher: matchString(apimodel.MatchString_RANGE, "10~20"), value: "15a"},
+		{name: "格式错误的范围不匹配", matcher: matchString(apimodel.MatchString_RANGE, "10-20"), value: "15"},
+		{name: "下界大于上界的范围不匹配", matcher: matchString(apimodel.MatchString_RANGE, "20~10"), value: "15"},
+		{name: "优先使用缓存的范围", matcher: cachedRange, value: "3", want: true},
+		{name: "缓存的范围外不匹配", matcher: cachedRange, value: "15"},
+		{name: "正则匹配", matcher: matchString(apimodel.MatchString_REGEX, "^gold-[0-9]+$"), value: "gold-1", want: true},
+		{name: "正则不匹配", matcher: matchString(apimodel.MatchString_REGEX, "^gold-[0-9]+$"), value: "gold-a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchStringValue(tt.matcher, tt.value, ruleCache))
+		})
+	}
+}
+
+// TestLookupRulesWithRangeAndPrefix 测试按方法前缀及标签数值范围查找匹配的规则
+func TestLookupRulesWithRangeAndPrefix(t *testing.T) {
+	methodRule := newTestRule("method", 10)
+	methodRule.Method = matchString(apimodel.MatchString_EXACT, "/api/*")
+	rangeRule := newTestRule("range", 10)
+	rangeRule.Arguments = []*apitraffic.MatchArgument{{
+		Type:  apitraffic.MatchArgument_HEADER,
+		Key:   "x-uid",
+		Value: matchString(apimodel.MatchString_RANGE, "1~100"),
+	}}
+	prefixRule := newTestRule("prefix", 10)
+	prefixRule.Arguments = []*apitraffic.MatchArgument{{
+		Type:  apitraffic.MatchArgument_CUSTOM,
+		Key:   "tier",
+		Value: matchString(apimodel.MatchString_EXACT, "gold*"),
+	}}
+	svcRule := newTestServiceRule(methodRule, rangeRule, prefixRule)
+	tests := []struct {
+		name      string
+		method    string
+		arguments map[apitraffic.MatchArgument_Type]map[string]string
+		want      []string
+	}{
+		{
+			name:   "全部匹配",
+			method: "/api/v1",
+			arguments: map[apitraffic.MatchArgument_Type]map[string]string{
+				apitraffic.MatchArgument_HEADER: {"x-uid": "50"},
+				apitraffic.MatchArgument_CUSTOM: {"tier": "gold-plus"},
+			},
+			want: []string{"method", "range", "prefix"},
+		},
+		{
+			name:   "数值超出范围",
+			method: "/api/v1",
+			arguments: map[apitraffic.MatchArgument_Type]map[string]string{
+				apitraffic.MatchArgument_HEADER: {"x-uid": "101"},
+				apitraffic.MatchArgument_CUSTOM: {"tier": "gold"},
+			},
+			want: []string{"method", "prefix"},
+		},
+		{
+			name:   "方法前缀及标签前缀都不匹配",
+			method: "/web/v1",
+			arguments: map[apitraffic.MatchArgument_Type]map[string]string{
+				apitraffic.MatchArgument_HEADER: {"x-uid": "1"},
+				apitraffic.MatchArgument_CUSTOM: {"tier": "silver"},
+			},
+			want: []string{"range"},
+		},
+		{
+			name:   "缺少标签时不匹配",
+			method: "/api/v1",
+			want:   []string{"method"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0, len(tt.want))
+			for _, rule := range lookupRules(svcRule, tt.method, tt.arguments) {
+				got = append(got, rule.GetName().GetValue())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+var testSvcKey = model.ServiceKey{Namespace: "Test", Service: "svc"}
+
+// countBucket 按分配次数计数的配额池，占用数超过maxAmount时限流，释放时归还占用
+type countBucket struct {
+	mutex     sync.Mutex
+	maxAmount uint32
+	used      uint32
+	limited   uint32
+	released  uint32
+}
+
+func (b *countBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.used+token > b.maxAmount {
+		b.limited += token
+		return &model.QuotaResponse{Code: model.QuotaResultLimited, Info: "count limited"}
+	}
+	b.used += token
+	return &model.QuotaResponse{Code: model.QuotaResultOk}
+}
+
+func (b *countBucket) Release() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.used--
+	b.released++
+}
+
+func (b *countBucket) OnRemoteUpdate(ratelimiter.RemoteQuotaResult) {}
+
+func (b *countBucket) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageInfo {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return ratelimiter.UsageInfo{
+		CurTimeMilli: curTimeMilli,
+		Passed:       map[int64]uint32{time.Second.Milliseconds(): b.used},
+		Limited:      map[int64]uint32{time.Second.Milliseconds(): b.limited},
+	}
+}
+
+func (b *countBucket) GetAmountInfos() []ratelimiter.AmountInfo {
+	return []ratelimiter.AmountInfo{{ValidDuration: 1, MaxAmount: b.maxAmount}}
+}
+
+// countLimiter 为每个限流窗口创建countBucket，按窗口标识记录创建的配额池
+type countLimiter struct {
+	plugin.Plugin
+	mutex   sync.Mutex
+	buckets map[string]*countBucket
+}
+
+func (l *countLimiter) InitQuota(criteria *ratelimiter.InitCriteria) ratelimiter.QuotaBucket {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	bucket := &countBucket{maxAmount: criteria.DstRule.GetAmounts()[0].GetMaxAmount().GetValue()}
+	l.buckets[criteria.DstRule.GetName().GetValue()+"|"+criteria.WindowKey] = bucket
+	return bucket
+}
+
+// bucket 获取规则在指定标签下的配额池
+func (l *countLimiter) bucket(ruleName string, window *RateLimitWindow) *countBucket {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.buckets[ruleName+"|"+window.uniqueKey]
+}
+
+// fakeSupplier 优先返回按插件名注册的限流插件，其余插件都返回countLimiter
+type fakeSupplier struct {
+	plugin.Supplier
+	limiter *countLimiter
+	plugins map[string]plugin.Plugin
+}
+
+func (s *fakeSupplier) GetPlugin(typ common.Type, name string) (plugin.Plugin, error) {
+	if plug, ok := s.plugins[name]; ok {
+		return plug, nil
+	}
+	return s.limiter, nil
+}
+
+func (s *fakeSupplier) GetEventSubscribers(event common.PluginEventType) []common.PluginEventHandler {
+	return nil
+}
+
+// newTestAssistant 创建只包含窗口管理所需字段的限流辅助类
+func newTestAssistant() (*FlowQuotaAssistant, *countLimiter) {
+	limiter := &countLimiter{buckets: make(map[string]*countBucket)}
+	return &FlowQuotaAssistant{
+		enable:             true,
+		supplier:           &fakeSupplier{limiter: limiter, plugins: make(map[string]plugin.Plugin)},
+		shadowRules:        make(map[string]struct{}),
+		purgeIntervalMilli: time.Second.Milliseconds(),
+		mutex:              &sync.Mutex{},
+		svcToWindowSet:     &sync.Map{},
+	}, limiter
+}
+
+// registerTestPlugin 指定插件名对应的限流插件
+func registerTestPlugin(f *FlowQuotaAssistant, name string, plug plugin.Plugin) {
+	f.supplier.(*fakeSupplier).plugins[name] = plug
+}
+
+// newTestRule 创建本地限流规则，规则名同时作为规则ID，版本号为规则名加后缀
+func newTestRule(name string, maxAmount uint32) *apitraffic.Rule {
+	return &apitraffic.Rule{
+		Id:        wrapperspb.String(name),
+		Name:      wrapperspb.String(name),
+		Revision:  wrapperspb.String(name + "-v1"),
+		Namespace: wrapperspb.String(testSvcKey.Namespace),
+		Service:   wrapperspb.String(testSvcKey.Service),
+		Type:      apitraffic.Rule_LOCAL,
+		Amounts: []*apitraffic.Amount{
+			{
+				MaxAmount:     wrapperspb.UInt32(maxAmount),
+				ValidDuration: durationpb.New(time.Second),
+			},
+		},
+	}
+}
+
+// newTestWindow 在服务的窗口集合中创建规则对应的限流窗口
+func newTestWindow(f *FlowQuotaAssistant, rule *apitraffic.Rule, labels string, regexSpread bool) *RateLimitWindow {
+	windowSet := f.GetRateLimitWindowSet(testSvcKey, true)
+	return windowSet.AddRateLimitWindow(&data.CommonRateLimitRequest{}, rule, labels, regexSpread)
+}
+
+// testServiceRule 只包含限流规则及规则缓存的服务规则
+type testServiceRule struct {
+	model.ServiceRule
+	rateLimit *apitraffic.RateLimit
+	ruleCache model.RuleCache
+}
+
+func (r *testServiceRule) GetValue() interface{} {
+	return r.rateLimit
+}
+
+func (r *testServiceRule) GetRuleCache() model.RuleCache {
+	return r.ruleCache
+}
+
+func (r *testServiceRule) GetValidateError() error {
+	return nil
+}
+
+func newTestServiceRule(rules ...*apitraffic.Rule) *testServiceRule {
+	return &testServiceRule{rateLimit: &apitraffic.RateLimit{Rules: rules}, ruleCache: model.NewRuleCache()}
+}
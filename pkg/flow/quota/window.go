@@ -28,6 +28,7 @@ import (
 	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
 	slimiter "github.com/polarismesh/specification/source/go/api/v1/traffic_manage/ratelimiter"
 
+	"github.com/polarismesh/polaris-go/pkg/algorithm/hash"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -39,12 +40,34 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
 )
 
+// windowShardCount 限流窗口的分片数，需为2的幂
+const windowShardCount = 32
+
+// windowKey 限流窗口索引，非正则展开的规则只有一个主窗口，labels为空
+type windowKey struct {
+	revision string
+	labels   string
+}
+
+// shardIndex 计算窗口所在的分片
+func (k windowKey) shardIndex() uint32 {
+	h := hash.FNV32aString(hash.FNV32aOffset, k.revision)
+	h = hash.FNV32aString(h, k.labels)
+	return h & (windowShardCount - 1)
+}
+
+// windowShard 限流窗口分片，不同规则及标签的窗口分散到各分片上，降低锁竞争
+type windowShard struct {
+	// 更新锁
+	mutex sync.RWMutex
+	// 限流窗口
+	windows map[windowKey]*RateLimitWindow
+}
+
 // RateLimitWindowSet 限流分配窗口的缓存
 type RateLimitWindowSet struct {
-	// 更新锁
-	updateMutex sync.RWMutex
-	// 限流窗口列表，key为revision，value为WindowContainer
-	windowByRule map[string]*WindowContainer
+	// 限流窗口分片
+	shards [windowShardCount]*windowShard
 	// 储存FlowQuotaAssistant
 	flowAssistant *FlowQuotaAssistant
 	// 最近一次超时检查时间
@@ -53,38 +76,48 @@ type RateLimitWindowSet struct {
 
 // NewRateLimitWindowSet 构造函数
 func NewRateLimitWindowSet(assistant *FlowQuotaAssistant) *RateLimitWindowSet {
-	return &RateLimitWindowSet{
-		windowByRule:       make(map[string]*WindowContainer),
+	rs := &RateLimitWindowSet{
 		flowAssistant:      assistant,
 		lastPurgeTimeMilli: model.CurrentMillisecond(),
 	}
+	for i := range rs.shards {
+		rs.shards[i] = &windowShard{windows: make(map[windowKey]*RateLimitWindow)}
+	}
+	return rs
+}
+
+// newWindowKey 构造窗口索引
+func newWindowKey(rule *apitraffic.Rule, flatLabels string, regexSpread bool) windowKey {
+	key := windowKey{revision: rule.GetRevision().GetValue()}
+	if regexSpread {
+		key.labels = flatLabels
+	}
+	return key
 }
 
 // GetRateLimitWindows 拷贝一份只读数据
 func (rs *RateLimitWindowSet) GetRateLimitWindows() []*RateLimitWindow {
-	rs.updateMutex.RLock()
-	defer rs.updateMutex.RUnlock()
-	result := make([]*RateLimitWindow, 0, len(rs.windowByRule))
-	for _, container := range rs.windowByRule {
-		result = append(result, container.GetRateLimitWindows()...)
+	var result []*RateLimitWindow
+	for _, shard := range rs.shards {
+		shard.mutex.RLock()
+		for _, window := range shard.windows {
+			result = append(result, window)
+		}
+		shard.mutex.RUnlock()
 	}
 	return result
 }
 
 // GetRateLimitWindow 获取限流窗口
-func (rs *RateLimitWindowSet) GetRateLimitWindow(rule *apitraffic.Rule, flatLabels string) *RateLimitWindow {
+func (rs *RateLimitWindowSet) GetRateLimitWindow(
+	rule *apitraffic.Rule, flatLabels string, regexSpread bool) *RateLimitWindow {
 	// 访问前进行一次窗口淘汰检查
 	rs.PurgeWindows(model.CurrentMillisecond())
-	rs.updateMutex.RLock()
-	defer rs.updateMutex.RUnlock()
-	container := rs.windowByRule[rule.GetRevision().GetValue()]
-	if nil == container {
-		return nil
-	}
-	if nil != container.MainWindow {
-		return container.MainWindow
-	}
-	return container.WindowByLabel[flatLabels]
+	key := newWindowKey(rule, flatLabels, regexSpread)
+	shard := rs.shards[key.shardIndex()]
+	shard.mutex.RLock()
+	defer shard.mutex.RUnlock()
+	return shard.windows[key]
 }
 
 // PurgeWindows 执行窗口淘汰
@@ -124,49 +157,32 @@ func HasRegex(rule *apitraffic.Rule) bool {
 // AddRateLimitWindow 添加限流窗口
 func (rs *RateLimitWindowSet) AddRateLimitWindow(
 	commonRequest *data.CommonRateLimitRequest, rule *apitraffic.Rule, flatLabels string, regexSpread bool) *RateLimitWindow {
-	rs.updateMutex.Lock()
-	defer rs.updateMutex.Unlock()
-	container := rs.windowByRule[rule.GetRevision().GetValue()]
-	if nil == container {
-		container = NewWindowContainer()
-		rs.windowByRule[rule.GetRevision().GetValue()] = container
-	}
-	var window *RateLimitWindow
-	if regexSpread {
-		window = container.WindowByLabel[flatLabels]
-	} else {
-		window = container.MainWindow
-	}
-	if nil != window {
+	key := newWindowKey(rule, flatLabels, regexSpread)
+	shard := rs.shards[key.shardIndex()]
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	if window := shard.windows[key]; nil != window {
 		return window
 	}
-	window = NewRateLimitWindow(rs, rule, commonRequest, flatLabels)
-	if regexSpread {
-		container.WindowByLabel[flatLabels] = window
-	} else {
-		container.MainWindow = window
-	}
+	window := NewRateLimitWindow(rs, rule, commonRequest, flatLabels)
+	window.key = key
+	shard.windows[key] = window
 	rs.flowAssistant.AddWindowCount()
 	return window
 }
 
 // OnWindowExpired 窗口过期
 func (rs *RateLimitWindowSet) OnWindowExpired(nowMilli int64, window *RateLimitWindow) bool {
-	rs.updateMutex.Lock()
-	defer rs.updateMutex.Unlock()
+	shard := rs.shards[window.key.shardIndex()]
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
 	if !window.Expired(nowMilli) {
 		return false
 	}
 	log.GetBaseLogger().Infof("[RateLimit]window expired, key=%s, nowMilli=%d, expireDuration=%d",
 		window.uniqueKey, nowMilli, model.ToMilliSeconds(window.expireDuration))
-	revision := window.Rule.GetRevision().GetValue()
-	container := rs.windowByRule[revision]
-	if nil != container {
-		if container.MainWindow == window {
-			delete(rs.windowByRule, revision)
-		} else {
-			delete(container.WindowByLabel, window.Labels)
-		}
+	if shard.windows[window.key] == window {
+		delete(shard.windows, window.key)
 	}
 	rs.deleteWindow(window)
 	return true
@@ -221,8 +237,6 @@ func (rs *RateLimitWindowSet) OnServiceUpdated(svcEventObject *common.ServiceEve
 	if nil == updatedRules {
 		return
 	}
-	rs.updateMutex.Lock()
-	defer rs.updateMutex.Unlock()
 	switch svcEventObject.SvcEventKey.Type {
 	case model.EventRateLimiting:
 		if len(updatedRules.DeletedRules) > 0 {
@@ -236,26 +250,22 @@ func (rs *RateLimitWindowSet) OnServiceUpdated(svcEventObject *common.ServiceEve
 	}
 }
 
-// deleteContainer 删除容器对象
+// deleteContainer 删除规则版本对应的所有窗口
 func (rs *RateLimitWindowSet) deleteContainer(revision string) {
-	container := rs.windowByRule[revision]
-	delete(rs.windowByRule, revision)
-	log.GetBaseLogger().Infof("[RateLimit]container %s has deleted", revision)
-	if nil == container {
+	if len(revision) == 0 {
 		return
 	}
-	if nil != container.MainWindow {
-		rs.deleteWindow(container.MainWindow)
-		log.GetBaseLogger().Infof(
-			"[RateLimit]container main window %s has deleted", container.MainWindow.uniqueKey)
-	}
-	if len(container.WindowByLabel) == 0 {
-		return
-	}
-	for _, window := range container.WindowByLabel {
-		rs.deleteWindow(window)
-		log.GetBaseLogger().Infof(
-			"[RateLimit]container spread window %s has deleted", window.uniqueKey)
+	for _, shard := range rs.shards {
+		shard.mutex.Lock()
+		for key, window := range shard.windows {
+			if key.revision != revision {
+				continue
+			}
+			delete(shard.windows, key)
+			rs.deleteWindow(window)
+			log.GetBaseLogger().Infof("[RateLimit]window %s of container %s has deleted", window.uniqueKey, revision)
+		}
+		shard.mutex.Unlock()
 	}
 }
 
@@ -265,34 +275,6 @@ func (rs *RateLimitWindowSet) deleteWindow(window *RateLimitWindow) {
 	rs.flowAssistant.DelWindowCount()
 }
 
-// WindowContainer 窗口容器
-type WindowContainer struct {
-	// 主窗口，非正则表达式的适用
-	MainWindow *RateLimitWindow
-	// 适用于正则表达式展开的
-	WindowByLabel map[string]*RateLimitWindow
-}
-
-// GetRateLimitWindows 获取限流滑窗
-func (w *WindowContainer) GetRateLimitWindows() []*RateLimitWindow {
-	windows := make([]*RateLimitWindow, 0, len(w.WindowByLabel))
-	if nil != w.MainWindow {
-		windows = append(windows, w.MainWindow)
-	} else if len(w.WindowByLabel) > 0 {
-		for _, window := range w.WindowByLabel {
-			windows = append(windows, window)
-		}
-	}
-	return windows
-}
-
-// NewWindowContainer 创建窗口容器
-func NewWindowContainer() *WindowContainer {
-	return &WindowContainer{
-		WindowByLabel: make(map[string]*RateLimitWindow),
-	}
-}
-
 const (
 	// Created 刚创建， 无需进行后台调度
 	Created int64 = iota
@@ -320,6 +302,8 @@ type RateLimitWindow struct {
 	Labels string
 	// 窗口的唯一标识，服务名+labels
 	uniqueKey string
+	// 窗口在窗口集合中的索引
+	key windowKey
 	// 通过服务名+labels计算出来的hash值，用于选上报服务器
 	hashValue uint64
 	// 最后一次获取限流配额时间
@@ -341,6 +325,9 @@ type RateLimitWindow struct {
 	syncParam RemoteSyncParam
 	// 流量整形算法桶
 	trafficShapingBucket ratelimiter.QuotaBucket
+	// 匀速排队模式使用的配额池，按需创建
+	throttlingBucket ratelimiter.ThrottlingQuotaBucket
+	throttlingOnce   sync.Once
 	// 限流插件
 	rateLimiter ratelimiter.ServiceRateLimiter
 	// 初始化后指定的限流模式（本地或远程）
@@ -351,6 +338,10 @@ type RateLimitWindow struct {
 	status int64
 	// 与服务端的时间差
 	timeDiff int64
+	// 窗口创建以来累计通过的请求数
+	passedCount uint64
+	// 窗口创建以来累计被限流的请求数
+	limitedCount uint64
 }
 
 // 超过多长时间后进行淘汰，淘汰后需要重新init
@@ -359,10 +350,16 @@ var (
 	ExpireFactor = 1 * time.Second
 
 	DefaultStatisticReportPeriod = 1 * time.Second
+
+	// ConcurrencyExpireDuration 并发数限流窗口的淘汰周期，需要覆盖请求的处理时长，避免淘汰后并发数被重置
+	ConcurrencyExpireDuration = 1 * time.Minute
 )
 
 // getExpireDuration 计算淘汰周期
 func getExpireDuration(rule *apitraffic.Rule) time.Duration {
+	if rule.GetResource() == apitraffic.Rule_CONCURRENCY {
+		return ConcurrencyExpireDuration
+	}
 	return getMaxDuration(rule) + ExpireFactor
 }
 
@@ -396,7 +393,12 @@ func NewRateLimitWindow(windowSet *RateLimitWindowSet, rule *apitraffic.Rule,
 	}
 	window.syncParam.ControlParam = commonRequest.ControlParam
 
-	window.rateLimiter = createBehavior(windowSet.flowAssistant.supplier, rule.GetAction().GetValue())
+	behaviorName := rule.GetAction().GetValue()
+	if rule.GetResource() == apitraffic.Rule_CONCURRENCY {
+		// 并发数限流规则统一使用并发数限流器
+		behaviorName = config.DefaultConcurrencyRateLimiter
+	}
+	window.rateLimiter = createBehavior(windowSet.flowAssistant.supplier, behaviorName)
 	// 初始化流量整形窗口
 	window.trafficShapingBucket = window.rateLimiter.InitQuota(
 		&ratelimiter.InitCriteria{DstRule: rule, WindowKey: window.uniqueKey})
@@ -437,8 +439,8 @@ func (r *RateLimitWindow) UpdateTimeDiff(timeDiff int64) {
 
 // buildRemoteConfigMode 构建限流模式及集群
 func (r *RateLimitWindow) buildRemoteConfigMode(windowSet *RateLimitWindowSet, rule *apitraffic.Rule) {
-	// 解析限流集群配置
-	if rule.GetType() == apitraffic.Rule_LOCAL {
+	// 解析限流集群配置，并发数限流只在本地生效
+	if rule.GetType() == apitraffic.Rule_LOCAL || rule.GetResource() == apitraffic.Rule_CONCURRENCY {
 		r.configMode = model.ConfigQuotaLocalMode
 		return
 	}
@@ -563,7 +565,7 @@ func (r *RateLimitWindow) EnsureDeleted(value interface{}) bool {
 }
 
 // acquireRequest 转换成限流PB上报消息
-func (r *RateLimitWindow) acquireRequest() *limitpb.ClientRateLimitReportRequest {
+func (r *RateLimitWindow) acquireRequest() (*limitpb.ClientRateLimitReportRequest, ratelimiter.UsageInfo) {
 	reportReq := &limitpb.ClientRateLimitReportRequest{
 		Service:   r.SvcKey.Service,
 		Namespace: r.SvcKey.Namespace,
@@ -581,7 +583,16 @@ func (r *RateLimitWindow) acquireRequest() *limitpb.ClientRateLimitReportRequest
 			}
 		}
 	}
-	return reportReq
+	return reportReq, usageInfo
+}
+
+// restoreQuotaUsed 上报失败时归还使用情况，待限流服务端恢复后重新上报
+func (r *RateLimitWindow) restoreQuotaUsed(usageInfo ratelimiter.UsageInfo) {
+	restorable, ok := r.trafficShapingBucket.(ratelimiter.UsageRestorableBucket)
+	if !ok {
+		return
+	}
+	restorable.RestoreQuotaUsed(r.toServerTimeMilli(model.CurrentMillisecond()), usageInfo)
 }
 
 // GetStatus 原子获取状态
@@ -605,7 +616,75 @@ func (r *RateLimitWindow) AllocateQuota(commonRequest *data.CommonRateLimitReque
 	atomic.StoreInt64(&r.lastAccessTimeMilli, nowMilli)
 	// 获取服务端时间
 	curTimeMs := r.toServerTimeMilli(nowMilli)
-	return r.trafficShapingBucket.GetQuota(curTimeMs, commonRequest.Token)
+	var resp *model.QuotaResponse
+	if commonRequest.Throttling {
+		if bucket := r.getThrottlingBucket(); bucket != nil {
+			resp = bucket.GetQuotaWithQueue(curTimeMs, commonRequest.Token, commonRequest.MaxQueueMs)
+		}
+	}
+	if resp == nil {
+		resp = r.trafficShapingBucket.GetQuota(curTimeMs, commonRequest.Token)
+	}
+	if resp.Code == model.QuotaResultOk {
+		atomic.AddUint64(&r.passedCount, 1)
+	} else {
+		atomic.AddUint64(&r.limitedCount, 1)
+	}
+	return resp
+}
+
+// GetUsage 获取窗口创建以来累计通过与被限流的请求数
+func (r *RateLimitWindow) GetUsage() (passed uint64, limited uint64) {
+	return atomic.LoadUint64(&r.passedCount), atomic.LoadUint64(&r.limitedCount)
+}
+
+// GetAmountInfos 获取窗口对应规则的限流阈值
+func (r *RateLimitWindow) GetAmountInfos() []ratelimiter.AmountInfo {
+	return r.trafficShapingBucket.GetAmountInfos()
+}
+
+// GetConfigMode 获取窗口的限流模式（本地或远程）
+func (r *RateLimitWindow) GetConfigMode() model.ConfigMode {
+	return r.configMode
+}
+
+// getThrottlingBucket 获取匀速排队模式使用的配额池
+// 窗口本身的配额池不支持排队时，按照同一规则创建匀速排队的漏桶
+func (r *RateLimitWindow) getThrottlingBucket() ratelimiter.ThrottlingQuotaBucket {
+	if bucket, ok := r.trafficShapingBucket.(ratelimiter.ThrottlingQuotaBucket); ok {
+		return bucket
+	}
+	r.throttlingOnce.Do(func() {
+		plug, err := r.WindowSet.flowAssistant.supplier.GetPlugin(
+			common.TypeRateLimiter, config.DefaultUniformRateLimiter)
+		if err != nil {
+			log.GetBaseLogger().Errorf("[RateLimit]fail to get %s rateLimiter for throttling, window %s: %v",
+				config.DefaultUniformRateLimiter, r.uniqueKey, err)
+			return
+		}
+		bucket := plug.(ratelimiter.ServiceRateLimiter).InitQuota(
+			&ratelimiter.InitCriteria{DstRule: r.Rule, WindowKey: r.uniqueKey})
+		r.throttlingBucket, _ = bucket.(ratelimiter.ThrottlingQuotaBucket)
+	})
+	return r.throttlingBucket
+}
+
+// Release 释放配额，throttling为分配时是否使用匀速排队模式，allocateTime为配额分配的时间
+func (r *RateLimitWindow) Release(throttling bool, allocateTime time.Time) {
+	if throttling && r.getThrottlingBucket() != nil {
+		// 匀速排队的漏桶无需释放配额
+		return
+	}
+	releaseBucket(r.trafficShapingBucket, allocateTime)
+}
+
+// releaseBucket 释放配额池中的配额，需要感知耗时的配额池会传入从分配到释放的耗时
+func releaseBucket(bucket ratelimiter.QuotaBucket, allocateTime time.Time) {
+	if timed, ok := bucket.(ratelimiter.TimedQuotaBucket); ok {
+		timed.ReleaseWithDuration(time.Since(allocateTime))
+		return
+	}
+	bucket.Release()
 }
 
 // GetLastAccessTimeMilli 获取最近访问时间
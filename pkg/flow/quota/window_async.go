@@ -67,9 +67,10 @@ func (r *RateLimitWindow) DoAsyncRemoteAcquire() error {
 	timeDiff := sender.AdjustTime()
 	r.UpdateTimeDiff(timeDiff)
 
-	request := r.acquireRequest()
+	request, usageInfo := r.acquireRequest()
 	err = sender.SendReportRequest(request)
 	if err != nil {
+		r.restoreQuotaUsed(usageInfo)
 		log.GetBaseLogger().Errorf(
 			"fail to call RateLimitService.Acquire, service %s, labels %s, error is %s",
 			r.SvcKey, r.Labels, err)
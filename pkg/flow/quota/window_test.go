@@ -0,0 +1,566 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// TestAddRateLimitWindow 测试窗口按规则版本及展开的标签去重
+func TestAddRateLimitWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      []string
+		regexSpread bool
+		wantWindows int
+	}{
+		{
+			name:        "相同标签复用窗口",
+			labels:      []string{"a=1", "a=1"},
+			wantWindows: 1,
+		},
+		{
+			name:        "未展开正则时不同标签共用主窗口",
+			labels:      []string{"a=1", "a=2"},
+			wantWindows: 1,
+		},
+		{
+			name:        "展开正则时每个标签一个窗口",
+			labels:      []string{"a=1", "a=2", "a=1"},
+			regexSpread: true,
+			wantWindows: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, _ := newTestAssistant()
+			rule := newTestRule("rule", 10)
+			created := make(map[*RateLimitWindow]struct{})
+			for _, labels := range tt.labels {
+				created[newTestWindow(f, rule, labels, tt.regexSpread)] = struct{}{}
+			}
+			windowSet := f.GetRateLimitWindowSet(testSvcKey, false)
+			assert.Equal(t, tt.wantWindows, len(created))
+			assert.Equal(t, tt.wantWindows, len(windowSet.GetRateLimitWindows()))
+			assert.Equal(t, int32(tt.wantWindows), f.GetWindowCount())
+			for _, labels := range tt.labels {
+				window := windowSet.GetRateLimitWindow(rule, labels, tt.regexSpread)
+				_, ok := created[window]
+				assert.True(t, ok)
+			}
+		})
+	}
+}
+
+// TestRateLimitWindowInit 测试本地限流窗口初始化后直接可用，且只初始化一次
+func TestRateLimitWindowInit(t *testing.T) {
+	f, _ := newTestAssistant()
+	window := newTestWindow(f, newTestRule("rule", 10), "", false)
+	assert.Equal(t, Created, window.GetStatus())
+	assert.Equal(t, model.ConfigQuotaLocalMode, window.GetConfigMode())
+
+	window.Init()
+	assert.Equal(t, Initialized, window.GetStatus())
+
+	// 已删除的窗口不会被再次初始化
+	window.SetStatus(Deleted)
+	window.Init()
+	assert.Equal(t, Deleted, window.GetStatus())
+}
+
+// TestPurgeWindows 测试窗口超过最大统计周期+1s未访问后被淘汰
+func TestPurgeWindows(t *testing.T) {
+	tests := []struct {
+		name string
+		// 距最近一次分配配额的时间
+		idleMilli   int64
+		wantExpired bool
+	}{
+		{
+			name:      "未超过淘汰周期",
+			idleMilli: 1500,
+		},
+		{
+			name:      "恰好达到淘汰周期",
+			idleMilli: 2000,
+		},
+		{
+			name:        "超过淘汰周期",
+			idleMilli:   2001,
+			wantExpired: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, limiter := newTestAssistant()
+			rule := newTestRule("rule", 10)
+			window := newTestWindow(f, rule, "", false)
+			window.Init()
+			resp := window.AllocateQuota(&data.CommonRateLimitRequest{Token: 1})
+			assert.Equal(t, model.QuotaResultOk, resp.Code)
+			assert.Equal(t, uint32(1), limiter.bucket("rule", window).used)
+
+			windowSet := f.GetRateLimitWindowSet(testSvcKey, false)
+			nowMilli := window.GetLastAccessTimeMilli() + tt.idleMilli
+			assert.Equal(t, tt.wantExpired, window.Expired(nowMilli))
+			windowSet.PurgeWindows(nowMilli)
+
+			windows := windowSet.GetRateLimitWindows()
+			if !tt.wantExpired {
+				assert.Equal(t, []*RateLimitWindow{window}, windows)
+				assert.Equal(t, Initialized, window.GetStatus())
+				assert.Equal(t, int32(1), f.GetWindowCount())
+				return
+			}
+			assert.Empty(t, windows)
+			assert.Equal(t, Deleted, window.GetStatus())
+			assert.True(t, window.EnsureDeleted(window))
+			assert.Equal(t, int32(0), f.GetWindowCount())
+			// 淘汰后再次访问会创建新的窗口及配额池
+			recreated := newTestWindow(f, rule, "", false)
+			assert.NotSame(t, window, recreated)
+			assert.Equal(t, uint32(0), limiter.bucket("rule", recreated).used)
+		})
+	}
+}
+
+// TestPurgeWindowsInterval 测试两次淘汰检查的间隔不小于purgeIntervalMilli
+func TestPurgeWindowsInterval(t *testing.T) {
+	f, _ := newTestAssistant()
+	window := newTestWindow(f, newTestRule("rule", 10), "", false)
+	window.AllocateQuota(&data.CommonRateLimitRequest{Token: 1})
+	windowSet := f.GetRateLimitWindowSet(testSvcKey, false)
+	lastAccessMilli := window.GetLastAccessTimeMilli()
+
+	// 检查时窗口尚未过期
+	windowSet.PurgeWindows(lastAccessMilli + 1500)
+	assert.Equal(t, 1, len(windowSet.GetRateLimitWindows()))
+	// 窗口已过期，但距上次检查不足purgeIntervalMilli，不执行淘汰
+	windowSet.PurgeWindows(lastAccessMilli + 2100)
+	assert.Equal(t, 1, len(windowSet.GetRateLimitWindows()))
+	// 达到检查间隔后淘汰
+	windowSet.PurgeWindows(lastAccessMilli + 2500)
+	assert.Empty(t, windowSet.GetRateLimitWindows())
+	assert.Equal(t, Deleted, window.GetStatus())
+}
+
+// TestOnWindowExpiredAfterAccess 测试淘汰前窗口被再次访问时不删除
+func TestOnWindowExpiredAfterAccess(t *testing.T) {
+	f, _ := newTestAssistant()
+	window := newTestWindow(f, newTestRule("rule", 10), "", false)
+	window.AllocateQuota(&data.CommonRateLimitRequest{Token: 1})
+	windowSet := f.GetRateLimitWindowSet(testSvcKey, false)
+	nowMilli := window.GetLastAccessTimeMilli() + 2001
+	assert.True(t, window.Expired(nowMilli))
+
+	window.AllocateQuota(&data.CommonRateLimitRequest{Token: 1})
+	assert.False(t, windowSet.OnWindowExpired(window.GetLastAccessTimeMilli()+1000, window))
+	assert.Equal(t, 1, len(windowSet.GetRateLimitWindows()))
+	assert.NotEqual(t, Deleted, window.GetStatus())
+}
+
+// TestDeleteContainer 测试规则版本变更时只删除旧版本的窗口
+func TestDeleteContainer(t *testing.T) {
+	f, _ := newTestAssistant()
+	ruleA := newTestRule("a", 10)
+	ruleB := newTestRule("b", 10)
+	windowsA := []*RateLimitWindow{
+		newTestWindow(f, ruleA, "x=1", true),
+		newTestWindow(f, ruleA, "x=2", true),
+	}
+	windowB := newTestWindow(f, ruleB, "", false)
+	windowSet := f.GetRateLimitWindowSet(testSvcKey, false)
+	assert.Equal(t, int32(3), f.GetWindowCount())
+
+	windowSet.deleteContainer("")
+	assert.Equal(t, 3, len(windowSet.GetRateLimitWindows()))
+
+	windowSet.deleteContainer(ruleA.GetRevision().GetValue())
+	assert.Equal(t, []*RateLimitWindow{windowB}, windowSet.GetRateLimitWindows())
+	assert.Equal(t, int32(1), f.GetWindowCount())
+	for _, window := range windowsA {
+		assert.Equal(t, Deleted, window.GetStatus())
+		assert.Nil(t, windowSet.GetRateLimitWindow(ruleA, window.Labels, true))
+	}
+	assert.Equal(t, Created, windowB.GetStatus())
+}
+
+// timedBucket 记录释放时传入耗时的配额池
+type timedBucket struct {
+	countBucket
+	durations []time.Duration
+}
+
+func (b *timedBucket) ReleaseWithDuration(duration time.Duration) {
+	b.Release()
+	b.durations = append(b.durations, duration)
+}
+
+// timedLimiter 创建timedBucket的限流插件
+type timedLimiter struct {
+	plugin.Plugin
+	buckets []*timedBucket
+}
+
+func (l *timedLimiter) InitQuota(criteria *ratelimiter.InitCriteria) ratelimiter.QuotaBucket {
+	bucket := &timedBucket{countBucket: countBucket{maxAmount: criteria.DstRule.GetAmounts()[0].GetMaxAmount().GetValue()}}
+	l.buckets = append(l.buckets, bucket)
+	return bucket
+}
+
+// newConcurrencyRule 创建配置了远程限流集群的并发数限流规则
+func newConcurrencyRule(name string, maxAmount uint32) *apitraffic.Rule {
+	rule := newTestRule(name, maxAmount)
+	rule.Resource = apitraffic.Rule_CONCURRENCY
+	rule.Type = apitraffic.Rule_GLOBAL
+	rule.Action = wrapperspb.String(config.DefaultRejectRateLimiter)
+	rule.Cluster = &apitraffic.RateLimitCluster{
+		Namespace: wrapperspb.String("Polaris"),
+		Service:   wrapperspb.String("polaris.limiter"),
+	}
+	return rule
+}
+
+// TestGetExpireDuration 测试窗口淘汰周期的计算
+func TestGetExpireDuration(t *testing.T) {
+	minuteRule := newTestRule("minute", 10)
+	minuteRule.Amounts = append(minuteRule.Amounts, &apitraffic.Amount{
+		MaxAmount:     wrapperspb.UInt32(100),
+		ValidDuration: durationpb.New(time.Minute),
+	})
+	tests := []struct {
+		name string
+		rule *apitraffic.Rule
+		want time.Duration
+	}{
+		{
+			name: "单个统计周期",
+			rule: newTestRule("second", 10),
+			want: time.Second + ExpireFactor,
+		},
+		{
+			name: "取最大统计周期",
+			rule: minuteRule,
+			want: time.Minute + ExpireFactor,
+		},
+		{
+			name: "并发数限流使用固定淘汰周期",
+			rule: newConcurrencyRule("concurrency", 10),
+			want: ConcurrencyExpireDuration,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getExpireDuration(tt.rule))
+		})
+	}
+}
+
+// TestConcurrencyWindow 测试并发数限流窗口统一使用并发数限流器，只在本地生效，且淘汰周期覆盖请求处理时长
+func TestConcurrencyWindow(t *testing.T) {
+	f, limiter := newTestAssistant()
+	concurrencyLimiter := &timedLimiter{}
+	registerTestPlugin(f, config.DefaultConcurrencyRateLimiter, concurrencyLimiter)
+	window := newTestWindow(f, newConcurrencyRule("concurrency", 1), "", false)
+	assert.Empty(t, limiter.buckets)
+	assert.Equal(t, 1, len(concurrencyLimiter.buckets))
+	assert.Equal(t, model.ConfigQuotaLocalMode, window.GetConfigMode())
+	window.Init()
+	assert.Equal(t, Initialized, window.GetStatus())
+
+	bucket := concurrencyLimiter.buckets[0]
+	allocateTime := time.Now().Add(-50 * time.Millisecond)
+	assert.Equal(t, model.QuotaResultOk, window.AllocateQuota(&data.CommonRateLimitRequest{Token: 1}).Code)
+	assert.Equal(t, model.QuotaResultLimited, window.AllocateQuota(&data.CommonRateLimitRequest{Token: 1}).Code)
+	// 释放时传入从分配到释放的耗时，归还并发数后可再次获取
+	window.Release(false, allocateTime)
+	assert.Equal(t, 1, len(bucket.durations))
+	assert.True(t, bucket.durations[0] >= 50*time.Millisecond)
+	assert.Equal(t, model.QuotaResultOk, window.AllocateQuota(&data.CommonRateLimitRequest{Token: 1}).Code)
+	passed, limited := window.GetUsage()
+	assert.Equal(t, uint64(2), passed)
+	assert.Equal(t, uint64(1), limited)
+
+	// 长时间处理的请求期间窗口不会被淘汰
+	lastAccessMilli := window.GetLastAccessTimeMilli()
+	assert.False(t, window.Expired(lastAccessMilli+model.ToMilliSeconds(ConcurrencyExpireDuration)))
+	assert.True(t, window.Expired(lastAccessMilli+model.ToMilliSeconds(ConcurrencyExpireDuration)+1))
+}
+
+// TestReleaseBucket 测试不感知耗时的配额池直接释放
+func TestReleaseBucket(t *testing.T) {
+	f, limiter := newTestAssistant()
+	window := newTestWindow(f, newTestRule("rule", 1), "", false)
+	bucket := limiter.bucket("rule", window)
+	assert.Equal(t, model.QuotaResultOk, window.AllocateQuota(&data.CommonRateLimitRequest{Token: 1}).Code)
+	window.Release(false, time.Now())
+	assert.Equal(t, uint32(0), bucket.used)
+	assert.Equal(t, uint32(1), bucket.released)
+}
+
+// queueBucket 支持排队的配额池，记录每次分配使用的最大排队时间，并按最大排队时间返回等待时间
+type queueBucket struct {
+	countBucket
+	maxQueueMs []int64
+}
+
+func (b *queueBucket) GetQuotaWithQueue(curTimeMs int64, token uint32, maxQueueMs int64) *model.QuotaResponse {
+	b.maxQueueMs = append(b.maxQueueMs, maxQueueMs)
+	return &model.QuotaResponse{Code: model.QuotaResultOk, WaitMs: maxQueueMs}
+}
+
+// queueLimiter 创建queueBucket的限流插件
+type queueLimiter struct {
+	plugin.Plugin
+	buckets []*queueBucket
+}
+
+func (l *queueLimiter) InitQuota(criteria *ratelimiter.InitCriteria) ratelimiter.QuotaBucket {
+	bucket := &queueBucket{countBucket: countBucket{maxAmount: criteria.DstRule.GetAmounts()[0].GetMaxAmount().GetValue()}}
+	l.buckets = append(l.buckets, bucket)
+	return bucket
+}
+
+// TestAllocateQuotaThrottling 测试匀速排队请求使用的配额池及释放逻辑
+func TestAllocateQuotaThrottling(t *testing.T) {
+	tests := []struct {
+		name string
+		// 规则指定的限流行为
+		action string
+		// 匀速排队限流器创建的配额池是否支持排队
+		uniformQueue bool
+		throttling   bool
+		// 期望匀速排队限流器创建的配额池个数
+		wantUniformBuckets int
+		// 期望请求经过排队配额池分配，否则经过窗口配额池分配并在释放时归还
+		wantQueued bool
+	}{
+		{
+			name:               "窗口配额池支持排队时直接使用",
+			action:             config.DefaultUniformRateLimiter,
+			uniformQueue:       true,
+			throttling:         true,
+			wantUniformBuckets: 1,
+			wantQueued:         true,
+		},
+		{
+			name:               "窗口配额池不支持排队时按规则创建一个匀速排队配额池",
+			uniformQueue:       true,
+			throttling:         true,
+			wantUniformBuckets: 1,
+			wantQueued:         true,
+		},
+		{
+			name:       "匀速排队限流器不支持排队时回退到窗口配额池",
+			throttling: true,
+		},
+		{
+			name:         "非匀速排队请求使用窗口配额池",
+			uniformQueue: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, _ := newTestAssistant()
+			uniform := &queueLimiter{}
+			if tt.uniformQueue {
+				registerTestPlugin(f, config.DefaultUniformRateLimiter, uniform)
+			} else {
+				registerTestPlugin(f, config.DefaultUniformRateLimiter,
+					&countLimiter{buckets: make(map[string]*countBucket)})
+			}
+			rule := newTestRule("rule", 10)
+			rule.Action = wrapperspb.String(tt.action)
+			window := newTestWindow(f, rule, "", false)
+			window.Init()
+
+			maxQueueMs := []int64{100, 500}
+			for _, queueMs := range maxQueueMs {
+				resp := window.AllocateQuota(&data.CommonRateLimitRequest{
+					Token: 1, Throttling: tt.throttling, MaxQueueMs: queueMs})
+				assert.Equal(t, model.QuotaResultOk, resp.Code)
+				if tt.wantQueued {
+					assert.Equal(t, queueMs, resp.WaitMs)
+				} else {
+					assert.Equal(t, int64(0), resp.WaitMs)
+				}
+			}
+			passed, limited := window.GetUsage()
+			assert.Equal(t, uint64(2), passed)
+			assert.Equal(t, uint64(0), limited)
+			assert.Equal(t, tt.wantUniformBuckets, len(uniform.buckets))
+			if tt.wantQueued {
+				// 多次分配共用同一个排队配额池，并使用请求指定的最大排队时间
+				assert.Equal(t, maxQueueMs, uniform.buckets[0].maxQueueMs)
+			}
+
+			var windowBucket *countBucket
+			switch bucket := window.trafficShapingBucket.(type) {
+			case *countBucket:
+				windowBucket = bucket
+			case *queueBucket:
+				windowBucket = &bucket.countBucket
+			}
+			if tt.wantQueued {
+				assert.Equal(t, uint32(0), windowBucket.used)
+			} else {
+				assert.Equal(t, uint32(2), windowBucket.used)
+			}
+			window.Release(tt.throttling, time.Now())
+			window.Release(tt.throttling, time.Now())
+			if tt.wantQueued {
+				// 匀速排队的配额池无需释放
+				assert.Equal(t, uint32(0), windowBucket.released)
+				return
+			}
+			assert.Equal(t, uint32(0), windowBucket.used)
+			assert.Equal(t, uint32(2), windowBucket.released)
+		})
+	}
+}
+
+// benchRules 并发测试使用的规则数及每个规则展开的标签数
+const (
+	benchRuleCount  = 8
+	benchLabelCount = 32
+)
+
+// benchWindowKey 并发测试中访问的窗口
+type benchWindowKey struct {
+	rule   *apitraffic.Rule
+	labels string
+}
+
+func newBenchWindowSet() (*RateLimitWindowSet, []benchWindowKey) {
+	f, _ := newTestAssistant()
+	windowSet := f.GetRateLimitWindowSet(testSvcKey, true)
+	var keys []benchWindowKey
+	for i := 0; i < benchRuleCount; i++ {
+		rule := newTestRule(fmt.Sprintf("rule-%d", i), 100)
+		for j := 0; j < benchLabelCount; j++ {
+			key := benchWindowKey{rule: rule, labels: fmt.Sprintf("uid=%d", j)}
+			windowSet.AddRateLimitWindow(&data.CommonRateLimitRequest{}, key.rule, key.labels, true)
+			keys = append(keys, key)
+		}
+	}
+	return windowSet, keys
+}
+
+// lockedWindowSet 改造前使用单把读写锁保护全部窗口的窗口集合，作为对照
+type lockedWindowSet struct {
+	*RateLimitWindowSet
+	mutex   sync.RWMutex
+	windows map[windowKey]*RateLimitWindow
+}
+
+func newLockedWindowSet(windowSet *RateLimitWindowSet) *lockedWindowSet {
+	locked := &lockedWindowSet{RateLimitWindowSet: windowSet, windows: make(map[windowKey]*RateLimitWindow)}
+	for _, window := range windowSet.GetRateLimitWindows() {
+		locked.windows[window.key] = window
+	}
+	return locked
+}
+
+func (rs *lockedWindowSet) GetRateLimitWindow(
+	rule *apitraffic.Rule, flatLabels string, regexSpread bool) *RateLimitWindow {
+	rs.PurgeWindows(model.CurrentMillisecond())
+	key := newWindowKey(rule, flatLabels, regexSpread)
+	rs.mutex.RLock()
+	defer rs.mutex.RUnlock()
+	return rs.windows[key]
+}
+
+func (rs *lockedWindowSet) AddRateLimitWindow(
+	commonRequest *data.CommonRateLimitRequest, rule *apitraffic.Rule, flatLabels string, regexSpread bool) *RateLimitWindow {
+	key := newWindowKey(rule, flatLabels, regexSpread)
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	if window := rs.windows[key]; nil != window {
+		return window
+	}
+	window := NewRateLimitWindow(rs.RateLimitWindowSet, rule, commonRequest, flatLabels)
+	window.key = key
+	rs.windows[key] = window
+	return window
+}
+
+// benchWindowAccessor 窗口集合的查找及创建操作
+type benchWindowAccessor interface {
+	GetRateLimitWindow(rule *apitraffic.Rule, flatLabels string, regexSpread bool) *RateLimitWindow
+	AddRateLimitWindow(commonRequest *data.CommonRateLimitRequest, rule *apitraffic.Rule,
+		flatLabels string, regexSpread bool) *RateLimitWindow
+}
+
+// runWindowSetParallel 并发访问窗口，每writeEvery次查找进行一次创建，writeEvery为0时只查找
+func runWindowSetParallel(b *testing.B, windowSet benchWindowAccessor, keys []benchWindowKey, writeEvery int) {
+	commonRequest := &data.CommonRateLimitRequest{}
+	var seq uint32
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(atomic.AddUint32(&seq, 1))
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			if writeEvery > 0 && i%writeEvery == 0 {
+				windowSet.AddRateLimitWindow(commonRequest, key.rule, key.labels, true)
+			} else {
+				windowSet.GetRateLimitWindow(key.rule, key.labels, true)
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkRateLimitWindowSet_ParallelGet 测试分片后并发查找限流窗口
+func BenchmarkRateLimitWindowSet_ParallelGet(b *testing.B) {
+	windowSet, keys := newBenchWindowSet()
+	runWindowSetParallel(b, windowSet, keys, 0)
+}
+
+// BenchmarkLockedWindowSet_ParallelGet 测试改造前单锁并发查找限流窗口，作为对照
+func BenchmarkLockedWindowSet_ParallelGet(b *testing.B) {
+	windowSet, keys := newBenchWindowSet()
+	runWindowSetParallel(b, newLockedWindowSet(windowSet), keys, 0)
+}
+
+// BenchmarkRateLimitWindowSet_ParallelMixed 测试分片后查找与创建窗口混合的并发访问
+func BenchmarkRateLimitWindowSet_ParallelMixed(b *testing.B) {
+	windowSet, keys := newBenchWindowSet()
+	runWindowSetParallel(b, windowSet, keys, 8)
+}
+
+// BenchmarkLockedWindowSet_ParallelMixed 测试改造前单锁下查找与创建窗口混合的并发访问，作为对照
+func BenchmarkLockedWindowSet_ParallelMixed(b *testing.B) {
+	windowSet, keys := newBenchWindowSet()
+	runWindowSetParallel(b, newLockedWindowSet(windowSet), keys, 8)
+}
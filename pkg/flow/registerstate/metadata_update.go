@@ -0,0 +1,129 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package registerstate
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// metadataUpdater 单个实例的元数据更新合并器，更新间隔内的多次变更合并为一次推送
+type metadataUpdater struct {
+	mutex sync.Mutex
+	// 合并了所有变更后的注册请求
+	instance *model.InstanceRegisterRequest
+	// 上次推送的时间
+	lastPushTime time.Time
+	// 待执行的推送
+	timer *time.Timer
+	// 是否已停止
+	stopped bool
+}
+
+// UpdateMetadata 合并实例的元数据变更，以注册请求的形式推送到服务端
+// 距上次推送不足更新间隔时延迟推送，期间的多次变更合并为一次
+func (c *RegisterStateManager) UpdateMetadata(req *model.InstanceMetadataUpdateRequest, regis registerFunc) error {
+	key := buildRegisterStateKey(req.Namespace, req.Service, req.Host, req.Port)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	updater, hasUpdater := c.updaters[key]
+	state, hasState := c.states[key]
+	var base *model.InstanceRegisterRequest
+	switch {
+	case hasState:
+		base = state.instance
+	case hasUpdater:
+		base = updater.current()
+	case req.Instance != nil:
+		base = req.Instance
+	default:
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"register info of instance {%s, %s, %s:%d} not found, instance should be set",
+			req.Namespace, req.Service, req.Host, req.Port)
+	}
+	updated := req.Apply(base)
+	if hasState {
+		// 心跳失败后的重新注册使用最新的实例信息
+		state.instance = updated
+	}
+	if !hasUpdater {
+		updater = &metadataUpdater{}
+		c.updaters[key] = updater
+	}
+	updater.update(updated, c.metadataUpdateInterval, regis)
+	return nil
+}
+
+func (m *metadataUpdater) current() *model.InstanceRegisterRequest {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.instance
+}
+
+// update 记录最新的实例信息，若没有待执行的推送，则按更新间隔安排一次
+func (m *metadataUpdater) update(instance *model.InstanceRegisterRequest, interval time.Duration, regis registerFunc) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.instance = instance
+	if m.stopped || m.timer != nil {
+		return
+	}
+	delay := interval - time.Since(m.lastPushTime)
+	if delay < 0 {
+		delay = 0
+	}
+	m.timer = time.AfterFunc(delay, func() {
+		m.push(regis)
+	})
+}
+
+// push 推送合并后的实例信息
+func (m *metadataUpdater) push(regis registerFunc) {
+	m.mutex.Lock()
+	if m.stopped {
+		m.mutex.Unlock()
+		return
+	}
+	instance := *m.instance
+	m.timer = nil
+	m.lastPushTime = time.Now()
+	m.mutex.Unlock()
+
+	instance.AutoHeartbeat = false
+	if _, err := regis(&instance, CreateRegisterV2Header()); err != nil {
+		log.GetBaseLogger().Errorf("[Provider][MetadataUpdate] fail to update instance {%s, %s, %s:%d}, err %v",
+			instance.Namespace, instance.Service, instance.Host, instance.Port, err)
+		return
+	}
+	log.GetBaseLogger().Infof("[Provider][MetadataUpdate] instance {%s, %s, %s:%d} updated",
+		instance.Namespace, instance.Service, instance.Host, instance.Port)
+}
+
+// stop 取消待执行的推送
+func (m *metadataUpdater) stop() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.stopped = true
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+}
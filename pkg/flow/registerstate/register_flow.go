@@ -18,13 +18,15 @@
 package registerstate
 
 import (
-	"context"
 	"fmt"
 	"sync"
 	"time"
 
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/scheduler"
 )
 
 type (
@@ -36,36 +38,61 @@ const (
 	_maxHeartbeatErrorCount = 2
 	_headerKeyAsyncRegis    = "async-regis"
 	_headerValueAsyncRegis  = "true"
+	_minHeartbeatBackoff    = time.Second
+	// heartbeatGroupName 心跳任务的调度组名
+	heartbeatGroupName = "heartbeat"
 )
 
-func NewRegisterStateManager(minRegisterInterval time.Duration) *RegisterStateManager {
+func NewRegisterStateManager(minRegisterInterval time.Duration,
+	metadataUpdateInterval time.Duration) *RegisterStateManager {
 	return &RegisterStateManager{
-		minRegisterInterval: minRegisterInterval,
-		states:              map[string]*registerState{},
+		minRegisterInterval:    minRegisterInterval,
+		metadataUpdateInterval: metadataUpdateInterval,
+		states:                 map[string]*registerState{},
+		updaters:               map[string]*metadataUpdater{},
+		heartbeatGroup:         scheduler.GetBlockingScheduler().NewGroup(heartbeatGroupName),
 	}
 }
 
 type RegisterStateManager struct {
-	mu                  sync.RWMutex
-	minRegisterInterval time.Duration
-	states              map[string]*registerState
+	mu                     sync.RWMutex
+	minRegisterInterval    time.Duration
+	metadataUpdateInterval time.Duration
+	states                 map[string]*registerState
+	updaters               map[string]*metadataUpdater
+	// 心跳任务所在的调度组，心跳及重新注册会阻塞等待服务端应答，因此使用独立的阻塞IO调度器
+	heartbeatGroup *scheduler.Group
 }
 
 type registerState struct {
 	instance         *model.InstanceRegisterRequest
 	lastRegisterTime time.Time
-	cancel           context.CancelFunc
+	heartbeat        *scheduler.JobHandle
+	errCnt           int
+}
+
+// cancel 停止心跳任务
+func (s *registerState) cancel() {
+	s.heartbeat.Cancel()
+	log.GetBaseLogger().Infof("[Provider][Heartbeat] instance heartbeat task stopped {%s, %s, %s:%d}",
+		s.instance.Namespace, s.instance.Service, s.instance.Host, s.instance.Port)
 }
 
 func (c *RegisterStateManager) Destroy() {
 	c.mu.Lock()
 	pre := c.states
 	c.states = make(map[string]*registerState)
+	preUpdaters := c.updaters
+	c.updaters = make(map[string]*metadataUpdater)
 	c.mu.Unlock()
 
 	for _, state := range pre {
 		state.cancel()
 	}
+	for _, updater := range preUpdaters {
+		updater.stop()
+	}
+	c.heartbeatGroup.Drain(scheduler.DefaultDrainTimeout)
 }
 
 func (c *RegisterStateManager) PutRegister(instance *model.InstanceRegisterRequest, regis registerFunc, beat heartbeatFunc) (*registerState, bool) {
@@ -77,14 +104,21 @@ func (c *RegisterStateManager) PutRegister(instance *model.InstanceRegisterReque
 		return nil, false
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
 	state := &registerState{
 		instance:         instance,
 		lastRegisterTime: time.Now(),
-		cancel:           cancel,
 	}
 	c.states[key] = state
-	go c.runHeartbeat(ctx, state, regis, beat)
+	log.GetBaseLogger().Infof("[Provider][Heartbeat] instance heartbeat task started {%s, %s, %s:%d}",
+		instance.Namespace, instance.Service, instance.Host, instance.Port)
+	state.heartbeat = c.heartbeatGroup.Schedule(scheduler.Job{
+		Name:     key,
+		Period:   time.Duration(*instance.TTL) * time.Second,
+		Priority: scheduler.PriorityHigh,
+		Run: func() time.Duration {
+			return c.doHeartbeat(state, regis, beat)
+		},
+	})
 	return state, true
 }
 
@@ -97,64 +131,106 @@ func (c *RegisterStateManager) RemoveRegister(instance *model.InstanceDeRegister
 		state.cancel()
 		delete(c.states, key)
 	}
+	if updater, ok := c.updaters[key]; ok {
+		updater.stop()
+		delete(c.updaters, key)
+	}
+}
+
+// GetRegister 获取自动心跳任务记录的注册请求，不存在时返回nil
+func (c *RegisterStateManager) GetRegister(instance *model.InstanceDeRegisterRequest) *model.InstanceRegisterRequest {
+	key := buildRegisterStateKey(instance.Namespace, instance.Service, instance.Host, instance.Port)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	state, ok := c.states[key]
+	if !ok {
+		return nil
+	}
+	return state.instance
+}
+
+// ListRegisters 获取所有自动心跳任务记录的注册请求
+func (c *RegisterStateManager) ListRegisters() []*model.InstanceRegisterRequest {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	instances := make([]*model.InstanceRegisterRequest, 0, len(c.states))
+	for _, state := range c.states {
+		instances = append(instances, state.instance)
+	}
+	return instances
 }
 
 func buildRegisterStateKey(namespace string, service string, host string, port int) string {
 	return fmt.Sprintf("%s##%s##%s##%d", namespace, service, host, port)
 }
 
-func (c *RegisterStateManager) runHeartbeat(ctx context.Context, state *registerState, regis registerFunc, beat heartbeatFunc) {
+// doHeartbeat 执行一次心跳，返回下一次心跳的延迟
+func (c *RegisterStateManager) doHeartbeat(state *registerState, regis registerFunc, beat heartbeatFunc) time.Duration {
+	// 实例信息可能被元数据更新替换，每次心跳时重新获取
+	c.mu.RLock()
 	instance := state.instance
-	log.GetBaseLogger().Infof("[Provider][Heartbeat] instance heartbeat task started {%s, %s, %s:%d}",
-		instance.Namespace, instance.Service, instance.Host, instance.Port)
-	ticker := time.NewTicker(time.Duration(*instance.TTL) * time.Second)
-	defer ticker.Stop()
-
-	errCnt := 0
-	minInterval := c.minRegisterInterval
-
-	for {
-		select {
-		case <-ctx.Done():
-			log.GetBaseLogger().Infof("[Provider][Heartbeat] instance heartbeat task stopped {%s, %s, %s:%d}",
+	c.mu.RUnlock()
+	ttl := time.Duration(*instance.TTL) * time.Second
+	hbReq := &model.InstanceHeartbeatRequest{
+		Namespace:    instance.Namespace,
+		Service:      instance.Service,
+		Host:         instance.Host,
+		Port:         instance.Port,
+		ServiceToken: instance.ServiceToken,
+		InstanceID:   instance.InstanceId,
+	}
+	start := time.Now()
+	err := beat(hbReq)
+	if err == nil {
+		log.GetBaseLogger().Debugf("[Provider][Heartbeat] success {%s, %s, %s:%d} cost:%d ms",
+			instance.Namespace, instance.Service, instance.Host, instance.Port, time.Since(start).Milliseconds())
+		state.errCnt = 0
+		return ttl
+	}
+	log.GetBaseLogger().Errorf("[Provider][Heartbeat] heartbeat failed {%s, %s, %s:%d}, err %v",
+		instance.Namespace, instance.Service, instance.Host, instance.Port, err)
+	state.errCnt++
+	// 服务端已不存在该实例时立即重新注册，其他错误连续失败多次后再重新注册
+	notFound := isInstanceNotFound(err)
+	needRegis := notFound ||
+		(state.errCnt > _maxHeartbeatErrorCount && time.Since(state.lastRegisterTime) > c.minRegisterInterval)
+	if needRegis {
+		// 重新记录注册的时间
+		state.lastRegisterTime = time.Now()
+		if _, err = regis(instance, CreateRegisterV2Header()); err == nil {
+			log.GetBaseLogger().Infof("[Provider][Heartbeat] re-register instatnce success {%s, %s, %s:%d}",
 				instance.Namespace, instance.Service, instance.Host, instance.Port)
-			return
-		case <-ticker.C:
-			hbReq := &model.InstanceHeartbeatRequest{
-				Namespace:    instance.Namespace,
-				Service:      instance.Service,
-				Host:         instance.Host,
-				Port:         instance.Port,
-				ServiceToken: instance.ServiceToken,
-				InstanceID:   instance.InstanceId,
-			}
-			start := time.Now()
-			if err := beat(hbReq); err != nil {
-				log.GetBaseLogger().Errorf("[Provider][Heartbeat] heartbeat failed {%s, %s, %s:%d}",
-					instance.Namespace, instance.Service, instance.Host, instance.Port, err)
-				errCnt++
-
-				needRegis := errCnt > _maxHeartbeatErrorCount && time.Since(state.lastRegisterTime) > minInterval
-				if needRegis {
-					// 重新记录注册的时间
-					state.lastRegisterTime = time.Now()
-					_, err = regis(instance, CreateRegisterV2Header())
-					if err == nil {
-						log.GetBaseLogger().Infof("[Provider][Heartbeat] re-register instatnce success {%s, %s, %s:%d}",
-							instance.Namespace, instance.Service, instance.Host, instance.Port)
-					} else {
-						log.GetBaseLogger().Warnf("[Provider][Heartbeat] re-register instatnce failed {%s, %s, %s:%d}",
-							instance.Namespace, instance.Service, instance.Host, instance.Port, err)
-					}
-				}
-				break
-			}
-			log.GetBaseLogger().Debugf("[Provider][Heartbeat] success {%s, %s, %s:%d} cost:%d ms",
-				instance.Namespace, instance.Service, instance.Host, instance.Port, time.Since(start).Milliseconds())
-			errCnt = 0
-			break
+			state.errCnt = 0
+		} else {
+			log.GetBaseLogger().Warnf("[Provider][Heartbeat] re-register instatnce failed {%s, %s, %s:%d}, err %v",
+				instance.Namespace, instance.Service, instance.Host, instance.Port, err)
 		}
 	}
+	return heartbeatBackoff(state.errCnt, ttl)
+}
+
+// isInstanceNotFound 心跳是否因服务端不存在该实例而失败
+func isInstanceNotFound(err error) bool {
+	sdkErr, ok := err.(model.SDKError)
+	if !ok {
+		return false
+	}
+	return sdkErr.ServerCode() == uint32(apimodel.Code_NotFoundResource)
+}
+
+// heartbeatBackoff 计算心跳失败后的重试间隔，从_minHeartbeatBackoff开始指数退避，最大不超过TTL
+func heartbeatBackoff(errCnt int, ttl time.Duration) time.Duration {
+	if errCnt <= 0 {
+		return ttl
+	}
+	backoff := _minHeartbeatBackoff
+	for i := 1; i < errCnt && backoff < ttl; i++ {
+		backoff *= 2
+	}
+	if backoff > ttl {
+		return ttl
+	}
+	return backoff
 }
 
 func CreateRegisterV2Header() map[string]string {
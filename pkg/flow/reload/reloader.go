@@ -0,0 +1,229 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package reload
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// ApplyFunc 应用新配置的回调，source为配置来源，返回错误时新配置整体不生效
+type ApplyFunc func(source string, cfg config.Configuration) error
+
+// ConfigReloader SDK自身配置热更新的辅助类，定期检查本地配置文件，并监听配置中心中的引导配置文件
+type ConfigReloader struct {
+	// 流程执行引擎
+	engine model.Engine
+	// 应用新配置的回调
+	apply ApplyFunc
+	// 监听的本地配置文件，为空时不检查
+	file string
+	// 检查本地配置文件的周期
+	interval time.Duration
+	// 配置中心中的引导配置文件，为空时不监听
+	fileReq *model.GetConfigFileRequest
+	// 拉取配置中心文件失败后的重试间隔
+	retryInterval time.Duration
+	// 本地配置文件上次检查时的修改时间
+	lastModTime time.Time
+	// 本地配置文件上次检查时的内容摘要
+	lastDigest uint64
+	// 是否启用
+	enable bool
+	// 销毁通知
+	done chan struct{}
+	// 销毁标识
+	destroyOnce sync.Once
+}
+
+// Init 初始化，并使初始配置中的日志级别生效
+func (r *ConfigReloader) Init(engine model.Engine, cfg config.Configuration, apply ApplyFunc) {
+	reloadCfg := cfg.GetGlobal().GetConfigReload()
+	r.engine = engine
+	r.apply = apply
+	r.done = make(chan struct{})
+	r.retryInterval = cfg.GetGlobal().GetAPI().GetRetryInterval()
+	if err := ApplyLogLevels(reloadCfg.GetLogLevels()); err != nil {
+		log.GetBaseLogger().Errorf("[ConfigReload] fail to apply log levels, err %v", err)
+	}
+	r.enable = reloadCfg.IsEnable()
+	if !r.enable {
+		return
+	}
+	r.file = reloadCfg.GetFile()
+	r.interval = reloadCfg.GetInterval()
+	if len(reloadCfg.GetFileName()) > 0 {
+		r.fileReq = &model.GetConfigFileRequest{
+			Namespace: reloadCfg.GetNamespace(),
+			FileGroup: reloadCfg.GetFileGroup(),
+			FileName:  reloadCfg.GetFileName(),
+			Subscribe: true,
+		}
+	}
+}
+
+// Start 启动本地配置文件的检查，以及配置中心引导配置文件的监听
+func (r *ConfigReloader) Start() {
+	if !r.enable {
+		return
+	}
+	if len(r.file) > 0 {
+		// 以启动时的文件内容为基准，仅在后续变更时重新加载
+		if info, err := os.Stat(r.file); err == nil {
+			r.lastModTime = info.ModTime()
+		}
+		if content, err := ioutil.ReadFile(r.file); err == nil {
+			r.lastDigest = digest(content)
+		}
+		go r.watchFile()
+	}
+	if r.fileReq != nil {
+		go r.watchConfigCenter()
+	}
+}
+
+// Destroy 销毁
+func (r *ConfigReloader) Destroy() {
+	r.destroyOnce.Do(func() {
+		close(r.done)
+	})
+}
+
+// watchFile 定期检查本地配置文件是否发生变更
+func (r *ConfigReloader) watchFile() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			r.checkFile()
+		}
+	}
+}
+
+func (r *ConfigReloader) checkFile() {
+	info, err := os.Stat(r.file)
+	if err != nil {
+		log.GetBaseLogger().Warnf("[ConfigReload] fail to stat config file %s, err %v", r.file, err)
+		return
+	}
+	if info.ModTime().Equal(r.lastModTime) {
+		return
+	}
+	r.lastModTime = info.ModTime()
+	content, err := ioutil.ReadFile(r.file)
+	if err != nil {
+		log.GetBaseLogger().Warnf("[ConfigReload] fail to read config file %s, err %v", r.file, err)
+		return
+	}
+	contentDigest := digest(content)
+	if contentDigest == r.lastDigest {
+		return
+	}
+	// 无论新配置是否生效都记录摘要，避免同一份非法配置被反复加载
+	r.lastDigest = contentDigest
+	r.reload(r.file, content)
+}
+
+// watchConfigCenter 拉取配置中心中的引导配置文件直至成功，并监听后续的变更
+func (r *ConfigReloader) watchConfigCenter() {
+	source := fmt.Sprintf("%s/%s/%s", r.fileReq.Namespace, r.fileReq.FileGroup, r.fileReq.FileName)
+	ticker := time.NewTicker(r.retryInterval)
+	defer ticker.Stop()
+	for {
+		configFile, err := r.engine.SyncGetConfigFile(r.fileReq)
+		if err == nil {
+			if configFile.HasContent() {
+				r.reload(source, []byte(configFile.GetContent()))
+			}
+			configFile.AddChangeListener(func(event model.ConfigFileChangeEvent) {
+				select {
+				case <-r.done:
+					return
+				default:
+				}
+				r.reload(source, []byte(event.NewValue))
+			})
+			return
+		}
+		log.GetBaseLogger().Errorf("[ConfigReload] fail to get config file %s, err %v", source, err)
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reload 解析并校验新的配置内容，校验通过后交由引擎应用
+func (r *ConfigReloader) reload(source string, content []byte) {
+	if len(bytes.TrimSpace(content)) == 0 {
+		log.GetBaseLogger().Warnf("[ConfigReload] config from %s is empty, ignored", source)
+		return
+	}
+	cfg, err := config.LoadConfiguration(content)
+	if err != nil {
+		log.GetBaseLogger().Errorf("[ConfigReload] invalid config from %s, ignored, err %v", source, err)
+		return
+	}
+	if err = r.apply(source, cfg); err != nil {
+		log.GetBaseLogger().Errorf("[ConfigReload] fail to apply config from %s, err %v", source, err)
+	}
+}
+
+// ApplyLogLevels 使各日志模块的打印级别生效，module为*时对所有模块生效
+func ApplyLogLevels(levels map[string]string) error {
+	if len(levels) == 0 {
+		return nil
+	}
+	modules := make([]string, 0, len(levels))
+	for module := range levels {
+		modules = append(modules, module)
+	}
+	// 保证 * 最先生效，以便具体模块的级别覆盖之
+	sort.Slice(modules, func(i, j int) bool {
+		if modules[i] == "*" || modules[j] == "*" {
+			return modules[i] == "*" && modules[j] != "*"
+		}
+		return modules[i] < modules[j]
+	})
+	lines := make([]string, 0, len(modules))
+	for _, module := range modules {
+		lines = append(lines, fmt.Sprintf("%s=%s", module, levels[module]))
+	}
+	return log.ApplyModuleLogLevels(strings.Join(lines, "\n"))
+}
+
+func digest(content []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(content)
+	return h.Sum64()
+}
@@ -0,0 +1,129 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package reload
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+const testConfigTemplate = `global:
+  serverConnector:
+    addresses:
+      - 127.0.0.1:8091
+  api:
+    timeout: %s
+`
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+// TestConfigReloaderCheckFile 测试本地配置文件变更的检查，仅内容变更且合法时应用新配置
+func TestConfigReloaderCheckFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "polaris-reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	file := filepath.Join(dir, "polaris.yaml")
+	initial := []byte(fmt.Sprintf(testConfigTemplate, "1s"))
+	assert.Nil(t, ioutil.WriteFile(file, initial, 0644))
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	assert.Nil(t, os.Chtimes(file, modTime, modTime))
+
+	var applied []config.Configuration
+	r := &ConfigReloader{
+		apply: func(source string, cfg config.Configuration) error {
+			assert.Equal(t, file, source)
+			applied = append(applied, cfg)
+			return nil
+		},
+		file:        file,
+		lastModTime: modTime,
+		lastDigest:  digest(initial),
+	}
+
+	steps := []struct {
+		name    string
+		content string
+		// touch 是否更新文件的修改时间
+		touch       bool
+		wantApplied int
+	}{
+		{
+			name:        "修改时间变更但内容不变时不应用",
+			content:     string(initial),
+			touch:       true,
+			wantApplied: 0,
+		},
+		{
+			name:        "内容变更时应用新配置",
+			content:     fmt.Sprintf(testConfigTemplate, "3s"),
+			touch:       true,
+			wantApplied: 1,
+		},
+		{
+			name:        "修改时间不变时不检查内容",
+			content:     fmt.Sprintf(testConfigTemplate, "5s"),
+			wantApplied: 1,
+		},
+		{
+			name:        "非法配置不应用",
+			content:     "global: [",
+			touch:       true,
+			wantApplied: 1,
+		},
+		{
+			name:        "同一份非法配置不重复加载",
+			content:     "global: [",
+			touch:       true,
+			wantApplied: 1,
+		},
+		{
+			name:        "空配置不应用",
+			content:     "  \n",
+			touch:       true,
+			wantApplied: 1,
+		},
+	}
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			assert.Nil(t, ioutil.WriteFile(file, []byte(step.content), 0644))
+			if step.touch {
+				modTime = modTime.Add(time.Second)
+			}
+			assert.Nil(t, os.Chtimes(file, modTime, modTime))
+			r.checkFile()
+			assert.Len(t, applied, step.wantApplied)
+		})
+	}
+	assert.Equal(t, 3*time.Second, applied[0].GetGlobal().GetAPI().GetTimeout())
+}
@@ -0,0 +1,85 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// resolveCall 一次进行中的远程资源等待
+type resolveCall struct {
+	done          chan struct{}
+	exceedTimeout bool
+	errs          map[ContextKey]model.SDKError
+}
+
+// resolveGroup 合并并发的首次资源加载，相同服务及资源类型同一时刻只有一个调用等待远程结果，其余调用共享其结果
+type resolveGroup struct {
+	mutex sync.Mutex
+	calls map[string]*resolveCall
+}
+
+func newResolveGroup() *resolveGroup {
+	return &resolveGroup{calls: make(map[string]*resolveCall)}
+}
+
+// wait 等待复合上下文中的资源加载完成，返回是否超时及远程错误。
+// 已有相同资源的等待时直接共享其结果，但最长不超过本次调用的超时时间，ctx结束时立即返回超时；
+// 共享的等待因发起方的超时较短而超时时，本次调用在剩余时间内使用自身的上下文继续等待
+func (g *resolveGroup) wait(ctx context.Context, combineContext *CombineNotifyContext,
+	timeout time.Duration) (bool, map[ContextKey]model.SDKError) {
+	key := combineContext.resolveKey()
+	g.mutex.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mutex.Unlock()
+		deadline := time.Now().Add(timeout)
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-call.done:
+			if !call.exceedTimeout || len(call.errs) > 0 {
+				return call.exceedTimeout, call.errs
+			}
+			remain := time.Until(deadline)
+			if remain <= 0 || ctx.Err() != nil {
+				return true, nil
+			}
+			exceedTimeout := combineContext.Wait(ctx, remain)
+			return exceedTimeout, combineContext.Errs()
+		case <-timer.C:
+			return true, nil
+		case <-ctx.Done():
+			return true, nil
+		}
+	}
+	call := &resolveCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mutex.Unlock()
+
+	call.exceedTimeout = combineContext.Wait(ctx, timeout)
+	call.errs = combineContext.Errs()
+	g.mutex.Lock()
+	delete(g.calls, key)
+	g.mutex.Unlock()
+	close(call.done)
+	return call.exceedTimeout, call.errs
+}
@@ -0,0 +1,114 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// newSharedCombineContext 基于同一个通知器创建复合回调上下文，模拟并发调用等待同一资源
+func newSharedCombineContext(notifier *common.Notifier) *CombineNotifyContext {
+	svcKey := &model.ServiceKey{Namespace: "Test", Service: "svc"}
+	single := NewSingleNotifyContext(&ContextKey{ServiceKey: svcKey, Operation: "instances"}, notifier)
+	return NewCombineNotifyContext(svcKey, []*SingleNotifyContext{single})
+}
+
+// waitLeaderRegistered 等待发起方登记到合并等待中
+func waitLeaderRegistered(t *testing.T, group *resolveGroup, key string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		group.mutex.Lock()
+		_, ok := group.calls[key]
+		group.mutex.Unlock()
+		if ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("leader not registered")
+}
+
+// TestResolveGroupMixedTimeout 测试发起方与跟随者超时时间不同时，各自按自身的超时时间返回
+func TestResolveGroupMixedTimeout(t *testing.T) {
+	tests := []struct {
+		name            string
+		leaderTimeout   time.Duration
+		followerTimeout time.Duration
+		notifyAfter     time.Duration
+		wantLeader      bool
+		wantFollower    bool
+	}{
+		{
+			name:            "发起方超时后跟随者在剩余时间内继续等待",
+			leaderTimeout:   50 * time.Millisecond,
+			followerTimeout: 5 * time.Second,
+			notifyAfter:     200 * time.Millisecond,
+			wantLeader:      true,
+			wantFollower:    false,
+		},
+		{
+			name:            "跟随者超时短于发起方",
+			leaderTimeout:   5 * time.Second,
+			followerTimeout: 50 * time.Millisecond,
+			notifyAfter:     200 * time.Millisecond,
+			wantLeader:      false,
+			wantFollower:    true,
+		},
+		{
+			name:            "双方均超时",
+			leaderTimeout:   50 * time.Millisecond,
+			followerTimeout: 100 * time.Millisecond,
+			notifyAfter:     time.Second,
+			wantLeader:      true,
+			wantFollower:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := newResolveGroup()
+			notifier := common.NewNotifier()
+			leaderCtx := newSharedCombineContext(notifier)
+			followerCtx := newSharedCombineContext(notifier)
+			wg := &sync.WaitGroup{}
+			var leaderExceed, followerExceed bool
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				leaderExceed, _ = group.wait(context.Background(), leaderCtx, tt.leaderTimeout)
+			}()
+			waitLeaderRegistered(t, group, leaderCtx.resolveKey())
+			go func() {
+				defer wg.Done()
+				followerExceed, _ = group.wait(context.Background(), followerCtx, tt.followerTimeout)
+			}()
+			timer := time.AfterFunc(tt.notifyAfter, func() { notifier.Notify(nil) })
+			defer timer.Stop()
+			wg.Wait()
+			assert.Equal(t, tt.wantLeader, leaderExceed)
+			assert.Equal(t, tt.wantFollower, followerExceed)
+		})
+	}
+}
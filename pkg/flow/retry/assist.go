@@ -0,0 +1,144 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package retry
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// budgetWindowSeconds 重试预算的统计窗口，按秒分桶
+const budgetWindowSeconds = 10
+
+// RetryAssistant 重试协助辅助类，负责重试预算统计及退避时间计算
+type RetryAssistant struct {
+	cfg     config.RetryConfig
+	budgets sync.Map
+}
+
+// Init 初始化
+func (r *RetryAssistant) Init(cfg config.Configuration) {
+	r.cfg = cfg.GetConsumer().GetRetry()
+}
+
+// GetConfig 获取重试配置
+func (r *RetryAssistant) GetConfig() config.RetryConfig {
+	return r.cfg
+}
+
+// RecordRequest 记录一次首次调用，用于计算重试预算
+func (r *RetryAssistant) RecordRequest(svcKey model.ServiceKey) {
+	r.getBudget(svcKey).recordRequest(time.Now())
+}
+
+// AcquireRetry 申请一次重试机会，超出重试预算时返回false
+func (r *RetryAssistant) AcquireRetry(svcKey model.ServiceKey) bool {
+	return r.getBudget(svcKey).acquire(time.Now(), r.cfg.GetBudgetPercent(), r.cfg.GetMinRetriesPerSecond())
+}
+
+// IsRetryable 判断调用失败后是否可以重试
+func (r *RetryAssistant) IsRetryable(retCode int32) bool {
+	codes := r.cfg.GetRetryableCodes()
+	if len(codes) == 0 {
+		return true
+	}
+	for _, code := range codes {
+		if code == retCode {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff 计算第retryTimes次重试前的等待时间，指数退避并叠加随机抖动
+func (r *RetryAssistant) Backoff(retryTimes int) time.Duration {
+	backoff := r.cfg.GetBaseBackoff()
+	maxBackoff := r.cfg.GetMaxBackoff()
+	for i := 1; i < retryTimes && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	half := int64(backoff / 2)
+	if half <= 0 {
+		return backoff
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+func (r *RetryAssistant) getBudget(svcKey model.ServiceKey) *budget {
+	value, ok := r.budgets.Load(svcKey)
+	if !ok {
+		value, _ = r.budgets.LoadOrStore(svcKey, &budget{})
+	}
+	return value.(*budget)
+}
+
+// budgetBucket 单秒内的请求数及重试数
+type budgetBucket struct {
+	second   int64
+	requests int64
+	retries  int64
+}
+
+// budget 单个服务的重试预算
+type budget struct {
+	mutex   sync.Mutex
+	buckets [budgetWindowSeconds]budgetBucket
+}
+
+// bucket 获取当前秒对应的桶，过期的桶会被重置，调用方需持有锁
+func (b *budget) bucket(second int64) *budgetBucket {
+	bucket := &b.buckets[second%budgetWindowSeconds]
+	if bucket.second != second {
+		bucket.second = second
+		bucket.requests = 0
+		bucket.retries = 0
+	}
+	return bucket
+}
+
+func (b *budget) recordRequest(now time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.bucket(now.Unix()).requests++
+}
+
+func (b *budget) acquire(now time.Time, percent float64, minRetriesPerSecond int) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	second := now.Unix()
+	var requests, retries int64
+	for i := range b.buckets {
+		bucket := &b.buckets[i]
+		if second-bucket.second < budgetWindowSeconds {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+	allowed := float64(requests)*percent/100 + float64(minRetriesPerSecond*budgetWindowSeconds)
+	if float64(retries) >= allowed {
+		return false
+	}
+	b.bucket(second).retries++
+	return true
+}
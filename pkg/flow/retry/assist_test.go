@@ -0,0 +1,103 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestBudgetAcquire 测试重试预算按窗口内请求数的百分比限制重试
+func TestBudgetAcquire(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name                string
+		requests            int
+		percent             float64
+		minRetriesPerSecond int
+		allowed             int
+	}{
+		{name: "按请求数百分比限制", requests: 100, percent: 20, allowed: 20},
+		{name: "没有请求时只允许最小重试数", requests: 0, percent: 20, minRetriesPerSecond: 1, allowed: budgetWindowSeconds},
+		{name: "百分比与最小重试数叠加", requests: 50, percent: 10, minRetriesPerSecond: 1, allowed: 5 + budgetWindowSeconds},
+		{name: "预算为0时不允许重试", requests: 100, percent: 0, allowed: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &budget{}
+			for i := 0; i < tt.requests; i++ {
+				b.recordRequest(now)
+			}
+			granted := 0
+			for b.acquire(now, tt.percent, tt.minRetriesPerSecond) {
+				granted++
+				if granted > tt.allowed {
+					break
+				}
+			}
+			assert.Equal(t, tt.allowed, granted)
+		})
+	}
+}
+
+// TestBudgetWindowExpire 测试预算耗尽后，统计窗口滑过时重新获得重试机会
+func TestBudgetWindowExpire(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	b := &budget{}
+	for i := 0; i < 10; i++ {
+		b.recordRequest(now)
+	}
+	assert.True(t, b.acquire(now, 10, 0))
+	assert.False(t, b.acquire(now, 10, 0))
+	// 窗口内仍计入之前的请求及重试
+	assert.False(t, b.acquire(now.Add((budgetWindowSeconds-1)*time.Second), 10, 0))
+
+	later := now.Add(budgetWindowSeconds * time.Second)
+	assert.False(t, b.acquire(later, 10, 0))
+	for i := 0; i < 10; i++ {
+		b.recordRequest(later)
+	}
+	assert.True(t, b.acquire(later, 10, 0))
+}
+
+// TestRetryAssistant 测试按服务隔离的预算及可重试返回码
+func TestRetryAssistant(t *testing.T) {
+	cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	retryCfg := cfg.GetConsumer().GetRetry()
+	retryCfg.SetBudgetPercent(50)
+	retryCfg.SetMinRetriesPerSecond(0)
+	retryCfg.SetRetryableCodes([]int32{503})
+	assistant := &RetryAssistant{}
+	assistant.Init(cfg)
+
+	svc1 := model.ServiceKey{Namespace: "Test", Service: "svc1"}
+	svc2 := model.ServiceKey{Namespace: "Test", Service: "svc2"}
+	assistant.RecordRequest(svc1)
+	assistant.RecordRequest(svc1)
+	assert.True(t, assistant.AcquireRetry(svc1))
+	assert.False(t, assistant.AcquireRetry(svc1))
+	// svc2没有请求，预算不受svc1影响
+	assert.False(t, assistant.AcquireRetry(svc2))
+
+	assert.True(t, assistant.IsRetryable(503))
+	assert.False(t, assistant.IsRetryable(500))
+}
@@ -0,0 +1,207 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package flow
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/retry"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// SyncInvokeWithRetry 选择实例并发起调用，失败时在重试预算内退避重试，重试时排除已经调用过的实例，
+// 所有实例都调用过后不再重试，返回最后一次调用的错误
+func (e *Engine) SyncInvokeWithRetry(req *model.InvokeWithRetryRequest) (*model.InvokeWithRetryResponse, error) {
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	oneReq := &req.GetOneInstanceRequest
+	instancesResp, err := e.SyncGetInstances(toGetInstancesRequest(oneReq))
+	if err != nil {
+		return nil, err
+	}
+	perTryTimeout := e.retryAssistant.GetConfig().GetPerTryTimeout()
+	loop := &retryLoop{
+		assistant: e.retryAssistant,
+		svcKey:    model.ServiceKey{Namespace: oneReq.Namespace, Service: oneReq.Service},
+		instances: instancesResp.GetInstances(),
+		selectInstance: func(candidates []model.Instance) (model.Instance, error) {
+			return e.selectInstance(oneReq, instancesResp, candidates)
+		},
+		invoke: func(ctx context.Context, instance model.Instance) (int32, error) {
+			return e.invokeOnce(ctx, req, instance, perTryTimeout)
+		},
+	}
+	return loop.run(ctx)
+}
+
+// retryLoop 单次带重试调用的执行过程
+type retryLoop struct {
+	assistant *retry.RetryAssistant
+	svcKey    model.ServiceKey
+	instances []model.Instance
+	// selectInstance 在候选实例中做负载均衡
+	selectInstance func(candidates []model.Instance) (model.Instance, error)
+	// invoke 对实例发起一次调用
+	invoke func(ctx context.Context, instance model.Instance) (int32, error)
+}
+
+func (l *retryLoop) run(ctx context.Context) (*model.InvokeWithRetryResponse, error) {
+	retryCfg := l.assistant.GetConfig()
+	l.assistant.RecordRequest(l.svcKey)
+
+	resp := &model.InvokeWithRetryResponse{}
+	tried := make(map[string]struct{})
+	var lastErr error
+	for resp.Attempts < retryCfg.GetMaxAttempts() {
+		candidates := untriedInstances(l.instances, tried)
+		if resp.Attempts > 0 {
+			// 在已失败的实例上重试意义不大，且会占用其他请求的重试预算
+			if len(candidates) == 0 {
+				log.GetBaseLogger().Debugf("[Retry] all instances of %s tried, attempts %d", l.svcKey, resp.Attempts)
+				break
+			}
+			if !l.assistant.AcquireRetry(l.svcKey) {
+				log.GetBaseLogger().Warnf("[Retry] retry budget of %s exhausted, attempts %d", l.svcKey, resp.Attempts)
+				break
+			}
+			if err := waitBackoff(ctx, l.assistant.Backoff(resp.Attempts)); err != nil {
+				break
+			}
+		}
+		instance, err := l.selectInstance(candidates)
+		if err != nil {
+			if lastErr == nil {
+				return nil, err
+			}
+			break
+		}
+		tried[instance.GetId()] = struct{}{}
+		resp.Attempts++
+		resp.Instance = instance
+		resp.RetCode, lastErr = l.invoke(ctx, instance)
+		if lastErr == nil {
+			return resp, nil
+		}
+		if !l.assistant.IsRetryable(resp.RetCode) || ctx.Err() != nil {
+			break
+		}
+	}
+	return resp, lastErr
+}
+
+// untriedInstances 过滤掉已经调用过的实例
+func untriedInstances(instances []model.Instance, tried map[string]struct{}) []model.Instance {
+	if len(tried) == 0 {
+		return instances
+	}
+	candidates := make([]model.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if _, ok := tried[instance.GetId()]; !ok {
+			candidates = append(candidates, instance)
+		}
+	}
+	return candidates
+}
+
+// toGetInstancesRequest 按单实例请求的参数构造路由后实例列表的查询请求
+func toGetInstancesRequest(req *model.GetOneInstanceRequest) *model.GetInstancesRequest {
+	return &model.GetInstancesRequest{
+		FlowID:                       req.FlowID,
+		Service:                      req.Service,
+		Namespace:                    req.Namespace,
+		Metadata:                     req.Metadata,
+		SourceService:                req.SourceService,
+		Arguments:                    req.Arguments,
+		IncludeCircuitBreakInstances: req.IncludeCircuitBreakInstances,
+		Timeout:                      req.Timeout,
+		RetryCount:                   req.RetryCount,
+		Canary:                       req.Canary,
+	}
+}
+
+// selectInstance 在候选实例中做负载均衡
+func (e *Engine) selectInstance(req *model.GetOneInstanceRequest, instancesResp *model.InstancesResponse,
+	candidates []model.Instance) (model.Instance, error) {
+	svcInfo := model.ServiceInfo{
+		Namespace: instancesResp.GetNamespace(),
+		Service:   instancesResp.GetService(),
+		Metadata:  instancesResp.GetMetadata(),
+	}
+	lbResp, err := e.ProcessLoadBalance(&model.ProcessLoadBalanceRequest{
+		DstInstances: model.NewDefaultServiceInstances(svcInfo, candidates),
+		LbPolicy:     req.LbPolicy,
+		HashKey:      req.HashKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	instance := lbResp.GetInstance()
+	if instance == nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"no instance available, namespace %s, service %s", svcInfo.Namespace, svcInfo.Service)
+	}
+	return instance, nil
+}
+
+// invokeOnce 在单次超时内发起一次调用并上报调用结果
+func (e *Engine) invokeOnce(ctx context.Context, req *model.InvokeWithRetryRequest, instance model.Instance,
+	perTryTimeout time.Duration) (int32, error) {
+	tryCtx, cancel := context.WithTimeout(ctx, perTryTimeout)
+	defer cancel()
+	start := time.Now()
+	retCode, err := req.Call(tryCtx, instance)
+	delay := time.Since(start)
+
+	result := &model.ServiceCallResult{
+		CalledInstance: instance,
+		Method:         req.Method,
+		RetStatus:      model.RetSuccess,
+		SourceService:  req.SourceService,
+	}
+	if err != nil {
+		result.SetRetStatus(model.RetFail)
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(tryCtx.Err(), context.DeadlineExceeded) {
+			result.SetRetStatus(model.RetTimeout)
+		}
+	}
+	result.SetRetCode(retCode).SetDelay(delay)
+	if reportErr := e.SyncUpdateServiceCallResult(result); reportErr != nil {
+		log.GetBaseLogger().Warnf("[Retry] report call result of instance %s fail, err %v",
+			instance.GetId(), reportErr)
+	}
+	return retCode, err
+}
+
+// waitBackoff 等待退避时间，上下文取消时提前返回
+func waitBackoff(ctx context.Context, backoff time.Duration) error {
+	if backoff <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,195 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package flow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/retry"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// retryTestInstance 只实现实例ID的测试实例
+type retryTestInstance struct {
+	model.Instance
+	id string
+}
+
+func (i *retryTestInstance) GetId() string {
+	return i.id
+}
+
+func newTestRetryLoop(t *testing.T, instanceIDs []string, setup func(retryCfg config.RetryConfig),
+	call func(instance model.Instance) (int32, error)) (*retryLoop, *[]string) {
+	cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	retryCfg := cfg.GetConsumer().GetRetry()
+	retryCfg.SetBaseBackoff(time.Millisecond)
+	retryCfg.SetMaxBackoff(time.Millisecond)
+	if setup != nil {
+		setup(retryCfg)
+	}
+	assistant := &retry.RetryAssistant{}
+	assistant.Init(cfg)
+
+	instances := make([]model.Instance, 0, len(instanceIDs))
+	for _, id := range instanceIDs {
+		instances = append(instances, &retryTestInstance{id: id})
+	}
+	called := &[]string{}
+	return &retryLoop{
+		assistant: assistant,
+		svcKey:    model.ServiceKey{Namespace: "Test", Service: t.Name()},
+		instances: instances,
+		// 按顺序选择第一个候选实例
+		selectInstance: func(candidates []model.Instance) (model.Instance, error) {
+			if len(candidates) == 0 {
+				return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil, "no instance available")
+			}
+			return candidates[0], nil
+		},
+		invoke: func(_ context.Context, instance model.Instance) (int32, error) {
+			*called = append(*called, instance.GetId())
+			return call(instance)
+		},
+	}, called
+}
+
+func TestRetryLoop(t *testing.T) {
+	errCall := errors.New("call fail")
+	failAll := func(model.Instance) (int32, error) {
+		return 503, errCall
+	}
+	tests := []struct {
+		name         string
+		instances    []string
+		setup        func(retryCfg config.RetryConfig)
+		call         func(instance model.Instance) (int32, error)
+		wantCalled   []string
+		wantRetCode  int32
+		wantErr      error
+		wantNotFound bool
+	}{
+		{
+			name:      "失败后排除已调用实例重试直至成功",
+			instances: []string{"a", "b", "c"},
+			call: func(instance model.Instance) (int32, error) {
+				if instance.GetId() == "c" {
+					return 0, nil
+				}
+				return 503, errCall
+			},
+			setup: func(retryCfg config.RetryConfig) {
+				retryCfg.SetMaxAttempts(5)
+			},
+			wantCalled: []string{"a", "b", "c"},
+		},
+		{
+			name:      "所有实例都调用过后返回最后一次的错误，不再重复调用",
+			instances: []string{"a", "b"},
+			call:      failAll,
+			setup: func(retryCfg config.RetryConfig) {
+				retryCfg.SetMaxAttempts(5)
+			},
+			wantCalled:  []string{"a", "b"},
+			wantRetCode: 503,
+			wantErr:     errCall,
+		},
+		{
+			name:       "达到最大调用次数后停止",
+			instances:  []string{"a", "b", "c", "d"},
+			call:       failAll,
+			wantCalled: []string{"a", "b", "c"},
+			setup: func(retryCfg config.RetryConfig) {
+				retryCfg.SetMaxAttempts(3)
+			},
+			wantRetCode: 503,
+			wantErr:     errCall,
+		},
+		{
+			name:      "重试预算耗尽时返回首次调用的错误",
+			instances: []string{"a", "b", "c"},
+			call:      failAll,
+			setup: func(retryCfg config.RetryConfig) {
+				retryCfg.SetMaxAttempts(5)
+				retryCfg.SetBudgetPercent(0)
+				retryCfg.SetMinRetriesPerSecond(0)
+			},
+			wantCalled:  []string{"a"},
+			wantRetCode: 503,
+			wantErr:     errCall,
+		},
+		{
+			name:      "不可重试的返回码直接返回",
+			instances: []string{"a", "b"},
+			call: func(model.Instance) (int32, error) {
+				return 400, errCall
+			},
+			setup: func(retryCfg config.RetryConfig) {
+				retryCfg.SetRetryableCodes([]int32{503})
+			},
+			wantCalled:  []string{"a"},
+			wantRetCode: 400,
+			wantErr:     errCall,
+		},
+		{
+			name:         "没有实例时返回选择实例的错误",
+			call:         failAll,
+			wantNotFound: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loop, called := newTestRetryLoop(t, tt.instances, tt.setup, tt.call)
+			resp, err := loop.run(context.Background())
+			if tt.wantNotFound {
+				assert.Nil(t, resp)
+				sdkErr, ok := err.(model.SDKError)
+				assert.True(t, ok)
+				assert.Equal(t, model.ErrCodeAPIInstanceNotFound, sdkErr.ErrorCode())
+				assert.Empty(t, *called)
+				return
+			}
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantCalled, *called)
+			assert.Equal(t, len(tt.wantCalled), resp.Attempts)
+			assert.Equal(t, tt.wantRetCode, resp.RetCode)
+			assert.Equal(t, tt.wantCalled[len(tt.wantCalled)-1], resp.Instance.GetId())
+		})
+	}
+}
+
+// TestRetryLoopContextCanceled 测试上下文取消后不再发起重试
+func TestRetryLoopContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	loop, called := newTestRetryLoop(t, []string{"a", "b"}, func(retryCfg config.RetryConfig) {
+		retryCfg.SetBaseBackoff(time.Hour)
+		retryCfg.SetMaxBackoff(time.Hour)
+	}, func(model.Instance) (int32, error) {
+		cancel()
+		return 503, context.Canceled
+	})
+	resp, err := loop.run(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, resp.Attempts)
+	assert.Equal(t, []string{"a"}, *called)
+}
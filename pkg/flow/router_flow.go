@@ -18,6 +18,9 @@
 package flow
 
 import (
+	"sync"
+	"sync/atomic"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -38,6 +41,64 @@ func (e *Engine) ProcessRouters(req *model.ProcessRoutersRequest) (*model.Instan
 	return resp, err
 }
 
+// maxCachedRouterChain 请求指定的路由链不超过该长度时缓存解析结果
+const maxCachedRouterChain = 4
+
+// routerChainKey 路由链缓存的键，使用数组作为map键查找时无需分配内存
+type routerChainKey [maxCachedRouterChain]string
+
+// routerChainCache 请求指定的路由链到路由插件的预解析结果，写时复制，读路径无锁
+// 高频查询通常复用同一组路由链，解析一次后不再每次调用查找插件及追加兜底路由
+type routerChainCache struct {
+	mutex  sync.Mutex
+	chains atomic.Value
+}
+
+func (c *routerChainCache) load(key routerChainKey) ([]servicerouter.ServiceRouter, bool) {
+	chains, _ := c.chains.Load().(map[routerChainKey][]servicerouter.ServiceRouter)
+	svcRouters, ok := chains[key]
+	return svcRouters, ok
+}
+
+func (c *routerChainCache) store(key routerChainKey, svcRouters []servicerouter.ServiceRouter) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	current, _ := c.chains.Load().(map[routerChainKey][]servicerouter.ServiceRouter)
+	chains := make(map[routerChainKey][]servicerouter.ServiceRouter, len(current)+1)
+	for k, v := range current {
+		chains[k] = v
+	}
+	chains[key] = svcRouters
+	c.chains.Store(chains)
+}
+
+// applyRequestRouters 请求中指定了路由链时，使用该路由链替代全局配置的路由链
+// 解析后的路由链在请求间共享，调用方只读不修改
+func (e *Engine) applyRequestRouters(routers []string, commonRequest *data.CommonInstancesRequest) error {
+	if len(routers) == 0 {
+		return nil
+	}
+	var key routerChainKey
+	cacheable := len(routers) <= maxCachedRouterChain
+	if cacheable {
+		copy(key[:], routers)
+		if svcRouters, ok := e.routerChains.load(key); ok {
+			commonRequest.Routers = svcRouters
+			return nil
+		}
+	}
+	// 限制容量，避免parseRouters追加兜底路由时改写用户在多个请求间共享的切片
+	svcRouters, err := e.parseRouters(routers[:len(routers):len(routers)])
+	if err != nil {
+		return err
+	}
+	if cacheable {
+		e.routerChains.store(key, svcRouters)
+	}
+	commonRequest.Routers = svcRouters
+	return nil
+}
+
 func (e *Engine) parseRouters(routers []string) ([]servicerouter.ServiceRouter, error) {
 	var svcRouters []servicerouter.ServiceRouter
 	if len(routers) == 0 {
@@ -0,0 +1,53 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// TestRouterChainCache 测试请求指定路由链的预解析缓存
+func TestRouterChainCache(t *testing.T) {
+	cache := &routerChainCache{}
+	key := func(routers ...string) routerChainKey {
+		var k routerChainKey
+		copy(k[:], routers)
+		return k
+	}
+	_, ok := cache.load(key("ruleBasedRouter"))
+	assert.False(t, ok)
+
+	single := make([]servicerouter.ServiceRouter, 1)
+	double := make([]servicerouter.ServiceRouter, 2)
+	cache.store(key("ruleBasedRouter"), single)
+	cache.store(key("ruleBasedRouter", "filterOnlyRouter"), double)
+
+	loaded, ok := cache.load(key("ruleBasedRouter"))
+	assert.True(t, ok)
+	assert.Len(t, loaded, 1)
+	loaded, ok = cache.load(key("ruleBasedRouter", "filterOnlyRouter"))
+	assert.True(t, ok)
+	assert.Len(t, loaded, 2)
+	// 顺序不同视为不同的路由链
+	_, ok = cache.load(key("filterOnlyRouter", "ruleBasedRouter"))
+	assert.False(t, ok)
+}
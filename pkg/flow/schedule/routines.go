@@ -28,6 +28,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/clock"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/scheduler"
 )
 
 // TaskRoutine 任务调度协程接口
@@ -36,12 +37,20 @@ type TaskRoutine interface {
 	Schedule() (chan<- *model.PriorityTask, model.TaskValues)
 	// 结束协程
 	Destroy()
+	// GetName 获取任务名
+	GetName() string
+	// GetQueueDepth 获取高优先级队列中等待处理的任务数
+	GetQueueDepth() int
 }
 
-// NewTaskRoutine 创建任务调度协程
-func NewTaskRoutine(periodicTask *model.PeriodicTask) TaskRoutine {
+// periodTaskJitter 周期任务的调度抖动比例
+const periodTaskJitter = 0.1
+
+// NewTaskRoutine 创建任务调度协程，不处理高优先级任务的周期任务由 group 所在的中心调度器执行
+func NewTaskRoutine(periodicTask *model.PeriodicTask, group *scheduler.Group) TaskRoutine {
 	return &taskRoutine{
 		periodicTask:        periodicTask,
+		group:               group,
 		mutableTaskValues:   make(map[interface{}]*TaskItem),
 		immutableTaskValues: &atomic.Value{},
 		mutex:               &sync.Mutex{}}
@@ -52,6 +61,9 @@ type taskRoutine struct {
 	periodicTask *model.PeriodicTask
 	ctx          context.Context
 	cancel       context.CancelFunc
+	// 周期任务所在的调度组及任务句柄
+	group     *scheduler.Group
+	jobHandle *scheduler.JobHandle
 	// 销毁后不能再次启动
 	destroyed           bool
 	started             bool
@@ -87,6 +99,16 @@ func (t *taskRoutine) Destroy() {
 	t.stop()
 }
 
+// GetName 获取任务名
+func (t *taskRoutine) GetName() string {
+	return t.periodicTask.Name
+}
+
+// GetQueueDepth 获取高优先级队列中等待处理的任务数
+func (t *taskRoutine) GetQueueDepth() int {
+	return len(t.priorityChan)
+}
+
 // TaskItem 任务包裹
 type TaskItem struct {
 	value           model.TaskValue
@@ -106,7 +128,17 @@ func (t *taskRoutine) start() {
 		go t.runTakePriority()
 	} else {
 		log.GetBaseLogger().Infof("task %s started period %v", t.periodicTask.Name, t.periodicTask.Period)
-		go t.runPeriod()
+		t.jobHandle = t.group.Schedule(scheduler.Job{
+			Name:           t.periodicTask.Name,
+			Period:         t.periodicTask.Period,
+			Jitter:         periodTaskJitter,
+			Priority:       scheduler.PriorityNormal,
+			RunImmediately: true,
+			Run: func() time.Duration {
+				t.iteratePeriodTaskItems(false)
+				return 0
+			},
+		})
 	}
 }
 
@@ -116,6 +148,10 @@ func (t *taskRoutine) stop() {
 		return
 	}
 	t.cancel()
+	if nil != t.jobHandle {
+		t.jobHandle.Cancel()
+		t.jobHandle = nil
+	}
 	t.started = false
 	t.periodicTask.CallBack.OnTaskEvent(model.EventStop)
 }
@@ -170,22 +206,6 @@ func (t *taskRoutine) iteratePeriodTaskItems(takePriority bool) {
 	}
 }
 
-// runPeriod 处理定时任务
-func (t *taskRoutine) runPeriod() {
-	ticker := time.NewTicker(t.periodicTask.Period)
-	defer ticker.Stop()
-	t.iteratePeriodTaskItems(false)
-	for {
-		select {
-		case <-t.ctx.Done():
-			log.GetBaseLogger().Infof("task %s has done", t.periodicTask.Name)
-			return
-		case <-ticker.C:
-			t.iteratePeriodTaskItems(false)
-		}
-	}
-}
-
 // Started 获取状态，仅供测试使用
 func (t *taskRoutine) Started() bool {
 	t.mutex.Lock()
@@ -0,0 +1,125 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// DestroyWithContext 优雅销毁流程引擎
+// 先对自动心跳的实例发送最后一次心跳或反注册，再停止后台任务，各环节的错误以环节名为前缀汇总返回
+func (e *Engine) DestroyWithContext(ctx context.Context) error {
+	var errs error
+	if err := e.finishRegisters(ctx); err != nil {
+		errs = multierror.Append(errs, multierror.Prefix(err, "provider:"))
+	}
+	if err := e.Destroy(); err != nil {
+		errs = multierror.Append(errs, multierror.Prefix(err, "engine:"))
+	}
+	return errs
+}
+
+// finishAbandonTimeout 截止时间到达后等待在途调用退出的上限，超过后放弃等待，避免阻塞连接器的销毁
+const finishAbandonTimeout = time.Second
+
+// finishRegisters 对自动心跳的实例并发发送最后一次心跳，配置了 provider.deregisterOnDestroy 时改为反注册
+func (e *Engine) finishRegisters(ctx context.Context) error {
+	instances := e.registerStates.ListRegisters()
+	if len(instances) == 0 {
+		return nil
+	}
+	deregister := e.configuration.GetProvider().IsDeregisterOnDestroy()
+	return finishInstances(ctx, instances, func(ctx context.Context, instance *model.InstanceRegisterRequest) error {
+		if deregister {
+			req := &model.InstanceDeRegisterRequest{
+				Namespace:    instance.Namespace,
+				Service:      instance.Service,
+				Host:         instance.Host,
+				Port:         instance.Port,
+				ServiceToken: instance.ServiceToken,
+				InstanceID:   instance.InstanceId,
+			}
+			req.SetContext(ctx)
+			return e.SyncDeregister(req)
+		}
+		req := &model.InstanceHeartbeatRequest{
+			Namespace:    instance.Namespace,
+			Service:      instance.Service,
+			Host:         instance.Host,
+			Port:         instance.Port,
+			ServiceToken: instance.ServiceToken,
+			InstanceID:   instance.InstanceId,
+		}
+		req.SetContext(ctx)
+		return e.SyncHeartbeat(req)
+	})
+}
+
+// finishInstances 并发对实例执行finish，ctx传递给每个调用，截止时间到达后调用随ctx中止，
+// 并最多等待finishAbandonTimeout让在途调用退出后返回
+func finishInstances(ctx context.Context, instances []*model.InstanceRegisterRequest,
+	finish func(ctx context.Context, instance *model.InstanceRegisterRequest) error) error {
+	var (
+		mutex sync.Mutex
+		errs  error
+		wg    sync.WaitGroup
+	)
+	for _, instance := range instances {
+		wg.Add(1)
+		go func(instance *model.InstanceRegisterRequest) {
+			defer wg.Done()
+			if err := finish(ctx, instance); err != nil {
+				mutex.Lock()
+				errs = multierror.Append(errs, fmt.Errorf("instance {%s, %s, %s:%d}: %v",
+					instance.Namespace, instance.Service, instance.Host, instance.Port, err))
+				mutex.Unlock()
+				return
+			}
+			log.GetBaseLogger().Infof("[Provider][Destroy] instance {%s, %s, %s:%d} finished",
+				instance.Namespace, instance.Service, instance.Host, instance.Port)
+		}(instance)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		timer := time.NewTimer(finishAbandonTimeout)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+			log.GetBaseLogger().Warnf("[Provider][Destroy] abandon unfinished instances after %v", finishAbandonTimeout)
+		}
+		return fmt.Errorf("not all instances finished before deadline: %v", ctx.Err())
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	return errs
+}
@@ -0,0 +1,96 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func testRegisters(n int) []*model.InstanceRegisterRequest {
+	instances := make([]*model.InstanceRegisterRequest, 0, n)
+	for i := 0; i < n; i++ {
+		instances = append(instances, &model.InstanceRegisterRequest{
+			Namespace: "Test",
+			Service:   "svc",
+			Host:      "127.0.0.1",
+			Port:      8080 + i,
+		})
+	}
+	return instances
+}
+
+// TestFinishInstances 测试优雅销毁时的实例收尾
+func TestFinishInstances(t *testing.T) {
+	t.Run("全部完成时汇总失败实例", func(t *testing.T) {
+		instances := testRegisters(3)
+		err := finishInstances(context.Background(), instances,
+			func(ctx context.Context, instance *model.InstanceRegisterRequest) error {
+				if instance.Port == 8081 {
+					return errors.New("heartbeat fail")
+				}
+				return nil
+			})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "127.0.0.1:8081")
+		assert.NotContains(t, err.Error(), "127.0.0.1:8080")
+	})
+
+	t.Run("截止时间到达后调用随ctx中止", func(t *testing.T) {
+		var running int32
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := finishInstances(ctx, testRegisters(3),
+			func(ctx context.Context, instance *model.InstanceRegisterRequest) error {
+				atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				<-ctx.Done()
+				return ctx.Err()
+			})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "deadline")
+		// 返回前在途调用均已退出，之后销毁连接器是安全的
+		assert.Equal(t, int32(0), atomic.LoadInt32(&running))
+		assert.True(t, time.Since(start) < finishAbandonTimeout)
+	})
+
+	t.Run("调用不响应ctx时有界等待后放弃", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := finishInstances(ctx, testRegisters(2),
+			func(ctx context.Context, instance *model.InstanceRegisterRequest) error {
+				<-block
+				return nil
+			})
+		elapsed := time.Since(start)
+		assert.Error(t, err)
+		assert.True(t, elapsed >= finishAbandonTimeout)
+		assert.True(t, elapsed < finishAbandonTimeout+time.Second)
+	})
+}
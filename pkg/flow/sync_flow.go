@@ -19,6 +19,7 @@ package flow
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/config"
@@ -53,8 +54,11 @@ func (e *Engine) reportRateLimitGauge(req *model.QuotaRequestImpl, resp *model.Q
 		EmptyInstanceGauge: model.EmptyInstanceGauge{},
 		Namespace:          req.GetNamespace(),
 		Service:            req.GetService(),
+		Method:             req.GetMethod(),
 		Result:             resp.Code,
 		Arguments:          req.Arguments(),
+		RuleName:           resp.ShadowRuleName,
+		ShadowLimited:      len(resp.ShadowRuleName) > 0,
 	}
 	_ = e.SyncReportStat(model.RateLimitStat, stat)
 }
@@ -83,16 +87,40 @@ func (e *Engine) syncConsumerInitCallServiceAndFinalize(commonRequest *data.Cons
 
 // SyncGetOneInstance 同步获取服务实例
 func (e *Engine) SyncGetOneInstance(req *model.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	// 热点路径，未添加拦截器时直接调用，不构造 Invocation 及闭包
+	if e.interceptors.empty() {
+		return e.syncGetOneInstance(req)
+	}
+	resp, err := e.interceptors.invoke(model.ApiGetOneInstance, req, func(inv *model.Invocation) (interface{}, error) {
+		return e.syncGetOneInstance(inv.Request.(*model.GetOneInstanceRequest))
+	})
+	oneResp, _ := resp.(*model.OneInstanceResponse)
+	return oneResp, err
+}
+
+// syncGetOneInstance 同步获取服务实例
+func (e *Engine) syncGetOneInstance(req *model.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
 	// 方法开始时间
+	startTime := e.globalCtx.Now()
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
 	commonRequest.InitByGetOneRequest(req, e.configuration)
-	resp, err := e.doSyncGetOneInstance(commonRequest)
+	commonRequest.RouteInfo.Trace = e.diagnosticsAssistant.StartTrace()
+	if err := e.applyRequestRouters(req.Routers, commonRequest); err != nil {
+		(&commonRequest.CallResult).SetFail(model.GetErrorCodeFromError(err), 0)
+		e.syncInstancesReportAndFinalize(commonRequest)
+		return nil, err
+	}
+	resp, err := e.doSyncGetOneInstance(req, commonRequest)
+	if nil != commonRequest.RouteInfo.Trace {
+		e.recordDiagnostics(e.globalCtx.Since(startTime), req, commonRequest, resp, err)
+	}
 	e.syncInstancesReportAndFinalize(commonRequest)
 	return resp, err
 }
 
 // doSyncGetOneInstance 操作主要业务逻辑
-func (e *Engine) doSyncGetOneInstance(commonRequest *data.CommonInstancesRequest) (*model.OneInstanceResponse, error) {
+func (e *Engine) doSyncGetOneInstance(req *model.GetOneInstanceRequest,
+	commonRequest *data.CommonInstancesRequest) (*model.OneInstanceResponse, error) {
 	startTime := e.globalCtx.Now()
 	err := e.syncGetWrapInstances(commonRequest)
 	consumeTime := e.globalCtx.Since(startTime)
@@ -100,6 +128,16 @@ func (e *Engine) doSyncGetOneInstance(commonRequest *data.CommonInstancesRequest
 		(&commonRequest.CallResult).SetFail(model.GetErrorCodeFromError(err), consumeTime)
 		return nil, err
 	}
+	// 实例全部被熔断时，优先使用用户注册的降级函数
+	if resp, ok, err := e.tryInstanceFallback(req, commonRequest); ok {
+		consumeTime = e.globalCtx.Since(startTime)
+		if err != nil {
+			(&commonRequest.CallResult).SetFail(model.GetErrorCodeFromError(err), consumeTime)
+			return nil, err
+		}
+		(&commonRequest.CallResult).SetSuccess(consumeTime)
+		return resp, nil
+	}
 	return e.doLoadBalanceToOneInstance(startTime, commonRequest)
 }
 
@@ -137,27 +175,37 @@ func (e *Engine) SyncGetResources(req model.CacheValueQuery) error {
 	var combineContext *CombineNotifyContext
 	dstService := req.GetDstService()
 	param := req.GetControlParam()
+	if param.CacheOnly {
+		return e.getResourcesFromCache(req)
+	}
 	var totalConsumedTime, totalSleepTime time.Duration
+	ctx := param.GetContext()
 outLoop:
 	for retryTimes < param.MaxRetry {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			// 调用方已取消或超时，不再等待远程结果及重试
+			return model.NewContextError(ctxErr)
+		}
 		startTime := e.globalCtx.Now()
 		// 尝试获取本地缓存的值
 		combineContext, err = getAndLoadCacheValues(e.registry, req, retryTimes < param.MaxRetry)
 		if err != nil {
 			break outLoop
 		}
+		if retryTimes < 0 {
+			e.reportCacheStat(dstService, nil == combineContext)
+		}
 		// 本地缓存已经加载完成，退出
 		if nil == combineContext {
 			return nil
 		}
 		// 发起并等待远程的结果
 		retryTimes++
-		syncCtx := combineContext
-		exceedTimeout := syncCtx.Wait(param.Timeout)
+		// 相同资源的并发加载合并为一次等待
+		exceedTimeout, sdkErrs := e.resolveGroup.wait(ctx, combineContext, param.Timeout)
 		// 计算请求耗时
 		consumedTime := e.globalCtx.Since(startTime)
 		totalConsumedTime += consumedTime
-		sdkErrs := syncCtx.Errs()
 		if len(sdkErrs) > 0 {
 			e.reportCombinedErrs(req.GetCallResult(), consumedTime, sdkErrs)
 			err = combineSDKErrors(sdkErrs)
@@ -165,7 +213,7 @@ outLoop:
 		}
 		if exceedTimeout {
 			// 只有网络错误才可以重试
-			time.Sleep(param.RetryInterval)
+			data.SleepWithContext(ctx, param.RetryInterval)
 			totalSleepTime += param.RetryInterval
 			continue
 		}
@@ -279,6 +327,16 @@ func verifyCluster(svcInstances model.ServiceInstances, cluster *model.Cluster)
 func (e *Engine) SyncGetInstances(req *model.GetInstancesRequest) (*model.InstancesResponse, error) {
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
 	commonRequest.InitByGetMultiRequest(req, e.configuration)
+	if err := e.applyRequestRouters(req.Routers, commonRequest); err != nil {
+		(&commonRequest.CallResult).SetFail(model.GetErrorCodeFromError(err), 0)
+		e.syncInstancesReportAndFinalize(commonRequest)
+		return nil, err
+	}
+	if err := e.applyFreshness(req.Freshness, commonRequest); err != nil {
+		(&commonRequest.CallResult).SetFail(model.GetErrorCodeFromError(err), 0)
+		e.syncInstancesReportAndFinalize(commonRequest)
+		return nil, err
+	}
 	resp, err := e.doSyncGetInstances(commonRequest)
 	e.syncInstancesReportAndFinalize(commonRequest)
 	return resp, err
@@ -332,6 +390,15 @@ func (e *Engine) doSyncGetInstances(commonRequest *data.CommonInstancesRequest)
 
 // SyncRegister 同步进行服务注册
 func (e *Engine) SyncRegister(instance *model.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	resp, err := e.interceptors.invoke(model.ApiRegister, instance, func(inv *model.Invocation) (interface{}, error) {
+		return e.syncRegister(inv.Request.(*model.InstanceRegisterRequest))
+	})
+	registerResp, _ := resp.(*model.InstanceRegisterResponse)
+	return registerResp, err
+}
+
+// syncRegister 同步进行服务注册，开启自动心跳时同时维护注册状态
+func (e *Engine) syncRegister(instance *model.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
 	if instance.AutoHeartbeat {
 		instance.SetDefaultTTL()
 		resp, err := e.doSyncRegister(instance, registerstate.CreateRegisterV2Header())
@@ -339,7 +406,10 @@ func (e *Engine) SyncRegister(instance *model.InstanceRegisterRequest) (*model.I
 			return nil, err
 		}
 
-		e.registerStates.PutRegister(instance, e.doSyncRegister, e.SyncHeartbeat)
+		// 后台心跳及重注册不受本次调用的ctx约束
+		background := *instance
+		background.SetContext(nil)
+		e.registerStates.PutRegister(&background, e.doSyncRegister, e.SyncHeartbeat)
 		return resp, nil
 	}
 	return e.doSyncRegister(instance, nil)
@@ -368,8 +438,13 @@ func (e *Engine) doSyncRegister(instance *model.InstanceRegisterRequest, header
 	if instance.Location == nil {
 		instance.Location = e.globalCtx.GetCurrentLocation().GetLocation()
 	}
+	request, err := e.encryptRegisterMetadata(instance)
+	if err != nil {
+		apiCallResult.SetFail(model.GetErrorCodeFromError(err), e.globalCtx.Since(startTime))
+		return nil, err
+	}
 
-	resp, err := data.RetrySyncCall("register", &svcKey, instance, func(request interface{}) (interface{}, error) {
+	resp, err := data.RetrySyncCall("register", &svcKey, request, func(request interface{}) (interface{}, error) {
 		return e.connector.RegisterInstance(request.(*model.InstanceRegisterRequest), header)
 	}, param)
 	consumeTime := e.globalCtx.Since(startTime)
@@ -412,8 +487,83 @@ func (e *Engine) SyncDeregister(instance *model.InstanceDeRegisterRequest) error
 	return err
 }
 
+// SyncGracefulDeregister 同步进行优雅下线
+// 先停止自动心跳并以隔离状态重新注册实例，使新流量不再路由到该实例，
+// 等待排空时长结束或在途请求归零后，再进行反注册
+func (e *Engine) SyncGracefulDeregister(instance *model.InstanceGracefulDeregisterRequest) error {
+	registered := instance.Instance
+	if registered == nil {
+		registered = e.registerStates.GetRegister(&instance.InstanceDeRegisterRequest)
+	}
+	e.registerStates.RemoveRegister(&instance.InstanceDeRegisterRequest)
+	if registered != nil {
+		isolated := *registered
+		isolated.AutoHeartbeat = false
+		isolated.SetIsolate(true)
+		if _, err := e.doSyncRegister(&isolated, registerstate.CreateRegisterV2Header()); err != nil {
+			log.GetBaseLogger().Warnf("[Provider][GracefulDeregister] fail to isolate instance %s, err %v",
+				instance.InstanceDeRegisterRequest, err)
+		} else {
+			log.GetBaseLogger().Infof("[Provider][GracefulDeregister] instance %s isolated, start draining",
+				instance.InstanceDeRegisterRequest)
+		}
+	} else {
+		log.GetBaseLogger().Warnf("[Provider][GracefulDeregister] register info of instance %s not found, "+
+			"skip isolating and drain directly", instance.InstanceDeRegisterRequest)
+	}
+	drainPeriod := e.configuration.GetProvider().GetDrainPeriod()
+	if instance.DrainPeriod != nil {
+		drainPeriod = *instance.DrainPeriod
+	}
+	waitDrained(drainPeriod, instance.InFlightRequests)
+	return e.SyncDeregister(&instance.InstanceDeRegisterRequest)
+}
+
+// SyncUpdateInstanceMetadata 更新已注册实例的元数据及权重，更新间隔内的多次变更合并后异步推送
+func (e *Engine) SyncUpdateInstanceMetadata(req *model.InstanceMetadataUpdateRequest) error {
+	return e.registerStates.UpdateMetadata(req, e.doSyncRegister)
+}
+
+// AddLoadSampler 添加自适应权重使用的负载采样器
+func (e *Engine) AddLoadSampler(sampler model.LoadSampler) {
+	e.adaptiveWeightAssistant.AddSampler(sampler)
+}
+
+// drainCheckInterval 优雅下线时检查在途请求数的间隔
+const drainCheckInterval = 100 * time.Millisecond
+
+// waitDrained 等待排空，在途请求归零或超过排空时长时返回
+func waitDrained(drainPeriod time.Duration, inFlightRequests func() int64) {
+	if drainPeriod <= 0 {
+		return
+	}
+	timer := time.NewTimer(drainPeriod)
+	defer timer.Stop()
+	if inFlightRequests == nil {
+		<-timer.C
+		return
+	}
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+	for inFlightRequests() > 0 {
+		select {
+		case <-timer.C:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // SyncHeartbeat 同步进行心跳上报
 func (e *Engine) SyncHeartbeat(instance *model.InstanceHeartbeatRequest) error {
+	_, err := e.interceptors.invoke(model.ApiHeartbeat, instance, func(inv *model.Invocation) (interface{}, error) {
+		return nil, e.syncHeartbeat(inv.Request.(*model.InstanceHeartbeatRequest))
+	})
+	return err
+}
+
+// syncHeartbeat 同步进行心跳上报
+func (e *Engine) syncHeartbeat(instance *model.InstanceHeartbeatRequest) error {
 	// 调用api的结果上报
 	apiCallResult := &model.APICallResult{
 		APICallKey: model.APICallKey{
@@ -460,14 +610,87 @@ func (e *Engine) SyncUpdateServiceCallResult(result *model.ServiceCallResult) er
 
 // realSyncUpdateServiceCallResult 同步上报调用结果信息 实际处理函数
 func (e *Engine) realSyncUpdateServiceCallResult(result *model.ServiceCallResult) error {
+	// 对冲请求只统计最先上报的调用，避免被取消的对冲调用影响调用统计、时延采样、负载均衡及熔断统计
+	if result.GetHedgeID() != "" && !e.hedgingAssistant.AcquireReport(result.GetHedgeID()) {
+		return nil
+	}
 	// 当前处理熔断和服务调用统计上报
 	if err := e.reportSvcStat(result); err != nil {
 		return err
 	}
-	// TODO 用新的熔断实现进行适配
+	e.recordCallLatency(result)
+	for _, aware := range e.callResultAwareLBs {
+		aware.UpdateCallResult(result)
+	}
+	e.reportInstanceCircuitBreak(result)
 	return nil
 }
 
+// recordCallLatency 记录成功调用的时延，用于计算对冲延迟
+func (e *Engine) recordCallLatency(result *model.ServiceCallResult) {
+	if result.CalledInstance == nil || result.GetRetStatus() != model.RetSuccess || result.GetDelay() == nil {
+		return
+	}
+	e.hedgingAssistant.RecordLatency(model.ServiceKey{
+		Namespace: result.CalledInstance.GetNamespace(),
+		Service:   result.CalledInstance.GetService(),
+	}, *result.GetDelay())
+}
+
+// reportInstanceCircuitBreak 将实例调用结果上报给熔断链，用于实例级、接口级熔断及离群检测
+func (e *Engine) reportInstanceCircuitBreak(result *model.ServiceCallResult) {
+	if e.circuitBreakerFlow == nil || result.CalledInstance == nil {
+		return
+	}
+	instance := result.CalledInstance
+	svcKey := &model.ServiceKey{
+		Namespace: instance.GetNamespace(),
+		Service:   instance.GetService(),
+	}
+	var caller *model.ServiceKey
+	if result.SourceService != nil {
+		caller = &model.ServiceKey{
+			Namespace: result.SourceService.Namespace,
+			Service:   result.SourceService.Service,
+		}
+	}
+	var delay time.Duration
+	if result.GetDelay() != nil {
+		delay = *result.GetDelay()
+	}
+	retCode := strconv.FormatInt(int64(result.GetRetCodeValue()), 10)
+	// 开启方法维度统计且携带方法名时按服务+方法维度统计，单个接口异常只熔断该接口
+	cbConfig := e.configuration.GetConsumer().GetCircuitBreaker()
+	if cbConfig.IsMethodLevel() && result.GetMethod() != "" {
+		methodRes, err := model.NewMethodResource(svcKey, caller, result.GetMethod())
+		if err == nil {
+			_ = e.circuitBreakerFlow.Report(&model.ResourceStat{
+				Resource:  methodRes,
+				RetCode:   retCode,
+				Delay:     delay,
+				RetStatus: result.GetRetStatus(),
+			})
+		} else {
+			log.GetBaseLogger().Debugf("[CircuitBreaker] build method resource fail, err %v", err)
+		}
+		if cbConfig.IsMethodIsolation() {
+			return
+		}
+	}
+	insRes, err := model.NewInstanceResource(svcKey, caller, instance.GetProtocol(), instance.GetHost(),
+		instance.GetPort())
+	if err != nil {
+		log.GetBaseLogger().Debugf("[CircuitBreaker] build instance resource fail, err %v", err)
+		return
+	}
+	_ = e.circuitBreakerFlow.Report(&model.ResourceStat{
+		Resource:  insRes,
+		RetCode:   retCode,
+		Delay:     delay,
+		RetStatus: result.GetRetStatus(),
+	})
+}
+
 // SyncGetServices 获取服务列表
 func (e *Engine) SyncGetServices(eventType model.EventType,
 	req *model.GetServicesRequest) (*model.ServicesResponse, error) {
@@ -507,7 +730,13 @@ func (e *Engine) doSyncGetServiceRule(commonRequest *data.CommonRuleRequest) (*m
 		ServiceKey: &commonRequest.DstService.ServiceKey,
 		Operation:  keyDstRoute}
 	apiStartTime := e.globalCtx.Now()
+	ctx := commonRequest.ControlParam.GetContext()
 	for retryTimes < maxRetryTimes {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			sdkErr := model.NewContextError(ctxErr)
+			(&commonRequest.CallResult).SetFail(sdkErr.ErrorCode(), e.globalCtx.Since(apiStartTime))
+			return nil, sdkErr
+		}
 		startTime := e.globalCtx.Now()
 		svcRule := e.registry.GetServiceRule(&commonRequest.DstService, false)
 		if svcRule.IsInitialized() {
@@ -522,12 +751,12 @@ func (e *Engine) doSyncGetServiceRule(commonRequest *data.CommonRuleRequest) (*m
 		}
 		singleCtx := NewSingleNotifyContext(svcRuleKey, notifier)
 		retryTimes++
-		exceedTimeout := singleCtx.Wait(commonRequest.ControlParam.Timeout)
+		exceedTimeout := singleCtx.Wait(ctx, commonRequest.ControlParam.Timeout)
 		// 计算请求耗时
 		consumedTime := e.globalCtx.Since(startTime)
 		if exceedTimeout {
 			// 只有网络错误才可以重试
-			time.Sleep(commonRequest.ControlParam.RetryInterval)
+			data.SleepWithContext(ctx, commonRequest.ControlParam.RetryInterval)
 			log.GetBaseLogger().Warnf("retry GetRoutes for timeout, consume time %v,"+
 				" Namespace: %s, Service: %s, retry times: %d",
 				consumedTime, commonRequest.DstService.Namespace, commonRequest.DstService.Service, retryTimes)
@@ -548,7 +777,7 @@ func (e *Engine) doSyncGetServiceRule(commonRequest *data.CommonRuleRequest) (*m
 		" Namespace: %s, Service: %s",
 		commonRequest.DstService.Namespace, commonRequest.DstService.Service)
 	// 上面的尝试超时之后，向尝试获取从缓存文件加载的信息
-	svcRule := e.registry.GetServiceRouteRule(&commonRequest.DstService.ServiceKey, true)
+	svcRule := e.registry.GetServiceRule(&commonRequest.DstService, true)
 	if svcRule.IsInitialized() {
 		commonRequest.CallResult.SetSuccess(e.globalCtx.Since(apiStartTime))
 		return commonRequest.BuildServiceRuleResponse(svcRule), nil
@@ -593,19 +822,53 @@ func (e *Engine) realInitCalleeService(req *model.InitCalleeServiceRequest,
 	return nil
 }
 
+// SyncInjectFault 计算本次调用需要注入的故障
+func (e *Engine) SyncInjectFault(req *model.InjectFaultRequest) (*model.InjectFaultResponse, error) {
+	return e.faultInjectionAssistant.InjectFault(req)
+}
+
 // SyncGetConfigFile 同步获取配置文件
 func (e *Engine) SyncGetConfigFile(req *model.GetConfigFileRequest) (model.ConfigFile, error) {
-	return e.configFlow.GetConfigFile(req)
+	resp, err := e.interceptors.invoke(model.ApiGetConfigFile, req, func(inv *model.Invocation) (interface{}, error) {
+		return e.configFlow.GetConfigFile(inv.Request.(*model.GetConfigFileRequest))
+	})
+	configFile, _ := resp.(model.ConfigFile)
+	return configFile, err
 }
 
 // SyncGetConfigGroup 同步获取配置文件
 func (e *Engine) SyncGetConfigGroup(namespace, fileGroup string) (model.ConfigFileGroup, error) {
-	return e.configFlow.GetConfigGroup(namespace, fileGroup)
+	req := &model.GetConfigGroupRequest{Namespace: namespace, FileGroup: fileGroup, Mode: model.SDKMode}
+	resp, err := e.interceptors.invoke(model.ApiGetConfigGroup, req, func(inv *model.Invocation) (interface{}, error) {
+		groupReq := inv.Request.(*model.GetConfigGroupRequest)
+		return e.configFlow.GetConfigGroup(groupReq.Namespace, groupReq.FileGroup)
+	})
+	configGroup, _ := resp.(model.ConfigFileGroup)
+	return configGroup, err
 }
 
 // SyncGetConfigGroupWithReq 同步获取配置文件
 func (e *Engine) SyncGetConfigGroupWithReq(req *model.GetConfigGroupRequest) (model.ConfigFileGroup, error) {
-	return e.configFlow.GetConfigGroupWithReq(req)
+	resp, err := e.interceptors.invoke(model.ApiGetConfigGroup, req, func(inv *model.Invocation) (interface{}, error) {
+		return e.configFlow.GetConfigGroupWithReq(inv.Request.(*model.GetConfigGroupRequest))
+	})
+	configGroup, _ := resp.(model.ConfigFileGroup)
+	return configGroup, err
+}
+
+// SyncGetConfigGroupFiles 同步获取配置分组下所有配置文件
+func (e *Engine) SyncGetConfigGroupFiles(namespace, fileGroup string) ([]model.ConfigFile, error) {
+	return e.configFlow.GetConfigGroupFiles(namespace, fileGroup)
+}
+
+// WatchConfigGroup 监听配置分组内任意文件的变更
+func (e *Engine) WatchConfigGroup(namespace, fileGroup string, cb model.OnConfigFileChange) error {
+	return e.configFlow.WatchConfigGroup(namespace, fileGroup, cb)
+}
+
+// WatchConfigFilesByPattern 按文件名通配符监听配置分组内的文件
+func (e *Engine) WatchConfigFilesByPattern(namespace, fileGroup, pattern string, cb model.OnConfigFileChange) error {
+	return e.configFlow.WatchConfigFilesByPattern(namespace, fileGroup, pattern, cb)
 }
 
 // SyncCreateConfigFile 同步创建配置文件
@@ -623,11 +886,37 @@ func (e *Engine) SyncPublishConfigFile(namespace, fileGroup, fileName string) er
 	return e.configFlow.PublishConfigFile(namespace, fileGroup, fileName)
 }
 
+// SyncCreateConfigFileWithReq 同步创建配置文件
+func (e *Engine) SyncCreateConfigFileWithReq(req *model.ConfigFileModifyRequest) error {
+	return e.configFlow.CreateConfigFileWithReq(req)
+}
+
+// SyncUpdateConfigFileWithReq 同步更新配置文件
+func (e *Engine) SyncUpdateConfigFileWithReq(req *model.ConfigFileModifyRequest) error {
+	return e.configFlow.UpdateConfigFileWithReq(req)
+}
+
+// SyncPublishConfigFileWithReq 同步发布配置文件
+func (e *Engine) SyncPublishConfigFileWithReq(req *model.ConfigFilePublishRequest) error {
+	return e.configFlow.PublishConfigFileWithReq(req)
+}
+
+// SyncRollbackConfigFile 同步回滚配置文件
+func (e *Engine) SyncRollbackConfigFile(req *model.ConfigFileRollbackRequest) error {
+	return e.configFlow.RollbackConfigFile(req)
+}
+
 // WatchAllInstances 监听所有的实例
 func (e *Engine) WatchAllInstances(request *model.WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error) {
 	return e.watchEngine.WatchAllInstances(request)
 }
 
+// WatchInstanceMetadata 监听服务实例的元数据变更
+func (e *Engine) WatchInstanceMetadata(
+	request *model.WatchInstanceMetadataRequest) (*model.WatchAllInstancesResponse, error) {
+	return e.watchEngine.WatchInstanceMetadata(request)
+}
+
 // WatchAllServices 监听所有的服务列表
 func (e *Engine) WatchAllServices(request *model.WatchAllServicesRequest) (*model.WatchAllServicesResponse, error) {
 	return e.watchEngine.WatchAllServices(request)
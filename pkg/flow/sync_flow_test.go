@@ -0,0 +1,214 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/hedging"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
+)
+
+// recordCircuitBreaker 记录上报到熔断链的资源
+type recordCircuitBreaker struct {
+	circuitbreaker.CircuitBreaker
+	stats []*model.ResourceStat
+}
+
+func (r *recordCircuitBreaker) Name() string {
+	return "record"
+}
+
+func (r *recordCircuitBreaker) Report(stat *model.ResourceStat) error {
+	r.stats = append(r.stats, stat)
+	return nil
+}
+
+// cbTestInstance 只实现熔断上报需要的字段
+type cbTestInstance struct {
+	model.Instance
+}
+
+func (cbTestInstance) GetNamespace() string { return "Test" }
+func (cbTestInstance) GetService() string   { return "svc" }
+func (cbTestInstance) GetProtocol() string  { return "grpc" }
+func (cbTestInstance) GetHost() string      { return "127.0.0.1" }
+func (cbTestInstance) GetPort() uint32      { return 8080 }
+
+// TestReportInstanceCircuitBreak 测试调用结果按配置上报到实例及服务+方法维度
+func TestReportInstanceCircuitBreak(t *testing.T) {
+	tests := []struct {
+		name            string
+		methodLevel     bool
+		methodIsolation bool
+		method          string
+		wantLevels      []fault_tolerance.Level
+	}{
+		{
+			name:       "默认只上报实例维度",
+			method:     "/echo",
+			wantLevels: []fault_tolerance.Level{fault_tolerance.Level_INSTANCE},
+		},
+		{
+			name:        "开启方法维度后同时上报方法及实例维度",
+			methodLevel: true,
+			method:      "/echo",
+			wantLevels:  []fault_tolerance.Level{fault_tolerance.Level_METHOD, fault_tolerance.Level_INSTANCE},
+		},
+		{
+			name:            "开启接口隔离后只上报方法维度",
+			methodLevel:     true,
+			methodIsolation: true,
+			method:          "/echo",
+			wantLevels:      []fault_tolerance.Level{fault_tolerance.Level_METHOD},
+		},
+		{
+			name:            "没有方法名时只上报实例维度",
+			methodLevel:     true,
+			methodIsolation: true,
+			wantLevels:      []fault_tolerance.Level{fault_tolerance.Level_INSTANCE},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+			cfg.GetConsumer().GetCircuitBreaker().SetMethodLevel(tt.methodLevel)
+			cfg.GetConsumer().GetCircuitBreaker().SetMethodIsolation(tt.methodIsolation)
+			breaker := &recordCircuitBreaker{}
+			e := &Engine{configuration: cfg}
+			e.circuitBreakerFlow = newCircuitBreakerFlow(e, []circuitbreaker.CircuitBreaker{breaker})
+
+			result := &model.ServiceCallResult{CalledInstance: cbTestInstance{}}
+			result.SetRetStatus(model.RetFail).SetRetCode(500).SetDelay(time.Second)
+			result.SetMethod(tt.method)
+			e.reportInstanceCircuitBreak(result)
+
+			levels := make([]fault_tolerance.Level, 0, len(breaker.stats))
+			for _, stat := range breaker.stats {
+				levels = append(levels, stat.Resource.GetLevel())
+				assert.Equal(t, "500", stat.RetCode)
+				assert.Equal(t, time.Second, stat.Delay)
+				assert.Equal(t, model.RetFail, stat.RetStatus)
+			}
+			assert.Equal(t, tt.wantLevels, levels)
+		})
+	}
+}
+
+// TestCircuitBreakerConfigMethodIsolation 测试接口隔离需要开启方法维度统计
+func TestCircuitBreakerConfigMethodIsolation(t *testing.T) {
+	cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	cbConfig := cfg.GetConsumer().GetCircuitBreaker()
+	cbConfig.SetMethodIsolation(true)
+	assert.NotNil(t, cbConfig.(*config.CircuitBreakerConfigImpl).Verify())
+	cbConfig.SetMethodLevel(true)
+	assert.Nil(t, cbConfig.(*config.CircuitBreakerConfigImpl).Verify())
+}
+
+// recordStatReporter 记录上报的服务调用统计
+type recordStatReporter struct {
+	statreporter.StatReporter
+	stats []model.InstanceGauge
+}
+
+func (r *recordStatReporter) ReportStat(typ model.MetricType, stat model.InstanceGauge) error {
+	r.stats = append(r.stats, stat)
+	return nil
+}
+
+// recordCallResultAware 记录负载均衡收到的调用结果
+type recordCallResultAware struct {
+	results []*model.ServiceCallResult
+}
+
+func (r *recordCallResultAware) UpdateCallResult(result *model.ServiceCallResult) {
+	r.results = append(r.results, result)
+}
+
+// TestUpdateServiceCallResultHedging 测试同一对冲请求只有最先上报的调用计入各项统计
+func TestUpdateServiceCallResultHedging(t *testing.T) {
+	tests := []struct {
+		name    string
+		hedging bool
+		// 依次上报的调用时延
+		delays []time.Duration
+		// 期望计入统计的调用时延
+		wantDelays []time.Duration
+	}{
+		{
+			name:       "对冲请求只统计最先上报的调用",
+			hedging:    true,
+			delays:     []time.Duration{100 * time.Millisecond, 5 * time.Second, time.Second},
+			wantDelays: []time.Duration{100 * time.Millisecond},
+		},
+		{
+			name:       "普通调用全部统计",
+			delays:     []time.Duration{100 * time.Millisecond, 5 * time.Second},
+			wantDelays: []time.Duration{100 * time.Millisecond, 5 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+			cfg.GetConsumer().GetHedging().SetPercentile(100)
+			cfg.GetConsumer().GetHedging().SetMinSamples(1)
+			reporter := &recordStatReporter{}
+			lb := &recordCallResultAware{}
+			breaker := &recordCircuitBreaker{}
+			e := &Engine{
+				configuration:      cfg,
+				reporterChain:      []statreporter.StatReporter{reporter},
+				callResultAwareLBs: []loadbalancer.CallResultAware{lb},
+				hedgingAssistant:   &hedging.HedgingAssistant{},
+			}
+			e.hedgingAssistant.Init(cfg)
+			e.circuitBreakerFlow = newCircuitBreakerFlow(e, []circuitbreaker.CircuitBreaker{breaker})
+
+			var hedgeID string
+			if tt.hedging {
+				hedgeID = e.hedgingAssistant.NewHedgeID()
+			}
+			for _, delay := range tt.delays {
+				result := &model.ServiceCallResult{CalledInstance: cbTestInstance{}}
+				result.SetRetStatus(model.RetSuccess).SetRetCode(0).SetDelay(delay)
+				result.SetHedgeID(hedgeID)
+				assert.Nil(t, e.realSyncUpdateServiceCallResult(result))
+			}
+
+			assert.Equal(t, len(tt.wantDelays), len(reporter.stats))
+			assert.Equal(t, len(tt.wantDelays), len(lb.results))
+			assert.Equal(t, len(tt.wantDelays), len(breaker.stats))
+			for i, want := range tt.wantDelays {
+				assert.Equal(t, want, *reporter.stats[i].GetDelay())
+				assert.Equal(t, want, *lb.results[i].GetDelay())
+				assert.Equal(t, want, breaker.stats[i].Delay)
+			}
+			// 按P100计算对冲延迟，被丢弃的调用时延不参与采样
+			maxDelay := tt.wantDelays[len(tt.wantDelays)-1]
+			assert.Equal(t, []time.Duration{maxDelay}, e.hedgingAssistant.HedgeDelays(model.ServiceKey{
+				Namespace: "Test", Service: "svc"}, 1))
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/flow/schedule"
 	"github.com/polarismesh/polaris-go/pkg/flow/startup"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/scheduler"
 )
 
 const (
@@ -30,12 +31,21 @@ const (
 	taskClientReport  = "clientReportTask"
 	taskServerService = "syncGetServerService"
 	taskHealthCheck   = "healthCheckTask"
+	taskHealthReport  = "sdkHealthReportTask"
 )
 
+// scheduleGroupEngine 流程引擎周期任务的调度组名
+const scheduleGroupEngine = "flowEngine"
+
 // ScheduleTask 调度任务
 func (e *Engine) ScheduleTask(task *model.PeriodicTask) (chan<- *model.PriorityTask, model.TaskValues) {
-	routine := schedule.NewTaskRoutine(task)
+	e.taskMutex.Lock()
+	if e.scheduleGroup == nil {
+		e.scheduleGroup = scheduler.GetScheduler().NewGroup(scheduleGroupEngine)
+	}
+	routine := schedule.NewTaskRoutine(task, e.scheduleGroup)
 	e.taskRoutines = append(e.taskRoutines, routine)
+	e.taskMutex.Unlock()
 	return routine.Schedule()
 }
 
@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/trafficlabel"
+)
+
+// SyncExtractTrafficLabels 从框架相关的请求对象中提取流量标签
+func (e *Engine) SyncExtractTrafficLabels(
+	req *model.ExtractTrafficLabelsRequest) (*model.ExtractTrafficLabelsResponse, error) {
+	var plugins []plugin.Plugin
+	if len(req.Provider) > 0 {
+		plug, err := e.plugins.GetPlugin(common.TypeTrafficLabelProvider, req.Provider)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, plug)
+	} else {
+		var err error
+		if plugins, err = e.plugins.GetPlugins(common.TypeTrafficLabelProvider); err != nil {
+			return nil, err
+		}
+	}
+	for _, plug := range plugins {
+		trafficReq, ok := plug.(trafficlabel.TrafficLabelProvider).Adapt(req.Request)
+		if !ok {
+			continue
+		}
+		return &model.ExtractTrafficLabelsResponse{
+			Arguments: model.BuildTrafficArguments(trafficReq, req.ClaimHeader),
+		}, nil
+	}
+	return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+		"no traffic label provider supports request type %T", req.Request)
+}
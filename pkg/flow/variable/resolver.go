@@ -0,0 +1,215 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package variable
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// VariableAssistant 路由变量解析的辅助类，按 global.system.variableResolvers 配置构建解析链
+type VariableAssistant struct {
+	// 变量解析链
+	chain *model.VariableResolverChain
+	// 配置中心解析器，未启用时为nil
+	fileResolver *ConfigFileResolver
+	// 重试间隔
+	retryInterval time.Duration
+	// 销毁通知
+	done chan struct{}
+	// 销毁标识
+	destroyOnce sync.Once
+}
+
+// Init 初始化
+func (v *VariableAssistant) Init(engine model.Engine, cfg config.Configuration) {
+	systemCfg := cfg.GetGlobal().GetSystem()
+	v.chain = model.NewVariableResolverChain()
+	v.retryInterval = cfg.GetGlobal().GetAPI().GetRetryInterval()
+	v.done = make(chan struct{})
+	for _, name := range systemCfg.GetVariableResolvers() {
+		switch name {
+		case model.VariableResolverSystem:
+			v.chain.AddResolver(&SystemConfigResolver{systemCfg: systemCfg})
+			systemCfg.AddVariableListener(func(key string) {
+				v.chain.Refresh(key)
+			})
+		case model.VariableResolverEnv:
+			v.chain.AddResolver(&model.EnvVariableResolver{})
+		case model.VariableResolverFlag:
+			v.chain.AddResolver(&model.FlagVariableResolver{})
+		case model.VariableResolverConfigCenter:
+			v.fileResolver = NewConfigFileResolver(engine, systemCfg.GetVariableFile(), v.retryInterval,
+				func() {
+					v.chain.Refresh()
+				})
+			v.chain.AddResolver(v.fileResolver)
+		}
+	}
+}
+
+// GetChain 获取变量解析链
+func (v *VariableAssistant) GetChain() *model.VariableResolverChain {
+	return v.chain
+}
+
+// Start 启用配置中心解析器时，后台拉取变量文件直至成功
+func (v *VariableAssistant) Start() {
+	if v.fileResolver == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(v.retryInterval)
+		defer ticker.Stop()
+		for !v.fileResolver.loadVariables(false) {
+			select {
+			case <-v.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Destroy 销毁
+func (v *VariableAssistant) Destroy() {
+	v.destroyOnce.Do(func() {
+		close(v.done)
+	})
+}
+
+// SystemConfigResolver 从 global.system.variables 中获取变量
+type SystemConfigResolver struct {
+	systemCfg config.SystemConfig
+}
+
+// Name 解析器名称
+func (s *SystemConfigResolver) Name() string {
+	return model.VariableResolverSystem
+}
+
+// Resolve 获取变量值
+func (s *SystemConfigResolver) Resolve(key string) (string, bool) {
+	return s.systemCfg.GetVariable(key)
+}
+
+// ConfigFileResolver 从配置中心的配置文件中获取变量，首次使用时拉取文件并监听后续变更
+type ConfigFileResolver struct {
+	// 流程执行引擎
+	engine model.Engine
+	// 变量所在的配置文件
+	fileReq *model.GetConfigFileRequest
+	// 拉取失败后的重试间隔
+	retryInterval time.Duration
+	// 变量发生变更时的回调
+	onChange func()
+	// 文件是否已加载
+	loaded uint32
+	// 加载锁
+	mutex sync.Mutex
+	// 上次拉取失败的时间
+	lastFailTime time.Time
+	// 当前生效的变量，类型为map[string]string
+	variables atomic.Value
+}
+
+// NewConfigFileResolver 创建配置中心变量解析器
+func NewConfigFileResolver(engine model.Engine, fileCfg config.VariableFileConfig,
+	retryInterval time.Duration, onChange func()) *ConfigFileResolver {
+	resolver := &ConfigFileResolver{
+		engine: engine,
+		fileReq: &model.GetConfigFileRequest{
+			Namespace: fileCfg.GetNamespace(),
+			FileGroup: fileCfg.GetFileGroup(),
+			FileName:  fileCfg.GetFileName(),
+			Subscribe: true,
+		},
+		retryInterval: retryInterval,
+		onChange:      onChange,
+	}
+	resolver.variables.Store(map[string]string{})
+	return resolver
+}
+
+// Name 解析器名称
+func (c *ConfigFileResolver) Name() string {
+	return model.VariableResolverConfigCenter
+}
+
+// Resolve 获取变量值，配置文件拉取失败时视为变量不存在
+func (c *ConfigFileResolver) Resolve(key string) (string, bool) {
+	c.loadVariables(true)
+	value, ok := c.variables.Load().(map[string]string)[key]
+	return value, ok
+}
+
+// loadVariables 获取变量配置文件，并监听后续的变更，返回文件是否已加载
+// checkInterval为true时，距上次失败不足重试间隔则不再拉取
+// 首次加载成功后会触发一次变更回调，刷新加载前已缓存的变量
+func (c *ConfigFileResolver) loadVariables(checkInterval bool) bool {
+	if atomic.LoadUint32(&c.loaded) == 1 {
+		return true
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if atomic.LoadUint32(&c.loaded) == 1 {
+		return true
+	}
+	if checkInterval && !c.lastFailTime.IsZero() && time.Since(c.lastFailTime) < c.retryInterval {
+		return false
+	}
+	configFile, err := c.engine.SyncGetConfigFile(c.fileReq)
+	if err != nil {
+		c.lastFailTime = time.Now()
+		log.GetBaseLogger().Errorf("[Variable] fail to get variable file %s/%s/%s, err %v",
+			c.fileReq.Namespace, c.fileReq.FileGroup, c.fileReq.FileName, err)
+		return false
+	}
+	c.updateVariables(configFile.GetContent())
+	configFile.AddChangeListener(func(event model.ConfigFileChangeEvent) {
+		c.updateVariables(event.NewValue)
+		c.notifyChange()
+	})
+	atomic.StoreUint32(&c.loaded, 1)
+	go c.notifyChange()
+	return true
+}
+
+func (c *ConfigFileResolver) notifyChange() {
+	if c.onChange != nil {
+		c.onChange()
+	}
+}
+
+// updateVariables 解析变量文件，解析失败时保留原有变量
+func (c *ConfigFileResolver) updateVariables(content string) {
+	variables := make(map[string]string)
+	if err := yaml.Unmarshal([]byte(content), &variables); err != nil {
+		log.GetBaseLogger().Errorf("[Variable] fail to parse variable file, keep the previous variables, err %v", err)
+		return
+	}
+	c.variables.Store(variables)
+	log.GetBaseLogger().Infof("[Variable] variables updated, count %d", len(variables))
+}
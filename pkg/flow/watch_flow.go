@@ -23,6 +23,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/polarismesh/polaris-go/pkg/dispatcher"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -51,14 +52,17 @@ type WatchEngine struct {
 	watchContexts map[uint64]WatchContext
 	indexSeed     uint64
 	registry      localregistry.LocalRegistry
+	// dispatcher 监听器回调分发协程池
+	dispatcher *dispatcher.Pool
 }
 
-func NewWatchEngine(registry localregistry.LocalRegistry) *WatchEngine {
+func NewWatchEngine(registry localregistry.LocalRegistry, pool *dispatcher.Pool) *WatchEngine {
 	return &WatchEngine{
 		instancesWatch: map[string]map[string]map[uint64]WatchContext{},
 		servicesWatch:  map[string]map[uint64]WatchContext{},
 		watchContexts:  make(map[uint64]WatchContext),
 		registry:       registry,
+		dispatcher:     pool,
 	}
 }
 
@@ -193,6 +197,7 @@ func (w *WatchEngine) notifyAllServices(
 			Type:       model.EventServices,
 		},
 		servicesListener: request.ServicesListener,
+		dispatcher:       w.dispatcher,
 	}
 	w.rwMutex.Lock()
 	w.addServiceWatchContext(nextId, request.Namespace, notifyCtx)
@@ -294,6 +299,7 @@ func (w *WatchEngine) notifyAllInstances(
 			Type:       model.EventInstances,
 		},
 		instancesListener: request.InstancesListener,
+		dispatcher:        w.dispatcher,
 	}
 	w.rwMutex.Lock()
 	w.addInstanceWatchContext(nextId, request.Namespace, request.Service, notifyCtx)
@@ -353,6 +359,7 @@ type NotifyUpdateContext struct {
 	svcEventKey       model.ServiceEventKey
 	instancesListener model.InstancesListener
 	servicesListener  model.ServicesListener
+	dispatcher        *dispatcher.Pool
 }
 
 func (l *NotifyUpdateContext) ServiceEventKey() model.ServiceEventKey {
@@ -360,21 +367,21 @@ func (l *NotifyUpdateContext) ServiceEventKey() model.ServiceEventKey {
 }
 
 func (l *NotifyUpdateContext) OnInstances(value model.ServiceInstances) {
-	go func() {
+	l.dispatcher.Dispatch(l.id, l.svcEventKey.String(), func() {
 		instancesResponse := data.BuildInstancesResponse(l.svcEventKey.ServiceKey, nil, value)
 		l.instancesListener.OnInstancesUpdate(instancesResponse)
-	}()
+	})
 }
 
 func (l *NotifyUpdateContext) OnServices(value model.Services) {
-	go func() {
+	l.dispatcher.Dispatch(l.id, l.svcEventKey.String(), func() {
 		l.servicesListener.OnServicesUpdate(&model.ServicesResponse{
 			Type:      model.EventServices,
 			Value:     value.GetValue(),
 			Revision:  value.GetRevision(),
 			HashValue: value.GetHashValue(),
 		})
-	}()
+	})
 }
 
 func (l *NotifyUpdateContext) Cancel() {
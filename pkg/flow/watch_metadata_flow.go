@@ -0,0 +1,155 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/dispatcher"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// WatchInstanceMetadata 监听服务实例的元数据变更，只在已存在实例的元数据发生变化时回调
+func (w *WatchEngine) WatchInstanceMetadata(
+	request *model.WatchInstanceMetadataRequest) (*model.WatchAllInstancesResponse, error) {
+	nextId := atomic.AddUint64(&w.indexSeed, 1)
+	svcEventKey := model.ServiceEventKey{
+		ServiceKey: request.ServiceKey,
+		Type:       model.EventInstances,
+	}
+	w.registry.WatchService(svcEventKey)
+	metaCtx := &MetadataWatchContext{
+		id:          nextId,
+		svcEventKey: svcEventKey,
+		keys:        request.Keys,
+		listener:    request.MetadataListener,
+		dispatcher:  w.dispatcher,
+	}
+	w.rwMutex.Lock()
+	w.addInstanceWatchContext(nextId, request.Namespace, request.Service, metaCtx)
+	w.watchContexts[nextId] = metaCtx
+	w.rwMutex.Unlock()
+	svcInstances := w.registry.GetInstances(&request.ServiceKey, false, false)
+	if !svcInstances.IsInitialized() {
+		notifier, err := w.registry.LoadInstances(&request.ServiceKey)
+		if err != nil {
+			w.CancelWatch(nextId)
+			return nil, err
+		}
+		<-notifier.GetContext().Done()
+		if err := notifier.GetError(); err != nil {
+			w.CancelWatch(nextId)
+			return nil, err
+		}
+		svcInstances = w.registry.GetInstances(&request.ServiceKey, false, false)
+	}
+	metaCtx.seed(svcInstances)
+	instancesResponse := data.BuildInstancesResponse(request.ServiceKey, nil, svcInstances)
+	return model.NewWatchAllInstancesResponse(nextId, instancesResponse, w.CancelWatch), nil
+}
+
+// MetadataWatchContext 实例元数据变更的监听上下文，保存上一次的实例快照用于比较
+type MetadataWatchContext struct {
+	id          uint64
+	svcEventKey model.ServiceEventKey
+	keys        []string
+	listener    model.InstanceMetadataListener
+	dispatcher  *dispatcher.Pool
+	mutex       sync.Mutex
+	// instances 上一次通知时的实例，按实例ID索引
+	instances map[string]model.Instance
+}
+
+func (l *MetadataWatchContext) ServiceEventKey() model.ServiceEventKey {
+	return l.svcEventKey
+}
+
+// seed 初始化实例快照，快照已由推送事件建立时不再覆盖
+func (l *MetadataWatchContext) seed(value model.ServiceInstances) {
+	if !value.IsInitialized() {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.instances == nil {
+		l.instances = indexInstances(value)
+	}
+}
+
+func (l *MetadataWatchContext) OnInstances(value model.ServiceInstances) {
+	// 比较放在分发协程中执行，同一监听的回调按顺序执行，队列溢出丢弃的事件会合并到下一次比较中
+	l.dispatcher.Dispatch(l.id, l.svcEventKey.String(), func() {
+		event := l.diff(value)
+		if event != nil {
+			l.listener.OnInstanceMetadataChanged(event)
+		}
+	})
+}
+
+func (l *MetadataWatchContext) diff(value model.ServiceInstances) *model.InstanceMetadataEvent {
+	if !value.IsInitialized() {
+		return nil
+	}
+	current := indexInstances(value)
+	l.mutex.Lock()
+	previous := l.instances
+	l.instances = current
+	l.mutex.Unlock()
+	var changes []model.InstanceMetadataChange
+	for id, instance := range current {
+		before, ok := previous[id]
+		if !ok {
+			continue
+		}
+		changedKeys := model.DiffMetadata(before.GetMetadata(), instance.GetMetadata(), l.keys)
+		if len(changedKeys) == 0 {
+			continue
+		}
+		changes = append(changes, model.InstanceMetadataChange{
+			Instance:    instance,
+			OldMetadata: before.GetMetadata(),
+			NewMetadata: instance.GetMetadata(),
+			ChangedKeys: changedKeys,
+		})
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return &model.InstanceMetadataEvent{
+		ServiceKey: l.svcEventKey.ServiceKey,
+		Revision:   value.GetRevision(),
+		Changes:    changes,
+	}
+}
+
+func (l *MetadataWatchContext) OnServices(value model.Services) {
+}
+
+func (l *MetadataWatchContext) Cancel() {
+}
+
+func indexInstances(value model.ServiceInstances) map[string]model.Instance {
+	instances := value.GetInstances()
+	indexed := make(map[string]model.Instance, len(instances))
+	for _, instance := range instances {
+		indexed[instance.GetId()] = instance
+	}
+	return indexed
+}
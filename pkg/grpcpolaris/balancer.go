@@ -0,0 +1,169 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpcpolaris
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// dstMetadataKey context中保存目标实例元数据的key
+type dstMetadataKey struct{}
+
+// NewDstMetadataContext 设置本次调用的目标实例元数据，用于元数据路由
+func NewDstMetadataContext(ctx context.Context, dstMetadata map[string]string) context.Context {
+	return context.WithValue(ctx, dstMetadataKey{}, dstMetadata)
+}
+
+// NewBalancerBuilder 创建polaris balancer构建器，实例选择委托给SDK的路由及负载均衡插件
+func NewBalancerBuilder(consumer api.ConsumerAPI) balancer.Builder {
+	return base.NewBalancerBuilder(Name, &pickerBuilder{consumer: consumer}, base.Config{HealthCheck: true})
+}
+
+// pickerBuilder 根据可用的连接构建picker
+type pickerBuilder struct {
+	consumer api.ConsumerAPI
+}
+
+// Build 构建picker
+func (b *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	p := &picker{
+		consumer: b.consumer,
+		subConns: make(map[string]balancer.SubConn, len(info.ReadySCs)),
+	}
+	for subConn, subConnInfo := range info.ReadySCs {
+		p.subConns[subConnInfo.Address.Addr] = subConn
+		if subConnInfo.Address.Attributes == nil {
+			continue
+		}
+		if svcKey, ok := subConnInfo.Address.Attributes.Value(serviceKeyAttr{}).(model.ServiceKey); ok {
+			p.svcKey = svcKey
+		}
+	}
+	return p
+}
+
+// picker 通过GetOneInstance选择实例，并上报调用结果
+type picker struct {
+	consumer api.ConsumerAPI
+	svcKey   model.ServiceKey
+	subConns map[string]balancer.SubConn
+}
+
+// Pick 选择本次调用使用的连接
+func (p *picker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	arguments := []model.Argument{model.BuildMethodArgument(info.FullMethodName)}
+	if md, ok := metadata.FromOutgoingContext(info.Ctx); ok {
+		for key, values := range md {
+			if len(values) > 0 {
+				arguments = append(arguments, model.BuildHeaderArgument(key, values[0]))
+			}
+		}
+	}
+	dstMetadata, _ := info.Ctx.Value(dstMetadataKey{}).(map[string]string)
+	req := &api.GetOneInstanceRequest{}
+	req.Namespace = p.svcKey.Namespace
+	req.Service = p.svcKey.Service
+	req.AddArguments(arguments...)
+	req.Metadata = dstMetadata
+	resp, err := p.consumer.GetOneInstance(req)
+	if err != nil {
+		return balancer.PickResult{}, status.Errorf(codes.Unavailable, "polaris pick instance fail: %v", err)
+	}
+	inst := resp.GetInstance()
+	subConn, ok := p.subConns[instanceAddr(inst)]
+	if !ok {
+		// 选中的实例尚无READY的连接，picker只会在连接状态变化时重建，返回ErrNoSubConnAvailable可能一直阻塞，
+		// 因此在路由后的实例中重新选择有READY连接的实例，均没有时直接失败
+		inst, subConn, err = p.pickReady(arguments, dstMetadata)
+		if err != nil {
+			return balancer.PickResult{}, err
+		}
+	}
+	start := time.Now()
+	return balancer.PickResult{
+		SubConn: subConn,
+		Done: func(doneInfo balancer.DoneInfo) {
+			p.report(inst, info.FullMethodName, time.Since(start), doneInfo.Err)
+		},
+	}, nil
+}
+
+// pickReady 在路由后的实例中随机选择一个有READY连接的实例
+func (p *picker) pickReady(arguments []model.Argument,
+	dstMetadata map[string]string) (model.Instance, balancer.SubConn, error) {
+	req := &api.GetInstancesRequest{}
+	req.Namespace = p.svcKey.Namespace
+	req.Service = p.svcKey.Service
+	req.AddArguments(arguments...)
+	req.Metadata = dstMetadata
+	resp, err := p.consumer.GetInstances(req)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Unavailable, "polaris pick instance fail: %v", err)
+	}
+	instances := resp.GetInstances()
+	var offset int
+	if len(instances) > 0 {
+		offset = rand.Intn(len(instances))
+	}
+	for i := range instances {
+		inst := instances[(offset+i)%len(instances)]
+		if subConn, ok := p.subConns[instanceAddr(inst)]; ok {
+			return inst, subConn, nil
+		}
+	}
+	return nil, nil, status.Errorf(codes.Unavailable,
+		"polaris pick instance fail: no ready connection for routed instances of %s", p.svcKey)
+}
+
+// instanceAddr 实例对应的连接地址
+func instanceAddr(inst model.Instance) string {
+	return net.JoinHostPort(inst.GetHost(), strconv.Itoa(int(inst.GetPort())))
+}
+
+// report 上报服务调用结果，用于熔断及统计
+func (p *picker) report(inst model.Instance, method string, delay time.Duration, err error) {
+	code := status.Code(err)
+	retStatus := model.RetSuccess
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+		retStatus = model.RetFail
+	}
+	result := &api.ServiceCallResult{}
+	result.SetCalledInstance(inst)
+	result.SetMethod(method)
+	result.SetDelay(delay)
+	result.SetRetStatus(retStatus)
+	result.SetRetCode(int32(code))
+	_ = p.consumer.UpdateServiceCallResult(result)
+}
@@ -0,0 +1,139 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpcpolaris
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// fakeInstance 只提供地址信息的实例
+type fakeInstance struct {
+	model.Instance
+	host string
+	port uint32
+}
+
+func (f *fakeInstance) GetHost() string {
+	return f.host
+}
+
+func (f *fakeInstance) GetPort() uint32 {
+	return f.port
+}
+
+// fakeSubConn 测试用的连接
+type fakeSubConn struct {
+	balancer.SubConn
+	addr string
+}
+
+// fakeConsumer GetOneInstance返回selected，GetInstances返回routed
+type fakeConsumer struct {
+	api.ConsumerAPI
+	selected model.Instance
+	routed   []model.Instance
+	err      error
+}
+
+func (f *fakeConsumer) GetOneInstance(req *api.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := &model.OneInstanceResponse{}
+	resp.Instances = []model.Instance{f.selected}
+	return resp, nil
+}
+
+func (f *fakeConsumer) GetInstances(req *api.GetInstancesRequest) (*model.InstancesResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &model.InstancesResponse{Instances: f.routed}, nil
+}
+
+// TestPickerPick 测试选中实例的连接未就绪时在有READY连接的实例中重新选择，均未就绪时快速失败
+func TestPickerPick(t *testing.T) {
+	instA := &fakeInstance{host: "127.0.0.1", port: 8080}
+	instB := &fakeInstance{host: "127.0.0.2", port: 8080}
+	instC := &fakeInstance{host: "127.0.0.3", port: 8080}
+	tests := []struct {
+		name     string
+		consumer *fakeConsumer
+		ready    []string
+		wantAddr string
+		wantCode codes.Code
+	}{
+		{
+			name:     "选中的实例连接已就绪",
+			consumer: &fakeConsumer{selected: instA, routed: []model.Instance{instA, instB}},
+			ready:    []string{"127.0.0.1:8080", "127.0.0.2:8080"},
+			wantAddr: "127.0.0.1:8080",
+		},
+		{
+			name:     "选中的实例连接未就绪时重新选择",
+			consumer: &fakeConsumer{selected: instA, routed: []model.Instance{instA, instB, instC}},
+			ready:    []string{"127.0.0.2:8080"},
+			wantAddr: "127.0.0.2:8080",
+		},
+		{
+			name:     "路由后的实例均未就绪",
+			consumer: &fakeConsumer{selected: instA, routed: []model.Instance{instA, instC}},
+			ready:    []string{"127.0.0.2:8080"},
+			wantCode: codes.Unavailable,
+		},
+		{
+			name:     "获取实例失败",
+			consumer: &fakeConsumer{err: errors.New("no instance")},
+			ready:    []string{"127.0.0.1:8080"},
+			wantCode: codes.Unavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &picker{
+				consumer: tt.consumer,
+				svcKey:   model.ServiceKey{Namespace: "Test", Service: "svc"},
+				subConns: make(map[string]balancer.SubConn, len(tt.ready)),
+			}
+			for _, addr := range tt.ready {
+				p.subConns[addr] = &fakeSubConn{addr: addr}
+			}
+			// 多次选择，覆盖重新选择时的随机起点
+			for i := 0; i < 10; i++ {
+				result, err := p.Pick(balancer.PickInfo{FullMethodName: "/svc/Method", Ctx: context.Background()})
+				if tt.wantCode != codes.OK {
+					assert.NotEqual(t, balancer.ErrNoSubConnAvailable, err)
+					assert.Equal(t, tt.wantCode, status.Code(err))
+					continue
+				}
+				assert.Nil(t, err)
+				assert.Equal(t, tt.wantAddr, result.SubConn.(*fakeSubConn).addr)
+			}
+		})
+	}
+}
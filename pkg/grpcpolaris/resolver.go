@@ -0,0 +1,156 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package grpcpolaris 提供基于polaris服务发现的gRPC resolver与balancer
+package grpcpolaris
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/resolver"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// Scheme polaris resolver的scheme，使用方式 polaris://namespace/service
+	Scheme = "polaris"
+	// Name polaris balancer的名称
+	Name = "polaris"
+	// DefaultNamespace 目标地址未指定命名空间时使用的命名空间
+	DefaultNamespace = "default"
+
+	serviceConfig = `{"loadBalancingConfig":[{"` + Name + `":{}}]}`
+)
+
+// serviceKeyAttr 地址属性中保存服务标识的key
+type serviceKeyAttr struct{}
+
+// Register 向gRPC注册polaris resolver及balancer，注册后可直接通过 grpc.Dial("polaris://namespace/service") 访问服务
+func Register(consumer api.ConsumerAPI) {
+	resolver.Register(NewResolverBuilder(consumer))
+	balancer.Register(NewBalancerBuilder(consumer))
+}
+
+// resolverBuilder polaris resolver构建器
+type resolverBuilder struct {
+	consumer api.ConsumerAPI
+}
+
+// NewResolverBuilder 创建polaris resolver构建器，可通过 grpc.WithResolvers 单独使用
+func NewResolverBuilder(consumer api.ConsumerAPI) resolver.Builder {
+	return &resolverBuilder{consumer: consumer}
+}
+
+// Scheme 返回resolver的scheme
+func (b *resolverBuilder) Scheme() string {
+	return Scheme
+}
+
+// Build 构建resolver，并监听服务实例的变更
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn,
+	opts resolver.BuildOptions) (resolver.Resolver, error) {
+	svcKey, err := parseTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	r := &polarisResolver{
+		cc:     cc,
+		svcKey: svcKey,
+	}
+	resp, err := b.consumer.WatchAllInstances(&api.WatchAllInstancesRequest{
+		WatchAllInstancesRequest: model.WatchAllInstancesRequest{
+			ServiceKey:        svcKey,
+			WatchMode:         api.WatchModeNotify,
+			InstancesListener: r,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.watchResp = resp
+	r.OnInstancesUpdate(resp.InstancesResponse())
+	return r, nil
+}
+
+// parseTarget 解析 polaris://namespace/service 格式的目标地址
+func parseTarget(target resolver.Target) (model.ServiceKey, error) {
+	namespace := target.Authority
+	if len(namespace) == 0 {
+		namespace = DefaultNamespace
+	}
+	service := strings.TrimPrefix(target.Endpoint, "/")
+	if len(service) == 0 {
+		return model.ServiceKey{}, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"invalid grpc target %s, service is empty", target.Endpoint)
+	}
+	return model.ServiceKey{Namespace: namespace, Service: service}, nil
+}
+
+// polarisResolver 将polaris的服务实例同步给gRPC
+type polarisResolver struct {
+	mutex     sync.Mutex
+	cc        resolver.ClientConn
+	svcKey    model.ServiceKey
+	watchResp *model.WatchAllInstancesResponse
+}
+
+// OnInstancesUpdate 服务实例变更时刷新gRPC的地址列表
+func (r *polarisResolver) OnInstancesUpdate(resp *model.InstancesResponse) {
+	if resp == nil {
+		return
+	}
+	addresses := make([]resolver.Address, 0, len(resp.GetInstances()))
+	for _, inst := range resp.GetInstances() {
+		// 隔离的实例不参与负载均衡，其余实例的健康状态由路由插件判断
+		if inst.IsIsolated() {
+			continue
+		}
+		addresses = append(addresses, resolver.Address{
+			Addr:       net.JoinHostPort(inst.GetHost(), strconv.Itoa(int(inst.GetPort()))),
+			ServerName: r.svcKey.Service,
+			Attributes: attributes.New(serviceKeyAttr{}, r.svcKey),
+		})
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	state := resolver.State{
+		Addresses:     addresses,
+		ServiceConfig: r.cc.ParseServiceConfig(serviceConfig),
+	}
+	if err := r.cc.UpdateState(state); err != nil {
+		r.cc.ReportError(fmt.Errorf("fail to update state for %s: %v", r.svcKey, err))
+	}
+}
+
+// ResolveNow 实例变更由polaris推送，无需主动解析
+func (r *polarisResolver) ResolveNow(resolver.ResolveNowOptions) {
+}
+
+// Close 取消服务实例监听
+func (r *polarisResolver) Close() {
+	if r.watchResp != nil {
+		r.watchResp.CancelWatch()
+	}
+}
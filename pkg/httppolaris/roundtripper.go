@@ -0,0 +1,233 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package httppolaris 提供基于polaris服务治理能力的 http.RoundTripper
+package httppolaris
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// DefaultNamespace 未指定命名空间时使用的命名空间
+const DefaultNamespace = "default"
+
+// RoundTripper 将请求的host作为服务名，通过ConsumerAPI执行路由及负载均衡后发送请求，
+// 并上报调用结果用于熔断，连接失败或幂等请求出现网络错误时会排除已调用过的实例重新选择
+type RoundTripper struct {
+	// Consumer 服务发现使用的ConsumerAPI
+	Consumer api.ConsumerAPI
+	// Namespace 被调服务所在的命名空间，为空时使用default
+	Namespace string
+	// Next 实际发送请求的RoundTripper，为空时使用 http.DefaultTransport
+	Next http.RoundTripper
+	// MaxRetries 调用失败时更换实例的最大重试次数
+	MaxRetries int
+}
+
+// NewRoundTripper 创建RoundTripper
+func NewRoundTripper(consumer api.ConsumerAPI, namespace string) *RoundTripper {
+	return &RoundTripper{
+		Consumer:   consumer,
+		Namespace:  namespace,
+		MaxRetries: 1,
+	}
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	maxRetries := t.MaxRetries
+	if req.Body != nil && req.GetBody == nil {
+		// 请求体无法重放，不进行重试
+		maxRetries = 0
+	}
+	instances, err := t.getInstances(req)
+	if err != nil {
+		return nil, err
+	}
+	tried := make(map[string]struct{})
+	var lastErr error
+	for i := 0; i <= maxRetries; i++ {
+		inst, err := t.selectUntriedInstance(instances, tried)
+		if err != nil {
+			if lastErr != nil {
+				// 可用实例都已尝试过，返回最后一次调用的错误
+				return nil, lastErr
+			}
+			return nil, err
+		}
+		tried[inst.GetId()] = struct{}{}
+		resp, retry, err := t.roundTripOnce(req, inst, i > 0)
+		if !retry {
+			return resp, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// roundTripOnce 向指定实例发送请求，返回是否需要更换实例重试
+func (t *RoundTripper) roundTripOnce(req *http.Request, inst model.Instance,
+	resetBody bool) (*http.Response, bool, error) {
+	outReq := req.Clone(req.Context())
+	if resetBody && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, false, err
+		}
+		outReq.Body = body
+	}
+	if len(outReq.Host) == 0 {
+		outReq.Host = req.URL.Host
+	}
+	outReq.URL.Host = net.JoinHostPort(inst.GetHost(), strconv.Itoa(int(inst.GetPort())))
+
+	start := time.Now()
+	resp, err := t.next().RoundTrip(outReq)
+	t.report(inst, req.URL.Path, time.Since(start), resp, err)
+	if err != nil {
+		return nil, isRetryableError(req, err), err
+	}
+	return resp, false, nil
+}
+
+// getInstances 以请求的host作为服务名，获取经过路由后的实例列表
+func (t *RoundTripper) getInstances(req *http.Request) (*model.InstancesResponse, error) {
+	getReq := &api.GetInstancesRequest{}
+	getReq.Namespace = t.Namespace
+	if len(getReq.Namespace) == 0 {
+		getReq.Namespace = DefaultNamespace
+	}
+	getReq.Service = req.URL.Hostname()
+	getReq.AddArguments(model.BuildPathArgument(req.URL.Path))
+	for key, values := range req.Header {
+		if len(values) > 0 {
+			getReq.AddArguments(model.BuildHeaderArgument(key, values[0]))
+		}
+	}
+	for key, values := range req.URL.Query() {
+		if len(values) > 0 {
+			getReq.AddArguments(model.BuildQueryArgument(key, values[0]))
+		}
+	}
+	return t.Consumer.GetInstances(getReq)
+}
+
+// selectUntriedInstance 在未调用过的实例中执行负载均衡
+func (t *RoundTripper) selectUntriedInstance(instances *model.InstancesResponse,
+	tried map[string]struct{}) (model.Instance, error) {
+	allInstances := instances.GetInstances()
+	candidates := make([]model.Instance, 0, len(allInstances))
+	for _, inst := range allInstances {
+		if _, ok := tried[inst.GetId()]; !ok {
+			candidates = append(candidates, inst)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"no untried instance available, namespace %s, service %s",
+			instances.GetNamespace(), instances.GetService())
+	}
+	svcInfo := model.ServiceInfo{
+		Namespace: instances.GetNamespace(),
+		Service:   instances.GetService(),
+		Metadata:  instances.GetMetadata(),
+	}
+	lbResp, err := t.Consumer.SDKContext().GetEngine().ProcessLoadBalance(&model.ProcessLoadBalanceRequest{
+		DstInstances: model.NewDefaultServiceInstances(svcInfo, candidates),
+	})
+	if err != nil {
+		return nil, err
+	}
+	inst := lbResp.GetInstance()
+	if inst == nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"no instance available, namespace %s, service %s", svcInfo.Namespace, svcInfo.Service)
+	}
+	return inst, nil
+}
+
+// report 上报服务调用结果，网络错误及5xx视为失败
+func (t *RoundTripper) report(inst model.Instance, method string, delay time.Duration,
+	resp *http.Response, err error) {
+	retStatus := model.RetSuccess
+	retCode := int32(-1)
+	if err != nil {
+		retStatus = model.RetFail
+	} else {
+		retCode = int32(resp.StatusCode)
+		if resp.StatusCode >= http.StatusInternalServerError {
+			retStatus = model.RetFail
+		}
+	}
+	result := &api.ServiceCallResult{}
+	result.SetCalledInstance(inst)
+	result.SetMethod(method)
+	result.SetDelay(delay)
+	result.SetRetStatus(retStatus)
+	result.SetRetCode(retCode)
+	_ = t.Consumer.UpdateServiceCallResult(result)
+}
+
+func (t *RoundTripper) next() http.RoundTripper {
+	if t.Next != nil {
+		return t.Next
+	}
+	return http.DefaultTransport
+}
+
+// isRetryableError 判断调用失败后是否可以更换实例重试：
+// 建立连接阶段的错误请求尚未发出，总是可以重试；其他网络错误仅对幂等请求重试
+func isRetryableError(req *http.Request, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if isConnectError(err) {
+		return true
+	}
+	return isIdempotent(req)
+}
+
+// isConnectError 判断是否为建立连接阶段（拨号、代理握手、TLS握手）的错误
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Op == "dial" || opErr.Op == "proxyconnect"
+	}
+	var recordErr tls.RecordHeaderError
+	return errors.As(err, &recordErr)
+}
+
+// isIdempotent 判断请求是否幂等，与 net/http 的判定保持一致
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+	_, ok := req.Header["X-Idempotency-Key"]
+	return ok
+}
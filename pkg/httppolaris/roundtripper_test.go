@@ -0,0 +1,376 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httppolaris
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// fakeEngine 负载均衡总是选择第一个候选实例
+type fakeEngine struct {
+	model.Engine
+}
+
+func (e *fakeEngine) ProcessLoadBalance(req *model.ProcessLoadBalanceRequest) (*model.OneInstanceResponse, error) {
+	instances := req.DstInstances.GetInstances()
+	return &model.OneInstanceResponse{
+		InstancesResponse: model.InstancesResponse{Instances: instances[:1]},
+	}, nil
+}
+
+type fakeContext struct {
+	api.SDKContext
+	engine *fakeEngine
+}
+
+func (c *fakeContext) GetEngine() model.Engine {
+	return c.engine
+}
+
+// fakeConsumer 返回固定的实例列表，并记录获取实例的请求及上报的调用结果
+type fakeConsumer struct {
+	api.ConsumerAPI
+	ctx       *fakeContext
+	instances []model.Instance
+	getReq    *api.GetInstancesRequest
+	results   []*api.ServiceCallResult
+}
+
+func (c *fakeConsumer) GetInstances(req *api.GetInstancesRequest) (*model.InstancesResponse, error) {
+	c.getReq = req
+	return &model.InstancesResponse{
+		ServiceInfo: model.ServiceInfo{Namespace: req.Namespace, Service: req.Service},
+		Instances:   c.instances,
+	}, nil
+}
+
+func (c *fakeConsumer) SDKContext() api.SDKContext {
+	return c.ctx
+}
+
+func (c *fakeConsumer) UpdateServiceCallResult(req *api.ServiceCallResult) error {
+	c.results = append(c.results, req)
+	return nil
+}
+
+// testBackend 测试使用的后端实例，addr为实例地址，server为空表示实例不可连接
+type testBackend struct {
+	id     string
+	addr   string
+	server *httptest.Server
+}
+
+// newEchoBackend 返回请求体的后端
+func newEchoBackend(t *testing.T, id string) *testBackend {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("X-Backend", id)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return &testBackend{id: id, addr: server.Listener.Addr().String(), server: server}
+}
+
+// newStatusBackend 总是返回指定状态码的后端
+func newStatusBackend(t *testing.T, id string, status int) *testBackend {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return &testBackend{id: id, addr: server.Listener.Addr().String(), server: server}
+}
+
+// newResetBackend 读取请求后直接断开连接的后端，请求已经发出，不属于建立连接阶段的错误
+func newResetBackend(t *testing.T, id string) *testBackend {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return &testBackend{id: id, addr: server.Listener.Addr().String(), server: server}
+}
+
+// newDownBackend 已关闭端口的后端，连接会被拒绝
+func newDownBackend(t *testing.T, id string) *testBackend {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+	return &testBackend{id: id, addr: addr}
+}
+
+func (b *testBackend) instance(t *testing.T) model.Instance {
+	host, portStr, err := net.SplitHostPort(b.addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.Atoi(portStr)
+	return pb.NewInstanceInProto(&apiservice.Instance{
+		Id:      wrapperspb.String(b.id),
+		Host:    wrapperspb.String(host),
+		Port:    wrapperspb.UInt32(uint32(port)),
+		Healthy: wrapperspb.Bool(true),
+		Weight:  wrapperspb.UInt32(100),
+	}, &model.ServiceKey{Namespace: DefaultNamespace, Service: "svc"}, nil)
+}
+
+func newTestRoundTripper(t *testing.T, backends ...*testBackend) (*RoundTripper, *fakeConsumer) {
+	consumer := &fakeConsumer{ctx: &fakeContext{engine: &fakeEngine{}}}
+	for _, backend := range backends {
+		consumer.instances = append(consumer.instances, backend.instance(t))
+	}
+	rt := NewRoundTripper(consumer, "")
+	// 每个用例使用独立的连接池，避免复用连接时标准库自身的重试影响结果
+	transport := &http.Transport{}
+	t.Cleanup(transport.CloseIdleConnections)
+	rt.Next = transport
+	return rt, consumer
+}
+
+// testReport 上报的调用结果
+type testReport struct {
+	id        string
+	retStatus model.RetStatus
+	retCode   int32
+}
+
+func reports(consumer *fakeConsumer) []testReport {
+	var result []testReport
+	for _, r := range consumer.results {
+		result = append(result, testReport{
+			id:        r.CalledInstance.GetId(),
+			retStatus: r.GetRetStatus(),
+			retCode:   r.GetRetCodeValue(),
+		})
+	}
+	return result
+}
+
+func TestIsConnectError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "拨号失败", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: true},
+		{name: "代理握手失败", err: &net.OpError{Op: "proxyconnect", Err: errors.New("connection refused")}, want: true},
+		{
+			name: "被包装的拨号错误",
+			err:  &url.Error{Op: "Get", URL: "http://svc", Err: &net.OpError{Op: "dial", Err: io.EOF}},
+			want: true,
+		},
+		{name: "TLS握手失败", err: tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, want: true},
+		{name: "读取应答失败", err: &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}},
+		{name: "连接被关闭", err: io.EOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isConnectError(tt.err))
+		})
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		header string
+		want   bool
+	}{
+		{name: "未指定方法", method: "", want: true},
+		{name: "GET", method: http.MethodGet, want: true},
+		{name: "HEAD", method: http.MethodHead, want: true},
+		{name: "OPTIONS", method: http.MethodOptions, want: true},
+		{name: "TRACE", method: http.MethodTrace, want: true},
+		{name: "POST", method: http.MethodPost},
+		{name: "PUT", method: http.MethodPut},
+		{name: "携带Idempotency-Key的POST", method: http.MethodPost, header: "Idempotency-Key", want: true},
+		{name: "携带X-Idempotency-Key的POST", method: http.MethodPost, header: "X-Idempotency-Key", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &http.Request{Method: tt.method, Header: http.Header{}}
+			if tt.header != "" {
+				req.Header[tt.header] = []string{"key"}
+			}
+			assert.Equal(t, tt.want, isIdempotent(req))
+		})
+	}
+}
+
+func TestRoundTripRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		backends func(t *testing.T) []*testBackend
+		// 请求体无法重放
+		streamBody bool
+		wantErr    bool
+		// 期望返回应答的实例及状态码
+		wantBackend string
+		wantStatus  int
+		wantReports []testReport
+	}{
+		{
+			name:   "连接失败时更换实例重试，非幂等请求同样重试并重放请求体",
+			method: http.MethodPost,
+			backends: func(t *testing.T) []*testBackend {
+				return []*testBackend{newDownBackend(t, "down"), newEchoBackend(t, "up")}
+			},
+			wantBackend: "up",
+			wantStatus:  http.StatusOK,
+			wantReports: []testReport{
+				{id: "down", retStatus: model.RetFail, retCode: -1},
+				{id: "up", retStatus: model.RetSuccess, retCode: http.StatusOK},
+			},
+		},
+		{
+			name:       "请求体无法重放时不重试",
+			method:     http.MethodPost,
+			streamBody: true,
+			backends: func(t *testing.T) []*testBackend {
+				return []*testBackend{newDownBackend(t, "down"), newEchoBackend(t, "up")}
+			},
+			wantErr:     true,
+			wantReports: []testReport{{id: "down", retStatus: model.RetFail, retCode: -1}},
+		},
+		{
+			name:   "请求发出后的网络错误对幂等请求重试",
+			method: http.MethodGet,
+			backends: func(t *testing.T) []*testBackend {
+				return []*testBackend{newResetBackend(t, "reset"), newEchoBackend(t, "up")}
+			},
+			wantBackend: "up",
+			wantStatus:  http.StatusOK,
+			wantReports: []testReport{
+				{id: "reset", retStatus: model.RetFail, retCode: -1},
+				{id: "up", retStatus: model.RetSuccess, retCode: http.StatusOK},
+			},
+		},
+		{
+			name:   "请求发出后的网络错误对非幂等请求不重试",
+			method: http.MethodPost,
+			backends: func(t *testing.T) []*testBackend {
+				return []*testBackend{newResetBackend(t, "reset"), newEchoBackend(t, "up")}
+			},
+			wantErr:     true,
+			wantReports: []testReport{{id: "reset", retStatus: model.RetFail, retCode: -1}},
+		},
+		{
+			name:   "5xx应答直接返回并上报失败",
+			method: http.MethodGet,
+			backends: func(t *testing.T) []*testBackend {
+				return []*testBackend{newStatusBackend(t, "error", http.StatusServiceUnavailable), newEchoBackend(t, "up")}
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantReports: []testReport{
+				{id: "error", retStatus: model.RetFail, retCode: http.StatusServiceUnavailable},
+			},
+		},
+		{
+			name:   "4xx应答上报成功",
+			method: http.MethodGet,
+			backends: func(t *testing.T) []*testBackend {
+				return []*testBackend{newStatusBackend(t, "notfound", http.StatusNotFound)}
+			},
+			wantStatus: http.StatusNotFound,
+			wantReports: []testReport{
+				{id: "notfound", retStatus: model.RetSuccess, retCode: http.StatusNotFound},
+			},
+		},
+		{
+			name:   "重试次数用尽后返回最后一次调用的错误",
+			method: http.MethodGet,
+			backends: func(t *testing.T) []*testBackend {
+				return []*testBackend{newDownBackend(t, "a"), newDownBackend(t, "b"), newEchoBackend(t, "up")}
+			},
+			wantErr: true,
+			wantReports: []testReport{
+				{id: "a", retStatus: model.RetFail, retCode: -1},
+				{id: "b", retStatus: model.RetFail, retCode: -1},
+			},
+		},
+		{
+			name:   "所有实例都调用过后返回最后一次调用的错误",
+			method: http.MethodGet,
+			backends: func(t *testing.T) []*testBackend {
+				return []*testBackend{newDownBackend(t, "a")}
+			},
+			wantErr:     true,
+			wantReports: []testReport{{id: "a", retStatus: model.RetFail, retCode: -1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, consumer := newTestRoundTripper(t, tt.backends(t)...)
+			payload := []byte("hello polaris")
+			req, err := http.NewRequest(tt.method, "http://svc/echo?user=1", bytes.NewReader(payload))
+			assert.Nil(t, err)
+			if tt.streamBody {
+				req.Body = ioutil.NopCloser(bytes.NewReader(payload))
+				req.GetBody = nil
+			}
+			req.Header.Set("X-Env", "test")
+
+			resp, err := rt.RoundTrip(req)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				assert.Nil(t, resp)
+			} else {
+				assert.Nil(t, err)
+				defer resp.Body.Close()
+				assert.Equal(t, tt.wantStatus, resp.StatusCode)
+				if tt.wantBackend != "" {
+					body, _ := ioutil.ReadAll(resp.Body)
+					assert.Equal(t, tt.wantBackend, resp.Header.Get("X-Backend"))
+					assert.Equal(t, payload, body)
+				}
+			}
+			assert.Equal(t, tt.wantReports, reports(consumer))
+			for _, result := range consumer.results {
+				assert.Equal(t, "/echo", result.GetMethod())
+			}
+			// 以请求的host作为服务名，路径、请求头及查询参数作为路由参数
+			assert.Equal(t, DefaultNamespace, consumer.getReq.Namespace)
+			assert.Equal(t, "svc", consumer.getReq.Service)
+			assert.Equal(t, 3, len(consumer.getReq.Arguments))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package log
+
+// discardLogger 丢弃所有日志的日志对象
+type discardLogger struct{}
+
+func (discardLogger) Tracef(format string, args ...interface{}) {}
+func (discardLogger) Debugf(format string, args ...interface{}) {}
+func (discardLogger) Infof(format string, args ...interface{})  {}
+func (discardLogger) Warnf(format string, args ...interface{})  {}
+func (discardLogger) Errorf(format string, args ...interface{}) {}
+func (discardLogger) Fatalf(format string, args ...interface{}) {}
+func (discardLogger) IsLevelEnabled(l int) bool                 { return false }
+func (discardLogger) SetLogLevel(l int) error                   { return nil }
+
+// NewDiscardLogger 创建丢弃所有日志的日志对象
+func NewDiscardLogger() Logger {
+	return discardLogger{}
+}
+
+// DiscardAllLoggers 将所有全局日志对象设置为丢弃日志，用于单元测试中未初始化日志插件的场景
+func DiscardAllLoggers() {
+	logger := NewDiscardLogger()
+	SetBaseLogger(logger)
+	SetStatLogger(logger)
+	SetStatReportLogger(logger)
+	SetDetectLogger(logger)
+	SetNetworkLogger(logger)
+	SetCacheLogger(logger)
+}
@@ -0,0 +1,160 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/modern-go/reflect2"
+)
+
+// noneLogName 禁止日志级别的名称
+const noneLogName = "NONE"
+
+// loggerModules 日志模块名到日志对象类型的映射
+var loggerModules = map[string]int{
+	baseLoggerName:       BaseLogger,
+	statLoggerName:       StatLogger,
+	statReportLoggerName: StatReportLogger,
+	detectLoggerName:     DetectLogger,
+	networkLoggerName:    NetworkLogger,
+	cacheLoggerName:      CacheLogger,
+}
+
+// getLogger 按日志对象类型获取日志对象
+func (c *container) getLogger(index int) Logger {
+	value := c.loggers[index].Load()
+	if reflect2.IsNil(value) {
+		return nil
+	}
+	return *(value.(*Logger))
+}
+
+// ParseLogLevel 解析日志级别，支持级别名称(不区分大小写，warn 等同于 warning)及数字
+func ParseLogLevel(value string) (int, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if level, err := strconv.Atoi(value); err == nil {
+		if err = VerifyLogLevel(level); err != nil {
+			return 0, err
+		}
+		return level, nil
+	}
+	if value == "WARN" {
+		return WarnLog, nil
+	}
+	if value == noneLogName {
+		return NoneLog, nil
+	}
+	for level, name := range SeverityName {
+		if name == value {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %s", value)
+}
+
+// LogLevelName 获取日志级别的名称
+func LogLevelName(level int) string {
+	if level >= 0 && level < len(SeverityName) {
+		return SeverityName[level]
+	}
+	return noneLogName
+}
+
+// GetLogLevel 获取日志对象当前生效的最低打印级别
+func GetLogLevel(logger Logger) int {
+	for level := minLogLevel; level < maxLogLevel; level++ {
+		if logger.IsLevelEnabled(level) {
+			return level
+		}
+	}
+	return NoneLog
+}
+
+// GetLoggerModules 获取所有日志模块名
+func GetLoggerModules() []string {
+	modules := make([]string, 0, len(loggerModules))
+	for module := range loggerModules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// SetModuleLogLevel 运行时调整指定日志模块的打印级别
+func SetModuleLogLevel(module string, level int) error {
+	index, ok := loggerModules[module]
+	if !ok {
+		return fmt.Errorf("unknown logger module %s, available modules are %v", module, GetLoggerModules())
+	}
+	logger := logContainer.getLogger(index)
+	if logger == nil {
+		return fmt.Errorf("logger module %s not configured", module)
+	}
+	return logger.SetLogLevel(level)
+}
+
+// GetModuleLogLevels 获取各日志模块当前生效的打印级别
+func GetModuleLogLevels() map[string]string {
+	levels := make(map[string]string, len(loggerModules))
+	for module, index := range loggerModules {
+		if logger := logContainer.getLogger(index); logger != nil {
+			levels[module] = LogLevelName(GetLogLevel(logger))
+		}
+	}
+	return levels
+}
+
+// ApplyModuleLogLevels 按文本内容批量调整日志模块的打印级别，每行格式为 module=level 或 module: level，
+// 以 # 开头的行为注释，module 为 * 时对所有模块生效
+func ApplyModuleLogLevels(content string) error {
+	var errs error
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sep := strings.IndexAny(line, "=:")
+		if sep <= 0 {
+			errs = multierror.Append(errs, fmt.Errorf("invalid log level line %q", line))
+			continue
+		}
+		module := strings.TrimSpace(line[:sep])
+		level, err := ParseLogLevel(line[sep+1:])
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		modules := []string{module}
+		if module == "*" {
+			modules = GetLoggerModules()
+		}
+		for _, m := range modules {
+			if err = SetModuleLogLevel(m, level); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+	}
+	return errs
+}
@@ -31,6 +31,8 @@ import (
 const (
 	// LoggerZap zap实现的logger
 	LoggerZap = "zaplog"
+	// LoggerSlog 标准库slog实现的logger，需要go1.21及以上版本
+	LoggerSlog = "sloglog"
 )
 
 // Logger logger object
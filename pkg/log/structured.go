@@ -0,0 +1,85 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StructuredLogger 支持结构化字段的日志对象
+type StructuredLogger interface {
+	Logger
+	// Logw 打印带结构化字段的日志，keyvals 为交替出现的键与值
+	Logw(level int, msg string, keyvals ...interface{})
+	// With 返回附带固定字段的日志对象，与原对象共享日志级别
+	With(keyvals ...interface{}) StructuredLogger
+}
+
+// Logw 使用结构化字段打印日志，日志对象不支持结构化字段时，字段以 key=value 的形式追加到消息之后
+func Logw(logger Logger, level int, msg string, keyvals ...interface{}) {
+	if logger == nil || !logger.IsLevelEnabled(level) {
+		return
+	}
+	if structured, ok := logger.(StructuredLogger); ok {
+		structured.Logw(level, msg, keyvals...)
+		return
+	}
+	line := msg
+	if len(keyvals) > 0 {
+		line = msg + " " + FormatKeyValues(keyvals...)
+	}
+	switch level {
+	case TraceLog:
+		logger.Tracef("%s", line)
+	case DebugLog:
+		logger.Debugf("%s", line)
+	case InfoLog:
+		logger.Infof("%s", line)
+	case WarnLog:
+		logger.Warnf("%s", line)
+	case ErrorLog:
+		logger.Errorf("%s", line)
+	case FatalLog:
+		logger.Fatalf("%s", line)
+	}
+}
+
+// With 返回附带固定字段的日志对象，日志对象不支持结构化字段时返回原对象
+func With(logger Logger, keyvals ...interface{}) Logger {
+	if structured, ok := logger.(StructuredLogger); ok {
+		return structured.With(keyvals...)
+	}
+	return logger
+}
+
+// FormatKeyValues 将键值对格式化为 key=value 形式，键值对数量为奇数时最后一个值的键为 !BADKEY
+func FormatKeyValues(keyvals ...interface{}) string {
+	builder := strings.Builder{}
+	for i := 0; i < len(keyvals); i += 2 {
+		if i > 0 {
+			builder.WriteByte(' ')
+		}
+		if i+1 >= len(keyvals) {
+			builder.WriteString(fmt.Sprintf("!BADKEY=%v", keyvals[i]))
+			break
+		}
+		builder.WriteString(fmt.Sprintf("%v=%v", keyvals[i], keyvals[i+1]))
+	}
+	return builder.String()
+}
@@ -0,0 +1,85 @@
+//go:build linux
+// +build linux
+
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package metric
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CPUReader 基于/proc/stat计算两次采样间的CPU使用率
+type CPUReader struct {
+	lastTotal uint64
+	lastIdle  uint64
+}
+
+// NewCPUReader 创建CPU使用率读取器
+func NewCPUReader() *CPUReader {
+	return &CPUReader{}
+}
+
+// Read 返回距上次采样的CPU使用率，千分比，首次调用仅记录基准值并返回false
+func (r *CPUReader) Read() (int64, bool) {
+	total, idle, ok := readProcStat()
+	if !ok {
+		return 0, false
+	}
+	lastTotal, lastIdle := r.lastTotal, r.lastIdle
+	r.lastTotal, r.lastIdle = total, idle
+	if lastTotal == 0 || total <= lastTotal || idle < lastIdle {
+		return 0, false
+	}
+	deltaTotal := total - lastTotal
+	deltaIdle := idle - lastIdle
+	return int64((deltaTotal - deltaIdle) * 1000 / deltaTotal), true
+}
+
+// readProcStat 读取/proc/stat中的总CPU时间和空闲时间
+func readProcStat() (uint64, uint64, bool) {
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, 0, false
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return 0, 0, false
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, false
+	}
+	var total, idle uint64
+	for i, field := range fields[1:] {
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		total += value
+		// idle及iowait
+		if i == 3 || i == 4 {
+			idle += value
+		}
+	}
+	return total, idle, true
+}
@@ -0,0 +1,34 @@
+//go:build !linux
+// +build !linux
+
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package metric
+
+// CPUReader 非linux系统暂不支持采集CPU使用率，此时依赖CPU使用率的能力不会生效
+type CPUReader struct{}
+
+// NewCPUReader 创建CPU使用率读取器
+func NewCPUReader() *CPUReader {
+	return &CPUReader{}
+}
+
+// Read 返回CPU使用率，千分比
+func (r *CPUReader) Read() (int64, bool) {
+	return 0, false
+}
@@ -0,0 +1,112 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+// BatchRegisterRequest 批量注册实例请求，适用于暴露多个端口或协议的服务
+type BatchRegisterRequest struct {
+	// Instances 需要注册的实例列表，AutoHeartbeat为true的实例由SDK维护心跳
+	Instances []*InstanceRegisterRequest
+	// Concurrency 可选，并发请求数，默认16
+	Concurrency int
+}
+
+// Validate 校验请求，单个实例的校验错误记录在对应的结果中
+func (r *BatchRegisterRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "BatchRegisterRequest can not be nil")
+	}
+	if len(r.Instances) == 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "BatchRegisterRequest: instances can not be empty")
+	}
+	if r.Concurrency < 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "BatchRegisterRequest: concurrency can not be negative")
+	}
+	return nil
+}
+
+// InstanceRegisterResult 单个实例的注册结果
+type InstanceRegisterResult struct {
+	// Instance 注册请求
+	Instance *InstanceRegisterRequest
+	// Response 注册应答，失败时为nil
+	Response *InstanceRegisterResponse
+	// Err 注册失败的原因
+	Err error
+}
+
+// BatchRegisterResponse 批量注册结果，与请求中的实例一一对应
+type BatchRegisterResponse struct {
+	Results []*InstanceRegisterResult
+}
+
+// Failed 获取注册失败的结果
+func (r *BatchRegisterResponse) Failed() []*InstanceRegisterResult {
+	var failed []*InstanceRegisterResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// BatchHeartbeatRequest 批量心跳上报请求
+type BatchHeartbeatRequest struct {
+	// Instances 需要上报心跳的实例列表
+	Instances []*InstanceHeartbeatRequest
+	// Concurrency 可选，并发请求数，默认16
+	Concurrency int
+}
+
+// Validate 校验请求，单个实例的校验错误记录在对应的结果中
+func (r *BatchHeartbeatRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "BatchHeartbeatRequest can not be nil")
+	}
+	if len(r.Instances) == 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "BatchHeartbeatRequest: instances can not be empty")
+	}
+	if r.Concurrency < 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "BatchHeartbeatRequest: concurrency can not be negative")
+	}
+	return nil
+}
+
+// InstanceHeartbeatResult 单个实例的心跳结果
+type InstanceHeartbeatResult struct {
+	// Instance 心跳请求
+	Instance *InstanceHeartbeatRequest
+	// Err 心跳失败的原因
+	Err error
+}
+
+// BatchHeartbeatResponse 批量心跳结果，与请求中的实例一一对应
+type BatchHeartbeatResponse struct {
+	Results []*InstanceHeartbeatResult
+}
+
+// Failed 获取心跳失败的结果
+func (r *BatchHeartbeatResponse) Failed() []*InstanceHeartbeatResult {
+	var failed []*InstanceHeartbeatResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
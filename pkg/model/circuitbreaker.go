@@ -199,6 +199,12 @@ type CircuitBreakerStatus interface {
 	SetFallbackInfo(*FallbackInfo)
 }
 
+// CircuitBreakerStatusListener 熔断状态监听器，用于观测完整的熔断状态机
+type CircuitBreakerStatusListener interface {
+	// OnStatusChange 资源熔断状态变更时回调，半开状态下放量比例的变化也会回调
+	OnStatusChange(resource Resource, previous CircuitBreakerStatus, current CircuitBreakerStatus)
+}
+
 // CircuitBreakerStatusWrapper 上方熔断管理器的包装，用于存入 atomic.Value
 type CircuitBreakerStatusWrapper struct {
 	Val CircuitBreakerStatus
@@ -280,6 +286,9 @@ type CustomerFunction func(ctx context.Context, args interface{}) (interface{},
 
 type DecoratorFunction func(ctx context.Context, args interface{}) (interface{}, *CallAborted, error)
 
+// FallbackFunction 资源被熔断时执行的降级逻辑，入参为熔断检查结果
+type FallbackFunction func(result *CheckResult) error
+
 type FallbackInfo struct {
 	Code    int
 	Headers map[string]string
@@ -424,11 +433,19 @@ func (c *BaseCircuitBreakerStatus) IsAvailable() bool {
 
 type HalfOpenStatus struct {
 	BaseCircuitBreakerStatus
-	maxRequest   int
-	scheduled    int32
-	calledResult []bool
-	triggered    bool
-	lock         sync.Mutex
+	maxRequest  int
+	scheduled   int32
+	calledCount int
+	failed      bool
+	triggered   bool
+	// ramp 逐级放量比例，为空时使用固定探测请求数
+	ramp []int
+	// rampWindow 每一级放量的观察窗口
+	rampWindow time.Duration
+	stage      int32
+	stageStart time.Time
+	allowed    uint64
+	lock       sync.Mutex
 }
 
 func NewHalfOpenStatus(name string, start time.Time, maxRequest int) CircuitBreakerStatus {
@@ -442,17 +459,105 @@ func NewHalfOpenStatus(name string, start time.Time, maxRequest int) CircuitBrea
 	}
 }
 
+// NewRampHalfOpenStatus 创建逐级放量的半开状态，按ramp比例放行流量，每一级窗口内无失败则进入下一级，
+// 最后一级窗口内无失败则恢复，任一请求失败则重新熔断
+func NewRampHalfOpenStatus(name string, start time.Time, maxRequest int,
+	ramp []int, rampWindow time.Duration) CircuitBreakerStatus {
+	if len(ramp) == 0 {
+		return NewHalfOpenStatus(name, start, maxRequest)
+	}
+	return &HalfOpenStatus{
+		BaseCircuitBreakerStatus: BaseCircuitBreakerStatus{
+			name:      name,
+			status:    HalfOpen,
+			startTime: start,
+		},
+		maxRequest: maxRequest,
+		ramp:       ramp,
+		rampWindow: rampWindow,
+		stageStart: start,
+	}
+}
+
+// Report 上报半开状态下的调用结果，返回是否需要计算下一个状态
 func (c *HalfOpenStatus) Report(success bool) bool {
+	needTrigger, _ := c.ReportWithStage(success)
+	return needTrigger
+}
+
+// ReportWithStage 同Report，本次上报使放量进入下一级时，额外返回上一级放量的状态快照，否则返回nil
+func (c *HalfOpenStatus) ReportWithStage(success bool) (bool, *HalfOpenStatus) {
+	return c.reportAt(success, time.Now())
+}
+
+func (c *HalfOpenStatus) reportAt(success bool, now time.Time) (bool, *HalfOpenStatus) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	c.calledResult = append(c.calledResult, success)
-	needTrigger := !success || (len(c.calledResult) >= c.maxRequest)
+	c.calledCount++
+	if !success {
+		c.failed = true
+	}
+	var needTrigger bool
+	var previous *HalfOpenStatus
+	if len(c.ramp) == 0 {
+		needTrigger = !success || c.calledCount >= c.maxRequest
+	} else if !success {
+		needTrigger = true
+	} else {
+		needTrigger, previous = c.advanceStage(now)
+	}
 	if needTrigger && !c.triggered {
 		c.triggered = true
+		return true, previous
+	}
+	return false, previous
+}
+
+// advanceStage 成功请求上报时调用，当前放量窗口结束时进入下一级，
+// 返回是否已完成全部放量，进入下一级时同时返回上一级放量的状态快照
+func (c *HalfOpenStatus) advanceStage(now time.Time) (bool, *HalfOpenStatus) {
+	if now.Sub(c.stageStart) < c.rampWindow {
+		return false, nil
+	}
+	stage := int(atomic.LoadInt32(&c.stage))
+	if stage >= len(c.ramp)-1 {
+		return true, nil
+	}
+	previous := c.stageSnapshot()
+	atomic.StoreInt32(&c.stage, int32(stage+1))
+	c.stageStart = now
+	return false, previous
+}
+
+// stageSnapshot 当前放量级别的只读快照，用于通知监听器变化前的状态，需要持有锁
+func (c *HalfOpenStatus) stageSnapshot() *HalfOpenStatus {
+	return &HalfOpenStatus{
+		BaseCircuitBreakerStatus: c.BaseCircuitBreakerStatus,
+		maxRequest:               c.maxRequest,
+		ramp:                     c.ramp,
+		rampWindow:               c.rampWindow,
+		stage:                    atomic.LoadInt32(&c.stage),
+		stageStart:               c.stageStart,
+	}
+}
+
+// GetRampPercent 获取当前的放量比例，未配置逐级放量时为100
+func (c *HalfOpenStatus) GetRampPercent() int {
+	if len(c.ramp) == 0 {
+		return 100
+	}
+	return c.ramp[atomic.LoadInt32(&c.stage)]
+}
+
+// Allow 按照当前放量比例判断请求是否放行
+func (c *HalfOpenStatus) Allow() bool {
+	percent := c.GetRampPercent()
+	if percent >= 100 {
 		return true
 	}
-	return false
+	n := atomic.AddUint64(&c.allowed, 1)
+	return int((n-1)%100) < percent
 }
 
 func (c *HalfOpenStatus) Schedule() bool {
@@ -466,11 +571,8 @@ func (c *HalfOpenStatus) CalNextStatus() Status {
 	if !c.triggered {
 		return HalfOpen
 	}
-
-	for _, ret := range c.calledResult {
-		if !ret {
-			return Open
-		}
+	if c.failed {
+		return Open
 	}
 	return Close
 }
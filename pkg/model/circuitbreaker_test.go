@@ -0,0 +1,105 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHalfOpenStatusRamp 测试半开状态逐级放量
+func TestHalfOpenStatusRamp(t *testing.T) {
+	start := time.Unix(1000, 0)
+	window := time.Second
+	newRamp := func() *HalfOpenStatus {
+		return NewRampHalfOpenStatus("rule", start, 3, []int{10, 50, 100}, window).(*HalfOpenStatus)
+	}
+
+	t.Run("每一级窗口结束后进入下一级，最后一级结束后恢复", func(t *testing.T) {
+		status := newRamp()
+		assert.Equal(t, 10, status.GetRampPercent())
+
+		// 窗口内的成功请求不进入下一级
+		triggered, previous := status.reportAt(true, start.Add(window/2))
+		assert.False(t, triggered)
+		assert.Nil(t, previous)
+		assert.Equal(t, 10, status.GetRampPercent())
+
+		triggered, previous = status.reportAt(true, start.Add(window))
+		assert.False(t, triggered)
+		assert.NotNil(t, previous)
+		assert.Equal(t, 10, previous.GetRampPercent())
+		assert.Equal(t, HalfOpen, previous.GetStatus())
+		assert.Equal(t, 50, status.GetRampPercent())
+
+		// 下一级窗口从进入时开始计算
+		triggered, previous = status.reportAt(true, start.Add(window+window/2))
+		assert.False(t, triggered)
+		assert.Nil(t, previous)
+
+		triggered, previous = status.reportAt(true, start.Add(2*window))
+		assert.False(t, triggered)
+		assert.Equal(t, 50, previous.GetRampPercent())
+		assert.Equal(t, 100, status.GetRampPercent())
+		assert.Equal(t, HalfOpen, status.CalNextStatus())
+
+		triggered, previous = status.reportAt(true, start.Add(3*window))
+		assert.True(t, triggered)
+		assert.Nil(t, previous)
+		assert.Equal(t, Close, status.CalNextStatus())
+
+		// 只触发一次状态计算
+		triggered, _ = status.reportAt(true, start.Add(4*window))
+		assert.False(t, triggered)
+	})
+
+	t.Run("放量过程中失败则重新熔断", func(t *testing.T) {
+		status := newRamp()
+		status.reportAt(true, start.Add(window))
+		assert.Equal(t, 50, status.GetRampPercent())
+		triggered, previous := status.reportAt(false, start.Add(window+time.Millisecond))
+		assert.True(t, triggered)
+		assert.Nil(t, previous)
+		assert.Equal(t, Open, status.CalNextStatus())
+	})
+
+	t.Run("按放量比例放行请求", func(t *testing.T) {
+		status := newRamp()
+		allowed := 0
+		for i := 0; i < 100; i++ {
+			if status.Allow() {
+				allowed++
+			}
+		}
+		assert.Equal(t, 10, allowed)
+	})
+}
+
+// TestHalfOpenStatusFixedRequests 测试未配置逐级放量时按固定探测请求数恢复
+func TestHalfOpenStatusFixedRequests(t *testing.T) {
+	status := NewRampHalfOpenStatus("rule", time.Now(), 2, nil, time.Second).(*HalfOpenStatus)
+	assert.Equal(t, 100, status.GetRampPercent())
+	assert.True(t, status.Allow())
+	triggered, previous := status.ReportWithStage(true)
+	assert.False(t, triggered)
+	assert.Nil(t, previous)
+	assert.True(t, status.Report(true))
+	assert.Equal(t, Close, status.CalNextStatus())
+}
@@ -38,6 +38,12 @@ type (
 	OnConfigFileChange func(event ConfigFileChangeEvent)
 	// OnConfigGroupChange .
 	OnConfigGroupChange func(event *ConfigGroupChangeEvent)
+	// OnConfigFileBind 配置文件变更后重新绑定结构体的回调，err非空时绑定对象保持不变
+	OnConfigFileBind func(event ConfigFileChangeEvent, err error)
+	// OnConfigFileValidate 配置文件变更校验函数，返回错误时拒绝本次变更
+	OnConfigFileValidate func(event ConfigFileChangeEvent) error
+	// OnConfigFileRollback 配置文件变更校验失败的回调，此时应用可见的配置内容保持不变
+	OnConfigFileRollback func(event ConfigFileChangeEvent, err error)
 )
 
 // ConfigFileChangeEvent 配置文件变更事件
@@ -115,8 +121,29 @@ type ConfigFile interface {
 	AddChangeListenerWithChannel() <-chan ConfigFileChangeEvent
 	// AddChangeListener 增加配置文件变更监听器
 	AddChangeListener(cb OnConfigFileChange)
+	// AddValidator 增加配置文件变更校验函数，变更内容在所有校验通过后才对应用可见并通知监听器
+	AddValidator(validator OnConfigFileValidate)
+	// AddRollbackListener 增加配置文件变更校验失败的监听器
+	AddRollbackListener(cb OnConfigFileRollback)
 	// GetPersistent 获取文件持久化数据
 	GetPersistent() Persistent
+	// GetVersion 获取当前配置内容的发布版本号
+	GetVersion() uint64
+	// GetReleaseName 获取当前配置内容命中的发布名称，命中灰度发布时为灰度发布的名称
+	GetReleaseName() string
+	// GetString 获取配置项的字符串值，多层结构的key以"."拼接，不存在时返回默认值
+	GetString(key string, defaultValue string) string
+	// GetInt 获取配置项的整数值，不存在或无法解析时返回默认值
+	GetInt(key string, defaultValue int) int
+	// GetBool 获取配置项的布尔值，不存在或无法解析时返回默认值
+	GetBool(key string, defaultValue bool) bool
+	// GetDuration 获取配置项的时长，支持"5s"格式，纯数字按毫秒解析，不存在或无法解析时返回默认值
+	GetDuration(key string, defaultValue time.Duration) time.Duration
+	// GetStringSlice 获取配置项的字符串列表，字符串值按","切分，不存在时返回默认值
+	GetStringSlice(key string, defaultValue []string) []string
+	// Bind 将配置内容按文件格式（yaml/json/properties）反序列化到target中，target必须为结构体指针
+	// 配置变更时重新绑定，并回调cb，cb可为nil
+	Bind(target interface{}, cb OnConfigFileBind) error
 }
 
 // DefaultConfigFileMetadata 默认 ConfigFileMetadata 实现类
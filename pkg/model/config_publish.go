@@ -0,0 +1,122 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"errors"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ConfigFileModifyRequest 创建或者更新配置文件的请求
+type ConfigFileModifyRequest struct {
+	Namespace string
+	FileGroup string
+	FileName  string
+	// Content 配置内容
+	Content string
+	// Comment 配置文件描述
+	Comment string
+	// ExpectedVersion 期望的当前发布版本号，不为空时与服务端当前发布版本一致才会执行，未发布过的文件版本号为0
+	ExpectedVersion *uint64
+}
+
+// SetExpectedVersion 设置期望的当前发布版本号
+func (r *ConfigFileModifyRequest) SetExpectedVersion(version uint64) {
+	r.ExpectedVersion = &version
+}
+
+// Validate 校验请求参数
+func (r *ConfigFileModifyRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ConfigFileModifyRequest can not be nil")
+	}
+	return checkConfigFileRequest(r.Namespace, r.FileGroup, r.FileName)
+}
+
+// ConfigFilePublishRequest 发布配置文件的请求
+type ConfigFilePublishRequest struct {
+	Namespace string
+	FileGroup string
+	FileName  string
+	// ReleaseName 发布名称，为空时由服务端生成
+	ReleaseName string
+	// ReleaseDescription 发布描述
+	ReleaseDescription string
+	// ExpectedVersion 期望的当前发布版本号，不为空时与服务端当前发布版本一致才会发布
+	ExpectedVersion *uint64
+}
+
+// SetExpectedVersion 设置期望的当前发布版本号
+func (r *ConfigFilePublishRequest) SetExpectedVersion(version uint64) {
+	r.ExpectedVersion = &version
+}
+
+// Validate 校验请求参数
+func (r *ConfigFilePublishRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ConfigFilePublishRequest can not be nil")
+	}
+	return checkConfigFileRequest(r.Namespace, r.FileGroup, r.FileName)
+}
+
+// ConfigFileRollbackRequest 回滚配置文件的请求
+// 服务端客户端协议不提供发布历史，回滚通过将目标版本的配置内容重新发布实现
+type ConfigFileRollbackRequest struct {
+	Namespace string
+	FileGroup string
+	FileName  string
+	// Content 回滚目标版本的配置内容
+	Content string
+	// ReleaseName 本次回滚的发布名称，为空时由服务端生成
+	ReleaseName string
+	// ReleaseDescription 本次回滚的发布描述
+	ReleaseDescription string
+	// ExpectedVersion 期望的当前发布版本号，不为空时与服务端当前发布版本一致才会回滚
+	ExpectedVersion *uint64
+}
+
+// SetExpectedVersion 设置期望的当前发布版本号
+func (r *ConfigFileRollbackRequest) SetExpectedVersion(version uint64) {
+	r.ExpectedVersion = &version
+}
+
+// Validate 校验请求参数
+func (r *ConfigFileRollbackRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ConfigFileRollbackRequest can not be nil")
+	}
+	return checkConfigFileRequest(r.Namespace, r.FileGroup, r.FileName)
+}
+
+func checkConfigFileRequest(namespace, fileGroup, fileName string) error {
+	var errs error
+	if namespace == "" {
+		errs = multierror.Append(errs, errors.New("namespace can not be empty"))
+	}
+	if fileGroup == "" {
+		errs = multierror.Append(errs, errors.New("fileGroup can not be empty"))
+	}
+	if fileName == "" {
+		errs = multierror.Append(errs, errors.New("fileName can not be empty"))
+	}
+	if errs != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, errs, "fail to validate config file request")
+	}
+	return nil
+}
@@ -43,6 +43,8 @@ const (
 	ContextKeyFinishInitTime = "SDKFinishInitTime"
 	// ContextKeySelfIP sdk bind ip
 	ContextKeySelfIP = "__sdk_bind_ip__"
+	// ContextKeyVariableResolver 路由规则变量解析链
+	ContextKeyVariableResolver = "variableResolver"
 )
 
 // SDKToken sdkContext的唯一标识
@@ -98,6 +100,8 @@ type ValueContext interface {
 	GetClientId() string
 	// GetEngine 获取引擎接口
 	GetEngine() Engine
+	// GetVariableResolver 获取路由规则变量解析链
+	GetVariableResolver() *VariableResolverChain
 	// WaitLocationInfo 等待location是否达到locationStatus
 	WaitLocationInfo(ctx context.Context, locationStatus uint32) bool
 	// SetCurrentLocation 设置当前节点地域信息
@@ -304,3 +308,30 @@ func (v *valueContext) GetEngine() Engine {
 	}
 	return value.(Engine)
 }
+
+// GetVariableResolver 获取路由规则变量解析链
+func (v *valueContext) GetVariableResolver() *VariableResolverChain {
+	value, ok := v.GetValue(ContextKeyVariableResolver)
+	if !ok {
+		return nil
+	}
+	return value.(*VariableResolverChain)
+}
+
+// callContext 请求携带的调用方ctx，嵌入到同步请求对象中，由主流程传递到等待及重试逻辑和服务端连接器
+type callContext struct {
+	ctx context.Context
+}
+
+// SetContext 设置调用方的ctx，取消或超时后中止等待远程结果及后续重试
+func (c *callContext) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// GetContext 获取调用方的ctx，未设置时返回context.Background()
+func (c *callContext) GetContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
@@ -39,6 +39,8 @@ const (
 	EventCircuitBreaker EventType = 0x2006
 	// EventFaultDetect 探测规则
 	EventFaultDetect EventType = 0x2007
+	// EventLane 泳道规则
+	EventLane EventType = 0x2008
 )
 
 // RegistryValue 存储于sdk缓存中的对象，包括服务实例和服务路由
@@ -72,6 +74,7 @@ var (
 		EventServices:       "services",
 		EventCircuitBreaker: "circuit_breaker",
 		EventFaultDetect:    "fault_detect",
+		EventLane:           "lane",
 	}
 
 	presentToEventType = map[string]EventType{
@@ -81,9 +84,22 @@ var (
 		"services":        EventServices,
 		"circuit_breaker": EventCircuitBreaker,
 		"fault_detect":    EventFaultDetect,
+		"lane":            EventLane,
 	}
 )
 
+// RegisterEventType 注册自定义的事件类型及其展示名，需要在init阶段调用
+func RegisterEventType(eventType EventType, present string) {
+	if _, ok := eventTypeToPresent[eventType]; ok {
+		panic(fmt.Sprintf("duplicate register event type %d", eventType))
+	}
+	if _, ok := presentToEventType[present]; ok {
+		panic(fmt.Sprintf("duplicate register event type present %s", present))
+	}
+	eventTypeToPresent[eventType] = present
+	presentToEventType[present] = eventType
+}
+
 // ToEventType 通过字符串构造事件类型
 func ToEventType(value string) EventType {
 	if eType, ok := presentToEventType[value]; ok {
@@ -18,6 +18,7 @@
 package model
 
 import (
+	"context"
 	"time"
 )
 
@@ -44,6 +45,18 @@ type ControlParam struct {
 	Timeout       time.Duration
 	MaxRetry      int
 	RetryInterval time.Duration
+	// 只读取本地缓存（包括持久化缓存），不等待远程加载
+	CacheOnly bool
+	// 调用方的ctx，取消或超时后中止等待远程结果及后续重试，为nil时只受Timeout及MaxRetry约束
+	Context context.Context
+}
+
+// GetContext 获取调用方的ctx，未设置时返回context.Background()
+func (c *ControlParam) GetContext() context.Context {
+	if c.Context == nil {
+		return context.Background()
+	}
+	return c.Context
 }
 
 // CacheValueQuery 缓存查询请求对象
@@ -74,6 +87,8 @@ type CacheValueQuery interface {
 type Engine interface {
 	// Destroy 销毁流程引擎
 	Destroy() error
+	// DestroyWithContext 优雅销毁流程引擎，先处理自动心跳的实例再停止后台任务，返回各环节的错误
+	DestroyWithContext(ctx context.Context) error
 	// SyncGetResources 同步加载资源，可通过配置参数指定一次同时加载多个资源
 	SyncGetResources(req CacheValueQuery) error
 	// SyncGetOneInstance 同步获取负载均衡后的服务实例
@@ -82,16 +97,30 @@ type Engine interface {
 	SyncGetInstances(req *GetInstancesRequest) (*InstancesResponse, error)
 	// SyncGetAllInstances 同步获取全量服务实例
 	SyncGetAllInstances(req *GetAllInstancesRequest) (*InstancesResponse, error)
+	// SyncIterateInstances 获取服务实例的分批迭代器
+	SyncIterateInstances(req *IterateInstancesRequest) (*InstancesIterator, error)
 	// SyncRegister 同步进行服务注册
 	SyncRegister(instance *InstanceRegisterRequest) (*InstanceRegisterResponse, error)
 	// SyncDeregister 同步进行服务反注册
 	SyncDeregister(instance *InstanceDeRegisterRequest) error
+	// SyncGracefulDeregister 同步进行优雅下线，隔离实例并等待排空后反注册
+	SyncGracefulDeregister(instance *InstanceGracefulDeregisterRequest) error
 	// SyncHeartbeat 同步进行心跳上报
 	SyncHeartbeat(instance *InstanceHeartbeatRequest) error
+	// SyncUpdateInstanceMetadata 更新已注册实例的元数据及权重
+	SyncUpdateInstanceMetadata(req *InstanceMetadataUpdateRequest) error
+	// AddLoadSampler 添加自适应权重使用的负载采样器
+	AddLoadSampler(sampler LoadSampler)
+	// SyncBatchRegister 同步并发注册多个实例
+	SyncBatchRegister(req *BatchRegisterRequest) *BatchRegisterResponse
+	// SyncBatchHeartbeat 同步并发上报多个实例的心跳
+	SyncBatchHeartbeat(req *BatchHeartbeatRequest) *BatchHeartbeatResponse
 	// SyncUpdateServiceCallResult 上报调用结果信息
 	SyncUpdateServiceCallResult(result *ServiceCallResult) error
 	// SyncReportStat 上报实例统计信息
 	SyncReportStat(typ MetricType, stat InstanceGauge) error
+	// SyncReportEvent 上报治理状态迁移事件
+	SyncReportEvent(event *GovernanceEvent) error
 	// SyncGetServiceRule 同步获取服务规则
 	SyncGetServiceRule(
 		eventType EventType, req *GetServiceRuleRequest) (*ServiceRuleResponse, error)
@@ -108,18 +137,48 @@ type Engine interface {
 	GetContext() ValueContext
 	// InitCalleeService 所需的被调初始化
 	InitCalleeService(req *InitCalleeServiceRequest) error
+	// SyncGetInstancesForHedging 获取对冲请求的主实例、备份实例及对冲延迟
+	SyncGetInstancesForHedging(req *GetInstancesForHedgingRequest) (*HedgingInstancesResponse, error)
+	// SyncGetInstancesByHashKey 沿一致性hash环获取hash key对应的多个不同实例
+	SyncGetInstancesByHashKey(req *GetInstancesByHashKeyRequest) (*HashKeyInstancesResponse, error)
+	// Prefetch 后台并发预热服务实例及规则
+	Prefetch(req *PrefetchRequest) *PrefetchResponse
+	// SyncInvokeWithRetry 选择实例并发起调用，失败时按重试策略重新选择实例重试
+	SyncInvokeWithRetry(req *InvokeWithRetryRequest) (*InvokeWithRetryResponse, error)
+	// RegisterInstanceFallback 注册服务实例全部熔断时的降级函数
+	RegisterInstanceFallback(req *RegisterFallbackRequest) error
+	// AddInterceptor 添加 SDK 调用拦截器，多个拦截器按添加顺序由外向内执行
+	AddInterceptor(interceptor Interceptor)
+	// SyncInjectFault 计算本次调用需要注入的故障
+	SyncInjectFault(req *InjectFaultRequest) (*InjectFaultResponse, error)
+	// SyncExtractTrafficLabels 从框架相关的请求对象中提取流量标签
+	SyncExtractTrafficLabels(req *ExtractTrafficLabelsRequest) (*ExtractTrafficLabelsResponse, error)
 	// SyncGetConfigFile 同步获取配置文件
 	SyncGetConfigFile(req *GetConfigFileRequest) (ConfigFile, error)
 	// SyncGetConfigGroup 同步获取配置文件
 	SyncGetConfigGroup(namespace, fileGroup string) (ConfigFileGroup, error)
 	// SyncGetConfigGroupWithReq 同步获取配置文件
 	SyncGetConfigGroupWithReq(req *GetConfigGroupRequest) (ConfigFileGroup, error)
+	// SyncGetConfigGroupFiles 同步获取配置分组下所有配置文件
+	SyncGetConfigGroupFiles(namespace, fileGroup string) ([]ConfigFile, error)
+	// WatchConfigGroup 监听配置分组内任意文件的变更，包括文件新增和删除
+	WatchConfigGroup(namespace, fileGroup string, cb OnConfigFileChange) error
+	// WatchConfigFilesByPattern 按文件名通配符监听配置分组内的文件
+	WatchConfigFilesByPattern(namespace, fileGroup, pattern string, cb OnConfigFileChange) error
 	// SyncCreateConfigFile 同步创建配置文件
 	SyncCreateConfigFile(namespace, fileGroup, fileName, content string) error
 	// SyncUpdateConfigFile 同步更新配置文件
 	SyncUpdateConfigFile(namespace, fileGroup, fileName, content string) error
 	// SyncPublishConfigFile 同步发布配置文件
 	SyncPublishConfigFile(namespace, fileGroup, fileName string) error
+	// SyncCreateConfigFileWithReq 同步创建配置文件，支持描述以及版本校验
+	SyncCreateConfigFileWithReq(req *ConfigFileModifyRequest) error
+	// SyncUpdateConfigFileWithReq 同步更新配置文件，支持描述以及版本校验
+	SyncUpdateConfigFileWithReq(req *ConfigFileModifyRequest) error
+	// SyncPublishConfigFileWithReq 同步发布配置文件，支持发布名称、发布描述以及版本校验
+	SyncPublishConfigFileWithReq(req *ConfigFilePublishRequest) error
+	// SyncRollbackConfigFile 同步回滚配置文件
+	SyncRollbackConfigFile(req *ConfigFileRollbackRequest) error
 	// ProcessRouters 执行路由链过滤，返回经过路由后的实例列表
 	ProcessRouters(req *ProcessRoutersRequest) (*InstancesResponse, error)
 	// ProcessLoadBalance 执行负载均衡策略，返回负载均衡后的实例
@@ -128,10 +187,18 @@ type Engine interface {
 	WatchAllInstances(request *WatchAllInstancesRequest) (*WatchAllInstancesResponse, error)
 	// WatchAllServices 监听服务列表变更事件
 	WatchAllServices(request *WatchAllServicesRequest) (*WatchAllServicesResponse, error)
+	// WatchInstanceMetadata 监听服务实例元数据变更事件
+	WatchInstanceMetadata(request *WatchInstanceMetadataRequest) (*WatchAllInstancesResponse, error)
 	// Check
 	Check(Resource) (*CheckResult, error)
 	// Report
 	Report(*ResourceStat) error
+	// AddCircuitBreakerStatusListener 添加熔断状态监听器
+	AddCircuitBreakerStatusListener(CircuitBreakerStatusListener) error
+	// AddSDKConfigChangedListener 添加SDK自身配置热更新监听器
+	AddSDKConfigChangedListener(SDKConfigChangedListener)
+	// DoWithCircuitBreaker 在熔断保护下执行业务逻辑，并自动上报调用结果
+	DoWithCircuitBreaker(Resource, func() error, FallbackFunction) error
 	// MakeFunctionDecorator
 	MakeFunctionDecorator(CustomerFunction, *RequestContext) DecoratorFunction
 	// MakeInvokeHandler
@@ -18,6 +18,7 @@
 package model
 
 import (
+	"context"
 	"fmt"
 
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
@@ -172,6 +173,14 @@ func NewSDKError(errCode ErrCode, cause error, msg string, args ...interface{})
 		cause:     cause}
 }
 
+// NewContextError 将调用方ctx的错误转换为SDK错误，超时对应ErrCodeAPITimeoutError，取消对应ErrCodeInvalidStateError
+func NewContextError(err error) SDKError {
+	if err == context.DeadlineExceeded {
+		return NewSDKError(ErrCodeAPITimeoutError, err, "context deadline exceeded")
+	}
+	return NewSDKError(ErrCodeInvalidStateError, err, "context canceled")
+}
+
 // NewSDKErrorWithServerInfo SDK错误相关的类构建器
 func NewSDKErrorWithServerInfo(errCode ErrCode, cause error, serverCode uint32, serverInfo string, msg string, args ...interface{}) SDKError {
 	return &sdkError{
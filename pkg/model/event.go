@@ -0,0 +1,96 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"time"
+)
+
+// GovernanceEventType 治理事件类型，用于审计流量变化的原因
+type GovernanceEventType string
+
+const (
+	// EventInstanceEjected 实例被熔断剔除
+	EventInstanceEjected GovernanceEventType = "InstanceEjected"
+	// EventInstanceRecovered 实例从熔断中恢复
+	EventInstanceRecovered GovernanceEventType = "InstanceRecovered"
+	// EventRateLimitRuleChanged 限流规则发生变更
+	EventRateLimitRuleChanged GovernanceEventType = "RateLimitRuleChanged"
+	// EventRouteRuleUpdated 路由规则版本号发生变更
+	EventRouteRuleUpdated GovernanceEventType = "RouteRuleUpdated"
+	// EventServerSwitched 与服务端的连接切换到了新的节点
+	EventServerSwitched GovernanceEventType = "ServerSwitched"
+	// EventConfigChanged SDK自身配置热更新生效
+	EventConfigChanged GovernanceEventType = "ConfigChanged"
+)
+
+// GovernanceEvent 治理状态迁移事件
+type GovernanceEvent struct {
+	// EventType 事件类型
+	EventType GovernanceEventType `json:"eventType"`
+	// EventTime 事件发生的时间
+	EventTime time.Time `json:"eventTime"`
+	// Namespace 事件关联的命名空间
+	Namespace string `json:"namespace,omitempty"`
+	// Service 事件关联的服务名
+	Service string `json:"service,omitempty"`
+	// Instance 事件关联的实例地址，格式为host:port
+	Instance string `json:"instance,omitempty"`
+	// PreviousStatus 迁移前的状态，如熔断状态、规则版本号、服务端地址
+	PreviousStatus string `json:"previousStatus,omitempty"`
+	// CurrentStatus 迁移后的状态
+	CurrentStatus string `json:"currentStatus,omitempty"`
+	// Reason 状态迁移的原因
+	Reason string `json:"reason,omitempty"`
+	// ClientID 产生事件的SDK客户端标识
+	ClientID string `json:"clientId,omitempty"`
+}
+
+// NewGovernanceEvent 创建治理事件
+func NewGovernanceEvent(eventType GovernanceEventType, svcKey *ServiceKey) *GovernanceEvent {
+	event := &GovernanceEvent{
+		EventType: eventType,
+		EventTime: time.Now(),
+	}
+	if svcKey != nil {
+		event.Namespace = svcKey.Namespace
+		event.Service = svcKey.Service
+	}
+	return event
+}
+
+// SDKConfigChange SDK自身配置项的变更
+type SDKConfigChange struct {
+	// Key 配置项路径，如 consumer.loadbalancer.type
+	Key string `json:"key"`
+	// OldValue 变更前的值
+	OldValue string `json:"oldValue"`
+	// NewValue 变更后的值
+	NewValue string `json:"newValue"`
+}
+
+// SDKConfigChangedEvent SDK自身配置热更新事件
+type SDKConfigChangedEvent struct {
+	// Source 配置来源，本地文件路径或配置中心文件
+	Source string
+	// Changes 已生效的配置项变更
+	Changes []SDKConfigChange
+}
+
+// SDKConfigChangedListener SDK自身配置热更新监听器
+type SDKConfigChangedListener func(event *SDKConfigChangedEvent)
@@ -0,0 +1,58 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package model
+
+// InstanceFallbackFunction 被调服务的实例全部被熔断时执行的降级函数，返回合成的降级应答
+type InstanceFallbackFunction func(req *GetOneInstanceRequest) (*OneInstanceResponse, error)
+
+// RegisterFallbackRequest 熔断降级函数注册请求
+type RegisterFallbackRequest struct {
+	// Namespace 必选，被调服务命名空间
+	Namespace string
+	// Service 必选，被调服务名
+	Service string
+	// Method 可选，被调方法，为空时对整个服务生效
+	Method string
+	// Fallback 降级函数，为nil时取消已注册的降级函数
+	Fallback InstanceFallbackFunction
+}
+
+// GetNamespace 获取命名空间
+func (r *RegisterFallbackRequest) GetNamespace() string {
+	return r.Namespace
+}
+
+// GetService 获取服务名
+func (r *RegisterFallbackRequest) GetService() string {
+	return r.Service
+}
+
+// GetMetadata 获取元数据，降级注册不涉及元数据
+func (r *RegisterFallbackRequest) GetMetadata() map[string]string {
+	return nil
+}
+
+// Validate 校验注册请求
+func (r *RegisterFallbackRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "RegisterFallbackRequest can not be nil")
+	}
+	if err := validateServiceMetadata("RegisterFallbackRequest", r); err != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, err, "fail to validate RegisterFallbackRequest")
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// FaultInjectionRules 故障注入规则集合，通过北极星配置中心下发
+type FaultInjectionRules struct {
+	Rules []*FaultInjectionRule `json:"rules"`
+}
+
+// FaultInjectionRule 故障注入规则
+type FaultInjectionRule struct {
+	// Name 规则名
+	Name string `json:"name"`
+	// Enable 是否启用
+	Enable bool `json:"enable"`
+	// Namespace 目标服务命名空间，为空或者*表示全部
+	Namespace string `json:"namespace"`
+	// Service 目标服务名，为空或者*表示全部
+	Service string `json:"service"`
+	// Method 目标方法，为空或者*表示全部
+	Method string `json:"method"`
+	// Labels 请求标签，全部精确匹配才生效，值为*表示只要求标签存在
+	Labels map[string]string `json:"labels"`
+	// Delay 延迟注入
+	Delay *FaultDelay `json:"delay"`
+	// Abort 中断注入
+	Abort *FaultAbort `json:"abort"`
+}
+
+// FaultDelay 延迟注入配置
+type FaultDelay struct {
+	// Percentage 注入比例，取值[0, 100]
+	Percentage float64 `json:"percentage"`
+	// DelayMs 注入的延迟时长，单位毫秒
+	DelayMs int64 `json:"delayMs"`
+}
+
+// FaultAbort 中断注入配置
+type FaultAbort struct {
+	// Percentage 注入比例，取值[0, 100]
+	Percentage float64 `json:"percentage"`
+	// Code 中断时返回的错误码
+	Code int32 `json:"code"`
+	// Message 中断时返回的错误信息
+	Message string `json:"message"`
+}
+
+// ParseFaultInjectionRules 解析故障注入规则
+func ParseFaultInjectionRules(content string) (*FaultInjectionRules, error) {
+	rules := &FaultInjectionRules{}
+	if len(content) == 0 {
+		return rules, nil
+	}
+	if err := json.Unmarshal([]byte(content), rules); err != nil {
+		return nil, err
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Validate 校验故障注入规则
+func (f *FaultInjectionRules) Validate() error {
+	var errs error
+	for i, rule := range f.Rules {
+		if rule == nil {
+			errs = multierror.Append(errs, fmt.Errorf("faultInjection.rules[%d] is nil", i))
+			continue
+		}
+		if rule.Delay != nil {
+			if rule.Delay.Percentage < 0 || rule.Delay.Percentage > 100 {
+				errs = multierror.Append(errs, fmt.Errorf("faultInjection rule %s: delay.percentage must be in [0, 100]",
+					rule.Name))
+			}
+			if rule.Delay.DelayMs < 0 {
+				errs = multierror.Append(errs, fmt.Errorf("faultInjection rule %s: delay.delayMs must not be negative",
+					rule.Name))
+			}
+		}
+		if rule.Abort != nil && (rule.Abort.Percentage < 0 || rule.Abort.Percentage > 100) {
+			errs = multierror.Append(errs, fmt.Errorf("faultInjection rule %s: abort.percentage must be in [0, 100]",
+				rule.Name))
+		}
+	}
+	return errs
+}
+
+// InjectFaultRequest 故障注入请求，RPC框架在调用下游前发起
+type InjectFaultRequest struct {
+	// Namespace 必选，被调服务命名空间
+	Namespace string
+	// Service 必选，被调服务名
+	Service string
+	// Method 可选，被调方法
+	Method string
+	// Labels 可选，请求标签
+	Labels map[string]string
+}
+
+// GetNamespace 获取命名空间
+func (r *InjectFaultRequest) GetNamespace() string {
+	return r.Namespace
+}
+
+// GetService 获取服务名
+func (r *InjectFaultRequest) GetService() string {
+	return r.Service
+}
+
+// GetMetadata 获取请求标签
+func (r *InjectFaultRequest) GetMetadata() map[string]string {
+	return r.Labels
+}
+
+// Validate 校验故障注入请求
+func (r *InjectFaultRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "InjectFaultRequest can not be nil")
+	}
+	if err := validateServiceMetadata("InjectFaultRequest", r); err != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, err, "fail to validate InjectFaultRequest")
+	}
+	return nil
+}
+
+// InjectFaultResponse 故障注入结果，调用方需要先等待Delay，再根据Abort决定是否中断调用
+type InjectFaultResponse struct {
+	// RuleName 命中的规则名
+	RuleName string
+	// Delay 需要注入的延迟，为0表示不注入
+	Delay time.Duration
+	// Abort 是否需要中断本次调用
+	Abort bool
+	// AbortCode 中断时返回的错误码
+	AbortCode int32
+	// AbortMessage 中断时返回的错误信息
+	AbortMessage string
+}
@@ -0,0 +1,73 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// FreshnessMode 获取服务实例时对数据新鲜度的要求
+type FreshnessMode int
+
+const (
+	// FreshnessDefault 默认行为，缓存已就绪时直接使用，未就绪时等待首次拉取
+	FreshnessDefault FreshnessMode = iota
+	// FreshnessMustBeFresh 必须与服务端完成一次交互后再返回
+	FreshnessMustBeFresh
+	// FreshnessAcceptStale 缓存数据在可容忍的过期时长内直接返回，同时后台刷新
+	FreshnessAcceptStale
+	// FreshnessCacheOnly 只使用本地缓存（包括持久化缓存），不等待服务端
+	FreshnessCacheOnly
+)
+
+// Freshness 单次调用的数据新鲜度控制
+type Freshness struct {
+	// 新鲜度模式
+	Mode FreshnessMode
+	// FreshnessAcceptStale模式下可容忍的最大过期时长
+	MaxStaleness time.Duration
+}
+
+// MustBeFresh 强制与服务端交互一次，适用于批处理等对一致性要求高的场景
+func MustBeFresh() *Freshness {
+	return &Freshness{Mode: FreshnessMustBeFresh}
+}
+
+// AcceptStaleUpTo 接受最多过期d的缓存数据并立即返回，超出时同步刷新
+func AcceptStaleUpTo(d time.Duration) *Freshness {
+	return &Freshness{Mode: FreshnessAcceptStale, MaxStaleness: d}
+}
+
+// CacheOnly 只使用本地缓存，缓存不存在时直接返回错误
+func CacheOnly() *Freshness {
+	return &Freshness{Mode: FreshnessCacheOnly}
+}
+
+// Validate 校验新鲜度参数
+func (f *Freshness) Validate() error {
+	if nil == f {
+		return nil
+	}
+	if f.Mode < FreshnessDefault || f.Mode > FreshnessCacheOnly {
+		return fmt.Errorf("invalid freshness mode %d", f.Mode)
+	}
+	if f.Mode == FreshnessAcceptStale && f.MaxStaleness < 0 {
+		return fmt.Errorf("maxStaleness %v must not be negative", f.MaxStaleness)
+	}
+	return nil
+}
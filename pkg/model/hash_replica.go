@@ -0,0 +1,56 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+// GetInstancesByHashKeyRequest 按hash key沿一致性hash环获取多个不同实例的请求，实例选择参数与GetOneInstance一致
+type GetInstancesByHashKeyRequest struct {
+	GetOneInstanceRequest
+	// Replicas 需要返回的不同实例数，包含hash key所在的主实例，默认为1
+	Replicas int
+}
+
+// Validate 校验请求
+func (r *GetInstancesByHashKeyRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "GetInstancesByHashKeyRequest can not be nil")
+	}
+	if len(r.HashKey) == 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+			"GetInstancesByHashKeyRequest: hashKey can not be empty")
+	}
+	if r.Replicas < 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+			"GetInstancesByHashKeyRequest: replicas can not be negative")
+	}
+	return r.GetOneInstanceRequest.Validate()
+}
+
+// HashKeyInstancesResponse 按hash key获取的实例列表
+type HashKeyInstancesResponse struct {
+	// Instances 按hash环顺序排列的不同实例，第一个为hash key所在的主实例，
+	// 可用实例不足时返回的数量少于请求的Replicas
+	Instances []Instance
+}
+
+// GetPrimary 获取hash key所在的主实例
+func (r *HashKeyInstancesResponse) GetPrimary() Instance {
+	if len(r.Instances) > 0 {
+		return r.Instances[0]
+	}
+	return nil
+}
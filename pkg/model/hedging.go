@@ -0,0 +1,52 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package model
+
+import (
+	"time"
+)
+
+// GetInstancesForHedgingRequest 对冲请求的实例获取请求，实例选择参数与GetOneInstance一致
+type GetInstancesForHedgingRequest struct {
+	GetOneInstanceRequest
+	// BackupCount 备份实例数，实际返回数量受可用实例数限制
+	BackupCount int
+}
+
+// Validate 校验请求
+func (r *GetInstancesForHedgingRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "GetInstancesForHedgingRequest can not be nil")
+	}
+	if r.BackupCount < 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+			"GetInstancesForHedgingRequest: backupCount can not be negative")
+	}
+	return r.GetOneInstanceRequest.Validate()
+}
+
+// HedgingInstancesResponse 对冲请求的实例选择结果
+type HedgingInstancesResponse struct {
+	// HedgeID 对冲请求标识，上报调用结果时需通过ServiceCallResult.SetHedgeID带上，用于去重
+	HedgeID string
+	// Primary 主调用实例
+	Primary Instance
+	// Backups 备份实例列表，不包含主调用实例
+	Backups []Instance
+	// HedgeDelays 与Backups一一对应，表示主调用发出后多久仍未返回时发起对应的备份调用
+	HedgeDelays []time.Duration
+}
@@ -0,0 +1,156 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ErrMetadataKeyNotFound 实例元数据中不存在指定的键
+var ErrMetadataKeyNotFound = errors.New("metadata key not found")
+
+// MetadataInt 将元数据中的值解析为整数，键不存在或解析失败时返回默认值
+func MetadataInt(metadata map[string]string, key string, defaultValue int64) int64 {
+	value, ok := metadata[key]
+	if !ok {
+		return defaultValue
+	}
+	intValue, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return intValue
+}
+
+// MetadataBool 将元数据中的值解析为布尔值，支持1/0、true/false等写法，键不存在或解析失败时返回默认值
+func MetadataBool(metadata map[string]string, key string, defaultValue bool) bool {
+	value, ok := metadata[key]
+	if !ok {
+		return defaultValue
+	}
+	boolValue, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return defaultValue
+	}
+	return boolValue
+}
+
+// MetadataJSON 将元数据中的JSON值反序列化到value中，键不存在时返回ErrMetadataKeyNotFound
+func MetadataJSON(metadata map[string]string, key string, value interface{}) error {
+	raw, ok := metadata[key]
+	if !ok {
+		return ErrMetadataKeyNotFound
+	}
+	if err := json.Unmarshal([]byte(raw), value); err != nil {
+		return fmt.Errorf("fail to unmarshal metadata %s: %w", key, err)
+	}
+	return nil
+}
+
+// InstanceMetadataChange 单个实例的元数据变更
+type InstanceMetadataChange struct {
+	// 变更后的实例
+	Instance Instance
+	// 变更前的元数据
+	OldMetadata map[string]string
+	// 变更后的元数据
+	NewMetadata map[string]string
+	// 发生变化的键，包括新增、删除及修改，按字典序排列
+	ChangedKeys []string
+}
+
+// InstanceMetadataEvent 服务下实例元数据变更事件，实例的上下线不会产生该事件
+type InstanceMetadataEvent struct {
+	// 服务标识
+	ServiceKey
+	// 服务实例的修订版本
+	Revision string
+	// 元数据发生变化的实例
+	Changes []InstanceMetadataChange
+}
+
+// InstanceMetadataListener 实例元数据变更监听器
+type InstanceMetadataListener interface {
+	// OnInstanceMetadataChanged 实例元数据发生变化时回调
+	OnInstanceMetadataChanged(event *InstanceMetadataEvent)
+}
+
+// WatchInstanceMetadataRequest 监听服务实例元数据变更的请求
+type WatchInstanceMetadataRequest struct {
+	ServiceKey
+	// 可选，只关注的元数据键，为空时任意键变化均会通知
+	Keys []string
+	// 必选，元数据变更监听器
+	MetadataListener InstanceMetadataListener
+}
+
+// Validate 校验请求参数
+func (req *WatchInstanceMetadataRequest) Validate() error {
+	if nil == req {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "WatchInstanceMetadataRequest can not be nil")
+	}
+	var errs error
+	if len(req.Namespace) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("namespace is empty"))
+	}
+	if len(req.Service) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("service is empty"))
+	}
+	if req.MetadataListener == nil {
+		errs = multierror.Append(errs, fmt.Errorf("metadata listener is empty"))
+	}
+	if errs != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, errs, "fail to validate WatchInstanceMetadataRequest")
+	}
+	return nil
+}
+
+// DiffMetadata 比较两份元数据，返回发生变化的键，keys不为空时只比较其中的键
+func DiffMetadata(oldMetadata, newMetadata map[string]string, keys []string) []string {
+	var changed []string
+	if len(keys) > 0 {
+		for _, key := range keys {
+			oldValue, oldOk := oldMetadata[key]
+			newValue, newOk := newMetadata[key]
+			if oldOk != newOk || oldValue != newValue {
+				changed = append(changed, key)
+			}
+		}
+		sort.Strings(changed)
+		return changed
+	}
+	for key, oldValue := range oldMetadata {
+		if newValue, ok := newMetadata[key]; !ok || newValue != oldValue {
+			changed = append(changed, key)
+		}
+	}
+	for key := range newMetadata {
+		if _, ok := oldMetadata[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,168 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// DefaultInstancesChunkSize 分批遍历实例时默认每批的实例数
+const DefaultInstancesChunkSize = 1000
+
+// IterateInstancesRequest 分批遍历服务实例的请求，适用于实例数量巨大的服务，避免一次性返回完整的实例列表
+type IterateInstancesRequest struct {
+	// 可选，流水号，用于跟踪用户的请求，默认0
+	FlowID uint64
+	// 必选，服务名
+	Service string
+	// 必选，命名空间
+	Namespace string
+	// 可选，实例元数据过滤条件，只返回元数据包含全部键值对的实例
+	Metadata map[string]string
+	// 可选，每批返回的实例数，默认DefaultInstancesChunkSize
+	ChunkSize int
+	// 可选，是否由服务端按Metadata过滤，开启后绕过本地缓存直接向服务端查询，减少传输的数据量，
+	// 查询结果不写入本地缓存，也不带有本地的熔断状态；SDK始终在本地按Metadata再次过滤，
+	// 服务端不支持过滤时结果仍然正确，只是无法减少传输的数据量
+	ServerSideFilter bool
+	// 可选，单次查询超时时间，默认直接获取全局的超时配置
+	Timeout *time.Duration
+	// 可选，重试次数，默认直接获取全局的超时配置，仅对读取本地缓存的方式生效
+	RetryCount *int
+}
+
+// Validate 校验请求
+func (r *IterateInstancesRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "IterateInstancesRequest can not be nil")
+	}
+	var errs error
+	if len(r.Namespace) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("namespace is empty"))
+	}
+	if len(r.Service) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("service is empty"))
+	}
+	if r.ChunkSize < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("chunkSize can not be negative"))
+	}
+	if r.ServerSideFilter && len(r.Metadata) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("metadata is empty when serverSideFilter enabled"))
+	}
+	if errs != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, errs, "fail to validate IterateInstancesRequest")
+	}
+	return nil
+}
+
+// InstancesIterator 服务实例的分批迭代器，每次返回一批实例，不会生成包含全部实例的新列表，非并发安全
+type InstancesIterator struct {
+	revision   string
+	total      int
+	instances  []Instance
+	at         func(int) Instance
+	metadataAt func(int) map[string]string
+	filter     map[string]string
+	chunkSize  int
+	pos        int
+}
+
+// NewInstancesIterator 按下标访问的方式创建迭代器，at在实例被遍历到时才会调用，metadataAt用于在转换实例前过滤
+func NewInstancesIterator(revision string, total int, at func(int) Instance,
+	metadataAt func(int) map[string]string, filter map[string]string, chunkSize int) *InstancesIterator {
+	if chunkSize <= 0 {
+		chunkSize = DefaultInstancesChunkSize
+	}
+	return &InstancesIterator{
+		revision:   revision,
+		total:      total,
+		at:         at,
+		metadataAt: metadataAt,
+		filter:     filter,
+		chunkSize:  chunkSize,
+	}
+}
+
+// NewSliceInstancesIterator 基于已有的实例列表创建迭代器，无过滤条件时直接返回列表的分段，不会复制
+func NewSliceInstancesIterator(revision string, instances []Instance,
+	filter map[string]string, chunkSize int) *InstancesIterator {
+	iterator := NewInstancesIterator(revision, len(instances), func(index int) Instance {
+		return instances[index]
+	}, func(index int) map[string]string {
+		return instances[index].GetMetadata()
+	}, filter, chunkSize)
+	iterator.instances = instances
+	return iterator
+}
+
+// GetRevision 获取实例列表的版本号
+func (it *InstancesIterator) GetRevision() string {
+	return it.revision
+}
+
+// Total 获取过滤前的实例总数
+func (it *InstancesIterator) Total() int {
+	return it.total
+}
+
+// Next 获取下一批实例，遍历结束时返回空列表
+func (it *InstancesIterator) Next() []Instance {
+	if it.pos >= it.total {
+		return nil
+	}
+	if len(it.filter) == 0 && it.instances != nil {
+		end := it.pos + it.chunkSize
+		if end > it.total {
+			end = it.total
+		}
+		chunk := it.instances[it.pos:end:end]
+		it.pos = end
+		return chunk
+	}
+	chunk := make([]Instance, 0, it.chunkSize)
+	for ; it.pos < it.total && len(chunk) < it.chunkSize; it.pos++ {
+		if !matchMetadata(it.metadataAt(it.pos), it.filter) {
+			continue
+		}
+		chunk = append(chunk, it.at(it.pos))
+	}
+	return chunk
+}
+
+// ForEachChunk 依次回调剩余的每批实例，回调返回错误时停止遍历并返回该错误
+func (it *InstancesIterator) ForEachChunk(handle func(chunk []Instance) error) error {
+	for chunk := it.Next(); len(chunk) > 0; chunk = it.Next() {
+		if err := handle(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchMetadata 实例元数据是否包含过滤条件中的全部键值对
+func matchMetadata(metadata map[string]string, filter map[string]string) bool {
+	for key, value := range filter {
+		if actual, ok := metadata[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
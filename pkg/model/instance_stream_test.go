@@ -0,0 +1,212 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// streamTestInstance 只提供实例ID及元数据的实例
+type streamTestInstance struct {
+	Instance
+	id       string
+	metadata map[string]string
+}
+
+func (s *streamTestInstance) GetId() string {
+	return s.id
+}
+
+func (s *streamTestInstance) GetMetadata() map[string]string {
+	return s.metadata
+}
+
+// streamTestInstances 创建5个实例，偶数下标env为prod，前两个实例zone为a
+func streamTestInstances() []Instance {
+	instances := make([]Instance, 0, 5)
+	for i := 0; i < 5; i++ {
+		metadata := map[string]string{"env": "test", "zone": "b"}
+		if i%2 == 0 {
+			metadata["env"] = "prod"
+		}
+		if i < 2 {
+			metadata["zone"] = "a"
+		}
+		instances = append(instances, &streamTestInstance{id: fmt.Sprintf("%d", i), metadata: metadata})
+	}
+	return instances
+}
+
+// collectChunkIds 遍历迭代器，按批返回实例ID
+func collectChunkIds(it *InstancesIterator) [][]string {
+	var chunks [][]string
+	_ = it.ForEachChunk(func(chunk []Instance) error {
+		ids := make([]string, 0, len(chunk))
+		for _, instance := range chunk {
+			ids = append(ids, instance.GetId())
+		}
+		chunks = append(chunks, ids)
+		return nil
+	})
+	return chunks
+}
+
+func TestInstancesIterator(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    map[string]string
+		chunkSize int
+		want      [][]string
+	}{
+		{
+			name:      "无过滤条件时按批返回",
+			chunkSize: 2,
+			want:      [][]string{{"0", "1"}, {"2", "3"}, {"4"}},
+		},
+		{
+			name: "未指定批大小时使用默认值",
+			want: [][]string{{"0", "1", "2", "3", "4"}},
+		},
+		{
+			name:      "按元数据过滤后每批仍然填满",
+			filter:    map[string]string{"env": "prod"},
+			chunkSize: 2,
+			want:      [][]string{{"0", "2"}, {"4"}},
+		},
+		{
+			name:      "需匹配全部键值对",
+			filter:    map[string]string{"env": "prod", "zone": "a"},
+			chunkSize: 2,
+			want:      [][]string{{"0"}},
+		},
+		{
+			name:      "没有匹配的实例",
+			filter:    map[string]string{"env": "dev"},
+			chunkSize: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := NewSliceInstancesIterator("rev1", streamTestInstances(), tt.filter, tt.chunkSize)
+			assert.Equal(t, "rev1", it.GetRevision())
+			assert.Equal(t, 5, it.Total())
+			assert.Equal(t, tt.want, collectChunkIds(it))
+			assert.Empty(t, it.Next())
+			assert.Equal(t, 5, it.Total())
+		})
+	}
+}
+
+// TestSliceInstancesIteratorNoCopy 测试无过滤条件时直接返回列表的分段，且追加不会覆盖原列表
+func TestSliceInstancesIteratorNoCopy(t *testing.T) {
+	instances := streamTestInstances()
+	it := NewSliceInstancesIterator("rev1", instances, nil, 2)
+	chunk := it.Next()
+	assert.Len(t, chunk, 2)
+	assert.True(t, &chunk[0] == &instances[0])
+	_ = append(chunk, &streamTestInstance{id: "new"})
+	assert.Equal(t, "2", instances[2].GetId())
+}
+
+// TestInstancesIteratorLazy 测试实例只在遍历到且匹配过滤条件时才转换
+func TestInstancesIteratorLazy(t *testing.T) {
+	instances := streamTestInstances()
+	var converted, matched []int
+	it := NewInstancesIterator("rev1", len(instances), func(index int) Instance {
+		converted = append(converted, index)
+		return instances[index]
+	}, func(index int) map[string]string {
+		matched = append(matched, index)
+		return instances[index].GetMetadata()
+	}, map[string]string{"env": "prod"}, 1)
+
+	assert.Equal(t, "0", it.Next()[0].GetId())
+	assert.Equal(t, []int{0}, converted)
+	assert.Equal(t, []int{0}, matched)
+	assert.Equal(t, "2", it.Next()[0].GetId())
+	assert.Equal(t, []int{0, 2}, converted)
+	assert.Equal(t, []int{0, 1, 2}, matched)
+}
+
+func TestInstancesIteratorForEachChunkError(t *testing.T) {
+	it := NewSliceInstancesIterator("rev1", streamTestInstances(), nil, 2)
+	stopErr := errors.New("stop")
+	var calls int
+	err := it.ForEachChunk(func(chunk []Instance) error {
+		calls++
+		if calls == 2 {
+			return stopErr
+		}
+		return nil
+	})
+	assert.Equal(t, stopErr, err)
+	assert.Equal(t, 2, calls)
+	// 出错后可继续遍历剩余的实例
+	assert.Equal(t, [][]string{{"4"}}, collectChunkIds(it))
+}
+
+func TestIterateInstancesRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *IterateInstancesRequest
+		wantErr bool
+	}{
+		{
+			name: "合法请求",
+			req:  &IterateInstancesRequest{Namespace: "Test", Service: "svc"},
+		},
+		{
+			name:    "请求为空",
+			wantErr: true,
+		},
+		{
+			name:    "缺少服务名",
+			req:     &IterateInstancesRequest{Namespace: "Test"},
+			wantErr: true,
+		},
+		{
+			name:    "批大小为负数",
+			req:     &IterateInstancesRequest{Namespace: "Test", Service: "svc", ChunkSize: -1},
+			wantErr: true,
+		},
+		{
+			name:    "服务端过滤时缺少元数据",
+			req:     &IterateInstancesRequest{Namespace: "Test", Service: "svc", ServerSideFilter: true},
+			wantErr: true,
+		},
+		{
+			name: "服务端过滤",
+			req: &IterateInstancesRequest{Namespace: "Test", Service: "svc", ServerSideFilter: true,
+				Metadata: map[string]string{"env": "prod"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+// Invocation 被拦截的一次 SDK 调用
+type Invocation struct {
+	// API 本次调用的接口标识
+	API ApiOperation
+	// Request 调用请求，类型与接口对应，如 *GetOneInstanceRequest、*QuotaRequestImpl、
+	// *InstanceRegisterRequest、*InstanceHeartbeatRequest、*GetConfigFileRequest、*GetConfigGroupRequest，
+	// 拦截器可以修改或替换请求对象，但不能改变其类型
+	Request interface{}
+}
+
+// Invoker 执行调用链中的后续逻辑，返回应答及错误
+type Invoker func(inv *Invocation) (interface{}, error)
+
+// Interceptor SDK 调用拦截器，通过调用 next 继续执行后续逻辑，可在调用前后观察或修改请求、应答及错误，
+// 不调用 next 时直接以其返回值作为本次调用的结果，此时应答类型需与接口对应
+type Interceptor func(inv *Invocation, next Invoker) (interface{}, error)
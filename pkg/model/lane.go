@@ -0,0 +1,70 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package model
+
+import (
+	"strings"
+)
+
+const (
+	// LaneTagKey 泳道标签在流量标签及请求元数据中的key
+	LaneTagKey = "service-lane"
+	// LaneTagSeparator 泳道标签中泳道组名与泳道名的分隔符，泳道标签格式为 <泳道组名>/<泳道名>
+	LaneTagSeparator = "/"
+)
+
+// BuildLaneTag 构建泳道标签
+func BuildLaneTag(groupName string, laneName string) string {
+	return groupName + LaneTagSeparator + laneName
+}
+
+// ParseLaneTag 解析泳道标签，返回泳道组名及泳道名
+func ParseLaneTag(tag string) (string, string, bool) {
+	idx := strings.Index(tag, LaneTagSeparator)
+	if idx <= 0 || idx == len(tag)-1 {
+		return "", "", false
+	}
+	return tag[:idx], tag[idx+1:], true
+}
+
+// BuildLaneArgument 将泳道标签构建为流量标签，用于入口流量已染色的场景
+func BuildLaneArgument(tag string) Argument {
+	return BuildHeaderArgument(LaneTagKey, tag)
+}
+
+// GetLaneTag 从主调的流量标签中获取泳道标签，支持自定义标签以及header两种形式
+func GetLaneTag(labels map[string]string) (string, bool) {
+	if tag, ok := labels[LaneTagKey]; ok && len(tag) > 0 {
+		return tag, true
+	}
+	if tag, ok := labels[LabelKeyHeader+LaneTagKey]; ok && len(tag) > 0 {
+		return tag, true
+	}
+	return "", false
+}
+
+// InjectLaneTag 将泳道标签透传到发往下游的请求元数据中，metadata为空时会新建
+func InjectLaneTag(metadata map[string]string, tag string) map[string]string {
+	if len(tag) == 0 {
+		return metadata
+	}
+	if nil == metadata {
+		metadata = make(map[string]string, 1)
+	}
+	metadata[LaneTagKey] = tag
+	return metadata
+}
@@ -0,0 +1,61 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+const (
+	// AdaptiveWeightModeWeight 根据负载调整实例权重
+	AdaptiveWeightModeWeight = "weight"
+	// AdaptiveWeightModeMetadata 将负载分数写入实例metadata，供感知负载的负载均衡器使用
+	AdaptiveWeightModeMetadata = "metadata"
+)
+
+// LoadSampler 实例负载采样器，用于自适应权重上报
+type LoadSampler interface {
+	// Name 采样器名称
+	Name() string
+	// Sample 采样当前负载，取值范围[0, 1]，采样失败时返回false
+	Sample() (float64, bool)
+}
+
+// InFlightLoadSampler 基于在途请求数的负载采样器，负载为在途请求数与容量的比值
+type InFlightLoadSampler struct {
+	// InFlightRequests 返回当前的在途请求数
+	InFlightRequests func() int64
+	// Capacity 实例可承载的最大在途请求数
+	Capacity int64
+}
+
+// Name 采样器名称
+func (s *InFlightLoadSampler) Name() string {
+	return "inFlight"
+}
+
+// Sample 采样当前负载
+func (s *InFlightLoadSampler) Sample() (float64, bool) {
+	if s.InFlightRequests == nil || s.Capacity <= 0 {
+		return 0, false
+	}
+	load := float64(s.InFlightRequests()) / float64(s.Capacity)
+	if load > 1 {
+		load = 1
+	}
+	if load < 0 {
+		load = 0
+	}
+	return load, true
+}
@@ -20,6 +20,7 @@ package pb
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -57,6 +58,66 @@ func isMatchAllValueString(value string) bool {
 	return len(value) == 0 || value == MatchAll
 }
 
+// IsPrefixMatchValue 精确匹配的值以*结尾时，按照前缀进行匹配
+func IsPrefixMatchValue(ruleMetaValue *apimodel.MatchString) bool {
+	value := ruleMetaValue.GetValue().GetValue()
+	return ruleMetaValue.GetType() == apimodel.MatchString_EXACT && len(value) > 1 && strings.HasSuffix(value, MatchAll)
+}
+
+// IsExactMatchValue 是否为只能匹配单个值的精确匹配
+func IsExactMatchValue(ruleMetaValue *apimodel.MatchString) bool {
+	return ruleMetaValue.GetType() == apimodel.MatchString_EXACT && !IsPrefixMatchValue(ruleMetaValue)
+}
+
+// NumberRange 数值范围匹配的上下界，闭区间
+type NumberRange struct {
+	Low  int64
+	High int64
+}
+
+// Contains 数值是否在范围内
+func (n *NumberRange) Contains(value int64) bool {
+	return value >= n.Low && value <= n.High
+}
+
+// ParseNumberRange 解析low~high格式的数值范围
+func ParseNumberRange(value string) (*NumberRange, error) {
+	tokens := strings.Split(value, "~")
+	if len(tokens) != 2 {
+		return nil, fmt.Errorf("range value %s must be in format low~high", value)
+	}
+	low, err := strconv.ParseInt(strings.TrimSpace(tokens[0]), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range low value %s: %v", tokens[0], err)
+	}
+	high, err := strconv.ParseInt(strings.TrimSpace(tokens[1]), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range high value %s: %v", tokens[1], err)
+	}
+	if low > high {
+		return nil, fmt.Errorf("range value %s: low must not be greater than high", value)
+	}
+	return &NumberRange{Low: low, High: high}, nil
+}
+
+// buildMatcherCache 预先编译正则表达式，解析数值范围，并放入规则缓存
+func buildMatcherCache(matchValue *apimodel.MatchString, cache model.RuleCache) error {
+	if IsMatchAllValue(matchValue) {
+		return nil
+	}
+	switch matchValue.GetType() {
+	case apimodel.MatchString_REGEX:
+		return buildRegexCache(matchValue, cache)
+	case apimodel.MatchString_RANGE:
+		numberRange, err := ParseNumberRange(matchValue.GetValue().GetValue())
+		if err != nil {
+			return err
+		}
+		cache.SetMessageCache(matchValue, numberRange)
+	}
+	return nil
+}
+
 // ParseRuleValue 解析出具体的规则值
 func (r *RateLimitingAssistant) ParseRuleValue(resp *apiservice.DiscoverResponse) (proto.Message, string) {
 	var revision string
@@ -228,6 +289,13 @@ func (r *RateLimitingAssistant) Validate(message proto.Message, ruleCache model.
 		return nil
 	}
 	for _, rule := range rateLimiting.GetRules() {
+		if rule.GetResource() == apitraffic.Rule_CONCURRENCY {
+			// 并发数限流的配额与时间周期无关
+			if err := validateBehavior(rule, ruleCache); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := validateAmount(rule.GetAmounts()); err != nil {
 			routeTxt, _ := (&jsonpb.Marshaler{}).MarshalToString(rule)
 			return fmt.Errorf("fail to validate rate limit rule, error is %v, rule text is\n%s",
@@ -243,9 +311,8 @@ func (r *RateLimitingAssistant) Validate(message proto.Message, ruleCache model.
 			return fmt.Errorf(
 				"fail to parse reportAmount in rate limit rule, value %d must in (0, 100]", amountPresent)
 		}
-		behaviorName := rule.GetAction().GetValue()
-		if !plugin.IsPluginRegistered(common.TypeRateLimiter, behaviorName) {
-			return fmt.Errorf("behavior plugin %s not registered", behaviorName)
+		if err = validateBehavior(rule, ruleCache); err != nil {
+			return err
 		}
 		ruleCache.SetMessageCache(rule, &RateLimitRuleCache{
 			MaxDuration: maxDuration})
@@ -253,6 +320,24 @@ func (r *RateLimitingAssistant) Validate(message proto.Message, ruleCache model.
 	return nil
 }
 
+// validateBehavior 校验限流插件是否注册，并预先解析规则中的匹配条件
+func validateBehavior(rule *apitraffic.Rule, ruleCache model.RuleCache) error {
+	behaviorName := rule.GetAction().GetValue()
+	if !plugin.IsPluginRegistered(common.TypeRateLimiter, behaviorName) {
+		return fmt.Errorf("behavior plugin %s not registered", behaviorName)
+	}
+	if err := buildMatcherCache(rule.GetMethod(), ruleCache); err != nil {
+		return fmt.Errorf("fail to parse method matcher in rate limit rule, error is %v", err)
+	}
+	for _, argument := range rule.GetArguments() {
+		if err := buildMatcherCache(argument.GetValue(), ruleCache); err != nil {
+			return fmt.Errorf("fail to parse argument %s matcher in rate limit rule, error is %v",
+				argument.GetKey(), err)
+		}
+	}
+	return nil
+}
+
 const minAmountDuration = 1 * time.Second
 
 // validateAmount 校验配额总量
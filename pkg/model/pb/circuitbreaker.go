@@ -19,11 +19,15 @@ package pb
 
 import (
 	"github.com/golang/protobuf/proto"
+	"github.com/modern-go/reflect2"
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	"github.com/polarismesh/specification/source/go/api/v1/service_manage"
 
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
+// CircuitBreakAssistant 熔断规则解析助手
 type CircuitBreakAssistant struct {
 }
 
@@ -43,11 +47,26 @@ func (a *CircuitBreakAssistant) SetDefault(message proto.Message) {
 
 }
 
-// Validate 规则校验
+// Validate 规则校验，同时对规则中的正则表达式进行预编译
 func (a *CircuitBreakAssistant) Validate(message proto.Message, cache model.RuleCache) error {
+	if reflect2.IsNil(message) {
+		return nil
+	}
+	circuitBreaker := message.(*fault_tolerance.CircuitBreaker)
+	for _, rule := range circuitBreaker.GetRules() {
+		if err := buildRegexCache(rule.GetRuleMatcher().GetDestination().GetMethod(), cache); err != nil {
+			return err
+		}
+		for _, errCondition := range rule.GetErrorConditions() {
+			if err := buildRegexCache(errCondition.GetCondition(), cache); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// FaultDetectAssistant 探测规则解析助手
 type FaultDetectAssistant struct {
 }
 
@@ -67,7 +86,25 @@ func (a *FaultDetectAssistant) SetDefault(message proto.Message) {
 
 }
 
-// Validate 规则校验
+// Validate 规则校验，同时对规则中的正则表达式进行预编译
 func (a *FaultDetectAssistant) Validate(message proto.Message, cache model.RuleCache) error {
+	if reflect2.IsNil(message) {
+		return nil
+	}
+	faultDetector := message.(*fault_tolerance.FaultDetector)
+	for _, rule := range faultDetector.GetRules() {
+		if err := buildRegexCache(rule.GetTargetService().GetMethod(), cache); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// buildRegexCache 正则类型的匹配规则，预先编译并放入规则缓存
+func buildRegexCache(matchValue *apimodel.MatchString, cache model.RuleCache) error {
+	if matchValue.GetType() != apimodel.MatchString_REGEX || len(matchValue.GetValue().GetValue()) == 0 {
+		return nil
+	}
+	_, err := cache.GetRegexMatcher(matchValue.GetValue().GetValue())
+	return err
+}
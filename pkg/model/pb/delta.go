@@ -0,0 +1,87 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pb
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+)
+
+const (
+	// DeltaHeader 客户端声明支持增量实例推送的请求头
+	DeltaHeader = "X-Polaris-Delta"
+	// DeltaMetaKey 服务元数据中标识本次应答为增量推送
+	DeltaMetaKey = "internal-delta"
+	// DeltaBaseRevisionMetaKey 服务元数据中增量推送所基于的版本号
+	DeltaBaseRevisionMetaKey = "internal-delta-base-revision"
+	// DeltaOpMetaKey 实例元数据中的增量操作类型，不设置时表示新增或更新
+	DeltaOpMetaKey = "internal-delta-op"
+	// DeltaOpRemove 删除实例
+	DeltaOpRemove = "remove"
+)
+
+// IsDeltaResponse 应答是否为增量实例推送.
+func IsDeltaResponse(resp *apiservice.DiscoverResponse) bool {
+	return resp.GetType() == apiservice.DiscoverResponse_INSTANCE &&
+		resp.GetService().GetMetadata()[DeltaMetaKey] == "true"
+}
+
+// MergeDeltaInstances 将增量应答合并到已缓存的实例上，生成全量应答.
+// 未变更的实例直接复用缓存中的pb对象，避免重复反序列化.
+// cachedRevision 为本地上报给服务端的版本号，内容等价的推送只更新版本号时可能与 base 的版本号不同.
+func MergeDeltaInstances(base *ServiceInstancesInProto, cachedRevision string,
+	delta *apiservice.DiscoverResponse) (*apiservice.DiscoverResponse, error) {
+	baseRevision := delta.GetService().GetMetadata()[DeltaBaseRevisionMetaKey]
+	if base == nil || !base.initialized {
+		return nil, fmt.Errorf("no cached instances for delta based on revision %s", baseRevision)
+	}
+	if cachedRevision != baseRevision {
+		return nil, fmt.Errorf("delta base revision %s mismatch cached revision %s",
+			baseRevision, cachedRevision)
+	}
+	changed := make(map[string]*apiservice.Instance, len(delta.Instances))
+	for _, inst := range delta.Instances {
+		changed[inst.GetId().GetValue()] = inst
+	}
+	instances := make([]*apiservice.Instance, 0, len(base.instances)+len(delta.Instances))
+	for _, inst := range base.instances {
+		instID := inst.GetId()
+		if _, ok := changed[instID]; ok {
+			continue
+		}
+		instances = append(instances, inst.(*InstanceInProto).Instance)
+	}
+	for _, inst := range delta.Instances {
+		if inst.GetMetadata()[DeltaOpMetaKey] == DeltaOpRemove {
+			continue
+		}
+		instances = append(instances, inst)
+	}
+	svc := proto.Clone(delta.GetService()).(*apiservice.Service)
+	delete(svc.Metadata, DeltaMetaKey)
+	delete(svc.Metadata, DeltaBaseRevisionMetaKey)
+	return &apiservice.DiscoverResponse{
+		Code:      delta.GetCode(),
+		Info:      delta.GetInfo(),
+		Type:      delta.GetType(),
+		Service:   svc,
+		Instances: instances,
+	}, nil
+}
@@ -0,0 +1,155 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pb
+
+import (
+	"sort"
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func deltaTestInstance(id string, weight uint32, op string) *apiservice.Instance {
+	inst := &apiservice.Instance{
+		Id:     wrapperspb.String(id),
+		Host:   wrapperspb.String("127.0.0.1"),
+		Port:   wrapperspb.UInt32(8080),
+		Weight: wrapperspb.UInt32(weight),
+	}
+	if len(op) > 0 {
+		inst.Metadata = map[string]string{DeltaOpMetaKey: op}
+	}
+	return inst
+}
+
+// deltaTestBase 已缓存的实例，版本号为rev1，包含实例a、b、c
+func deltaTestBase() *ServiceInstancesInProto {
+	svcKey := &model.ServiceKey{Namespace: "Test", Service: "svc"}
+	base := &ServiceInstancesInProto{initialized: true, revision: "rev1"}
+	for _, id := range []string{"a", "b", "c"} {
+		base.instances = append(base.instances, NewInstanceInProto(deltaTestInstance(id, 100, ""), svcKey, nil))
+	}
+	return base
+}
+
+func deltaTestResponse(baseRevision string, instances ...*apiservice.Instance) *apiservice.DiscoverResponse {
+	return &apiservice.DiscoverResponse{
+		Code: wrapperspb.UInt32(uint32(apimodel.Code_ExecuteSuccess)),
+		Type: apiservice.DiscoverResponse_INSTANCE,
+		Service: &apiservice.Service{
+			Name:      wrapperspb.String("svc"),
+			Namespace: wrapperspb.String("Test"),
+			Revision:  wrapperspb.String("rev2"),
+			Metadata: map[string]string{
+				DeltaMetaKey:             "true",
+				DeltaBaseRevisionMetaKey: baseRevision,
+				"env":                    "prod",
+			},
+		},
+		Instances: instances,
+	}
+}
+
+func instanceWeights(resp *apiservice.DiscoverResponse) map[string]uint32 {
+	weights := make(map[string]uint32, len(resp.Instances))
+	for _, inst := range resp.Instances {
+		weights[inst.GetId().GetValue()] = inst.GetWeight().GetValue()
+	}
+	return weights
+}
+
+// TestMergeDeltaInstances 测试增量实例推送的合并
+func TestMergeDeltaInstances(t *testing.T) {
+	tests := []struct {
+		name  string
+		delta *apiservice.DiscoverResponse
+		want  map[string]uint32
+	}{
+		{
+			name:  "新增实例",
+			delta: deltaTestResponse("rev1", deltaTestInstance("d", 50, "")),
+			want:  map[string]uint32{"a": 100, "b": 100, "c": 100, "d": 50},
+		},
+		{
+			name:  "删除实例",
+			delta: deltaTestResponse("rev1", deltaTestInstance("b", 0, DeltaOpRemove)),
+			want:  map[string]uint32{"a": 100, "c": 100},
+		},
+		{
+			name:  "修改实例",
+			delta: deltaTestResponse("rev1", deltaTestInstance("c", 10, "")),
+			want:  map[string]uint32{"a": 100, "b": 100, "c": 10},
+		},
+		{
+			name: "同时新增删除修改",
+			delta: deltaTestResponse("rev1", deltaTestInstance("a", 0, DeltaOpRemove),
+				deltaTestInstance("b", 20, ""), deltaTestInstance("e", 30, "")),
+			want: map[string]uint32{"b": 20, "c": 100, "e": 30},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := deltaTestBase()
+			merged, err := MergeDeltaInstances(base, base.GetRevision(), tt.delta)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, instanceWeights(merged))
+			assert.Equal(t, "rev2", merged.GetService().GetRevision().GetValue())
+			// 增量标记不带入全量应答，其余服务元数据保留
+			assert.Equal(t, map[string]string{"env": "prod"}, merged.GetService().GetMetadata())
+			assert.Equal(t, "true", tt.delta.GetService().GetMetadata()[DeltaMetaKey])
+		})
+	}
+}
+
+// TestMergeDeltaInstancesReuseBase 测试未变更的实例复用缓存中的pb对象
+func TestMergeDeltaInstancesReuseBase(t *testing.T) {
+	base := deltaTestBase()
+	merged, err := MergeDeltaInstances(base, "rev1", deltaTestResponse("rev1", deltaTestInstance("c", 10, "")))
+	assert.Nil(t, err)
+	sort.Sort(InstSlice(merged.Instances))
+	assert.Same(t, base.instances[0].(*InstanceInProto).Instance, merged.Instances[0])
+	assert.Same(t, base.instances[1].(*InstanceInProto).Instance, merged.Instances[1])
+}
+
+// TestMergeDeltaInstancesFail 测试无法合并的增量推送，调用方需要转为全量拉取
+func TestMergeDeltaInstancesFail(t *testing.T) {
+	tests := []struct {
+		name           string
+		base           *ServiceInstancesInProto
+		cachedRevision string
+		baseRevision   string
+	}{
+		{name: "基础版本号与缓存不一致", base: deltaTestBase(), cachedRevision: "rev1", baseRevision: "rev0"},
+		{name: "缓存版本号已被强制清空", base: deltaTestBase(), cachedRevision: "", baseRevision: "rev1"},
+		{name: "没有缓存", base: nil, cachedRevision: "", baseRevision: "rev1"},
+		{name: "缓存未初始化", base: &ServiceInstancesInProto{}, cachedRevision: "", baseRevision: "rev1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergeDeltaInstances(tt.base, tt.cachedRevision,
+				deltaTestResponse(tt.baseRevision, deltaTestInstance("d", 50, "")))
+			assert.Error(t, err)
+			assert.Nil(t, merged)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pb
+
+import (
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+)
+
+// InstanceFilterMetaPrefix 服务发现请求的服务元数据中携带实例过滤条件的键前缀
+const InstanceFilterMetaPrefix = "internal-instance-filter-"
+
+// ToInstanceFilterMetadata 将实例元数据过滤条件转换为服务发现请求中的服务元数据.
+func ToInstanceFilterMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	filter := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		filter[InstanceFilterMetaPrefix+key] = value
+	}
+	return filter
+}
+
+// NewQueriedInstancesIterator 基于一次性查询的应答创建实例迭代器，实例在遍历到时才转换，
+// 不依赖服务端是否支持过滤，迭代器始终在本地按metadata再次过滤.
+func NewQueriedInstancesIterator(resp *apiservice.DiscoverResponse, svcKey *model.ServiceKey,
+	metadata map[string]string, chunkSize int) *model.InstancesIterator {
+	instances := resp.GetInstances()
+	return model.NewInstancesIterator(resp.GetService().GetRevision().GetValue(), len(instances),
+		func(index int) model.Instance {
+			return NewInstanceInProto(instances[index], svcKey, local.NewInstanceLocalValue())
+		}, func(index int) map[string]string {
+			return instances[index].GetMetadata()
+		}, metadata, chunkSize)
+}
@@ -0,0 +1,70 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package pb
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/modern-go/reflect2"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// LaneAssistant 泳道规则解析助手
+// 泳道规则以泳道组列表的形式下发，规则值为仅包含Lanes字段的DiscoverResponse
+type LaneAssistant struct {
+}
+
+// ParseRuleValue 解析出具体的规则值
+func (a *LaneAssistant) ParseRuleValue(resp *apiservice.DiscoverResponse) (proto.Message, string) {
+	var laneValue *apiservice.DiscoverResponse
+	groups := resp.GetLanes()
+	if len(groups) == 0 {
+		return laneValue, ""
+	}
+	revisions := make([]string, 0, len(groups))
+	for _, group := range groups {
+		revisions = append(revisions, group.GetRevision())
+	}
+	laneValue = &apiservice.DiscoverResponse{Lanes: groups}
+	return laneValue, strings.Join(revisions, ",")
+}
+
+// SetDefault 设置默认值
+func (a *LaneAssistant) SetDefault(message proto.Message) {
+
+}
+
+// Validate 规则校验，同时对染色规则中的正则表达式进行预编译
+func (a *LaneAssistant) Validate(message proto.Message, cache model.RuleCache) error {
+	if reflect2.IsNil(message) {
+		return nil
+	}
+	laneValue := message.(*apiservice.DiscoverResponse)
+	for _, group := range laneValue.GetLanes() {
+		for _, rule := range group.GetRules() {
+			for _, argument := range rule.GetTrafficMatchRule().GetArguments() {
+				if err := buildRegexCache(argument.GetValue(), cache); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -59,6 +59,10 @@ func (r *RoutingAssistant) Validate(message proto.Message, ruleCache model.RuleC
 	if err = r.validateRoute("outbound", routingValue.Outbounds, ruleCache); err != nil {
 		return err
 	}
+	ruleCache.SetMessageCache(routingValue, &RoutingIndex{
+		Inbounds:  buildRouteIndex(routingValue.Inbounds),
+		Outbounds: buildRouteIndex(routingValue.Outbounds),
+	})
 	return nil
 }
 
@@ -71,7 +75,7 @@ func (r *RoutingAssistant) validateRoute(direction string, routes []*apitraffic.
 		for _, source := range route.GetSources() {
 			for _, matchValue := range source.GetMetadata() {
 				if matchValue.GetType() == apimodel.MatchString_REGEX && len(matchValue.GetValue().GetValue()) > 0 {
-					_, err := ruleCache.GetRegexMatcher(matchValue.GetValue().GetValue())
+					_, err := ruleCache.GetMatcher(matchValue.GetValue().GetValue())
 					if err != nil {
 						return err
 					}
@@ -81,7 +85,7 @@ func (r *RoutingAssistant) validateRoute(direction string, routes []*apitraffic.
 		for _, destination := range route.GetDestinations() {
 			for _, matchValue := range destination.GetMetadata() {
 				if matchValue.GetType() == apimodel.MatchString_REGEX && len(matchValue.GetValue().GetValue()) > 0 {
-					_, err := ruleCache.GetRegexMatcher(matchValue.GetValue().GetValue())
+					_, err := ruleCache.GetMatcher(matchValue.GetValue().GetValue())
 					if err != nil {
 						return err
 					}
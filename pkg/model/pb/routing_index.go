@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pb
+
+import (
+	"sort"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+)
+
+// RoutingIndex 路由规则的标签索引，在规则校验时构建并存放于RuleCache中，key为*apitraffic.Routing
+type RoutingIndex struct {
+	// Inbounds 入规则的索引
+	Inbounds *RouteIndex
+	// Outbounds 出规则的索引
+	Outbounds *RouteIndex
+}
+
+// SourceRef 指向规则中的一个source
+type SourceRef struct {
+	// Route route在规则中的下标
+	Route int
+	// Source source在route中的下标，为-1表示该route没有配置source
+	Source int
+}
+
+// RouteIndex 一个方向的路由规则索引
+// 对于包含精确匹配文本标签的source，选取其中一个标签作为锚点，按key->value建立索引；其余source每次都需要参与匹配。
+// 索引只用于筛选可能匹配的source，筛选出的source仍需完整匹配
+type RouteIndex struct {
+	// 无法建立索引的source
+	generic []SourceRef
+	// 精确匹配的source，key为标签名，value为标签值到source的映射
+	exact map[string]map[string][]SourceRef
+}
+
+// buildRouteIndex 构建一个方向的路由规则索引
+func buildRouteIndex(routes []*apitraffic.Route) *RouteIndex {
+	index := &RouteIndex{exact: make(map[string]map[string][]SourceRef)}
+	for i, route := range routes {
+		if len(route.GetSources()) == 0 {
+			index.generic = append(index.generic, SourceRef{Route: i, Source: -1})
+			continue
+		}
+		for j, source := range route.GetSources() {
+			ref := SourceRef{Route: i, Source: j}
+			key, value, ok := anchorLabel(source.GetMetadata())
+			if !ok {
+				index.generic = append(index.generic, ref)
+				continue
+			}
+			values, exists := index.exact[key]
+			if !exists {
+				values = make(map[string][]SourceRef)
+				index.exact[key] = values
+			}
+			values[value] = append(values[value], ref)
+		}
+	}
+	return index
+}
+
+// anchorLabel 选取source中按字典序最小的精确匹配文本标签作为锚点
+func anchorLabel(metadata map[string]*apimodel.MatchString) (string, string, bool) {
+	var anchorKey, anchorValue string
+	for key, matchValue := range metadata {
+		if key == MatchAll || matchValue.GetType() != apimodel.MatchString_EXACT ||
+			matchValue.GetValueType() != apimodel.MatchString_TEXT {
+			continue
+		}
+		value := matchValue.GetValue().GetValue()
+		if len(value) == 0 || value == MatchAll {
+			continue
+		}
+		if len(anchorKey) == 0 || key < anchorKey {
+			anchorKey, anchorValue = key, value
+		}
+	}
+	return anchorKey, anchorValue, len(anchorKey) > 0
+}
+
+// Candidates 根据主调标签筛选可能匹配的source，按规则中的顺序返回
+func (r *RouteIndex) Candidates(labels map[string]string) []SourceRef {
+	refs := make([]SourceRef, 0, len(r.generic))
+	refs = append(refs, r.generic...)
+	if len(r.exact) > 0 {
+		for key, value := range labels {
+			if values, ok := r.exact[key]; ok {
+				refs = append(refs, values[value]...)
+			}
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Route != refs[j].Route {
+			return refs[i].Route < refs[j].Route
+		}
+		return refs[i].Source < refs[j].Source
+	})
+	return refs
+}
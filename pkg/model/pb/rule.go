@@ -18,6 +18,7 @@
 package pb
 
 import (
+	"fmt"
 	"sync/atomic"
 
 	"github.com/golang/protobuf/proto"
@@ -43,6 +44,25 @@ var eventTypeToAssistant = map[model.EventType]ServiceRuleAssistant{
 	model.EventRateLimiting:   &RateLimitingAssistant{},
 	model.EventCircuitBreaker: &CircuitBreakAssistant{},
 	model.EventFaultDetect:    &FaultDetectAssistant{},
+	model.EventLane:           &LaneAssistant{},
+}
+
+// RegisterRuleAssistant 注册规则解析助手，插件可通过该方法为新的规则类型提供解析及校验能力
+// 需要在init阶段调用，重复注册会直接panic以便快速发现问题
+func RegisterRuleAssistant(eventType model.EventType, assistant ServiceRuleAssistant) {
+	if nil == assistant {
+		panic(fmt.Sprintf("rule assistant for event type %s can not be nil", eventType))
+	}
+	if _, ok := eventTypeToAssistant[eventType]; ok {
+		panic(fmt.Sprintf("duplicate register rule assistant for event type %s", eventType))
+	}
+	eventTypeToAssistant[eventType] = assistant
+}
+
+// GetRuleAssistant 获取规则类型对应的解析助手
+func GetRuleAssistant(eventType model.EventType) (ServiceRuleAssistant, bool) {
+	assistant, ok := eventTypeToAssistant[eventType]
+	return assistant, ok
 }
 
 // ServiceRuleInProto 路由规则配置对象.
@@ -86,7 +106,13 @@ func NewServiceRuleInProtoWithInitializeStatus(resp *apiservice.DiscoverResponse
 		value.notExists = true
 	}
 	value.eventType = GetEventType(resp.GetType())
-	value.assistant = eventTypeToAssistant[value.eventType]
+	assistant, ok := eventTypeToAssistant[value.eventType]
+	if !ok {
+		log.GetBaseLogger().Errorf("rule assistant not found for rule %s, type %v", value.ServiceKey, value.eventType)
+		value.ruleCache = model.NewRuleCache()
+		return value
+	}
+	value.assistant = assistant
 	value.ruleValue, value.revision = value.assistant.ParseRuleValue(resp)
 	if len(value.revision) > 0 {
 		var err error
@@ -107,6 +133,9 @@ func (s *ServiceRuleInProto) IsCacheLoaded() bool {
 
 // ValidateAndBuildCache 校验路由规则，以及构建正则表达式缓存.
 func (s *ServiceRuleInProto) ValidateAndBuildCache() error {
+	if nil == s.assistant {
+		return nil
+	}
 	s.assistant.SetDefault(s.ruleValue)
 	if err := s.assistant.Validate(s.ruleValue, s.ruleCache); err != nil {
 		// 缓存规则解释失败异常
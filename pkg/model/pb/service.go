@@ -24,6 +24,7 @@ import (
 	"sort"
 	"strconv"
 	"sync/atomic"
+	"time"
 
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
@@ -37,6 +38,9 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
 )
 
+// ctimeLayout 服务端返回的实例创建时间格式
+const ctimeLayout = "2006-01-02 15:04:05"
+
 // SvcPluginValues 服务级插件值.
 type SvcPluginValues struct {
 	Routers      *servicerouter.RouterChain
@@ -282,6 +286,8 @@ type InstanceInProto struct {
 	localValue local.InstanceLocalValue
 	// 保存单个实例的数组引用
 	singleInstances []model.Instance
+	// 实例的注册时间
+	createTime time.Time
 }
 
 // NewInstanceInProto InstanceInProto的构造函数.
@@ -301,9 +307,25 @@ func NewInstanceInProto(
 		Port: int(instance.GetPort().GetValue()),
 	}
 	instInProto.singleInstances = []model.Instance{instInProto}
+	instInProto.createTime = parseCreateTime(instance)
 	return instInProto
 }
 
+// parseCreateTime 解析实例的注册时间，优先使用metadata中记录的时间戳，其次为服务端的创建时间
+func parseCreateTime(instance *apiservice.Instance) time.Time {
+	if value, ok := instance.GetMetadata()[model.MetadataKeyRegisterTime]; ok {
+		if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return time.Unix(0, millis*int64(time.Millisecond))
+		}
+	}
+	if ctime := instance.GetCtime().GetValue(); len(ctime) > 0 {
+		if createTime, err := time.ParseInLocation(ctimeLayout, ctime, time.Local); err == nil {
+			return createTime
+		}
+	}
+	return time.Time{}
+}
+
 // GetNamespace 命名空间.
 func (i *InstanceInProto) GetNamespace() string {
 	return i.instanceKey.Namespace
@@ -444,6 +466,26 @@ func (i *InstanceInProto) SingleInstances() []model.Instance {
 	return i.singleInstances
 }
 
+// GetCreateTime 获取实例的注册时间
+func (i *InstanceInProto) GetCreateTime() time.Time {
+	return i.createTime
+}
+
+// GetMetaInt 将元数据中的值解析为整数
+func (i *InstanceInProto) GetMetaInt(key string, defaultValue int64) int64 {
+	return model.MetadataInt(i.GetMetadata(), key, defaultValue)
+}
+
+// GetMetaBool 将元数据中的值解析为布尔值
+func (i *InstanceInProto) GetMetaBool(key string, defaultValue bool) bool {
+	return model.MetadataBool(i.GetMetadata(), key, defaultValue)
+}
+
+// GetMetaJSON 将元数据中的JSON值反序列化到value中
+func (i *InstanceInProto) GetMetaJSON(key string, value interface{}) error {
+	return model.MetadataJSON(i.GetMetadata(), key, value)
+}
+
 // GetTtl 获取实例设置的 TTL
 func (i *InstanceInProto) GetTtl() int64 {
 	return int64(i.GetHealthCheck().GetHeartbeat().GetTtl().GetValue())
@@ -494,6 +536,7 @@ func (i *InstanceInProto) DeepClone() model.Instance {
 			Port: int(i.GetPort()),
 		},
 		localValue: i.GetInstanceLocalValue(),
+		createTime: i.createTime,
 	}
 	copyIns.singleInstances = []model.Instance{copyIns}
 	return copyIns
@@ -509,6 +552,20 @@ func GenServicesRevision(services []*apiservice.Service) string {
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
+// GenInstancesRevision 根据实例版本号计算服务实例列表的版本号，与实例顺序无关.
+func GenInstancesRevision(instances []*apiservice.Instance) string {
+	revisions := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		revisions = append(revisions, instance.GetRevision().GetValue())
+	}
+	sort.Strings(revisions)
+	h := md5.New()
+	for _, revision := range revisions {
+		io.WriteString(h, revision)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
 // ServicesProto 批量服务.
 type ServicesProto struct {
 	initialized bool
@@ -35,6 +35,7 @@ var (
 		model.EventServices:       apiservice.DiscoverRequest_SERVICES,
 		model.EventCircuitBreaker: apiservice.DiscoverRequest_CIRCUIT_BREAKER,
 		model.EventFaultDetect:    apiservice.DiscoverRequest_FAULT_DETECTOR,
+		model.EventLane:           apiservice.DiscoverRequest_LANE,
 	}
 
 	protoRespTypeToEventType = map[apiservice.DiscoverResponse_DiscoverResponseType]model.EventType{
@@ -44,9 +45,24 @@ var (
 		apiservice.DiscoverResponse_SERVICES:        model.EventServices,
 		apiservice.DiscoverResponse_CIRCUIT_BREAKER: model.EventCircuitBreaker,
 		apiservice.DiscoverResponse_FAULT_DETECTOR:  model.EventFaultDetect,
+		apiservice.DiscoverResponse_LANE:            model.EventLane,
 	}
 )
 
+// RegisterEventProtoType 注册自定义规则类型与discover协议请求/应答类型的映射关系
+// 需要在init阶段调用，重复注册会直接panic以便快速发现问题
+func RegisterEventProtoType(event model.EventType, reqType apiservice.DiscoverRequest_DiscoverRequestType,
+	respType apiservice.DiscoverResponse_DiscoverResponseType) {
+	if _, ok := eventTypeToProtoRequestType[event]; ok {
+		panic(fmt.Sprintf("duplicate register proto request type for event type %s", event))
+	}
+	if _, ok := protoRespTypeToEventType[respType]; ok {
+		panic(fmt.Sprintf("duplicate register proto response type %v", respType))
+	}
+	eventTypeToProtoRequestType[event] = reqType
+	protoRespTypeToEventType[respType] = event
+}
+
 // GetProtoRequestType 通过事件类型获取请求类型
 func GetProtoRequestType(event model.EventType) apiservice.DiscoverRequest_DiscoverRequestType {
 	if reqType, ok := eventTypeToProtoRequestType[event]; ok {
@@ -55,6 +71,26 @@ func GetProtoRequestType(event model.EventType) apiservice.DiscoverRequest_Disco
 	return apiservice.DiscoverRequest_UNKNOWN
 }
 
+// GetEventTypeByRequest 通过请求类型获取事件类型
+func GetEventTypeByRequest(reqType apiservice.DiscoverRequest_DiscoverRequestType) model.EventType {
+	for eventType, protoReqType := range eventTypeToProtoRequestType {
+		if protoReqType == reqType {
+			return eventType
+		}
+	}
+	return model.EventUnknown
+}
+
+// GetProtoResponseType 通过事件类型获取应答类型
+func GetProtoResponseType(event model.EventType) apiservice.DiscoverResponse_DiscoverResponseType {
+	for respType, eventType := range protoRespTypeToEventType {
+		if eventType == event {
+			return respType
+		}
+	}
+	return apiservice.DiscoverResponse_UNKNOWN
+}
+
 // GetEventType 通过应答类型获取事件类型
 func GetEventType(respType apiservice.DiscoverResponse_DiscoverResponseType) model.EventType {
 	if eventType, ok := protoRespTypeToEventType[respType]; ok {
@@ -0,0 +1,110 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"sync"
+	"time"
+)
+
+// PrefetchRequest 缓存预热请求
+type PrefetchRequest struct {
+	// Services 需要预热的服务列表
+	Services []ServiceKey
+	// SkipRules 是否跳过路由及限流规则的预热
+	SkipRules bool
+	// Concurrency 可选，并发预热的服务数，默认10
+	Concurrency int
+	// Timeout 可选，单个资源的查询超时时间，默认使用全局超时配置
+	Timeout *time.Duration
+}
+
+// Validate 校验请求
+func (r *PrefetchRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "PrefetchRequest can not be nil")
+	}
+	if len(r.Services) == 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "PrefetchRequest: services can not be empty")
+	}
+	for _, svc := range r.Services {
+		if len(svc.Namespace) == 0 || len(svc.Service) == 0 {
+			return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+				"PrefetchRequest: namespace and service can not be empty, got %s", svc)
+		}
+	}
+	if r.Concurrency < 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "PrefetchRequest: concurrency can not be negative")
+	}
+	return nil
+}
+
+// PrefetchResponse 缓存预热结果，预热在后台进行，通过Ready获取完成通知
+type PrefetchResponse struct {
+	ready  chan struct{}
+	mutex  sync.Mutex
+	errors map[ServiceKey]error
+}
+
+// NewPrefetchResponse 创建预热结果
+func NewPrefetchResponse() *PrefetchResponse {
+	return &PrefetchResponse{
+		ready:  make(chan struct{}),
+		errors: make(map[ServiceKey]error),
+	}
+}
+
+// Ready 所有服务预热结束后关闭，无论成功与否
+func (r *PrefetchResponse) Ready() <-chan struct{} {
+	return r.ready
+}
+
+// Wait 等待预热结束，超时返回false
+func (r *PrefetchResponse) Wait(timeout time.Duration) bool {
+	select {
+	case <-r.ready:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// Errors 预热失败的服务及原因，需在Ready后读取
+func (r *PrefetchResponse) Errors() map[ServiceKey]error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	errs := make(map[ServiceKey]error, len(r.errors))
+	for svc, err := range r.errors {
+		errs[svc] = err
+	}
+	return errs
+}
+
+// AddError 记录服务预热失败
+func (r *PrefetchResponse) AddError(svc ServiceKey, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.errors[svc]; !ok {
+		r.errors[svc] = err
+	}
+}
+
+// Finish 预热结束
+func (r *PrefetchResponse) Finish() {
+	close(r.ready)
+}
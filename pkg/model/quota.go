@@ -20,6 +20,7 @@ package model
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -27,6 +28,7 @@ import (
 
 // QuotaRequestImpl 配额获取的请求.
 type QuotaRequestImpl struct {
+	callContext
 	// 必选，命名空间
 	namespace string
 	// 必选，服务名
@@ -42,6 +44,8 @@ type QuotaRequestImpl struct {
 	RetryCount *int
 	// 可选，获取的配额数
 	Token uint32
+	// 可选，匀速排队模式下的最大排队时间，设置后配额不足时不直接拒绝，而是返回需要等待的时间
+	MaxQueueTime *time.Duration
 }
 
 // GetService 获取服务名.
@@ -121,6 +125,16 @@ func (q *QuotaRequestImpl) SetRetryCount(retryCount int) {
 	q.RetryCount = &retryCount
 }
 
+// SetThrottling 开启匀速排队模式，请求按照规则阈值匀速通过，排队时间超过maxQueueTime时才拒绝.
+func (q *QuotaRequestImpl) SetThrottling(maxQueueTime time.Duration) {
+	q.MaxQueueTime = &maxQueueTime
+}
+
+// GetMaxQueueTimePtr 获取匀速排队最大排队时间指针.
+func (q *QuotaRequestImpl) GetMaxQueueTimePtr() *time.Duration {
+	return q.MaxQueueTime
+}
+
 // GetTimeoutPtr 获取超时值指针.
 func (q *QuotaRequestImpl) GetTimeoutPtr() *time.Duration {
 	return q.Timeout
@@ -143,6 +157,9 @@ func (q *QuotaRequestImpl) Validate() error {
 	if len(q.GetNamespace()) == 0 {
 		errs = multierror.Append(errs, fmt.Errorf("QuotaRequestImpl: namespace is empty"))
 	}
+	if q.MaxQueueTime != nil && *q.MaxQueueTime < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("QuotaRequestImpl: maxQueueTime must not be negative"))
+	}
 	return errs
 }
 
@@ -164,6 +181,8 @@ type QuotaResponse struct {
 	Info string
 	// 需要等待的时间段
 	WaitMs int64
+	// 影子模式下本应触发限流的规则名，不为空时表示请求本应被限流
+	ShadowRuleName string
 }
 
 // QuotaFutureImpl 异步获取配额的future.
@@ -171,6 +190,9 @@ type QuotaFutureImpl struct {
 	resp        *QuotaResponse
 	deadlineCtx context.Context
 	cancel      context.CancelFunc
+	// 配额释放时的回调
+	releaseFuncs []func()
+	releaseOnce  sync.Once
 }
 
 func QuotaFutureWithResponse(resp *QuotaResponse) *QuotaFutureImpl {
@@ -183,10 +205,17 @@ func QuotaFutureWithResponse(resp *QuotaResponse) *QuotaFutureImpl {
 		resp: resp, deadlineCtx: deadlineCtx, cancel: cancel}
 }
 
+// 已经关闭的channel，用于无需等待的分配结果
+var closedChan = make(chan struct{})
+
+func init() {
+	close(closedChan)
+}
+
 // Done 分配是否结束.
 func (q *QuotaFutureImpl) Done() <-chan struct{} {
-	if nil != q.deadlineCtx {
-		return nil
+	if nil == q.deadlineCtx {
+		return closedChan
 	}
 	return q.deadlineCtx.Done()
 }
@@ -204,8 +233,18 @@ func (q *QuotaFutureImpl) Get() *QuotaResponse {
 	return q.resp
 }
 
-// Release 释放资源，仅用于并发数限流的场景.
+// AddReleaseFunc 添加配额释放时的回调.
+func (q *QuotaFutureImpl) AddReleaseFunc(releaseFunc func()) {
+	q.releaseFuncs = append(q.releaseFuncs, releaseFunc)
+}
+
+// Release 释放配额，分配成功后必须在请求结束时调用，多次调用只会释放一次.
 func (q *QuotaFutureImpl) Release() {
+	q.releaseOnce.Do(func() {
+		for _, releaseFunc := range q.releaseFuncs {
+			releaseFunc()
+		}
+	})
 }
 
 const (
@@ -0,0 +1,172 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	stdregexp "regexp"
+	"regexp/syntax"
+	"sync/atomic"
+	"time"
+
+	regexp "github.com/dlclark/regexp2"
+)
+
+const (
+	// RegexEngineRegexp2 基于dlclark/regexp2的正则引擎，支持环视等扩展语法，匹配存在回溯
+	RegexEngineRegexp2 = "regexp2"
+	// RegexEngineStd 基于标准库regexp的正则引擎，线性时间匹配，只支持RE2语法
+	RegexEngineStd = "std"
+)
+
+// RegexOptions 规则中正则表达式的编译选项
+type RegexOptions struct {
+	// Engine 正则引擎
+	Engine string
+	// MaxLength 表达式最大长度，为0表示不限制
+	MaxLength int
+	// MatchTimeout regexp2引擎单次匹配的超时时间，为0表示不限制
+	MatchTimeout time.Duration
+}
+
+var regexOptions atomic.Value
+
+func init() {
+	regexOptions.Store(RegexOptions{Engine: RegexEngineRegexp2})
+}
+
+// SetRegexOptions 设置正则表达式的编译选项，对之后编译的表达式生效
+// 规则缓存不感知SDK上下文，因此该选项为进程级别，存在多个SDK上下文时以最后设置的为准
+func SetRegexOptions(options RegexOptions) {
+	regexOptions.Store(options)
+}
+
+// GetRegexOptions 获取正则表达式的编译选项
+func GetRegexOptions() RegexOptions {
+	return regexOptions.Load().(RegexOptions)
+}
+
+// RegexMatcher 正则匹配器，屏蔽不同正则引擎的差异
+type RegexMatcher interface {
+	// MatchString 是否能在s中找到非空的匹配，匹配出错或者超时均视为不匹配
+	MatchString(s string) bool
+	// String 原始表达式
+	String() string
+}
+
+// CompileRegex 按当前的编译选项编译正则表达式
+func CompileRegex(pattern string) (RegexMatcher, error) {
+	options := GetRegexOptions()
+	if err := checkRegexComplexity(pattern, options); err != nil {
+		return nil, err
+	}
+	if options.Engine == RegexEngineStd {
+		regexObj, err := stdregexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex expression %s, error is %v", pattern, err)
+		}
+		return &stdMatcher{regexObj: regexObj}, nil
+	}
+	regexObj, err := compileRegexp2(pattern, options)
+	if err != nil {
+		return nil, err
+	}
+	return &regexp2Matcher{regexObj: regexObj}, nil
+}
+
+// compileRegexp2 编译regexp2表达式并设置匹配超时
+func compileRegexp2(pattern string, options RegexOptions) (*regexp.Regexp, error) {
+	regexObj, err := regexp.Compile(pattern, regexp.RE2)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex expression %s, error is %v", pattern, err)
+	}
+	if options.MatchTimeout > 0 {
+		regexObj.MatchTimeout = options.MatchTimeout
+	}
+	return regexObj, nil
+}
+
+// checkRegexComplexity 校验表达式的长度，以及是否存在嵌套的重复量词，如(a+)+，这类表达式在回溯引擎下可能出现指数级的匹配耗时
+func checkRegexComplexity(pattern string, options RegexOptions) error {
+	if options.MaxLength > 0 && len(pattern) > options.MaxLength {
+		return fmt.Errorf("regex expression %s exceeds max length %d", pattern, options.MaxLength)
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		// 标准库不支持的扩展语法，交由引擎自身编译校验，regexp2依赖匹配超时兜底
+		return nil
+	}
+	if hasNestedRepeat(parsed, false) {
+		return fmt.Errorf("regex expression %s contains nested quantifiers", pattern)
+	}
+	return nil
+}
+
+// hasNestedRepeat 判断表达式中是否存在嵌套的可变长重复
+func hasNestedRepeat(re *syntax.Regexp, inRepeat bool) bool {
+	repeat := false
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		repeat = true
+	case syntax.OpRepeat:
+		repeat = re.Max == -1 || re.Max > re.Min
+	}
+	if repeat && inRepeat {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasNestedRepeat(sub, inRepeat || repeat) {
+			return true
+		}
+	}
+	return false
+}
+
+// regexp2Matcher 基于regexp2的匹配器
+type regexp2Matcher struct {
+	regexObj *regexp.Regexp
+}
+
+// MatchString 是否能找到非空的匹配
+func (m *regexp2Matcher) MatchString(s string) bool {
+	matched, err := m.regexObj.FindStringMatch(s)
+	if err != nil || nil == matched {
+		return false
+	}
+	return matched.String() != ""
+}
+
+// String 原始表达式
+func (m *regexp2Matcher) String() string {
+	return m.regexObj.String()
+}
+
+// stdMatcher 基于标准库regexp的匹配器
+type stdMatcher struct {
+	regexObj *stdregexp.Regexp
+}
+
+// MatchString 是否能找到非空的匹配
+func (m *stdMatcher) MatchString(s string) bool {
+	return m.regexObj.FindString(s) != ""
+}
+
+// String 原始表达式
+func (m *stdMatcher) String() string {
+	return m.regexObj.String()
+}
@@ -0,0 +1,56 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package model
+
+import (
+	"context"
+)
+
+// InstanceCallFunction 对选中的服务实例发起一次调用，返回业务返回码，error不为nil表示本次调用失败
+type InstanceCallFunction func(ctx context.Context, instance Instance) (int32, error)
+
+// InvokeWithRetryRequest 带重试的服务调用请求，实例选择参数与GetOneInstance一致
+type InvokeWithRetryRequest struct {
+	GetOneInstanceRequest
+	// Context 可选，整体调用的上下文，取消后不再发起重试
+	Context context.Context
+	// Method 可选，被调方法，用于调用结果上报
+	Method string
+	// Call 必选，实际发起调用的函数
+	Call InstanceCallFunction
+}
+
+// Validate 校验请求
+func (r *InvokeWithRetryRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "InvokeWithRetryRequest can not be nil")
+	}
+	if r.Call == nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "InvokeWithRetryRequest: call function can not be nil")
+	}
+	return r.GetOneInstanceRequest.Validate()
+}
+
+// InvokeWithRetryResponse 带重试的服务调用结果
+type InvokeWithRetryResponse struct {
+	// Instance 最后一次调用的服务实例
+	Instance Instance
+	// RetCode 最后一次调用的返回码
+	RetCode int32
+	// Attempts 实际调用次数，包含首次调用
+	Attempts int
+}
@@ -18,8 +18,8 @@
 package model
 
 import (
-	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	regexp "github.com/dlclark/regexp2"
@@ -69,8 +69,10 @@ type ServiceRule interface {
 
 // RuleCache 服务规则缓存.
 type RuleCache interface {
-	// 通过字面值获取表达式对象
+	// 通过字面值获取regexp2表达式对象
 	GetRegexMatcher(message string) (*regexp.Regexp, error)
+	// 通过字面值获取按配置的正则引擎编译的匹配器
+	GetMatcher(message string) (RegexMatcher, error)
 	// 获取消息缓存
 	GetMessageCache(message proto.Message) interface{}
 	// 设置消息缓存
@@ -79,42 +81,77 @@ type RuleCache interface {
 
 // NewRuleCache 创建规则缓存对象.
 func NewRuleCache() RuleCache {
-	return &ruleCache{
-		regexMatchers: make(map[string]*regexp.Regexp),
+	cache := &ruleCache{
 		messageCaches: make(map[proto.Message]interface{}),
 	}
+	cache.regexMatchers.Store(map[string]*regexp.Regexp{})
+	cache.matchers.Store(map[string]RegexMatcher{})
+	return cache
 }
 
-// ruleCache 路由规则缓存实现.
+// ruleCache 路由规则缓存实现，读取时原子加载不可变的快照，新增表达式时复制后整体替换，读路径无锁.
 type ruleCache struct {
-	mutex         sync.RWMutex
-	regexMatchers map[string]*regexp.Regexp
+	// 写入互斥，保证编译及替换只执行一次
+	mutex sync.Mutex
+	// 类型为map[string]*regexp.Regexp
+	regexMatchers atomic.Value
+	// 类型为map[string]RegexMatcher
+	matchers      atomic.Value
 	messageCaches map[proto.Message]interface{}
 }
 
 // GetRegexMatcher 通过字面值获取表达式对象.
 func (r *ruleCache) GetRegexMatcher(message string) (*regexp.Regexp, error) {
-	r.mutex.RLock()
-	regexObj, ok := r.regexMatchers[message]
-	r.mutex.RUnlock()
-	if ok {
+	if regexObj, ok := r.regexMatchers.Load().(map[string]*regexp.Regexp)[message]; ok {
 		return regexObj, nil
 	}
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
-	regexObj, ok = r.regexMatchers[message]
-	if ok {
+	current := r.regexMatchers.Load().(map[string]*regexp.Regexp)
+	if regexObj, ok := current[message]; ok {
 		return regexObj, nil
 	}
-	var err error
-	regexObj, err = regexp.Compile(message, regexp.RE2)
+	options := GetRegexOptions()
+	if err := checkRegexComplexity(message, options); err != nil {
+		return nil, err
+	}
+	regexObj, err := compileRegexp2(message, options)
 	if err != nil {
-		return nil, fmt.Errorf("invalid regex expression %s, error is %v", message, err)
+		return nil, err
+	}
+	next := make(map[string]*regexp.Regexp, len(current)+1)
+	for key, value := range current {
+		next[key] = value
 	}
-	r.regexMatchers[message] = regexObj
+	next[message] = regexObj
+	r.regexMatchers.Store(next)
 	return regexObj, nil
 }
 
+// GetMatcher 通过字面值获取匹配器.
+func (r *ruleCache) GetMatcher(message string) (RegexMatcher, error) {
+	if matcher, ok := r.matchers.Load().(map[string]RegexMatcher)[message]; ok {
+		return matcher, nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	current := r.matchers.Load().(map[string]RegexMatcher)
+	if matcher, ok := current[message]; ok {
+		return matcher, nil
+	}
+	matcher, err := CompileRegex(message)
+	if err != nil {
+		return nil, err
+	}
+	next := make(map[string]RegexMatcher, len(current)+1)
+	for key, value := range current {
+		next[key] = value
+	}
+	next[message] = matcher
+	r.matchers.Store(next)
+	return matcher, nil
+}
+
 // GetMessageCache 获取hash值.
 func (r *ruleCache) GetMessageCache(message proto.Message) interface{} {
 	return r.messageCaches[message]
@@ -127,6 +164,7 @@ func (r *ruleCache) SetMessageCache(message proto.Message, cacheValue interface{
 
 // GetServiceRuleRequest 获取服务规则请求.
 type GetServiceRuleRequest struct {
+	callContext
 	// 可选，流水号，用于跟踪用户的请求，默认0
 	FlowID uint64
 	// 命名空间
@@ -26,6 +26,8 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/go-multierror"
 	"github.com/modern-go/reflect2"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/version"
 )
 
 // RunMode SDK的运行模式，可以指定为agent或者no-agent模式
@@ -104,6 +106,12 @@ type Instance interface {
 	GetPriority() uint32
 	// GetMetadata 实例元数据信息
 	GetMetadata() map[string]string
+	// GetMetaInt 将元数据中的值解析为整数，键不存在或解析失败时返回默认值
+	GetMetaInt(key string, defaultValue int64) int64
+	// GetMetaBool 将元数据中的值解析为布尔值，键不存在或解析失败时返回默认值
+	GetMetaBool(key string, defaultValue bool) bool
+	// GetMetaJSON 将元数据中的JSON值反序列化到value中，键不存在时返回ErrMetadataKeyNotFound
+	GetMetaJSON(key string, value interface{}) error
 	// GetLogicSet 实例逻辑分区
 	GetLogicSet() string
 	// GetCircuitBreakerStatus 实例的断路器状态，包括：
@@ -129,12 +137,18 @@ type Instance interface {
 	GetRevision() string
 	// GetTtl 获取实例设置的 TTL
 	GetTtl() int64
+	// GetCreateTime 获取实例的注册时间，优先取metadata中的MetadataKeyRegisterTime，其次为服务端的创建时间
+	// 均不存在时返回零值
+	GetCreateTime() time.Time
 	// SetHealthy
 	SetHealthy(status bool)
 	// DeepClone deep clone Instance
 	DeepClone() Instance
 }
 
+// MetadataKeyRegisterTime 实例注册时间的metadata键，值为毫秒级时间戳
+const MetadataKeyRegisterTime = "polaris.register.time"
+
 // InstanceWeight 节点权重
 type InstanceWeight struct {
 	// 实例ID
@@ -165,6 +179,7 @@ type FailOverDefaultMetaConfig struct {
 
 // GetOneInstanceRequest 单个服务实例查询请求
 type GetOneInstanceRequest struct {
+	callContext
 	// 可选，流水号，用于跟踪用户的请求，默认0
 	FlowID uint64
 	// 必选，服务名
@@ -182,6 +197,8 @@ type GetOneInstanceRequest struct {
 	// 已经计算好的hash值，用于一致性hash的负载均衡选择
 	// Deprecated: 已弃用，请直接使用HashKey参数传入key来计算hash
 	HashValue uint64
+	// 可选，会话标识，用于会话保持的负载均衡，相同会话标识的请求会选择到相同实例
+	SessionKey string
 	// 主调方服务信息
 	SourceService *ServiceInfo
 	// 路由标签参数
@@ -200,8 +217,14 @@ type GetOneInstanceRequest struct {
 	LbPolicy string
 	// 金丝雀
 	Canary string
+	// 可选，金丝雀按比例分流的hash key，如用户ID、traceID，相同key会稳定地落在同一版本上
+	CanaryHashKey string
+	// 可选，目标实例的版本约束，如">=1.2.0 <2.0.0"，需要启用versionRouter
+	TargetVersion string
 	// 可选，是否包含被熔断的服务实例，默认false
 	IncludeCircuitBreakInstances bool
+	// 可选，按顺序指定本次请求使用的路由插件名，覆盖全局配置的consumer.serviceRouter.chain
+	Routers []string
 }
 
 // SetTimeout 设置超时时间
@@ -214,6 +237,11 @@ func (g *GetOneInstanceRequest) SetRetryCount(retryCount int) {
 	g.RetryCount = &retryCount
 }
 
+// Reset 重置请求的全部字段，请求被复用前必须清空，否则复用方会读到上一次调用遗留的参数
+func (g *GetOneInstanceRequest) Reset() {
+	*g = GetOneInstanceRequest{}
+}
+
 // GetService 获取服务名
 func (g *GetOneInstanceRequest) GetService() string {
 	return g.Service
@@ -254,6 +282,11 @@ func (g *GetOneInstanceRequest) SetCanary(canary string) {
 	g.Canary = canary
 }
 
+// SetCanaryHashKey 设置金丝雀按比例分流的hash key
+func (g *GetOneInstanceRequest) SetCanaryHashKey(key string) {
+	g.CanaryHashKey = key
+}
+
 // AddArguments .
 func (g *GetOneInstanceRequest) AddArguments(argumet ...Argument) {
 	if len(g.Arguments) == 0 {
@@ -271,11 +304,39 @@ func (g *GetOneInstanceRequest) Validate() error {
 		return NewSDKError(ErrCodeAPIInvalidArgument, err,
 			"fail to validate GetInstancesRequest")
 	}
+	if err := validateTargetVersion(g.TargetVersion); err != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, err,
+			"fail to validate GetOneInstanceRequest")
+	}
+	if err := validateRouters(g.Routers); err != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, err,
+			"fail to validate GetOneInstanceRequest")
+	}
+	return nil
+}
+
+// validateRouters 校验请求指定的路由链
+func validateRouters(routers []string) error {
+	for i, router := range routers {
+		if len(router) == 0 {
+			return fmt.Errorf("routers[%d] is empty", i)
+		}
+	}
 	return nil
 }
 
+// validateTargetVersion 校验版本约束是否合法
+func validateTargetVersion(targetVersion string) error {
+	if len(targetVersion) == 0 {
+		return nil
+	}
+	_, err := version.ParseConstraint(targetVersion)
+	return err
+}
+
 // GetAllInstancesRequest 获取所有实例的请求
 type GetAllInstancesRequest struct {
+	callContext
 	// 可选，流水号，用于跟踪用户的请求，默认0
 	FlowID uint64
 	// 必选，服务名
@@ -345,6 +406,7 @@ func (g *GetAllInstancesRequest) Validate() error {
 
 // GetInstancesRequest 批量服务实例查询请求
 type GetInstancesRequest struct {
+	callContext
 	// 可选，流水号，用于跟踪用户的请求，默认0
 	FlowID uint64
 	// 必选，服务名
@@ -374,6 +436,18 @@ type GetInstancesRequest struct {
 	response InstancesResponse
 	// 金丝雀
 	Canary string
+	// 可选，金丝雀按比例分流的hash key，如用户ID、traceID，相同key会稳定地落在同一版本上
+	CanaryHashKey string
+	// 可选，目标实例的版本约束，如">=1.2.0 <2.0.0"，需要启用versionRouter
+	TargetVersion string
+	// 可选，按顺序指定本次请求使用的路由插件名，覆盖全局配置的consumer.serviceRouter.chain
+	Routers []string
+	// 是否开启元数据匹配不到时启用自定义匹配规则，仅用于dstMetadata路由插件
+	EnableFailOverDefaultMeta bool
+	// 自定义匹配规则，仅当EnableFailOverDefaultMeta为true时生效
+	FailOverDefaultMeta FailOverDefaultMetaConfig
+	// 可选，数据新鲜度要求，默认使用已就绪的缓存
+	Freshness *Freshness
 }
 
 // SetTimeout 设置超时时间
@@ -426,6 +500,11 @@ func (g *GetInstancesRequest) SetCanary(canary string) {
 	g.Canary = canary
 }
 
+// SetCanaryHashKey 设置金丝雀按比例分流的hash key
+func (g *GetInstancesRequest) SetCanaryHashKey(key string) {
+	g.CanaryHashKey = key
+}
+
 // AddArguments .
 func (g *GetInstancesRequest) AddArguments(argumet ...Argument) {
 	if len(g.Arguments) == 0 {
@@ -443,6 +522,18 @@ func (g *GetInstancesRequest) Validate() error {
 		return NewSDKError(ErrCodeAPIInvalidArgument, err,
 			"fail to validate GetInstancesRequest")
 	}
+	if err := g.Freshness.Validate(); err != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, err,
+			"fail to validate GetInstancesRequest")
+	}
+	if err := validateTargetVersion(g.TargetVersion); err != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, err,
+			"fail to validate GetInstancesRequest")
+	}
+	if err := validateRouters(g.Routers); err != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, err,
+			"fail to validate GetInstancesRequest")
+	}
 	return nil
 }
 
@@ -618,6 +709,8 @@ func (i *ServiceInfo) HasService() bool {
 // OneInstanceResponse 单个服务实例
 type OneInstanceResponse struct {
 	InstancesResponse
+	// 是否为实例全部熔断后由降级函数生成的应答
+	Fallback bool
 }
 
 // GetInstance get the only instance
@@ -756,6 +849,8 @@ type ServiceCallResult struct {
 	RuleName string
 	// 可选，主调服务实例的服务信息
 	SourceService *ServiceInfo
+	// 可选，对冲请求标识，同一标识下只有最先上报的结果会计入调用统计及熔断统计
+	HedgeID string
 }
 
 // RateLimitGauge Rate Limit Gauge
@@ -767,6 +862,8 @@ type RateLimitGauge struct {
 	Arguments []Argument
 	Result    QuotaResultCode
 	RuleName  string
+	// 是否为影子模式下本应被限流的请求
+	ShadowLimited bool
 }
 
 // CircuitBreakGauge Circuit Break Gauge
@@ -795,6 +892,54 @@ func (cbg *CircuitBreakGauge) Validate() error {
 	return NewSDKError(ErrCodeAPIInvalidArgument, nil, "empty change instance")
 }
 
+// CacheGauge 本地缓存命中统计
+type CacheGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	Service   string
+	// 是否直接命中本地缓存，无需等待远程加载
+	Hit bool
+	// 本次上报代表的查询次数，命中为采样上报，为0时视为1
+	Count uint32
+}
+
+// GetNamespace 获取服务的命名空间
+func (c *CacheGauge) GetNamespace() string {
+	return c.Namespace
+}
+
+// GetService 获取服务名
+func (c *CacheGauge) GetService() string {
+	return c.Service
+}
+
+// GetCount 获取本次上报代表的查询次数
+func (c *CacheGauge) GetCount() uint32 {
+	if c.Count == 0 {
+		return 1
+	}
+	return c.Count
+}
+
+// SDKHealthGauge SDK 自身的运行状态，用于在业务流量受影响前发现 SDK 的异常
+type SDKHealthGauge struct {
+	EmptyInstanceGauge
+	// CachedServices 本地缓存中已加载实例的服务数
+	CachedServices int
+	// CachedInstances 本地缓存中的实例总数
+	CachedInstances int
+	// PendingDiscoverRequests 已发送但尚未收到应答的服务发现请求数
+	PendingDiscoverRequests int
+	// ReconnectCount 与服务端重新建立连接的累计次数
+	ReconnectCount int64
+	// PersistFailures 本地缓存持久化失败的累计次数
+	PersistFailures int64
+	// TaskQueueDepths 各任务队列中等待处理的任务数，key 为队列名
+	TaskQueueDepths map[string]int
+	// Goroutines 进程当前的协程数
+	Goroutines int
+}
+
 // APICallKey API调用的唯一标识
 type APICallKey struct {
 	// 调用的API接口名字
@@ -913,6 +1058,17 @@ func (s *ServiceCallResult) GetRetStatus() RetStatus {
 	return s.RetStatus
 }
 
+// SetHedgeID 设置对冲请求标识
+func (s *ServiceCallResult) SetHedgeID(hedgeID string) *ServiceCallResult {
+	s.HedgeID = hedgeID
+	return s
+}
+
+// GetHedgeID 获取对冲请求标识
+func (s *ServiceCallResult) GetHedgeID() string {
+	return s.HedgeID
+}
+
 // GetCallerService 获取主调服务实例的服务信息
 func (s *ServiceCallResult) GetCallerService() string {
 	if s.SourceService != nil {
@@ -992,6 +1148,7 @@ func (a *APICallResult) GetDelayRange() ApiDelayRange {
 
 // InstanceHeartbeatRequest 心跳上报请求
 type InstanceHeartbeatRequest struct {
+	callContext
 	// 必选，服务名
 	Service string
 	// 必选，服务访问Token
@@ -1069,6 +1226,7 @@ func (g *InstanceHeartbeatRequest) Validate() error {
 
 // InstanceDeRegisterRequest 反注册服务请求
 type InstanceDeRegisterRequest struct {
+	callContext
 	// 服务名
 	Service string
 	// 服务访问Token
@@ -1144,6 +1302,114 @@ func (g *InstanceDeRegisterRequest) Validate() error {
 	return nil
 }
 
+// InstanceGracefulDeregisterRequest 优雅下线请求，先将实例置为隔离，排空在途请求后再反注册
+type InstanceGracefulDeregisterRequest struct {
+	InstanceDeRegisterRequest
+	// 可选，实例注册时的请求，用于将实例置为隔离，为空时使用自动心跳任务记录的注册信息
+	Instance *InstanceRegisterRequest
+	// 可选，排空等待的最大时长，默认使用 provider.drainPeriod 配置
+	DrainPeriod *time.Duration
+	// 可选，返回当前的在途请求数，返回值不大于0时提前结束等待
+	InFlightRequests func() int64
+}
+
+// SetDrainPeriod 设置排空等待的最大时长
+func (g *InstanceGracefulDeregisterRequest) SetDrainPeriod(period time.Duration) {
+	g.DrainPeriod = ToDurationPtr(period)
+}
+
+// Validate 校验InstanceGracefulDeregisterRequest
+func (g *InstanceGracefulDeregisterRequest) Validate() error {
+	if nil == g {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "InstanceGracefulDeregisterRequest can not be nil")
+	}
+	if err := g.InstanceDeRegisterRequest.Validate(); err != nil {
+		return err
+	}
+	if g.DrainPeriod != nil && *g.DrainPeriod < 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+			"fail to validate InstanceGracefulDeregisterRequest: drainPeriod should not be negative")
+	}
+	return nil
+}
+
+// InstanceMetadataUpdateRequest 更新已注册实例的元数据及权重，无需反注册
+type InstanceMetadataUpdateRequest struct {
+	// 必选，服务名
+	Service string
+	// 必选，命名空间
+	Namespace string
+	// 必选，服务监听host
+	Host string
+	// 必选，服务实例监听port
+	Port int
+	// 可选，实例注册时的请求，为空时使用自动心跳任务记录的注册信息
+	Instance *InstanceRegisterRequest
+	// 可选，需要新增或修改的metadata
+	Metadata map[string]string
+	// 可选，需要删除的metadata键
+	DeleteMetadataKeys []string
+	// 可选，新的权重，范围0-10000
+	Weight *int
+}
+
+// SetWeight 设置新的权重
+func (g *InstanceMetadataUpdateRequest) SetWeight(weight int) {
+	g.Weight = &weight
+}
+
+// Apply 基于实例的注册请求生成更新后的注册请求，不修改原请求
+func (g *InstanceMetadataUpdateRequest) Apply(instance *InstanceRegisterRequest) *InstanceRegisterRequest {
+	updated := *instance
+	metadata := make(map[string]string, len(instance.Metadata)+len(g.Metadata))
+	for k, v := range instance.Metadata {
+		metadata[k] = v
+	}
+	for k, v := range g.Metadata {
+		metadata[k] = v
+	}
+	for _, k := range g.DeleteMetadataKeys {
+		delete(metadata, k)
+	}
+	updated.Metadata = metadata
+	if g.Weight != nil {
+		weight := *g.Weight
+		updated.Weight = &weight
+	}
+	return &updated
+}
+
+// Validate 校验InstanceMetadataUpdateRequest
+func (g *InstanceMetadataUpdateRequest) Validate() error {
+	if nil == g {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "InstanceMetadataUpdateRequest can not be nil")
+	}
+	var errs error
+	if len(g.Service) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("InstanceMetadataUpdateRequest: serviceName should not be empty"))
+	}
+	if len(g.Namespace) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("InstanceMetadataUpdateRequest: namespace should not be empty"))
+	}
+	if len(g.Host) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("InstanceMetadataUpdateRequest: host should not be empty"))
+	}
+	if g.Port <= 0 || g.Port >= 65536 {
+		errs = multierror.Append(errs, fmt.Errorf("InstanceMetadataUpdateRequest: port should be in range (0, 65536)"))
+	}
+	if g.Weight != nil && (*g.Weight < MinWeight || *g.Weight > MaxWeight) {
+		errs = multierror.Append(errs, fmt.Errorf("InstanceMetadataUpdateRequest: weight should be in range [%d, %d]",
+			MinWeight, MaxWeight))
+	}
+	if len(g.Metadata) == 0 && len(g.DeleteMetadataKeys) == 0 && g.Weight == nil {
+		errs = multierror.Append(errs, fmt.Errorf("InstanceMetadataUpdateRequest: nothing to update"))
+	}
+	if errs != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, errs, "fail to validate InstanceMetadataUpdateRequest: ")
+	}
+	return nil
+}
+
 const (
 	// MinWeight 最小权重值
 	MinWeight int = 0
@@ -1164,6 +1430,7 @@ const (
 
 // InstanceRegisterRequest 注册服务请求
 type InstanceRegisterRequest struct {
+	callContext
 	// 必选，服务名
 	Service string
 	// 必选，命名空间
@@ -62,6 +62,8 @@ const (
 	LoadBalanceStat
 	RateLimitStat
 	RouteStat
+	CacheStat
+	SDKHealthStat
 )
 
 func DescMetricType(t MetricType) string {
@@ -84,6 +86,10 @@ func DescMetricType(t MetricType) string {
 		return "RateLimitStat"
 	case RouteStat:
 		return "RouteStat"
+	case CacheStat:
+		return "CacheStat"
+	case SDKHealthStat:
+		return "SDKHealthStat"
 	default:
 		return "Unknown"
 	}
@@ -182,6 +188,8 @@ const (
 	ApiInitCalleeServices
 	ApiProcessRouters
 	ApiProcessLoadBalance
+	ApiGetConfigFile
+	ApiGetConfigGroup
 	// ApiOperationMax 这个必须在最下面
 	ApiOperationMax
 )
@@ -202,6 +210,8 @@ var (
 		ApiInitCalleeServices:      "Consumer::InitCalleeServices",
 		ApiProcessRouters:          "Router::ProcessRouters",
 		ApiProcessLoadBalance:      "Router::ProcessLoadBalance",
+		ApiGetConfigFile:           "Config::GetConfigFile",
+		ApiGetConfigGroup:          "Config::GetConfigGroup",
 	}
 )
 
@@ -256,4 +266,6 @@ func init() {
 	metricTypes.Add(LoadBalanceStat)
 	metricTypes.Add(RateLimitStat)
 	metricTypes.Add(RouteStat)
+	metricTypes.Add(CacheStat)
+	metricTypes.Add(SDKHealthStat)
 }
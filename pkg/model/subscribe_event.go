@@ -28,6 +28,8 @@ type SubScribeEventType int
 const (
 	// service实例事件
 	EventInstance SubScribeEventType = 1
+	// service规则事件
+	EventRule SubScribeEventType = 2
 )
 
 type SubScribeEvent interface {
@@ -68,9 +70,28 @@ type InstanceDeleteEvent struct {
 	Instances []Instance
 }
 
+// RuleEvent 规则变更事件
+type RuleEvent struct {
+	// 规则类型，如路由规则、限流规则
+	Type EventType
+	// 规则所属服务
+	Service ServiceKey
+	// 变更前的规则，首次加载时为nil
+	Before ServiceRule
+	// 变更后的规则
+	After ServiceRule
+}
+
+// GetSubScribeEventType
+func (e *RuleEvent) GetSubScribeEventType() SubScribeEventType {
+	return EventRule
+}
+
 // WatchServiceRequest WatchService req
 type WatchServiceRequest struct {
 	Key ServiceKey
+	// 可选，需要同时监听的规则类型，如EventRouting、EventRateLimiting，规则变更时会推送RuleEvent
+	RuleTypes []EventType
 }
 
 // Validate WatchServiceRequest 校验
@@ -85,6 +106,11 @@ func (req *WatchServiceRequest) Validate() error {
 	if len(req.Key.Service) == 0 {
 		errs = multierror.Append(errs, fmt.Errorf("service is empty"))
 	}
+	for _, ruleType := range req.RuleTypes {
+		if ruleType == EventInstances || ruleType == EventServices || ruleType == EventUnknown {
+			errs = multierror.Append(errs, fmt.Errorf("invalid rule type %s", ruleType))
+		}
+	}
 	if errs != nil {
 		return NewSDKError(ErrCodeAPIInvalidArgument, errs,
 			"fail to validate GetInstancesRequest")
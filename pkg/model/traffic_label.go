@@ -0,0 +1,159 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// LabelKeyClaim JWT声明的标签前缀，以自定义参数的形式参与规则匹配，如$claim.sub
+	LabelKeyClaim = "$claim."
+	// DefaultClaimHeader 默认携带JWT的请求头
+	DefaultClaimHeader = "authorization"
+
+	bearerPrefix = "bearer "
+)
+
+// TrafficRequest 框架无关的请求访问接口，由TrafficLabelProvider插件对框架请求对象进行适配
+type TrafficRequest interface {
+	// GetMethod 请求方法，HTTP请求为请求方法，RPC请求为接口方法
+	GetMethod() string
+	// GetPath 请求路径
+	GetPath() string
+	// GetCallerIP 主调IP
+	GetCallerIP() string
+	// GetHeaders 请求头，RPC请求为请求元数据
+	GetHeaders() map[string][]string
+	// GetQueries 请求参数
+	GetQueries() map[string][]string
+	// GetCookies 请求cookie
+	GetCookies() map[string]string
+}
+
+// ExtractTrafficLabelsRequest 流量标签提取请求
+type ExtractTrafficLabelsRequest struct {
+	// Request 必选，框架相关的请求对象，如*http.Request、grpc服务端的context.Context
+	Request interface{}
+	// Provider 可选，指定使用的流量标签插件名，为空时依次尝试所有插件
+	Provider string
+	// ClaimHeader 可选，携带JWT的请求头，默认为authorization
+	ClaimHeader string
+}
+
+// Validate 校验流量标签提取请求
+func (r *ExtractTrafficLabelsRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ExtractTrafficLabelsRequest can not be nil")
+	}
+	if nil == r.Request {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ExtractTrafficLabelsRequest.Request can not be nil")
+	}
+	return nil
+}
+
+// ExtractTrafficLabelsResponse 流量标签提取结果，可直接作为路由或限流请求的参数
+type ExtractTrafficLabelsResponse struct {
+	// Arguments 提取出的请求参数
+	Arguments []Argument
+}
+
+// GetLabels 以标签的形式返回提取结果，key为$header.x-user-id这类规则中引用的标签名
+func (r *ExtractTrafficLabelsResponse) GetLabels() map[string]string {
+	labels := make(map[string]string, len(r.Arguments))
+	for _, argument := range r.Arguments {
+		argument.ToLabels(labels)
+	}
+	return labels
+}
+
+// BuildTrafficArguments 从请求中构建路由及限流参数
+// 请求头名称统一转为小写，多值时取第一个值；claimHeader中携带的JWT声明以$claim.为前缀的自定义参数返回，
+// 注意这里仅解析JWT内容而不校验签名，只可用于流量打标，不可用于鉴权
+func BuildTrafficArguments(request TrafficRequest, claimHeader string) []Argument {
+	headers := request.GetHeaders()
+	queries := request.GetQueries()
+	cookies := request.GetCookies()
+	arguments := make([]Argument, 0, len(headers)+len(queries)+len(cookies)+3)
+	if method := request.GetMethod(); len(method) > 0 {
+		arguments = append(arguments, BuildMethodArgument(method))
+	}
+	if path := request.GetPath(); len(path) > 0 {
+		arguments = append(arguments, BuildPathArgument(path))
+	}
+	if callerIP := request.GetCallerIP(); len(callerIP) > 0 {
+		arguments = append(arguments, BuildCallerIPArgument(callerIP))
+	}
+	if len(claimHeader) == 0 {
+		claimHeader = DefaultClaimHeader
+	}
+	claimHeader = strings.ToLower(claimHeader)
+	for key, values := range headers {
+		if len(values) == 0 {
+			continue
+		}
+		key = strings.ToLower(key)
+		arguments = append(arguments, BuildHeaderArgument(key, values[0]))
+		if key == claimHeader {
+			arguments = appendClaimArguments(arguments, values[0])
+		}
+	}
+	for key, values := range queries {
+		if len(values) > 0 {
+			arguments = append(arguments, BuildQueryArgument(key, values[0]))
+		}
+	}
+	for key, value := range cookies {
+		arguments = append(arguments, BuildCookieArgument(key, value))
+	}
+	return arguments
+}
+
+// appendClaimArguments 解析JWT载荷中的标量声明，解析失败时忽略
+func appendClaimArguments(arguments []Argument, token string) []Argument {
+	if len(token) > len(bearerPrefix) && strings.ToLower(token[:len(bearerPrefix)]) == bearerPrefix {
+		token = token[len(bearerPrefix):]
+	}
+	segments := strings.Split(strings.TrimSpace(token), ".")
+	if len(segments) != 3 {
+		return arguments
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segments[1], "="))
+	if err != nil {
+		return arguments
+	}
+	claims := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err = decoder.Decode(&claims); err != nil {
+		return arguments
+	}
+	for name, value := range claims {
+		switch v := value.(type) {
+		case string:
+			arguments = append(arguments, BuildCustomArgument(LabelKeyClaim+name, v))
+		case json.Number, bool:
+			arguments = append(arguments, BuildCustomArgument(LabelKeyClaim+name, fmt.Sprint(v)))
+		}
+	}
+	return arguments
+}
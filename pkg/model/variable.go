@@ -0,0 +1,187 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"flag"
+	"os"
+	"sync"
+)
+
+const (
+	// VariableResolverSystem 从 global.system.variables 中获取变量
+	VariableResolverSystem = "system"
+	// VariableResolverEnv 从进程环境变量中获取变量
+	VariableResolverEnv = "env"
+	// VariableResolverFlag 从进程启动参数(flag)中获取变量
+	VariableResolverFlag = "flag"
+	// VariableResolverConfigCenter 从配置中心的配置文件中获取变量
+	VariableResolverConfigCenter = "configCenter"
+)
+
+// VariableResolver 路由规则中 VARIABLE 类型参数的取值来源
+type VariableResolver interface {
+	// Name 解析器名称
+	Name() string
+	// Resolve 获取变量值，变量不存在时返回false
+	Resolve(key string) (string, bool)
+}
+
+// VariableChangeListener 变量值变更的回调
+type VariableChangeListener func(key string, oldValue string, newValue string)
+
+// variableValue 缓存的变量值
+type variableValue struct {
+	value string
+	exist bool
+}
+
+// VariableResolverChain 变量解析链，按顺序从各解析器中获取变量，第一个获取成功的值生效
+// 解析结果会被缓存，解析器数据源发生变更时需调用 Refresh 刷新
+type VariableResolverChain struct {
+	mutex     sync.RWMutex
+	resolvers []VariableResolver
+	listeners []VariableChangeListener
+	// key为变量名，value为*variableValue
+	cache sync.Map
+}
+
+// NewVariableResolverChain 创建变量解析链
+func NewVariableResolverChain(resolvers ...VariableResolver) *VariableResolverChain {
+	return &VariableResolverChain{resolvers: resolvers}
+}
+
+// Resolve 获取变量值
+func (c *VariableResolverChain) Resolve(key string) (string, bool) {
+	if cached, ok := c.cache.Load(key); ok {
+		value := cached.(*variableValue)
+		return value.value, value.exist
+	}
+	value := c.doResolve(key)
+	c.cache.Store(key, value)
+	return value.value, value.exist
+}
+
+func (c *VariableResolverChain) doResolve(key string) *variableValue {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	for _, resolver := range c.resolvers {
+		if value, ok := resolver.Resolve(key); ok {
+			return &variableValue{value: value, exist: true}
+		}
+	}
+	return &variableValue{}
+}
+
+// AddResolver 在解析链末尾添加解析器，添加后会刷新已缓存的变量
+func (c *VariableResolverChain) AddResolver(resolver VariableResolver) {
+	c.mutex.Lock()
+	c.resolvers = append(c.resolvers, resolver)
+	c.mutex.Unlock()
+	c.Refresh()
+}
+
+// GetResolvers 获取当前的解析器列表
+func (c *VariableResolverChain) GetResolvers() []VariableResolver {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	resolvers := make([]VariableResolver, len(c.resolvers))
+	copy(resolvers, c.resolvers)
+	return resolvers
+}
+
+// AddChangeListener 添加变量变更监听
+func (c *VariableResolverChain) AddChangeListener(listener VariableChangeListener) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.listeners = append(c.listeners, listener)
+}
+
+// Refresh 重新解析变量，不传入key时刷新全部已缓存的变量，值发生变化时通知监听者
+func (c *VariableResolverChain) Refresh(keys ...string) {
+	if len(keys) == 0 {
+		c.cache.Range(func(key, _ interface{}) bool {
+			keys = append(keys, key.(string))
+			return true
+		})
+	}
+	for _, key := range keys {
+		cached, ok := c.cache.Load(key)
+		if !ok {
+			// 未被使用过的变量，无需刷新
+			continue
+		}
+		oldValue := cached.(*variableValue)
+		newValue := c.doResolve(key)
+		c.cache.Store(key, newValue)
+		if oldValue.exist == newValue.exist && oldValue.value == newValue.value {
+			continue
+		}
+		c.notify(key, oldValue.value, newValue.value)
+	}
+}
+
+func (c *VariableResolverChain) notify(key string, oldValue string, newValue string) {
+	c.mutex.RLock()
+	listeners := c.listeners
+	c.mutex.RUnlock()
+	for _, listener := range listeners {
+		listener(key, oldValue, newValue)
+	}
+}
+
+// EnvVariableResolver 从进程环境变量中获取变量
+type EnvVariableResolver struct{}
+
+// Name 解析器名称
+func (e *EnvVariableResolver) Name() string {
+	return VariableResolverEnv
+}
+
+// Resolve 获取变量值，空值视为不存在
+func (e *EnvVariableResolver) Resolve(key string) (string, bool) {
+	value := os.Getenv(key)
+	return value, value != ""
+}
+
+// FlagVariableResolver 从进程启动参数中获取变量，仅对已定义且解析过的flag生效
+type FlagVariableResolver struct {
+	// 为空时使用 flag.CommandLine
+	FlagSet *flag.FlagSet
+}
+
+// Name 解析器名称
+func (f *FlagVariableResolver) Name() string {
+	return VariableResolverFlag
+}
+
+// Resolve 获取变量值
+func (f *FlagVariableResolver) Resolve(key string) (string, bool) {
+	flagSet := f.FlagSet
+	if flagSet == nil {
+		flagSet = flag.CommandLine
+	}
+	if !flagSet.Parsed() {
+		return "", false
+	}
+	fl := flagSet.Lookup(key)
+	if fl == nil {
+		return "", false
+	}
+	return fl.Value.String(), true
+}
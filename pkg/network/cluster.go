@@ -0,0 +1,186 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// serverCluster 一个独立的server集群
+type serverCluster struct {
+	name      string
+	addresses []string
+	// 最近一次探测开始持续健康的时间，零值表示不健康
+	healthySince time.Time
+}
+
+// serverClusters 按优先级排列的server集群，负责故障切换与自动切回
+type serverClusters struct {
+	mutex    sync.RWMutex
+	clusters []*serverCluster
+	// 当前生效集群下标
+	active int
+	// 当前集群连续失败次数
+	failures int32
+	// 连续失败多少次后切换
+	threshold int32
+	// 高优先级集群持续健康多久后切回
+	failbackWindow time.Duration
+	// 健康探测间隔
+	probeInterval time.Duration
+	// 探测连接超时
+	connectTimeout time.Duration
+	// 集群发生切换时的回调
+	onSwitch func()
+}
+
+// newServerClusters 根据配置创建集群列表，未配置备用集群时返回nil
+func newServerClusters(cfg config.ServerConnectorConfig) *serverClusters {
+	if len(cfg.GetClusters()) == 0 {
+		return nil
+	}
+	s := &serverClusters{
+		threshold:      cfg.GetFailoverThreshold(),
+		failbackWindow: cfg.GetFailbackWindow(),
+		probeInterval:  cfg.GetClusterProbeInterval(),
+		connectTimeout: cfg.GetConnectTimeout(),
+	}
+	if len(cfg.GetAddresses()) > 0 {
+		s.clusters = append(s.clusters, &serverCluster{name: "primary", addresses: cfg.GetAddresses()})
+	}
+	for _, cluster := range cfg.GetClusters() {
+		s.clusters = append(s.clusters, &serverCluster{name: cluster.Name, addresses: cluster.Addresses})
+	}
+	return s
+}
+
+// activeAddresses 获取当前生效集群的地址
+func (s *serverClusters) activeAddresses() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.clusters[s.active].addresses
+}
+
+// reportSuccess 当前集群请求成功，清空连续失败计数
+func (s *serverClusters) reportSuccess() {
+	atomic.StoreInt32(&s.failures, 0)
+}
+
+// reportFailure 当前集群请求失败，连续失败达到阈值后切换到下一个集群
+func (s *serverClusters) reportFailure() {
+	if atomic.AddInt32(&s.failures, 1) < s.threshold {
+		return
+	}
+	s.mutex.Lock()
+	from := s.active
+	to := s.nextCluster()
+	if to == from {
+		s.mutex.Unlock()
+		atomic.StoreInt32(&s.failures, 0)
+		return
+	}
+	s.active = to
+	s.mutex.Unlock()
+	atomic.StoreInt32(&s.failures, 0)
+	log.GetNetworkLogger().Warnf("server cluster %s failed %d times continuously, failover to %s",
+		s.clusters[from].name, s.threshold, s.clusters[to].name)
+	s.onSwitch()
+}
+
+// nextCluster 选择下一个集群，优先选择最近探测健康的集群，调用方需持有写锁
+func (s *serverClusters) nextCluster() int {
+	count := len(s.clusters)
+	for i := 1; i < count; i++ {
+		idx := (s.active + i) % count
+		if !s.clusters[idx].healthySince.IsZero() {
+			return idx
+		}
+	}
+	return (s.active + 1) % count
+}
+
+// run 定期探测各集群健康状态，高优先级集群持续健康超过稳定窗口后切回
+func (s *serverClusters) run(ctx context.Context) {
+	ticker := time.NewTicker(s.probeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.probe()
+		}
+	}
+}
+
+// probe 执行一轮探测
+func (s *serverClusters) probe() {
+	healthy := make([]bool, len(s.clusters))
+	for i, cluster := range s.clusters {
+		healthy[i] = s.probeCluster(cluster)
+	}
+	now := time.Now()
+	s.mutex.Lock()
+	for i, cluster := range s.clusters {
+		if !healthy[i] {
+			cluster.healthySince = time.Time{}
+			continue
+		}
+		if cluster.healthySince.IsZero() {
+			cluster.healthySince = now
+		}
+	}
+	from := s.active
+	to := from
+	for i := 0; i < from; i++ {
+		if healthy[i] && now.Sub(s.clusters[i].healthySince) >= s.failbackWindow {
+			to = i
+			break
+		}
+	}
+	s.active = to
+	s.mutex.Unlock()
+	if to == from {
+		return
+	}
+	atomic.StoreInt32(&s.failures, 0)
+	log.GetNetworkLogger().Infof("server cluster %s has been healthy for %v, failback from %s",
+		s.clusters[to].name, s.failbackWindow, s.clusters[from].name)
+	s.onSwitch()
+}
+
+// probeCluster 集群内任意一个地址可连通即认为集群健康
+func (s *serverClusters) probeCluster(cluster *serverCluster) bool {
+	for _, addr := range cluster.addresses {
+		conn, err := net.DialTimeout("tcp", addr, s.connectTimeout)
+		if err != nil {
+			continue
+		}
+		_ = conn.Close()
+		return true
+	}
+	log.GetNetworkLogger().Debugf("server cluster %s is unreachable", cluster.name)
+	return false
+}
@@ -141,5 +141,8 @@ func (c *Connection) Release(opKey string) {
 
 // ToGRPCConn convert to a grpc connection
 func ToGRPCConn(conn ClosableConn) *grpc.ClientConn {
+	if shared, ok := conn.(*sharedConn); ok {
+		return shared.conn
+	}
 	return conn.(*grpc.ClientConn)
 }
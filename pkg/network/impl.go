@@ -56,6 +56,8 @@ type ServerAddressList struct {
 	curIndex int
 	// 预埋地址列表
 	addresses []string
+	// 多集群故障切换，未配置备用集群时为nil
+	clusters *serverClusters
 	// 首次连接控制锁
 	connectMutex sync.Mutex
 	// 全局管理对象指针
@@ -85,8 +87,12 @@ func (s *ServerAddressList) getServerAddress(hashKey []byte) (string, model.Inst
 	var targetAddress string
 	var instance model.Instance
 	if s.service.ClusterType == config.BuiltinCluster || s.service.ClusterType == config.ConfigCluster {
-		serverCount := len(s.addresses)
-		targetAddress = s.addresses[s.curIndex%serverCount]
+		addresses := s.addresses
+		if s.clusters != nil {
+			addresses = s.clusters.activeAddresses()
+		}
+		serverCount := len(addresses)
+		targetAddress = addresses[s.curIndex%serverCount]
 		if s.curIndex == math.MaxInt32 {
 			s.curIndex = 0
 		} else {
@@ -155,14 +161,20 @@ func (s *ServerAddressList) connectServer(force bool, addr string, instance mode
 	if err != nil {
 		if !reflect2.IsNil(instance) {
 			s.manager.ReportFail(connID, int32(model.ErrCodeConnectError), connectDuration)
+		} else if s.clusters != nil {
+			s.clusters.reportFailure()
 		}
 		return nil, fmt.Errorf("fail to connect to %s, timeout is %v, service is %s, because %s",
 			addr, connectDuration, s.service, err.Error())
 	}
 
 	if nil != lastConn {
+		atomic.AddInt64(&s.manager.reconnectCount, 1)
 		// 延迟释放连接
 		lastConn.lazyClose(false)
+		if lastConn.Address != addr {
+			s.reportSwitchEvent(lastConn.Address, addr)
+		}
 	}
 
 	conn := &Connection{
@@ -178,6 +190,19 @@ func (s *ServerAddressList) connectServer(force bool, addr string, instance mode
 	return conn, nil
 }
 
+// reportSwitchEvent 上报服务端连接切换的治理事件
+func (s *ServerAddressList) reportSwitchEvent(oldAddr string, newAddr string) {
+	engineValue, ok := s.manager.valueCtx.GetValue(model.ContextKeyEngine)
+	if !ok {
+		return
+	}
+	event := model.NewGovernanceEvent(model.EventServerSwitched, &s.service.ServiceKey)
+	event.PreviousStatus = oldAddr
+	event.CurrentStatus = newAddr
+	event.Reason = fmt.Sprintf("%s cluster connection switched", s.service.ClusterType)
+	_ = engineValue.(model.Engine).SyncReportEvent(event)
+}
+
 // ConnectServerByAddrOnly 。根据地址进行链接
 func (s *ServerAddressList) ConnectServerByAddrOnly(addr string, timeout time.Duration,
 	clsService config.ClusterService, instance model.Instance) (*Connection, error) {
@@ -222,6 +247,20 @@ func (s *ServerAddressList) tryGetConnection(timeout time.Duration, hashKey []by
 	return s.connectServer(false, address, instance, s.service, timeout)
 }
 
+// initClusters 初始化多集群故障切换
+func (s *ServerAddressList) initClusters(cfg config.ServerConnectorConfig) {
+	s.clusters = newServerClusters(cfg)
+	if s.clusters == nil {
+		return
+	}
+	s.addresses = s.clusters.activeAddresses()
+	s.clusters.onSwitch = func() {
+		// 集群切换后释放当前连接，下次获取连接时使用新集群地址
+		s.closeCurrentConnection(false)
+	}
+	go s.clusters.run(s.manager.ctx)
+}
+
 // closeCurrentConnection 关闭当前连接
 func (s *ServerAddressList) closeCurrentConnection(force bool) {
 	conn := s.loadCurrentConnection()
@@ -259,6 +298,8 @@ type connectionManager struct {
 	protocol string
 	// 连接创建器
 	creator ConnCreator
+	// 替换已有连接的累计次数
+	reconnectCount int64
 }
 
 // NewConnectionManager 创建连接管理器
@@ -296,7 +337,6 @@ func NewConnectionManager(
 		useDefault: false,
 		manager:    manager,
 		addresses:  addresses,
-		curIndex:   rand.Intn(len(addresses)),
 	}
 	manager.serverServices[config.BuiltinCluster] = builtInAddrList
 	if len(manager.discoverService.Service) == 0 {
@@ -304,6 +344,8 @@ func NewConnectionManager(
 		manager.ready = serviceReadyStatus
 	}
 	manager.ctx, manager.cancel = context.WithCancel(context.Background())
+	builtInAddrList.initClusters(cfg.GetGlobal().GetServerConnector())
+	builtInAddrList.curIndex = rand.Intn(len(builtInAddrList.addresses))
 	go manager.doSwitchRoutine()
 	return manager, nil
 }
@@ -330,7 +372,6 @@ func NewConfigConnectionManager(cfg config.Configuration, valueCtx model.ValueCo
 		useDefault: false,
 		manager:    configManager,
 		addresses:  configAddresses,
-		curIndex:   rand.Intn(len(configAddresses)),
 	}
 	configManager.serverServices[config.ConfigCluster] = configAddrList
 
@@ -340,6 +381,8 @@ func NewConfigConnectionManager(cfg config.Configuration, valueCtx model.ValueCo
 	}
 
 	configManager.ctx, configManager.cancel = context.WithCancel(context.Background())
+	configAddrList.initClusters(cfg.GetConfigFile().GetConfigConnectorConfig())
+	configAddrList.curIndex = rand.Intn(len(configAddrList.addresses))
 	return configManager, nil
 }
 
@@ -350,18 +393,27 @@ func (c *connectionManager) SetConnCreator(creator ConnCreator) {
 
 // tryGetConnection 尝试获取连接
 func (c *connectionManager) tryGetConnection(clusterType config.ClusterType, hashKey []byte) (*Connection, error) {
+	serverList, err := c.getServerList(clusterType)
+	if err != nil {
+		return nil, err
+	}
+	return serverList.tryGetConnection(c.connectTimeout, hashKey)
+}
+
+// getServerList 获取集群对应的服务地址列表，未配置时按需回退到预埋地址
+func (c *connectionManager) getServerList(clusterType config.ClusterType) (*ServerAddressList, error) {
 	serverList, ok := c.serverServices[clusterType]
+	if ok {
+		return serverList, nil
+	}
+	useDefault, ok := config.DefaultServerServiceToUseDefault[clusterType]
 	if !ok {
-		var useDefault, ok bool
-		if useDefault, ok = config.DefaultServerServiceToUseDefault[clusterType]; !ok {
-			return nil, fmt.Errorf("cluster %v is invalid", clusterType)
-		}
-		if !useDefault {
-			return nil, fmt.Errorf("service name for cluster %v is not config", clusterType)
-		}
-		serverList = c.serverServices[config.BuiltinCluster]
+		return nil, fmt.Errorf("cluster %v is invalid", clusterType)
 	}
-	return serverList.tryGetConnection(c.connectTimeout, hashKey)
+	if !useDefault {
+		return nil, fmt.Errorf("service name for cluster %v is not config", clusterType)
+	}
+	return c.serverServices[config.BuiltinCluster], nil
 }
 
 // GetConnection 获取并占用连接
@@ -387,9 +439,9 @@ func (c *connectionManager) GetConnectionByHashKey(
 
 func (c *connectionManager) GetHashExpectedInstance(clusterType config.ClusterType,
 	hash []byte) (string, model.Instance, error) {
-	serverList, ok := c.serverServices[clusterType]
-	if !ok {
-		panic(fmt.Sprintf("connectionManager has no clusterType %s", clusterType))
+	serverList, err := c.getServerList(clusterType)
+	if err != nil {
+		return "", nil, err
 	}
 	addr, ins, err := serverList.getServerAddress(hash)
 	return addr, ins, err
@@ -397,9 +449,9 @@ func (c *connectionManager) GetHashExpectedInstance(clusterType config.ClusterTy
 
 func (c *connectionManager) ConnectByAddr(clusterType config.ClusterType, addr string,
 	instance model.Instance) (*Connection, error) {
-	serverList, ok := c.serverServices[clusterType]
-	if !ok {
-		panic(fmt.Sprintf("connectionManager has no clusterType %s", clusterType))
+	serverList, err := c.getServerList(clusterType)
+	if err != nil {
+		return nil, err
 	}
 	return serverList.ConnectServerByAddrOnly(addr, time.Millisecond*500, serverList.service, instance)
 }
@@ -407,11 +459,34 @@ func (c *connectionManager) ConnectByAddr(clusterType config.ClusterType, addr s
 // ReportSuccess 上报服务成功
 func (c *connectionManager) ReportSuccess(connID ConnID, retCode int32, timeout time.Duration) {
 	log.GetNetworkLogger().Debugf("service %s: reported success", connID.Service)
+	if clusters := c.getServerClusters(connID); clusters != nil {
+		clusters.reportSuccess()
+	}
+}
+
+// GetReconnectCount 获取替换已有连接的累计次数
+func (c *connectionManager) GetReconnectCount() int64 {
+	return atomic.LoadInt64(&c.reconnectCount)
 }
 
 // ReportFail 上报服务失败
 func (c *connectionManager) ReportFail(connID ConnID, retCode int32, timeout time.Duration) {
 	log.GetNetworkLogger().Warnf("connection %s: reported fail", connID)
+	if clusters := c.getServerClusters(connID); clusters != nil {
+		clusters.reportFailure()
+	}
+}
+
+// getServerClusters 获取连接所属的多集群对象，仅对预埋地址生效
+func (c *connectionManager) getServerClusters(connID ConnID) *serverClusters {
+	if connID.Service.ClusterType != config.BuiltinCluster && connID.Service.ClusterType != config.ConfigCluster {
+		return nil
+	}
+	serverList, ok := c.serverServices[connID.Service.ClusterType]
+	if !ok {
+		return nil
+	}
+	return serverList.clusters
 }
 
 // ReportConnectionDown 报告连接故障
@@ -108,4 +108,7 @@ type ConnectionManager interface {
 
 	// ConnectByAddr 直接通过addr连接，慎使用
 	ConnectByAddr(clusterType config.ClusterType, addr string, instance model.Instance) (*Connection, error)
+
+	// GetReconnectCount 获取与服务端重新建立连接(替换已有连接)的累计次数
+	GetReconnectCount() int64
 }
@@ -0,0 +1,105 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// sharedConnEntry 进程内共享的连接及其引用计数
+type sharedConnEntry struct {
+	conn *grpc.ClientConn
+	ref  int
+}
+
+var (
+	sharedConnMutex sync.Mutex
+	sharedConns     = map[string]*sharedConnEntry{}
+)
+
+// sharedConn 共享连接的引用，Close 只释放引用，最后一个引用释放时关闭底层连接
+type sharedConn struct {
+	key       string
+	conn      *grpc.ClientConn
+	closeOnce sync.Once
+}
+
+// Close 释放共享连接的引用
+func (s *sharedConn) Close() error {
+	s.closeOnce.Do(func() {
+		releaseSharedConn(s.key, s.conn)
+	})
+	return nil
+}
+
+// AcquireSharedConn 获取进程内共享的连接，key 相同的SDK上下文复用同一个连接，
+// 不存在或已关闭时通过 dial 创建，返回的连接关闭时只减少引用计数
+func AcquireSharedConn(key string, dial func() (*grpc.ClientConn, error)) (ClosableConn, error) {
+	sharedConnMutex.Lock()
+	entry, ok := sharedConns[key]
+	if ok && entry.conn.GetState() != connectivity.Shutdown {
+		entry.ref++
+		sharedConnMutex.Unlock()
+		return &sharedConn{key: key, conn: entry.conn}, nil
+	}
+	sharedConnMutex.Unlock()
+
+	// 建连可能阻塞至超时，不持有锁
+	conn, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	sharedConnMutex.Lock()
+	defer sharedConnMutex.Unlock()
+	entry, ok = sharedConns[key]
+	if ok && entry.conn.GetState() != connectivity.Shutdown {
+		// 并发建连时保留先完成的连接
+		_ = conn.Close()
+		entry.ref++
+		return &sharedConn{key: key, conn: entry.conn}, nil
+	}
+	sharedConns[key] = &sharedConnEntry{conn: conn, ref: 1}
+	log.GetBaseLogger().Infof("[Network] shared connection %s created", key)
+	return &sharedConn{key: key, conn: conn}, nil
+}
+
+// releaseSharedConn 减少共享连接的引用计数，计数归零时关闭连接
+func releaseSharedConn(key string, conn *grpc.ClientConn) {
+	sharedConnMutex.Lock()
+	entry, ok := sharedConns[key]
+	if !ok || entry.conn != conn {
+		sharedConnMutex.Unlock()
+		// 连接已被替换，直接关闭旧连接
+		_ = conn.Close()
+		return
+	}
+	entry.ref--
+	if entry.ref > 0 {
+		sharedConnMutex.Unlock()
+		return
+	}
+	delete(sharedConns, key)
+	sharedConnMutex.Unlock()
+	log.GetBaseLogger().Infof("[Network] shared connection %s closed", key)
+	_ = conn.Close()
+}
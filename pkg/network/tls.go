@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+)
+
+// CertificateSource 动态证书来源，如SPIFFE工作负载身份，证书轮转后无需重建连接配置
+type CertificateSource interface {
+	// GetCertificate 获取当前客户端证书及私钥
+	GetCertificate() (*tls.Certificate, error)
+	// GetTrustBundle 获取校验server证书的信任CA证书池
+	GetTrustBundle() (*x509.CertPool, error)
+}
+
+// BuildTLSConfig 根据配置构建与server通信的TLS配置，未启用TLS且没有证书来源时返回空。
+// 指定证书来源时使用其证书进行双向认证，未配置CA证书文件时使用其信任CA证书池校验server证书
+func BuildTLSConfig(cfg config.TLSConfig, source CertificateSource) (*tls.Config, error) {
+	enable := nil != cfg && cfg.IsEnable()
+	if !enable && nil == source {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if nil != cfg {
+		tlsConfig.ServerName = cfg.GetServerName()
+		tlsConfig.InsecureSkipVerify = cfg.IsInsecureSkipVerify()
+	}
+	if nil != cfg && len(cfg.GetCAFile()) > 0 {
+		caData, err := ioutil.ReadFile(cfg.GetCAFile())
+		if err != nil {
+			return nil, fmt.Errorf("fail to read tls ca file %s: %v", cfg.GetCAFile(), err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no valid certificate found in tls ca file %s", cfg.GetCAFile())
+		}
+		tlsConfig.RootCAs = pool
+	} else if nil != source && !tlsConfig.InsecureSkipVerify {
+		// SPIFFE证书不包含DNS名称，仅校验证书链是否由信任的CA签发
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyPeerWithBundle(rawCerts, source)
+		}
+	}
+	if nil != source {
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return source.GetCertificate()
+		}
+		return tlsConfig, nil
+	}
+	if len(cfg.GetCertFile()) > 0 {
+		certFile, keyFile := cfg.GetCertFile(), cfg.GetKeyFile()
+		// 每次握手时重新加载证书，证书轮转后无需重启
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, fmt.Errorf("fail to load tls client certificate %s: %v", certFile, err)
+			}
+			return &cert, nil
+		}
+		if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+			return nil, fmt.Errorf("fail to load tls client certificate %s: %v", certFile, err)
+		}
+	}
+	return tlsConfig, nil
+}
+
+// verifyPeerWithBundle 使用证书来源的信任CA证书池校验server证书链
+func verifyPeerWithBundle(rawCerts [][]byte, source CertificateSource) error {
+	if len(rawCerts) == 0 {
+		return errors.New("server presents no certificate")
+	}
+	roots, err := source.GetTrustBundle()
+	if err != nil {
+		return err
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("fail to parse server certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	return err
+}
+
+// NewGRPCCredentials 根据TLS配置创建gRPC传输凭证，未启用TLS时使用明文传输
+func NewGRPCCredentials(tlsConfig *tls.Config) credentials.TransportCredentials {
+	if nil == tlsConfig {
+		return insecure.NewCredentials()
+	}
+	return credentials.NewTLS(tlsConfig)
+}
@@ -0,0 +1,98 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// TokenProvider 访问server的鉴权token，按文件、环境变量、配置的优先级获取，
+// 文件内容会按周期重新读取，token轮转后无需重启
+type TokenProvider struct {
+	staticToken     string
+	envName         string
+	filePath        string
+	refreshInterval time.Duration
+
+	mutex    sync.RWMutex
+	token    string
+	loadTime time.Time
+}
+
+// NewTokenProvider 根据连接器配置创建鉴权token提供者
+func NewTokenProvider(cfg config.ServerConnectorConfig) *TokenProvider {
+	provider := &TokenProvider{
+		staticToken:     cfg.GetToken(),
+		envName:         cfg.GetTokenEnv(),
+		filePath:        cfg.GetTokenFile(),
+		refreshInterval: cfg.GetTokenRefreshInterval(),
+	}
+	provider.token = provider.load()
+	provider.loadTime = time.Now()
+	return provider
+}
+
+// GetToken 获取当前的鉴权token
+func (t *TokenProvider) GetToken() string {
+	if nil == t {
+		return ""
+	}
+	if len(t.filePath) == 0 {
+		return t.token
+	}
+	t.mutex.RLock()
+	token, loadTime := t.token, t.loadTime
+	t.mutex.RUnlock()
+	if time.Since(loadTime) < t.refreshInterval {
+		return token
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if time.Since(t.loadTime) >= t.refreshInterval {
+		t.token = t.load()
+		t.loadTime = time.Now()
+	}
+	return t.token
+}
+
+// load 按优先级读取token，文件读取失败时沿用上一次的token
+func (t *TokenProvider) load() string {
+	if len(t.filePath) > 0 {
+		data, err := ioutil.ReadFile(t.filePath)
+		if err == nil {
+			return strings.TrimSpace(string(data))
+		}
+		log.GetNetworkLogger().Warnf("fail to read token file %s, err %v", t.filePath, err)
+		if len(t.token) > 0 {
+			return t.token
+		}
+	}
+	if len(t.envName) > 0 {
+		if token, ok := os.LookupEnv(t.envName); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return t.staticToken
+}
@@ -34,6 +34,21 @@ type CircuitBreaker interface {
 	Report(*model.ResourceStat) error
 }
 
+// StatusObservable 支持熔断状态监听的熔断器
+type StatusObservable interface {
+	// AddStatusListener 添加熔断状态监听器
+	AddStatusListener(listener model.CircuitBreakerStatusListener)
+}
+
+// GetStatusObservable 获取熔断器的状态监听能力，熔断器未实现时返回false
+func GetStatusObservable(breaker CircuitBreaker) (StatusObservable, bool) {
+	if proxy, ok := breaker.(*Proxy); ok {
+		breaker = proxy.CircuitBreaker
+	}
+	observable, ok := breaker.(StatusObservable)
+	return observable, ok
+}
+
 // Result 熔断结算结果
 type Result struct {
 	Now time.Time
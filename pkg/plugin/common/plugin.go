@@ -56,22 +56,31 @@ const (
 	TypeConfigConnector Type = 0x1014
 	// TypeConfigFilter extend point of config file filter
 	TypeConfigFilter Type = 0x1015
+	// TypeTrafficLabelProvider 从框架请求对象中提取流量标签的扩展点
+	TypeTrafficLabelProvider Type = 0x1016
+	// TypeEventReporter 治理事件上报扩展点
+	TypeEventReporter Type = 0x1017
+	// TypeIdentityProvider 工作负载身份提供扩展点
+	TypeIdentityProvider Type = 0x1018
 )
 
 var typeToPresent = map[Type]string{
-	TypePluginBase:       "TypePluginBase",
-	TypeServerConnector:  "serverConnector",
-	TypeLocalRegistry:    "localRegistry",
-	TypeServiceRouter:    "serviceRouter",
-	TypeLoadBalancer:     "loadBalancer",
-	TypeHealthCheck:      "healthChecker",
-	TypeCircuitBreaker:   "circuitBreaker",
-	TypeWeightAdjuster:   "weightAdjuster",
-	TypeStatReporter:     "statReporter",
-	TypeRateLimiter:      "rateLimiter",
-	TypeLocationProvider: "locationProvider",
-	TypeConfigConnector:  "configConnector",
-	TypeConfigFilter:     "configFilter",
+	TypePluginBase:           "TypePluginBase",
+	TypeServerConnector:      "serverConnector",
+	TypeLocalRegistry:        "localRegistry",
+	TypeServiceRouter:        "serviceRouter",
+	TypeLoadBalancer:         "loadBalancer",
+	TypeHealthCheck:          "healthChecker",
+	TypeCircuitBreaker:       "circuitBreaker",
+	TypeWeightAdjuster:       "weightAdjuster",
+	TypeStatReporter:         "statReporter",
+	TypeRateLimiter:          "rateLimiter",
+	TypeLocationProvider:     "locationProvider",
+	TypeConfigConnector:      "configConnector",
+	TypeConfigFilter:         "configFilter",
+	TypeTrafficLabelProvider: "trafficLabelProvider",
+	TypeEventReporter:        "eventReporter",
+	TypeIdentityProvider:     "identityProvider",
 }
 
 // ToString方法
@@ -101,6 +110,8 @@ const (
 	OnRateLimitWindowCreated PluginEventType = 0x8008
 	// OnRateLimitWindowDeleted 一个限流规则的限流窗口被删除时触发的事件
 	OnRateLimitWindowDeleted PluginEventType = 0x8009
+	// OnConfigReloaded SDK自身配置热更新生效后触发的事件，事件对象为 *model.SDKConfigChangedEvent
+	OnConfigReloaded PluginEventType = 0x800A
 )
 
 // PluginEvent 插件事件
@@ -212,6 +223,8 @@ func (n *Notifier) Notify(sdkErr model.SDKError) {
 
 // LoadedPluginTypes 要加载的插件类型
 var LoadedPluginTypes = []Type{
+	// 身份提供插件需要先于连接器初始化，连接器使用其证书建立双向认证连接
+	TypeIdentityProvider,
 	TypeServerConnector,
 	TypeServiceRouter,
 	TypeLoadBalancer,
@@ -224,4 +237,6 @@ var LoadedPluginTypes = []Type{
 	TypeLocationProvider,
 	TypeConfigConnector,
 	TypeConfigFilter,
+	TypeTrafficLabelProvider,
+	TypeEventReporter,
 }
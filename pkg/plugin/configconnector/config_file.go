@@ -36,6 +36,23 @@ const (
 	ConfigFileTagKeyDataKey = "internal-datakey"
 	// ConfigFileTagKeyEncryptAlgo 加密算法 tag key
 	ConfigFileTagKeyEncryptAlgo = "internal-encryptalgo"
+	// ConfigFileTagKeyReleaseName 服务端返回的命中发布名称 tag key，灰度发布时为灰度发布的名称
+	ConfigFileTagKeyReleaseName = "internal-release-name"
+	// ConfigFileTagKeyEncryptKeyID 客户端密钥 ID tag key，存在时由客户端从密钥提供者获取密钥解密
+	ConfigFileTagKeyEncryptKeyID = "internal-encrypt-keyid"
+)
+
+const (
+	// ClientLabelIP 客户端 IP 标签
+	ClientLabelIP = "CLIENT_IP"
+	// ClientLabelID 客户端 ID 标签
+	ClientLabelID = "CLIENT_ID"
+	// ClientLabelEnv 客户端环境标签
+	ClientLabelEnv = "CLIENT_ENV"
+	// ClientLabelLanguage 客户端语言标签
+	ClientLabelLanguage = "CLIENT_LANGUAGE"
+	// ClientLabelVersion 客户端 SDK 版本标签
+	ClientLabelVersion = "CLIENT_VERSION"
 )
 
 // ConfigFile 配置文件
@@ -57,6 +74,12 @@ type ConfigFile struct {
 	Mode model.GetConfigFileRequestMode
 	// 文件持久化配置
 	Persistent model.Persistent
+	// Comment 配置文件描述，创建或者更新配置文件时使用
+	Comment string
+	// ReleaseName 发布名称，发布配置文件时使用
+	ReleaseName string
+	// ReleaseDescription 发布描述，发布配置文件时使用
+	ReleaseDescription string
 }
 
 func (c *ConfigFile) String() string {
@@ -154,6 +177,26 @@ func (c *ConfigFile) GetFileMode() model.GetConfigFileRequestMode {
 	return c.Mode
 }
 
+// GetReleaseName 获取命中的发布名称
+func (c *ConfigFile) GetReleaseName() string {
+	for _, tag := range c.Tags {
+		if tag.Key == ConfigFileTagKeyReleaseName {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+// GetEncryptKeyID 获取配置文件客户端密钥 ID
+func (c *ConfigFile) GetEncryptKeyID() (string, bool) {
+	for _, tag := range c.Tags {
+		if tag.Key == ConfigFileTagKeyEncryptKeyID {
+			return tag.Value, true
+		}
+	}
+	return "", false
+}
+
 // GetEncryptAlgo 获取配置文件数据加密算法
 func (c *ConfigFile) GetEncryptAlgo() string {
 	for _, tag := range c.Tags {
@@ -0,0 +1,36 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package events
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// EventReporter 【扩展点接口】上报治理状态迁移事件
+type EventReporter interface {
+	plugin.Plugin
+	// ReportEvent 上报一个治理事件，实现需保证不阻塞调用方
+	ReportEvent(event *model.GovernanceEvent) error
+}
+
+// init 初始化
+func init() {
+	plugin.RegisterPluginInterface(common.TypeEventReporter, new(EventReporter))
+}
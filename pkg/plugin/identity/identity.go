@@ -0,0 +1,56 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package identity
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// IdentityProvider 【扩展点接口】工作负载身份提供者，为与server的双向认证提供证书，并提供调用方身份标识
+type IdentityProvider interface {
+	plugin.Plugin
+	// GetIdentity 获取当前工作负载的身份标识，如 spiffe://example.org/ns/default/sa/foo
+	GetIdentity() string
+	// GetCertificate 获取当前身份证书及私钥，证书轮转后返回新的证书
+	GetCertificate() (*tls.Certificate, error)
+	// GetTrustBundle 获取校验server证书的信任CA证书池
+	GetTrustBundle() (*x509.CertPool, error)
+}
+
+// GetIdentityProvider 获取配置启用的身份提供插件，未启用时返回空
+func GetIdentityProvider(cfg config.Configuration, supplier plugin.Supplier) (IdentityProvider, error) {
+	identityCfg := cfg.GetGlobal().GetIdentityProvider()
+	if !identityCfg.IsEnable() {
+		return nil, nil
+	}
+	targetPlugin, err := supplier.GetPlugin(common.TypeIdentityProvider, identityCfg.GetType())
+	if err != nil {
+		return nil, err
+	}
+	return targetPlugin.(IdentityProvider), nil
+}
+
+// init 初始化
+func init() {
+	plugin.RegisterPluginInterface(common.TypeIdentityProvider, new(IdentityProvider))
+}
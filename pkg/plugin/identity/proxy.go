@@ -0,0 +1,59 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package identity
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// Proxy is a proxy plugin for identity provider
+type Proxy struct {
+	IdentityProvider
+	engine model.Engine
+}
+
+// SetRealPlugin 设置
+func (p *Proxy) SetRealPlugin(plug plugin.Plugin, engine model.Engine) {
+	p.IdentityProvider = plug.(IdentityProvider)
+	p.engine = engine
+}
+
+// GetIdentity 获取当前工作负载的身份标识
+func (p *Proxy) GetIdentity() string {
+	return p.IdentityProvider.GetIdentity()
+}
+
+// GetCertificate 获取当前身份证书及私钥
+func (p *Proxy) GetCertificate() (*tls.Certificate, error) {
+	return p.IdentityProvider.GetCertificate()
+}
+
+// GetTrustBundle 获取信任的CA证书池
+func (p *Proxy) GetTrustBundle() (*x509.CertPool, error) {
+	return p.IdentityProvider.GetTrustBundle()
+}
+
+// init 注册proxy
+func init() {
+	plugin.RegisterPluginProxy(common.TypeIdentityProvider, &Proxy{})
+}
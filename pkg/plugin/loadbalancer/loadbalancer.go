@@ -29,6 +29,8 @@ type Criteria struct {
 	HashKey []byte
 	// 用户传入用于计算hash的int值
 	HashValue uint64
+	// 会话标识，用于会话保持的负载均衡
+	SessionKey string
 	// 分配时忽略半开实例，只有当没有其他节点时才分配半开节点
 	IgnoreHalfOpen bool
 	// 必选，目标cluster
@@ -50,6 +52,21 @@ type LoadBalancer interface {
 	ChooseInstance(criteria *Criteria, instances model.ServiceInstances) (model.Instance, error)
 }
 
+// CallResultAware 【可选接口】负载均衡插件实现该接口后可感知服务调用结果，用于基于时延等动态指标的负载均衡
+type CallResultAware interface {
+	// UpdateCallResult 更新服务调用结果
+	UpdateCallResult(result *model.ServiceCallResult)
+}
+
+// GetCallResultAware 获取负载均衡插件实现的CallResultAware接口，会穿透Proxy
+func GetCallResultAware(plug plugin.Plugin) (CallResultAware, bool) {
+	if proxy, ok := plug.(*Proxy); ok {
+		plug = proxy.LoadBalancer
+	}
+	aware, ok := plug.(CallResultAware)
+	return aware, ok
+}
+
 // init 初始化
 func init() {
 	plugin.RegisterPluginInterface(common.TypeLoadBalancer, new(LoadBalancer))
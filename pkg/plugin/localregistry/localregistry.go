@@ -20,6 +20,7 @@ package localregistry
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 
@@ -140,6 +141,53 @@ type RuleRegistry interface {
 	UnwatchService(svcEventKey model.ServiceEventKey)
 }
 
+// FreshnessAware 【可选接口】本地缓存实现该接口后支持按新鲜度获取服务实例
+type FreshnessAware interface {
+	// GetInstancesAge 获取服务实例缓存距最近一次服务端确认的时长，缓存不存在时返回false
+	GetInstancesAge(svcKey *model.ServiceKey) (time.Duration, bool)
+	// RefreshInstances 尽快向服务端发起一次服务实例拉取，收到应答后通知
+	RefreshInstances(svcKey *model.ServiceKey) (*common.Notifier, error)
+}
+
+// CacheInspector 【可选接口】本地缓存实现该接口后支持列出已缓存的资源，用于调试观测
+type CacheInspector interface {
+	// ListCachedKeys 列出已加载到本地缓存中的所有资源标识
+	ListCachedKeys() []model.ServiceEventKey
+}
+
+// GetCacheInspector 获取本地缓存实现的CacheInspector接口，会穿透Proxy
+func GetCacheInspector(plug plugin.Plugin) (CacheInspector, bool) {
+	if proxy, ok := plug.(*Proxy); ok {
+		plug = proxy.LocalRegistry
+	}
+	inspector, ok := plug.(CacheInspector)
+	return inspector, ok
+}
+
+// CacheStatistics 【可选接口】本地缓存实现该接口后支持输出缓存规模及持久化情况，用于SDK自监控
+type CacheStatistics interface {
+	// GetCacheStatistics 获取已加载实例的服务数、实例总数及持久化失败的累计次数
+	GetCacheStatistics() (services int, instances int, persistFailures int64)
+}
+
+// GetCacheStatistics 获取本地缓存实现的CacheStatistics接口，会穿透Proxy
+func GetCacheStatistics(plug plugin.Plugin) (CacheStatistics, bool) {
+	if proxy, ok := plug.(*Proxy); ok {
+		plug = proxy.LocalRegistry
+	}
+	statistics, ok := plug.(CacheStatistics)
+	return statistics, ok
+}
+
+// GetFreshnessAware 获取本地缓存实现的FreshnessAware接口，会穿透Proxy
+func GetFreshnessAware(plug plugin.Plugin) (FreshnessAware, bool) {
+	if proxy, ok := plug.(*Proxy); ok {
+		plug = proxy.LocalRegistry
+	}
+	aware, ok := plug.(FreshnessAware)
+	return aware, ok
+}
+
 // init 初始化
 func init() {
 	plugin.RegisterPluginInterface(common.TypeLocalRegistry, new(LocalRegistry))
@@ -18,6 +18,7 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"sync/atomic"
@@ -85,6 +86,8 @@ type Manager interface {
 	InitPlugins(initContext InitContext, types []common.Type, engine model.Engine, delegate func() error) (err error)
 	// DestroyPlugins 销毁已初始化的插件列表
 	DestroyPlugins() (err error)
+	// FlushPlugins 调用实现了Flusher接口的插件，写出缓冲中的数据，用于优雅销毁
+	FlushPlugins(ctx context.Context) error
 	// StartPlugins 执行已经初始化完毕的插件
 	StartPlugins() error
 }
@@ -95,6 +98,14 @@ type pluginWrapper struct {
 	id int32
 	// 具体插件标识
 	instance Plugin
+	// 被代理的插件实例
+	origin Plugin
+}
+
+// Flusher 【可选接口】插件实现该接口后，SDK上下文优雅销毁时会在销毁插件前调用，写出缓冲中的统计数据、缓存文件等
+type Flusher interface {
+	// Flush 写出缓冲中的数据，需在ctx结束前返回
+	Flush(ctx context.Context) error
 }
 
 // NewPluginManager 创建插件管理器实例
@@ -219,6 +230,7 @@ func (m *manager) InitPlugins(
 			wrapper := &pluginWrapper{
 				id:       plugClazz.pluginId,
 				instance: proxy,
+				origin:   plug,
 			}
 			plugInstances[proxy.Name()] = wrapper
 			pluginSlice = append(pluginSlice, wrapper)
@@ -307,6 +319,26 @@ func (m *manager) DestroyPlugins() (errs error) {
 	return nil
 }
 
+// FlushPlugins 调用实现了Flusher接口的插件，ctx结束后不再调用剩余的插件
+func (m *manager) FlushPlugins(ctx context.Context) (errs error) {
+	for typ, plugs := range m.plugins {
+		for name, plug := range plugs {
+			flusher, ok := plug.origin.(Flusher)
+			if !ok {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return multierror.Append(errs, fmt.Errorf("FlushPlugins: plugin %v:%s not flushed, %v", typ, name, err))
+			}
+			if err := flusher.Flush(ctx); err != nil {
+				errs = multierror.Append(errs, multierror.Prefix(err,
+					fmt.Sprintf("FlushPlugins: plugin %v:%s error, ", typ, name)))
+			}
+		}
+	}
+	return errs
+}
+
 // GetPlugin 获取插件
 func (m *manager) GetPlugin(typ common.Type, name string) (Plugin, error) {
 	plugins, exists := m.plugins[typ]
@@ -36,6 +36,22 @@ type StatReporter interface {
 	Info() model.StatInfo
 }
 
+// SDKStatReporter 【可选接口】插件实现该接口后才会收到SDK自身API调用的统计数据，
+// 未实现的插件不会收到SDKAPIStat类型的数据，避免SDK内部调用与用户的服务调用监控数据混合
+type SDKStatReporter interface {
+	// ReportSDKAPIStat 上报SDK自身API的一次调用结果
+	ReportSDKAPIStat(result *model.APICallResult) error
+}
+
+// GetSDKStatReporter 获取插件实现的SDKStatReporter接口，会穿透Proxy
+func GetSDKStatReporter(plug plugin.Plugin) (SDKStatReporter, bool) {
+	if proxy, ok := plug.(*Proxy); ok {
+		plug = proxy.StatReporter
+	}
+	reporter, ok := plug.(SDKStatReporter)
+	return reporter, ok
+}
+
 // init 初始化
 func init() {
 	plugin.RegisterPluginInterface(common.TypeStatReporter, new(StatReporter))
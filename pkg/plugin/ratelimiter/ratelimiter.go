@@ -18,6 +18,8 @@
 package ratelimiter
 
 import (
+	"time"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
@@ -45,6 +47,39 @@ type QuotaBucket interface {
 	GetAmountInfos() []AmountInfo
 }
 
+// TimedQuotaBucket 【可选接口】需要感知请求耗时的配额池，配额释放时会传入从分配到释放的耗时
+type TimedQuotaBucket interface {
+	// ReleaseWithDuration 释放配额
+	ReleaseWithDuration(duration time.Duration)
+}
+
+// ThrottlingQuotaBucket 【可选接口】支持匀速排队的配额池，配额不足时返回需要等待的时间
+type ThrottlingQuotaBucket interface {
+	// GetQuotaWithQueue 分配配额，等待时间超过maxQueueMs时拒绝
+	GetQuotaWithQueue(curTimeMs int64, token uint32, maxQueueMs int64) *model.QuotaResponse
+}
+
+// UsageRestorableBucket 【可选接口】支持归还上报失败的配额使用情况的配额池，用于服务端恢复后重新同步配额
+type UsageRestorableBucket interface {
+	// RestoreQuotaUsed 将上报失败的使用情况重新计入当前窗口
+	RestoreQuotaUsed(curTimeMilli int64, usage UsageInfo)
+}
+
+// RuleFreeRateLimiter 【可选接口】无需限流规则即可生效的限流插件，服务未匹配到限流规则时使用
+type RuleFreeRateLimiter interface {
+	// GetRuleFreeBucket 获取服务接口对应的配额池，返回nil表示不对该服务生效
+	GetRuleFreeBucket(svcKey model.ServiceKey, method string) QuotaBucket
+}
+
+// GetRuleFreeRateLimiter 获取限流插件实现的RuleFreeRateLimiter接口，会穿透Proxy
+func GetRuleFreeRateLimiter(plug plugin.Plugin) (RuleFreeRateLimiter, bool) {
+	if proxy, ok := plug.(*Proxy); ok {
+		plug = proxy.ServiceRateLimiter
+	}
+	limiter, ok := plug.(RuleFreeRateLimiter)
+	return limiter, ok
+}
+
 // init 初始化
 func init() {
 	plugin.RegisterPluginInterface(common.TypeRateLimiter, new(ServiceRateLimiter))
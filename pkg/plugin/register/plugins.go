@@ -21,7 +21,9 @@ import (
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
+	_ "github.com/polarismesh/polaris-go/pkg/plugin/events"
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/healthcheck"
+	_ "github.com/polarismesh/polaris-go/pkg/plugin/identity"
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/location"
@@ -31,29 +33,59 @@ import (
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/weightadjuster"
 	_ "github.com/polarismesh/polaris-go/plugin/circuitbreaker/composite"
+	_ "github.com/polarismesh/polaris-go/plugin/circuitbreaker/outlier"
+	_ "github.com/polarismesh/polaris-go/plugin/configconnector/nacos"
 	_ "github.com/polarismesh/polaris-go/plugin/configconnector/polaris"
 	_ "github.com/polarismesh/polaris-go/plugin/configfilter/crypto"
 	_ "github.com/polarismesh/polaris-go/plugin/configfilter/crypto/aes"
+	_ "github.com/polarismesh/polaris-go/plugin/configfilter/crypto/sm4"
+	_ "github.com/polarismesh/polaris-go/plugin/configfilter/localoverride"
+	_ "github.com/polarismesh/polaris-go/plugin/events/file"
+	_ "github.com/polarismesh/polaris-go/plugin/events/stdout"
+	_ "github.com/polarismesh/polaris-go/plugin/events/webhook"
 	_ "github.com/polarismesh/polaris-go/plugin/healthcheck/http"
 	_ "github.com/polarismesh/polaris-go/plugin/healthcheck/tcp"
 	_ "github.com/polarismesh/polaris-go/plugin/healthcheck/udp"
+	_ "github.com/polarismesh/polaris-go/plugin/identity/spiffe"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/hash"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/maglev"
+	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/p2c"
+	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/peakewma"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/ringhash"
+	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/sticky"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/weightedrandom"
 	_ "github.com/polarismesh/polaris-go/plugin/localregistry/inmemory"
 	_ "github.com/polarismesh/polaris-go/plugin/location"
+	_ "github.com/polarismesh/polaris-go/plugin/logger/sloglog"
 	_ "github.com/polarismesh/polaris-go/plugin/logger/zaplog"
+	_ "github.com/polarismesh/polaris-go/plugin/metrics/opentelemetry"
 	_ "github.com/polarismesh/polaris-go/plugin/metrics/prometheus"
+	_ "github.com/polarismesh/polaris-go/plugin/metrics/statsd"
+	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/adaptive"
+	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/concurrency"
 	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/reject"
+	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/slidinglog"
 	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/unirate"
+	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/warmup"
+	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/consul"
 	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/grpc"
+	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/http"
+	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/kubernetes"
+	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/nacos"
+	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/xds"
+	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/blacklist"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/canary"
+	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/canarypercent"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/dstmeta"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/filteronly"
+	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/lane"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/nearbybase"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/rulebase"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/setdivision"
+	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/version"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/zeroprotect"
+	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/zoneaware"
+	_ "github.com/polarismesh/polaris-go/plugin/trafficlabel/grpc"
+	_ "github.com/polarismesh/polaris-go/plugin/trafficlabel/http"
 	_ "github.com/polarismesh/polaris-go/plugin/weightadjuster/ratedelay"
 )
@@ -36,6 +36,8 @@ type ServiceEvent struct {
 	Value proto.Message
 	// 服务错误
 	Error model.SDKError
+	// Delta 是否为增量实例推送，连接器声明接受增量推送且服务端应答标记为增量时为true
+	Delta bool
 }
 
 // EventHandler 事件回调handler
@@ -90,6 +92,55 @@ type ServerConnector interface {
 	UpdateServers(key *model.ServiceEventKey) error
 }
 
+// ServiceRefresher 【可选接口】连接器实现该接口后支持立即刷新已监听的资源，用于强制获取最新数据
+type ServiceRefresher interface {
+	// RefreshServiceHandler 不等待刷新间隔，尽快向服务端发起一次拉取，资源未监听时返回error
+	RefreshServiceHandler(key *model.ServiceEventKey) error
+}
+
+// GetServiceRefresher 获取连接器实现的ServiceRefresher接口，会穿透Proxy
+func GetServiceRefresher(plug plugin.Plugin) (ServiceRefresher, bool) {
+	if proxy, ok := plug.(*Proxy); ok {
+		plug = proxy.ServerConnector
+	}
+	refresher, ok := plug.(ServiceRefresher)
+	return refresher, ok
+}
+
+// InstancesQuerier 【可选接口】连接器实现该接口后支持直接向服务端查询实例，并由服务端按实例元数据过滤
+type InstancesQuerier interface {
+	// QueryInstances 一次性查询服务实例，metadata为实例元数据过滤条件，应答不会写入本地缓存
+	QueryInstances(svcKey *model.ServiceKey, metadata map[string]string, timeout time.Duration) (*ServiceEvent, error)
+}
+
+// GetInstancesQuerier 获取连接器实现的InstancesQuerier接口，会穿透Proxy
+func GetInstancesQuerier(plug plugin.Plugin) (InstancesQuerier, bool) {
+	if proxy, ok := plug.(*Proxy); ok {
+		plug = proxy.ServerConnector
+	}
+	querier, ok := plug.(InstancesQuerier)
+	return querier, ok
+}
+
+// HealthStatistics 【可选接口】连接器实现该接口后支持输出自身的运行状态，用于SDK自监控
+type HealthStatistics interface {
+	// GetPendingDiscoverRequests 获取已发送但尚未收到应答的服务发现请求数
+	GetPendingDiscoverRequests() int
+	// GetDiscoverQueueDepth 获取等待处理的服务发现任务数
+	GetDiscoverQueueDepth() int
+	// GetReconnectCount 获取与服务端重新建立连接的累计次数
+	GetReconnectCount() int64
+}
+
+// GetHealthStatistics 获取连接器实现的HealthStatistics接口，会穿透Proxy
+func GetHealthStatistics(plug plugin.Plugin) (HealthStatistics, bool) {
+	if proxy, ok := plug.(*Proxy); ok {
+		plug = proxy.ServerConnector
+	}
+	statistics, ok := plug.(HealthStatistics)
+	return statistics, ok
+}
+
 // 初始化
 func init() {
 	plugin.RegisterPluginInterface(common.TypeServerConnector, new(ServerConnector))
@@ -65,10 +65,16 @@ type RouteInfo struct {
 	FailOverDefaultMeta model.FailOverDefaultMetaConfig
 	// 金丝雀
 	Canary string
+	// 金丝雀按比例分流的hash key
+	CanaryHashKey string
+	// 目标实例的版本约束
+	TargetVersion string
 	// 进行匹配的规则类型，如规则路由有入规则和出规则之分
 	MatchRuleType RuleType
 	// 规则路由失败降级类型
 	FailOverType *FailOverType
+	// 路由过程记录，为空时不记录
+	Trace *RouteTrace
 }
 
 // Init 初始化map
@@ -89,8 +95,13 @@ func (r *RouteInfo) ClearValue() {
 	r.DestRouteRule = nil
 	r.SourceService = nil
 	r.FilterOnlyRouter = nil
+	r.EnableFailOverDefaultMeta = false
+	r.FailOverDefaultMeta = model.FailOverDefaultMetaConfig{}
+	r.CanaryHashKey = ""
+	r.TargetVersion = ""
 	r.MatchRuleType = UnknownRule
 	r.ignoreFilterOnlyOnEndChain = false
+	r.Trace = nil
 	for k := range r.chainEnables {
 		r.chainEnables[k] = true
 	}
@@ -0,0 +1,77 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package servicerouter
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// RouteTrace 一次路由过程中各路由插件的过滤记录，仅在开启调用诊断时记录
+type RouteTrace struct {
+	// SourceRuleRevision 匹配的源服务路由规则版本
+	SourceRuleRevision string `json:"sourceRuleRevision,omitempty"`
+	// DestRuleRevision 匹配的目标服务路由规则版本
+	DestRuleRevision string `json:"destRuleRevision,omitempty"`
+	// Steps 依次执行的路由插件及其过滤结果
+	Steps []RouteTraceStep `json:"steps"`
+}
+
+// RouteTraceStep 单个路由插件的过滤结果
+type RouteTraceStep struct {
+	// Router 路由插件名
+	Router string `json:"router"`
+	// Status 路由结束状态
+	Status string `json:"status"`
+	// InputInstances 过滤前的实例数
+	InputInstances int `json:"inputInstances"`
+	// OutputInstances 过滤后的实例数
+	OutputInstances int `json:"outputInstances"`
+	// Cluster 过滤后的集群元数据
+	Cluster string `json:"cluster,omitempty"`
+	// RedirectService 规则要求转发的目标服务
+	RedirectService string `json:"redirectService,omitempty"`
+}
+
+// begin 记录路由规则版本，返回路由前的实例数
+func (t *RouteTrace) begin(routeInfo *RouteInfo, svcClusters model.ServiceClusters) int {
+	if nil != routeInfo.SourceRouteRule && nil != routeInfo.SourceRouteRule.GetValue() {
+		t.SourceRuleRevision = routeInfo.SourceRouteRule.GetRevision()
+	}
+	if nil != routeInfo.DestRouteRule && nil != routeInfo.DestRouteRule.GetValue() {
+		t.DestRuleRevision = routeInfo.DestRouteRule.GetRevision()
+	}
+	return len(svcClusters.GetServiceInstances().GetInstances())
+}
+
+// addStep 记录路由插件的过滤结果，返回过滤后的实例数
+func (t *RouteTrace) addStep(router string, input int, result *RouteResult) int {
+	step := RouteTraceStep{
+		Router:         router,
+		Status:         result.Status.String(),
+		InputInstances: input,
+	}
+	if nil != result.RedirectDestService {
+		step.RedirectService = result.RedirectDestService.Namespace + "/" + result.RedirectDestService.Service
+	} else if nil != result.OutputCluster {
+		instances, _ := result.OutputCluster.GetInstances()
+		step.OutputInstances = len(instances)
+		step.Cluster = result.OutputCluster.ComposeMetaValue
+	}
+	t.Steps = append(t.Steps, step)
+	return step.OutputInstances
+}
@@ -59,6 +59,10 @@ func processServiceRouters(ctx model.ValueContext, routers []ServiceRouter, rout
 	svcClusters model.ServiceClusters, cluster *model.Cluster) (*RouteResult, model.SDKError) {
 	var result *RouteResult
 	var err error
+	var traceInput int
+	if nil != routeInfo.Trace {
+		traceInput = routeInfo.Trace.begin(routeInfo, svcClusters)
+	}
 	for _, router := range routers {
 		if !routeInfo.IsRouterEnable(router.ID()) || !router.Enable(routeInfo, svcClusters) {
 			continue
@@ -75,11 +79,16 @@ func processServiceRouters(ctx model.ValueContext, routers []ServiceRouter, rout
 		if err != nil {
 			return nil, err.(model.SDKError)
 		}
+		if nil != routeInfo.Trace {
+			traceInput = routeInfo.Trace.addStep(router.Name(), traceInput, result)
+		}
 		if nil != result.RedirectDestService {
 			// 转发规则
 			return result, nil
 		}
 		cluster = result.OutputCluster
+		// 路由插件可能剔除了部分实例，后续插件基于剔除后的服务集群继续过滤
+		svcClusters = cluster.GetClusters()
 	}
 	if !routeInfo.ignoreFilterOnlyOnEndChain {
 		// 需要执行一遍全死全活
@@ -94,6 +103,9 @@ func processServiceRouters(ctx model.ValueContext, routers []ServiceRouter, rout
 		if err != nil {
 			return nil, err.(model.SDKError)
 		}
+		if nil != routeInfo.Trace {
+			routeInfo.Trace.addStep(routeInfo.FilterOnlyRouter.Name(), traceInput, result)
+		}
 		cluster = result.OutputCluster
 	}
 	return result, nil
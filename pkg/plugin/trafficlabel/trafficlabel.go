@@ -0,0 +1,37 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package trafficlabel
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// TrafficLabelProvider 【扩展点接口】流量标签提取接口
+// 将gin/grpc/kitex等框架相关的请求对象适配为统一的TrafficRequest，从而保证路由、限流规则中引用的标签在各框架中取值一致
+type TrafficLabelProvider interface {
+	plugin.Plugin
+	// Adapt 将框架相关的请求对象适配为TrafficRequest，不支持该请求对象时返回false
+	Adapt(request interface{}) (model.TrafficRequest, bool)
+}
+
+// init 初始化
+func init() {
+	plugin.RegisterPluginInterface(common.TypeTrafficLabelProvider, new(TrafficLabelProvider))
+}
@@ -0,0 +1,416 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package scheduler 进程级的后台任务调度器
+package scheduler
+
+import (
+	"container/heap"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// Priority 任务优先级，同时到期的任务按优先级从高到低执行
+type Priority int
+
+const (
+	// PriorityLow 低优先级，如缓存落盘、日志打印
+	PriorityLow Priority = iota
+	// PriorityNormal 普通优先级，如规则刷新、过期检查
+	PriorityNormal
+	// PriorityHigh 高优先级，如实例心跳
+	PriorityHigh
+)
+
+const (
+	// minMaxWorkers 执行任务的最少协程数
+	minMaxWorkers = 4
+	// minBlockingMaxWorkers 执行阻塞IO任务的最少协程数
+	minBlockingMaxWorkers = 16
+	// DefaultDrainTimeout 销毁时等待任务组中正在执行的任务结束的默认超时时间
+	DefaultDrainTimeout = 3 * time.Second
+)
+
+// Job 由中心调度器周期执行的任务
+type Job struct {
+	// Name 任务名
+	Name string
+	// Period 调度周期
+	Period time.Duration
+	// Jitter 每次调度在周期基础上随机增减的比例，取值[0, 1)，用于打散多个 SDK 上下文的同类任务
+	Jitter float64
+	// Priority 任务优先级
+	Priority Priority
+	// RunImmediately 是否在添加后立即执行一次，否则等待一个周期后执行
+	RunImmediately bool
+	// Run 任务执行函数，返回值大于0时作为下一次执行的延迟，等于0时使用 Period，小于0时结束该任务
+	Run func() time.Duration
+}
+
+// scheduledJob 调度器内部的任务状态
+type scheduledJob struct {
+	job   Job
+	group *Group
+	next  time.Time
+	seq   uint64
+	// timerIndex 在定时堆中的下标，不在堆中时为-1
+	timerIndex int
+	cancelled  bool
+}
+
+// nextDelay 计算下一次执行的延迟
+func (j *scheduledJob) nextDelay(delay time.Duration) time.Duration {
+	if delay == 0 {
+		delay = j.job.Period
+	}
+	return withJitter(delay, j.job.Jitter)
+}
+
+// withJitter 在 d 的基础上随机增减 jitter 比例
+func withJitter(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 || d <= 0 {
+		return d
+	}
+	if jitter >= 1 {
+		jitter = 0.99
+	}
+	return d + time.Duration((rand.Float64()*2-1)*jitter*float64(d))
+}
+
+// timerHeap 按下一次执行时间排序的任务堆
+type timerHeap []*scheduledJob
+
+func (h timerHeap) Len() int { return len(h) }
+
+func (h timerHeap) Less(i, j int) bool {
+	if h[i].next.Equal(h[j].next) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].next.Before(h[j].next)
+}
+
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].timerIndex = i
+	h[j].timerIndex = j
+}
+
+func (h *timerHeap) Push(x interface{}) {
+	job := x.(*scheduledJob)
+	job.timerIndex = len(*h)
+	*h = append(*h, job)
+}
+
+func (h *timerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	job := old[n-1]
+	old[n-1] = nil
+	job.timerIndex = -1
+	*h = old[:n-1]
+	return job
+}
+
+// readyHeap 已到期待执行的任务，按优先级从高到低、到期时间从早到晚排序
+type readyHeap []*scheduledJob
+
+func (h readyHeap) Len() int { return len(h) }
+
+func (h readyHeap) Less(i, j int) bool {
+	if h[i].job.Priority != h[j].job.Priority {
+		return h[i].job.Priority > h[j].job.Priority
+	}
+	if h[i].next.Equal(h[j].next) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].next.Before(h[j].next)
+}
+
+func (h readyHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *readyHeap) Push(x interface{}) { *h = append(*h, x.(*scheduledJob)) }
+
+func (h *readyHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	job := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return job
+}
+
+// Scheduler 进程级的中心任务调度器，由一个分发协程按到期时间分发任务，
+// 按需创建的执行协程按优先级执行任务，无任务时不占用协程
+type Scheduler struct {
+	mutex       sync.Mutex
+	timers      timerHeap
+	ready       readyHeap
+	wake        chan struct{}
+	dispatching bool
+	workers     int
+	maxWorkers  int
+	seq         uint64
+}
+
+var (
+	defaultScheduler      *Scheduler
+	defaultSchedulerOnce  sync.Once
+	blockingScheduler     *Scheduler
+	blockingSchedulerOnce sync.Once
+)
+
+// GetScheduler 获取进程级的调度器，同一进程内的所有 SDK 上下文共享
+func GetScheduler() *Scheduler {
+	defaultSchedulerOnce.Do(func() {
+		maxWorkers := runtime.NumCPU()
+		if maxWorkers < minMaxWorkers {
+			maxWorkers = minMaxWorkers
+		}
+		defaultScheduler = NewScheduler(maxWorkers)
+	})
+	return defaultScheduler
+}
+
+// GetBlockingScheduler 获取进程级的阻塞IO调度器，用于心跳、重新注册等需要同步等待服务端应答的任务。
+// 与 GetScheduler 使用各自的执行协程，服务端响应变慢时不会占满规则刷新、过期检查等任务的执行协程
+func GetBlockingScheduler() *Scheduler {
+	blockingSchedulerOnce.Do(func() {
+		maxWorkers := 2 * runtime.NumCPU()
+		if maxWorkers < minBlockingMaxWorkers {
+			maxWorkers = minBlockingMaxWorkers
+		}
+		blockingScheduler = NewScheduler(maxWorkers)
+	})
+	return blockingScheduler
+}
+
+// NewScheduler 创建调度器，maxWorkers 为同时执行任务的最大协程数
+func NewScheduler(maxWorkers int) *Scheduler {
+	if maxWorkers <= 0 {
+		maxWorkers = minMaxWorkers
+	}
+	return &Scheduler{
+		wake:       make(chan struct{}, 1),
+		maxWorkers: maxWorkers,
+	}
+}
+
+// NewGroup 创建任务组，同一组的任务可以一起取消并等待执行完毕
+func (s *Scheduler) NewGroup(name string) *Group {
+	return &Group{
+		name:      name,
+		scheduler: s,
+		jobs:      make(map[*scheduledJob]struct{}),
+	}
+}
+
+// addLocked 将任务加入定时堆，调用方需持有锁
+func (s *Scheduler) addLocked(job *scheduledJob, delay time.Duration) {
+	s.seq++
+	job.seq = s.seq
+	job.next = time.Now().Add(delay)
+	heap.Push(&s.timers, job)
+	if !s.dispatching {
+		s.dispatching = true
+		go s.dispatch()
+		return
+	}
+	if job.timerIndex == 0 {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// cancelLocked 取消任务，正在执行的任务在执行结束后不再调度，调用方需持有锁
+func (s *Scheduler) cancelLocked(job *scheduledJob) {
+	if job.cancelled {
+		return
+	}
+	job.cancelled = true
+	if job.timerIndex >= 0 {
+		heap.Remove(&s.timers, job.timerIndex)
+	}
+	delete(job.group.jobs, job)
+}
+
+// dispatch 分发到期的任务，定时堆为空时退出
+func (s *Scheduler) dispatch() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		s.mutex.Lock()
+		now := time.Now()
+		for len(s.timers) > 0 && !s.timers[0].next.After(now) {
+			heap.Push(&s.ready, heap.Pop(&s.timers))
+		}
+		for s.workers < s.maxWorkers && s.workers < len(s.ready) {
+			s.workers++
+			go s.work()
+		}
+		if len(s.timers) == 0 {
+			s.dispatching = false
+			s.mutex.Unlock()
+			return
+		}
+		wait := s.timers[0].next.Sub(now)
+		s.mutex.Unlock()
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// work 执行已到期的任务，没有待执行的任务时退出
+func (s *Scheduler) work() {
+	for {
+		s.mutex.Lock()
+		if len(s.ready) == 0 {
+			s.workers--
+			s.mutex.Unlock()
+			return
+		}
+		job := heap.Pop(&s.ready).(*scheduledJob)
+		if job.cancelled {
+			s.mutex.Unlock()
+			continue
+		}
+		job.group.inflight++
+		s.mutex.Unlock()
+
+		delay := runJob(job)
+
+		s.mutex.Lock()
+		job.group.finishLocked()
+		if delay < 0 {
+			s.cancelLocked(job)
+		} else if !job.cancelled {
+			s.addLocked(job, job.nextDelay(delay))
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// runJob 执行任务，任务 panic 时记录日志并按周期继续调度
+func runJob(job *scheduledJob) (delay time.Duration) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.GetBaseLogger().Errorf("[Schedule] job %s of group %s panic: %v", job.job.Name, job.group.name, err)
+			delay = 0
+		}
+	}()
+	return job.job.Run()
+}
+
+// Group 任务组，通常对应一个 SDK 上下文中的一个模块
+type Group struct {
+	name      string
+	scheduler *Scheduler
+	jobs      map[*scheduledJob]struct{}
+	closed    bool
+	inflight  int
+	drained   chan struct{}
+}
+
+// JobHandle 已添加任务的句柄
+type JobHandle struct {
+	job *scheduledJob
+}
+
+// Cancel 取消任务，正在执行的任务不会被中断，但执行结束后不再调度
+func (h *JobHandle) Cancel() {
+	if nil == h {
+		return
+	}
+	s := h.job.group.scheduler
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.cancelLocked(h.job)
+}
+
+// Schedule 添加周期任务，任务组已关闭时返回nil
+func (g *Group) Schedule(job Job) *JobHandle {
+	s := g.scheduler
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if g.closed {
+		log.GetBaseLogger().Warnf("[Schedule] group %s has been drained, job %s ignored", g.name, job.Name)
+		return nil
+	}
+	if job.Period < clock.TimeStep() {
+		job.Period = clock.TimeStep()
+	}
+	sj := &scheduledJob{job: job, group: g, timerIndex: -1}
+	g.jobs[sj] = struct{}{}
+	var delay time.Duration
+	if !job.RunImmediately {
+		delay = sj.nextDelay(0)
+	}
+	s.addLocked(sj, delay)
+	return &JobHandle{job: sj}
+}
+
+// finishLocked 任务执行结束，调用方需持有调度器的锁
+func (g *Group) finishLocked() {
+	g.inflight--
+	if g.inflight == 0 && g.drained != nil {
+		close(g.drained)
+		g.drained = nil
+	}
+}
+
+// Drain 关闭任务组，取消所有任务并等待正在执行的任务结束，超时返回false
+func (g *Group) Drain(timeout time.Duration) bool {
+	s := g.scheduler
+	s.mutex.Lock()
+	g.closed = true
+	for job := range g.jobs {
+		s.cancelLocked(job)
+	}
+	if g.inflight == 0 {
+		s.mutex.Unlock()
+		return true
+	}
+	if g.drained == nil {
+		g.drained = make(chan struct{})
+	}
+	drained := g.drained
+	s.mutex.Unlock()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-drained:
+		return true
+	case <-timer.C:
+		log.GetBaseLogger().Warnf("[Schedule] group %s drain timeout after %v", g.name, timeout)
+		return false
+	}
+}
@@ -0,0 +1,226 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package scheduler
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+// waitReady 等待指定数量的任务到期并进入待执行队列
+func waitReady(t *testing.T, s *Scheduler, count int) {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		s.mutex.Lock()
+		ready := len(s.ready)
+		s.mutex.Unlock()
+		if ready >= count {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expect %d ready jobs", count)
+}
+
+func TestSchedulerPriority(t *testing.T) {
+	s := NewScheduler(1)
+	group := s.NewGroup("test")
+	// 唯一的执行协程被占用期间到期的任务按优先级从高到低执行
+	started := make(chan struct{})
+	release := make(chan struct{})
+	group.Schedule(Job{
+		Name:           "blocker",
+		Period:         time.Hour,
+		RunImmediately: true,
+		Run: func() time.Duration {
+			close(started)
+			<-release
+			return -1
+		},
+	})
+	<-started
+
+	var mutex sync.Mutex
+	var order []Priority
+	done := make(chan struct{}, 3)
+	for _, priority := range []Priority{PriorityLow, PriorityNormal, PriorityHigh} {
+		priority := priority
+		group.Schedule(Job{
+			Name:           "job",
+			Period:         time.Hour,
+			Priority:       priority,
+			RunImmediately: true,
+			Run: func() time.Duration {
+				mutex.Lock()
+				order = append(order, priority)
+				mutex.Unlock()
+				done <- struct{}{}
+				return -1
+			},
+		})
+	}
+	waitReady(t, s, 3)
+	close(release)
+	for i := 0; i < 3; i++ {
+		<-done
+	}
+	assert.Equal(t, []Priority{PriorityHigh, PriorityNormal, PriorityLow}, order)
+}
+
+func TestGroupDrain(t *testing.T) {
+	tests := []struct {
+		name    string
+		block   bool
+		timeout time.Duration
+		expect  bool
+	}{
+		{
+			name:    "无执行中的任务",
+			timeout: 50 * time.Millisecond,
+			expect:  true,
+		},
+		{
+			name:    "执行中的任务超时未结束",
+			block:   true,
+			timeout: 50 * time.Millisecond,
+			expect:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler(1)
+			group := s.NewGroup("test")
+			started := make(chan struct{})
+			release := make(chan struct{})
+			group.Schedule(Job{
+				Name:           "job",
+				Period:         time.Hour,
+				RunImmediately: true,
+				Run: func() time.Duration {
+					close(started)
+					if tt.block {
+						<-release
+					}
+					return 0
+				},
+			})
+			<-started
+			if !tt.block {
+				// 等待任务执行结束
+				waitIdle(t, group)
+			}
+			assert.Equal(t, tt.expect, group.Drain(tt.timeout))
+			close(release)
+			// 任务结束后再次排空立即返回
+			assert.True(t, group.Drain(time.Second))
+			// 排空后的任务组不再接受新任务
+			assert.Nil(t, group.Schedule(Job{Name: "late", Period: time.Hour, Run: func() time.Duration { return 0 }}))
+		})
+	}
+}
+
+// waitIdle 等待任务组中没有执行中的任务
+func waitIdle(t *testing.T, g *Group) {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		g.scheduler.mutex.Lock()
+		inflight := g.inflight
+		g.scheduler.mutex.Unlock()
+		if inflight == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("expect group idle")
+}
+
+func TestSchedulerPanicRecovery(t *testing.T) {
+	s := NewScheduler(1)
+	group := s.NewGroup("test")
+	defer group.Drain(time.Second)
+	runs := make(chan int, 2)
+	count := 0
+	group.Schedule(Job{
+		Name:           "panic",
+		Period:         10 * time.Millisecond,
+		RunImmediately: true,
+		Run: func() time.Duration {
+			count++
+			runs <- count
+			if count == 1 {
+				panic("job panic")
+			}
+			return -1
+		},
+	})
+	// panic 后任务按周期继续调度
+	for expect := 1; expect <= 2; expect++ {
+		select {
+		case run := <-runs:
+			assert.Equal(t, expect, run)
+		case <-time.After(time.Second):
+			t.Fatalf("job not run %d times after panic", expect)
+		}
+	}
+}
+
+func TestJobHandleCancelDuringRun(t *testing.T) {
+	s := NewScheduler(1)
+	group := s.NewGroup("test")
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	runs := 0
+	handle := group.Schedule(Job{
+		Name:           "job",
+		Period:         10 * time.Millisecond,
+		RunImmediately: true,
+		Run: func() time.Duration {
+			mutex.Lock()
+			runs++
+			mutex.Unlock()
+			started <- struct{}{}
+			<-release
+			return 0
+		},
+	})
+	<-started
+	// 执行期间取消，执行结束后不再调度
+	handle.Cancel()
+	close(release)
+	waitIdle(t, group)
+	time.Sleep(50 * time.Millisecond)
+	mutex.Lock()
+	assert.Equal(t, 1, runs)
+	mutex.Unlock()
+	s.mutex.Lock()
+	assert.Empty(t, s.timers)
+	assert.Empty(t, group.jobs)
+	s.mutex.Unlock()
+}
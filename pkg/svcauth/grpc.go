@@ -0,0 +1,80 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package svcauth
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// http2Method gRPC 调用统一使用 POST
+const http2Method = "POST"
+
+// UnaryClientInterceptor 对 gRPC 调用签名，以完整方法名作为签名路径，请求体不参与签名
+func (s *Signer) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		headers := s.SignedHeaders(http2Method, method, UnsignedPayload)
+		pairs := make([]string, 0, len(headers)*2)
+		for key, value := range headers {
+			pairs = append(pairs, strings.ToLower(key), value)
+		}
+		return invoker(metadata.AppendToOutgoingContext(ctx, pairs...), method, req, reply, cc, opts...)
+	}
+}
+
+// UnaryServerInterceptor 被调方 gRPC 认证拦截器，认证通过后将主调方身份保存到上下文，失败时返回 Unauthenticated。
+// 配置了 allowedPeers 且连接为双向TLS时，优先使用证书身份认证
+func (v *Verifier) UnaryServerInterceptor(allowedPeers ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		identity, err := v.verifyContext(ctx, info.FullMethod, allowedPeers)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(WithIdentity(ctx, identity), req)
+	}
+}
+
+func (v *Verifier) verifyContext(ctx context.Context, fullMethod string, allowedPeers []string) (*Identity, error) {
+	if len(allowedPeers) > 0 {
+		if p, ok := peer.FromContext(ctx); ok {
+			if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+				return VerifyPeerCertificate(&tlsInfo.State, allowedPeers...)
+			}
+		}
+	}
+	if v == nil {
+		return nil, ErrMissingSignature
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return v.VerifyHeaders(http2Method, fullMethod, UnsignedPayload, func(key string) string {
+		values := md.Get(key)
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	})
+}
@@ -0,0 +1,129 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package svcauth
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+)
+
+type identityContextKey struct{}
+
+// WithIdentity 将认证通过的主调方身份保存到上下文
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityContextKey{}, identity)
+}
+
+// IdentityFromContext 获取认证通过的主调方身份
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityContextKey{}).(*Identity)
+	return identity, ok
+}
+
+// SignRequest 对 http 请求签名，请求体可重放时其摘要参与签名
+func (s *Signer) SignRequest(req *http.Request) error {
+	contentSHA256, err := requestPayloadHash(req)
+	if err != nil {
+		return err
+	}
+	for key, value := range s.SignedHeaders(req.Method, req.URL.EscapedPath(), contentSHA256) {
+		req.Header.Set(key, value)
+	}
+	return nil
+}
+
+// VerifyRequest 校验 http 请求签名，请求声明了请求体摘要时读取请求体进行比对
+func (v *Verifier) VerifyRequest(req *http.Request) (*Identity, error) {
+	contentSHA256 := UnsignedPayload
+	if req.Header.Get(HeaderContentSHA256) != UnsignedPayload && req.Body != nil {
+		payload, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(payload))
+		contentSHA256 = payloadHash(payload)
+	}
+	return v.VerifyHeaders(req.Method, req.URL.EscapedPath(), contentSHA256, req.Header.Get)
+}
+
+// requestPayloadHash 计算请求体摘要，请求体无法重放时不参与签名
+func requestPayloadHash(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return payloadHash(nil), nil
+	}
+	if req.GetBody == nil {
+		return UnsignedPayload, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	payload, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	return payloadHash(payload), nil
+}
+
+// Transport 发送前对请求签名的 http.RoundTripper，可包装 httppolaris.RoundTripper 使用
+type Transport struct {
+	// Signer 签名器
+	Signer *Signer
+	// Next 实际发送请求的RoundTripper，为空时使用 http.DefaultTransport
+	Next http.RoundTripper
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	outReq := req.Clone(req.Context())
+	if err := t.Signer.SignRequest(outReq); err != nil {
+		return nil, err
+	}
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(outReq)
+}
+
+// Handler 被调方认证中间件，签名或证书校验通过后将主调方身份保存到请求上下文，失败时返回401。
+// 配置了 allowedPeers 且请求通过双向TLS建立时，优先使用证书身份认证
+func Handler(verifier *Verifier, next http.Handler, allowedPeers ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var (
+			identity *Identity
+			err      error
+		)
+		if len(allowedPeers) > 0 && req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+			identity, err = VerifyPeerCertificate(req.TLS, allowedPeers...)
+		} else if verifier != nil {
+			identity, err = verifier.VerifyRequest(req)
+		} else {
+			err = ErrMissingSignature
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req.WithContext(WithIdentity(req.Context(), identity)))
+	})
+}
@@ -0,0 +1,110 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package svcauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// MetadataAuthMode 实例元数据中声明的认证方式
+	MetadataAuthMode = "polaris.auth.mode"
+	// MetadataAuthIdentity 实例元数据中声明的证书身份，即实例证书中应包含的 SAN
+	MetadataAuthIdentity = "polaris.auth.identity"
+	// AuthModeHMAC 基于共享密钥的请求签名
+	AuthModeHMAC = "hmac"
+	// AuthModeMTLS 基于双向TLS证书的身份断言
+	AuthModeMTLS = "mtls"
+)
+
+// ErrIdentityMismatch 对端证书身份与预期不一致
+var ErrIdentityMismatch = errors.New("svcauth: peer identity mismatch")
+
+// PublishIdentity 在注册请求的元数据中声明本实例的认证方式及证书身份，供主调方校验
+func PublishIdentity(req *model.InstanceRegisterRequest, mode string, identity string) {
+	if req.Metadata == nil {
+		req.Metadata = make(map[string]string, 2)
+	}
+	req.Metadata[MetadataAuthMode] = mode
+	if len(identity) > 0 {
+		req.Metadata[MetadataAuthIdentity] = identity
+	}
+}
+
+// InstanceIdentity 获取实例声明的认证方式及证书身份
+func InstanceIdentity(inst model.Instance) (mode string, identity string) {
+	metadata := inst.GetMetadata()
+	if metadata == nil {
+		return "", ""
+	}
+	return metadata[MetadataAuthMode], metadata[MetadataAuthIdentity]
+}
+
+// VerifyInstancePeer 主调方校验所连接实例的证书是否与其注册时声明的身份一致，实例未声明证书身份时不校验
+func VerifyInstancePeer(state *tls.ConnectionState, inst model.Instance) error {
+	mode, identity := InstanceIdentity(inst)
+	if mode != AuthModeMTLS || len(identity) == 0 {
+		return nil
+	}
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("svcauth: instance %s:%d declares mtls but presents no certificate",
+			inst.GetHost(), inst.GetPort())
+	}
+	_, err := matchSAN(state.PeerCertificates[0], identity)
+	return err
+}
+
+// VerifyPeerCertificate 被调方校验主调方证书，证书 SAN 命中任一允许的身份即通过，allowed 为空时接受任意已校验的证书
+func VerifyPeerCertificate(state *tls.ConnectionState, allowed ...string) (*Identity, error) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil, ErrMissingSignature
+	}
+	principal, err := matchSAN(state.PeerCertificates[0], allowed...)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{Principal: principal}, nil
+}
+
+// matchSAN 匹配证书中的 URI 及 DNS SAN，返回命中的身份
+func matchSAN(cert *x509.Certificate, allowed ...string) (string, error) {
+	sans := make([]string, 0, len(cert.URIs)+len(cert.DNSNames))
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	sans = append(sans, cert.DNSNames...)
+	if len(allowed) == 0 {
+		if len(sans) == 0 {
+			return cert.Subject.CommonName, nil
+		}
+		return sans[0], nil
+	}
+	for _, san := range sans {
+		for _, expect := range allowed {
+			if san == expect {
+				return san, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: certificate %v does not match %v", ErrIdentityMismatch, sans, allowed)
+}
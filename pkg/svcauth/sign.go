@@ -0,0 +1,220 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package svcauth 提供服务间调用的身份认证，主调方使用注册的服务身份对请求签名，被调方校验对端身份，
+// 支持基于共享密钥的 HMAC 签名及基于双向TLS证书 SAN 的身份断言
+package svcauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// HeaderCaller 主调方身份，格式为 namespace/service
+	HeaderCaller = "X-Polaris-Caller"
+	// HeaderTimestamp 签名时间，unix秒
+	HeaderTimestamp = "X-Polaris-Timestamp"
+	// HeaderContentSHA256 请求体的 sha256 摘要，请求体无法重放时为 UNSIGNED-PAYLOAD
+	HeaderContentSHA256 = "X-Polaris-Content-Sha256"
+	// HeaderSignature 请求签名，base64(HMAC-SHA256(key, canonical))
+	HeaderSignature = "X-Polaris-Signature"
+	// UnsignedPayload 请求体不参与签名时的摘要取值
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+	// DefaultMaxClockSkew 默认允许的签名时间偏差
+	DefaultMaxClockSkew = 5 * time.Minute
+)
+
+var (
+	// ErrMissingSignature 请求未携带签名
+	ErrMissingSignature = errors.New("svcauth: request is not signed")
+	// ErrInvalidSignature 签名校验失败
+	ErrInvalidSignature = errors.New("svcauth: signature mismatch")
+	// ErrExpiredSignature 签名时间超出允许的偏差
+	ErrExpiredSignature = errors.New("svcauth: signature expired")
+	// ErrUnknownCaller 找不到主调方的签名密钥
+	ErrUnknownCaller = errors.New("svcauth: unknown caller")
+)
+
+// Identity 通过认证的主调方身份
+type Identity struct {
+	// Caller 主调方服务
+	Caller model.ServiceKey
+	// Principal 通过证书认证时为证书中匹配的 SAN，如 spiffe://example.org/ns/default/sa/foo
+	Principal string
+}
+
+// String 输出身份
+func (i Identity) String() string {
+	if len(i.Principal) > 0 {
+		return i.Principal
+	}
+	return FormatCaller(i.Caller)
+}
+
+// FormatCaller 将服务格式化为 namespace/service
+func FormatCaller(svc model.ServiceKey) string {
+	return svc.Namespace + "/" + svc.Service
+}
+
+// ParseCaller 解析 namespace/service 格式的主调方身份
+func ParseCaller(value string) (model.ServiceKey, error) {
+	idx := strings.Index(value, "/")
+	if idx <= 0 || idx == len(value)-1 {
+		return model.ServiceKey{}, fmt.Errorf("svcauth: invalid caller %q", value)
+	}
+	return model.ServiceKey{Namespace: value[:idx], Service: value[idx+1:]}, nil
+}
+
+// KeyResolver 根据主调方服务获取签名密钥
+type KeyResolver func(caller model.ServiceKey) ([]byte, error)
+
+// StaticKeys 使用固定映射获取签名密钥，key 为 namespace/service
+func StaticKeys(keys map[string][]byte) KeyResolver {
+	return func(caller model.ServiceKey) ([]byte, error) {
+		key, ok := keys[FormatCaller(caller)]
+		if !ok {
+			return nil, ErrUnknownCaller
+		}
+		return key, nil
+	}
+}
+
+// Signer 主调方签名器
+type Signer struct {
+	// Caller 主调方注册的服务身份
+	Caller model.ServiceKey
+	// Key 签名密钥，与被调方约定
+	Key []byte
+	// Now 获取当前时间，为空时使用 time.Now
+	Now func() time.Time
+}
+
+// NewSigner 创建签名器
+func NewSigner(namespace string, service string, key []byte) *Signer {
+	return &Signer{
+		Caller: model.ServiceKey{Namespace: namespace, Service: service},
+		Key:    key,
+	}
+}
+
+// SignedHeaders 对一次调用签名，返回需要附加到请求上的头部
+func (s *Signer) SignedHeaders(method string, path string, contentSHA256 string) map[string]string {
+	caller := FormatCaller(s.Caller)
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	return map[string]string{
+		HeaderCaller:        caller,
+		HeaderTimestamp:     timestamp,
+		HeaderContentSHA256: contentSHA256,
+		HeaderSignature:     sign(s.Key, method, path, caller, timestamp, contentSHA256),
+	}
+}
+
+func (s *Signer) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// Verifier 被调方签名校验器
+type Verifier struct {
+	// Keys 获取主调方签名密钥
+	Keys KeyResolver
+	// MaxClockSkew 允许的签名时间偏差，为0时使用 DefaultMaxClockSkew
+	MaxClockSkew time.Duration
+	// Now 获取当前时间，为空时使用 time.Now
+	Now func() time.Time
+}
+
+// NewVerifier 创建签名校验器
+func NewVerifier(keys KeyResolver) *Verifier {
+	return &Verifier{Keys: keys}
+}
+
+// VerifyHeaders 校验请求签名，get 用于读取请求头
+func (v *Verifier) VerifyHeaders(method string, path string, contentSHA256 string,
+	get func(key string) string) (*Identity, error) {
+	callerValue := get(HeaderCaller)
+	signature := get(HeaderSignature)
+	timestampValue := get(HeaderTimestamp)
+	if len(callerValue) == 0 || len(signature) == 0 || len(timestampValue) == 0 {
+		return nil, ErrMissingSignature
+	}
+	caller, err := ParseCaller(callerValue)
+	if err != nil {
+		return nil, err
+	}
+	timestamp, err := strconv.ParseInt(timestampValue, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("svcauth: invalid timestamp %q", timestampValue)
+	}
+	skew := v.now().Sub(time.Unix(timestamp, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > v.maxClockSkew() {
+		return nil, ErrExpiredSignature
+	}
+	if get(HeaderContentSHA256) != contentSHA256 {
+		return nil, ErrInvalidSignature
+	}
+	key, err := v.Keys(caller)
+	if err != nil {
+		return nil, err
+	}
+	expected := sign(key, method, path, callerValue, timestampValue, contentSHA256)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+	return &Identity{Caller: caller}, nil
+}
+
+func (v *Verifier) now() time.Time {
+	if v.Now != nil {
+		return v.Now()
+	}
+	return time.Now()
+}
+
+func (v *Verifier) maxClockSkew() time.Duration {
+	if v.MaxClockSkew > 0 {
+		return v.MaxClockSkew
+	}
+	return DefaultMaxClockSkew
+}
+
+// sign 计算签名，规范串为各字段按换行拼接
+func sign(key []byte, method, path, caller, timestamp, contentSHA256 string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join([]string{method, path, caller, timestamp, contentSHA256}, "\n")))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// payloadHash 计算请求体摘要
+func payloadHash(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -26,6 +26,8 @@ import (
 	regexp "github.com/dlclark/regexp2"
 	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
 
+	"github.com/polarismesh/polaris-go/pkg/algorithm/hash"
+	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
@@ -47,6 +49,8 @@ type CompositeCircuitBreaker struct {
 	countersCache map[fault_tolerance.Level]*CountersBucket
 	// healthCheckers .
 	healthCheckers map[fault_tolerance.FaultDetectRule_Protocol]healthcheck.HealthChecker
+	// recoverCheckers 熔断恢复前的主动探测插件，consumer.healthCheck.when为on_recover时生效
+	recoverCheckers []healthcheck.HealthChecker
 	// healthCheckCache map[model.Resource]*ResourceHealthChecker
 	healthCheckCache *sync.Map
 	// serviceHealthCheckCache map[model.ServiceKey]map[model.Resource]*ResourceHealthChecker
@@ -77,6 +81,12 @@ type CompositeCircuitBreaker struct {
 	taskCtx context.Context
 	// executor
 	executor *TaskExecutor
+	// halfOpenRamp 半开逐级放量配置，类型为*halfOpenRamp，配置热更新时整体替换
+	halfOpenRamp atomic.Value
+	// listenerLock
+	listenerLock sync.RWMutex
+	// listeners 熔断状态监听器
+	listeners []model.CircuitBreakerStatusListener
 }
 
 // Init 初始化插件
@@ -90,6 +100,9 @@ func (c *CompositeCircuitBreaker) Init(ctx *plugin.InitContext) error {
 	c.pluginCtx.Plugins.RegisterEventSubscriber(common.OnServiceAdded, callbackHandler)
 	c.pluginCtx.Plugins.RegisterEventSubscriber(common.OnServiceUpdated, callbackHandler)
 	c.pluginCtx.Plugins.RegisterEventSubscriber(common.OnServiceDeleted, callbackHandler)
+	c.pluginCtx.Plugins.RegisterEventSubscriber(common.OnConfigReloaded, common.PluginEventHandler{
+		Callback: c.onConfigReloaded,
+	})
 	return nil
 }
 
@@ -108,6 +121,7 @@ func (c *CompositeCircuitBreaker) Start() error {
 		c.checkPeriod = defaultCheckPeriod
 	}
 	c.healthCheckInstanceExpireInterval = c.checkPeriod * defaultCheckPeriodMultiple
+	c.loadHalfOpenRamp()
 	c.engineFlow = c.pluginCtx.ValueCtx.GetEngine()
 	c.start = 1
 
@@ -125,6 +139,16 @@ func (c *CompositeCircuitBreaker) Start() error {
 		checker := item.(healthcheck.HealthChecker)
 		c.healthCheckers[checker.Protocol()] = checker
 	}
+	healthCheckCfg := c.pluginCtx.Config.GetConsumer().GetHealthCheck()
+	if healthCheckCfg.GetWhen() == config.HealthCheckOnRecover {
+		for _, name := range healthCheckCfg.GetChain() {
+			checker, err := c.pluginCtx.Plugins.GetPlugin(common.TypeHealthCheck, name)
+			if err != nil {
+				return err
+			}
+			c.recoverCheckers = append(c.recoverCheckers, checker.(healthcheck.HealthChecker))
+		}
+	}
 	registryPlugin, err := c.pluginCtx.Plugins.GetPlugin(common.TypeLocalRegistry, c.pluginCtx.Config.GetConsumer().GetLocalCache().GetType())
 	if err != nil {
 		return err
@@ -160,6 +184,21 @@ func (c *CompositeCircuitBreaker) CheckResource(res model.Resource) model.Circui
 	return counters.CurrentCircuitBreakerStatus()
 }
 
+// AddStatusListener 添加熔断状态监听器
+func (c *CompositeCircuitBreaker) AddStatusListener(listener model.CircuitBreakerStatusListener) {
+	c.listenerLock.Lock()
+	defer c.listenerLock.Unlock()
+	c.listeners = append(c.listeners, listener)
+}
+
+func (c *CompositeCircuitBreaker) notifyStatusChange(res model.Resource, previous, current model.CircuitBreakerStatus) {
+	c.listenerLock.RLock()
+	defer c.listenerLock.RUnlock()
+	for _, listener := range c.listeners {
+		listener.OnStatusChange(res, previous, current)
+	}
+}
+
 // Report report resource invoke result stat
 func (c *CompositeCircuitBreaker) Report(stat *model.ResourceStat) error {
 	return c.doReport(stat, true)
@@ -250,6 +289,33 @@ func (c *CompositeCircuitBreaker) OnEvent(event *common.PluginEvent) error {
 	return nil
 }
 
+// halfOpenRamp 半开逐级放量配置
+type halfOpenRamp struct {
+	// ramp 逐级放量比例
+	ramp []int
+	// window 每一级的观察窗口
+	window time.Duration
+}
+
+func (c *CompositeCircuitBreaker) loadHalfOpenRamp() {
+	cbCfg := c.pluginCtx.Config.GetConsumer().GetCircuitBreaker()
+	c.halfOpenRamp.Store(&halfOpenRamp{ramp: cbCfg.GetHalfOpenRamp(), window: cbCfg.GetHalfOpenRampWindow()})
+}
+
+func (c *CompositeCircuitBreaker) getHalfOpenRamp() *halfOpenRamp {
+	value, _ := c.halfOpenRamp.Load().(*halfOpenRamp)
+	return value
+}
+
+// onConfigReloaded SDK配置热更新后刷新半开放量配置，仅对之后进入半开状态的资源生效
+func (c *CompositeCircuitBreaker) onConfigReloaded(event *common.PluginEvent) error {
+	if c.isDestroyed() || c.start == 0 {
+		return nil
+	}
+	c.loadHalfOpenRamp()
+	return nil
+}
+
 func (c *CompositeCircuitBreaker) doSchedule(expectKey model.ServiceEventKey) {
 	c.containers.Range(func(key, value interface{}) bool {
 		ruleC := value.(*RuleContainer)
@@ -315,36 +381,58 @@ func init() {
 	plugin.RegisterConfigurablePlugin(&CompositeCircuitBreaker{}, &circuitbreakConfig{})
 }
 
+// countersShardCount 熔断计数器的分片数，需为2的幂
+const countersShardCount = 16
+
 func newCountersBucket() *CountersBucket {
-	return &CountersBucket{m: make(map[string]*ResourceCounters)}
+	bucket := &CountersBucket{}
+	for i := range bucket.shards {
+		bucket.shards[i] = &countersShard{m: make(map[string]*ResourceCounters)}
+	}
+	return bucket
 }
 
+// CountersBucket 按资源分片存放的熔断计数器，降低高并发上报时的锁竞争
 type CountersBucket struct {
+	shards [countersShardCount]*countersShard
+}
+
+type countersShard struct {
 	lock sync.RWMutex
 	m    map[string]*ResourceCounters
 }
 
+func (c *CountersBucket) shard(key string) *countersShard {
+	return c.shards[hash.FNV32aString(hash.FNV32aOffset, key)&(countersShardCount-1)]
+}
+
 func (c *CountersBucket) get(key model.Resource) (*ResourceCounters, bool) {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
+	resKey := key.String()
+	shard := c.shard(resKey)
+	shard.lock.RLock()
+	defer shard.lock.RUnlock()
 
-	v, ok := c.m[key.String()]
+	v, ok := shard.m[resKey]
 	return v, ok
 }
 
 func (c *CountersBucket) put(key model.Resource, counter *ResourceCounters) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
+	resKey := key.String()
+	shard := c.shard(resKey)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
 
-	c.m[key.String()] = counter
+	shard.m[resKey] = counter
 }
 
 func (c *CountersBucket) remove(key model.Resource) (*ResourceCounters, bool) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
+	resKey := key.String()
+	shard := c.shard(resKey)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
 
-	v, ok := c.m[key.String()]
-	delete(c.m, key.String())
+	v, ok := shard.m[resKey]
+	delete(shard.m, resKey)
 	return v, ok
 }
 
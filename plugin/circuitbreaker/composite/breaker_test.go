@@ -0,0 +1,86 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// TestOnConfigReloaded 测试SDK配置热更新后半开放量配置刷新到熔断器
+func TestOnConfigReloaded(t *testing.T) {
+	tests := []struct {
+		name       string
+		start      int32
+		destroy    int32
+		wantRamp   []int
+		wantWindow time.Duration
+	}{
+		{
+			name:       "运行中的熔断器刷新半开放量",
+			start:      1,
+			wantRamp:   []int{20, 50, 100},
+			wantWindow: 30 * time.Second,
+		},
+		{
+			name:       "未启动的熔断器不刷新",
+			wantRamp:   []int{10, 100},
+			wantWindow: 10 * time.Second,
+		},
+		{
+			name:       "已销毁的熔断器不刷新",
+			start:      1,
+			destroy:    1,
+			wantRamp:   []int{10, 100},
+			wantWindow: 10 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+			cbCfg := cfg.GetConsumer().GetCircuitBreaker()
+			cbCfg.SetHalfOpenRamp([]int{10, 100})
+			cbCfg.SetHalfOpenRampWindow(10 * time.Second)
+			c := &CompositeCircuitBreaker{
+				pluginCtx: &plugin.InitContext{Config: cfg},
+				start:     tt.start,
+				destroy:   tt.destroy,
+			}
+			c.loadHalfOpenRamp()
+
+			// 与热更新流程一致，先原地修改配置再发送事件
+			cbCfg.SetHalfOpenRamp([]int{20, 50, 100})
+			cbCfg.SetHalfOpenRampWindow(30 * time.Second)
+			err := c.onConfigReloaded(&common.PluginEvent{
+				EventType:   common.OnConfigReloaded,
+				EventObject: &model.SDKConfigChangedEvent{Source: "test"},
+			})
+			assert.Nil(t, err)
+			ramp := c.getHalfOpenRamp()
+			assert.Equal(t, tt.wantRamp, ramp.ramp)
+			assert.Equal(t, tt.wantWindow, ramp.window)
+		})
+	}
+}
@@ -18,6 +18,7 @@
 package composite
 
 import (
+	"fmt"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -25,14 +26,24 @@ import (
 
 	regexp "github.com/dlclark/regexp2"
 	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	"github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/polarismesh/polaris-go/pkg/algorithm/match"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	pb "github.com/polarismesh/polaris-go/pkg/model/pb"
 	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
 	"github.com/polarismesh/polaris-go/plugin/circuitbreaker/composite/trigger"
 )
 
+const (
+	// minHalfOpenProbeBackoff 熔断窗口为0时恢复前探测失败后的首次重试间隔
+	minHalfOpenProbeBackoff = time.Second
+	// maxHalfOpenProbeBackoff 恢复前探测连续失败时重新调度的最大间隔，熔断窗口更长时以熔断窗口为准
+	maxHalfOpenProbeBackoff = 5 * time.Minute
+)
+
 const (
 	_stateCloseToOpen = iota
 	_stateOpenToHalfOpen
@@ -155,12 +166,90 @@ func (rc *ResourceCounters) toOpen(before model.CircuitBreakerStatus, name strin
 		})
 	rc.updateCircuitBreakerStatus(newStatus)
 	rc.reportCircuitStatus(newStatus)
+	rc.notifyStatusChange(before, newStatus)
 	rc.log.Infof("previous status %s, current status %s, resource %s, rule %s", before.GetStatus(),
 		newStatus.GetStatus(), rc.resource.String(), before.GetCircuitBreaker())
 	sleepWindow := rc.activeRule.GetRecoverCondition().GetSleepWindow()
 	delay := time.Duration(sleepWindow) * time.Second
 
-	rc.executor.AffinityDelayExecute(rc.activeRule.Id, delay, rc.OpenToHalfOpen)
+	rc.scheduleHalfOpen(delay)
+}
+
+// scheduleHalfOpen 熔断窗口结束后转为半开，开启恢复前探测时实例需要主动探测成功才允许半开，
+// 否则按指数退避等待下一次探测
+func (rc *ResourceCounters) scheduleHalfOpen(delay time.Duration) {
+	rc.scheduleHalfOpenAfter(delay, delay)
+}
+
+func (rc *ResourceCounters) scheduleHalfOpenAfter(sleepWindow, delay time.Duration) {
+	rc.executor.AffinityDelayExecute(rc.activeRule.Id, delay, func() {
+		// 资源已被清理或计数器已因规则变更被替换时，不再继续调度
+		if !rc.isTracked() || rc.CurrentCircuitBreakerStatus().GetStatus() != model.Open {
+			return
+		}
+		// 主动探测可能阻塞到探测超时，不能占用执行器的协程
+		go func() {
+			if !rc.probeBeforeHalfOpen() {
+				rc.scheduleHalfOpenAfter(sleepWindow, nextProbeDelay(sleepWindow, delay))
+				return
+			}
+			rc.executor.AffinityExecute(rc.activeRule.Id, rc.OpenToHalfOpen)
+		}()
+	})
+}
+
+// nextProbeDelay 探测失败后下一次探测的间隔，每次翻倍，不超过maxHalfOpenProbeBackoff与熔断窗口的较大值
+func nextProbeDelay(sleepWindow, delay time.Duration) time.Duration {
+	limit := maxHalfOpenProbeBackoff
+	if sleepWindow > limit {
+		limit = sleepWindow
+	}
+	next := delay * 2
+	if next <= 0 {
+		next = minHalfOpenProbeBackoff
+	}
+	if next > limit {
+		return limit
+	}
+	return next
+}
+
+// isTracked 当前计数器是否仍被熔断插件管理
+func (rc *ResourceCounters) isTracked() bool {
+	if rc.circuitBreaker == nil {
+		return true
+	}
+	if rc.circuitBreaker.isDestroyed() {
+		return false
+	}
+	bucket := rc.circuitBreaker.getLevelResourceCounters(rc.resource.GetLevel())
+	if bucket == nil {
+		return false
+	}
+	current, ok := bucket.get(rc.resource)
+	return ok && current == rc
+}
+
+// probeBeforeHalfOpen 使用健康探测链对被熔断的实例进行主动探测，全部探测成功才返回true
+func (rc *ResourceCounters) probeBeforeHalfOpen() bool {
+	if !rc.isInsRes || rc.circuitBreaker == nil || len(rc.circuitBreaker.recoverCheckers) == 0 {
+		return true
+	}
+	insRes := rc.resource.(*model.InstanceResource)
+	ins := pb.NewInstanceInProto(&service_manage.Instance{
+		Host:     wrapperspb.String(insRes.GetNode().Host),
+		Port:     wrapperspb.UInt32(insRes.GetNode().Port),
+		Protocol: wrapperspb.String(insRes.GetProtocol()),
+	}, defaultServiceKey(insRes.GetService()), nil)
+	for _, checker := range rc.circuitBreaker.recoverCheckers {
+		ret, err := checker.DetectInstance(ins, nil)
+		if err != nil || ret == nil || !ret.IsSuccess() {
+			rc.log.Infof("probe before half-open fail, resource %s, checker %s, err %v",
+				rc.resource.String(), checker.Name(), err)
+			return false
+		}
+	}
+	return true
 }
 
 func (rc *ResourceCounters) OpenToHalfOpen() {
@@ -172,11 +261,22 @@ func (rc *ResourceCounters) OpenToHalfOpen() {
 		return
 	}
 	consecutiveSuccess := rc.activeRule.GetRecoverCondition().ConsecutiveSuccess
-	halfOpenStatus := model.NewHalfOpenStatus(status.GetCircuitBreaker(), time.Now(), int(consecutiveSuccess))
+	var halfOpenStatus model.CircuitBreakerStatus
+	var ramp *halfOpenRamp
+	if rc.circuitBreaker != nil {
+		ramp = rc.circuitBreaker.getHalfOpenRamp()
+	}
+	if ramp != nil && len(ramp.ramp) > 0 {
+		halfOpenStatus = model.NewRampHalfOpenStatus(status.GetCircuitBreaker(), time.Now(), int(consecutiveSuccess),
+			ramp.ramp, ramp.window)
+	} else {
+		halfOpenStatus = model.NewHalfOpenStatus(status.GetCircuitBreaker(), time.Now(), int(consecutiveSuccess))
+	}
 	rc.log.Infof("previous status %s, current status %s, resource %s, rule %s", status.GetStatus(),
 		halfOpenStatus.GetStatus(), rc.resource.String(), status.GetCircuitBreaker())
 	rc.updateCircuitBreakerStatus(halfOpenStatus)
 	rc.reportCircuitStatus(halfOpenStatus)
+	rc.notifyStatusChange(status, halfOpenStatus)
 }
 
 func (rc *ResourceCounters) HalfOpenToClose() {
@@ -192,6 +292,7 @@ func (rc *ResourceCounters) HalfOpenToClose() {
 	rc.log.Infof("previous status %s, current status %s, resource %s, rule %s", status.GetStatus(),
 		newStatus.GetStatus(), rc.resource.String(), status.GetCircuitBreaker())
 	rc.reportCircuitStatus(newStatus)
+	rc.notifyStatusChange(status, newStatus)
 }
 
 func (rc *ResourceCounters) HalfOpenToOpen() {
@@ -210,7 +311,12 @@ func (rc *ResourceCounters) Report(stat *model.ResourceStat) {
 	curStatus := rc.CurrentCircuitBreakerStatus()
 	if curStatus != nil && curStatus.GetStatus() == model.HalfOpen {
 		halfOpenStatus := curStatus.(*model.HalfOpenStatus)
-		checked := halfOpenStatus.Report(isSuccess)
+		checked, previous := halfOpenStatus.ReportWithStage(isSuccess)
+		if previous != nil {
+			rc.log.Infof("half-open ramp percent %d -> %d, resource %s, rule %s", previous.GetRampPercent(),
+				halfOpenStatus.GetRampPercent(), rc.resource.String(), halfOpenStatus.GetCircuitBreaker())
+			rc.notifyStatusChange(previous, halfOpenStatus)
+		}
 		if !checked {
 			return
 		}
@@ -277,6 +383,56 @@ func (rc *ResourceCounters) reportCircuitStatus(newStatus model.CircuitBreakerSt
 		log.GetBaseLogger().Errorf("update instance circuitbreaker status fail, resource %s, rule %s, err %s",
 			insRes.String(), rc.activeRule.Id, err.Error())
 	}
+	rc.reportCircuitStat(insRes, newStatus)
+}
+
+// reportCircuitStat 上报熔断状态变化的统计数据
+func (rc *ResourceCounters) reportCircuitStat(insRes *model.InstanceResource, newStatus model.CircuitBreakerStatus) {
+	if rc.circuitBreaker.engineFlow == nil {
+		return
+	}
+	ins := pb.NewInstanceInProto(&service_manage.Instance{
+		Host:     wrapperspb.String(insRes.GetNode().Host),
+		Port:     wrapperspb.UInt32(insRes.GetNode().Port),
+		Protocol: wrapperspb.String(insRes.GetProtocol()),
+	}, defaultServiceKey(insRes.GetService()), nil)
+	gauge := &model.CircuitBreakGauge{
+		ChangeInstance: ins,
+		CBStatus:       newStatus,
+	}
+	_ = rc.circuitBreaker.engineFlow.SyncReportStat(model.CircuitBreakStat, gauge)
+}
+
+// notifyStatusChange 通知熔断状态监听器，半开放量比例变化时previous为上一级放量的状态快照
+func (rc *ResourceCounters) notifyStatusChange(previous, current model.CircuitBreakerStatus) {
+	if rc.circuitBreaker == nil {
+		return
+	}
+	rc.reportCircuitEvent(previous, current)
+	rc.circuitBreaker.notifyStatusChange(rc.resource, previous, current)
+}
+
+// reportCircuitEvent 实例被熔断或从熔断中恢复时上报治理事件
+func (rc *ResourceCounters) reportCircuitEvent(previous, current model.CircuitBreakerStatus) {
+	if !rc.isInsRes || rc.circuitBreaker.engineFlow == nil || previous.GetStatus() == current.GetStatus() {
+		return
+	}
+	var eventType model.GovernanceEventType
+	switch current.GetStatus() {
+	case model.Open:
+		eventType = model.EventInstanceEjected
+	case model.Close:
+		eventType = model.EventInstanceRecovered
+	default:
+		return
+	}
+	insRes := rc.resource.(*model.InstanceResource)
+	event := model.NewGovernanceEvent(eventType, insRes.GetService())
+	event.Instance = fmt.Sprintf("%s:%d", insRes.GetNode().Host, insRes.GetNode().Port)
+	event.PreviousStatus = previous.GetStatus().String()
+	event.CurrentStatus = current.GetStatus().String()
+	event.Reason = fmt.Sprintf("circuit breaker rule %s", current.GetCircuitBreaker())
+	_ = rc.circuitBreaker.engineFlow.SyncReportEvent(event)
 }
 
 func buildFallbackInfo(rule *fault_tolerance.CircuitBreakerRule) *model.FallbackInfo {
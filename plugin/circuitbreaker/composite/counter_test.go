@@ -0,0 +1,169 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package composite
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestNextProbeDelay 测试恢复前探测失败后的退避间隔
+func TestNextProbeDelay(t *testing.T) {
+	tests := []struct {
+		name        string
+		sleepWindow time.Duration
+		delay       time.Duration
+		want        time.Duration
+	}{
+		{name: "首次失败间隔翻倍", sleepWindow: 30 * time.Second, delay: 30 * time.Second, want: time.Minute},
+		{name: "多次失败继续翻倍", sleepWindow: 30 * time.Second, delay: 2 * time.Minute, want: 4 * time.Minute},
+		{name: "不超过最大退避间隔", sleepWindow: 30 * time.Second, delay: 4 * time.Minute, want: maxHalfOpenProbeBackoff},
+		{name: "熔断窗口大于最大退避间隔时以熔断窗口为准", sleepWindow: 10 * time.Minute, delay: 10 * time.Minute,
+			want: 10 * time.Minute},
+		{name: "熔断窗口为0", sleepWindow: 0, delay: 0, want: minHalfOpenProbeBackoff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextProbeDelay(tt.sleepWindow, tt.delay))
+		})
+	}
+}
+
+// TestResourceCountersIsTracked 测试资源被清理、计数器被替换或插件销毁后不再继续调度
+func TestResourceCountersIsTracked(t *testing.T) {
+	svcKey := &model.ServiceKey{Namespace: "Test", Service: "svc"}
+	res, err := model.NewInstanceResource(svcKey, nil, "http", "127.0.0.1", 8080)
+	assert.Nil(t, err)
+	cb := &CompositeCircuitBreaker{
+		countersCache: map[fault_tolerance.Level]*CountersBucket{
+			fault_tolerance.Level_INSTANCE: newCountersBucket(),
+		},
+	}
+	bucket := cb.getLevelResourceCounters(fault_tolerance.Level_INSTANCE)
+	rc := &ResourceCounters{circuitBreaker: cb, resource: res}
+	assert.False(t, rc.isTracked())
+
+	bucket.put(res, rc)
+	assert.True(t, rc.isTracked())
+
+	bucket.put(res, &ResourceCounters{circuitBreaker: cb, resource: res})
+	assert.False(t, rc.isTracked())
+
+	bucket.put(res, rc)
+	cb.destroy = 1
+	assert.False(t, rc.isTracked())
+
+	cb.destroy = 0
+	bucket.remove(res)
+	assert.False(t, rc.isTracked())
+}
+
+// benchResourceCount 并发测试使用的资源数
+const benchResourceCount = 256
+
+func newBenchResources(b *testing.B) []model.Resource {
+	svcKey := &model.ServiceKey{Namespace: "Test", Service: "svc"}
+	resources := make([]model.Resource, 0, benchResourceCount)
+	for i := 0; i < benchResourceCount; i++ {
+		res, err := model.NewInstanceResource(svcKey, nil, "http", "127.0.0.1", uint32(8000+i))
+		if err != nil {
+			b.Fatal(err)
+		}
+		resources = append(resources, res)
+	}
+	return resources
+}
+
+// lockedCountersBucket 改造前使用单把读写锁的熔断计数器，作为对照
+type lockedCountersBucket struct {
+	lock sync.RWMutex
+	m    map[string]*ResourceCounters
+}
+
+func (c *lockedCountersBucket) get(key model.Resource) (*ResourceCounters, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	v, ok := c.m[key.String()]
+	return v, ok
+}
+
+func (c *lockedCountersBucket) put(key model.Resource, counter *ResourceCounters) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.m[key.String()] = counter
+}
+
+// benchCounters 熔断计数器的读写操作
+type benchCounters interface {
+	get(key model.Resource) (*ResourceCounters, bool)
+	put(key model.Resource, counter *ResourceCounters)
+}
+
+// runCountersParallel 并发读取计数器，每writeEvery次读取进行一次写入，writeEvery为0时只读取
+func runCountersParallel(b *testing.B, bucket benchCounters, writeEvery int) {
+	resources := newBenchResources(b)
+	counters := make([]*ResourceCounters, len(resources))
+	for i, res := range resources {
+		counters[i] = &ResourceCounters{resource: res}
+		bucket.put(res, counters[i])
+	}
+	var seq uint32
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(atomic.AddUint32(&seq, 1))
+		for pb.Next() {
+			idx := i % len(resources)
+			if writeEvery > 0 && i%writeEvery == 0 {
+				bucket.put(resources[idx], counters[idx])
+			} else {
+				bucket.get(resources[idx])
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkCountersBucket_ParallelGet 测试分片后并发读取熔断计数器
+func BenchmarkCountersBucket_ParallelGet(b *testing.B) {
+	runCountersParallel(b, newCountersBucket(), 0)
+}
+
+// BenchmarkLockedCountersBucket_ParallelGet 测试改造前单锁并发读取熔断计数器，作为对照
+func BenchmarkLockedCountersBucket_ParallelGet(b *testing.B) {
+	runCountersParallel(b, &lockedCountersBucket{m: make(map[string]*ResourceCounters)}, 0)
+}
+
+// BenchmarkCountersBucket_ParallelMixed 测试分片后读写混合的并发访问
+func BenchmarkCountersBucket_ParallelMixed(b *testing.B) {
+	runCountersParallel(b, newCountersBucket(), 8)
+}
+
+// BenchmarkLockedCountersBucket_ParallelMixed 测试改造前单锁下读写混合的并发访问，作为对照
+func BenchmarkLockedCountersBucket_ParallelMixed(b *testing.B) {
+	runCountersParallel(b, &lockedCountersBucket{m: make(map[string]*ResourceCounters)}, 8)
+}
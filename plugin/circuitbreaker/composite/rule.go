@@ -86,7 +86,7 @@ func (c *RuleContainer) realRefreshCircuitBreaker() {
 		return
 	}
 	resourceCounters := c.breaker.getLevelResourceCounters(c.res.GetLevel())
-	cbRule := selectCircuitBreakerRule(c.res, resp, c.regexFunction)
+	cbRule := selectCircuitBreakerRule(c.res, resp, ruleCacheRegexFunction(resp, c.regexFunction))
 	if cbRule == nil {
 		if _, exist := resourceCounters.remove(c.res); exist {
 			c.scheduleHealthCheck()
@@ -128,7 +128,7 @@ func (c *RuleContainer) realRefreshHealthCheck() {
 			c.executor.AffinityDelayExecute(c.res.String(), 5*time.Second, c.realRefreshHealthCheck)
 			return
 		}
-		if faultDetector := selectFaultDetector(c.res, resp, c.regexFunction); faultDetector != nil {
+		if faultDetector := selectFaultDetector(c.res, resp, ruleCacheRegexFunction(resp, c.regexFunction)); faultDetector != nil {
 			if curChecker, ok := c.breaker.getResourceHealthChecker(c.res); ok {
 				curRule := curChecker.faultDetector
 				if curRule.Revision == faultDetector.Revision {
@@ -162,6 +162,21 @@ func (c *RuleContainer) realRefreshHealthCheck() {
 	}
 }
 
+// ruleCacheRegexFunction 优先使用规则缓存中预编译好的正则表达式，缓存不存在时再使用本地编译
+func ruleCacheRegexFunction(resp *model.ServiceRuleResponse, regexFunc func(string) *regexp.Regexp) func(string) *regexp.Regexp {
+	if resp == nil || resp.GetRuleCache() == nil {
+		return regexFunc
+	}
+	ruleCache := resp.GetRuleCache()
+	return func(s string) *regexp.Regexp {
+		regex, err := ruleCache.GetRegexMatcher(s)
+		if err != nil {
+			return nil
+		}
+		return regex
+	}
+}
+
 func selectCircuitBreakerRule(res model.Resource, object *model.ServiceRuleResponse, regexFunc func(string) *regexp.Regexp) *fault_tolerance.CircuitBreakerRule {
 	if object == nil {
 		return nil
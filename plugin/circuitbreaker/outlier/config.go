@@ -0,0 +1,132 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package outlier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DefaultConsecutive5xx 默认连续5xx错误数阈值
+	DefaultConsecutive5xx = 5
+	// DefaultInterval 默认的离群检测周期
+	DefaultInterval = 10 * time.Second
+	// DefaultBaseEjectionTime 默认的基础剔除时长，实际剔除时长为基础时长乘以剔除次数
+	DefaultBaseEjectionTime = 30 * time.Second
+	// DefaultMaxEjectionTime 默认的最大剔除时长
+	DefaultMaxEjectionTime = 300 * time.Second
+	// DefaultMaxEjectionPercent 默认的最大剔除实例百分比
+	DefaultMaxEjectionPercent = 10
+	// DefaultLatencyFactor 默认的时延倍数，实例P99时延超过服务平均时延的该倍数时剔除
+	DefaultLatencyFactor = 2.0
+	// DefaultMinimumRequests 默认参与时延检测的实例在周期内的最少请求数
+	DefaultMinimumRequests = 100
+	// DefaultMinimumHosts 默认参与时延检测的最少实例数
+	DefaultMinimumHosts = 3
+	// DefaultMaxSamples 默认单实例在周期内保留的最大时延样本数
+	DefaultMaxSamples = 1024
+)
+
+// Config 离群检测熔断配置
+type Config struct {
+	// Consecutive5xx 连续5xx错误数阈值，达到后立即剔除实例
+	Consecutive5xx int `yaml:"consecutive5xx" json:"consecutive5xx"`
+	// Interval 离群检测周期，周期性进行时延检测以及剔除实例的恢复
+	Interval time.Duration `yaml:"interval" json:"interval"`
+	// BaseEjectionTime 基础剔除时长
+	BaseEjectionTime time.Duration `yaml:"baseEjectionTime" json:"baseEjectionTime"`
+	// MaxEjectionTime 最大剔除时长
+	MaxEjectionTime time.Duration `yaml:"maxEjectionTime" json:"maxEjectionTime"`
+	// MaxEjectionPercent 单个服务最多可剔除的实例百分比
+	MaxEjectionPercent int `yaml:"maxEjectionPercent" json:"maxEjectionPercent"`
+	// LatencyFactor 时延倍数，实例P99时延超过服务平均时延的该倍数时剔除，必须大于1
+	LatencyFactor float64 `yaml:"latencyFactor" json:"latencyFactor"`
+	// MinimumRequests 参与时延检测的实例在周期内的最少请求数
+	MinimumRequests int `yaml:"minimumRequests" json:"minimumRequests"`
+	// MinimumHosts 参与时延检测的最少实例数
+	MinimumHosts int `yaml:"minimumHosts" json:"minimumHosts"`
+	// MaxSamples 单实例在周期内保留的最大时延样本数
+	MaxSamples int `yaml:"maxSamples" json:"maxSamples"`
+}
+
+// Verify 检验离群检测配置
+func (c *Config) Verify() error {
+	var errs error
+	if c.Consecutive5xx <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("outlier.consecutive5xx must be greater than 0"))
+	}
+	if c.Interval <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("outlier.interval must be greater than 0"))
+	}
+	if c.BaseEjectionTime <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("outlier.baseEjectionTime must be greater than 0"))
+	}
+	if c.MaxEjectionTime < c.BaseEjectionTime {
+		errs = multierror.Append(errs, fmt.Errorf("outlier.maxEjectionTime must not be less than baseEjectionTime"))
+	}
+	if c.MaxEjectionPercent <= 0 || c.MaxEjectionPercent > 100 {
+		errs = multierror.Append(errs, fmt.Errorf("outlier.maxEjectionPercent must be in range (0, 100]"))
+	}
+	if c.LatencyFactor <= 1 {
+		errs = multierror.Append(errs, fmt.Errorf("outlier.latencyFactor must be greater than 1"))
+	}
+	if c.MinimumRequests <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("outlier.minimumRequests must be greater than 0"))
+	}
+	if c.MinimumHosts <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("outlier.minimumHosts must be greater than 0"))
+	}
+	if c.MaxSamples <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("outlier.maxSamples must be greater than 0"))
+	}
+	return errs
+}
+
+// SetDefault 设置离群检测默认值
+func (c *Config) SetDefault() {
+	if c.Consecutive5xx == 0 {
+		c.Consecutive5xx = DefaultConsecutive5xx
+	}
+	if c.Interval == 0 {
+		c.Interval = DefaultInterval
+	}
+	if c.BaseEjectionTime == 0 {
+		c.BaseEjectionTime = DefaultBaseEjectionTime
+	}
+	if c.MaxEjectionTime == 0 {
+		c.MaxEjectionTime = DefaultMaxEjectionTime
+	}
+	if c.MaxEjectionPercent == 0 {
+		c.MaxEjectionPercent = DefaultMaxEjectionPercent
+	}
+	if c.LatencyFactor == 0 {
+		c.LatencyFactor = DefaultLatencyFactor
+	}
+	if c.MinimumRequests == 0 {
+		c.MinimumRequests = DefaultMinimumRequests
+	}
+	if c.MinimumHosts == 0 {
+		c.MinimumHosts = DefaultMinimumHosts
+	}
+	if c.MaxSamples == 0 {
+		c.MaxSamples = DefaultMaxSamples
+	}
+}
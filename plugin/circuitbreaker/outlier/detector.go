@@ -0,0 +1,269 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package outlier
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	reasonConsecutive5xx = "consecutive5xx"
+	reasonLatency        = "latency"
+)
+
+type statusUpdater func(res *model.InstanceResource, previous, current model.CircuitBreakerStatus)
+
+// statusTransition 实例熔断状态的一次变更，在锁内收集，释放锁后再通知，避免回调中重入检测器导致死锁
+type statusTransition struct {
+	res      *model.InstanceResource
+	previous model.CircuitBreakerStatus
+	current  model.CircuitBreakerStatus
+}
+
+// hostStat 单个实例在检测周期内的统计数据
+type hostStat struct {
+	res *model.InstanceResource
+	// consecutive5xx 当前连续5xx错误数
+	consecutive5xx int
+	// samples 周期内的时延样本环
+	samples []time.Duration
+	// requests 周期内的请求数
+	requests int
+	// ejectTime 剔除时间，零值表示未被剔除
+	ejectTime time.Time
+	// ejectCount 剔除次数，决定剔除时长，未剔除的周期会逐步衰减
+	ejectCount int
+	cbStatus   model.CircuitBreakerStatus
+}
+
+func (h *hostStat) ejected() bool {
+	return !h.ejectTime.IsZero()
+}
+
+func (h *hostStat) addSample(delay time.Duration, maxSamples int) {
+	if len(h.samples) < maxSamples {
+		h.samples = append(h.samples, delay)
+	} else {
+		h.samples[h.requests%maxSamples] = delay
+	}
+	h.requests++
+}
+
+// p99 计算周期内的P99时延
+func (h *hostStat) p99() time.Duration {
+	sorted := make([]time.Duration, len(h.samples))
+	copy(sorted, h.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(math.Ceil(float64(len(sorted))*0.99)) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+func (h *hostStat) resetWindow() {
+	h.samples = h.samples[:0]
+	h.requests = 0
+}
+
+// serviceDetector 单个服务的离群检测器
+type serviceDetector struct {
+	svcKey   model.ServiceKey
+	cfg      *Config
+	onUpdate statusUpdater
+	mutex    sync.Mutex
+	hosts    map[model.Node]*hostStat
+}
+
+func newServiceDetector(svcKey model.ServiceKey, cfg *Config, onUpdate statusUpdater) *serviceDetector {
+	return &serviceDetector{
+		svcKey:   svcKey,
+		cfg:      cfg,
+		onUpdate: onUpdate,
+		hosts:    make(map[model.Node]*hostStat),
+	}
+}
+
+func (s *serviceDetector) status(node model.Node) model.CircuitBreakerStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	host, ok := s.hosts[node]
+	if !ok {
+		return nil
+	}
+	return host.cbStatus
+}
+
+func (s *serviceDetector) report(res *model.InstanceResource, stat *model.ResourceStat, now time.Time) {
+	s.mutex.Lock()
+	transitions := s.doReport(res, stat, now)
+	s.mutex.Unlock()
+	s.notify(transitions)
+}
+
+func (s *serviceDetector) doReport(res *model.InstanceResource, stat *model.ResourceStat,
+	now time.Time) []statusTransition {
+	host, ok := s.hosts[res.GetNode()]
+	if !ok {
+		host = &hostStat{res: res}
+		s.hosts[res.GetNode()] = host
+	}
+	host.addSample(stat.Delay, s.cfg.MaxSamples)
+	if !is5xx(stat) {
+		host.consecutive5xx = 0
+		return nil
+	}
+	host.consecutive5xx++
+	if host.consecutive5xx >= s.cfg.Consecutive5xx && !host.ejected() {
+		return s.eject(nil, host, reasonConsecutive5xx, now)
+	}
+	return nil
+}
+
+// detect 周期性检测：恢复到期的实例，并基于时延偏离剔除实例，返回该服务是否已无统计数据可被清理
+func (s *serviceDetector) detect(now time.Time) bool {
+	s.mutex.Lock()
+	transitions, empty := s.doDetect(now)
+	s.mutex.Unlock()
+	s.notify(transitions)
+	return empty
+}
+
+func (s *serviceDetector) doDetect(now time.Time) ([]statusTransition, bool) {
+	var transitions []statusTransition
+	for node, host := range s.hosts {
+		if host.ejected() {
+			if now.Sub(host.ejectTime) >= s.ejectionTime(host) {
+				transitions = s.uneject(transitions, host, now)
+			}
+			continue
+		}
+		if host.requests == 0 && host.ejectCount == 0 {
+			delete(s.hosts, node)
+			continue
+		}
+		if host.ejectCount > 0 {
+			host.ejectCount--
+		}
+	}
+	transitions = s.detectLatency(transitions, now)
+	for _, host := range s.hosts {
+		host.resetWindow()
+	}
+	return transitions, len(s.hosts) == 0
+}
+
+// notify 在释放锁后通知状态变更
+func (s *serviceDetector) notify(transitions []statusTransition) {
+	for _, transition := range transitions {
+		s.onUpdate(transition.res, transition.previous, transition.current)
+	}
+}
+
+// detectLatency 剔除P99时延超过服务平均时延指定倍数的实例
+func (s *serviceDetector) detectLatency(transitions []statusTransition, now time.Time) []statusTransition {
+	candidates := make([]*hostStat, 0, len(s.hosts))
+	var (
+		total time.Duration
+		count int
+	)
+	for _, host := range s.hosts {
+		if host.ejected() || host.requests < s.cfg.MinimumRequests {
+			continue
+		}
+		candidates = append(candidates, host)
+		for _, sample := range host.samples {
+			total += sample
+		}
+		count += len(host.samples)
+	}
+	if len(candidates) < s.cfg.MinimumHosts || count == 0 {
+		return transitions
+	}
+	mean := float64(total) / float64(count)
+	threshold := time.Duration(mean * s.cfg.LatencyFactor)
+	for _, host := range candidates {
+		if host.p99() > threshold {
+			transitions = s.eject(transitions, host, reasonLatency, now)
+		}
+	}
+	return transitions
+}
+
+// eject 剔除实例，状态变更追加到transitions中返回，需在锁内调用
+func (s *serviceDetector) eject(transitions []statusTransition, host *hostStat, reason string,
+	now time.Time) []statusTransition {
+	var ejected int
+	for _, h := range s.hosts {
+		if h.ejected() {
+			ejected++
+		}
+	}
+	// 与envoy保持一致，无论百分比配置如何，至少允许剔除一个实例
+	if ejected > 0 && float64(ejected+1)*100 > float64(len(s.hosts)*s.cfg.MaxEjectionPercent) {
+		log.GetBaseLogger().Debugf("[CircuitBreaker][Outlier] skip eject %s, reason %s, max ejection percent reached",
+			host.res.String(), reason)
+		return transitions
+	}
+	previous := host.cbStatus
+	host.ejectTime = now
+	host.ejectCount++
+	host.consecutive5xx = 0
+	host.cbStatus = model.NewCircuitBreakerStatus(config.DefaultCircuitBreakerOutlier, model.Open, now)
+	log.GetBaseLogger().Infof("[CircuitBreaker][Outlier] eject %s, reason %s, ejection time %v",
+		host.res.String(), reason, s.ejectionTime(host))
+	return append(transitions, statusTransition{res: host.res, previous: previous, current: host.cbStatus})
+}
+
+// uneject 恢复实例，状态变更追加到transitions中返回，需在锁内调用
+func (s *serviceDetector) uneject(transitions []statusTransition, host *hostStat,
+	now time.Time) []statusTransition {
+	previous := host.cbStatus
+	host.ejectTime = time.Time{}
+	host.cbStatus = model.NewCircuitBreakerStatus(config.DefaultCircuitBreakerOutlier, model.Close, now)
+	log.GetBaseLogger().Infof("[CircuitBreaker][Outlier] uneject %s", host.res.String())
+	return append(transitions, statusTransition{res: host.res, previous: previous, current: host.cbStatus})
+}
+
+func (s *serviceDetector) ejectionTime(host *hostStat) time.Duration {
+	ejectionTime := s.cfg.BaseEjectionTime * time.Duration(host.ejectCount)
+	if ejectionTime > s.cfg.MaxEjectionTime {
+		return s.cfg.MaxEjectionTime
+	}
+	return ejectionTime
+}
+
+// is5xx 返回码为5xx，或者调用超时，均视为服务端错误；未设置返回码时以调用状态为准
+func is5xx(stat *model.ResourceStat) bool {
+	if stat.RetStatus == model.RetTimeout {
+		return true
+	}
+	code, err := strconv.Atoi(stat.RetCode)
+	if err != nil || code == 0 {
+		return stat.RetStatus == model.RetFail
+	}
+	return code >= 500 && code < 600
+}
@@ -0,0 +1,186 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package outlier
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+var testSvcKey = model.ServiceKey{Namespace: "Test", Service: "svc"}
+
+// recorder 记录检测器通知的状态变更
+type recorder struct {
+	transitions []statusTransition
+}
+
+func (r *recorder) onUpdate(res *model.InstanceResource, previous, current model.CircuitBreakerStatus) {
+	r.transitions = append(r.transitions, statusTransition{res: res, previous: previous, current: current})
+}
+
+func newTestConfig() *Config {
+	cfg := &Config{}
+	cfg.SetDefault()
+	return cfg
+}
+
+func newTestResource(t *testing.T, idx int) *model.InstanceResource {
+	res, err := model.NewInstanceResource(&testSvcKey, nil, "http", fmt.Sprintf("127.0.0.%d", idx+1), 8080)
+	assert.Nil(t, err)
+	return res
+}
+
+func reportTimes(d *serviceDetector, res *model.InstanceResource, code string, times int, now time.Time) {
+	for i := 0; i < times; i++ {
+		d.report(res, &model.ResourceStat{Resource: res, RetCode: code, Delay: time.Millisecond}, now)
+	}
+}
+
+func isEjected(d *serviceDetector, res *model.InstanceResource) bool {
+	status := d.status(res.GetNode())
+	return status != nil && status.GetStatus() == model.Open
+}
+
+// TestConsecutive5xxEjection 测试连续5xx达到阈值后剔除实例，中间出现成功时重新计数
+func TestConsecutive5xxEjection(t *testing.T) {
+	rec := &recorder{}
+	d := newServiceDetector(testSvcKey, newTestConfig(), rec.onUpdate)
+	res := newTestResource(t, 0)
+	now := time.Now()
+
+	reportTimes(d, res, "500", DefaultConsecutive5xx-1, now)
+	reportTimes(d, res, "200", 1, now)
+	reportTimes(d, res, "503", DefaultConsecutive5xx-1, now)
+	assert.False(t, isEjected(d, res))
+	assert.Empty(t, rec.transitions)
+
+	reportTimes(d, res, "503", 1, now)
+	assert.True(t, isEjected(d, res))
+	assert.Equal(t, 1, len(rec.transitions))
+	assert.Nil(t, rec.transitions[0].previous)
+	assert.Equal(t, model.Open, rec.transitions[0].current.GetStatus())
+
+	// 已剔除的实例不会重复剔除
+	reportTimes(d, res, "500", DefaultConsecutive5xx, now)
+	assert.Equal(t, 1, len(rec.transitions))
+}
+
+// TestMaxEjectionPercent 测试剔除比例上限，无论比例如何至少允许剔除一个实例
+func TestMaxEjectionPercent(t *testing.T) {
+	tests := []struct {
+		name       string
+		percent    int
+		hostCount  int
+		failHosts  int
+		wantEjects int
+	}{
+		{name: "比例不足一个实例时至少剔除一个", percent: 10, hostCount: 5, failHosts: 3, wantEjects: 1},
+		{name: "达到比例上限后不再剔除", percent: 20, hostCount: 10, failHosts: 4, wantEjects: 2},
+		{name: "全部允许剔除", percent: 100, hostCount: 3, failHosts: 3, wantEjects: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.MaxEjectionPercent = tt.percent
+			rec := &recorder{}
+			d := newServiceDetector(testSvcKey, cfg, rec.onUpdate)
+			now := time.Now()
+			resources := make([]*model.InstanceResource, 0, tt.hostCount)
+			for i := 0; i < tt.hostCount; i++ {
+				res := newTestResource(t, i)
+				resources = append(resources, res)
+				reportTimes(d, res, "200", 1, now)
+			}
+			for i := 0; i < tt.failHosts; i++ {
+				reportTimes(d, resources[i], "500", cfg.Consecutive5xx, now)
+			}
+			var ejected int
+			for _, res := range resources {
+				if isEjected(d, res) {
+					ejected++
+				}
+			}
+			assert.Equal(t, tt.wantEjects, ejected)
+			assert.Equal(t, tt.wantEjects, len(rec.transitions))
+		})
+	}
+}
+
+// TestEjectionBackoff 测试再次剔除时剔除时长按剔除次数递增，且不超过最大剔除时长
+func TestEjectionBackoff(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.BaseEjectionTime = 30 * time.Second
+	cfg.MaxEjectionTime = 75 * time.Second
+	rec := &recorder{}
+	d := newServiceDetector(testSvcKey, cfg, rec.onUpdate)
+	res := newTestResource(t, 0)
+	now := time.Now()
+
+	expectEjections := []time.Duration{30 * time.Second, 60 * time.Second, 75 * time.Second}
+	for i, ejection := range expectEjections {
+		reportTimes(d, res, "500", cfg.Consecutive5xx, now)
+		assert.True(t, isEjected(d, res), "ejection %d", i)
+		d.detect(now.Add(ejection - time.Second))
+		assert.True(t, isEjected(d, res), "ejection %d should last %v", i, ejection)
+		now = now.Add(ejection)
+		d.detect(now)
+		assert.False(t, isEjected(d, res), "ejection %d should end after %v", i, ejection)
+	}
+	assert.Equal(t, 2*len(expectEjections), len(rec.transitions))
+	last := rec.transitions[len(rec.transitions)-1]
+	assert.Equal(t, model.Open, last.previous.GetStatus())
+	assert.Equal(t, model.Close, last.current.GetStatus())
+}
+
+// TestNotifyOutsideLock 测试状态变更回调在释放锁后执行，回调中可以重入检测器
+func TestNotifyOutsideLock(t *testing.T) {
+	res := newTestResource(t, 0)
+	var d *serviceDetector
+	var observed []model.CircuitBreakerStatus
+	d = newServiceDetector(testSvcKey, newTestConfig(),
+		func(res *model.InstanceResource, previous, current model.CircuitBreakerStatus) {
+			observed = append(observed, d.status(res.GetNode()))
+		})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		now := time.Now()
+		reportTimes(d, res, "500", DefaultConsecutive5xx, now)
+		d.detect(now.Add(DefaultBaseEjectionTime))
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("detector deadlocked when listener re-entered it")
+	}
+	assert.Equal(t, 2, len(observed))
+	assert.Equal(t, model.Open, observed[0].GetStatus())
+	assert.Equal(t, model.Close, observed[1].GetStatus())
+}
@@ -0,0 +1,176 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package outlier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+// OutlierCircuitBreaker 离群检测熔断器，参考envoy的outlier detection，
+// 基于连续5xx错误以及实例P99时延相对服务平均时延的偏离程度剔除实例
+type OutlierCircuitBreaker struct {
+	*plugin.PluginBase
+	cfg        *Config
+	pluginCtx  *plugin.InitContext
+	localCache localregistry.LocalRegistry
+	// detectors model.ServiceKey -> *serviceDetector
+	detectors *sync.Map
+	cancel    context.CancelFunc
+	// listeners 熔断状态监听器
+	listenerLock sync.RWMutex
+	listeners    []model.CircuitBreakerStatusListener
+}
+
+// Type 插件类型
+func (o *OutlierCircuitBreaker) Type() common.Type {
+	return common.TypeCircuitBreaker
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (o *OutlierCircuitBreaker) Name() string {
+	return config.DefaultCircuitBreakerOutlier
+}
+
+// Init 初始化插件
+func (o *OutlierCircuitBreaker) Init(ctx *plugin.InitContext) error {
+	o.PluginBase = plugin.NewPluginBase(ctx)
+	o.pluginCtx = ctx
+	o.cfg = ctx.Config.GetConsumer().GetCircuitBreaker().GetPluginConfig(o.Name()).(*Config)
+	o.detectors = &sync.Map{}
+	return nil
+}
+
+// Start 启动插件，获取本地缓存并开启周期性的离群检测任务
+func (o *OutlierCircuitBreaker) Start() error {
+	registryPlugin, err := o.pluginCtx.Plugins.GetPlugin(common.TypeLocalRegistry,
+		o.pluginCtx.Config.GetConsumer().GetLocalCache().GetType())
+	if err != nil {
+		return err
+	}
+	o.localCache = registryPlugin.(localregistry.LocalRegistry)
+	var ctx context.Context
+	ctx, o.cancel = context.WithCancel(context.Background())
+	go o.runDetect(ctx)
+	return nil
+}
+
+// Destroy 销毁插件
+func (o *OutlierCircuitBreaker) Destroy() error {
+	if o.cancel != nil {
+		o.cancel()
+	}
+	return nil
+}
+
+// CheckResource 获取实例的离群检测状态，非实例级资源不做处理
+func (o *OutlierCircuitBreaker) CheckResource(res model.Resource) model.CircuitBreakerStatus {
+	insRes, ok := res.(*model.InstanceResource)
+	if !ok {
+		return nil
+	}
+	value, ok := o.detectors.Load(*insRes.GetService())
+	if !ok {
+		return nil
+	}
+	return value.(*serviceDetector).status(insRes.GetNode())
+}
+
+// Report 上报实例调用结果
+func (o *OutlierCircuitBreaker) Report(stat *model.ResourceStat) error {
+	insRes, ok := stat.Resource.(*model.InstanceResource)
+	if !ok || insRes.GetLevel() != fault_tolerance.Level_INSTANCE {
+		return nil
+	}
+	// 因为限流、熔断被拒绝的请求，不需要进入离群检测
+	if stat.RetStatus == model.RetReject || stat.RetStatus == model.RetFlowControl {
+		return nil
+	}
+	svcKey := *insRes.GetService()
+	value, ok := o.detectors.Load(svcKey)
+	if !ok {
+		value, _ = o.detectors.LoadOrStore(svcKey, newServiceDetector(svcKey, o.cfg, o.updateInstanceStatus))
+	}
+	value.(*serviceDetector).report(insRes, stat, time.Now())
+	return nil
+}
+
+func (o *OutlierCircuitBreaker) runDetect(ctx context.Context) {
+	ticker := time.NewTicker(o.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			o.detectors.Range(func(key, value interface{}) bool {
+				if value.(*serviceDetector).detect(now) {
+					o.detectors.Delete(key)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// AddStatusListener 添加熔断状态监听器
+func (o *OutlierCircuitBreaker) AddStatusListener(listener model.CircuitBreakerStatusListener) {
+	o.listenerLock.Lock()
+	defer o.listenerLock.Unlock()
+	o.listeners = append(o.listeners, listener)
+}
+
+// updateInstanceStatus 将剔除/恢复结果写入本地缓存，使负载均衡感知实例状态，并通知状态监听器
+func (o *OutlierCircuitBreaker) updateInstanceStatus(res *model.InstanceResource,
+	previous, status model.CircuitBreakerStatus) {
+	o.listenerLock.RLock()
+	for _, listener := range o.listeners {
+		listener.OnStatusChange(res, previous, status)
+	}
+	o.listenerLock.RUnlock()
+	updateRequest := &localregistry.ServiceUpdateRequest{
+		ServiceKey: *res.GetService(),
+		Properties: []localregistry.InstanceProperties{
+			{
+				Host:       res.GetNode().Host,
+				Port:       res.GetNode().Port,
+				Service:    res.GetService(),
+				Properties: map[string]interface{}{localregistry.PropertyCircuitBreakerStatus: status},
+			},
+		},
+	}
+	if err := o.localCache.UpdateInstances(updateRequest); err != nil {
+		log.GetBaseLogger().Errorf("[CircuitBreaker][Outlier] update instance status fail, resource %s, err %v",
+			res.String(), err)
+	}
+}
+
+func init() {
+	plugin.RegisterConfigurablePlugin(&OutlierCircuitBreaker{}, &Config{})
+}
@@ -0,0 +1,58 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package nacos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/plugin/serverconnector/nacos"
+)
+
+const (
+	// DefaultLongPollingTimeout 默认的长轮询超时时间
+	DefaultLongPollingTimeout = 30 * time.Second
+)
+
+// Config Nacos配置中心连接器的插件配置
+type Config struct {
+	nacos.ClientConfig `yaml:",inline"`
+	// LongPollingTimeout 监听配置变更的长轮询超时时间
+	LongPollingTimeout time.Duration `yaml:"longPollingTimeout" json:"longPollingTimeout"`
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	var errs error
+	if err := c.ClientConfig.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if c.LongPollingTimeout < time.Second {
+		errs = multierror.Append(errs, fmt.Errorf("nacos.longPollingTimeout must be greater than 1s"))
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	c.ClientConfig.SetDefault()
+	if c.LongPollingTimeout == 0 {
+		c.LongPollingTimeout = DefaultLongPollingTimeout
+	}
+}
@@ -0,0 +1,351 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package nacos
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/plugin/serverconnector/nacos"
+)
+
+const (
+	// protocolNacos 插件名，config.configConnector.protocol配置为nacos时启用
+	protocolNacos = "nacos"
+	// 长轮询报文中的分隔符
+	wordSeparator = "\x02"
+	lineSeparator = "\x01"
+	// headerLastModified 配置最后修改时间（毫秒），作为配置版本号
+	headerLastModified = "Last-Modified"
+	// headerContentMD5 配置内容的md5
+	headerContentMD5 = "Content-MD5"
+	// emptyWatchInterval 没有监听的配置时，两次轮询的间隔
+	emptyWatchInterval = time.Second
+	// groupPageSize 查询配置分组的分页大小
+	groupPageSize = 500
+)
+
+// configListResponse /v1/cs/configs 分组查询应答
+type configListResponse struct {
+	TotalCount int `json:"totalCount"`
+	PageItems  []struct {
+		DataID string `json:"dataId"`
+		Group  string `json:"group"`
+		Md5    string `json:"md5"`
+	} `json:"pageItems"`
+}
+
+// Connector 通过Nacos Open API对接Nacos配置中心，北极星命名空间对应Nacos命名空间（default对应public），
+// 配置分组对应Nacos的group，配置文件名对应Nacos的dataId。Nacos没有草稿的概念，
+// 创建及更新配置后立即生效，发布操作直接返回成功
+type Connector struct {
+	*plugin.PluginBase
+	cfg            *Config
+	client         *nacos.Client
+	messageTimeout time.Duration
+	// 已拉取配置的md5，监听时用于服务端比较
+	md5s sync.Map
+}
+
+// Type 插件类型
+func (c *Connector) Type() common.Type {
+	return common.TypeConfigConnector
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (c *Connector) Name() string {
+	return protocolNacos
+}
+
+// Init 初始化插件
+func (c *Connector) Init(ctx *plugin.InitContext) error {
+	c.PluginBase = plugin.NewPluginBase(ctx)
+	connectorCfg := ctx.Config.GetConfigFile().GetConfigConnectorConfig()
+	if connectorCfg.GetProtocol() != c.Name() {
+		return nil
+	}
+	c.cfg = connectorCfg.GetPluginConfig(c.Name()).(*Config)
+	c.messageTimeout = connectorCfg.GetMessageTimeout()
+	c.client = nacos.NewClient(&c.cfg.ClientConfig, connectorCfg.GetAddresses(), c.messageTimeout)
+	return nil
+}
+
+// IsEnable 插件开关
+func (c *Connector) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// GetConfigFile 获取配置文件，配置的最后修改时间作为版本号
+func (c *Connector) GetConfigFile(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	if err := c.checkEnable(); err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(&nacos.Request{
+		Method: http.MethodGet,
+		Path:   "/v1/cs/configs",
+		Params: configParams(configFile.Namespace, configFile.FileGroup, configFile.FileName),
+	}, nil)
+	if err != nil {
+		return nil, networkError(err, "get config file %s", configFile)
+	}
+	key := cacheKey(configFile.Namespace, configFile.FileGroup, configFile.FileName)
+	if !resp.Found {
+		c.md5s.Delete(key)
+		return &configconnector.ConfigFileResponse{
+			Code:    uint32(apimodel.Code_NotFoundResource),
+			Message: "config file not found",
+			ConfigFile: &configconnector.ConfigFile{
+				Namespace: configFile.Namespace,
+				FileGroup: configFile.FileGroup,
+				FileName:  configFile.FileName,
+				NotExist:  true,
+			},
+		}, nil
+	}
+	content := string(resp.Body)
+	contentMd5 := resp.Header.Get(headerContentMD5)
+	if contentMd5 == "" {
+		sum := md5.Sum(resp.Body)
+		contentMd5 = hex.EncodeToString(sum[:])
+	}
+	version, _ := strconv.ParseUint(resp.Header.Get(headerLastModified), 10, 64)
+	if version == 0 {
+		version = uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	}
+	c.md5s.Store(key, contentMd5)
+	return &configconnector.ConfigFileResponse{
+		Code: uint32(apimodel.Code_ExecuteSuccess),
+		ConfigFile: &configconnector.ConfigFile{
+			Namespace:     configFile.Namespace,
+			FileGroup:     configFile.FileGroup,
+			FileName:      configFile.FileName,
+			SourceContent: content,
+			Version:       version,
+			Md5:           contentMd5,
+		},
+	}, nil
+}
+
+// WatchConfigFiles 通过Nacos长轮询监听配置变更，有变更时拉取第一个变更的配置并返回其版本号
+func (c *Connector) WatchConfigFiles(configFileList []*configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	if err := c.checkEnable(); err != nil {
+		return nil, err
+	}
+	if len(configFileList) == 0 {
+		time.Sleep(emptyWatchInterval)
+		return &configconnector.ConfigFileResponse{Code: uint32(apimodel.Code_DataNoChange)}, nil
+	}
+	var listening strings.Builder
+	for _, file := range configFileList {
+		contentMd5 := ""
+		if value, ok := c.md5s.Load(cacheKey(file.Namespace, file.FileGroup, file.FileName)); ok {
+			contentMd5 = value.(string)
+		}
+		listening.WriteString(file.FileName + wordSeparator + file.FileGroup + wordSeparator + contentMd5)
+		if tenant := nacos.ToNamespaceID(file.Namespace); tenant != "" {
+			listening.WriteString(wordSeparator + tenant)
+		}
+		listening.WriteString(lineSeparator)
+	}
+	params := url.Values{}
+	params.Set("Listening-Configs", listening.String())
+	header := http.Header{}
+	header.Set("Long-Pulling-Timeout", strconv.FormatInt(int64(c.cfg.LongPollingTimeout/time.Millisecond), 10))
+	resp, err := c.client.Do(&nacos.Request{
+		Method:  http.MethodPost,
+		Path:    "/v1/cs/configs/listener",
+		Params:  params,
+		Header:  header,
+		Timeout: c.cfg.LongPollingTimeout + c.messageTimeout,
+	}, nil)
+	if err != nil {
+		return nil, networkError(err, "watch %d config files", len(configFileList))
+	}
+	changed, err := url.QueryUnescape(strings.TrimSpace(string(resp.Body)))
+	if err != nil || changed == "" {
+		return &configconnector.ConfigFileResponse{Code: uint32(apimodel.Code_DataNoChange)}, nil
+	}
+	for _, file := range configFileList {
+		if !isChanged(changed, file) {
+			continue
+		}
+		fileResp, err := c.GetConfigFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if fileResp.Code == uint32(apimodel.Code_NotFoundResource) {
+			// 配置被删除，通过一个更大的版本号触发重新拉取
+			fileResp.ConfigFile.Version = file.Version + 1
+		}
+		fileResp.Code = uint32(apimodel.Code_ExecuteSuccess)
+		return fileResp, nil
+	}
+	return &configconnector.ConfigFileResponse{Code: uint32(apimodel.Code_DataNoChange)}, nil
+}
+
+// CreateConfigFile 创建配置文件，创建后立即生效
+func (c *Connector) CreateConfigFile(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	return c.publish(configFile)
+}
+
+// UpdateConfigFile 更新配置文件，更新后立即生效
+func (c *Connector) UpdateConfigFile(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	return c.publish(configFile)
+}
+
+// PublishConfigFile Nacos的配置在创建及更新时已经生效，直接返回成功
+func (c *Connector) PublishConfigFile(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	if err := c.checkEnable(); err != nil {
+		return nil, err
+	}
+	return &configconnector.ConfigFileResponse{
+		Code:       uint32(apimodel.Code_ExecuteSuccess),
+		ConfigFile: configFile,
+	}, nil
+}
+
+// GetConfigGroup 查询分组下的所有配置，版本号为各配置md5的摘要
+func (c *Connector) GetConfigGroup(req *configconnector.ConfigGroup) (*configconnector.ConfigGroupResponse, error) {
+	if err := c.checkEnable(); err != nil {
+		return nil, err
+	}
+	groupResp := &configconnector.ConfigGroupResponse{
+		Code:      uint32(apimodel.Code_ExecuteSuccess),
+		Namespace: req.Namespace,
+		Group:     req.Group,
+	}
+	for pageNo := 1; ; pageNo++ {
+		params := configParams(req.Namespace, req.Group, "")
+		params.Set("search", "accurate")
+		params.Set("pageNo", strconv.Itoa(pageNo))
+		params.Set("pageSize", strconv.Itoa(groupPageSize))
+		listResp := &configListResponse{}
+		if _, err := c.client.Do(&nacos.Request{
+			Method: http.MethodGet,
+			Path:   "/v1/cs/configs",
+			Params: params,
+		}, listResp); err != nil {
+			return nil, networkError(err, "get config group %s/%s", req.Namespace, req.Group)
+		}
+		for _, item := range listResp.PageItems {
+			groupResp.ReleaseFiles = append(groupResp.ReleaseFiles, &model.SimpleConfigFile{
+				Namespace: req.Namespace,
+				FileGroup: item.Group,
+				FileName:  item.DataID,
+				Md5:       item.Md5,
+			})
+		}
+		if len(listResp.PageItems) < groupPageSize || len(groupResp.ReleaseFiles) >= listResp.TotalCount {
+			break
+		}
+	}
+	if len(groupResp.ReleaseFiles) == 0 {
+		groupResp.Code = uint32(apimodel.Code_NotFoundResource)
+		return groupResp, nil
+	}
+	sort.Slice(groupResp.ReleaseFiles, func(i, j int) bool {
+		return groupResp.ReleaseFiles[i].FileName < groupResp.ReleaseFiles[j].FileName
+	})
+	h := md5.New()
+	for _, file := range groupResp.ReleaseFiles {
+		_, _ = h.Write([]byte(file.FileName + wordSeparator + file.Md5 + lineSeparator))
+	}
+	groupResp.Revision = hex.EncodeToString(h.Sum(nil))
+	return groupResp, nil
+}
+
+func (c *Connector) publish(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	if err := c.checkEnable(); err != nil {
+		return nil, err
+	}
+	params := configParams(configFile.Namespace, configFile.FileGroup, configFile.FileName)
+	params.Set("content", configFile.SourceContent)
+	if _, err := c.client.Do(&nacos.Request{
+		Method: http.MethodPost,
+		Path:   "/v1/cs/configs",
+		Params: params,
+	}, nil); err != nil {
+		return nil, networkError(err, "publish config file %s", configFile)
+	}
+	return &configconnector.ConfigFileResponse{
+		Code:       uint32(apimodel.Code_ExecuteSuccess),
+		ConfigFile: configFile,
+	}, nil
+}
+
+func (c *Connector) checkEnable() error {
+	if c.client == nil {
+		return model.NewSDKError(model.ErrCodePluginError, nil,
+			"%s config connector is not enabled", c.Name())
+	}
+	return nil
+}
+
+func configParams(namespace, group, dataID string) url.Values {
+	params := url.Values{}
+	params.Set("dataId", dataID)
+	params.Set("group", group)
+	if tenant := nacos.ToNamespaceID(namespace); tenant != "" {
+		params.Set("tenant", tenant)
+	}
+	return params
+}
+
+// isChanged 长轮询应答格式为 dataId^2group[^2tenant]^1
+func isChanged(changed string, file *configconnector.ConfigFile) bool {
+	for _, line := range strings.Split(changed, lineSeparator) {
+		words := strings.Split(line, wordSeparator)
+		if len(words) < 2 || words[0] != file.FileName || words[1] != file.FileGroup {
+			continue
+		}
+		tenant := ""
+		if len(words) > 2 {
+			tenant = words[2]
+		}
+		if tenant == nacos.ToNamespaceID(file.Namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+func cacheKey(namespace, group, dataID string) string {
+	return namespace + wordSeparator + group + wordSeparator + dataID
+}
+
+func networkError(err error, format string, args ...interface{}) error {
+	return model.NewSDKError(model.ErrCodeNetworkError, err, "[nacos] fail to "+format, args...)
+}
+
+// init 注册插件信息
+func init() {
+	plugin.RegisterConfigurablePlugin(&Connector{}, &Config{})
+}
@@ -20,6 +20,7 @@ package polaris
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"sync/atomic"
 	"time"
@@ -38,6 +39,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/pkg/plugin/identity"
 	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
 )
 
@@ -57,7 +59,11 @@ type Connector struct {
 	valueCtx              model.ValueContext
 	// 有没有打印过connManager ready的信息，用于避免重复打印
 	hasPrintedReady uint32
-	token           string
+	tokenProvider   *network.TokenProvider
+	// 与server通信的TLS配置，未启用时为空
+	tlsConfig *tls.Config
+	// 是否与进程内其他SDK上下文共享连接
+	shareConnection bool
 }
 
 // Type 插件类型.
@@ -78,7 +84,18 @@ func (c *Connector) Init(ctx *plugin.InitContext) error {
 	if cfgValue != nil {
 		c.cfg = cfgValue.(*networkConfig)
 	}
-	c.token = ctx.Config.GetConfigFile().GetConfigConnectorConfig().GetToken()
+	connectorCfg := ctx.Config.GetConfigFile().GetConfigConnectorConfig()
+	c.tokenProvider = network.NewTokenProvider(connectorCfg)
+	identityProvider, err := identity.GetIdentityProvider(ctx.Config, ctx.Plugins)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := network.BuildTLSConfig(connectorCfg.GetTLS(), identityProvider)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to build tls config for configConnector")
+	}
+	c.tlsConfig = tlsConfig
+	c.shareConnection = connectorCfg.IsShareConnection()
 	connManager, err := network.NewConfigConnectionManager(ctx.Config, ctx.ValueCtx)
 	if err != nil {
 		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to create config connectionManager")
@@ -123,7 +140,7 @@ func (c *Connector) GetConfigFile(configFile *configconnector.ConfigFile) (*conf
 	defer conn.Release(opKey)
 	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
 	reqID := connector.NextRegisterInstanceReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
+	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.tokenProvider.GetToken()),
 		connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
@@ -156,7 +173,7 @@ func (c *Connector) WatchConfigFiles(configFileList []*configconnector.ConfigFil
 	defer conn.Release(opKey)
 	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
 	reqID := connector.NextWatchConfigFilesReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
+	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.tokenProvider.GetToken()),
 		connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
@@ -194,7 +211,7 @@ func (c *Connector) CreateConfigFile(configFile *configconnector.ConfigFile) (*c
 	defer conn.Release(opKey)
 	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
 	reqID := connector.NextCreateConfigFileReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
+	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.tokenProvider.GetToken()),
 		connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
@@ -227,7 +244,7 @@ func (c *Connector) UpdateConfigFile(configFile *configconnector.ConfigFile) (*c
 	defer conn.Release(opKey)
 	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
 	reqID := connector.NextUpdateConfigFileReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
+	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.tokenProvider.GetToken()),
 		connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
@@ -260,7 +277,7 @@ func (c *Connector) PublishConfigFile(configFile *configconnector.ConfigFile) (*
 	defer conn.Release(opKey)
 	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
 	reqID := connector.NextPublishConfigFileReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
+	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.tokenProvider.GetToken()),
 		connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
@@ -290,7 +307,7 @@ func (c *Connector) GetConfigGroup(req *configconnector.ConfigGroup) (*configcon
 	}
 
 	reqID := connector.NextPublishConfigFileReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
+	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.tokenProvider.GetToken()),
 		connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
@@ -442,15 +459,23 @@ func transferToConfigFile(configFile *configconnector.ConfigFile) *config_manage
 		Group:     wrapperspb.String(configFile.GetFileGroup()),
 		Name:      wrapperspb.String(configFile.GetFileName()),
 		Content:   wrapperspb.String(configFile.GetContent()),
+		Comment:   wrapperspb.String(configFile.Comment),
 	}
 }
 
 func transferToConfigFileRelease(configFile *configconnector.ConfigFile) *config_manage.ConfigFileRelease {
-	return &config_manage.ConfigFileRelease{
+	release := &config_manage.ConfigFileRelease{
 		Namespace: wrapperspb.String(configFile.GetNamespace()),
 		Group:     wrapperspb.String(configFile.GetFileGroup()),
 		FileName:  wrapperspb.String(configFile.GetFileName()),
 	}
+	if configFile.ReleaseName != "" {
+		release.Name = wrapperspb.String(configFile.ReleaseName)
+	}
+	if configFile.ReleaseDescription != "" {
+		release.Comment = wrapperspb.String(configFile.ReleaseDescription)
+	}
+	return release
 }
 
 // init 注册插件信息.
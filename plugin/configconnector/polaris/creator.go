@@ -19,11 +19,11 @@ package polaris
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/stats"
 
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -36,7 +36,7 @@ func (c *Connector) CreateConnection(
 	address string, timeout time.Duration, clientInfo *network.ClientInfo,
 ) (network.ClosableConn, error) {
 	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	opts = append(opts, grpc.WithTransportCredentials(network.NewGRPCCredentials(c.tlsConfig)))
 	opts = append(opts, grpc.WithBlock())
 	localIPValue := clientInfo.GetIPString()
 	if len(localIPValue) == 0 {
@@ -44,9 +44,17 @@ func (c *Connector) CreateConnection(
 	}
 	log.GetBaseLogger().Debugf("create connection with maxCallRecvSize %d", c.cfg.MaxCallRecvMsgSize)
 	opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(c.cfg.MaxCallRecvMsgSize)))
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-	conn, err := grpc.DialContext(ctx, address, opts...)
+	dial := func() (*grpc.ClientConn, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return grpc.DialContext(ctx, address, opts...)
+	}
+	// 鉴权凭据按请求携带，未启用TLS且已获取本机IP时，连接与进程内其他SDK上下文共享
+	if c.shareConnection && c.tlsConfig == nil && len(localIPValue) > 0 {
+		key := fmt.Sprintf("%s|%s|%d", c.Name(), address, c.cfg.MaxCallRecvMsgSize)
+		return network.AcquireSharedConn(key, dial)
+	}
+	conn, err := dial()
 	if err != nil {
 		return nil, err
 	}
@@ -28,6 +28,8 @@ import (
 // Config crypto filter config
 type Config struct {
 	Entries []ConfigEntry `yaml:"entries"`
+	// KeyProviders 客户端密钥模式下按顺序查询的密钥提供者
+	KeyProviders []string `yaml:"keyProviders"`
 }
 
 // ConfigEntry config entry
@@ -53,6 +55,11 @@ func (c *Config) SetDefault() {
 	if len(c.Entries) == 0 {
 		c.Entries = append(c.Entries, ConfigEntry{
 			Name: "AES",
+		}, ConfigEntry{
+			Name: "SM4",
 		})
 	}
+	if len(c.KeyProviders) == 0 {
+		c.KeyProviders = []string{EnvKeyProviderName}
+	}
 }
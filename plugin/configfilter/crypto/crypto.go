@@ -45,8 +45,9 @@ func init() {
 // CryptoFilter crypto filter plugin
 type CryptoFilter struct {
 	*plugin.PluginBase
-	cfg     *Config
-	cryptos map[string]Crypto
+	cfg          *Config
+	cryptos      map[string]Crypto
+	keyProviders []KeyProvider
 }
 
 // Type plugin type
@@ -82,6 +83,14 @@ func (c *CryptoFilter) Init(ctx *plugin.InitContext) error {
 		}
 		c.cryptos[entry.Name] = crypto
 	}
+	for _, name := range c.cfg.KeyProviders {
+		provider, exist := keyProviderSet[name]
+		if !exist {
+			log.GetBaseLogger().Errorf("plugin Crypto not found key provider: %s", name)
+			continue
+		}
+		c.keyProviders = append(c.keyProviders, provider)
+	}
 	return nil
 }
 
@@ -116,6 +125,13 @@ func (c *CryptoFilter) DoFilter(configFile *configconnector.ConfigFile, next con
 		if err != nil {
 			return resp, err
 		}
+		if resp.GetConfigFile() == nil {
+			return resp, err
+		}
+		// 客户端密钥模式，密钥不经过服务端
+		if keyID, ok := resp.GetConfigFile().GetEncryptKeyID(); ok {
+			return c.decryptWithClientKey(resp, keyID)
+		}
 		// 如果是加密配置
 		if !resp.GetConfigFile().GetEncrypted() {
 			// 删除掉之前保存的 token cache
@@ -157,6 +173,43 @@ func (c *CryptoFilter) DoFilter(configFile *configconnector.ConfigFile, next con
 	}
 }
 
+// decryptWithClientKey 使用密钥提供者中的密钥解密配置，算法由文件标签指定
+func (c *CryptoFilter) decryptWithClientKey(resp *configconnector.ConfigFileResponse,
+	keyID string) (*configconnector.ConfigFileResponse, error) {
+	configFile := resp.GetConfigFile()
+	crypto, err := c.GetCrypto(configFile.GetEncryptAlgo())
+	if err != nil {
+		return nil, err
+	}
+	key, err := c.getClientKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	plainContent, err := crypto.Decrypt(configFile.GetSourceContent(), key)
+	if err != nil {
+		log.GetBaseLogger().Errorf("config file %s/%s/%s decrypt with client key %s fail: %s",
+			configFile.Namespace, configFile.FileGroup, configFile.FileName, keyID, err)
+		return nil, err
+	}
+	configFile.SetContent(plainContent)
+	return resp, nil
+}
+
+// getClientKey 按配置顺序从密钥提供者获取密钥
+func (c *CryptoFilter) getClientKey(keyID string) ([]byte, error) {
+	for _, provider := range c.keyProviders {
+		key, err := provider.GetKey(keyID)
+		if err != nil {
+			log.GetBaseLogger().Warnf("key provider %s get key %s fail: %s", provider.Name(), keyID, err)
+			continue
+		}
+		if len(key) > 0 {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("config crypto key %s not found in key providers %v", keyID, c.cfg.KeyProviders)
+}
+
 // GetCrypto get crypto by algorithm
 func (c *CryptoFilter) GetCrypto(algo string) (Crypto, error) {
 	crypto, ok := c.cryptos[algo]
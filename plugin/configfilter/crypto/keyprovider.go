@@ -0,0 +1,89 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package crypto
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// EnvKeyProviderName 环境变量密钥提供者
+	EnvKeyProviderName = "env"
+	// EnvKeyPrefix 环境变量密钥前缀，密钥 ID 转为大写并将非字母数字字符替换为"_"后拼接
+	EnvKeyPrefix = "POLARIS_CONFIG_CRYPTO_KEY"
+)
+
+func init() {
+	RegisterKeyProvider(&envKeyProvider{})
+}
+
+// KeyProvider 客户端密钥提供者，根据配置文件标签中的密钥 ID 获取解密密钥
+// 接入 KMS 时实现该接口并通过 RegisterKeyProvider 注册，再在 keyProviders 中配置名称
+type KeyProvider interface {
+	// Name 密钥提供者名称
+	Name() string
+	// GetKey 根据密钥 ID 获取密钥，密钥不存在时返回空
+	GetKey(keyID string) ([]byte, error)
+}
+
+var keyProviderSet = make(map[string]KeyProvider)
+
+// RegisterKeyProvider register key provider
+func RegisterKeyProvider(provider KeyProvider) {
+	if _, exist := keyProviderSet[provider.Name()]; exist {
+		panic(fmt.Sprintf("existed key provider: name=%v", provider.Name()))
+	}
+	keyProviderSet[provider.Name()] = provider
+}
+
+// envKeyProvider 从环境变量读取 base64 编码的密钥
+type envKeyProvider struct {
+}
+
+// Name key provider name
+func (p *envKeyProvider) Name() string {
+	return EnvKeyProviderName
+}
+
+// GetKey get key from env
+func (p *envKeyProvider) GetKey(keyID string) ([]byte, error) {
+	value, ok := os.LookupEnv(envKeyName(keyID))
+	if !ok || value == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("env %s is not base64 encoded: %w", envKeyName(keyID), err)
+	}
+	return key, nil
+}
+
+func envKeyName(keyID string) string {
+	if keyID == "" {
+		return EnvKeyPrefix
+	}
+	return EnvKeyPrefix + "_" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(keyID))
+}
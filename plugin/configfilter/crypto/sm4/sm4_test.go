@@ -0,0 +1,82 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sm4
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sm4Block_StandardVector(t *testing.T) {
+	// GB/T 32907-2016 附录A 示例1
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	block, err := NewCipher(key)
+	assert.Nil(t, err)
+	ciphertext := make([]byte, BlockSize)
+	block.Encrypt(ciphertext, key)
+	assert.Equal(t, "681edf34d206965e86b3e94f536e4246", hex.EncodeToString(ciphertext))
+	plaintext := make([]byte, BlockSize)
+	block.Decrypt(plaintext, ciphertext)
+	assert.Equal(t, key, plaintext)
+}
+
+func Test_sm4Cryptor_Encrypt(t *testing.T) {
+	type args struct {
+		plaintext string
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "encrypt",
+			args: args{
+				plaintext: "1234abcd!@#$",
+			},
+		},
+		{
+			name: "encrypt full block",
+			args: args{
+				plaintext: "0123456789abcdef",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &sm4Cryptor{}
+			key, err := c.GenerateKey()
+			assert.Nil(t, err)
+			ciphertext, err := c.Encrypt(tt.args.plaintext, key)
+			assert.Nil(t, err)
+			plaintext, err := c.Decrypt(ciphertext, key)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.args.plaintext, plaintext)
+		})
+	}
+}
+
+func Test_sm4Cryptor_DecryptCompatible(t *testing.T) {
+	// 与 openssl enc -sm4-cbc 的结果一致，IV 与密钥相同
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	c := &sm4Cryptor{}
+	plaintext, err := c.Decrypt("rrVT9kllvAFhrnvHaZ1ahISunr5ZODUFCLkYRHFdbfk=", key)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello world config", plaintext)
+}
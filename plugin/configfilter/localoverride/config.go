@@ -0,0 +1,51 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package localoverride
+
+import (
+	"errors"
+)
+
+const (
+	// DefaultOverrideDir 默认的本地覆盖目录
+	DefaultOverrideDir = "./polaris/config/override"
+)
+
+// Config 本地覆盖插件配置
+type Config struct {
+	// Dir 本地覆盖目录，文件路径为 <dir>/<namespace>/<fileGroup>/<fileName>
+	Dir string `yaml:"dir"`
+}
+
+// Verify verify config
+func (c *Config) Verify() error {
+	if nil == c {
+		return errors.New("LocalOverrideConfig is nil")
+	}
+	if c.Dir == "" {
+		return errors.New("config.configFilter.plugin.localOverride.dir is empty")
+	}
+	return nil
+}
+
+// SetDefault set default config
+func (c *Config) SetDefault() {
+	if c.Dir == "" {
+		c.Dir = DefaultOverrideDir
+	}
+}
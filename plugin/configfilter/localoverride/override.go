@@ -0,0 +1,124 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package localoverride
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
+)
+
+const (
+	// PluginName localOverride
+	PluginName = "localOverride"
+)
+
+func init() {
+	plugin.RegisterConfigurablePlugin(&LocalOverrideFilter{}, &Config{})
+}
+
+// LocalOverrideFilter 本地覆盖插件，本地覆盖目录中存在同名文件时优先使用本地文件内容
+// 服务端不可达或者配置不存在时同样返回本地文件，适用于本地开发以及隔离网络环境
+// 需要放在过滤链的第一位，保证覆盖内容不会被其他过滤器修改
+type LocalOverrideFilter struct {
+	*plugin.PluginBase
+	cfg *Config
+}
+
+// Type plugin type
+func (o *LocalOverrideFilter) Type() common.Type {
+	return common.TypeConfigFilter
+}
+
+// Name plugin name
+func (o *LocalOverrideFilter) Name() string {
+	return PluginName
+}
+
+// Init plugin
+func (o *LocalOverrideFilter) Init(ctx *plugin.InitContext) error {
+	o.PluginBase = plugin.NewPluginBase(ctx)
+	o.cfg = &Config{}
+	cfgValue := ctx.Config.GetConfigFile().GetConfigFilterConfig().GetPluginConfig(o.Name())
+	if cfgValue != nil {
+		o.cfg = cfgValue.(*Config)
+	}
+	o.cfg.SetDefault()
+	return nil
+}
+
+// Destroy plugin
+func (o *LocalOverrideFilter) Destroy() error {
+	return nil
+}
+
+// DoFilter 先从服务端获取配置，本地存在覆盖文件时替换配置内容
+func (o *LocalOverrideFilter) DoFilter(configFile *configconnector.ConfigFile,
+	next configfilter.ConfigFileHandleFunc) configfilter.ConfigFileHandleFunc {
+	return func(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+		resp, err := next(configFile)
+		content, ok := o.loadOverride(configFile)
+		if !ok {
+			return resp, err
+		}
+		if err == nil && resp != nil && resp.GetCode() == uint32(apimodel.Code_ExecuteSuccess) &&
+			resp.GetConfigFile() != nil {
+			// 保留服务端的版本信息，便于服务端恢复后继续长轮询
+			resp.GetConfigFile().SetContent(content)
+			return resp, nil
+		}
+		if err != nil {
+			log.GetBaseLogger().Warnf("[Config] pull config file %s fail, use local override. err = %v",
+				configFile.String(), err)
+		}
+		overrideFile := &configconnector.ConfigFile{
+			Namespace:     configFile.Namespace,
+			FileGroup:     configFile.FileGroup,
+			FileName:      configFile.FileName,
+			SourceContent: content,
+			Version:       configFile.Version,
+			Mode:          configFile.Mode,
+		}
+		overrideFile.SetContent(content)
+		return &configconnector.ConfigFileResponse{
+			Code:       uint32(apimodel.Code_ExecuteSuccess),
+			ConfigFile: overrideFile,
+		}, nil
+	}
+}
+
+// loadOverride 读取本地覆盖文件
+func (o *LocalOverrideFilter) loadOverride(configFile *configconnector.ConfigFile) (string, bool) {
+	path := filepath.Join(o.cfg.Dir, configFile.Namespace, configFile.FileGroup, filepath.FromSlash(configFile.FileName))
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.GetBaseLogger().Errorf("[Config] read local override file %s fail: %v", path, err)
+		}
+		return "", false
+	}
+	return string(data), true
+}
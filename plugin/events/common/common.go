@@ -0,0 +1,36 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/config"
+)
+
+// IsReporterEnabled 事件上报开启且插件位于上报插件链中时才启用插件
+func IsReporterEnabled(cfg config.Configuration, name string) bool {
+	eventCfg := cfg.GetGlobal().GetEventReporter()
+	if !eventCfg.IsEnable() {
+		return false
+	}
+	for _, reporter := range eventCfg.GetChain() {
+		if reporter == name {
+			return true
+		}
+	}
+	return false
+}
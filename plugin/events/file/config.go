@@ -0,0 +1,79 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"errors"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+)
+
+func init() {
+	plugin.RegisterConfigurablePlugin(&Reporter{}, &Config{})
+}
+
+const (
+	// DefaultPath 默认的事件文件路径
+	DefaultPath = "./polaris/events/governance.log"
+	// DefaultMaxSize 默认单个事件文件的最大大小，单位MB
+	DefaultMaxSize = 50
+	// DefaultMaxBackups 默认保留的历史事件文件个数
+	DefaultMaxBackups = 5
+)
+
+// Config 文件事件上报插件配置
+type Config struct {
+	// Path 事件文件路径
+	Path string `yaml:"path" json:"path"`
+	// MaxSize 单个事件文件的最大大小，单位MB，超出后滚动
+	MaxSize int `yaml:"maxSize" json:"maxSize"`
+	// MaxBackups 保留的历史事件文件个数
+	MaxBackups int `yaml:"maxBackups" json:"maxBackups"`
+	// MaxAge 历史事件文件的最长保留天数，0表示不按时间清理
+	MaxAge int `yaml:"maxAge" json:"maxAge"`
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	var errs error
+	if c.MaxSize < 0 {
+		errs = multierror.Append(errs, errors.New("eventReporter.file.maxSize must not be negative"))
+	}
+	if c.MaxBackups < 0 {
+		errs = multierror.Append(errs, errors.New("eventReporter.file.maxBackups must not be negative"))
+	}
+	if c.MaxAge < 0 {
+		errs = multierror.Append(errs, errors.New("eventReporter.file.maxAge must not be negative"))
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.Path == "" {
+		c.Path = DefaultPath
+	}
+	if c.MaxSize == 0 {
+		c.MaxSize = DefaultMaxSize
+	}
+	if c.MaxBackups == 0 {
+		c.MaxBackups = DefaultMaxBackups
+	}
+}
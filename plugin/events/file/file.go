@@ -0,0 +1,105 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/natefinch/lumberjack"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/events"
+	eventcommon "github.com/polarismesh/polaris-go/plugin/events/common"
+)
+
+const (
+	// PluginName 插件名称
+	PluginName = "file"
+)
+
+var _ events.EventReporter = (*Reporter)(nil)
+
+// Reporter 将治理事件以单行JSON的形式追加写入滚动文件
+type Reporter struct {
+	*plugin.PluginBase
+	cfg     *Config
+	lock    sync.Mutex
+	writer  *lumberjack.Logger
+	encoder *json.Encoder
+}
+
+// Type 插件类型
+func (r *Reporter) Type() common.Type {
+	return common.TypeEventReporter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (r *Reporter) Name() string {
+	return PluginName
+}
+
+// Init 初始化插件
+func (r *Reporter) Init(ctx *plugin.InitContext) error {
+	r.PluginBase = plugin.NewPluginBase(ctx)
+	r.cfg = &Config{}
+	cfgValue := ctx.Config.GetGlobal().GetEventReporter().GetPluginConfig(PluginName)
+	if cfgValue != nil {
+		r.cfg = cfgValue.(*Config)
+	}
+	r.cfg.SetDefault()
+	r.writer = &lumberjack.Logger{
+		Filename:   r.cfg.Path,
+		MaxSize:    r.cfg.MaxSize,
+		MaxBackups: r.cfg.MaxBackups,
+		MaxAge:     r.cfg.MaxAge,
+		LocalTime:  true,
+	}
+	r.encoder = json.NewEncoder(r.writer)
+	return nil
+}
+
+// IsEnable 是否启用插件
+func (r *Reporter) IsEnable(cfg config.Configuration) bool {
+	return eventcommon.IsReporterEnabled(cfg, PluginName)
+}
+
+// ReportEvent 写入一个治理事件
+func (r *Reporter) ReportEvent(event *model.GovernanceEvent) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.encoder.Encode(event)
+}
+
+// Destroy 销毁插件，关闭事件文件
+func (r *Reporter) Destroy() error {
+	if r.PluginBase != nil {
+		if err := r.PluginBase.Destroy(); err != nil {
+			return err
+		}
+	}
+	if r.writer != nil {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		return r.writer.Close()
+	}
+	return nil
+}
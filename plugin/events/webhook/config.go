@@ -0,0 +1,76 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+)
+
+func init() {
+	plugin.RegisterConfigurablePlugin(&Reporter{}, &Config{})
+}
+
+const (
+	// DefaultTimeout 默认的回调请求超时时间
+	DefaultTimeout = 3 * time.Second
+	// DefaultQueueSize 默认的待发送事件队列长度
+	DefaultQueueSize = 1024
+)
+
+// Config webhook 事件上报插件配置
+type Config struct {
+	// URL 接收事件的回调地址，事件以JSON格式通过POST请求发送
+	URL string `yaml:"url" json:"url"`
+	// Headers 回调请求附带的请求头，如鉴权信息
+	Headers map[string]string `yaml:"headers" json:"headers"`
+	// Timeout 单次回调请求的超时时间
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
+	// QueueSize 待发送事件的最大数量，超出后丢弃
+	QueueSize int `yaml:"queueSize" json:"queueSize"`
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	var errs error
+	if c.URL != "" && !strings.HasPrefix(c.URL, "http://") && !strings.HasPrefix(c.URL, "https://") {
+		errs = multierror.Append(errs, errors.New("eventReporter.webhook.url must start with http:// or https://"))
+	}
+	if c.Timeout < 0 {
+		errs = multierror.Append(errs, errors.New("eventReporter.webhook.timeout must not be negative"))
+	}
+	if c.QueueSize < 0 {
+		errs = multierror.Append(errs, errors.New("eventReporter.webhook.queueSize must not be negative"))
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.Timeout == 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.QueueSize == 0 {
+		c.QueueSize = DefaultQueueSize
+	}
+}
@@ -0,0 +1,161 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/events"
+	eventcommon "github.com/polarismesh/polaris-go/plugin/events/common"
+)
+
+const (
+	// PluginName 插件名称
+	PluginName = "webhook"
+)
+
+var _ events.EventReporter = (*Reporter)(nil)
+
+// Reporter 将治理事件异步POST到配置的回调地址
+type Reporter struct {
+	*plugin.PluginBase
+	*common.RunContext
+	cfg     *Config
+	client  *http.Client
+	queue   chan *model.GovernanceEvent
+	stopped chan struct{}
+	dropped int64
+}
+
+// Type 插件类型
+func (r *Reporter) Type() common.Type {
+	return common.TypeEventReporter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (r *Reporter) Name() string {
+	return PluginName
+}
+
+// Init 初始化插件
+func (r *Reporter) Init(ctx *plugin.InitContext) error {
+	r.PluginBase = plugin.NewPluginBase(ctx)
+	r.RunContext = common.NewRunContext()
+	r.cfg = &Config{}
+	cfgValue := ctx.Config.GetGlobal().GetEventReporter().GetPluginConfig(PluginName)
+	if cfgValue != nil {
+		r.cfg = cfgValue.(*Config)
+	}
+	r.cfg.SetDefault()
+	if r.cfg.URL == "" {
+		return errors.New("eventReporter.webhook.url is required")
+	}
+	r.client = &http.Client{Timeout: r.cfg.Timeout}
+	r.queue = make(chan *model.GovernanceEvent, r.cfg.QueueSize)
+	r.stopped = make(chan struct{})
+	go r.run()
+	return nil
+}
+
+// IsEnable 是否启用插件
+func (r *Reporter) IsEnable(cfg config.Configuration) bool {
+	return eventcommon.IsReporterEnabled(cfg, PluginName)
+}
+
+// ReportEvent 将事件放入发送队列，队列满时丢弃事件
+func (r *Reporter) ReportEvent(event *model.GovernanceEvent) error {
+	select {
+	case r.queue <- event:
+		return nil
+	default:
+		dropped := atomic.AddInt64(&r.dropped, 1)
+		return fmt.Errorf("webhook event queue is full, %d events dropped", dropped)
+	}
+}
+
+// run 后台发送事件，插件销毁后队列中未发送的事件直接丢弃，避免回调地址不可用时阻塞退出
+func (r *Reporter) run() {
+	defer close(r.stopped)
+	for {
+		select {
+		case <-r.Done():
+			return
+		case event := <-r.queue:
+			r.send(event)
+		}
+	}
+}
+
+// send 发送单个事件
+func (r *Reporter) send(event *model.GovernanceEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.GetBaseLogger().Errorf("[EventReporter][Webhook] fail to marshal event %s: %v", event.EventType, err)
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, r.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		log.GetBaseLogger().Errorf("[EventReporter][Webhook] fail to build request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range r.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		log.GetBaseLogger().Warnf("[EventReporter][Webhook] fail to send event %s: %v", event.EventType, err)
+		return
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusBadRequest {
+		log.GetBaseLogger().Warnf("[EventReporter][Webhook] send event %s, unexpected status %d",
+			event.EventType, resp.StatusCode)
+	}
+}
+
+// Destroy 销毁插件
+func (r *Reporter) Destroy() error {
+	if r.PluginBase != nil {
+		if err := r.PluginBase.Destroy(); err != nil {
+			return err
+		}
+	}
+	if r.RunContext != nil {
+		if err := r.RunContext.Destroy(); err != nil {
+			return err
+		}
+	}
+	if r.stopped != nil {
+		<-r.stopped
+	}
+	return nil
+}
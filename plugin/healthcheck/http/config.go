@@ -19,7 +19,9 @@ package http
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 )
@@ -43,6 +45,10 @@ const (
 
 // Config 健康探测的配置
 type Config struct {
+	// Timeout 探测超时时间，未配置时使用consumer.healthCheck.timeout
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
+	// Method HTTP请求方法，默认为GET
+	Method string `yaml:"method" json:"method"`
 	// Path HTTP请求的pattern 如/health
 	Path string `yaml:"path" json:"path"`
 	// Host host to add into the health check request header
@@ -55,6 +61,9 @@ type Config struct {
 
 // SetDefault 设置默认值
 func (r *Config) SetDefault() {
+	if r.Method == "" {
+		r.Method = http.MethodGet
+	}
 	if len(r.ExpectedStatuses) == 0 {
 		r.ExpectedStatuses = []*ExpectedStatus{
 			{Start: defaultExpectStatusStart, End: defaultExpectStatusEnd},
@@ -72,5 +81,8 @@ func (r *Config) Verify() error {
 	if len(r.ExpectedStatuses) == 0 {
 		errs = multierror.Append(errs, fmt.Errorf("expectStatuses can not be empty"))
 	}
+	if r.Timeout < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("http.timeout must not be negative"))
+	}
 	return errs
 }
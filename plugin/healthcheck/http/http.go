@@ -67,6 +67,9 @@ func (g *Detector) Init(ctx *plugin.InitContext) (err error) {
 	}
 	g.client = &http.Client{}
 	g.timeout = ctx.Config.GetConsumer().GetHealthCheck().GetTimeout()
+	if g.cfg != nil && g.cfg.Timeout > 0 {
+		g.timeout = g.cfg.Timeout
+	}
 	return nil
 }
 
@@ -79,7 +82,7 @@ func (g *Detector) Destroy() error {
 func (g *Detector) DetectInstance(ins model.Instance, rule *fault_tolerance.FaultDetectRule) (result healthcheck.DetectResult, err error) {
 	start := time.Now()
 	timeout := g.timeout
-	if rule != nil && rule.Protocol == fault_tolerance.FaultDetectRule_HTTP {
+	if rule != nil && rule.Protocol == fault_tolerance.FaultDetectRule_HTTP && rule.GetTimeout() > 0 {
 		timeout = time.Duration(rule.GetTimeout()) * time.Millisecond
 	}
 
@@ -113,10 +116,20 @@ func (g *Detector) doHttpDetect(detReq *http.Request, rule *fault_tolerance.Faul
 		return "", false
 	}
 	defer resp.Body.Close()
-	if code := resp.StatusCode; code >= 200 && code < 500 {
-		return strconv.Itoa(resp.StatusCode), true
+	code := resp.StatusCode
+	// 未配置探测规则时，按照插件配置的期望状态码校验
+	if rule == nil && g.cfg != nil {
+		for _, expected := range g.cfg.ExpectedStatuses {
+			if code >= expected.Start && code < expected.End {
+				return strconv.Itoa(code), true
+			}
+		}
+		return strconv.Itoa(code), false
+	}
+	if code >= 200 && code < 500 {
+		return strconv.Itoa(code), true
 	}
-	return strconv.Itoa(resp.StatusCode), false
+	return strconv.Itoa(code), false
 }
 
 // Protocol .
@@ -127,12 +140,20 @@ func (g *Detector) Protocol() fault_tolerance.FaultDetectRule_Protocol {
 func (g *Detector) generateHttpRequest(ctx context.Context, ins model.Instance, rule *fault_tolerance.FaultDetectRule) (*http.Request, error) {
 	var (
 		address   string
-		customUrl = g.cfg.Path
+		customUrl string
+		method    = http.MethodGet
+		body      string
 		port      = ins.GetPort()
 	)
 	header := http.Header{}
 	if rule == nil {
-		customUrl = strings.TrimPrefix(customUrl, "/")
+		if g.cfg == nil {
+			return nil, fmt.Errorf("http health check config not found")
+		}
+		customUrl = strings.TrimPrefix(g.cfg.Path, "/")
+		if g.cfg.Method != "" {
+			method = g.cfg.Method
+		}
 		if len(g.cfg.Host) > 0 {
 			header.Add("Host", g.cfg.Host)
 		}
@@ -147,6 +168,10 @@ func (g *Detector) generateHttpRequest(ctx context.Context, ins model.Instance,
 		}
 		customUrl = rule.GetHttpConfig().GetUrl()
 		customUrl = strings.TrimPrefix(customUrl, "/")
+		if rule.GetHttpConfig().GetMethod() != "" {
+			method = rule.GetHttpConfig().GetMethod()
+		}
+		body = rule.GetHttpConfig().GetBody()
 		ruleHeaders := rule.GetHttpConfig().GetHeaders()
 		for i := range ruleHeaders {
 			header.Add(ruleHeaders[i].Key, ruleHeaders[i].Value)
@@ -154,7 +179,7 @@ func (g *Detector) generateHttpRequest(ctx context.Context, ins model.Instance,
 	}
 	address = fmt.Sprintf("http://%s:%d/%s", ins.GetHost(), port, customUrl)
 
-	request, err := http.NewRequestWithContext(ctx, rule.GetHttpConfig().Method, address, bytes.NewBufferString(rule.HttpConfig.GetBody()))
+	request, err := http.NewRequestWithContext(ctx, method, address, bytes.NewBufferString(body))
 	if err != nil {
 		log.GetDetectLogger().Errorf("[HealthCheck][http] fail to build request %+v, err is %v", address, err)
 		return nil, err
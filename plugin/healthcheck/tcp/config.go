@@ -17,13 +17,30 @@
 
 package tcp
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
 // Config 健康探测的配置
 type Config struct {
+	// Timeout 探测超时时间，未配置时使用consumer.healthCheck.timeout
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
+	// Send 未配置探测规则时，连接建立后发送的数据，为空则只检测连接是否可建立
+	Send string `yaml:"send" json:"send"`
+	// Receive 期望收到的数据，为空则不校验返回
+	Receive []string `yaml:"receive" json:"receive"`
 }
 
 // Verify 检验健康探测配置
 func (r *Config) Verify() error {
-	return nil
+	var errs error
+	if r.Timeout < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("tcp.timeout must not be negative"))
+	}
+	return errs
 }
 
 // SetDefault 设置默认值
@@ -66,6 +66,9 @@ func (g *Detector) Init(ctx *plugin.InitContext) (err error) {
 		g.cfg = cfgValue.(*Config)
 	}
 	g.timeout = ctx.Config.GetConsumer().GetHealthCheck().GetTimeout()
+	if g.cfg != nil && g.cfg.Timeout > 0 {
+		g.timeout = g.cfg.Timeout
+	}
 	return nil
 }
 
@@ -94,7 +97,7 @@ func (g *Detector) DetectInstance(ins model.Instance, rule *fault_tolerance.Faul
 // doTCPDetect 执行一次探测逻辑
 func (g *Detector) doTCPDetect(address string, rule *fault_tolerance.FaultDetectRule) bool {
 	timeout := g.timeout
-	if rule != nil {
+	if rule != nil && rule.GetTimeout() > 0 {
 		timeout = time.Duration(rule.GetTimeout()) * time.Millisecond
 	}
 	// 建立连接
@@ -106,32 +109,42 @@ func (g *Detector) doTCPDetect(address string, rule *fault_tolerance.FaultDetect
 	defer func() {
 		_ = conn.Close()
 	}()
-	if rule == nil || rule.GetTcpConfig() == nil {
+	send, receive := g.sendAndReceive(rule)
+	if send == "" {
 		return true
 	}
 	// 发送数据
-	tcpCfg := rule.GetTcpConfig()
-	if tcpCfg.Send == "" {
-		return true
-	}
 	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
 		return false
 	}
-	if _, err = conn.Write([]byte(tcpCfg.Send)); err != nil {
+	if _, err = conn.Write([]byte(send)); err != nil {
 		return false
 	}
 	recvData, err := ioutil.ReadAll(conn)
 	if err != nil && err != io.EOF {
 		return false
 	}
+	if len(receive) == 0 {
+		return true
+	}
 	actualData := string(recvData)
-	found := false
-	for i := range tcpCfg.Receive {
-		if tcpCfg.Receive[i] == actualData {
-			found = true
+	for i := range receive {
+		if receive[i] == actualData {
+			return true
 		}
 	}
-	return found
+	return false
+}
+
+// sendAndReceive 优先使用探测规则中的报文配置，未配置规则时使用插件配置
+func (g *Detector) sendAndReceive(rule *fault_tolerance.FaultDetectRule) (string, []string) {
+	if rule != nil {
+		return rule.GetTcpConfig().GetSend(), rule.GetTcpConfig().GetReceive()
+	}
+	if g.cfg == nil {
+		return "", nil
+	}
+	return g.cfg.Send, g.cfg.Receive
 }
 
 // Protocol .
@@ -17,13 +17,30 @@
 
 package udp
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
 // Config 健康探测的配置
 type Config struct {
+	// Timeout 探测超时时间，未配置时使用consumer.healthCheck.timeout
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
+	// Send 未配置探测规则时发送的探测报文，UDP无连接，为空时无法判断实例是否存活
+	Send string `yaml:"send" json:"send"`
+	// Receive 期望收到的报文，为空时要求实例原样回显Send
+	Receive []string `yaml:"receive" json:"receive"`
 }
 
 // Verify 检验健康探测配置
 func (r *Config) Verify() error {
-	return nil
+	var errs error
+	if r.Timeout < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("udp.timeout must not be negative"))
+	}
+	return errs
 }
 
 // SetDefault 设置默认值
@@ -19,8 +19,6 @@ package udp
 
 import (
 	"fmt"
-	"io"
-	"io/ioutil"
 	"net"
 	"time"
 
@@ -34,6 +32,9 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/healthcheck"
 )
 
+// maxPacketSize UDP探测回包的最大长度
+const maxPacketSize = 65535
+
 // Detector UDP 协议的实例健康探测器
 type Detector struct {
 	*plugin.PluginBase
@@ -55,7 +56,7 @@ func (g *Detector) Type() common.Type {
 
 // Name 插件名，一个类型下插件名唯一
 func (g *Detector) Name() string {
-	return config.DefaultTCPHealthCheck
+	return config.DefaultUDPHealthCheck
 }
 
 // Init 初始化插件
@@ -66,6 +67,9 @@ func (g *Detector) Init(ctx *plugin.InitContext) (err error) {
 		g.cfg = cfgValue.(*Config)
 	}
 	g.timeout = ctx.Config.GetConsumer().GetHealthCheck().GetTimeout()
+	if g.cfg != nil && g.cfg.Timeout > 0 {
+		g.timeout = g.cfg.Timeout
+	}
 	return nil
 }
 
@@ -81,14 +85,20 @@ func (g *Detector) DetectInstance(ins model.Instance, rule *fault_tolerance.Faul
 		Success:        success,
 		DetectTime:     start,
 		DetectInstance: ins,
+		Code: func() string {
+			if success {
+				return "0"
+			}
+			return "-1"
+		}(),
 	}
 	return result, nil
 }
 
-// doTCPDetect 执行一次探测逻辑
+// doUDPDetect 执行一次探测逻辑，发送探测报文并在超时时间内等待回包
 func (g *Detector) doUDPDetect(address string, rule *fault_tolerance.FaultDetectRule) bool {
 	timeout := g.timeout
-	if rule != nil {
+	if rule != nil && rule.GetTimeout() > 0 {
 		timeout = time.Duration(rule.GetTimeout()) * time.Millisecond
 	}
 	// 建立连接
@@ -100,30 +110,45 @@ func (g *Detector) doUDPDetect(address string, rule *fault_tolerance.FaultDetect
 	defer func() {
 		_ = conn.Close()
 	}()
-	if rule == nil || rule.GetUdpConfig() == nil {
+	send, receive := g.sendAndReceive(rule)
+	if send == "" {
 		return true
 	}
-	udpCfg := rule.GetUdpConfig()
-	if udpCfg.Send == "" {
-		return true
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return false
 	}
-	if _, err = conn.Write([]byte(udpCfg.Send)); err != nil {
+	if _, err = conn.Write([]byte(send)); err != nil {
 		log.GetDetectLogger().Errorf("[HealthCheck][udp] fail to write send body %s, err is %v", address, err)
 		return false
 	}
-	recvData, err := ioutil.ReadAll(conn)
-	if err != nil && err != io.EOF {
+	buf := make([]byte, maxPacketSize)
+	n, err := conn.Read(buf)
+	if err != nil {
 		log.GetDetectLogger().Errorf("[HealthCheck][udp] fail to read receive data %s, err is %v", address, err)
 		return false
 	}
-	actualData := string(recvData)
-	found := false
-	for i := range udpCfg.Receive {
-		if udpCfg.Receive[i] == actualData {
-			found = true
+	actualData := string(buf[:n])
+	// 未配置期望报文时按照echo校验
+	if len(receive) == 0 {
+		return actualData == send
+	}
+	for i := range receive {
+		if receive[i] == actualData {
+			return true
 		}
 	}
-	return found
+	return false
+}
+
+// sendAndReceive 优先使用探测规则中的报文配置，未配置规则时使用插件配置
+func (g *Detector) sendAndReceive(rule *fault_tolerance.FaultDetectRule) (string, []string) {
+	if rule != nil {
+		return rule.GetUdpConfig().GetSend(), rule.GetUdpConfig().GetReceive()
+	}
+	if g.cfg == nil {
+		return "", nil
+	}
+	return g.cfg.Send, g.cfg.Receive
 }
 
 // Protocol .
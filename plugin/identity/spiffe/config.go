@@ -0,0 +1,95 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package spiffe
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+)
+
+func init() {
+	plugin.RegisterConfigurablePlugin(&Provider{}, &Config{})
+}
+
+const (
+	// EnvEndpointSocket SPIFFE 规范约定的 Workload API 地址环境变量
+	EnvEndpointSocket = "SPIFFE_ENDPOINT_SOCKET"
+	// DefaultSocketPath 默认的 Workload API 地址，与 spire-agent 默认配置一致
+	DefaultSocketPath = "unix:///tmp/spire-agent/public/api.sock"
+	// DefaultFetchTimeout 默认的首次获取 SVID 超时时间
+	DefaultFetchTimeout = 5 * time.Second
+	// DefaultReconnectInterval 默认的 Workload API 断开后重连间隔
+	DefaultReconnectInterval = time.Second
+	// DefaultRefreshBefore 默认在证书过期前多久主动刷新
+	DefaultRefreshBefore = 5 * time.Minute
+)
+
+// Config spiffe 插件配置
+type Config struct {
+	// SocketPath Workload API 地址，格式为 unix:///path/to/api.sock
+	SocketPath string `yaml:"socketPath" json:"socketPath"`
+	// FetchTimeout 首次获取 SVID 的超时时间，超时则 SDK 初始化失败
+	FetchTimeout time.Duration `yaml:"fetchTimeout" json:"fetchTimeout"`
+	// ReconnectInterval Workload API 断开后的重连间隔
+	ReconnectInterval time.Duration `yaml:"reconnectInterval" json:"reconnectInterval"`
+	// RefreshBefore 在证书过期前多久主动重新获取，避免 agent 未及时推送导致证书过期
+	RefreshBefore time.Duration `yaml:"refreshBefore" json:"refreshBefore"`
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	var errs error
+	if !strings.HasPrefix(c.SocketPath, "unix://") {
+		errs = multierror.Append(errs, fmt.Errorf("spiffe.socketPath must start with unix://"))
+	}
+	if c.FetchTimeout <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("spiffe.fetchTimeout must be greater than 0"))
+	}
+	if c.ReconnectInterval <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("spiffe.reconnectInterval must be greater than 0"))
+	}
+	if c.RefreshBefore < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("spiffe.refreshBefore must not be negative"))
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.SocketPath == "" {
+		c.SocketPath = os.Getenv(EnvEndpointSocket)
+	}
+	if c.SocketPath == "" {
+		c.SocketPath = DefaultSocketPath
+	}
+	if c.FetchTimeout == 0 {
+		c.FetchTimeout = DefaultFetchTimeout
+	}
+	if c.ReconnectInterval == 0 {
+		c.ReconnectInterval = DefaultReconnectInterval
+	}
+	if c.RefreshBefore == 0 {
+		c.RefreshBefore = DefaultRefreshBefore
+	}
+}
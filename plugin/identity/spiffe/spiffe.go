@@ -0,0 +1,205 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package spiffe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/identity"
+)
+
+const (
+	// PluginName 插件名称
+	PluginName = "spiffe"
+)
+
+var _ identity.IdentityProvider = (*Provider)(nil)
+
+// Provider 通过 SPIFFE Workload API 获取 X509-SVID，并在证书过期前自动刷新
+type Provider struct {
+	*plugin.PluginBase
+	pluginCfg *Config
+	conn      *grpc.ClientConn
+	rwMutex   sync.RWMutex
+	svid      *x509SVID
+	cancel    context.CancelFunc
+	stopped   chan struct{}
+}
+
+// Type 插件类型
+func (p *Provider) Type() common.Type {
+	return common.TypeIdentityProvider
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (p *Provider) Name() string {
+	return PluginName
+}
+
+// IsEnable 仅在启用工作负载身份且选择了 spiffe 插件时启用
+func (p *Provider) IsEnable(cfg config.Configuration) bool {
+	identityCfg := cfg.GetGlobal().GetIdentityProvider()
+	return identityCfg.IsEnable() && identityCfg.GetType() == PluginName
+}
+
+// Init 初始化插件，同步获取一次 SVID，获取失败则 SDK 初始化失败
+func (p *Provider) Init(ctx *plugin.InitContext) error {
+	p.PluginBase = plugin.NewPluginBase(ctx)
+	p.pluginCfg = &Config{}
+	cfgValue := ctx.Config.GetGlobal().GetIdentityProvider().GetPluginConfig(PluginName)
+	if cfgValue != nil {
+		p.pluginCfg = cfgValue.(*Config)
+	}
+	p.pluginCfg.SetDefault()
+	conn, err := dialWorkloadAPI(p.pluginCfg.SocketPath)
+	if err != nil {
+		return fmt.Errorf("fail to dial spiffe workload api %s: %v", p.pluginCfg.SocketPath, err)
+	}
+	p.conn = conn
+	fetchCtx, cancel := context.WithTimeout(context.Background(), p.pluginCfg.FetchTimeout)
+	defer cancel()
+	err = watchX509SVID(fetchCtx, p.conn, func(svid *x509SVID) bool {
+		p.updateSVID(svid)
+		return false
+	})
+	if err != nil {
+		_ = p.conn.Close()
+		return fmt.Errorf("fail to fetch svid from spiffe workload api %s: %v", p.pluginCfg.SocketPath, err)
+	}
+	return nil
+}
+
+// Start 启动插件，持续订阅 SVID 更新
+func (p *Provider) Start() error {
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(context.Background())
+	p.stopped = make(chan struct{})
+	go p.watch(ctx)
+	return nil
+}
+
+// watch 订阅 Workload API 推送，流的截止时间设置为证书的刷新时间点，
+// 确保 agent 未及时推送时也能在证书过期前重新拉取
+func (p *Provider) watch(ctx context.Context) {
+	defer close(p.stopped)
+	for {
+		streamCtx, cancel := context.WithDeadline(ctx, p.refreshTime())
+		err := watchX509SVID(streamCtx, p.conn, func(svid *x509SVID) bool {
+			p.updateSVID(svid)
+			return true
+		})
+		deadlineExceeded := errors.Is(streamCtx.Err(), context.DeadlineExceeded)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if deadlineExceeded {
+			log.GetBaseLogger().Infof("[Identity][SPIFFE] svid %s is about to expire, refetch", p.GetIdentity())
+			continue
+		}
+		log.GetBaseLogger().Warnf("[Identity][SPIFFE] workload api stream broken, reconnect after %v: %v",
+			p.pluginCfg.ReconnectInterval, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.pluginCfg.ReconnectInterval):
+		}
+	}
+}
+
+// refreshTime 计算当前证书的刷新时间点，至少间隔一个重连周期以避免证书已过期时频繁重建流
+func (p *Provider) refreshTime() time.Time {
+	p.rwMutex.RLock()
+	svid := p.svid
+	p.rwMutex.RUnlock()
+	earliest := time.Now().Add(p.pluginCfg.ReconnectInterval)
+	refreshAt := svid.notAfter.Add(-p.pluginCfg.RefreshBefore)
+	// 证书有效期较短时，在有效期过半时刷新
+	if half := svid.notBefore.Add(svid.notAfter.Sub(svid.notBefore) / 2); half.After(refreshAt) {
+		refreshAt = half
+	}
+	if refreshAt.Before(earliest) {
+		return earliest
+	}
+	return refreshAt
+}
+
+// updateSVID 替换当前 SVID
+func (p *Provider) updateSVID(svid *x509SVID) {
+	p.rwMutex.Lock()
+	previous := p.svid
+	p.svid = svid
+	p.rwMutex.Unlock()
+	if previous == nil || !previous.notAfter.Equal(svid.notAfter) || previous.id != svid.id {
+		log.GetBaseLogger().Infof("[Identity][SPIFFE] svid %s updated, expire at %v", svid.id, svid.notAfter)
+	}
+}
+
+// GetIdentity 获取当前 SPIFFE ID
+func (p *Provider) GetIdentity() string {
+	p.rwMutex.RLock()
+	defer p.rwMutex.RUnlock()
+	if p.svid == nil {
+		return ""
+	}
+	return p.svid.id
+}
+
+// GetCertificate 获取当前 X509-SVID 证书及私钥
+func (p *Provider) GetCertificate() (*tls.Certificate, error) {
+	p.rwMutex.RLock()
+	defer p.rwMutex.RUnlock()
+	if p.svid == nil {
+		return nil, errors.New("spiffe svid not fetched")
+	}
+	return p.svid.certificate, nil
+}
+
+// GetTrustBundle 获取信任域的 CA 证书池
+func (p *Provider) GetTrustBundle() (*x509.CertPool, error) {
+	p.rwMutex.RLock()
+	defer p.rwMutex.RUnlock()
+	if p.svid == nil {
+		return nil, errors.New("spiffe svid not fetched")
+	}
+	return p.svid.bundle, nil
+}
+
+// Destroy 销毁插件，停止订阅并关闭连接
+func (p *Provider) Destroy() error {
+	if p.cancel != nil {
+		p.cancel()
+		<-p.stopped
+	}
+	if p.conn != nil {
+		return p.conn.Close()
+	}
+	return nil
+}
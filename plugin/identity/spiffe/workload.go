@@ -0,0 +1,227 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package spiffe
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// fetchX509SVIDMethod Workload API 获取 X509-SVID 的流式方法
+	fetchX509SVIDMethod = "/SpiffeWorkloadAPI/FetchX509SVID"
+	// workloadHeaderKey Workload API 要求请求携带的安全头
+	workloadHeaderKey = "workload.spiffe.io"
+)
+
+var fetchX509SVIDStream = &grpc.StreamDesc{
+	StreamName:    "FetchX509SVID",
+	ServerStreams: true,
+}
+
+// x509SVID 从 Workload API 获取到的身份证书
+type x509SVID struct {
+	id          string
+	certificate *tls.Certificate
+	bundle      *x509.CertPool
+	notBefore   time.Time
+	notAfter    time.Time
+}
+
+// rawCodec 直接收发 protobuf 编码后的字节，避免引入 Workload API 的生成代码
+type rawCodec struct{}
+
+// Marshal 编码
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	data, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("rawCodec: unsupported message type %T", v)
+	}
+	return *data, nil
+}
+
+// Unmarshal 解码
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	buf, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("rawCodec: unsupported message type %T", v)
+	}
+	*buf = append((*buf)[:0], data...)
+	return nil
+}
+
+// Name 编码名，与 protobuf 保持一致以通过服务端 content-type 校验
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// dialWorkloadAPI 连接本机 Workload API
+func dialWorkloadAPI(socketPath string) (*grpc.ClientConn, error) {
+	return grpc.Dial(socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(rawCodec{})))
+}
+
+// watchX509SVID 订阅 X509-SVID 更新，每收到一次推送回调一次，直到流出错或 ctx 结束
+func watchX509SVID(ctx context.Context, conn *grpc.ClientConn, onUpdate func(*x509SVID) bool) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, workloadHeaderKey, "true")
+	stream, err := conn.NewStream(ctx, fetchX509SVIDStream, fetchX509SVIDMethod)
+	if err != nil {
+		return err
+	}
+	// X509SVIDRequest 没有字段，发送空消息即可
+	request := []byte{}
+	if err = stream.SendMsg(&request); err != nil {
+		return err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		var response []byte
+		if err = stream.RecvMsg(&response); err != nil {
+			return err
+		}
+		svid, err := parseX509SVIDResponse(response)
+		if err != nil {
+			return err
+		}
+		if !onUpdate(svid) {
+			return nil
+		}
+	}
+}
+
+// parseX509SVIDResponse 解析 X509SVIDResponse，取第一个 SVID 作为默认身份
+func parseX509SVIDResponse(data []byte) (*x509SVID, error) {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+		// X509SVIDResponse.svids = 1
+		if num == 1 && typ == protowire.BytesType {
+			value, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			return parseX509SVID(value)
+		}
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+	}
+	return nil, errors.New("no svid in workload api response")
+}
+
+// parseX509SVID 解析 X509SVID 消息
+func parseX509SVID(data []byte) (*x509SVID, error) {
+	var (
+		id      string
+		certDER []byte
+		keyDER  []byte
+		bundle  []byte
+	)
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+		value, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+		switch num {
+		case 1:
+			id = string(value)
+		case 2:
+			certDER = value
+		case 3:
+			keyDER = value
+		case 4:
+			bundle = value
+		}
+	}
+	return buildX509SVID(id, certDER, keyDER, bundle)
+}
+
+// buildX509SVID 将 DER 编码的证书链、私钥及信任包转换为 tls 可用的结构
+func buildX509SVID(id string, certDER, keyDER, bundleDER []byte) (*x509SVID, error) {
+	if id == "" {
+		return nil, errors.New("svid without spiffe id")
+	}
+	certs, err := x509.ParseCertificates(certDER)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse svid %s certificates: %v", id, err)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("svid %s without certificate", id)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyDER)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse svid %s private key: %v", id, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("svid %s private key is not a signer", id)
+	}
+	bundleCerts, err := x509.ParseCertificates(bundleDER)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse svid %s bundle: %v", id, err)
+	}
+	pool := x509.NewCertPool()
+	for _, cert := range bundleCerts {
+		pool.AddCert(cert)
+	}
+	certificate := &tls.Certificate{
+		PrivateKey: signer,
+		Leaf:       certs[0],
+	}
+	for _, cert := range certs {
+		certificate.Certificate = append(certificate.Certificate, cert.Raw)
+	}
+	return &x509SVID{
+		id:          id,
+		certificate: certificate,
+		bundle:      pool,
+		notBefore:   certs[0].NotBefore,
+		notAfter:    certs[0].NotAfter,
+	}, nil
+}
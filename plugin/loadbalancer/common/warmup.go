@@ -0,0 +1,98 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Warmup 新实例预热，预热窗口内实例的有效权重随注册时长线性增长
+// 未启用预热时为nil，nil值的方法直接返回实例原始权重
+type Warmup struct {
+	window time.Duration
+}
+
+// NewWarmup 根据配置创建预热计算器，未启用时返回nil
+func NewWarmup(cfg config.WarmupConfig) *Warmup {
+	if cfg == nil || !cfg.IsEnable() || cfg.GetWindow() <= 0 {
+		return nil
+	}
+	return &Warmup{window: cfg.GetWindow()}
+}
+
+// Weight 获取实例在当前时刻的有效权重
+func (w *Warmup) Weight(instance model.Instance, now time.Time) int {
+	weight := instance.GetWeight()
+	if w == nil || weight <= 0 {
+		return weight
+	}
+	createTime := instance.GetCreateTime()
+	if createTime.IsZero() {
+		return weight
+	}
+	elapsed := now.Sub(createTime)
+	if elapsed >= w.window {
+		return weight
+	}
+	if elapsed <= 0 {
+		return 1
+	}
+	effective := int(int64(weight) * int64(elapsed) / int64(w.window))
+	if effective < 1 {
+		return 1
+	}
+	return effective
+}
+
+// SelectWeightedInstance 按有效权重进行随机选择
+// 集合中没有处于预热期的实例时返回nil，由调用方走原有的选择逻辑
+func (w *Warmup) SelectWeightedInstance(scalableRand *rand.ScalableRand,
+	svcInstances model.ServiceInstances, instances *model.InstanceSet) model.Instance {
+	if w == nil {
+		return nil
+	}
+	now := time.Now()
+	indexes := instances.GetInstances()
+	allInstances := svcInstances.GetInstances()
+	weights := make([]int, len(indexes))
+	var totalWeight int
+	var warming bool
+	for i, index := range indexes {
+		instance := allInstances[index.Index]
+		weights[i] = w.Weight(instance, now)
+		if weights[i] != instance.GetWeight() {
+			warming = true
+		}
+		totalWeight += weights[i]
+	}
+	if !warming || totalWeight <= 0 {
+		return nil
+	}
+	selector := scalableRand.Intn(totalWeight)
+	for i, index := range indexes {
+		if selector < weights[i] {
+			return allInstances[index.Index]
+		}
+		selector -= weights[i]
+	}
+	return nil
+}
@@ -35,6 +35,10 @@ const (
 type Config struct {
 	HashFunction string `yaml:"hashFunction" json:"hashFunction"`
 	TableSize    int    `yaml:"tableSize" json:"tableSize"`
+	// PermutationSeed 计算实例排列offset的hash种子，skip使用PermutationSeed+1，修改后实例在表中的分布随之改变
+	PermutationSeed uint32 `yaml:"permutationSeed" json:"permutationSeed"`
+	// ReplicateCount 请求未指定备份节点数时，默认返回的备份节点数
+	ReplicateCount int `yaml:"replicateCount" json:"replicateCount"`
 }
 
 // Verify 检验一致性hash配置
@@ -43,6 +47,9 @@ func (c *Config) Verify() error {
 	if !isPrime(c.TableSize) {
 		errs = multierror.Append(errs, fmt.Errorf("maglev.tableSize must be prime"))
 	}
+	if c.ReplicateCount < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("maglev.replicateCount must not be negative"))
+	}
 	return errs
 }
 
@@ -71,7 +71,7 @@ func (m *MaglevLoadBalancer) getOrBuildHashRing(instSet *model.InstanceSet) (mod
 	if nil != selector {
 		return selector, nil
 	}
-	tableSelector, err := NewTable(instSet, uint64(m.cfg.TableSize), m.hashFunc, m.ID())
+	tableSelector, err := NewTable(instSet, m.cfg, m.hashFunc, m.ID())
 	instSet.SetSelector(tableSelector)
 	return tableSelector, err
 }
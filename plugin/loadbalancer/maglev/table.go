@@ -34,6 +34,10 @@ type TableSelector struct {
 	nodes             []*model.WeightedIndex
 	tableSize         uint64
 	hashFunc          hash.HashFuncWithSeed
+	permutationSeed   uint32
+	replicateCount    int
+	instanceCount     int
+	svcClusters       model.ServiceClusters
 	minEntriesPerHost float64
 	maxEntriesPerHost float64
 }
@@ -68,11 +72,11 @@ func (t *TableSelector) buildTableEntries(
 			maxNormalizedWeight = normalizedWeight
 		}
 		idBuf := []byte(realInstance.GetId())
-		seed0HashValue, err := hashFunc(idBuf, 0)
+		seed0HashValue, err := hashFunc(idBuf, t.permutationSeed)
 		if err != nil {
 			return 0, nil, fmt.Errorf("fail to get seed0 hash value for %s", realInstance.GetId())
 		}
-		seed1HashValue, err := hashFunc(idBuf, 1)
+		seed1HashValue, err := hashFunc(idBuf, t.permutationSeed+1)
 		if err != nil {
 			return 0, nil, fmt.Errorf("fail to get seed1 hash value for %s", realInstance.GetId())
 		}
@@ -86,10 +90,15 @@ func (t *TableSelector) buildTableEntries(
 
 // NewTable 创建maglev向量选择器
 func NewTable(
-	instanceSet *model.InstanceSet, tableSize uint64, hashFunc hash.HashFuncWithSeed, id int32) (*TableSelector, error) {
+	instanceSet *model.InstanceSet, cfg *Config, hashFunc hash.HashFuncWithSeed, id int32) (*TableSelector, error) {
+	tableSize := uint64(cfg.TableSize)
 	var selector = &TableSelector{
-		hashFunc:  hashFunc,
-		tableSize: tableSize,
+		hashFunc:        hashFunc,
+		tableSize:       tableSize,
+		permutationSeed: cfg.PermutationSeed,
+		replicateCount:  cfg.ReplicateCount,
+		instanceCount:   instanceSet.Count(),
+		svcClusters:     instanceSet.GetServiceClusters(),
 	}
 	selector.Id = id
 	if instanceSet.Count() == 0 {
@@ -153,5 +162,46 @@ func (t *TableSelector) Select(value interface{}) (int, *model.ReplicateNodes, e
 		return -1, nil, err
 	}
 	nodeIndex := int(hashValue % t.tableSize)
-	return t.nodes[nodeIndex].Index, nil, nil
+	replicateCount := criteria.ReplicateInfo.Count
+	if replicateCount == 0 {
+		replicateCount = t.replicateCount
+	}
+	return t.nodes[nodeIndex].Index, t.selectReplicates(nodeIndex, replicateCount), nil
+}
+
+// selectReplicates 沿向量表向后查找与目标实例不同的备份节点
+func (t *TableSelector) selectReplicates(nodeIndex int, replicateCount int) *model.ReplicateNodes {
+	if replicateCount == 0 {
+		return nil
+	}
+	// 备份节点数最多为实例数减一，避免无效地遍历整张表
+	maxCount := replicateCount
+	if maxCount > t.instanceCount-1 {
+		maxCount = t.instanceCount - 1
+	}
+	targetIndex := t.nodes[nodeIndex].Index
+	replicateIndexes := make([]int, 0, replicateCount)
+	tableSize := len(t.nodes)
+	for i := 1; i < tableSize && len(replicateIndexes) < maxCount; i++ {
+		replicateIndex := t.nodes[(nodeIndex+i)%tableSize].Index
+		if replicateIndex == targetIndex || containsIndex(replicateIndexes, replicateIndex) {
+			continue
+		}
+		replicateIndexes = append(replicateIndexes, replicateIndex)
+	}
+	return &model.ReplicateNodes{
+		SvcClusters: t.svcClusters,
+		Count:       replicateCount,
+		Indexes:     replicateIndexes,
+	}
+}
+
+// 查看数组是否包含索引
+func containsIndex(replicateIndexes []int, replicateIndex int) bool {
+	for _, idx := range replicateIndexes {
+		if idx == replicateIndex {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,145 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package p2c
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	lbcommon "github.com/polarismesh/polaris-go/plugin/loadbalancer/common"
+)
+
+// LoadBalancer 基于在途请求数的P2C负载均衡插件
+// 随机选取两个实例，选择按权重折算后在途请求数较少的实例
+// 在途请求数在选中实例时增加，在上报调用结果时减少，因此使用该插件时需要上报每一次的调用结果
+type LoadBalancer struct {
+	*plugin.PluginBase
+	scalableRand *rand.ScalableRand
+	// 实例ID到在途请求数的映射
+	inflights sync.Map
+	// 新实例预热
+	warmup *lbcommon.Warmup
+}
+
+// Type 插件类型
+func (l *LoadBalancer) Type() common.Type {
+	return common.TypeLoadBalancer
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (l *LoadBalancer) Name() string {
+	return config.DefaultLoadBalancerP2C
+}
+
+// Init 初始化插件
+func (l *LoadBalancer) Init(ctx *plugin.InitContext) error {
+	l.PluginBase = plugin.NewPluginBase(ctx)
+	l.scalableRand = rand.NewScalableRand()
+	l.warmup = lbcommon.NewWarmup(ctx.Config.GetConsumer().GetLoadbalancer().GetWarmup())
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (l *LoadBalancer) Destroy() error {
+	return nil
+}
+
+// UpdateCallResult 调用结束，减少实例的在途请求数
+func (l *LoadBalancer) UpdateCallResult(result *model.ServiceCallResult) {
+	instance := result.GetCalledInstance()
+	if instance == nil {
+		return
+	}
+	value, ok := l.inflights.Load(instance.GetId())
+	if !ok {
+		return
+	}
+	counter := value.(*int64)
+	for {
+		current := atomic.LoadInt64(counter)
+		if current <= 0 || atomic.CompareAndSwapInt64(counter, current, current-1) {
+			return
+		}
+	}
+}
+
+// ChooseInstance 获取单个服务实例
+func (l *LoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
+	inputInstances model.ServiceInstances) (model.Instance, error) {
+	cluster := criteria.Cluster
+	svcClusters := inputInstances.GetServiceClusters()
+	svcInstances := svcClusters.GetServiceInstances()
+	targetInstances := lbcommon.SelectAvailableInstanceSet(cluster.GetClusterValue(), cluster.HasLimitedInstances,
+		cluster.IncludeHalfOpen)
+	if targetInstances.TotalWeight() == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"instances of %s in cluster %s all weight 0 (instance count %d) in load balance",
+			svcClusters.GetServiceKey(), *cluster, targetInstances.Count())
+	}
+	indexes := targetInstances.GetInstances()
+	allInstances := svcInstances.GetInstances()
+	var instance model.Instance
+	if len(indexes) == 1 {
+		instance = allInstances[indexes[0].Index]
+	} else {
+		first := l.scalableRand.Intn(len(indexes))
+		second := l.scalableRand.Intn(len(indexes) - 1)
+		if second >= first {
+			second++
+		}
+		instA := allInstances[indexes[first].Index]
+		instB := allInstances[indexes[second].Index]
+		instance = instA
+		now := time.Now()
+		if l.load(now, instB) < l.load(now, instA) {
+			instance = instB
+		}
+	}
+	atomic.AddInt64(l.counter(instance.GetId()), 1)
+	return instance, nil
+}
+
+// load 计算实例的负载，在途请求数按权重折算
+func (l *LoadBalancer) load(now time.Time, instance model.Instance) float64 {
+	weight := l.warmup.Weight(instance, now)
+	if weight <= 0 {
+		weight = 1
+	}
+	return float64(atomic.LoadInt64(l.counter(instance.GetId()))+1) / float64(weight)
+}
+
+// counter 获取实例的在途请求计数器
+func (l *LoadBalancer) counter(instanceID string) *int64 {
+	value, ok := l.inflights.Load(instanceID)
+	if !ok {
+		value, _ = l.inflights.LoadOrStore(instanceID, new(int64))
+	}
+	return value.(*int64)
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterPlugin(&LoadBalancer{})
+}
@@ -0,0 +1,52 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package peakewma
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DefaultDecay 默认的时延衰减周期
+	DefaultDecay = 10 * time.Second
+)
+
+// Config peak ewma负载均衡配置对象
+type Config struct {
+	// Decay 时延的衰减周期，越小对时延变化越敏感
+	Decay time.Duration `yaml:"decay" json:"decay"`
+}
+
+// Verify 检验peak ewma配置
+func (c *Config) Verify() error {
+	var errs error
+	if c.Decay <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("peakEwma.decay must be greater than 0"))
+	}
+	return errs
+}
+
+// SetDefault 设置peak ewma默认值
+func (c *Config) SetDefault() {
+	if c.Decay == 0 {
+		c.Decay = DefaultDecay
+	}
+}
@@ -0,0 +1,188 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package peakewma
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	lbcommon "github.com/polarismesh/polaris-go/plugin/loadbalancer/common"
+)
+
+// LoadBalancer 基于peak ewma时延的负载均衡插件
+// 随机选取两个实例，选择按权重折算后时延较低的实例（P2C + PeakEWMA）
+type LoadBalancer struct {
+	*plugin.PluginBase
+	cfg          *Config
+	scalableRand *rand.ScalableRand
+	// 新实例预热
+	warmup *lbcommon.Warmup
+}
+
+// Type 插件类型
+func (l *LoadBalancer) Type() common.Type {
+	return common.TypeLoadBalancer
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (l *LoadBalancer) Name() string {
+	return config.DefaultLoadBalancerPeakEWMA
+}
+
+// Init 初始化插件
+func (l *LoadBalancer) Init(ctx *plugin.InitContext) error {
+	l.PluginBase = plugin.NewPluginBase(ctx)
+	l.cfg = ctx.Config.GetConsumer().GetLoadbalancer().GetPluginConfig(l.Name()).(*Config)
+	l.scalableRand = rand.NewScalableRand()
+	l.warmup = lbcommon.NewWarmup(ctx.Config.GetConsumer().GetLoadbalancer().GetWarmup())
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (l *LoadBalancer) Destroy() error {
+	return nil
+}
+
+// UpdateCallResult 根据调用结果更新实例的时延统计
+func (l *LoadBalancer) UpdateCallResult(result *model.ServiceCallResult) {
+	delay := result.GetDelay()
+	instance := result.GetCalledInstance()
+	if delay == nil || instance == nil {
+		return
+	}
+	if stat := l.getStat(instance, true); stat != nil {
+		stat.observe(time.Now(), float64(*delay), l.cfg.Decay)
+	}
+}
+
+// localValueHolder 持有本地状态的实例，时延统计随实例本地状态一同释放，实例下线后不会残留
+type localValueHolder interface {
+	GetInstanceLocalValue() local.InstanceLocalValue
+}
+
+// getStat 获取实例的时延统计，create为true时不存在则创建
+// 并发创建时可能丢失个别样本，对时延均值的影响可以忽略
+func (l *LoadBalancer) getStat(instance model.Instance, create bool) *ewma {
+	holder, ok := instance.(localValueHolder)
+	if !ok {
+		return nil
+	}
+	localValue := holder.GetInstanceLocalValue()
+	if localValue == nil {
+		return nil
+	}
+	if stat, ok := localValue.GetExtendedData(l.ID()).(*ewma); ok {
+		return stat
+	}
+	if !create {
+		return nil
+	}
+	stat := &ewma{}
+	localValue.SetExtendedData(l.ID(), stat)
+	return stat
+}
+
+// ChooseInstance 获取单个服务实例
+func (l *LoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
+	inputInstances model.ServiceInstances) (model.Instance, error) {
+	cluster := criteria.Cluster
+	svcClusters := inputInstances.GetServiceClusters()
+	svcInstances := svcClusters.GetServiceInstances()
+	targetInstances := lbcommon.SelectAvailableInstanceSet(cluster.GetClusterValue(), cluster.HasLimitedInstances,
+		cluster.IncludeHalfOpen)
+	if targetInstances.TotalWeight() == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"instances of %s in cluster %s all weight 0 (instance count %d) in load balance",
+			svcClusters.GetServiceKey(), *cluster, targetInstances.Count())
+	}
+	indexes := targetInstances.GetInstances()
+	allInstances := svcInstances.GetInstances()
+	if len(indexes) == 1 {
+		return allInstances[indexes[0].Index], nil
+	}
+	first := l.scalableRand.Intn(len(indexes))
+	second := l.scalableRand.Intn(len(indexes) - 1)
+	if second >= first {
+		second++
+	}
+	instA := allInstances[indexes[first].Index]
+	instB := allInstances[indexes[second].Index]
+	now := time.Now()
+	if l.cost(now, instB) < l.cost(now, instA) {
+		return instB, nil
+	}
+	return instA, nil
+}
+
+// cost 计算实例的负载代价，时延按权重折算，权重越大代价越小
+func (l *LoadBalancer) cost(now time.Time, instance model.Instance) float64 {
+	weight := l.warmup.Weight(instance, now)
+	if weight <= 0 {
+		return math.MaxFloat64
+	}
+	stat := l.getStat(instance, false)
+	if stat == nil {
+		// 没有统计数据的实例优先探测
+		return 0
+	}
+	return stat.get(now, l.cfg.Decay) / float64(weight)
+}
+
+// ewma 带峰值敏感的指数加权移动平均时延
+type ewma struct {
+	mutex sync.Mutex
+	value float64
+	stamp time.Time
+}
+
+// observe 记录一次时延，时延高于均值时直接取峰值，否则按时间衰减合并
+func (e *ewma) observe(now time.Time, rtt float64, decay time.Duration) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.stamp.IsZero() || rtt > e.value {
+		e.value = rtt
+	} else {
+		w := math.Exp(-float64(now.Sub(e.stamp)) / float64(decay))
+		e.value = e.value*w + rtt*(1-w)
+	}
+	e.stamp = now
+}
+
+// get 获取当前时延，长时间没有调用的实例时延会逐步衰减，使其重新获得流量
+func (e *ewma) get(now time.Time, decay time.Duration) float64 {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	elapsed := now.Sub(e.stamp)
+	if elapsed <= 0 {
+		return e.value
+	}
+	return e.value * math.Exp(-float64(elapsed)/float64(decay))
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&LoadBalancer{}, &Config{})
+}
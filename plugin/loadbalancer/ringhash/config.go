@@ -28,12 +28,18 @@ import (
 const (
 	// DefaultVnodeCount 默认虚拟节点数
 	DefaultVnodeCount = 10
+	// DefaultLoadFactor 有界负载模式下默认的负载因子
+	DefaultLoadFactor = 1.25
 )
 
 // Config 一致性hash配置对象
 type Config struct {
 	HashFunction string `yaml:"hashFunction" json:"hashFunction"`
 	VnodeCount   int    `yaml:"vnodeCount" json:"vnodeCount"`
+	// BoundedLoad 是否开启有界负载模式（CH-BL），实例的在途请求数超过上限时顺延到环上的下一个实例
+	BoundedLoad bool `yaml:"boundedLoad" json:"boundedLoad"`
+	// LoadFactor 负载因子，实例的在途请求数上限为 LoadFactor * 按权重计算的平均在途请求数
+	LoadFactor float64 `yaml:"loadFactor" json:"loadFactor"`
 }
 
 // Verify 检验一致性hash配置
@@ -42,6 +48,9 @@ func (c *Config) Verify() error {
 	if c.VnodeCount <= 0 {
 		errs = multierror.Append(errs, fmt.Errorf("ringhash.vnodeCount must be greater than 0"))
 	}
+	if c.BoundedLoad && c.LoadFactor <= 1 {
+		errs = multierror.Append(errs, fmt.Errorf("ringhash.loadFactor must be greater than 1"))
+	}
 	return errs
 }
 
@@ -50,6 +59,9 @@ func (c *Config) SetDefault() {
 	if c.VnodeCount == 0 {
 		c.VnodeCount = DefaultVnodeCount
 	}
+	if c.LoadFactor == 0 {
+		c.LoadFactor = DefaultLoadFactor
+	}
 	if len(c.HashFunction) == 0 {
 		c.HashFunction = hash.DefaultHashFuncName
 	}
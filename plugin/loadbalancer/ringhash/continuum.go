@@ -214,6 +214,26 @@ func (c *ContinuumSelector) Select(value interface{}) (int, *model.ReplicateNode
 	}
 }
 
+// SelectBounded 从hash值对应的节点开始沿环查找第一个被accept接受的实例，都不接受时返回hash值对应的实例
+func (c *ContinuumSelector) SelectBounded(criteria *loadbalancer.Criteria, accept func(index int) bool) (int, error) {
+	ringLen := len(c.ring)
+	if ringLen == 0 {
+		return -1, nil
+	}
+	hashValue, err := common.CalcHashValue(criteria, c.hashFunc)
+	if err != nil {
+		return -1, err
+	}
+	ringIndex := search.BinarySearch(c.ring, hashValue)
+	for i := 0; i < ringLen; i++ {
+		index := c.ring[(ringIndex+i)%ringLen].index
+		if accept(index) {
+			return index, nil
+		}
+	}
+	return c.ring[ringIndex].index, nil
+}
+
 // 通过hash值选择具体的节点
 func (c *ContinuumSelector) selectByHashValue(hashValue uint64, replicateCount int) (int, *model.ReplicateNodes) {
 	ringIndex := search.BinarySearch(c.ring, hashValue)
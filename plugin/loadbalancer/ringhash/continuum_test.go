@@ -0,0 +1,108 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package ringhash
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildTestRing 按hash值从小到大构造hash环，indexes 为各节点对应的实例下标
+func buildTestRing(indexes ...int) *ContinuumSelector {
+	ring := make(points, 0, len(indexes))
+	for i, index := range indexes {
+		ring = append(ring, continuumPoint{
+			hashValue:  uint64(i+1) * 10,
+			index:      index,
+			replicates: &atomic.Value{},
+		})
+	}
+	return &ContinuumSelector{ring: ring}
+}
+
+func TestContinuumSelector_selectByHashValue(t *testing.T) {
+	// 环上节点的hash值依次为10到60，同一实例有多个虚拟节点
+	ring := buildTestRing(0, 1, 0, 2, 1, 3)
+	tests := []struct {
+		name           string
+		hashValue      uint64
+		replicateCount int
+		expectTarget   int
+		expectReplicas []int
+	}{
+		{
+			name:           "不需要备份节点",
+			hashValue:      20,
+			expectTarget:   1,
+			expectReplicas: nil,
+		},
+		{
+			name:           "跳过与目标实例相同的虚拟节点",
+			hashValue:      10,
+			replicateCount: 2,
+			expectTarget:   0,
+			expectReplicas: []int{1, 2},
+		},
+		{
+			name:           "跳过已选中实例的虚拟节点并沿环回绕",
+			hashValue:      40,
+			replicateCount: 3,
+			expectTarget:   2,
+			expectReplicas: []int{1, 3, 0},
+		},
+		{
+			name:           "hash值超过环上最大值时从头开始",
+			hashValue:      70,
+			replicateCount: 1,
+			expectTarget:   0,
+			expectReplicas: []int{1},
+		},
+		{
+			name:           "备份数超过其余实例数时返回全部其余实例",
+			hashValue:      30,
+			replicateCount: 5,
+			expectTarget:   0,
+			expectReplicas: []int{2, 1, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, nodes := ring.selectByHashValue(tt.hashValue, tt.replicateCount)
+			assert.Equal(t, tt.expectTarget, target)
+			if tt.expectReplicas == nil {
+				assert.Nil(t, nodes)
+				return
+			}
+			assert.Equal(t, tt.expectReplicas, nodes.Indexes)
+			assert.Equal(t, tt.replicateCount, nodes.Count)
+		})
+	}
+}
+
+func TestContinuumSelector_selectByHashValueCache(t *testing.T) {
+	ring := buildTestRing(0, 1, 2, 3)
+	_, first := ring.selectByHashValue(10, 2)
+	_, second := ring.selectByHashValue(10, 2)
+	// 备份数相同时复用缓存的备份节点
+	assert.Same(t, first, second)
+	_, third := ring.selectByHashValue(10, 3)
+	assert.NotSame(t, first, third)
+	assert.Equal(t, []int{1, 2, 3}, third.Indexes)
+}
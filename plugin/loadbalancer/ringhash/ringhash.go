@@ -18,6 +18,10 @@
 package ringhash
 
 import (
+	"math"
+	"sync"
+	"sync/atomic"
+
 	"github.com/polarismesh/polaris-go/pkg/algorithm/hash"
 	mconfig "github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -32,6 +36,8 @@ type KetamaLoadBalancer struct {
 	*plugin.PluginBase
 	cfg      *Config
 	hashFunc hash.HashFuncWithSeed
+	// 有界负载模式下，实例ID到在途请求数的映射
+	inflights sync.Map
 }
 
 // Type 插件类型
@@ -93,6 +99,9 @@ func (k *KetamaLoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
 	if err != nil {
 		return nil, model.NewSDKError(model.ErrCodeInternalError, err, "fail to build ring, err is %v", err)
 	}
+	if k.cfg.BoundedLoad {
+		return k.chooseBounded(criteria, selector.(*ContinuumSelector), targetInstances, svcInstances)
+	}
 	index, nodes, err := selector.Select(criteria)
 	if err != nil {
 		return nil, model.NewSDKError(model.ErrCodeInternalError, err, "fail to select from ring")
@@ -105,6 +114,59 @@ func (k *KetamaLoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
 	return instance, nil
 }
 
+// chooseBounded 有界负载模式下选择实例，在途请求数超过上限的实例会被跳过
+func (k *KetamaLoadBalancer) chooseBounded(criteria *loadbalancer.Criteria, selector *ContinuumSelector,
+	targetInstances *model.InstanceSet, svcInstances model.ServiceInstances) (model.Instance, error) {
+	instances := svcInstances.GetInstances()
+	var totalLoad int64
+	for _, idx := range targetInstances.GetInstances() {
+		totalLoad += atomic.LoadInt64(k.counter(instances[idx.Index].GetId()))
+	}
+	totalWeight := float64(targetInstances.TotalWeight())
+	index, err := selector.SelectBounded(criteria, func(index int) bool {
+		instance := instances[index]
+		capacity := math.Ceil(k.cfg.LoadFactor * float64(totalLoad+1) * float64(instance.GetWeight()) / totalWeight)
+		return float64(atomic.LoadInt64(k.counter(instance.GetId()))) < capacity
+	})
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeInternalError, err, "fail to select from ring")
+	}
+	instance := instances[index]
+	atomic.AddInt64(k.counter(instance.GetId()), 1)
+	return instance, nil
+}
+
+// UpdateCallResult 有界负载模式下，调用结束时减少实例的在途请求数
+func (k *KetamaLoadBalancer) UpdateCallResult(result *model.ServiceCallResult) {
+	if !k.cfg.BoundedLoad {
+		return
+	}
+	instance := result.GetCalledInstance()
+	if instance == nil {
+		return
+	}
+	value, ok := k.inflights.Load(instance.GetId())
+	if !ok {
+		return
+	}
+	counter := value.(*int64)
+	for {
+		current := atomic.LoadInt64(counter)
+		if current <= 0 || atomic.CompareAndSwapInt64(counter, current, current-1) {
+			return
+		}
+	}
+}
+
+// counter 获取实例的在途请求计数器
+func (k *KetamaLoadBalancer) counter(instanceID string) *int64 {
+	value, ok := k.inflights.Load(instanceID)
+	if !ok {
+		value, _ = k.inflights.LoadOrStore(instanceID, new(int64))
+	}
+	return value.(*int64)
+}
+
 // Destroy 销毁插件，可用于释放资源
 func (k *KetamaLoadBalancer) Destroy() error {
 	return nil
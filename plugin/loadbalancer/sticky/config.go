@@ -0,0 +1,72 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sticky
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+)
+
+const (
+	// DefaultSessionTTL 默认会话保持时间
+	DefaultSessionTTL = 30 * time.Minute
+	// DefaultMaxSessions 默认最大会话数
+	DefaultMaxSessions = 100000
+)
+
+// Config 会话保持负载均衡配置对象
+type Config struct {
+	// SessionTTL 会话保持时间，会话在该时间内没有被访问则失效
+	SessionTTL time.Duration `yaml:"sessionTTL" json:"sessionTTL"`
+	// MaxSessions 最大会话数，超过后新的会话不再保持
+	MaxSessions int `yaml:"maxSessions" json:"maxSessions"`
+	// Fallback 未携带会话标识或会话失效时使用的负载均衡算法
+	Fallback string `yaml:"fallback" json:"fallback"`
+}
+
+// Verify 检验会话保持配置
+func (c *Config) Verify() error {
+	var errs error
+	if c.SessionTTL <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("stickySession.sessionTTL must be greater than 0"))
+	}
+	if c.MaxSessions <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("stickySession.maxSessions must be greater than 0"))
+	}
+	if c.Fallback == config.DefaultLoadBalancerStickySession {
+		errs = multierror.Append(errs, fmt.Errorf("stickySession.fallback can not be stickySession"))
+	}
+	return errs
+}
+
+// SetDefault 设置会话保持默认值
+func (c *Config) SetDefault() {
+	if c.SessionTTL == 0 {
+		c.SessionTTL = DefaultSessionTTL
+	}
+	if c.MaxSessions == 0 {
+		c.MaxSessions = DefaultMaxSessions
+	}
+	if len(c.Fallback) == 0 {
+		c.Fallback = config.DefaultLoadBalancerWR
+	}
+}
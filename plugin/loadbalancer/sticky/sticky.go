@@ -0,0 +1,230 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sticky
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	lbcommon "github.com/polarismesh/polaris-go/plugin/loadbalancer/common"
+)
+
+const (
+	// 会话持久化文件名，与服务缓存位于同一目录
+	sessionFile = "sticky_session.json"
+	// 过期会话清理及持久化的周期
+	flushInterval = time.Minute
+)
+
+// session 会话与实例的绑定关系
+type session struct {
+	InstanceID string    `json:"instanceId"`
+	ExpireAt   time.Time `json:"expireAt"`
+}
+
+// LoadBalancer 会话保持负载均衡插件
+// 相同会话标识的请求在会话有效期内选择到同一个实例，绑定的实例不可用时重新选择
+type LoadBalancer struct {
+	*plugin.PluginBase
+	cfg           *Config
+	plugins       plugin.Supplier
+	persistEnable bool
+	persistDir    string
+	mutex         sync.Mutex
+	sessions      map[string]*session
+	stopCh        chan struct{}
+}
+
+// Type 插件类型
+func (l *LoadBalancer) Type() common.Type {
+	return common.TypeLoadBalancer
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (l *LoadBalancer) Name() string {
+	return config.DefaultLoadBalancerStickySession
+}
+
+// Init 初始化插件
+func (l *LoadBalancer) Init(ctx *plugin.InitContext) error {
+	l.PluginBase = plugin.NewPluginBase(ctx)
+	l.cfg = ctx.Config.GetConsumer().GetLoadbalancer().GetPluginConfig(l.Name()).(*Config)
+	l.plugins = ctx.Plugins
+	localCache := ctx.Config.GetConsumer().GetLocalCache()
+	l.persistEnable = localCache.IsPersistEnable()
+	l.persistDir = model.ReplaceHomeVar(localCache.GetPersistDir())
+	l.sessions = make(map[string]*session)
+	l.stopCh = make(chan struct{})
+	l.loadSessions()
+	go l.runFlushTask()
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (l *LoadBalancer) Destroy() error {
+	if l.stopCh != nil {
+		close(l.stopCh)
+	}
+	return nil
+}
+
+// ChooseInstance 获取单个服务实例
+func (l *LoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
+	inputInstances model.ServiceInstances) (model.Instance, error) {
+	if len(criteria.SessionKey) == 0 {
+		return l.fallbackChoose(criteria, inputInstances)
+	}
+	sessionKey := inputInstances.GetNamespace() + "#" + inputInstances.GetService() + "#" + criteria.SessionKey
+	now := time.Now()
+	if instance := l.getPinnedInstance(sessionKey, criteria.Cluster, inputInstances, now); instance != nil {
+		return instance, nil
+	}
+	instance, err := l.fallbackChoose(criteria, inputInstances)
+	if err != nil {
+		return nil, err
+	}
+	l.mutex.Lock()
+	if _, ok := l.sessions[sessionKey]; ok || len(l.sessions) < l.cfg.MaxSessions {
+		l.sessions[sessionKey] = &session{InstanceID: instance.GetId(), ExpireAt: now.Add(l.cfg.SessionTTL)}
+	}
+	l.mutex.Unlock()
+	return instance, nil
+}
+
+// getPinnedInstance 获取会话绑定的实例，实例不在可用实例集合中时返回nil
+func (l *LoadBalancer) getPinnedInstance(sessionKey string, cluster *model.Cluster,
+	inputInstances model.ServiceInstances, now time.Time) model.Instance {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	sess, ok := l.sessions[sessionKey]
+	if !ok || now.After(sess.ExpireAt) {
+		return nil
+	}
+	svcInstances := inputInstances.GetServiceClusters().GetServiceInstances()
+	targetInstances := lbcommon.SelectAvailableInstanceSet(cluster.GetClusterValue(), cluster.HasLimitedInstances,
+		cluster.IncludeHalfOpen)
+	allInstances := svcInstances.GetInstances()
+	for _, idx := range targetInstances.GetInstances() {
+		instance := allInstances[idx.Index]
+		if instance.GetId() == sess.InstanceID && instance.GetWeight() > 0 {
+			sess.ExpireAt = now.Add(l.cfg.SessionTTL)
+			return instance
+		}
+	}
+	return nil
+}
+
+// fallbackChoose 使用兜底的负载均衡算法选择实例
+func (l *LoadBalancer) fallbackChoose(criteria *loadbalancer.Criteria,
+	inputInstances model.ServiceInstances) (model.Instance, error) {
+	fallback, err := l.plugins.GetPlugin(common.TypeLoadBalancer, l.cfg.Fallback)
+	if err != nil {
+		return nil, err
+	}
+	return fallback.(loadbalancer.LoadBalancer).ChooseInstance(criteria, inputInstances)
+}
+
+// runFlushTask 定期清理过期会话并持久化
+func (l *LoadBalancer) runFlushTask() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stopCh:
+			l.flushSessions()
+			return
+		case <-ticker.C:
+			l.flushSessions()
+		}
+	}
+}
+
+// flushSessions 清理过期会话，并在开启持久化时写入本地文件
+func (l *LoadBalancer) flushSessions() {
+	now := time.Now()
+	l.mutex.Lock()
+	for key, sess := range l.sessions {
+		if now.After(sess.ExpireAt) {
+			delete(l.sessions, key)
+		}
+	}
+	if !l.persistEnable {
+		l.mutex.Unlock()
+		return
+	}
+	data, err := json.Marshal(l.sessions)
+	l.mutex.Unlock()
+	if err != nil {
+		log.GetBaseLogger().Errorf("fail to marshal sticky sessions, error %v", err)
+		return
+	}
+	if err = model.EnsureAndVerifyDir(l.persistDir); err != nil {
+		log.GetBaseLogger().Errorf("fail to verify persist dir %s, error %v", l.persistDir, err)
+		return
+	}
+	fileName := filepath.Join(l.persistDir, sessionFile)
+	tmpFileName := fileName + ".tmp"
+	if err = ioutil.WriteFile(tmpFileName, data, 0600); err != nil {
+		log.GetBaseLogger().Errorf("fail to write sticky sessions to %s, error %v", tmpFileName, err)
+		return
+	}
+	if err = os.Rename(tmpFileName, fileName); err != nil {
+		log.GetBaseLogger().Errorf("fail to rename sticky sessions file %s, error %v", tmpFileName, err)
+	}
+}
+
+// loadSessions 从本地文件加载未过期的会话
+func (l *LoadBalancer) loadSessions() {
+	if !l.persistEnable {
+		return
+	}
+	fileName := filepath.Join(l.persistDir, sessionFile)
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.GetBaseLogger().Warnf("fail to read sticky sessions from %s, error %v", fileName, err)
+		}
+		return
+	}
+	sessions := make(map[string]*session)
+	if err = json.Unmarshal(data, &sessions); err != nil {
+		log.GetBaseLogger().Warnf("fail to unmarshal sticky sessions from %s, error %v", fileName, err)
+		return
+	}
+	now := time.Now()
+	for key, sess := range sessions {
+		if now.Before(sess.ExpireAt) && len(l.sessions) < l.cfg.MaxSessions {
+			l.sessions[key] = sess
+		}
+	}
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&LoadBalancer{}, &Config{})
+}
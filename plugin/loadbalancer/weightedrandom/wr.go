@@ -31,6 +31,7 @@ import (
 type WRLoadBalancer struct {
 	*plugin.PluginBase
 	scalableRand *rand.ScalableRand
+	warmup       *lbcommon.Warmup
 }
 
 // Type 插件类型
@@ -47,6 +48,7 @@ func (g *WRLoadBalancer) Name() string {
 func (g *WRLoadBalancer) Init(ctx *plugin.InitContext) error {
 	g.PluginBase = plugin.NewPluginBase(ctx)
 	g.scalableRand = rand.NewScalableRand()
+	g.warmup = lbcommon.NewWarmup(ctx.Config.GetConsumer().GetLoadbalancer().GetWarmup())
 	return nil
 }
 
@@ -93,6 +95,10 @@ func (g *WRLoadBalancer) getSelector(totalWeight int) int {
 // 基于集群进行权重随机选择合适权重的服务实例
 func (g *WRLoadBalancer) clusterBasedSelectWeightedInstance(
 	svcInstances model.ServiceInstances, instances *model.InstanceSet) model.Instance {
+	// 存在预热期实例时按有效权重选择
+	if instance := g.warmup.SelectWeightedInstance(g.scalableRand, svcInstances, instances); instance != nil {
+		return instance
+	}
 	index := rand.SelectWeightedRandItem(g.scalableRand, instances)
 	if index >= 0 {
 		instanceIndex := instances.GetInstances()[index]
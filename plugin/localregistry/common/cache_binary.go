@@ -0,0 +1,99 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"runtime/debug"
+
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// BinaryCacheSuffix 二进制缓存文件后缀
+	BinaryCacheSuffix = ".pb"
+	// 二进制缓存文件版本
+	binaryCacheVersion byte = 1
+)
+
+// 二进制缓存文件头
+var binaryCacheMagic = []byte("PLRC")
+
+// encodeBinaryCache 编码为二进制缓存，格式为 magic|version|uvarint长度|protobuf|crc32
+func encodeBinaryCache(message proto.Message) ([]byte, error) {
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, len(binaryCacheMagic)+1+binary.MaxVarintLen64+len(payload)+crc32.Size)
+	buf = append(buf, binaryCacheMagic...)
+	buf = append(buf, binaryCacheVersion)
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], uint64(len(payload)))
+	buf = append(buf, lenBuf[:n]...)
+	buf = append(buf, payload...)
+	var crcBuf [crc32.Size]byte
+	binary.BigEndian.PutUint32(crcBuf[:], crc32.ChecksumIEEE(payload))
+	return append(buf, crcBuf[:]...), nil
+}
+
+// decodeBinaryCache 解码二进制缓存，长度或校验和不一致说明文件被截断或损坏
+func decodeBinaryCache(data []byte, message proto.Message) error {
+	if !bytes.HasPrefix(data, binaryCacheMagic) {
+		return fmt.Errorf("invalid binary cache header")
+	}
+	data = data[len(binaryCacheMagic):]
+	if len(data) == 0 || data[0] != binaryCacheVersion {
+		return fmt.Errorf("unsupported binary cache version")
+	}
+	data = data[1:]
+	size, n := binary.Uvarint(data)
+	if n <= 0 {
+		return fmt.Errorf("invalid binary cache length")
+	}
+	data = data[n:]
+	if uint64(len(data)) != size+crc32.Size {
+		return fmt.Errorf("binary cache length %d mismatch, expect %d", len(data), size+crc32.Size)
+	}
+	payload := data[:size]
+	if binary.BigEndian.Uint32(data[size:]) != crc32.ChecksumIEEE(payload) {
+		return fmt.Errorf("binary cache checksum mismatch")
+	}
+	return proto.Unmarshal(payload, message)
+}
+
+// loadBinaryCacheFile 通过mmap读取并解码二进制缓存文件
+func loadBinaryCacheFile(cacheFile string, message proto.Message) (err error) {
+	data, release, err := mmapFile(cacheFile)
+	if err != nil {
+		return err
+	}
+	defer release()
+	// 映射期间文件被其他进程截断会触发SIGBUS，转为panic后按文件损坏处理，由调用方重试
+	oldPanicOnFault := debug.SetPanicOnFault(true)
+	defer func() {
+		debug.SetPanicOnFault(oldPanicOnFault)
+		if r := recover(); r != nil {
+			err = fmt.Errorf("binary cache file %s truncated while reading: %v", cacheFile, r)
+		}
+	}()
+	return decodeBinaryCache(data, message)
+}
@@ -0,0 +1,228 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+var testSvcKey = model.ServiceEventKey{
+	ServiceKey: model.ServiceKey{Namespace: "Test", Service: "svc"},
+	Type:       model.EventInstances,
+}
+
+func newTestResponse(revision string, instanceCount int) *apiservice.DiscoverResponse {
+	resp := &apiservice.DiscoverResponse{
+		Type: apiservice.DiscoverResponse_INSTANCE,
+		Service: &apiservice.Service{
+			Namespace: &wrappers.StringValue{Value: testSvcKey.Namespace},
+			Name:      &wrappers.StringValue{Value: testSvcKey.Service},
+			Revision:  &wrappers.StringValue{Value: revision},
+		},
+	}
+	for i := 0; i < instanceCount; i++ {
+		resp.Instances = append(resp.Instances, &apiservice.Instance{
+			Id:   &wrappers.StringValue{Value: "instance-" + strconv.Itoa(i)},
+			Host: &wrappers.StringValue{Value: "127.0.0.1"},
+			Port: &wrappers.UInt32Value{Value: uint32(10000 + i)},
+		})
+	}
+	return resp
+}
+
+// TestDecodeBinaryCache 测试二进制缓存的编解码，截断、校验和不一致等损坏的文件需解码失败
+func TestDecodeBinaryCache(t *testing.T) {
+	encoded, err := encodeBinaryCache(newTestResponse("rev1", 3))
+	assert.Nil(t, err)
+	corrupt := func(f func(data []byte) []byte) []byte {
+		data := make([]byte, len(encoded))
+		copy(data, encoded)
+		return f(data)
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{
+			name: "完整的缓存",
+			data: encoded,
+		},
+		{
+			name:    "文件头不匹配",
+			data:    corrupt(func(data []byte) []byte { data[0] = 'X'; return data }),
+			wantErr: true,
+		},
+		{
+			name: "不支持的版本",
+			data: corrupt(func(data []byte) []byte {
+				data[len(binaryCacheMagic)] = binaryCacheVersion + 1
+				return data
+			}),
+			wantErr: true,
+		},
+		{
+			name:    "只有文件头",
+			data:    encoded[:len(binaryCacheMagic)+1],
+			wantErr: true,
+		},
+		{
+			name:    "截断了校验和",
+			data:    encoded[:len(encoded)-1],
+			wantErr: true,
+		},
+		{
+			name:    "截断了消息体",
+			data:    encoded[:len(encoded)/2],
+			wantErr: true,
+		},
+		{
+			name:    "末尾有多余数据",
+			data:    append(corrupt(func(data []byte) []byte { return data }), 0),
+			wantErr: true,
+		},
+		{
+			name:    "校验和不一致",
+			data:    corrupt(func(data []byte) []byte { data[len(data)-1] ^= 0xff; return data }),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &apiservice.DiscoverResponse{}
+			err := decodeBinaryCache(tt.data, resp)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, "rev1", resp.GetService().GetRevision().GetValue())
+			assert.Equal(t, 3, len(resp.GetInstances()))
+		})
+	}
+}
+
+// TestLoadBinaryCacheFile 测试直接读取的小文件以及通过mmap映射的大文件
+func TestLoadBinaryCacheFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "binary_cache")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	tests := []struct {
+		name          string
+		instanceCount int
+	}{
+		{name: "小文件直接读取", instanceCount: 1},
+		{name: "大文件通过mmap读取", instanceCount: 5000},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect := newTestResponse("rev1", tt.instanceCount)
+			encoded, err := encodeBinaryCache(expect)
+			assert.Nil(t, err)
+			cacheFile := filepath.Join(dir, strconv.Itoa(i)+BinaryCacheSuffix)
+			assert.Nil(t, ioutil.WriteFile(cacheFile, encoded, 0600))
+			resp := &apiservice.DiscoverResponse{}
+			assert.Nil(t, loadBinaryCacheFile(cacheFile, resp))
+			assert.True(t, proto.Equal(expect, resp))
+		})
+	}
+}
+
+// TestLoadPersistedServicesPrecedence 测试同一服务同时存在json与二进制缓存时以较新的文件为准
+func TestLoadPersistedServicesPrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		jsonNewer  bool
+		expectRev  string
+		expectLazy bool
+	}{
+		{name: "json文件较新", jsonNewer: true, expectRev: "json"},
+		{name: "二进制文件较新", jsonNewer: false, expectRev: "binary", expectLazy: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "persisted_services")
+			assert.Nil(t, err)
+			defer os.RemoveAll(dir)
+			fileName := ServiceEventKeyToFileName(testSvcKey)
+			jsonHandler, err := NewCachePersistHandler(true, dir, 0, 0, 0, config.PersistFormatJSON, "")
+			assert.Nil(t, err)
+			jsonHandler.SaveMessageToFile(fileName, newTestResponse("json", 1))
+			binaryHandler, err := NewCachePersistHandler(true, dir, 0, 0, 0, config.PersistFormatBinary, "")
+			assert.Nil(t, err)
+			binaryHandler.SaveMessageToFile(fileName, newTestResponse("binary", 1))
+
+			older, newer := time.Now().Add(-time.Hour), time.Now()
+			jsonTime, binaryTime := older, newer
+			if tt.jsonNewer {
+				jsonTime, binaryTime = newer, older
+			}
+			assert.Nil(t, os.Chtimes(filepath.Join(dir, fileName), jsonTime, jsonTime))
+			assert.Nil(t, os.Chtimes(filepath.Join(dir, toBinaryFileName(fileName)), binaryTime, binaryTime))
+
+			values := binaryHandler.LoadPersistedServices()
+			assert.Equal(t, 1, len(values))
+			info, ok := values[testSvcKey]
+			assert.True(t, ok)
+			msg := info.Msg
+			if tt.expectLazy {
+				assert.Nil(t, msg)
+				msg = info.Loader()
+			}
+			assert.Equal(t, tt.expectRev, msg.(*apiservice.DiscoverResponse).GetService().GetRevision().GetValue())
+		})
+	}
+}
+
+// TestLazyLoaderCorruptFile 测试二进制缓存在首次使用前被损坏时，延迟解码返回nil
+func TestLazyLoaderCorruptFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "persisted_services")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	handler, err := NewCachePersistHandler(true, dir, 0, 0, 0, config.PersistFormatBinary, "")
+	assert.Nil(t, err)
+	fileName := ServiceEventKeyToFileName(testSvcKey)
+	handler.SaveMessageToFile(fileName, newTestResponse("binary", 1))
+	values := handler.LoadPersistedServices()
+	info, ok := values[testSvcKey]
+	assert.True(t, ok)
+	binaryFile := filepath.Join(dir, toBinaryFileName(fileName))
+	data, err := ioutil.ReadFile(binaryFile)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(binaryFile, data[:len(data)-1], 0600))
+	assert.Nil(t, info.Loader())
+}
@@ -18,12 +18,15 @@
 package common
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/jsonpb"
@@ -31,6 +34,8 @@ import (
 	"github.com/hashicorp/go-multierror"
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 
+	"github.com/polarismesh/polaris-go/pkg/cachecipher"
+	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/model/pb"
@@ -53,23 +58,37 @@ type CachePersistHandler struct {
 	maxReadRetry  int
 	retryInterval time.Duration
 	marshaler     *jsonpb.Marshaler
+	// 缓存文件格式
+	format string
+	// 缓存文件加解密器，为nil时不加密
+	cipher cachecipher.CacheCipher
+	// 持久化失败的累计次数
+	saveFailures int64
 }
 
 // CacheFileInfo 文件信息
 type CacheFileInfo struct {
 	Msg      proto.Message
 	FileInfo os.FileInfo
+	// 二进制缓存延迟解码，Msg为空时通过Loader获取，解码失败返回nil
+	Loader func() proto.Message
 }
 
 // NewCachePersistHandler create persistence handler
 func NewCachePersistHandler(persistEnable bool, persistDir string, maxWriteRetry int,
-	maxReadRetry int, retryInterval time.Duration) (*CachePersistHandler, error) {
+	maxReadRetry int, retryInterval time.Duration, format string, cipherName string) (*CachePersistHandler, error) {
+	cacheCipher, err := cachecipher.GetCacheCipher(cipherName)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to init cachePersistHandler")
+	}
 	handler := &CachePersistHandler{}
+	handler.cipher = cacheCipher
 	handler.persistEnable = persistEnable
 	handler.persistDir = persistDir
 	handler.maxReadRetry = maxReadRetry
 	handler.maxWriteRetry = maxWriteRetry
 	handler.retryInterval = retryInterval
+	handler.format = format
 	if err := handler.init(); err != nil {
 		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to init cachePersistHandler")
 	}
@@ -88,13 +107,39 @@ func (cph *CachePersistHandler) init() error {
 }
 
 // LoadPersistedServices 加载目录中所有的缓存文件
+// 二进制缓存只在启动时建立索引，首次使用时才解码；同一服务同时存在两种格式时以较新的文件为准
 func (cph *CachePersistHandler) LoadPersistedServices() map[model.ServiceEventKey]CacheFileInfo {
 	cacheFiles, _ := filepath.Glob(filepath.Join(cph.persistDir, PatternGlob+CacheSuffix))
-	if len(cacheFiles) == 0 {
+	binaryFiles, _ := filepath.Glob(filepath.Join(cph.persistDir, PatternGlob+BinaryCacheSuffix))
+	if len(cacheFiles) == 0 && len(binaryFiles) == 0 {
 		return nil
 	}
-	values := make(map[model.ServiceEventKey]CacheFileInfo, len(cacheFiles))
+	values := make(map[model.ServiceEventKey]CacheFileInfo, len(cacheFiles)+len(binaryFiles))
+	for _, cacheFile := range binaryFiles {
+		svcValueKey, err := cph.fileNameToServiceEventKey(cacheFile)
+		if err != nil {
+			log.GetBaseLogger().Errorf("fail to decode the cache file name %s, error is %v", cacheFile, err)
+			continue
+		}
+		fileInfo, err := os.Stat(cacheFile)
+		if err != nil {
+			log.GetBaseLogger().Errorf("fail to stat the cache file %s, error is %v", cacheFile, err)
+			continue
+		}
+		values[*svcValueKey] = CacheFileInfo{
+			FileInfo: fileInfo,
+			Loader:   cph.lazyLoader(cacheFile),
+		}
+	}
 	for _, cacheFile := range cacheFiles {
+		if svcValueKey, err := cph.fileNameToServiceEventKey(cacheFile); err == nil {
+			if info, ok := values[*svcValueKey]; ok {
+				if fileInfo, err := os.Stat(cacheFile); err != nil ||
+					!fileInfo.ModTime().After(info.FileInfo.ModTime()) {
+					continue
+				}
+			}
+		}
 		msg := &apiservice.DiscoverResponse{}
 		svcValueKey, fileInfo, err := cph.loadCacheFromFile(cacheFile, msg)
 		if err != nil {
@@ -112,6 +157,19 @@ func (cph *CachePersistHandler) LoadPersistedServices() map[model.ServiceEventKe
 	return values
 }
 
+// lazyLoader 创建二进制缓存的延迟解码函数
+func (cph *CachePersistHandler) lazyLoader(cacheFile string) func() proto.Message {
+	return func() proto.Message {
+		msg := &apiservice.DiscoverResponse{}
+		if _, _, err := cph.loadCacheFromFile(cacheFile, msg); err != nil {
+			log.GetBaseLogger().Errorf("fail to load cache from file %s, error is %v", cacheFile, err)
+			return nil
+		}
+		sort.Sort(pb.InstSlice(msg.Instances))
+		return msg
+	}
+}
+
 // 从文件中加载服务缓存
 func (cph *CachePersistHandler) loadCacheFromFile(
 	cacheFile string, message proto.Message) (*model.ServiceEventKey, os.FileInfo, error) {
@@ -135,7 +193,7 @@ func (cph *CachePersistHandler) loadCacheFromFile(
 
 // LoadMessageFromFile 从相对文件中加载缓存
 func (cph *CachePersistHandler) LoadMessageFromFile(relativeFile string, message proto.Message) error {
-	absFile := filepath.Join(cph.persistDir, relativeFile)
+	absFile := filepath.Join(cph.persistDir, cph.toFormatFileName(relativeFile))
 	return cph.loadMessageFromAbsoluteFile(absFile, message, cph.maxReadRetry)
 }
 
@@ -146,6 +204,32 @@ func (cph *CachePersistHandler) loadMessageFromAbsoluteFile(cacheFile string, me
 	var lastErr error
 	var retryTimes int
 	for retryTimes = 0; retryTimes <= maxRetry; retryTimes++ {
+		if cph.cipher != nil {
+			err := cph.loadEncryptedFile(cacheFile, message)
+			if err == nil {
+				return nil
+			}
+			if os.IsNotExist(err) {
+				lastErr = model.NewSDKError(model.ErrCodeDiskError, err, "fail to read file cache")
+				break
+			}
+			lastErr = multierror.Prefix(err, "Fail to decrypt file cache: ")
+			time.Sleep(cph.retryInterval)
+			continue
+		}
+		if strings.HasSuffix(cacheFile, BinaryCacheSuffix) {
+			err := loadBinaryCacheFile(cacheFile, message)
+			if err == nil {
+				return nil
+			}
+			if os.IsNotExist(err) {
+				lastErr = model.NewSDKError(model.ErrCodeDiskError, err, "fail to read file cache")
+				break
+			}
+			lastErr = multierror.Prefix(err, "Fail to decode binary file cache: ")
+			time.Sleep(cph.retryInterval)
+			continue
+		}
 		cacheJson, err := os.OpenFile(cacheFile, os.O_RDONLY, 0600)
 		if err != nil {
 			lastErr = model.NewSDKError(model.ErrCodeDiskError, err, "fail to read file cache")
@@ -166,9 +250,24 @@ func (cph *CachePersistHandler) loadMessageFromAbsoluteFile(cacheFile string, me
 		fmt.Sprintf("load message from %s failed after retry %d times", cacheFile, retryTimes))
 }
 
+// loadEncryptedFile 读取并解密缓存文件，加密后无法使用mmap延迟读取，需整体读入内存
+func (cph *CachePersistHandler) loadEncryptedFile(cacheFile string, message proto.Message) error {
+	data, err := ioutil.ReadFile(cacheFile)
+	if err != nil {
+		return err
+	}
+	if data, err = cachecipher.Open(cph.cipher, data); err != nil {
+		return err
+	}
+	if strings.HasSuffix(cacheFile, BinaryCacheSuffix) {
+		return decodeBinaryCache(data, message)
+	}
+	return jsonpb.Unmarshal(bytes.NewReader(data), message)
+}
+
 // 从文件名转化为serviceKey
 func (cph *CachePersistHandler) fileNameToServiceEventKey(fileName string) (*model.ServiceEventKey, error) {
-	svcKeyFile := fileName[0 : len(fileName)-len(CacheSuffix)]
+	svcKeyFile := strings.TrimSuffix(fileName, filepath.Ext(fileName))
 	pieces := strings.Split(svcKeyFile, "#")
 	namespace, err := url.QueryUnescape(pieces[1])
 	if err != nil {
@@ -189,9 +288,19 @@ func (cph *CachePersistHandler) fileNameToServiceEventKey(fileName string) (*mod
 	return svcValueKey, nil
 }
 
-// DeleteCacheFromFile 删除缓存文件
+// DeleteCacheFromFile 删除缓存文件，同时清理另一种格式的残留文件
 func (cph *CachePersistHandler) DeleteCacheFromFile(fileName string) {
-	fileToDelete := filepath.Join(cph.persistDir, fileName)
+	formatFileName := cph.toFormatFileName(fileName)
+	for _, name := range []string{fileName, toBinaryFileName(fileName)} {
+		if name != formatFileName && model.PathExist(filepath.Join(cph.persistDir, name)) {
+			cph.deleteFile(filepath.Join(cph.persistDir, name))
+		}
+	}
+	cph.deleteFile(filepath.Join(cph.persistDir, formatFileName))
+}
+
+// 删除单个文件
+func (cph *CachePersistHandler) deleteFile(fileToDelete string) {
 	log.GetBaseLogger().Infof("Start to delete cache for %s", fileToDelete)
 	for retryTimes := 0; retryTimes <= cph.maxWriteRetry; retryTimes++ {
 		err := os.Remove(fileToDelete)
@@ -214,15 +323,21 @@ func (cph *CachePersistHandler) DeleteCacheFromFile(fileName string) {
 
 // SaveMessageToFile 按服务来进行缓存存储
 func (cph *CachePersistHandler) SaveMessageToFile(fileName string, svcResp proto.Message) {
-	fileToAdd := filepath.Join(cph.persistDir, fileName)
+	fileToAdd := filepath.Join(cph.persistDir, cph.toFormatFileName(fileName))
 	log.GetBaseLogger().Infof("Start to save cache to file %s", fileToAdd)
-	msg, err := cph.marshaler.MarshalToString(svcResp)
+	msg, err := cph.marshal(svcResp)
 	if err != nil {
+		atomic.AddInt64(&cph.saveFailures, 1)
 		log.GetBaseLogger().Warnf("Fail to marshal the service response for %s", fileToAdd)
 		return
 	}
+	if msg, err = cachecipher.Seal(cph.cipher, msg); err != nil {
+		atomic.AddInt64(&cph.saveFailures, 1)
+		log.GetBaseLogger().Warnf("Fail to encrypt the service response for %s, %v", fileToAdd, err)
+		return
+	}
 	for retryTimes := 0; retryTimes <= cph.maxWriteRetry; retryTimes++ {
-		err = cph.doWriteFile(fileToAdd, []byte(msg))
+		err = cph.doWriteFile(fileToAdd, msg)
 		if err != nil {
 			if retryTimes > 0 {
 				log.GetBaseLogger().Warnf("Fail to write cache file %s, error: %s,"+
@@ -234,6 +349,37 @@ func (cph *CachePersistHandler) SaveMessageToFile(fileName string, svcResp proto
 		}
 		time.Sleep(cph.retryInterval)
 	}
+	atomic.AddInt64(&cph.saveFailures, 1)
+}
+
+// GetSaveFailures 获取缓存持久化失败的累计次数
+func (cph *CachePersistHandler) GetSaveFailures() int64 {
+	return atomic.LoadInt64(&cph.saveFailures)
+}
+
+// 按缓存格式编码
+func (cph *CachePersistHandler) marshal(svcResp proto.Message) ([]byte, error) {
+	if cph.format == config.PersistFormatBinary {
+		return encodeBinaryCache(svcResp)
+	}
+	msg, err := cph.marshaler.MarshalToString(svcResp)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(msg), nil
+}
+
+// 将缓存文件名转换为当前格式的文件名
+func (cph *CachePersistHandler) toFormatFileName(fileName string) string {
+	if cph.format == config.PersistFormatBinary {
+		return toBinaryFileName(fileName)
+	}
+	return fileName
+}
+
+// 将json缓存文件名转换为二进制缓存文件名
+func toBinaryFileName(fileName string) string {
+	return strings.TrimSuffix(fileName, CacheSuffix) + BinaryCacheSuffix
 }
 
 // 实际写文件
@@ -0,0 +1,59 @@
+//go:build !windows
+// +build !windows
+
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"syscall"
+)
+
+// 小于该大小的缓存文件直接读入内存，映射的开销和映射期间文件被截断的风险都不值得
+const mmapMinSize = 64 * 1024
+
+// mmapFile 以只读私有方式映射文件，release后不可再访问返回的数据。
+// 映射期间文件被其他进程截断时，访问超出文件末尾的页会触发SIGBUS，调用方需通过debug.SetPanicOnFault兜底
+func mmapFile(file string) ([]byte, func(), error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Size() == 0 {
+		return nil, func() {}, nil
+	}
+	if info.Size() < mmapMinSize {
+		data, err := ioutil.ReadAll(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		return data, func() {}, nil
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() { _ = syscall.Munmap(data) }, nil
+}
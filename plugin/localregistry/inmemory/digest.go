@@ -0,0 +1,94 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"hash/fnv"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+)
+
+// revisionDigest 根据服务端为每个实例（或服务）下发的版本号计算与顺序无关的摘要，只做哈希不复制应答，
+// 摘要不同说明至少有一个实例发生了变化，无需再计算代价较高的 contentDigest，返回0表示无法计算
+func revisionDigest(message proto.Message) uint64 {
+	resp, ok := message.(*apiservice.DiscoverResponse)
+	if !ok || resp == nil {
+		return 0
+	}
+	hasher := fnv.New64a()
+	itemDigest := func(parts ...string) uint64 {
+		hasher.Reset()
+		for _, part := range parts {
+			_, _ = hasher.Write([]byte(part))
+			_, _ = hasher.Write([]byte{0})
+		}
+		return hasher.Sum64()
+	}
+	// 各条目摘要求和，与条目顺序无关
+	digest := uint64(len(resp.Instances))<<32 | uint64(len(resp.Services))
+	for _, instance := range resp.Instances {
+		digest += itemDigest(instance.GetId().GetValue(), instance.GetRevision().GetValue())
+	}
+	for _, svc := range resp.Services {
+		digest += itemDigest(svc.GetNamespace().GetValue(), svc.GetName().GetValue(), svc.GetRevision().GetValue())
+	}
+	if digest == 0 {
+		digest = 1
+	}
+	return digest
+}
+
+// contentDigest 计算服务端应答的规范化摘要，忽略应答码、服务版本号以及实例和服务列表的顺序，
+// 用于识别版本号变化但内容等价的推送（如实例重新排序），返回0表示无法计算。
+// 需要复制并序列化整个应答，仅在 revisionDigest 相同时计算
+func contentDigest(message proto.Message) uint64 {
+	resp, ok := message.(*apiservice.DiscoverResponse)
+	if !ok || resp == nil {
+		return 0
+	}
+	canonical := proto.Clone(resp).(*apiservice.DiscoverResponse)
+	canonical.Code = nil
+	canonical.Info = nil
+	if canonical.Service != nil {
+		canonical.Service.Revision = nil
+	}
+	sort.SliceStable(canonical.Instances, func(i, j int) bool {
+		return canonical.Instances[i].GetId().GetValue() < canonical.Instances[j].GetId().GetValue()
+	})
+	sort.SliceStable(canonical.Services, func(i, j int) bool {
+		left, right := canonical.Services[i], canonical.Services[j]
+		if left.GetNamespace().GetValue() != right.GetNamespace().GetValue() {
+			return left.GetNamespace().GetValue() < right.GetNamespace().GetValue()
+		}
+		return left.GetName().GetValue() < right.GetName().GetValue()
+	})
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(canonical); err != nil {
+		return 0
+	}
+	hasher := fnv.New64a()
+	_, _ = hasher.Write(buf.Bytes())
+	digest := hasher.Sum64()
+	if digest == 0 {
+		digest = 1
+	}
+	return digest
+}
@@ -0,0 +1,81 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildDigestResponse(revision string, instances ...[2]string) *apiservice.DiscoverResponse {
+	resp := &apiservice.DiscoverResponse{
+		Service: &apiservice.Service{
+			Namespace: &wrappers.StringValue{Value: "default"},
+			Name:      &wrappers.StringValue{Value: "svc"},
+			Revision:  &wrappers.StringValue{Value: revision},
+		},
+	}
+	for _, instance := range instances {
+		resp.Instances = append(resp.Instances, &apiservice.Instance{
+			Id:       &wrappers.StringValue{Value: instance[0]},
+			Host:     &wrappers.StringValue{Value: "127.0.0.1"},
+			Revision: &wrappers.StringValue{Value: instance[1]},
+		})
+	}
+	return resp
+}
+
+func TestDigest(t *testing.T) {
+	base := buildDigestResponse("v1", [2]string{"a", "r1"}, [2]string{"b", "r1"})
+	tests := []struct {
+		name         string
+		resp         *apiservice.DiscoverResponse
+		sameRevision bool
+		sameContent  bool
+	}{
+		{
+			name:         "仅服务版本号变化",
+			resp:         buildDigestResponse("v2", [2]string{"a", "r1"}, [2]string{"b", "r1"}),
+			sameRevision: true,
+			sameContent:  true,
+		},
+		{
+			name:         "实例重新排序",
+			resp:         buildDigestResponse("v2", [2]string{"b", "r1"}, [2]string{"a", "r1"}),
+			sameRevision: true,
+			sameContent:  true,
+		},
+		{
+			name: "实例版本号变化",
+			resp: buildDigestResponse("v2", [2]string{"a", "r2"}, [2]string{"b", "r1"}),
+		},
+		{
+			name: "实例减少",
+			resp: buildDigestResponse("v2", [2]string{"a", "r1"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.sameRevision, revisionDigest(tt.resp) == revisionDigest(base))
+			assert.Equal(t, tt.sameContent, contentDigest(tt.resp) == contentDigest(base))
+		})
+	}
+}
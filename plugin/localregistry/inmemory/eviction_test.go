@@ -0,0 +1,173 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// newEvictionLocalCache 创建用于测试淘汰的本地缓存，按顺序加入服务，越靠前的服务最近访问时间越早
+func newEvictionLocalCache(maxServiceCount int, services ...string) (*LocalCache, *[]string) {
+	cache := &LocalCache{
+		servicesMutex:     &sync.RWMutex{},
+		serviceWatchers:   make(map[model.ServiceEventKey]int32),
+		serviceMap:        newServiceSnapshot(),
+		serviceExpireTime: time.Minute,
+		maxServiceCount:   maxServiceCount,
+		serverServicesSet: make(map[model.ServiceKey]clusterAndInterval),
+		globalCtx:         model.NewValueContext(),
+	}
+	evicted := make([]string, 0)
+	cache.eventToCacheHandlers = map[model.EventType]CacheHandlers{
+		model.EventInstances: {
+			OnEventDeleted: func(key *model.ServiceEventKey, cacheValue interface{}) {
+				evicted = append(evicted, key.Service)
+				cache.serviceMap.Delete(*key)
+			},
+		},
+	}
+	base := time.Now().Add(-time.Hour).UnixNano()
+	for i, service := range services {
+		cache.addTestService(service, base+int64(i)*int64(time.Second))
+	}
+	return cache, &evicted
+}
+
+func (g *LocalCache) addTestService(service string, lastVisitTime int64) *CacheObject {
+	key := testEventKey(service)
+	cacheObj := newTestCacheObject(key)
+	cacheObj.lastVisitTime = lastVisitTime
+	g.serviceMap.Store(key, cacheObj)
+	return cacheObj
+}
+
+func testEventKey(service string) model.ServiceEventKey {
+	return model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: "default", Service: service},
+		Type:       model.EventInstances,
+	}
+}
+
+func testServices(count int) []string {
+	services := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		services = append(services, "svc-"+strconv.Itoa(i))
+	}
+	return services
+}
+
+// TestEvictExceededServices 测试超过最大服务数时按最近访问时间淘汰，系统服务及被订阅的服务不淘汰
+func TestEvictExceededServices(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxServiceCount int
+		serviceCount    int
+		watched         []string
+		serverServices  []string
+		evicted         []string
+	}{
+		{
+			name:            "未超过最大服务数",
+			maxServiceCount: 5,
+			serviceCount:    5,
+			evicted:         []string{},
+		},
+		{
+			name:            "不限制服务数",
+			maxServiceCount: 0,
+			serviceCount:    5,
+			evicted:         []string{},
+		},
+		{
+			name:            "淘汰最久未访问的服务",
+			maxServiceCount: 3,
+			serviceCount:    5,
+			evicted:         []string{"svc-0", "svc-1"},
+		},
+		{
+			name:            "被订阅的服务不淘汰",
+			maxServiceCount: 3,
+			serviceCount:    5,
+			watched:         []string{"svc-0"},
+			evicted:         []string{"svc-1", "svc-2"},
+		},
+		{
+			name:            "系统服务不淘汰",
+			maxServiceCount: 3,
+			serviceCount:    5,
+			serverServices:  []string{"svc-1"},
+			evicted:         []string{"svc-0", "svc-2"},
+		},
+		{
+			name:            "可淘汰的服务不足时只淘汰可淘汰的服务",
+			maxServiceCount: 1,
+			serviceCount:    3,
+			watched:         []string{"svc-0"},
+			serverServices:  []string{"svc-1"},
+			evicted:         []string{"svc-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, evicted := newEvictionLocalCache(tt.maxServiceCount, testServices(tt.serviceCount)...)
+			for _, service := range tt.watched {
+				cache.WatchService(testEventKey(service))
+			}
+			for _, service := range tt.serverServices {
+				cache.serverServicesSet[testEventKey(service).ServiceKey] = clusterAndInterval{
+					clsType: config.DiscoverCluster}
+			}
+			cache.evictExceededServices(nil)
+			assert.Equal(t, tt.evicted, *evicted)
+			for _, service := range tt.evicted {
+				_, ok := cache.serviceMap.Load(testEventKey(service))
+				assert.False(t, ok)
+			}
+		})
+	}
+}
+
+// TestEvictExceededServicesOnInsert 测试新增服务时立即淘汰，刚新增的服务即使最久未访问也不淘汰
+func TestEvictExceededServicesOnInsert(t *testing.T) {
+	cache, evicted := newEvictionLocalCache(2, testServices(2)...)
+	inserted := cache.addTestService("svc-new", time.Now().Add(-2*time.Hour).UnixNano())
+	cache.evictExceededServices(inserted)
+	assert.Equal(t, []string{"svc-0"}, *evicted)
+	_, ok := cache.serviceMap.Load(testEventKey("svc-new"))
+	assert.True(t, ok)
+	assert.Equal(t, 2, cache.serviceMap.Len())
+}
+
+// TestEvictIdleServices 测试淘汰超过过期时间未访问的服务，系统服务及被订阅的服务不淘汰
+func TestEvictIdleServices(t *testing.T) {
+	cache, evicted := newEvictionLocalCache(0, "idle", "watched", "server")
+	cache.addTestService("active", time.Now().UnixNano())
+	cache.WatchService(testEventKey("watched"))
+	cache.serverServicesSet[testEventKey("server").ServiceKey] = clusterAndInterval{clsType: config.BuiltinCluster}
+	cache.evictIdleServices()
+	assert.Equal(t, []string{"idle"}, *evicted)
+	assert.Equal(t, 3, cache.serviceMap.Len())
+}
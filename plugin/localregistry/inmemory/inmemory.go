@@ -18,6 +18,8 @@
 package inmemory
 
 import (
+	"context"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,6 +30,7 @@ import (
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
 
+	"github.com/polarismesh/polaris-go/pkg/cachecipher"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -39,12 +42,17 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
 	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
 	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/pkg/scheduler"
 	lrplug "github.com/polarismesh/polaris-go/plugin/localregistry/common"
 )
 
 const (
 	name               = "inmemory"
 	emptyReplaceHolder = "<empty>"
+	// logServiceMapInterval 打印异常缓存对象的周期
+	logServiceMapInterval = 5 * time.Minute
+	// cacheTaskJitter 缓存周期任务的调度抖动比例
+	cacheTaskJitter = 0.1
 )
 
 var (
@@ -65,16 +73,20 @@ type LocalCache struct {
 	// 这个锁的只有在服务新增或者删除时候触发，频率较小
 	servicesMutex          *sync.RWMutex
 	serviceWatchers        map[model.ServiceEventKey]int32
-	serviceMap             *sync.Map
+	serviceMap             *serviceSnapshot
 	connector              serverconnector.ServerConnector
 	serviceRefreshInterval time.Duration
 	serviceExpireTime      time.Duration
-	persistEnable          bool
-	persistDir             string
-	persistTasks           *sync.Map
-	persistTaskChan        chan struct{}
-	cachePersistHandler    *lrplug.CachePersistHandler
-	eventToCacheHandlers   map[model.EventType]CacheHandlers
+	// 本地缓存的最大服务数，0表示不限制
+	maxServiceCount int
+	// 超限淘汰的互斥锁，避免并发新增服务时重复淘汰
+	evictMutex           sync.Mutex
+	persistEnable        bool
+	persistDir           string
+	persistTasks         *sync.Map
+	persistTaskChan      chan struct{}
+	cachePersistHandler  *lrplug.CachePersistHandler
+	eventToCacheHandlers map[model.EventType]CacheHandlers
 	// 系统服务集合，用于比对本地缓存
 	serverServicesSet map[model.ServiceKey]clusterAndInterval
 	// 全局配置
@@ -94,6 +106,10 @@ type LocalCache struct {
 	pushEmptyProtection bool
 	// 缓存文件的有效时间
 	cacheFromPersistAvailableInterval time.Duration
+	// 实例元数据加解密器，未启用元数据加密时为空
+	metadataCipher cachecipher.CacheCipher
+	// 过期检查、缓存落盘等周期任务所在的调度组
+	scheduleGroup *scheduler.Group
 }
 
 // 系统服务集群及刷新间隔信息
@@ -114,6 +130,9 @@ func (g *LocalCache) Name() string {
 
 // Destroy 销毁插件
 func (g *LocalCache) Destroy() error {
+	if g.scheduleGroup != nil {
+		g.scheduleGroup.Drain(scheduler.DefaultDrainTimeout)
+	}
 	err := g.PluginBase.Destroy()
 	if err != nil {
 		return err
@@ -125,6 +144,15 @@ func (g *LocalCache) Destroy() error {
 	return nil
 }
 
+// Flush 将待写入的缓存文件立即落盘，在SDK上下文优雅销毁时调用
+func (g *LocalCache) Flush(ctx context.Context) error {
+	if g.cachePersistHandler == nil {
+		return nil
+	}
+	g.persistCacheFiles()
+	return nil
+}
+
 // 构建系统服务集合
 func (g *LocalCache) buildServerServiceSet(clsTypeToConfig map[config.ClusterType]config.ClusterService) {
 	g.serverServicesSet = make(map[model.ServiceKey]clusterAndInterval, 0)
@@ -151,19 +179,21 @@ func (g *LocalCache) Init(ctx *plugin.InitContext) error {
 	g.serviceWatchers = make(map[model.ServiceEventKey]int32, 0)
 	g.serviceRefreshInterval = ctx.Config.GetConsumer().GetLocalCache().GetServiceRefreshInterval()
 	g.serviceExpireTime = ctx.Config.GetConsumer().GetLocalCache().GetServiceExpireTime()
+	g.maxServiceCount = ctx.Config.GetConsumer().GetLocalCache().GetMaxServiceCount()
 	g.persistEnable = ctx.Config.GetConsumer().GetLocalCache().IsPersistEnable()
 	g.persistDir = model.ReplaceHomeVar(ctx.Config.GetConsumer().GetLocalCache().GetPersistDir())
 	log.GetBaseLogger().Infof("LocalCache Real persistDir:%s", g.persistDir)
 	g.persistTasks = &sync.Map{}
 	g.persistTaskChan = make(chan struct{}, 1)
 	g.connector = connectorPlugin.(serverconnector.ServerConnector)
-	g.serviceMap = &sync.Map{}
+	g.serviceMap = newServiceSnapshot()
 	g.eventToCacheHandlers = make(map[model.EventType]CacheHandlers, 0)
 	g.eventToCacheHandlers[model.EventInstances] = g.newServiceCacheHandler()
 	g.eventToCacheHandlers[model.EventRouting] = g.newRuleCacheHandler()
 	g.eventToCacheHandlers[model.EventRateLimiting] = g.newRateLimitCacheHandler()
 	g.eventToCacheHandlers[model.EventCircuitBreaker] = g.newCircuitBreakerCacheHandler()
 	g.eventToCacheHandlers[model.EventFaultDetect] = g.newFaultDetectCacheHandler()
+	g.eventToCacheHandlers[model.EventLane] = g.newLaneCacheHandler()
 	// 批量服务
 	g.eventToCacheHandlers[model.EventServices] = g.newServicesHandler()
 	g.cacheFromPersistAvailableInterval = ctx.Config.GetConsumer().GetLocalCache().GetPersistAvailableInterval()
@@ -172,10 +202,17 @@ func (g *LocalCache) Init(ctx *plugin.InitContext) error {
 		g.persistDir,
 		ctx.Config.GetConsumer().GetLocalCache().GetPersistMaxWriteRetry(),
 		ctx.Config.GetConsumer().GetLocalCache().GetPersistMaxReadRetry(),
-		ctx.Config.GetConsumer().GetLocalCache().GetPersistRetryInterval())
+		ctx.Config.GetConsumer().GetLocalCache().GetPersistRetryInterval(),
+		ctx.Config.GetConsumer().GetLocalCache().GetPersistFormat(),
+		ctx.Config.GetConsumer().GetLocalCache().GetCipher())
 	if err != nil {
 		return err
 	}
+	if encryptionCfg := ctx.Config.GetGlobal().GetMetadataEncryption(); encryptionCfg.IsEnable() {
+		if g.metadataCipher, err = cachecipher.GetCacheCipher(encryptionCfg.GetCipher()); err != nil {
+			return err
+		}
+	}
 	g.plugins = ctx.Plugins
 	g.globalCtx = ctx.ValueCtx
 	clsTypeToSvcConfigs := config.GetServerServices(ctx.Config)
@@ -192,35 +229,21 @@ func (g *LocalCache) Init(ctx *plugin.InitContext) error {
 
 // 打印有问题的cacheObject
 func (g *LocalCache) logServiceMap() {
-	logTicker := time.NewTicker(5 * time.Minute)
-	defer logTicker.Stop()
-	for {
-		select {
-		case <-g.Done():
-			log.GetBaseLogger().Infof("logServiceMap of inmemory localRegistry has been terminated")
-			return
-		case <-logTicker.C:
-			g.serviceMap.Range(func(k, v interface{}) bool {
-				svcKey := k.(model.ServiceEventKey)
-				cacheObj := v.(*CacheObject)
-				if reflect2.IsNil(cacheObj.value.Load()) {
-					log.GetBaseLogger().Warnf("%s, logServiceMap: %s cacheObject has nil value, createTime, %v,"+
-						" hasRegistered, %d", g.GetSDKContextID(), svcKey, cacheObj.createTime,
-						atomic.LoadUint32(&cacheObj.hasRegistered))
-				}
-				return true
-			})
+	g.serviceMap.Range(func(svcKey model.ServiceEventKey, cacheObj *CacheObject) bool {
+		if reflect2.IsNil(cacheObj.value.Load()) {
+			log.GetBaseLogger().Warnf("%s, logServiceMap: %s cacheObject has nil value, createTime, %v,"+
+				" hasRegistered, %d", g.GetSDKContextID(), svcKey, cacheObj.createTime,
+				atomic.LoadUint32(&cacheObj.hasRegistered))
 		}
-	}
+		return true
+	})
 }
 
 // Start 启动插件
 func (g *LocalCache) Start() error {
 	g.loadCacheFromFiles()
-	if g.persistEnable {
-		go g.eliminateExpiredCache()
-	}
-	go g.logServiceMap()
+	g.scheduleGroup = scheduler.GetScheduler().NewGroup(name)
+	g.scheduleTasks()
 	return nil
 }
 
@@ -228,12 +251,11 @@ func (g *LocalCache) Start() error {
 func (g *LocalCache) GetInstances(svcKey *model.ServiceKey, includeCache bool,
 	isInternalRequest bool) model.ServiceInstances {
 	eventKey := poolGetSvcEventKey(svcKey, model.EventInstances)
-	value, ok := g.serviceMap.Load(*eventKey)
+	cacheObj, ok := g.serviceMap.Load(*eventKey)
 	poolPutSvcEventKey(eventKey)
 	if !ok {
 		return emptyInstance
 	}
-	cacheObj := value.(*CacheObject)
 	instances := g.getInstances(cacheObj, isInternalRequest)
 	if nil == instances {
 		return emptyInstance
@@ -448,6 +470,7 @@ func (g *LocalCache) messageToServiceInstances(cachedValue interface{}, value pr
 			return newLocalValue
 		}
 	}
+	respInProto = g.decryptInstanceMetadata(respInProto)
 	svcInstances := pb.NewServiceInstancesInProto(respInProto, createLocalValueFunc, pluginValues, svcLocalValue)
 	if cacheLoaded {
 		svcInstances.CacheLoaded = 1
@@ -455,6 +478,31 @@ func (g *LocalCache) messageToServiceInstances(cachedValue interface{}, value pr
 	return svcInstances
 }
 
+// decryptInstanceMetadata 解密实例的加密元数据，存在加密值时在副本上解密，避免明文写入缓存文件
+func (g *LocalCache) decryptInstanceMetadata(resp *apiservice.DiscoverResponse) *apiservice.DiscoverResponse {
+	if g.metadataCipher == nil {
+		return resp
+	}
+	decrypted := resp
+	for i, inst := range resp.Instances {
+		metadata, changed, err := cachecipher.DecryptMetadata(g.metadataCipher, inst.GetMetadata())
+		if err != nil {
+			log.GetBaseLogger().Errorf("fail to decrypt metadata of instance %s in service %s::%s: %v",
+				inst.GetId().GetValue(), resp.GetService().GetNamespace().GetValue(),
+				resp.GetService().GetName().GetValue(), err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if decrypted == resp {
+			decrypted = proto.Clone(resp).(*apiservice.DiscoverResponse)
+		}
+		decrypted.Instances[i].Metadata = metadata
+	}
+	return decrypted
+}
+
 // 转换为北极星命名空间下的插件链
 func (g *LocalCache) toNamespacePluginValues() *pb.SvcPluginValues {
 	values := &pb.SvcPluginValues{}
@@ -516,6 +564,79 @@ func (g *LocalCache) LoadInstances(svcKey *model.ServiceKey) (*common.Notifier,
 	return g.loadRemoteValue(svcEvKey, g.eventToCacheHandlers[svcEvKey.Type])
 }
 
+// GetInstancesAge 获取服务实例缓存距最近一次服务端确认的时长
+func (g *LocalCache) GetInstancesAge(svcKey *model.ServiceKey) (time.Duration, bool) {
+	svcEvKey := model.ServiceEventKey{ServiceKey: *svcKey, Type: model.EventInstances}
+	svcObject, ok := g.serviceMap.Load(svcEvKey)
+	if !ok {
+		return 0, false
+	}
+	if reflect2.IsNil(svcObject.LoadValue(false)) || atomic.LoadInt64(&svcObject.confirmTime) == 0 {
+		return 0, false
+	}
+	return g.globalCtx.Since(svcObject.GetConfirmTime()), true
+}
+
+// ListCachedKeys 列出已加载到本地缓存中的所有资源标识
+func (g *LocalCache) ListCachedKeys() []model.ServiceEventKey {
+	keys := make([]model.ServiceEventKey, 0)
+	g.serviceMap.Range(func(key model.ServiceEventKey, cacheObj *CacheObject) bool {
+		if reflect2.IsNil(cacheObj.LoadValue(false)) {
+			return true
+		}
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// GetCacheStatistics 获取已加载实例的服务数、实例总数及持久化失败的累计次数
+func (g *LocalCache) GetCacheStatistics() (services int, instances int, persistFailures int64) {
+	g.serviceMap.Range(func(key model.ServiceEventKey, cacheObj *CacheObject) bool {
+		if key.Type != model.EventInstances {
+			return true
+		}
+		svcInstances, ok := cacheObj.LoadValue(false).(model.ServiceInstances)
+		if !ok || reflect2.IsNil(svcInstances) {
+			return true
+		}
+		services++
+		instances += len(svcInstances.GetInstances())
+		return true
+	})
+	return services, instances, g.cachePersistHandler.GetSaveFailures()
+}
+
+// RefreshInstances 尽快向服务端发起一次服务实例拉取，未监听时发起首次加载
+func (g *LocalCache) RefreshInstances(svcKey *model.ServiceKey) (*common.Notifier, error) {
+	svcEvKey := model.ServiceEventKey{ServiceKey: *svcKey, Type: model.EventInstances}
+	value, ok := g.serviceMap.Load(svcEvKey)
+	if !ok || atomic.LoadUint32(&value.hasRegistered) == 0 {
+		return g.LoadInstances(svcKey)
+	}
+	refresher, ok := serverconnector.GetServiceRefresher(g.connector)
+	if !ok {
+		return nil, model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+			"serverConnector %s not support refresh", g.connector.Name())
+	}
+	notifier := value.(*CacheObject).addRefreshNotifier()
+	if err := refresher.RefreshServiceHandler(&svcEvKey); err != nil {
+		return nil, err
+	}
+	return notifier, nil
+}
+
+// requestFullSync 增量推送无法合并时，请求连接器尽快重新拉取，此时缓存对象的版本号为空，服务端会返回全量
+func (g *LocalCache) requestFullSync(svcEvKey *model.ServiceEventKey) {
+	refresher, ok := serverconnector.GetServiceRefresher(g.connector)
+	if !ok {
+		return
+	}
+	if err := refresher.RefreshServiceHandler(svcEvKey); err != nil {
+		log.GetBaseLogger().Warnf("fail to request full sync for %s: %v", *svcEvKey, err)
+	}
+}
+
 // loadRemoteValue 通用远程查询逻辑
 func (g *LocalCache) loadRemoteValue(svcKey *model.ServiceEventKey, handler CacheHandlers) (*common.Notifier, error) {
 	if g.IsDestroyed() {
@@ -523,14 +644,14 @@ func (g *LocalCache) loadRemoteValue(svcKey *model.ServiceEventKey, handler Cach
 			"loadRemoteValue: LocalCache %s has been destroyed", name)
 	}
 
-	var actualSvcObject *CacheObject
-	value, ok := g.serviceMap.Load(svcKey)
+	actualSvcObject, ok := g.serviceMap.Load(*svcKey)
 	if !ok {
-		svcObject := NewCacheObject(handler, g, svcKey)
-		actualValue, _ := g.serviceMap.LoadOrStore(*svcKey, svcObject)
-		actualSvcObject = actualValue.(*CacheObject)
-	} else {
-		actualSvcObject = value.(*CacheObject)
+		var loaded bool
+		actualSvcObject, loaded = g.serviceMap.LoadOrStore(*svcKey, NewCacheObject(handler, g, svcKey))
+		if !loaded && g.maxServiceCount > 0 && g.serviceMap.Len() > g.maxServiceCount {
+			// 新增服务时立即执行超限淘汰，不等待过期检查的周期任务
+			g.evictExceededServices(actualSvcObject)
+		}
 	}
 
 	// 如果cas操作失败了，那么说明原本注册就是1，或者为0的时候由另一个协程设置成功了
@@ -657,12 +778,11 @@ func (g *LocalCache) GetServiceRouteRule(key *model.ServiceKey, includeCache boo
 // GetServicesByMeta 非阻塞获取服务列表
 func (g *LocalCache) GetServicesByMeta(key *model.ServiceKey, includeCache bool) model.Services {
 	svcEventKey := poolGetSvcEventKey(key, model.EventServices)
-	value, ok := g.serviceMap.Load(*svcEventKey)
+	cacheObj, ok := g.serviceMap.Load(*svcEventKey)
 	if !ok {
 		poolPutSvcEventKey(svcEventKey)
 		return pb.NewServicesProto(nil)
 	}
-	cacheObj := value.(*CacheObject)
 	ruleValue := cacheObj.LoadValue(true)
 	if reflect2.IsNil(ruleValue) {
 		poolPutSvcEventKey(svcEventKey)
@@ -688,11 +808,10 @@ func (g *LocalCache) GetServiceRateLimitRule(key *model.ServiceKey, includeCache
 
 // GetServiceRule 非阻塞获取规则信息
 func (g *LocalCache) GetServiceRule(svcEventKey *model.ServiceEventKey, includeCache bool) model.ServiceRule {
-	value, ok := g.serviceMap.Load(*svcEventKey)
+	cacheObj, ok := g.serviceMap.Load(*svcEventKey)
 	if !ok {
 		return emptyRule
 	}
-	cacheObj := value.(*CacheObject)
 	ruleValue := cacheObj.LoadValue(true)
 	if reflect2.IsNil(ruleValue) {
 		if atomic.LoadUint32(&cacheObj.hasRemoteError) > 0 {
@@ -752,6 +871,15 @@ func (g *LocalCache) newFaultDetectCacheHandler() CacheHandlers {
 	}
 }
 
+// 创建泳道规则缓存操作回调集合
+func (g *LocalCache) newLaneCacheHandler() CacheHandlers {
+	return CacheHandlers{
+		CompareMessage:      compareResource,
+		MessageToCacheValue: messageToServiceRule,
+		OnEventDeleted:      g.deleteRule,
+	}
+}
+
 // 创建批量服务回调
 func (g *LocalCache) newServicesHandler() CacheHandlers {
 	return CacheHandlers{
@@ -1032,21 +1160,29 @@ func (g *LocalCache) LoadServiceRule(svcEventKey *model.ServiceEventKey) (*commo
 func (g *LocalCache) loadCacheFromFiles() {
 	timeNow := time.Now()
 	persistedServices := g.cachePersistHandler.LoadPersistedServices()
+	loadedObjects := make(map[model.ServiceEventKey]*CacheObject, len(persistedServices))
 	for svcKey, message := range persistedServices {
 		newSvcKey := &model.ServiceEventKey{
 			ServiceKey: svcKey.ServiceKey,
 			Type:       svcKey.Type,
 		}
-		newSvcObj := NewCacheObjectWithInitValue(g.eventToCacheHandlers[newSvcKey.Type], g, newSvcKey, message.Msg)
+		var newSvcObj *CacheObject
+		if message.Msg == nil {
+			newSvcObj = NewCacheObjectWithLazyValue(g.eventToCacheHandlers[newSvcKey.Type], g, newSvcKey, message.Loader)
+		} else {
+			newSvcObj = NewCacheObjectWithInitValue(g.eventToCacheHandlers[newSvcKey.Type], g, newSvcKey, message.Msg)
+		}
+		newSvcObj.confirmTime = message.FileInfo.ModTime().UnixNano()
 		if timeNow.Sub(message.FileInfo.ModTime()) <= g.cacheFromPersistAvailableInterval {
 			newSvcObj.cachePersistentAvailable = 1
 		} else {
 			newSvcObj.cachePersistentAvailable = 0
 		}
-		g.serviceMap.Store(*newSvcKey, newSvcObj)
+		loadedObjects[*newSvcKey] = newSvcObj
 		log.GetBaseLogger().Infof("cache loaded from files, key: %v, cacheObject: %v",
 			newSvcKey, newSvcObj.serviceValueKey)
 	}
+	g.serviceMap.StoreAll(loadedObjects)
 }
 
 // 补充ServiceEventHandler的特殊字段
@@ -1071,73 +1207,145 @@ func (g *LocalCache) checkResourceWatched(resKey model.ServiceEventKey) bool {
 	return ok && v > 0
 }
 
-// 淘汰过时缓存
-func (g *LocalCache) eliminateExpiredCache() {
-	// 用于检测服务是否过期的定时器，周期为服务过期时间一半
+// scheduleTasks 添加过期缓存淘汰、缓存文件落盘及异常缓存打印的周期任务
+func (g *LocalCache) scheduleTasks() {
+	// 用于检测服务是否过期的周期为服务过期时间一半
 	checkTime := g.serviceExpireTime / 2
 	if checkTime > config.DefaultMaxServiceExpireCheckTime {
 		checkTime = config.DefaultMaxServiceExpireCheckTime
 	}
-	expireTicker := time.NewTicker(checkTime)
-	defer expireTicker.Stop()
-	// 执行缓存文件创建和删除操作的定时器，周期为config.DefaultMinTimingInterval(100ms)
-	fileTaskTicker := time.NewTicker(config.DefaultMinTimingInterval)
-	defer fileTaskTicker.Stop()
-	for {
-		select {
-		case <-g.Done():
-			log.GetBaseLogger().Infof("eliminateExpiredCache of inmemory localRegistry has been terminated")
-			return
-		case <-expireTicker.C:
-			currentTime := g.globalCtx.Now().UnixNano()
-			g.serviceMap.Range(func(k, v interface{}) bool {
-				cacheObjectValue := v.(*CacheObject)
-				svcKey := cacheObjectValue.serviceValueKey.ServiceKey
-				if _, ok := g.serverServicesSet[svcKey]; ok {
-					// 系统服务不淘汰
-					return true
-				}
-				// 如果当前时间减去最新访问时间没有超过expireTime，那么不用淘汰，继续检查下一个服务
-				lastVisitTime := atomic.LoadInt64(&cacheObjectValue.lastVisitTime)
-				diffTime := currentTime - lastVisitTime
-				if diffTime < 0 {
-					// 时间发生倒退，则直接更新最近访问时间
-					atomic.CompareAndSwapInt64(&cacheObjectValue.lastVisitTime, lastVisitTime, currentTime)
-					return true
+	// 过期淘汰只在开启持久化时执行，超限淘汰在新增服务时已执行，这里兜底淘汰新增期间并发写入的服务
+	if g.persistEnable || g.maxServiceCount > 0 {
+		g.scheduleGroup.Schedule(scheduler.Job{
+			Name:     "eliminateExpiredCache",
+			Period:   checkTime,
+			Jitter:   cacheTaskJitter,
+			Priority: scheduler.PriorityNormal,
+			Run: func() time.Duration {
+				if g.persistEnable {
+					g.evictIdleServices()
 				}
+				g.evictExceededServices(nil)
+				return 0
+			},
+		})
+	}
+	// 执行缓存文件创建和删除操作，周期为config.DefaultMinTimingInterval(100ms)
+	g.scheduleGroup.Schedule(scheduler.Job{
+		Name:     "persistCacheFiles",
+		Period:   config.DefaultMinTimingInterval,
+		Priority: scheduler.PriorityLow,
+		Run: func() time.Duration {
+			g.persistCacheFiles()
+			return 0
+		},
+	})
+	g.scheduleGroup.Schedule(scheduler.Job{
+		Name:     "logServiceMap",
+		Period:   logServiceMapInterval,
+		Jitter:   cacheTaskJitter,
+		Priority: scheduler.PriorityLow,
+		Run: func() time.Duration {
+			g.logServiceMap()
+			return 0
+		},
+	})
+}
 
-				// 该服务被订阅,不能淘汰
-				if g.checkResourceWatched(*cacheObjectValue.serviceValueKey) {
-					log.GetBaseLogger().Debugf("%s serviceIsWatched, can not expire", svcKey.String())
-					return true
-				}
-				if time.Duration(diffTime) < g.serviceExpireTime {
-					return true
-				}
-				svcEvKey := k.(model.ServiceEventKey)
-				log.GetBaseLogger().Infof("%s expired, lastVisited: %v, serviceExpireTime：%v",
-					cacheObjectValue.serviceValueKey, time.Unix(0, lastVisitTime),
-					g.serviceExpireTime)
-				oldValue := cacheObjectValue.LoadValue(false)
-				g.eventToCacheHandlers[svcEvKey.Type].OnEventDeleted(&svcEvKey, oldValue)
-				return true
-			})
-		case <-fileTaskTicker.C:
-			g.persistTasks.Range(func(k, v interface{}) bool {
-				g.persistTasks.Delete(k)
-				cacheFile := k.(string)
-				task := v.(*persistTask)
-				if addCache == task.op {
-					g.cachePersistHandler.SaveMessageToFile(cacheFile, task.protoMsg)
-				} else {
-					g.cachePersistHandler.DeleteCacheFromFile(cacheFile)
-				}
-				return true
-			})
+// persistCacheFiles 执行缓存文件的创建和删除
+func (g *LocalCache) persistCacheFiles() {
+	g.persistTasks.Range(func(k, v interface{}) bool {
+		g.persistTasks.Delete(k)
+		cacheFile := k.(string)
+		task := v.(*persistTask)
+		if addCache == task.op {
+			g.cachePersistHandler.SaveMessageToFile(cacheFile, task.protoMsg)
+		} else {
+			g.cachePersistHandler.DeleteCacheFromFile(cacheFile)
+		}
+		return true
+	})
+}
+
+// evictIdleServices 淘汰超过serviceExpireTime未被访问的服务
+func (g *LocalCache) evictIdleServices() {
+	currentTime := g.globalCtx.Now().UnixNano()
+	g.serviceMap.Range(func(svcKey model.ServiceEventKey, cacheObjectValue *CacheObject) bool {
+		if !g.isEvictable(cacheObjectValue) {
+			return true
+		}
+		// 如果当前时间减去最新访问时间没有超过expireTime，那么不用淘汰，继续检查下一个服务
+		lastVisitTime := atomic.LoadInt64(&cacheObjectValue.lastVisitTime)
+		diffTime := currentTime - lastVisitTime
+		if diffTime < 0 {
+			// 时间发生倒退，则直接更新最近访问时间
+			atomic.CompareAndSwapInt64(&cacheObjectValue.lastVisitTime, lastVisitTime, currentTime)
+			return true
+		}
+		if time.Duration(diffTime) < g.serviceExpireTime {
+			return true
+		}
+		log.GetBaseLogger().Infof("%s expired, lastVisited: %v, serviceExpireTime：%v",
+			cacheObjectValue.serviceValueKey, time.Unix(0, lastVisitTime),
+			g.serviceExpireTime)
+		g.evictService(svcKey, cacheObjectValue)
+		return true
+	})
+}
+
+// evictExceededServices 缓存数超过maxServiceCount时，按最近访问时间淘汰最久未访问的服务，
+// inserted为刚新增的服务，不参与本次淘汰，避免调用方等待的服务被立即淘汰
+func (g *LocalCache) evictExceededServices(inserted *CacheObject) {
+	if g.maxServiceCount <= 0 {
+		return
+	}
+	g.evictMutex.Lock()
+	defer g.evictMutex.Unlock()
+	var total int
+	candidates := make([]*CacheObject, 0)
+	g.serviceMap.Range(func(_ model.ServiceEventKey, cacheObjectValue *CacheObject) bool {
+		total++
+		if cacheObjectValue != inserted && g.isEvictable(cacheObjectValue) {
+			candidates = append(candidates, cacheObjectValue)
 		}
+		return true
+	})
+	exceeded := total - g.maxServiceCount
+	if exceeded <= 0 {
+		return
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return atomic.LoadInt64(&candidates[i].lastVisitTime) < atomic.LoadInt64(&candidates[j].lastVisitTime)
+	})
+	if exceeded > len(candidates) {
+		exceeded = len(candidates)
+	}
+	for _, cacheObjectValue := range candidates[:exceeded] {
+		log.GetBaseLogger().Infof("%s evicted, cache size %d exceeds maxServiceCount %d",
+			cacheObjectValue.serviceValueKey, total, g.maxServiceCount)
+		g.evictService(*cacheObjectValue.serviceValueKey, cacheObjectValue)
 	}
 }
 
+// isEvictable 系统服务及被订阅的服务不能淘汰
+func (g *LocalCache) isEvictable(cacheObjectValue *CacheObject) bool {
+	svcKey := cacheObjectValue.serviceValueKey.ServiceKey
+	if _, ok := g.serverServicesSet[svcKey]; ok {
+		return false
+	}
+	if g.checkResourceWatched(*cacheObjectValue.serviceValueKey) {
+		log.GetBaseLogger().Debugf("%s serviceIsWatched, can not expire", svcKey.String())
+		return false
+	}
+	return true
+}
+
+// evictService 淘汰服务缓存，同时注销监听并删除缓存文件
+func (g *LocalCache) evictService(svcEvKey model.ServiceEventKey, cacheObjectValue *CacheObject) {
+	oldValue := cacheObjectValue.LoadValue(false)
+	g.eventToCacheHandlers[svcEvKey.Type].OnEventDeleted(&svcEvKey, oldValue)
+}
+
 // PersistMessage 对PB缓存进行持久化
 func (g *LocalCache) PersistMessage(file string, message proto.Message) error {
 	if g.persistEnable {
@@ -18,11 +18,13 @@
 package inmemory
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/modern-go/reflect2"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 
 	"github.com/polarismesh/polaris-go/pkg/clock"
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -139,6 +141,24 @@ type CacheObject struct {
 	cachePersistentAvailable uint32
 	// 是否为远程服务端出现错误无法获取数据
 	hasRemoteError uint32
+	// 增量推送无法合并时，下次请求不带版本号以拉取全量
+	forceFullSync uint32
+	// 延迟解码的缓存文件加载函数，首次读取缓存值时执行
+	lazyLoader func() proto.Message
+	lazyOnce   sync.Once
+	// 缓存值最近一次被服务端确认的时间，持久化缓存为文件的修改时间
+	confirmTime int64
+	// 等待下一次服务端应答的刷新通知
+	refreshMutex     sync.Mutex
+	refreshNotifiers []*common.Notifier
+	// 当前缓存值对应服务端应答的实例版本号摘要，0表示未知
+	revisionDigest uint64
+	// 当前缓存值对应服务端应答的规范化摘要，0表示尚未计算，在实例版本号摘要相同时由 contentMessage 计算
+	contentDigest uint64
+	// 当前缓存值对应的服务端应答，用于延迟计算 contentDigest
+	contentMessage atomic.Value
+	// 与当前缓存值内容等价的服务端最新版本号，内容未变化时仅更新版本号，不重建缓存也不通知监听者
+	equivalentRevision atomic.Value
 }
 
 // NewCacheObject 创建缓存对象
@@ -179,6 +199,23 @@ func NewCacheObjectWithInitValue(handler CacheHandlers, registry *LocalCache,
 	return cacheObject
 }
 
+// NewCacheObjectWithLazyValue 创建初始值延迟解码的缓存对象
+func NewCacheObjectWithLazyValue(handler CacheHandlers, registry *LocalCache,
+	serviceValueKey *model.ServiceEventKey, loader func() proto.Message) *CacheObject {
+	cacheObject := NewCacheObject(handler, registry, serviceValueKey)
+	cacheObject.lazyLoader = loader
+	return cacheObject
+}
+
+// 解码缓存文件并设置初始值
+func (s *CacheObject) doLazyLoad() {
+	message := s.lazyLoader()
+	if reflect2.IsNil(message) || !reflect2.IsNil(s.value.Load()) {
+		return
+	}
+	s.SetValue(s.Handler.MessageToCacheValue(nil, message, s.svcLocalValue, true))
+}
+
 // MakeInValid 将本缓存值为不可用，只用于首次请求时，向后端connector监听失败的场景
 func (s *CacheObject) MakeInValid(err model.SDKError) {
 	if atomic.CompareAndSwapUint32(&s.inValid, 0, 1) {
@@ -203,9 +240,17 @@ func (s *CacheObject) isValueAvailable() bool {
 // LoadValue 判断缓存值是否可读取
 func (s *CacheObject) LoadValue(updateVisitTime bool) interface{} {
 	if updateVisitTime {
-		atomic.StoreInt64(&s.lastVisitTime, clock.GetClock().Now().UnixNano())
+		// 全局时钟按步长更新，时间未变化时不写入，避免并发读取时反复写同一缓存行
+		now := clock.GetClock().Now().UnixNano()
+		if atomic.LoadInt64(&s.lastVisitTime) != now {
+			atomic.StoreInt64(&s.lastVisitTime, now)
+		}
 	}
 	value := s.value.Load()
+	if reflect2.IsNil(value) && s.lazyLoader != nil {
+		s.lazyOnce.Do(s.doLazyLoad)
+		value = s.value.Load()
+	}
 	if reflect2.IsNil(value) {
 		return nil
 	}
@@ -271,9 +316,43 @@ func (s *CacheObject) OnServiceUpdate(event *serverconnector.ServiceEvent) {
 			atomic.StoreUint32(&s.hasRemoteError, 1)
 		}
 	} else {
+		atomic.StoreInt64(&s.confirmTime, clock.GetClock().Now().UnixNano())
 		message := event.Value
 		cachedValue := s.LoadValue(false)
+		if resp, ok := message.(*apiservice.DiscoverResponse); ok && event.Delta {
+			var baseValue *pb.ServiceInstancesInProto
+			if !reflect2.IsNil(cachedValue) {
+				baseValue = cachedValue.(*pb.ServiceInstancesInProto)
+			}
+			merged, mergeErr := pb.MergeDeltaInstances(baseValue, s.GetRevision(), resp)
+			if mergeErr != nil {
+				// 缓存未更新，不通知等待者，由全量应答到达后再通知
+				log.GetBaseLogger().Warnf("OnServiceUpdate: fail to merge delta for %s, %v, "+
+					"pending to full sync", *svcEventKey, mergeErr)
+				atomic.StoreUint32(&s.forceFullSync, 1)
+				if s.registry != nil {
+					s.registry.requestFullSync(svcEventKey)
+				}
+				return
+			}
+			message = merged
+		}
+		atomic.StoreUint32(&s.forceFullSync, 0)
 		cachedStatus := s.Handler.CompareMessage(cachedValue, message)
+		// 实例及服务列表的版本号变化时，内容可能只是重新排序，通过规范化摘要识别后不重建缓存也不通知监听者
+		var revDigest uint64
+		if (cachedStatus == CacheChanged || cachedStatus == CacheAdded) &&
+			(event.Type == model.EventInstances || event.Type == model.EventServices) {
+			revDigest = revisionDigest(message)
+		}
+		if cachedStatus == CacheChanged && !reflect2.IsNil(cachedValue) && revDigest != 0 &&
+			s.isEquivalentContent(message, revDigest) {
+			revision := message.(*apiservice.DiscoverResponse).GetService().GetRevision().GetValue()
+			log.GetBaseLogger().Debugf("OnServiceUpdate: content of %s is not changed, "+
+				"only revision updated to %s", *svcEventKey, revision)
+			s.equivalentRevision.Store(revision)
+			cachedStatus = CacheNotChanged
+		}
 		if reflect2.IsNil(cachedValue) || cachedStatus == CacheChanged || cachedStatus == CacheAdded ||
 			cachedStatus == CacheDeleted {
 			log.GetBaseLogger().Infof(
@@ -282,6 +361,9 @@ func (s *CacheObject) OnServiceUpdate(event *serverconnector.ServiceEvent) {
 			_ = s.registry.PersistMessage(svcCacheFile, message)
 			cacheValue := s.Handler.MessageToCacheValue(cachedValue, message, s.svcLocalValue, false)
 			s.SetValue(cacheValue)
+			atomic.StoreUint64(&s.revisionDigest, revDigest)
+			atomic.StoreUint64(&s.contentDigest, 0)
+			s.contentMessage.Store(contentHolder{message: message})
 			eventObject := &common.ServiceEventObject{SvcEventKey: *svcEventKey,
 				OldValue: cachedValue, NewValue: cacheValue}
 			s.notifyEventHandlers(eventObject, cachedStatus)
@@ -295,14 +377,75 @@ func (s *CacheObject) OnServiceUpdate(event *serverconnector.ServiceEvent) {
 		}
 	}
 	s.notifier.Notify(err)
+	s.notifyRefreshed(err)
+}
+
+// contentHolder 包装服务端应答，保证存入 atomic.Value 的类型一致
+type contentHolder struct {
+	message proto.Message
+}
+
+// isEquivalentContent 判断推送与当前缓存值内容是否等价。先比较服务端下发的版本号：
+// 推送的版本号与已确认等价的版本号相同时直接返回，实例版本号摘要不同时说明内容已变化，
+// 仅在实例版本号摘要相同时才计算并比较规范化摘要
+func (s *CacheObject) isEquivalentContent(message proto.Message, revDigest uint64) bool {
+	revision := message.(*apiservice.DiscoverResponse).GetService().GetRevision().GetValue()
+	if equivalent, ok := s.equivalentRevision.Load().(string); ok && len(equivalent) > 0 && equivalent == revision {
+		return true
+	}
+	if revDigest != atomic.LoadUint64(&s.revisionDigest) {
+		return false
+	}
+	cachedDigest := atomic.LoadUint64(&s.contentDigest)
+	if cachedDigest == 0 {
+		holder, ok := s.contentMessage.Load().(contentHolder)
+		if !ok {
+			return false
+		}
+		cachedDigest = contentDigest(holder.message)
+		atomic.StoreUint64(&s.contentDigest, cachedDigest)
+	}
+	digest := contentDigest(message)
+	return digest != 0 && digest == cachedDigest
+}
+
+// addRefreshNotifier 添加一个在下一次服务端应答后触发的通知
+func (s *CacheObject) addRefreshNotifier() *common.Notifier {
+	notifier := common.NewNotifier()
+	s.refreshMutex.Lock()
+	s.refreshNotifiers = append(s.refreshNotifiers, notifier)
+	s.refreshMutex.Unlock()
+	return notifier
+}
+
+// notifyRefreshed 触发并清空刷新通知
+func (s *CacheObject) notifyRefreshed(err model.SDKError) {
+	s.refreshMutex.Lock()
+	notifiers := s.refreshNotifiers
+	s.refreshNotifiers = nil
+	s.refreshMutex.Unlock()
+	for _, notifier := range notifiers {
+		notifier.Notify(err)
+	}
+}
+
+// GetConfirmTime 获取缓存值最近一次被服务端确认的时间
+func (s *CacheObject) GetConfirmTime() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&s.confirmTime))
 }
 
 // GetRevision 获取服务对象的版本号
 func (s *CacheObject) GetRevision() string {
+	if atomic.LoadUint32(&s.forceFullSync) == 1 {
+		return ""
+	}
 	value := s.LoadValue(false)
 	if nil == value {
 		return ""
 	}
+	if revision, ok := s.equivalentRevision.Load().(string); ok && len(revision) > 0 {
+		return revision
+	}
 	svcValue := value.(model.RegistryValue)
 	return svcValue.GetRevision()
 }
@@ -310,6 +453,7 @@ func (s *CacheObject) GetRevision() string {
 // SetValue 设置缓存对象
 func (s *CacheObject) SetValue(cacheValue model.RegistryValue) {
 	s.value.Store(cacheValue)
+	s.equivalentRevision.Store("")
 	log.GetBaseLogger().Infof(
 		"CacheObject: value for %s is updated, revision %s", *s.serviceValueKey, cacheValue.GetRevision())
 }
@@ -0,0 +1,100 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+func benchCacheObject() *CacheObject {
+	key := model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: "default", Service: "svc"},
+		Type:       model.EventInstances,
+	}
+	cacheObj := &CacheObject{serviceValueKey: &key}
+	cacheObj.value.Store(emptyInstance)
+	return cacheObj
+}
+
+// BenchmarkCacheObject_LoadValue 测试更新访问时间的并发读取，时间未变化时不写入
+func BenchmarkCacheObject_LoadValue(b *testing.B) {
+	cacheObj := benchCacheObject()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			cacheObj.LoadValue(true)
+		}
+	})
+}
+
+// BenchmarkCacheObject_LoadValueAlwaysStore 测试每次读取都写入访问时间的并发读取，作为对照
+func BenchmarkCacheObject_LoadValueAlwaysStore(b *testing.B) {
+	cacheObj := benchCacheObject()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			atomic.StoreInt64(&cacheObj.lastVisitTime, clock.GetClock().Now().UnixNano())
+			cacheObj.value.Load()
+		}
+	})
+}
+
+// TestCacheObjectDeltaMergeFail 测试增量推送无法合并时转为全量拉取，且不以成功通知等待者
+func TestCacheObjectDeltaMergeFail(t *testing.T) {
+	cacheObj := benchCacheObject()
+	cacheObj.notifier = common.NewNotifier()
+	delta := &apiservice.DiscoverResponse{
+		Type: apiservice.DiscoverResponse_INSTANCE,
+		Service: &apiservice.Service{
+			Revision: &wrappers.StringValue{Value: "rev2"},
+			Metadata: map[string]string{pb.DeltaMetaKey: "true", pb.DeltaBaseRevisionMetaKey: "rev1"},
+		},
+	}
+	cacheObj.OnServiceUpdate(&serverconnector.ServiceEvent{
+		ServiceEventKey: *cacheObj.serviceValueKey,
+		Value:           delta,
+		Delta:           true,
+	})
+	assert.Equal(t, uint32(1), atomic.LoadUint32(&cacheObj.forceFullSync))
+	// 版本号为空，下次拉取时服务端返回全量
+	assert.Equal(t, "", cacheObj.GetRevision())
+	assert.Same(t, emptyInstance, cacheObj.LoadValue(false))
+	select {
+	case <-cacheObj.notifier.GetContext().Done():
+		t.Fatal("waiters should not be notified before full sync")
+	default:
+	}
+}
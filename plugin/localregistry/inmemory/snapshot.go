@@ -0,0 +1,166 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// snapshotOp 一次待合并写入快照的变更
+type snapshotOp struct {
+	key   model.ServiceEventKey
+	value *CacheObject
+	// deleted 是否为删除操作
+	deleted bool
+	// onlyAbsent 仅在不存在时保存，对应LoadOrStore
+	onlyAbsent bool
+	// actual 变更生效后key对应的缓存对象，loaded表示是否为已有的对象
+	actual *CacheObject
+	loaded bool
+	// applied 是否已被写入快照，由持有flushMutex的协程读写
+	applied bool
+}
+
+// serviceSnapshot 缓存对象索引，读取时原子加载不可变的快照，写入时复制后整体替换。
+// 缓存对象的增删频率远低于读取，读路径上没有任何锁，避免多核高并发读取时的竞争。
+// 并发的写入先进入待写队列，由抢到flushMutex的协程一次复制快照并合并所有待写变更，
+// 避免首次批量加载或批量淘汰时每次写入都复制整个快照
+type serviceSnapshot struct {
+	// 待写队列的互斥锁
+	pendingMutex sync.Mutex
+	pending      []*snapshotOp
+	// 合并写入的互斥锁，保证复制与替换的原子性
+	flushMutex sync.Mutex
+	// 当前快照，类型为map[model.ServiceEventKey]*CacheObject，发布后不再修改
+	snapshot atomic.Value
+}
+
+// newServiceSnapshot 创建空的缓存对象索引
+func newServiceSnapshot() *serviceSnapshot {
+	s := &serviceSnapshot{}
+	s.snapshot.Store(map[model.ServiceEventKey]*CacheObject{})
+	return s
+}
+
+func (s *serviceSnapshot) current() map[model.ServiceEventKey]*CacheObject {
+	return s.snapshot.Load().(map[model.ServiceEventKey]*CacheObject)
+}
+
+// Load 获取缓存对象
+func (s *serviceSnapshot) Load(key model.ServiceEventKey) (*CacheObject, bool) {
+	value, ok := s.current()[key]
+	return value, ok
+}
+
+// LoadOrStore 已存在时返回已有的缓存对象，否则保存并返回传入的对象
+func (s *serviceSnapshot) LoadOrStore(key model.ServiceEventKey, value *CacheObject) (*CacheObject, bool) {
+	if actual, ok := s.Load(key); ok {
+		return actual, true
+	}
+	op := &snapshotOp{key: key, value: value, onlyAbsent: true}
+	s.write(op)
+	return op.actual, op.loaded
+}
+
+// Store 保存缓存对象
+func (s *serviceSnapshot) Store(key model.ServiceEventKey, value *CacheObject) {
+	s.write(&snapshotOp{key: key, value: value})
+}
+
+// StoreAll 批量保存缓存对象，只复制一次快照
+func (s *serviceSnapshot) StoreAll(values map[model.ServiceEventKey]*CacheObject) {
+	if len(values) == 0 {
+		return
+	}
+	ops := make([]*snapshotOp, 0, len(values))
+	for key, value := range values {
+		ops = append(ops, &snapshotOp{key: key, value: value})
+	}
+	s.write(ops...)
+}
+
+// Delete 删除缓存对象
+func (s *serviceSnapshot) Delete(key model.ServiceEventKey) {
+	if _, ok := s.Load(key); !ok {
+		return
+	}
+	s.write(&snapshotOp{key: key, deleted: true})
+}
+
+// Len 当前快照中的缓存对象数
+func (s *serviceSnapshot) Len() int {
+	return len(s.current())
+}
+
+// Range 遍历调用时的快照，遍历期间的增删不影响本次遍历，f返回false时停止
+func (s *serviceSnapshot) Range(f func(key model.ServiceEventKey, value *CacheObject) bool) {
+	for key, value := range s.current() {
+		if !f(key, value) {
+			return
+		}
+	}
+}
+
+// write 将变更放入待写队列并等待生效，已被其他协程合并写入时直接返回
+func (s *serviceSnapshot) write(ops ...*snapshotOp) {
+	s.pendingMutex.Lock()
+	s.pending = append(s.pending, ops...)
+	s.pendingMutex.Unlock()
+
+	s.flushMutex.Lock()
+	defer s.flushMutex.Unlock()
+	// 同一批变更总是一起被取出，检查最后一个即可
+	if ops[len(ops)-1].applied {
+		return
+	}
+	s.pendingMutex.Lock()
+	batch := s.pending
+	s.pending = nil
+	s.pendingMutex.Unlock()
+	s.flush(batch)
+}
+
+// flush 复制一次当前快照，按顺序合并一批变更后整体替换，调用方需持有flushMutex
+func (s *serviceSnapshot) flush(batch []*snapshotOp) {
+	current := s.current()
+	next := make(map[model.ServiceEventKey]*CacheObject, len(current)+len(batch))
+	for key, value := range current {
+		next[key] = value
+	}
+	for _, op := range batch {
+		switch {
+		case op.deleted:
+			delete(next, op.key)
+		case op.onlyAbsent:
+			if actual, ok := next[op.key]; ok {
+				op.actual, op.loaded = actual, true
+			} else {
+				next[op.key] = op.value
+				op.actual = op.value
+			}
+		default:
+			next[op.key] = op.value
+			op.actual = op.value
+		}
+		op.applied = true
+	}
+	s.snapshot.Store(next)
+}
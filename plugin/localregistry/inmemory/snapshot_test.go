@@ -0,0 +1,202 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const benchServiceCount = 1000
+
+func benchKeys() []model.ServiceEventKey {
+	keys := make([]model.ServiceEventKey, 0, benchServiceCount)
+	for i := 0; i < benchServiceCount; i++ {
+		keys = append(keys, model.ServiceEventKey{
+			ServiceKey: model.ServiceKey{Namespace: "default", Service: "svc-" + strconv.Itoa(i)},
+			Type:       model.EventInstances,
+		})
+	}
+	return keys
+}
+
+func newTestCacheObject(key model.ServiceEventKey) *CacheObject {
+	cacheObj := &CacheObject{serviceValueKey: &key, hasRemoteUpdated: 1}
+	cacheObj.value.Store(emptyInstance)
+	return cacheObj
+}
+
+// TestServiceSnapshot 测试快照索引的增删及遍历
+func TestServiceSnapshot(t *testing.T) {
+	keys := benchKeys()[:3]
+	snapshot := newServiceSnapshot()
+	first := newTestCacheObject(keys[0])
+	actual, loaded := snapshot.LoadOrStore(keys[0], first)
+	assert.False(t, loaded)
+	assert.Same(t, first, actual)
+	actual, loaded = snapshot.LoadOrStore(keys[0], newTestCacheObject(keys[0]))
+	assert.True(t, loaded)
+	assert.Same(t, first, actual)
+
+	snapshot.StoreAll(map[model.ServiceEventKey]*CacheObject{
+		keys[1]: newTestCacheObject(keys[1]),
+		keys[2]: newTestCacheObject(keys[2]),
+	})
+	var count int
+	snapshot.Range(func(key model.ServiceEventKey, value *CacheObject) bool {
+		// 遍历期间删除不影响本次遍历
+		snapshot.Delete(key)
+		count++
+		return true
+	})
+	assert.Equal(t, 3, count)
+	for _, key := range keys {
+		_, ok := snapshot.Load(key)
+		assert.False(t, ok)
+	}
+}
+
+// TestServiceSnapshotConcurrentWrites 测试并发写入合并后全部生效，且LoadOrStore只有一个对象胜出
+func TestServiceSnapshotConcurrentWrites(t *testing.T) {
+	keys := benchKeys()
+	snapshot := newServiceSnapshot()
+	const writers = 8
+	winners := make([]*CacheObject, writers)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			for j := idx; j < len(keys); j += writers {
+				snapshot.Store(keys[j], newTestCacheObject(keys[j]))
+			}
+			winners[idx], _ = snapshot.LoadOrStore(keys[0], newTestCacheObject(keys[0]))
+		}(i)
+	}
+	wg.Wait()
+	var count int
+	snapshot.Range(func(key model.ServiceEventKey, value *CacheObject) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, len(keys), count)
+	for i := 1; i < writers; i++ {
+		assert.Same(t, winners[0], winners[i])
+	}
+	assert.Equal(t, 0, len(snapshot.pending))
+}
+
+// newBenchLocalCache 创建只包含服务索引的本地缓存，用于测试读路径
+func newBenchLocalCache(keys []model.ServiceEventKey) *LocalCache {
+	values := make(map[model.ServiceEventKey]*CacheObject, len(keys))
+	for _, key := range keys {
+		values[key] = newTestCacheObject(key)
+	}
+	cache := &LocalCache{serviceMap: newServiceSnapshot()}
+	cache.serviceMap.StoreAll(values)
+	return cache
+}
+
+// syncMapGetInstances 改造前基于sync.Map的GetInstances读路径，作为对照
+func syncMapGetInstances(serviceMap *sync.Map, svcKey *model.ServiceKey) model.ServiceInstances {
+	eventKey := poolGetSvcEventKey(svcKey, model.EventInstances)
+	value, ok := serviceMap.Load(*eventKey)
+	poolPutSvcEventKey(eventKey)
+	if !ok {
+		return emptyInstance
+	}
+	cacheObj := value.(*CacheObject)
+	instances := cacheObj.LoadValue(true)
+	if nil == instances || atomic.LoadUint32(&cacheObj.hasRemoteUpdated) == 0 {
+		return emptyInstance
+	}
+	return instances.(model.ServiceInstances)
+}
+
+// BenchmarkLocalCache_GetInstances 测试基于快照索引的GetInstances并发读取
+func BenchmarkLocalCache_GetInstances(b *testing.B) {
+	keys := benchKeys()
+	cache := newBenchLocalCache(keys)
+	var seq uint32
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(atomic.AddUint32(&seq, 1))
+		for pb.Next() {
+			cache.GetInstances(&keys[i%len(keys)].ServiceKey, false, false)
+			i++
+		}
+	})
+}
+
+// BenchmarkSyncMap_GetInstances 测试改造前基于sync.Map的GetInstances并发读取，作为对照
+func BenchmarkSyncMap_GetInstances(b *testing.B) {
+	keys := benchKeys()
+	serviceMap := &sync.Map{}
+	for _, key := range keys {
+		serviceMap.Store(key, newTestCacheObject(key))
+	}
+	var seq uint32
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(atomic.AddUint32(&seq, 1))
+		for pb.Next() {
+			syncMapGetInstances(serviceMap, &keys[i%len(keys)].ServiceKey)
+			i++
+		}
+	})
+}
+
+// BenchmarkServiceSnapshot_ParallelStore 测试并发写入时合并复制快照的开销
+func BenchmarkServiceSnapshot_ParallelStore(b *testing.B) {
+	keys := benchKeys()
+	snapshot := newServiceSnapshot()
+	var seq uint32
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(atomic.AddUint32(&seq, 1))
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			snapshot.Store(key, newTestCacheObject(key))
+			i++
+		}
+	})
+}
+
+// BenchmarkSyncMap_ParallelStore 测试改造前基于sync.Map的并发写入，作为对照
+func BenchmarkSyncMap_ParallelStore(b *testing.B) {
+	keys := benchKeys()
+	serviceMap := &sync.Map{}
+	var seq uint32
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(atomic.AddUint32(&seq, 1))
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			serviceMap.Store(key, newTestCacheObject(key))
+			i++
+		}
+	})
+}
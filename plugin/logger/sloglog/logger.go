@@ -0,0 +1,232 @@
+//go:build go1.21
+// +build go1.21
+
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sloglog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/natefinch/lumberjack"
+
+	plog "github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// slog 中没有 trace 与 fatal 级别，分别映射到 debug 之下与 error 之上
+const (
+	levelTrace = slog.LevelDebug - 4
+	levelFatal = slog.LevelError + 4
+)
+
+// 使用标准库 slog 的log实现
+type slogLogger struct {
+	// outputLevel 通过 With 派生出的日志对象共享同一个日志级别
+	outputLevel *int32
+	logger      *slog.Logger
+	logDir      string
+}
+
+// NewLogger 将应用自身的 slog.Logger 适配为 SDK 的日志对象，level 为初始的日志打印级别
+func NewLogger(logger *slog.Logger, level int) plog.StructuredLogger {
+	outputLevel := int32(getOutputLevel(level, plog.DefaultLogLevel))
+	return &slogLogger{
+		outputLevel: &outputLevel,
+		logger:      logger,
+	}
+}
+
+// 归一化日志级别
+func getOutputLevel(level int, defaultLevel int) int {
+	if level >= plog.NoneLog {
+		return plog.NoneLog
+	}
+	if level < 0 {
+		return defaultLevel
+	}
+	return level
+}
+
+// 配置 slog 日志，以 JSON 格式输出到滚动日志文件
+func prepareSlog(name string, options *plog.Options, defaultLevel int) (plog.Logger, error) {
+	writers := make([]io.Writer, 0, 2)
+	if len(options.RotateOutputPath) > 0 {
+		writers = append(writers, &lumberjack.Logger{
+			Filename:   options.RotateOutputPath,
+			MaxSize:    options.RotationMaxSize,
+			MaxBackups: options.RotationMaxBackups,
+			MaxAge:     options.RotationMaxAge,
+			LocalTime:  true,
+		})
+	}
+	for _, path := range options.OutputPaths {
+		switch path {
+		case "stdout":
+			writers = append(writers, os.Stdout)
+		case "stderr":
+			writers = append(writers, os.Stderr)
+		default:
+			file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				return nil, err
+			}
+			writers = append(writers, file)
+		}
+	}
+	handler := slog.NewJSONHandler(io.MultiWriter(writers...), &slog.HandlerOptions{
+		AddSource: true,
+		Level:     levelTrace,
+	})
+	outputLevel := int32(getOutputLevel(options.LogLevel, defaultLevel))
+	return &slogLogger{
+		outputLevel: &outputLevel,
+		logger:      slog.New(handler).With("scope", name),
+		logDir:      filepath.Dir(options.RotateOutputPath),
+	}, nil
+}
+
+// toSlogLevel 将 SDK 日志级别转换为 slog 级别
+func toSlogLevel(level int) slog.Level {
+	switch level {
+	case plog.TraceLog:
+		return levelTrace
+	case plog.DebugLog:
+		return slog.LevelDebug
+	case plog.InfoLog:
+		return slog.LevelInfo
+	case plog.WarnLog:
+		return slog.LevelWarn
+	case plog.ErrorLog:
+		return slog.LevelError
+	default:
+		return levelFatal
+	}
+}
+
+// Tracef 打印trace级别的日志
+func (s *slogLogger) Tracef(format string, args ...interface{}) {
+	s.printf(plog.TraceLog, format, args...)
+}
+
+// Debugf 打印debug级别的日志
+func (s *slogLogger) Debugf(format string, args ...interface{}) {
+	s.printf(plog.DebugLog, format, args...)
+}
+
+// Infof 打印info级别的日志
+func (s *slogLogger) Infof(format string, args ...interface{}) {
+	s.printf(plog.InfoLog, format, args...)
+}
+
+// Warnf 打印warn级别的日志
+func (s *slogLogger) Warnf(format string, args ...interface{}) {
+	s.printf(plog.WarnLog, format, args...)
+}
+
+// Errorf 打印error级别的日志
+func (s *slogLogger) Errorf(format string, args ...interface{}) {
+	s.printf(plog.ErrorLog, format, args...)
+}
+
+// Fatalf 打印fatalf级别的日志，打印后退出进程
+func (s *slogLogger) Fatalf(format string, args ...interface{}) {
+	s.printf(plog.FatalLog, format, args...)
+}
+
+// IsLevelEnabled 判断当前级别是否满足日志打印的最低级别
+func (s *slogLogger) IsLevelEnabled(l int) bool {
+	return int32(l) >= atomic.LoadInt32(s.outputLevel)
+}
+
+// SetLogLevel 动态设置日志级别
+func (s *slogLogger) SetLogLevel(l int) error {
+	if err := plog.VerifyLogLevel(l); err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to verify log level")
+	}
+	atomic.StoreInt32(s.outputLevel, int32(l))
+	return nil
+}
+
+// GetLogDir 返回日志的目录
+func (s *slogLogger) GetLogDir() string {
+	return s.logDir
+}
+
+// Logw 打印带结构化字段的日志
+func (s *slogLogger) Logw(level int, msg string, keyvals ...interface{}) {
+	if !s.IsLevelEnabled(level) {
+		return
+	}
+	s.log(level, msg, keyvals...)
+	if level == plog.FatalLog {
+		os.Exit(1)
+	}
+}
+
+// With 返回附带固定字段的日志对象
+func (s *slogLogger) With(keyvals ...interface{}) plog.StructuredLogger {
+	return &slogLogger{
+		outputLevel: s.outputLevel,
+		logger:      s.logger.With(keyvals...),
+		logDir:      s.logDir,
+	}
+}
+
+// 通用打印函数
+func (s *slogLogger) printf(level int, format string, args ...interface{}) {
+	if !s.IsLevelEnabled(level) {
+		return
+	}
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	s.log(level, msg)
+	if level == plog.FatalLog {
+		os.Exit(1)
+	}
+}
+
+// log 构造日志记录并交给 handler 处理，记录的调用位置为 SDK 日志接口的调用方
+func (s *slogLogger) log(level int, msg string, keyvals ...interface{}) {
+	ctx := context.Background()
+	slogLevel := toSlogLevel(level)
+	if !s.logger.Enabled(ctx, slogLevel) {
+		return
+	}
+	var pcs [1]uintptr
+	// 跳过 runtime.Callers、log 及 printf/Logw 所在的栈帧
+	runtime.Callers(4, pcs[:])
+	record := slog.NewRecord(time.Now(), slogLevel, msg, pcs[0])
+	record.Add(keyvals...)
+	_ = s.logger.Handler().Handle(ctx, record)
+}
+
+// 初始化
+func init() {
+	plog.RegisterLoggerCreator(plog.LoggerSlog, prepareSlog)
+}
@@ -0,0 +1,38 @@
+//go:build !go1.21
+// +build !go1.21
+
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sloglog
+
+import (
+	"fmt"
+	"runtime"
+
+	plog "github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// prepareSlog 低于 go1.21 时标准库没有 log/slog，配置使用该日志类型时直接报错
+func prepareSlog(name string, options *plog.Options, defaultLevel int) (plog.Logger, error) {
+	return nil, fmt.Errorf("logger %s requires go1.21 or later, current runtime is %s",
+		plog.LoggerSlog, runtime.Version())
+}
+
+func init() {
+	plog.RegisterLoggerCreator(plog.LoggerSlog, prepareSlog)
+}
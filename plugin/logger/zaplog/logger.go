@@ -33,11 +33,21 @@ import (
 
 // 使用zap框架的log实现
 type zapLogger struct {
-	outputLevel int32
+	// outputLevel 通过 With 派生出的日志对象共享同一个日志级别
+	outputLevel *int32
 	logger      *zap.Logger
 	logDir      string
 }
 
+// NewLogger 将应用自身的 zap.Logger 适配为 SDK 的日志对象，level 为初始的日志打印级别
+func NewLogger(logger *zap.Logger, level int) plog.StructuredLogger {
+	outputLevel := int32(getOutputLevel(level, plog.DefaultLogLevel))
+	return &zapLogger{
+		outputLevel: &outputLevel,
+		logger:      logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(2)),
+	}
+}
+
 // 归一化日志级别
 func getOutputLevel(level int, defaultLevel int) int {
 	if level >= plog.NoneLog {
@@ -105,8 +115,9 @@ func prepareZap(name string, options *plog.Options, defaultLevel int) (plog.Logg
 	outputLevel := getOutputLevel(options.LogLevel, defaultLevel)
 	core := zapcore.NewCore(enc, sink, zap.NewAtomicLevelAt(zapcore.DebugLevel))
 	logger := zap.New(core, zap.ErrorOutput(errSink), zap.AddCaller(), zap.AddCallerSkip(2)).Named(name)
+	level := int32(outputLevel)
 	return &zapLogger{
-		outputLevel: int32(outputLevel),
+		outputLevel: &level,
 		logger:      logger,
 		logDir:      filepath.Dir(options.RotateOutputPath),
 	}, nil
@@ -184,7 +195,7 @@ func (z *zapLogger) Fatalf(format string, args ...interface{}) {
 
 // IsLevelEnabled 判断当前级别是否满足日志打印的最低级别
 func (z *zapLogger) IsLevelEnabled(l int) bool {
-	outputLevel := atomic.LoadInt32(&z.outputLevel)
+	outputLevel := atomic.LoadInt32(z.outputLevel)
 	return int32(l) >= outputLevel
 }
 
@@ -193,7 +204,7 @@ func (z *zapLogger) SetLogLevel(l int) error {
 	if err := plog.VerifyLogLevel(l); err != nil {
 		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to verify log level")
 	}
-	atomic.StoreInt32(&z.outputLevel, int32(l))
+	atomic.StoreInt32(z.outputLevel, int32(l))
 	return nil
 }
 
@@ -202,6 +213,51 @@ func (z *zapLogger) GetLogDir() string {
 	return z.logDir
 }
 
+// Logw 打印带结构化字段的日志
+func (z *zapLogger) Logw(level int, msg string, keyvals ...interface{}) {
+	if !z.IsLevelEnabled(level) {
+		return
+	}
+	fields := toZapFields(keyvals)
+	switch level {
+	case plog.TraceLog, plog.DebugLog:
+		z.logger.Debug(msg, fields...)
+	case plog.InfoLog:
+		z.logger.Info(msg, fields...)
+	case plog.WarnLog:
+		z.logger.Warn(msg, fields...)
+	case plog.ErrorLog:
+		z.logger.Error(msg, fields...)
+	case plog.FatalLog:
+		z.logger.Fatal(msg, fields...)
+	}
+}
+
+// With 返回附带固定字段的日志对象
+func (z *zapLogger) With(keyvals ...interface{}) plog.StructuredLogger {
+	return &zapLogger{
+		outputLevel: z.outputLevel,
+		logger:      z.logger.With(toZapFields(keyvals)...),
+		logDir:      z.logDir,
+	}
+}
+
+// toZapFields 将交替出现的键值对转换为 zap 字段
+func toZapFields(keyvals []interface{}) []zap.Field {
+	if len(keyvals) == 0 {
+		return nil
+	}
+	fields := make([]zap.Field, 0, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 >= len(keyvals) {
+			fields = append(fields, zap.Any("!BADKEY", keyvals[i]))
+			break
+		}
+		fields = append(fields, zap.Any(fmt.Sprint(keyvals[i]), keyvals[i+1]))
+	}
+	return fields
+}
+
 // 通用打印函数
 func (z *zapLogger) printf(
 	logFun func(msg string, fields ...zap.Field), level int, format string, args ...interface{}) {
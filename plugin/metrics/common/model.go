@@ -59,6 +59,10 @@ const (
 	CallerLabels    = "caller_labels"
 	MetricNameLabel = "metric_name"
 	RuleName        = "rule_name"
+	RouteRuleType   = "route_rule_type"
+	RouteStatus     = "route_status"
+	RouteRetCode    = "route_result_code"
+	SDKTaskQueue    = "sdk_task_queue"
 
 	// MetricsNameUpstreamRequestTotal 与路由、请求相关的指标信息.
 	MetricsNameUpstreamRequestTotal      = "upstream_rq_total"
@@ -66,16 +70,36 @@ const (
 	MetricsNameUpstreamRequestTimeout    = "upstream_rq_timeout"
 	MetricsNameUpstreamRequestMaxTimeout = "upstream_rq_max_timeout"
 	MetricsNameUpstreamRequestDelay      = "upstream_rq_delay"
+	// MetricsNameUpstreamRequestDelaySeconds 调用时延直方图
+	MetricsNameUpstreamRequestDelaySeconds = "upstream_rq_delay_seconds"
+	// MetricsNameRouteRequestTotal 路由结果
+	MetricsNameRouteRequestTotal = "route_rq_total"
 
 	// 限流相关指标信息.
 	MetricsNameRateLimitRequestTotal = "ratelimit_rq_total"
 	MetricsNameRateLimitRequestPass  = "ratelimit_rq_pass"
 	MetricsNameRateLimitRequestLimit = "ratelimit_rq_limit"
+	// 影子模式下本应被限流的请求数
+	MetricsNameRateLimitRequestShadowLimit = "ratelimit_rq_shadow_limit"
 
 	// 熔断相关指标信息.
 	MetricsNameCircuitBreakerOpen     = "circuitbreaker_open"
 	MetricsNameCircuitBreakerHalfOpen = "circuitbreaker_halfopen"
 
+	// 本地缓存相关指标信息.
+	MetricsNameCacheRequestTotal = "cache_rq_total"
+	MetricsNameCacheRequestHit   = "cache_rq_hit"
+	MetricsNameCacheHitRatio     = "cache_hit_ratio"
+
+	// SDK自监控相关指标信息.
+	MetricsNameSDKCachedServices          = "sdk_cached_services"
+	MetricsNameSDKCachedInstances         = "sdk_cached_instances"
+	MetricsNameSDKPendingDiscoverRequests = "sdk_pending_discover_requests"
+	MetricsNameSDKReconnects              = "sdk_reconnects"
+	MetricsNameSDKPersistFailures         = "sdk_persist_failures"
+	MetricsNameSDKTaskQueueDepth          = "sdk_task_queue_depth"
+	MetricsNameSDKGoroutines              = "sdk_goroutines"
+
 	// SystemMetricValue.
 	NilValue = "__NULL__"
 )
@@ -215,6 +239,13 @@ var (
 			val := args.(*model.CircuitBreakGauge)
 			return val.GetService()
 		},
+		RuleName: func(args interface{}) string {
+			val := args.(*model.CircuitBreakGauge)
+			if val.CBStatus == nil || val.CBStatus.GetCircuitBreaker() == "" {
+				return NilValue
+			}
+			return val.CBStatus.GetCircuitBreaker()
+		},
 	}
 )
 
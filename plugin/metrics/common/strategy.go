@@ -47,6 +47,7 @@ var (
 		&RateLimitRequestTotalStrategy{},
 		&RateLimitRequestPassStrategy{},
 		&RateLimitRequestLimitStrategy{},
+		&RateLimitRequestShadowLimitStrategy{},
 	}
 	RateLimitLabelOrder = []string{
 		CalleeNamespace,
@@ -255,7 +256,7 @@ func (us *CircuitBreakerOpenStrategy) GetStrategyName() string {
 
 // 根据数据源的内容获取第一次创建metric的时候的初始值
 func (us *CircuitBreakerOpenStrategy) InitMetricValue(dataSource interface{}) float64 {
-	gauge, ok := dataSource.(model.CircuitBreakGauge)
+	gauge, ok := dataSource.(*model.CircuitBreakGauge)
 	if !ok {
 		return 0
 	}
@@ -271,7 +272,7 @@ func (us *CircuitBreakerOpenStrategy) InitMetricValue(dataSource interface{}) fl
 
 // 根据metric自身的value值和聚合数据源T的值来更新metric的value
 func (us *CircuitBreakerOpenStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
-	gauge, ok := dataSource.(model.CircuitBreakGauge)
+	gauge, ok := dataSource.(*model.CircuitBreakGauge)
 	if !ok {
 		return
 	}
@@ -302,7 +303,7 @@ func (us *CircuitBreakerHalfOpenStrategy) GetStrategyName() string {
 
 // 根据数据源的内容获取第一次创建metric的时候的初始值
 func (us *CircuitBreakerHalfOpenStrategy) InitMetricValue(dataSource interface{}) float64 {
-	gauge, ok := dataSource.(model.CircuitBreakGauge)
+	gauge, ok := dataSource.(*model.CircuitBreakGauge)
 	if !ok {
 		return 0
 	}
@@ -318,7 +319,7 @@ func (us *CircuitBreakerHalfOpenStrategy) InitMetricValue(dataSource interface{}
 
 // 根据metric自身的value值和聚合数据源T的值来更新metric的value
 func (us *CircuitBreakerHalfOpenStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
-	gauge, ok := dataSource.(model.CircuitBreakGauge)
+	gauge, ok := dataSource.(*model.CircuitBreakGauge)
 	if !ok {
 		return
 	}
@@ -438,3 +439,39 @@ func (us *RateLimitRequestLimitStrategy) UpdateMetricValue(targetValue StatMetri
 		targetValue.Inc()
 	}
 }
+
+type RateLimitRequestShadowLimitStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *RateLimitRequestShadowLimitStrategy) GetStrategyDescription() string {
+	return "total of request which should be limited in shadow mode per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *RateLimitRequestShadowLimitStrategy) GetStrategyName() string {
+	return MetricsNameRateLimitRequestShadowLimit
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *RateLimitRequestShadowLimitStrategy) InitMetricValue(dataSource interface{}) float64 {
+	gauge, ok := dataSource.(*model.RateLimitGauge)
+	if !ok {
+		return 0
+	}
+	if gauge.ShadowLimited {
+		return 1.0
+	}
+	return 0
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *RateLimitRequestShadowLimitStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	gauge, ok := dataSource.(*model.RateLimitGauge)
+	if !ok {
+		return
+	}
+	if gauge.ShadowLimited {
+		targetValue.Inc()
+	}
+}
@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package opentelemetry
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+)
+
+func init() {
+	plugin.RegisterConfigurablePlugin(&Reporter{}, &Config{})
+}
+
+const (
+	// DefaultEndpoint 默认的 OTLP/HTTP 接收地址
+	DefaultEndpoint = "http://127.0.0.1:4318"
+	// DefaultInterval 默认的导出周期
+	DefaultInterval = 15 * time.Second
+	// DefaultTimeout 默认的导出超时时间
+	DefaultTimeout = 5 * time.Second
+	// DefaultMaxQueueSize 默认的待导出 span 队列长度
+	DefaultMaxQueueSize = 2048
+	// DefaultServiceName 默认上报的 service.name
+	DefaultServiceName = "polaris-go"
+)
+
+// Config opentelemetry 插件配置
+type Config struct {
+	// Endpoint OTLP/HTTP 接收地址，指标与链路分别发送到 /v1/metrics 与 /v1/traces
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+	// Headers 导出请求附带的请求头，如鉴权信息
+	Headers map[string]string `yaml:"headers" json:"headers"`
+	// Interval 导出周期
+	Interval time.Duration `yaml:"interval" json:"interval"`
+	// Timeout 单次导出超时时间
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
+	// ServiceName 资源属性 service.name
+	ServiceName string `yaml:"serviceName" json:"serviceName"`
+	// EnableTrace 是否将 SDK 操作导出为 span
+	EnableTrace *bool `yaml:"enableTrace" json:"enableTrace"`
+	// MaxQueueSize 待导出 span 的最大数量，超出后丢弃
+	MaxQueueSize int `yaml:"maxQueueSize" json:"maxQueueSize"`
+	// DelayBuckets 时延直方图的分桶，单位秒
+	DelayBuckets []float64 `yaml:"delayBuckets" json:"delayBuckets"`
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	var errs error
+	if !strings.HasPrefix(c.Endpoint, "http://") && !strings.HasPrefix(c.Endpoint, "https://") {
+		errs = multierror.Append(errs, fmt.Errorf("opentelemetry.endpoint must start with http:// or https://"))
+	}
+	if c.Interval < time.Second {
+		errs = multierror.Append(errs, fmt.Errorf("opentelemetry.interval must be greater than 1s"))
+	}
+	if c.Timeout <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("opentelemetry.timeout must be greater than 0"))
+	}
+	if c.MaxQueueSize <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("opentelemetry.maxQueueSize must be greater than 0"))
+	}
+	for i := 1; i < len(c.DelayBuckets); i++ {
+		if c.DelayBuckets[i] <= c.DelayBuckets[i-1] {
+			errs = multierror.Append(errs, fmt.Errorf("opentelemetry.delayBuckets must be in increasing order"))
+			break
+		}
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.Endpoint == "" {
+		c.Endpoint = DefaultEndpoint
+	}
+	c.Endpoint = strings.TrimSuffix(c.Endpoint, "/")
+	if c.Interval == 0 {
+		c.Interval = DefaultInterval
+	}
+	if c.Timeout == 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.ServiceName == "" {
+		c.ServiceName = DefaultServiceName
+	}
+	if c.EnableTrace == nil {
+		enable := true
+		c.EnableTrace = &enable
+	}
+	if c.MaxQueueSize == 0 {
+		c.MaxQueueSize = DefaultMaxQueueSize
+	}
+	if len(c.DelayBuckets) == 0 {
+		c.DelayBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	}
+}
@@ -0,0 +1,371 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package opentelemetry
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 以下为 OTLP/HTTP JSON 编码所需的数据结构，字段命名遵循 opentelemetry-proto 的 JSON 映射
+
+const (
+	// aggregationTemporalityCumulative 累计型聚合
+	aggregationTemporalityCumulative = 2
+	// spanKindClient 客户端 span
+	spanKindClient = 3
+	// statusCodeOk span 状态正常
+	statusCodeOk = 1
+	// statusCodeError span 状态异常
+	statusCodeError = 2
+	// scopeName 上报的 instrumentation scope 名称
+	scopeName = "github.com/polarismesh/polaris-go"
+)
+
+type anyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type exportMetricsRequest struct {
+	ResourceMetrics []resourceMetrics `json:"resourceMetrics"`
+}
+
+type resourceMetrics struct {
+	Resource     resource       `json:"resource"`
+	ScopeMetrics []scopeMetrics `json:"scopeMetrics"`
+}
+
+type scopeMetrics struct {
+	Scope   scope    `json:"scope"`
+	Metrics []metric `json:"metrics"`
+}
+
+type metric struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Unit        string     `json:"unit"`
+	Sum         *sum       `json:"sum,omitempty"`
+	Gauge       *gauge     `json:"gauge,omitempty"`
+	Histogram   *histogram `json:"histogram,omitempty"`
+}
+
+type sum struct {
+	DataPoints             []numberDataPoint `json:"dataPoints"`
+	AggregationTemporality int               `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+}
+
+type gauge struct {
+	DataPoints []numberDataPoint `json:"dataPoints"`
+}
+
+type numberDataPoint struct {
+	Attributes        []keyValue `json:"attributes"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      string     `json:"timeUnixNano"`
+	AsInt             string     `json:"asInt"`
+}
+
+type histogram struct {
+	DataPoints             []histogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                  `json:"aggregationTemporality"`
+}
+
+type histogramDataPoint struct {
+	Attributes        []keyValue `json:"attributes"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	TimeUnixNano      string     `json:"timeUnixNano"`
+	Count             string     `json:"count"`
+	Sum               float64    `json:"sum"`
+	BucketCounts      []string   `json:"bucketCounts"`
+	ExplicitBounds    []float64  `json:"explicitBounds"`
+}
+
+type exportTraceRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type span struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes"`
+	Status            spanStatus `json:"status"`
+}
+
+type spanStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// toKeyValues 将属性转换为按 key 排序的 OTLP 属性列表
+func toKeyValues(attrs map[string]string) []keyValue {
+	kvs := make([]keyValue, 0, len(attrs))
+	for k, v := range attrs {
+		kvs = append(kvs, keyValue{Key: k, Value: anyValue{StringValue: v}})
+	}
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key < kvs[j].Key
+	})
+	return kvs
+}
+
+// attrsSignature 属性的唯一标识，用于聚合同一组属性的数据点
+func attrsSignature(kvs []keyValue) string {
+	var builder strings.Builder
+	for i := range kvs {
+		builder.WriteString(kvs[i].Key)
+		builder.WriteByte('=')
+		builder.WriteString(kvs[i].Value.StringValue)
+		builder.WriteByte('|')
+	}
+	return builder.String()
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// counterInstrument 单调递增的计数器
+type counterInstrument struct {
+	name        string
+	description string
+	unit        string
+	lock        sync.Mutex
+	points      map[string]*counterPoint
+}
+
+type counterPoint struct {
+	attrs []keyValue
+	value int64
+}
+
+func newCounter(name, description, unit string) *counterInstrument {
+	return &counterInstrument{
+		name:        name,
+		description: description,
+		unit:        unit,
+		points:      map[string]*counterPoint{},
+	}
+}
+
+// add 累加计数
+func (c *counterInstrument) add(attrs map[string]string, delta int64) {
+	kvs := toKeyValues(attrs)
+	signature := attrsSignature(kvs)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	point, ok := c.points[signature]
+	if !ok {
+		point = &counterPoint{attrs: kvs}
+		c.points[signature] = point
+	}
+	point.value += delta
+}
+
+// collect 生成累计型的数据快照
+func (c *counterInstrument) collect(start, now time.Time) *metric {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.points) == 0 {
+		return nil
+	}
+	dataPoints := make([]numberDataPoint, 0, len(c.points))
+	for _, point := range c.points {
+		dataPoints = append(dataPoints, numberDataPoint{
+			Attributes:        point.attrs,
+			StartTimeUnixNano: unixNano(start),
+			TimeUnixNano:      unixNano(now),
+			AsInt:             strconv.FormatInt(point.value, 10),
+		})
+	}
+	return &metric{
+		Name:        c.name,
+		Description: c.description,
+		Unit:        c.unit,
+		Sum: &sum{
+			DataPoints:             dataPoints,
+			AggregationTemporality: aggregationTemporalityCumulative,
+			IsMonotonic:            true,
+		},
+	}
+}
+
+// gaugeInstrument 记录最新值的仪表
+type gaugeInstrument struct {
+	name        string
+	description string
+	unit        string
+	lock        sync.Mutex
+	points      map[string]*counterPoint
+}
+
+func newGauge(name, description, unit string) *gaugeInstrument {
+	return &gaugeInstrument{
+		name:        name,
+		description: description,
+		unit:        unit,
+		points:      map[string]*counterPoint{},
+	}
+}
+
+// set 设置最新值
+func (g *gaugeInstrument) set(attrs map[string]string, value int64) {
+	kvs := toKeyValues(attrs)
+	signature := attrsSignature(kvs)
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	point, ok := g.points[signature]
+	if !ok {
+		point = &counterPoint{attrs: kvs}
+		g.points[signature] = point
+	}
+	point.value = value
+}
+
+// collect 生成最新值的数据快照
+func (g *gaugeInstrument) collect(now time.Time) *metric {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if len(g.points) == 0 {
+		return nil
+	}
+	dataPoints := make([]numberDataPoint, 0, len(g.points))
+	for _, point := range g.points {
+		dataPoints = append(dataPoints, numberDataPoint{
+			Attributes:   point.attrs,
+			TimeUnixNano: unixNano(now),
+			AsInt:        strconv.FormatInt(point.value, 10),
+		})
+	}
+	return &metric{
+		Name:        g.name,
+		Description: g.description,
+		Unit:        g.unit,
+		Gauge:       &gauge{DataPoints: dataPoints},
+	}
+}
+
+// histogramInstrument 显式分桶的直方图
+type histogramInstrument struct {
+	name        string
+	description string
+	unit        string
+	bounds      []float64
+	lock        sync.Mutex
+	points      map[string]*histogramPoint
+}
+
+type histogramPoint struct {
+	attrs  []keyValue
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(name, description, unit string, bounds []float64) *histogramInstrument {
+	return &histogramInstrument{
+		name:        name,
+		description: description,
+		unit:        unit,
+		bounds:      bounds,
+		points:      map[string]*histogramPoint{},
+	}
+}
+
+// record 记录一个观测值
+func (h *histogramInstrument) record(attrs map[string]string, value float64) {
+	kvs := toKeyValues(attrs)
+	signature := attrsSignature(kvs)
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	point, ok := h.points[signature]
+	if !ok {
+		point = &histogramPoint{attrs: kvs, counts: make([]uint64, len(h.bounds)+1)}
+		h.points[signature] = point
+	}
+	// 桶的上界为闭区间，与 OTLP 的定义保持一致
+	idx := sort.SearchFloat64s(h.bounds, value)
+	point.counts[idx]++
+	point.count++
+	point.sum += value
+}
+
+// collect 生成累计型的数据快照
+func (h *histogramInstrument) collect(start, now time.Time) *metric {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if len(h.points) == 0 {
+		return nil
+	}
+	dataPoints := make([]histogramDataPoint, 0, len(h.points))
+	for _, point := range h.points {
+		bucketCounts := make([]string, 0, len(point.counts))
+		for _, count := range point.counts {
+			bucketCounts = append(bucketCounts, strconv.FormatUint(count, 10))
+		}
+		dataPoints = append(dataPoints, histogramDataPoint{
+			Attributes:        point.attrs,
+			StartTimeUnixNano: unixNano(start),
+			TimeUnixNano:      unixNano(now),
+			Count:             strconv.FormatUint(point.count, 10),
+			Sum:               point.sum,
+			BucketCounts:      bucketCounts,
+			ExplicitBounds:    h.bounds,
+		})
+	}
+	return &metric{
+		Name:        h.name,
+		Description: h.description,
+		Unit:        h.unit,
+		Histogram: &histogram{
+			DataPoints:             dataPoints,
+			AggregationTemporality: aggregationTemporalityCumulative,
+		},
+	}
+}
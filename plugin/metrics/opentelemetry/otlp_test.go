@@ -0,0 +1,246 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package opentelemetry
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 以下用例按 opentelemetry-proto 的 JSON 映射校验编码结果：
+// 字段名为 lowerCamelCase，64位整数及 fixed64 编码为字符串，枚举编码为整数，traceId/spanId 编码为十六进制字符串
+
+var (
+	testStart = time.Unix(1, 0)
+	testNow   = time.Unix(2, 500)
+)
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal %T fail: %v", v, err)
+	}
+	return string(data)
+}
+
+func TestInstrumentEncoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		collect func() *metric
+		want    string
+	}{
+		{
+			name: "counter编码为单调累计的sum",
+			collect: func() *metric {
+				c := newCounter("polaris.test.requests", "test counter", "{request}")
+				c.add(map[string]string{"b": "2", "a": "1"}, 3)
+				c.add(map[string]string{"a": "1", "b": "2"}, 4)
+				return c.collect(testStart, testNow)
+			},
+			want: `{
+				"name": "polaris.test.requests",
+				"description": "test counter",
+				"unit": "{request}",
+				"sum": {
+					"dataPoints": [{
+						"attributes": [
+							{"key": "a", "value": {"stringValue": "1"}},
+							{"key": "b", "value": {"stringValue": "2"}}
+						],
+						"startTimeUnixNano": "1000000000",
+						"timeUnixNano": "2000000500",
+						"asInt": "7"
+					}],
+					"aggregationTemporality": 2,
+					"isMonotonic": true
+				}
+			}`,
+		},
+		{
+			name: "gauge只保留最新值且不带startTimeUnixNano",
+			collect: func() *metric {
+				g := newGauge("polaris.test.depth", "test gauge", "{task}")
+				g.set(map[string]string{"queue": "q1"}, 5)
+				g.set(map[string]string{"queue": "q1"}, 2)
+				return g.collect(testNow)
+			},
+			want: `{
+				"name": "polaris.test.depth",
+				"description": "test gauge",
+				"unit": "{task}",
+				"gauge": {
+					"dataPoints": [{
+						"attributes": [{"key": "queue", "value": {"stringValue": "q1"}}],
+						"timeUnixNano": "2000000500",
+						"asInt": "2"
+					}]
+				}
+			}`,
+		},
+		{
+			name: "histogram的桶上界为闭区间，计数编码为字符串",
+			collect: func() *metric {
+				h := newHistogram("polaris.test.duration", "test histogram", "s", []float64{0.5, 1})
+				for _, v := range []float64{0.25, 0.5, 2} {
+					h.record(map[string]string{"api": "GetOneInstance"}, v)
+				}
+				return h.collect(testStart, testNow)
+			},
+			want: `{
+				"name": "polaris.test.duration",
+				"description": "test histogram",
+				"unit": "s",
+				"histogram": {
+					"dataPoints": [{
+						"attributes": [{"key": "api", "value": {"stringValue": "GetOneInstance"}}],
+						"startTimeUnixNano": "1000000000",
+						"timeUnixNano": "2000000500",
+						"count": "3",
+						"sum": 2.75,
+						"bucketCounts": ["2", "0", "1"],
+						"explicitBounds": [0.5, 1]
+					}],
+					"aggregationTemporality": 2
+				}
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.collect()
+			if m == nil {
+				t.Fatal("collect returns nil")
+			}
+			assert.JSONEq(t, tt.want, mustMarshal(t, m))
+		})
+	}
+}
+
+func TestEmptyInstrumentNotExported(t *testing.T) {
+	if m := newCounter("c", "", "").collect(testStart, testNow); m != nil {
+		t.Errorf("empty counter collect = %+v, want nil", m)
+	}
+	if m := newGauge("g", "", "").collect(testNow); m != nil {
+		t.Errorf("empty gauge collect = %+v, want nil", m)
+	}
+	if m := newHistogram("h", "", "", []float64{1}).collect(testStart, testNow); m != nil {
+		t.Errorf("empty histogram collect = %+v, want nil", m)
+	}
+}
+
+func TestExportRequestEncoding(t *testing.T) {
+	res := resource{Attributes: toKeyValues(map[string]string{"service.name": "demo"})}
+	sdkScope := scope{Name: scopeName, Version: "v1.0.0"}
+	c := newCounter("polaris.test.requests", "", "1")
+	c.add(map[string]string{}, 1)
+
+	tests := []struct {
+		name string
+		req  interface{}
+		want string
+	}{
+		{
+			name: "ExportMetricsServiceRequest",
+			req: &exportMetricsRequest{ResourceMetrics: []resourceMetrics{{
+				Resource:     res,
+				ScopeMetrics: []scopeMetrics{{Scope: sdkScope, Metrics: []metric{*c.collect(testStart, testNow)}}},
+			}}},
+			want: `{"resourceMetrics": [{
+				"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "demo"}}]},
+				"scopeMetrics": [{
+					"scope": {"name": "github.com/polarismesh/polaris-go", "version": "v1.0.0"},
+					"metrics": [{
+						"name": "polaris.test.requests",
+						"description": "",
+						"unit": "1",
+						"sum": {
+							"dataPoints": [{
+								"attributes": [],
+								"startTimeUnixNano": "1000000000",
+								"timeUnixNano": "2000000500",
+								"asInt": "1"
+							}],
+							"aggregationTemporality": 2,
+							"isMonotonic": true
+						}
+					}]
+				}]
+			}]}`,
+		},
+		{
+			name: "ExportTraceServiceRequest",
+			req: &exportTraceRequest{ResourceSpans: []resourceSpans{{
+				Resource: res,
+				ScopeSpans: []scopeSpans{{Scope: sdkScope, Spans: []span{{
+					TraceID:           "5b8efff798038103d269b633813fc60c",
+					SpanID:            "eee19b7ec3c1b174",
+					Name:              "GetOneInstance",
+					Kind:              spanKindClient,
+					StartTimeUnixNano: unixNano(testStart),
+					EndTimeUnixNano:   unixNano(testNow),
+					Attributes:        toKeyValues(map[string]string{"polaris.api": "GetOneInstance"}),
+					Status:            spanStatus{Code: statusCodeError, Message: "ErrCodeAPITimeoutError"},
+				}}}},
+			}}},
+			want: `{"resourceSpans": [{
+				"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "demo"}}]},
+				"scopeSpans": [{
+					"scope": {"name": "github.com/polarismesh/polaris-go", "version": "v1.0.0"},
+					"spans": [{
+						"traceId": "5b8efff798038103d269b633813fc60c",
+						"spanId": "eee19b7ec3c1b174",
+						"name": "GetOneInstance",
+						"kind": 3,
+						"startTimeUnixNano": "1000000000",
+						"endTimeUnixNano": "2000000500",
+						"attributes": [{"key": "polaris.api", "value": {"stringValue": "GetOneInstance"}}],
+						"status": {"code": 2, "message": "ErrCodeAPITimeoutError"}
+					}]
+				}]
+			}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.JSONEq(t, tt.want, mustMarshal(t, tt.req))
+		})
+	}
+}
+
+func TestRandomHexID(t *testing.T) {
+	tests := []struct {
+		name    string
+		bytes   int
+		pattern string
+	}{
+		{name: "traceId为16字节", bytes: 16, pattern: `^[0-9a-f]{32}$`},
+		{name: "spanId为8字节", bytes: 8, pattern: `^[0-9a-f]{16}$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := randomHex(tt.bytes)
+			if !regexp.MustCompile(tt.pattern).MatchString(id) {
+				t.Errorf("randomHex(%d) = %q, want match %s", tt.bytes, id, tt.pattern)
+			}
+		})
+	}
+}
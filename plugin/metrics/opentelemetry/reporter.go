@@ -0,0 +1,425 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package opentelemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/pkg/version"
+)
+
+const (
+	// PluginName 插件名称
+	PluginName = "opentelemetry"
+
+	metricsPath = "/v1/metrics"
+	tracesPath  = "/v1/traces"
+)
+
+const (
+	healthCachedServices  = "cachedServices"
+	healthCachedInstances = "cachedInstances"
+	healthPendingDiscover = "pendingDiscover"
+	healthReconnects      = "reconnects"
+	healthPersistFailures = "persistFailures"
+	healthGoroutines      = "goroutines"
+)
+
+// healthGaugeNames 自监控指标的导出顺序
+var healthGaugeNames = []string{healthCachedServices, healthCachedInstances, healthPendingDiscover,
+	healthReconnects, healthPersistFailures, healthGoroutines}
+
+var _ statreporter.StatReporter = (*Reporter)(nil)
+
+// Reporter 通过 OTLP/HTTP(JSON) 导出 SDK 操作的指标与链路数据
+type Reporter struct {
+	*plugin.PluginBase
+	*common.RunContext
+	cfg       *Config
+	client    *http.Client
+	resource  resource
+	startTime time.Time
+	cancel    context.CancelFunc
+	stopped   chan struct{}
+
+	apiDuration        *histogramInstrument
+	upstreamDuration   *histogramInstrument
+	rateLimitRequests  *counterInstrument
+	routeRequests      *counterInstrument
+	circuitBreakEvents *counterInstrument
+	cacheLookups       *counterInstrument
+	sdkHealth          map[string]*gaugeInstrument
+	sdkTaskQueueDepth  *gaugeInstrument
+
+	spanLock     sync.Mutex
+	spans        []span
+	droppedSpans int64
+}
+
+// Type 插件类型
+func (r *Reporter) Type() common.Type {
+	return common.TypeStatReporter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (r *Reporter) Name() string {
+	return PluginName
+}
+
+// Init 初始化插件
+func (r *Reporter) Init(ctx *plugin.InitContext) error {
+	r.PluginBase = plugin.NewPluginBase(ctx)
+	r.RunContext = common.NewRunContext()
+	r.cfg = &Config{}
+	cfgValue := ctx.Config.GetGlobal().GetStatReporter().GetPluginConfig(PluginName)
+	if cfgValue != nil {
+		r.cfg = cfgValue.(*Config)
+	}
+	r.cfg.SetDefault()
+	r.client = &http.Client{Timeout: r.cfg.Timeout}
+	r.resource = resource{Attributes: toKeyValues(map[string]string{
+		"service.name":           r.cfg.ServiceName,
+		"telemetry.sdk.name":     "polaris-go",
+		"telemetry.sdk.language": "go",
+		"telemetry.sdk.version":  version.Version,
+		"host.ip":                ctx.Config.GetGlobal().GetAPI().GetBindIP(),
+		"polaris.sdk.context.id": ctx.SDKContextID,
+	})}
+	r.startTime = time.Now()
+	r.apiDuration = newHistogram("polaris.sdk.api.duration",
+		"duration of polaris sdk api invocation", "s", r.cfg.DelayBuckets)
+	r.upstreamDuration = newHistogram("polaris.upstream.request.duration",
+		"duration of request to callee service", "s", r.cfg.DelayBuckets)
+	r.rateLimitRequests = newCounter("polaris.ratelimit.requests",
+		"total of rate limit quota acquisition", "{request}")
+	r.routeRequests = newCounter("polaris.route.requests",
+		"total of service route result", "{request}")
+	r.circuitBreakEvents = newCounter("polaris.circuitbreaker.transitions",
+		"total of circuit breaker status transition", "{transition}")
+	r.cacheLookups = newCounter("polaris.cache.lookups",
+		"total of local cache lookup", "{lookup}")
+	r.sdkHealth = map[string]*gaugeInstrument{
+		healthCachedServices: newGauge("polaris.sdk.cache.services",
+			"number of services with instances cached in sdk", "{service}"),
+		healthCachedInstances: newGauge("polaris.sdk.cache.instances",
+			"number of instances cached in sdk", "{instance}"),
+		healthPendingDiscover: newGauge("polaris.sdk.discover.pending",
+			"number of discover requests waiting for response", "{request}"),
+		healthReconnects: newGauge("polaris.sdk.reconnects",
+			"total of reconnections to polaris server", "{reconnect}"),
+		healthPersistFailures: newGauge("polaris.sdk.persist.failures",
+			"total of local cache persist failures", "{failure}"),
+		healthGoroutines: newGauge("polaris.sdk.goroutines",
+			"number of goroutines in process", "{goroutine}"),
+	}
+	r.sdkTaskQueueDepth = newGauge("polaris.sdk.task.queue.depth",
+		"number of tasks waiting in sdk task queue", "{task}")
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.stopped = make(chan struct{})
+	go r.run(runCtx)
+	return nil
+}
+
+// ReportSDKAPIStat 记录SDK自身API的调用结果，与服务调用指标使用不同的指标名
+func (r *Reporter) ReportSDKAPIStat(result *model.APICallResult) error {
+	if result != nil {
+		r.recordAPICall(result)
+	}
+	return nil
+}
+
+// ReportStat 记录统计数据，在导出周期到达时统一发送
+func (r *Reporter) ReportStat(metricsType model.MetricType, metricsVal model.InstanceGauge) error {
+	switch metricsType {
+	case model.ServiceStat:
+		if val, ok := metricsVal.(*model.ServiceCallResult); ok && val != nil {
+			r.recordServiceCall(val)
+		}
+	case model.RateLimitStat:
+		if val, ok := metricsVal.(*model.RateLimitGauge); ok && val != nil {
+			result := "pass"
+			if val.Result == model.QuotaResultLimited {
+				result = "limit"
+			}
+			r.rateLimitRequests.add(map[string]string{
+				"polaris.namespace":        val.Namespace,
+				"polaris.service":          val.Service,
+				"polaris.method":           val.Method,
+				"polaris.ratelimit.result": result,
+			}, 1)
+		}
+	case model.CircuitBreakStat:
+		if val, ok := metricsVal.(*model.CircuitBreakGauge); ok && val != nil && val.CBStatus != nil &&
+			val.ChangeInstance != nil {
+			ins := val.ChangeInstance
+			r.circuitBreakEvents.add(map[string]string{
+				"polaris.namespace":              ins.GetNamespace(),
+				"polaris.service":                ins.GetService(),
+				"polaris.instance":               fmt.Sprintf("%s:%d", ins.GetHost(), ins.GetPort()),
+				"polaris.circuitbreaker.status":  val.CBStatus.GetStatus().String(),
+				"polaris.circuitbreaker.breaker": val.CBStatus.GetCircuitBreaker(),
+			}, 1)
+		}
+	case model.RouteStat:
+		if val, ok := metricsVal.(*servicerouter.RouteGauge); ok && val != nil {
+			attrs := map[string]string{
+				"polaris.route.status":   val.Status.String(),
+				"polaris.route.ret_code": strconv.Itoa(int(val.RetCode)),
+			}
+			if val.ServiceInstances != nil {
+				attrs["polaris.namespace"] = val.ServiceInstances.GetNamespace()
+				attrs["polaris.service"] = val.ServiceInstances.GetService()
+			}
+			r.routeRequests.add(attrs, 1)
+		}
+	case model.CacheStat:
+		if val, ok := metricsVal.(*model.CacheGauge); ok && val != nil {
+			r.cacheLookups.add(map[string]string{
+				"polaris.namespace": val.Namespace,
+				"polaris.service":   val.Service,
+				"polaris.cache.hit": strconv.FormatBool(val.Hit),
+			}, int64(val.GetCount()))
+		}
+	case model.SDKHealthStat:
+		if val, ok := metricsVal.(*model.SDKHealthGauge); ok && val != nil {
+			r.recordHealth(val)
+		}
+	}
+	return nil
+}
+
+// recordHealth 记录 SDK 自监控指标
+func (r *Reporter) recordHealth(val *model.SDKHealthGauge) {
+	r.sdkHealth[healthCachedServices].set(nil, int64(val.CachedServices))
+	r.sdkHealth[healthCachedInstances].set(nil, int64(val.CachedInstances))
+	r.sdkHealth[healthPendingDiscover].set(nil, int64(val.PendingDiscoverRequests))
+	r.sdkHealth[healthReconnects].set(nil, val.ReconnectCount)
+	r.sdkHealth[healthPersistFailures].set(nil, val.PersistFailures)
+	r.sdkHealth[healthGoroutines].set(nil, int64(val.Goroutines))
+	for queue, depth := range val.TaskQueueDepths {
+		r.sdkTaskQueueDepth.set(map[string]string{"polaris.sdk.task.queue": queue}, int64(depth))
+	}
+}
+
+// recordAPICall 记录 SDK 接口调用，同时生成对应的 span
+func (r *Reporter) recordAPICall(val *model.APICallResult) {
+	delay := *val.GetDelay()
+	attrs := map[string]string{
+		"polaris.api":        val.GetAPI().String(),
+		"polaris.ret_code":   strconv.Itoa(int(val.RetCode)),
+		"polaris.ret_status": string(val.RetStatus),
+	}
+	r.apiDuration.record(attrs, delay.Seconds())
+	if !*r.cfg.EnableTrace {
+		return
+	}
+	end := time.Now()
+	status := spanStatus{Code: statusCodeOk}
+	if val.RetStatus == model.RetFail {
+		status = spanStatus{Code: statusCodeError, Message: model.ErrCodeToString(val.RetCode)}
+	}
+	r.enqueueSpan(span{
+		TraceID:           randomHex(16),
+		SpanID:            randomHex(8),
+		Name:              val.GetAPI().String(),
+		Kind:              spanKindClient,
+		StartTimeUnixNano: unixNano(end.Add(-delay)),
+		EndTimeUnixNano:   unixNano(end),
+		Attributes:        toKeyValues(attrs),
+		Status:            status,
+	})
+}
+
+// recordServiceCall 记录业务调用时延
+func (r *Reporter) recordServiceCall(val *model.ServiceCallResult) {
+	if val.GetDelay() == nil || val.CalledInstance == nil {
+		return
+	}
+	retCode := ""
+	if val.GetRetCode() != nil {
+		retCode = strconv.Itoa(int(*val.GetRetCode()))
+	}
+	r.upstreamDuration.record(map[string]string{
+		"polaris.namespace":  val.CalledInstance.GetNamespace(),
+		"polaris.service":    val.CalledInstance.GetService(),
+		"polaris.method":     val.GetMethod(),
+		"polaris.ret_code":   retCode,
+		"polaris.ret_status": string(val.GetRetStatus()),
+	}, val.GetDelay().Seconds())
+}
+
+func (r *Reporter) enqueueSpan(s span) {
+	r.spanLock.Lock()
+	defer r.spanLock.Unlock()
+	if len(r.spans) >= r.cfg.MaxQueueSize {
+		r.droppedSpans++
+		return
+	}
+	r.spans = append(r.spans, s)
+}
+
+func (r *Reporter) drainSpans() ([]span, int64) {
+	r.spanLock.Lock()
+	defer r.spanLock.Unlock()
+	spans, dropped := r.spans, r.droppedSpans
+	r.spans = nil
+	r.droppedSpans = 0
+	return spans, dropped
+}
+
+func (r *Reporter) run(ctx context.Context) {
+	defer close(r.stopped)
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.export()
+		case <-ctx.Done():
+			// 退出前将剩余数据导出
+			r.export()
+			return
+		}
+	}
+}
+
+// export 导出当前的指标快照以及缓存的 span
+func (r *Reporter) export() {
+	defer func() {
+		if err := recover(); err != nil {
+			log.GetBaseLogger().Errorf("[metrics][opentelemetry] export panic: %v", err)
+		}
+	}()
+	now := time.Now()
+	var metrics []metric
+	for _, m := range []*metric{
+		r.apiDuration.collect(r.startTime, now),
+		r.upstreamDuration.collect(r.startTime, now),
+		r.rateLimitRequests.collect(r.startTime, now),
+		r.routeRequests.collect(r.startTime, now),
+		r.circuitBreakEvents.collect(r.startTime, now),
+		r.cacheLookups.collect(r.startTime, now),
+		r.sdkTaskQueueDepth.collect(now),
+	} {
+		if m != nil {
+			metrics = append(metrics, *m)
+		}
+	}
+	for _, name := range healthGaugeNames {
+		if m := r.sdkHealth[name].collect(now); m != nil {
+			metrics = append(metrics, *m)
+		}
+	}
+	sdkScope := scope{Name: scopeName, Version: version.Version}
+	if len(metrics) > 0 {
+		req := &exportMetricsRequest{ResourceMetrics: []resourceMetrics{{
+			Resource:     r.resource,
+			ScopeMetrics: []scopeMetrics{{Scope: sdkScope, Metrics: metrics}},
+		}}}
+		if err := r.post(metricsPath, req); err != nil {
+			log.GetBaseLogger().Errorf("[metrics][opentelemetry] export metrics fail: %v", err)
+		}
+	}
+	spans, dropped := r.drainSpans()
+	if dropped > 0 {
+		log.GetBaseLogger().Warnf("[metrics][opentelemetry] span queue is full, dropped %d spans", dropped)
+	}
+	if len(spans) > 0 {
+		req := &exportTraceRequest{ResourceSpans: []resourceSpans{{
+			Resource:   r.resource,
+			ScopeSpans: []scopeSpans{{Scope: sdkScope, Spans: spans}},
+		}}}
+		if err := r.post(tracesPath, req); err != nil {
+			log.GetBaseLogger().Errorf("[metrics][opentelemetry] export %d spans fail: %v", len(spans), err)
+		}
+	}
+}
+
+func (r *Reporter) post(path string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, r.cfg.Endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range r.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, path)
+	}
+	return nil
+}
+
+// Info 插件信息
+func (r *Reporter) Info() model.StatInfo {
+	return model.StatInfo{}
+}
+
+// Destroy 销毁插件，导出剩余数据
+func (r *Reporter) Destroy() error {
+	if r.PluginBase != nil {
+		if err := r.PluginBase.Destroy(); err != nil {
+			return err
+		}
+	}
+	if r.RunContext != nil {
+		if err := r.RunContext.Destroy(); err != nil {
+			return err
+		}
+	}
+	if r.cancel != nil {
+		r.cancel()
+		<-r.stopped
+	}
+	return nil
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
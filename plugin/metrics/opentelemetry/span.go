@@ -0,0 +1,137 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// 路由决策写入 span 时使用的属性名
+const (
+	AttrRouteNamespace   = "polaris.route.namespace"
+	AttrRouteService     = "polaris.route.service"
+	AttrRouteInstance    = "polaris.route.instance"
+	AttrRouteInstanceID  = "polaris.route.instance_id"
+	AttrRouteRouterChain = "polaris.route.router_chain"
+	AttrRouteLbPolicy    = "polaris.route.lb_policy"
+	AttrRouteFallback    = "polaris.route.fallback"
+)
+
+// SpanAttributeSetter 调用方活跃 span 的属性写入接口，
+// 使用 opentelemetry-go 时可通过 SpanAttributeSetterFunc 适配 trace.Span 的 SetAttributes
+type SpanAttributeSetter interface {
+	// SetAttribute 写入字符串属性
+	SetAttribute(key, value string)
+}
+
+// SpanAttributeSetterFunc 函数形式的 SpanAttributeSetter
+type SpanAttributeSetterFunc func(key, value string)
+
+// SetAttribute 写入字符串属性
+func (f SpanAttributeSetterFunc) SetAttribute(key, value string) {
+	f(key, value)
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpan 将调用方的活跃 span 放入 context
+func ContextWithSpan(ctx context.Context, span SpanAttributeSetter) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext 从 context 中获取调用方的活跃 span，不存在时返回nil
+func SpanFromContext(ctx context.Context) SpanAttributeSetter {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(SpanAttributeSetter)
+	return span
+}
+
+// RouteDecision 一次服务发现的路由决策
+type RouteDecision struct {
+	Namespace   string
+	Service     string
+	Instance    model.Instance
+	RouterChain []string
+	LbPolicy    string
+	Fallback    bool
+}
+
+// NewRouteDecision 根据 GetOneInstance 的请求与应答构建路由决策，
+// 请求未指定路由链与负载均衡策略时使用 SDK 的全局配置
+func NewRouteDecision(cfg config.Configuration, req *model.GetOneInstanceRequest,
+	resp *model.OneInstanceResponse) *RouteDecision {
+	decision := &RouteDecision{}
+	if req != nil {
+		decision.Namespace = req.Namespace
+		decision.Service = req.Service
+		decision.RouterChain = req.Routers
+		decision.LbPolicy = req.LbPolicy
+	}
+	if cfg != nil {
+		if len(decision.RouterChain) == 0 {
+			decision.RouterChain = cfg.GetConsumer().GetServiceRouter().GetChain()
+		}
+		if decision.LbPolicy == "" {
+			decision.LbPolicy = cfg.GetConsumer().GetLoadbalancer().GetType()
+		}
+	}
+	if resp != nil {
+		decision.Instance = resp.GetInstance()
+		decision.Fallback = resp.Fallback
+	}
+	return decision
+}
+
+// Attributes 路由决策对应的 span 属性
+func (d *RouteDecision) Attributes() map[string]string {
+	attrs := map[string]string{
+		AttrRouteNamespace:   d.Namespace,
+		AttrRouteService:     d.Service,
+		AttrRouteRouterChain: strings.Join(d.RouterChain, ","),
+		AttrRouteLbPolicy:    d.LbPolicy,
+		AttrRouteFallback:    strconv.FormatBool(d.Fallback),
+	}
+	if d.Instance != nil {
+		attrs[AttrRouteInstance] = fmt.Sprintf("%s:%d", d.Instance.GetHost(), d.Instance.GetPort())
+		attrs[AttrRouteInstanceID] = d.Instance.GetId()
+	}
+	return attrs
+}
+
+// AnnotateSpan 将路由决策写入调用方的活跃 span
+func AnnotateSpan(span SpanAttributeSetter, decision *RouteDecision) {
+	if span == nil || decision == nil {
+		return
+	}
+	for k, v := range decision.Attributes() {
+		span.SetAttribute(k, v)
+	}
+}
+
+// AnnotateContextSpan 将路由决策写入 context 中的活跃 span，context 中没有 span 时不做处理
+func AnnotateContextSpan(ctx context.Context, decision *RouteDecision) {
+	AnnotateSpan(SpanFromContext(ctx), decision)
+}
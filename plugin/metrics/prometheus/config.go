@@ -17,6 +17,7 @@
 package prometheus
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
@@ -32,6 +33,9 @@ const (
 	defaultMetricPort     = 28080
 )
 
+// defaultDelayBuckets 调用时延直方图的默认分桶，单位秒
+var defaultDelayBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
 // Config prometheus 的配置
 type Config struct {
 	Type     string        `yaml:"type"`
@@ -40,10 +44,17 @@ type Config struct {
 	port     int           `yaml:"-"`
 	Interval time.Duration `yaml:"interval"`
 	Address  string        `yaml:"address"`
+	// DelayBuckets 调用时延直方图的分桶，单位秒
+	DelayBuckets []float64 `yaml:"delayBuckets"`
 }
 
 // Verify verify config
 func (c *Config) Verify() error {
+	for i := 1; i < len(c.DelayBuckets); i++ {
+		if c.DelayBuckets[i] <= c.DelayBuckets[i-1] {
+			return errors.New("prometheus delayBuckets must be in increasing order")
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,240 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package prometheus
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	statcommon "github.com/polarismesh/polaris-go/plugin/metrics/common"
+)
+
+var (
+	// delayLabelOrder 调用时延直方图的label
+	delayLabelOrder = []string{
+		statcommon.CalleeNamespace,
+		statcommon.CalleeService,
+		statcommon.CalleeMethod,
+		statcommon.CalleeRetCode,
+		statcommon.CalleeResult,
+		statcommon.CallerNamespace,
+		statcommon.CallerService,
+	}
+	// routeLabelOrder 路由结果的label
+	routeLabelOrder = []string{
+		statcommon.CalleeNamespace,
+		statcommon.CalleeService,
+		statcommon.CallerNamespace,
+		statcommon.CallerService,
+		statcommon.RouteRuleType,
+		statcommon.RouteStatus,
+		statcommon.RouteRetCode,
+	}
+	// cacheLabelOrder 本地缓存命中的label
+	cacheLabelOrder = []string{
+		statcommon.CalleeNamespace,
+		statcommon.CalleeService,
+	}
+)
+
+// governanceMetrics 使用 prometheus 原生类型记录的治理指标，拉取模式下可直接被采集
+type governanceMetrics struct {
+	rqDelay       *prometheus.HistogramVec
+	routeTotal    *prometheus.CounterVec
+	cacheTotal    *prometheus.CounterVec
+	cacheHit      *prometheus.CounterVec
+	cacheHitRatio *prometheus.GaugeVec
+	// cacheCounters 各服务的缓存命中计数，用于计算命中率
+	cacheCounters sync.Map
+	// health SDK自监控指标
+	health *healthMetrics
+}
+
+// healthMetrics SDK自监控指标，累计值同样以 gauge 的形式直接设置
+type healthMetrics struct {
+	cachedServices          prometheus.Gauge
+	cachedInstances         prometheus.Gauge
+	pendingDiscoverRequests prometheus.Gauge
+	reconnects              prometheus.Gauge
+	persistFailures         prometheus.Gauge
+	taskQueueDepth          *prometheus.GaugeVec
+	goroutines              prometheus.Gauge
+}
+
+type cacheCounter struct {
+	lock  sync.Mutex
+	hit   float64
+	total float64
+}
+
+func newGovernanceMetrics(delayBuckets []float64) *governanceMetrics {
+	return &governanceMetrics{
+		rqDelay: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    statcommon.MetricsNameUpstreamRequestDelaySeconds,
+			Help:    "latency of request to callee service in seconds",
+			Buckets: delayBuckets,
+		}, delayLabelOrder),
+		routeTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: statcommon.MetricsNameRouteRequestTotal,
+			Help: "total of service route result",
+		}, routeLabelOrder),
+		cacheTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: statcommon.MetricsNameCacheRequestTotal,
+			Help: "total of local cache lookup",
+		}, cacheLabelOrder),
+		cacheHit: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: statcommon.MetricsNameCacheRequestHit,
+			Help: "total of local cache hit",
+		}, cacheLabelOrder),
+		cacheHitRatio: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: statcommon.MetricsNameCacheHitRatio,
+			Help: "hit ratio of local cache",
+		}, cacheLabelOrder),
+		health: &healthMetrics{
+			cachedServices: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: statcommon.MetricsNameSDKCachedServices,
+				Help: "number of services with instances cached in sdk",
+			}),
+			cachedInstances: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: statcommon.MetricsNameSDKCachedInstances,
+				Help: "number of instances cached in sdk",
+			}),
+			pendingDiscoverRequests: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: statcommon.MetricsNameSDKPendingDiscoverRequests,
+				Help: "number of discover requests waiting for response",
+			}),
+			reconnects: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: statcommon.MetricsNameSDKReconnects,
+				Help: "total of reconnections to polaris server",
+			}),
+			persistFailures: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: statcommon.MetricsNameSDKPersistFailures,
+				Help: "total of local cache persist failures",
+			}),
+			taskQueueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: statcommon.MetricsNameSDKTaskQueueDepth,
+				Help: "number of tasks waiting in sdk task queue",
+			}, []string{statcommon.SDKTaskQueue}),
+			goroutines: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: statcommon.MetricsNameSDKGoroutines,
+				Help: "number of goroutines in process",
+			}),
+		},
+	}
+}
+
+// register 注册到指标仓库
+func (g *governanceMetrics) register(registry *prometheus.Registry) error {
+	collectors := []prometheus.Collector{g.rqDelay, g.routeTotal, g.cacheTotal, g.cacheHit, g.cacheHitRatio,
+		g.health.cachedServices, g.health.cachedInstances, g.health.pendingDiscoverRequests, g.health.reconnects,
+		g.health.persistFailures, g.health.taskQueueDepth, g.health.goroutines}
+	for i := range collectors {
+		if err := registry.Register(collectors[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// observeServiceCall 记录调用时延
+func (g *governanceMetrics) observeServiceCall(val *model.ServiceCallResult, labels map[string]string) {
+	if val.GetDelay() == nil {
+		return
+	}
+	g.rqDelay.WithLabelValues(pickLabels(labels, delayLabelOrder)...).Observe(val.GetDelay().Seconds())
+}
+
+// observeRoute 记录路由结果
+func (g *governanceMetrics) observeRoute(val *servicerouter.RouteGauge) {
+	calleeNamespace, calleeService := statcommon.NilValue, statcommon.NilValue
+	if val.ServiceInstances != nil {
+		calleeNamespace = val.ServiceInstances.GetNamespace()
+		calleeService = val.ServiceInstances.GetService()
+	}
+	g.routeTotal.WithLabelValues(
+		calleeNamespace,
+		calleeService,
+		labelValue(val.SrcService.Namespace),
+		labelValue(val.SrcService.Service),
+		routeRuleTypeLabel(val.RouteRuleType),
+		val.Status.String(),
+		strconv.Itoa(int(val.RetCode)),
+	).Inc()
+}
+
+// observeCache 记录本地缓存命中情况
+func (g *governanceMetrics) observeCache(val *model.CacheGauge) {
+	values := []string{labelValue(val.Namespace), labelValue(val.Service)}
+	count := float64(val.GetCount())
+	g.cacheTotal.WithLabelValues(values...).Add(count)
+	if val.Hit {
+		g.cacheHit.WithLabelValues(values...).Add(count)
+	}
+	value, _ := g.cacheCounters.LoadOrStore(val.Namespace+"/"+val.Service, &cacheCounter{})
+	counter := value.(*cacheCounter)
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+	counter.total += count
+	if val.Hit {
+		counter.hit += count
+	}
+	g.cacheHitRatio.WithLabelValues(values...).Set(counter.hit / counter.total)
+}
+
+// observeHealth 记录SDK自监控指标
+func (g *governanceMetrics) observeHealth(val *model.SDKHealthGauge) {
+	g.health.cachedServices.Set(float64(val.CachedServices))
+	g.health.cachedInstances.Set(float64(val.CachedInstances))
+	g.health.pendingDiscoverRequests.Set(float64(val.PendingDiscoverRequests))
+	g.health.reconnects.Set(float64(val.ReconnectCount))
+	g.health.persistFailures.Set(float64(val.PersistFailures))
+	g.health.goroutines.Set(float64(val.Goroutines))
+	for queue, depth := range val.TaskQueueDepths {
+		g.health.taskQueueDepth.WithLabelValues(queue).Set(float64(depth))
+	}
+}
+
+func pickLabels(labels map[string]string, order []string) []string {
+	values := make([]string, 0, len(order))
+	for i := range order {
+		values = append(values, labels[order[i]])
+	}
+	return values
+}
+
+func labelValue(v string) string {
+	if v == "" {
+		return statcommon.NilValue
+	}
+	return v
+}
+
+func routeRuleTypeLabel(ruleType servicerouter.RuleType) string {
+	switch ruleType {
+	case servicerouter.DestRule:
+		return "dest"
+	case servicerouter.SrcRule:
+		return "source"
+	default:
+		return "unknown"
+	}
+}
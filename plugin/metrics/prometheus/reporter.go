@@ -36,6 +36,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
 	statcommon "github.com/polarismesh/polaris-go/plugin/metrics/common"
 )
 
@@ -90,6 +91,8 @@ type PrometheusReporter struct {
 	insCollector            *statcommon.StatInfoRevisionCollector
 	rateLimitCollector      *statcommon.StatInfoRevisionCollector
 	circuitBreakerCollector *statcommon.StatInfoStatefulCollector
+	// governance 直接采集的治理指标
+	governance *governanceMetrics
 
 	cancel context.CancelFunc
 }
@@ -131,7 +134,12 @@ func (s *PrometheusReporter) Init(ctx *plugin.InitContext) error {
 	if err := s.initSampleMapping(statcommon.CircuitBreakerStrategy, statcommon.CircuitBreakerLabelOrder); err != nil {
 		return err
 	}
-	return nil
+	delayBuckets := defaultDelayBuckets
+	if s.cfg != nil && len(s.cfg.DelayBuckets) > 0 {
+		delayBuckets = s.cfg.DelayBuckets
+	}
+	s.governance = newGovernanceMetrics(delayBuckets)
+	return s.governance.register(s.registry)
 }
 
 // ReportStat 报告统计数据.
@@ -145,6 +153,7 @@ func (s *PrometheusReporter) ReportStat(metricsType model.MetricType, metricsVal
 				return nil
 			}
 			labels := statcommon.ConvertInsGaugeToLabels(val, s.clientIP)
+			s.governance.observeServiceCall(val, labels)
 			s.insCollector.CollectStatInfo(val, labels, statcommon.ServiceCallStrategy,
 				statcommon.ServiceCallLabelOrder)
 		}
@@ -161,13 +170,28 @@ func (s *PrometheusReporter) ReportStat(metricsType model.MetricType, metricsVal
 	case model.CircuitBreakStat:
 		val, ok := metricsVal.(*model.CircuitBreakGauge)
 		if ok {
-			if s.rateLimitCollector == nil || val == nil {
+			if s.circuitBreakerCollector == nil || val == nil {
 				return nil
 			}
 			labels := statcommon.ConvertCircuitBreakGaugeToLabels(val)
 			s.circuitBreakerCollector.CollectStatInfo(val, labels, statcommon.CircuitBreakerStrategy,
 				statcommon.CircuitBreakerLabelOrder)
 		}
+	case model.RouteStat:
+		val, ok := metricsVal.(*servicerouter.RouteGauge)
+		if ok && val != nil && s.governance != nil {
+			s.governance.observeRoute(val)
+		}
+	case model.CacheStat:
+		val, ok := metricsVal.(*model.CacheGauge)
+		if ok && val != nil && s.governance != nil {
+			s.governance.observeCache(val)
+		}
+	case model.SDKHealthStat:
+		val, ok := metricsVal.(*model.SDKHealthGauge)
+		if ok && val != nil && s.governance != nil {
+			s.governance.observeHealth(val)
+		}
 	}
 	return nil
 }
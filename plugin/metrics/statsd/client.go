@@ -0,0 +1,168 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package statsd
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	typeCounter = "c"
+	typeTiming  = "ms"
+	typeGauge   = "g"
+
+	writeTimeout = 100 * time.Millisecond
+)
+
+var (
+	// nameReplacer 替换指标名与标签名中 statsd 协议的保留字符
+	nameReplacer = strings.NewReplacer(":", "_", "|", "_", "@", "_", "#", "_", ",", "_", "\n", "_")
+	// tagValueReplacer 替换标签值中的保留字符，DogStatsD 允许标签值中出现冒号
+	tagValueReplacer = strings.NewReplacer("|", "_", "@", "_", "#", "_", ",", "_", "\n", "_")
+)
+
+// client 批量发送 statsd 协议数据的客户端，多条指标以换行分隔合并为一个数据包
+type client struct {
+	conn          net.Conn
+	prefix        string
+	enableTags    bool
+	constTags     string
+	maxPacketSize int
+
+	lock   sync.Mutex
+	buffer bytes.Buffer
+}
+
+// newClient 创建 statsd 客户端
+func newClient(cfg *Config) (*client, error) {
+	network, address := cfg.network()
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	c := &client{
+		conn:          conn,
+		prefix:        strings.TrimSuffix(cfg.Prefix, "."),
+		enableTags:    *cfg.EnableTags,
+		maxPacketSize: cfg.MaxPacketSize,
+	}
+	c.constTags = formatTags(cfg.Tags)
+	return c, nil
+}
+
+// count 上报计数
+func (c *client) count(name string, value int64, tags map[string]string) {
+	c.write(name, strconv.FormatInt(value, 10), typeCounter, tags)
+}
+
+// timing 上报耗时
+func (c *client) timing(name string, value time.Duration, tags map[string]string) {
+	ms := float64(value) / float64(time.Millisecond)
+	c.write(name, strconv.FormatFloat(ms, 'f', 3, 64), typeTiming, tags)
+}
+
+// gauge 上报当前值
+func (c *client) gauge(name string, value int64, tags map[string]string) {
+	c.write(name, strconv.FormatInt(value, 10), typeGauge, tags)
+}
+
+// write 组装一条指标并写入缓冲区，缓冲区放不下时先发送已有数据
+func (c *client) write(name string, value string, metricType string, tags map[string]string) {
+	var line strings.Builder
+	line.WriteString(c.prefix)
+	line.WriteByte('.')
+	line.WriteString(nameReplacer.Replace(name))
+	line.WriteByte(':')
+	line.WriteString(value)
+	line.WriteByte('|')
+	line.WriteString(metricType)
+	if c.enableTags {
+		tagStr := formatTags(tags)
+		if len(c.constTags) > 0 && len(tagStr) > 0 {
+			tagStr = c.constTags + "," + tagStr
+		} else if len(c.constTags) > 0 {
+			tagStr = c.constTags
+		}
+		if len(tagStr) > 0 {
+			line.WriteString("|#")
+			line.WriteString(tagStr)
+		}
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.buffer.Len() > 0 && c.buffer.Len()+1+line.Len() > c.maxPacketSize {
+		c.flushLocked()
+	}
+	if c.buffer.Len() > 0 {
+		c.buffer.WriteByte('\n')
+	}
+	c.buffer.WriteString(line.String())
+}
+
+// flush 发送缓冲区中的数据
+func (c *client) flush() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.flushLocked()
+}
+
+func (c *client) flushLocked() {
+	if c.buffer.Len() == 0 {
+		return
+	}
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	// statsd 基于不可靠传输，发送失败直接丢弃本批数据
+	_, _ = c.conn.Write(c.buffer.Bytes())
+	c.buffer.Reset()
+}
+
+// close 发送剩余数据并关闭连接
+func (c *client) close() error {
+	c.flush()
+	return c.conn.Close()
+}
+
+// formatTags 按标签名排序后输出 DogStatsD 格式的标签
+func formatTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var builder strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(nameReplacer.Replace(k))
+		if v := tags[k]; len(v) > 0 {
+			builder.WriteByte(':')
+			builder.WriteString(tagValueReplacer.Replace(v))
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,110 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package statsd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+)
+
+func init() {
+	plugin.RegisterConfigurablePlugin(&Reporter{}, &Config{})
+}
+
+const (
+	// DefaultAddress 默认的 statsd agent 地址
+	DefaultAddress = "udp://127.0.0.1:8125"
+	// DefaultPrefix 默认的指标名前缀
+	DefaultPrefix = "polaris"
+	// DefaultFlushInterval 默认的缓冲区刷新周期
+	DefaultFlushInterval = time.Second
+	// DefaultUDPPacketSize 默认的 UDP 单包大小，避免超过常见网络的 MTU
+	DefaultUDPPacketSize = 1432
+	// DefaultUDSPacketSize 默认的 UDS 单包大小
+	DefaultUDSPacketSize = 8192
+
+	schemeUDP  = "udp://"
+	schemeUnix = "unix://"
+)
+
+// Config statsd 插件配置
+type Config struct {
+	// Address statsd agent 地址，支持 udp://host:port 与 unix:///path/to/socket 两种格式
+	Address string `yaml:"address" json:"address"`
+	// Prefix 指标名前缀
+	Prefix string `yaml:"prefix" json:"prefix"`
+	// EnableTags 是否使用 DogStatsD 的标签扩展，关闭后只输出指标名与数值
+	EnableTags *bool `yaml:"enableTags" json:"enableTags"`
+	// Tags 附加在所有指标上的固定标签
+	Tags map[string]string `yaml:"tags" json:"tags"`
+	// FlushInterval 缓冲区刷新周期，缓冲区写满时会提前发送
+	FlushInterval time.Duration `yaml:"flushInterval" json:"flushInterval"`
+	// MaxPacketSize 单个数据包的最大字节数
+	MaxPacketSize int `yaml:"maxPacketSize" json:"maxPacketSize"`
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	var errs error
+	if !strings.HasPrefix(c.Address, schemeUDP) && !strings.HasPrefix(c.Address, schemeUnix) {
+		errs = multierror.Append(errs, fmt.Errorf("statsd.address must start with %s or %s", schemeUDP, schemeUnix))
+	}
+	if c.FlushInterval < 10*time.Millisecond {
+		errs = multierror.Append(errs, fmt.Errorf("statsd.flushInterval must be greater than 10ms"))
+	}
+	if c.MaxPacketSize <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("statsd.maxPacketSize must be greater than 0"))
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.Address == "" {
+		c.Address = DefaultAddress
+	}
+	if c.Prefix == "" {
+		c.Prefix = DefaultPrefix
+	}
+	if c.EnableTags == nil {
+		enable := true
+		c.EnableTags = &enable
+	}
+	if c.FlushInterval == 0 {
+		c.FlushInterval = DefaultFlushInterval
+	}
+	if c.MaxPacketSize == 0 {
+		c.MaxPacketSize = DefaultUDPPacketSize
+		if strings.HasPrefix(c.Address, schemeUnix) {
+			c.MaxPacketSize = DefaultUDSPacketSize
+		}
+	}
+}
+
+// network 返回地址对应的网络类型及拨号地址
+func (c *Config) network() (string, string) {
+	if strings.HasPrefix(c.Address, schemeUnix) {
+		return "unixgram", strings.TrimPrefix(c.Address, schemeUnix)
+	}
+	return "udp", strings.TrimPrefix(c.Address, schemeUDP)
+}
@@ -0,0 +1,237 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package statsd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+const (
+	// PluginName 插件名称
+	PluginName = "statsd"
+)
+
+var _ statreporter.StatReporter = (*Reporter)(nil)
+
+// Reporter 通过 StatsD/DogStatsD 协议上报调用结果及限流、熔断计数
+type Reporter struct {
+	*plugin.PluginBase
+	*common.RunContext
+	cfg     *Config
+	client  *client
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+// Type 插件类型
+func (r *Reporter) Type() common.Type {
+	return common.TypeStatReporter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (r *Reporter) Name() string {
+	return PluginName
+}
+
+// Init 初始化插件
+func (r *Reporter) Init(ctx *plugin.InitContext) error {
+	r.PluginBase = plugin.NewPluginBase(ctx)
+	r.RunContext = common.NewRunContext()
+	r.cfg = &Config{}
+	cfgValue := ctx.Config.GetGlobal().GetStatReporter().GetPluginConfig(PluginName)
+	if cfgValue != nil {
+		r.cfg = cfgValue.(*Config)
+	}
+	r.cfg.SetDefault()
+	return nil
+}
+
+// Start 启动插件，连接 statsd agent 并定期发送缓冲区数据
+func (r *Reporter) Start() error {
+	statsdClient, err := newClient(r.cfg)
+	if err != nil {
+		return fmt.Errorf("fail to connect statsd agent %s: %v", r.cfg.Address, err)
+	}
+	r.client = statsdClient
+	var ctx context.Context
+	ctx, r.cancel = context.WithCancel(context.Background())
+	r.stopped = make(chan struct{})
+	go r.run(ctx)
+	log.GetBaseLogger().Infof("[Metrics][StatsD] start reporting to %s", r.cfg.Address)
+	return nil
+}
+
+// run 定期发送缓冲区数据
+func (r *Reporter) run(ctx context.Context) {
+	defer close(r.stopped)
+	ticker := time.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.client.flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ReportStat 上报统计数据
+func (r *Reporter) ReportStat(metricsType model.MetricType, metricsVal model.InstanceGauge) error {
+	if r.client == nil {
+		return nil
+	}
+	switch metricsType {
+	case model.ServiceStat:
+		if val, ok := metricsVal.(*model.ServiceCallResult); ok && val != nil {
+			r.reportServiceCall(val)
+		}
+	case model.RateLimitStat:
+		if val, ok := metricsVal.(*model.RateLimitGauge); ok && val != nil {
+			result := "pass"
+			if val.Result == model.QuotaResultLimited {
+				result = "limit"
+			}
+			r.client.count("ratelimit.requests", 1, map[string]string{
+				"namespace": val.Namespace,
+				"service":   val.Service,
+				"method":    val.Method,
+				"rule":      val.RuleName,
+				"result":    result,
+			})
+		}
+	case model.CircuitBreakStat:
+		if val, ok := metricsVal.(*model.CircuitBreakGauge); ok && val != nil && val.CBStatus != nil &&
+			val.ChangeInstance != nil {
+			ins := val.ChangeInstance
+			r.client.count("circuitbreaker.transitions", 1, map[string]string{
+				"namespace": ins.GetNamespace(),
+				"service":   ins.GetService(),
+				"instance":  fmt.Sprintf("%s:%d", ins.GetHost(), ins.GetPort()),
+				"status":    val.CBStatus.GetStatus().String(),
+				"breaker":   val.CBStatus.GetCircuitBreaker(),
+			})
+		}
+	case model.RouteStat:
+		if val, ok := metricsVal.(*servicerouter.RouteGauge); ok && val != nil {
+			tags := map[string]string{
+				"status":   val.Status.String(),
+				"ret_code": strconv.Itoa(int(val.RetCode)),
+			}
+			if val.ServiceInstances != nil {
+				tags["namespace"] = val.ServiceInstances.GetNamespace()
+				tags["service"] = val.ServiceInstances.GetService()
+			}
+			r.client.count("route.requests", 1, tags)
+		}
+	case model.CacheStat:
+		if val, ok := metricsVal.(*model.CacheGauge); ok && val != nil {
+			r.client.count("cache.lookups", int64(val.GetCount()), map[string]string{
+				"namespace": val.Namespace,
+				"service":   val.Service,
+				"hit":       strconv.FormatBool(val.Hit),
+			})
+		}
+	case model.SDKHealthStat:
+		if val, ok := metricsVal.(*model.SDKHealthGauge); ok && val != nil {
+			r.reportHealth(val)
+		}
+	}
+	return nil
+}
+
+// reportHealth 上报 SDK 自监控指标
+func (r *Reporter) reportHealth(val *model.SDKHealthGauge) {
+	r.client.gauge("sdk.cached_services", int64(val.CachedServices), nil)
+	r.client.gauge("sdk.cached_instances", int64(val.CachedInstances), nil)
+	r.client.gauge("sdk.pending_discover_requests", int64(val.PendingDiscoverRequests), nil)
+	r.client.gauge("sdk.reconnects", val.ReconnectCount, nil)
+	r.client.gauge("sdk.persist_failures", val.PersistFailures, nil)
+	r.client.gauge("sdk.goroutines", int64(val.Goroutines), nil)
+	for queue, depth := range val.TaskQueueDepths {
+		r.client.gauge("sdk.task_queue_depth", int64(depth), map[string]string{"queue": queue})
+	}
+}
+
+// reportServiceCall 上报业务调用的结果与时延
+func (r *Reporter) reportServiceCall(val *model.ServiceCallResult) {
+	if val.CalledInstance == nil {
+		return
+	}
+	retCode := ""
+	if val.GetRetCode() != nil {
+		retCode = strconv.Itoa(int(*val.GetRetCode()))
+	}
+	tags := map[string]string{
+		"namespace":  val.CalledInstance.GetNamespace(),
+		"service":    val.CalledInstance.GetService(),
+		"method":     val.GetMethod(),
+		"instance":   fmt.Sprintf("%s:%d", val.CalledInstance.GetHost(), val.CalledInstance.GetPort()),
+		"ret_code":   retCode,
+		"ret_status": string(val.GetRetStatus()),
+	}
+	r.client.count("upstream.requests", 1, tags)
+	if val.GetDelay() != nil {
+		r.client.timing("upstream.duration", *val.GetDelay(), tags)
+	}
+}
+
+// Info 插件信息
+func (r *Reporter) Info() model.StatInfo {
+	return model.StatInfo{}
+}
+
+// Flush 立即发送缓冲区中的数据，在SDK上下文优雅销毁时调用
+func (r *Reporter) Flush(ctx context.Context) error {
+	if r.client != nil {
+		r.client.flush()
+	}
+	return nil
+}
+
+// Destroy 销毁插件，发送剩余数据
+func (r *Reporter) Destroy() error {
+	if r.PluginBase != nil {
+		if err := r.PluginBase.Destroy(); err != nil {
+			return err
+		}
+	}
+	if r.RunContext != nil {
+		if err := r.RunContext.Destroy(); err != nil {
+			return err
+		}
+	}
+	if r.cancel != nil {
+		r.cancel()
+		<-r.stopped
+	}
+	if r.client != nil {
+		return r.client.close()
+	}
+	return nil
+}
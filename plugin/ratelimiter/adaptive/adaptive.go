@@ -0,0 +1,123 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package adaptive
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// RateLimiterAdaptive 基于BBR算法的自适应限流控制器
+// 根据CPU使用率以及统计到的最大通过量和最小时延，估算系统最大承载的并发数，超过后拒绝请求
+// 并发数在获取配额时增加，在QuotaFuture.Release时减少，因此使用时必须在请求结束后释放配额，
+// 否则并发数只增不减，CPU超过阈值后会持续限流
+type RateLimiterAdaptive struct {
+	*plugin.PluginBase
+	cfg        *Config
+	cpuSampler *cpuSampler
+	// 无规则模式下，服务接口到配额池的映射
+	ruleFreeBuckets sync.Map
+	// 无规则模式配额池的淘汰周期，空闲超过该时间的配额池会被淘汰
+	purgeIntervalMilli int64
+	lastPurgeTimeMilli int64
+}
+
+// Type 插件类型
+func (a *RateLimiterAdaptive) Type() common.Type {
+	return common.TypeRateLimiter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (a *RateLimiterAdaptive) Name() string {
+	return config.DefaultAdaptiveRateLimiter
+}
+
+// Init 初始化插件
+func (a *RateLimiterAdaptive) Init(ctx *plugin.InitContext) error {
+	a.PluginBase = plugin.NewPluginBase(ctx)
+	a.cfg = ctx.Config.GetProvider().GetRateLimit().GetPluginConfig(a.Name()).(*Config)
+	a.cpuSampler = newCPUSampler()
+	a.purgeIntervalMilli = model.ToMilliSeconds(ctx.Config.GetProvider().GetRateLimit().GetPurgeInterval())
+	a.lastPurgeTimeMilli = model.CurrentMillisecond()
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (a *RateLimiterAdaptive) Destroy() error {
+	if a.cpuSampler != nil {
+		a.cpuSampler.stop()
+	}
+	return nil
+}
+
+// IsEnable enable
+func (a *RateLimiterAdaptive) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// InitQuota 初始化并创建配额池
+// 主流程会在首次调用，以及规则对象变更的时候，调用该方法
+func (a *RateLimiterAdaptive) InitQuota(criteria *ratelimiter.InitCriteria) ratelimiter.QuotaBucket {
+	a.cpuSampler.start()
+	return newBBRBucket(a.cfg, a.cpuSampler)
+}
+
+// GetRuleFreeBucket 未配置限流规则的服务，开启enableWithoutRule后也使用自适应限流
+func (a *RateLimiterAdaptive) GetRuleFreeBucket(svcKey model.ServiceKey, method string) ratelimiter.QuotaBucket {
+	if !a.cfg.EnableWithoutRule {
+		return nil
+	}
+	a.purgeRuleFreeBuckets(model.CurrentMillisecond())
+	key := svcKey.Namespace + config.DefaultNamesSeparator + svcKey.Service + config.DefaultNamesSeparator + method
+	bucket, ok := a.ruleFreeBuckets.Load(key)
+	if !ok {
+		a.cpuSampler.start()
+		bucket, _ = a.ruleFreeBuckets.LoadOrStore(key, newBBRBucket(a.cfg, a.cpuSampler))
+	}
+	return bucket.(ratelimiter.QuotaBucket)
+}
+
+// purgeRuleFreeBuckets 每个淘汰周期检查一次，淘汰空闲超过一个周期且没有在途请求的配额池
+// 检查与删除之间被取走的配额池仍可正常分配和释放，只是统计数据不再保留
+func (a *RateLimiterAdaptive) purgeRuleFreeBuckets(nowMilli int64) {
+	lastPurgeTimeMilli := atomic.LoadInt64(&a.lastPurgeTimeMilli)
+	if nowMilli-lastPurgeTimeMilli < a.purgeIntervalMilli {
+		// 未达到检查时间
+		return
+	}
+	if !atomic.CompareAndSwapInt64(&a.lastPurgeTimeMilli, lastPurgeTimeMilli, nowMilli) {
+		return
+	}
+	a.ruleFreeBuckets.Range(func(key, value interface{}) bool {
+		if value.(*bbrBucket).idle(nowMilli, a.purgeIntervalMilli) {
+			a.ruleFreeBuckets.Delete(key)
+		}
+		return true
+	})
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&RateLimiterAdaptive{}, &Config{})
+}
@@ -0,0 +1,85 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package adaptive
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func newTestRateLimiter(enableWithoutRule bool) *RateLimiterAdaptive {
+	cfg := newTestConfig()
+	cfg.EnableWithoutRule = enableWithoutRule
+	return &RateLimiterAdaptive{
+		cfg:                cfg,
+		cpuSampler:         newCPUSampler(),
+		purgeIntervalMilli: time.Minute.Milliseconds(),
+		lastPurgeTimeMilli: model.CurrentMillisecond(),
+	}
+}
+
+func TestRateLimiterAdaptiveGetRuleFreeBucket(t *testing.T) {
+	svcKey := model.ServiceKey{Namespace: "Test", Service: "svc"}
+
+	disabled := newTestRateLimiter(false)
+	defer disabled.Destroy()
+	assert.Nil(t, disabled.GetRuleFreeBucket(svcKey, "m1"))
+
+	limiter := newTestRateLimiter(true)
+	defer limiter.Destroy()
+	bucket := limiter.GetRuleFreeBucket(svcKey, "m1")
+	assert.NotNil(t, bucket)
+	assert.Same(t, bucket, limiter.GetRuleFreeBucket(svcKey, "m1"))
+	assert.NotSame(t, bucket, limiter.GetRuleFreeBucket(svcKey, "m2"))
+}
+
+func TestRateLimiterAdaptivePurgeRuleFreeBuckets(t *testing.T) {
+	svcKey := model.ServiceKey{Namespace: "Test", Service: "svc"}
+	limiter := newTestRateLimiter(true)
+	defer limiter.Destroy()
+	idle := limiter.GetRuleFreeBucket(svcKey, "idle").(*bbrBucket)
+	inFlight := limiter.GetRuleFreeBucket(svcKey, "inFlight").(*bbrBucket)
+	recent := limiter.GetRuleFreeBucket(svcKey, "recent").(*bbrBucket)
+
+	now := model.CurrentMillisecond() + 2*limiter.purgeIntervalMilli
+	atomic.StoreInt64(&idle.lastAccessTimeMilli, now-limiter.purgeIntervalMilli-1)
+	atomic.StoreInt64(&inFlight.lastAccessTimeMilli, now-limiter.purgeIntervalMilli-1)
+	atomic.StoreInt64(&inFlight.inFlight, 1)
+	atomic.StoreInt64(&recent.lastAccessTimeMilli, now)
+
+	exists := func(method string) bool {
+		_, ok := limiter.ruleFreeBuckets.Load("Test#svc#" + method)
+		return ok
+	}
+	limiter.purgeRuleFreeBuckets(now)
+	assert.False(t, exists("idle"))
+	assert.True(t, exists("inFlight"))
+	assert.True(t, exists("recent"))
+
+	// 未达到下一个淘汰周期时不检查
+	atomic.StoreInt64(&inFlight.inFlight, 0)
+	limiter.purgeRuleFreeBuckets(now + 1)
+	assert.True(t, exists("inFlight"))
+	limiter.purgeRuleFreeBuckets(now + limiter.purgeIntervalMilli)
+	assert.False(t, exists("inFlight"))
+}
@@ -0,0 +1,180 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package adaptive
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+const (
+	// 自适应限流的限流提示信息
+	adaptiveLimitedInfo = "adaptive rate limited"
+)
+
+// windowBucket 统计窗口中的单个桶
+type windowBucket struct {
+	// 桶对应的时间序号
+	seq     int64
+	pass    int64
+	rtSum   int64
+	rtCount int64
+}
+
+// bbrBucket 基于BBR算法的配额池
+type bbrBucket struct {
+	cfg            *Config
+	cpuSampler     *cpuSampler
+	bucketDuration int64
+	mutex          sync.Mutex
+	buckets        []windowBucket
+	inFlight       int64
+	// 最近一次触发限流的时间，纳秒
+	prevDropTime int64
+	// 最近一次获取配额的时间，毫秒
+	lastAccessTimeMilli int64
+}
+
+func newBBRBucket(cfg *Config, sampler *cpuSampler) *bbrBucket {
+	return &bbrBucket{
+		cfg:                 cfg,
+		cpuSampler:          sampler,
+		bucketDuration:      int64(cfg.Window) / int64(cfg.Buckets),
+		buckets:             make([]windowBucket, cfg.Buckets),
+		lastAccessTimeMilli: model.CurrentMillisecond(),
+	}
+}
+
+// GetQuota 判断系统是否过载，未过载时增加并发数
+func (b *bbrBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
+	atomic.StoreInt64(&b.lastAccessTimeMilli, curTimeMs)
+	if b.shouldDrop(time.Now().UnixNano()) {
+		return &model.QuotaResponse{
+			Code: model.QuotaResultLimited,
+			Info: adaptiveLimitedInfo,
+		}
+	}
+	atomic.AddInt64(&b.inFlight, 1)
+	return &model.QuotaResponse{
+		Code: model.QuotaResultOk,
+	}
+}
+
+// Release 释放并发数，未知耗时时不纳入统计
+func (b *bbrBucket) Release() {
+	atomic.AddInt64(&b.inFlight, -1)
+}
+
+// ReleaseWithDuration 释放并发数，并统计通过量及时延
+func (b *bbrBucket) ReleaseWithDuration(duration time.Duration) {
+	atomic.AddInt64(&b.inFlight, -1)
+	b.record(time.Now().UnixNano(), duration)
+}
+
+// record 在now所在的桶中统计一次通过及其时延
+func (b *bbrBucket) record(now int64, duration time.Duration) {
+	seq := now / b.bucketDuration
+	b.mutex.Lock()
+	bucket := b.currentBucket(seq)
+	bucket.pass++
+	bucket.rtSum += duration.Milliseconds()
+	bucket.rtCount++
+	b.mutex.Unlock()
+}
+
+// OnRemoteUpdate 自适应限流只在本地生效
+func (b *bbrBucket) OnRemoteUpdate(ratelimiter.RemoteQuotaResult) {
+}
+
+// GetQuotaUsed 自适应限流不上报配额使用情况
+func (b *bbrBucket) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageInfo {
+	return ratelimiter.UsageInfo{CurTimeMilli: curTimeMilli}
+}
+
+// GetAmountInfos 自适应限流没有固定的限流阈值
+func (b *bbrBucket) GetAmountInfos() []ratelimiter.AmountInfo {
+	return nil
+}
+
+// idle 判断配额池是否空闲超过expireMilli，且没有在途的请求
+func (b *bbrBucket) idle(nowMilli int64, expireMilli int64) bool {
+	return atomic.LoadInt64(&b.inFlight) <= 0 &&
+		nowMilli-atomic.LoadInt64(&b.lastAccessTimeMilli) > expireMilli
+}
+
+// currentBucket 获取时间序号对应的桶，桶过期时重置，需要持有锁
+func (b *bbrBucket) currentBucket(seq int64) *windowBucket {
+	bucket := &b.buckets[seq%int64(len(b.buckets))]
+	if bucket.seq != seq {
+		*bucket = windowBucket{seq: seq}
+	}
+	return bucket
+}
+
+// maxInFlight 根据窗口内的最大通过量和最小时延估算系统最大承载的并发数
+func (b *bbrBucket) maxInFlight(now int64) int64 {
+	seq := now / b.bucketDuration
+	var maxPass int64 = 1
+	var minRt = math.MaxFloat64
+	b.mutex.Lock()
+	for i := range b.buckets {
+		bucket := &b.buckets[i]
+		// 只统计窗口内已经结束的桶
+		if bucket.seq >= seq || bucket.seq <= seq-int64(len(b.buckets)) {
+			continue
+		}
+		if bucket.pass > maxPass {
+			maxPass = bucket.pass
+		}
+		if bucket.rtCount > 0 {
+			minRt = math.Min(minRt, float64(bucket.rtSum)/float64(bucket.rtCount))
+		}
+	}
+	b.mutex.Unlock()
+	if minRt == math.MaxFloat64 || minRt <= 0 {
+		minRt = 1
+	}
+	bucketsPerSecond := float64(time.Second) / float64(b.bucketDuration)
+	return int64(math.Floor(float64(maxPass)*minRt*bucketsPerSecond/1000.0 + 0.5))
+}
+
+// shouldDrop 判断是否需要拒绝请求
+func (b *bbrBucket) shouldDrop(now int64) bool {
+	inFlight := atomic.LoadInt64(&b.inFlight)
+	prevDropTime := atomic.LoadInt64(&b.prevDropTime)
+	if b.cpuSampler.usage() < b.cfg.CPUThreshold {
+		if prevDropTime == 0 {
+			return false
+		}
+		if now-prevDropTime <= int64(b.cfg.CoolDown) {
+			return inFlight > 1 && inFlight > b.maxInFlight(now)
+		}
+		atomic.CompareAndSwapInt64(&b.prevDropTime, prevDropTime, 0)
+		return false
+	}
+	drop := inFlight > 1 && inFlight > b.maxInFlight(now)
+	if drop && prevDropTime == 0 {
+		atomic.CompareAndSwapInt64(&b.prevDropTime, 0, now)
+	}
+	return drop
+}
@@ -0,0 +1,141 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package adaptive
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// newTestConfig 1s窗口10个桶，每秒10个桶
+func newTestConfig() *Config {
+	return &Config{
+		CPUThreshold: DefaultCPUThreshold,
+		Window:       time.Second,
+		Buckets:      10,
+		CoolDown:     time.Second,
+	}
+}
+
+// newTestBBRBucket 创建配额池，base为对齐到桶边界的起始时间
+func newTestBBRBucket(cpu int64) (*bbrBucket, int64) {
+	sampler := newCPUSampler()
+	atomic.StoreInt64(&sampler.value, cpu)
+	bucket := newBBRBucket(newTestConfig(), sampler)
+	return bucket, 1000 * bucket.bucketDuration
+}
+
+// fillBuckets 第0个桶100次10ms，第1个桶50次20ms，估算的最大并发数为 100*10ms*10/1000 = 10
+func fillBuckets(bucket *bbrBucket, base int64) {
+	for i := 0; i < 100; i++ {
+		bucket.record(base, 10*time.Millisecond)
+	}
+	for i := 0; i < 50; i++ {
+		bucket.record(base+bucket.bucketDuration, 20*time.Millisecond)
+	}
+}
+
+func TestBBRBucketMaxInFlight(t *testing.T) {
+	bucket, base := newTestBBRBucket(0)
+	fillBuckets(bucket, base)
+	// 当前桶的统计不计入
+	for i := 0; i < 1000; i++ {
+		bucket.record(base+2*bucket.bucketDuration, time.Millisecond)
+	}
+	assert.Equal(t, int64(10), bucket.maxInFlight(base+2*bucket.bucketDuration))
+	// 当前桶结束后计入，最大通过量1000，最小时延1ms
+	assert.Equal(t, int64(10), bucket.maxInFlight(base+3*bucket.bucketDuration))
+	// 滑出窗口后不计入
+	assert.Equal(t, int64(0), bucket.maxInFlight(base+20*bucket.bucketDuration))
+}
+
+func TestBBRBucketShouldDrop(t *testing.T) {
+	tests := []struct {
+		name     string
+		cpu      int64
+		inFlight int64
+		want     bool
+	}{
+		{name: "CPU未超过阈值时不限流", cpu: 500, inFlight: 100, want: false},
+		{name: "CPU超过阈值且并发数超过估算值时限流", cpu: 900, inFlight: 11, want: true},
+		{name: "CPU超过阈值但并发数未超过估算值", cpu: 900, inFlight: 10, want: false},
+		{name: "单个在途请求不限流", cpu: 1000, inFlight: 1, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket, base := newTestBBRBucket(tt.cpu)
+			fillBuckets(bucket, base)
+			bucket.inFlight = tt.inFlight
+			now := base + 2*bucket.bucketDuration
+			assert.Equal(t, tt.want, bucket.shouldDrop(now))
+			if tt.want {
+				assert.Equal(t, now, atomic.LoadInt64(&bucket.prevDropTime))
+			} else {
+				assert.Equal(t, int64(0), atomic.LoadInt64(&bucket.prevDropTime))
+			}
+		})
+	}
+}
+
+func TestBBRBucketCoolDown(t *testing.T) {
+	bucket, base := newTestBBRBucket(900)
+	fillBuckets(bucket, base)
+	bucket.inFlight = 11
+	dropTime := base + 2*bucket.bucketDuration
+	assert.True(t, bucket.shouldDrop(dropTime))
+
+	// CPU恢复后，冷却时间内仍按并发数限流
+	atomic.StoreInt64(&bucket.cpuSampler.value, 500)
+	now := dropTime + int64(500*time.Millisecond)
+	assert.True(t, bucket.shouldDrop(now))
+	bucket.inFlight = 5
+	assert.False(t, bucket.shouldDrop(now))
+	assert.Equal(t, dropTime, atomic.LoadInt64(&bucket.prevDropTime))
+
+	// 冷却时间过后不再限流，并重置限流时间
+	bucket.inFlight = 100
+	assert.False(t, bucket.shouldDrop(dropTime+int64(2*time.Second)))
+	assert.Equal(t, int64(0), atomic.LoadInt64(&bucket.prevDropTime))
+}
+
+func TestBBRBucketGetQuotaAndRelease(t *testing.T) {
+	bucket, _ := newTestBBRBucket(0)
+	now := model.CurrentMillisecond()
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(now+int64(i), 1).Code)
+	}
+	assert.Equal(t, int64(3), atomic.LoadInt64(&bucket.inFlight))
+	assert.Equal(t, now+2, atomic.LoadInt64(&bucket.lastAccessTimeMilli))
+
+	bucket.Release()
+	bucket.ReleaseWithDuration(5 * time.Millisecond)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&bucket.inFlight))
+	// 只有感知耗时的释放计入统计
+	var pass, rtSum int64
+	for _, b := range bucket.buckets {
+		pass += b.pass
+		rtSum += b.rtSum
+	}
+	assert.Equal(t, int64(1), pass)
+	assert.Equal(t, int64(5), rtSum)
+}
@@ -0,0 +1,84 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package adaptive
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DefaultCPUThreshold 默认的CPU使用率阈值，千分比
+	DefaultCPUThreshold = 800
+	// DefaultWindow 默认的统计窗口
+	DefaultWindow = 10 * time.Second
+	// DefaultBuckets 默认的统计窗口桶数
+	DefaultBuckets = 100
+	// DefaultCoolDown 默认的限流冷却时间
+	DefaultCoolDown = time.Second
+)
+
+// Config 自适应限流配置对象
+type Config struct {
+	// EnableWithoutRule 是否对没有配置限流规则的服务也开启自适应限流
+	EnableWithoutRule bool `yaml:"enableWithoutRule" json:"enableWithoutRule"`
+	// CPUThreshold CPU使用率阈值，千分比，超过后按照系统最大承载的并发数进行限流
+	CPUThreshold int64 `yaml:"cpuThreshold" json:"cpuThreshold"`
+	// Window 通过量及时延的统计窗口
+	Window time.Duration `yaml:"window" json:"window"`
+	// Buckets 统计窗口的桶数
+	Buckets int `yaml:"buckets" json:"buckets"`
+	// CoolDown CPU恢复到阈值以下后，继续按照并发数进行限流的冷却时间
+	CoolDown time.Duration `yaml:"coolDown" json:"coolDown"`
+}
+
+// Verify 检验自适应限流配置
+func (c *Config) Verify() error {
+	var errs error
+	if c.CPUThreshold <= 0 || c.CPUThreshold > 1000 {
+		errs = multierror.Append(errs, fmt.Errorf("adaptive.cpuThreshold must be in (0, 1000]"))
+	}
+	if c.Buckets <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("adaptive.buckets must be greater than 0"))
+	}
+	if c.Window < time.Duration(c.Buckets)*time.Millisecond {
+		errs = multierror.Append(errs, fmt.Errorf("adaptive.window must be at least 1ms per bucket"))
+	}
+	if c.CoolDown < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("adaptive.coolDown must not be negative"))
+	}
+	return errs
+}
+
+// SetDefault 设置自适应限流默认值
+func (c *Config) SetDefault() {
+	if c.CPUThreshold == 0 {
+		c.CPUThreshold = DefaultCPUThreshold
+	}
+	if c.Window == 0 {
+		c.Window = DefaultWindow
+	}
+	if c.Buckets == 0 {
+		c.Buckets = DefaultBuckets
+	}
+	if c.CoolDown == 0 {
+		c.CoolDown = DefaultCoolDown
+	}
+}
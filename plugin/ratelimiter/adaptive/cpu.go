@@ -0,0 +1,83 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package adaptive
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/metric"
+)
+
+const (
+	// CPU使用率采样周期
+	cpuSampleInterval = 250 * time.Millisecond
+	// CPU使用率滑动平均的衰减系数
+	cpuDecay = 0.95
+)
+
+// cpuSampler 定期采样CPU使用率，使用率为千分比
+// 首次创建配额池时才开始采样，插件加载但未使用时不占用后台协程
+type cpuSampler struct {
+	value     int64
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+}
+
+func newCPUSampler() *cpuSampler {
+	return &cpuSampler{stopCh: make(chan struct{})}
+}
+
+// start 开始采样，多次调用只会启动一次，stop之后调用不再启动
+func (s *cpuSampler) start() {
+	s.startOnce.Do(func() {
+		go s.run()
+	})
+}
+
+// usage 获取滑动平均后的CPU使用率
+func (s *cpuSampler) usage() int64 {
+	return atomic.LoadInt64(&s.value)
+}
+
+func (s *cpuSampler) run() {
+	ticker := time.NewTicker(cpuSampleInterval)
+	defer ticker.Stop()
+	reader := metric.NewCPUReader()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			current, ok := reader.Read()
+			if !ok {
+				continue
+			}
+			prev := atomic.LoadInt64(&s.value)
+			atomic.StoreInt64(&s.value, int64(float64(prev)*cpuDecay+float64(current)*(1-cpuDecay)))
+		}
+	}
+}
+
+func (s *cpuSampler) stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
@@ -19,7 +19,6 @@
 package common
 
 import (
-	"sync"
 	"sync/atomic"
 )
 
@@ -28,7 +27,6 @@ func NewSlidingWindow(slideCount int, intervalMs int) *SlidingWindow {
 	slidingWindow := &SlidingWindow{}
 	slidingWindow.intervalMs = intervalMs
 	slidingWindow.slideCount = slideCount
-	slidingWindow.mutex = &sync.Mutex{}
 	slidingWindow.windowLengthMs = intervalMs / slideCount
 	slidingWindow.windowArray = make([]*Window, slideCount)
 	for i := 0; i < slideCount; i++ {
@@ -64,10 +62,8 @@ func (s *SlidingWindow) currentWindow(curTimeMs int64, reset bool) (*Window, *Wi
 	} else if !reset {
 		return nil, nil
 	}
-	s.mutex.Lock()
-	expiredWindow := oldWindow.reset(oldWindowStart, windowStart)
-	s.mutex.Unlock()
-	return oldWindow, expiredWindow
+	// 窗口轮转通过CAS完成，并发时仅有一个调用方能取到过期窗口的数据
+	return oldWindow, oldWindow.reset(oldWindowStart, windowStart)
 }
 
 // AddAndGetCurrentPassed 原子增加，并返回当前bucket
@@ -103,8 +99,6 @@ type SlidingWindow struct {
 	windowLengthMs int
 	// 所有窗口总长度
 	intervalMs int
-	// 更新锁
-	mutex *sync.Mutex
 	// 滑窗列表
 	windowArray []*Window
 	// 滑窗数
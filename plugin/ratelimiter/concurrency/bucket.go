@@ -0,0 +1,96 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package concurrency
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// semaphoreBucket 基于信号量的配额池，每次获取配额占用一个并发数
+type semaphoreBucket struct {
+	// 最大并发数，取规则中最小的配额数
+	maxPermits int64
+	// 当前占用的并发数
+	acquired int64
+}
+
+func newSemaphoreBucket(criteria *ratelimiter.InitCriteria) *semaphoreBucket {
+	bucket := &semaphoreBucket{maxPermits: -1}
+	for _, amount := range criteria.DstRule.GetAmounts() {
+		maxAmount := int64(amount.GetMaxAmount().GetValue())
+		if bucket.maxPermits < 0 || maxAmount < bucket.maxPermits {
+			bucket.maxPermits = maxAmount
+		}
+	}
+	return bucket
+}
+
+// GetQuota 占用一个并发数，并发数已满时拒绝
+func (s *semaphoreBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
+	if s.maxPermits < 0 {
+		return &model.QuotaResponse{
+			Code: model.QuotaResultOk,
+			Info: "rule has no amount config",
+		}
+	}
+	for {
+		acquired := atomic.LoadInt64(&s.acquired)
+		if acquired >= s.maxPermits {
+			return &model.QuotaResponse{
+				Code: model.QuotaResultLimited,
+				Info: fmt.Sprintf("concurrency RateLimiter: concurrency %d reach max %d", acquired, s.maxPermits),
+			}
+		}
+		if atomic.CompareAndSwapInt64(&s.acquired, acquired, acquired+1) {
+			return &model.QuotaResponse{
+				Code: model.QuotaResultOk,
+			}
+		}
+	}
+}
+
+// Release 归还一个并发数
+func (s *semaphoreBucket) Release() {
+	for {
+		acquired := atomic.LoadInt64(&s.acquired)
+		if acquired <= 0 || atomic.CompareAndSwapInt64(&s.acquired, acquired, acquired-1) {
+			return
+		}
+	}
+}
+
+// OnRemoteUpdate 并发数限流只在本地生效
+func (s *semaphoreBucket) OnRemoteUpdate(ratelimiter.RemoteQuotaResult) {
+}
+
+// GetQuotaUsed 并发数限流不上报配额使用情况
+func (s *semaphoreBucket) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageInfo {
+	return ratelimiter.UsageInfo{CurTimeMilli: curTimeMilli}
+}
+
+// GetAmountInfos 并发数限流没有时间周期
+func (s *semaphoreBucket) GetAmountInfos() []ratelimiter.AmountInfo {
+	if s.maxPermits < 0 {
+		return nil
+	}
+	return []ratelimiter.AmountInfo{{MaxAmount: uint32(s.maxPermits)}}
+}
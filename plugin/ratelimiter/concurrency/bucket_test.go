@@ -0,0 +1,122 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package concurrency
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+func newTestBucket(maxAmounts ...uint32) *semaphoreBucket {
+	rule := &apitraffic.Rule{Resource: apitraffic.Rule_CONCURRENCY}
+	for _, maxAmount := range maxAmounts {
+		rule.Amounts = append(rule.Amounts, &apitraffic.Amount{
+			MaxAmount:     wrapperspb.UInt32(maxAmount),
+			ValidDuration: durationpb.New(time.Second),
+		})
+	}
+	return newSemaphoreBucket(&ratelimiter.InitCriteria{DstRule: rule, WindowKey: "test"})
+}
+
+func TestSemaphoreBucket(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxAmounts []uint32
+		// 依次执行的操作，true为获取配额，false为释放配额
+		steps []bool
+		want  []model.QuotaResultCode
+	}{
+		{
+			name:       "并发数已满时拒绝，释放后恢复",
+			maxAmounts: []uint32{2},
+			steps:      []bool{true, true, true, false, true, true},
+			want: []model.QuotaResultCode{
+				model.QuotaResultOk, model.QuotaResultOk, model.QuotaResultLimited,
+				model.QuotaResultOk, model.QuotaResultLimited,
+			},
+		},
+		{
+			name:       "多个配额取最小值",
+			maxAmounts: []uint32{3, 1},
+			steps:      []bool{true, true},
+			want:       []model.QuotaResultCode{model.QuotaResultOk, model.QuotaResultLimited},
+		},
+		{
+			name:       "多余的释放不会使并发数为负",
+			maxAmounts: []uint32{1},
+			steps:      []bool{false, false, true, true},
+			want:       []model.QuotaResultCode{model.QuotaResultOk, model.QuotaResultLimited},
+		},
+		{
+			name:       "配额为0时全部拒绝",
+			maxAmounts: []uint32{0},
+			steps:      []bool{true},
+			want:       []model.QuotaResultCode{model.QuotaResultLimited},
+		},
+		{
+			name:  "未配置配额时放通",
+			steps: []bool{true, true},
+			want:  []model.QuotaResultCode{model.QuotaResultOk, model.QuotaResultOk},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := newTestBucket(tt.maxAmounts...)
+			var got []model.QuotaResultCode
+			for _, acquire := range tt.steps {
+				if !acquire {
+					bucket.Release()
+					continue
+				}
+				got = append(got, bucket.GetQuota(0, 1).Code)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSemaphoreBucketConcurrent(t *testing.T) {
+	const maxPermits = 5
+	bucket := newTestBucket(maxPermits)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var passed int
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if bucket.GetQuota(0, 1).Code == model.QuotaResultOk {
+				mutex.Lock()
+				passed++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, maxPermits, passed)
+	assert.Equal(t, []ratelimiter.AmountInfo{{MaxAmount: maxPermits}}, bucket.GetAmountInfos())
+}
@@ -46,6 +46,11 @@ func (q *QuotaBucketReject) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageIn
 	return q.bucket.GetQuotaUsed(curTimeMilli)
 }
 
+// RestoreQuotaUsed 归还上报失败的使用情况，在下次上报时重新同步
+func (q *QuotaBucketReject) RestoreQuotaUsed(curTimeMilli int64, usage ratelimiter.UsageInfo) {
+	q.bucket.RestoreQuotaUsed(curTimeMilli, usage)
+}
+
 // GetAmountInfos 获取规则的限流阈值信息
 func (q *QuotaBucketReject) GetAmountInfos() []ratelimiter.AmountInfo {
 	tokenBuckets := q.bucket.GetTokenBuckets()
@@ -35,12 +35,13 @@ import (
 )
 
 // NewRemoteAwareQpsBucket 创建QPS远程限流窗口
-func NewRemoteAwareQpsBucket(criteria *ratelimiter.InitCriteria) *RemoteAwareQpsBucket {
+// degradePolicy 为限流服务端不可用时的降级策略
+func NewRemoteAwareQpsBucket(criteria *ratelimiter.InitCriteria, degradePolicy string) *RemoteAwareQpsBucket {
 	raqb := &RemoteAwareQpsBucket{
 		uniqueKey:      criteria.WindowKey,
 		identifierPool: &sync.Pool{},
 	}
-	raqb.tokenBuckets = initTokenBuckets(criteria.DstRule, criteria.WindowKey)
+	raqb.tokenBuckets = initTokenBuckets(criteria.DstRule, criteria.WindowKey, degradePolicy)
 	raqb.tokenBucketMap = make(map[int64]*TokenBucket, len(raqb.tokenBuckets))
 	for _, tokenBucket := range raqb.tokenBuckets {
 		raqb.tokenBucketMap[tokenBucket.validDurationMilli] = tokenBucket
@@ -105,13 +106,13 @@ func (r *RemoteAwareQpsBucket) Allocate(curTimeMs int64, token uint32) *model.Qu
 			break
 		}
 	}
-	usedRemoteQuota := mode == Remote
+	// 远程及远程降级时记录滑窗，滑窗用于上报，降级期间的用量在服务端恢复后上报以重新同步配额
+	usedRemoteQuota := mode == Remote || mode == RemoteToLocal
 	// 有一个扣除不成功，则进行限流
 	if stopIndex >= 0 {
 		// 出现了限流
 		tokenBucket := r.tokenBuckets[stopIndex]
 		if usedRemoteQuota {
-			// 远程才记录滑窗, 滑窗用于上报
 			tokenBucket.ConfirmLimited(token, curTimeMs)
 		}
 		// 归还配额
@@ -190,6 +191,22 @@ func (r *RemoteAwareQpsBucket) GetQuotaUsed(serverTimeMilli int64) ratelimiter.U
 	return *result
 }
 
+// RestoreQuotaUsed 将上报失败的使用情况重新计入当前滑窗
+func (r *RemoteAwareQpsBucket) RestoreQuotaUsed(curTimeMilli int64, usage ratelimiter.UsageInfo) {
+	for durationMilli, passed := range usage.Passed {
+		tokenBucket := r.tokenBucketMap[durationMilli]
+		if nil == tokenBucket {
+			continue
+		}
+		if passed > 0 {
+			tokenBucket.ConfirmPassed(passed, curTimeMilli)
+		}
+		if limited := usage.Limited[durationMilli]; limited > 0 {
+			tokenBucket.ConfirmLimited(limited, curTimeMilli)
+		}
+	}
+}
+
 func (r *RemoteAwareQpsBucket) GetTokenBuckets() TokenBuckets {
 	return r.tokenBuckets
 }
@@ -203,8 +220,8 @@ type BucketShareInfo struct {
 	shareEqual bool
 	// 是否本地配额
 	local bool
-	// 远程实效是否放通
-	passOnRemoteFail bool
+	// 远程失效时的降级策略
+	degradePolicy string
 }
 
 // UpdateIdentifier 令牌桶是否进行更新的凭证
@@ -381,9 +398,9 @@ func (t *TokenBucket) allocateRemoteReadOnly(
 	if !t.remoteExpired(nowMilli) {
 		return true, t.directAllocateRemoteToken(token), Remote
 	}
-	// 远程配额过期，配置了直接放通
-	if t.shareInfo.passOnRemoteFail {
-		return true, 0, RemoteToLocal
+	// 远程配额过期，配置了直接放通或直接拒绝
+	if left, ok := t.degradeDirectly(); ok {
+		return true, left, RemoteToLocal
 	}
 	stageStartMilli := atomic.LoadInt64(&t.stageStartMilli)
 	if stageStartMilli == t.calculateStageStart(nowMilli) {
@@ -394,11 +411,22 @@ func (t *TokenBucket) allocateRemoteReadOnly(
 	return false, 0, RemoteToLocal
 }
 
+// degradeDirectly 远程配额过期时，按降级策略直接放通或拒绝，返回剩余配额以及是否直接处理
+func (t *TokenBucket) degradeDirectly() (int64, bool) {
+	switch t.shareInfo.degradePolicy {
+	case DegradeToPass:
+		return 0, true
+	case DegradeToReject:
+		return -1, true
+	}
+	return 0, false
+}
+
 // allocateRemoteToLocal 以本地退化远程模式来进行分配
 func (t *TokenBucket) allocateRemoteToLocal(token uint32, nowMilli int64, identifier *UpdateIdentifier) int64 {
-	// 远程配额过期，配置了直接放通
-	if t.shareInfo.passOnRemoteFail {
-		return 0
+	// 远程配额过期，配置了直接放通或直接拒绝
+	if left, ok := t.degradeDirectly(); ok {
+		return left
 	}
 	stageStartMilli := atomic.LoadInt64(&t.stageStartMilli)
 	allocReadOnly := func() (bool, int64) {
@@ -506,7 +534,7 @@ func (tbs TokenBuckets) Swap(i, j int) {
 }
 
 // initTokenBuckets 初始化令牌桶
-func initTokenBuckets(rule *apitraffic.Rule, windowKey string) TokenBuckets {
+func initTokenBuckets(rule *apitraffic.Rule, windowKey string, degradePolicy string) TokenBuckets {
 	shareInfo := &BucketShareInfo{}
 	if rule.GetAmountMode() == apitraffic.Rule_SHARE_EQUALLY {
 		shareInfo.shareEqual = true
@@ -514,9 +542,7 @@ func initTokenBuckets(rule *apitraffic.Rule, windowKey string) TokenBuckets {
 	if rule.GetType() == apitraffic.Rule_LOCAL {
 		shareInfo.local = true
 	}
-	if rule.GetFailover() == apitraffic.Rule_FAILOVER_PASS {
-		shareInfo.passOnRemoteFail = true
-	}
+	shareInfo.degradePolicy = degradePolicy
 	amounts := rule.GetAmounts()
 	buckets := make(TokenBuckets, 0, len(amounts))
 	for _, amount := range amounts {
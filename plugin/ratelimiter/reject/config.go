@@ -0,0 +1,72 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package reject
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DegradeToLocalShare 限流服务端不可用时，按照规则总配额/实例数在本地均摊
+	DegradeToLocalShare = "local-share"
+	// DegradeToPass 限流服务端不可用时，直接放通
+	DegradeToPass = "pass"
+	// DegradeToReject 限流服务端不可用时，直接拒绝
+	DegradeToReject = "reject"
+)
+
+// Config 直接拒绝限流器配置
+type Config struct {
+	// DegradePolicy 分布式限流的服务端不可用时的默认降级策略
+	DegradePolicy string `yaml:"degradePolicy" json:"degradePolicy"`
+	// RuleDegradePolicies 按规则名指定的降级策略，优先级高于规则中的failover配置及默认降级策略
+	RuleDegradePolicies map[string]string `yaml:"ruleDegradePolicies" json:"ruleDegradePolicies"`
+}
+
+// Verify 校验配置值
+func (c *Config) Verify() error {
+	var errs error
+	if !validDegradePolicy(c.DegradePolicy) {
+		errs = multierror.Append(errs, fmt.Errorf("reject.degradePolicy %s is invalid", c.DegradePolicy))
+	}
+	for ruleName, policy := range c.RuleDegradePolicies {
+		if !validDegradePolicy(policy) {
+			errs = multierror.Append(errs,
+				fmt.Errorf("reject.ruleDegradePolicies: policy %s of rule %s is invalid", policy, ruleName))
+		}
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if len(c.DegradePolicy) == 0 {
+		c.DegradePolicy = DegradeToLocalShare
+	}
+}
+
+// validDegradePolicy 是否为合法的降级策略
+func validDegradePolicy(policy string) bool {
+	switch policy {
+	case DegradeToLocalShare, DegradeToPass, DegradeToReject:
+		return true
+	}
+	return false
+}
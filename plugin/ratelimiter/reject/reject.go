@@ -18,6 +18,8 @@
 package reject
 
 import (
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
@@ -28,6 +30,7 @@ import (
 // RateLimiterReject 基于直接拒绝策略的限流控制器
 type RateLimiterReject struct {
 	*plugin.PluginBase
+	cfg *Config
 }
 
 // Type 插件类型
@@ -43,6 +46,7 @@ func (g *RateLimiterReject) Name() string {
 // Init 初始化插件
 func (g *RateLimiterReject) Init(ctx *plugin.InitContext) error {
 	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.cfg = ctx.Config.GetProvider().GetRateLimit().GetPluginConfig(g.Name()).(*Config)
 	return nil
 }
 
@@ -60,11 +64,22 @@ func (g *RateLimiterReject) IsEnable(cfg config.Configuration) bool {
 // 主流程会在首次调用，以及规则对象变更的时候，调用该方法
 func (g *RateLimiterReject) InitQuota(criteria *ratelimiter.InitCriteria) ratelimiter.QuotaBucket {
 	return &QuotaBucketReject{
-		bucket: NewRemoteAwareQpsBucket(criteria),
+		bucket: NewRemoteAwareQpsBucket(criteria, g.degradePolicy(criteria.DstRule)),
+	}
+}
+
+// degradePolicy 获取规则在限流服务端不可用时的降级策略
+func (g *RateLimiterReject) degradePolicy(rule *apitraffic.Rule) string {
+	if policy, ok := g.cfg.RuleDegradePolicies[rule.GetName().GetValue()]; ok {
+		return policy
+	}
+	if rule.GetFailover() == apitraffic.Rule_FAILOVER_PASS {
+		return DegradeToPass
 	}
+	return g.cfg.DegradePolicy
 }
 
 // init 注册插件
 func init() {
-	plugin.RegisterPlugin(&RateLimiterReject{})
+	plugin.RegisterConfigurablePlugin(&RateLimiterReject{}, &Config{})
 }
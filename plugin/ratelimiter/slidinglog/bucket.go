@@ -0,0 +1,159 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slidinglog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+const (
+	// 滑动日志限流的限流提示信息
+	slidingLogLimitedInfo = "sliding log rate limited"
+)
+
+// logWindow 单个限流阈值对应的时间戳日志
+// timestamps为容量等于最大配额数的环形数组，head指向最早分配的配额
+type logWindow struct {
+	validDurationMilli  int64
+	validDurationSecond uint32
+	maxAmount           uint32
+	timestamps          []int64
+	head                int
+	passed              uint32
+	limited             uint32
+}
+
+// tryAcquire 判断当前时间能否分配token个配额
+// 第token早的配额已经滑出窗口时，说明窗口内剩余的配额数足够
+func (w *logWindow) tryAcquire(curTimeMs int64, token uint32) bool {
+	if token == 0 {
+		return true
+	}
+	if token > w.maxAmount {
+		return false
+	}
+	last := (w.head + int(token) - 1) % len(w.timestamps)
+	return curTimeMs-w.timestamps[last] >= w.validDurationMilli
+}
+
+// allocate 记录token个配额的分配时间
+func (w *logWindow) allocate(curTimeMs int64, token uint32) {
+	for i := uint32(0); i < token; i++ {
+		w.timestamps[w.head] = curTimeMs
+		w.head = (w.head + 1) % len(w.timestamps)
+	}
+}
+
+// slidingLogBucket 基于滑动日志的配额池，所有阈值都满足时才分配配额
+type slidingLogBucket struct {
+	mutex   sync.Mutex
+	windows []*logWindow
+}
+
+func newSlidingLogBucket(criteria *ratelimiter.InitCriteria) *slidingLogBucket {
+	amounts := criteria.DstRule.GetAmounts()
+	bucket := &slidingLogBucket{
+		windows: make([]*logWindow, 0, len(amounts)),
+	}
+	for _, amount := range amounts {
+		validDuration, err := pb.ConvertDuration(amount.GetValidDuration())
+		maxAmount := amount.GetMaxAmount().GetValue()
+		if err != nil || validDuration <= 0 {
+			continue
+		}
+		window := &logWindow{
+			validDurationMilli:  validDuration.Milliseconds(),
+			validDurationSecond: uint32(validDuration / time.Second),
+			maxAmount:           maxAmount,
+		}
+		if maxAmount > 0 {
+			window.timestamps = make([]int64, maxAmount)
+			// 初始时所有配额都视为已滑出窗口
+			for i := range window.timestamps {
+				window.timestamps[i] = -window.validDurationMilli
+			}
+		}
+		bucket.windows = append(bucket.windows, window)
+	}
+	return bucket
+}
+
+// GetQuota 在所有阈值的时间戳日志中检查并分配配额
+func (b *slidingLogBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, window := range b.windows {
+		if !window.tryAcquire(curTimeMs, token) {
+			window.limited += token
+			return &model.QuotaResponse{
+				Code: model.QuotaResultLimited,
+				Info: slidingLogLimitedInfo,
+			}
+		}
+	}
+	for _, window := range b.windows {
+		window.allocate(curTimeMs, token)
+		window.passed += token
+	}
+	return &model.QuotaResponse{
+		Code: model.QuotaResultOk,
+	}
+}
+
+// Release 滑动日志限流无需释放配额
+func (b *slidingLogBucket) Release() {
+}
+
+// OnRemoteUpdate 滑动日志限流只在本地生效
+func (b *slidingLogBucket) OnRemoteUpdate(ratelimiter.RemoteQuotaResult) {
+}
+
+// GetQuotaUsed 拉取上次拉取以来的配额使用情况
+func (b *slidingLogBucket) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageInfo {
+	result := ratelimiter.UsageInfo{
+		CurTimeMilli: curTimeMilli,
+		Passed:       make(map[int64]uint32, len(b.windows)),
+		Limited:      make(map[int64]uint32, len(b.windows)),
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, window := range b.windows {
+		result.Passed[window.validDurationMilli] = window.passed
+		result.Limited[window.validDurationMilli] = window.limited
+		window.passed = 0
+		window.limited = 0
+	}
+	return result
+}
+
+// GetAmountInfos 获取规则的限流阈值信息
+func (b *slidingLogBucket) GetAmountInfos() []ratelimiter.AmountInfo {
+	amounts := make([]ratelimiter.AmountInfo, 0, len(b.windows))
+	for _, window := range b.windows {
+		amounts = append(amounts, ratelimiter.AmountInfo{
+			ValidDuration: window.validDurationSecond,
+			MaxAmount:     window.maxAmount,
+		})
+	}
+	return amounts
+}
@@ -0,0 +1,191 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slidinglog
+
+import (
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// baseTimeMs 测试使用的起始时间
+const baseTimeMs int64 = 1000000
+
+type quotaStep struct {
+	at    int64
+	token uint32
+	want  model.QuotaResultCode
+}
+
+func newTestBucket(amounts ...*apitraffic.Amount) *slidingLogBucket {
+	return newSlidingLogBucket(&ratelimiter.InitCriteria{
+		DstRule:   &apitraffic.Rule{Amounts: amounts},
+		WindowKey: "test",
+	})
+}
+
+func amount(maxAmount uint32, validDuration time.Duration) *apitraffic.Amount {
+	return &apitraffic.Amount{
+		MaxAmount:     wrapperspb.UInt32(maxAmount),
+		ValidDuration: durationpb.New(validDuration),
+	}
+}
+
+func TestSlidingLogBucketGetQuota(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []*apitraffic.Amount
+		steps   []quotaStep
+	}{
+		{
+			name:    "窗口内配额用尽后限流，最早的配额滑出窗口后恢复",
+			amounts: []*apitraffic.Amount{amount(3, time.Second)},
+			steps: []quotaStep{
+				{at: 0, token: 1, want: model.QuotaResultOk},
+				{at: 100, token: 1, want: model.QuotaResultOk},
+				{at: 200, token: 1, want: model.QuotaResultOk},
+				{at: 300, token: 1, want: model.QuotaResultLimited},
+				{at: 999, token: 1, want: model.QuotaResultLimited},
+				{at: 1000, token: 1, want: model.QuotaResultOk},
+				{at: 1050, token: 1, want: model.QuotaResultLimited},
+				{at: 1100, token: 1, want: model.QuotaResultOk},
+			},
+		},
+		{
+			name:    "一次获取多个配额",
+			amounts: []*apitraffic.Amount{amount(3, time.Second)},
+			steps: []quotaStep{
+				{at: 0, token: 2, want: model.QuotaResultOk},
+				{at: 10, token: 2, want: model.QuotaResultLimited},
+				{at: 10, token: 1, want: model.QuotaResultOk},
+				{at: 999, token: 1, want: model.QuotaResultLimited},
+				{at: 1000, token: 2, want: model.QuotaResultOk},
+				{at: 1000, token: 1, want: model.QuotaResultLimited},
+			},
+		},
+		{
+			name:    "获取的配额数超过阈值",
+			amounts: []*apitraffic.Amount{amount(3, time.Second)},
+			steps: []quotaStep{
+				{at: 0, token: 4, want: model.QuotaResultLimited},
+				{at: 0, token: 3, want: model.QuotaResultOk},
+			},
+		},
+		{
+			name:    "获取0个配额总是成功",
+			amounts: []*apitraffic.Amount{amount(1, time.Second)},
+			steps: []quotaStep{
+				{at: 0, token: 1, want: model.QuotaResultOk},
+				{at: 1, token: 0, want: model.QuotaResultOk},
+				{at: 2, token: 1, want: model.QuotaResultLimited},
+			},
+		},
+		{
+			name:    "阈值为0时全部限流",
+			amounts: []*apitraffic.Amount{amount(0, time.Second)},
+			steps: []quotaStep{
+				{at: 0, token: 1, want: model.QuotaResultLimited},
+				{at: 5000, token: 1, want: model.QuotaResultLimited},
+			},
+		},
+		{
+			name:    "多个阈值同时满足才分配，被限流时不占用其他阈值的配额",
+			amounts: []*apitraffic.Amount{amount(2, time.Second), amount(3, 10*time.Second)},
+			steps: []quotaStep{
+				{at: 0, token: 1, want: model.QuotaResultOk},
+				{at: 1, token: 1, want: model.QuotaResultOk},
+				{at: 2, token: 1, want: model.QuotaResultLimited},
+				{at: 1000, token: 1, want: model.QuotaResultOk},
+				{at: 2000, token: 1, want: model.QuotaResultLimited},
+				{at: 9999, token: 1, want: model.QuotaResultLimited},
+				{at: 10000, token: 1, want: model.QuotaResultOk},
+				{at: 10001, token: 1, want: model.QuotaResultOk},
+				{at: 10002, token: 1, want: model.QuotaResultLimited},
+			},
+		},
+		{
+			name:    "无效的阈值被忽略",
+			amounts: []*apitraffic.Amount{amount(1, 0), amount(2, time.Second)},
+			steps: []quotaStep{
+				{at: 0, token: 1, want: model.QuotaResultOk},
+				{at: 1, token: 1, want: model.QuotaResultOk},
+				{at: 2, token: 1, want: model.QuotaResultLimited},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := newTestBucket(tt.amounts...)
+			for i, step := range tt.steps {
+				resp := bucket.GetQuota(baseTimeMs+step.at, step.token)
+				if resp.Code != step.want {
+					t.Fatalf("step %d: GetQuota(at=%d, token=%d) = %d, want %d",
+						i, step.at, step.token, resp.Code, step.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSlidingLogBucketGetQuotaUsed(t *testing.T) {
+	bucket := newTestBucket(amount(2, time.Second))
+	for i := int64(0); i < 5; i++ {
+		bucket.GetQuota(baseTimeMs+i, 1)
+	}
+	validDurationMs := time.Second.Milliseconds()
+	usage := bucket.GetQuotaUsed(baseTimeMs + 5)
+	if usage.Passed[validDurationMs] != 2 || usage.Limited[validDurationMs] != 3 {
+		t.Fatalf("usage passed %d limited %d, want passed 2 limited 3",
+			usage.Passed[validDurationMs], usage.Limited[validDurationMs])
+	}
+	usage = bucket.GetQuotaUsed(baseTimeMs + 6)
+	if usage.Passed[validDurationMs] != 0 || usage.Limited[validDurationMs] != 0 {
+		t.Fatalf("usage not reset after GetQuotaUsed, passed %d limited %d",
+			usage.Passed[validDurationMs], usage.Limited[validDurationMs])
+	}
+}
+
+func TestNewSlidingLogBucket(t *testing.T) {
+	bucket := newTestBucket(amount(3, time.Second), amount(0, time.Minute), amount(5, 0))
+	if len(bucket.windows) != 2 {
+		t.Fatalf("windows %d, want 2", len(bucket.windows))
+	}
+	// 时间戳日志按配额数分配，初始时所有配额都已滑出窗口
+	first := bucket.windows[0]
+	if len(first.timestamps) != 3 {
+		t.Fatalf("timestamps %d, want 3", len(first.timestamps))
+	}
+	for i, ts := range first.timestamps {
+		if ts != -first.validDurationMilli {
+			t.Fatalf("timestamps[%d] = %d, want %d", i, ts, -first.validDurationMilli)
+		}
+	}
+	if bucket.windows[1].timestamps != nil {
+		t.Fatalf("zero maxAmount window should not allocate timestamps")
+	}
+	infos := bucket.GetAmountInfos()
+	if len(infos) != 2 || infos[0].MaxAmount != 3 || infos[0].ValidDuration != 1 || infos[1].ValidDuration != 60 {
+		t.Fatalf("unexpected amount infos %+v", infos)
+	}
+}
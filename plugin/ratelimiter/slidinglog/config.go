@@ -0,0 +1,51 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slidinglog
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DefaultQPSThreshold 默认的QPS阈值，规则的QPS超过该值时退化为粗粒度的窗口限流
+	DefaultQPSThreshold = 100
+)
+
+// Config 滑动日志限流配置对象
+type Config struct {
+	// QPSThreshold 使用滑动日志算法的最大QPS，超过后退化为reject限流器的滑窗限流，以控制时间戳日志的内存占用
+	QPSThreshold float64 `yaml:"qpsThreshold" json:"qpsThreshold"`
+}
+
+// Verify 检验滑动日志限流配置
+func (c *Config) Verify() error {
+	var errs error
+	if c.QPSThreshold <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("slidinglog.qpsThreshold must be greater than 0"))
+	}
+	return errs
+}
+
+// SetDefault 设置滑动日志限流默认值
+func (c *Config) SetDefault() {
+	if c.QPSThreshold == 0 {
+		c.QPSThreshold = DefaultQPSThreshold
+	}
+}
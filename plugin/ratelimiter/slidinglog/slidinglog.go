@@ -0,0 +1,108 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slidinglog
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// maxLogAmount 单个阈值允许的最大配额数，时间戳日志按配额数预分配内存，
+// 窗口较长时即使QPS低于qpsThreshold，配额数也可能很大，超过该值时同样退化为滑窗限流
+const maxLogAmount = 100000
+
+// RateLimiterSlidingLog 基于滑动日志算法的限流控制器
+// 记录窗口内每个配额的分配时间戳，适用于低QPS且要求精确限流的场景
+type RateLimiterSlidingLog struct {
+	*plugin.PluginBase
+	cfg     *Config
+	plugins plugin.Supplier
+}
+
+// Type 插件类型
+func (s *RateLimiterSlidingLog) Type() common.Type {
+	return common.TypeRateLimiter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (s *RateLimiterSlidingLog) Name() string {
+	return config.DefaultSlidingLogRateLimiter
+}
+
+// Init 初始化插件
+func (s *RateLimiterSlidingLog) Init(ctx *plugin.InitContext) error {
+	s.PluginBase = plugin.NewPluginBase(ctx)
+	s.cfg = ctx.Config.GetProvider().GetRateLimit().GetPluginConfig(s.Name()).(*Config)
+	s.plugins = ctx.Plugins
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (s *RateLimiterSlidingLog) Destroy() error {
+	return nil
+}
+
+// IsEnable enable
+func (s *RateLimiterSlidingLog) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// InitQuota 初始化并创建配额池
+// 规则中任意一个阈值的QPS超过qpsThreshold或配额数超过maxLogAmount时，退化为reject限流器的滑窗限流
+func (s *RateLimiterSlidingLog) InitQuota(criteria *ratelimiter.InitCriteria) ratelimiter.QuotaBucket {
+	if s.exceedThreshold(criteria) {
+		fallback, err := s.plugins.GetPlugin(common.TypeRateLimiter, config.DefaultRejectRateLimiter)
+		if err == nil {
+			log.GetBaseLogger().Infof("[RateLimit] window %s exceeds slidingLog qpsThreshold %.1f, "+
+				"fallback to %s", criteria.WindowKey, s.cfg.QPSThreshold, config.DefaultRejectRateLimiter)
+			return fallback.(ratelimiter.ServiceRateLimiter).InitQuota(criteria)
+		}
+		log.GetBaseLogger().Errorf("[RateLimit] fail to get fallback rateLimiter %s: %v",
+			config.DefaultRejectRateLimiter, err)
+	}
+	return newSlidingLogBucket(criteria)
+}
+
+// exceedThreshold 判断规则的QPS或配额数是否超过滑动日志的阈值
+func (s *RateLimiterSlidingLog) exceedThreshold(criteria *ratelimiter.InitCriteria) bool {
+	for _, amount := range criteria.DstRule.GetAmounts() {
+		validDuration, err := pb.ConvertDuration(amount.GetValidDuration())
+		if err != nil || validDuration <= 0 {
+			continue
+		}
+		maxAmount := amount.GetMaxAmount().GetValue()
+		if maxAmount > maxLogAmount {
+			return true
+		}
+		qps := float64(maxAmount) / validDuration.Seconds()
+		if qps > s.cfg.QPSThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&RateLimiterSlidingLog{}, &Config{})
+}
@@ -0,0 +1,78 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slidinglog
+
+import (
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+func TestRateLimiterSlidingLogExceedThreshold(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []*apitraffic.Amount
+		want    bool
+	}{
+		{
+			name:    "QPS未超过阈值",
+			amounts: []*apitraffic.Amount{amount(100, time.Second)},
+			want:    false,
+		},
+		{
+			name:    "QPS超过阈值",
+			amounts: []*apitraffic.Amount{amount(101, time.Second)},
+			want:    true,
+		},
+		{
+			name:    "长窗口QPS较低但配额数超过上限",
+			amounts: []*apitraffic.Amount{amount(maxLogAmount+1, 24*time.Hour)},
+			want:    true,
+		},
+		{
+			name:    "配额数等于上限",
+			amounts: []*apitraffic.Amount{amount(maxLogAmount, 24*time.Hour)},
+			want:    false,
+		},
+		{
+			name:    "任意一个阈值超过即退化",
+			amounts: []*apitraffic.Amount{amount(10, time.Second), amount(maxLogAmount+1, 24*time.Hour)},
+			want:    true,
+		},
+		{
+			name:    "无效的阈值被忽略",
+			amounts: []*apitraffic.Amount{amount(maxLogAmount+1, 0)},
+			want:    false,
+		},
+	}
+	limiter := &RateLimiterSlidingLog{cfg: &Config{QPSThreshold: DefaultQPSThreshold}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria := &ratelimiter.InitCriteria{
+				DstRule:   &apitraffic.Rule{Amounts: tt.amounts},
+				WindowKey: "test",
+			}
+			if got := limiter.exceedThreshold(criteria); got != tt.want {
+				t.Fatalf("exceedThreshold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -98,7 +98,7 @@ func createLeakyBucket(criteria *ratelimiter.InitCriteria, cfg *Config) *LeakyBu
 	return bucket
 }
 
-func (l *LeakyBucket) allocateQuota() *model.QuotaResponse {
+func (l *LeakyBucket) allocateQuota(maxQueuingDuration int64) *model.QuotaResponse {
 	if l.rejectAll {
 		return &model.QuotaResponse{
 			Code: model.QuotaResultLimited,
@@ -132,7 +132,7 @@ func (l *LeakyBucket) allocateQuota() *model.QuotaResponse {
 		}
 	}
 	// 如果等待时间在上限之内，那么放通
-	if waitDuration <= l.maxQueuingDuration {
+	if waitDuration <= maxQueuingDuration {
 		// log.Printf("grant quota, waitDuration %v", waitDuration)
 		return &model.QuotaResponse{
 			Code:   model.QuotaResultOk,
@@ -153,7 +153,12 @@ func (l *LeakyBucket) allocateQuota() *model.QuotaResponse {
 
 // GetQuota 在令牌桶/漏桶中进行单个配额的划扣，并返回本次分配的结果
 func (l *LeakyBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
-	return l.allocateQuota()
+	return l.allocateQuota(l.maxQueuingDuration)
+}
+
+// GetQuotaWithQueue 使用调用方指定的最大排队时间分配配额
+func (l *LeakyBucket) GetQuotaWithQueue(curTimeMs int64, token uint32, maxQueueMs int64) *model.QuotaResponse {
+	return l.allocateQuota(maxQueueMs)
 }
 
 // Release 释放配额（仅对于并发数限流有用）
@@ -0,0 +1,155 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package unirate
+
+import (
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// newTestBucket 创建单个配额的匀速排队桶，每个请求的间隔为validDuration/maxAmount
+func newTestBucket(maxAmount uint32, validDuration time.Duration, maxQueueDelay uint32) *LeakyBucket {
+	rule := &apitraffic.Rule{
+		Amounts: []*apitraffic.Amount{
+			{
+				MaxAmount:     wrapperspb.UInt32(maxAmount),
+				ValidDuration: durationpb.New(validDuration),
+			},
+		},
+	}
+	if maxQueueDelay > 0 {
+		rule.MaxQueueDelay = wrapperspb.UInt32(maxQueueDelay)
+	}
+	cfg := &Config{}
+	cfg.SetDefault()
+	return createLeakyBucket(&ratelimiter.InitCriteria{DstRule: rule, WindowKey: "test"}, cfg)
+}
+
+func TestCreateLeakyBucket(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxAmount     uint32
+		maxQueueDelay uint32
+		wantRate      int64
+		wantQueueMs   int64
+		wantRejectAll bool
+	}{
+		{
+			name:        "按配额计算请求间隔，使用插件默认的最大排队时间",
+			maxAmount:   10,
+			wantRate:    100,
+			wantQueueMs: defaultMaxQueuingTime.Milliseconds(),
+		},
+		{
+			name:          "规则指定的最大排队时间优先",
+			maxAmount:     10,
+			maxQueueDelay: 3,
+			wantRate:      100,
+			wantQueueMs:   3000,
+		},
+		{
+			name:          "配额为0时拒绝所有请求",
+			wantQueueMs:   defaultMaxQueuingTime.Milliseconds(),
+			wantRejectAll: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := newTestBucket(tt.maxAmount, time.Second, tt.maxQueueDelay)
+			assert.Equal(t, tt.wantRate, bucket.effectiveRate)
+			assert.Equal(t, tt.wantQueueMs, bucket.maxQueuingDuration)
+			assert.Equal(t, tt.wantRejectAll, bucket.rejectAll)
+		})
+	}
+}
+
+// TestLeakyBucketGetQuotaWithQueue 请求间隔为1s，按调用方指定的最大排队时间排队或拒绝
+func TestLeakyBucketGetQuotaWithQueue(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxQueueMs int64
+		// 依次分配的结果，以及放通时等待时间的下限，等待时间不会超过下限+请求间隔
+		wantCodes   []model.QuotaResultCode
+		wantMinWait []int64
+	}{
+		{
+			name:       "不允许排队时只放通首个请求",
+			maxQueueMs: 0,
+			wantCodes: []model.QuotaResultCode{
+				model.QuotaResultOk, model.QuotaResultLimited, model.QuotaResultLimited,
+			},
+			wantMinWait: []int64{0},
+		},
+		{
+			name:       "排队时间未超过上限时放通并返回等待时间",
+			maxQueueMs: 1500,
+			wantCodes: []model.QuotaResultCode{
+				model.QuotaResultOk, model.QuotaResultOk, model.QuotaResultLimited, model.QuotaResultLimited,
+			},
+			wantMinWait: []int64{0, 500},
+		},
+		{
+			name:       "被拒绝的请求归还排队位置",
+			maxQueueMs: 2500,
+			wantCodes: []model.QuotaResultCode{
+				model.QuotaResultOk, model.QuotaResultOk, model.QuotaResultOk,
+				model.QuotaResultLimited, model.QuotaResultLimited,
+			},
+			wantMinWait: []int64{0, 500, 1500},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := newTestBucket(1, time.Second, 0)
+			for i, wantCode := range tt.wantCodes {
+				resp := bucket.GetQuotaWithQueue(model.CurrentMillisecond(), 1, tt.maxQueueMs)
+				assert.Equal(t, wantCode, resp.Code, "request %d", i)
+				if wantCode != model.QuotaResultOk {
+					continue
+				}
+				assert.True(t, resp.WaitMs >= tt.wantMinWait[i], "request %d wait %d", i, resp.WaitMs)
+				assert.True(t, resp.WaitMs <= tt.wantMinWait[i]+1000, "request %d wait %d", i, resp.WaitMs)
+			}
+		})
+	}
+}
+
+// TestLeakyBucketGetQuota 未指定排队时间时使用规则或插件配置的最大排队时间
+func TestLeakyBucketGetQuota(t *testing.T) {
+	bucket := newTestBucket(1, time.Second, 2)
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(model.CurrentMillisecond(), 1).Code)
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(model.CurrentMillisecond(), 1).Code)
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(model.CurrentMillisecond(), 1).Code)
+	assert.Equal(t, model.QuotaResultLimited, bucket.GetQuota(model.CurrentMillisecond(), 1).Code)
+	// 调用方指定的排队时间不受规则限制
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuotaWithQueue(model.CurrentMillisecond(), 1, 5000).Code)
+}
+
+func TestLeakyBucketRejectAll(t *testing.T) {
+	bucket := newTestBucket(0, time.Second, 0)
+	assert.Equal(t, model.QuotaResultLimited, bucket.GetQuotaWithQueue(model.CurrentMillisecond(), 1, 5000).Code)
+	assert.Equal(t, model.QuotaResultLimited, bucket.GetQuota(model.CurrentMillisecond(), 1).Code)
+}
@@ -0,0 +1,175 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package warmup
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+const (
+	// 预热限流的限流提示信息
+	warmUpLimitedInfo = "warm up rate limited"
+)
+
+// warmUpWindow 单个限流阈值对应的令牌桶，令牌生成速率及桶容量随预热进度线性增长
+type warmUpWindow struct {
+	validDurationMilli  int64
+	validDurationSecond uint32
+	maxAmount           uint32
+	tokens              float64
+	lastRefillMs        int64
+	passed              uint32
+	limited             uint32
+}
+
+// warmUpBucket 基于预热令牌桶的配额池，所有阈值都满足时才分配配额
+type warmUpBucket struct {
+	cfg       *Config
+	coldRatio float64
+	mutex     sync.Mutex
+	windows   []*warmUpWindow
+	// 本轮预热的开始时间，为0表示尚未开始预热
+	warmStartMs   int64
+	lastAcquireMs int64
+}
+
+func newWarmUpBucket(criteria *ratelimiter.InitCriteria, cfg *Config) *warmUpBucket {
+	amounts := criteria.DstRule.GetAmounts()
+	bucket := &warmUpBucket{
+		cfg:       cfg,
+		coldRatio: 1 / cfg.ColdFactor,
+		windows:   make([]*warmUpWindow, 0, len(amounts)),
+	}
+	for _, amount := range amounts {
+		validDuration, err := pb.ConvertDuration(amount.GetValidDuration())
+		if err != nil || validDuration <= 0 {
+			continue
+		}
+		bucket.windows = append(bucket.windows, &warmUpWindow{
+			validDurationMilli:  validDuration.Milliseconds(),
+			validDurationSecond: uint32(validDuration / time.Second),
+			maxAmount:           amount.GetMaxAmount().GetValue(),
+		})
+	}
+	return bucket
+}
+
+// warmFactor 计算当前的预热进度，取值范围为[coldRatio, 1]
+func (b *warmUpBucket) warmFactor(curTimeMs int64) float64 {
+	elapsed := float64(curTimeMs - b.warmStartMs)
+	progress := elapsed / float64(b.cfg.WarmUpPeriod.Milliseconds())
+	return math.Min(1, b.coldRatio+(1-b.coldRatio)*progress)
+}
+
+// coolDown 首次请求或空闲超时后，重新开始预热，令牌数重置为冷启动容量
+func (b *warmUpBucket) coolDown(curTimeMs int64) {
+	b.warmStartMs = curTimeMs
+	for _, window := range b.windows {
+		window.tokens = float64(window.maxAmount) * b.coldRatio
+		window.lastRefillMs = curTimeMs
+	}
+}
+
+// refill 按照当前的预热进度补充令牌
+func (b *warmUpBucket) refill(curTimeMs int64) {
+	if b.warmStartMs == 0 || curTimeMs-b.lastAcquireMs >= b.cfg.IdleTimeout.Milliseconds() {
+		b.coolDown(curTimeMs)
+		return
+	}
+	factor := b.warmFactor(curTimeMs)
+	for _, window := range b.windows {
+		elapsed := curTimeMs - window.lastRefillMs
+		if elapsed <= 0 {
+			continue
+		}
+		capacity := float64(window.maxAmount) * factor
+		window.tokens += float64(elapsed) * capacity / float64(window.validDurationMilli)
+		if window.tokens > capacity {
+			window.tokens = capacity
+		}
+		window.lastRefillMs = curTimeMs
+	}
+}
+
+// GetQuota 补充令牌后在所有阈值中划扣配额
+func (b *warmUpBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refill(curTimeMs)
+	b.lastAcquireMs = curTimeMs
+	for _, window := range b.windows {
+		if window.tokens < float64(token) {
+			window.limited += token
+			return &model.QuotaResponse{
+				Code: model.QuotaResultLimited,
+				Info: warmUpLimitedInfo,
+			}
+		}
+	}
+	for _, window := range b.windows {
+		window.tokens -= float64(token)
+		window.passed += token
+	}
+	return &model.QuotaResponse{
+		Code: model.QuotaResultOk,
+	}
+}
+
+// Release 预热限流无需释放配额
+func (b *warmUpBucket) Release() {
+}
+
+// OnRemoteUpdate 预热限流只在本地生效
+func (b *warmUpBucket) OnRemoteUpdate(ratelimiter.RemoteQuotaResult) {
+}
+
+// GetQuotaUsed 拉取上次拉取以来的配额使用情况
+func (b *warmUpBucket) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageInfo {
+	result := ratelimiter.UsageInfo{
+		CurTimeMilli: curTimeMilli,
+		Passed:       make(map[int64]uint32, len(b.windows)),
+		Limited:      make(map[int64]uint32, len(b.windows)),
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, window := range b.windows {
+		result.Passed[window.validDurationMilli] = window.passed
+		result.Limited[window.validDurationMilli] = window.limited
+		window.passed = 0
+		window.limited = 0
+	}
+	return result
+}
+
+// GetAmountInfos 获取规则的限流阈值信息
+func (b *warmUpBucket) GetAmountInfos() []ratelimiter.AmountInfo {
+	amounts := make([]ratelimiter.AmountInfo, 0, len(b.windows))
+	for _, window := range b.windows {
+		amounts = append(amounts, ratelimiter.AmountInfo{
+			ValidDuration: window.validDurationSecond,
+			MaxAmount:     window.maxAmount,
+		})
+	}
+	return amounts
+}
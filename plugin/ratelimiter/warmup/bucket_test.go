@@ -0,0 +1,153 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package warmup
+
+import (
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// baseTimeMs 测试使用的起始时间
+const baseTimeMs int64 = 1000000
+
+// burstStep 在at时刻连续获取count个配额，期望通过passed个
+type burstStep struct {
+	at     time.Duration
+	count  int
+	passed int
+}
+
+func newTestBucket(amounts ...*apitraffic.Amount) *warmUpBucket {
+	cfg := &Config{}
+	cfg.SetDefault()
+	return newWarmUpBucket(&ratelimiter.InitCriteria{
+		DstRule:   &apitraffic.Rule{Amounts: amounts},
+		WindowKey: "test",
+	}, cfg)
+}
+
+func amount(maxAmount uint32, validDuration time.Duration) *apitraffic.Amount {
+	return &apitraffic.Amount{
+		MaxAmount:     wrapperspb.UInt32(maxAmount),
+		ValidDuration: durationpb.New(validDuration),
+	}
+}
+
+// burst 在同一时刻连续获取配额，返回通过的个数
+func burst(bucket *warmUpBucket, curTimeMs int64, count int) int {
+	var passed int
+	for i := 0; i < count; i++ {
+		if bucket.GetQuota(curTimeMs, 1).Code == model.QuotaResultOk {
+			passed++
+		}
+	}
+	return passed
+}
+
+// TestWarmUpBucketRamp 按时间推进驱动预热过程，默认预热10秒，冷启动因子为3，空闲1分钟后重新预热
+func TestWarmUpBucketRamp(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []*apitraffic.Amount
+		steps   []burstStep
+	}{
+		{
+			name:    "冷启动时只允许阈值的1/coldFactor",
+			amounts: []*apitraffic.Amount{amount(30, time.Second)},
+			steps: []burstStep{
+				{at: 0, count: 31, passed: 10},
+			},
+		},
+		{
+			name:    "预热期间容量线性增长，预热结束后达到阈值",
+			amounts: []*apitraffic.Amount{amount(30, time.Second)},
+			steps: []burstStep{
+				{at: 0, count: 31, passed: 10},
+				{at: 5 * time.Second, count: 31, passed: 20},
+				{at: 10 * time.Second, count: 31, passed: 30},
+				{at: 15 * time.Second, count: 31, passed: 30},
+			},
+		},
+		{
+			name:    "预热期间令牌按当前速率补充",
+			amounts: []*apitraffic.Amount{amount(30, time.Second)},
+			steps: []burstStep{
+				{at: 0, count: 11, passed: 10},
+				{at: 100 * time.Millisecond, count: 3, passed: 1},
+			},
+		},
+		{
+			name:    "空闲未超时保持预热完成的容量",
+			amounts: []*apitraffic.Amount{amount(30, time.Second)},
+			steps: []burstStep{
+				{at: 0, count: 31, passed: 10},
+				{at: 10 * time.Second, count: 31, passed: 30},
+				{at: 10*time.Second + time.Minute - time.Millisecond, count: 31, passed: 30},
+			},
+		},
+		{
+			name:    "空闲超时后重新预热",
+			amounts: []*apitraffic.Amount{amount(30, time.Second)},
+			steps: []burstStep{
+				{at: 0, count: 31, passed: 10},
+				{at: 10 * time.Second, count: 31, passed: 30},
+				{at: 10*time.Second + time.Minute, count: 31, passed: 10},
+				{at: 15*time.Second + time.Minute, count: 31, passed: 20},
+			},
+		},
+		{
+			name:    "多个阈值同时预热，所有阈值都满足才分配",
+			amounts: []*apitraffic.Amount{amount(30, time.Second), amount(40, 10*time.Second)},
+			steps: []burstStep{
+				{at: 0, count: 31, passed: 10},
+				{at: 10 * time.Second, count: 31, passed: 30},
+				{at: 11 * time.Second, count: 31, passed: 14},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := newTestBucket(tt.amounts...)
+			for _, step := range tt.steps {
+				curTimeMs := baseTimeMs + step.at.Milliseconds()
+				assert.Equal(t, step.passed, burst(bucket, curTimeMs, step.count), "at %v", step.at)
+			}
+		})
+	}
+}
+
+// TestWarmUpBucketQuotaUsed 测试配额使用情况的统计，拉取后清零
+func TestWarmUpBucketQuotaUsed(t *testing.T) {
+	bucket := newTestBucket(amount(30, time.Second))
+	assert.Equal(t, 10, burst(bucket, baseTimeMs, 12))
+	usage := bucket.GetQuotaUsed(baseTimeMs)
+	assert.Equal(t, uint32(10), usage.Passed[time.Second.Milliseconds()])
+	assert.Equal(t, uint32(2), usage.Limited[time.Second.Milliseconds()])
+	usage = bucket.GetQuotaUsed(baseTimeMs)
+	assert.Equal(t, uint32(0), usage.Passed[time.Second.Milliseconds()])
+	assert.Equal(t, uint32(0), usage.Limited[time.Second.Milliseconds()])
+	assert.Equal(t, []ratelimiter.AmountInfo{{ValidDuration: 1, MaxAmount: 30}}, bucket.GetAmountInfos())
+}
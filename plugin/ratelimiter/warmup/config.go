@@ -0,0 +1,72 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package warmup
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DefaultWarmUpPeriod 默认的预热时长
+	DefaultWarmUpPeriod = 10 * time.Second
+	// DefaultColdFactor 默认的冷启动因子，预热开始时的QPS为阈值的1/coldFactor
+	DefaultColdFactor = 3
+	// DefaultIdleTimeout 默认的空闲时长，超过该时长没有请求则重新预热
+	DefaultIdleTimeout = time.Minute
+)
+
+// Config 预热限流配置对象
+type Config struct {
+	// WarmUpPeriod 预热时长，QPS在该时长内从冷启动值线性增长到规则阈值
+	WarmUpPeriod time.Duration `yaml:"warmUpPeriod" json:"warmUpPeriod"`
+	// ColdFactor 冷启动因子，必须大于1
+	ColdFactor float64 `yaml:"coldFactor" json:"coldFactor"`
+	// IdleTimeout 空闲时长，超过该时长没有请求则重新进入预热
+	IdleTimeout time.Duration `yaml:"idleTimeout" json:"idleTimeout"`
+}
+
+// Verify 检验预热限流配置
+func (c *Config) Verify() error {
+	var errs error
+	if c.WarmUpPeriod <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("warmup.warmUpPeriod must be greater than 0"))
+	}
+	if c.ColdFactor <= 1 {
+		errs = multierror.Append(errs, fmt.Errorf("warmup.coldFactor must be greater than 1"))
+	}
+	if c.IdleTimeout <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("warmup.idleTimeout must be greater than 0"))
+	}
+	return errs
+}
+
+// SetDefault 设置预热限流默认值
+func (c *Config) SetDefault() {
+	if c.WarmUpPeriod == 0 {
+		c.WarmUpPeriod = DefaultWarmUpPeriod
+	}
+	if c.ColdFactor == 0 {
+		c.ColdFactor = DefaultColdFactor
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = DefaultIdleTimeout
+	}
+}
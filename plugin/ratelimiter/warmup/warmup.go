@@ -0,0 +1,71 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package warmup
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// RateLimiterWarmUp 基于预热策略的限流控制器
+// 实例启动或长时间空闲后，允许的QPS从冷启动值线性增长到规则阈值，避免冷缓存被瞬时流量打垮
+type RateLimiterWarmUp struct {
+	*plugin.PluginBase
+	cfg *Config
+}
+
+// Type 插件类型
+func (w *RateLimiterWarmUp) Type() common.Type {
+	return common.TypeRateLimiter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (w *RateLimiterWarmUp) Name() string {
+	return config.DefaultWarmUpRateLimiter
+}
+
+// Init 初始化插件
+func (w *RateLimiterWarmUp) Init(ctx *plugin.InitContext) error {
+	w.PluginBase = plugin.NewPluginBase(ctx)
+	w.cfg = ctx.Config.GetProvider().GetRateLimit().GetPluginConfig(w.Name()).(*Config)
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (w *RateLimiterWarmUp) Destroy() error {
+	return nil
+}
+
+// IsEnable enable
+func (w *RateLimiterWarmUp) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// InitQuota 初始化并创建配额池
+// 主流程会在首次调用，以及规则对象变更的时候，调用该方法
+func (w *RateLimiterWarmUp) InitQuota(criteria *ratelimiter.InitCriteria) ratelimiter.QuotaBucket {
+	return newWarmUpBucket(criteria, w.cfg)
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&RateLimiterWarmUp{}, &Config{})
+}
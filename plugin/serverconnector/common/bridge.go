@@ -0,0 +1,306 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package common
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+const (
+	// 桥接服务发现的默认刷新周期
+	defaultBridgeRefreshInterval = 2 * time.Second
+)
+
+// BridgeFetcher 从外部注册中心获取资源并转换为北极星服务发现应答，返回nil表示不支持该资源类型
+type BridgeFetcher func(key *model.ServiceEventKey) (*apiservice.DiscoverResponse, error)
+
+// BridgeRevisionFetcher 带本地缓存版本号的BridgeFetcher，用于服务端支持按版本号返回未变更的场景
+type BridgeRevisionFetcher func(key *model.ServiceEventKey, revision string) (*apiservice.DiscoverResponse, error)
+
+// BridgeDiscover 对接外部注册中心（xDS、Consul、Nacos、Kubernetes等）的通用服务发现实现，
+// 按服务刷新周期轮询，外部注册中心有推送能力时可通过Notify立即触发刷新
+type BridgeDiscover struct {
+	name    string
+	fetch   BridgeRevisionFetcher
+	tasks   sync.Map
+	done    chan struct{}
+	destroy sync.Once
+}
+
+// bridgeTask 单个服务资源的刷新任务
+type bridgeTask struct {
+	key     model.ServiceEventKey
+	handler serverconnector.EventHandler
+	trigger chan struct{}
+	stop    chan struct{}
+}
+
+// NewBridgeDiscover 创建桥接服务发现
+func NewBridgeDiscover(name string, fetch BridgeFetcher) *BridgeDiscover {
+	return NewBridgeRevisionDiscover(name, func(key *model.ServiceEventKey, _ string) (*apiservice.DiscoverResponse, error) {
+		return fetch(key)
+	})
+}
+
+// NewBridgeRevisionDiscover 创建桥接服务发现，拉取时带上本地缓存的版本号
+func NewBridgeRevisionDiscover(name string, fetch BridgeRevisionFetcher) *BridgeDiscover {
+	return &BridgeDiscover{
+		name:  name,
+		fetch: fetch,
+		done:  make(chan struct{}),
+	}
+}
+
+// RegisterServiceHandler 注册服务监听器，并立即拉取一次
+func (b *BridgeDiscover) RegisterServiceHandler(svcEventHandler *serverconnector.ServiceEventHandler) error {
+	select {
+	case <-b.done:
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+			"RegisterServiceHandler: %s connector has been destroyed", b.name)
+	default:
+	}
+	task := &bridgeTask{
+		key:     *svcEventHandler.ServiceEventKey,
+		handler: svcEventHandler.Handler,
+		trigger: make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+	}
+	if old, loaded := b.tasks.Load(task.key); loaded {
+		close(old.(*bridgeTask).stop)
+	}
+	b.tasks.Store(task.key, task)
+	interval := svcEventHandler.RefreshInterval
+	if interval <= 0 {
+		interval = defaultBridgeRefreshInterval
+	}
+	go b.run(task, interval)
+	return nil
+}
+
+// DeRegisterServiceHandler 反注册服务监听器
+func (b *BridgeDiscover) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
+	if value, ok := b.tasks.Load(*key); ok {
+		b.tasks.Delete(*key)
+		close(value.(*bridgeTask).stop)
+	}
+	return nil
+}
+
+// Notify 外部注册中心推送变更时，立即刷新对应的服务资源
+func (b *BridgeDiscover) Notify(key model.ServiceEventKey) {
+	value, ok := b.tasks.Load(key)
+	if !ok {
+		return
+	}
+	select {
+	case value.(*bridgeTask).trigger <- struct{}{}:
+	default:
+	}
+}
+
+// NotifyService 刷新服务下所有类型的资源
+func (b *BridgeDiscover) NotifyService(svcKey model.ServiceKey) {
+	b.tasks.Range(func(key, _ interface{}) bool {
+		if eventKey := key.(model.ServiceEventKey); eventKey.ServiceKey == svcKey {
+			b.Notify(eventKey)
+		}
+		return true
+	})
+}
+
+// NotifyType 刷新指定类型的所有资源，用于服务列表等与具体服务无关的变更
+func (b *BridgeDiscover) NotifyType(eventType model.EventType) {
+	b.tasks.Range(func(key, _ interface{}) bool {
+		if eventKey := key.(model.ServiceEventKey); eventKey.Type == eventType {
+			b.Notify(eventKey)
+		}
+		return true
+	})
+}
+
+// Destroy 停止所有刷新任务
+func (b *BridgeDiscover) Destroy() {
+	b.destroy.Do(func() {
+		close(b.done)
+	})
+}
+
+func (b *BridgeDiscover) run(task *bridgeTask, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		b.update(task)
+		select {
+		case <-b.done:
+			return
+		case <-task.stop:
+			return
+		case <-ticker.C:
+		case <-task.trigger:
+		}
+	}
+}
+
+func (b *BridgeDiscover) update(task *bridgeTask) {
+	key := task.key
+	resp, err := b.fetch(&key, task.handler.GetRevision())
+	if err != nil {
+		log.GetNetworkLogger().Errorf("[%s] fail to fetch %s, err %v", b.name, key, err)
+		sdkErr, ok := err.(model.SDKError)
+		if !ok {
+			sdkErr = model.NewSDKError(model.ErrCodeNetworkError, err, "fail to fetch %s from %s", key, b.name)
+		}
+		task.handler.OnServiceUpdate(&serverconnector.ServiceEvent{ServiceEventKey: key, Error: sdkErr})
+		return
+	}
+	if resp == nil {
+		resp = NewBridgeResponse(&key)
+	}
+	task.handler.OnServiceUpdate(&serverconnector.ServiceEvent{ServiceEventKey: key, Value: resp})
+}
+
+// BridgeInstance 外部注册中心中的服务实例
+type BridgeInstance struct {
+	// ID 实例ID，为空时根据服务及地址生成
+	ID       string
+	Host     string
+	Port     uint32
+	Protocol string
+	Weight   uint32
+	Healthy  bool
+	Isolated bool
+	Metadata map[string]string
+	Region   string
+	Zone     string
+	Campus   string
+}
+
+// NewBridgeResponse 创建指定资源类型的空应答，用于外部注册中心不支持的资源类型
+func NewBridgeResponse(key *model.ServiceEventKey) *apiservice.DiscoverResponse {
+	return &apiservice.DiscoverResponse{
+		Code: &wrappers.UInt32Value{Value: uint32(apimodel.Code_ExecuteSuccess)},
+		Type: pb.GetProtoResponseType(key.Type),
+		Service: &apiservice.Service{
+			Name:      &wrappers.StringValue{Value: key.Service},
+			Namespace: &wrappers.StringValue{Value: key.Namespace},
+			Revision:  &wrappers.StringValue{Value: ""},
+		},
+	}
+}
+
+// NewBridgeInstancesResponse 将外部注册中心的实例转换为北极星实例应答，版本号根据实例内容计算
+func NewBridgeInstancesResponse(key *model.ServiceEventKey, instances []BridgeInstance) *apiservice.DiscoverResponse {
+	resp := NewBridgeResponse(key)
+	resp.Instances = make([]*apiservice.Instance, 0, len(instances))
+	for i := range instances {
+		resp.Instances = append(resp.Instances, toProtoInstance(key, &instances[i]))
+	}
+	resp.Service.Revision = &wrappers.StringValue{Value: pb.GenInstancesRevision(resp.Instances)}
+	return resp
+}
+
+// NewBridgeRoutingResponse 将外部注册中心的路由配置转换为北极星路由规则应答，版本号根据规则内容计算
+func NewBridgeRoutingResponse(key *model.ServiceEventKey, routing *apitraffic.Routing) *apiservice.DiscoverResponse {
+	resp := NewBridgeResponse(key)
+	revision := fmt.Sprintf("%X", model.HashMessage(routing))
+	routing.Revision = &wrappers.StringValue{Value: revision}
+	resp.Routing = routing
+	resp.Service.Revision = &wrappers.StringValue{Value: revision}
+	return resp
+}
+
+// NewBridgeServicesResponse 将外部注册中心的服务列表转换为北极星批量服务应答
+func NewBridgeServicesResponse(key *model.ServiceEventKey, services []model.ServiceKey) *apiservice.DiscoverResponse {
+	resp := NewBridgeResponse(key)
+	resp.Services = make([]*apiservice.Service, 0, len(services))
+	for _, svc := range services {
+		resp.Services = append(resp.Services, &apiservice.Service{
+			Name:      &wrappers.StringValue{Value: svc.Service},
+			Namespace: &wrappers.StringValue{Value: svc.Namespace},
+		})
+	}
+	resp.Service.Revision = &wrappers.StringValue{Value: pb.GenServicesRevision(resp.Services)}
+	return resp
+}
+
+func toProtoInstance(key *model.ServiceEventKey, instance *BridgeInstance) *apiservice.Instance {
+	id := instance.ID
+	if id == "" {
+		h := sha1.New()
+		_, _ = io.WriteString(h, fmt.Sprintf("%s-%s-%s-%d", key.Namespace, key.Service, instance.Host, instance.Port))
+		id = fmt.Sprintf("%x", h.Sum(nil))
+	}
+	protoInstance := &apiservice.Instance{
+		Id:        &wrappers.StringValue{Value: id},
+		Service:   &wrappers.StringValue{Value: key.Service},
+		Namespace: &wrappers.StringValue{Value: key.Namespace},
+		Host:      &wrappers.StringValue{Value: instance.Host},
+		Port:      &wrappers.UInt32Value{Value: instance.Port},
+		Protocol:  &wrappers.StringValue{Value: instance.Protocol},
+		Weight:    &wrappers.UInt32Value{Value: instance.Weight},
+		Healthy:   &wrappers.BoolValue{Value: instance.Healthy},
+		Isolate:   &wrappers.BoolValue{Value: instance.Isolated},
+		Metadata:  instance.Metadata,
+	}
+	if instance.Region != "" || instance.Zone != "" || instance.Campus != "" {
+		protoInstance.Location = &apimodel.Location{
+			Region: &wrappers.StringValue{Value: instance.Region},
+			Zone:   &wrappers.StringValue{Value: instance.Zone},
+			Campus: &wrappers.StringValue{Value: instance.Campus},
+		}
+	}
+	revision := md5.New()
+	_, _ = io.WriteString(revision, instanceDigest(protoInstance))
+	protoInstance.Revision = &wrappers.StringValue{Value: fmt.Sprintf("%x", revision.Sum(nil))}
+	return protoInstance
+}
+
+// instanceDigest 实例内容摘要，元数据按key排序保证稳定
+func instanceDigest(instance *apiservice.Instance) string {
+	keys := make([]string, 0, len(instance.GetMetadata()))
+	for k := range instance.GetMetadata() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	digest := instance.GetId().GetValue() + "|" + instance.GetHost().GetValue() + "|" +
+		strconv.FormatUint(uint64(instance.GetPort().GetValue()), 10) + "|" + instance.GetProtocol().GetValue() + "|" +
+		strconv.FormatUint(uint64(instance.GetWeight().GetValue()), 10) + "|" +
+		strconv.FormatBool(instance.GetHealthy().GetValue()) + "|" + strconv.FormatBool(instance.GetIsolate().GetValue()) +
+		"|" + instance.GetLocation().GetRegion().GetValue() + "|" + instance.GetLocation().GetZone().GetValue() +
+		"|" + instance.GetLocation().GetCampus().GetValue()
+	for _, k := range keys {
+		digest += "|" + k + "=" + instance.GetMetadata()[k]
+	}
+	return digest
+}
@@ -41,6 +41,8 @@ type DiscoverClientCreatorArgs struct {
 	AuthToken  string
 	Connection *network.Connection
 	Timeout    time.Duration
+	// 是否接受增量实例推送
+	AcceptDelta bool
 }
 
 // DiscoverClientCreator 创建client的函数
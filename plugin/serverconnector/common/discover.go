@@ -70,8 +70,8 @@ type DiscoverConnector struct {
 	ServiceConnector      *plugin.PluginBase
 	connectionIdleTimeout time.Duration
 	messageTimeout        time.Duration
-	// authToken
-	authToken string
+	// 鉴权token提供者
+	tokenProvider *network.TokenProvider
 	// 普通任务队列
 	taskChannel chan *clientTask
 	// 高优先级重试任务队列，只会在系统服务未ready时候会往队列塞值
@@ -93,6 +93,14 @@ type DiscoverConnector struct {
 	// 创建具体调度客户端的逻辑
 	createClient DiscoverClientCreator
 	scalableRand *rand.ScalableRand
+	// 首次发现的对冲请求延迟，为0时不启用对冲
+	hedgeDelay time.Duration
+	// 最多额外发起的对冲请求数
+	maxHedgedRequests int
+	// 是否接受增量实例推送
+	acceptDelta bool
+	// 当前使用的流式客户端，存放的是*StreamingClient，用于统计待应答的请求数
+	currentClient atomic.Value
 }
 
 // 任务对象，用于在connector协程中做轮转处理
@@ -104,7 +112,7 @@ type clientTask struct {
 // Init 初始化插件
 func (g *DiscoverConnector) Init(ctx *plugin.InitContext, createClient DiscoverClientCreator) {
 	ctxConfig := ctx.Config
-	g.authToken = ctxConfig.GetGlobal().GetServerConnector().GetToken()
+	g.tokenProvider = network.NewTokenProvider(ctxConfig.GetGlobal().GetServerConnector())
 	g.RunContext = common.NewRunContext()
 	g.scalableRand = rand.NewScalableRand()
 	g.discoverKey.Namespace = ctxConfig.GetGlobal().GetSystem().GetDiscoverCluster().GetNamespace()
@@ -228,6 +236,7 @@ func (g *DiscoverConnector) doSend() {
 			return
 		case clientTask := <-g.taskChannel:
 			streamingClient = g.onClientTask(streamingClient, clientTask)
+			g.currentClient.Store(streamingClient)
 		case <-updateTicker.C:
 			if nil != streamingClient {
 				allTaskTimeout := g.clearTimeoutClient(streamingClient)
@@ -252,10 +261,27 @@ func (g *DiscoverConnector) doSend() {
 				}
 				return true
 			})
+			g.currentClient.Store(streamingClient)
 		}
 	}
 }
 
+// GetPendingDiscoverRequests 获取当前流上已发送但尚未收到应答的请求数
+func (g *DiscoverConnector) GetPendingDiscoverRequests() int {
+	streamingClient, _ := g.currentClient.Load().(*StreamingClient)
+	if nil == streamingClient || streamingClient.IsEndStream() {
+		return 0
+	}
+	streamingClient.mutex.Lock()
+	defer streamingClient.mutex.Unlock()
+	return len(streamingClient.pendingTasks)
+}
+
+// GetTaskQueueDepth 获取等待处理的服务发现任务数
+func (g *DiscoverConnector) GetTaskQueueDepth() int {
+	return len(g.taskChannel)
+}
+
 // 重试更新任务
 func (g *DiscoverConnector) retryUpdateTask(updateTask *serviceUpdateTask, err error, notReady bool) {
 	updateTask.retryLock.Lock()
@@ -305,14 +331,16 @@ func logDiscoverResponse(resp *apiservice.DiscoverResponse, connection *network.
 }
 
 // 服务发现应答转为事件，从应答里面获取调用discover的返回码
-func discoverResponseToEvent(resp *apiservice.DiscoverResponse,
-	svcEventKey model.ServiceEventKey, connection *network.Connection) (*serverconnector.ServiceEvent, model.ErrCode) {
+// 只有声明了接受增量推送时，才按照internal-delta元数据识别增量应答，否则按普通的服务元数据处理
+func discoverResponseToEvent(resp *apiservice.DiscoverResponse, svcEventKey model.ServiceEventKey,
+	connection *network.Connection, acceptDelta bool) (*serverconnector.ServiceEvent, model.ErrCode) {
 	svcEvent := &serverconnector.ServiceEvent{ServiceEventKey: svcEventKey}
 	retCode := resp.GetCode().GetValue()
 	errInfo := resp.GetInfo().GetValue()
 	svcCode := pb.ConvertServerErrorToRpcError(retCode)
 	if model.IsSuccessResultCode(retCode) {
 		svcEvent.Value = resp
+		svcEvent.Delta = acceptDelta && pb.IsDeltaResponse(resp)
 	} else {
 		log.GetNetworkLogger().Errorf("server error received, code %v, info: %s", retCode, errInfo)
 		svcEvent.Error = model.NewServerSDKError(retCode,
@@ -531,7 +559,8 @@ func (s *StreamingClient) receiveAndNotify() {
 			atomic.AddUint64(&updateTask.successUpdates, 1)
 			// g.reportCallStatus(curClient, updateTask, nil, true)
 			// 触发回调事件
-			svcEvent, discoverCode := discoverResponseToEvent(resp, updateTask.ServiceEventKey, s.connection)
+			svcEvent, discoverCode := discoverResponseToEvent(resp, updateTask.ServiceEventKey, s.connection,
+				s.connector.acceptDelta)
 			// 没有返回grpc错误，返回的消息合法且不是返回了5XX，认为这次调用成功了
 			s.connector.connManager.ReportSuccess(s.connection.ConnID, int32(discoverCode), GetUpdateTaskRequestTime(updateTask))
 			updateTask.handler.OnServiceUpdate(svcEvent)
@@ -593,10 +622,11 @@ func (g *DiscoverConnector) newStream(task *serviceUpdateTask) (streamingClient
 	}
 	streamingClient.reqID = NextDiscoverReqID()
 	streamingClient.discoverClient, streamingClient.cancel, err = g.createClient(&DiscoverClientCreatorArgs{
-		ReqId:      streamingClient.reqID,
-		Connection: streamingClient.connection,
-		Timeout:    0,
-		AuthToken:  g.authToken,
+		ReqId:       streamingClient.reqID,
+		Connection:  streamingClient.connection,
+		Timeout:     0,
+		AuthToken:   g.tokenProvider.GetToken(),
+		AcceptDelta: g.acceptDelta,
 	})
 	if err != nil {
 		log.GetNetworkLogger().Errorf("%s, newStream: fail to get streaming client from %s, reqID %s, err %v",
@@ -721,15 +751,7 @@ func (g *DiscoverConnector) clearIdleClient(client *StreamingClient, forceClear
 // 异步处理发现事件
 func (g *DiscoverConnector) asyncUpdateTask(
 	streamingClient *StreamingClient, task *serviceUpdateTask) *StreamingClient {
-	// 服务发现请求是否已经准备可以处理
-	// 需要获取discover集群完毕，以及地域信息获取完毕
-	var notReadyErr error
-	if !g.connManager.IsReady() {
-		notReadyErr = fmt.Errorf("discover is not ready")
-	} else if !g.valueCtx.GetCurrentLocation().IsLocationInitialized() {
-		notReadyErr = fmt.Errorf("location info is not inited")
-	}
-	if nil != notReadyErr {
+	if notReadyErr := g.checkReady(); nil != notReadyErr {
 		g.retryUpdateTask(task, notReadyErr, true)
 		return streamingClient
 	}
@@ -765,6 +787,18 @@ func (g *DiscoverConnector) asyncUpdateTask(
 	return streamingClient
 }
 
+// 服务发现请求是否已经准备可以处理
+// 需要获取discover集群完毕，以及地域信息获取完毕
+func (g *DiscoverConnector) checkReady() error {
+	if !g.connManager.IsReady() {
+		return fmt.Errorf("discover is not ready")
+	}
+	if !g.valueCtx.GetCurrentLocation().IsLocationInitialized() {
+		return fmt.Errorf("location info is not inited")
+	}
+	return nil
+}
+
 // 处理更新任务
 func (g *DiscoverConnector) processUpdateTask(
 	streamingClient *StreamingClient, task *serviceUpdateTask) *StreamingClient {
@@ -774,7 +808,12 @@ func (g *DiscoverConnector) processUpdateTask(
 	}
 	log.GetNetworkLogger().Debugf("start to process task %s", task.ServiceEventKey)
 	if task.targetCluster == config.BuiltinCluster {
-		err := g.syncUpdateTask(task)
+		var err error
+		if g.hedgeDelay > 0 {
+			err = g.hedgedUpdateTask(task)
+		} else {
+			err = g.syncUpdateTask(task)
+		}
 		if err != nil {
 			g.retryUpdateTask(task, err, true)
 			return streamingClient
@@ -783,6 +822,15 @@ func (g *DiscoverConnector) processUpdateTask(
 		g.addUpdateTaskSet(task)
 		return streamingClient
 	}
+	if g.hedgeDelay > 0 && atomic.LoadUint32(&task.longRun) != longRunning {
+		// 首次发现走对冲请求，避免单个慢server拖住服务的首次获取
+		if notReadyErr := g.checkReady(); nil != notReadyErr {
+			g.retryUpdateTask(task, notReadyErr, true)
+			return streamingClient
+		}
+		go g.hedgedFirstTask(task)
+		return streamingClient
+	}
 	return g.asyncUpdateTask(streamingClient, task)
 }
 
@@ -900,6 +948,11 @@ func (g *DiscoverConnector) RegisterServiceHandler(svcEventHandler *serverconnec
 	return g.addFirstTask(updateTask)
 }
 
+// SetAcceptDelta 设置是否向服务端声明接受增量实例推送，仅在服务端支持增量推送时开启
+func (g *DiscoverConnector) SetAcceptDelta(acceptDelta bool) {
+	g.acceptDelta = acceptDelta
+}
+
 // 往队列插入任务
 func (g *DiscoverConnector) addFirstTask(updateTask *serviceUpdateTask) error {
 	task := &clientTask{updateTask: updateTask, op: opAddListener}
@@ -952,60 +1005,124 @@ func (g *DiscoverConnector) UpdateServers(key *model.ServiceEventKey) error {
 	return nil
 }
 
+// RefreshServiceHandler 清空任务的最近更新时间，使其在下一个调度周期立即拉取
+func (g *DiscoverConnector) RefreshServiceHandler(key *model.ServiceEventKey) error {
+	value, ok := g.updateTaskSet.Load(*key)
+	if !ok {
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil, "update task %s not found", *key)
+	}
+	value.(*serviceUpdateTask).lastUpdateTime.Store(time.Time{})
+	return nil
+}
+
 // 同步进行服务或规则发现
 func (g *DiscoverConnector) syncUpdateTask(task *serviceUpdateTask) error {
-	var curTime = time.Now()
 	// 获取服务发现server连接
 	connection, err := g.connManager.GetConnection(OpKeyDiscover, task.targetCluster)
 	if err != nil {
 		return err
 	}
 	defer connection.Release(OpKeyDiscover)
+	resp, err := g.discoverOnce(connection, task, g.messageTimeout, nil)
+	if err != nil {
+		return err
+	}
+	g.onSyncResponse(task, resp, connection)
+	return nil
+}
+
+// discoverOnce 在指定连接上发起一次服务发现请求，stopCh关闭时提前取消请求
+func (g *DiscoverConnector) discoverOnce(connection *network.Connection, task *serviceUpdateTask,
+	timeout time.Duration, stopCh <-chan struct{}) (*apiservice.DiscoverResponse, error) {
+	task.msgSendTime.Store(time.Now())
+	atomic.AddUint64(&task.totalRequests, 1)
+	return g.doDiscover(connection, task.toDiscoverRequest(), timeout, stopCh)
+}
+
+// doDiscover 创建一次性的发现流，发送请求并等待一个应答
+func (g *DiscoverConnector) doDiscover(connection *network.Connection, request *apiservice.DiscoverRequest,
+	timeout time.Duration, stopCh <-chan struct{}) (*apiservice.DiscoverResponse, error) {
 	reqID := NextDiscoverReqID()
 	discoverClient, cancel, err := g.createClient(&DiscoverClientCreatorArgs{
-		ReqId:      reqID,
-		Connection: connection,
-		Timeout:    g.messageTimeout,
-		AuthToken:  g.authToken,
+		ReqId:       reqID,
+		Connection:  connection,
+		Timeout:     timeout,
+		AuthToken:   g.tokenProvider.GetToken(),
+		AcceptDelta: g.acceptDelta,
 	})
 	if cancel != nil {
 		defer cancel()
+		if stopCh != nil {
+			doneCh := make(chan struct{})
+			defer close(doneCh)
+			go func() {
+				select {
+				case <-stopCh:
+					cancel()
+				case <-doneCh:
+				}
+			}()
+		}
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 	log.GetNetworkLogger().Debugf("sync stream %s created, connection %s, timeout %v",
-		reqID, connection.ConnID, g.connectionIdleTimeout)
-	var request = task.toDiscoverRequest()
-	task.msgSendTime.Store(curTime)
-	atomic.AddUint64(&task.totalRequests, 1)
+		reqID, connection.ConnID, timeout)
 	err = discoverClient.Send(request)
 	if err != nil {
-		log.GetNetworkLogger().Errorf(
-			"fail to send request for service %v, error is %+v", task.ServiceEventKey, err)
-		return err
+		log.GetNetworkLogger().Errorf("fail to send request for service %s::%s, error is %+v",
+			request.GetService().GetNamespace().GetValue(), request.GetService().GetName().GetValue(), err)
+		return nil, err
 	}
 	resp, err := discoverClient.Recv()
-	var sdkErr model.SDKError
 	if err != nil {
-		sdkErr = model.NewSDKError(model.ErrCodeNetworkError, err,
+		return nil, model.NewSDKError(model.ErrCodeNetworkError, err,
 			"error while receiving from %s(%s), reqID %s",
 			connection.ConnID, connection.Address, reqID)
-	} else {
-		err = pb.ValidateMessage(nil, resp)
-		if err != nil {
-			sdkErr = model.NewSDKError(model.ErrCodeInvalidResponse, err,
-				"invalid response from %s(%s), reqID %s",
-				connection.ConnID, connection.Address, reqID)
-		}
 	}
-	if nil != sdkErr {
-		return sdkErr
+	if err = pb.ValidateMessage(nil, resp); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeInvalidResponse, err,
+			"invalid response from %s(%s), reqID %s",
+			connection.ConnID, connection.Address, reqID)
+	}
+	return resp, nil
+}
+
+// QueryInstances 一次性查询服务实例，过滤条件随请求发送给服务端，应答不会写入本地缓存
+func (g *DiscoverConnector) QueryInstances(svcKey *model.ServiceKey, metadata map[string]string,
+	timeout time.Duration) (*serverconnector.ServiceEvent, error) {
+	connection, err := g.connManager.GetConnection(OpKeyDiscover, config.DiscoverCluster)
+	if err != nil {
+		return nil, err
 	}
+	defer connection.Release(OpKeyDiscover)
+	request := &apiservice.DiscoverRequest{
+		Type: apiservice.DiscoverRequest_INSTANCE,
+		Service: &apiservice.Service{
+			Name:      &wrappers.StringValue{Value: svcKey.Service},
+			Namespace: &wrappers.StringValue{Value: svcKey.Namespace},
+			Metadata:  pb.ToInstanceFilterMetadata(metadata),
+		},
+	}
+	resp, err := g.doDiscover(connection, request, timeout, nil)
+	if err != nil {
+		return nil, err
+	}
+	logDiscoverResponse(resp, connection)
+	svcEvent, _ := discoverResponseToEvent(resp, model.ServiceEventKey{
+		ServiceKey: *svcKey,
+		Type:       model.EventInstances,
+	}, connection, false)
+	return svcEvent, nil
+}
+
+// onSyncResponse 处理同步发现的应答
+func (g *DiscoverConnector) onSyncResponse(task *serviceUpdateTask, resp *apiservice.DiscoverResponse,
+	connection *network.Connection) {
 	// 打印应答报文
 	logDiscoverResponse(resp, connection)
-	svcEvent, _ := discoverResponseToEvent(resp, task.ServiceEventKey, connection)
+	svcEvent, _ := discoverResponseToEvent(resp, task.ServiceEventKey, connection, g.acceptDelta)
 	atomic.AddUint64(&task.successUpdates, 1)
 	task.handler.OnServiceUpdate(svcEvent)
-	return nil
 }
@@ -0,0 +1,63 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// TestDiscoverResponseToEventDelta 测试只有声明接受增量推送时才识别增量应答
+func TestDiscoverResponseToEventDelta(t *testing.T) {
+	newResp := func(metadata map[string]string) *apiservice.DiscoverResponse {
+		return &apiservice.DiscoverResponse{
+			Code: &wrappers.UInt32Value{Value: uint32(apimodel.Code_ExecuteSuccess)},
+			Type: apiservice.DiscoverResponse_INSTANCE,
+			Service: &apiservice.Service{
+				Name:      &wrappers.StringValue{Value: "svc"},
+				Namespace: &wrappers.StringValue{Value: "Test"},
+				Metadata:  metadata,
+			},
+		}
+	}
+	deltaMeta := map[string]string{pb.DeltaMetaKey: "true", pb.DeltaBaseRevisionMetaKey: "rev1"}
+	tests := []struct {
+		name        string
+		resp        *apiservice.DiscoverResponse
+		acceptDelta bool
+		want        bool
+	}{
+		{name: "声明接受且应答为增量", resp: newResp(deltaMeta), acceptDelta: true, want: true},
+		{name: "未声明接受时增量元数据按普通元数据处理", resp: newResp(deltaMeta), acceptDelta: false, want: false},
+		{name: "声明接受但应答为全量", resp: newResp(map[string]string{"env": "prod"}), acceptDelta: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svcEvent, _ := discoverResponseToEvent(tt.resp, hedgeSvcKey, newTestConnection(primaryAddr), tt.acceptDelta)
+			assert.Nil(t, svcEvent.Error)
+			assert.Same(t, tt.resp, svcEvent.Value)
+			assert.Equal(t, tt.want, svcEvent.Delta)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/network"
+)
+
+const (
+	// 选取对冲server地址的最大尝试次数
+	maxHedgeAddressPicks = 3
+)
+
+// hedgeResult 一路对冲请求的结果，建立连接失败时connection为空
+type hedgeResult struct {
+	resp       *apiservice.DiscoverResponse
+	connection *network.Connection
+	err        error
+}
+
+// SetHedging 设置首次发现的对冲请求参数，delay为0时不启用对冲
+func (g *DiscoverConnector) SetHedging(delay time.Duration, maxHedgedRequests int) {
+	g.hedgeDelay = delay
+	g.maxHedgedRequests = maxHedgedRequests
+}
+
+// hedgedFirstTask 异步执行非埋点集群的首次发现
+func (g *DiscoverConnector) hedgedFirstTask(task *serviceUpdateTask) {
+	if err := g.hedgedUpdateTask(task); err != nil {
+		g.retryUpdateTask(task, err, false)
+		return
+	}
+	task.lastUpdateTime.Store(time.Now())
+	g.addUpdateTaskSet(task)
+}
+
+// hedgedUpdateTask 在messageTimeout的截止时间内进行服务发现，
+// 当前请求超过hedgeDelay未返回或失败时，向其他server发起对冲请求，取最先成功的应答
+func (g *DiscoverConnector) hedgedUpdateTask(task *serviceUpdateTask) error {
+	deadline := time.Now().Add(g.messageTimeout)
+	connection, err := g.connManager.GetConnection(OpKeyDiscover, task.targetCluster)
+	if err != nil {
+		return err
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	results := make(chan *hedgeResult, g.maxHedgedRequests+1)
+	usedAddresses := map[string]bool{connection.Address: true}
+	go func() {
+		defer connection.Release(OpKeyDiscover)
+		g.discoverTo(task, connection, deadline, stopCh, results)
+	}()
+	pending := 1
+	hedges := 0
+	hedgeTimer := time.NewTimer(g.hedgeDelay)
+	defer hedgeTimer.Stop()
+	deadlineTimer := time.NewTimer(time.Until(deadline))
+	defer deadlineTimer.Stop()
+	var lastErr error
+	for {
+		if pending == 0 {
+			return lastErr
+		}
+		select {
+		case <-g.Done():
+			return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+				"hedgedUpdateTask: serverConnector has been destroyed")
+		case <-deadlineTimer.C:
+			return model.NewSDKError(model.ErrCodeAPITimeoutError, lastErr,
+				"discover %s timeout after %v, %d hedged requests sent", task.ServiceEventKey, g.messageTimeout, hedges)
+		case result := <-results:
+			pending--
+			consumeTime := GetUpdateTaskRequestTime(task)
+			if result.err == nil {
+				g.connManager.ReportSuccess(result.connection.ConnID, int32(model.ErrCodeSuccess), consumeTime)
+				g.onSyncResponse(task, result.resp, result.connection)
+				return nil
+			}
+			if result.connection != nil {
+				g.connManager.ReportFail(result.connection.ConnID, int32(model.ErrCodeNetworkError), consumeTime)
+			}
+			lastErr = result.err
+			// 请求失败时立即对冲，无需等待
+			if hedges < g.maxHedgedRequests && g.launchHedge(task, usedAddresses, deadline, stopCh, results) {
+				hedges++
+				pending++
+			}
+		case <-hedgeTimer.C:
+			if hedges < g.maxHedgedRequests && g.launchHedge(task, usedAddresses, deadline, stopCh, results) {
+				hedges++
+				pending++
+				hedgeTimer.Reset(g.hedgeDelay)
+			}
+		}
+	}
+}
+
+// launchHedge 选择一个未使用过的server，异步建立连接并发起对冲请求，
+// 建立连接可能阻塞，放在对冲协程中进行，避免阻塞对首个应答及截止时间的处理
+func (g *DiscoverConnector) launchHedge(task *serviceUpdateTask, usedAddresses map[string]bool,
+	deadline time.Time, stopCh <-chan struct{}, results chan<- *hedgeResult) bool {
+	if time.Until(deadline) <= 0 {
+		return false
+	}
+	for i := 0; i < maxHedgeAddressPicks; i++ {
+		addr, instance, err := g.connManager.GetHashExpectedInstance(task.targetCluster, []byte(NextDiscoverReqID()))
+		if err != nil {
+			log.GetNetworkLogger().Warnf("fail to pick hedge server for %s, error %v", task.ServiceEventKey, err)
+			return false
+		}
+		if usedAddresses[addr] {
+			continue
+		}
+		usedAddresses[addr] = true
+		log.GetNetworkLogger().Infof("send hedged discover request for %s to %s", task.ServiceEventKey, addr)
+		go func() {
+			connection, err := g.connManager.ConnectByAddr(task.targetCluster, addr, instance)
+			if err != nil {
+				log.GetNetworkLogger().Warnf("fail to connect hedge server %s for %s, error %v",
+					addr, task.ServiceEventKey, err)
+				results <- &hedgeResult{err: err}
+				return
+			}
+			defer func() {
+				connection.Release(OpKeyDiscover)
+				connection.ForceClose()
+			}()
+			g.discoverTo(task, connection, deadline, stopCh, results)
+		}()
+		return true
+	}
+	return false
+}
+
+// discoverTo 发起一路请求，超时时间为距离截止时间的剩余时长，并随请求传递给server
+func (g *DiscoverConnector) discoverTo(task *serviceUpdateTask, connection *network.Connection,
+	deadline time.Time, stopCh <-chan struct{}, results chan<- *hedgeResult) {
+	resp, err := g.discoverOnce(connection, task, time.Until(deadline), stopCh)
+	results <- &hedgeResult{resp: resp, connection: connection, err: err}
+}
@@ -0,0 +1,259 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/network"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+const (
+	primaryAddr = "127.0.0.1:8091"
+	hedgeAddr   = "127.0.0.2:8091"
+)
+
+var hedgeSvcKey = model.ServiceEventKey{
+	ServiceKey: model.ServiceKey{Namespace: "Test", Service: "svc"},
+	Type:       model.EventInstances,
+}
+
+type nopConn struct{}
+
+func (nopConn) Close() error {
+	return nil
+}
+
+func newTestConnection(addr string) *network.Connection {
+	return &network.Connection{
+		ConnID: network.ConnID{
+			Address: addr,
+			Service: config.ClusterService{ClusterType: config.DiscoverCluster},
+		},
+		Conn: nopConn{},
+	}
+}
+
+// serverBehavior 模拟server的行为，delay后返回应答，hang为true时一直阻塞到请求被取消
+type serverBehavior struct {
+	dialDelay time.Duration
+	delay     time.Duration
+	hang      bool
+}
+
+// fakeConnManager 主连接固定为primaryAddr，对冲地址固定为hedgeAddr
+type fakeConnManager struct {
+	network.ConnectionManager
+	servers map[string]serverBehavior
+	mutex   sync.Mutex
+	success []string
+}
+
+func (f *fakeConnManager) GetConnection(opKey string, clusterType config.ClusterType) (*network.Connection, error) {
+	return newTestConnection(primaryAddr), nil
+}
+
+func (f *fakeConnManager) GetHashExpectedInstance(clusterType config.ClusterType,
+	hash []byte) (string, model.Instance, error) {
+	return hedgeAddr, nil, nil
+}
+
+func (f *fakeConnManager) ConnectByAddr(clusterType config.ClusterType, addr string,
+	instance model.Instance) (*network.Connection, error) {
+	time.Sleep(f.servers[addr].dialDelay)
+	return newTestConnection(addr), nil
+}
+
+func (f *fakeConnManager) ReportSuccess(connID network.ConnID, retCode int32, timeout time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.success = append(f.success, connID.Address)
+}
+
+func (f *fakeConnManager) ReportFail(connID network.ConnID, retCode int32, timeout time.Duration) {
+}
+
+// fakeDiscoverClient 按serverBehavior返回应答
+type fakeDiscoverClient struct {
+	behavior serverBehavior
+	ctx      context.Context
+}
+
+func (f *fakeDiscoverClient) Send(*apiservice.DiscoverRequest) error {
+	return nil
+}
+
+func (f *fakeDiscoverClient) Recv() (*apiservice.DiscoverResponse, error) {
+	if f.behavior.hang {
+		<-f.ctx.Done()
+		return nil, f.ctx.Err()
+	}
+	select {
+	case <-time.After(f.behavior.delay):
+	case <-f.ctx.Done():
+		return nil, f.ctx.Err()
+	}
+	return &apiservice.DiscoverResponse{
+		Code: &wrappers.UInt32Value{Value: uint32(apimodel.Code_ExecuteSuccess)},
+		Type: apiservice.DiscoverResponse_INSTANCE,
+		Service: &apiservice.Service{
+			Namespace: &wrappers.StringValue{Value: hedgeSvcKey.Namespace},
+			Name:      &wrappers.StringValue{Value: hedgeSvcKey.Service},
+		},
+	}, nil
+}
+
+func (f *fakeDiscoverClient) CloseSend() error {
+	return nil
+}
+
+// fakeEventHandler 记录收到的服务事件
+type fakeEventHandler struct {
+	mutex  sync.Mutex
+	events []*serverconnector.ServiceEvent
+}
+
+func (f *fakeEventHandler) OnServiceUpdate(event *serverconnector.ServiceEvent) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.events = append(f.events, event)
+}
+
+func (f *fakeEventHandler) GetRevision() string {
+	return ""
+}
+
+func (f *fakeEventHandler) GetBusiness() string {
+	return ""
+}
+
+func newHedgeConnector(servers map[string]serverBehavior, messageTimeout time.Duration) (*DiscoverConnector,
+	*fakeConnManager) {
+	connManager := &fakeConnManager{servers: servers}
+	g := &DiscoverConnector{
+		RunContext:     common.NewRunContext(),
+		messageTimeout: messageTimeout,
+		connManager:    connManager,
+		createClient: func(args *DiscoverClientCreatorArgs) (DiscoverClient, context.CancelFunc, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), args.Timeout)
+			return &fakeDiscoverClient{behavior: servers[args.Connection.Address], ctx: ctx}, cancel, nil
+		},
+	}
+	g.SetHedging(20*time.Millisecond, 1)
+	return g, connManager
+}
+
+// TestHedgedUpdateTask 测试对冲请求取最先返回的应答，且在截止时间内返回
+func TestHedgedUpdateTask(t *testing.T) {
+	tests := []struct {
+		name           string
+		servers        map[string]serverBehavior
+		messageTimeout time.Duration
+		// maxElapsed 最长耗时
+		maxElapsed  time.Duration
+		wantErrCode model.ErrCode
+		wantSuccess string
+	}{
+		{
+			name: "对冲请求先返回",
+			servers: map[string]serverBehavior{
+				primaryAddr: {delay: 2 * time.Second},
+				hedgeAddr:   {delay: 10 * time.Millisecond},
+			},
+			messageTimeout: 5 * time.Second,
+			maxElapsed:     time.Second,
+			wantSuccess:    hedgeAddr,
+		},
+		{
+			name: "首个请求先返回",
+			servers: map[string]serverBehavior{
+				primaryAddr: {delay: 30 * time.Millisecond},
+				hedgeAddr:   {delay: 2 * time.Second},
+			},
+			messageTimeout: 5 * time.Second,
+			maxElapsed:     time.Second,
+			wantSuccess:    primaryAddr,
+		},
+		{
+			name: "均无应答时在截止时间返回",
+			servers: map[string]serverBehavior{
+				primaryAddr: {hang: true},
+				hedgeAddr:   {hang: true},
+			},
+			messageTimeout: 100 * time.Millisecond,
+			maxElapsed:     time.Second,
+			wantErrCode:    model.ErrCodeAPITimeoutError,
+		},
+		{
+			name: "对冲server建立连接阻塞时不影响截止时间",
+			servers: map[string]serverBehavior{
+				primaryAddr: {hang: true},
+				hedgeAddr:   {dialDelay: 3 * time.Second},
+			},
+			messageTimeout: 100 * time.Millisecond,
+			maxElapsed:     time.Second,
+			wantErrCode:    model.ErrCodeAPITimeoutError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, connManager := newHedgeConnector(tt.servers, tt.messageTimeout)
+			handler := &fakeEventHandler{}
+			task := &serviceUpdateTask{
+				ServiceEventKey: hedgeSvcKey,
+				targetCluster:   config.DiscoverCluster,
+				handler:         handler,
+			}
+			start := time.Now()
+			err := g.hedgedUpdateTask(task)
+			assert.True(t, time.Since(start) < tt.maxElapsed, "hedgedUpdateTask took %v", time.Since(start))
+			if tt.wantErrCode != model.ErrCodeSuccess {
+				var sdkErr model.SDKError
+				assert.True(t, errors.As(err, &sdkErr))
+				assert.Equal(t, tt.wantErrCode, sdkErr.ErrorCode())
+				assert.Empty(t, handler.events)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(handler.events))
+			connManager.mutex.Lock()
+			defer connManager.mutex.Unlock()
+			assert.Equal(t, []string{tt.wantSuccess}, connManager.success)
+		})
+	}
+}
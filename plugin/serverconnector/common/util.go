@@ -29,6 +29,7 @@ import (
 
 	"github.com/polarismesh/polaris-go/pkg/clock"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
 	"github.com/polarismesh/polaris-go/pkg/network"
 )
 
@@ -175,6 +176,12 @@ func GetUpdateTaskRequestTime(updateTask *serviceUpdateTask) time.Duration {
 // }
 
 func CreateHeadersContext(timeout time.Duration, options ...func(map[string]string)) (context.Context, context.CancelFunc) {
+	return CreateHeadersContextWithParent(context.Background(), timeout, options...)
+}
+
+// CreateHeadersContextWithParent 基于调用方的ctx创建带请求头的上下文，调用方取消时同步中止远程调用
+func CreateHeadersContextWithParent(parent context.Context, timeout time.Duration,
+	options ...func(map[string]string)) (context.Context, context.CancelFunc) {
 	headers := map[string]string{}
 	for _, option := range options {
 		option(headers)
@@ -184,9 +191,9 @@ func CreateHeadersContext(timeout time.Duration, options ...func(map[string]stri
 	var ctx context.Context
 	var cancel context.CancelFunc
 	if timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		ctx, cancel = context.WithTimeout(parent, timeout)
 	} else {
-		ctx = context.Background()
+		ctx = parent
 		cancel = nil
 	}
 	return metadata.NewOutgoingContext(ctx, md), cancel
@@ -203,3 +210,12 @@ func AppendHeaderWithReqId(reqID string) func(map[string]string) {
 		header[headerRequestID] = reqID
 	}
 }
+
+// AppendDeltaHeader 声明接受增量实例推送
+func AppendDeltaHeader(acceptDelta bool) func(map[string]string) {
+	return func(header map[string]string) {
+		if acceptDelta {
+			header[pb.DeltaHeader] = "true"
+		}
+	}
+}
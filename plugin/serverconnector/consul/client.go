@@ -0,0 +1,178 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package consul
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// headerToken ACL Token请求头
+	headerToken = "X-Consul-Token"
+	// checkStatusWarning 健康检查告警
+	checkStatusWarning = "warning"
+	// checkStatusCritical 健康检查失败
+	checkStatusCritical = "critical"
+	// serviceMaintenancePrefix 服务维护模式的检查ID前缀
+	serviceMaintenancePrefix = "_service_maintenance:"
+	// nodeMaintenanceCheckID 节点维护模式的检查ID
+	nodeMaintenanceCheckID = "_node_maintenance"
+)
+
+// healthEntry /v1/health/service/:service 的应答条目
+type healthEntry struct {
+	Node    nodeEntry     `json:"Node"`
+	Service serviceEntry  `json:"Service"`
+	Checks  []healthCheck `json:"Checks"`
+}
+
+type nodeEntry struct {
+	Node       string            `json:"Node"`
+	Address    string            `json:"Address"`
+	Datacenter string            `json:"Datacenter"`
+	Meta       map[string]string `json:"Meta"`
+}
+
+type serviceEntry struct {
+	ID      string            `json:"ID"`
+	Service string            `json:"Service"`
+	Address string            `json:"Address"`
+	Port    uint32            `json:"Port"`
+	Tags    []string          `json:"Tags"`
+	Meta    map[string]string `json:"Meta"`
+	Weights struct {
+		Passing uint32 `json:"Passing"`
+		Warning uint32 `json:"Warning"`
+	} `json:"Weights"`
+}
+
+type healthCheck struct {
+	CheckID string `json:"CheckID"`
+	Status  string `json:"Status"`
+}
+
+// kvPair /v1/kv/:key 的应答条目，Value为base64编码，由json自动解码
+type kvPair struct {
+	Key         string `json:"Key"`
+	Value       []byte `json:"Value"`
+	ModifyIndex uint64 `json:"ModifyIndex"`
+}
+
+// client Consul HTTP API客户端，请求失败时切换到下一个地址
+type client struct {
+	scheme     string
+	token      string
+	datacenter string
+	addresses  []string
+	index      uint32
+	httpClient *http.Client
+}
+
+func newClient(cfg *Config, addresses []string, timeout time.Duration) *client {
+	return &client{
+		scheme:     cfg.Scheme,
+		token:      cfg.Token,
+		datacenter: cfg.Datacenter,
+		addresses:  addresses,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// healthService 查询服务的所有实例及健康检查状态
+func (c *client) healthService(service string) ([]healthEntry, error) {
+	var entries []healthEntry
+	if _, err := c.get("/v1/health/service/"+url.PathEscape(service), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// catalogServices 查询数据中心的所有服务及其标签
+func (c *client) catalogServices() (map[string][]string, error) {
+	services := make(map[string][]string)
+	if _, err := c.get("/v1/catalog/services", &services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// getKV 查询KV，key不存在时返回nil
+func (c *client) getKV(key string) (*kvPair, error) {
+	var pairs []kvPair
+	found, err := c.get("/v1/kv/"+key, &pairs)
+	if err != nil || !found || len(pairs) == 0 {
+		return nil, err
+	}
+	return &pairs[0], nil
+}
+
+// get 发送GET请求并解析json应答，404时返回found为false
+func (c *client) get(path string, out interface{}) (bool, error) {
+	if len(c.addresses) == 0 {
+		return false, fmt.Errorf("consul addresses is empty")
+	}
+	query := url.Values{}
+	if c.datacenter != "" {
+		query.Set("dc", c.datacenter)
+	}
+	var lastErr error
+	for i := 0; i < len(c.addresses); i++ {
+		idx := atomic.LoadUint32(&c.index)
+		address := c.addresses[int(idx)%len(c.addresses)]
+		reqURL := url.URL{Scheme: c.scheme, Host: address, Path: path, RawQuery: query.Encode()}
+		found, err := c.doGet(reqURL.String(), out)
+		if err == nil {
+			return found, nil
+		}
+		lastErr = err
+		atomic.CompareAndSwapUint32(&c.index, idx, idx+1)
+	}
+	return false, lastErr
+}
+
+func (c *client) doGet(reqURL string, out interface{}) (bool, error) {
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return false, err
+	}
+	if c.token != "" {
+		req.Header.Set(headerToken, c.token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, json.Unmarshal(body, out)
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("GET %s, status %d, body %s", reqURL, resp.StatusCode, string(body))
+	}
+}
@@ -0,0 +1,75 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package consul
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DefaultScheme 默认访问协议
+	DefaultScheme = "http"
+	// DefaultKVPrefix 默认规则存放的KV前缀
+	DefaultKVPrefix = "polaris/rules"
+	// DefaultTagSeparator 默认的标签键值分隔符
+	DefaultTagSeparator = "="
+)
+
+// Config Consul连接器的插件配置
+type Config struct {
+	// Scheme 访问Consul HTTP API的协议，http或https
+	Scheme string `yaml:"scheme" json:"scheme"`
+	// Token Consul ACL Token
+	Token string `yaml:"token" json:"token"`
+	// Datacenter 查询的数据中心，为空时使用agent所在的数据中心
+	Datacenter string `yaml:"datacenter" json:"datacenter"`
+	// KVPrefix 规则在KV中的前缀，规则存放于 {prefix}/{namespace}/{service}/{routing|rate_limiting|circuit_breaker|fault_detect}
+	KVPrefix string `yaml:"kvPrefix" json:"kvPrefix"`
+	// TagSeparator 标签键值分隔符，形如key=value的标签转换为实例元数据，其余标签的值为空
+	TagSeparator string `yaml:"tagSeparator" json:"tagSeparator"`
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	if nil == c {
+		return errors.New("consul config is nil")
+	}
+	var errs error
+	if c.Scheme != "http" && c.Scheme != "https" {
+		errs = multierror.Append(errs, fmt.Errorf("consul.scheme must be http or https, got %s", c.Scheme))
+	}
+	if c.TagSeparator == "" {
+		errs = multierror.Append(errs, fmt.Errorf("consul.tagSeparator can not be empty"))
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.Scheme == "" {
+		c.Scheme = DefaultScheme
+	}
+	if c.KVPrefix == "" {
+		c.KVPrefix = DefaultKVPrefix
+	}
+	if c.TagSeparator == "" {
+		c.TagSeparator = DefaultTagSeparator
+	}
+}
@@ -0,0 +1,249 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package consul
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
+)
+
+const (
+	// protocolConsul 插件名，global.serverConnector.protocol配置为consul时启用
+	protocolConsul = "consul"
+	// defaultInstanceWeight Consul实例未设置权重时的默认权重
+	defaultInstanceWeight = 100
+	// metadataNode 实例所在Consul节点在元数据中的key
+	metadataNode = "consul.node"
+	// metadataProtocol 从服务元数据中读取实例协议的key
+	metadataProtocol = "protocol"
+)
+
+// Connector 从Consul读取服务、健康检查及KV中的规则，Consul标签转换为实例元数据，
+// 用于从Consul迁移期间对接已有的注册中心，注册、心跳等写操作不支持
+type Connector struct {
+	*plugin.PluginBase
+	cfg      *Config
+	enable   bool
+	client   *client
+	discover *connector.BridgeDiscover
+}
+
+// Type 插件类型
+func (c *Connector) Type() common.Type {
+	return common.TypeServerConnector
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (c *Connector) Name() string {
+	return protocolConsul
+}
+
+// Init 初始化插件
+func (c *Connector) Init(ctx *plugin.InitContext) error {
+	c.PluginBase = plugin.NewPluginBase(ctx)
+	connectorCfg := ctx.Config.GetGlobal().GetServerConnector()
+	c.enable = connectorCfg.GetProtocol() == c.Name()
+	if !c.enable {
+		return nil
+	}
+	c.cfg = connectorCfg.GetPluginConfig(c.Name()).(*Config)
+	c.client = newClient(c.cfg, connectorCfg.GetAddresses(), connectorCfg.GetMessageTimeout())
+	c.discover = connector.NewBridgeDiscover(c.Name(), c.fetch)
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (c *Connector) Destroy() error {
+	if c.enable {
+		c.discover.Destroy()
+	}
+	return nil
+}
+
+// IsEnable 插件开关
+func (c *Connector) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// RegisterServiceHandler 注册服务监听器
+func (c *Connector) RegisterServiceHandler(svcEventHandler *serverconnector.ServiceEventHandler) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	return c.discover.RegisterServiceHandler(svcEventHandler)
+}
+
+// DeRegisterServiceHandler 反注册事件监听器
+func (c *Connector) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	return c.discover.DeRegisterServiceHandler(key)
+}
+
+// RegisterInstance 迁移期间实例仍通过Consul注册，不支持注册
+func (c *Connector) RegisterInstance(req *model.InstanceRegisterRequest,
+	header map[string]string) (*model.InstanceRegisterResponse, error) {
+	return nil, c.notSupported("RegisterInstance")
+}
+
+// DeregisterInstance 迁移期间实例仍通过Consul注册，不支持反注册
+func (c *Connector) DeregisterInstance(instance *model.InstanceDeRegisterRequest) error {
+	return c.notSupported("DeregisterInstance")
+}
+
+// Heartbeat 健康状态由Consul健康检查决定，不支持心跳上报
+func (c *Connector) Heartbeat(instance *model.InstanceHeartbeatRequest) error {
+	return c.notSupported("Heartbeat")
+}
+
+// ReportClient Consul不提供地域信息，直接返回空应答
+func (c *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportClientResponse, error) {
+	return &model.ReportClientResponse{}, nil
+}
+
+// UpdateServers Consul地址固定为global.serverConnector.addresses，无需更新
+func (c *Connector) UpdateServers(key *model.ServiceEventKey) error {
+	return nil
+}
+
+func (c *Connector) notSupported(operation string) error {
+	return model.NewSDKError(model.ErrCodePluginError, nil,
+		"%s is not supported by %s server connector", operation, c.Name())
+}
+
+func (c *Connector) notEnabled() error {
+	return model.NewSDKError(model.ErrCodePluginError, nil,
+		"%s server connector is not enabled", c.Name())
+}
+
+// fetch 从Consul获取资源，Consul开源版没有命名空间，实例及服务列表的查询忽略命名空间
+func (c *Connector) fetch(key *model.ServiceEventKey) (*apiservice.DiscoverResponse, error) {
+	switch key.Type {
+	case model.EventInstances:
+		entries, err := c.client.healthService(key.Service)
+		if err != nil {
+			return nil, err
+		}
+		instances := make([]connector.BridgeInstance, 0, len(entries))
+		for i := range entries {
+			instances = append(instances, c.toBridgeInstance(&entries[i]))
+		}
+		return connector.NewBridgeInstancesResponse(key, instances), nil
+	case model.EventServices:
+		services, err := c.client.catalogServices()
+		if err != nil {
+			return nil, err
+		}
+		svcKeys := make([]model.ServiceKey, 0, len(services))
+		for name := range services {
+			svcKeys = append(svcKeys, model.ServiceKey{Namespace: key.Namespace, Service: name})
+		}
+		return connector.NewBridgeServicesResponse(key, svcKeys), nil
+	default:
+		return c.fetchRule(key)
+	}
+}
+
+// fetchRule 从KV读取json格式的规则，内容为DiscoverResponse中对应的规则字段，如{"routing": {...}}
+func (c *Connector) fetchRule(key *model.ServiceEventKey) (*apiservice.DiscoverResponse, error) {
+	pair, err := c.client.getKV(path.Join(c.cfg.KVPrefix, key.Namespace, key.Service, key.Type.String()))
+	if err != nil || pair == nil {
+		return nil, err
+	}
+	resp := connector.NewBridgeResponse(key)
+	if len(pair.Value) > 0 {
+		if err = jsonpb.UnmarshalString(string(pair.Value), resp); err != nil {
+			return nil, model.NewSDKError(model.ErrCodeInvalidRule, err,
+				"fail to unmarshal consul kv %s", pair.Key)
+		}
+	}
+	base := connector.NewBridgeResponse(key)
+	resp.Code = base.Code
+	resp.Type = base.Type
+	resp.Service = base.Service
+	resp.Service.Revision = &wrappers.StringValue{Value: strconv.FormatUint(pair.ModifyIndex, 10)}
+	return resp, nil
+}
+
+// toBridgeInstance 将Consul服务实例转换为北极星实例，任一检查为critical时实例不健康，处于维护模式时实例隔离
+func (c *Connector) toBridgeInstance(entry *healthEntry) connector.BridgeInstance {
+	host := entry.Service.Address
+	if host == "" {
+		host = entry.Node.Address
+	}
+	healthy, isolated, warning := true, false, false
+	for _, check := range entry.Checks {
+		switch check.Status {
+		case checkStatusCritical:
+			healthy = false
+			if check.CheckID == nodeMaintenanceCheckID || strings.HasPrefix(check.CheckID, serviceMaintenancePrefix) {
+				isolated = true
+			}
+		case checkStatusWarning:
+			warning = true
+		}
+	}
+	weight := entry.Service.Weights.Passing
+	if warning {
+		weight = entry.Service.Weights.Warning
+	}
+	if weight == 0 && !warning {
+		weight = defaultInstanceWeight
+	}
+	metadata := make(map[string]string, len(entry.Service.Meta)+len(entry.Service.Tags)+1)
+	for k, v := range entry.Service.Meta {
+		metadata[k] = v
+	}
+	for _, tag := range entry.Service.Tags {
+		kv := strings.SplitN(tag, c.cfg.TagSeparator, 2)
+		if len(kv) == 2 {
+			metadata[kv[0]] = kv[1]
+		} else {
+			metadata[tag] = ""
+		}
+	}
+	metadata[metadataNode] = entry.Node.Node
+	return connector.BridgeInstance{
+		ID:       entry.Node.Node + "-" + entry.Service.ID,
+		Host:     host,
+		Port:     entry.Service.Port,
+		Protocol: entry.Service.Meta[metadataProtocol],
+		Weight:   weight,
+		Healthy:  healthy,
+		Isolated: isolated,
+		Metadata: metadata,
+		Zone:     entry.Node.Datacenter,
+	}
+}
+
+// init 注册插件信息
+func init() {
+	plugin.RegisterConfigurablePlugin(&Connector{}, &Config{})
+}
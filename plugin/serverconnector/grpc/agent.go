@@ -0,0 +1,55 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package grpc
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// initAgent 配置了本机缓存代理地址时，建立到代理的连接，服务发现的stream优先走代理
+func (g *Connector) initAgent() error {
+	if g.cfg == nil || len(g.cfg.AgentAddress) == 0 {
+		return nil
+	}
+	conn, err := grpc.Dial(g.cfg.AgentAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(g.cfg.MaxCallRecvMsgSizeLimit)))
+	if err != nil {
+		return err
+	}
+	log.GetBaseLogger().Infof("discover through local agent %s", g.cfg.AgentAddress)
+	g.agentConn = conn
+	return nil
+}
+
+// agentConnection 获取可用的代理连接，代理不可用时返回nil，回退到直连服务端
+func (g *Connector) agentConnection() *grpc.ClientConn {
+	if g.agentConn == nil {
+		return nil
+	}
+	switch g.agentConn.GetState() {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		g.agentConn.Connect()
+		return nil
+	default:
+		return g.agentConn
+	}
+}
@@ -0,0 +1,77 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"sync/atomic"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// callOptions 单次调用的选项，包括压缩算法以及当前协商的接收包大小
+func (g *Connector) callOptions() []grpc.CallOption {
+	if g.cfg == nil {
+		return nil
+	}
+	opts := []grpc.CallOption{grpc.MaxCallRecvMsgSize(int(atomic.LoadInt32(&g.recvMsgSize)))}
+	if g.cfg.Compressor != CompressorNone {
+		opts = append(opts, grpc.UseCompressor(g.cfg.Compressor))
+	}
+	return opts
+}
+
+// onCallError 应答超过接收包大小时，扩大后续调用的接收包大小
+func (g *Connector) onCallError(err error) {
+	if g.cfg == nil || status.Code(err) != codes.ResourceExhausted {
+		return
+	}
+	for {
+		curSize := atomic.LoadInt32(&g.recvMsgSize)
+		if int(curSize) >= g.cfg.MaxCallRecvMsgSizeLimit {
+			return
+		}
+		nextSize := int(curSize) * 2
+		if nextSize > g.cfg.MaxCallRecvMsgSizeLimit {
+			nextSize = g.cfg.MaxCallRecvMsgSizeLimit
+		}
+		if atomic.CompareAndSwapInt32(&g.recvMsgSize, curSize, int32(nextSize)) {
+			log.GetNetworkLogger().Warnf("message exceeds recv size %d, enlarge to %d", curSize, nextSize)
+			return
+		}
+	}
+}
+
+// discoverClient 包装服务发现流，用于感知接收包大小超限
+type discoverClient struct {
+	apiservice.PolarisGRPC_DiscoverClient
+	connector *Connector
+}
+
+// Recv 接收服务发现应答
+func (d *discoverClient) Recv() (*apiservice.DiscoverResponse, error) {
+	resp, err := d.PolarisGRPC_DiscoverClient.Recv()
+	if err != nil {
+		d.connector.onCallError(err)
+	}
+	return resp, err
+}
@@ -19,8 +19,13 @@ package grpc
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"google.golang.org/grpc/encoding"
+	// 注册gzip压缩器
+	_ "google.golang.org/grpc/encoding/gzip"
 )
 
 const (
@@ -28,11 +33,28 @@ const (
 	DefaultMaxCallRecvMsgSize = 50 * 1024 * 1024
 	// MaxMaxCallRecvMsgSize GRPC链路包接收大小的设置上限
 	MaxMaxCallRecvMsgSize = 500 * 1024 * 1024
+	// CompressorNone 不启用压缩
+	CompressorNone = "none"
+	// DefaultMaxHedgedRequests 默认最多额外发起的对冲请求数
+	DefaultMaxHedgedRequests = 1
 )
 
 // GRPC插件级别配置
 type networkConfig struct {
 	MaxCallRecvMsgSize int `yaml:"maxCallRecvMsgSize"`
+	// 应答超过接收包大小时自动扩大的上限
+	MaxCallRecvMsgSizeLimit int `yaml:"maxCallRecvMsgSizeLimit"`
+	// 请求压缩算法，需为已注册到grpc的压缩器，如gzip，zstd需引入对应的压缩器包
+	Compressor string `yaml:"compressor"`
+	// 首次服务发现超过该时长未返回时，向其他server发起对冲请求，为0时不启用
+	HedgeDelay time.Duration `yaml:"hedgeDelay"`
+	// 最多额外发起的对冲请求数
+	MaxHedgedRequests int `yaml:"maxHedgedRequests"`
+	// 服务端是否支持增量实例推送，开启后才会携带X-Polaris-Delta请求头并解析internal-delta元数据，
+	// 服务端不支持时需保持关闭，否则服务自身的同名元数据会被误认为增量标记
+	DeltaInstances bool `yaml:"deltaInstances"`
+	// 本机缓存代理地址，如unix:///var/run/polaris/agent.sock，配置后服务发现通过代理进行，代理不可用时直连服务端
+	AgentAddress string `yaml:"agentAddress"`
 }
 
 // Verify 校验GRPC配置值
@@ -41,6 +63,22 @@ func (r *networkConfig) Verify() error {
 	if r.MaxCallRecvMsgSize <= 0 || r.MaxCallRecvMsgSize > MaxMaxCallRecvMsgSize {
 		errs = multierror.Append(errs, fmt.Errorf("grpc.maxCallRecvMsgSize must be int (0, 524288000]"))
 	}
+	if r.MaxCallRecvMsgSizeLimit < r.MaxCallRecvMsgSize || r.MaxCallRecvMsgSizeLimit > MaxMaxCallRecvMsgSize {
+		errs = multierror.Append(errs,
+			fmt.Errorf("grpc.maxCallRecvMsgSizeLimit must be int [maxCallRecvMsgSize, 524288000]"))
+	}
+	if r.Compressor != CompressorNone && encoding.GetCompressor(r.Compressor) == nil {
+		errs = multierror.Append(errs, fmt.Errorf("grpc.compressor %s is not registered", r.Compressor))
+	}
+	if r.HedgeDelay < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("grpc.hedgeDelay must not be negative"))
+	}
+	if len(r.AgentAddress) > 0 && !strings.HasPrefix(r.AgentAddress, "unix://") {
+		errs = multierror.Append(errs, fmt.Errorf("grpc.agentAddress %s must start with unix://", r.AgentAddress))
+	}
+	if r.MaxHedgedRequests <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("grpc.maxHedgedRequests must be greater than 0"))
+	}
 	return errs
 }
 
@@ -49,4 +87,13 @@ func (r *networkConfig) SetDefault() {
 	if r.MaxCallRecvMsgSize <= 0 {
 		r.MaxCallRecvMsgSize = DefaultMaxCallRecvMsgSize
 	}
+	if r.MaxCallRecvMsgSizeLimit <= 0 {
+		r.MaxCallRecvMsgSizeLimit = MaxMaxCallRecvMsgSize
+	}
+	if len(r.Compressor) == 0 {
+		r.Compressor = CompressorNone
+	}
+	if r.MaxHedgedRequests <= 0 {
+		r.MaxHedgedRequests = DefaultMaxHedgedRequests
+	}
 }
@@ -19,9 +19,11 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 	"time"
 
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/grpc"
 
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -29,6 +31,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/network"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/identity"
 	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
 	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
 )
@@ -52,7 +55,15 @@ type Connector struct {
 	discoverConnector     *connector.DiscoverConnector
 	// 有没有打印过connManager ready的信息，用于避免重复打印
 	hasPrintedReady uint32
-	token           string
+	tokenProvider   *network.TokenProvider
+	// 与server通信的TLS配置，未启用时为空
+	tlsConfig *tls.Config
+	// 是否与进程内其他SDK上下文共享连接
+	shareConnection bool
+	// 当前协商的接收包大小
+	recvMsgSize int32
+	// 本机缓存代理连接
+	agentConn *grpc.ClientConn
 }
 
 // Type 插件类型
@@ -72,8 +83,20 @@ func (g *Connector) Init(ctx *plugin.InitContext) error {
 	cfgValue := ctx.Config.GetGlobal().GetServerConnector().GetPluginConfig(g.Name())
 	if cfgValue != nil {
 		g.cfg = cfgValue.(*networkConfig)
+		g.recvMsgSize = int32(g.cfg.MaxCallRecvMsgSize)
 	}
-	g.token = ctx.Config.GetGlobal().GetServerConnector().GetToken()
+	connectorCfg := ctx.Config.GetGlobal().GetServerConnector()
+	g.tokenProvider = network.NewTokenProvider(connectorCfg)
+	identityProvider, err := identity.GetIdentityProvider(ctx.Config, ctx.Plugins)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := network.BuildTLSConfig(connectorCfg.GetTLS(), identityProvider)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to build tls config for serverConnector")
+	}
+	g.tlsConfig = tlsConfig
+	g.shareConnection = connectorCfg.IsShareConnection()
 	g.connManager = ctx.ConnManager
 	g.connectionIdleTimeout = ctx.Config.GetGlobal().GetServerConnector().GetConnectionIdleTimeout()
 	g.valueCtx = ctx.ValueCtx
@@ -85,7 +108,11 @@ func (g *Connector) Init(ctx *plugin.InitContext) error {
 	g.discoverConnector = &connector.DiscoverConnector{}
 	g.discoverConnector.ServiceConnector = g.PluginBase
 	g.discoverConnector.Init(ctx, g.createDiscoverClient)
-	return nil
+	if g.cfg != nil {
+		g.discoverConnector.SetHedging(g.cfg.HedgeDelay, g.cfg.MaxHedgedRequests)
+		g.discoverConnector.SetAcceptDelta(g.cfg.DeltaInstances)
+	}
+	return g.initAgent()
 }
 
 // Start 启动插件
@@ -102,13 +129,21 @@ func (g *Connector) GetConnectionManager() network.ConnectionManager {
 // 创建服务发现客户端
 func (g *Connector) createDiscoverClient(args *connector.DiscoverClientCreatorArgs) (connector.DiscoverClient, context.CancelFunc, error) {
 	// 创建namingClient对象
-	client := apiservice.NewPolarisGRPCClient(network.ToGRPCConn(args.Connection.Conn))
+	conn := network.ToGRPCConn(args.Connection.Conn)
+	if agentConn := g.agentConnection(); agentConn != nil {
+		conn = agentConn
+	}
+	client := apiservice.NewPolarisGRPCClient(conn)
 	outgoingCtx, cancel := connector.CreateHeadersContext(args.Timeout,
 		connector.AppendAuthHeader(args.AuthToken),
-		connector.AppendHeaderWithReqId(args.ReqId))
+		connector.AppendHeaderWithReqId(args.ReqId),
+		connector.AppendDeltaHeader(args.AcceptDelta))
 
-	discoverClient, err := client.Discover(outgoingCtx)
-	return discoverClient, cancel, err
+	stream, err := client.Discover(outgoingCtx, g.callOptions()...)
+	if err != nil {
+		return nil, cancel, err
+	}
+	return &discoverClient{PolarisGRPC_DiscoverClient: stream, connector: g}, cancel, nil
 }
 
 // Destroy 销毁插件，可用于释放资源
@@ -116,6 +151,9 @@ func (g *Connector) Destroy() error {
 	_ = g.RunContext.Destroy()
 	_ = g.discoverConnector.Destroy()
 	g.connManager.Destroy()
+	if g.agentConn != nil {
+		_ = g.agentConn.Close()
+	}
 	return nil
 }
 
@@ -136,6 +174,32 @@ func (g *Connector) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
 	return g.discoverConnector.DeRegisterServiceHandler(key)
 }
 
+// RefreshServiceHandler 立即刷新已监听的资源
+func (g *Connector) RefreshServiceHandler(key *model.ServiceEventKey) error {
+	return g.discoverConnector.RefreshServiceHandler(key)
+}
+
+// QueryInstances 直接向服务端查询实例，由服务端按实例元数据过滤
+func (g *Connector) QueryInstances(svcKey *model.ServiceKey, metadata map[string]string,
+	timeout time.Duration) (*serverconnector.ServiceEvent, error) {
+	return g.discoverConnector.QueryInstances(svcKey, metadata, timeout)
+}
+
+// GetPendingDiscoverRequests 获取已发送但尚未收到应答的服务发现请求数
+func (g *Connector) GetPendingDiscoverRequests() int {
+	return g.discoverConnector.GetPendingDiscoverRequests()
+}
+
+// GetDiscoverQueueDepth 获取等待处理的服务发现任务数
+func (g *Connector) GetDiscoverQueueDepth() int {
+	return g.discoverConnector.GetTaskQueueDepth()
+}
+
+// GetReconnectCount 获取与服务端重新建立连接的累计次数
+func (g *Connector) GetReconnectCount() int64 {
+	return g.connManager.GetReconnectCount()
+}
+
 // UpdateServers 更新服务端地址
 // 异常场景：当地址列表为空，或者地址全部连接失败，则返回error，调用者需进行重试
 func (g *Connector) UpdateServers(key *model.ServiceEventKey) error {
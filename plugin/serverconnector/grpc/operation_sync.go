@@ -56,8 +56,8 @@ func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextRegisterInstanceReqID()
-		ctx, cancel  = connector.CreateHeadersContext(*req.Timeout,
-			connector.AppendAuthHeader(g.token),
+		ctx, cancel  = connector.CreateHeadersContextWithParent(req.GetContext(), *req.Timeout,
+			connector.AppendAuthHeader(g.tokenProvider.GetToken()),
 			connector.AppendHeaderWithReqId(reqID))
 	)
 
@@ -70,9 +70,10 @@ func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header
 		reqJson, _ := (&jsonpb.Marshaler{}).MarshalToString(reqProto)
 		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
 	}
-	pbResp, err := namingClient.RegisterInstance(ctx, reqProto)
+	pbResp, err := namingClient.RegisterInstance(ctx, reqProto, g.callOptions()...)
 	endTime := clock.GetClock().Now()
 	if err != nil {
+		g.onCallError(err)
 		return nil, connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
 			fmt.Sprintf("fail to registerInstance, request %s, "+
 				"reason is fail to send request, reqID %s, server %s", *req, reqID, conn.ConnID))
@@ -122,8 +123,8 @@ func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) err
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextDeRegisterInstanceReqID()
-		ctx, cancel  = connector.CreateHeadersContext(*req.Timeout,
-			connector.AppendAuthHeader(g.token),
+		ctx, cancel  = connector.CreateHeadersContextWithParent(req.GetContext(), *req.Timeout,
+			connector.AppendAuthHeader(g.tokenProvider.GetToken()),
 			connector.AppendHeaderWithReqId(reqID))
 	)
 	if cancel != nil {
@@ -135,9 +136,10 @@ func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) err
 		reqJson, _ := (&jsonpb.Marshaler{}).MarshalToString(reqProto)
 		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
 	}
-	pbResp, err := namingClient.DeregisterInstance(ctx, reqProto)
+	pbResp, err := namingClient.DeregisterInstance(ctx, reqProto, g.callOptions()...)
 	endTime := clock.GetClock().Now()
 	if err != nil {
+		g.onCallError(err)
 		return connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
 			fmt.Sprintf("fail to deregisterInstance, request %s, "+
 				"reason is fail to send request, reqID %s, server %s", *req, reqID, conn.ConnID))
@@ -185,8 +187,8 @@ func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextHeartbeatReqID()
-		ctx, cancel  = connector.CreateHeadersContext(*req.Timeout,
-			connector.AppendAuthHeader(g.token),
+		ctx, cancel  = connector.CreateHeadersContextWithParent(req.GetContext(), *req.Timeout,
+			connector.AppendAuthHeader(g.tokenProvider.GetToken()),
 			connector.AppendHeaderWithReqId(reqID))
 	)
 	if cancel != nil {
@@ -198,9 +200,10 @@ func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
 		reqJson, _ := (&jsonpb.Marshaler{}).MarshalToString(reqProto)
 		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
 	}
-	pbResp, err := namingClient.Heartbeat(ctx, reqProto)
+	pbResp, err := namingClient.Heartbeat(ctx, reqProto, g.callOptions()...)
 	endTime := clock.GetClock().Now()
 	if err != nil {
+		g.onCallError(err)
 		return connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
 			fmt.Sprintf("fail to heartbeat, request %s, reason is fail to send request, reqID %s, server %s",
 				*req, reqID, conn.ConnID))
@@ -276,7 +279,7 @@ func (g *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportC
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextReportClientReqID()
 		ctx, cancel  = connector.CreateHeadersContext(req.Timeout,
-			connector.AppendAuthHeader(g.token),
+			connector.AppendAuthHeader(g.tokenProvider.GetToken()),
 			connector.AppendHeaderWithReqId(reqID))
 	)
 	if cancel != nil {
@@ -292,9 +295,10 @@ func (g *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportC
 		reqJson, _ := (&jsonpb.Marshaler{}).MarshalToString(reqProto)
 		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
 	}
-	pbResp, err := namingClient.ReportClient(ctx, reqProto)
+	pbResp, err := namingClient.ReportClient(ctx, reqProto, g.callOptions()...)
 	endTime := g.valueCtx.Now()
 	if err != nil {
+		g.onCallError(err)
 		return nil, connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
 			fmt.Sprintf("fail to send request, opKey %s, reqID %s, connID %s", opKey, reqID, conn.ConnID))
 	}
@@ -0,0 +1,117 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package http
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/network"
+)
+
+const (
+	// headerAuthToken 鉴权token请求头
+	headerAuthToken = "X-Polaris-Token"
+	// 北极星客户端HTTP接口
+	pathDiscover           = "/v1/Discover"
+	pathRegisterInstance   = "/v1/RegisterInstance"
+	pathDeregisterInstance = "/v1/DeregisterInstance"
+	pathHeartbeat          = "/v1/Heartbeat"
+	pathReportClient       = "/v1/ReportClient"
+)
+
+// client 北极星HTTP接口客户端，使用json编码的protobuf报文，请求失败时切换到下一个地址
+type client struct {
+	addresses     []string
+	index         uint32
+	scheme        string
+	tokenProvider *network.TokenProvider
+	httpClient    *http.Client
+	marshaler     *jsonpb.Marshaler
+	unmarshal     *jsonpb.Unmarshaler
+}
+
+func newClient(addresses []string, tokenProvider *network.TokenProvider, tlsConfig *tls.Config,
+	timeout time.Duration) *client {
+	c := &client{
+		addresses:     addresses,
+		scheme:        "http://",
+		tokenProvider: tokenProvider,
+		httpClient:    &http.Client{Timeout: timeout},
+		marshaler:     &jsonpb.Marshaler{},
+		unmarshal:     &jsonpb.Unmarshaler{AllowUnknownFields: true},
+	}
+	if nil != tlsConfig {
+		c.scheme = "https://"
+		c.httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+	return c
+}
+
+// post 发送请求，服务端业务错误也会返回json报文，由调用方根据应答中的code判断
+func (c *client) post(path string, req proto.Message, resp proto.Message, timeout time.Duration) error {
+	if len(c.addresses) == 0 {
+		return fmt.Errorf("polaris http addresses is empty")
+	}
+	body, err := c.marshaler.MarshalToString(req)
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	for i := 0; i < len(c.addresses); i++ {
+		idx := atomic.LoadUint32(&c.index)
+		address := c.addresses[int(idx)%len(c.addresses)]
+		if lastErr = c.postOnce(address, path, body, resp, timeout); lastErr == nil {
+			return nil
+		}
+		log.GetNetworkLogger().Warnf("[HTTP] fail to post %s to %s, err %v", path, address, lastErr)
+		atomic.CompareAndSwapUint32(&c.index, idx, idx+1)
+	}
+	return lastErr
+}
+
+func (c *client) postOnce(address string, path string, body string, resp proto.Message, timeout time.Duration) error {
+	req, err := http.NewRequest(http.MethodPost, c.scheme+address+path, bytes.NewBufferString(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := c.tokenProvider.GetToken(); token != "" {
+		req.Header.Set(headerAuthToken, token)
+	}
+	httpClient := c.httpClient
+	if timeout > 0 {
+		httpClient = &http.Client{Timeout: timeout, Transport: c.httpClient.Transport}
+	}
+	httpResp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	if err = c.unmarshal.Unmarshal(httpResp.Body, resp); err != nil {
+		return fmt.Errorf("status %d, fail to unmarshal response: %v", httpResp.StatusCode, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package http
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DefaultHTTPPort 北极星服务端默认的HTTP端口
+	DefaultHTTPPort = 8090
+	// DefaultProbeInterval 默认的gRPC端口探测间隔
+	DefaultProbeInterval = 30 * time.Second
+	// DefaultFailureThreshold 默认的gRPC连续失败次数阈值
+	DefaultFailureThreshold = 3
+)
+
+// Config HTTP连接器的插件配置
+type Config struct {
+	// Addresses 北极星服务端HTTP地址，为空时使用global.serverConnector.addresses的主机及默认HTTP端口8090
+	Addresses []string `yaml:"addresses" json:"addresses"`
+	// PreferGRPC 优先使用gRPC协议，gRPC端口不可达或连续失败时自动降级为HTTP，恢复后切回gRPC
+	PreferGRPC bool `yaml:"preferGrpc" json:"preferGrpc"`
+	// ProbeInterval gRPC端口的探测间隔
+	ProbeInterval time.Duration `yaml:"probeInterval" json:"probeInterval"`
+	// FailureThreshold gRPC同步请求连续网络失败达到该次数时降级为HTTP
+	FailureThreshold int `yaml:"failureThreshold" json:"failureThreshold"`
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	if nil == c {
+		return errors.New("http config is nil")
+	}
+	var errs error
+	if c.ProbeInterval < time.Second {
+		errs = multierror.Append(errs, fmt.Errorf("http.probeInterval must be greater than 1s"))
+	}
+	if c.FailureThreshold <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("http.failureThreshold must be greater than 0"))
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.ProbeInterval == 0 {
+		c.ProbeInterval = DefaultProbeInterval
+	}
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = DefaultFailureThreshold
+	}
+}
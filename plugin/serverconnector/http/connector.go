@@ -0,0 +1,484 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package http
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/network"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/identity"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
+)
+
+const (
+	// protocolHTTP 插件名，global.serverConnector.protocol配置为http时启用
+	protocolHTTP = "http"
+	// protocolGRPC 优先使用的gRPC连接器插件名
+	protocolGRPC = "grpc"
+)
+
+// operations 服务发现及注册操作，由gRPC连接器或HTTP实现
+type operations interface {
+	RegisterServiceHandler(*serverconnector.ServiceEventHandler) error
+	DeRegisterServiceHandler(*model.ServiceEventKey) error
+	RegisterInstance(*model.InstanceRegisterRequest, map[string]string) (*model.InstanceRegisterResponse, error)
+	DeregisterInstance(*model.InstanceDeRegisterRequest) error
+	Heartbeat(*model.InstanceHeartbeatRequest) error
+	ReportClient(*model.ReportClientRequest) (*model.ReportClientResponse, error)
+	UpdateServers(*model.ServiceEventKey) error
+}
+
+// Connector 通过北极星HTTP接口（json）对接服务端，用于gRPC端口被代理或防火墙拦截的场景。
+// 服务发现按本地版本号轮询，服务端数据未变更时只返回DataNoChange，以此近似gRPC的流式推送。
+// 配置preferGrpc后优先使用gRPC连接器，gRPC不可用时自动降级为HTTP，探测恢复后切回gRPC
+type Connector struct {
+	*plugin.PluginBase
+	cfg            *Config
+	enable         bool
+	grpcAddresses  []string
+	connectTimeout time.Duration
+	messageTimeout time.Duration
+	plugins        plugin.Supplier
+	connManager    network.ConnectionManager
+	http           *httpOperations
+	grpc           serverconnector.ServerConnector
+	// useHTTP 当前是否使用HTTP，未配置preferGrpc时始终为1
+	useHTTP     uint32
+	failures    int32
+	switchMutex sync.Mutex
+	handlers    sync.Map
+	done        chan struct{}
+	destroy     sync.Once
+}
+
+// Type 插件类型
+func (c *Connector) Type() common.Type {
+	return common.TypeServerConnector
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (c *Connector) Name() string {
+	return protocolHTTP
+}
+
+// Init 初始化插件
+func (c *Connector) Init(ctx *plugin.InitContext) error {
+	c.PluginBase = plugin.NewPluginBase(ctx)
+	connectorCfg := ctx.Config.GetGlobal().GetServerConnector()
+	c.enable = connectorCfg.GetProtocol() == c.Name()
+	if !c.enable {
+		return nil
+	}
+	c.cfg = connectorCfg.GetPluginConfig(c.Name()).(*Config)
+	c.grpcAddresses = connectorCfg.GetAddresses()
+	c.connectTimeout = connectorCfg.GetConnectTimeout()
+	c.messageTimeout = connectorCfg.GetMessageTimeout()
+	c.plugins = ctx.Plugins
+	c.connManager = ctx.ConnManager
+	c.done = make(chan struct{})
+	addresses := c.cfg.Addresses
+	if len(addresses) == 0 {
+		addresses = toHTTPAddresses(c.grpcAddresses)
+	}
+	identityProvider, err := identity.GetIdentityProvider(ctx.Config, ctx.Plugins)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := network.BuildTLSConfig(connectorCfg.GetTLS(), identityProvider)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to build tls config for serverConnector")
+	}
+	c.http = &httpOperations{
+		client:         newClient(addresses, network.NewTokenProvider(connectorCfg), tlsConfig, c.messageTimeout),
+		messageTimeout: c.messageTimeout,
+	}
+	c.http.discover = connector.NewBridgeRevisionDiscover(c.Name(), c.http.fetch)
+	c.useHTTP = 1
+	return nil
+}
+
+// Start 启动插件，优先使用gRPC时探测gRPC端口是否可达
+func (c *Connector) Start() error {
+	if !c.enable || !c.cfg.PreferGRPC {
+		return nil
+	}
+	grpcPlugin, err := c.plugins.GetPlugin(common.TypeServerConnector, protocolGRPC)
+	if err != nil {
+		return err
+	}
+	c.grpc = grpcPlugin.(serverconnector.ServerConnector)
+	if creator, ok := grpcPlugin.(network.ConnCreator); ok {
+		c.connManager.SetConnCreator(creator)
+	}
+	if c.probeGRPC() {
+		atomic.StoreUint32(&c.useHTTP, 0)
+	} else {
+		log.GetBaseLogger().Warnf("[HTTP] grpc addresses %v are unreachable, use http protocol", c.grpcAddresses)
+	}
+	go c.probeLoop()
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (c *Connector) Destroy() error {
+	if c.enable {
+		c.destroy.Do(func() {
+			close(c.done)
+			c.http.discover.Destroy()
+		})
+	}
+	return nil
+}
+
+// IsEnable 插件开关
+func (c *Connector) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// RegisterServiceHandler 注册服务监听器
+func (c *Connector) RegisterServiceHandler(svcEventHandler *serverconnector.ServiceEventHandler) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	c.switchMutex.Lock()
+	defer c.switchMutex.Unlock()
+	c.handlers.Store(*svcEventHandler.ServiceEventKey, svcEventHandler)
+	return c.active().RegisterServiceHandler(svcEventHandler)
+}
+
+// DeRegisterServiceHandler 反注册事件监听器
+func (c *Connector) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	c.switchMutex.Lock()
+	defer c.switchMutex.Unlock()
+	c.handlers.Delete(*key)
+	return c.active().DeRegisterServiceHandler(key)
+}
+
+// RegisterInstance 同步注册服务
+func (c *Connector) RegisterInstance(req *model.InstanceRegisterRequest,
+	header map[string]string) (*model.InstanceRegisterResponse, error) {
+	if !c.enable {
+		return nil, c.notEnabled()
+	}
+	ops := c.active()
+	resp, err := ops.RegisterInstance(req, header)
+	if c.onResult(ops, err) {
+		return c.http.RegisterInstance(req, header)
+	}
+	return resp, err
+}
+
+// DeregisterInstance 同步反注册服务
+func (c *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	ops := c.active()
+	err := ops.DeregisterInstance(req)
+	if c.onResult(ops, err) {
+		return c.http.DeregisterInstance(req)
+	}
+	return err
+}
+
+// Heartbeat 心跳上报
+func (c *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	ops := c.active()
+	err := ops.Heartbeat(req)
+	if c.onResult(ops, err) {
+		return c.http.Heartbeat(req)
+	}
+	return err
+}
+
+// ReportClient 上报客户端信息
+func (c *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportClientResponse, error) {
+	if !c.enable {
+		return nil, c.notEnabled()
+	}
+	ops := c.active()
+	resp, err := ops.ReportClient(req)
+	if c.onResult(ops, err) {
+		return c.http.ReportClient(req)
+	}
+	return resp, err
+}
+
+// UpdateServers 更新服务端地址
+func (c *Connector) UpdateServers(key *model.ServiceEventKey) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	return c.active().UpdateServers(key)
+}
+
+func (c *Connector) notEnabled() error {
+	return model.NewSDKError(model.ErrCodePluginError, nil,
+		"%s server connector is not enabled", c.Name())
+}
+
+// active 当前生效的实现
+func (c *Connector) active() operations {
+	if atomic.LoadUint32(&c.useHTTP) == 1 {
+		return c.http
+	}
+	return c.grpc
+}
+
+// onResult 统计gRPC同步请求的网络失败，连续失败达到阈值时降级为HTTP，返回是否需要通过HTTP重试本次请求
+func (c *Connector) onResult(ops operations, err error) bool {
+	if ops == operations(c.http) {
+		return false
+	}
+	sdkErr, ok := err.(model.SDKError)
+	if !ok || (sdkErr.ErrorCode() != model.ErrCodeNetworkError && sdkErr.ErrorCode() != model.ErrCodeConnectError) {
+		atomic.StoreInt32(&c.failures, 0)
+		return false
+	}
+	if atomic.AddInt32(&c.failures, 1) < int32(c.cfg.FailureThreshold) {
+		return false
+	}
+	log.GetBaseLogger().Warnf("[HTTP] grpc failed %d times continuously, fallback to http protocol, err %v",
+		c.cfg.FailureThreshold, err)
+	c.switchTo(true)
+	return true
+}
+
+// switchTo 切换协议，并将已注册的服务监听器迁移到新的实现上
+func (c *Connector) switchTo(useHTTP bool) {
+	c.switchMutex.Lock()
+	defer c.switchMutex.Unlock()
+	target := uint32(0)
+	if useHTTP {
+		target = 1
+	}
+	if atomic.LoadUint32(&c.useHTTP) == target {
+		return
+	}
+	from := c.active()
+	atomic.StoreUint32(&c.useHTTP, target)
+	atomic.StoreInt32(&c.failures, 0)
+	to := c.active()
+	c.handlers.Range(func(key, value interface{}) bool {
+		eventKey := key.(model.ServiceEventKey)
+		_ = from.DeRegisterServiceHandler(&eventKey)
+		if err := to.RegisterServiceHandler(value.(*serverconnector.ServiceEventHandler)); err != nil {
+			log.GetBaseLogger().Errorf("[HTTP] fail to move service handler %s, err %v", eventKey, err)
+		}
+		return true
+	})
+	log.GetBaseLogger().Infof("[HTTP] switch server connector protocol, useHTTP %v", useHTTP)
+}
+
+// probeLoop 定期探测gRPC端口，降级后恢复时切回gRPC，gRPC端口不可达时降级
+func (c *Connector) probeLoop() {
+	ticker := time.NewTicker(c.cfg.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			reachable := c.probeGRPC()
+			if reachable == (atomic.LoadUint32(&c.useHTTP) == 1) {
+				c.switchTo(!reachable)
+			}
+		}
+	}
+}
+
+// probeGRPC 任意一个gRPC地址可以建立TCP连接即认为可达
+func (c *Connector) probeGRPC() bool {
+	for _, address := range c.grpcAddresses {
+		conn, err := net.DialTimeout("tcp", address, c.connectTimeout)
+		if err == nil {
+			_ = conn.Close()
+			return true
+		}
+	}
+	return false
+}
+
+// toHTTPAddresses 将gRPC地址的端口替换为默认的HTTP端口
+func toHTTPAddresses(addresses []string) []string {
+	httpAddresses := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		httpAddresses = append(httpAddresses, net.JoinHostPort(host, strconv.Itoa(DefaultHTTPPort)))
+	}
+	return httpAddresses
+}
+
+// httpOperations 基于北极星HTTP接口的服务发现及注册实现
+type httpOperations struct {
+	client         *client
+	discover       *connector.BridgeDiscover
+	messageTimeout time.Duration
+}
+
+// RegisterServiceHandler 注册服务监听器
+func (h *httpOperations) RegisterServiceHandler(svcEventHandler *serverconnector.ServiceEventHandler) error {
+	return h.discover.RegisterServiceHandler(svcEventHandler)
+}
+
+// DeRegisterServiceHandler 反注册事件监听器
+func (h *httpOperations) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
+	return h.discover.DeRegisterServiceHandler(key)
+}
+
+// fetch 带上本地版本号拉取资源，未变更时服务端返回DataNoChange
+func (h *httpOperations) fetch(key *model.ServiceEventKey, revision string) (*apiservice.DiscoverResponse, error) {
+	req := &apiservice.DiscoverRequest{
+		Type: pb.GetProtoRequestType(key.Type),
+		Service: &apiservice.Service{
+			Name:      &wrappers.StringValue{Value: key.Service},
+			Namespace: &wrappers.StringValue{Value: key.Namespace},
+			Revision:  &wrappers.StringValue{Value: revision},
+		},
+	}
+	resp := &apiservice.DiscoverResponse{}
+	if err := h.client.post(pathDiscover, req, resp, 0); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeNetworkError, err, "fail to discover %s", key)
+	}
+	switch apimodel.Code(resp.GetCode().GetValue()) {
+	case apimodel.Code_ExecuteSuccess, apimodel.Code_DataNoChange, apimodel.Code_NotFoundResource:
+		return resp, nil
+	default:
+		return nil, codeError("discover", resp.GetCode().GetValue(), resp.GetInfo().GetValue())
+	}
+}
+
+// RegisterInstance 同步注册服务
+func (h *httpOperations) RegisterInstance(req *model.InstanceRegisterRequest,
+	header map[string]string) (*model.InstanceRegisterResponse, error) {
+	resp := &apiservice.Response{}
+	if err := h.client.post(pathRegisterInstance, connector.RegisterRequestToProto(req), resp,
+		h.timeout(req.Timeout)); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeNetworkError, err, "fail to registerInstance %s", req)
+	}
+	code := resp.GetCode().GetValue()
+	if code != uint32(apimodel.Code_ExecuteSuccess) && code != uint32(apimodel.Code_ExistedResource) {
+		return nil, codeError("registerInstance", code, resp.GetInfo().GetValue())
+	}
+	return &model.InstanceRegisterResponse{
+		InstanceID: resp.GetInstance().GetId().GetValue(),
+		Existed:    code == uint32(apimodel.Code_ExistedResource),
+	}, nil
+}
+
+// DeregisterInstance 同步反注册服务
+func (h *httpOperations) DeregisterInstance(req *model.InstanceDeRegisterRequest) error {
+	resp := &apiservice.Response{}
+	if err := h.client.post(pathDeregisterInstance, connector.DeregisterRequestToProto(req), resp,
+		h.timeout(req.Timeout)); err != nil {
+		return model.NewSDKError(model.ErrCodeNetworkError, err, "fail to deregisterInstance %s", req)
+	}
+	code := resp.GetCode().GetValue()
+	if code != uint32(apimodel.Code_ExecuteSuccess) && code != uint32(apimodel.Code_NotFoundResource) {
+		return codeError("deregisterInstance", code, resp.GetInfo().GetValue())
+	}
+	return nil
+}
+
+// Heartbeat 心跳上报
+func (h *httpOperations) Heartbeat(req *model.InstanceHeartbeatRequest) error {
+	resp := &apiservice.Response{}
+	if err := h.client.post(pathHeartbeat, connector.HeartbeatRequestToProto(req), resp,
+		h.timeout(req.Timeout)); err != nil {
+		return model.NewSDKError(model.ErrCodeNetworkError, err, "fail to heartbeat %s", req)
+	}
+	if code := resp.GetCode().GetValue(); code != uint32(apimodel.Code_ExecuteSuccess) {
+		return codeError("heartbeat", code, resp.GetInfo().GetValue())
+	}
+	return nil
+}
+
+// ReportClient 上报客户端信息
+func (h *httpOperations) ReportClient(req *model.ReportClientRequest) (*model.ReportClientResponse, error) {
+	resp := &apiservice.Response{}
+	if err := h.client.post(pathReportClient, connector.ReportClientRequestToProto(req), resp,
+		h.timeout(&req.Timeout)); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeNetworkError, err, "fail to reportClient")
+	}
+	code := resp.GetCode().GetValue()
+	if code != uint32(apimodel.Code_ExecuteSuccess) && code != uint32(apimodel.Code_CMDBNotFindHost) {
+		return nil, codeError("reportClient", code, resp.GetInfo().GetValue())
+	}
+	if nil != req.PersistHandler {
+		if err := req.PersistHandler(resp); err != nil {
+			log.GetBaseLogger().Errorf("fail to persist client report response, err is %v", err)
+		}
+	}
+	return &model.ReportClientResponse{
+		Mode:    model.RunMode(resp.GetClient().GetType()),
+		Version: resp.GetClient().GetVersion().GetValue(),
+		Region:  resp.GetClient().GetLocation().GetRegion().GetValue(),
+		Zone:    resp.GetClient().GetLocation().GetZone().GetValue(),
+		Campus:  resp.GetClient().GetLocation().GetCampus().GetValue(),
+	}, nil
+}
+
+// UpdateServers HTTP地址固定，无需更新
+func (h *httpOperations) UpdateServers(key *model.ServiceEventKey) error {
+	return nil
+}
+
+func (h *httpOperations) timeout(timeout *time.Duration) time.Duration {
+	if timeout != nil && *timeout > 0 {
+		return *timeout
+	}
+	return h.messageTimeout
+}
+
+// codeError 将服务端错误码转换为SDK错误
+func codeError(operation string, code uint32, info string) error {
+	if pb.ConvertServerErrorToRpcError(code) == model.ErrCodeServerError {
+		return model.NewSDKError(model.ErrCodeServerException, nil,
+			"fail to %s, server code %d, reason %s", operation, code, info)
+	}
+	return model.NewSDKError(model.ErrCodeServerUserError, nil,
+		"fail to %s, server code %d, reason %s", operation, code, info)
+}
+
+// init 注册插件信息
+func init() {
+	plugin.RegisterConfigurablePlugin(&Connector{}, &Config{})
+}
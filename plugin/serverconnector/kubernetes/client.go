@@ -0,0 +1,241 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package kubernetes
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// watch事件类型
+	eventAdded    = "ADDED"
+	eventModified = "MODIFIED"
+	eventDeleted  = "DELETED"
+	eventError    = "ERROR"
+	eventBookmark = "BOOKMARK"
+)
+
+type objectMeta struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace"`
+	ResourceVersion string            `json:"resourceVersion"`
+	Labels          map[string]string `json:"labels"`
+}
+
+type listMeta struct {
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// endpointSlice discovery.k8s.io/v1 EndpointSlice
+type endpointSlice struct {
+	Metadata    objectMeta     `json:"metadata"`
+	AddressType string         `json:"addressType"`
+	Endpoints   []endpoint     `json:"endpoints"`
+	Ports       []endpointPort `json:"ports"`
+}
+
+type endpointSliceList struct {
+	Metadata listMeta        `json:"metadata"`
+	Items    []endpointSlice `json:"items"`
+}
+
+type endpoint struct {
+	Addresses  []string `json:"addresses"`
+	Conditions struct {
+		Ready       *bool `json:"ready"`
+		Serving     *bool `json:"serving"`
+		Terminating *bool `json:"terminating"`
+	} `json:"conditions"`
+	Hostname  string `json:"hostname"`
+	NodeName  string `json:"nodeName"`
+	Zone      string `json:"zone"`
+	TargetRef *struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+		UID  string `json:"uid"`
+	} `json:"targetRef"`
+}
+
+type endpointPort struct {
+	Name        string `json:"name"`
+	Protocol    string `json:"protocol"`
+	Port        uint32 `json:"port"`
+	AppProtocol string `json:"appProtocol"`
+}
+
+type serviceList struct {
+	Items []struct {
+		Metadata objectMeta `json:"metadata"`
+	} `json:"items"`
+}
+
+type node struct {
+	Metadata objectMeta `json:"metadata"`
+}
+
+// watchEvent watch接口返回的事件，ERROR事件的Object为Status
+type watchEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// statusError ApiServer返回的错误，410表示resourceVersion过期，需要重新list
+type statusError struct {
+	code    int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("kubernetes api status %d: %s", e.code, e.message)
+}
+
+// restClient 访问Kubernetes ApiServer的REST客户端。
+// 连接器只需要对EndpointSlice做list+watch、按需查询Service及Node，因此没有引入client-go的informer：
+// client-go依赖k8s.io/apimachinery等大量模块，且要求的Go版本高于SDK go.mod声明的1.15，会传递给所有SDK使用方。
+// list+watch的语义与client-go的Reflector保持一致：从list返回的resourceVersion开始watch，
+// 正常关闭后从最新版本续watch，410时立即重新list，其他错误在重连间隔后重新list
+type restClient struct {
+	server     string
+	tokenFile  string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+func newRestClient(cfg *Config, timeout time.Duration) (*restClient, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if !cfg.InsecureSkipVerify && strings.HasPrefix(cfg.APIServer, "https://") {
+		ca, err := ioutil.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("invalid kubernetes ca file %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return &restClient{
+		server:    strings.TrimSuffix(cfg.APIServer, "/"),
+		tokenFile: cfg.TokenFile,
+		timeout:   timeout,
+		// watch为长连接，超时时间由请求的context控制
+		httpClient: &http.Client{Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}},
+	}, nil
+}
+
+// get 查询资源，结果按json解析到out中
+func (c *restClient) get(path string, query url.Values, out interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	resp, err := c.do(ctx, path, query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// watch 从resourceVersion开始监听资源变更，直到连接断开、超时或ctx结束
+func (c *restClient) watch(ctx context.Context, path string, query url.Values, timeout time.Duration,
+	handler func(event *watchEvent) error) error {
+	watchQuery := url.Values{}
+	for k, v := range query {
+		watchQuery[k] = v
+	}
+	watchQuery.Set("watch", "true")
+	watchQuery.Set("allowWatchBookmarks", "true")
+	watchQuery.Set("timeoutSeconds", strconv.Itoa(int(timeout/time.Second)))
+	ctx, cancel := context.WithTimeout(ctx, timeout+c.timeout)
+	defer cancel()
+	resp, err := c.do(ctx, path, watchQuery)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		event := &watchEvent{}
+		if err = decoder.Decode(event); err != nil {
+			// ApiServer在timeoutSeconds到期后正常关闭连接，从当前版本继续watch即可
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if event.Type == eventError {
+			st := &status{}
+			_ = json.Unmarshal(event.Object, st)
+			return &statusError{code: st.Code, message: st.Message}
+		}
+		if err = handler(event); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *restClient) do(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	reqURL := c.server + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if token, err := ioutil.ReadFile(c.tokenFile); err == nil {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		st := &status{Code: resp.StatusCode}
+		body, _ := ioutil.ReadAll(resp.Body)
+		if json.Unmarshal(body, st) != nil || st.Message == "" {
+			st.Message = string(body)
+		}
+		return nil, &statusError{code: resp.StatusCode, message: st.Message}
+	}
+	return resp, nil
+}
@@ -0,0 +1,93 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DefaultTokenFile Pod内ServiceAccount的token路径
+	DefaultTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	// DefaultCAFile Pod内ServiceAccount的CA证书路径
+	DefaultCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+	// DefaultNodeCacheTTL 节点标签的默认缓存时间
+	DefaultNodeCacheTTL = 5 * time.Minute
+	// DefaultWatchTimeout 单次watch请求的默认超时时间，超时后重新发起watch
+	DefaultWatchTimeout = 5 * time.Minute
+)
+
+// Config Kubernetes连接器的插件配置
+type Config struct {
+	// APIServer ApiServer地址，为空时使用Pod内的KUBERNETES_SERVICE_HOST及KUBERNETES_SERVICE_PORT环境变量
+	APIServer string `yaml:"apiServer" json:"apiServer"`
+	// TokenFile 访问ApiServer的token文件，每次请求时重新读取以支持token轮转
+	TokenFile string `yaml:"tokenFile" json:"tokenFile"`
+	// CAFile ApiServer的CA证书
+	CAFile string `yaml:"caFile" json:"caFile"`
+	// InsecureSkipVerify 是否跳过ApiServer证书校验
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify" json:"insecureSkipVerify"`
+	// PortName 只使用EndpointSlice中指定名称的端口，为空时每个端口都作为一个实例
+	PortName string `yaml:"portName" json:"portName"`
+	// NodeCacheTTL 节点标签（用于获取region）的缓存时间
+	NodeCacheTTL time.Duration `yaml:"nodeCacheTTL" json:"nodeCacheTTL"`
+	// WatchTimeout 单次watch请求的超时时间
+	WatchTimeout time.Duration `yaml:"watchTimeout" json:"watchTimeout"`
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	if nil == c {
+		return errors.New("kubernetes config is nil")
+	}
+	var errs error
+	if c.NodeCacheTTL <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("kubernetes.nodeCacheTTL must be greater than 0"))
+	}
+	if c.WatchTimeout < time.Second {
+		errs = multierror.Append(errs, fmt.Errorf("kubernetes.watchTimeout must be greater than 1s"))
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.APIServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host != "" && port != "" {
+			c.APIServer = "https://" + net.JoinHostPort(host, port)
+		}
+	}
+	if c.TokenFile == "" {
+		c.TokenFile = DefaultTokenFile
+	}
+	if c.CAFile == "" {
+		c.CAFile = DefaultCAFile
+	}
+	if c.NodeCacheTTL == 0 {
+		c.NodeCacheTTL = DefaultNodeCacheTTL
+	}
+	if c.WatchTimeout == 0 {
+		c.WatchTimeout = DefaultWatchTimeout
+	}
+}
@@ -0,0 +1,297 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package kubernetes
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
+)
+
+const (
+	// protocolKubernetes 插件名，global.serverConnector.protocol配置为kubernetes时启用
+	protocolKubernetes = "kubernetes"
+	// defaultInstanceWeight Pod实例的默认权重
+	defaultInstanceWeight = 100
+	// 节点的地域标签
+	labelRegion = "topology.kubernetes.io/region"
+	labelZone   = "topology.kubernetes.io/zone"
+	// 实例元数据中的Kubernetes信息
+	metadataPod      = "k8s.pod"
+	metadataNode     = "k8s.node"
+	metadataPortName = "k8s.port.name"
+)
+
+// nodeLabels 节点标签缓存，查询失败时同样缓存，避免没有节点查询权限时反复请求
+type nodeLabels struct {
+	labels map[string]string
+	expire time.Time
+}
+
+// Connector 通过list+watch Kubernetes EndpointSlice获取服务实例，北极星命名空间及服务名对应Kubernetes的命名空间及Service名，
+// 每个Pod的每个端口作为一个实例，Pod所在节点的zone及region标签转换为实例地域信息，注册、心跳等写操作不支持
+type Connector struct {
+	*plugin.PluginBase
+	cfg               *Config
+	enable            bool
+	messageTimeout    time.Duration
+	reconnectInterval time.Duration
+	client            *restClient
+	discover          *connector.BridgeDiscover
+	informerMutex     sync.Mutex
+	informers         map[model.ServiceKey]*sliceInformer
+	nodes             sync.Map
+}
+
+// Type 插件类型
+func (c *Connector) Type() common.Type {
+	return common.TypeServerConnector
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (c *Connector) Name() string {
+	return protocolKubernetes
+}
+
+// Init 初始化插件
+func (c *Connector) Init(ctx *plugin.InitContext) error {
+	c.PluginBase = plugin.NewPluginBase(ctx)
+	connectorCfg := ctx.Config.GetGlobal().GetServerConnector()
+	c.enable = connectorCfg.GetProtocol() == c.Name()
+	if !c.enable {
+		return nil
+	}
+	c.cfg = connectorCfg.GetPluginConfig(c.Name()).(*Config)
+	if c.cfg.APIServer == "" {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, nil,
+			"kubernetes.apiServer is empty and the sdk is not running in a kubernetes pod")
+	}
+	c.messageTimeout = connectorCfg.GetMessageTimeout()
+	c.reconnectInterval = connectorCfg.GetReconnectInterval()
+	client, err := newRestClient(c.cfg, c.messageTimeout)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to create kubernetes client")
+	}
+	c.client = client
+	c.informers = make(map[model.ServiceKey]*sliceInformer)
+	c.discover = connector.NewBridgeDiscover(c.Name(), c.fetch)
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (c *Connector) Destroy() error {
+	if !c.enable {
+		return nil
+	}
+	c.discover.Destroy()
+	c.informerMutex.Lock()
+	defer c.informerMutex.Unlock()
+	for svcKey, informer := range c.informers {
+		informer.stop()
+		delete(c.informers, svcKey)
+	}
+	return nil
+}
+
+// IsEnable 插件开关
+func (c *Connector) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// RegisterServiceHandler 注册服务监听器，实例类型的监听会启动对应Service的EndpointSlice watch
+func (c *Connector) RegisterServiceHandler(svcEventHandler *serverconnector.ServiceEventHandler) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	if svcEventHandler.Type == model.EventInstances {
+		c.startInformer(svcEventHandler.ServiceKey)
+	}
+	return c.discover.RegisterServiceHandler(svcEventHandler)
+}
+
+// DeRegisterServiceHandler 反注册事件监听器
+func (c *Connector) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	if key.Type == model.EventInstances {
+		c.informerMutex.Lock()
+		if informer, ok := c.informers[key.ServiceKey]; ok {
+			informer.stop()
+			delete(c.informers, key.ServiceKey)
+		}
+		c.informerMutex.Unlock()
+	}
+	return c.discover.DeRegisterServiceHandler(key)
+}
+
+// RegisterInstance Pod实例由Kubernetes管理，不支持注册
+func (c *Connector) RegisterInstance(req *model.InstanceRegisterRequest,
+	header map[string]string) (*model.InstanceRegisterResponse, error) {
+	return nil, c.notSupported("RegisterInstance")
+}
+
+// DeregisterInstance Pod实例由Kubernetes管理，不支持反注册
+func (c *Connector) DeregisterInstance(instance *model.InstanceDeRegisterRequest) error {
+	return c.notSupported("DeregisterInstance")
+}
+
+// Heartbeat Pod健康状态由readiness探针决定，不支持心跳上报
+func (c *Connector) Heartbeat(instance *model.InstanceHeartbeatRequest) error {
+	return c.notSupported("Heartbeat")
+}
+
+// ReportClient 直接返回空应答
+func (c *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportClientResponse, error) {
+	return &model.ReportClientResponse{}, nil
+}
+
+// UpdateServers ApiServer地址固定，无需更新
+func (c *Connector) UpdateServers(key *model.ServiceEventKey) error {
+	return nil
+}
+
+func (c *Connector) notSupported(operation string) error {
+	return model.NewSDKError(model.ErrCodePluginError, nil,
+		"%s is not supported by %s server connector", operation, c.Name())
+}
+
+func (c *Connector) notEnabled() error {
+	return model.NewSDKError(model.ErrCodePluginError, nil,
+		"%s server connector is not enabled", c.Name())
+}
+
+// startInformer 启动服务的informer，已存在时忽略
+func (c *Connector) startInformer(svcKey model.ServiceKey) {
+	c.informerMutex.Lock()
+	defer c.informerMutex.Unlock()
+	if _, ok := c.informers[svcKey]; ok {
+		return
+	}
+	informer := newSliceInformer(svcKey, c.client, c.cfg.WatchTimeout, c.reconnectInterval, c.discover.NotifyService)
+	c.informers[svcKey] = informer
+	go informer.run()
+}
+
+// fetch 实例从informer缓存中读取，服务列表直接查询ApiServer，Kubernetes没有北极星的治理规则，规则返回空应答
+func (c *Connector) fetch(key *model.ServiceEventKey) (*apiservice.DiscoverResponse, error) {
+	switch key.Type {
+	case model.EventInstances:
+		c.informerMutex.Lock()
+		informer, ok := c.informers[key.ServiceKey]
+		c.informerMutex.Unlock()
+		if !ok {
+			return nil, model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+				"endpointslices of %s is not watched", key.ServiceKey)
+		}
+		slices, ok := informer.getSlices(c.messageTimeout)
+		if !ok {
+			return nil, model.NewSDKError(model.ErrCodeAPITimeoutError, nil,
+				"wait endpointslices of %s timeout after %v", key.ServiceKey, c.messageTimeout)
+		}
+		return connector.NewBridgeInstancesResponse(key, c.toBridgeInstances(slices)), nil
+	case model.EventServices:
+		list := &serviceList{}
+		if err := c.client.get("/api/v1/namespaces/"+url.PathEscape(key.Namespace)+"/services", nil, list); err != nil {
+			return nil, err
+		}
+		services := make([]model.ServiceKey, 0, len(list.Items))
+		for _, item := range list.Items {
+			services = append(services, model.ServiceKey{Namespace: key.Namespace, Service: item.Metadata.Name})
+		}
+		return connector.NewBridgeServicesResponse(key, services), nil
+	default:
+		return nil, nil
+	}
+}
+
+// toBridgeInstances 将EndpointSlice中的每个地址及端口转换为一个实例，ready为空时视为就绪
+func (c *Connector) toBridgeInstances(slices []*endpointSlice) []connector.BridgeInstance {
+	instances := make([]connector.BridgeInstance, 0)
+	for _, slice := range slices {
+		for _, ep := range slice.Endpoints {
+			healthy := ep.Conditions.Ready == nil || *ep.Conditions.Ready
+			terminating := ep.Conditions.Terminating != nil && *ep.Conditions.Terminating
+			labels := c.getNodeLabels(ep.NodeName)
+			zone := ep.Zone
+			if zone == "" {
+				zone = labels[labelZone]
+			}
+			for _, address := range ep.Addresses {
+				for _, port := range slice.Ports {
+					if c.cfg.PortName != "" && port.Name != c.cfg.PortName {
+						continue
+					}
+					metadata := map[string]string{metadataNode: ep.NodeName}
+					if ep.TargetRef != nil {
+						metadata[metadataPod] = ep.TargetRef.Name
+					}
+					if port.Name != "" {
+						metadata[metadataPortName] = port.Name
+					}
+					protocol := port.AppProtocol
+					if protocol == "" {
+						protocol = port.Protocol
+					}
+					instances = append(instances, connector.BridgeInstance{
+						Host:     address,
+						Port:     port.Port,
+						Protocol: protocol,
+						Weight:   defaultInstanceWeight,
+						Healthy:  healthy,
+						Isolated: terminating,
+						Metadata: metadata,
+						Region:   labels[labelRegion],
+						Zone:     zone,
+					})
+				}
+			}
+		}
+	}
+	return instances
+}
+
+// getNodeLabels 获取节点标签，带缓存
+func (c *Connector) getNodeLabels(nodeName string) map[string]string {
+	if nodeName == "" {
+		return nil
+	}
+	if value, ok := c.nodes.Load(nodeName); ok && time.Now().Before(value.(*nodeLabels).expire) {
+		return value.(*nodeLabels).labels
+	}
+	n := &node{}
+	if err := c.client.get("/api/v1/nodes/"+url.PathEscape(nodeName), nil, n); err != nil {
+		log.GetBaseLogger().Debugf("[Kubernetes] fail to get node %s, err %v", nodeName, err)
+	}
+	c.nodes.Store(nodeName, &nodeLabels{labels: n.Metadata.Labels, expire: time.Now().Add(c.cfg.NodeCacheTTL)})
+	return n.Metadata.Labels
+}
+
+// init 注册插件信息
+func init() {
+	plugin.RegisterConfigurablePlugin(&Connector{}, &Config{})
+}
@@ -0,0 +1,184 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// labelServiceName EndpointSlice所属Service的标签
+	labelServiceName = "kubernetes.io/service-name"
+)
+
+// sliceInformer 以list+watch的方式维护单个Service的EndpointSlice缓存，变更时回调onChange
+type sliceInformer struct {
+	svcKey            model.ServiceKey
+	client            *restClient
+	watchTimeout      time.Duration
+	reconnectInterval time.Duration
+	onChange          func(svcKey model.ServiceKey)
+
+	mutex  sync.RWMutex
+	slices map[string]*endpointSlice
+	synced chan struct{}
+	once   sync.Once
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newSliceInformer(svcKey model.ServiceKey, client *restClient, watchTimeout time.Duration,
+	reconnectInterval time.Duration, onChange func(svcKey model.ServiceKey)) *sliceInformer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &sliceInformer{
+		svcKey:            svcKey,
+		client:            client,
+		watchTimeout:      watchTimeout,
+		reconnectInterval: reconnectInterval,
+		onChange:          onChange,
+		slices:            make(map[string]*endpointSlice),
+		synced:            make(chan struct{}),
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+}
+
+func (s *sliceInformer) path() string {
+	return "/apis/discovery.k8s.io/v1/namespaces/" + url.PathEscape(s.svcKey.Namespace) + "/endpointslices"
+}
+
+func (s *sliceInformer) query() url.Values {
+	query := url.Values{}
+	query.Set("labelSelector", labelServiceName+"="+s.svcKey.Service)
+	return query
+}
+
+// run list后从返回的resourceVersion开始watch，watch结束或resourceVersion过期时重新list
+func (s *sliceInformer) run() {
+	resourceVersion := ""
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		default:
+		}
+		if resourceVersion == "" {
+			var err error
+			if resourceVersion, err = s.list(); err != nil {
+				log.GetNetworkLogger().Errorf("[Kubernetes] fail to list endpointslices of %s, err %v", s.svcKey, err)
+				s.sleep()
+				continue
+			}
+		}
+		err := s.client.watch(s.ctx, s.path(), s.withResourceVersion(resourceVersion), s.watchTimeout,
+			func(event *watchEvent) error {
+				resourceVersion = s.handle(event, resourceVersion)
+				return nil
+			})
+		if err == nil {
+			continue
+		}
+		if statusErr, ok := err.(*statusError); ok && statusErr.code == http.StatusGone {
+			log.GetBaseLogger().Infof("[Kubernetes] resource version of %s expired, relist", s.svcKey)
+		} else {
+			log.GetNetworkLogger().Errorf("[Kubernetes] fail to watch endpointslices of %s, err %v", s.svcKey, err)
+			s.sleep()
+		}
+		resourceVersion = ""
+	}
+}
+
+func (s *sliceInformer) withResourceVersion(resourceVersion string) url.Values {
+	query := s.query()
+	query.Set("resourceVersion", resourceVersion)
+	return query
+}
+
+func (s *sliceInformer) list() (string, error) {
+	list := &endpointSliceList{}
+	if err := s.client.get(s.path(), s.query(), list); err != nil {
+		return "", err
+	}
+	slices := make(map[string]*endpointSlice, len(list.Items))
+	for i := range list.Items {
+		slices[list.Items[i].Metadata.Name] = &list.Items[i]
+	}
+	s.mutex.Lock()
+	s.slices = slices
+	s.mutex.Unlock()
+	s.once.Do(func() {
+		close(s.synced)
+	})
+	s.onChange(s.svcKey)
+	return list.Metadata.ResourceVersion, nil
+}
+
+// handle 处理watch事件，返回最新的resourceVersion
+func (s *sliceInformer) handle(event *watchEvent, resourceVersion string) string {
+	slice := &endpointSlice{}
+	if err := json.Unmarshal(event.Object, slice); err != nil {
+		log.GetBaseLogger().Errorf("[Kubernetes] fail to unmarshal %s event of %s, err %v", event.Type, s.svcKey, err)
+		return resourceVersion
+	}
+	s.mutex.Lock()
+	switch event.Type {
+	case eventAdded, eventModified:
+		s.slices[slice.Metadata.Name] = slice
+	case eventDeleted:
+		delete(s.slices, slice.Metadata.Name)
+	}
+	s.mutex.Unlock()
+	if event.Type != eventBookmark {
+		s.onChange(s.svcKey)
+	}
+	return slice.Metadata.ResourceVersion
+}
+
+// getSlices 等待首次list完成后返回EndpointSlice快照
+func (s *sliceInformer) getSlices(timeout time.Duration) ([]*endpointSlice, bool) {
+	select {
+	case <-s.synced:
+	case <-time.After(timeout):
+		return nil, false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	slices := make([]*endpointSlice, 0, len(s.slices))
+	for _, slice := range s.slices {
+		slices = append(slices, slice)
+	}
+	return slices, true
+}
+
+func (s *sliceInformer) sleep() {
+	select {
+	case <-s.ctx.Done():
+	case <-time.After(s.reconnectInterval):
+	}
+}
+
+func (s *sliceInformer) stop() {
+	s.cancel()
+}
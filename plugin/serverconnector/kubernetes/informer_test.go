@@ -0,0 +1,417 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package kubernetes
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+var testSvcKey = model.ServiceKey{Namespace: "ns", Service: "svc"}
+
+// watchScript 单次watch请求的应答脚本
+type watchScript struct {
+	// status 非0时直接返回该HTTP状态码
+	status int
+	// events 依次写入的watch事件，写完后关闭连接
+	events []string
+	// gate 不为空时，等待gate关闭后再应答
+	gate chan struct{}
+}
+
+// recordedRequest ApiServer收到的请求
+type recordedRequest struct {
+	watch           bool
+	resourceVersion string
+	auth            string
+}
+
+// fakeAPIServer 按脚本回放list及watch应答的ApiServer
+// list脚本用完后重复最后一个应答，watch脚本用完后保持连接直到请求结束
+type fakeAPIServer struct {
+	t        *testing.T
+	server   *httptest.Server
+	mutex    sync.Mutex
+	lists    []string
+	watches  []watchScript
+	listGate chan struct{}
+	requests chan recordedRequest
+}
+
+func newFakeAPIServer(t *testing.T, lists []string, watches []watchScript) *fakeAPIServer {
+	f := &fakeAPIServer{
+		t:        t,
+		lists:    lists,
+		watches:  watches,
+		requests: make(chan recordedRequest, 100),
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	return f
+}
+
+func (f *fakeAPIServer) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/apis/discovery.k8s.io/v1/namespaces/ns/endpointslices" {
+		f.t.Errorf("unexpected path %s", r.URL.Path)
+	}
+	query := r.URL.Query()
+	if selector := query.Get("labelSelector"); selector != labelServiceName+"=svc" {
+		f.t.Errorf("unexpected labelSelector %s", selector)
+	}
+	isWatch := query.Get("watch") == "true"
+	f.requests <- recordedRequest{
+		watch:           isWatch,
+		resourceVersion: query.Get("resourceVersion"),
+		auth:            r.Header.Get("Authorization"),
+	}
+	if !isWatch {
+		f.serveList(w, r)
+		return
+	}
+	f.mutex.Lock()
+	if len(f.watches) == 0 {
+		f.mutex.Unlock()
+		<-r.Context().Done()
+		return
+	}
+	script := f.watches[0]
+	f.watches = f.watches[1:]
+	f.mutex.Unlock()
+	if script.gate != nil {
+		select {
+		case <-script.gate:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if script.status != 0 {
+		w.WriteHeader(script.status)
+		fmt.Fprintf(w, `{"kind":"Status","code":%d,"message":"scripted status"}`, script.status)
+		return
+	}
+	for _, event := range script.events {
+		fmt.Fprintln(w, event)
+		w.(http.Flusher).Flush()
+	}
+}
+
+func (f *fakeAPIServer) serveList(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	gate := f.listGate
+	body := f.lists[0]
+	if len(f.lists) > 1 {
+		f.lists = f.lists[1:]
+	}
+	f.mutex.Unlock()
+	if gate != nil {
+		select {
+		case <-gate:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	fmt.Fprint(w, body)
+}
+
+// nextRequest 等待ApiServer收到下一个请求
+func (f *fakeAPIServer) nextRequest() recordedRequest {
+	select {
+	case req := <-f.requests:
+		return req
+	case <-time.After(5 * time.Second):
+		f.t.Fatal("timeout waiting for request")
+		return recordedRequest{}
+	}
+}
+
+func (f *fakeAPIServer) close() {
+	f.server.Close()
+}
+
+// startInformer 启动连接到fakeAPIServer的informer，返回onChange的回调通道
+func startInformer(t *testing.T, f *fakeAPIServer, tokenFile string,
+	reconnectInterval time.Duration) (*sliceInformer, chan model.ServiceKey) {
+	client := &restClient{
+		server:     f.server.URL,
+		tokenFile:  tokenFile,
+		timeout:    time.Second,
+		httpClient: f.server.Client(),
+	}
+	changes := make(chan model.ServiceKey, 100)
+	informer := newSliceInformer(testSvcKey, client, time.Minute, reconnectInterval,
+		func(svcKey model.ServiceKey) {
+			changes <- svcKey
+		})
+	go informer.run()
+	return informer, changes
+}
+
+func sliceList(resourceVersion string, names ...string) string {
+	items := ""
+	for i, name := range names {
+		if i > 0 {
+			items += ","
+		}
+		items += fmt.Sprintf(`{"metadata":{"name":%q,"resourceVersion":%q}}`, name, resourceVersion)
+	}
+	return fmt.Sprintf(`{"metadata":{"resourceVersion":%q},"items":[%s]}`, resourceVersion, items)
+}
+
+func sliceEvent(eventType string, name string, resourceVersion string) string {
+	return fmt.Sprintf(`{"type":%q,"object":{"metadata":{"name":%q,"resourceVersion":%q}}}`,
+		eventType, name, resourceVersion)
+}
+
+func errorEvent(code int) string {
+	return fmt.Sprintf(`{"type":"ERROR","object":{"kind":"Status","code":%d,"message":"scripted error"}}`, code)
+}
+
+func sliceNames(t *testing.T, informer *sliceInformer) []string {
+	slices, ok := informer.getSlices(time.Second)
+	if !ok {
+		t.Fatal("informer not synced")
+	}
+	names := make([]string, 0, len(slices))
+	for _, slice := range slices {
+		names = append(names, slice.Metadata.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSliceInformerListWatch(t *testing.T) {
+	tests := []struct {
+		name              string
+		lists             []string
+		watches           []watchScript
+		reconnectInterval time.Duration
+		wantRequests      []recordedRequest
+		wantSlices        []string
+		wantChanges       int
+	}{
+		{
+			name:  "从list返回的版本开始watch并处理增删改事件，连接正常关闭后从最新版本继续watch",
+			lists: []string{sliceList("10", "a")},
+			watches: []watchScript{{events: []string{
+				sliceEvent(eventAdded, "b", "11"),
+				sliceEvent(eventModified, "a", "12"),
+				sliceEvent(eventDeleted, "b", "13"),
+			}}},
+			reconnectInterval: time.Hour,
+			wantRequests: []recordedRequest{
+				{watch: false},
+				{watch: true, resourceVersion: "10"},
+				{watch: true, resourceVersion: "13"},
+			},
+			wantSlices:  []string{"a"},
+			wantChanges: 4,
+		},
+		{
+			name:              "watch返回410时立即重新list",
+			lists:             []string{sliceList("10", "a"), sliceList("20", "b")},
+			watches:           []watchScript{{status: http.StatusGone}},
+			reconnectInterval: time.Hour,
+			wantRequests: []recordedRequest{
+				{watch: false},
+				{watch: true, resourceVersion: "10"},
+				{watch: false},
+				{watch: true, resourceVersion: "20"},
+			},
+			wantSlices:  []string{"b"},
+			wantChanges: 2,
+		},
+		{
+			name:              "watch流中的410 ERROR事件立即重新list",
+			lists:             []string{sliceList("10", "a"), sliceList("20", "b")},
+			watches:           []watchScript{{events: []string{errorEvent(http.StatusGone)}}},
+			reconnectInterval: time.Hour,
+			wantRequests: []recordedRequest{
+				{watch: false},
+				{watch: true, resourceVersion: "10"},
+				{watch: false},
+				{watch: true, resourceVersion: "20"},
+			},
+			wantSlices:  []string{"b"},
+			wantChanges: 2,
+		},
+		{
+			name:  "其他ERROR事件在重连间隔后重新list",
+			lists: []string{sliceList("10", "a"), sliceList("20", "a", "b")},
+			watches: []watchScript{{events: []string{
+				sliceEvent(eventAdded, "c", "11"),
+				errorEvent(http.StatusInternalServerError),
+			}}},
+			reconnectInterval: 10 * time.Millisecond,
+			wantRequests: []recordedRequest{
+				{watch: false},
+				{watch: true, resourceVersion: "10"},
+				{watch: false},
+				{watch: true, resourceVersion: "20"},
+			},
+			wantSlices:  []string{"a", "b"},
+			wantChanges: 3,
+		},
+		{
+			name:              "watch返回非410的错误码时在重连间隔后重新list",
+			lists:             []string{sliceList("10", "a"), sliceList("20", "b")},
+			watches:           []watchScript{{status: http.StatusInternalServerError}},
+			reconnectInterval: 10 * time.Millisecond,
+			wantRequests: []recordedRequest{
+				{watch: false},
+				{watch: true, resourceVersion: "10"},
+				{watch: false},
+				{watch: true, resourceVersion: "20"},
+			},
+			wantSlices:  []string{"b"},
+			wantChanges: 2,
+		},
+		{
+			name:  "watch连接在事件中途断开时丢弃残缺事件并重新list",
+			lists: []string{sliceList("10", "a"), sliceList("20", "a")},
+			watches: []watchScript{{events: []string{
+				sliceEvent(eventAdded, "b", "11"),
+				`{"type":"ADDED","object":{"metadata":{"name":"c"`,
+			}}},
+			reconnectInterval: 10 * time.Millisecond,
+			wantRequests: []recordedRequest{
+				{watch: false},
+				{watch: true, resourceVersion: "10"},
+				{watch: false},
+				{watch: true, resourceVersion: "20"},
+			},
+			wantSlices:  []string{"a"},
+			wantChanges: 3,
+		},
+		{
+			name:              "list失败时在重连间隔后重试",
+			lists:             []string{`{"metadata":`, sliceList("10", "a")},
+			reconnectInterval: 10 * time.Millisecond,
+			wantRequests: []recordedRequest{
+				{watch: false},
+				{watch: false},
+				{watch: true, resourceVersion: "10"},
+			},
+			wantSlices:  []string{"a"},
+			wantChanges: 1,
+		},
+		{
+			name:  "BOOKMARK事件只推进版本，不触发变更",
+			lists: []string{sliceList("10", "a")},
+			watches: []watchScript{{events: []string{
+				`{"type":"BOOKMARK","object":{"metadata":{"resourceVersion":"15"}}}`,
+			}}},
+			reconnectInterval: time.Hour,
+			wantRequests: []recordedRequest{
+				{watch: false},
+				{watch: true, resourceVersion: "10"},
+				{watch: true, resourceVersion: "15"},
+			},
+			wantSlices:  []string{"a"},
+			wantChanges: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeAPIServer(t, tt.lists, tt.watches)
+			defer f.close()
+			informer, changes := startInformer(t, f, filepath.Join(t.TempDir(), "token"), tt.reconnectInterval)
+			defer informer.stop()
+			for i, want := range tt.wantRequests {
+				got := f.nextRequest()
+				got.auth = ""
+				if got != want {
+					t.Fatalf("request %d = %+v, want %+v", i, got, want)
+				}
+			}
+			if got := sliceNames(t, informer); !reflect.DeepEqual(got, tt.wantSlices) {
+				t.Errorf("slices = %v, want %v", got, tt.wantSlices)
+			}
+			if got := len(changes); got != tt.wantChanges {
+				t.Errorf("onChange called %d times, want %d", got, tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestSliceInformerWaitFirstSync(t *testing.T) {
+	f := newFakeAPIServer(t, []string{sliceList("10", "a")}, nil)
+	defer f.close()
+	f.listGate = make(chan struct{})
+	informer, _ := startInformer(t, f, filepath.Join(t.TempDir(), "token"), time.Hour)
+	defer informer.stop()
+
+	f.nextRequest()
+	if _, ok := informer.getSlices(50 * time.Millisecond); ok {
+		t.Fatal("getSlices should time out before the first list completes")
+	}
+	close(f.listGate)
+	if got := sliceNames(t, informer); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("slices = %v, want [a]", got)
+	}
+}
+
+func TestSliceInformerTokenRotation(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("token-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	gate := make(chan struct{})
+	f := newFakeAPIServer(t, []string{sliceList("10", "a")},
+		[]watchScript{{status: http.StatusGone, gate: gate}})
+	defer f.close()
+	informer, _ := startInformer(t, f, tokenFile, time.Hour)
+	defer informer.stop()
+
+	for _, want := range []recordedRequest{
+		{watch: false, auth: "Bearer token-1"},
+		{watch: true, resourceVersion: "10", auth: "Bearer token-1"},
+	} {
+		if got := f.nextRequest(); got != want {
+			t.Fatalf("request = %+v, want %+v", got, want)
+		}
+	}
+	// 模拟kubelet轮换projected token，后续请求需要使用新的token
+	if err := ioutil.WriteFile(tokenFile, []byte("token-2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	close(gate)
+	for _, want := range []recordedRequest{
+		{watch: false, auth: "Bearer token-2"},
+		{watch: true, resourceVersion: "10", auth: "Bearer token-2"},
+	} {
+		if got := f.nextRequest(); got != want {
+			t.Fatalf("request = %+v, want %+v", got, want)
+		}
+	}
+}
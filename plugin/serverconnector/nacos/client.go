@@ -0,0 +1,253 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package nacos
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultContextPath Nacos服务端默认的上下文路径
+	DefaultContextPath = "/nacos"
+	// DefaultGroup Nacos默认分组
+	DefaultGroup = "DEFAULT_GROUP"
+	// DefaultCluster Nacos默认集群
+	DefaultCluster = "DEFAULT"
+	// PolarisDefaultNamespace 北极星默认命名空间，对应Nacos的public命名空间
+	PolarisDefaultNamespace = "default"
+	// groupServiceSeparator Nacos分组与服务名的分隔符
+	groupServiceSeparator = "@@"
+	// tokenRefreshAhead 提前刷新accessToken的时间
+	tokenRefreshAhead = 10 * time.Second
+)
+
+// ClientConfig Nacos Open API客户端配置，服务发现及配置中心连接器共用
+type ClientConfig struct {
+	// Scheme 访问协议，http或https
+	Scheme string `yaml:"scheme" json:"scheme"`
+	// ContextPath 服务端上下文路径
+	ContextPath string `yaml:"contextPath" json:"contextPath"`
+	// Username 开启鉴权时的用户名
+	Username string `yaml:"username" json:"username"`
+	// Password 开启鉴权时的密码
+	Password string `yaml:"password" json:"password"`
+}
+
+// Verify 校验配置
+func (c *ClientConfig) Verify() error {
+	if nil == c {
+		return fmt.Errorf("nacos config is nil")
+	}
+	if c.Scheme != "http" && c.Scheme != "https" {
+		return fmt.Errorf("nacos.scheme must be http or https, got %s", c.Scheme)
+	}
+	if c.Username != "" && c.Password == "" {
+		return fmt.Errorf("nacos.password can not be empty when username is set")
+	}
+	return nil
+}
+
+// SetDefault 设置默认值
+func (c *ClientConfig) SetDefault() {
+	if c.Scheme == "" {
+		c.Scheme = "http"
+	}
+	if c.ContextPath == "" {
+		c.ContextPath = DefaultContextPath
+	}
+}
+
+// ToNamespaceID 北极星命名空间转换为Nacos命名空间ID，default对应public
+func ToNamespaceID(namespace string) string {
+	if namespace == PolarisDefaultNamespace {
+		return ""
+	}
+	return namespace
+}
+
+// SplitServiceName 北极星服务名转换为Nacos的分组及服务名，服务名形如group@@service时拆分出分组，否则为默认分组
+func SplitServiceName(service string) (group string, name string) {
+	if idx := strings.Index(service, groupServiceSeparator); idx > 0 {
+		return service[:idx], service[idx+len(groupServiceSeparator):]
+	}
+	return DefaultGroup, service
+}
+
+// JoinServiceName Nacos的分组及服务名转换为北极星服务名，默认分组省略
+func JoinServiceName(group string, name string) string {
+	if group == "" || group == DefaultGroup {
+		return name
+	}
+	return group + groupServiceSeparator + name
+}
+
+// Client Nacos Open API客户端，请求失败时切换到下一个地址
+type Client struct {
+	cfg         *ClientConfig
+	addresses   []string
+	index       uint32
+	httpClient  *http.Client
+	tokenMutex  sync.Mutex
+	token       string
+	tokenExpire time.Time
+}
+
+// NewClient 创建Nacos客户端，timeout为单次请求的超时时间
+func NewClient(cfg *ClientConfig, addresses []string, timeout time.Duration) *Client {
+	return &Client{
+		cfg:        cfg,
+		addresses:  addresses,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Request Nacos Open API请求，GET及DELETE请求参数放在query中，其余放在form表单中
+type Request struct {
+	Method string
+	Path   string
+	Params url.Values
+	Header http.Header
+	// Timeout 单次请求超时时间，为0时使用客户端默认超时
+	Timeout time.Duration
+}
+
+// Response Nacos Open API应答
+type Response struct {
+	// Found 资源是否存在，服务端返回404时为false
+	Found  bool
+	Body   []byte
+	Header http.Header
+}
+
+// Do 发送请求，out不为nil时将应答按json解析到out中，请求失败时切换到下一个地址重试
+func (c *Client) Do(req *Request, out interface{}) (*Response, error) {
+	if len(c.addresses) == 0 {
+		return nil, fmt.Errorf("nacos addresses is empty")
+	}
+	var lastErr error
+	for i := 0; i < len(c.addresses); i++ {
+		idx := atomic.LoadUint32(&c.index)
+		address := c.addresses[int(idx)%len(c.addresses)]
+		resp, err := c.doOnce(address, req, out)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+		atomic.CompareAndSwapUint32(&c.index, idx, idx+1)
+	}
+	return nil, lastErr
+}
+
+func (c *Client) doOnce(address string, req *Request, out interface{}) (*Response, error) {
+	query := url.Values{}
+	token, err := c.accessToken(address)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		query.Set("accessToken", token)
+	}
+	var reqBody string
+	if req.Method == http.MethodGet || req.Method == http.MethodDelete {
+		for k, v := range req.Params {
+			query[k] = v
+		}
+	} else {
+		reqBody = req.Params.Encode()
+	}
+	reqURL := url.URL{Scheme: c.cfg.Scheme, Host: address, Path: c.cfg.ContextPath + req.Path, RawQuery: query.Encode()}
+	httpReq, err := http.NewRequest(req.Method, reqURL.String(), strings.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range req.Header {
+		httpReq.Header[k] = v
+	}
+	if reqBody != "" {
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	httpClient := c.httpClient
+	if req.Timeout > 0 {
+		httpClient = &http.Client{Timeout: req.Timeout}
+	}
+	httpResp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp := &Response{Body: body, Header: httpResp.Header}
+	switch httpResp.StatusCode {
+	case http.StatusOK:
+		resp.Found = true
+		if out != nil {
+			if err = json.Unmarshal(body, out); err != nil {
+				return nil, err
+			}
+		}
+		return resp, nil
+	case http.StatusNotFound:
+		return resp, nil
+	default:
+		return nil, fmt.Errorf("%s %s, status %d, body %s", req.Method, req.Path, httpResp.StatusCode, string(body))
+	}
+}
+
+// accessToken 开启鉴权时登录获取accessToken，过期前复用
+func (c *Client) accessToken(address string) (string, error) {
+	if c.cfg.Username == "" {
+		return "", nil
+	}
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	if c.token != "" && time.Now().Before(c.tokenExpire) {
+		return c.token, nil
+	}
+	form := url.Values{}
+	form.Set("username", c.cfg.Username)
+	form.Set("password", c.cfg.Password)
+	loginURL := url.URL{Scheme: c.cfg.Scheme, Host: address, Path: c.cfg.ContextPath + "/v1/auth/login"}
+	resp, err := c.httpClient.PostForm(loginURL.String(), form)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("nacos login fail, status %d", resp.StatusCode)
+	}
+	login := &struct {
+		AccessToken string `json:"accessToken"`
+		TokenTTL    int64  `json:"tokenTtl"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(login); err != nil {
+		return "", err
+	}
+	c.token = login.AccessToken
+	c.tokenExpire = time.Now().Add(time.Duration(login.TokenTTL)*time.Second - tokenRefreshAhead)
+	return c.token, nil
+}
@@ -0,0 +1,341 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package nacos
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
+)
+
+const (
+	// protocolNacos 插件名，global.serverConnector.protocol配置为nacos时启用
+	protocolNacos = "nacos"
+	// weightScale Nacos权重为浮点数，默认1.0，按100倍转换为北极星权重
+	weightScale = 100
+	// servicePageSize 查询服务列表的分页大小
+	servicePageSize = 1000
+)
+
+// instanceListResponse /v1/ns/instance/list 应答
+type instanceListResponse struct {
+	Hosts []nacosInstance `json:"hosts"`
+}
+
+type nacosInstance struct {
+	InstanceID  string            `json:"instanceId"`
+	IP          string            `json:"ip"`
+	Port        uint32            `json:"port"`
+	Weight      float64           `json:"weight"`
+	Healthy     bool              `json:"healthy"`
+	Enabled     bool              `json:"enabled"`
+	ClusterName string            `json:"clusterName"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+// serviceListResponse /v1/ns/service/list 应答
+type serviceListResponse struct {
+	Count int      `json:"count"`
+	Doms  []string `json:"doms"`
+}
+
+// registration 已注册的实例，心跳时需要带上注册时的集群及元数据
+type registration struct {
+	cluster   string
+	weight    float64
+	metadata  map[string]string
+	ephemeral bool
+}
+
+// Connector 通过Nacos Open API对接Nacos注册中心，北极星命名空间对应Nacos命名空间（default对应public），
+// 北极星服务名对应Nacos的group@@service（默认分组时省略分组），Nacos集群对应实例的campus
+type Connector struct {
+	*plugin.PluginBase
+	enable         bool
+	messageTimeout time.Duration
+	client         *Client
+	discover       *connector.BridgeDiscover
+	registrations  sync.Map
+}
+
+// Type 插件类型
+func (c *Connector) Type() common.Type {
+	return common.TypeServerConnector
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (c *Connector) Name() string {
+	return protocolNacos
+}
+
+// Init 初始化插件
+func (c *Connector) Init(ctx *plugin.InitContext) error {
+	c.PluginBase = plugin.NewPluginBase(ctx)
+	connectorCfg := ctx.Config.GetGlobal().GetServerConnector()
+	c.enable = connectorCfg.GetProtocol() == c.Name()
+	if !c.enable {
+		return nil
+	}
+	cfg := connectorCfg.GetPluginConfig(c.Name()).(*ClientConfig)
+	c.messageTimeout = connectorCfg.GetMessageTimeout()
+	c.client = NewClient(cfg, connectorCfg.GetAddresses(), c.messageTimeout)
+	c.discover = connector.NewBridgeDiscover(c.Name(), c.fetch)
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (c *Connector) Destroy() error {
+	if c.enable {
+		c.discover.Destroy()
+	}
+	return nil
+}
+
+// IsEnable 插件开关
+func (c *Connector) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// RegisterServiceHandler 注册服务监听器
+func (c *Connector) RegisterServiceHandler(svcEventHandler *serverconnector.ServiceEventHandler) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	return c.discover.RegisterServiceHandler(svcEventHandler)
+}
+
+// DeRegisterServiceHandler 反注册事件监听器
+func (c *Connector) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	return c.discover.DeRegisterServiceHandler(key)
+}
+
+// RegisterInstance 注册实例，设置了TTL的实例注册为临时实例，需要通过Heartbeat续约，否则注册为持久化实例
+func (c *Connector) RegisterInstance(req *model.InstanceRegisterRequest,
+	header map[string]string) (*model.InstanceRegisterResponse, error) {
+	if !c.enable {
+		return nil, c.notEnabled()
+	}
+	group, service := SplitServiceName(req.Service)
+	reg := &registration{
+		cluster:   DefaultCluster,
+		weight:    1,
+		metadata:  req.Metadata,
+		ephemeral: req.TTL != nil,
+	}
+	if req.Location != nil && req.Location.Campus != "" {
+		reg.cluster = req.Location.Campus
+	}
+	if req.Weight != nil {
+		reg.weight = float64(*req.Weight) / weightScale
+	}
+	metadata, err := json.Marshal(reg.metadata)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "invalid metadata of %s", req.Service)
+	}
+	params := c.instanceParams(req.Namespace, group, service, req.Host, req.Port, reg.cluster, reg.ephemeral)
+	params.Set("weight", strconv.FormatFloat(reg.weight, 'f', -1, 64))
+	params.Set("metadata", string(metadata))
+	params.Set("enabled", strconv.FormatBool(req.Isolate == nil || !*req.Isolate))
+	params.Set("healthy", strconv.FormatBool(req.Healthy == nil || *req.Healthy))
+	if _, err = c.client.Do(&Request{
+		Method: http.MethodPost, Path: "/v1/ns/instance", Params: params, Timeout: c.timeout(req.Timeout),
+	}, nil); err != nil {
+		return nil, c.networkError(err, "register instance %s:%d of %s", req.Host, req.Port, req.Service)
+	}
+	c.registrations.Store(registrationKey(req.Namespace, req.Service, req.Host, req.Port), reg)
+	return &model.InstanceRegisterResponse{
+		InstanceID: fmt.Sprintf("%s#%d#%s#%s@@%s", req.Host, req.Port, reg.cluster, group, service),
+	}, nil
+}
+
+// DeregisterInstance 反注册实例
+func (c *Connector) DeregisterInstance(instance *model.InstanceDeRegisterRequest) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	group, service := SplitServiceName(instance.Service)
+	key := registrationKey(instance.Namespace, instance.Service, instance.Host, instance.Port)
+	cluster, ephemeral := DefaultCluster, false
+	if value, ok := c.registrations.Load(key); ok {
+		cluster = value.(*registration).cluster
+		ephemeral = value.(*registration).ephemeral
+	}
+	params := c.instanceParams(instance.Namespace, group, service, instance.Host, instance.Port, cluster, ephemeral)
+	if _, err := c.client.Do(&Request{
+		Method: http.MethodDelete, Path: "/v1/ns/instance", Params: params, Timeout: c.timeout(instance.Timeout),
+	}, nil); err != nil {
+		return c.networkError(err, "deregister instance %s:%d of %s", instance.Host, instance.Port, instance.Service)
+	}
+	c.registrations.Delete(key)
+	return nil
+}
+
+// Heartbeat 临时实例心跳续约
+func (c *Connector) Heartbeat(instance *model.InstanceHeartbeatRequest) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	group, service := SplitServiceName(instance.Service)
+	reg := &registration{cluster: DefaultCluster, weight: 1}
+	if value, ok := c.registrations.Load(
+		registrationKey(instance.Namespace, instance.Service, instance.Host, instance.Port)); ok {
+		reg = value.(*registration)
+	}
+	beat, err := json.Marshal(map[string]interface{}{
+		"ip":          instance.Host,
+		"port":        instance.Port,
+		"serviceName": group + "@@" + service,
+		"cluster":     reg.cluster,
+		"weight":      reg.weight,
+		"metadata":    reg.metadata,
+	})
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "invalid heartbeat of %s", instance.Service)
+	}
+	params := c.instanceParams(instance.Namespace, group, service, instance.Host, instance.Port, reg.cluster, true)
+	params.Set("beat", string(beat))
+	if _, err = c.client.Do(&Request{
+		Method: http.MethodPut, Path: "/v1/ns/instance/beat", Params: params, Timeout: c.timeout(instance.Timeout),
+	}, nil); err != nil {
+		return c.networkError(err, "heartbeat instance %s:%d of %s", instance.Host, instance.Port, instance.Service)
+	}
+	return nil
+}
+
+// ReportClient Nacos不提供地域信息，直接返回空应答
+func (c *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportClientResponse, error) {
+	return &model.ReportClientResponse{}, nil
+}
+
+// UpdateServers Nacos地址固定为global.serverConnector.addresses，无需更新
+func (c *Connector) UpdateServers(key *model.ServiceEventKey) error {
+	return nil
+}
+
+// fetch 从Nacos获取实例及服务列表，Nacos没有北极星的治理规则，规则返回空应答
+func (c *Connector) fetch(key *model.ServiceEventKey) (*apiservice.DiscoverResponse, error) {
+	switch key.Type {
+	case model.EventInstances:
+		return c.fetchInstances(key)
+	case model.EventServices:
+		return c.fetchServices(key)
+	default:
+		return nil, nil
+	}
+}
+
+func (c *Connector) fetchInstances(key *model.ServiceEventKey) (*apiservice.DiscoverResponse, error) {
+	group, service := SplitServiceName(key.Service)
+	params := url.Values{}
+	params.Set("namespaceId", ToNamespaceID(key.Namespace))
+	params.Set("groupName", group)
+	params.Set("serviceName", service)
+	params.Set("healthyOnly", "false")
+	resp := &instanceListResponse{}
+	if _, err := c.client.Do(&Request{Method: http.MethodGet, Path: "/v1/ns/instance/list", Params: params}, resp); err != nil {
+		return nil, err
+	}
+	instances := make([]connector.BridgeInstance, 0, len(resp.Hosts))
+	for _, host := range resp.Hosts {
+		instances = append(instances, connector.BridgeInstance{
+			ID:       host.InstanceID,
+			Host:     host.IP,
+			Port:     host.Port,
+			Weight:   uint32(host.Weight * weightScale),
+			Healthy:  host.Healthy,
+			Isolated: !host.Enabled,
+			Metadata: host.Metadata,
+			Campus:   host.ClusterName,
+		})
+	}
+	return connector.NewBridgeInstancesResponse(key, instances), nil
+}
+
+func (c *Connector) fetchServices(key *model.ServiceEventKey) (*apiservice.DiscoverResponse, error) {
+	services := make([]model.ServiceKey, 0)
+	for pageNo := 1; ; pageNo++ {
+		params := url.Values{}
+		params.Set("namespaceId", ToNamespaceID(key.Namespace))
+		params.Set("pageNo", strconv.Itoa(pageNo))
+		params.Set("pageSize", strconv.Itoa(servicePageSize))
+		resp := &serviceListResponse{}
+		if _, err := c.client.Do(&Request{Method: http.MethodGet, Path: "/v1/ns/service/list", Params: params}, resp); err != nil {
+			return nil, err
+		}
+		for _, name := range resp.Doms {
+			services = append(services, model.ServiceKey{Namespace: key.Namespace, Service: name})
+		}
+		if len(resp.Doms) < servicePageSize || len(services) >= resp.Count {
+			break
+		}
+	}
+	return connector.NewBridgeServicesResponse(key, services), nil
+}
+
+func (c *Connector) instanceParams(namespace, group, service, host string, port int, cluster string,
+	ephemeral bool) url.Values {
+	params := url.Values{}
+	params.Set("namespaceId", ToNamespaceID(namespace))
+	params.Set("groupName", group)
+	params.Set("serviceName", service)
+	params.Set("ip", host)
+	params.Set("port", strconv.Itoa(port))
+	params.Set("clusterName", cluster)
+	params.Set("ephemeral", strconv.FormatBool(ephemeral))
+	return params
+}
+
+func (c *Connector) timeout(timeout *time.Duration) time.Duration {
+	if timeout != nil {
+		return *timeout
+	}
+	return c.messageTimeout
+}
+
+func (c *Connector) networkError(err error, format string, args ...interface{}) error {
+	return model.NewSDKError(model.ErrCodeNetworkError, err, "[nacos] fail to "+format, args...)
+}
+
+func (c *Connector) notEnabled() error {
+	return model.NewSDKError(model.ErrCodePluginError, nil,
+		"%s server connector is not enabled", c.Name())
+}
+
+func registrationKey(namespace, service, host string, port int) string {
+	return namespace + "/" + service + "/" + host + ":" + strconv.Itoa(port)
+}
+
+// init 注册插件信息
+func init() {
+	plugin.RegisterConfigurablePlugin(&Connector{}, &ClientConfig{})
+}
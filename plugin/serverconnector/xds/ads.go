@@ -0,0 +1,413 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package xds
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/network"
+)
+
+const (
+	// adsMethod ADS双向流的方法名
+	adsMethod = "/envoy.service.discovery.v3.AggregatedDiscoveryService/StreamAggregatedResources"
+)
+
+// rawFrame 已编码的protobuf消息
+type rawFrame struct {
+	data []byte
+}
+
+// rawCodec 直接透传已编码的protobuf字节，避免依赖envoy的生成代码
+type rawCodec struct{}
+
+// Marshal 编码
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	return v.(*rawFrame).data, nil
+}
+
+// Unmarshal 解码
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	frame := v.(*rawFrame)
+	frame.data = append(frame.data[:0], data...)
+	return nil
+}
+
+// Name 编码名，与标准protobuf编码的content-subtype保持一致
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// typeState 单个资源类型的订阅状态
+type typeState struct {
+	// names 订阅的资源名，wildcard订阅时为空
+	names   map[string]struct{}
+	version string
+	nonce   string
+}
+
+// adsClient ADS客户端，维护与控制面的双向流，断开后自动重连并重新订阅
+type adsClient struct {
+	addresses         []string
+	nodeID            string
+	nodeCluster       string
+	connectTimeout    time.Duration
+	reconnectInterval time.Duration
+	// tlsConfig 与控制面通信的TLS配置，未启用时为空
+	tlsConfig *tls.Config
+	// onUpdate 资源更新回调
+	onUpdate func(typeURL string, names []string)
+
+	mutex       sync.Mutex
+	subscribed  map[string]*typeState
+	endpoints   map[string]*clusterLoadAssignment
+	clusters    map[string]struct{}
+	hasClusters bool
+	routes      map[string]*routeConfiguration
+	ready       map[string]chan struct{}
+
+	sendMutex sync.Mutex
+	stream    grpc.ClientStream
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newADSClient(addresses []string, nodeID, nodeCluster string, connectTimeout, reconnectInterval time.Duration,
+	tlsConfig *tls.Config, onUpdate func(typeURL string, names []string)) *adsClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &adsClient{
+		addresses:         addresses,
+		nodeID:            nodeID,
+		nodeCluster:       nodeCluster,
+		connectTimeout:    connectTimeout,
+		reconnectInterval: reconnectInterval,
+		tlsConfig:         tlsConfig,
+		onUpdate:          onUpdate,
+		subscribed:        make(map[string]*typeState),
+		endpoints:         make(map[string]*clusterLoadAssignment),
+		clusters:          make(map[string]struct{}),
+		routes:            make(map[string]*routeConfiguration),
+		ready:             make(map[string]chan struct{}),
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+}
+
+// start 启动收发协程
+func (a *adsClient) start() {
+	go a.run()
+}
+
+// stop 关闭连接
+func (a *adsClient) stop() {
+	a.cancel()
+}
+
+// readyKey 资源首次下发的等待标识
+func readyKey(typeURL string, name string) string {
+	return typeURL + "/" + name
+}
+
+// subscribe 订阅资源，name为空表示wildcard订阅，返回资源首次下发时关闭的channel
+func (a *adsClient) subscribe(typeURL string, name string) <-chan struct{} {
+	a.mutex.Lock()
+	key := readyKey(typeURL, name)
+	ready, ok := a.ready[key]
+	if !ok {
+		ready = make(chan struct{})
+		a.ready[key] = ready
+	}
+	state, ok := a.subscribed[typeURL]
+	if !ok {
+		state = &typeState{names: make(map[string]struct{})}
+		a.subscribed[typeURL] = state
+	}
+	changed := false
+	if name != "" {
+		if _, exists := state.names[name]; !exists {
+			state.names[name] = struct{}{}
+			changed = true
+		}
+	} else if !ok {
+		changed = true
+	}
+	req := a.buildRequest(typeURL, state)
+	a.mutex.Unlock()
+	if changed {
+		a.send(req)
+	}
+	return ready
+}
+
+// unsubscribe 取消订阅资源
+func (a *adsClient) unsubscribe(typeURL string, name string) {
+	a.mutex.Lock()
+	state, ok := a.subscribed[typeURL]
+	if !ok || name == "" {
+		a.mutex.Unlock()
+		return
+	}
+	if _, exists := state.names[name]; !exists {
+		a.mutex.Unlock()
+		return
+	}
+	delete(state.names, name)
+	delete(a.ready, readyKey(typeURL, name))
+	delete(a.endpoints, name)
+	delete(a.routes, name)
+	req := a.buildRequest(typeURL, state)
+	a.mutex.Unlock()
+	a.send(req)
+}
+
+// getEndpoints 获取集群的端点信息
+func (a *adsClient) getEndpoints(clusterName string) (*clusterLoadAssignment, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	cla, ok := a.endpoints[clusterName]
+	return cla, ok
+}
+
+// getClusters 获取CDS下发的集群名列表
+func (a *adsClient) getClusters() ([]string, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	names := make([]string, 0, len(a.clusters))
+	for name := range a.clusters {
+		names = append(names, name)
+	}
+	return names, a.hasClusters
+}
+
+// getRoutes 获取RDS下发的路由配置
+func (a *adsClient) getRoutes(routeName string) (*routeConfiguration, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	rc, ok := a.routes[routeName]
+	return rc, ok
+}
+
+// buildRequest 构造订阅请求，调用方需持有锁
+func (a *adsClient) buildRequest(typeURL string, state *typeState) *discoveryRequest {
+	names := make([]string, 0, len(state.names))
+	for name := range state.names {
+		names = append(names, name)
+	}
+	return &discoveryRequest{
+		versionInfo:   state.version,
+		nodeID:        a.nodeID,
+		nodeCluster:   a.nodeCluster,
+		resourceNames: names,
+		typeURL:       typeURL,
+		responseNonce: state.nonce,
+	}
+}
+
+// send 在当前流上发送请求，流未建立时忽略，重连后会重新订阅
+func (a *adsClient) send(req *discoveryRequest) {
+	a.sendMutex.Lock()
+	defer a.sendMutex.Unlock()
+	if a.stream == nil {
+		return
+	}
+	if err := a.stream.SendMsg(&rawFrame{data: req.marshal()}); err != nil {
+		log.GetNetworkLogger().Warnf("[xDS] fail to send %s request, err %v", req.typeURL, err)
+	}
+}
+
+func (a *adsClient) run() {
+	for index := 0; ; index++ {
+		address := a.addresses[index%len(a.addresses)]
+		if err := a.serve(address); err != nil {
+			log.GetNetworkLogger().Errorf("[xDS] ads stream to %s broken, err %v", address, err)
+		}
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-time.After(a.reconnectInterval):
+		}
+	}
+}
+
+// serve 建立连接及双向流，重新发送所有订阅后持续收包，直到流断开
+func (a *adsClient) serve(address string) error {
+	dialCtx, cancel := context.WithTimeout(a.ctx, a.connectTimeout)
+	conn, err := grpc.DialContext(dialCtx, address,
+		grpc.WithTransportCredentials(network.NewGRPCCredentials(a.tlsConfig)), grpc.WithBlock())
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stream, err := conn.NewStream(a.ctx, &grpc.StreamDesc{
+		StreamName:    "StreamAggregatedResources",
+		ServerStreams: true,
+		ClientStreams: true,
+	}, adsMethod, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return err
+	}
+	log.GetNetworkLogger().Infof("[xDS] ads stream to %s established", address)
+
+	a.sendMutex.Lock()
+	a.stream = stream
+	a.sendMutex.Unlock()
+	defer func() {
+		a.sendMutex.Lock()
+		a.stream = nil
+		a.sendMutex.Unlock()
+	}()
+
+	// 新的流上版本号及nonce需要重置
+	a.mutex.Lock()
+	reqs := make([]*discoveryRequest, 0, len(a.subscribed))
+	for typeURL, state := range a.subscribed {
+		state.version = ""
+		state.nonce = ""
+		reqs = append(reqs, a.buildRequest(typeURL, state))
+	}
+	a.mutex.Unlock()
+	for _, req := range reqs {
+		a.send(req)
+	}
+
+	for {
+		frame := &rawFrame{}
+		if err := stream.RecvMsg(frame); err != nil {
+			return err
+		}
+		resp, err := unmarshalDiscoveryResponse(frame.data)
+		if err != nil {
+			log.GetNetworkLogger().Errorf("[xDS] fail to decode discovery response, err %v", err)
+			continue
+		}
+		a.onResponse(resp)
+	}
+}
+
+// onResponse 处理下发的资源，更新缓存后回复ACK，解析失败时回复NACK
+func (a *adsClient) onResponse(resp *discoveryResponse) {
+	var names []string
+	var parseErr error
+	a.mutex.Lock()
+	switch resp.typeURL {
+	case typeURLEndpoint:
+		names, parseErr = a.updateEndpoints(resp.resources)
+	case typeURLCluster:
+		names, parseErr = a.updateClusters(resp.resources)
+	case typeURLRoute:
+		names, parseErr = a.updateRoutes(resp.resources)
+	}
+	state, ok := a.subscribed[resp.typeURL]
+	if !ok {
+		a.mutex.Unlock()
+		return
+	}
+	state.nonce = resp.nonce
+	ack := a.buildRequest(resp.typeURL, state)
+	if parseErr == nil {
+		state.version = resp.versionInfo
+		ack.versionInfo = resp.versionInfo
+	} else {
+		ack.errorDetail = parseErr.Error()
+	}
+	for _, name := range names {
+		a.markReady(resp.typeURL, name)
+	}
+	a.mutex.Unlock()
+	a.send(ack)
+	if parseErr != nil {
+		log.GetNetworkLogger().Errorf("[xDS] reject %s version %s, err %v", resp.typeURL, resp.versionInfo, parseErr)
+		return
+	}
+	if a.onUpdate != nil {
+		a.onUpdate(resp.typeURL, names)
+	}
+}
+
+// updateEndpoints EDS全量下发订阅的集群，任一资源解析失败时整体拒绝，调用方需持有锁
+func (a *adsClient) updateEndpoints(resources [][]byte) ([]string, error) {
+	assignments := make([]*clusterLoadAssignment, 0, len(resources))
+	for _, resource := range resources {
+		cla, err := unmarshalClusterLoadAssignment(resource)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, cla)
+	}
+	names := make([]string, 0, len(assignments))
+	for _, cla := range assignments {
+		a.endpoints[cla.clusterName] = cla
+		names = append(names, cla.clusterName)
+	}
+	return names, nil
+}
+
+// updateClusters CDS为wildcard全量下发，调用方需持有锁
+func (a *adsClient) updateClusters(resources [][]byte) ([]string, error) {
+	clusters := make(map[string]struct{}, len(resources))
+	for _, resource := range resources {
+		name, err := unmarshalClusterName(resource)
+		if err != nil {
+			return nil, err
+		}
+		clusters[name] = struct{}{}
+	}
+	a.clusters = clusters
+	a.hasClusters = true
+	return []string{""}, nil
+}
+
+// updateRoutes RDS全量下发订阅的路由配置，任一资源解析失败时整体拒绝，调用方需持有锁
+func (a *adsClient) updateRoutes(resources [][]byte) ([]string, error) {
+	configs := make([]*routeConfiguration, 0, len(resources))
+	for _, resource := range resources {
+		rc, err := unmarshalRouteConfiguration(resource)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, rc)
+	}
+	names := make([]string, 0, len(configs))
+	for _, rc := range configs {
+		a.routes[rc.name] = rc
+		names = append(names, rc.name)
+	}
+	return names, nil
+}
+
+// markReady 标记资源已下发，调用方需持有锁
+func (a *adsClient) markReady(typeURL string, name string) {
+	key := readyKey(typeURL, name)
+	ready, ok := a.ready[key]
+	if !ok {
+		ready = make(chan struct{})
+		a.ready[key] = ready
+	}
+	select {
+	case <-ready:
+	default:
+		close(ready)
+	}
+}
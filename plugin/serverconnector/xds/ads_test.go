@@ -0,0 +1,287 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package xds
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+func TestMain(m *testing.M) {
+	log.DiscardAllLoggers()
+	os.Exit(m.Run())
+}
+
+// 以下辅助函数按envoy v3的字段编号拼装报文，结构与Istio控制面实际下发的报文一致
+
+func message(fields ...[]byte) []byte {
+	var b []byte
+	for _, f := range fields {
+		b = append(b, f...)
+	}
+	return b
+}
+
+func bytesField(num protowire.Number, value []byte) []byte {
+	b := protowire.AppendTag(nil, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+func stringField(num protowire.Number, value string) []byte {
+	return bytesField(num, []byte(value))
+}
+
+func varintField(num protowire.Number, value uint64) []byte {
+	b := protowire.AppendTag(nil, num, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+// recordedResponse 构造DiscoveryResponse，资源以google.protobuf.Any封装
+func recordedResponse(typeURL, version, nonce string, resources ...[]byte) []byte {
+	fields := [][]byte{stringField(1, version)}
+	for _, resource := range resources {
+		fields = append(fields, bytesField(2, message(stringField(1, typeURL), bytesField(2, resource))))
+	}
+	fields = append(fields, stringField(4, typeURL), stringField(5, nonce))
+	return message(fields...)
+}
+
+// recordedEndpoint 构造LbEndpoint，带istio的元数据及权重
+func recordedEndpoint(host string, port uint64, health uint64, weight uint64, version string) []byte {
+	socket := message(stringField(2, host), varintField(3, port))
+	endpoint := bytesField(1, bytesField(1, bytesField(1, socket)))
+	versionValue := bytesField(2, stringField(3, version))
+	fields := bytesField(1, message(stringField(1, "version"), versionValue))
+	metadata := bytesField(1, message(stringField(1, "istio"), bytesField(2, fields)))
+	return message(endpoint, varintField(2, health), bytesField(3, metadata), bytesField(4, varintField(1, weight)))
+}
+
+func recordedClusterLoadAssignment(clusterName string, endpoints ...[]byte) []byte {
+	locality := message(stringField(1, "ap-guangzhou"), stringField(2, "ap-guangzhou-3"), stringField(3, "rack-1"))
+	fields := [][]byte{bytesField(1, locality)}
+	for _, endpoint := range endpoints {
+		fields = append(fields, bytesField(2, endpoint))
+	}
+	return message(stringField(1, clusterName), bytesField(2, message(fields...)))
+}
+
+// recordedRouteConfiguration 构造reviews服务的路由：带头部的请求转发到v2子集，其余按权重转发到v1/v2
+func recordedRouteConfiguration(name string) []byte {
+	headerRoute := message(
+		bytesField(1, message(
+			stringField(1, "/"),
+			bytesField(6, message(stringField(1, "end-user"), bytesField(13, stringField(1, "jason")))),
+		)),
+		bytesField(2, stringField(1, "outbound|9080|v2|reviews.default.svc.cluster.local")),
+		stringField(14, "jason"),
+	)
+	weightedRoute := message(
+		bytesField(1, stringField(1, "/reviews")),
+		bytesField(2, bytesField(3, message(
+			bytesField(1, message(stringField(1, "outbound|9080|v1|reviews.default.svc.cluster.local"),
+				bytesField(2, varintField(1, 80)))),
+			bytesField(1, message(stringField(1, "outbound|9080|v2|reviews.default.svc.cluster.local"),
+				bytesField(2, varintField(1, 20)))),
+		))),
+		stringField(14, "default"),
+	)
+	otherHost := message(
+		stringField(1, "ratings.default.svc.cluster.local:9080"),
+		stringField(2, "ratings.default.svc.cluster.local"),
+		bytesField(3, message(
+			bytesField(1, stringField(1, "/")),
+			bytesField(2, stringField(1, "outbound|9080||ratings.default.svc.cluster.local")),
+		)),
+	)
+	reviewsHost := message(
+		stringField(1, "reviews.default.svc.cluster.local:9080"),
+		stringField(2, "reviews.default.svc.cluster.local"),
+		stringField(2, "reviews"),
+		bytesField(3, headerRoute),
+		bytesField(3, weightedRoute),
+	)
+	return message(stringField(1, name), bytesField(2, otherHost), bytesField(2, reviewsHost))
+}
+
+// fakeStream 记录发送的ACK/NACK请求
+type fakeStream struct {
+	grpc.ClientStream
+	sent [][]byte
+}
+
+func (s *fakeStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m.(*rawFrame).data)
+	return nil
+}
+
+// ackOf 解析发送的请求中的版本号、nonce及是否携带错误详情
+func ackOf(t *testing.T, data []byte) (version string, nonce string, nack bool) {
+	err := walkFields(data, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			version = string(value)
+		case 5:
+			nonce = string(value)
+		case 6:
+			nack = true
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	return version, nonce, nack
+}
+
+func TestADSClientOnResponse(t *testing.T) {
+	const cluster = "outbound|9080||reviews.default.svc.cluster.local"
+
+	t.Run("EDS下发后更新端点并回复ACK", func(t *testing.T) {
+		client, stream, updates := newTestADSClient()
+		ready := client.subscribe(typeURLEndpoint, cluster)
+		raw := recordedResponse(typeURLEndpoint, "2024-01-01T00:00:00Z/1", "nonce-1",
+			recordedClusterLoadAssignment(cluster,
+				recordedEndpoint("10.0.0.1", 9080, healthStatusHealthy, 3, "v1"),
+				recordedEndpoint("10.0.0.2", 9080, healthStatusDraining, 0, "v2")))
+		resp, err := unmarshalDiscoveryResponse(raw)
+		assert.Nil(t, err)
+		client.onResponse(resp)
+
+		assertClosed(t, ready)
+		cla, ok := client.getEndpoints(cluster)
+		assert.True(t, ok)
+		assert.Equal(t, []lbEndpoint{
+			{host: "10.0.0.1", port: 9080, healthStatus: healthStatusHealthy, weight: 3,
+				metadata: map[string]string{"version": "v1"},
+				region:   "ap-guangzhou", zone: "ap-guangzhou-3", subZone: "rack-1"},
+			{host: "10.0.0.2", port: 9080, healthStatus: healthStatusDraining,
+				metadata: map[string]string{"version": "v2"},
+				region:   "ap-guangzhou", zone: "ap-guangzhou-3", subZone: "rack-1"},
+		}, cla.endpoints)
+		assert.Equal(t, [][]string{{cluster}}, *updates)
+		version, nonce, nack := ackOf(t, stream.sent[len(stream.sent)-1])
+		assert.Equal(t, "2024-01-01T00:00:00Z/1", version)
+		assert.Equal(t, "nonce-1", nonce)
+		assert.False(t, nack)
+	})
+
+	t.Run("CDS全量替换集群列表", func(t *testing.T) {
+		client, _, _ := newTestADSClient()
+		client.subscribe(typeURLCluster, "")
+		for i, names := range [][]string{{cluster, "BlackHoleCluster"}, {cluster}} {
+			resources := make([][]byte, 0, len(names))
+			for _, name := range names {
+				resources = append(resources, message(stringField(1, name), varintField(2, 3)))
+			}
+			resp, err := unmarshalDiscoveryResponse(recordedResponse(typeURLCluster, "v", "n", resources...))
+			assert.Nil(t, err)
+			client.onResponse(resp)
+			clusters, ok := client.getClusters()
+			assert.True(t, ok)
+			assert.ElementsMatch(t, names, clusters, "round %d", i)
+		}
+	})
+
+	t.Run("RDS下发后缓存路由配置", func(t *testing.T) {
+		client, _, updates := newTestADSClient()
+		ready := client.subscribe(typeURLRoute, "9080")
+		resp, err := unmarshalDiscoveryResponse(
+			recordedResponse(typeURLRoute, "v1", "n1", recordedRouteConfiguration("9080")))
+		assert.Nil(t, err)
+		client.onResponse(resp)
+
+		assertClosed(t, ready)
+		rc, ok := client.getRoutes("9080")
+		assert.True(t, ok)
+		assert.Len(t, rc.virtualHosts, 2)
+		reviews := rc.virtualHosts[1]
+		assert.Equal(t, []string{"reviews.default.svc.cluster.local", "reviews"}, reviews.domains)
+		assert.Equal(t, []route{
+			{
+				name: "jason", path: "/", pathMatch: matchPrefix,
+				headers:  []headerMatcher{{name: "end-user", match: matchExact, value: "jason"}},
+				clusters: []clusterWeight{{name: "outbound|9080|v2|reviews.default.svc.cluster.local"}},
+			},
+			{
+				name: "default", path: "/reviews", pathMatch: matchPrefix,
+				clusters: []clusterWeight{
+					{name: "outbound|9080|v1|reviews.default.svc.cluster.local", weight: 80},
+					{name: "outbound|9080|v2|reviews.default.svc.cluster.local", weight: 20},
+				},
+			},
+		}, reviews.routes)
+		assert.Equal(t, [][]string{{"9080"}}, *updates)
+	})
+
+	t.Run("资源解析失败时回复NACK并保留旧数据", func(t *testing.T) {
+		client, stream, updates := newTestADSClient()
+		client.subscribe(typeURLEndpoint, cluster)
+		resp, err := unmarshalDiscoveryResponse(recordedResponse(typeURLEndpoint, "v1", "n1",
+			recordedClusterLoadAssignment(cluster, recordedEndpoint("10.0.0.1", 9080, healthStatusHealthy, 1, "v1"))))
+		assert.Nil(t, err)
+		client.onResponse(resp)
+
+		valid := recordedClusterLoadAssignment(cluster, recordedEndpoint("10.0.0.9", 9080, healthStatusHealthy, 1, "v1"))
+		truncated := recordedClusterLoadAssignment(cluster, recordedEndpoint("10.0.0.3", 9080, healthStatusHealthy, 1, "v1"))
+		truncated = truncated[:len(truncated)-3]
+		resp, err = unmarshalDiscoveryResponse(recordedResponse(typeURLEndpoint, "v2", "n2", valid, truncated))
+		assert.Nil(t, err)
+		client.onResponse(resp)
+
+		cla, _ := client.getEndpoints(cluster)
+		assert.Equal(t, "10.0.0.1", cla.endpoints[0].host)
+		assert.Len(t, *updates, 1)
+		version, nonce, nack := ackOf(t, stream.sent[len(stream.sent)-1])
+		assert.Equal(t, "v1", version)
+		assert.Equal(t, "n2", nonce)
+		assert.True(t, nack)
+	})
+
+	t.Run("未订阅的资源类型不回复", func(t *testing.T) {
+		client, stream, updates := newTestADSClient()
+		resp, err := unmarshalDiscoveryResponse(recordedResponse(typeURLCluster, "v1", "n1",
+			message(stringField(1, cluster))))
+		assert.Nil(t, err)
+		client.onResponse(resp)
+		assert.Empty(t, stream.sent)
+		assert.Empty(t, *updates)
+	})
+}
+
+func newTestADSClient() (*adsClient, *fakeStream, *[][]string) {
+	updates := &[][]string{}
+	client := newADSClient([]string{"127.0.0.1:15010"}, "sidecar~10.0.0.1~test~default.svc.cluster.local", "",
+		time.Second, time.Second, nil, func(typeURL string, names []string) {
+			*updates = append(*updates, names)
+		})
+	stream := &fakeStream{}
+	client.stream = stream
+	return client, stream, updates
+}
+
+func assertClosed(t *testing.T, ch <-chan struct{}) {
+	select {
+	case <-ch:
+	default:
+		t.Fatal("channel not closed")
+	}
+}
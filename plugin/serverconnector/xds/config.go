@@ -0,0 +1,84 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package xds
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// placeholderNamespace 集群名模板中的命名空间占位符
+	placeholderNamespace = "{namespace}"
+	// placeholderService 集群名模板中的服务名占位符
+	placeholderService = "{service}"
+	// placeholderSubset 集群名模板中的子集占位符，服务自身的集群对应空子集
+	placeholderSubset = "{subset}"
+	// DefaultClusterNameFormat 默认集群名模板
+	DefaultClusterNameFormat = placeholderService
+	// DefaultSubsetLabel 默认的子集标签，与Istio DestinationRule中常用的版本标签一致
+	DefaultSubsetLabel = "version"
+)
+
+// Config xDS连接器的插件配置
+type Config struct {
+	// NodeID 上报给控制面的节点ID，对接Istio时需满足sidecar~ip~id~domain格式
+	NodeID string `yaml:"nodeId" json:"nodeId"`
+	// NodeCluster 上报给控制面的节点集群名
+	NodeCluster string `yaml:"nodeCluster" json:"nodeCluster"`
+	// ClusterNameFormat 北极星服务与xDS集群名的映射模板，支持{namespace}及{service}占位符，
+	// 对接Istio时可配置为 outbound|8080||{service}.{namespace}.svc.cluster.local
+	ClusterNameFormat string `yaml:"clusterNameFormat" json:"clusterNameFormat"`
+	// RouteNameFormat 北极星服务与RDS路由配置名的映射模板，支持{namespace}及{service}占位符，为空时不订阅RDS。
+	// 对接Istio时可配置为端口号如 8080，同一路由配置下按虚拟主机转发的集群筛选出服务的路由
+	RouteNameFormat string `yaml:"routeNameFormat" json:"routeNameFormat"`
+	// SubsetLabel 集群名模板中{subset}对应的实例标签，RDS转发到子集集群时转换为该标签的匹配条件
+	SubsetLabel string `yaml:"subsetLabel" json:"subsetLabel"`
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	if nil == c {
+		return errors.New("xds config is nil")
+	}
+	var errs error
+	if c.NodeID == "" {
+		errs = multierror.Append(errs, fmt.Errorf("xds.nodeId can not be empty"))
+	}
+	if !strings.Contains(c.ClusterNameFormat, placeholderService) {
+		errs = multierror.Append(errs, fmt.Errorf("xds.clusterNameFormat must contain %s", placeholderService))
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.NodeID == "" {
+		hostname, _ := os.Hostname()
+		c.NodeID = "polaris-go~" + hostname
+	}
+	if c.ClusterNameFormat == "" {
+		c.ClusterNameFormat = DefaultClusterNameFormat
+	}
+	if c.SubsetLabel == "" {
+		c.SubsetLabel = DefaultSubsetLabel
+	}
+}
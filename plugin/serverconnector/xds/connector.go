@@ -0,0 +1,333 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package xds
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/network"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/identity"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+)
+
+const (
+	// protocolXDS 插件名，global.serverConnector.protocol配置为xds时启用
+	protocolXDS = "xds"
+	// defaultInstanceWeight 端点未设置权重时的默认权重
+	defaultInstanceWeight = 100
+	// metadataSubZone 端点sub_zone在实例元数据中的key
+	metadataSubZone = "xds.subZone"
+)
+
+// Connector 通过Envoy xDS协议（ADS）从Istio/Envoy控制面获取服务实例，EDS转换为服务实例，CDS转换为服务列表，
+// RDS转换为服务的入流量路由规则，注册、心跳等写操作不支持
+type Connector struct {
+	*plugin.PluginBase
+	cfg            *Config
+	enable         bool
+	messageTimeout time.Duration
+	clusterPattern *regexp.Regexp
+	ads            *adsClient
+	discover       *connector.BridgeDiscover
+}
+
+// Type 插件类型
+func (c *Connector) Type() common.Type {
+	return common.TypeServerConnector
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (c *Connector) Name() string {
+	return protocolXDS
+}
+
+// Init 初始化插件
+func (c *Connector) Init(ctx *plugin.InitContext) error {
+	c.PluginBase = plugin.NewPluginBase(ctx)
+	connectorCfg := ctx.Config.GetGlobal().GetServerConnector()
+	c.enable = connectorCfg.GetProtocol() == c.Name()
+	if !c.enable {
+		return nil
+	}
+	c.cfg = connectorCfg.GetPluginConfig(c.Name()).(*Config)
+	c.messageTimeout = connectorCfg.GetMessageTimeout()
+	c.clusterPattern = compileClusterPattern(c.cfg.ClusterNameFormat)
+	identityProvider, err := identity.GetIdentityProvider(ctx.Config, ctx.Plugins)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := network.BuildTLSConfig(connectorCfg.GetTLS(), identityProvider)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to build tls config for serverConnector")
+	}
+	c.ads = newADSClient(connectorCfg.GetAddresses(), c.cfg.NodeID, c.cfg.NodeCluster,
+		connectorCfg.GetConnectTimeout(), connectorCfg.GetReconnectInterval(), tlsConfig, c.onResourceUpdate)
+	c.discover = connector.NewBridgeDiscover(c.Name(), c.fetch)
+	return nil
+}
+
+// Start 启动插件
+func (c *Connector) Start() error {
+	if c.enable {
+		c.ads.start()
+	}
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (c *Connector) Destroy() error {
+	if c.enable {
+		c.discover.Destroy()
+		c.ads.stop()
+	}
+	return nil
+}
+
+// IsEnable 插件开关
+func (c *Connector) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// RegisterServiceHandler 注册服务监听器
+func (c *Connector) RegisterServiceHandler(svcEventHandler *serverconnector.ServiceEventHandler) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	return c.discover.RegisterServiceHandler(svcEventHandler)
+}
+
+// DeRegisterServiceHandler 反注册事件监听器
+func (c *Connector) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
+	if !c.enable {
+		return c.notEnabled()
+	}
+	// 路由配置可能被多个服务共用，不随单个服务反注册而取消订阅
+	if key.Type == model.EventInstances {
+		c.ads.unsubscribe(typeURLEndpoint, c.clusterName(key.ServiceKey))
+	}
+	return c.discover.DeRegisterServiceHandler(key)
+}
+
+// RegisterInstance xDS为只读的控制面协议，不支持注册
+func (c *Connector) RegisterInstance(req *model.InstanceRegisterRequest,
+	header map[string]string) (*model.InstanceRegisterResponse, error) {
+	return nil, c.notSupported("RegisterInstance")
+}
+
+// DeregisterInstance xDS为只读的控制面协议，不支持反注册
+func (c *Connector) DeregisterInstance(instance *model.InstanceDeRegisterRequest) error {
+	return c.notSupported("DeregisterInstance")
+}
+
+// Heartbeat xDS为只读的控制面协议，不支持心跳上报
+func (c *Connector) Heartbeat(instance *model.InstanceHeartbeatRequest) error {
+	return c.notSupported("Heartbeat")
+}
+
+// ReportClient 控制面不提供地域信息，直接返回空应答
+func (c *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportClientResponse, error) {
+	return &model.ReportClientResponse{}, nil
+}
+
+// UpdateServers 控制面地址固定为global.serverConnector.addresses，无需更新
+func (c *Connector) UpdateServers(key *model.ServiceEventKey) error {
+	return nil
+}
+
+func (c *Connector) notSupported(operation string) error {
+	return model.NewSDKError(model.ErrCodePluginError, nil,
+		"%s is not supported by %s server connector", operation, c.Name())
+}
+
+func (c *Connector) notEnabled() error {
+	return model.NewSDKError(model.ErrCodePluginError, nil,
+		"%s server connector is not enabled", c.Name())
+}
+
+// fetch 从ADS缓存中获取资源，首次订阅时等待控制面下发
+func (c *Connector) fetch(key *model.ServiceEventKey) (*apiservice.DiscoverResponse, error) {
+	switch key.Type {
+	case model.EventInstances:
+		clusterName := c.clusterName(key.ServiceKey)
+		if err := c.waitReady(c.ads.subscribe(typeURLEndpoint, clusterName), key); err != nil {
+			return nil, err
+		}
+		cla, _ := c.ads.getEndpoints(clusterName)
+		return connector.NewBridgeInstancesResponse(key, toBridgeInstances(cla)), nil
+	case model.EventServices:
+		if err := c.waitReady(c.ads.subscribe(typeURLCluster, ""), key); err != nil {
+			return nil, err
+		}
+		clusterNames, _ := c.ads.getClusters()
+		services := make([]model.ServiceKey, 0, len(clusterNames))
+		for _, clusterName := range clusterNames {
+			// 子集集群与服务自身的集群对应同一个服务，只保留服务自身的集群
+			if svcKey, subset, ok := c.parseClusterName(clusterName, key.Namespace); ok && subset == "" {
+				services = append(services, svcKey)
+			}
+		}
+		return connector.NewBridgeServicesResponse(key, services), nil
+	case model.EventRouting:
+		if c.cfg.RouteNameFormat == "" {
+			return nil, nil
+		}
+		routeName := c.routeName(key.ServiceKey)
+		if err := c.waitReady(c.ads.subscribe(typeURLRoute, routeName), key); err != nil {
+			return nil, err
+		}
+		rc, _ := c.ads.getRoutes(routeName)
+		return connector.NewBridgeRoutingResponse(key, c.toRouting(key.ServiceKey, rc)), nil
+	default:
+		// 限流、熔断等规则不通过xDS下发，返回空规则
+		return nil, nil
+	}
+}
+
+func (c *Connector) waitReady(ready <-chan struct{}, key *model.ServiceEventKey) error {
+	select {
+	case <-ready:
+		return nil
+	case <-time.After(c.messageTimeout):
+		return model.NewSDKError(model.ErrCodeAPITimeoutError, nil,
+			"wait xds resource for %s timeout after %v", key, c.messageTimeout)
+	}
+}
+
+// onResourceUpdate 控制面推送资源后触发对应服务立即刷新
+func (c *Connector) onResourceUpdate(typeURL string, names []string) {
+	switch typeURL {
+	case typeURLEndpoint:
+		for _, name := range names {
+			svcKey, _, ok := c.parseClusterName(name, "")
+			if !ok {
+				log.GetBaseLogger().Debugf("[xDS] cluster %s not match format %s", name, c.cfg.ClusterNameFormat)
+				continue
+			}
+			c.discover.NotifyService(svcKey)
+		}
+	case typeURLCluster:
+		c.discover.NotifyType(model.EventServices)
+	case typeURLRoute:
+		// 路由配置名与服务不一定一一对应，刷新所有服务的路由规则
+		c.discover.NotifyType(model.EventRouting)
+	}
+}
+
+// clusterName 将北极星服务映射为xDS集群名
+func (c *Connector) clusterName(svcKey model.ServiceKey) string {
+	return formatName(c.cfg.ClusterNameFormat, svcKey)
+}
+
+// routeName 将北极星服务映射为RDS路由配置名
+func (c *Connector) routeName(svcKey model.ServiceKey) string {
+	return formatName(c.cfg.RouteNameFormat, svcKey)
+}
+
+func formatName(format string, svcKey model.ServiceKey) string {
+	name := strings.ReplaceAll(format, placeholderNamespace, svcKey.Namespace)
+	name = strings.ReplaceAll(name, placeholderSubset, "")
+	return strings.ReplaceAll(name, placeholderService, svcKey.Service)
+}
+
+// parseClusterName 将xDS集群名还原为北极星服务及子集，模板中没有命名空间时使用defaultNamespace
+func (c *Connector) parseClusterName(clusterName string, defaultNamespace string) (model.ServiceKey, string, bool) {
+	match := c.clusterPattern.FindStringSubmatch(clusterName)
+	if match == nil {
+		return model.ServiceKey{}, "", false
+	}
+	svcKey := model.ServiceKey{Namespace: defaultNamespace}
+	var subset string
+	for i, name := range c.clusterPattern.SubexpNames() {
+		switch name {
+		case "namespace":
+			svcKey.Namespace = match[i]
+		case "service":
+			svcKey.Service = match[i]
+		case "subset":
+			subset = match[i]
+		}
+	}
+	if defaultNamespace != "" && svcKey.Namespace != defaultNamespace {
+		return model.ServiceKey{}, "", false
+	}
+	return svcKey, subset, true
+}
+
+// compileClusterPattern 将集群名模板转换为正则，占位符转换为命名分组
+func compileClusterPattern(format string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(format)
+	pattern = strings.Replace(pattern, regexp.QuoteMeta(placeholderNamespace), "(?P<namespace>[^|]+?)", 1)
+	pattern = strings.Replace(pattern, regexp.QuoteMeta(placeholderService), "(?P<service>[^|]+?)", 1)
+	pattern = strings.Replace(pattern, regexp.QuoteMeta(placeholderSubset), "(?P<subset>[^|]*?)", 1)
+	return regexp.MustCompile("^" + pattern + "$")
+}
+
+// toBridgeInstances 将EDS端点转换为实例
+func toBridgeInstances(cla *clusterLoadAssignment) []connector.BridgeInstance {
+	if cla == nil {
+		return nil
+	}
+	instances := make([]connector.BridgeInstance, 0, len(cla.endpoints))
+	for _, endpoint := range cla.endpoints {
+		weight := endpoint.weight
+		if weight == 0 {
+			weight = defaultInstanceWeight
+		}
+		metadata := endpoint.metadata
+		if endpoint.subZone != "" {
+			if metadata == nil {
+				metadata = make(map[string]string)
+			}
+			metadata[metadataSubZone] = endpoint.subZone
+		}
+		instances = append(instances, connector.BridgeInstance{
+			Host:     endpoint.host,
+			Port:     endpoint.port,
+			Weight:   weight,
+			Healthy:  isHealthy(endpoint.healthStatus),
+			Isolated: endpoint.healthStatus == healthStatusDraining,
+			Metadata: metadata,
+			Region:   endpoint.region,
+			Zone:     endpoint.zone,
+		})
+	}
+	return instances
+}
+
+func isHealthy(status uint64) bool {
+	switch status {
+	case healthStatusUnknown, healthStatusHealthy, healthStatusDegraded:
+		return true
+	default:
+		return false
+	}
+}
+
+// init 注册插件信息
+func init() {
+	plugin.RegisterConfigurablePlugin(&Connector{}, &Config{})
+}
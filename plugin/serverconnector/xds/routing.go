@@ -0,0 +1,152 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package xds
+
+import (
+	"regexp"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// sourcePathKey 请求路径在主调方元数据中的key，与HTTP/2的:path伪头部保持一致
+	sourcePathKey = ":path"
+	// matchAll 匹配任意值
+	matchAll = "*"
+)
+
+// toRouting 将RDS路由配置转换为服务的入流量路由规则，只保留转发到该服务（含子集）的路由，
+// 路由顺序与虚拟主机中的顺序一致，首个匹配的路由生效
+func (c *Connector) toRouting(svcKey model.ServiceKey, rc *routeConfiguration) *apitraffic.Routing {
+	routing := &apitraffic.Routing{
+		Service:   &wrappers.StringValue{Value: svcKey.Service},
+		Namespace: &wrappers.StringValue{Value: svcKey.Namespace},
+	}
+	if rc == nil {
+		return routing
+	}
+	for _, vh := range rc.virtualHosts {
+		for i := range vh.routes {
+			if inbound, ok := c.toRoute(svcKey, &vh.routes[i]); ok {
+				routing.Inbounds = append(routing.Inbounds, inbound)
+			}
+		}
+	}
+	return routing
+}
+
+// toRoute 将单条RDS路由转换为北极星路由，没有转发到该服务的集群或包含无法转换的匹配条件时忽略
+func (c *Connector) toRoute(svcKey model.ServiceKey, r *route) (*apitraffic.Route, bool) {
+	destinations := make([]*apitraffic.Destination, 0, len(r.clusters))
+	for _, cluster := range r.clusters {
+		target, subset, ok := c.parseClusterName(cluster.name, svcKey.Namespace)
+		if !ok || target != svcKey {
+			continue
+		}
+		weight := cluster.weight
+		if len(r.clusters) == 1 && weight == 0 {
+			weight = defaultInstanceWeight
+		}
+		destination := &apitraffic.Destination{
+			Service:   &wrappers.StringValue{Value: svcKey.Service},
+			Namespace: &wrappers.StringValue{Value: svcKey.Namespace},
+			Priority:  &wrappers.UInt32Value{Value: 0},
+			Weight:    &wrappers.UInt32Value{Value: weight},
+		}
+		if subset != "" {
+			destination.Metadata = map[string]*apimodel.MatchString{
+				c.cfg.SubsetLabel: {Type: apimodel.MatchString_EXACT, Value: &wrappers.StringValue{Value: subset}},
+			}
+		}
+		destinations = append(destinations, destination)
+	}
+	if len(destinations) == 0 {
+		return nil, false
+	}
+	metadata := make(map[string]*apimodel.MatchString, len(r.headers)+1)
+	pathMatch, ok := toPathMatchString(r)
+	if !ok {
+		log.GetBaseLogger().Warnf("[xDS] ignore route %s of %s, unsupported path match", r.name, svcKey)
+		return nil, false
+	}
+	if pathMatch != nil {
+		metadata[sourcePathKey] = pathMatch
+	}
+	for _, header := range r.headers {
+		matchString, ok := toHeaderMatchString(&header)
+		if !ok {
+			log.GetBaseLogger().Warnf("[xDS] ignore route %s of %s, unsupported header match on %s",
+				r.name, svcKey, header.name)
+			return nil, false
+		}
+		metadata[header.name] = matchString
+	}
+	source := &apitraffic.Source{
+		Service:   &wrappers.StringValue{Value: matchAll},
+		Namespace: &wrappers.StringValue{Value: matchAll},
+	}
+	if len(metadata) > 0 {
+		source.Metadata = metadata
+	}
+	return &apitraffic.Route{Sources: []*apitraffic.Source{source}, Destinations: destinations}, true
+}
+
+// toPathMatchString 转换路径匹配条件，前缀为/时匹配任意路径，返回空
+func toPathMatchString(r *route) (*apimodel.MatchString, bool) {
+	if r.pathMatch == matchPrefix && (r.path == "" || r.path == "/") {
+		return nil, true
+	}
+	return toMatchString(r.pathMatch, r.path, false)
+}
+
+func toHeaderMatchString(header *headerMatcher) (*apimodel.MatchString, bool) {
+	return toMatchString(header.match, header.value, header.invert)
+}
+
+// toMatchString 将envoy的字符串匹配转换为北极星的匹配条件，envoy的正则为全量匹配，转换时补齐首尾锚点。
+// 取反只支持精确匹配
+func toMatchString(match int, value string, invert bool) (*apimodel.MatchString, bool) {
+	if invert && match != matchExact {
+		return nil, false
+	}
+	matchType := apimodel.MatchString_REGEX
+	switch match {
+	case matchExact:
+		matchType = apimodel.MatchString_EXACT
+		if invert {
+			matchType = apimodel.MatchString_NOT_EQUALS
+		}
+	case matchPresent:
+		matchType, value = apimodel.MatchString_EXACT, matchAll
+	case matchPrefix:
+		value = "^" + regexp.QuoteMeta(value)
+	case matchSuffix:
+		value = regexp.QuoteMeta(value) + "$"
+	case matchContains:
+		value = regexp.QuoteMeta(value)
+	case matchRegex:
+		value = "^(?:" + value + ")$"
+	default:
+		return nil, false
+	}
+	return &apimodel.MatchString{Type: matchType, Value: &wrappers.StringValue{Value: value}}, true
+}
@@ -0,0 +1,165 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package xds
+
+import (
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func newTestConnector() *Connector {
+	cfg := &Config{
+		NodeID:            "sidecar~10.0.0.1~test~default.svc.cluster.local",
+		ClusterNameFormat: "outbound|9080|{subset}|{service}.{namespace}.svc.cluster.local",
+		RouteNameFormat:   "9080",
+	}
+	cfg.SetDefault()
+	return &Connector{cfg: cfg, clusterPattern: compileClusterPattern(cfg.ClusterNameFormat)}
+}
+
+func TestConnectorParseClusterName(t *testing.T) {
+	c := newTestConnector()
+	tests := []struct {
+		name      string
+		cluster   string
+		namespace string
+		svcKey    model.ServiceKey
+		subset    string
+		ok        bool
+	}{
+		{
+			name:    "服务自身的集群",
+			cluster: "outbound|9080||reviews.default.svc.cluster.local",
+			svcKey:  model.ServiceKey{Namespace: "default", Service: "reviews"},
+			ok:      true,
+		},
+		{
+			name:    "子集集群",
+			cluster: "outbound|9080|v2|reviews.default.svc.cluster.local",
+			svcKey:  model.ServiceKey{Namespace: "default", Service: "reviews"},
+			subset:  "v2",
+			ok:      true,
+		},
+		{
+			name:      "命名空间不一致",
+			cluster:   "outbound|9080||reviews.prod.svc.cluster.local",
+			namespace: "default",
+		},
+		{
+			name:    "不满足模板",
+			cluster: "BlackHoleCluster",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svcKey, subset, ok := c.parseClusterName(tt.cluster, tt.namespace)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.svcKey, svcKey)
+			assert.Equal(t, tt.subset, subset)
+		})
+	}
+	svcKey := model.ServiceKey{Namespace: "default", Service: "reviews"}
+	assert.Equal(t, "outbound|9080||reviews.default.svc.cluster.local", c.clusterName(svcKey))
+	assert.Equal(t, "9080", c.routeName(svcKey))
+}
+
+func TestConnectorToRouting(t *testing.T) {
+	c := newTestConnector()
+	rc, err := unmarshalRouteConfiguration(recordedRouteConfiguration("9080"))
+	assert.Nil(t, err)
+
+	svcKey := model.ServiceKey{Namespace: "default", Service: "reviews"}
+	routing := c.toRouting(svcKey, rc)
+	assert.Equal(t, "reviews", routing.GetService().GetValue())
+	assert.Equal(t, "default", routing.GetNamespace().GetValue())
+	// ratings虚拟主机的路由不转发到reviews，被过滤
+	assert.Len(t, routing.GetInbounds(), 2)
+
+	headerRoute := routing.GetInbounds()[0]
+	assert.Len(t, headerRoute.GetSources(), 1)
+	source := headerRoute.GetSources()[0]
+	assert.Equal(t, matchAll, source.GetService().GetValue())
+	assert.Equal(t, matchAll, source.GetNamespace().GetValue())
+	assert.Len(t, source.GetMetadata(), 1)
+	assert.Equal(t, apimodel.MatchString_EXACT, source.GetMetadata()["end-user"].GetType())
+	assert.Equal(t, "jason", source.GetMetadata()["end-user"].GetValue().GetValue())
+	assert.Len(t, headerRoute.GetDestinations(), 1)
+	destination := headerRoute.GetDestinations()[0]
+	assert.Equal(t, "reviews", destination.GetService().GetValue())
+	assert.Equal(t, uint32(defaultInstanceWeight), destination.GetWeight().GetValue())
+	assert.Equal(t, "v2", destination.GetMetadata()[DefaultSubsetLabel].GetValue().GetValue())
+
+	weightedRoute := routing.GetInbounds()[1]
+	pathMatch := weightedRoute.GetSources()[0].GetMetadata()[sourcePathKey]
+	assert.Equal(t, apimodel.MatchString_REGEX, pathMatch.GetType())
+	assert.Equal(t, "^/reviews", pathMatch.GetValue().GetValue())
+	weights := make(map[string]uint32)
+	for _, destination := range weightedRoute.GetDestinations() {
+		weights[destination.GetMetadata()[DefaultSubsetLabel].GetValue().GetValue()] = destination.GetWeight().GetValue()
+	}
+	assert.Equal(t, map[string]uint32{"v1": 80, "v2": 20}, weights)
+
+	// 其他服务只得到自身虚拟主机的路由
+	ratings := c.toRouting(model.ServiceKey{Namespace: "default", Service: "ratings"}, rc)
+	assert.Len(t, ratings.GetInbounds(), 1)
+	assert.Nil(t, ratings.GetInbounds()[0].GetSources()[0].GetMetadata())
+	assert.Nil(t, ratings.GetInbounds()[0].GetDestinations()[0].GetMetadata())
+
+	// 路由配置尚未下发时返回空规则
+	assert.Empty(t, c.toRouting(svcKey, nil).GetInbounds())
+}
+
+func TestToMatchString(t *testing.T) {
+	tests := []struct {
+		name      string
+		match     int
+		value     string
+		invert    bool
+		matchType apimodel.MatchString_MatchStringType
+		expect    string
+		ok        bool
+	}{
+		{name: "精确匹配", match: matchExact, value: "a.b", matchType: apimodel.MatchString_EXACT, expect: "a.b", ok: true},
+		{name: "精确匹配取反", match: matchExact, value: "a", invert: true,
+			matchType: apimodel.MatchString_NOT_EQUALS, expect: "a", ok: true},
+		{name: "存在即匹配", match: matchPresent, matchType: apimodel.MatchString_EXACT, expect: matchAll, ok: true},
+		{name: "前缀转义", match: matchPrefix, value: "/a.b", matchType: apimodel.MatchString_REGEX,
+			expect: `^/a\.b`, ok: true},
+		{name: "后缀转义", match: matchSuffix, value: ".json", matchType: apimodel.MatchString_REGEX,
+			expect: `\.json$`, ok: true},
+		{name: "包含", match: matchContains, value: "a+", matchType: apimodel.MatchString_REGEX,
+			expect: `a\+`, ok: true},
+		{name: "正则全量匹配", match: matchRegex, value: "v1|v2", matchType: apimodel.MatchString_REGEX,
+			expect: "^(?:v1|v2)$", ok: true},
+		{name: "非精确匹配不支持取反", match: matchPrefix, value: "/a", invert: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchString, ok := toMatchString(tt.match, tt.value, tt.invert)
+			assert.Equal(t, tt.ok, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, tt.matchType, matchString.GetType())
+			assert.Equal(t, tt.expect, matchString.GetValue().GetValue())
+		})
+	}
+}
@@ -0,0 +1,584 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package xds
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// 本文件按envoy v3协议的字段编号手工编解码xDS消息，只处理连接器需要的字段，未知字段直接跳过
+
+const (
+	// typeURLEndpoint EDS资源类型
+	typeURLEndpoint = "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment"
+	// typeURLCluster CDS资源类型
+	typeURLCluster = "type.googleapis.com/envoy.config.cluster.v3.Cluster"
+	// typeURLRoute RDS资源类型
+	typeURLRoute = "type.googleapis.com/envoy.config.route.v3.RouteConfiguration"
+)
+
+// headerMatcher的匹配方式，对应envoy.type.matcher.v3.StringMatcher及HeaderMatcher中的各匹配字段
+const (
+	matchExact = iota
+	matchPrefix
+	matchSuffix
+	matchRegex
+	matchContains
+	matchPresent
+)
+
+// envoy.config.core.v3.HealthStatus
+const (
+	healthStatusUnknown   = 0
+	healthStatusHealthy   = 1
+	healthStatusUnhealthy = 2
+	healthStatusDraining  = 3
+	healthStatusTimeout   = 4
+	healthStatusDegraded  = 5
+)
+
+// discoveryRequest envoy.service.discovery.v3.DiscoveryRequest
+type discoveryRequest struct {
+	versionInfo   string
+	nodeID        string
+	nodeCluster   string
+	resourceNames []string
+	typeURL       string
+	responseNonce string
+	errorDetail   string
+}
+
+// discoveryResponse envoy.service.discovery.v3.DiscoveryResponse
+type discoveryResponse struct {
+	versionInfo string
+	resources   [][]byte
+	typeURL     string
+	nonce       string
+}
+
+// clusterLoadAssignment envoy.config.endpoint.v3.ClusterLoadAssignment
+type clusterLoadAssignment struct {
+	clusterName string
+	endpoints   []lbEndpoint
+}
+
+// lbEndpoint 展开后的envoy.config.endpoint.v3.LbEndpoint，携带所属的locality
+type lbEndpoint struct {
+	host         string
+	port         uint32
+	healthStatus uint64
+	weight       uint32
+	metadata     map[string]string
+	region       string
+	zone         string
+	subZone      string
+}
+
+// routeConfiguration envoy.config.route.v3.RouteConfiguration
+type routeConfiguration struct {
+	name         string
+	virtualHosts []virtualHost
+}
+
+// virtualHost envoy.config.route.v3.VirtualHost
+type virtualHost struct {
+	name    string
+	domains []string
+	routes  []route
+}
+
+// route envoy.config.route.v3.Route，只保留匹配条件及转发的集群，重定向等其他动作的路由clusters为空
+type route struct {
+	name string
+	// path 精确路径、前缀或正则，由pathMatch区分
+	path      string
+	pathMatch int
+	headers   []headerMatcher
+	clusters  []clusterWeight
+}
+
+// headerMatcher envoy.config.route.v3.HeaderMatcher
+type headerMatcher struct {
+	name   string
+	match  int
+	value  string
+	invert bool
+}
+
+// clusterWeight 路由转发的集群及权重，单集群转发时权重为0
+type clusterWeight struct {
+	name   string
+	weight uint32
+}
+
+func (r *discoveryRequest) marshal() []byte {
+	var node []byte
+	node = appendString(node, 1, r.nodeID)
+	node = appendString(node, 2, r.nodeCluster)
+
+	var b []byte
+	b = appendString(b, 1, r.versionInfo)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendBytes(b, node)
+	for _, name := range r.resourceNames {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendString(b, name)
+	}
+	b = appendString(b, 4, r.typeURL)
+	b = appendString(b, 5, r.responseNonce)
+	if r.errorDetail != "" {
+		// google.rpc.Status: code=1, message=2，code固定为INVALID_ARGUMENT
+		var status []byte
+		status = protowire.AppendTag(status, 1, protowire.VarintType)
+		status = protowire.AppendVarint(status, 3)
+		status = appendString(status, 2, r.errorDetail)
+		b = protowire.AppendTag(b, 6, protowire.BytesType)
+		b = protowire.AppendBytes(b, status)
+	}
+	return b
+}
+
+func appendString(b []byte, num protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+func unmarshalDiscoveryResponse(b []byte) (*discoveryResponse, error) {
+	resp := &discoveryResponse{}
+	err := walkFields(b, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			resp.versionInfo = string(value)
+		case 2:
+			// google.protobuf.Any: type_url=1, value=2
+			return walkFields(value, func(num protowire.Number, _ protowire.Type, anyValue []byte, _ uint64) error {
+				if num == 2 {
+					resp.resources = append(resp.resources, anyValue)
+				}
+				return nil
+			})
+		case 4:
+			resp.typeURL = string(value)
+		case 5:
+			resp.nonce = string(value)
+		}
+		return nil
+	})
+	return resp, err
+}
+
+func unmarshalClusterLoadAssignment(b []byte) (*clusterLoadAssignment, error) {
+	cla := &clusterLoadAssignment{}
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			cla.clusterName = string(value)
+		case 2:
+			return unmarshalLocalityEndpoints(value, cla)
+		}
+		return nil
+	})
+	return cla, err
+}
+
+// unmarshalLocalityEndpoints 解析LocalityLbEndpoints: locality=1, lb_endpoints=2
+func unmarshalLocalityEndpoints(b []byte, cla *clusterLoadAssignment) error {
+	var region, zone, subZone string
+	var rawEndpoints [][]byte
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			return walkFields(value, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+				switch num {
+				case 1:
+					region = string(v)
+				case 2:
+					zone = string(v)
+				case 3:
+					subZone = string(v)
+				}
+				return nil
+			})
+		case 2:
+			rawEndpoints = append(rawEndpoints, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, raw := range rawEndpoints {
+		endpoint := lbEndpoint{region: region, zone: zone, subZone: subZone}
+		if err := unmarshalLbEndpoint(raw, &endpoint); err != nil {
+			return err
+		}
+		cla.endpoints = append(cla.endpoints, endpoint)
+	}
+	return nil
+}
+
+// unmarshalLbEndpoint 解析LbEndpoint: endpoint=1, health_status=2, metadata=3, load_balancing_weight=4
+func unmarshalLbEndpoint(b []byte, endpoint *lbEndpoint) error {
+	return walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, varint uint64) error {
+		switch num {
+		case 1:
+			// Endpoint.address=1 -> Address.socket_address=1 -> SocketAddress: address=2, port_value=3
+			return walkFields(value, func(num protowire.Number, _ protowire.Type, address []byte, _ uint64) error {
+				if num != 1 {
+					return nil
+				}
+				return walkFields(address, func(num protowire.Number, _ protowire.Type, socket []byte, _ uint64) error {
+					if num != 1 {
+						return nil
+					}
+					return walkFields(socket, func(num protowire.Number, _ protowire.Type, v []byte, port uint64) error {
+						switch num {
+						case 2:
+							endpoint.host = string(v)
+						case 3:
+							endpoint.port = uint32(port)
+						}
+						return nil
+					})
+				})
+			})
+		case 2:
+			endpoint.healthStatus = varint
+		case 3:
+			endpoint.metadata = unmarshalMetadata(value)
+		case 4:
+			// google.protobuf.UInt32Value: value=1
+			return walkFields(value, func(num protowire.Number, _ protowire.Type, _ []byte, weight uint64) error {
+				if num == 1 {
+					endpoint.weight = uint32(weight)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+// unmarshalMetadata 将envoy.config.core.v3.Metadata中各filter下的标量字段平铺为字符串map
+func unmarshalMetadata(b []byte) map[string]string {
+	metadata := make(map[string]string)
+	_ = walkFields(b, func(num protowire.Number, _ protowire.Type, entry []byte, _ uint64) error {
+		if num != 1 {
+			return nil
+		}
+		// map<string, Struct>，entry: key=1, value=2
+		return walkFields(entry, func(num protowire.Number, _ protowire.Type, structValue []byte, _ uint64) error {
+			if num != 2 {
+				return nil
+			}
+			unmarshalStruct(structValue, metadata)
+			return nil
+		})
+	})
+	return metadata
+}
+
+// unmarshalStruct 解析google.protobuf.Struct，fields=1 map<string, Value>
+func unmarshalStruct(b []byte, out map[string]string) {
+	_ = walkFields(b, func(num protowire.Number, _ protowire.Type, entry []byte, _ uint64) error {
+		if num != 1 {
+			return nil
+		}
+		var key string
+		var value string
+		var hasValue bool
+		_ = walkFields(entry, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+			switch num {
+			case 1:
+				key = string(v)
+			case 2:
+				value, hasValue = unmarshalScalarValue(v)
+			}
+			return nil
+		})
+		if key != "" && hasValue {
+			out[key] = value
+		}
+		return nil
+	})
+}
+
+// unmarshalScalarValue 解析google.protobuf.Value中的标量值: number=2, string=3, bool=4
+func unmarshalScalarValue(b []byte) (string, bool) {
+	var value string
+	var ok bool
+	_ = walkFields(b, func(num protowire.Number, _ protowire.Type, v []byte, varint uint64) error {
+		switch num {
+		case 2:
+			if len(v) == 8 {
+				value, ok = strconv.FormatFloat(decodeDouble(v), 'f', -1, 64), true
+			}
+		case 3:
+			value, ok = string(v), true
+		case 4:
+			value, ok = strconv.FormatBool(varint != 0), true
+		}
+		return nil
+	})
+	return value, ok
+}
+
+func decodeDouble(v []byte) float64 {
+	bits, _ := protowire.ConsumeFixed64(v)
+	return math.Float64frombits(bits)
+}
+
+// unmarshalClusterName 解析envoy.config.cluster.v3.Cluster的name字段
+func unmarshalClusterName(b []byte) (string, error) {
+	var name string
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		if num == 1 {
+			name = string(value)
+		}
+		return nil
+	})
+	return name, err
+}
+
+// unmarshalRouteConfiguration 解析RouteConfiguration: name=1, virtual_hosts=2
+func unmarshalRouteConfiguration(b []byte) (*routeConfiguration, error) {
+	rc := &routeConfiguration{}
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			rc.name = string(value)
+		case 2:
+			vh, err := unmarshalVirtualHost(value)
+			if err != nil {
+				return err
+			}
+			rc.virtualHosts = append(rc.virtualHosts, *vh)
+		}
+		return nil
+	})
+	return rc, err
+}
+
+// unmarshalVirtualHost 解析VirtualHost: name=1, domains=2, routes=3
+func unmarshalVirtualHost(b []byte) (*virtualHost, error) {
+	vh := &virtualHost{}
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			vh.name = string(value)
+		case 2:
+			vh.domains = append(vh.domains, string(value))
+		case 3:
+			r, err := unmarshalRoute(value)
+			if err != nil {
+				return err
+			}
+			vh.routes = append(vh.routes, *r)
+		}
+		return nil
+	})
+	return vh, err
+}
+
+// unmarshalRoute 解析Route: match=1, route=2, name=14
+func unmarshalRoute(b []byte) (*route, error) {
+	r := &route{pathMatch: matchPrefix}
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			return unmarshalRouteMatch(value, r)
+		case 2:
+			return unmarshalRouteAction(value, r)
+		case 14:
+			r.name = string(value)
+		}
+		return nil
+	})
+	return r, err
+}
+
+// unmarshalRouteMatch 解析RouteMatch: prefix=1, path=2, headers=6, safe_regex=10
+func unmarshalRouteMatch(b []byte, r *route) error {
+	return walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			r.path, r.pathMatch = string(value), matchPrefix
+		case 2:
+			r.path, r.pathMatch = string(value), matchExact
+		case 6:
+			header, err := unmarshalHeaderMatcher(value)
+			if err != nil {
+				return err
+			}
+			r.headers = append(r.headers, *header)
+		case 10:
+			regex, err := unmarshalRegexMatcher(value)
+			if err != nil {
+				return err
+			}
+			r.path, r.pathMatch = regex, matchRegex
+		}
+		return nil
+	})
+}
+
+// unmarshalHeaderMatcher 解析HeaderMatcher: name=1, exact_match=4, present_match=7, invert_match=8,
+// prefix_match=9, suffix_match=10, safe_regex_match=11, contains_match=12, string_match=13
+func unmarshalHeaderMatcher(b []byte) (*headerMatcher, error) {
+	header := &headerMatcher{}
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, varint uint64) error {
+		switch num {
+		case 1:
+			header.name = string(value)
+		case 4:
+			header.match, header.value = matchExact, string(value)
+		case 7:
+			header.match = matchPresent
+		case 8:
+			header.invert = varint != 0
+		case 9:
+			header.match, header.value = matchPrefix, string(value)
+		case 10:
+			header.match, header.value = matchSuffix, string(value)
+		case 11:
+			regex, err := unmarshalRegexMatcher(value)
+			if err != nil {
+				return err
+			}
+			header.match, header.value = matchRegex, regex
+		case 12:
+			header.match, header.value = matchContains, string(value)
+		case 13:
+			return unmarshalStringMatcher(value, header)
+		}
+		return nil
+	})
+	return header, err
+}
+
+// unmarshalStringMatcher 解析StringMatcher: exact=1, prefix=2, suffix=3, safe_regex=5, contains=7
+func unmarshalStringMatcher(b []byte, header *headerMatcher) error {
+	return walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			header.match, header.value = matchExact, string(value)
+		case 2:
+			header.match, header.value = matchPrefix, string(value)
+		case 3:
+			header.match, header.value = matchSuffix, string(value)
+		case 5:
+			regex, err := unmarshalRegexMatcher(value)
+			if err != nil {
+				return err
+			}
+			header.match, header.value = matchRegex, regex
+		case 7:
+			header.match, header.value = matchContains, string(value)
+		}
+		return nil
+	})
+}
+
+// unmarshalRegexMatcher 解析RegexMatcher的regex字段(2)
+func unmarshalRegexMatcher(b []byte) (string, error) {
+	var regex string
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		if num == 2 {
+			regex = string(value)
+		}
+		return nil
+	})
+	return regex, err
+}
+
+// unmarshalRouteAction 解析RouteAction: cluster=1, weighted_clusters=3 -> WeightedCluster.clusters=1
+// -> ClusterWeight: name=1, weight=2
+func unmarshalRouteAction(b []byte, r *route) error {
+	return walkFields(b, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			r.clusters = append(r.clusters, clusterWeight{name: string(value)})
+		case 3:
+			return walkFields(value, func(num protowire.Number, _ protowire.Type, entry []byte, _ uint64) error {
+				if num != 1 {
+					return nil
+				}
+				cluster := clusterWeight{}
+				err := walkFields(entry, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+					switch num {
+					case 1:
+						cluster.name = string(v)
+					case 2:
+						return walkFields(v, func(num protowire.Number, _ protowire.Type, _ []byte, weight uint64) error {
+							if num == 1 {
+								cluster.weight = uint32(weight)
+							}
+							return nil
+						})
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				r.clusters = append(r.clusters, cluster)
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+// walkFields 遍历消息的字段，长度类型的字段传入value，varint类型传入varint，fixed64类型以8字节value传入
+func walkFields(b []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("invalid tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		var value []byte
+		var varint uint64
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(b)
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			if n = protowire.ConsumeFieldValue(num, typ, b); n >= 0 {
+				value = b[:n]
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return fmt.Errorf("invalid field %d: %w", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+		if err := fn(num, typ, value, varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
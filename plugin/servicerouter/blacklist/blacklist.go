@@ -0,0 +1,105 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package blacklist
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// rawBlacklist 配置中心下发的黑名单内容
+type rawBlacklist struct {
+	// Regions 被隔离的地域
+	Regions []string `json:"regions"`
+	// Zones 被隔离的可用区
+	Zones []string `json:"zones"`
+	// Campuses 被隔离的园区
+	Campuses []string `json:"campuses"`
+	// Instances 被隔离的实例，取值为实例ID或者host:port
+	Instances []string `json:"instances"`
+}
+
+// blacklist 解析后的黑名单
+type blacklist struct {
+	// 版本号，每次更新递增，用于判断过滤结果缓存是否失效
+	version   uint64
+	regions   map[string]struct{}
+	zones     map[string]struct{}
+	campuses  map[string]struct{}
+	instances map[string]struct{}
+}
+
+// parseBlacklist 解析黑名单内容，内容为空时返回空黑名单
+func parseBlacklist(content string, version uint64) (*blacklist, error) {
+	raw := &rawBlacklist{}
+	if len(content) > 0 {
+		if err := json.Unmarshal([]byte(content), raw); err != nil {
+			return nil, err
+		}
+	}
+	return &blacklist{
+		version:   version,
+		regions:   toSet(raw.Regions),
+		zones:     toSet(raw.Zones),
+		campuses:  toSet(raw.Campuses),
+		instances: toSet(raw.Instances),
+	}, nil
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		if len(value) > 0 {
+			set[value] = struct{}{}
+		}
+	}
+	return set
+}
+
+// isEmpty 黑名单是否为空
+func (b *blacklist) isEmpty() bool {
+	return len(b.regions) == 0 && len(b.zones) == 0 && len(b.campuses) == 0 && len(b.instances) == 0
+}
+
+// contains 实例是否命中黑名单
+func (b *blacklist) contains(instance model.Instance) bool {
+	if hit(b.regions, instance.GetRegion()) || hit(b.zones, instance.GetZone()) ||
+		hit(b.campuses, instance.GetCampus()) || hit(b.instances, instance.GetId()) {
+		return true
+	}
+	if len(b.instances) == 0 {
+		return false
+	}
+	return hit(b.instances, fmt.Sprintf("%s:%d", instance.GetHost(), instance.GetPort()))
+}
+
+func hit(set map[string]struct{}, value string) bool {
+	if len(value) == 0 {
+		return false
+	}
+	_, ok := set[value]
+	return ok
+}
+
+// String 黑名单ToString
+func (b *blacklist) String() string {
+	return fmt.Sprintf("{version: %d, regions: %d, zones: %d, campuses: %d, instances: %d}",
+		b.version, len(b.regions), len(b.zones), len(b.campuses), len(b.instances))
+}
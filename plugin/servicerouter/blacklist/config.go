@@ -0,0 +1,71 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package blacklist
+
+import (
+	"errors"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DefaultNamespace 黑名单配置文件默认命名空间
+	DefaultNamespace = "Polaris"
+	// DefaultFileGroup 黑名单配置文件默认分组
+	DefaultFileGroup = "isolation"
+	// DefaultFileName 黑名单配置文件默认文件名
+	DefaultFileName = "blacklist.json"
+)
+
+// Config 隔离黑名单路由插件配置
+type Config struct {
+	// Namespace 黑名单配置文件所在的命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// FileGroup 黑名单配置文件所在的分组
+	FileGroup string `yaml:"fileGroup" json:"fileGroup"`
+	// FileName 黑名单配置文件名
+	FileName string `yaml:"fileName" json:"fileName"`
+}
+
+// Verify 校验配置是否OK
+func (c *Config) Verify() error {
+	var errs error
+	if len(c.Namespace) == 0 {
+		errs = multierror.Append(errs, errors.New("blacklistRouter.namespace is empty"))
+	}
+	if len(c.FileGroup) == 0 {
+		errs = multierror.Append(errs, errors.New("blacklistRouter.fileGroup is empty"))
+	}
+	if len(c.FileName) == 0 {
+		errs = multierror.Append(errs, errors.New("blacklistRouter.fileName is empty"))
+	}
+	return errs
+}
+
+// SetDefault 对关键值设置默认值
+func (c *Config) SetDefault() {
+	if len(c.Namespace) == 0 {
+		c.Namespace = DefaultNamespace
+	}
+	if len(c.FileGroup) == 0 {
+		c.FileGroup = DefaultFileGroup
+	}
+	if len(c.FileName) == 0 {
+		c.FileName = DefaultFileName
+	}
+}
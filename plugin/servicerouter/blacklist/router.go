@@ -0,0 +1,196 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package blacklist
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&BlacklistRouter{}, &Config{})
+}
+
+// BlacklistRouter 隔离黑名单路由插件
+// 黑名单以配置文件的形式从北极星配置中心获取并监听变更，命中黑名单的地域、可用区、园区或实例会被剔除，
+// 运维可以借此在所有客户端上一键隔离故障可用区，而无需逐个服务修改路由规则
+type BlacklistRouter struct {
+	*plugin.PluginBase
+	valueCtx model.ValueContext
+	cfg      *Config
+	// 配置中心拉取失败时的重试间隔
+	retryInterval time.Duration
+	// 当前生效的黑名单，类型为*blacklist
+	current atomic.Value
+	// 黑名单版本号
+	version uint64
+	// 剔除黑名单实例后的服务集群，key为model.ServiceKey，value为*filteredClusters
+	filtered sync.Map
+	// 插件销毁通知
+	done chan struct{}
+}
+
+// filteredClusters 剔除黑名单实例后的服务集群缓存
+type filteredClusters struct {
+	revision string
+	version  uint64
+	// 为nil表示没有实例命中黑名单，或者全部实例都命中黑名单
+	clusters model.ServiceClusters
+}
+
+// Type 插件类型
+func (g *BlacklistRouter) Type() common.Type {
+	return common.TypeServiceRouter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *BlacklistRouter) Name() string {
+	return config.DefaultServiceRouterBlacklist
+}
+
+// Init 初始化插件
+func (g *BlacklistRouter) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.valueCtx = ctx.ValueCtx
+	g.cfg = &Config{}
+	g.cfg.SetDefault()
+	cfgValue := ctx.Config.GetConsumer().GetServiceRouter().GetPluginConfig(g.Name())
+	if cfgValue != nil {
+		g.cfg = cfgValue.(*Config)
+	}
+	g.retryInterval = ctx.Config.GetGlobal().GetAPI().GetRetryInterval()
+	g.done = make(chan struct{})
+	g.current.Store(&blacklist{})
+	ctx.Plugins.RegisterEventSubscriber(common.OnContextStarted,
+		common.PluginEventHandler{Callback: g.onContextStarted})
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *BlacklistRouter) Destroy() error {
+	close(g.done)
+	return nil
+}
+
+// Enable 黑名单不为空时才启用
+func (g *BlacklistRouter) Enable(routeInfo *servicerouter.RouteInfo, clusters model.ServiceClusters) bool {
+	return !g.current.Load().(*blacklist).isEmpty()
+}
+
+// GetFilteredInstances 插件模式进行服务实例过滤，并返回过滤后的实例列表
+func (g *BlacklistRouter) GetFilteredInstances(routeInfo *servicerouter.RouteInfo,
+	clusters model.ServiceClusters, withinCluster *model.Cluster) (*servicerouter.RouteResult, error) {
+	result := servicerouter.PoolGetRouteResult(g.valueCtx)
+	if filtered := g.getFilteredClusters(clusters); nil != filtered {
+		clusters = filtered
+	}
+	outCluster := model.NewCluster(clusters, withinCluster)
+	outCluster.ClearClusterValue()
+	result.OutputCluster = outCluster
+	return result, nil
+}
+
+// getFilteredClusters 获取剔除黑名单实例后的服务集群，无需剔除时返回nil
+func (g *BlacklistRouter) getFilteredClusters(clusters model.ServiceClusters) model.ServiceClusters {
+	current := g.current.Load().(*blacklist)
+	svcInstances := clusters.GetServiceInstances()
+	svcKey := clusters.GetServiceKey()
+	revision := svcInstances.GetRevision()
+	if value, ok := g.filtered.Load(svcKey); ok {
+		cached := value.(*filteredClusters)
+		if cached.revision == revision && cached.version == current.version {
+			return cached.clusters
+		}
+	}
+	cached := &filteredClusters{revision: revision, version: current.version}
+	instances := svcInstances.GetInstances()
+	remains := make([]model.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if !current.contains(instance) {
+			remains = append(remains, instance)
+		}
+	}
+	switch {
+	case len(remains) == len(instances):
+	case len(remains) == 0:
+		// 全部实例都被隔离时不做剔除，避免黑名单配置错误导致服务完全不可用
+		log.GetBaseLogger().Warnf("[Router][Blacklist] all instances of %s are in blacklist %s, ignore it",
+			svcKey, current)
+	default:
+		cached.clusters = model.NewServiceClusters(model.NewDefaultServiceInstancesWithRegistryValue(model.ServiceInfo{
+			Service:   svcInstances.GetService(),
+			Namespace: svcInstances.GetNamespace(),
+			Metadata:  svcInstances.GetMetadata(),
+		}, svcInstances, remains))
+	}
+	g.filtered.Store(svcKey, cached)
+	return cached.clusters
+}
+
+// onContextStarted 上下文启动后异步拉取黑名单，避免阻塞SDK初始化
+func (g *BlacklistRouter) onContextStarted(event *common.PluginEvent) error {
+	go g.loadBlacklist()
+	return nil
+}
+
+// loadBlacklist 拉取黑名单配置文件并监听后续的变更，拉取失败时定期重试
+func (g *BlacklistRouter) loadBlacklist() {
+	fileReq := &model.GetConfigFileRequest{
+		Namespace: g.cfg.Namespace,
+		FileGroup: g.cfg.FileGroup,
+		FileName:  g.cfg.FileName,
+		Subscribe: true,
+	}
+	for {
+		configFile, err := g.valueCtx.GetEngine().SyncGetConfigFile(fileReq)
+		if err == nil {
+			g.updateBlacklist(configFile.GetContent())
+			configFile.AddChangeListener(func(event model.ConfigFileChangeEvent) {
+				g.updateBlacklist(event.NewValue)
+			})
+			return
+		}
+		log.GetBaseLogger().Errorf("[Router][Blacklist] fail to get blacklist file %s/%s/%s, retry after %v, err %v",
+			fileReq.Namespace, fileReq.FileGroup, fileReq.FileName, g.retryInterval, err)
+		select {
+		case <-g.done:
+			return
+		case <-time.After(g.retryInterval):
+		}
+	}
+}
+
+// updateBlacklist 解析黑名单内容，解析失败时保留原有黑名单
+func (g *BlacklistRouter) updateBlacklist(content string) {
+	parsed, err := parseBlacklist(content, atomic.AddUint64(&g.version, 1))
+	if err != nil {
+		log.GetBaseLogger().Errorf("[Router][Blacklist] fail to parse blacklist, keep the previous one, err %v", err)
+		return
+	}
+	g.current.Store(parsed)
+	log.GetBaseLogger().Infof("[Router][Blacklist] blacklist updated to %s", parsed)
+}
@@ -0,0 +1,73 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package canarypercent
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// DefaultMetaKey 实例元数据中标识金丝雀版本的key
+	DefaultMetaKey = model.CanaryMetaKey
+)
+
+// Config 金丝雀按比例分流路由插件配置
+type Config struct {
+	// MetaKey 实例元数据中标识金丝雀版本的key，值为true表示金丝雀版本，false或者不存在表示稳定版本
+	MetaKey string `yaml:"metaKey" json:"metaKey"`
+	// Percent 默认分流到金丝雀版本的流量百分比，取值[0, 100]，为0时只对services中配置的服务生效
+	Percent int `yaml:"percent" json:"percent"`
+	// Services 按服务配置的分流百分比，key的格式为 <命名空间>/<服务名>
+	Services map[string]int `yaml:"services" json:"services"`
+}
+
+// Verify 校验配置是否OK
+func (c *Config) Verify() error {
+	var errs error
+	if len(c.MetaKey) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("canaryPercentRouter.metaKey can not be empty"))
+	}
+	if c.Percent < 0 || c.Percent > totalBuckets {
+		errs = multierror.Append(errs, fmt.Errorf("canaryPercentRouter.percent must be in [0, 100]"))
+	}
+	for svc, percent := range c.Services {
+		if percent < 0 || percent > totalBuckets {
+			errs = multierror.Append(errs,
+				fmt.Errorf("canaryPercentRouter.services.%s must be in [0, 100]", svc))
+		}
+	}
+	return errs
+}
+
+// SetDefault 对关键值设置默认值
+func (c *Config) SetDefault() {
+	if len(c.MetaKey) == 0 {
+		c.MetaKey = DefaultMetaKey
+	}
+}
+
+// getPercent 获取服务的分流百分比，服务未配置且默认比例为0时返回false
+func (c *Config) getPercent(namespace string, service string) (int, bool) {
+	if percent, ok := c.Services[namespace+"/"+service]; ok {
+		return percent, true
+	}
+	return c.Percent, c.Percent > 0
+}
@@ -0,0 +1,166 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package canarypercent
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/algorithm/hash"
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+const (
+	canaryValue = "true"
+	stableValue = "false"
+	// 分流的总桶数，比例以百分比配置
+	totalBuckets = 100
+)
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&PercentRouter{}, &Config{})
+}
+
+// PercentRouter 金丝雀按比例分流路由插件
+// 实例通过canary=true/false区分金丝雀版本与稳定版本，按主调传入的hash key稳定地将指定比例的流量分到金丝雀版本
+type PercentRouter struct {
+	*plugin.PluginBase
+	valueCtx     model.ValueContext
+	cfg          *Config
+	hashFunc     hash.HashFuncWithSeed
+	scalableRand *rand.ScalableRand
+}
+
+// Type 插件类型
+func (g *PercentRouter) Type() common.Type {
+	return common.TypeServiceRouter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *PercentRouter) Name() string {
+	return config.DefaultServiceRouterCanaryPercent
+}
+
+// Init 初始化插件
+func (g *PercentRouter) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.valueCtx = ctx.ValueCtx
+	g.scalableRand = rand.NewScalableRand()
+	g.cfg = &Config{}
+	g.cfg.SetDefault()
+	cfgValue := ctx.Config.GetConsumer().GetServiceRouter().GetPluginConfig(g.Name())
+	if cfgValue != nil {
+		g.cfg = cfgValue.(*Config)
+	}
+	var err error
+	if g.hashFunc, err = hash.GetHashFunc(hash.DefaultHashFuncName); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *PercentRouter) Destroy() error {
+	return nil
+}
+
+// Enable 目标服务配置了分流比例才启用
+func (g *PercentRouter) Enable(routeInfo *servicerouter.RouteInfo, clusters model.ServiceClusters) bool {
+	_, ok := g.cfg.getPercent(routeInfo.DestService.GetNamespace(), routeInfo.DestService.GetService())
+	return ok
+}
+
+// GetFilteredInstances 插件模式进行服务实例过滤，并返回过滤后的实例列表
+func (g *PercentRouter) GetFilteredInstances(routeInfo *servicerouter.RouteInfo,
+	clusters model.ServiceClusters, withinCluster *model.Cluster) (*servicerouter.RouteResult, error) {
+	percent, _ := g.cfg.getPercent(routeInfo.DestService.GetNamespace(), routeInfo.DestService.GetService())
+	toCanary := g.selectBucket(routeInfo.CanaryHashKey) < percent
+
+	result := servicerouter.PoolGetRouteResult(g.valueCtx)
+	if toCanary {
+		if cls := g.getCanaryCluster(clusters, withinCluster); nil != cls {
+			result.OutputCluster = cls
+			return result, nil
+		}
+		if cls := g.getStableCluster(clusters, withinCluster); nil != cls {
+			result.OutputCluster = cls
+			result.Status = servicerouter.DegradeToNotCanary
+			return result, nil
+		}
+	} else {
+		if cls := g.getStableCluster(clusters, withinCluster); nil != cls {
+			result.OutputCluster = cls
+			return result, nil
+		}
+		if cls := g.getCanaryCluster(clusters, withinCluster); nil != cls {
+			result.OutputCluster = cls
+			result.Status = servicerouter.DegradeToCanary
+			return result, nil
+		}
+	}
+	// 两个版本都没有可用实例，交由后续路由兜底
+	result.OutputCluster = model.NewCluster(clusters, withinCluster)
+	return result, nil
+}
+
+// selectBucket 计算请求落入的分流桶，相同的hash key总是落在同一个桶，未传入key时随机选择
+func (g *PercentRouter) selectBucket(hashKey string) int {
+	if len(hashKey) == 0 {
+		return g.scalableRand.Intn(totalBuckets)
+	}
+	hashValue, err := g.hashFunc([]byte(hashKey), 0)
+	if err != nil {
+		log.GetBaseLogger().Errorf("[CanaryPercentRouter] fail to hash key %s: %v", hashKey, err)
+		return g.scalableRand.Intn(totalBuckets)
+	}
+	return int(hashValue % totalBuckets)
+}
+
+// getCanaryCluster 获取金丝雀版本的实例集群，无可用实例时返回nil
+func (g *PercentRouter) getCanaryCluster(clusters model.ServiceClusters, withinCluster *model.Cluster) *model.Cluster {
+	cls := model.NewCluster(clusters, withinCluster)
+	cls.AddMetadata(g.cfg.MetaKey, canaryValue)
+	cls.ReloadComposeMetaValue()
+	if cls.GetClusterValue().GetInstancesSet(false, true).Count() > 0 {
+		return cls
+	}
+	cls.PoolPut()
+	return nil
+}
+
+// getStableCluster 获取稳定版本的实例集群，未打金丝雀标签的实例也视为稳定版本，无可用实例时返回nil
+func (g *PercentRouter) getStableCluster(clusters model.ServiceClusters, withinCluster *model.Cluster) *model.Cluster {
+	cls := model.NewCluster(clusters, withinCluster)
+	cls.AddMetadata(g.cfg.MetaKey, stableValue)
+	cls.ReloadComposeMetaValue()
+	if cls.GetClusterValue().GetInstancesSet(false, true).Count() > 0 {
+		return cls
+	}
+	cls.PoolPut()
+	notContainCls := model.NewCluster(clusters, withinCluster)
+	notContainCls.AddMetadata(g.cfg.MetaKey, "")
+	notContainCls.ReloadComposeMetaValue()
+	if notContainCls.GetNotContainMetaKeyClusterValue().GetInstancesSet(false, true).Count() > 0 {
+		return notContainCls
+	}
+	notContainCls.PoolPut()
+	return nil
+}
@@ -0,0 +1,79 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dstmeta
+
+import (
+	"fmt"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// FailOverTypeNone 不启用兜底，元数据匹配不到时返回错误
+	FailOverTypeNone = "none"
+	// FailOverTypeAll 兜底到全部可用实例
+	FailOverTypeAll = "all"
+	// FailOverTypeNotContainMetaKey 兜底到不带该元数据key的实例
+	FailOverTypeNotContainMetaKey = "notContainMetaKey"
+	// FailOverTypeCustomMeta 兜底到指定元数据的实例
+	FailOverTypeCustomMeta = "customMeta"
+)
+
+var failOverTypes = map[string]model.FailOverHandler{
+	FailOverTypeAll:               model.GetOneHealth,
+	FailOverTypeNotContainMetaKey: model.NotContainMetaKey,
+	FailOverTypeCustomMeta:        model.CustomMeta,
+}
+
+// Config 元数据路由插件配置，请求中未开启EnableFailOverDefaultMeta时使用这里的兜底策略
+type Config struct {
+	// FailOverType 元数据匹配不到时的兜底策略，取值为none、all、notContainMetaKey、customMeta
+	FailOverType string `yaml:"failOverType" json:"failOverType"`
+	// FailOverMeta 兜底到的元数据，仅failOverType为customMeta时生效
+	FailOverMeta map[string]string `yaml:"failOverMeta" json:"failOverMeta"`
+}
+
+// Verify 校验配置是否OK
+func (c *Config) Verify() error {
+	if c.FailOverType == FailOverTypeNone {
+		return nil
+	}
+	if _, ok := failOverTypes[c.FailOverType]; !ok {
+		return fmt.Errorf("dstMetaRouter.failOverType %s is invalid", c.FailOverType)
+	}
+	if c.FailOverType == FailOverTypeCustomMeta {
+		return validateEmptyKey(c.FailOverMeta)
+	}
+	return nil
+}
+
+// SetDefault 对关键值设置默认值
+func (c *Config) SetDefault() {
+	if len(c.FailOverType) == 0 {
+		c.FailOverType = FailOverTypeNone
+	}
+}
+
+// getFailOverMeta 转换为兜底策略，未启用时返回nil
+func (c *Config) getFailOverMeta() *model.FailOverDefaultMetaConfig {
+	handler, ok := failOverTypes[c.FailOverType]
+	if !ok {
+		return nil
+	}
+	return &model.FailOverDefaultMetaConfig{Type: handler, Meta: c.FailOverMeta}
+}
@@ -34,6 +34,8 @@ type InstancesFilter struct {
 	percentOfMinInstances float64
 	valueCtx              model.ValueContext
 	recoverAll            bool
+	// 插件配置的兜底策略，未配置时为nil
+	failOverMeta *model.FailOverDefaultMetaConfig
 }
 
 // Type 插件类型
@@ -53,6 +55,10 @@ func (g *InstancesFilter) Init(ctx *plugin.InitContext) error {
 	g.percentOfMinInstances = ctx.Config.GetConsumer().GetServiceRouter().GetPercentOfMinInstances()
 	g.recoverAll = ctx.Config.GetConsumer().GetServiceRouter().IsEnableRecoverAll()
 	g.valueCtx = ctx.ValueCtx
+	cfgValue := ctx.Config.GetConsumer().GetServiceRouter().GetPluginConfig(g.Name())
+	if cfgValue != nil {
+		g.failOverMeta = cfgValue.(*Config).getFailOverMeta()
+	}
 	return nil
 }
 
@@ -76,8 +82,8 @@ func (g *InstancesFilter) GetFilteredInstances(
 		}
 
 		targetCluster.PoolPut()
-		if routeInfo.EnableFailOverDefaultMeta {
-			targetCluster, err := g.failOverDefaultMetaHandler(clusters, withinCluster, routeInfo)
+		if failOverMeta := g.getFailOverMeta(routeInfo); nil != failOverMeta {
+			targetCluster, err := g.failOverDefaultMetaHandler(clusters, withinCluster, routeInfo, failOverMeta)
 			if err != nil {
 				return nil, err
 			}
@@ -91,14 +97,23 @@ func (g *InstancesFilter) GetFilteredInstances(
 	return g.getResult(targetCluster), nil
 }
 
+// getFailOverMeta 获取兜底策略，请求中的策略优先于插件配置，均未启用时返回nil
+func (g *InstancesFilter) getFailOverMeta(routeInfo *servicerouter.RouteInfo) *model.FailOverDefaultMetaConfig {
+	if routeInfo.EnableFailOverDefaultMeta {
+		return &routeInfo.FailOverDefaultMeta
+	}
+	return g.failOverMeta
+}
+
 // 元数据匹配不到时处理自定义匹配规则
-func (g *InstancesFilter) failOverDefaultMetaHandler(clusters model.ServiceClusters, withinCluster *model.Cluster, routeInfo *servicerouter.RouteInfo) (*model.Cluster, error) {
-	if routeInfo.FailOverDefaultMeta.Type == model.GetOneHealth {
+func (g *InstancesFilter) failOverDefaultMetaHandler(clusters model.ServiceClusters, withinCluster *model.Cluster,
+	routeInfo *servicerouter.RouteInfo, failOverMeta *model.FailOverDefaultMetaConfig) (*model.Cluster, error) {
+	if failOverMeta.Type == model.GetOneHealth {
 		return g.getOneHealthHandler(clusters, withinCluster, routeInfo)
-	} else if routeInfo.FailOverDefaultMeta.Type == model.NotContainMetaKey {
+	} else if failOverMeta.Type == model.NotContainMetaKey {
 		return g.notContainMetaKeyHandler(clusters, withinCluster, routeInfo)
-	} else if routeInfo.FailOverDefaultMeta.Type == model.CustomMeta {
-		return g.customMetaHandler(clusters, withinCluster, routeInfo)
+	} else if failOverMeta.Type == model.CustomMeta {
+		return g.customMetaHandler(clusters, withinCluster, routeInfo, failOverMeta.Meta)
 	}
 
 	return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, fmt.Errorf("failOverDefaultMeta Type not match"), "fail to enable failOverDefaultMeta")
@@ -121,11 +136,12 @@ func (g *InstancesFilter) notContainMetaKeyHandler(clusters model.ServiceCluster
 }
 
 // 匹配自定义meta
-func (g *InstancesFilter) customMetaHandler(clusters model.ServiceClusters, withinCluster *model.Cluster, routeInfo *servicerouter.RouteInfo) (*model.Cluster, error) {
-	if err := validateEmptyKey(routeInfo.FailOverDefaultMeta.Meta); err != nil {
+func (g *InstancesFilter) customMetaHandler(clusters model.ServiceClusters, withinCluster *model.Cluster,
+	routeInfo *servicerouter.RouteInfo, meta map[string]string) (*model.Cluster, error) {
+	if err := validateEmptyKey(meta); err != nil {
 		return nil, err
 	}
-	targetCluster := g.getTargetCluster(clusters, withinCluster, routeInfo.FailOverDefaultMeta.Meta)
+	targetCluster := g.getTargetCluster(clusters, withinCluster, meta)
 	clusterValue := targetCluster.GetClusterValue()
 	instSet := g.getInstSet(clusterValue)
 	return targetCluster, g.validateInstSet(instSet, routeInfo)
@@ -190,7 +206,7 @@ func validateEmptyKey(m map[string]string) error {
 
 // init 注册插件
 func init() {
-	plugin.RegisterPlugin(&InstancesFilter{})
+	plugin.RegisterConfigurablePlugin(&InstancesFilter{}, &Config{})
 }
 
 // Enable 是否需要启动规则路由
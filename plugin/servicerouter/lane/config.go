@@ -0,0 +1,47 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package lane
+
+import (
+	"errors"
+)
+
+const (
+	// DefaultInstanceLaneKey 实例元数据中标识所属泳道的key
+	DefaultInstanceLaneKey = "lane"
+)
+
+// Config 泳道路由插件配置
+type Config struct {
+	// InstanceLaneKey 实例元数据中标识所属泳道的key，值为泳道规则的defaultLabelValue
+	InstanceLaneKey string `yaml:"instanceLaneKey" json:"instanceLaneKey"`
+}
+
+// Verify 校验配置是否OK
+func (c *Config) Verify() error {
+	if len(c.InstanceLaneKey) == 0 {
+		return errors.New("instanceLaneKey of laneRouter can not be empty")
+	}
+	return nil
+}
+
+// SetDefault 对关键值设置默认值
+func (c *Config) SetDefault() {
+	if len(c.InstanceLaneKey) == 0 {
+		c.InstanceLaneKey = DefaultInstanceLaneKey
+	}
+}
@@ -0,0 +1,258 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package lane
+
+import (
+	"fmt"
+	"sort"
+
+	regexp "github.com/dlclark/regexp2"
+	"github.com/modern-go/reflect2"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/match"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&LaneRouter{}, &Config{})
+}
+
+// LaneRouter 泳道路由插件
+// 带有泳道标签的请求只会路由到对应泳道的实例，泳道内无可用实例时降级到基线实例（未打泳道标签的实例）
+type LaneRouter struct {
+	*plugin.PluginBase
+	valueCtx model.ValueContext
+	cfg      *Config
+}
+
+// Type 插件类型
+func (g *LaneRouter) Type() common.Type {
+	return common.TypeServiceRouter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *LaneRouter) Name() string {
+	return config.DefaultServiceRouterLane
+}
+
+// Init 初始化插件
+func (g *LaneRouter) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.valueCtx = ctx.ValueCtx
+	g.cfg = &Config{}
+	g.cfg.SetDefault()
+	cfgValue := ctx.Config.GetConsumer().GetServiceRouter().GetPluginConfig(g.Name())
+	if cfgValue != nil {
+		g.cfg = cfgValue.(*Config)
+	}
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *LaneRouter) Destroy() error {
+	return nil
+}
+
+// Enable 是否需要启动泳道路由
+func (g *LaneRouter) Enable(routeInfo *servicerouter.RouteInfo, clusters model.ServiceClusters) bool {
+	return nil != g.valueCtx.GetEngine()
+}
+
+// GetFilteredInstances 插件模式进行服务实例过滤，并返回过滤后的实例列表
+func (g *LaneRouter) GetFilteredInstances(routeInfo *servicerouter.RouteInfo,
+	clusters model.ServiceClusters, withinCluster *model.Cluster) (*servicerouter.RouteResult, error) {
+	groups := g.getLaneGroups(routeInfo.DestService)
+	if len(groups) == 0 {
+		return g.getResult(model.NewCluster(clusters, withinCluster)), nil
+	}
+	var srcLabels map[string]string
+	if !reflect2.IsNil(routeInfo.SourceService) {
+		srcLabels = routeInfo.SourceService.GetMetadata()
+	}
+	tag, ok := model.GetLaneTag(srcLabels)
+	if !ok {
+		// 入口流量染色
+		tag, ok = g.dyeTraffic(groups, srcLabels)
+	}
+	if !ok {
+		return g.getResult(g.getBaselineCluster(clusters, withinCluster)), nil
+	}
+	rule := findLaneRule(groups, tag)
+	if nil == rule {
+		log.GetBaseLogger().Debugf("[LaneRouter] lane %s not found for service %s(%s), route to baseline",
+			tag, routeInfo.DestService.GetService(), routeInfo.DestService.GetNamespace())
+		return g.getResult(g.getBaselineCluster(clusters, withinCluster)), nil
+	}
+	laneCluster := model.NewCluster(clusters, withinCluster)
+	laneCluster.AddMetadata(g.cfg.InstanceLaneKey, rule.GetDefaultLabelValue())
+	laneCluster.ReloadComposeMetaValue()
+	if laneCluster.GetClusterValue().GetInstancesSet(true, true).Count() > 0 {
+		return g.getResult(laneCluster), nil
+	}
+	laneCluster.PoolPut()
+	if rule.GetMatchMode() == apitraffic.LaneRule_STRICT {
+		errorText := fmt.Sprintf("lane %s has no instance for service %s(namespace %s)",
+			tag, routeInfo.DestService.GetService(), routeInfo.DestService.GetNamespace())
+		log.GetBaseLogger().Errorf(errorText)
+		return nil, model.NewSDKError(model.ErrCodeRouteRuleNotMatch, nil, errorText)
+	}
+	return g.getResult(g.getBaselineCluster(clusters, withinCluster)), nil
+}
+
+// getLaneGroups 获取与目标服务相关的泳道组
+func (g *LaneRouter) getLaneGroups(dstService model.ServiceMetadata) []*apitraffic.LaneGroup {
+	engine := g.valueCtx.GetEngine()
+	resp, err := engine.SyncGetServiceRule(model.EventLane, &model.GetServiceRuleRequest{
+		Namespace: dstService.GetNamespace(),
+		Service:   dstService.GetService(),
+	})
+	if err != nil {
+		log.GetBaseLogger().Errorf("[LaneRouter] fail to get lane rule for service %s(%s): %v",
+			dstService.GetService(), dstService.GetNamespace(), err)
+		return nil
+	}
+	laneValue, ok := resp.GetValue().(*apiservice.DiscoverResponse)
+	if !ok || nil == laneValue {
+		return nil
+	}
+	svcKey := &model.ServiceKey{Namespace: dstService.GetNamespace(), Service: dstService.GetService()}
+	var groups []*apitraffic.LaneGroup
+	for _, group := range laneValue.GetLanes() {
+		for _, dst := range group.GetDestinations() {
+			if match.MatchService(svcKey, dst.GetNamespace(), dst.GetService()) {
+				groups = append(groups, group)
+				break
+			}
+		}
+	}
+	return groups
+}
+
+// dyeTraffic 按染色规则的优先级匹配主调的流量标签，返回命中规则对应的泳道标签
+func (g *LaneRouter) dyeTraffic(groups []*apitraffic.LaneGroup, srcLabels map[string]string) (string, bool) {
+	type candidate struct {
+		group string
+		rule  *apitraffic.LaneRule
+	}
+	var candidates []candidate
+	for _, group := range groups {
+		for _, rule := range group.GetRules() {
+			if rule.GetEnable() {
+				candidates = append(candidates, candidate{group: group.GetName(), rule: rule})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].rule.GetPriority() < candidates[j].rule.GetPriority()
+	})
+	for _, c := range candidates {
+		if matchTrafficRule(c.rule.GetTrafficMatchRule(), srcLabels) {
+			return model.BuildLaneTag(c.group, c.rule.GetName()), true
+		}
+	}
+	return "", false
+}
+
+// getBaselineCluster 获取基线实例集群，基线实例为不带泳道标签的实例，基线为空时使用全部实例
+func (g *LaneRouter) getBaselineCluster(clusters model.ServiceClusters, withinCluster *model.Cluster) *model.Cluster {
+	baseline := model.NewCluster(clusters, withinCluster)
+	baseline.AddMetadata(g.cfg.InstanceLaneKey, "")
+	baseline.ReloadComposeMetaValue()
+	if baseline.GetNotContainMetaKeyClusterValue().GetInstancesSet(true, true).Count() > 0 {
+		return baseline
+	}
+	baseline.PoolPut()
+	return model.NewCluster(clusters, withinCluster)
+}
+
+func (g *LaneRouter) getResult(cluster *model.Cluster) *servicerouter.RouteResult {
+	result := servicerouter.PoolGetRouteResult(g.valueCtx)
+	result.OutputCluster = cluster
+	return result
+}
+
+// findLaneRule 通过泳道标签查找泳道规则
+func findLaneRule(groups []*apitraffic.LaneGroup, tag string) *apitraffic.LaneRule {
+	groupName, laneName, ok := model.ParseLaneTag(tag)
+	if !ok {
+		return nil
+	}
+	for _, group := range groups {
+		if group.GetName() != groupName {
+			continue
+		}
+		for _, rule := range group.GetRules() {
+			if rule.GetName() == laneName && rule.GetEnable() {
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+// matchTrafficRule 染色规则的所有参数均匹配时才认为命中
+func matchTrafficRule(rule *apitraffic.TrafficMatchRule, srcLabels map[string]string) bool {
+	arguments := rule.GetArguments()
+	if len(arguments) == 0 {
+		return false
+	}
+	for _, argument := range arguments {
+		value, ok := srcLabels[toLabelKey(argument)]
+		if !ok {
+			return false
+		}
+		matched := match.MatchString(value, argument.GetValue(), func(s string) *regexp.Regexp {
+			matchExp, err := regexp.Compile(s, regexp.RE2)
+			if err != nil {
+				return nil
+			}
+			return matchExp
+		})
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// toLabelKey 将染色规则中的参数转换为流量标签的key
+func toLabelKey(argument *apitraffic.SourceMatch) string {
+	switch argument.GetType() {
+	case apitraffic.SourceMatch_METHOD:
+		return model.LabelKeyMethod
+	case apitraffic.SourceMatch_HEADER:
+		return model.LabelKeyHeader + argument.GetKey()
+	case apitraffic.SourceMatch_QUERY:
+		return model.LabelKeyQuery + argument.GetKey()
+	case apitraffic.SourceMatch_CALLER_IP:
+		return model.LabelKeyCallerIp
+	case apitraffic.SourceMatch_PATH:
+		return model.LabelKeyPath
+	case apitraffic.SourceMatch_COOKIE:
+		return model.LabelKeyCookie + argument.GetKey()
+	default:
+		return argument.GetKey()
+	}
+}
@@ -30,6 +30,10 @@ type nearbyConfig struct {
 	StrictNearby                    bool   `yaml:"strictNearby" json:"strictNearby"`
 	EnableDegradeByUnhealthyPercent *bool  `yaml:"enableDegradeByUnhealthyPercent" json:"enableDegradeByUnhealthyPercent"`
 	UnhealthyPercentToDegrade       int    `yaml:"unhealthyPercentToDegrade" json:"unhealthyPercentToDegrade"`
+	// 匹配级别的健康实例比例低于该百分比时，按SpillPercent将部分流量溢出到上一级别，为0时不开启溢出
+	SpillHealthyPercent int `yaml:"spillHealthyPercent" json:"spillHealthyPercent"`
+	// 溢出到上一级别的流量百分比
+	SpillPercent int `yaml:"spillPercent" json:"spillPercent"`
 }
 
 // SetMatchLevel 设置配置级别
@@ -95,6 +99,26 @@ func (n *nearbyConfig) SetUnhealthyPercentToDegrade(u int) {
 	n.UnhealthyPercentToDegrade = u
 }
 
+// GetSpillHealthyPercent 获取触发流量溢出的健康实例百分比
+func (n *nearbyConfig) GetSpillHealthyPercent() int {
+	return n.SpillHealthyPercent
+}
+
+// SetSpillHealthyPercent 设置触发流量溢出的健康实例百分比
+func (n *nearbyConfig) SetSpillHealthyPercent(p int) {
+	n.SpillHealthyPercent = p
+}
+
+// GetSpillPercent 获取溢出到上一级别的流量百分比
+func (n *nearbyConfig) GetSpillPercent() int {
+	return n.SpillPercent
+}
+
+// SetSpillPercent 设置溢出到上一级别的流量百分比
+func (n *nearbyConfig) SetSpillPercent(p int) {
+	n.SpillPercent = p
+}
+
 // SetDefault 设置默认值
 func (n *nearbyConfig) SetDefault() {
 	if n.MatchLevel == "" {
@@ -136,5 +160,13 @@ func (n *nearbyConfig) Verify() error {
 		return fmt.Errorf("unhealthyPercentToDegrade must be in the range of (0,100],"+
 			" but provided value is %v", n.UnhealthyPercentToDegrade)
 	}
+	if n.SpillHealthyPercent < 0 || n.SpillHealthyPercent > 100 {
+		return fmt.Errorf("spillHealthyPercent must be in the range of [0,100],"+
+			" but provided value is %v", n.SpillHealthyPercent)
+	}
+	if n.SpillHealthyPercent > 0 && (n.SpillPercent <= 0 || n.SpillPercent > 100) {
+		return fmt.Errorf("spillPercent must be in the range of (0,100] when spill is enabled,"+
+			" but provided value is %v", n.SpillPercent)
+	}
 	return nil
 }
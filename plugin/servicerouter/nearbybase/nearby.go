@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -43,6 +44,7 @@ type NearbyBasedInstancesFilter struct {
 	maxMatchLevel         int
 	unHealthyRatio        float64
 	locationReadyTimeout  time.Duration
+	scalableRand          *rand.ScalableRand
 }
 
 // Type 插件类型
@@ -71,6 +73,7 @@ func (g *NearbyBasedInstancesFilter) Init(ctx *plugin.InitContext) error {
 	g.matchLevel = nearbyLevels[g.cfg.MatchLevel]
 	g.maxMatchLevel = nearbyLevels[g.cfg.MaxMatchLevel]
 	g.unHealthyRatio = float64(g.cfg.UnhealthyPercentToDegrade) / 100
+	g.scalableRand = rand.NewScalableRand()
 	g.locationReadyTimeout = (ctx.Config.GetGlobal().GetAPI().GetRetryInterval() +
 		ctx.Config.GetGlobal().GetServerConnector().GetConnectTimeout()) *
 		time.Duration(ctx.Config.GetGlobal().GetAPI().GetMaxRetryTimes()+1)
@@ -293,12 +296,49 @@ finally:
 	if outCluster.MissLocationInstances {
 		return nil, g.misMatchError(location, outCluster)
 	}
+	if spillCluster, spillLevel := g.trySpill(clusters, withinCluster, outCluster, location,
+		finalLevel, maxMatchLevel); nil != spillCluster {
+		outCluster = spillCluster
+		finalLevel = spillLevel
+	}
 	result := servicerouter.PoolGetRouteResult(g.valueCtx)
 	result.OutputCluster = outCluster
 	result.Status = checkNearbyStatus(matchLevel, finalLevel)
 	return result, nil
 }
 
+// trySpill 匹配级别的健康实例比例低于阈值时，按比例将请求溢出到上一级别，未发生溢出时返回nil
+// 溢出的cluster为单次请求新建，不会写入就近路由的缓存
+func (g *NearbyBasedInstancesFilter) trySpill(clusters model.ServiceClusters, withinCluster *model.Cluster,
+	outCluster *model.Cluster, location *model.Location, finalLevel int, maxMatchLevel int) (*model.Cluster, int) {
+	if g.cfg.SpillHealthyPercent <= 0 || finalLevel <= maxMatchLevel || finalLevel <= priorityLevelAll {
+		return nil, finalLevel
+	}
+	var count nearbyLevelInstanceCount
+	getClusterInstanceCount(outCluster, false, &count)
+	if count.allCount == 0 || count.healthCount*100 >= count.allCount*g.cfg.SpillHealthyPercent {
+		return nil, finalLevel
+	}
+	if g.scalableRand.Intn(100) >= g.cfg.SpillPercent {
+		return nil, finalLevel
+	}
+	spillLevel := finalLevel - 1
+	spillCluster := model.NewCluster(clusters, withinCluster)
+	switch spillLevel {
+	case priorityLevelZone:
+		spillCluster.Location.Zone = location.Zone
+		fallthrough
+	case priorityLevelRegion:
+		spillCluster.Location.Region = location.Region
+	}
+	spillCluster.ClearClusterValue()
+	if spillCluster.GetClusterValue().GetInstancesSet(false, false).Count() == 0 {
+		spillCluster.PoolPut()
+		return nil, finalLevel
+	}
+	return spillCluster, spillLevel
+}
+
 // 返回地域匹配错误
 func (g *NearbyBasedInstancesFilter) misMatchError(location *model.Location, outCluster *model.Cluster) model.SDKError {
 	maxLevel := g.cfg.MaxMatchLevel
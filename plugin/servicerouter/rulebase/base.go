@@ -21,7 +21,6 @@ import (
 	"os"
 	"sort"
 
-	regexp "github.com/dlclark/regexp2"
 	"github.com/modern-go/reflect2"
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
@@ -29,8 +28,8 @@ import (
 
 	"github.com/polarismesh/polaris-go/pkg/algorithm/match"
 	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
-	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
 	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
 )
 
@@ -161,16 +160,18 @@ func (g *RuleBasedInstancesFilter) matchSourceMetadata(ruleMeta map[string]*apim
 			if !exist {
 				return false, "", nil
 			}
-			allMetaMatched = match.MatchString(srcMetaValue, &apimodel.MatchString{
-				Type:  ruleMetaValue.Type,
-				Value: wrapperspb.String(rawMetaValue),
-			}, func(s string) *regexp.Regexp {
-				matchExp, err := regexp.Compile(rawMetaValue, regexp.RE2)
+			if ruleMetaValue.Type == apimodel.MatchString_REGEX && !match.IsMatchAll(rawMetaValue) {
+				matcher, err := ruleCache.GetMatcher(rawMetaValue)
 				if err != nil {
-					return nil
+					return false, rawMetaValue, err
 				}
-				return matchExp
-			})
+				allMetaMatched = matcher.MatchString(srcMetaValue)
+			} else {
+				allMetaMatched = match.MatchString(srcMetaValue, &apimodel.MatchString{
+					Type:  ruleMetaValue.Type,
+					Value: wrapperspb.String(rawMetaValue),
+				}, nil)
+			}
 		} else {
 			// 假如不存在规则要求的KEY，则直接返回匹配失败
 			allMetaMatched = false
@@ -182,8 +183,11 @@ func (g *RuleBasedInstancesFilter) matchSourceMetadata(ruleMeta map[string]*apim
 	return allMetaMatched, "", nil
 }
 
-// 获取规则variable
+// 获取规则variable，优先通过上下文中的变量解析链获取，解析链变更后下次路由即按新值匹配
 func (g *RuleBasedInstancesFilter) getVariable(envKey string) (string, bool) {
+	if chain := g.valueCtx.GetVariableResolver(); chain != nil {
+		return chain.Resolve(envKey)
+	}
 	value, exist := g.systemCfg.GetVariable(envKey)
 	if !exist {
 		value = os.Getenv(envKey)
@@ -290,7 +294,7 @@ func (g *RuleBasedInstancesFilter) matchSource(sources []*apitraffic.Source, rou
 
 // 校验输入的元数据是否符合规则
 func validateInMetadata(ruleMetaKey string, ruleMetaValue *apimodel.MatchString, ruleMetaValueStr string,
-	metadata map[string]map[string]string, matcher *regexp.Regexp) bool {
+	metadata map[string]map[string]string, matcher model.RegexMatcher) bool {
 	if len(metadata) == 0 {
 		return true
 	}
@@ -303,12 +307,7 @@ func validateInMetadata(ruleMetaKey string, ruleMetaValue *apimodel.MatchString,
 	switch ruleMetaValue.Type {
 	case apimodel.MatchString_REGEX:
 		for value := range values {
-			m, err := matcher.FindStringMatch(value)
-			if err != nil {
-				log.GetBaseLogger().Errorf("regex match metadata error. ruleMetaKey: %s, value: %s, errors: %s", ruleMetaKey, value, err)
-				return false
-			}
-			if m == nil || m.String() == "" {
+			if !matcher.MatchString(value) {
 				return false
 			}
 		}
@@ -349,22 +348,17 @@ func (g *RuleBasedInstancesFilter) matchDstMetadata(routeInfo *servicerouter.Rou
 		case apimodel.MatchString_REGEX:
 			// 对于正则表达式，则可能匹配到多个value，
 			// 需要把服务下面的所有的meta value都拿出来比较
-			regexObj, err := ruleCache.GetRegexMatcher(ruleMetaValueStr)
+			matcher, err := ruleCache.GetMatcher(ruleMetaValueStr)
 			if err != nil {
 				return nil, false, ruleMetaValueStr, err
 			}
 			// 校验从上一个路由插件继承下来的规则是否符合该目标规则
-			if !validateInMetadata(ruleMetaKey, ruleMetaValue, ruleMetaValueStr, inCluster.Metadata, regexObj) {
+			if !validateInMetadata(ruleMetaKey, ruleMetaValue, ruleMetaValueStr, inCluster.Metadata, matcher) {
 				return nil, false, "", nil
 			}
 			var hasMatchedValue bool
 			for value, composedValue := range metaValues {
-				m, err := regexObj.FindStringMatch(value)
-				if err != nil {
-					log.GetBaseLogger().Errorf("regex match dst metadata error. ruleMetaValueStr: %s, value: %s, errors: %s", ruleMetaValueStr, value, err)
-					continue
-				}
-				if m == nil || m.String() == "" {
+				if !matcher.MatchString(value) {
 					continue
 				}
 				hasMatchedValue = true
@@ -610,9 +604,18 @@ func (g *RuleBasedInstancesFilter) getRuleFilteredInstances(ruleMatchType int, r
 	} else {
 		ruleCache = routeInfo.SourceRouteRule.GetRuleCache()
 	}
-	for _, route := range routes {
+	indexedSources := g.getIndexedSources(ruleMatchType, routeInfo, ruleCache)
+	for i, route := range routes {
+		sources := route.Sources
+		if nil != indexedSources {
+			var ok bool
+			if sources, ok = indexedSources[i]; !ok {
+				// 索引中没有可能匹配的source，跳过该route
+				continue
+			}
+		}
 		// 匹配source规则
-		sourceMatched, matchSource, notMatches, invalidRegex := g.matchSource(route.Sources, routeInfo, ruleMatchType, ruleCache)
+		sourceMatched, matchSource, notMatches, invalidRegex := g.matchSource(sources, routeInfo, ruleMatchType, ruleCache)
 
 		if invalidRegex != nil {
 			// summary.invalidRegexSources = append(summary.invalidRegexSources, invalidRegex.invalidRegexes...)
@@ -675,6 +678,41 @@ func (g *RuleBasedInstancesFilter) getRuleFilteredInstances(ruleMatchType int, r
 	return nil, nil
 }
 
+// getIndexedSources 通过规则索引筛选各route中可能匹配的source，key为route下标，规则没有索引时返回nil
+// 未命中索引的source不会出现在匹配失败的日志中
+func (g *RuleBasedInstancesFilter) getIndexedSources(ruleMatchType int, routeInfo *servicerouter.RouteInfo,
+	ruleCache model.RuleCache) map[int][]*apitraffic.Source {
+	rule := routeInfo.SourceRouteRule
+	if ruleMatchType == dstRouteRuleMatch {
+		rule = routeInfo.DestRouteRule
+	}
+	routing := rule.GetValue().(*apitraffic.Routing)
+	index, ok := ruleCache.GetMessageCache(routing).(*pb.RoutingIndex)
+	if !ok {
+		return nil
+	}
+	routes := routing.Outbounds
+	routeIndex := index.Outbounds
+	if ruleMatchType == dstRouteRuleMatch {
+		routes = routing.Inbounds
+		routeIndex = index.Inbounds
+	}
+	var labels map[string]string
+	if routeInfo.SourceService != nil {
+		labels = routeInfo.SourceService.GetMetadata()
+	}
+	refs := routeIndex.Candidates(labels)
+	sources := make(map[int][]*apitraffic.Source, len(refs))
+	for _, ref := range refs {
+		if ref.Source < 0 {
+			sources[ref.Route] = nil
+			continue
+		}
+		sources[ref.Route] = append(sources[ref.Route], routes[ref.Route].Sources[ref.Source])
+	}
+	return sources
+}
+
 // 在instance中全匹配被调服务metadata
 func (g *RuleBasedInstancesFilter) searchMetadata(destServiceMetadata map[string]string, instanceMetadata map[string]string) bool {
 	// metadata是否全部匹配
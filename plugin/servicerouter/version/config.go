@@ -0,0 +1,49 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package version
+
+import (
+	"errors"
+)
+
+const (
+	// DefaultMetaKey 实例元数据中标识版本号的key
+	DefaultMetaKey = "version"
+)
+
+// Config 版本路由插件配置
+type Config struct {
+	// MetaKey 实例元数据中标识版本号的key
+	MetaKey string `yaml:"metaKey" json:"metaKey"`
+	// FailoverAll 没有满足版本约束的实例时是否降级返回全部实例，默认返回错误
+	FailoverAll bool `yaml:"failoverAll" json:"failoverAll"`
+}
+
+// Verify 校验配置是否OK
+func (c *Config) Verify() error {
+	if len(c.MetaKey) == 0 {
+		return errors.New("versionRouter.metaKey can not be empty")
+	}
+	return nil
+}
+
+// SetDefault 对关键值设置默认值
+func (c *Config) SetDefault() {
+	if len(c.MetaKey) == 0 {
+		c.MetaKey = DefaultMetaKey
+	}
+}
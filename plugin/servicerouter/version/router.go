@@ -0,0 +1,130 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package version
+
+import (
+	"fmt"
+	"sync"
+
+	semver "github.com/polarismesh/polaris-go/pkg/algorithm/version"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&InstancesFilter{}, &Config{})
+}
+
+// InstancesFilter 基于版本约束的服务路由插件
+// 主调通过TargetVersion指定版本约束，只返回实例元数据中版本号满足约束的实例，可用于蓝绿发布的切换
+type InstancesFilter struct {
+	*plugin.PluginBase
+	valueCtx model.ValueContext
+	cfg      *Config
+	// 已解析的版本约束，key为约束的字面值
+	constraints sync.Map
+}
+
+// Type 插件类型
+func (g *InstancesFilter) Type() common.Type {
+	return common.TypeServiceRouter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *InstancesFilter) Name() string {
+	return config.DefaultServiceRouterVersion
+}
+
+// Init 初始化插件
+func (g *InstancesFilter) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.valueCtx = ctx.ValueCtx
+	g.cfg = &Config{}
+	g.cfg.SetDefault()
+	cfgValue := ctx.Config.GetConsumer().GetServiceRouter().GetPluginConfig(g.Name())
+	if cfgValue != nil {
+		g.cfg = cfgValue.(*Config)
+	}
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *InstancesFilter) Destroy() error {
+	return nil
+}
+
+// Enable 主调指定了版本约束才启用
+func (g *InstancesFilter) Enable(routeInfo *servicerouter.RouteInfo, clusters model.ServiceClusters) bool {
+	return len(routeInfo.TargetVersion) > 0
+}
+
+// GetFilteredInstances 插件模式进行服务实例过滤，并返回过滤后的实例列表
+func (g *InstancesFilter) GetFilteredInstances(routeInfo *servicerouter.RouteInfo,
+	clusters model.ServiceClusters, withinCluster *model.Cluster) (*servicerouter.RouteResult, error) {
+	constraint, err := g.getConstraint(routeInfo.TargetVersion)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err,
+			"invalid target version %s", routeInfo.TargetVersion)
+	}
+	targetCluster := model.NewCluster(clusters, withinCluster)
+	metaValues := clusters.GetInstanceMetaValues(targetCluster.Location, g.cfg.MetaKey)
+	var matched bool
+	for value, composedValue := range metaValues {
+		if constraint.CheckString(value) {
+			targetCluster.RuleAddMetadata(g.cfg.MetaKey, value, composedValue)
+			matched = true
+		}
+	}
+	if matched {
+		targetCluster.ReloadComposeMetaValue()
+		if targetCluster.GetClusterValue().GetInstancesSet(true, true).Count() > 0 {
+			return g.getResult(targetCluster), nil
+		}
+	}
+	targetCluster.PoolPut()
+	if g.cfg.FailoverAll {
+		return g.getResult(model.NewCluster(clusters, withinCluster)), nil
+	}
+	errorText := fmt.Sprintf("no instance of service %s(namespace %s) matches version %s",
+		routeInfo.DestService.GetService(), routeInfo.DestService.GetNamespace(), routeInfo.TargetVersion)
+	log.GetBaseLogger().Errorf(errorText)
+	return nil, model.NewSDKError(model.ErrCodeRouteRuleNotMatch, nil, errorText)
+}
+
+// getConstraint 获取已解析的版本约束，未解析过则解析后缓存
+func (g *InstancesFilter) getConstraint(text string) (*semver.Constraint, error) {
+	if value, ok := g.constraints.Load(text); ok {
+		return value.(*semver.Constraint), nil
+	}
+	constraint, err := semver.ParseConstraint(text)
+	if err != nil {
+		return nil, err
+	}
+	value, _ := g.constraints.LoadOrStore(text, constraint)
+	return value.(*semver.Constraint), nil
+}
+
+func (g *InstancesFilter) getResult(cluster *model.Cluster) *servicerouter.RouteResult {
+	result := servicerouter.PoolGetRouteResult(g.valueCtx)
+	result.OutputCluster = cluster
+	return result
+}
@@ -0,0 +1,47 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package zoneaware
+
+import (
+	"errors"
+)
+
+const (
+	// DefaultMinClusterSize 被调实例数小于该值时不进行可用区加权
+	DefaultMinClusterSize = 6
+)
+
+// Config 可用区加权路由插件配置
+type Config struct {
+	// MinClusterSize 被调实例数小于该值时不进行可用区加权，避免实例过少时分布失真
+	MinClusterSize int `yaml:"minClusterSize" json:"minClusterSize"`
+}
+
+// Verify 校验配置是否OK
+func (c *Config) Verify() error {
+	if c.MinClusterSize < 0 {
+		return errors.New("zoneAwareRouter.minClusterSize can not be negative")
+	}
+	return nil
+}
+
+// SetDefault 对关键值设置默认值
+func (c *Config) SetDefault() {
+	if c.MinClusterSize == 0 {
+		c.MinClusterSize = DefaultMinClusterSize
+	}
+}
@@ -0,0 +1,243 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package zoneaware
+
+import (
+	"sync"
+
+	"github.com/modern-go/reflect2"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&ZoneAwareRouter{}, &Config{})
+}
+
+// ZoneAwareRouter 按可用区容量进行加权的路由插件
+// 根据主调服务与被调服务在各可用区的实例占比计算本可用区可承接的流量比例，超出部分按其他可用区的剩余容量概率性跨区，
+// 避免被调实例较少的可用区被本区主调打满
+type ZoneAwareRouter struct {
+	*plugin.PluginBase
+	valueCtx     model.ValueContext
+	cfg          *Config
+	scalableRand *rand.ScalableRand
+	// 已计算的可用区权重，key为weightsKey
+	weights sync.Map
+}
+
+// weightsKey 可用区权重的缓存key
+type weightsKey struct {
+	dstService model.ServiceKey
+	srcService model.ServiceKey
+	cluster    model.ClusterKey
+}
+
+// zoneWeights 可用区权重，主调或被调实例变更后需要重新计算
+type zoneWeights struct {
+	dstRevision string
+	srcRevision string
+	// 路由到本可用区的概率，取值[0, 10000]
+	localPercent int
+	// 跨区时各可用区的剩余容量
+	residual []zoneCapacity
+	// 剩余容量总和
+	totalResidual int
+}
+
+// zoneCapacity 单个可用区的剩余容量
+type zoneCapacity struct {
+	location model.Location
+	capacity int
+}
+
+// Type 插件类型
+func (g *ZoneAwareRouter) Type() common.Type {
+	return common.TypeServiceRouter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *ZoneAwareRouter) Name() string {
+	return config.DefaultServiceRouterZoneAware
+}
+
+// Init 初始化插件
+func (g *ZoneAwareRouter) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.valueCtx = ctx.ValueCtx
+	g.scalableRand = rand.NewScalableRand()
+	g.cfg = &Config{}
+	g.cfg.SetDefault()
+	cfgValue := ctx.Config.GetConsumer().GetServiceRouter().GetPluginConfig(g.Name())
+	if cfgValue != nil {
+		g.cfg = cfgValue.(*Config)
+	}
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *ZoneAwareRouter) Destroy() error {
+	return nil
+}
+
+// Enable 需要知道主调服务以及本机的可用区信息
+func (g *ZoneAwareRouter) Enable(routeInfo *servicerouter.RouteInfo, clusters model.ServiceClusters) bool {
+	if reflect2.IsNil(routeInfo.SourceService) || len(routeInfo.SourceService.GetService()) == 0 {
+		return false
+	}
+	location := g.valueCtx.GetCurrentLocation().GetLocation()
+	return nil != location && len(location.Zone) > 0
+}
+
+// GetFilteredInstances 插件模式进行服务实例过滤，并返回过滤后的实例列表
+func (g *ZoneAwareRouter) GetFilteredInstances(routeInfo *servicerouter.RouteInfo,
+	clusters model.ServiceClusters, withinCluster *model.Cluster) (*servicerouter.RouteResult, error) {
+	result := servicerouter.PoolGetRouteResult(g.valueCtx)
+	local := g.valueCtx.GetCurrentLocation().GetLocation()
+	weights := g.getZoneWeights(routeInfo, clusters, withinCluster, local)
+	if nil == weights {
+		result.OutputCluster = model.NewCluster(clusters, withinCluster)
+		return result, nil
+	}
+	target := model.Location{Region: local.Region, Zone: local.Zone}
+	if g.scalableRand.Intn(percentBase) >= weights.localPercent && weights.totalResidual > 0 {
+		target = weights.pickResidual(g.scalableRand.Intn(weights.totalResidual))
+		result.Status = servicerouter.DegradeToRegion
+	}
+	outCluster := model.NewCluster(clusters, withinCluster)
+	outCluster.Location.Region = target.Region
+	outCluster.Location.Zone = target.Zone
+	outCluster.ClearClusterValue()
+	result.OutputCluster = outCluster
+	return result, nil
+}
+
+// getZoneWeights 获取可用区权重，不满足按可用区加权的条件时返回nil
+func (g *ZoneAwareRouter) getZoneWeights(routeInfo *servicerouter.RouteInfo, clusters model.ServiceClusters,
+	withinCluster *model.Cluster, local *model.Location) *zoneWeights {
+	srcResp, err := g.valueCtx.GetEngine().SyncGetAllInstances(&model.GetAllInstancesRequest{
+		Namespace: routeInfo.SourceService.GetNamespace(),
+		Service:   routeInfo.SourceService.GetService(),
+	})
+	if err != nil {
+		log.GetBaseLogger().Warnf("[ZoneAwareRouter] fail to get instances of caller %s(%s): %v",
+			routeInfo.SourceService.GetService(), routeInfo.SourceService.GetNamespace(), err)
+		return nil
+	}
+	key := weightsKey{
+		dstService: clusters.GetServiceKey(),
+		srcService: model.ServiceKey{Namespace: srcResp.Namespace, Service: srcResp.Service},
+	}
+	if nil != withinCluster {
+		key.cluster = withinCluster.ClusterKey
+	}
+	dstRevision := clusters.GetServiceInstances().GetRevision()
+	if value, ok := g.weights.Load(key); ok {
+		weights := value.(*zoneWeights)
+		if weights.dstRevision == dstRevision && weights.srcRevision == srcResp.Revision {
+			return weights.validOrNil()
+		}
+	}
+	baseCluster := model.NewCluster(clusters, withinCluster)
+	dstInstances := baseCluster.GetClusterValue().GetInstancesSet(false, false).GetRealInstances()
+	baseCluster.PoolPut()
+	weights := g.calculate(srcResp.GetInstances(), dstInstances, local)
+	weights.dstRevision = dstRevision
+	weights.srcRevision = srcResp.Revision
+	g.weights.Store(key, weights)
+	return weights.validOrNil()
+}
+
+const (
+	// 概率计算的精度，万分比
+	percentBase = 10000
+)
+
+// calculate 计算可用区权重，算法与envoy的zone aware routing一致
+// 本区被调占比不小于本区主调占比时全部留在本区，否则按两者比值留在本区，剩余流量按其他可用区被调占比超出主调占比的部分分配
+func (g *ZoneAwareRouter) calculate(srcInstances []model.Instance, dstInstances []model.Instance,
+	local *model.Location) *zoneWeights {
+	weights := &zoneWeights{localPercent: -1}
+	if len(dstInstances) < g.cfg.MinClusterSize {
+		return weights
+	}
+	srcCount, srcTotal := countByZone(srcInstances)
+	dstCount, dstTotal := countByZone(dstInstances)
+	localZone := model.Location{Region: local.Region, Zone: local.Zone}
+	if srcTotal == 0 || dstTotal == 0 || srcCount[localZone] == 0 || dstCount[localZone] == 0 {
+		return weights
+	}
+	// 按万分比计算各可用区的实例占比
+	srcPercent := func(zone model.Location) int { return srcCount[zone] * percentBase / srcTotal }
+	dstPercent := func(zone model.Location) int { return dstCount[zone] * percentBase / dstTotal }
+	if dstPercent(localZone) >= srcPercent(localZone) {
+		weights.localPercent = percentBase
+		return weights
+	}
+	weights.localPercent = dstPercent(localZone) * percentBase / srcPercent(localZone)
+	for zone := range dstCount {
+		if zone == localZone {
+			continue
+		}
+		capacity := dstPercent(zone) - srcPercent(zone)
+		if capacity > 0 {
+			weights.residual = append(weights.residual, zoneCapacity{location: zone, capacity: capacity})
+			weights.totalResidual += capacity
+		}
+	}
+	return weights
+}
+
+// countByZone 统计各可用区的实例数，不计入隔离及权重为0的实例
+func countByZone(instances []model.Instance) (map[model.Location]int, int) {
+	counts := make(map[model.Location]int)
+	var total int
+	for _, instance := range instances {
+		if instance.IsIsolated() || instance.GetWeight() == 0 || !instance.IsHealthy() {
+			continue
+		}
+		counts[model.Location{Region: instance.GetRegion(), Zone: instance.GetZone()}]++
+		total++
+	}
+	return counts, total
+}
+
+// validOrNil 权重无效时返回nil
+func (w *zoneWeights) validOrNil() *zoneWeights {
+	if w.localPercent < 0 {
+		return nil
+	}
+	return w
+}
+
+// pickResidual 按剩余容量选择跨区的可用区
+func (w *zoneWeights) pickResidual(value int) model.Location {
+	for _, zone := range w.residual {
+		if value < zone.capacity {
+			return zone.location
+		}
+		value -= zone.capacity
+	}
+	return w.residual[len(w.residual)-1].location
+}
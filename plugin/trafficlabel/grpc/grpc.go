@@ -0,0 +1,127 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+const (
+	// PluginName 插件名
+	PluginName = "grpc"
+)
+
+// init 注册插件
+func init() {
+	plugin.RegisterPlugin(&Provider{})
+}
+
+// Provider grpc请求的流量标签插件，支持服务端拦截器中的context.Context，以及metadata.MD
+type Provider struct {
+	*plugin.PluginBase
+}
+
+// Type 插件类型
+func (p *Provider) Type() common.Type {
+	return common.TypeTrafficLabelProvider
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (p *Provider) Name() string {
+	return PluginName
+}
+
+// Init 初始化插件
+func (p *Provider) Init(ctx *plugin.InitContext) error {
+	p.PluginBase = plugin.NewPluginBase(ctx)
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (p *Provider) Destroy() error {
+	return nil
+}
+
+// Adapt 将grpc的请求上下文或者元数据适配为TrafficRequest
+func (p *Provider) Adapt(request interface{}) (model.TrafficRequest, bool) {
+	switch req := request.(type) {
+	case metadata.MD:
+		return &trafficRequest{md: req}, true
+	case context.Context:
+		md, ok := metadata.FromIncomingContext(req)
+		if !ok {
+			return nil, false
+		}
+		trafficReq := &trafficRequest{md: md}
+		trafficReq.method, _ = grpc.Method(req)
+		if pr, ok := peer.FromContext(req); ok && nil != pr.Addr {
+			trafficReq.callerIP = pr.Addr.String()
+			if host, _, err := net.SplitHostPort(trafficReq.callerIP); err == nil {
+				trafficReq.callerIP = host
+			}
+		}
+		return trafficReq, true
+	}
+	return nil, false
+}
+
+// trafficRequest grpc请求的适配
+type trafficRequest struct {
+	md       metadata.MD
+	method   string
+	callerIP string
+}
+
+// GetMethod 接口方法，格式为/package.service/method
+func (r *trafficRequest) GetMethod() string {
+	return r.method
+}
+
+// GetPath grpc请求的路径与接口方法一致
+func (r *trafficRequest) GetPath() string {
+	return r.method
+}
+
+// GetCallerIP 主调IP
+func (r *trafficRequest) GetCallerIP() string {
+	return r.callerIP
+}
+
+// GetHeaders 请求元数据
+func (r *trafficRequest) GetHeaders() map[string][]string {
+	return r.md
+}
+
+// GetQueries grpc请求没有请求参数
+func (r *trafficRequest) GetQueries() map[string][]string {
+	return nil
+}
+
+// GetCookies grpc请求没有cookie
+func (r *trafficRequest) GetCookies() map[string]string {
+	return nil
+}
@@ -0,0 +1,122 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package http
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+const (
+	// PluginName 插件名
+	PluginName = "http"
+)
+
+// init 注册插件
+func init() {
+	plugin.RegisterPlugin(&Provider{})
+}
+
+// Provider 标准库net/http请求的流量标签插件，gin等基于net/http的框架可直接传入*http.Request
+type Provider struct {
+	*plugin.PluginBase
+}
+
+// Type 插件类型
+func (p *Provider) Type() common.Type {
+	return common.TypeTrafficLabelProvider
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (p *Provider) Name() string {
+	return PluginName
+}
+
+// Init 初始化插件
+func (p *Provider) Init(ctx *plugin.InitContext) error {
+	p.PluginBase = plugin.NewPluginBase(ctx)
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (p *Provider) Destroy() error {
+	return nil
+}
+
+// Adapt 将*http.Request适配为TrafficRequest
+func (p *Provider) Adapt(request interface{}) (model.TrafficRequest, bool) {
+	httpReq, ok := request.(*http.Request)
+	if !ok || nil == httpReq {
+		return nil, false
+	}
+	return &trafficRequest{request: httpReq}, true
+}
+
+// trafficRequest *http.Request的适配
+type trafficRequest struct {
+	request *http.Request
+}
+
+// GetMethod 请求方法
+func (r *trafficRequest) GetMethod() string {
+	return r.request.Method
+}
+
+// GetPath 请求路径
+func (r *trafficRequest) GetPath() string {
+	if nil == r.request.URL {
+		return ""
+	}
+	return r.request.URL.Path
+}
+
+// GetCallerIP 主调IP
+func (r *trafficRequest) GetCallerIP() string {
+	host, _, err := net.SplitHostPort(r.request.RemoteAddr)
+	if err != nil {
+		return r.request.RemoteAddr
+	}
+	return host
+}
+
+// GetHeaders 请求头
+func (r *trafficRequest) GetHeaders() map[string][]string {
+	return r.request.Header
+}
+
+// GetQueries 请求参数
+func (r *trafficRequest) GetQueries() map[string][]string {
+	if nil == r.request.URL {
+		return nil
+	}
+	return r.request.URL.Query()
+}
+
+// GetCookies 请求cookie
+func (r *trafficRequest) GetCookies() map[string]string {
+	cookies := r.request.Cookies()
+	values := make(map[string]string, len(cookies))
+	for _, cookie := range cookies {
+		values[cookie.Name] = cookie.Value
+	}
+	return values
+}
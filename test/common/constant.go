@@ -35,6 +35,7 @@ const (
 	DefaultServerAbNormalSuitServerPort = 58014
 	CacheFastUpdateSuitServerPort       = 58015
 	CacheFastUpdateFailSuitServerPort   = 58016
+	GetOneInstanceBenchServerPort       = 58017
 )
 
 var (
@@ -0,0 +1,130 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package test
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/google/uuid"
+	"github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/grpc"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	commontest "github.com/polarismesh/polaris-go/test/common"
+	"github.com/polarismesh/polaris-go/test/mock"
+)
+
+const (
+	benchNamespace = "benchns"
+	benchService   = "benchsvc"
+)
+
+// startBenchNamingServer 启动带有测试服务的模拟服务端
+func startBenchNamingServer(b *testing.B) (string, func()) {
+	addr := fmt.Sprintf("127.0.0.1:%d", commontest.GetOneInstanceBenchServerPort)
+	mockServer := mock.NewNamingServer()
+	token := mockServer.RegisterServerService(config.ServerDiscoverService)
+	mockServer.RegisterServerInstance("127.0.0.1", commontest.GetOneInstanceBenchServerPort,
+		config.ServerDiscoverService, token, true)
+	mockServer.RegisterServerServices("127.0.0.1", commontest.GetOneInstanceBenchServerPort)
+	svc := &service_manage.Service{
+		Name:      &wrappers.StringValue{Value: benchService},
+		Namespace: &wrappers.StringValue{Value: benchNamespace},
+		Token:     &wrappers.StringValue{Value: uuid.New().String()},
+	}
+	mockServer.RegisterService(svc)
+	mockServer.GenTestInstances(svc, 10)
+
+	grpcServer := grpc.NewServer()
+	service_manage.RegisterPolarisGRPCServer(grpcServer, mockServer)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		b.Fatalf("fail to listen %s: %v", addr, err)
+	}
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	return addr, grpcServer.Stop
+}
+
+// BenchmarkGetOneInstance 服务已缓存、路由无变化时，端到端单实例查询的耗时及内存分配
+func BenchmarkGetOneInstance(b *testing.B) {
+	addr, stop := startBenchNamingServer(b)
+	defer stop()
+	cfg := config.NewDefaultConfiguration([]string{addr})
+	enableStat := false
+	cfg.Global.StatReporter.Enable = &enableStat
+	consumer, err := api.NewConsumerAPIByConfig(cfg)
+	if err != nil {
+		b.Fatalf("fail to create consumer: %v", err)
+	}
+	defer consumer.Destroy()
+	// 预热，确保服务实例及路由规则已经缓存
+	warmup := &api.GetOneInstanceRequest{}
+	warmup.Namespace = benchNamespace
+	warmup.Service = benchService
+	warmup.SetTimeout(5 * time.Second)
+	if _, err := consumer.GetOneInstance(warmup); err != nil {
+		b.Fatalf("fail to warm up: %v", err)
+	}
+
+	b.Run("新建请求", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			req := &api.GetOneInstanceRequest{}
+			req.Namespace = benchNamespace
+			req.Service = benchService
+			if _, err := consumer.GetOneInstance(req); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("对象池", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			req := api.AcquireGetOneInstanceRequest()
+			req.Namespace = benchNamespace
+			req.Service = benchService
+			_, err := consumer.GetOneInstance(req)
+			api.ReleaseGetOneInstanceRequest(req)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("指定路由链", func(b *testing.B) {
+		b.ReportAllocs()
+		routers := []string{config.DefaultServiceRouterRuleBased, config.DefaultServiceRouterFilterOnly}
+		for i := 0; i < b.N; i++ {
+			req := api.AcquireGetOneInstanceRequest()
+			req.Namespace = benchNamespace
+			req.Service = benchService
+			req.Routers = routers
+			_, err := consumer.GetOneInstance(req)
+			api.ReleaseGetOneInstanceRequest(req)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}